@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,8 +15,8 @@ type InitServerResponse struct {
 	AdminAccessToken string `json:"admin_access_token"`
 }
 
-// InitServer sends a request to initialize the server in enterprise mode
-func (c *Client) InitServer() (*InitServerResponse, error) {
+// InitServerContext sends a request to initialize the server in enterprise mode
+func (c *Client) InitServerContext(ctx context.Context) (*InitServerResponse, error) {
 	u, _ := url.JoinPath(c.baseURL, "/init")
 
 	// TODO: Replace ModeProd with ModeEnterprise in future.
@@ -50,3 +51,10 @@ func (c *Client) InitServer() (*InitServerResponse, error) {
 	}
 	return &initResp, nil
 }
+
+// InitServer is like InitServerContext, without a context.
+//
+// Deprecated: use InitServerContext instead, InitServer will be removed in the next release.
+func (c *Client) InitServer() (*InitServerResponse, error) {
+	return c.InitServerContext(context.Background())
+}
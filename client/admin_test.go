@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -50,7 +51,7 @@ func TestInitServer(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "", &http.Client{})
-		response, err := client.InitServer()
+		response, err := client.InitServerContext(context.Background())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -68,7 +69,7 @@ func TestInitServer(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "", &http.Client{})
-		response, err := client.InitServer()
+		response, err := client.InitServerContext(context.Background())
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -86,7 +87,7 @@ func TestInitServer(t *testing.T) {
 	t.Run("network error", func(t *testing.T) {
 		// Use an invalid URL to simulate network error
 		client := NewClient("http://invalid-url-that-does-not-exist", "", &http.Client{})
-		response, err := client.InitServer()
+		response, err := client.InitServerContext(context.Background())
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -109,7 +110,7 @@ func TestInitServer(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "", &http.Client{})
-		response, err := client.InitServer()
+		response, err := client.InitServerContext(context.Background())
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -131,7 +132,7 @@ func TestInitServer(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "", &http.Client{})
-		response, err := client.InitServer()
+		response, err := client.InitServerContext(context.Background())
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -165,7 +166,7 @@ func TestInitServerWithAccessToken(t *testing.T) {
 
 	// Test with access token (should be ignored for init)
 	client := NewClient(server.URL, "some-token", &http.Client{})
-	response, err := client.InitServer()
+	response, err := client.InitServerContext(context.Background())
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
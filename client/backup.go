@@ -0,0 +1,76 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// Backup starts a backup of the registry and returns the stream of the backup file, which the caller must close.
+// The stream is in the format described by types.BackupHeader. A backup that failed after it started ends with
+// a types.BackupRecord holding the error instead of the end record, so the file must be checked before it is kept.
+// The timeout of the Client bounds waiting for the backup to start, it is then read for as long as it takes.
+func (c *Client) Backup(ctx context.Context, req *types.BackupRequest) (io.ReadCloser, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	u, _ := c.constructAPIEndpoint("/backup")
+	r, err := c.newRequest(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doStream(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, c.parseErrorResponse(resp)
+	}
+	return resp.Body, nil
+}
+
+// Restore restores the backup read from backup into the registry, replacing its entities if force is true.
+// Restoring into a registry that is not empty fails with ErrRegistryNotEmpty otherwise.
+// The backup is uploaded as it is read, the timeout of the Client doesn't apply since restoring a large backup
+// takes as long as it takes, use ctx to bound it.
+func (c *Client) Restore(
+	ctx context.Context, backup io.Reader, passphrase string, force bool,
+) (*types.RestoreResult, error) {
+	u, _ := c.constructAPIEndpoint("/restore")
+	req, err := c.newRequest(ctx, http.MethodPost, u, backup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", types.BackupContentType)
+	req.Header.Set(types.BackupPassphraseHeader, passphrase)
+	if force {
+		req.URL.RawQuery = "force=true"
+	}
+
+	hc := *c.httpClient
+	hc.Timeout = 0
+	resp, err := c.send(&hc, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var result types.RestoreResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestBackup(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/backup" {
+			t.Errorf("Expected POST /api/v1/backup, got %s %s", r.Method, r.URL.Path)
+		}
+		var in types.BackupRequest
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if in.Passphrase != "correct horse" || !in.IncludeAudit {
+			t.Errorf("Unexpected backup request: %+v", in)
+		}
+		w.Header().Set("Content-Type", types.BackupContentType)
+		_, _ = w.Write([]byte("backup data"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", &http.Client{})
+	stream, err := client.Backup(context.Background(), &types.BackupRequest{Passphrase: "correct horse", IncludeAudit: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer stream.Close()
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(data) != "backup data" {
+		t.Errorf("Expected the backup to be returned as it was sent, got %q", data)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("restored", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/api/v1/restore" {
+				t.Errorf("Expected POST /api/v1/restore, got %s %s", r.Method, r.URL.Path)
+			}
+			if got := r.Header.Get(types.BackupPassphraseHeader); got != "correct horse" {
+				t.Errorf("Expected the passphrase in the %s header, got %q", types.BackupPassphraseHeader, got)
+			}
+			if r.URL.Query().Get("force") != "true" {
+				t.Errorf("Expected force=true, got %q", r.URL.RawQuery)
+			}
+			if body, _ := io.ReadAll(r.Body); string(body) != "backup data" {
+				t.Errorf("Expected the backup as the body, got %q", body)
+			}
+			// restoring takes longer than the timeout of the client
+			time.Sleep(150 * time.Millisecond)
+			_ = json.NewEncoder(w).Encode(types.RestoreResult{
+				Version: "0.3.0", Tables: []types.BackupTable{{Name: "mcp_servers", Rows: 2}},
+			})
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, "test-token", &http.Client{Timeout: 50 * time.Millisecond})
+		result, err := client.Restore(context.Background(), strings.NewReader("backup data"), "correct horse", true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Version != "0.3.0" || len(result.Tables) != 1 || result.Tables[0].Rows != 2 {
+			t.Errorf("Unexpected restore result: %+v", result)
+		}
+	})
+
+	t.Run("registry not empty", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.RawQuery != "" {
+				t.Errorf("Expected no query, got %q", r.URL.RawQuery)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(types.ErrorResponse{Error: types.APIError{
+				Code:    types.ErrorCodeRegistryNotEmpty,
+				Message: "the registry is not empty, it has 1 MCP servers",
+				Details: map[string]any{"entities": map[string]any{"MCP servers": 1}},
+			}})
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, "test-token", &http.Client{})
+		_, err := client.Restore(context.Background(), strings.NewReader("backup data"), "correct horse", false)
+		if !errors.Is(err, ErrRegistryNotEmpty) {
+			t.Fatalf("Expected ErrRegistryNotEmpty, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// Bounds of the responses kept by a CacheTransport.
+const (
+	maxCachedResponses = 64
+	// maxCachedBodySize is the size of the largest response body that is cached, larger ones are always downloaded.
+	maxCachedBodySize = 4 << 20
+)
+
+// CacheTransport is an http.RoundTripper that keeps the last response of every GET endpoint that had an ETag,
+// and revalidates it with an If-None-Match header on the next request to the same endpoint.
+// When the server answers 304 Not Modified, the kept response is returned instead, so callers see a 200 response
+// with the same body without it being downloaded again.
+//
+// Responses are kept per URL and Authorization header, since different users may be shown different content.
+// A successful mutating request to a URL, eg- a DELETE, forgets the response kept for it.
+type CacheTransport struct {
+	base http.RoundTripper
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+	// order holds the keys of entries from the oldest to the newest, to evict the oldest when full
+	order []string
+}
+
+type cachedResponse struct {
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+// NewCacheTransport wraps base (http.DefaultTransport if nil) so that GET responses are cached and revalidated.
+func NewCacheTransport(base http.RoundTripper) *CacheTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &CacheTransport{base: base, entries: make(map[string]*cachedResponse)}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.URL.String() + " " + req.Header.Get("Authorization")
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodHead, http.MethodOptions:
+		return t.base.RoundTrip(req)
+	default:
+		resp, err := t.base.RoundTrip(req)
+		if err == nil && resp.StatusCode < 400 {
+			// the request may have changed what the URL describes
+			t.forget(key)
+		}
+		return resp, err
+	}
+	// conditional and partial requests made by the caller are its own business
+	if req.Header.Get("If-None-Match") != "" || req.Header.Get("Range") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	cached := t.get(key)
+	r := req
+	if cached != nil {
+		r = req.Clone(req.Context())
+		r.Header.Set("If-None-Match", cached.etag)
+	}
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return cached.response(req, resp), nil
+	case resp.StatusCode != http.StatusOK:
+		return resp, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" || resp.Header.Get("Cache-Control") == "no-store" {
+		t.forget(key)
+		return resp, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBodySize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedBodySize {
+		// too large to keep, hand the rest of the body to the caller as it is downloaded
+		t.forget(key)
+		resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.put(key, &cachedResponse{etag: etag, header: resp.Header.Clone(), body: body})
+	return resp, nil
+}
+
+// response builds the response to req out of the cached one, with the headers of the 304 response that validated it.
+func (c *cachedResponse) response(req *http.Request, notModified *http.Response) *http.Response {
+	header := c.header.Clone()
+	for k, v := range notModified.Header {
+		header[k] = v
+	}
+	header.Set("Content-Length", strconv.Itoa(len(c.body)))
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(c.body)),
+		ContentLength: int64(len(c.body)),
+		Request:       req,
+	}
+}
+
+func (t *CacheTransport) get(key string) *cachedResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.entries[key]
+}
+
+func (t *CacheTransport) put(key string, c *cachedResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.entries[key]; !ok {
+		if len(t.order) >= maxCachedResponses {
+			delete(t.entries, t.order[0])
+			t.order = t.order[1:]
+		}
+		t.order = append(t.order, key)
+	}
+	t.entries[key] = c
+}
+
+func (t *CacheTransport) forget(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.entries[key]; !ok {
+		return
+	}
+	delete(t.entries, key)
+	for i, k := range t.order {
+		if k == key {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// readCloser reads from Reader and closes Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestCacheTransport(t *testing.T) {
+	t.Parallel()
+
+	var version, downloads atomic.Int32
+	version.Store(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			version.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		etag := strconv.Quote(strconv.Itoa(int(version.Load())))
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		servers := []*types.McpServer{{Name: "github"}}
+		if version.Load() > 1 {
+			servers = nil
+		}
+		_ = json.NewEncoder(w).Encode(types.Page[*types.McpServer]{Items: servers})
+	}))
+	defer server.Close()
+
+	c := New(server.URL, "test-token", WithRetries(0))
+	list := func() []*types.McpServer {
+		t.Helper()
+		servers, err := c.ListServersContext(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return servers
+	}
+
+	if got := list(); len(got) != 1 || got[0].Name != "github" {
+		t.Fatalf("Expected the github server, got %v", got)
+	}
+	// the second list is served from the cache after the server confirmed it didn't change
+	if got := list(); len(got) != 1 || got[0].Name != "github" {
+		t.Fatalf("Expected the cached github server, got %v", got)
+	}
+	if downloads.Load() != 1 {
+		t.Errorf("Expected the unchanged list to be downloaded once, got %d downloads", downloads.Load())
+	}
+
+	if err := c.DeregisterServerContext(context.Background(), "github"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := list(); len(got) != 0 {
+		t.Errorf("Expected the list to be downloaded again after it changed, got %v", got)
+	}
+	if downloads.Load() != 2 {
+		t.Errorf("Expected 2 downloads, got %d", downloads.Load())
+	}
+}
+
+func TestCacheTransportKeepsCallerConditionalRequests(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"1"`)
+		if r.Header.Get("If-None-Match") == `"1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = io.WriteString(w, "body")
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Transport: NewCacheTransport(nil)}
+	resp, err := httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("If-None-Match", `"1"`)
+	resp, err = httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("Expected the caller's conditional request to get the 304 response, got %d", resp.StatusCode)
+	}
+}
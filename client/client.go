@@ -4,10 +4,12 @@ package client
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"sync/atomic"
+	"time"
 
 	"github.com/mcpjungle/mcpjungle/internal/api"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
@@ -18,8 +20,16 @@ type Client struct {
 	baseURL     string
 	accessToken string
 	httpClient  *http.Client
+	userAgent   string
+	// idempotencyKeys is true if POST requests are sent with a generated idempotency key, so that they can be retried
+	idempotencyKeys bool
+
+	// rateLimit is the rate limit reported by the last response, nil if none was reported
+	rateLimit atomic.Pointer[RateLimit]
 }
 
+// NewClient creates a Client that sends requests with httpClient as-is.
+// Use New instead to get retries and timeouts configured with options.
 func NewClient(baseURL string, accessToken string, httpClient *http.Client) *Client {
 	return &Client{
 		baseURL:     baseURL,
@@ -33,61 +43,154 @@ func (c *Client) BaseURL() string {
 	return c.baseURL
 }
 
+// HTTPClient returns the HTTP client requests are sent with.
+func (c *Client) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
 // constructAPIEndpoint constructs the full API endpoint URL where a request must be sent
 func (c *Client) constructAPIEndpoint(suffixPath string) (string, error) {
-	return url.JoinPath(c.baseURL, api.V0ApiPathPrefix, suffixPath)
+	return url.JoinPath(c.baseURL, api.V1ApiPathPrefix, suffixPath)
 }
 
-// newRequest creates a new HTTP request with the specified method, URL, and body.
-// It automatically adds the Authorization header if an access token is present.
-func (c *Client) newRequest(method, url string, body io.Reader) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, body)
+// newRequest creates a new HTTP request bound to ctx with the specified method, URL, and body.
+// It automatically adds the Authorization header if an access token is present, and the User-Agent header if one is set.
+func (c *Client) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
 	if c.accessToken != "" {
 		req.Header.Set("Authorization", "Bearer "+c.accessToken)
 	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 	return req, nil
 }
 
-// ErrorResponse represents the JSON structure of error responses from the server
+// do sends req and records the rate limit reported by the response.
+// If the Client generates idempotency keys, one is set on POST requests that don't have one yet.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.idempotencyKeys && req.Method == http.MethodPost && req.Header.Get(IdempotencyKeyHeader) == "" {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return c.send(c.httpClient, req)
+}
+
+// send sends req with hc and records the rate limit reported by the response.
+func (c *Client) send(hc *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if rl, ok := ParseRateLimit(resp.Header); ok {
+		c.rateLimit.Store(&rl)
+	}
+	return resp, nil
+}
+
+// doStream is like do, for requests whose response body is read for as long as it takes, eg- streamed lists.
+// The timeout of the Client only bounds waiting for the response headers.
+func (c *Client) doStream(req *http.Request) (*http.Response, error) {
+	if c.httpClient.Timeout == 0 {
+		return c.do(req)
+	}
+	hc := *c.httpClient
+	hc.Timeout = 0
+
+	ctx, cancel := context.WithCancel(req.Context())
+	var timedOut atomic.Bool
+	timer := time.AfterFunc(c.httpClient.Timeout, func() {
+		timedOut.Store(true)
+		cancel()
+	})
+	resp, err := c.send(&hc, req.WithContext(ctx))
+	timer.Stop()
+	if err != nil {
+		cancel()
+		if timedOut.Load() {
+			// report it like the timeout of the http.Client would have been
+			return nil, &url.Error{Op: req.Method, URL: req.URL.String(), Err: os.ErrDeadlineExceeded}
+		}
+		return nil, err
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose is a response body that cancels the context of its request when closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// ErrorResponse represents the JSON structure of error responses from the server.
+// Error holds a types.APIError, or the error message as a string for servers older than the error codes.
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error json.RawMessage `json:"error"`
+	// RequiredRole is set by older servers when a request is rejected because the user lacks a role
+	RequiredRole string `json:"required_role,omitempty"`
 }
 
-// parseErrorResponse parses HTTP error responses (4xx and 5xx) and returns a user-friendly error message
+// parseErrorResponse parses HTTP error responses (4xx and 5xx) and returns a user-friendly error message.
+// The returned error is always an *APIError.
 func (c *Client) parseErrorResponse(resp *http.Response) error {
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("request failed with status: %d (unable to read error details)", resp.StatusCode)
+		return newAPIError(resp, "request failed with status: %d (unable to read error details)", resp.StatusCode)
 	}
 
 	// For 4xx and 5xx status codes, try to parse as JSON error response
 	if resp.StatusCode >= 400 && resp.StatusCode < 600 {
 		var errorResp ErrorResponse
-		err := json.Unmarshal(body, &errorResp)
-		if err != nil || errorResp.Error == "" {
+		var e types.APIError
+		if err := json.Unmarshal(body, &errorResp); err == nil && len(errorResp.Error) > 0 {
+			if json.Unmarshal(errorResp.Error, &e) != nil {
+				// older servers send the message as a string
+				_ = json.Unmarshal(errorResp.Error, &e.Message)
+			}
+		}
+		if e.Message == "" {
 			// If parsing as JSON fails or the error message is empty, return the raw response
-			return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, string(body))
+			return newAPIError(resp, "request failed with status: %d, message: %s", resp.StatusCode, string(body))
 		}
 		// Return the parsed error message
-		return fmt.Errorf("%s", errorResp.Error)
+		apiErr := newAPIError(resp, "%s", e.Message)
+		if e.Code != "" {
+			apiErr.Code = e.Code
+		} else {
+			apiErr.Code = legacyErrorCode(resp.StatusCode, e.Message)
+		}
+		apiErr.Details = e.Details
+		apiErr.RequiredRole = errorResp.RequiredRole
+		if role, ok := e.Details["required_role"].(string); ok {
+			apiErr.RequiredRole = role
+		}
+		return apiErr
 	}
 
 	// For any other status code, return the full response
-	return fmt.Errorf("unexpected response with status: %d, body: %s", resp.StatusCode, string(body))
+	return newAPIError(resp, "unexpected response with status: %d, body: %s", resp.StatusCode, string(body))
 }
 
 // GetServerMetadata fetches metadata about the MCPJungle server.
 func (c *Client) GetServerMetadata(ctx context.Context) (*types.ServerMetadata, error) {
-	req, err := c.newRequest(http.MethodGet, c.baseURL+"/metadata", nil)
+	req, err := c.newRequest(ctx, http.MethodGet, c.baseURL+"/metadata", nil)
 	if err != nil {
 		return nil, err
 	}
-	req = req.WithContext(ctx)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -104,3 +207,93 @@ func (c *Client) GetServerMetadata(ctx context.Context) (*types.ServerMetadata,
 
 	return &metadata, nil
 }
+
+// GetServerVersion fetches detailed version information about the MCPJungle server.
+// If the server is too old to expose the version endpoint, it falls back to the version reported in the metadata.
+func (c *Client) GetServerVersion(ctx context.Context) (*types.ServerVersion, error) {
+	u, err := url.JoinPath(c.baseURL, api.V1ApiPathPrefix, "/version")
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		metadata, err := c.GetServerMetadata(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &types.ServerVersion{Version: metadata.Version}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var v types.ServerVersion
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// GetServerReadiness fetches the readiness status of the MCPJungle server.
+// It succeeds even if the server reports that it is not ready, callers must check the Ready field.
+func (c *Client) GetServerReadiness(ctx context.Context) (*types.ServerReadiness, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.baseURL+"/ready", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var r types.ServerReadiness
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetServerInfo describes the server that answered the request, including its role among the replicas
+// sharing its database. Behind a load balancer, every call may be answered by another replica.
+func (c *Client) GetServerInfo(ctx context.Context) (*types.ServerInfo, error) {
+	u, err := c.constructAPIEndpoint("/server/info")
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var info types.ServerInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
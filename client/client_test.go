@@ -1,11 +1,18 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
 func TestNewClient(t *testing.T) {
@@ -51,17 +58,17 @@ func TestConstructAPIEndpoint(t *testing.T) {
 		{
 			name:         "simple path",
 			suffixPath:   "servers",
-			expectedPath: "https://api.example.com/api/v0/servers",
+			expectedPath: "https://api.example.com/api/v1/servers",
 		},
 		{
 			name:         "nested path",
 			suffixPath:   "servers/test-server",
-			expectedPath: "https://api.example.com/api/v0/servers/test-server",
+			expectedPath: "https://api.example.com/api/v1/servers/test-server",
 		},
 		{
 			name:         "empty suffix",
 			suffixPath:   "",
-			expectedPath: "https://api.example.com/api/v0",
+			expectedPath: "https://api.example.com/api/v1",
 		},
 	}
 
@@ -85,7 +92,7 @@ func TestNewRequest(t *testing.T) {
 
 	t.Run("request with access token", func(t *testing.T) {
 		body := strings.NewReader("test body")
-		req, err := client.newRequest(http.MethodPost, "https://api.example.com/test", body)
+		req, err := client.newRequest(context.Background(), http.MethodPost, "https://api.example.com/test", body)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -106,7 +113,7 @@ func TestNewRequest(t *testing.T) {
 
 	t.Run("request without access token", func(t *testing.T) {
 		clientNoToken := NewClient("https://api.example.com", "", &http.Client{})
-		req, err := clientNoToken.newRequest(http.MethodGet, "https://api.example.com/test", nil)
+		req, err := clientNoToken.newRequest(context.Background(), http.MethodGet, "https://api.example.com/test", nil)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -118,7 +125,7 @@ func TestNewRequest(t *testing.T) {
 	})
 
 	t.Run("request with nil body", func(t *testing.T) {
-		req, err := client.newRequest(http.MethodGet, "https://api.example.com/test", nil)
+		req, err := client.newRequest(context.Background(), http.MethodGet, "https://api.example.com/test", nil)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -135,7 +142,7 @@ func TestNewRequestWithInvalidURL(t *testing.T) {
 	client := NewClient("https://api.example.com", "token", &http.Client{})
 
 	// Test with invalid URL
-	req, err := client.newRequest(http.MethodGet, "://invalid-url", nil)
+	req, err := client.newRequest(context.Background(), http.MethodGet, "://invalid-url", nil)
 	if err == nil {
 		t.Error("Expected error for invalid URL, got nil")
 	}
@@ -174,7 +181,7 @@ func TestClientIntegration(t *testing.T) {
 		t.Fatalf("Failed to construct endpoint: %v", err)
 	}
 
-	req, err := client.newRequest(http.MethodGet, endpoint, nil)
+	req, err := client.newRequest(context.Background(), http.MethodGet, endpoint, nil)
 	if err != nil {
 		t.Fatalf("Failed to create request: %v", err)
 	}
@@ -237,17 +244,17 @@ func TestConstructAPIEndpointWithComplexPaths(t *testing.T) {
 		{
 			name:         "path with query params",
 			suffixPath:   "tools?server=test",
-			expectedPath: "https://api.example.com/api/v0/tools%3Fserver=test",
+			expectedPath: "https://api.example.com/api/v1/tools%3Fserver=test",
 		},
 		{
 			name:         "path with special characters",
 			suffixPath:   "servers/test-server-123",
-			expectedPath: "https://api.example.com/api/v0/servers/test-server-123",
+			expectedPath: "https://api.example.com/api/v1/servers/test-server-123",
 		},
 		{
 			name:         "path with multiple segments",
 			suffixPath:   "tool-groups/my-group/tools",
-			expectedPath: "https://api.example.com/api/v0/tool-groups/my-group/tools",
+			expectedPath: "https://api.example.com/api/v1/tool-groups/my-group/tools",
 		},
 	}
 
@@ -354,3 +361,484 @@ func TestParseErrorResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestGetServerVersion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("version endpoint", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/version" {
+				t.Errorf("Expected path /api/v1/version, got %s", r.URL.Path)
+			}
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"version":"v1.2.3","commit":"abc","min_client_version":"v1.0.0"}`))
+		}))
+		defer server.Close()
+
+		v, err := NewClient(server.URL, "", &http.Client{}).GetServerVersion(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if v.Version != "v1.2.3" || v.Commit != "abc" || v.MinClientVersion != "v1.0.0" {
+			t.Errorf("Unexpected version info: %+v", v)
+		}
+	})
+
+	t.Run("falls back to metadata on older servers", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/metadata" {
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`{"version":"v0.2.0"}`))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		v, err := NewClient(server.URL, "", &http.Client{}).GetServerVersion(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if v.Version != "v0.2.0" {
+			t.Errorf("Expected version v0.2.0, got %s", v.Version)
+		}
+	})
+}
+
+func TestParseErrorResponseReturnsAPIError(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("https://api.example.com", "token", &http.Client{})
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/api/v1/tool?name=github__foo", nil)
+	resp := &http.Response{
+		StatusCode: http.StatusForbidden,
+		Body:       io.NopCloser(strings.NewReader(`{"error":"user is not authorized to perform this action","required_role":"admin"}`)),
+		Request:    req,
+	}
+
+	err := client.parseErrorResponse(resp)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != http.StatusForbidden || apiErr.RequiredRole != "admin" {
+		t.Errorf("Unexpected status or required role: %+v", apiErr)
+	}
+	if apiErr.Method != http.MethodGet || apiErr.Path != "/api/v1/tool" || apiErr.Query.Get("name") != "github__foo" {
+		t.Errorf("Request details were not recorded: %+v", apiErr)
+	}
+	if err.Error() != "user is not authorized to perform this action" {
+		t.Errorf("Unexpected error message: %s", err.Error())
+	}
+	if !IsStatus(fmt.Errorf("wrapped: %w", err), http.StatusForbidden) {
+		t.Error("IsStatus should see through wrapped errors")
+	}
+}
+
+func TestParseErrorResponseDecodesErrorCodes(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("https://api.example.com", "token", &http.Client{})
+	parse := func(status int, body string) *APIError {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, "https://api.example.com/api/v1/servers", nil)
+		err := client.parseErrorResponse(&http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Request: req})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected an *APIError, got %T", err)
+		}
+		return apiErr
+	}
+
+	apiErr := parse(
+		http.StatusBadRequest,
+		`{"error":{"code":"validation_failed","message":"name is required","details":{"field":"name"}}}`,
+	)
+	if apiErr.Code != types.ErrorCodeValidationFailed || apiErr.Message != "name is required" || apiErr.Details["field"] != "name" {
+		t.Errorf("Unexpected error: %+v", apiErr)
+	}
+	if !errors.Is(fmt.Errorf("wrapped: %w", apiErr), ErrValidationFailed) || errors.Is(apiErr, ErrInvalidRequest) {
+		t.Error("errors.Is should match the error of the code only")
+	}
+	if apiErr.FieldErrors() != nil {
+		t.Errorf("Expected no field errors without an errors detail, got %v", apiErr.FieldErrors())
+	}
+
+	apiErr = parse(
+		http.StatusBadRequest,
+		`{"error":{"code":"validation_failed","message":"name is required; url is required",`+
+			`"details":{"field":"name","errors":[{"field":"name","message":"is required"},{"field":"url","message":"is required"}]}}}`,
+	)
+	if errs := apiErr.FieldErrors(); len(errs) != 2 || errs[1].Field != "url" || errs[1].Message != "is required" {
+		t.Errorf("Unexpected field errors: %+v", errs)
+	}
+
+	apiErr = parse(
+		http.StatusForbidden,
+		`{"error":{"code":"forbidden","message":"user is not authorized","details":{"required_role":"admin"}}}`,
+	)
+	if !errors.Is(apiErr, ErrForbidden) || apiErr.RequiredRole != "admin" {
+		t.Errorf("Unexpected error: %+v", apiErr)
+	}
+
+	// servers older than the error codes send the message as a string
+	if apiErr = parse(http.StatusConflict, `{"error":"duplicated key not allowed"}`); !errors.Is(apiErr, ErrAlreadyExists) {
+		t.Errorf("Expected the code to be derived from the status, got %q", apiErr.Code)
+	}
+	if apiErr = parse(http.StatusForbidden, `{"error":"server is not initialized"}`); !errors.Is(apiErr, ErrNotInitialized) {
+		t.Errorf("Expected an uninitialized server to be recognized, got %q", apiErr.Code)
+	}
+	if apiErr = parse(http.StatusNotFound, `404 page not found`); !errors.Is(apiErr, ErrNotFound) {
+		t.Errorf("Expected the code to be derived from the status, got %q", apiErr.Code)
+	}
+}
+
+func TestGetServerReadiness(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not ready", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/ready" {
+				t.Errorf("Expected path /ready, got %s", r.URL.Path)
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"ready":false,"database":"connection refused","initialized":false}`))
+		}))
+		defer server.Close()
+
+		r, err := NewClient(server.URL, "", &http.Client{}).GetServerReadiness(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if r.Ready || r.Database != "connection refused" {
+			t.Errorf("Unexpected readiness: %+v", r)
+		}
+	})
+
+	t.Run("older servers", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		_, err := NewClient(server.URL, "", &http.Client{}).GetServerReadiness(context.Background())
+		if !IsStatus(err, http.StatusNotFound) {
+			t.Errorf("Expected a not found error, got %v", err)
+		}
+	})
+}
+
+func TestGetServerInfo(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/server/info" {
+			t.Errorf("Expected path /api/v1/server/info, got %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"version":"1.2.3","mode":"enterprise","started_at":"2026-01-02T03:04:05Z",` +
+			`"replica":{"id":"mcpjungle-1","role":"follower","leader":"mcpjungle-0"}}`))
+	}))
+	defer server.Close()
+
+	info, err := NewClient(server.URL, "", &http.Client{}).GetServerInfo(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.Version != "1.2.3" || info.Replica.Role != types.ReplicaFollower || info.Replica.Leader != "mcpjungle-0" {
+		t.Errorf("Unexpected server info: %+v", info)
+	}
+}
+
+func TestRequestsAreBoundToContext(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", &http.Client{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-received
+		cancel()
+	}()
+
+	_, err := client.ListServersContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the request to be aborted with context.Canceled, got %v", err)
+	}
+}
+
+func TestDeprecatedMethodsStillWork(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name":"github"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", &http.Client{})
+	servers, err := client.ListServers() //nolint:staticcheck // the deprecated wrapper is what's being tested
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(servers) != 1 || servers[0].Name != "github" {
+		t.Errorf("Expected the github server, got %v", servers)
+	}
+}
+
+// TestDeprecatedMethodsDelegate checks that every deprecated method sends the same request, and returns the same
+// result, as the ...Context method it wraps.
+func TestDeprecatedMethodsDelegate(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.RequestURI(), body))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	// the deprecated methods are what's being tested
+	//nolint:staticcheck
+	tests := []struct {
+		name       string
+		deprecated func(c *Client) (any, error)
+		withCtx    func(ctx context.Context, c *Client) (any, error)
+	}{
+		{
+			"InitServer",
+			func(c *Client) (any, error) { return c.InitServer() },
+			func(ctx context.Context, c *Client) (any, error) { return c.InitServerContext(ctx) },
+		},
+		{
+			"ListMcpClients",
+			func(c *Client) (any, error) { return c.ListMcpClients() },
+			func(ctx context.Context, c *Client) (any, error) { return c.ListMcpClientsContext(ctx) },
+		},
+		{
+			"DeleteMcpClient",
+			func(c *Client) (any, error) { return nil, c.DeleteMcpClient("cursor") },
+			func(ctx context.Context, c *Client) (any, error) { return nil, c.DeleteMcpClientContext(ctx, "cursor") },
+		},
+		{
+			"CreateMcpClient",
+			func(c *Client) (any, error) { return c.CreateMcpClient(&types.McpClient{Name: "cursor"}) },
+			func(ctx context.Context, c *Client) (any, error) {
+				return c.CreateMcpClientContext(ctx, &types.McpClient{Name: "cursor"})
+			},
+		},
+		{
+			"UpdateMcpClient",
+			func(c *Client) (any, error) { return nil, c.UpdateMcpClient(&types.McpClient{Name: "cursor"}) },
+			func(ctx context.Context, c *Client) (any, error) {
+				return nil, c.UpdateMcpClientContext(ctx, &types.McpClient{Name: "cursor"})
+			},
+		},
+		{
+			"ListPrompts",
+			func(c *Client) (any, error) { return c.ListPrompts("github") },
+			func(ctx context.Context, c *Client) (any, error) { return c.ListPromptsContext(ctx, "github") },
+		},
+		{
+			"GetPrompt",
+			func(c *Client) (any, error) { return c.GetPrompt("github__review") },
+			func(ctx context.Context, c *Client) (any, error) { return c.GetPromptContext(ctx, "github__review") },
+		},
+		{
+			"GetPromptWithArgs",
+			func(c *Client) (any, error) {
+				return c.GetPromptWithArgs("github__review", map[string]string{"pr": "1"})
+			},
+			func(ctx context.Context, c *Client) (any, error) {
+				return c.GetPromptWithArgsContext(ctx, "github__review", map[string]string{"pr": "1"})
+			},
+		},
+		{
+			"EnablePrompts",
+			func(c *Client) (any, error) { return c.EnablePrompts("github") },
+			func(ctx context.Context, c *Client) (any, error) { return c.EnablePromptsContext(ctx, "github") },
+		},
+		{
+			"DisablePrompts",
+			func(c *Client) (any, error) { return c.DisablePrompts("github") },
+			func(ctx context.Context, c *Client) (any, error) { return c.DisablePromptsContext(ctx, "github") },
+		},
+		{
+			"RegisterServer",
+			func(c *Client) (any, error) { return c.RegisterServer(&types.RegisterServerInput{Name: "github"}) },
+			func(ctx context.Context, c *Client) (any, error) {
+				return c.RegisterServerContext(ctx, &types.RegisterServerInput{Name: "github"})
+			},
+		},
+		{
+			"UpdateServer",
+			func(c *Client) (any, error) { return c.UpdateServer(&types.RegisterServerInput{Name: "github"}) },
+			func(ctx context.Context, c *Client) (any, error) {
+				return c.UpdateServerContext(ctx, &types.RegisterServerInput{Name: "github"})
+			},
+		},
+		{
+			"ListServers",
+			func(c *Client) (any, error) { return c.ListServers() },
+			func(ctx context.Context, c *Client) (any, error) { return c.ListServersContext(ctx) },
+		},
+		{
+			"GetServerConfigs",
+			func(c *Client) (any, error) { return c.GetServerConfigs() },
+			func(ctx context.Context, c *Client) (any, error) { return c.GetServerConfigsContext(ctx) },
+		},
+		{
+			"DeregisterServer",
+			func(c *Client) (any, error) { return nil, c.DeregisterServer("github") },
+			func(ctx context.Context, c *Client) (any, error) {
+				return nil, c.DeregisterServerContext(ctx, "github")
+			},
+		},
+		{
+			"EnableServer",
+			func(c *Client) (any, error) { return c.EnableServer("github") },
+			func(ctx context.Context, c *Client) (any, error) { return c.EnableServerContext(ctx, "github") },
+		},
+		{
+			"DisableServer",
+			func(c *Client) (any, error) { return c.DisableServer("github") },
+			func(ctx context.Context, c *Client) (any, error) { return c.DisableServerContext(ctx, "github") },
+		},
+		{
+			"ListTools",
+			func(c *Client) (any, error) { return c.ListTools("github") },
+			func(ctx context.Context, c *Client) (any, error) { return c.ListToolsContext(ctx, "github") },
+		},
+		{
+			"EnableTools",
+			func(c *Client) (any, error) { return c.EnableTools("github") },
+			func(ctx context.Context, c *Client) (any, error) { return c.EnableToolsContext(ctx, "github") },
+		},
+		{
+			"DisableTools",
+			func(c *Client) (any, error) { return c.DisableTools("github") },
+			func(ctx context.Context, c *Client) (any, error) { return c.DisableToolsContext(ctx, "github") },
+		},
+		{
+			"GetTool",
+			func(c *Client) (any, error) { return c.GetTool("github__get_me") },
+			func(ctx context.Context, c *Client) (any, error) { return c.GetToolContext(ctx, "github__get_me") },
+		},
+		{
+			"InvokeTool",
+			func(c *Client) (any, error) { return c.InvokeTool("github__get_me", map[string]any{"a": 1}) },
+			func(ctx context.Context, c *Client) (any, error) {
+				return c.InvokeToolContext(ctx, "github__get_me", map[string]any{"a": 1})
+			},
+		},
+		{
+			"CreateToolGroup",
+			func(c *Client) (any, error) { return c.CreateToolGroup(&types.ToolGroup{Name: "dev"}) },
+			func(ctx context.Context, c *Client) (any, error) {
+				return c.CreateToolGroupContext(ctx, &types.ToolGroup{Name: "dev"})
+			},
+		},
+		{
+			"DeleteToolGroup",
+			func(c *Client) (any, error) { return nil, c.DeleteToolGroup("dev") },
+			func(ctx context.Context, c *Client) (any, error) { return nil, c.DeleteToolGroupContext(ctx, "dev") },
+		},
+		{
+			"ListToolGroups",
+			func(c *Client) (any, error) { return c.ListToolGroups() },
+			func(ctx context.Context, c *Client) (any, error) { return c.ListToolGroupsContext(ctx) },
+		},
+		{
+			"GetToolGroup",
+			func(c *Client) (any, error) { return c.GetToolGroup("dev") },
+			func(ctx context.Context, c *Client) (any, error) { return c.GetToolGroupContext(ctx, "dev") },
+		},
+		{
+			"UpdateToolGroup",
+			func(c *Client) (any, error) { return c.UpdateToolGroup(&types.ToolGroup{Name: "dev"}) },
+			func(ctx context.Context, c *Client) (any, error) {
+				return c.UpdateToolGroupContext(ctx, &types.ToolGroup{Name: "dev"})
+			},
+		},
+		{
+			"GetToolGroupConfigs",
+			func(c *Client) (any, error) { return c.GetToolGroupConfigs() },
+			func(ctx context.Context, c *Client) (any, error) { return c.GetToolGroupConfigsContext(ctx) },
+		},
+		{
+			"GetToolReferences",
+			func(c *Client) (any, error) { return c.GetToolReferences("github", []string{"get_me"}) },
+			func(ctx context.Context, c *Client) (any, error) {
+				return c.GetToolReferencesContext(ctx, "github", []string{"get_me"})
+			},
+		},
+		{
+			"CreateUser",
+			func(c *Client) (any, error) { return c.CreateUser(&types.CreateOrUpdateUserRequest{Username: "alice"}) },
+			func(ctx context.Context, c *Client) (any, error) {
+				return c.CreateUserContext(ctx, &types.CreateOrUpdateUserRequest{Username: "alice"})
+			},
+		},
+		{
+			"DeleteUser",
+			func(c *Client) (any, error) { return nil, c.DeleteUser("alice") },
+			func(ctx context.Context, c *Client) (any, error) { return nil, c.DeleteUserContext(ctx, "alice") },
+		},
+		{
+			"UpdateUser",
+			func(c *Client) (any, error) { return c.UpdateUser(&types.CreateOrUpdateUserRequest{Username: "alice"}) },
+			func(ctx context.Context, c *Client) (any, error) {
+				return c.UpdateUserContext(ctx, &types.CreateOrUpdateUserRequest{Username: "alice"})
+			},
+		},
+		{
+			"ListUsers",
+			func(c *Client) (any, error) { return c.ListUsers() },
+			func(ctx context.Context, c *Client) (any, error) { return c.ListUsersContext(ctx) },
+		},
+		{
+			"Whoami",
+			func(c *Client) (any, error) { return c.Whoami("token") },
+			func(ctx context.Context, c *Client) (any, error) { return c.WhoamiContext(ctx, "token") },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(server.URL, "", &http.Client{})
+			record := func(call func() (any, error)) (string, string) {
+				mu.Lock()
+				requests = nil
+				mu.Unlock()
+				res, err := call()
+				data, _ := json.Marshal(res)
+				mu.Lock()
+				defer mu.Unlock()
+				return fmt.Sprintf("%s %v", data, err), strings.Join(requests, "\n")
+			}
+			gotResult, gotRequests := record(func() (any, error) { return tt.deprecated(c) })
+			wantResult, wantRequests := record(func() (any, error) { return tt.withCtx(context.Background(), c) })
+			if gotRequests == "" || gotRequests != wantRequests {
+				t.Errorf("Expected the requests of %sContext:\n%s\ngot:\n%s", tt.name, wantRequests, gotRequests)
+			}
+			if gotResult != wantResult {
+				t.Errorf("Expected the result of %sContext %s, got %s", tt.name, wantResult, gotResult)
+			}
+		})
+	}
+}
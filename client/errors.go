@@ -0,0 +1,165 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// Errors matched by the APIErrors with the corresponding error code, eg- errors.Is(err, client.ErrNotFound).
+// See types.ErrorCode for the meaning of every code.
+var (
+	ErrInvalidRequest       = errors.New("invalid request")
+	ErrValidationFailed     = errors.New("validation failed")
+	ErrUnauthorized         = errors.New("unauthorized")
+	ErrForbidden            = errors.New("forbidden")
+	ErrNotInitialized       = errors.New("server not initialized")
+	ErrWrongMode            = errors.New("not available in the server mode")
+	ErrNotFound             = errors.New("not found")
+	ErrAlreadyExists        = errors.New("already exists")
+	ErrRequestInProgress    = errors.New("request in progress")
+	ErrRegistryNotEmpty     = errors.New("registry not empty")
+	ErrVersionConflict      = errors.New("version conflict")
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused")
+	ErrIncompatibleBackup   = errors.New("incompatible backup")
+	ErrBatchFailed          = errors.New("batch failed")
+	ErrRateLimited          = errors.New("rate limited")
+	ErrInternal             = errors.New("internal server error")
+	ErrUpstreamUnreachable  = errors.New("upstream MCP server unreachable")
+	ErrUnavailable          = errors.New("unavailable")
+)
+
+var codeErrors = map[types.ErrorCode]error{
+	types.ErrorCodeInvalidRequest:       ErrInvalidRequest,
+	types.ErrorCodeValidationFailed:     ErrValidationFailed,
+	types.ErrorCodeUnauthorized:         ErrUnauthorized,
+	types.ErrorCodeForbidden:            ErrForbidden,
+	types.ErrorCodeNotInitialized:       ErrNotInitialized,
+	types.ErrorCodeWrongMode:            ErrWrongMode,
+	types.ErrorCodeNotFound:             ErrNotFound,
+	types.ErrorCodeAlreadyExists:        ErrAlreadyExists,
+	types.ErrorCodeRequestInProgress:    ErrRequestInProgress,
+	types.ErrorCodeRegistryNotEmpty:     ErrRegistryNotEmpty,
+	types.ErrorCodeVersionConflict:      ErrVersionConflict,
+	types.ErrorCodeIdempotencyKeyReused: ErrIdempotencyKeyReused,
+	types.ErrorCodeIncompatibleBackup:   ErrIncompatibleBackup,
+	types.ErrorCodeBatchFailed:          ErrBatchFailed,
+	types.ErrorCodeRateLimited:          ErrRateLimited,
+	types.ErrorCodeInternal:             ErrInternal,
+	types.ErrorCodeUpstreamUnreachable:  ErrUpstreamUnreachable,
+	types.ErrorCodeUnavailable:          ErrUnavailable,
+}
+
+// legacyErrorCode returns the error code of an error response without one, sent by a server older than the error codes.
+func legacyErrorCode(status int, message string) types.ErrorCode {
+	if status == http.StatusForbidden {
+		switch {
+		case strings.Contains(message, "not initialized"):
+			return types.ErrorCodeNotInitialized
+		case strings.Contains(message, "only allowed in"):
+			return types.ErrorCodeWrongMode
+		}
+	}
+	return statusCodes[status]
+}
+
+// statusCodes are the error codes assumed for the responses of servers older than the error codes, by status.
+var statusCodes = map[int]types.ErrorCode{
+	http.StatusBadRequest:          types.ErrorCodeInvalidRequest,
+	http.StatusUnauthorized:        types.ErrorCodeUnauthorized,
+	http.StatusForbidden:           types.ErrorCodeForbidden,
+	http.StatusNotFound:            types.ErrorCodeNotFound,
+	http.StatusConflict:            types.ErrorCodeAlreadyExists,
+	http.StatusPreconditionFailed:  types.ErrorCodeVersionConflict,
+	http.StatusTooManyRequests:     types.ErrorCodeRateLimited,
+	http.StatusInternalServerError: types.ErrorCodeInternal,
+	http.StatusServiceUnavailable:  types.ErrorCodeUnavailable,
+}
+
+// APIError is returned by the client when the MCPJungle server responds to a request with an error status.
+// It carries enough detail about the failed request for callers (eg- the CLI) to explain the failure to the user.
+type APIError struct {
+	// StatusCode is the HTTP status code returned by the server
+	StatusCode int
+	// Code identifies the kind of failure. For servers older than the error codes, it is derived from the status.
+	Code types.ErrorCode
+	// Message is the error message returned by the server, or a description of the response if it had none
+	Message string
+	// Details holds information specific to the code, eg- the "field" that failed validation
+	Details map[string]any
+	// RequiredRole is the role the user needs to perform the request, if the server reported one
+	RequiredRole string
+	// RateLimit is the rate limit of the caller reported by the response, nil if it reported none.
+	// It tells when requests can be made again after a 429 response.
+	RateLimit *RateLimit
+	// RetryAfter is how long the server asked to wait before retrying the request, 0 if it didn't.
+	RetryAfter time.Duration
+
+	// Method, Path and Query identify the request that failed.
+	// They are empty if the request is not known.
+	Method string
+	Path   string
+	Query  url.Values
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is the error of the APIError's code, eg- ErrNotFound.
+func (e *APIError) Is(target error) bool {
+	return target != nil && codeErrors[e.Code] == target
+}
+
+// FieldErrors returns the invalid fields of the request that the server reported, if it failed validation.
+// Servers older than the field-level validation errors only report the first invalid field, in Details["field"].
+func (e *APIError) FieldErrors() types.ValidationErrors {
+	raw, ok := e.Details["errors"]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var errs types.ValidationErrors
+	if err := json.Unmarshal(data, &errs); err != nil {
+		return nil
+	}
+	return errs
+}
+
+// IsStatus reports whether err is an APIError with the given HTTP status code.
+func IsStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// newAPIError creates an APIError for the given response and message.
+func newAPIError(resp *http.Response, format string, args ...any) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Code:       statusCodes[resp.StatusCode],
+		Message:    fmt.Sprintf(format, args...),
+	}
+	if rl, ok := ParseRateLimit(resp.Header); ok {
+		e.RateLimit = &rl
+	}
+	if d, ok := retryAfter(resp, time.Now()); ok {
+		e.RetryAfter = d
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			e.Path = resp.Request.URL.Path
+			e.Query = resp.Request.URL.Query()
+		}
+	}
+	return e
+}
@@ -0,0 +1,16 @@
+package client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// newIdempotencyKey returns a random idempotency key.
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate an idempotency key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
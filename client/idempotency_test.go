@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestIdempotencyKeys(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	// the first attempt of every request fails, the second one succeeds
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		attempt := len(keys)
+		mu.Unlock()
+		if attempt%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"tool_group_endpoint": "http://localhost/v0/groups/ci-tools/mcp"}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "", WithBackoff(time.Millisecond, time.Millisecond))
+	for range 2 {
+		_, err := c.CreateToolGroupContext(context.Background(), &types.ToolGroup{Name: "ci-tools"})
+		testhelpers.AssertNoError(t, err)
+	}
+	testhelpers.AssertEqual(t, 4, len(keys))
+	testhelpers.AssertTrue(t, keys[0] != "", "POST requests should be sent with an idempotency key")
+	testhelpers.AssertEqual(t, keys[0], keys[1])
+	testhelpers.AssertTrue(t, keys[2] != keys[0], "every request should have its own key")
+	testhelpers.AssertEqual(t, keys[2], keys[3])
+
+	// without retries, there is no need for keys
+	keys = nil
+	c = New(srv.URL, "", WithRetries(0))
+	_, err := c.CreateToolGroupContext(context.Background(), &types.ToolGroup{Name: "ci-tools"})
+	testhelpers.AssertTrue(t, err != nil, "the request should not have been retried")
+	testhelpers.AssertEqual(t, 1, len(keys))
+	testhelpers.AssertEqual(t, "", keys[0])
+}
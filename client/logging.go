@@ -0,0 +1,179 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Verbosity levels supported by LoggingTransport
+const (
+	// LogRequests logs the method, URL, status and duration of each request.
+	LogRequests = 1
+	// LogBodies additionally logs request & response headers and bodies, with secrets redacted.
+	LogBodies = 2
+)
+
+// redacted is the placeholder that replaces secret values in logs.
+const redacted = "[REDACTED]"
+
+// maxLoggedBodySize is the maximum number of bytes of a body that is logged, the rest is truncated.
+const maxLoggedBodySize = 64 * 1024
+
+// secretKeyPattern matches names of headers and JSON fields whose values must never be logged.
+var secretKeyPattern = regexp.MustCompile(`(?i)(authorization|token|secret|password|passwd|api[_-]?key|cookie)`)
+
+// LoggingTransport is an http.RoundTripper that logs every request made through it.
+// It is used by the CLI to implement the --verbose flag.
+type LoggingTransport struct {
+	base  http.RoundTripper
+	out   io.Writer
+	level int
+}
+
+// NewLoggingTransport wraps base (http.DefaultTransport if nil) so that requests are logged to out
+// at the given verbosity level (LogRequests or LogBodies).
+func NewLoggingTransport(base http.RoundTripper, out io.Writer, level int) *LoggingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &LoggingTransport{base: base, out: out, level: level}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.level >= LogBodies {
+		t.logf("> %s %s", req.Method, req.URL.String())
+		t.logHeaders(">", req.Header)
+		if req.Body != nil && req.Body != http.NoBody {
+			body, err := io.ReadAll(req.Body)
+			_ = req.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			t.logBody(">", body)
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		t.logf("%s %s failed after %s: %v", req.Method, req.URL.String(), elapsed, err)
+		return nil, err
+	}
+	if rl, ok := ParseRateLimit(resp.Header); ok {
+		// the budget helps understand why bulk operations get throttled
+		t.logf(
+			"%s %s -> %d (%s, %d of %d requests left, reset in %s)",
+			req.Method, req.URL.String(), resp.StatusCode, elapsed,
+			rl.Remaining, rl.Limit, max(time.Until(rl.Reset), 0).Round(time.Second),
+		)
+	} else {
+		t.logf("%s %s -> %d (%s)", req.Method, req.URL.String(), resp.StatusCode, elapsed)
+	}
+
+	if t.level >= LogBodies {
+		t.logHeaders("<", resp.Header)
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		t.logBody("<", body)
+	}
+	return resp, nil
+}
+
+func (t *LoggingTransport) logf(format string, args ...any) {
+	_, _ = fmt.Fprintf(t.out, "[http] "+format+"\n", args...)
+}
+
+func (t *LoggingTransport) logHeaders(prefix string, h http.Header) {
+	safe := RedactHeaders(h)
+	for _, name := range sortedHeaderNames(safe) {
+		t.logf("%s %s: %s", prefix, name, strings.Join(safe[name], ", "))
+	}
+}
+
+func (t *LoggingTransport) logBody(prefix string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	s := RedactBody(body)
+	if len(s) > maxLoggedBodySize {
+		s = s[:maxLoggedBodySize] + "... (truncated)"
+	}
+	t.logf("%s %s", prefix, s)
+}
+
+func sortedHeaderNames(h http.Header) []string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RedactHeaders returns a copy of h in which the values of secret headers (eg- Authorization) are redacted.
+func RedactHeaders(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for name, values := range h {
+		if secretKeyPattern.MatchString(name) {
+			out[name] = []string{redacted}
+			continue
+		}
+		out[name] = values
+	}
+	return out
+}
+
+// RedactBody returns the body as a string with the values of secret JSON fields redacted.
+// JSON fields are considered secret if their name looks like a credential (eg- "access_token", "password").
+// Bodies that are not valid JSON can't be inspected field by field, so they are redacted entirely
+// if they look like they contain a secret.
+func RedactBody(body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		if secretKeyPattern.Match(body) {
+			return redacted
+		}
+		return string(body)
+	}
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return redacted
+	}
+	return string(out)
+}
+
+func redactValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if secretKeyPattern.MatchString(k) {
+				val[k] = redacted
+				continue
+			}
+			val[k] = redactValue(child)
+		}
+		return val
+	case []any:
+		for i, child := range val {
+			val[i] = redactValue(child)
+		}
+		return val
+	default:
+		return v
+	}
+}
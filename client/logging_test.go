@@ -0,0 +1,143 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	t.Parallel()
+
+	h := http.Header{}
+	h.Set("Authorization", "Bearer super-secret")
+	h.Set("X-Api-Key", "key-123")
+	h.Set("Content-Type", "application/json")
+
+	got := RedactHeaders(h)
+	if got.Get("Authorization") != redacted {
+		t.Errorf("Authorization header was not redacted: %s", got.Get("Authorization"))
+	}
+	if got.Get("X-Api-Key") != redacted {
+		t.Errorf("X-Api-Key header was not redacted: %s", got.Get("X-Api-Key"))
+	}
+	if got.Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type header should be kept, got %s", got.Get("Content-Type"))
+	}
+	if h.Get("Authorization") != "Bearer super-secret" {
+		t.Error("RedactHeaders must not modify the original headers")
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		body        string
+		mustHide    []string
+		mustContain []string
+	}{
+		{
+			name:        "top level token fields",
+			body:        `{"username":"alice","access_token":"tok-1","admin_access_token":"tok-2"}`,
+			mustHide:    []string{"tok-1", "tok-2"},
+			mustContain: []string{"alice"},
+		},
+		{
+			name:        "nested server config",
+			body:        `{"name":"github","bearer_token":"ghp_abc","env":{"GITHUB_TOKEN":"ghp_def","LOG_LEVEL":"debug"}}`,
+			mustHide:    []string{"ghp_abc", "ghp_def"},
+			mustContain: []string{"github", "debug"},
+		},
+		{
+			name:        "arrays of objects",
+			body:        `[{"name":"c1","access_token":"t1"},{"name":"c2","password":"p2"}]`,
+			mustHide:    []string{"t1", "p2"},
+			mustContain: []string{"c1", "c2"},
+		},
+		{
+			name:     "non-json body mentioning a secret",
+			body:     `access_token=abc123`,
+			mustHide: []string{"abc123"},
+		},
+		{
+			name:        "non-json body without secrets",
+			body:        `plain text error`,
+			mustContain: []string{"plain text error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := RedactBody([]byte(tt.body))
+			for _, s := range tt.mustHide {
+				if strings.Contains(got, s) {
+					t.Errorf("secret %q leaked in %s", s, got)
+				}
+			}
+			for _, s := range tt.mustContain {
+				if !strings.Contains(got, s) {
+					t.Errorf("expected %q in %s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLoggingTransport(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"access_token":"req-secret"}` {
+			t.Errorf("request body was not passed through intact: %s", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"name":"u1","access_token":"resp-secret"}`))
+	}))
+	defer server.Close()
+
+	for _, level := range []int{LogRequests, LogBodies} {
+		logs := &bytes.Buffer{}
+		c := NewClient(server.URL, "bearer-secret", &http.Client{
+			Transport: NewLoggingTransport(nil, logs, level),
+		})
+
+		req, err := c.newRequest(context.Background(), http.MethodPost, server.URL+"/api/v1/users", strings.NewReader(`{"access_token":"req-secret"}`))
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		if !strings.Contains(string(body), "resp-secret") {
+			t.Errorf("response body must reach the caller unredacted, got %s", body)
+		}
+
+		out := logs.String()
+		if !strings.Contains(out, "POST "+server.URL+"/api/v1/users -> 201") {
+			t.Errorf("level %d: expected request summary in logs, got:\n%s", level, out)
+		}
+		for _, secret := range []string{"bearer-secret", "req-secret", "resp-secret"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("level %d: secret %q leaked into logs:\n%s", level, secret, out)
+			}
+		}
+		if level == LogBodies && !strings.Contains(out, `"name":"u1"`) {
+			t.Errorf("expected response body in logs at level %d, got:\n%s", level, out)
+		}
+		if level == LogRequests && strings.Contains(out, `"name":"u1"`) {
+			t.Errorf("bodies must not be logged at level %d, got:\n%s", level, out)
+		}
+	}
+}
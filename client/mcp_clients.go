@@ -2,48 +2,47 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
-func (c *Client) ListMcpClients() ([]types.McpClient, error) {
-	u, _ := c.constructAPIEndpoint("/clients")
-
-	req, err := c.newRequest(http.MethodGet, u, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
-	}
-	defer resp.Body.Close()
+// ListMcpClientsContext fetches the list of MCP clients, all pages of it.
+func (c *Client) ListMcpClientsContext(ctx context.Context) ([]types.McpClient, error) {
+	return collect(c.AllMcpClients(ctx))
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, c.parseErrorResponse(resp)
-	}
+// ListMcpClientsPage fetches a page of the list of MCP clients.
+func (c *Client) ListMcpClientsPage(ctx context.Context, opts PageOptions) (*types.Page[types.McpClient], error) {
+	return fetchPage[types.McpClient](ctx, c, "/clients", nil, opts)
+}
 
-	var clients []types.McpClient
-	if err := json.NewDecoder(resp.Body).Decode(&clients); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
+// AllMcpClients iterates over all MCP clients, fetching pages as needed.
+func (c *Client) AllMcpClients(ctx context.Context) iter.Seq2[types.McpClient, error] {
+	return allPages(ctx, c.ListMcpClientsPage)
+}
 
-	return clients, nil
+// ListMcpClients is like ListMcpClientsContext, without a context.
+//
+// Deprecated: use ListMcpClientsContext instead, ListMcpClients will be removed in the next release.
+func (c *Client) ListMcpClients() ([]types.McpClient, error) {
+	return c.ListMcpClientsContext(context.Background())
 }
 
-func (c *Client) DeleteMcpClient(name string) error {
+// DeleteMcpClientContext deletes an MCP client by its name or UUID.
+func (c *Client) DeleteMcpClientContext(ctx context.Context, name string) error {
 	u, _ := c.constructAPIEndpoint("/clients/" + name)
 
-	req, err := c.newRequest(http.MethodDelete, u, nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, u, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -56,7 +55,14 @@ func (c *Client) DeleteMcpClient(name string) error {
 	return nil
 }
 
-func (c *Client) CreateMcpClient(mcpClient *types.McpClient) (string, error) {
+// DeleteMcpClient is like DeleteMcpClientContext, without a context.
+//
+// Deprecated: use DeleteMcpClientContext instead, DeleteMcpClient will be removed in the next release.
+func (c *Client) DeleteMcpClient(name string) error {
+	return c.DeleteMcpClientContext(context.Background(), name)
+}
+
+func (c *Client) CreateMcpClientContext(ctx context.Context, mcpClient *types.McpClient) (string, error) {
 	u, _ := c.constructAPIEndpoint("/clients")
 
 	body, err := json.Marshal(mcpClient)
@@ -64,13 +70,13 @@ func (c *Client) CreateMcpClient(mcpClient *types.McpClient) (string, error) {
 		return "", fmt.Errorf("failed to marshal client data: %w", err)
 	}
 
-	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	req, err := c.newRequest(ctx, http.MethodPost, u, bytes.NewReader(body))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -90,7 +96,14 @@ func (c *Client) CreateMcpClient(mcpClient *types.McpClient) (string, error) {
 	return response.AccessToken, nil
 }
 
-func (c *Client) UpdateMcpClient(mcpClient *types.McpClient) error {
+// CreateMcpClient is like CreateMcpClientContext, without a context.
+//
+// Deprecated: use CreateMcpClientContext instead, CreateMcpClient will be removed in the next release.
+func (c *Client) CreateMcpClient(mcpClient *types.McpClient) (string, error) {
+	return c.CreateMcpClientContext(context.Background(), mcpClient)
+}
+
+func (c *Client) UpdateMcpClientContext(ctx context.Context, mcpClient *types.McpClient) error {
 	u, _ := c.constructAPIEndpoint("/clients/" + mcpClient.Name)
 
 	body, err := json.Marshal(mcpClient)
@@ -98,13 +111,13 @@ func (c *Client) UpdateMcpClient(mcpClient *types.McpClient) error {
 		return fmt.Errorf("failed to marshal client data: %w", err)
 	}
 
-	req, err := c.newRequest(http.MethodPut, u, bytes.NewReader(body))
+	req, err := c.newRequest(ctx, http.MethodPut, u, bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -116,3 +129,10 @@ func (c *Client) UpdateMcpClient(mcpClient *types.McpClient) error {
 
 	return nil
 }
+
+// UpdateMcpClient is like UpdateMcpClientContext, without a context.
+//
+// Deprecated: use UpdateMcpClientContext instead, UpdateMcpClient will be removed in the next release.
+func (c *Client) UpdateMcpClient(mcpClient *types.McpClient) error {
+	return c.UpdateMcpClientContext(context.Background(), mcpClient)
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -50,7 +51,7 @@ func TestListMcpClients(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		clients, err := client.ListMcpClients()
+		clients, err := client.ListMcpClientsContext(context.Background())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -78,7 +79,7 @@ func TestListMcpClients(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		clients, err := client.ListMcpClients()
+		clients, err := client.ListMcpClientsContext(context.Background())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -96,7 +97,7 @@ func TestListMcpClients(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		clients, err := client.ListMcpClients()
+		clients, err := client.ListMcpClientsContext(context.Background())
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -113,7 +114,7 @@ func TestListMcpClients(t *testing.T) {
 
 	t.Run("network error", func(t *testing.T) {
 		client := NewClient("http://invalid-url", "test-token", &http.Client{})
-		clients, err := client.ListMcpClients()
+		clients, err := client.ListMcpClientsContext(context.Background())
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -139,7 +140,7 @@ func TestDeleteMcpClient(t *testing.T) {
 			if r.Method != "DELETE" {
 				t.Errorf("Expected DELETE method, got %s", r.Method)
 			}
-			expectedPath := "/api/v0/clients/" + clientName
+			expectedPath := "/api/v1/clients/" + clientName
 			if !strings.HasSuffix(r.URL.Path, expectedPath) {
 				t.Errorf("Expected path to end with %s, got %s", expectedPath, r.URL.Path)
 			}
@@ -156,7 +157,7 @@ func TestDeleteMcpClient(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		err := client.DeleteMcpClient(clientName)
+		err := client.DeleteMcpClientContext(context.Background(), clientName)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -170,7 +171,7 @@ func TestDeleteMcpClient(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		err := client.DeleteMcpClient("non-existent-client")
+		err := client.DeleteMcpClientContext(context.Background(), "non-existent-client")
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -184,7 +185,7 @@ func TestDeleteMcpClient(t *testing.T) {
 
 	t.Run("network error", func(t *testing.T) {
 		client := NewClient("http://invalid-url", "test-token", &http.Client{})
-		err := client.DeleteMcpClient("test-client")
+		err := client.DeleteMcpClientContext(context.Background(), "test-client")
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -255,7 +256,7 @@ func TestCreateMcpClient(t *testing.T) {
 			AllowList:   []string{"server1", "server2"},
 		}
 
-		accessToken, err := client.CreateMcpClient(mcpClient)
+		accessToken, err := client.CreateMcpClientContext(context.Background(), mcpClient)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -279,7 +280,7 @@ func TestCreateMcpClient(t *testing.T) {
 			AllowList:   []string{"server1"},
 		}
 
-		accessToken, err := client.CreateMcpClient(mcpClient)
+		accessToken, err := client.CreateMcpClientContext(context.Background(), mcpClient)
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -302,7 +303,7 @@ func TestCreateMcpClient(t *testing.T) {
 			AllowList:   []string{"server1"},
 		}
 
-		accessToken, err := client.CreateMcpClient(mcpClient)
+		accessToken, err := client.CreateMcpClientContext(context.Background(), mcpClient)
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -331,7 +332,7 @@ func TestCreateMcpClient(t *testing.T) {
 			AllowList:   []string{"server1"},
 		}
 
-		accessToken, err := client.CreateMcpClient(mcpClient)
+		accessToken, err := client.CreateMcpClientContext(context.Background(), mcpClient)
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -379,7 +380,7 @@ func TestCreateMcpClientWithEmptyAllowList(t *testing.T) {
 		AllowList:   []string{},
 	}
 
-	_, err := client.CreateMcpClient(mcpClient)
+	_, err := client.CreateMcpClientContext(context.Background(), mcpClient)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
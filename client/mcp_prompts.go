@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,8 +13,8 @@ import (
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
-// ListPrompts retrieves all prompts or prompts filtered by server name
-func (c *Client) ListPrompts(serverName string) ([]model.Prompt, error) {
+// ListPromptsContext retrieves all prompts or prompts filtered by server name
+func (c *Client) ListPromptsContext(ctx context.Context, serverName string) ([]model.Prompt, error) {
 	u, err := c.constructAPIEndpoint("/prompts")
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct API endpoint: %w", err)
@@ -28,12 +29,12 @@ func (c *Client) ListPrompts(serverName string) ([]model.Prompt, error) {
 		u = parsed.String()
 	}
 
-	req, err := c.newRequest(http.MethodGet, u, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -51,8 +52,15 @@ func (c *Client) ListPrompts(serverName string) ([]model.Prompt, error) {
 	return prompts, nil
 }
 
-// GetPrompt retrieves a specific prompt by name
-func (c *Client) GetPrompt(name string) (*model.Prompt, error) {
+// ListPrompts is like ListPromptsContext, without a context.
+//
+// Deprecated: use ListPromptsContext instead, ListPrompts will be removed in the next release.
+func (c *Client) ListPrompts(serverName string) ([]model.Prompt, error) {
+	return c.ListPromptsContext(context.Background(), serverName)
+}
+
+// GetPromptContext retrieves a specific prompt by name
+func (c *Client) GetPromptContext(ctx context.Context, name string) (*model.Prompt, error) {
 	u, err := c.constructAPIEndpoint("/prompt")
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct API endpoint: %w", err)
@@ -65,12 +73,12 @@ func (c *Client) GetPrompt(name string) (*model.Prompt, error) {
 	parsed.RawQuery = q.Encode()
 	u = parsed.String()
 
-	req, err := c.newRequest(http.MethodGet, u, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -88,8 +96,15 @@ func (c *Client) GetPrompt(name string) (*model.Prompt, error) {
 	return &prompt, nil
 }
 
-// GetPromptWithArgs retrieves a prompt with arguments and returns the rendered template
-func (c *Client) GetPromptWithArgs(name string, arguments map[string]string) (*types.PromptResult, error) {
+// GetPrompt is like GetPromptContext, without a context.
+//
+// Deprecated: use GetPromptContext instead, GetPrompt will be removed in the next release.
+func (c *Client) GetPrompt(name string) (*model.Prompt, error) {
+	return c.GetPromptContext(context.Background(), name)
+}
+
+// GetPromptWithArgsContext retrieves a prompt with arguments and returns the rendered template
+func (c *Client) GetPromptWithArgsContext(ctx context.Context, name string, arguments map[string]string) (*types.PromptResult, error) {
 	u, err := c.constructAPIEndpoint("/prompts/render")
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct API endpoint: %w", err)
@@ -105,13 +120,13 @@ func (c *Client) GetPromptWithArgs(name string, arguments map[string]string) (*t
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := c.newRequest(http.MethodPost, u, bytes.NewBuffer(body))
+	req, err := c.newRequest(ctx, http.MethodPost, u, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -129,18 +144,39 @@ func (c *Client) GetPromptWithArgs(name string, arguments map[string]string) (*t
 	return &result, nil
 }
 
-// EnablePrompts enables one or more prompts
+// GetPromptWithArgs is like GetPromptWithArgsContext, without a context.
+//
+// Deprecated: use GetPromptWithArgsContext instead, GetPromptWithArgs will be removed in the next release.
+func (c *Client) GetPromptWithArgs(name string, arguments map[string]string) (*types.PromptResult, error) {
+	return c.GetPromptWithArgsContext(context.Background(), name, arguments)
+}
+
+// EnablePromptsContext enables one or more prompts
+func (c *Client) EnablePromptsContext(ctx context.Context, entity string) ([]string, error) {
+	return c.setPromptsEnabled(ctx, entity, true)
+}
+
+// EnablePrompts is like EnablePromptsContext, without a context.
+//
+// Deprecated: use EnablePromptsContext instead, EnablePrompts will be removed in the next release.
 func (c *Client) EnablePrompts(entity string) ([]string, error) {
-	return c.setPromptsEnabled(entity, true)
+	return c.EnablePromptsContext(context.Background(), entity)
+}
+
+// DisablePromptsContext disables one or more prompts
+func (c *Client) DisablePromptsContext(ctx context.Context, entity string) ([]string, error) {
+	return c.setPromptsEnabled(ctx, entity, false)
 }
 
-// DisablePrompts disables one or more prompts
+// DisablePrompts is like DisablePromptsContext, without a context.
+//
+// Deprecated: use DisablePromptsContext instead, DisablePrompts will be removed in the next release.
 func (c *Client) DisablePrompts(entity string) ([]string, error) {
-	return c.setPromptsEnabled(entity, false)
+	return c.DisablePromptsContext(context.Background(), entity)
 }
 
 // setPromptsEnabled is a helper function to enable or disable prompts
-func (c *Client) setPromptsEnabled(entity string, enabled bool) ([]string, error) {
+func (c *Client) setPromptsEnabled(ctx context.Context, entity string, enabled bool) ([]string, error) {
 	var endpoint string
 	if enabled {
 		endpoint = "/prompts/enable"
@@ -160,12 +196,12 @@ func (c *Client) setPromptsEnabled(entity string, enabled bool) ([]string, error
 	parsed.RawQuery = q.Encode()
 	u = parsed.String()
 
-	req, err := c.newRequest(http.MethodPost, u, nil)
+	req, err := c.newRequest(ctx, http.MethodPost, u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -177,7 +213,7 @@ func (c *Client) setPromptsEnabled(entity string, enabled bool) ([]string, error
 		if !enabled {
 			action = "disable"
 		}
-		return nil, fmt.Errorf("failed to %s prompts: status %d, message: %s", action, resp.StatusCode, body)
+		return nil, newAPIError(resp, "failed to %s prompts: status %d, message: %s", action, resp.StatusCode, body)
 	}
 
 	var promptNames []string
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -36,7 +37,7 @@ func TestListPrompts(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "token", &http.Client{})
-		result, err := client.ListPrompts("")
+		result, err := client.ListPromptsContext(context.Background(), "")
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -56,7 +57,7 @@ func TestListPrompts(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "token", &http.Client{})
-		_, err := client.ListPrompts("srv")
+		_, err := client.ListPromptsContext(context.Background(), "srv")
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -70,7 +71,7 @@ func TestListPrompts(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "token", &http.Client{})
-		result, err := client.ListPrompts("")
+		result, err := client.ListPromptsContext(context.Background(), "")
 		if err == nil || result != nil {
 			t.Error("Expected error and nil result")
 		}
@@ -92,7 +93,7 @@ func TestGetPrompt(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "token", &http.Client{})
-		result, err := client.GetPrompt("prompt1")
+		result, err := client.GetPromptContext(context.Background(), "prompt1")
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -109,7 +110,7 @@ func TestGetPrompt(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "token", &http.Client{})
-		result, err := client.GetPrompt("missing")
+		result, err := client.GetPromptContext(context.Background(), "missing")
 		if err == nil || result != nil {
 			t.Error("Expected error and nil result")
 		}
@@ -149,7 +150,7 @@ func TestGetPromptWithArgs(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "token", &http.Client{})
-		result, err := client.GetPromptWithArgs("greet", map[string]string{"name": "Alice"})
+		result, err := client.GetPromptWithArgsContext(context.Background(), "greet", map[string]string{"name": "Alice"})
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -180,7 +181,7 @@ func TestGetPromptWithArgs(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "token", &http.Client{})
-		result, err := client.GetPromptWithArgs("greet", map[string]string{"name": "Bob"})
+		result, err := client.GetPromptWithArgsContext(context.Background(), "greet", map[string]string{"name": "Bob"})
 		if err == nil || result != nil {
 			t.Error("Expected error and nil result")
 		}
@@ -208,7 +209,7 @@ func TestEnableDisablePrompts(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "token", &http.Client{})
-		result, err := client.EnablePrompts("test-entity")
+		result, err := client.EnablePromptsContext(context.Background(), "test-entity")
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -232,7 +233,7 @@ func TestEnableDisablePrompts(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "token", &http.Client{})
-		result, err := client.DisablePrompts("test-entity")
+		result, err := client.DisablePromptsContext(context.Background(), "test-entity")
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -249,7 +250,7 @@ func TestEnableDisablePrompts(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "token", &http.Client{})
-		result, err := client.EnablePrompts("fail-entity")
+		result, err := client.EnablePromptsContext(context.Background(), "fail-entity")
 		if err == nil || result != nil {
 			t.Error("Expected error and nil result")
 		}
@@ -263,7 +264,7 @@ func TestEnableDisablePrompts(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "token", &http.Client{})
-		result, err := client.DisablePrompts("fail-entity")
+		result, err := client.DisablePromptsContext(context.Background(), "fail-entity")
 		if err == nil || result != nil {
 			t.Error("Expected error and nil result")
 		}
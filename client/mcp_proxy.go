@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/version"
+)
+
+// MCPProxyOptions configures a connection to the MCP proxy of the registry.
+type MCPProxyOptions struct {
+	// ClientToken is the access token of the MCP client to connect as, it is required in enterprise mode.
+	ClientToken string
+	// HTTPClient sends the requests of the connection, the HTTP client of the Client if nil.
+	HTTPClient *http.Client
+}
+
+// ConnectMCPProxy connects to the MCP proxy of the registry, served on /mcp, the way MCP clients do,
+// and initializes the session. The caller must close the returned client.
+func (c *Client) ConnectMCPProxy(ctx context.Context, opts MCPProxyOptions) (*mcpclient.Client, error) {
+	u, err := url.JoinPath(c.baseURL, "mcp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct the URL of the MCP proxy: %w", err)
+	}
+
+	headers := make(map[string]string)
+	if opts.ClientToken != "" {
+		headers["Authorization"] = "Bearer " + opts.ClientToken
+	}
+	if c.userAgent != "" {
+		headers["User-Agent"] = c.userAgent
+	}
+	hc := opts.HTTPClient
+	if hc == nil {
+		hc = c.httpClient
+	}
+	mc, err := mcpclient.NewStreamableHttpClient(
+		u, transport.WithHTTPHeaders(headers), transport.WithHTTPBasicClient(hc),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a client for the MCP proxy: %w", err)
+	}
+
+	req := mcp.InitializeRequest{}
+	req.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	req.Params.ClientInfo = mcp.Implementation{Name: "mcpjungle-client", Version: version.GetVersion()}
+	if _, err := mc.Initialize(ctx, req); err != nil {
+		_ = mc.Close()
+		return nil, fmt.Errorf("failed to initialize the session with the MCP proxy at %s: %w", u, err)
+	}
+	return mc, nil
+}
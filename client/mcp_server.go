@@ -2,28 +2,31 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"iter"
 	"net/http"
 
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
-// RegisterServer registers a new MCP server with the registry.
-func (c *Client) RegisterServer(server *types.RegisterServerInput) (*types.McpServer, error) {
+// RegisterServerContext registers a new MCP server with the registry.
+func (c *Client) RegisterServerContext(ctx context.Context, server *types.RegisterServerInput) (*types.McpServer, error) {
 	u, _ := c.constructAPIEndpoint("/servers")
 	body, err := json.Marshal(server)
 	if err != nil {
 		return nil, fmt.Errorf("failed to serialize server data into JSON: %w", err)
 	}
 
-	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	req, err := c.newRequest(ctx, http.MethodPost, u, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -40,15 +43,125 @@ func (c *Client) RegisterServer(server *types.RegisterServerInput) (*types.McpSe
 	return &registeredServer, nil
 }
 
-// ListServers fetches the list of registered servers.
-func (c *Client) ListServers() ([]*types.McpServer, error) {
-	u, _ := c.constructAPIEndpoint("/servers")
-	req, err := c.newRequest(http.MethodGet, u, nil)
+// RegisterServer is like RegisterServerContext, without a context.
+//
+// Deprecated: use RegisterServerContext instead, RegisterServer will be removed in the next release.
+func (c *Client) RegisterServer(server *types.RegisterServerInput) (*types.McpServer, error) {
+	return c.RegisterServerContext(context.Background(), server)
+}
+
+// BulkRegisterOptions configures the registration of a batch of MCP servers.
+type BulkRegisterOptions struct {
+	// Mode is how the batch is registered, types.BulkRegistrationAtomic if empty.
+	Mode types.BulkRegistrationMode
+	// DeferValidation registers the servers of an atomic batch without connecting to them first.
+	DeferValidation bool
+}
+
+// RegisterServersBulk registers a batch of MCP servers in a single request.
+// The result reports the outcome of every server of the batch. When an atomic batch fails, none of its servers
+// are registered and both the result and an *APIError with status 422 are returned.
+// Servers that don't advertise types.FeatureBulkRegistration respond with an *APIError with status 404.
+func (c *Client) RegisterServersBulk(
+	ctx context.Context, servers []types.RegisterServerInput, opts BulkRegisterOptions,
+) (*types.BulkRegistrationResult, error) {
+	u, _ := c.constructAPIEndpoint("/servers/bulk")
+	body, err := json.Marshal(servers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize server data into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	q := req.URL.Query()
+	if opts.Mode != "" {
+		q.Set("mode", string(opts.Mode))
+	}
+	if opts.DeferValidation {
+		q.Set("defer_validation", "true")
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated, http.StatusMultiStatus:
+		var result types.BulkRegistrationResult
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return &result, nil
+	case http.StatusUnprocessableEntity:
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		var result types.BulkRegistrationResult
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		return &result, c.parseErrorResponse(resp)
+	default:
+		return nil, c.parseErrorResponse(resp)
+	}
+}
+
+// UpdateServerContext replaces the configuration of a registered MCP server, identified by the name in the configuration.
+func (c *Client) UpdateServerContext(ctx context.Context, server *types.RegisterServerInput) (*types.McpServer, error) {
+	u, _ := c.constructAPIEndpoint("/servers/" + server.Name)
+	body, err := json.Marshal(server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize server data into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPut, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var updatedServer types.McpServer
+	if err := json.NewDecoder(resp.Body).Decode(&updatedServer); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &updatedServer, nil
+}
+
+// UpdateServer is like UpdateServerContext, without a context.
+//
+// Deprecated: use UpdateServerContext instead, UpdateServer will be removed in the next release.
+func (c *Client) UpdateServer(server *types.RegisterServerInput) (*types.McpServer, error) {
+	return c.UpdateServerContext(context.Background(), server)
+}
+
+// GetServerContext fetches a registered MCP server by its name or UUID.
+// Its Version can be passed to PatchServer to make sure the server wasn't changed in between.
+func (c *Client) GetServerContext(ctx context.Context, name string) (*types.McpServer, error) {
+	u, _ := c.constructAPIEndpoint("/servers/" + name)
+	req, err := c.newRequest(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -58,24 +171,124 @@ func (c *Client) ListServers() ([]*types.McpServer, error) {
 		return nil, c.parseErrorResponse(resp)
 	}
 
-	var servers []*types.McpServer
-	if err := json.NewDecoder(resp.Body).Decode(&servers); err != nil {
+	var server types.McpServer
+	if err := json.NewDecoder(resp.Body).Decode(&server); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
-	return servers, nil
+	return &server, nil
 }
 
-// GetServerConfigs returns the configurations of all registered MCP servers.
+// PatchServer partially updates the configuration of a registered MCP server with a JSON merge patch.
+// The patch is a map or a struct encoding the fields of types.RegisterServerInput to change, nil values clear fields.
+// The server only reconnects to the MCP server if its connection settings changed.
+// name is the name or the UUID of the server.
+func (c *Client) PatchServer(ctx context.Context, name string, patch any, opts PatchOptions) (*types.McpServer, error) {
+	var server types.McpServer
+	if err := sendMergePatch(ctx, c, "/servers/"+name, patch, opts, &server); err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+// ListServersContext fetches the list of registered servers, all pages of it.
+func (c *Client) ListServersContext(ctx context.Context) ([]*types.McpServer, error) {
+	return collect(c.AllServers(ctx))
+}
+
+// ListServersPage fetches a page of the list of registered servers.
+func (c *Client) ListServersPage(ctx context.Context, opts PageOptions) (*types.Page[*types.McpServer], error) {
+	return fetchPage[*types.McpServer](ctx, c, "/servers", nil, opts)
+}
+
+// AllServers iterates over all registered servers, fetching pages as needed.
+func (c *Client) AllServers(ctx context.Context) iter.Seq2[*types.McpServer, error] {
+	return allPages(ctx, c.ListServersPage)
+}
+
+// ListServers is like ListServersContext, without a context.
+//
+// Deprecated: use ListServersContext instead, ListServers will be removed in the next release.
+func (c *Client) ListServers() ([]*types.McpServer, error) {
+	return c.ListServersContext(context.Background())
+}
+
+// GetServersHealth fetches the health of the registered MCP servers, as of their last health check.
+// If refresh is true, the servers are checked before the response is sent, which requires the admin role.
+// It succeeds even if the health is critical, callers must check the Status field.
+func (c *Client) GetServersHealth(ctx context.Context, refresh bool) (*types.ServersHealth, error) {
+	u, _ := c.constructAPIEndpoint("/servers/health")
+	if refresh {
+		u += "?refresh=true"
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var health types.ServersHealth
+	err = json.NewDecoder(resp.Body).Decode(&health)
+	if resp.StatusCode != http.StatusOK && (err != nil || health.Status == "") {
+		// the 503 doesn't come from the health check, eg- a proxy in front of the registry is unavailable
+		return nil, newAPIError(resp, "the registry is unavailable")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &health, nil
+}
+
+// SyncServers synchronizes the tools and prompts of the MCP servers with the given names, all registered servers
+// if none are given. A server that fails to synchronize doesn't fail the call, callers must check the Failed field.
+func (c *Client) SyncServers(ctx context.Context, names ...string) (*types.SyncServersResult, error) {
+	u, _ := c.constructAPIEndpoint("/servers/sync")
+	body, err := json.Marshal(types.SyncServersInput{Servers: names})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize request body into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusMultiStatus {
+		return nil, c.parseErrorResponse(resp)
+	}
+	var result types.SyncServersResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// GetServerConfigsContext returns the configurations of all registered MCP servers.
 // This is different from ListServers() because it returns the complete configuration used to register the servers.
 // This config can be used to register the servers again elsewhere.
-func (c *Client) GetServerConfigs() ([]*types.RegisterServerInput, error) {
+func (c *Client) GetServerConfigsContext(ctx context.Context) ([]*types.RegisterServerInput, error) {
 	u, _ := c.constructAPIEndpoint("/server_configs")
-	req, err := c.newRequest(http.MethodGet, u, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -92,12 +305,45 @@ func (c *Client) GetServerConfigs() ([]*types.RegisterServerInput, error) {
 	return serverConfigs, nil
 }
 
-// DeregisterServer deletes a server by name.
-func (c *Client) DeregisterServer(name string) error {
+// GetServerConfigs is like GetServerConfigsContext, without a context.
+//
+// Deprecated: use GetServerConfigsContext instead, GetServerConfigs will be removed in the next release.
+func (c *Client) GetServerConfigs() ([]*types.RegisterServerInput, error) {
+	return c.GetServerConfigsContext(context.Background())
+}
+
+// GetServerConfig returns the complete configuration of a registered MCP server, like GetServerConfigsContext.
+// name is the name or the UUID of the server.
+func (c *Client) GetServerConfig(ctx context.Context, name string) (*types.RegisterServerInput, error) {
+	u, _ := c.constructAPIEndpoint("/server_configs/" + name)
+	req, err := c.newRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var serverConfig types.RegisterServerInput
+	if err := json.NewDecoder(resp.Body).Decode(&serverConfig); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &serverConfig, nil
+}
+
+// DeregisterServerContext deletes a server by its name or UUID.
+func (c *Client) DeregisterServerContext(ctx context.Context, name string) error {
 	u, _ := c.constructAPIEndpoint("/servers/" + name)
-	req, _ := c.newRequest(http.MethodDelete, u, nil)
+	req, _ := c.newRequest(ctx, http.MethodDelete, u, nil)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -109,17 +355,38 @@ func (c *Client) DeregisterServer(name string) error {
 	return nil
 }
 
-// EnableServer sends API request to enable a server by name.
+// DeregisterServer is like DeregisterServerContext, without a context.
+//
+// Deprecated: use DeregisterServerContext instead, DeregisterServer will be removed in the next release.
+func (c *Client) DeregisterServer(name string) error {
+	return c.DeregisterServerContext(context.Background(), name)
+}
+
+// EnableServerContext sends API request to enable a server by its name or UUID.
+func (c *Client) EnableServerContext(ctx context.Context, name string) (*types.EnableDisableServerResult, error) {
+	return c.setServerEnabled(ctx, name, true)
+}
+
+// EnableServer is like EnableServerContext, without a context.
+//
+// Deprecated: use EnableServerContext instead, EnableServer will be removed in the next release.
 func (c *Client) EnableServer(name string) (*types.EnableDisableServerResult, error) {
-	return c.setServerEnabled(name, true)
+	return c.EnableServerContext(context.Background(), name)
+}
+
+// DisableServerContext sends API request to disable a server by its name or UUID.
+func (c *Client) DisableServerContext(ctx context.Context, name string) (*types.EnableDisableServerResult, error) {
+	return c.setServerEnabled(ctx, name, false)
 }
 
-// DisableServer sends API request to disable a server by name.
+// DisableServer is like DisableServerContext, without a context.
+//
+// Deprecated: use DisableServerContext instead, DisableServer will be removed in the next release.
 func (c *Client) DisableServer(name string) (*types.EnableDisableServerResult, error) {
-	return c.setServerEnabled(name, false)
+	return c.DisableServerContext(context.Background(), name)
 }
 
-func (c *Client) setServerEnabled(name string, enabled bool) (*types.EnableDisableServerResult, error) {
+func (c *Client) setServerEnabled(ctx context.Context, name string, enabled bool) (*types.EnableDisableServerResult, error) {
 	api := "enable"
 	if !enabled {
 		api = "disable"
@@ -131,12 +398,12 @@ func (c *Client) setServerEnabled(name string, enabled bool) (*types.EnableDisab
 		return nil, fmt.Errorf("failed to construct API endpoint: %w", err)
 	}
 
-	req, err := c.newRequest(http.MethodPost, u, nil)
+	req, err := c.newRequest(ctx, http.MethodPost, u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
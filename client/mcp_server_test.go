@@ -1,7 +1,9 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -55,7 +57,7 @@ func TestRegisterServer(t *testing.T) {
 			Command:   "/usr/bin/test-server",
 		}
 
-		response, err := client.RegisterServer(serverInput)
+		response, err := client.RegisterServerContext(context.Background(), serverInput)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -85,7 +87,7 @@ func TestRegisterServer(t *testing.T) {
 			Command:   "/usr/bin/test-server",
 		}
 
-		response, err := client.RegisterServer(serverInput)
+		response, err := client.RegisterServerContext(context.Background(), serverInput)
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -108,7 +110,7 @@ func TestRegisterServer(t *testing.T) {
 			Command:   "/usr/bin/test-server",
 		}
 
-		response, err := client.RegisterServer(serverInput)
+		response, err := client.RegisterServerContext(context.Background(), serverInput)
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -163,7 +165,7 @@ func TestListServers(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		servers, err := client.ListServers()
+		servers, err := client.ListServersContext(context.Background())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -188,7 +190,7 @@ func TestListServers(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		servers, err := client.ListServers()
+		servers, err := client.ListServersContext(context.Background())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -206,7 +208,7 @@ func TestListServers(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		servers, err := client.ListServers()
+		servers, err := client.ListServersContext(context.Background())
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -233,7 +235,7 @@ func TestDeregisterServer(t *testing.T) {
 			if r.Method != "DELETE" {
 				t.Errorf("Expected DELETE method, got %s", r.Method)
 			}
-			expectedPath := "/api/v0/servers/" + serverName
+			expectedPath := "/api/v1/servers/" + serverName
 			if !strings.HasSuffix(r.URL.Path, expectedPath) {
 				t.Errorf("Expected path to end with %s, got %s", expectedPath, r.URL.Path)
 			}
@@ -250,7 +252,7 @@ func TestDeregisterServer(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		err := client.DeregisterServer(serverName)
+		err := client.DeregisterServerContext(context.Background(), serverName)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -264,7 +266,7 @@ func TestDeregisterServer(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		err := client.DeregisterServer("non-existent-server")
+		err := client.DeregisterServerContext(context.Background(), "non-existent-server")
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -278,7 +280,7 @@ func TestDeregisterServer(t *testing.T) {
 
 	t.Run("network error", func(t *testing.T) {
 		client := NewClient("http://invalid-url", "test-token", &http.Client{})
-		err := client.DeregisterServer("test-server")
+		err := client.DeregisterServerContext(context.Background(), "test-server")
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -290,6 +292,148 @@ func TestDeregisterServer(t *testing.T) {
 	})
 }
 
+func TestGetServerByUUID(t *testing.T) {
+	t.Parallel()
+
+	id := "9a7b330a-a736-41e5-8b6c-2e8f8e6d2b1c"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := "/api/v1/servers/" + id
+		if r.URL.Path != expectedPath {
+			t.Errorf("Expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(types.McpServer{UUID: id, Name: "github"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", &http.Client{})
+	s, err := client.GetServerContext(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.UUID != id || s.Name != "github" {
+		t.Errorf("Expected server github with UUID %s, got %s with UUID %s", id, s.Name, s.UUID)
+	}
+}
+
+func TestUpdateServer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful update", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut {
+				t.Errorf("Expected PUT method, got %s", r.Method)
+			}
+			expectedPath := "/api/v1/servers/github"
+			if !strings.HasSuffix(r.URL.Path, expectedPath) {
+				t.Errorf("Expected path to end with %s, got %s", expectedPath, r.URL.Path)
+			}
+
+			var input types.RegisterServerInput
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				t.Fatalf("Failed to decode request body: %v", err)
+			}
+			if input.BearerToken != "new-token" {
+				t.Errorf("Expected bearer token new-token, got %s", input.BearerToken)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(&types.McpServer{Name: input.Name, Transport: input.Transport, URL: input.URL})
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, "test-token", &http.Client{})
+		updated, err := client.UpdateServerContext(context.Background(), &types.RegisterServerInput{
+			Name:        "github",
+			Transport:   "streamable_http",
+			URL:         "https://api.githubcopilot.com/mcp/",
+			BearerToken: "new-token",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if updated.URL != "https://api.githubcopilot.com/mcp/" {
+			t.Errorf("Expected URL to be updated, got %s", updated.URL)
+		}
+	})
+
+	t.Run("server not found", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("Server not found"))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, "test-token", &http.Client{})
+		_, err := client.UpdateServerContext(context.Background(), &types.RegisterServerInput{Name: "non-existent-server"})
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "Server not found") {
+			t.Errorf("Expected error to contain 'Server not found', got %s", err.Error())
+		}
+	})
+}
+
+func TestPatchServer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful patch", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPatch {
+				t.Errorf("Expected PATCH method, got %s", r.Method)
+			}
+			if !strings.HasSuffix(r.URL.Path, "/api/v1/servers/github") {
+				t.Errorf("Expected path to end with /api/v1/servers/github, got %s", r.URL.Path)
+			}
+			if ct := r.Header.Get("Content-Type"); ct != "application/merge-patch+json" {
+				t.Errorf("Expected Content-Type application/merge-patch+json, got %s", ct)
+			}
+			if im := r.Header.Get("If-Match"); im != `"3"` {
+				t.Errorf(`Expected If-Match "3", got %s`, im)
+			}
+
+			var patch map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+				t.Fatalf("Failed to decode request body: %v", err)
+			}
+			if v, ok := patch["description"]; !ok || v != nil {
+				t.Errorf("Expected the description to be cleared, got %v", patch)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(&types.McpServer{Name: "github", Version: 4})
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, "test-token", &http.Client{})
+		updated, err := client.PatchServer(context.Background(), "github", map[string]any{"description": nil}, PatchOptions{Version: 3})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if updated.Version != 4 {
+			t.Errorf("Expected version 4, got %d", updated.Version)
+		}
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusPreconditionFailed)
+			_, _ = w.Write([]byte(`{"error":"the entity was changed since it was read, fetch it again and retry"}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, "test-token", &http.Client{})
+		_, err := client.PatchServer(context.Background(), "github", map[string]any{"description": "GitHub"}, PatchOptions{Version: 1})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusPreconditionFailed {
+			t.Fatalf("Expected an APIError with status 412, got %v", err)
+		}
+	})
+}
+
 func TestGetServerConfigs(t *testing.T) {
 	t.Parallel()
 
@@ -330,7 +474,7 @@ func TestGetServerConfigs(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		configs, err := client.GetServerConfigs()
+		configs, err := client.GetServerConfigsContext(context.Background())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -358,7 +502,7 @@ func TestGetServerConfigs(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		configs, err := client.GetServerConfigs()
+		configs, err := client.GetServerConfigsContext(context.Background())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -376,7 +520,7 @@ func TestGetServerConfigs(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		configs, err := client.GetServerConfigs()
+		configs, err := client.GetServerConfigsContext(context.Background())
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -393,7 +537,7 @@ func TestGetServerConfigs(t *testing.T) {
 
 	t.Run("network error", func(t *testing.T) {
 		client := NewClient("http://invalid-url", "test-token", &http.Client{})
-		configs, err := client.GetServerConfigs()
+		configs, err := client.GetServerConfigsContext(context.Background())
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -407,3 +551,160 @@ func TestGetServerConfigs(t *testing.T) {
 		}
 	})
 }
+
+func TestGetServerConfig(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1/server_configs/github" {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": "not_found", "error": "MCP server not found"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&types.RegisterServerInput{
+			Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/", BearerToken: "secret",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", &http.Client{})
+	config, err := client.GetServerConfig(context.Background(), "github")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Name != "github" || config.BearerToken != "secret" {
+		t.Errorf("Expected the complete configuration of github, got %+v", config)
+	}
+
+	_, err = client.GetServerConfig(context.Background(), "missing")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Expected an APIError with status 404, got %v", err)
+	}
+}
+
+func TestRegisterServersBulk(t *testing.T) {
+	t.Parallel()
+
+	t.Run("partial success", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/servers/bulk" || r.URL.Query().Get("mode") != "best_effort" {
+				t.Errorf("Unexpected request %s", r.URL)
+			}
+			var inputs []types.RegisterServerInput
+			if err := json.NewDecoder(r.Body).Decode(&inputs); err != nil || len(inputs) != 2 {
+				t.Errorf("Expected a batch of 2 servers, got %v (%v)", inputs, err)
+			}
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = w.Write([]byte(`{"mode": "best_effort", "registered": 1, "failed": 1, "results": [
+				{"index": 0, "name": "a", "status": "registered", "server": {"name": "a"}},
+				{"index": 1, "name": "b", "status": "failed", "error": "connection refused"}
+			]}`))
+		}))
+		defer server.Close()
+
+		c := NewClient(server.URL, "", &http.Client{})
+		result, err := c.RegisterServersBulk(
+			context.Background(),
+			[]types.RegisterServerInput{{Name: "a"}, {Name: "b"}},
+			BulkRegisterOptions{Mode: types.BulkRegistrationBestEffort},
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Registered != 1 || result.Results[1].Error != "connection refused" {
+			t.Errorf("Unexpected result %+v", result)
+		}
+	})
+
+	t.Run("atomic failure", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"error": "1 of 1 servers could not be registered, none were registered", "mode": "atomic", "failed": 1,
+				"results": [{"index": 0, "name": "a", "status": "failed", "error": "invalid transport"}]}`))
+		}))
+		defer server.Close()
+
+		c := NewClient(server.URL, "", &http.Client{})
+		result, err := c.RegisterServersBulk(context.Background(), []types.RegisterServerInput{{Name: "a"}}, BulkRegisterOptions{})
+		if !IsStatus(err, http.StatusUnprocessableEntity) {
+			t.Errorf("Expected an APIError with status 422, got %v", err)
+		}
+		if result == nil || result.Results[0].Status != types.BulkItemFailed {
+			t.Errorf("Expected the per-server results along with the error, got %+v", result)
+		}
+	})
+}
+
+func TestGetServersHealth(t *testing.T) {
+	t.Parallel()
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/servers/health" {
+			http.NotFound(w, r)
+			return
+		}
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"status": "critical", "servers": [{"name": "github", "status": "unhealthy", "consecutive_failures": 2}]}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "", &http.Client{})
+
+	health, err := c.GetServersHealth(context.Background(), true)
+	if err != nil {
+		t.Fatalf("Expected a critical health not to be an error, got %v", err)
+	}
+	if query != "refresh=true" {
+		t.Errorf("Expected the refresh to be requested, got query %q", query)
+	}
+	if health.Status != types.HealthCritical || len(health.Servers) != 1 || health.Servers[0].ConsecutiveFailures != 2 {
+		t.Errorf("Unexpected health %+v", health)
+	}
+
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no healthy upstream", http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+	_, err = NewClient(unavailable.URL, "", &http.Client{}).GetServersHealth(context.Background(), false)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable from a proxy that is unavailable, got %v", err)
+	}
+}
+
+func TestSyncServers(t *testing.T) {
+	t.Parallel()
+	var input types.SyncServersInput
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/servers/sync" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&input)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = w.Write([]byte(`{"synced": 1, "failed": 1, "servers": [` +
+			`{"name": "github", "status": "synced", "tools_added": ["github__git_commit"]},` +
+			`{"name": "slack", "status": "failed", "error": "connection refused"}]}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "", &http.Client{})
+
+	result, err := c.SyncServers(context.Background(), "github", "slack")
+	if err != nil {
+		t.Fatalf("Expected a partially failed sync not to be an error, got %v", err)
+	}
+	if strings.Join(input.Servers, ",") != "github,slack" {
+		t.Errorf("Expected the servers to be sent in the body, got %v", input.Servers)
+	}
+	if result.Synced != 1 || result.Failed != 1 || result.Servers[1].Error != "connection refused" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
@@ -1,46 +1,128 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"iter"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
-// ListTools fetches the list of tools, optionally filtered by server name.
+// ToolFilter selects the tools to list. The zero value selects all tools.
+// Filters are combined, only the tools matching all of them are listed.
+type ToolFilter struct {
+	// Server is the name of the MCP server whose tools to list.
+	Server string
+	// Group is the name of the tool group whose tools to list.
+	Group string
+	// Enabled selects the enabled tools if true, the disabled ones if false, and both if nil.
+	Enabled *bool
+	// Query selects the tools whose name or description contains it, ignoring case.
+	Query string
+}
+
+// values returns the query parameters of the filter.
+func (f ToolFilter) values() url.Values {
+	query := url.Values{}
+	if f.Server != "" {
+		query.Set("server", f.Server)
+	}
+	if f.Group != "" {
+		query.Set("group", f.Group)
+	}
+	if f.Enabled != nil {
+		query.Set("enabled", strconv.FormatBool(*f.Enabled))
+	}
+	if f.Query != "" {
+		query.Set("q", f.Query)
+	}
+	return query
+}
+
+// ListToolsContext fetches the list of tools, all pages of it, optionally filtered by server name.
 // If server is an empty string, this method fetches all tools.
-func (c *Client) ListTools(server string) ([]*types.Tool, error) {
-	u, _ := c.constructAPIEndpoint("/tools")
-	req, _ := c.newRequest(http.MethodGet, u, nil)
-	if server != "" {
-		q := req.URL.Query()
-		q.Add("server", server)
-		req.URL.RawQuery = q.Encode()
+func (c *Client) ListToolsContext(ctx context.Context, server string) ([]*types.Tool, error) {
+	return collect(c.AllTools(ctx, ToolFilter{Server: server}))
+}
+
+// ListToolsPage fetches a page of the tools matching the filter.
+// The page's Total is the number of matching tools across all pages.
+func (c *Client) ListToolsPage(ctx context.Context, filter ToolFilter, opts PageOptions) (*types.Page[*types.Tool], error) {
+	return fetchPage[*types.Tool](ctx, c, "/tools", filter.values(), opts)
+}
+
+// AllTools iterates over the tools matching the filter, fetching pages as needed.
+func (c *Client) AllTools(ctx context.Context, filter ToolFilter) iter.Seq2[*types.Tool, error] {
+	return allPages(ctx, func(ctx context.Context, opts PageOptions) (*types.Page[*types.Tool], error) {
+		return c.ListToolsPage(ctx, filter, opts)
+	})
+}
+
+// StreamTools calls fn for every tool matching the filter, in a single request to the streaming endpoint of the server,
+// which sends them one by one as it reads them from its database. Unlike AllTools, the tools are never all held
+// in memory, which makes it the way to dump very large registries. It stops at the first error returned by fn.
+// The timeout of the Client bounds waiting for the stream to start, the tools are then read for as long as it takes.
+// Servers that don't support streaming respond with an error matching ErrNotFound, use AllTools with them.
+func (c *Client) StreamTools(ctx context.Context, filter ToolFilter, fn func(*types.Tool) error) error {
+	u, _ := c.constructAPIEndpoint("/tools/stream")
+	req, err := c.newRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
 	}
+	req.URL.RawQuery = filter.values().Encode()
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doStream(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, c.parseErrorResponse(resp)
+		return c.parseErrorResponse(resp)
 	}
 
-	var tools []*types.Tool
-	if err := json.NewDecoder(resp.Body).Decode(&tools); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var line struct {
+			types.Tool
+			// Error is only set on the last line of a stream that failed
+			Error *types.APIError `json:"error"`
+		}
+		if err := dec.Decode(&line); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to decode tool stream: %w", err)
+		}
+		if line.Error != nil {
+			apiErr := newAPIError(resp, "%s", line.Error.Message)
+			apiErr.Code, apiErr.Details = line.Error.Code, line.Error.Details
+			return fmt.Errorf("the tool stream failed: %w", apiErr)
+		}
+		if err := fn(&line.Tool); err != nil {
+			return err
+		}
 	}
-	return tools, nil
 }
 
-// EnableTools enables a tool or all tools provided by an MCP server.
-func (c *Client) EnableTools(name string) ([]string, error) {
+// ListTools is like ListToolsContext, without a context.
+//
+// Deprecated: use ListToolsContext instead, ListTools will be removed in the next release.
+func (c *Client) ListTools(server string) ([]*types.Tool, error) {
+	return c.ListToolsContext(context.Background(), server)
+}
+
+// EnableToolsContext enables a tool or all tools provided by an MCP server.
+func (c *Client) EnableToolsContext(ctx context.Context, name string) ([]string, error) {
 	u, _ := c.constructAPIEndpoint("/tools/enable")
-	req, err := c.newRequest(http.MethodPost, u, nil)
+	req, err := c.newRequest(ctx, http.MethodPost, u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -49,7 +131,7 @@ func (c *Client) EnableTools(name string) ([]string, error) {
 	q.Add("entity", name)
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
 	}
@@ -66,10 +148,17 @@ func (c *Client) EnableTools(name string) ([]string, error) {
 	return tools, nil
 }
 
-// DisableTools disables a tool or all tools provided by an MCP server.
-func (c *Client) DisableTools(name string) ([]string, error) {
+// EnableTools is like EnableToolsContext, without a context.
+//
+// Deprecated: use EnableToolsContext instead, EnableTools will be removed in the next release.
+func (c *Client) EnableTools(name string) ([]string, error) {
+	return c.EnableToolsContext(context.Background(), name)
+}
+
+// DisableToolsContext disables a tool or all tools provided by an MCP server.
+func (c *Client) DisableToolsContext(ctx context.Context, name string) ([]string, error) {
 	u, _ := c.constructAPIEndpoint("/tools/disable")
-	req, err := c.newRequest(http.MethodPost, u, nil)
+	req, err := c.newRequest(ctx, http.MethodPost, u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -78,7 +167,7 @@ func (c *Client) DisableTools(name string) ([]string, error) {
 	q.Add("entity", name)
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
 	}
@@ -95,15 +184,22 @@ func (c *Client) DisableTools(name string) ([]string, error) {
 	return tools, nil
 }
 
-// GetTool fetches a specific tool by its name.
-func (c *Client) GetTool(name string) (*types.Tool, error) {
+// DisableTools is like DisableToolsContext, without a context.
+//
+// Deprecated: use DisableToolsContext instead, DisableTools will be removed in the next release.
+func (c *Client) DisableTools(name string) ([]string, error) {
+	return c.DisableToolsContext(context.Background(), name)
+}
+
+// GetToolContext fetches a specific tool by its canonical name or UUID.
+func (c *Client) GetToolContext(ctx context.Context, name string) (*types.Tool, error) {
 	u, _ := c.constructAPIEndpoint("/tool")
-	req, _ := c.newRequest(http.MethodGet, u, nil)
+	req, _ := c.newRequest(ctx, http.MethodGet, u, nil)
 	q := req.URL.Query()
 	q.Add("name", name)
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
 	}
@@ -120,9 +216,16 @@ func (c *Client) GetTool(name string) (*types.Tool, error) {
 	return &tool, nil
 }
 
-// InvokeTool sends a JSON payload to invoke a tool.
+// GetTool is like GetToolContext, without a context.
+//
+// Deprecated: use GetToolContext instead, GetTool will be removed in the next release.
+func (c *Client) GetTool(name string) (*types.Tool, error) {
+	return c.GetToolContext(context.Background(), name)
+}
+
+// InvokeToolContext sends a JSON payload to invoke a tool.
 // For now, this function only supports invoking tools that return a string response.
-func (c *Client) InvokeTool(name string, input map[string]any) (*types.ToolInvokeResult, error) {
+func (c *Client) InvokeToolContext(ctx context.Context, name string, input map[string]any) (*types.ToolInvokeResult, error) {
 	// We need to insert the tool name into the POST payload
 	// In order not to mutate the user-supplied input, create a shallow copy of the input
 	// and add the name field to it.
@@ -134,13 +237,13 @@ func (c *Client) InvokeTool(name string, input map[string]any) (*types.ToolInvok
 
 	body, _ := json.Marshal(payload)
 	u, _ := c.constructAPIEndpoint("/tools/invoke")
-	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	req, err := c.newRequest(ctx, http.MethodPost, u, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request to server failed: %w", err)
 	}
@@ -157,3 +260,145 @@ func (c *Client) InvokeTool(name string, input map[string]any) (*types.ToolInvok
 
 	return result, nil
 }
+
+// InvokeTool is like InvokeToolContext, without a context.
+//
+// Deprecated: use InvokeToolContext instead, InvokeTool will be removed in the next release.
+func (c *Client) InvokeTool(name string, input map[string]any) (*types.ToolInvokeResult, error) {
+	return c.InvokeToolContext(context.Background(), name, input)
+}
+
+// CallTool calls a tool through the MCP proxy of the server, ie, like an MCP client connected to /mcp would.
+// In enterprise mode, the Client must be created with the access token of an MCP client allowed to use the tool.
+// Unlike InvokeToolContext, the call goes through the access checks of the proxy and only reaches enabled tools.
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]any) (*types.ToolInvokeResult, error) {
+	req, err := c.newCallToolRequest(ctx, name, args)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var result types.ToolInvokeResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// CallToolStream is like CallTool, but gets the result as server-sent events, which keep the connection busy
+// for as long as the tool runs. fn, if not nil, is called with every content block of the result as it arrives.
+// The returned result holds all of them. The timeout of the Client only bounds waiting for the stream to start.
+func (c *Client) CallToolStream(
+	ctx context.Context, name string, args map[string]any, fn func(content map[string]any) error,
+) (*types.ToolInvokeResult, error) {
+	req, err := c.newCallToolRequest(ctx, name, args)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.doStream(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	result := &types.ToolInvokeResult{Content: []map[string]any{}}
+	var event string
+	var data bytes.Buffer
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, maxEventSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, ":"):
+			// keep-alive comment
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case line == "":
+			done, err := c.handleToolCallEvent(resp, event, data.Bytes(), result, fn)
+			if err != nil || done {
+				return result, err
+			}
+			event = ""
+			data.Reset()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the event stream: %w", err)
+	}
+	return nil, fmt.Errorf("the event stream ended before the result of the tool call")
+}
+
+// maxEventSize is the size of the largest server-sent event CallToolStream accepts.
+const maxEventSize = 16 << 20
+
+// handleToolCallEvent adds the data of a server-sent event of a tool call to result.
+// It reports whether the event was the last one of the stream.
+func (c *Client) handleToolCallEvent(
+	resp *http.Response, event string, data []byte, result *types.ToolInvokeResult, fn func(map[string]any) error,
+) (bool, error) {
+	switch event {
+	case "content":
+		var content map[string]any
+		if err := json.Unmarshal(data, &content); err != nil {
+			return false, fmt.Errorf("failed to decode content event: %w", err)
+		}
+		result.Content = append(result.Content, content)
+		if fn != nil {
+			if err := fn(content); err != nil {
+				return false, err
+			}
+		}
+	case "result":
+		if err := json.Unmarshal(data, result); err != nil {
+			return false, fmt.Errorf("failed to decode result event: %w", err)
+		}
+		return true, nil
+	case "error":
+		var errResp types.ErrorResponse
+		if err := json.Unmarshal(data, &errResp); err != nil || errResp.Error.Code == "" {
+			return false, fmt.Errorf("failed to decode error event: %s", data)
+		}
+		apiErr := newAPIError(resp, "%s", errResp.Error.Message)
+		apiErr.Code, apiErr.Details = errResp.Error.Code, errResp.Error.Details
+		return false, fmt.Errorf("the tool call failed: %w", apiErr)
+	}
+	return false, nil
+}
+
+// newCallToolRequest creates the request of CallTool and CallToolStream.
+func (c *Client) newCallToolRequest(ctx context.Context, name string, args map[string]any) (*http.Request, error) {
+	if args == nil {
+		args = map[string]any{}
+	}
+	body, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the arguments of the tool: %w", err)
+	}
+	u, _ := c.constructAPIEndpoint("/tools/" + url.PathEscape(name) + "/invoke")
+	req, err := c.newRequest(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
@@ -1,9 +1,12 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -36,9 +39,9 @@ func TestListTools(t *testing.T) {
 				t.Errorf("Expected path to end with /tools, got %s", r.URL.Path)
 			}
 
-			// Verify no query parameters
-			if r.URL.RawQuery != "" {
-				t.Errorf("Expected no query parameters, got %s", r.URL.RawQuery)
+			// Verify the tools are not filtered by server
+			if r.URL.Query().Has("server") {
+				t.Errorf("Expected no server query parameter, got %s", r.URL.RawQuery)
 			}
 
 			// Return success response
@@ -49,7 +52,7 @@ func TestListTools(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		tools, err := client.ListTools("")
+		tools, err := client.ListToolsContext(context.Background(), "")
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -80,7 +83,7 @@ func TestListTools(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		_, err := client.ListTools("test-server")
+		_, err := client.ListToolsContext(context.Background(), "test-server")
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -94,7 +97,7 @@ func TestListTools(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		tools, err := client.ListTools("")
+		tools, err := client.ListToolsContext(context.Background(), "")
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -139,7 +142,7 @@ func TestEnableTools(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		tools, err := client.EnableTools("test-tool")
+		tools, err := client.EnableToolsContext(context.Background(), "test-tool")
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -163,7 +166,7 @@ func TestEnableTools(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		tools, err := client.EnableTools("non-existent-tool")
+		tools, err := client.EnableToolsContext(context.Background(), "non-existent-tool")
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -208,7 +211,7 @@ func TestDisableTools(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		tools, err := client.DisableTools("test-tool")
+		tools, err := client.DisableToolsContext(context.Background(), "test-tool")
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -226,7 +229,7 @@ func TestDisableTools(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		tools, err := client.DisableTools("non-existent-tool")
+		tools, err := client.DisableToolsContext(context.Background(), "non-existent-tool")
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -282,7 +285,7 @@ func TestInvokeTool(t *testing.T) {
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
 		input := map[string]any{"param": "value"}
-		result, err := client.InvokeTool("test-tool", input)
+		result, err := client.InvokeToolContext(context.Background(), "test-tool", input)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -311,7 +314,7 @@ func TestInvokeTool(t *testing.T) {
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
 		input := map[string]any{"invalid": "input"}
-		result, err := client.InvokeTool("test-tool", input)
+		result, err := client.InvokeToolContext(context.Background(), "test-tool", input)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -330,7 +333,7 @@ func TestInvokeTool(t *testing.T) {
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
 		input := map[string]any{"param": "value"}
-		result, err := client.InvokeTool("non-existent-tool", input)
+		result, err := client.InvokeToolContext(context.Background(), "non-existent-tool", input)
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -379,7 +382,7 @@ func TestGetTool(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		tool, err := client.GetTool("test-tool")
+		tool, err := client.GetToolContext(context.Background(), "test-tool")
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -400,7 +403,7 @@ func TestGetTool(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		tool, err := client.GetTool("non-existent-tool")
+		tool, err := client.GetToolContext(context.Background(), "non-existent-tool")
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -415,3 +418,142 @@ func TestGetTool(t *testing.T) {
 		}
 	})
 }
+
+func TestListToolsPageFilter(t *testing.T) {
+	t.Parallel()
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items": [{"name": "git__status"}], "total": 7}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "", &http.Client{})
+
+	enabled := false
+	page, err := c.ListToolsPage(context.Background(), ToolFilter{Server: "git", Group: "review", Enabled: &enabled, Query: "stat"}, PageOptions{Limit: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := url.Values{"server": {"git"}, "group": {"review"}, "enabled": {"false"}, "q": {"stat"}, "limit": {"1"}}
+	if query.Encode() != expected.Encode() {
+		t.Errorf("Expected query %s, got %s", expected.Encode(), query.Encode())
+	}
+	if len(page.Items) != 1 || page.Total == nil || *page.Total != 7 {
+		t.Errorf("Expected 1 tool out of 7, got %d tools and total %v", len(page.Items), page.Total)
+	}
+}
+
+func TestStreamTools(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("server") {
+		case "git":
+			w.Header().Set("Content-Type", types.NDJSONContentType)
+			_, _ = w.Write([]byte("{\"name\": \"git__commit\"}\n{\"name\": \"git__status\"}\n"))
+		case "broken":
+			w.Header().Set("Content-Type", types.NDJSONContentType)
+			_, _ = w.Write([]byte("{\"name\": \"broken__one\"}\n{\"error\": {\"code\": \"internal_error\", \"message\": \"database is gone\"}}\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "", &http.Client{})
+
+	var names []string
+	collect := func(tool *types.Tool) error {
+		names = append(names, tool.Name)
+		return nil
+	}
+	if err := c.StreamTools(context.Background(), ToolFilter{Server: "git"}, collect); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Join(names, ",") != "git__commit,git__status" {
+		t.Errorf("Expected git__commit and git__status, got %v", names)
+	}
+
+	names = nil
+	err := c.StreamTools(context.Background(), ToolFilter{Server: "broken"}, collect)
+	if !errors.Is(err, ErrInternal) || !strings.Contains(err.Error(), "database is gone") {
+		t.Errorf("Expected the error at the end of the stream, got %v", err)
+	}
+	if len(names) != 1 {
+		t.Errorf("Expected the tool before the error to be seen, got %v", names)
+	}
+
+	err = c.StreamTools(context.Background(), ToolFilter{Server: "old"}, collect)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound from a server without the endpoint, got %v", err)
+	}
+}
+
+func TestCallTool(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if r.Header.Get("Authorization") != "Bearer client-token" {
+			t.Errorf("Expected the token of the client, got %q", r.Header.Get("Authorization"))
+		}
+		var args map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&args)
+		switch r.URL.Path {
+		case "/api/v1/tools/git__commit/invoke":
+			if args["message"] != "fix" {
+				t.Errorf("Expected the arguments in the body, got %v", args)
+			}
+			if r.Header.Get("Accept") == "text/event-stream" {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = w.Write([]byte(": the tool is running\n\n" +
+					"event:content\ndata:{\"type\":\"text\",\"text\":\"committed\"}\n\n" +
+					"event:content\ndata:{\"type\":\"text\",\"text\":\"pushed\"}\n\n" +
+					"event:result\ndata:{\"isError\":false}\n\n"))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"committed"}]}`))
+		case "/api/v1/tools/git__push/invoke":
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("event:error\ndata:{\"error\":{\"code\":\"internal_error\",\"message\":\"upstream is gone\"}}\n\n"))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"code":"forbidden","message":"access denied"}}`))
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "client-token", &http.Client{})
+	args := map[string]any{"message": "fix"}
+
+	res, err := c.CallTool(context.Background(), "git__commit", args)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(res.Content) != 1 || res.Content[0]["text"] != "committed" {
+		t.Errorf("Expected the content of the result, got %v", res.Content)
+	}
+
+	var seen []string
+	res, err = c.CallToolStream(context.Background(), "git__commit", args, func(content map[string]any) error {
+		seen = append(seen, content["text"].(string))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Join(seen, ",") != "committed,pushed" || len(res.Content) != 2 || res.IsError {
+		t.Errorf("Expected both content blocks, got %v and %+v", seen, res)
+	}
+
+	_, err = c.CallToolStream(context.Background(), "git__push", args, nil)
+	if !errors.Is(err, ErrInternal) || !strings.Contains(err.Error(), "upstream is gone") {
+		t.Errorf("Expected the error event, got %v", err)
+	}
+
+	_, err = c.CallTool(context.Background(), "slack__post", args)
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+}
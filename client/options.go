@@ -0,0 +1,115 @@
+package client
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout is how long a request made by a Client created with New may take, retries included.
+const DefaultTimeout = 30 * time.Second
+
+// Option configures a Client created with New.
+type Option func(*options)
+
+type options struct {
+	transport  http.RoundTripper
+	timeout    time.Duration
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	retryLog   io.Writer
+	userAgent  string
+	noCache    bool
+	tlsConfig  *tls.Config
+}
+
+// WithTransport sets the http.RoundTripper requests are sent with, eg- to instrument them.
+// Retries are done on top of it, so it sees every attempt. Defaults to http.DefaultTransport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *options) { o.transport = rt }
+}
+
+// WithTLSConfig sets the TLS configuration of the connections to the server, eg- to trust a private CA
+// or to present a client certificate, see TLSOptions.Config.
+// It applies to the transport set with WithTransport only if it is an *http.Transport, which is then copied.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *options) { o.tlsConfig = cfg }
+}
+
+// WithTimeout sets how long a request may take, retries included. 0 means no timeout.
+// Defaults to DefaultTimeout. Use a context deadline to bound individual calls instead.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) { o.timeout = d }
+}
+
+// WithRetries sets how many times a request that failed with a transient error is retried, 0 disables retries.
+// Only requests that are safe to repeat are retried: GET and HEAD requests, and mutating requests
+// that carry an Idempotency-Key header. Defaults to DefaultMaxRetries.
+func WithRetries(n int) Option {
+	return func(o *options) { o.maxRetries = n }
+}
+
+// WithBackoff sets the delays between retries: the first retry happens after up to base,
+// and the delay doubles after every failed attempt, up to maxDelay.
+// A Retry-After header in the response takes precedence over the backoff.
+func WithBackoff(base, maxDelay time.Duration) Option {
+	return func(o *options) { o.baseDelay, o.maxDelay = base, maxDelay }
+}
+
+// WithRetryLog makes the Client write a line to w for every retry, with the reason.
+func WithRetryLog(w io.Writer) Option {
+	return func(o *options) { o.retryLog = w }
+}
+
+// WithUserAgent sets the User-Agent header of every request, so that the server can tell callers apart.
+// Defaults to Go's user agent.
+func WithUserAgent(ua string) Option {
+	return func(o *options) { o.userAgent = ua }
+}
+
+// WithETagCache sets whether the Client keeps the responses of GET requests that have an ETag,
+// to revalidate them instead of downloading them again when they didn't change. Enabled by default.
+func WithETagCache(enabled bool) Option {
+	return func(o *options) { o.noCache = !enabled }
+}
+
+// New creates a Client for the MCPJungle server at baseURL, authenticated with accessToken (which may be empty).
+// Without options, requests time out after DefaultTimeout and are retried up to DefaultMaxRetries times,
+// and unchanged GET responses are served from a cache, see CacheTransport.
+// When retries are enabled, POST requests are sent with a generated idempotency key so that they are retried too:
+// the server applies them once however many times they are sent.
+func New(baseURL string, accessToken string, opts ...Option) *Client {
+	o := options{
+		transport:  http.DefaultTransport,
+		timeout:    DefaultTimeout,
+		maxRetries: DefaultMaxRetries,
+		baseDelay:  DefaultRetryBaseDelay,
+		maxDelay:   DefaultRetryMaxDelay,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	transport := o.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if o.tlsConfig != nil {
+		transport = withTLSConfig(transport, o.tlsConfig)
+	}
+	if !o.noCache {
+		transport = NewCacheTransport(transport)
+	}
+	if o.maxRetries > 0 {
+		rt := NewRetryTransport(transport, o.maxRetries, o.retryLog)
+		rt.SetBackoff(o.baseDelay, o.maxDelay)
+		transport = rt
+	}
+
+	c := NewClient(baseURL, accessToken, &http.Client{Transport: transport, Timeout: o.timeout})
+	c.userAgent = o.userAgent
+	c.idempotencyKeys = o.maxRetries > 0
+	return c
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingTransport counts the requests that go through it.
+type countingTransport struct {
+	base  http.RoundTripper
+	count atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.count.Add(1)
+	return t.base.RoundTrip(req)
+}
+
+func TestNewDefaults(t *testing.T) {
+	t.Parallel()
+
+	c := New("http://127.0.0.1:8080", "token")
+	if c.HTTPClient().Timeout != DefaultTimeout {
+		t.Errorf("Expected timeout %s, got %s", DefaultTimeout, c.HTTPClient().Timeout)
+	}
+	rt, ok := c.HTTPClient().Transport.(*RetryTransport)
+	if !ok {
+		t.Fatalf("Expected requests to be retried by default, got transport %T", c.HTTPClient().Transport)
+	}
+	if rt.maxRetries != DefaultMaxRetries || rt.baseDelay != DefaultRetryBaseDelay || rt.maxDelay != DefaultRetryMaxDelay {
+		t.Errorf("Expected the default retry policy, got %d retries between %s and %s", rt.maxRetries, rt.baseDelay, rt.maxDelay)
+	}
+}
+
+func TestNewWithOptions(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	var userAgent atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent.Store(r.Header.Get("User-Agent"))
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{base: http.DefaultTransport}
+	c := New(
+		server.URL, "",
+		WithTransport(transport),
+		WithRetries(3),
+		WithBackoff(time.Millisecond, 2*time.Millisecond),
+		WithTimeout(10*time.Second),
+		WithUserAgent("billing-service/1.4"),
+	)
+	if c.HTTPClient().Timeout != 10*time.Second {
+		t.Errorf("Expected timeout 10s, got %s", c.HTTPClient().Timeout)
+	}
+
+	if _, err := c.ListServersContext(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// the custom transport sees every attempt, retries included
+	if transport.count.Load() != 2 {
+		t.Errorf("Expected 2 requests through the custom transport, got %d", transport.count.Load())
+	}
+	if ua := userAgent.Load(); ua != "billing-service/1.4" {
+		t.Errorf("Expected user agent billing-service/1.4, got %v", ua)
+	}
+}
+
+func TestNewWithoutRetries(t *testing.T) {
+	t.Parallel()
+
+	c := New("http://127.0.0.1:8080", "", WithRetries(0))
+	if _, ok := c.HTTPClient().Transport.(*RetryTransport); ok {
+		t.Error("Expected retries to be disabled")
+	}
+}
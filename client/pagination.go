@@ -0,0 +1,114 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/mcpjungle/mcpjungle/internal/api"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// PageOptions selects a page of a paginated list.
+type PageOptions struct {
+	// Limit is the maximum number of items in the page, 0 to use the server's default.
+	Limit int
+	// After is the NextCursor of the previous page, empty for the first page.
+	After string
+}
+
+// fetchPage fetches a page of the list served at path, with the given extra query parameters.
+func fetchPage[T any](ctx context.Context, c *Client, path string, query url.Values, opts PageOptions) (*types.Page[T], error) {
+	u, _ := c.constructAPIEndpoint(path)
+	req, err := c.newRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	q := req.URL.Query()
+	for k, v := range query {
+		q[k] = v
+	}
+	if opts.Limit > 0 {
+		q.Set(types.PageLimitParam, strconv.Itoa(opts.Limit))
+	}
+	if opts.After != "" {
+		q.Set(types.PageAfterParam, opts.After)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return decodePage[T](raw)
+}
+
+// decodePage decodes a page of a list.
+// Servers that predate pagination respond with the full list as a JSON array, which is returned as a single page.
+func decodePage[T any](raw json.RawMessage) (*types.Page[T], error) {
+	page := &types.Page[T]{}
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &page.Items); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return page, nil
+	}
+	if err := json.Unmarshal(raw, page); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return page, nil
+}
+
+// allPages returns an iterator over the items of every page returned by fetch.
+// Pages are fetched as the iteration proceeds, iteration stops at the first error.
+func allPages[T any](ctx context.Context, fetch func(ctx context.Context, opts PageOptions) (*types.Page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		// the largest pages make for the fewest round trips
+		opts := PageOptions{Limit: api.MaxPageLimit}
+		for {
+			page, err := fetch(ctx, opts)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" {
+				return
+			}
+			opts.After = page.NextCursor
+		}
+	}
+}
+
+// collect returns all the items of seq, or the first error it yields.
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	items := []T{}
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// pagedUsersServer serves n users, in pages of at most limit users.
+func pagedUsersServer(t *testing.T, n int) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		limit, _ := strconv.Atoi(r.URL.Query().Get(types.PageLimitParam))
+		start, _ := strconv.Atoi(r.URL.Query().Get(types.PageAfterParam))
+		page := types.Page[*types.User]{Items: []*types.User{}}
+		for i := start; i < n && len(page.Items) < limit; i++ {
+			page.Items = append(page.Items, &types.User{Username: "user-" + strconv.Itoa(i)})
+		}
+		if end := start + len(page.Items); end < n {
+			page.NextCursor = strconv.Itoa(end)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestListUsersPage(t *testing.T) {
+	t.Parallel()
+	srv, _ := pagedUsersServer(t, 5)
+	c := NewClient(srv.URL, "", &http.Client{})
+
+	page, err := c.ListUsersPage(context.Background(), PageOptions{Limit: 2, After: "2"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[0].Username != "user-2" || page.NextCursor != "4" {
+		t.Errorf("Expected users 2 and 3 followed by cursor 4, got %d users and cursor %q", len(page.Items), page.NextCursor)
+	}
+}
+
+func TestAllUsersFollowsCursors(t *testing.T) {
+	t.Parallel()
+	srv, requests := pagedUsersServer(t, 2500)
+	c := NewClient(srv.URL, "", &http.Client{})
+
+	users, err := c.ListUsersContext(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(users) != 2500 || users[2499].Username != "user-2499" {
+		t.Errorf("Expected all 2500 users, got %d", len(users))
+	}
+	if *requests != 3 {
+		t.Errorf("Expected 3 pages to be fetched, got %d", *requests)
+	}
+
+	// stopping the iteration early doesn't fetch the remaining pages
+	*requests = 0
+	for u, err := range c.AllUsers(context.Background()) {
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if u.Username == "user-10" {
+			break
+		}
+	}
+	if *requests != 1 {
+		t.Errorf("Expected a single page to be fetched, got %d", *requests)
+	}
+}
+
+func TestListFromServerWithoutPagination(t *testing.T) {
+	t.Parallel()
+	// servers that predate pagination respond with the full list
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(` [{"username":"alice","role":"admin"},{"username":"bob","role":"user"}]`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "", &http.Client{})
+
+	users, err := c.ListUsersContext(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(users) != 2 || users[1].Username != "bob" {
+		t.Errorf("Expected alice and bob, got %v", users)
+	}
+}
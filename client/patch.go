@@ -0,0 +1,52 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mcpjungle/mcpjungle/internal/api"
+)
+
+// PatchOptions configures a partial update of an entity.
+type PatchOptions struct {
+	// Version is the version of the entity the patch was computed against, 0 to apply the patch unconditionally.
+	// If the entity was changed since, the update fails with an *APIError with status 412.
+	Version uint
+}
+
+// sendMergePatch sends a JSON merge patch (RFC 7386) to the entity at path and decodes the updated entity into out.
+// Fields of the patch that are nil are cleared.
+func sendMergePatch(ctx context.Context, c *Client, path string, patch any, opts PatchOptions, out any) error {
+	u, _ := c.constructAPIEndpoint(path)
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed to serialize the patch into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPatch, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	req.Header.Set("Content-Type", api.MergePatchContentType)
+	if opts.Version > 0 {
+		req.Header.Set("If-Match", strconv.Quote(strconv.FormatUint(uint64(opts.Version), 10)))
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.parseErrorResponse(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
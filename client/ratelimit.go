@@ -0,0 +1,47 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// RateLimit is the budget of API requests of the caller, as reported by a server configured with a rate limit.
+type RateLimit struct {
+	// Limit is the number of requests the caller can make per window.
+	Limit int
+	// Remaining is the number of requests the caller can still make in the current window.
+	Remaining int
+	// Reset is when the current window ends and the budget is reset.
+	Reset time.Time
+}
+
+// ParseRateLimit reads the rate limit reported in the X-RateLimit-* headers of a response.
+// It returns false if the response doesn't report one.
+func ParseRateLimit(h http.Header) (RateLimit, bool) {
+	limit, err := strconv.Atoi(h.Get(types.RateLimitLimitHeader))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(h.Get(types.RateLimitRemainingHeader))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	reset, err := strconv.ParseInt(h.Get(types.RateLimitResetHeader), 10, 64)
+	if err != nil {
+		return RateLimit{}, false
+	}
+	return RateLimit{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}, true
+}
+
+// RateLimit returns the rate limit reported by the last response the Client received,
+// and false if no response reported one, eg- because the server doesn't rate limit requests.
+func (c *Client) RateLimit() (RateLimit, bool) {
+	rl := c.rateLimit.Load()
+	if rl == nil {
+		return RateLimit{}, false
+	}
+	return *rl, true
+}
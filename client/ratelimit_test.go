@@ -0,0 +1,91 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// rateLimitedServer serves GET /servers/github with a budget of 2 requests, then rejects requests with 429.
+func rateLimitedServer(t *testing.T, reset time.Time) *httptest.Server {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remaining := max(2-int(calls.Add(1)), 0)
+		w.Header().Set(types.RateLimitLimitHeader, "2")
+		w.Header().Set(types.RateLimitRemainingHeader, strconv.Itoa(remaining))
+		w.Header().Set(types.RateLimitResetHeader, strconv.FormatInt(reset.Unix(), 10))
+		if calls.Load() > 2 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error": "rate limit of 2 requests per 1m0s exceeded, retry in 30 seconds"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"name": "github", "transport": "streamable_http"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRateLimit(t *testing.T) {
+	reset := time.Now().Add(30 * time.Second).Truncate(time.Second)
+	srv := rateLimitedServer(t, reset)
+	c := New(srv.URL, "", WithRetries(0))
+
+	_, ok := c.RateLimit()
+	testhelpers.AssertFalse(t, ok, "no rate limit should be known before the first response")
+
+	for _, remaining := range []int{1, 0} {
+		_, err := c.GetServerContext(context.Background(), "github")
+		testhelpers.AssertNoError(t, err)
+		rl, ok := c.RateLimit()
+		testhelpers.AssertTrue(t, ok, "the rate limit of the response should be recorded")
+		testhelpers.AssertEqual(t, RateLimit{Limit: 2, Remaining: remaining, Reset: reset}, rl)
+	}
+
+	_, err := c.GetServerContext(context.Background(), "github")
+	var apiErr *APIError
+	testhelpers.AssertTrue(t, errors.As(err, &apiErr), "expected an APIError")
+	testhelpers.AssertEqual(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	testhelpers.AssertEqual(t, 30*time.Second, apiErr.RetryAfter)
+	testhelpers.AssertTrue(t, apiErr.RateLimit != nil, "the rate limit should be reported with the error")
+	testhelpers.AssertEqual(t, 0, apiErr.RateLimit.Remaining)
+}
+
+func TestParseRateLimit(t *testing.T) {
+	h := http.Header{}
+	_, ok := ParseRateLimit(h)
+	testhelpers.AssertFalse(t, ok, "a response without headers has no rate limit")
+
+	h.Set(types.RateLimitLimitHeader, "60")
+	h.Set(types.RateLimitRemainingHeader, "many")
+	h.Set(types.RateLimitResetHeader, "1700000000")
+	_, ok = ParseRateLimit(h)
+	testhelpers.AssertFalse(t, ok, "invalid headers should be ignored")
+
+	h.Set(types.RateLimitRemainingHeader, "59")
+	rl, ok := ParseRateLimit(h)
+	testhelpers.AssertTrue(t, ok, "the rate limit should be parsed")
+	testhelpers.AssertEqual(t, RateLimit{Limit: 60, Remaining: 59, Reset: time.Unix(1700000000, 0)}, rl)
+}
+
+func TestLoggingTransportReportsRateLimit(t *testing.T) {
+	srv := rateLimitedServer(t, time.Now().Add(time.Minute))
+	logs := &bytes.Buffer{}
+	c := New(srv.URL, "", WithRetries(0), WithTransport(NewLoggingTransport(nil, logs, LogRequests)))
+
+	_, err := c.GetServerContext(context.Background(), "github")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertStringContains(t, logs.String(), "1 of 2 requests left, reset in ")
+	testhelpers.AssertTrue(t, strings.Count(logs.String(), "\n") == 1, "the budget should be logged on the request line")
+}
@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// DefaultMaxRetries is the number of times the CLI retries a request that failed with a transient error.
+const DefaultMaxRetries = 2
+
+// IdempotencyKeyHeader is the header that makes a mutating request safe to retry.
+// Requests carrying it are retried like GET requests, because the server applies them at most once.
+// A Client created with New sets it on its POST requests when retries are enabled.
+const IdempotencyKeyHeader = types.IdempotencyKeyHeader
+
+// Default delays between two attempts of a request: the delay doubles after every failed attempt, up to the maximum.
+const (
+	DefaultRetryBaseDelay = 250 * time.Millisecond
+	DefaultRetryMaxDelay  = 4 * time.Second
+)
+
+// maxRetryAfter is the longest Retry-After delay a request is retried after.
+// A server asking to wait longer is not going to recover soon, so its response is returned to the caller instead.
+const maxRetryAfter = time.Minute
+
+// RetryTransport is an http.RoundTripper that retries requests which failed with a transient error:
+// connection errors, timeouts and 429, 502, 503 & 504 responses.
+// Only requests that are safe to repeat are retried, see isRetryableRequest.
+//
+// Attempts are spaced with jittered exponential backoff, unless the response says how long to wait with
+// a Retry-After header. Retrying stops as soon as the request's context is done, so the total time of a request,
+// retries included, never exceeds the deadline or the http.Client timeout.
+type RetryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	// baseDelay and maxDelay bound the backoff between attempts
+	baseDelay, maxDelay time.Duration
+	// out receives a line for every retry, with the reason. Retries are not logged if it is nil.
+	out io.Writer
+
+	// sleep waits for d or until ctx is done. It is a field so that tests don't have to wait.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryTransport wraps base (http.DefaultTransport if nil) so that failed requests are retried up to
+// maxRetries times. Retries are logged to out, unless it is nil.
+func NewRetryTransport(base http.RoundTripper, maxRetries int, out io.Writer) *RetryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RetryTransport{
+		base:       base,
+		maxRetries: maxRetries,
+		baseDelay:  DefaultRetryBaseDelay,
+		maxDelay:   DefaultRetryMaxDelay,
+		out:        out,
+		sleep:      sleepContext,
+	}
+}
+
+// SetBackoff changes the delays between attempts: the first retry happens after up to base,
+// and the delay doubles after every failed attempt, up to maxDelay.
+func (t *RetryTransport) SetBackoff(base, maxDelay time.Duration) {
+	t.baseDelay, t.maxDelay = base, maxDelay
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.maxRetries <= 0 || !isRetryableRequest(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 && req.GetBody != nil {
+			// a round tripper must not modify the request, so the rewound body is set on a copy
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind the request body for a retry: %w", err)
+			}
+			r = req.Clone(req.Context())
+			r.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		reason, retry := retryReason(req, resp, err)
+		if !retry || attempt >= t.maxRetries {
+			return resp, err
+		}
+
+		delay := backoffDelay(t.baseDelay, t.maxDelay, attempt)
+		if d, ok := retryAfter(resp, time.Now()); ok {
+			// the server knows best when it will be able to serve the request again
+			if d > maxRetryAfter || exceedsDeadline(req.Context(), d) {
+				return resp, err
+			}
+			delay = d
+		}
+		if resp != nil {
+			// the response is discarded, drain it so that the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		if t.out != nil {
+			_, _ = fmt.Fprintf(
+				t.out, "[http] %s %s failed (%s), retrying in %s (retry %d of %d)\n",
+				req.Method, req.URL.String(), reason, delay.Round(time.Millisecond), attempt+1, t.maxRetries,
+			)
+		}
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isRetryableRequest reports whether repeating req cannot cause side effects on the server:
+// GET and HEAD requests, and mutating requests that carry an idempotency key.
+// A request whose body cannot be rewound is never retried.
+func isRetryableRequest(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	default:
+		return req.Header.Get(IdempotencyKeyHeader) != ""
+	}
+}
+
+// retryReason reports whether the outcome of an attempt is a transient failure worth retrying, and describes it.
+func retryReason(req *http.Request, resp *http.Response, err error) (string, bool) {
+	if err != nil {
+		// the caller gave up on the request, eg- because of the timeout or Ctrl+C
+		if req.Context().Err() != nil || errors.Is(err, context.Canceled) {
+			return "", false
+		}
+		return err.Error(), true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return resp.Status, true
+	default:
+		return "", false
+	}
+}
+
+// backoffDelay returns the delay before the retry that follows the given failed attempt (0-based).
+// The delay is picked randomly between half and all of the exponential backoff, so that many clients
+// failing at the same time don't all retry at the same time.
+func backoffDelay(base, maxDelay time.Duration, attempt int) time.Duration {
+	d := base << attempt
+	if d <= 0 || d > maxDelay {
+		d = maxDelay
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter returns the delay requested by the Retry-After header of resp, which is either
+// a number of seconds or an HTTP date. It returns false if resp has no valid Retry-After header.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// exceedsDeadline reports whether waiting for d would outlast the deadline of ctx.
+func exceedsDeadline(ctx context.Context, d time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Until(deadline) < d
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,271 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestRetryTransport returns a RetryTransport that records its delays instead of sleeping.
+func newTestRetryTransport(maxRetries int, out io.Writer) (*RetryTransport, *[]time.Duration) {
+	var delays []time.Duration
+	rt := NewRetryTransport(http.DefaultTransport, maxRetries, out)
+	rt.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	return rt, &delays
+}
+
+// flakyServer fails the first `failures` requests with status, then succeeds.
+func flakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if attempts.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &attempts
+}
+
+func TestRetryTransportRetriesTransientFailures(t *testing.T) {
+	srv, attempts := flakyServer(t, 2, http.StatusServiceUnavailable)
+	log := &strings.Builder{}
+	rt, delays := newTestRetryTransport(2, log)
+
+	resp, err := (&http.Client{Transport: rt}).Get(srv.URL + "/api/v1/servers")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200 after retries, got %d", resp.StatusCode)
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts.Load())
+	}
+	if len(*delays) != 2 {
+		t.Errorf("Expected 2 delays, got %d", len(*delays))
+	}
+	if !strings.Contains(log.String(), "failed (503 Service Unavailable), retrying in") {
+		t.Errorf("Expected retries to be logged with their reason, got %q", log.String())
+	}
+	if !strings.Contains(log.String(), "(retry 2 of 2)") {
+		t.Errorf("Expected the retry count to be logged, got %q", log.String())
+	}
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	srv, attempts := flakyServer(t, 10, http.StatusBadGateway)
+	rt, _ := newTestRetryTransport(2, nil)
+
+	resp, err := (&http.Client{Transport: rt}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// the response of the last attempt is returned as is
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected status 502, got %d", resp.StatusCode)
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts.Load())
+	}
+}
+
+func TestRetryTransportOnlyRetriesIdempotentRequests(t *testing.T) {
+	t.Run("errors other than 429, 502, 503 and 504", func(t *testing.T) {
+		srv, attempts := flakyServer(t, 1, http.StatusInternalServerError)
+		rt, _ := newTestRetryTransport(2, nil)
+		resp, err := (&http.Client{Transport: rt}).Get(srv.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if attempts.Load() != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts.Load())
+		}
+	})
+
+	t.Run("mutating request", func(t *testing.T) {
+		srv, attempts := flakyServer(t, 1, http.StatusServiceUnavailable)
+		rt, _ := newTestRetryTransport(2, nil)
+		resp, err := (&http.Client{Transport: rt}).Post(srv.URL, "application/json", strings.NewReader(`{}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if attempts.Load() != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts.Load())
+		}
+	})
+
+	t.Run("mutating request with an idempotency key", func(t *testing.T) {
+		srv, attempts := flakyServer(t, 1, http.StatusServiceUnavailable)
+		rt, _ := newTestRetryTransport(2, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{"name":"github"}`))
+		req.Header.Set(IdempotencyKeyHeader, "f81d4fae")
+		resp, err := (&http.Client{Transport: rt}).Do(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if attempts.Load() != 2 {
+			t.Errorf("Expected 2 attempts, got %d", attempts.Load())
+		}
+		// the body is sent again on retry
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != `{"name":"github"}` {
+			t.Errorf("Expected the request body to be resent, got %q", string(body))
+		}
+	})
+}
+
+func TestRetryTransportRespectsRetryAfter(t *testing.T) {
+	retryAfterServer := func(t *testing.T, retryAfter string) (*httptest.Server, *atomic.Int32) {
+		t.Helper()
+		var attempts atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				w.Header().Set("Retry-After", retryAfter)
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(srv.Close)
+		return srv, &attempts
+	}
+
+	t.Run("delay in seconds", func(t *testing.T) {
+		srv, attempts := retryAfterServer(t, "7")
+		rt, delays := newTestRetryTransport(2, nil)
+		resp, err := (&http.Client{Transport: rt}).Get(srv.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if attempts.Load() != 2 {
+			t.Errorf("Expected 2 attempts, got %d", attempts.Load())
+		}
+		if len(*delays) != 1 || (*delays)[0] != 7*time.Second {
+			t.Errorf("Expected to wait the 7s asked by the server, got %v", *delays)
+		}
+	})
+
+	t.Run("delay longer than the request deadline", func(t *testing.T) {
+		srv, attempts := retryAfterServer(t, "120")
+		rt, delays := newTestRetryTransport(2, nil)
+		resp, err := (&http.Client{Transport: rt, Timeout: 5 * time.Second}).Get(srv.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		// waiting would outlast the deadline, so the response is returned right away
+		if resp.StatusCode != http.StatusTooManyRequests || attempts.Load() != 1 || len(*delays) != 0 {
+			t.Errorf("Expected no retry, got status %d after %d attempts", resp.StatusCode, attempts.Load())
+		}
+	})
+
+	t.Run("mutating request", func(t *testing.T) {
+		srv, attempts := retryAfterServer(t, "1")
+		rt, _ := newTestRetryTransport(2, nil)
+		resp, err := (&http.Client{Transport: rt}).Post(srv.URL, "application/json", strings.NewReader(`{}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if attempts.Load() != 1 {
+			t.Errorf("Expected requests without an idempotency key not to be retried, got %d attempts", attempts.Load())
+		}
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{header: "", ok: false},
+		{header: "3", want: 3 * time.Second, ok: true},
+		{header: "-3", ok: false},
+		{header: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second, ok: true},
+		{header: now.Add(-time.Hour).Format(http.TimeFormat), want: 0, ok: true},
+		{header: "soon", ok: false},
+	}
+	for _, tc := range testCases {
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Set("Retry-After", tc.header)
+		got, ok := retryAfter(resp, now)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("retryAfter(%q) = %s, %t, expected %s, %t", tc.header, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestRetryTransportConnectionErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	rt, delays := newTestRetryTransport(2, nil)
+	_, err := (&http.Client{Transport: rt}).Get(srv.URL)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if len(*delays) != 2 {
+		t.Errorf("Expected connection errors to be retried twice, got %d retries", len(*delays))
+	}
+}
+
+func TestRetryTransportRespectsContext(t *testing.T) {
+	srv, attempts := flakyServer(t, 10, http.StatusServiceUnavailable)
+	rt := NewRetryTransport(http.DefaultTransport, 5, nil)
+
+	// the timeout covers all attempts, so retrying stops once it expires
+	httpClient := &http.Client{Transport: rt, Timeout: 100 * time.Millisecond}
+	start := time.Now()
+	_, err := httpClient.Get(srv.URL)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) && !strings.Contains(err.Error(), "Client.Timeout") {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected retries to stop at the timeout, took %s", elapsed)
+	}
+	if attempts.Load() >= 6 {
+		t.Errorf("Expected retries to stop before all attempts were made, got %d", attempts.Load())
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		d := backoffDelay(DefaultRetryBaseDelay, DefaultRetryMaxDelay, attempt)
+		limit := min(DefaultRetryBaseDelay<<attempt, DefaultRetryMaxDelay)
+		if d < limit/2 || d > limit {
+			t.Errorf("Expected delay of attempt %d to be between %s and %s, got %s", attempt, limit/2, limit, d)
+		}
+	}
+	// the delay is capped even for absurd numbers of attempts
+	if d := backoffDelay(DefaultRetryBaseDelay, DefaultRetryMaxDelay, 100); d > DefaultRetryMaxDelay {
+		t.Errorf("Expected delay to be capped at %s, got %s", DefaultRetryMaxDelay, d)
+	}
+}
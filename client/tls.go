@@ -0,0 +1,74 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// TLSOptions describes how a Client verifies the certificate of the server and authenticates itself to it.
+// The zero value uses the system trust store and no client certificate.
+type TLSOptions struct {
+	// CACertFile is the path of a PEM file with the certificates of the CAs to trust, on top of the system ones.
+	CACertFile string
+	// ClientCertFile and ClientKeyFile are the paths of the PEM encoded certificate and key the Client presents
+	// to servers that require mutual TLS. Either both or none must be set.
+	ClientCertFile string
+	ClientKeyFile  string
+	// InsecureSkipVerify disables the verification of the certificate of the server.
+	// Anyone on the network path can then impersonate the server, only use it for testing.
+	InsecureSkipVerify bool
+}
+
+// IsZero reports whether o has no settings, ie, connections use Go's default TLS configuration.
+func (o TLSOptions) IsZero() bool {
+	return o == TLSOptions{}
+}
+
+// Config loads the files referenced by o and returns the TLS configuration they describe.
+func (o TLSOptions) Config() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: o.InsecureSkipVerify}
+
+	if o.CACertFile != "" {
+		pem, err := os.ReadFile(o.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			// the system pool is unavailable on some platforms, the given CAs are trusted alone then
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM encoded certificates found in CA certificate file %s", o.CACertFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	switch {
+	case o.ClientCertFile != "" && o.ClientKeyFile != "":
+		cert, err := tls.LoadX509KeyPair(o.ClientCertFile, o.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	case o.ClientCertFile != "" || o.ClientKeyFile != "":
+		return nil, errors.New("a client certificate and its key must be set together")
+	}
+	return cfg, nil
+}
+
+// withTLSConfig returns a copy of rt that uses cfg for its connections.
+// Only *http.Transport can be configured, other transports are returned as they are.
+func withTLSConfig(rt http.RoundTripper, cfg *tls.Config) http.RoundTripper {
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return rt
+	}
+	t = t.Clone()
+	t.TLSClientConfig = cfg
+	return t
+}
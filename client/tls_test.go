@@ -0,0 +1,118 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeClientCert generates a self-signed client certificate and writes it and its key to dir.
+func writeClientCert(t *testing.T, dir string) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mcpjungle-cli"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ = x509.ParseCertificate(der)
+
+	certFile, keyFile = filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile, cert
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTLSOptions(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	clientCertFile, clientKeyFile, clientCert := writeClientCert(t, dir)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ready":true,"initialized":true}`))
+	}))
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(dir, "ca.crt")
+	writePEM(t, caFile, "CERTIFICATE", server.Certificate().Raw)
+
+	ready := func(opts TLSOptions) error {
+		t.Helper()
+		cfg, err := opts.Config()
+		if err != nil {
+			t.Fatalf("Failed to load TLS options %+v: %v", opts, err)
+		}
+		_, err = New(server.URL, "", WithTLSConfig(cfg), WithRetries(0)).GetServerReadiness(t.Context())
+		return err
+	}
+
+	if err := ready(TLSOptions{}); err == nil {
+		t.Error("Expected the certificate of the server not to be trusted by default")
+	}
+	if err := ready(TLSOptions{CACertFile: caFile}); err == nil {
+		t.Error("Expected the server to require a client certificate")
+	}
+	if err := ready(TLSOptions{CACertFile: caFile, ClientCertFile: clientCertFile, ClientKeyFile: clientKeyFile}); err != nil {
+		t.Errorf("Expected the request to succeed with the CA and the client certificate, got %v", err)
+	}
+	if err := ready(TLSOptions{InsecureSkipVerify: true, ClientCertFile: clientCertFile, ClientKeyFile: clientKeyFile}); err != nil {
+		t.Errorf("Expected the request to succeed without verification, got %v", err)
+	}
+}
+
+func TestTLSOptionsInvalid(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	clientCertFile, _, _ := writeClientCert(t, dir)
+	notPEM := filepath.Join(dir, "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, opts := range []TLSOptions{
+		{CACertFile: filepath.Join(dir, "missing.crt")},
+		{CACertFile: notPEM},
+		{ClientCertFile: clientCertFile},
+		{ClientCertFile: clientCertFile, ClientKeyFile: notPEM},
+	} {
+		if _, err := opts.Config(); err == nil {
+			t.Errorf("Expected an error for %+v", opts)
+		}
+	}
+}
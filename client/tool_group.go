@@ -2,15 +2,17 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
-// CreateToolGroup sends API request to create a new Tool Group.
-func (c *Client) CreateToolGroup(group *types.ToolGroup) (*types.CreateToolGroupResponse, error) {
+// CreateToolGroupContext sends API request to create a new Tool Group.
+func (c *Client) CreateToolGroupContext(ctx context.Context, group *types.ToolGroup) (*types.CreateToolGroupResponse, error) {
 	u, _ := c.constructAPIEndpoint("/tool-groups")
 
 	body, err := json.Marshal(group)
@@ -18,13 +20,13 @@ func (c *Client) CreateToolGroup(group *types.ToolGroup) (*types.CreateToolGroup
 		return nil, err
 	}
 
-	req, err := c.newRequest(http.MethodPost, u, bytes.NewBuffer(body))
+	req, err := c.newRequest(ctx, http.MethodPost, u, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -41,16 +43,23 @@ func (c *Client) CreateToolGroup(group *types.ToolGroup) (*types.CreateToolGroup
 	return &createResp, nil
 }
 
-// DeleteToolGroup sends API request to delete a Tool Group by name.
-func (c *Client) DeleteToolGroup(name string) error {
+// CreateToolGroup is like CreateToolGroupContext, without a context.
+//
+// Deprecated: use CreateToolGroupContext instead, CreateToolGroup will be removed in the next release.
+func (c *Client) CreateToolGroup(group *types.ToolGroup) (*types.CreateToolGroupResponse, error) {
+	return c.CreateToolGroupContext(context.Background(), group)
+}
+
+// DeleteToolGroupContext sends API request to delete a Tool Group by its name or UUID.
+func (c *Client) DeleteToolGroupContext(ctx context.Context, name string) error {
 	u, _ := c.constructAPIEndpoint("/tool-groups/" + name)
 
-	req, err := c.newRequest(http.MethodDelete, u, nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, u, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request to %s: %w", u, err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -63,16 +72,45 @@ func (c *Client) DeleteToolGroup(name string) error {
 	return nil
 }
 
-// ListToolGroups sends API request to list all Tool Groups.
+// DeleteToolGroup is like DeleteToolGroupContext, without a context.
+//
+// Deprecated: use DeleteToolGroupContext instead, DeleteToolGroup will be removed in the next release.
+func (c *Client) DeleteToolGroup(name string) error {
+	return c.DeleteToolGroupContext(context.Background(), name)
+}
+
+// ListToolGroupsContext fetches the list of tool groups, all pages of it.
+func (c *Client) ListToolGroupsContext(ctx context.Context) ([]types.ToolGroup, error) {
+	return collect(c.AllToolGroups(ctx))
+}
+
+// ListToolGroupsPage fetches a page of the list of tool groups.
+func (c *Client) ListToolGroupsPage(ctx context.Context, opts PageOptions) (*types.Page[types.ToolGroup], error) {
+	return fetchPage[types.ToolGroup](ctx, c, "/tool-groups", nil, opts)
+}
+
+// AllToolGroups iterates over all tool groups, fetching pages as needed.
+func (c *Client) AllToolGroups(ctx context.Context) iter.Seq2[types.ToolGroup, error] {
+	return allPages(ctx, c.ListToolGroupsPage)
+}
+
+// ListToolGroups is like ListToolGroupsContext, without a context.
+//
+// Deprecated: use ListToolGroupsContext instead, ListToolGroups will be removed in the next release.
 func (c *Client) ListToolGroups() ([]types.ToolGroup, error) {
-	u, _ := c.constructAPIEndpoint("/tool-groups")
+	return c.ListToolGroupsContext(context.Background())
+}
 
-	req, err := c.newRequest(http.MethodGet, u, nil)
+// GetToolGroupContext sends API request to get details of a specific Tool Group by its name or UUID.
+func (c *Client) GetToolGroupContext(ctx context.Context, name string) (*types.GetToolGroupResponse, error) {
+	u, _ := c.constructAPIEndpoint("/tool-groups/" + name)
+
+	req, err := c.newRequest(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -82,23 +120,35 @@ func (c *Client) ListToolGroups() ([]types.ToolGroup, error) {
 		return nil, c.parseErrorResponse(resp)
 	}
 
-	var groups []types.ToolGroup
-	if err := json.NewDecoder(resp.Body).Decode(&groups); err != nil {
+	var group types.GetToolGroupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&group); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
-	return groups, nil
+	return &group, nil
 }
 
-// GetToolGroup sends API request to get details of a specific Tool Group by name.
+// GetToolGroup is like GetToolGroupContext, without a context.
+//
+// Deprecated: use GetToolGroupContext instead, GetToolGroup will be removed in the next release.
 func (c *Client) GetToolGroup(name string) (*types.GetToolGroupResponse, error) {
-	u, _ := c.constructAPIEndpoint("/tool-groups/" + name)
+	return c.GetToolGroupContext(context.Background(), name)
+}
 
-	req, err := c.newRequest(http.MethodGet, u, nil)
+func (c *Client) UpdateToolGroupContext(ctx context.Context, group *types.ToolGroup) (*types.UpdateToolGroupResponse, error) {
+	u, _ := c.constructAPIEndpoint("/tool-groups/" + group.Name)
+
+	body, err := json.Marshal(group)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPut, u, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -108,28 +158,82 @@ func (c *Client) GetToolGroup(name string) (*types.GetToolGroupResponse, error)
 		return nil, c.parseErrorResponse(resp)
 	}
 
-	var group types.GetToolGroupResponse
-	if err := json.NewDecoder(resp.Body).Decode(&group); err != nil {
+	var updateResp types.UpdateToolGroupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&updateResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
-	return &group, nil
+	return &updateResp, nil
 }
 
+// UpdateToolGroup is like UpdateToolGroupContext, without a context.
+//
+// Deprecated: use UpdateToolGroupContext instead, UpdateToolGroup will be removed in the next release.
 func (c *Client) UpdateToolGroup(group *types.ToolGroup) (*types.UpdateToolGroupResponse, error) {
-	u, _ := c.constructAPIEndpoint("/tool-groups/" + group.Name)
+	return c.UpdateToolGroupContext(context.Background(), group)
+}
 
-	body, err := json.Marshal(group)
+// PatchToolGroup partially updates a Tool Group with a JSON merge patch.
+// The patch is a map or a struct encoding the fields of types.ToolGroup to change, nil values clear fields.
+// Lists of tools, servers and exclusions are replaced as a whole.
+// name is the name or the UUID of the group.
+func (c *Client) PatchToolGroup(ctx context.Context, name string, patch any, opts PatchOptions) (*types.UpdateToolGroupResponse, error) {
+	var updateResp types.UpdateToolGroupResponse
+	if err := sendMergePatch(ctx, c, "/tool-groups/"+name, patch, opts, &updateResp); err != nil {
+		return nil, err
+	}
+	return &updateResp, nil
+}
+
+// GetToolGroupConfigsContext returns all Tool Group configurations.
+// It is just a user-friendly wrapper around ListToolGroups(), without the UUIDs of the groups,
+// so that the configurations can be used to create the groups again elsewhere.
+func (c *Client) GetToolGroupConfigsContext(ctx context.Context) ([]types.ToolGroup, error) {
+	groups, err := c.ListToolGroupsContext(ctx)
+	for i := range groups {
+		groups[i].UUID = ""
+	}
+	return groups, err
+}
+
+// GetToolGroupConfig returns the configuration of a Tool Group, like GetToolGroupConfigsContext.
+// name is the name or the UUID of the group.
+func (c *Client) GetToolGroupConfig(ctx context.Context, name string) (*types.ToolGroup, error) {
+	group, err := c.GetToolGroupContext(ctx, name)
 	if err != nil {
 		return nil, err
 	}
+	if group.ToolGroup == nil {
+		return nil, fmt.Errorf("the response has no configuration for tool group %s", name)
+	}
+	group.ToolGroup.UUID = ""
+	return group.ToolGroup, nil
+}
 
-	req, err := c.newRequest(http.MethodPut, u, bytes.NewBuffer(body))
+// GetToolGroupConfigs is like GetToolGroupConfigsContext, without a context.
+//
+// Deprecated: use GetToolGroupConfigsContext instead, GetToolGroupConfigs will be removed in the next release.
+func (c *Client) GetToolGroupConfigs() ([]types.ToolGroup, error) {
+	return c.GetToolGroupConfigsContext(context.Background())
+}
+
+// GetToolReferencesContext returns the tool groups that reference any of the given tools, or any of the tools provided by
+// the given server. Either server or tools may be empty, but not both.
+func (c *Client) GetToolReferencesContext(ctx context.Context, server string, tools []string) (*types.ToolReferences, error) {
+	u, _ := c.constructAPIEndpoint("/tool-references")
+	req, err := c.newRequest(ctx, http.MethodGet, u, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	q := req.URL.Query()
+	if server != "" {
+		q.Add("server", server)
+	}
+	for _, t := range tools {
+		q.Add("tool", t)
+	}
+	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -139,15 +243,16 @@ func (c *Client) UpdateToolGroup(group *types.ToolGroup) (*types.UpdateToolGroup
 		return nil, c.parseErrorResponse(resp)
 	}
 
-	var updateResp types.UpdateToolGroupResponse
-	if err := json.NewDecoder(resp.Body).Decode(&updateResp); err != nil {
+	var refs types.ToolReferences
+	if err := json.NewDecoder(resp.Body).Decode(&refs); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
-	return &updateResp, nil
+	return &refs, nil
 }
 
-// GetToolGroupConfigs returns all Tool Group configurations.
-// It is just a user-friendly wrapper around ListToolGroups().
-func (c *Client) GetToolGroupConfigs() ([]types.ToolGroup, error) {
-	return c.ListToolGroups()
+// GetToolReferences is like GetToolReferencesContext, without a context.
+//
+// Deprecated: use GetToolReferencesContext instead, GetToolReferences will be removed in the next release.
+func (c *Client) GetToolReferences(server string, tools []string) (*types.ToolReferences, error) {
+	return c.GetToolReferencesContext(context.Background(), server, tools)
 }
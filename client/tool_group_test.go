@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -16,9 +17,9 @@ func TestCreateToolGroup(t *testing.T) {
 	t.Run("successful creation", func(t *testing.T) {
 		expectedResponse := &types.CreateToolGroupResponse{
 			ToolGroupEndpoints: &types.ToolGroupEndpoints{
-				StreamableHTTPEndpoint: "/api/v0/tool-groups/test-group",
-				SSEEndpoint:            "/api/v0/tool-groups/test-group/sse",
-				SSEMessageEndpoint:     "/api/v0/tool-groups/test-group/sse/message",
+				StreamableHTTPEndpoint: "/api/v1/tool-groups/test-group",
+				SSEEndpoint:            "/api/v1/tool-groups/test-group/sse",
+				SSEMessageEndpoint:     "/api/v1/tool-groups/test-group/sse/message",
 			},
 		}
 
@@ -61,7 +62,7 @@ func TestCreateToolGroup(t *testing.T) {
 			IncludedTools: []string{"tool1", "tool2"},
 		}
 
-		response, err := client.CreateToolGroup(toolGroup)
+		response, err := client.CreateToolGroupContext(context.Background(), toolGroup)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -85,7 +86,7 @@ func TestCreateToolGroup(t *testing.T) {
 			IncludedTools: []string{"tool1"},
 		}
 
-		response, err := client.CreateToolGroup(toolGroup)
+		response, err := client.CreateToolGroupContext(context.Background(), toolGroup)
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -101,6 +102,34 @@ func TestCreateToolGroup(t *testing.T) {
 	})
 }
 
+func TestGetToolGroupConfig(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/tool-groups/ops") {
+			t.Errorf("Expected path to end with /tool-groups/ops, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&types.GetToolGroupResponse{
+			ToolGroup:          &types.ToolGroup{UUID: "6f1c0b6e-3e8a-4d4e-9a51-3c7c37c1f2a0", Name: "ops", IncludedTools: []string{"a"}},
+			ToolGroupEndpoints: &types.ToolGroupEndpoints{},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", &http.Client{})
+	group, err := client.GetToolGroupConfig(context.Background(), "ops")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if group.Name != "ops" || len(group.IncludedTools) != 1 {
+		t.Errorf("Expected the configuration of ops, got %+v", group)
+	}
+	if group.UUID != "" {
+		t.Errorf("Expected the UUID to be left out of the configuration, got %s", group.UUID)
+	}
+}
+
 func TestGetToolGroup(t *testing.T) {
 	t.Parallel()
 
@@ -128,7 +157,7 @@ func TestGetToolGroup(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		group, err := client.GetToolGroup("test-group")
+		group, err := client.GetToolGroupContext(context.Background(), "test-group")
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -152,7 +181,7 @@ func TestGetToolGroup(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		group, err := client.GetToolGroup("non-existent-group")
+		group, err := client.GetToolGroupContext(context.Background(), "non-existent-group")
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -179,7 +208,7 @@ func TestDeleteToolGroup(t *testing.T) {
 			if r.Method != "DELETE" {
 				t.Errorf("Expected DELETE method, got %s", r.Method)
 			}
-			expectedPath := "/api/v0/tool-groups/" + groupName
+			expectedPath := "/api/v1/tool-groups/" + groupName
 			if !strings.HasSuffix(r.URL.Path, expectedPath) {
 				t.Errorf("Expected path to end with %s, got %s", expectedPath, r.URL.Path)
 			}
@@ -190,7 +219,7 @@ func TestDeleteToolGroup(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		err := client.DeleteToolGroup(groupName)
+		err := client.DeleteToolGroupContext(context.Background(), groupName)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -204,7 +233,7 @@ func TestDeleteToolGroup(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		err := client.DeleteToolGroup("non-existent-group")
+		err := client.DeleteToolGroupContext(context.Background(), "non-existent-group")
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -251,7 +280,7 @@ func TestListToolGroups(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		groups, err := client.ListToolGroups()
+		groups, err := client.ListToolGroupsContext(context.Background())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -276,7 +305,7 @@ func TestListToolGroups(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		groups, err := client.ListToolGroups()
+		groups, err := client.ListToolGroupsContext(context.Background())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -294,7 +323,7 @@ func TestListToolGroups(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		groups, err := client.ListToolGroups()
+		groups, err := client.ListToolGroupsContext(context.Background())
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -309,3 +338,41 @@ func TestListToolGroups(t *testing.T) {
 		}
 	})
 }
+
+func TestGetToolReferences(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/api/v1/tool-references") {
+			t.Errorf("Expected path to end with /api/v1/tool-references, got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("server"); got != "github" {
+			t.Errorf("Expected server query parameter github, got %s", got)
+		}
+		if got := r.URL.Query()["tool"]; len(got) != 1 || got[0] != "time__now" {
+			t.Errorf("Expected tool query parameter time__now, got %v", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(types.ToolReferences{
+			Tools:  []string{"time__now", "github__git_commit"},
+			Groups: []types.ToolGroupReference{{Name: "ci-tools", Tools: []string{"github__git_commit"}}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", &http.Client{})
+	refs, err := client.GetToolReferencesContext(context.Background(), "github", []string{"time__now"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(refs.Tools) != 2 {
+		t.Errorf("Expected 2 tools, got %d", len(refs.Tools))
+	}
+	if len(refs.Groups) != 1 || refs.Groups[0].Name != "ci-tools" {
+		t.Errorf("Expected group ci-tools, got %v", refs.Groups)
+	}
+}
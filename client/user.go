@@ -2,15 +2,17 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
-// CreateUser sends a request to create a new authenticated, human user in mcpjungle
-func (c *Client) CreateUser(user *types.CreateOrUpdateUserRequest) (*types.CreateOrUpdateUserResponse, error) {
+// CreateUserContext sends a request to create a new authenticated, human user in mcpjungle
+func (c *Client) CreateUserContext(ctx context.Context, user *types.CreateOrUpdateUserRequest) (*types.CreateOrUpdateUserResponse, error) {
 	u, _ := c.constructAPIEndpoint("/users")
 
 	body, err := json.Marshal(user)
@@ -18,13 +20,13 @@ func (c *Client) CreateUser(user *types.CreateOrUpdateUserRequest) (*types.Creat
 		return nil, err
 	}
 
-	req, err := c.newRequest(http.MethodPost, u, bytes.NewBuffer(body))
+	req, err := c.newRequest(ctx, http.MethodPost, u, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -41,16 +43,23 @@ func (c *Client) CreateUser(user *types.CreateOrUpdateUserRequest) (*types.Creat
 	return &createResp, nil
 }
 
-// DeleteUser sends a request to delete a user from mcpjungle
-func (c *Client) DeleteUser(username string) error {
+// CreateUser is like CreateUserContext, without a context.
+//
+// Deprecated: use CreateUserContext instead, CreateUser will be removed in the next release.
+func (c *Client) CreateUser(user *types.CreateOrUpdateUserRequest) (*types.CreateOrUpdateUserResponse, error) {
+	return c.CreateUserContext(context.Background(), user)
+}
+
+// DeleteUserContext sends a request to delete a user from mcpjungle, by their username or UUID
+func (c *Client) DeleteUserContext(ctx context.Context, username string) error {
 	u, _ := c.constructAPIEndpoint("/users/" + username)
 
-	req, err := c.newRequest(http.MethodDelete, u, nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, u, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request to %s: %w", u, err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -63,7 +72,14 @@ func (c *Client) DeleteUser(username string) error {
 	return nil
 }
 
-func (c *Client) UpdateUser(user *types.CreateOrUpdateUserRequest) (*types.CreateOrUpdateUserResponse, error) {
+// DeleteUser is like DeleteUserContext, without a context.
+//
+// Deprecated: use DeleteUserContext instead, DeleteUser will be removed in the next release.
+func (c *Client) DeleteUser(username string) error {
+	return c.DeleteUserContext(context.Background(), username)
+}
+
+func (c *Client) UpdateUserContext(ctx context.Context, user *types.CreateOrUpdateUserRequest) (*types.CreateOrUpdateUserResponse, error) {
 	u, _ := c.constructAPIEndpoint("/users/" + user.Username)
 
 	body, err := json.Marshal(user)
@@ -71,13 +87,13 @@ func (c *Client) UpdateUser(user *types.CreateOrUpdateUserRequest) (*types.Creat
 		return nil, err
 	}
 
-	req, err := c.newRequest(http.MethodPut, u, bytes.NewBuffer(body))
+	req, err := c.newRequest(ctx, http.MethodPut, u, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -94,43 +110,46 @@ func (c *Client) UpdateUser(user *types.CreateOrUpdateUserRequest) (*types.Creat
 	return &updateResp, nil
 }
 
-// ListUsers sends a request to list all users in mcpjungle
-func (c *Client) ListUsers() ([]*types.User, error) {
-	u, _ := c.constructAPIEndpoint("/users")
+// UpdateUser is like UpdateUserContext, without a context.
+//
+// Deprecated: use UpdateUserContext instead, UpdateUser will be removed in the next release.
+func (c *Client) UpdateUser(user *types.CreateOrUpdateUserRequest) (*types.CreateOrUpdateUserResponse, error) {
+	return c.UpdateUserContext(context.Background(), user)
+}
 
-	req, err := c.newRequest(http.MethodGet, u, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
-	}
+// ListUsersContext fetches the list of users, all pages of it.
+func (c *Client) ListUsersContext(ctx context.Context) ([]*types.User, error) {
+	return collect(c.AllUsers(ctx))
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
-	}
-	defer resp.Body.Close()
+// ListUsersPage fetches a page of the list of users.
+func (c *Client) ListUsersPage(ctx context.Context, opts PageOptions) (*types.Page[*types.User], error) {
+	return fetchPage[*types.User](ctx, c, "/users", nil, opts)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, c.parseErrorResponse(resp)
-	}
+// AllUsers iterates over all users, fetching pages as needed.
+func (c *Client) AllUsers(ctx context.Context) iter.Seq2[*types.User, error] {
+	return allPages(ctx, c.ListUsersPage)
+}
 
-	var users []*types.User
-	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-	return users, nil
+// ListUsers is like ListUsersContext, without a context.
+//
+// Deprecated: use ListUsersContext instead, ListUsers will be removed in the next release.
+func (c *Client) ListUsers() ([]*types.User, error) {
+	return c.ListUsersContext(context.Background())
 }
 
-// Whoami sends a request to get information about the user associated with the provided access token
-func (c *Client) Whoami(accessToken string) (*types.User, error) {
+// WhoamiContext sends a request to get information about the user associated with the provided access token
+func (c *Client) WhoamiContext(ctx context.Context, accessToken string) (*types.User, error) {
 	u, _ := c.constructAPIEndpoint("/users/whoami")
 
-	req, err := c.newRequest(http.MethodGet, u, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
 	}
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
@@ -146,3 +165,10 @@ func (c *Client) Whoami(accessToken string) (*types.User, error) {
 	}
 	return &user, nil
 }
+
+// Whoami is like WhoamiContext, without a context.
+//
+// Deprecated: use WhoamiContext instead, Whoami will be removed in the next release.
+func (c *Client) Whoami(accessToken string) (*types.User, error) {
+	return c.WhoamiContext(context.Background(), accessToken)
+}
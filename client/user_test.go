@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -55,7 +56,7 @@ func TestCreateUser(t *testing.T) {
 			Username: "testuser",
 		}
 
-		response, err := client.CreateUser(createUserRequest)
+		response, err := client.CreateUserContext(context.Background(), createUserRequest)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -77,7 +78,7 @@ func TestCreateUser(t *testing.T) {
 			Username: "testuser",
 		}
 
-		response, err := client.CreateUser(createUserRequest)
+		response, err := client.CreateUserContext(context.Background(), createUserRequest)
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -98,7 +99,7 @@ func TestCreateUser(t *testing.T) {
 			Username: "testuser",
 		}
 
-		response, err := client.CreateUser(createUserRequest)
+		response, err := client.CreateUserContext(context.Background(), createUserRequest)
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -151,7 +152,7 @@ func TestListUsers(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		users, err := client.ListUsers()
+		users, err := client.ListUsersContext(context.Background())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -179,7 +180,7 @@ func TestListUsers(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		users, err := client.ListUsers()
+		users, err := client.ListUsersContext(context.Background())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -197,7 +198,7 @@ func TestListUsers(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		users, err := client.ListUsers()
+		users, err := client.ListUsersContext(context.Background())
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -224,7 +225,7 @@ func TestDeleteUser(t *testing.T) {
 			if r.Method != "DELETE" {
 				t.Errorf("Expected DELETE method, got %s", r.Method)
 			}
-			expectedPath := "/api/v0/users/" + username
+			expectedPath := "/api/v1/users/" + username
 			if !strings.HasSuffix(r.URL.Path, expectedPath) {
 				t.Errorf("Expected path to end with %s, got %s", expectedPath, r.URL.Path)
 			}
@@ -241,7 +242,7 @@ func TestDeleteUser(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		err := client.DeleteUser(username)
+		err := client.DeleteUserContext(context.Background(), username)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -255,7 +256,7 @@ func TestDeleteUser(t *testing.T) {
 		defer server.Close()
 
 		client := NewClient(server.URL, "test-token", &http.Client{})
-		err := client.DeleteUser("non-existent-user")
+		err := client.DeleteUserContext(context.Background(), "non-existent-user")
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -269,7 +270,7 @@ func TestDeleteUser(t *testing.T) {
 
 	t.Run("network error", func(t *testing.T) {
 		client := NewClient("http://invalid-url", "test-token", &http.Client{})
-		err := client.DeleteUser("testuser")
+		err := client.DeleteUserContext(context.Background(), "testuser")
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -320,7 +321,7 @@ func TestCreateUserWithDifferentUsernames(t *testing.T) {
 				Username: tc.username,
 			}
 
-			_, err := client.CreateUser(createUserRequest)
+			_, err := client.CreateUserContext(context.Background(), createUserRequest)
 			if err != nil {
 				t.Fatalf("Unexpected error: %v", err)
 			}
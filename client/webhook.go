@@ -0,0 +1,124 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// CreateWebhook subscribes a URL to registry events.
+// The returned webhook holds its secret, which is not returned by any other method: keep it to verify the deliveries.
+func (c *Client) CreateWebhook(ctx context.Context, webhook *types.Webhook) (*types.Webhook, error) {
+	var created types.Webhook
+	if err := c.webhookRequest(ctx, http.MethodPost, "/webhooks", webhook, http.StatusCreated, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetWebhook fetches a webhook by name.
+func (c *Client) GetWebhook(ctx context.Context, name string) (*types.Webhook, error) {
+	var w types.Webhook
+	if err := c.webhookRequest(ctx, http.MethodGet, "/webhooks/"+name, nil, http.StatusOK, &w); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// ListWebhooks fetches the list of webhooks, all pages of it.
+func (c *Client) ListWebhooks(ctx context.Context) ([]*types.Webhook, error) {
+	return collect(c.AllWebhooks(ctx))
+}
+
+// ListWebhooksPage fetches a page of the list of webhooks.
+func (c *Client) ListWebhooksPage(ctx context.Context, opts PageOptions) (*types.Page[*types.Webhook], error) {
+	return fetchPage[*types.Webhook](ctx, c, "/webhooks", nil, opts)
+}
+
+// AllWebhooks iterates over all webhooks, fetching pages as needed.
+func (c *Client) AllWebhooks(ctx context.Context) iter.Seq2[*types.Webhook, error] {
+	return allPages(ctx, c.ListWebhooksPage)
+}
+
+// DeleteWebhook deletes a webhook along with its delivery log.
+func (c *Client) DeleteWebhook(ctx context.Context, name string) error {
+	return c.webhookRequest(ctx, http.MethodDelete, "/webhooks/"+name, nil, http.StatusNoContent, nil)
+}
+
+// EnableWebhook enables a webhook, eg- after it was disabled automatically because its deliveries kept failing.
+func (c *Client) EnableWebhook(ctx context.Context, name string) (*types.Webhook, error) {
+	var w types.Webhook
+	if err := c.webhookRequest(ctx, http.MethodPost, "/webhooks/"+name+"/enable", nil, http.StatusOK, &w); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// DisableWebhook stops the deliveries to a webhook.
+func (c *Client) DisableWebhook(ctx context.Context, name string) (*types.Webhook, error) {
+	var w types.Webhook
+	if err := c.webhookRequest(ctx, http.MethodPost, "/webhooks/"+name+"/disable", nil, http.StatusOK, &w); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// TestWebhook makes the server send a test event to a webhook, and returns the outcome of the delivery.
+// A delivery that failed is not an error, check the Succeeded field of the result.
+func (c *Client) TestWebhook(ctx context.Context, name string) (*types.WebhookDelivery, error) {
+	var d types.WebhookDelivery
+	if err := c.webhookRequest(ctx, http.MethodPost, "/webhooks/"+name+"/test", nil, http.StatusOK, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ListWebhookDeliveriesPage fetches a page of the delivery log of a webhook, oldest first.
+func (c *Client) ListWebhookDeliveriesPage(ctx context.Context, name string, opts PageOptions) (*types.Page[types.WebhookDelivery], error) {
+	return fetchPage[types.WebhookDelivery](ctx, c, "/webhooks/"+name+"/deliveries", nil, opts)
+}
+
+// webhookRequest sends a request with an optional JSON body to the webhooks API,
+// and decodes the response into out unless it is nil.
+func (c *Client) webhookRequest(ctx context.Context, method, path string, in any, wantStatus int, out any) error {
+	u, _ := c.constructAPIEndpoint(path)
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to serialize the request into JSON: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := c.newRequest(ctx, method, u, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != wantStatus {
+		return c.parseErrorResponse(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/webhooks" {
+			t.Errorf("Expected POST /api/v1/webhooks, got %s %s", r.Method, r.URL.Path)
+		}
+		var in types.Webhook
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if in.Name != "ci" || in.URL != "https://ci.example.com/hook" {
+			t.Errorf("Unexpected webhook in request: %+v", in)
+		}
+		in.Secret = "generated-secret-value"
+		in.Enabled = true
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(in)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", &http.Client{})
+	created, err := client.CreateWebhook(context.Background(), &types.Webhook{Name: "ci", URL: "https://ci.example.com/hook"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if created.Secret != "generated-secret-value" {
+		t.Errorf("Expected the generated secret, got %q", created.Secret)
+	}
+	if !created.Enabled {
+		t.Error("Expected the webhook to be enabled")
+	}
+}
+
+func TestTestWebhook(t *testing.T) {
+	t.Parallel()
+
+	t.Run("failed delivery", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/api/v1/webhooks/ci/test" {
+				t.Errorf("Expected POST /api/v1/webhooks/ci/test, got %s %s", r.Method, r.URL.Path)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(types.WebhookDelivery{
+				EventID: "abc", Event: types.EventWebhookTest, Attempts: 1, StatusCode: 500,
+				Error: "the webhook responded with status 500",
+			})
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, "test-token", &http.Client{})
+		d, err := client.TestWebhook(context.Background(), "ci")
+		if err != nil {
+			t.Fatalf("A failed delivery should not be an error, got: %v", err)
+		}
+		if d.Succeeded || d.StatusCode != 500 {
+			t.Errorf("Unexpected delivery: %+v", d)
+		}
+	})
+
+	t.Run("unknown webhook", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "record not found"}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, "test-token", &http.Client{})
+		if _, err := client.TestWebhook(context.Background(), "ci"); err == nil {
+			t.Error("Expected an error for an unknown webhook")
+		}
+	})
+}
@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	clientconfig "github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// BackupPassphraseEnvVar is the env var the passphrase of backups is read from, when --passphrase-file is not set.
+const BackupPassphraseEnvVar = "MCPJUNGLE_BACKUP_PASSPHRASE"
+
+// backupProgressRows is the number of rows after which the progress of a backup is updated.
+const backupProgressRows = 1000
+
+// errNotABackup is returned when a file is not a backup of mcpjungle.
+var errNotABackup = errors.New("the file is not a backup of mcpjungle")
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up the registry to a file",
+	Long: "Write a consistent backup of the whole registry to a file: its MCP servers, tools, prompts, tool groups,\n" +
+		"MCP clients, users and webhooks. The server reads it in a single transaction, so it can keep serving requests.\n\n" +
+		"The credentials the backup holds, eg- the access tokens and the bearer tokens of the MCP servers, are encrypted\n" +
+		"with a passphrase, which is needed to restore the backup.\n" +
+		"It is read from the file passed with --passphrase-file, otherwise from " + BackupPassphraseEnvVar + ",\n" +
+		"otherwise it is asked for.\n\n" +
+		"The delivery log of the webhooks, which can be large, is only backed up with --include-audit.\n" +
+		"Restore the backup with `mcpjungle restore`, into a server of the same version.\n\n" +
+		"NOTE: In enterprise mode, you must be an admin to back up the registry.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "21",
+	},
+	Example: "  mcpjungle backup -o backup.mcpj\n" +
+		"  MCPJUNGLE_BACKUP_PASSPHRASE=... mcpjungle backup -o nightly.mcpj --include-audit",
+	Args: cobra.NoArgs,
+	RunE: runBackup,
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a backup of the registry",
+	Long: "Restore a backup made with `mcpjungle backup` into the registry, as a whole or not at all.\n\n" +
+		"The registry must be empty, eg- a server that was just initialized, unless --force is set,\n" +
+		"in which case all its entities are replaced by the ones of the backup.\n" +
+		"The users are always replaced, log in with the access token of an admin of the backup afterwards.\n" +
+		"The backup must be restored by a server of the version and mode it was made with.\n\n" +
+		"The passphrase of the backup is read from the file passed with --passphrase-file,\n" +
+		"otherwise from " + BackupPassphraseEnvVar + ", otherwise it is asked for.\n\n" +
+		"The server keeps serving the entities it loaded when it started, restart it once the backup is restored.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "22",
+	},
+	Example: "  mcpjungle restore -i backup.mcpj\n" +
+		"  mcpjungle restore -i backup.mcpj --force --passphrase-file ./passphrase",
+	Args: cobra.NoArgs,
+	RunE: runRestore,
+}
+
+var (
+	backupCmdOutput         string
+	backupCmdIncludeAudit   bool
+	backupCmdPassphraseFile string
+
+	restoreCmdInput          string
+	restoreCmdForce          bool
+	restoreCmdPassphraseFile string
+)
+
+func init() {
+	backupCmd.Flags().StringVarP(&backupCmdOutput, "output", "o", "", "File to write the backup to, it must not exist")
+	_ = backupCmd.MarkFlagRequired("output")
+	backupCmd.Flags().BoolVar(
+		&backupCmdIncludeAudit,
+		"include-audit",
+		false,
+		"Include the audit data, ie- the delivery log of the webhooks",
+	)
+	backupCmd.Flags().StringVar(
+		&backupCmdPassphraseFile,
+		"passphrase-file",
+		"",
+		"Path to a file containing the passphrase to encrypt the credentials of the backup with",
+	)
+
+	restoreCmd.Flags().StringVarP(&restoreCmdInput, "input", "i", "", "File to read the backup from")
+	_ = restoreCmd.MarkFlagRequired("input")
+	restoreCmd.Flags().BoolVar(
+		&restoreCmdForce,
+		"force",
+		false,
+		"Replace the entities of a registry that is not empty",
+	)
+	restoreCmd.Flags().StringVar(
+		&restoreCmdPassphraseFile,
+		"passphrase-file",
+		"",
+		"Path to a file containing the passphrase the backup was made with",
+	)
+
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
+
+// readBackupPassphrase returns the passphrase of a backup, read from file if set, otherwise from the env var,
+// otherwise asked for on the terminal. A new passphrase is asked for twice, so that a typo doesn't make
+// the backup impossible to restore.
+func readBackupPassphrase(cmd *cobra.Command, file string, isNew bool) (string, error) {
+	var passphrase string
+	switch {
+	case file != "":
+		data, err := os.ReadFile(clientconfig.ExpandHome(file))
+		if err != nil {
+			return "", fmt.Errorf("failed to read the passphrase file: %w", err)
+		}
+		passphrase = strings.TrimSpace(string(data))
+	case os.Getenv(BackupPassphraseEnvVar) != "":
+		passphrase = os.Getenv(BackupPassphraseEnvVar)
+	case stdinIsTerminal():
+		p := newPrompter(cmd.InOrStdin(), cmd.ErrOrStderr())
+		var err error
+		if passphrase, err = p.askSecret("Passphrase of the backup"); err != nil {
+			return "", err
+		}
+		if isNew && passphrase != "" {
+			again, err := p.askSecret("Repeat the passphrase")
+			if err != nil {
+				return "", err
+			}
+			if again != passphrase {
+				return "", usageErrorf("the passphrases don't match")
+			}
+		}
+	default:
+		return "", usageErrorf(
+			"the passphrase of the backup is required, pass --passphrase-file or set %s", BackupPassphraseEnvVar,
+		)
+	}
+	if passphrase == "" {
+		return "", usageErrorf("the passphrase of the backup must not be empty")
+	}
+	if isNew && len(passphrase) < types.MinBackupPassphraseLength {
+		return "", usageErrorf("the passphrase must be at least %d characters long", types.MinBackupPassphraseLength)
+	}
+	return passphrase, nil
+}
+
+func runBackup(cmd *cobra.Command, args []string) error {
+	path, err := filepath.Abs(clientconfig.ExpandHome(backupCmdOutput))
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return usageErrorf("%s already exists, choose another file to write the backup to", path)
+	}
+	passphrase, err := readBackupPassphrase(cmd, backupCmdPassphraseFile, true)
+	if err != nil {
+		return err
+	}
+
+	stream, err := apiClient.Backup(
+		commandContext(cmd), &types.BackupRequest{Passphrase: passphrase, IncludeAudit: backupCmdIncludeAudit},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to back up the registry: %w", err)
+	}
+	defer stream.Close()
+
+	// the backup is written next to its destination and only renamed once it is complete,
+	// so that an incomplete backup is never mistaken for a complete one
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create the backup file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	p := newPrinter(cmd)
+	pr := p.Progress("Backing up the registry")
+	tee := io.TeeReader(stream, f)
+	header, err := verifyBackup(tee, func(table string, rows, total int64) {
+		if rows%backupProgressRows == 0 || rows == total {
+			pr.Step("Backing up %s %d/%d rows", table, rows, total)
+		}
+	})
+	if err == nil {
+		// the trailer of the gzip stream, if it wasn't read already
+		_, err = io.Copy(io.Discard, tee)
+	}
+	pr.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to back up the registry: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write the backup file: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to write the backup file: %w", err)
+	}
+
+	if isStructuredOutput() {
+		return printOutput(cmd, header.Tables)
+	}
+	p.Resultf("Backed up %d rows of %d tables to %s\n", header.Rows(), len(header.Tables), path)
+	p.Infoln("Keep the passphrase safe, the backup can't be restored without it.")
+	return nil
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	path := clientconfig.ExpandHome(restoreCmdInput)
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open the backup: %w", err)
+	}
+	defer f.Close()
+
+	// the header is checked before anything is sent, to fail early with a file that is not a backup
+	header, err := readBackupHeader(f)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	p := newPrinter(cmd)
+	p.Infof(
+		"Backup made by mcpjungle %s on %s, with %d rows of %d tables\n",
+		header.Version, header.CreatedAt.Local().Format("2006-01-02 15:04:05"), header.Rows(), len(header.Tables),
+	)
+	if restoreCmdForce {
+		err := confirmDestructiveAction(cmd, confirmation{
+			Action: "replace the registry with the backup " + path,
+			Impact: []string{
+				"delete all the MCP servers, tools, prompts, tool groups, MCP clients, users and webhooks of the registry",
+				fmt.Sprintf("restore the %d rows of the backup in their place", header.Rows()),
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	passphrase, err := readBackupPassphrase(cmd, restoreCmdPassphraseFile, false)
+	if err != nil {
+		return err
+	}
+
+	pr := p.Progress("Uploading the backup")
+	body := &uploadProgress{r: f, size: info.Size(), pr: pr}
+	result, err := apiClient.Restore(commandContext(cmd), body, passphrase, restoreCmdForce)
+	pr.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to restore the backup: %w", err)
+	}
+
+	if isStructuredOutput() {
+		return printOutput(cmd, result)
+	}
+	var rows int64
+	for _, t := range result.Tables {
+		rows += t.Rows
+	}
+	p.Resultf("Restored %d rows of %d tables from %s\n", rows, len(result.Tables), path)
+	for _, t := range result.Tables {
+		p.Infof("  %s: %d\n", t.Name, t.Rows)
+	}
+	p.Infoln()
+	p.Infoln("Restart the server to serve the restored registry.")
+	p.Infoln("The users were replaced by the ones of the backup, log in with the access token of one of its admins.")
+	return nil
+}
+
+// readBackupHeader reads the header of a backup.
+func readBackupHeader(r io.Reader) (*types.BackupHeader, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errNotABackup
+	}
+	var h types.BackupHeader
+	if err := json.NewDecoder(zr).Decode(&h); err != nil || h.Format != types.BackupFormat {
+		return nil, errNotABackup
+	}
+	return &h, nil
+}
+
+// verifyBackup reads a backup up to its end record, calling progress after every row with its table,
+// the number of rows of the table read so far and in total. It fails if the backup is incomplete,
+// eg- because the server failed while streaming it.
+func verifyBackup(
+	r io.Reader, progress func(table string, rows, total int64),
+) (*types.BackupHeader, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errNotABackup
+	}
+	dec := json.NewDecoder(zr)
+	var h types.BackupHeader
+	if err := dec.Decode(&h); err != nil || h.Format != types.BackupFormat {
+		return nil, errNotABackup
+	}
+	totals := make(map[string]int64, len(h.Tables))
+	for _, t := range h.Tables {
+		totals[t.Name] = t.Rows
+	}
+
+	var table string
+	var rows int64
+	for {
+		var rec types.BackupRecord
+		if err := dec.Decode(&rec); err != nil {
+			return nil, fmt.Errorf("the backup is incomplete: %w", err)
+		}
+		switch {
+		case rec.Error != nil:
+			return nil, fmt.Errorf("the server failed while streaming the backup: %s", rec.Error.Message)
+		case rec.End:
+			// reading the rest of the stream checks its checksum
+			if _, err := io.Copy(io.Discard, zr); err != nil {
+				return nil, fmt.Errorf("the backup is corrupted: %w", err)
+			}
+			return &h, nil
+		}
+		if rec.Table != table {
+			table, rows = rec.Table, 0
+		}
+		rows++
+		progress(table, rows, totals[table])
+	}
+}
+
+// uploadProgress shows how much of a file was uploaded as it is read.
+type uploadProgress struct {
+	r    io.Reader
+	size int64
+	read int64
+	pr   *progress
+}
+
+func (u *uploadProgress) Read(b []byte) (int, error) {
+	n, err := u.r.Read(b)
+	u.read += int64(n)
+	switch {
+	case errors.Is(err, io.EOF):
+		u.pr.Step("Restoring the backup")
+	case u.size > 0:
+		u.pr.Step("Uploading the backup %d%%", u.read*100/u.size)
+	}
+	return n, err
+}
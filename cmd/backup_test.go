@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// newTestBackupFile returns a backup of a registry with two MCP servers, without its end record if incomplete.
+func newTestBackupFile(t *testing.T, incomplete bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	testhelpers.AssertNoError(t, enc.Encode(types.BackupHeader{
+		Format: types.BackupFormat, FormatVersion: types.BackupFormatVersion, SchemaVersion: 1, Version: "0.3.0",
+		CreatedAt: time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC), Tables: []types.BackupTable{{Name: "mcp_servers", Rows: 2}},
+	}))
+	for _, name := range []string{"github", "slack"} {
+		testhelpers.AssertNoError(t, enc.Encode(types.BackupRecord{Table: "mcp_servers", Row: json.RawMessage(`{"name":"` + name + `"}`)}))
+	}
+	if !incomplete {
+		testhelpers.AssertNoError(t, enc.Encode(types.BackupRecord{End: true}))
+	}
+	testhelpers.AssertNoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func newBackupTestCmd(t *testing.T) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	withConfirmState(t, true, false)
+	t.Setenv(BackupPassphraseEnvVar, "correct horse")
+	cmd := &cobra.Command{}
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	return cmd, out
+}
+
+func TestBackupCommandStructure(t *testing.T) {
+	testhelpers.TestCommandAnnotations(t, backupCmd.Annotations, []testhelpers.CommandAnnotationTest{
+		{Key: "group", Expected: string(subCommandGroupAdvanced)},
+		{Key: "order", Expected: "21"},
+	})
+	testhelpers.TestCommandAnnotations(t, restoreCmd.Annotations, []testhelpers.CommandAnnotationTest{
+		{Key: "group", Expected: string(subCommandGroupAdvanced)},
+		{Key: "order", Expected: "22"},
+	})
+	testhelpers.AssertNotNil(t, backupCmd.Flags().Lookup("include-audit"))
+	testhelpers.AssertNotNil(t, restoreCmd.Flags().Lookup("force"))
+}
+
+func TestRunBackup(t *testing.T) {
+	var incomplete bool
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"POST /api/v1/backup": func(w http.ResponseWriter, r *http.Request) {
+			var req types.BackupRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.Passphrase != "correct horse" || !req.IncludeAudit {
+				t.Errorf("unexpected backup request: %+v", req)
+			}
+			w.Header().Set("Content-Type", types.BackupContentType)
+			_, _ = w.Write(newTestBackupFile(t, incomplete))
+		},
+	})
+	dir := t.TempDir()
+	origOutput, origAudit := backupCmdOutput, backupCmdIncludeAudit
+	t.Cleanup(func() { backupCmdOutput, backupCmdIncludeAudit = origOutput, origAudit })
+	backupCmdIncludeAudit = true
+
+	backupCmdOutput = filepath.Join(dir, "backup.mcpj")
+	cmd, out := newBackupTestCmd(t)
+	testhelpers.AssertNoError(t, runBackup(cmd, nil))
+	testhelpers.AssertStringContains(t, out.String(), "Backed up 2 rows of 1 tables")
+	data, err := os.ReadFile(backupCmdOutput)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, string(newTestBackupFile(t, false)), string(data))
+
+	// an existing backup is never overwritten
+	cmd, _ = newBackupTestCmd(t)
+	err = runBackup(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "already exists")
+
+	// an incomplete backup is not kept
+	incomplete = true
+	backupCmdOutput = filepath.Join(dir, "incomplete.mcpj")
+	cmd, _ = newBackupTestCmd(t)
+	err = runBackup(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "incomplete")
+	entries, err := os.ReadDir(dir)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(entries))
+
+	// the passphrase is required
+	t.Setenv(BackupPassphraseEnvVar, "")
+	err = runBackup(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+}
+
+func TestRunRestore(t *testing.T) {
+	status := http.StatusOK
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"POST /api/v1/restore": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.Header.Get(types.BackupPassphraseHeader); got != "correct horse" {
+				t.Errorf("expected the passphrase of the backup, got %q", got)
+			}
+			body, _ := io.ReadAll(r.Body)
+			if !bytes.Equal(body, newTestBackupFile(t, false)) {
+				t.Error("expected the backup file as the body")
+			}
+			if status != http.StatusOK {
+				writeTestJSON(w, status, types.ErrorResponse{Error: types.APIError{
+					Code: types.ErrorCodeRegistryNotEmpty, Message: "the registry is not empty, it has 1 MCP servers",
+				}})
+				return
+			}
+			writeTestJSON(w, http.StatusOK, types.RestoreResult{
+				Version: "0.3.0", Tables: []types.BackupTable{{Name: "mcp_servers", Rows: 2}},
+			})
+		},
+	})
+	dir := t.TempDir()
+	path := filepath.Join(dir, "backup.mcpj")
+	testhelpers.AssertNoError(t, os.WriteFile(path, newTestBackupFile(t, false), 0o600))
+	orig := restoreCmdInput
+	t.Cleanup(func() { restoreCmdInput = orig })
+
+	restoreCmdInput = path
+	cmd, out := newBackupTestCmd(t)
+	testhelpers.AssertNoError(t, runRestore(cmd, nil))
+	testhelpers.AssertStringContains(t, out.String(), "Backup made by mcpjungle 0.3.0")
+	testhelpers.AssertStringContains(t, out.String(), "Restored 2 rows of 1 tables")
+	testhelpers.AssertStringContains(t, out.String(), "Restart the server")
+
+	status = http.StatusConflict
+	cmd, _ = newBackupTestCmd(t)
+	err := runRestore(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitConflict, ExitCodeForError(err))
+
+	// a file that is not a backup is rejected before it is sent
+	restoreCmdInput = filepath.Join(dir, "notes.txt")
+	testhelpers.AssertNoError(t, os.WriteFile(restoreCmdInput, []byte("not a backup"), 0o600))
+	cmd, _ = newBackupTestCmd(t)
+	err = runRestore(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "not a backup of mcpjungle")
+}
@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// benchEchoTool is the tool of the fake upstream server of bench, it returns the message it is called with.
+const benchEchoTool = "echo"
+
+var (
+	benchCmdTool         string
+	benchCmdInput        string
+	benchCmdConcurrency  int
+	benchCmdDuration     time.Duration
+	benchCmdListRatio    float64
+	benchCmdFakeUpstream bool
+	benchCmdUpstreamAddr string
+	benchCmdClientToken  string
+	benchCmdReport       string
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure the throughput of the MCP gateway",
+	Long: "Call a tool through the MCP proxy of the registry (/mcp) from concurrent MCP clients for a while,\n" +
+		"then report the throughput, the latency percentiles and the errors.\n\n" +
+		"Every worker holds its own MCP session, like an agent does, and calls the tool as fast as it can.\n" +
+		"--list-ratio makes part of the requests tools/list instead of tools/call.\n\n" +
+		"With --fake-upstream, the command serves a built-in echo MCP server and registers it for the duration of the\n" +
+		"benchmark, so that it measures the overhead of the gateway rather than the latency of a real upstream.\n" +
+		"The registry must be able to reach --fake-upstream-addr, and registering the server requires admin access.\n" +
+		"The server is deregistered once the benchmark is done, even if it is interrupted.\n\n" +
+		"In enterprise mode, pass the access token of an MCP client that can access the server with --client-token.",
+	Example: "  mcpjungle bench --tool github__get_me --concurrency 50 --duration 60s\n" +
+		"  mcpjungle bench --fake-upstream --duration 30s --list-ratio 0.1 --report bench.json",
+	Args: cobra.NoArgs,
+	RunE: runBench,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "17",
+	},
+}
+
+func init() {
+	benchCmd.Flags().StringVar(&benchCmdTool, "tool", "", "canonical name of the tool to call, eg- github__get_me")
+	benchCmd.Flags().StringVar(
+		&benchCmdInput, "input", "", "JSON arguments of the tool calls (default {}, or a message for --fake-upstream)",
+	)
+	benchCmd.Flags().IntVar(&benchCmdConcurrency, "concurrency", 50, "number of concurrent MCP clients")
+	benchCmd.Flags().DurationVar(&benchCmdDuration, "duration", time.Minute, "how long to run the benchmark for")
+	benchCmd.Flags().Float64Var(
+		&benchCmdListRatio, "list-ratio", 0, "fraction of the requests that are tools/list, between 0 and 1",
+	)
+	benchCmd.Flags().BoolVar(
+		&benchCmdFakeUpstream, "fake-upstream", false, "register a built-in echo MCP server and benchmark its tool",
+	)
+	benchCmd.Flags().StringVar(
+		&benchCmdUpstreamAddr, "fake-upstream-addr", "127.0.0.1:0", "address the fake upstream server listens on",
+	)
+	benchCmd.Flags().StringVar(
+		&benchCmdClientToken, "client-token", "", "access token of the MCP client to connect as (enterprise mode)",
+	)
+	benchCmd.Flags().StringVar(&benchCmdReport, "report", "", "file to write the report to, as JSON")
+	rootCmd.AddCommand(benchCmd)
+}
+
+// benchLatency are the latency statistics of the requests of a benchmark, in milliseconds.
+type benchLatency struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// benchReport is the outcome of a benchmark.
+type benchReport struct {
+	Tool        string  `json:"tool"`
+	Concurrency int     `json:"concurrency"`
+	ListRatio   float64 `json:"list_ratio"`
+	DurationMs  int64   `json:"duration_ms"`
+
+	Requests   int `json:"requests"`
+	ToolCalls  int `json:"tool_calls"`
+	ToolsLists int `json:"tools_lists"`
+	Errors     int `json:"errors"`
+
+	// Throughput is the number of requests completed per second, failed or not
+	Throughput float64      `json:"throughput_rps"`
+	Latency    benchLatency `json:"latency_ms"`
+	// ErrorCounts counts the failed requests by error message
+	ErrorCounts map[string]int `json:"error_counts,omitempty"`
+}
+
+// benchSample is the outcome of a request of a benchmark.
+type benchSample struct {
+	latency time.Duration
+	list    bool
+	err     string
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	if benchCmdConcurrency <= 0 {
+		return usageErrorf("--concurrency must be a positive number")
+	}
+	if benchCmdDuration <= 0 {
+		return usageErrorf("--duration must be positive")
+	}
+	if benchCmdListRatio < 0 || benchCmdListRatio > 1 {
+		return usageErrorf("--list-ratio must be between 0 and 1")
+	}
+	if benchCmdTool == "" && !benchCmdFakeUpstream {
+		return usageErrorf("either --tool or --fake-upstream must be set")
+	}
+	if benchCmdTool != "" && benchCmdFakeUpstream {
+		return usageErrorf("--tool cannot be used together with --fake-upstream")
+	}
+
+	input := benchCmdInput
+	if input == "" {
+		input = "{}"
+		if benchCmdFakeUpstream {
+			input = `{"message": "hello"}`
+		}
+	}
+	var arguments map[string]any
+	if err := json.Unmarshal([]byte(input), &arguments); err != nil {
+		return usageErrorf("--input must be a JSON object: %v", err)
+	}
+
+	// an interrupted benchmark still reports what it measured and cleans up
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	p := newPrinter(cmd)
+	tool := benchCmdTool
+	if benchCmdFakeUpstream {
+		name, cleanup, err := registerBenchUpstream(ctx, cmd)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		tool = name + "__" + benchEchoTool
+	}
+
+	p.Infof("Benchmarking %s with %d MCP clients for %s...\n", tool, benchCmdConcurrency, benchCmdDuration)
+	report, err := runBenchWorkers(ctx, tool, arguments)
+	if err != nil {
+		return err
+	}
+
+	if isStructuredOutput() {
+		if err := printOutput(cmd, report); err != nil {
+			return err
+		}
+	} else {
+		printBenchReport(cmd, report)
+	}
+	if benchCmdReport != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode the report: %w", err)
+		}
+		if err := os.WriteFile(benchCmdReport, append(data, '\n'), 0o644); err != nil {
+			return fmt.Errorf("failed to write the report to %s: %w", benchCmdReport, err)
+		}
+		p.Infof("Report written to %s\n", benchCmdReport)
+	}
+
+	if report.Requests > 0 && report.Errors == report.Requests {
+		return fmt.Errorf("all the %d requests of the benchmark failed", report.Requests)
+	}
+	return nil
+}
+
+// registerBenchUpstream serves the fake upstream MCP server of the benchmark and registers it.
+// It returns the name the server is registered with, and a function that deregisters and stops it.
+func registerBenchUpstream(ctx context.Context, cmd *cobra.Command) (string, func(), error) {
+	ln, err := net.Listen("tcp", benchCmdUpstreamAddr)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to listen on %s for the fake upstream server: %w", benchCmdUpstreamAddr, err)
+	}
+	httpServer := &http.Server{Handler: server.NewStreamableHTTPServer(newBenchUpstream())}
+	go func() { _ = httpServer.Serve(ln) }()
+	stopServer := func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}
+
+	// the name is unique, so that concurrent benchmarks don't deregister each other's server
+	name := "mcpjungle-bench-" + strconv.Itoa(os.Getpid())
+	input := &types.RegisterServerInput{
+		Name:        name,
+		Transport:   string(types.TransportStreamableHTTP),
+		Description: "Fake upstream server of `mcpjungle bench`, it is deregistered once the benchmark is done",
+		URL:         "http://" + ln.Addr().String() + "/mcp",
+	}
+	if _, err := apiClient.RegisterServerContext(ctx, input); err != nil {
+		stopServer()
+		return "", nil, fmt.Errorf("failed to register the fake upstream server: %w", err)
+	}
+	newPrinter(cmd).Infof("Registered the fake upstream server %s, serving on %s\n", name, ln.Addr())
+
+	cleanup := func() {
+		deregisterCtx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+		defer cancel()
+		if err := apiClient.DeregisterServerContext(deregisterCtx, name); err != nil {
+			newPrinter(cmd).Warnf("failed to deregister the fake upstream server %s, deregister it yourself: %v", name, err)
+		}
+		stopServer()
+	}
+	return name, cleanup, nil
+}
+
+// newBenchUpstream returns the fake upstream MCP server of the benchmark.
+func newBenchUpstream() *server.MCPServer {
+	s := server.NewMCPServer("mcpjungle-bench", "1.0.0", server.WithToolCapabilities(false))
+	s.AddTool(
+		mcp.NewTool(
+			benchEchoTool,
+			mcp.WithDescription("Returns the message it is called with"),
+			mcp.WithString("message", mcp.Description("the message to return")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(req.GetString("message", "")), nil
+		},
+	)
+	return s
+}
+
+// runBenchWorkers connects the MCP clients of the benchmark to the MCP proxy, then has them send requests
+// until the duration of the benchmark elapses or ctx is canceled.
+func runBenchWorkers(ctx context.Context, tool string, arguments map[string]any) (*benchReport, error) {
+	// every worker keeps its connections open, as MCP clients do
+	hc := apiClient.HTTPClient()
+	if t, ok := hc.Transport.(*http.Transport); ok {
+		t = t.Clone()
+		t.MaxIdleConnsPerHost = benchCmdConcurrency
+		hc = &http.Client{Transport: t, Timeout: hc.Timeout}
+	}
+	clients := make([]*mcpclient.Client, benchCmdConcurrency)
+	defer func() {
+		for _, c := range clients {
+			if c != nil {
+				_ = c.Close()
+			}
+		}
+	}()
+	for i := range clients {
+		c, err := apiClient.ConnectMCPProxy(ctx, client.MCPProxyOptions{ClientToken: benchCmdClientToken, HTTPClient: hc})
+		if err != nil {
+			return nil, err
+		}
+		clients[i] = c
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, benchCmdDuration)
+	defer cancel()
+	start := time.Now()
+	samples := make([][]benchSample, len(clients))
+	var wg sync.WaitGroup
+	for i, c := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for runCtx.Err() == nil {
+				s := benchRequest(runCtx, c, tool, arguments)
+				// the request that was interrupted by the end of the benchmark doesn't count
+				if runCtx.Err() != nil {
+					return
+				}
+				samples[i] = append(samples[i], s)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return newBenchReport(tool, time.Since(start), samples), nil
+}
+
+// benchRequest sends a request of the benchmark, either tools/call or tools/list depending on --list-ratio.
+func benchRequest(ctx context.Context, c *mcpclient.Client, tool string, arguments map[string]any) benchSample {
+	s := benchSample{list: benchCmdListRatio > 0 && rand.Float64() < benchCmdListRatio}
+	started := time.Now()
+	var err error
+	if s.list {
+		_, err = c.ListTools(ctx, mcp.ListToolsRequest{})
+	} else {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = tool
+		req.Params.Arguments = arguments
+		var res *mcp.CallToolResult
+		res, err = c.CallTool(ctx, req)
+		if err == nil && res.IsError {
+			err = errors.New("the tool returned an error")
+		}
+	}
+	s.latency = time.Since(started)
+	if err != nil {
+		s.err = err.Error()
+	}
+	return s
+}
+
+// newBenchReport computes the report of a benchmark that ran for elapsed, from the samples of its workers.
+func newBenchReport(tool string, elapsed time.Duration, workerSamples [][]benchSample) *benchReport {
+	r := &benchReport{
+		Tool:        tool,
+		Concurrency: len(workerSamples),
+		ListRatio:   benchCmdListRatio,
+		DurationMs:  elapsed.Milliseconds(),
+	}
+	var latencies []time.Duration
+	var total time.Duration
+	for _, samples := range workerSamples {
+		for _, s := range samples {
+			r.Requests++
+			if s.list {
+				r.ToolsLists++
+			} else {
+				r.ToolCalls++
+			}
+			if s.err != "" {
+				r.Errors++
+				if r.ErrorCounts == nil {
+					r.ErrorCounts = make(map[string]int)
+				}
+				r.ErrorCounts[s.err]++
+			}
+			latencies = append(latencies, s.latency)
+			total += s.latency
+		}
+	}
+	if elapsed > 0 {
+		r.Throughput = float64(r.Requests) / elapsed.Seconds()
+	}
+	if len(latencies) == 0 {
+		return r
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+	percentile := func(p float64) float64 {
+		// nearest-rank percentile
+		i := int(float64(len(latencies))*p+0.999999) - 1
+		return ms(latencies[max(0, min(i, len(latencies)-1))])
+	}
+	r.Latency = benchLatency{
+		Mean: ms(total / time.Duration(len(latencies))),
+		P50:  percentile(0.50),
+		P90:  percentile(0.90),
+		P99:  percentile(0.99),
+		Max:  ms(latencies[len(latencies)-1]),
+	}
+	return r
+}
+
+// printBenchReport prints the report of a benchmark for humans.
+func printBenchReport(cmd *cobra.Command, r *benchReport) {
+	p := newPrinter(cmd)
+	p.Value("Tool", r.Tool)
+	p.Value("Duration", (time.Duration(r.DurationMs) * time.Millisecond).String())
+	p.Value("Requests", fmt.Sprintf("%d (%d tools/call, %d tools/list)", r.Requests, r.ToolCalls, r.ToolsLists))
+	p.Value("Throughput", fmt.Sprintf("%.1f requests/s", r.Throughput))
+	p.Value("Latency", fmt.Sprintf(
+		"mean %.2fms, p50 %.2fms, p90 %.2fms, p99 %.2fms, max %.2fms",
+		r.Latency.Mean, r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.Max,
+	))
+	p.Value("Errors", strconv.Itoa(r.Errors))
+
+	messages := make([]string, 0, len(r.ErrorCounts))
+	for m := range r.ErrorCounts {
+		messages = append(messages, m)
+	}
+	// the most frequent errors first
+	sort.Slice(messages, func(i, j int) bool {
+		if r.ErrorCounts[messages[i]] != r.ErrorCounts[messages[j]] {
+			return r.ErrorCounts[messages[i]] > r.ErrorCounts[messages[j]]
+		}
+		return messages[i] < messages[j]
+	})
+	for _, m := range messages {
+		p.Resultf("  %6d  %s\n", r.ErrorCounts[m], m)
+	}
+}
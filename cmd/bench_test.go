@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestNewBenchReport(t *testing.T) {
+	var samples []benchSample
+	for i := 1; i <= 100; i++ {
+		samples = append(samples, benchSample{latency: time.Duration(i) * time.Millisecond})
+	}
+	samples[0].err = "connection refused"
+	samples[1].err = "connection refused"
+	samples[2].list = true
+
+	r := newBenchReport("github__get_me", 2*time.Second, [][]benchSample{samples[:50], samples[50:]})
+	testhelpers.AssertEqual(t, 2, r.Concurrency)
+	testhelpers.AssertEqual(t, 100, r.Requests)
+	testhelpers.AssertEqual(t, 99, r.ToolCalls)
+	testhelpers.AssertEqual(t, 1, r.ToolsLists)
+	testhelpers.AssertEqual(t, 2, r.Errors)
+	testhelpers.AssertEqual(t, 2, r.ErrorCounts["connection refused"])
+	testhelpers.AssertEqual(t, 50.0, r.Throughput)
+	testhelpers.AssertEqual(t, benchLatency{Mean: 50.5, P50: 50, P90: 90, P99: 99, Max: 100}, r.Latency)
+
+	empty := newBenchReport("github__get_me", time.Second, [][]benchSample{nil})
+	testhelpers.AssertEqual(t, 0, empty.Requests)
+	testhelpers.AssertEqual(t, benchLatency{}, empty.Latency)
+}
+
+func TestRunBenchWithFakeUpstream(t *testing.T) {
+	name := "mcpjungle-bench-" + strconv.Itoa(os.Getpid())
+	// the fake registry serves the tool of the fake upstream on its MCP proxy
+	proxy := server.NewMCPServer("proxy", "0.0.0")
+	proxy.AddTool(mcp.NewTool(name+"__echo"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(req.GetString("message", "")), nil
+	})
+	var registered *types.RegisterServerInput
+	var deregistered string
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"/mcp": server.NewStreamableHTTPServer(proxy).ServeHTTP,
+		"/api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			registered = &types.RegisterServerInput{}
+			_ = json.NewDecoder(r.Body).Decode(registered)
+			writeTestJSON(w, http.StatusCreated, types.McpServer{Name: registered.Name})
+		},
+		"/api/v1/servers/": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodDelete {
+				deregistered = strings.TrimPrefix(r.URL.Path, "/api/v1/servers/")
+			}
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+	report := filepath.Join(t.TempDir(), "bench.json")
+	benchCmdFakeUpstream, benchCmdConcurrency, benchCmdDuration = true, 2, 200*time.Millisecond
+	benchCmdListRatio, benchCmdReport = 0.5, report
+	t.Cleanup(func() {
+		benchCmdFakeUpstream, benchCmdConcurrency, benchCmdDuration = false, 50, time.Minute
+		benchCmdListRatio, benchCmdReport = 0, ""
+	})
+
+	testhelpers.AssertNoError(t, runBench(newExitCodeTestCmd(), nil))
+	testhelpers.AssertNotNil(t, registered)
+	testhelpers.AssertEqual(t, name, registered.Name)
+	testhelpers.AssertStringContains(t, registered.URL, "http://127.0.0.1:")
+	testhelpers.AssertEqual(t, name, deregistered)
+
+	data, err := os.ReadFile(report)
+	testhelpers.AssertNoError(t, err)
+	var r benchReport
+	testhelpers.AssertNoError(t, json.Unmarshal(data, &r))
+	testhelpers.AssertEqual(t, name+"__echo", r.Tool)
+	testhelpers.AssertTrue(t, r.ToolCalls > 0 && r.ToolsLists > 0, "both tool calls and tools lists should be sent")
+	testhelpers.AssertEqual(t, 0, r.Errors)
+	testhelpers.AssertTrue(t, r.Latency.Max > 0, "the latency should be measured")
+
+	// the tool to call or --fake-upstream must be given, not both
+	benchCmdFakeUpstream = false
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(runBench(newExitCodeTestCmd(), nil)))
+	benchCmdTool, benchCmdFakeUpstream = "github__get_me", true
+	t.Cleanup(func() { benchCmdTool = "" })
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(runBench(newExitCodeTestCmd(), nil)))
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// maxBundleFileBytes bounds the size of a configuration file read from a bundle.
+const maxBundleFileBytes = 10 << 20
+
+// configFormatJSON and configFormatYAML are the formats the configuration files of an export can be written in.
+const (
+	configFormatJSON = "json"
+	configFormatYAML = "yaml"
+)
+
+// exportBundle is the content of an export: the configurations of the tool groups and MCP servers.
+// It is also the document written by an export to stdout, with the servers and groups arrays.
+type exportBundle struct {
+	Servers []*types.RegisterServerInput `json:"servers"`
+	Groups  []types.ToolGroup            `json:"groups"`
+}
+
+// marshalConfig serializes the configuration of an entity like it is written in an export.
+func marshalConfig(entity any) ([]byte, error) {
+	return json.MarshalIndent(entity, "", "  ")
+}
+
+// marshalConfigAs serializes the configuration of an entity in the given format, like it is written in an export,
+// in its canonical form, see canonicalConfig.
+// The YAML has the same field names as the JSON returned by the API, so that it can be registered again.
+func marshalConfigAs(format string, entity any) ([]byte, error) {
+	entity = canonicalConfig(entity)
+	if format == configFormatYAML {
+		data, err := marshalEditableYAML(entity)
+		return []byte(data), err
+	}
+	return marshalConfig(entity)
+}
+
+// printConfigAs prints the configuration of an entity, or a bundle, in the given format as the result of a command.
+func printConfigAs(p *printer, format string, v any) error {
+	data, err := marshalConfigAs(format, v)
+	if err != nil {
+		return err
+	}
+	if format == configFormatYAML {
+		// the YAML already ends with a newline
+		p.Resultf("%s", data)
+	} else {
+		p.Resultln(string(data))
+	}
+	return nil
+}
+
+// isConfigFile reports whether the file with the given name is a JSON or YAML configuration file.
+func isConfigFile(name string) bool {
+	switch path.Ext(name) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// writeDirArchive writes the files of the export in dir to a gzip compressed tar archive, with their paths relative
+// to dir, so that extracting the archive gives the same files as the export to a directory.
+func writeDirArchive(w io.Writer, dir string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || name == dir {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// readBundleArchive reads the bundle of an archive written by writeDirArchive.
+// The files outside of the groups and servers directories are ignored, eg- the manifest and the users.
+func readBundleArchive(r io.Reader) (*exportBundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("the bundle is not a gzip compressed archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	b := &exportBundle{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !isConfigFile(hdr.Name) {
+			continue
+		}
+		dir := path.Dir(path.Clean(hdr.Name))
+		if dir != exportToolGroupsDir && dir != exportMcpServersDir {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxBundleFileBytes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from the bundle: %w", hdr.Name, err)
+		}
+		if err := b.add(dir, hdr.Name, data); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// readBundleDir reads the configurations of a directory with the layout of an export, eg- .mcpjungle,
+// in JSON or YAML files. The files outside of the groups and servers directories are ignored,
+// and either of them may be missing, but not both.
+// It fails at the first file that can't be read or decoded, see scanBundleDir to skip them instead.
+func readBundleDir(root string) (*exportBundle, error) {
+	scanned, err := scanBundleDir(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(scanned.invalid) > 0 {
+		return nil, scanned.invalid[0].err
+	}
+	return &scanned.exportBundle, nil
+}
+
+// scannedBundle is an export directory read by scanBundleDir.
+type scannedBundle struct {
+	exportBundle
+	// serverFiles and groupFiles are the paths of the files the servers and the groups were read from, by index,
+	// relative to the directory
+	serverFiles []string
+	groupFiles  []string
+	// invalid are the files that couldn't be read or decoded
+	invalid []invalidBundleFile
+}
+
+// invalidBundleFile is a configuration file of an export directory that couldn't be read or decoded.
+type invalidBundleFile struct {
+	// path is relative to the directory
+	path string
+	err  error
+}
+
+// scanBundleDir reads an export directory like readBundleDir, but the files that can't be read or decoded
+// are recorded, and skipped, instead of failing.
+func scanBundleDir(root string) (*scannedBundle, error) {
+	b := &scannedBundle{}
+	found := false
+	for _, dir := range []string{exportToolGroupsDir, exportMcpServersDir} {
+		files, err := filepath.Glob(filepath.Join(root, dir, "*"))
+		if err != nil {
+			return nil, err
+		}
+		files = slices.DeleteFunc(files, func(f string) bool { return !isConfigFile(f) })
+		if info, err := os.Stat(filepath.Join(root, dir)); err == nil && info.IsDir() {
+			found = true
+		}
+		for _, f := range files {
+			rel := filepath.Join(dir, filepath.Base(f))
+			data, err := os.ReadFile(f)
+			if err == nil {
+				err = b.add(dir, f, data)
+			} else {
+				err = fmt.Errorf("failed to read %s: %w", f, err)
+			}
+			switch {
+			case err != nil:
+				b.invalid = append(b.invalid, invalidBundleFile{path: rel, err: err})
+			case dir == exportToolGroupsDir:
+				b.groupFiles = append(b.groupFiles, rel)
+			default:
+				b.serverFiles = append(b.serverFiles, rel)
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf(
+			"%s is not an export directory, it has neither a %s nor a %s directory",
+			root, exportToolGroupsDir, exportMcpServersDir,
+		)
+	}
+	return b, nil
+}
+
+// files returns the paths of all the configuration files of the directory, valid or not.
+func (b *scannedBundle) files() []string {
+	files := slices.Concat(b.groupFiles, b.serverFiles)
+	for _, f := range b.invalid {
+		files = append(files, f.path)
+	}
+	return files
+}
+
+// add decodes the configuration file with the given name, read from the groups or servers directory of a bundle.
+// A YAML file is decoded with the field names of the JSON configuration.
+func (b *exportBundle) add(dir, name string, data []byte) error {
+	if ext := path.Ext(name); ext == ".yaml" || ext == ".yml" {
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("invalid YAML in %s from the bundle: %w", name, err)
+		}
+		raw, err := json.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("invalid YAML in %s from the bundle: %w", name, err)
+		}
+		data = raw
+	}
+	if dir == exportToolGroupsDir {
+		var g types.ToolGroup
+		if err := json.Unmarshal(data, &g); err != nil {
+			return fmt.Errorf("invalid tool group %s in the bundle: %w", name, err)
+		}
+		b.Groups = append(b.Groups, g)
+		return nil
+	}
+	var s types.RegisterServerInput
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid MCP server %s in the bundle: %w", name, err)
+	}
+	b.Servers = append(b.Servers, &s)
+	return nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// canonicalConfig returns the configuration of an entity, or a bundle of them, in its canonical form, so that
+// serializing the same configuration always gives the same bytes, whatever order the API returned it in:
+// the lists whose order doesn't matter are sorted, eg- the tools of a group, the embedded JSON schemas have
+// their keys sorted, and the empty lists and maps are serialized like unset ones, ie- omitted if they are
+// optional. The maps are sorted by encoding/json already.
+// The entity itself is left untouched. Entities of other types are returned as they are.
+func canonicalConfig(entity any) any {
+	switch e := entity.(type) {
+	case *exportBundle:
+		b := &exportBundle{Groups: e.Groups, Servers: e.Servers}
+		if e.Groups != nil {
+			b.Groups = make([]types.ToolGroup, len(e.Groups))
+			for i, g := range e.Groups {
+				b.Groups[i] = canonicalToolGroup(g)
+			}
+		}
+		if e.Servers != nil {
+			b.Servers = make([]*types.RegisterServerInput, len(e.Servers))
+			for i, s := range e.Servers {
+				b.Servers[i] = canonicalServer(s)
+			}
+		}
+		return b
+	case types.ToolGroup:
+		return canonicalToolGroup(e)
+	case *types.ToolGroup:
+		g := canonicalToolGroup(*e)
+		return &g
+	case *types.RegisterServerInput:
+		return canonicalServer(e)
+	case types.McpClient:
+		e.AllowList = sortedStrings(e.AllowList)
+		return e
+	}
+	return entity
+}
+
+// canonicalToolGroup sorts the tools and servers of a group, which select the same tools in any order.
+func canonicalToolGroup(g types.ToolGroup) types.ToolGroup {
+	g.IncludedTools = sortedStrings(g.IncludedTools)
+	g.IncludedServers = sortedStrings(g.IncludedServers)
+	g.ExcludedTools = sortedStrings(g.ExcludedTools)
+	return g
+}
+
+// canonicalServer returns the canonical form of the configuration of a server. The arguments of its command,
+// and the ports and volumes of its container, are kept in their order, which matters.
+func canonicalServer(s *types.RegisterServerInput) *types.RegisterServerInput {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	if len(c.Args) == 0 {
+		c.Args = nil
+	}
+	if len(c.Env) == 0 {
+		c.Env = nil
+	}
+	if s.OpenAPI != nil {
+		openAPI := *s.OpenAPI
+		// the operations are the tools of the server, served under their names in any order
+		openAPI.Operations = slices.Clone(openAPI.Operations)
+		if len(openAPI.Operations) == 0 {
+			openAPI.Operations = nil
+		}
+		for i := range openAPI.Operations {
+			openAPI.Operations[i].InputSchema = canonicalJSON(openAPI.Operations[i].InputSchema)
+		}
+		slices.SortStableFunc(openAPI.Operations, func(a, b types.OpenAPIOperation) int {
+			return strings.Compare(a.Tool, b.Tool)
+		})
+		c.OpenAPI = &openAPI
+	}
+	if s.WebhookTool != nil {
+		webhook := *s.WebhookTool
+		if len(webhook.Headers) == 0 {
+			webhook.Headers = nil
+		}
+		webhook.InputSchema = canonicalJSON(webhook.InputSchema)
+		c.WebhookTool = &webhook
+	}
+	return &c
+}
+
+// sortedStrings returns a sorted copy of the list, nil if it is empty so that an empty list is serialized like
+// an unset one.
+func sortedStrings(list []string) []string {
+	if len(list) == 0 {
+		return nil
+	}
+	return slices.Sorted(slices.Values(list))
+}
+
+// canonicalJSON returns the JSON document with the keys of its objects sorted, encoding/json keeps the ones of
+// a json.RawMessage in their order. A document that isn't valid JSON is returned as it is.
+func canonicalJSON(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return raw
+	}
+	// the numbers are kept as they are written, rather than as float64
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return raw
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return raw
+	}
+	return data
+}
+
+// marshalConfigFile serializes the configuration of an entity in the given format, in its canonical form, like it is
+// written to the file of an export. The file always ends with a newline.
+func marshalConfigFile(format string, entity any) ([]byte, error) {
+	data, err := marshalConfigAs(format, entity)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	return data, nil
+}
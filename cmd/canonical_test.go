@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// canonicalTestFixtures returns the same configurations with their lists, and the keys of their schemas,
+// in the order given by reversed.
+func canonicalTestFixtures(reversed bool) ([]types.ToolGroup, []*types.RegisterServerInput) {
+	order := func(list []string) []string {
+		list = slices.Clone(list)
+		if reversed {
+			slices.Reverse(list)
+		}
+		return list
+	}
+	schema := json.RawMessage(`{"type": "object", "properties": {"id": {"type": "integer"}}}`)
+	if reversed {
+		schema = json.RawMessage(`{"properties": {"id": {"type": "integer"}}, "type": "object"}`)
+	}
+	operations := []types.OpenAPIOperation{
+		{Tool: "get_pet", Method: "GET", Path: "/pets/{id}", InputSchema: schema},
+		{Tool: "add_pet", Method: "POST", Path: "/pets", InputSchema: json.RawMessage(`{"type": "object"}`)},
+	}
+	if reversed {
+		slices.Reverse(operations)
+	}
+	groups := []types.ToolGroup{{
+		Name:            "ops",
+		IncludedTools:   order([]string{"github__get_issue", "jira__create_issue", "github__create_pr"}),
+		IncludedServers: order([]string{"time", "filesystem"}),
+		ExcludedTools:   order([]string{"filesystem__write_file", "filesystem__delete_file"}),
+	}}
+	servers := []*types.RegisterServerInput{
+		{Name: "filesystem", Transport: "stdio", Command: "npx", Args: []string{"-y", "fs-mcp", "/data"}, Env: map[string]string{}},
+		{
+			Name: "petstore", Transport: "openapi",
+			OpenAPI: &types.OpenAPIConfig{BaseURL: "https://petstore.example.com", Operations: operations},
+		},
+		{
+			Name: "deploy", Transport: "webhook_tool",
+			WebhookTool: &types.WebhookToolConfig{
+				URL: "https://automation.example.com/hooks/deploy", Method: "POST",
+				Headers: map[string]string{"X-Source": "mcpjungle", "X-Env": "prod"}, InputSchema: schema,
+			},
+		},
+	}
+	return groups, servers
+}
+
+func TestCanonicalExport(t *testing.T) {
+	reversed := false
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			groups, _ := canonicalTestFixtures(reversed)
+			writeTestJSON(w, http.StatusOK, groups)
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			_, servers := canonicalTestFixtures(reversed)
+			writeTestJSON(w, http.StatusOK, servers)
+		},
+	})
+	origNow := nowFunc
+	t.Cleanup(func() { nowFunc = origNow })
+	nowFunc = func() time.Time { return time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC) }
+	origDir, origFormat := exportCmdTargetDir, exportCmdFormat
+	t.Cleanup(func() { exportCmdTargetDir, exportCmdFormat = origDir, origFormat })
+
+	for _, format := range []string{exportFormatJSON, exportFormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			export := func(rev bool) string {
+				t.Helper()
+				reversed = rev
+				exportCmdTargetDir, exportCmdFormat = filepath.Join(t.TempDir(), "export"), format
+				cmd := &cobra.Command{}
+				cmd.SetOut(io.Discard)
+				cmd.SetErr(io.Discard)
+				testhelpers.AssertNoError(t, runExport(cmd, nil))
+				return exportCmdTargetDir
+			}
+			first, second := export(false), export(true)
+
+			// the two exports of the same configurations are byte-identical, manifest included
+			var files []string
+			testhelpers.AssertNoError(t, filepath.WalkDir(first, func(path string, d os.DirEntry, err error) error {
+				if err == nil && !d.IsDir() {
+					rel, _ := filepath.Rel(first, path)
+					files = append(files, rel)
+				}
+				return err
+			}))
+			testhelpers.AssertEqual(t, 5, len(files))
+			for _, f := range files {
+				a, err := os.ReadFile(filepath.Join(first, f))
+				testhelpers.AssertNoError(t, err)
+				b, err := os.ReadFile(filepath.Join(second, f))
+				testhelpers.AssertNoError(t, err)
+				testhelpers.AssertEqual(t, string(a), string(b))
+				testhelpers.AssertTrue(t, strings.HasSuffix(string(a), "\n"), f+" should end with a newline")
+			}
+
+			// the lists are sorted, but for the arguments of the command, whose order matters
+			bundle, err := readBundleDir(first)
+			testhelpers.AssertNoError(t, err)
+			testhelpers.AssertEqual(t, "github__create_pr,github__get_issue,jira__create_issue", strings.Join(bundle.Groups[0].IncludedTools, ","))
+			i := slices.IndexFunc(bundle.Servers, func(s *types.RegisterServerInput) bool { return s.Name == "filesystem" })
+			testhelpers.AssertEqual(t, "-y,fs-mcp,/data", strings.Join(bundle.Servers[i].Args, ","))
+		})
+	}
+}
+
+func TestCanonicalConfigLeavesEntityUntouched(t *testing.T) {
+	groups, servers := canonicalTestFixtures(true)
+	_ = canonicalConfig(&exportBundle{Groups: groups, Servers: servers})
+	_ = canonicalConfig(servers[1])
+	// the configurations the export fetched keep their order
+	testhelpers.AssertEqual(t, "github__create_pr", groups[0].IncludedTools[0])
+	testhelpers.AssertEqual(t, "add_pet", servers[1].OpenAPI.Operations[0].Tool)
+	testhelpers.AssertStringContains(t, string(servers[1].OpenAPI.Operations[1].InputSchema), `{"properties"`)
+}
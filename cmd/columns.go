@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/spf13/cobra"
+)
+
+// columnsHelp is the special value of the --columns flag that prints the valid column names.
+const columnsHelp = "help"
+
+// listColumnsFlag is set by the --columns flag of the list commands.
+var listColumnsFlag string
+
+// listSaveColumnsFlag is set by the --save-columns flag of the list commands.
+var listSaveColumnsFlag bool
+
+// tableColumn is a column that can be selected for table output using --columns.
+type tableColumn[T any] struct {
+	name  string
+	value func(T) string
+}
+
+// tableSpec describes all the columns available for a command's table output.
+type tableSpec[T any] struct {
+	// command is the key under which the user's default column selection is stored in the config file,
+	// eg- "list tools"
+	command string
+	columns []tableColumn[T]
+}
+
+// names returns the names of all available columns in their default order.
+func (s tableSpec[T]) names() []string {
+	names := make([]string, 0, len(s.columns))
+	for _, c := range s.columns {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// selectColumns returns the columns with the given names, in the given order.
+// An error listing the valid column names is returned if any name is unknown.
+func (s tableSpec[T]) selectColumns(names []string) ([]tableColumn[T], error) {
+	selected := make([]tableColumn[T], 0, len(names))
+	for _, n := range names {
+		found := false
+		for _, c := range s.columns {
+			if c.name == n {
+				selected = append(selected, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, usageErrorf(
+				"unknown column '%s' for `%s`, valid columns are: %s", n, s.command, strings.Join(s.names(), ","),
+			)
+		}
+	}
+	return selected, nil
+}
+
+// parseColumnList splits a comma-separated list of column names, ignoring whitespace and empty entries.
+func parseColumnList(s string) []string {
+	var names []string
+	for _, n := range strings.Split(s, ",") {
+		n = strings.ToLower(strings.TrimSpace(n))
+		if n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// addColumnsFlags adds the --columns and --save-columns flags to a command that supports table output.
+func addColumnsFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(
+		&listColumnsFlag,
+		"columns",
+		"",
+		"Comma-separated list of columns to display as a table, eg- name,enabled. Use 'help' to list valid columns",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&listSaveColumnsFlag,
+		"save-columns",
+		false,
+		"Save the columns passed via --columns as the default for this command in the CLI config file",
+	)
+}
+
+// columnsHelpRequested returns true if the user passed `--columns help`.
+// In that case, the valid column names for the command are printed.
+func columnsHelpRequested[T any](cmd *cobra.Command, spec tableSpec[T]) bool {
+	p := newPrinter(cmd)
+	if strings.TrimSpace(strings.ToLower(listColumnsFlag)) != columnsHelp {
+		return false
+	}
+	p.Resultf("Valid columns for `%s`: %s\n", spec.command, strings.Join(spec.names(), ","))
+	return true
+}
+
+// resolveColumns determines which columns the user wants to see.
+// The --columns flag takes precedence over the default saved in the config file.
+// It returns nil if no columns were selected, meaning the command should use its regular output format.
+func resolveColumns[T any](spec tableSpec[T]) ([]tableColumn[T], error) {
+	names := parseColumnList(listColumnsFlag)
+
+	if len(names) > 0 && listSaveColumnsFlag {
+		// validate before saving so that the config file never contains a broken selection
+		if _, err := spec.selectColumns(names); err != nil {
+			return nil, err
+		}
+		if err := saveDefaultColumns(spec.command, names); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(names) == 0 {
+		if f, err := config.LoadFile(); err == nil {
+			names = f.Columns[spec.command]
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return spec.selectColumns(names)
+}
+
+// saveDefaultColumns persists the column selection for a command in the CLI config file.
+func saveDefaultColumns(command string, names []string) error {
+	f, err := config.LoadFile()
+	if err != nil {
+		return err
+	}
+	if f.Columns == nil {
+		f.Columns = make(map[string][]string)
+	}
+	f.Columns[command] = names
+	if err := config.SaveFile(f); err != nil {
+		return fmt.Errorf("failed to save default columns: %w", err)
+	}
+	return nil
+}
+
+// renderTable writes rows as a table with the given columns.
+// Column widths adapt to the widest value in each column.
+func renderTable[T any](w io.Writer, cols []tableColumn[T], rows []T) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	headers := make([]string, 0, len(cols))
+	for _, c := range cols {
+		headers = append(headers, strings.ToUpper(c.name))
+	}
+	_, _ = fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, r := range rows {
+		values := make([]string, 0, len(cols))
+		for _, c := range cols {
+			// tabs and newlines would break the table layout
+			v := strings.NewReplacer("\t", " ", "\n", " ").Replace(c.value(r))
+			values = append(values, v)
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
+
+// renderColumns renders rows as a table if the user selected any columns.
+// It returns true if the table was rendered, in which case the command should not print its regular output.
+func renderColumns[T any](cmd *cobra.Command, spec tableSpec[T], rows []T) (bool, error) {
+	cols, err := resolveColumns(spec)
+	if err != nil {
+		return false, err
+	}
+	if cols == nil {
+		return false, nil
+	}
+	return true, renderTable(cmd.OutOrStdout(), cols, rows)
+}
+
+// enabledLabel returns the label used to display whether an entity is enabled.
+func enabledLabel(enabled bool) string {
+	if enabled {
+		return "ENABLED"
+	}
+	return "DISABLED"
+}
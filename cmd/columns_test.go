@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func withColumnsFlags(t *testing.T, columns string, save bool) {
+	t.Helper()
+	origCols, origSave := listColumnsFlag, listSaveColumnsFlag
+	listColumnsFlag, listSaveColumnsFlag = columns, save
+	t.Cleanup(func() { listColumnsFlag, listSaveColumnsFlag = origCols, origSave })
+}
+
+func TestParseColumnList(t *testing.T) {
+	testhelpers.AssertEqual(t, "name|enabled", strings.Join(parseColumnList(" Name, ,enabled "), "|"))
+	testhelpers.AssertEqual(t, 0, len(parseColumnList("")))
+}
+
+func TestSelectColumns(t *testing.T) {
+	cols, err := toolColumns.selectColumns([]string{"enabled", "name"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 2, len(cols))
+	testhelpers.AssertEqual(t, "enabled", cols[0].name)
+	testhelpers.AssertEqual(t, "name", cols[1].name)
+
+	_, err = toolColumns.selectColumns([]string{"name", "bogus"})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "bogus")
+	testhelpers.AssertStringContains(t, err.Error(), strings.Join(toolColumns.names(), ","))
+}
+
+func TestRenderTable(t *testing.T) {
+	tools := []*types.Tool{
+		{Name: "github__create_issue", Enabled: true, Description: "Create an issue"},
+		{Name: "calc__add", Enabled: false, Description: "Add\ttwo numbers"},
+	}
+	cols, err := toolColumns.selectColumns([]string{"server", "tool", "enabled"})
+	testhelpers.AssertNoError(t, err)
+
+	buf := &bytes.Buffer{}
+	testhelpers.AssertNoError(t, renderTable(buf, cols, tools))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testhelpers.AssertEqual(t, 3, len(lines))
+	testhelpers.AssertEqual(t, "SERVER  TOOL          ENABLED", lines[0])
+	testhelpers.AssertEqual(t, "github  create_issue  ENABLED", lines[1])
+	testhelpers.AssertEqual(t, "calc    add           DISABLED", lines[2])
+}
+
+func TestResolveColumns(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	t.Run("no selection", func(t *testing.T) {
+		withColumnsFlags(t, "", false)
+		cols, err := resolveColumns(toolColumns)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertTrue(t, cols == nil, "expected no columns")
+	})
+
+	t.Run("unknown columns are not saved", func(t *testing.T) {
+		withColumnsFlags(t, "name,nope", true)
+		_, err := resolveColumns(toolColumns)
+		testhelpers.AssertError(t, err)
+
+		f, err := config.LoadFile()
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 0, len(f.Columns))
+	})
+
+	t.Run("saved selection is used as default", func(t *testing.T) {
+		withColumnsFlags(t, "name,description", true)
+		_, err := resolveColumns(toolColumns)
+		testhelpers.AssertNoError(t, err)
+
+		withColumnsFlags(t, "", false)
+		cols, err := resolveColumns(toolColumns)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 2, len(cols))
+		testhelpers.AssertEqual(t, "description", cols[1].name)
+
+		// other commands are not affected
+		serverCols, err := resolveColumns(serverColumns)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertTrue(t, serverCols == nil, "expected no default columns for list servers")
+	})
+}
+
+func TestColumnsHelpRequested(t *testing.T) {
+	withColumnsFlags(t, "help", false)
+
+	cmd := &cobra.Command{}
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+
+	testhelpers.AssertTrue(t, columnsHelpRequested(cmd, userColumns), "expected help to be handled")
+	testhelpers.AssertStringContains(t, buf.String(), "username,role")
+}
+
+func TestListServersWide(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []*types.McpServer{
+				{Name: "github", Transport: "streamable_http", CredentialSource: types.CredentialSourceVault},
+				{
+					Name: "time", Transport: "stdio", CredentialSource: types.CredentialSourceNone,
+					Container: &types.ContainerConfig{Image: "mcp/time"}, ContainerState: types.ContainerStateRunning,
+				},
+			})
+		},
+	})
+	listServersCmdWide = true
+	t.Cleanup(func() { listServersCmdWide = false })
+
+	withColumnsFlags(t, "", false)
+	cmd := &cobra.Command{}
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	testhelpers.AssertNoError(t, runListServers(cmd, nil))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testhelpers.AssertEqual(t, 3, len(lines))
+	testhelpers.AssertStringContains(t, lines[0], "CREDENTIAL_SOURCE")
+	testhelpers.AssertStringContains(t, lines[0], "CONTAINER_STATE")
+	github, timeServer := strings.Fields(lines[1]), strings.Fields(lines[2])
+	testhelpers.AssertEqual(t, "vault", github[len(github)-1])
+	testhelpers.AssertEqual(t, "none", timeServer[len(timeServer)-2])
+	testhelpers.AssertEqual(t, "running", timeServer[len(timeServer)-1])
+	testhelpers.AssertEqual(t, "", listColumnsFlag)
+
+	withColumnsFlags(t, "name", false)
+	testhelpers.AssertError(t, runListServers(cmd, nil))
+}
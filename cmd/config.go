@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the mcpjungle CLI configuration",
+	Long: "Manage the mcpjungle CLI configuration.\n" +
+		"The configuration file can hold multiple named contexts, each pointing to a different registry server.\n" +
+		"Commands use the settings of the current context, which can be overridden using flags and env vars.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "10",
+	},
+}
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Print the effective CLI configuration",
+	Long: "Print the contents of the CLI configuration file along with the effective settings used by commands.\n" +
+		"Secrets like access tokens are masked.",
+	Args: cobra.NoArgs,
+	RunE: runConfigView,
+}
+
+func init() {
+	configCmd.AddCommand(configViewCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// configView is the structure printed by the `config view` command.
+type configView struct {
+	ConfigFile     string           `yaml:"config_file" json:"config_file"`
+	CurrentContext string           `yaml:"current-context" json:"current-context"`
+	Contexts       []config.Context `yaml:"contexts" json:"contexts"`
+	Effective      effectiveView    `yaml:"effective" json:"effective"`
+}
+
+type effectiveView struct {
+	Context     string `yaml:"context" json:"context"`
+	RegistryURL string `yaml:"registry_url" json:"registry_url"`
+	AccessToken string `yaml:"access_token,omitempty" json:"access_token,omitempty"`
+	Output      string `yaml:"output" json:"output"`
+	IndexURL    string `yaml:"index_url" json:"index_url"`
+
+	CACert                string `yaml:"ca_cert,omitempty" json:"ca_cert,omitempty"`
+	ClientCert            string `yaml:"client_cert,omitempty" json:"client_cert,omitempty"`
+	ClientKey             string `yaml:"client_key,omitempty" json:"client_key,omitempty"`
+	InsecureSkipTLSVerify bool   `yaml:"insecure_skip_tls_verify,omitempty" json:"insecure_skip_tls_verify,omitempty"`
+
+	// Sources tells the user where each effective setting was resolved from (flag, env, context or default).
+	Sources map[string]string `yaml:"sources" json:"sources"`
+}
+
+func runConfigView(cmd *cobra.Command, args []string) error {
+	path, err := config.FilePath()
+	if err != nil {
+		return fmt.Errorf("failed to get client configuration path: %w", err)
+	}
+	f, err := config.LoadFile()
+	if err != nil {
+		return err
+	}
+
+	v := configView{
+		ConfigFile:     path,
+		CurrentContext: f.CurrentContext,
+		Contexts:       make([]config.Context, 0, len(f.Contexts)),
+	}
+	for _, c := range f.Contexts {
+		c.AccessToken = config.MaskSecret(c.AccessToken)
+		v.Contexts = append(v.Contexts, c)
+	}
+
+	if activeSettings != nil {
+		v.Effective = effectiveView{
+			Context:     activeSettings.ContextName,
+			RegistryURL: activeSettings.RegistryURL,
+			AccessToken: config.MaskSecret(activeSettings.AccessToken),
+			Output:      activeSettings.Output,
+			IndexURL:    activeSettings.IndexURL,
+
+			CACert:                activeSettings.TLS.CACertFile,
+			ClientCert:            activeSettings.TLS.ClientCertFile,
+			ClientKey:             activeSettings.TLS.ClientKeyFile,
+			InsecureSkipTLSVerify: activeSettings.TLS.InsecureSkipVerify,
+			Sources: map[string]string{
+				"registry_url": activeSettings.RegistryURLSource,
+				"output":       activeSettings.OutputSource,
+				"index_url":    activeSettings.IndexURLSource,
+			},
+		}
+		if activeSettings.AccessTokenSource != "" {
+			v.Effective.Sources["access_token"] = activeSettings.AccessTokenSource
+		}
+	}
+
+	if isStructuredOutput() {
+		return printOutput(cmd, v)
+	}
+
+	enc := yaml.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to serialize configuration: %w", err)
+	}
+	return enc.Close()
+}
@@ -8,9 +8,12 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// ClientConfigFileName is the name of the legacy single-registry client config file.
+// Newer versions of the CLI store their configuration in ConfigDirName/ConfigFileName instead (see LoadFile),
+// but this file is still read as a fallback so that existing setups keep working.
 const ClientConfigFileName = ".mcpjungle.conf"
 
-// ClientConfig represents the MCPJungle client configuration stored in the user's home directory.
+// ClientConfig represents the legacy MCPJungle client configuration stored in the user's home directory.
 // It can contain configuration for both a standard user and an admin user.
 type ClientConfig struct {
 	// RegistryURL is the URL of the MCPJungle server.
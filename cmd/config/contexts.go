@@ -0,0 +1,218 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ConfigDirName is the name of the directory in the user's home directory that holds the CLI configuration.
+	ConfigDirName = ".mcpjungle"
+	// ConfigFileName is the name of the CLI configuration file inside ConfigDirName.
+	ConfigFileName = "config.yaml"
+
+	// DefaultContextName is the name of the context created when the CLI has no contexts yet,
+	// for eg- when migrating from the legacy single-registry config file.
+	DefaultContextName = "default"
+)
+
+// Context holds the connection settings for a single MCPJungle registry.
+type Context struct {
+	// Name uniquely identifies the context within the config file.
+	Name string `yaml:"name" json:"name"`
+	// RegistryURL is the base URL of the MCPJungle registry server.
+	RegistryURL string `yaml:"registry_url,omitempty" json:"registry_url,omitempty"`
+
+	// AccessToken is the access token used to authenticate with the registry, stored inline.
+	AccessToken string `yaml:"access_token,omitempty" json:"access_token,omitempty"`
+	// AccessTokenEnv is the name of an environment variable to read the access token from.
+	// Use this instead of AccessToken to avoid storing secrets in the config file.
+	AccessTokenEnv string `yaml:"access_token_env,omitempty" json:"access_token_env,omitempty"`
+	// AccessTokenFile is the path to a file containing the access token.
+	AccessTokenFile string `yaml:"access_token_file,omitempty" json:"access_token_file,omitempty"`
+
+	// Output is the default output format for commands run against this context (eg- "table", "json").
+	Output string `yaml:"output,omitempty" json:"output,omitempty"`
+
+	// CACert is the path to a PEM file with the CA certificates to trust when connecting to the registry,
+	// for registries whose certificate is signed by a private CA.
+	CACert string `yaml:"ca_cert,omitempty" json:"ca_cert,omitempty"`
+	// ClientCert and ClientKey are the paths to the certificate and key presented to registries that require mutual TLS.
+	ClientCert string `yaml:"client_cert,omitempty" json:"client_cert,omitempty"`
+	ClientKey  string `yaml:"client_key,omitempty" json:"client_key,omitempty"`
+	// InsecureSkipTLSVerify disables the verification of the registry's certificate. Only use it for testing.
+	InsecureSkipTLSVerify bool `yaml:"insecure_skip_tls_verify,omitempty" json:"insecure_skip_tls_verify,omitempty"`
+
+	// IndexURL is the base URL of the index of MCP servers searched by `search registry`,
+	// for eg- a private registry of the organization. The public MCP registry is used if it is empty.
+	IndexURL string `yaml:"index_url,omitempty" json:"index_url,omitempty"`
+}
+
+// TLSFiles returns the paths of the CA certificate, client certificate and client key of this context,
+// with a leading ~ expanded to the user's home directory.
+func (c *Context) TLSFiles() (caCert, clientCert, clientKey string) {
+	return ExpandHome(c.CACert), ExpandHome(c.ClientCert), ExpandHome(c.ClientKey)
+}
+
+// ResolveAccessToken returns the access token for this context.
+// An inline token takes precedence over the env var reference, which takes precedence over the file reference.
+// An empty string is returned if the context has no credentials configured.
+func (c *Context) ResolveAccessToken() (string, error) {
+	if c.AccessToken != "" {
+		return c.AccessToken, nil
+	}
+	if c.AccessTokenEnv != "" {
+		return os.Getenv(c.AccessTokenEnv), nil
+	}
+	if c.AccessTokenFile != "" {
+		data, err := os.ReadFile(ExpandHome(c.AccessTokenFile))
+		if err != nil {
+			return "", fmt.Errorf("failed to read access token file for context '%s': %w", c.Name, err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return "", nil
+}
+
+// File represents the CLI configuration file, which can hold multiple named contexts.
+type File struct {
+	// CurrentContext is the name of the context used when none is explicitly selected.
+	CurrentContext string `yaml:"current-context" json:"current-context"`
+	// Contexts is the list of all registry contexts known to the CLI.
+	Contexts []Context `yaml:"contexts" json:"contexts"`
+	// Columns holds the user's default column selection for table output, keyed by command (eg- "list tools").
+	Columns map[string][]string `yaml:"columns,omitempty" json:"columns,omitempty"`
+}
+
+// GetContext returns the context with the given name, or nil if it does not exist.
+func (f *File) GetContext(name string) *Context {
+	for i := range f.Contexts {
+		if f.Contexts[i].Name == name {
+			return &f.Contexts[i]
+		}
+	}
+	return nil
+}
+
+// Current returns the current context, or nil if current-context is unset or refers to a missing context.
+func (f *File) Current() *Context {
+	if f.CurrentContext == "" {
+		return nil
+	}
+	return f.GetContext(f.CurrentContext)
+}
+
+// SetContext adds the given context to the file, replacing any existing context with the same name.
+func (f *File) SetContext(c Context) {
+	if existing := f.GetContext(c.Name); existing != nil {
+		*existing = c
+		return
+	}
+	f.Contexts = append(f.Contexts, c)
+}
+
+// FilePath returns the absolute path to the CLI configuration file.
+// The path is returned regardless of whether the file actually exists there or not.
+func FilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ConfigDirName, ConfigFileName), nil
+}
+
+// LoadFile loads the CLI configuration file.
+// If the file does not exist, it falls back to the legacy config file (see Load) and
+// converts its contents into a single context named DefaultContextName.
+// If neither exists, an empty File is returned.
+func LoadFile() (*File, error) {
+	path, err := FilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fromLegacy(Load()), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	f := &File{}
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// SaveFile writes the CLI configuration file, creating its parent directory if needed.
+// The file is only readable by the current user since it may contain access tokens.
+func SaveFile(f *File) error {
+	path, err := FilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(f); err != nil {
+		return fmt.Errorf("failed to serialize config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to serialize config: %w", err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+// MaskSecret masks a secret value so that it can be safely displayed.
+// Only the last 4 characters of long secrets are revealed.
+func MaskSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	if len(s) < 12 {
+		return "****"
+	}
+	return "****" + s[len(s)-4:]
+}
+
+// fromLegacy converts the legacy single-registry config into a File with one default context.
+func fromLegacy(legacy *ClientConfig) *File {
+	f := &File{}
+	if legacy == nil || (legacy.RegistryURL == "" && legacy.AccessToken == "") {
+		return f
+	}
+	f.CurrentContext = DefaultContextName
+	f.Contexts = []Context{
+		{
+			Name:        DefaultContextName,
+			RegistryURL: legacy.RegistryURL,
+			AccessToken: legacy.AccessToken,
+		},
+	}
+	return f
+}
+
+// ExpandHome replaces the leading ~ of a path with the home directory of the user, eg- ~/certs/ca.pem.
+// The ~ must be followed by a slash or, on Windows, a backslash. Other paths are returned as is.
+func ExpandHome(p string) string {
+	if p != "~" && !strings.HasPrefix(p, "~/") && !strings.HasPrefix(p, "~"+string(filepath.Separator)) {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, p[1:])
+}
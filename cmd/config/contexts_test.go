@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func TestLoadFile(t *testing.T) {
+	t.Run("returns empty file when no config exists", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+
+		f, err := LoadFile()
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "", f.CurrentContext)
+		testhelpers.AssertEqual(t, 0, len(f.Contexts))
+		testhelpers.AssertTrue(t, f.Current() == nil, "expected no current context")
+	})
+
+	t.Run("falls back to legacy config", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		testhelpers.AssertNoError(t, Save(&ClientConfig{RegistryURL: "http://legacy:8080", AccessToken: "legacy-token"}))
+
+		f, err := LoadFile()
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, DefaultContextName, f.CurrentContext)
+
+		c := f.Current()
+		testhelpers.AssertNotNil(t, c)
+		testhelpers.AssertEqual(t, "http://legacy:8080", c.RegistryURL)
+		testhelpers.AssertEqual(t, "legacy-token", c.AccessToken)
+	})
+
+	t.Run("save and load round trip", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+
+		f := &File{CurrentContext: "remote"}
+		f.SetContext(Context{Name: "local", RegistryURL: "http://127.0.0.1:8080"})
+		f.SetContext(Context{Name: "remote", RegistryURL: "https://jungle.example.com", AccessToken: "tok", Output: "json"})
+		testhelpers.AssertNoError(t, SaveFile(f))
+
+		info, err := os.Stat(filepath.Join(home, ConfigDirName, ConfigFileName))
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, os.FileMode(0o600), info.Mode().Perm())
+
+		loaded, err := LoadFile()
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 2, len(loaded.Contexts))
+		testhelpers.AssertEqual(t, "https://jungle.example.com", loaded.Current().RegistryURL)
+		testhelpers.AssertEqual(t, "json", loaded.Current().Output)
+	})
+
+	t.Run("invalid yaml returns error", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		testhelpers.AssertNoError(t, os.MkdirAll(filepath.Join(home, ConfigDirName), 0o700))
+		testhelpers.AssertNoError(t, os.WriteFile(
+			filepath.Join(home, ConfigDirName, ConfigFileName), []byte("contexts: ["), 0o600,
+		))
+
+		_, err := LoadFile()
+		testhelpers.AssertError(t, err)
+	})
+}
+
+func TestSetContextReplacesExisting(t *testing.T) {
+	f := &File{}
+	f.SetContext(Context{Name: "a", RegistryURL: "http://one"})
+	f.SetContext(Context{Name: "a", RegistryURL: "http://two"})
+
+	testhelpers.AssertEqual(t, 1, len(f.Contexts))
+	testhelpers.AssertEqual(t, "http://two", f.GetContext("a").RegistryURL)
+}
+
+func TestResolveAccessToken(t *testing.T) {
+	t.Run("inline token", func(t *testing.T) {
+		c := &Context{AccessToken: "inline", AccessTokenEnv: "SHOULD_NOT_BE_USED"}
+		tok, err := c.ResolveAccessToken()
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "inline", tok)
+	})
+
+	t.Run("env reference", func(t *testing.T) {
+		t.Setenv("MCPJUNGLE_TEST_TOKEN", "from-env")
+		c := &Context{AccessTokenEnv: "MCPJUNGLE_TEST_TOKEN"}
+		tok, err := c.ResolveAccessToken()
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "from-env", tok)
+	})
+
+	t.Run("file reference", func(t *testing.T) {
+		p := filepath.Join(t.TempDir(), "token")
+		testhelpers.AssertNoError(t, os.WriteFile(p, []byte("from-file\n"), 0o600))
+		c := &Context{AccessTokenFile: p}
+		tok, err := c.ResolveAccessToken()
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "from-file", tok)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		c := &Context{Name: "x", AccessTokenFile: filepath.Join(t.TempDir(), "missing")}
+		_, err := c.ResolveAccessToken()
+		testhelpers.AssertError(t, err)
+	})
+}
+
+func TestMaskSecret(t *testing.T) {
+	testhelpers.AssertEqual(t, "", MaskSecret(""))
+	testhelpers.AssertEqual(t, "****", MaskSecret("short"))
+	testhelpers.AssertEqual(t, "****cdef", MaskSecret("0123456789abcdef"))
+}
+
+func TestExpandHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	testhelpers.AssertEqual(t, home, ExpandHome("~"))
+	testhelpers.AssertEqual(t, filepath.Join(home, "certs", "ca.pem"), ExpandHome("~/certs/ca.pem"))
+	testhelpers.AssertEqual(t, filepath.Join(home, "certs", "ca.pem"), ExpandHome("~"+string(filepath.Separator)+filepath.Join("certs", "ca.pem")))
+	testhelpers.AssertEqual(t, "~alice/certs", ExpandHome("~alice/certs"))
+	testhelpers.AssertEqual(t, "certs/~/ca.pem", ExpandHome("certs/~/ca.pem"))
+}
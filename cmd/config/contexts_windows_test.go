@@ -0,0 +1,20 @@
+//go:build windows
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func TestExpandHomeWindows(t *testing.T) {
+	home := `C:\Users\alice`
+	t.Setenv("USERPROFILE", home)
+
+	testhelpers.AssertEqual(t, filepath.Join(home, "certs", "ca.pem"), ExpandHome(`~\certs\ca.pem`))
+	testhelpers.AssertEqual(t, filepath.Join(home, "certs", "ca.pem"), ExpandHome(`~/certs\ca.pem`))
+	// paths that are already absolute are left as is
+	testhelpers.AssertEqual(t, `D:\certs\ca.pem`, ExpandHome(`D:\certs\ca.pem`))
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// LocalServerFileName is the name of the file inside ConfigDirName where `mcpjungle start` records
+// the server running on this machine, so that the CLI can find it without any configuration.
+const LocalServerFileName = "server.json"
+
+// LocalServer describes an mcpjungle server running on this machine.
+type LocalServer struct {
+	// URL is the base URL at which the server can be reached from this machine.
+	URL string `json:"url"`
+	// ListenAddress is the address the server listens on, eg- ":8080".
+	ListenAddress string `json:"listen_address"`
+	// PID is the process ID of the server.
+	PID int `json:"pid"`
+	// Mode is the mode the server was started in.
+	Mode string `json:"mode"`
+	// StartedAt is when the server was started.
+	StartedAt time.Time `json:"started_at"`
+}
+
+// LocalServerFilePath returns the absolute path to the local server file.
+// The path is returned regardless of whether the file actually exists there or not.
+func LocalServerFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ConfigDirName, LocalServerFileName), nil
+}
+
+// WriteLocalServer records s as the server running on this machine, replacing any previous record.
+func WriteLocalServer(s *LocalServer) error {
+	path, err := LocalServerFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize local server state: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// RemoveLocalServer deletes the local server file, if it was written by the process with the given PID.
+// A file written by another server started in the meantime is left alone.
+func RemoveLocalServer(pid int) error {
+	s, path, err := readLocalServer()
+	if err != nil || s == nil || s.PID != pid {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// DiscoverLocalServer returns the mcpjungle server running on this machine, as recorded by `mcpjungle start`.
+// nil is returned if no server was recorded, or if its process is not running anymore (the file is stale,
+// eg- because the server crashed).
+func DiscoverLocalServer() (*LocalServer, error) {
+	s, _, err := readLocalServer()
+	if err != nil || s == nil {
+		return nil, err
+	}
+	if s.URL == "" || !processAlive(s.PID) {
+		return nil, nil
+	}
+	return s, nil
+}
+
+func readLocalServer() (*LocalServer, string, error) {
+	path, err := LocalServerFilePath()
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, path, nil
+	}
+	if err != nil {
+		return nil, path, fmt.Errorf("failed to read local server file %s: %w", path, err)
+	}
+	s := &LocalServer{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, path, fmt.Errorf("failed to parse local server file %s: %w", path, err)
+	}
+	return s, path, nil
+}
+
+// processAlive reports whether a process with the given PID is running.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		// on windows, FindProcess fails if there is no such process
+		return true
+	}
+	// signal 0 checks that the process exists without affecting it,
+	// EPERM means it exists but belongs to another user
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+// deadPID is larger than any PID the OS hands out, so no process can have it.
+const deadPID = 0x7ffffff0
+
+func TestDiscoverLocalServer(t *testing.T) {
+	t.Run("no server recorded", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		s, err := DiscoverLocalServer()
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertTrue(t, s == nil, "expected no local server")
+	})
+
+	t.Run("running server", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		testhelpers.AssertNoError(t, WriteLocalServer(&LocalServer{
+			URL: "http://127.0.0.1:9090", ListenAddress: ":9090", PID: os.Getpid(), Mode: "development",
+		}))
+
+		s, err := DiscoverLocalServer()
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertNotNil(t, s)
+		testhelpers.AssertEqual(t, "http://127.0.0.1:9090", s.URL)
+		testhelpers.AssertEqual(t, "development", s.Mode)
+	})
+
+	t.Run("stale file of a server that is not running anymore", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		testhelpers.AssertNoError(t, WriteLocalServer(&LocalServer{URL: "http://127.0.0.1:9090", PID: deadPID}))
+
+		s, err := DiscoverLocalServer()
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertTrue(t, s == nil, "a stale local server file should be ignored")
+	})
+}
+
+func TestRemoveLocalServer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	testhelpers.AssertNoError(t, WriteLocalServer(&LocalServer{URL: "http://127.0.0.1:9090", PID: os.Getpid()}))
+	path, err := LocalServerFilePath()
+	testhelpers.AssertNoError(t, err)
+
+	// the file of another server is left alone
+	testhelpers.AssertNoError(t, RemoveLocalServer(deadPID))
+	_, err = os.Stat(path)
+	testhelpers.AssertNoError(t, err)
+
+	testhelpers.AssertNoError(t, RemoveLocalServer(os.Getpid()))
+	_, err = os.Stat(path)
+	testhelpers.AssertTrue(t, os.IsNotExist(err), "the local server file should be removed")
+
+	// removing a file that doesn't exist is not an error
+	testhelpers.AssertNoError(t, RemoveLocalServer(os.Getpid()))
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// stdinConfigPath is the value of a --conf flag that tells the CLI to read the configuration from stdin.
+const stdinConfigPath = "-"
+
+// errNoConfigProvided is returned when a command expects a configuration on stdin but stdin is empty.
+var errNoConfigProvided = errors.New(
+	"no configuration provided: pass a configuration file with --conf, or pipe the configuration into stdin",
+)
+
+// stdinIsPiped reports whether the CLI's stdin is a pipe or a redirected file, ie, it may contain data.
+// It is a variable so that tests can override it.
+var stdinIsPiped = func() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&(os.ModeNamedPipe|os.ModeCharDevice) == os.ModeNamedPipe || info.Mode().IsRegular()
+}
+
+// configFromStdin reports whether a command should read its configuration from stdin, given the value of its
+// --conf flag: either the user explicitly asked for it with "-", or they omitted the flag and piped data into the CLI.
+func configFromStdin(path string) bool {
+	return path == stdinConfigPath || (path == "" && stdinIsPiped())
+}
+
+// configSourceName returns a human-readable name for where the configuration is read from, for use in messages.
+func configSourceName(path string) string {
+	if configFromStdin(path) {
+		return "stdin"
+	}
+	return path
+}
+
+// requireConfigInput returns an error if a command that needs a configuration got neither a --conf flag nor data on stdin.
+func requireConfigInput(path string) error {
+	if path == "" && !stdinIsPiped() {
+		return usageErrorf("required flag \"conf\" not set")
+	}
+	return nil
+}
+
+// readConfigInput reads a configuration from the file at path, or from stdin (see configFromStdin).
+func readConfigInput(cmd *cobra.Command, path string) ([]byte, error) {
+	if !configFromStdin(path) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+		return data, nil
+	}
+
+	data, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration from stdin: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, errNoConfigProvided
+	}
+	return data, nil
+}
+
+// decodeConfigEntities decodes one or more entities from a JSON or YAML configuration.
+// The format is detected from the first non-whitespace character: '{' or '[' means JSON, anything else is YAML.
+// Multiple entities can be supplied as a JSON array, a stream of JSON objects, a YAML list
+// or multiple YAML documents separated by "---".
+func decodeConfigEntities[T any](data []byte) ([]T, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errNoConfigProvided
+	}
+
+	var docs []any
+	switch trimmed[0] {
+	case '{', '[':
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		for {
+			var doc any
+			if err := dec.Decode(&doc); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, fmt.Errorf("failed to parse JSON configuration: %w", err)
+			}
+			docs = append(docs, doc)
+		}
+	default:
+		dec := yaml.NewDecoder(bytes.NewReader(trimmed))
+		for {
+			var doc any
+			if err := dec.Decode(&doc); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, fmt.Errorf("failed to parse YAML configuration: %w", err)
+			}
+			if doc != nil {
+				docs = append(docs, doc)
+			}
+		}
+	}
+
+	var entities []T
+	for _, doc := range docs {
+		items := []any{doc}
+		if list, ok := doc.([]any); ok {
+			items = list
+		}
+		for _, item := range items {
+			// round-trip through JSON so that the entity's json tags apply to YAML input too
+			raw, err := json.Marshal(item)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse configuration: %w", err)
+			}
+			var e T
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return nil, fmt.Errorf("failed to parse configuration: %w", err)
+			}
+			entities = append(entities, e)
+		}
+	}
+	if len(entities) == 0 {
+		return nil, errNoConfigProvided
+	}
+	return entities, nil
+}
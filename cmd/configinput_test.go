@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func setStdinIsPiped(t *testing.T, piped bool) {
+	t.Helper()
+	orig := stdinIsPiped
+	stdinIsPiped = func() bool { return piped }
+	t.Cleanup(func() { stdinIsPiped = orig })
+}
+
+func TestConfigFromStdin(t *testing.T) {
+	setStdinIsPiped(t, false)
+	testhelpers.AssertTrue(t, configFromStdin("-"), "'-' should always read from stdin")
+	testhelpers.AssertFalse(t, configFromStdin(""), "stdin is a terminal, it must not be read")
+	testhelpers.AssertFalse(t, configFromStdin("server.json"), "a file path must not read from stdin")
+	testhelpers.AssertError(t, requireConfigInput(""))
+
+	setStdinIsPiped(t, true)
+	testhelpers.AssertTrue(t, configFromStdin(""), "piped data should be read when --conf is omitted")
+	testhelpers.AssertFalse(t, configFromStdin("server.json"), "an explicit file path takes precedence over stdin")
+	testhelpers.AssertNoError(t, requireConfigInput(""))
+}
+
+func TestDecodeConfigEntities(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "json object",
+			input: `  {"name": "github", "url": "https://api.githubcopilot.com/mcp/"}`,
+			want:  []string{"github"},
+		},
+		{
+			name:  "json array",
+			input: `[{"name": "github"}, {"name": "calculator"}]`,
+			want:  []string{"github", "calculator"},
+		},
+		{
+			name:  "json stream",
+			input: "{\"name\": \"github\"}\n{\"name\": \"calculator\"}\n",
+			want:  []string{"github", "calculator"},
+		},
+		{
+			name:  "yaml document",
+			input: "name: filesystem\ntransport: stdio\ncommand: npx\nargs: [\"-y\", \"@modelcontextprotocol/server-filesystem\"]\n",
+			want:  []string{"filesystem"},
+		},
+		{
+			name:  "yaml list",
+			input: "- name: github\n- name: calculator\n",
+			want:  []string{"github", "calculator"},
+		},
+		{
+			name:  "multiple yaml documents",
+			input: "---\nname: github\n---\nname: calculator\n---\n",
+			want:  []string{"github", "calculator"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			servers, err := decodeConfigEntities[types.RegisterServerInput]([]byte(tc.input))
+			testhelpers.AssertNoError(t, err)
+			var names []string
+			for _, s := range servers {
+				names = append(names, s.Name)
+			}
+			testhelpers.AssertEqual(t, strings.Join(tc.want, ","), strings.Join(names, ","))
+		})
+	}
+
+	t.Run("yaml uses the json field names", func(t *testing.T) {
+		servers, err := decodeConfigEntities[types.RegisterServerInput]([]byte("name: x\nbearer_token: tok\n"))
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "tok", servers[0].BearerToken)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := decodeConfigEntities[types.RegisterServerInput]([]byte(`{"name": `))
+		testhelpers.AssertError(t, err)
+		testhelpers.AssertStringContains(t, err.Error(), "JSON")
+	})
+}
+
+func TestReadConfigInput(t *testing.T) {
+	setStdinIsPiped(t, true)
+
+	t.Run("reads stdin", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		cmd.SetIn(strings.NewReader(`{"name": "github"}`))
+		groups, err := readToolGroupConfigs(cmd, "-")
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 1, len(groups))
+		testhelpers.AssertEqual(t, "github", groups[0].Name)
+	})
+
+	t.Run("empty stdin", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		cmd.SetIn(strings.NewReader(" \n"))
+		_, err := readMcpServerConfigs(cmd, "")
+		testhelpers.AssertTrue(t, errors.Is(err, errNoConfigProvided), "empty stdin should report a missing configuration")
+	})
+
+	t.Run("reads a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "servers.yaml")
+		testhelpers.AssertNoError(t, os.WriteFile(path, []byte("- name: a\n- name: b\n"), 0o600))
+		servers, err := readMcpServerConfigs(&cobra.Command{}, path)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 2, len(servers))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := readMcpServerConfigs(&cobra.Command{}, filepath.Join(t.TempDir(), "missing.json"))
+		testhelpers.AssertError(t, err)
+		testhelpers.AssertStringContains(t, err.Error(), "failed to read config file")
+	})
+}
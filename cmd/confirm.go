@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// assumeYes is set by the global --yes flag.
+// When true, destructive commands skip the confirmation prompt.
+var assumeYes bool
+
+// ErrConfirmationRequired is returned when a destructive command needs confirmation
+// but the CLI is not attached to an interactive terminal and --yes was not passed.
+var ErrConfirmationRequired = errors.New("confirmation required: stdin is not a terminal, pass --yes to proceed")
+
+// ErrAborted is returned when the user declines a confirmation prompt.
+var ErrAborted = errors.New("operation aborted by user")
+
+// stdinIsTerminal reports whether the CLI's stdin is an interactive terminal.
+// It is a variable so that tests can override it.
+var stdinIsTerminal = func() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// confirmation describes a destructive action that must be confirmed by the user before it is carried out.
+type confirmation struct {
+	// Action is a short description of what is about to happen, eg- "deregister MCP server 'github'"
+	Action string
+	// Impact lists the consequences of the action (the "blast radius"), one item per line.
+	// These are shown to the user before they are asked to confirm.
+	Impact []string
+}
+
+// confirmDestructiveAction is the single entry point all destructive commands use to get the user's consent.
+// It renders the action and its blast radius, then:
+//   - returns nil immediately if --yes was passed
+//   - prompts the user if stdin is a terminal
+//   - refuses with ErrConfirmationRequired otherwise, so that automation never hangs on a prompt
+func confirmDestructiveAction(cmd *cobra.Command, c confirmation) error {
+	if assumeYes {
+		return nil
+	}
+
+	out := cmd.ErrOrStderr()
+	renderConfirmation(out, c)
+
+	if !stdinIsTerminal() {
+		return ErrConfirmationRequired
+	}
+
+	ok, err := promptYesNo(cmd.InOrStdin(), out, "Do you want to continue?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAborted
+	}
+	return nil
+}
+
+// renderConfirmation prints the action and its impact in a consistent format.
+func renderConfirmation(w io.Writer, c confirmation) {
+	_, _ = fmt.Fprintf(w, "You are about to %s.\n", c.Action)
+	if len(c.Impact) > 0 {
+		_, _ = fmt.Fprintln(w, "This will:")
+		for _, line := range c.Impact {
+			_, _ = fmt.Fprintf(w, "  - %s\n", line)
+		}
+	}
+}
+
+// promptYesNo asks a yes/no question and reads the answer from r.
+// Anything other than "y" or "yes" (case-insensitive) is treated as a no.
+func promptYesNo(r io.Reader, w io.Writer, question string) (bool, error) {
+	_, _ = fmt.Fprintf(w, "%s [y/N]: ", question)
+
+	answer, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
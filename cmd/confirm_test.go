@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/spf13/cobra"
+)
+
+// withConfirmState overrides the global confirmation state for the duration of a test.
+func withConfirmState(t *testing.T, yes bool, tty bool) {
+	t.Helper()
+	origYes, origTTY := assumeYes, stdinIsTerminal
+	assumeYes = yes
+	stdinIsTerminal = func() bool { return tty }
+	t.Cleanup(func() {
+		assumeYes, stdinIsTerminal = origYes, origTTY
+	})
+}
+
+func newConfirmTestCmd(input string) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	out := &bytes.Buffer{}
+	cmd.SetIn(strings.NewReader(input))
+	cmd.SetErr(out)
+	cmd.SetOut(out)
+	return cmd, out
+}
+
+func TestConfirmDestructiveAction(t *testing.T) {
+	c := confirmation{
+		Action: "delete tool group 'g1'",
+		Impact: []string{"make the group's endpoint unavailable"},
+	}
+
+	t.Run("--yes skips prompt", func(t *testing.T) {
+		withConfirmState(t, true, false)
+		cmd, out := newConfirmTestCmd("")
+		testhelpers.AssertNoError(t, confirmDestructiveAction(cmd, c))
+		testhelpers.AssertEqual(t, "", out.String())
+	})
+
+	t.Run("non-tty without --yes refuses", func(t *testing.T) {
+		withConfirmState(t, false, false)
+		cmd, out := newConfirmTestCmd("y\n")
+		err := confirmDestructiveAction(cmd, c)
+		testhelpers.AssertTrue(t, errors.Is(err, ErrConfirmationRequired), "expected ErrConfirmationRequired")
+		testhelpers.AssertTrue(t, strings.Contains(err.Error(), "--yes"), "error should tell the user to pass --yes")
+		testhelpers.AssertTrue(t, strings.Contains(out.String(), "make the group's endpoint unavailable"),
+			"blast radius should be rendered")
+	})
+
+	t.Run("tty and user accepts", func(t *testing.T) {
+		withConfirmState(t, false, true)
+		cmd, out := newConfirmTestCmd("yes\n")
+		testhelpers.AssertNoError(t, confirmDestructiveAction(cmd, c))
+		testhelpers.AssertTrue(t, strings.Contains(out.String(), "[y/N]"), "prompt should be shown")
+	})
+
+	t.Run("tty and user declines", func(t *testing.T) {
+		withConfirmState(t, false, true)
+		cmd, _ := newConfirmTestCmd("n\n")
+		err := confirmDestructiveAction(cmd, c)
+		testhelpers.AssertTrue(t, errors.Is(err, ErrAborted), "expected ErrAborted")
+	})
+
+	t.Run("tty and empty input defaults to no", func(t *testing.T) {
+		withConfirmState(t, false, true)
+		cmd, _ := newConfirmTestCmd("")
+		err := confirmDestructiveAction(cmd, c)
+		testhelpers.AssertTrue(t, errors.Is(err, ErrAborted), "expected ErrAborted")
+	})
+}
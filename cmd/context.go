@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/spf13/cobra"
+)
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Manage CLI contexts to switch between registries",
+	Long: "A context holds the connection settings (registry URL, credentials, default output format) " +
+		"for one MCPJungle registry.\n" +
+		"Use contexts to easily switch between multiple registries, eg- a local dev registry and a remote one.\n" +
+		"To use a context for a single command without switching to it, pass the global --context flag.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "11",
+	},
+}
+
+var contextListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all contexts",
+	Long:  "List all contexts configured in the CLI config file. The current context is marked with an asterisk (*).",
+	Args:  cobra.NoArgs,
+	RunE:  runContextList,
+}
+
+var contextUseCmd = &cobra.Command{
+	Use:   "use [name]",
+	Short: "Switch the current context",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runContextUse,
+}
+
+var (
+	contextCreateCmdRegistryURL     string
+	contextCreateCmdAccessTokenEnv  string
+	contextCreateCmdAccessTokenFile string
+	contextCreateCmdOutput          string
+	contextCreateCmdCACert          string
+	contextCreateCmdClientCert      string
+	contextCreateCmdClientKey       string
+	contextCreateCmdInsecure        bool
+	contextCreateCmdIndexURL        string
+	contextCreateCmdUse             bool
+)
+
+var contextCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a new context",
+	Long: "Create a new context pointing to a registry server.\n" +
+		"To store credentials in the context, either reference an env var or a file containing the token,\n" +
+		"or run `mcpjungle login` after switching to the new context.",
+	Args: cobra.ExactArgs(1),
+	RunE: runContextCreate,
+}
+
+var contextDeleteCmdForce bool
+
+var contextDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a context",
+	Long: "Delete a context from the CLI config file.\n" +
+		"Deleting the current context is refused unless --force is passed.",
+	Args: cobra.ExactArgs(1),
+	RunE: runContextDelete,
+}
+
+func init() {
+	contextCreateCmd.Flags().StringVar(
+		&contextCreateCmdRegistryURL,
+		"registry",
+		"",
+		"Base URL of the MCPJungle registry server for this context",
+	)
+	_ = contextCreateCmd.MarkFlagRequired("registry")
+	contextCreateCmd.Flags().StringVar(
+		&contextCreateCmdAccessTokenEnv,
+		"access-token-env",
+		"",
+		"Name of an env var to read the access token from",
+	)
+	contextCreateCmd.Flags().StringVar(
+		&contextCreateCmdAccessTokenFile,
+		"access-token-file",
+		"",
+		"Path to a file containing the access token",
+	)
+	contextCreateCmd.Flags().StringVar(
+		&contextCreateCmdOutput,
+		"default-output",
+		"",
+		"Default output format for this context (table, json, name or template=<go template>)",
+	)
+	contextCreateCmd.Flags().StringVar(
+		&contextCreateCmdCACert,
+		"ca-cert",
+		"",
+		"Path to a PEM file with the CA certificates to trust for the registry's TLS certificate",
+	)
+	contextCreateCmd.Flags().StringVar(
+		&contextCreateCmdClientCert,
+		"client-cert",
+		"",
+		"Path to the PEM encoded client certificate to present to the registry",
+	)
+	contextCreateCmd.Flags().StringVar(
+		&contextCreateCmdClientKey,
+		"client-key",
+		"",
+		"Path to the PEM encoded key of the client certificate",
+	)
+	contextCreateCmd.Flags().BoolVar(
+		&contextCreateCmdInsecure,
+		"insecure-skip-tls-verify",
+		false,
+		"Do not verify the registry's TLS certificate, only use it for testing",
+	)
+	contextCreateCmd.MarkFlagsRequiredTogether("client-cert", "client-key")
+	contextCreateCmd.Flags().StringVar(
+		&contextCreateCmdIndexURL,
+		"index-url",
+		"",
+		"Base URL of the index of MCP servers searched by `search registry` (default: the public MCP registry)",
+	)
+	contextCreateCmd.Flags().BoolVar(
+		&contextCreateCmdUse,
+		"use",
+		false,
+		"Switch to the new context after creating it",
+	)
+
+	contextDeleteCmd.Flags().BoolVar(
+		&contextDeleteCmdForce,
+		"force",
+		false,
+		"Delete the context even if it is the current context",
+	)
+
+	contextCmd.AddCommand(contextListCmd)
+	contextCmd.AddCommand(contextUseCmd)
+	contextCmd.AddCommand(contextCreateCmd)
+	contextCmd.AddCommand(contextDeleteCmd)
+
+	rootCmd.AddCommand(contextCmd)
+}
+
+func runContextList(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+	f, err := config.LoadFile()
+	if err != nil {
+		return err
+	}
+
+	if isStructuredOutput() {
+		type contextListItem struct {
+			Name        string `json:"name"`
+			RegistryURL string `json:"registry_url"`
+			Current     bool   `json:"current"`
+		}
+		items := make([]contextListItem, 0, len(f.Contexts))
+		for _, c := range f.Contexts {
+			items = append(items, contextListItem{
+				Name:        c.Name,
+				RegistryURL: c.RegistryURL,
+				Current:     c.Name == f.CurrentContext,
+			})
+		}
+		return printOutput(cmd, items)
+	}
+
+	if len(f.Contexts) == 0 {
+		p.Infoln("There are no contexts configured. Create one using `mcpjungle context create`")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CURRENT\tNAME\tREGISTRY")
+	for _, c := range f.Contexts {
+		marker := ""
+		if c.Name == f.CurrentContext {
+			marker = "*"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", marker, c.Name, c.RegistryURL)
+	}
+	return w.Flush()
+}
+
+func runContextUse(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+	name := args[0]
+
+	f, err := config.LoadFile()
+	if err != nil {
+		return err
+	}
+	if f.GetContext(name) == nil {
+		return fmt.Errorf("context '%s' does not exist", name)
+	}
+
+	f.CurrentContext = name
+	if err := config.SaveFile(f); err != nil {
+		return err
+	}
+	p.Infof("Switched to context '%s'\n", name)
+	return nil
+}
+
+func runContextCreate(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+	name := args[0]
+
+	if contextCreateCmdAccessTokenEnv != "" && contextCreateCmdAccessTokenFile != "" {
+		return usageErrorf("only one of --access-token-env and --access-token-file can be set")
+	}
+	if contextCreateCmdOutput != "" {
+		if err := validateOutputFormat(contextCreateCmdOutput); err != nil {
+			return err
+		}
+	}
+
+	f, err := config.LoadFile()
+	if err != nil {
+		return err
+	}
+	if f.GetContext(name) != nil {
+		return fmt.Errorf("context '%s' already exists", name)
+	}
+
+	f.SetContext(config.Context{
+		Name:                  name,
+		RegistryURL:           contextCreateCmdRegistryURL,
+		AccessTokenEnv:        contextCreateCmdAccessTokenEnv,
+		AccessTokenFile:       contextCreateCmdAccessTokenFile,
+		Output:                contextCreateCmdOutput,
+		CACert:                contextCreateCmdCACert,
+		ClientCert:            contextCreateCmdClientCert,
+		ClientKey:             contextCreateCmdClientKey,
+		InsecureSkipTLSVerify: contextCreateCmdInsecure,
+		IndexURL:              contextCreateCmdIndexURL,
+	})
+	// the first context ever created automatically becomes the current one
+	if contextCreateCmdUse || f.CurrentContext == "" {
+		f.CurrentContext = name
+	}
+
+	if err := config.SaveFile(f); err != nil {
+		return err
+	}
+
+	p.Infof("Context '%s' created\n", name)
+	if f.CurrentContext == name {
+		p.Infof("Switched to context '%s'\n", name)
+	}
+	return nil
+}
+
+func runContextDelete(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+	name := args[0]
+
+	f, err := config.LoadFile()
+	if err != nil {
+		return err
+	}
+	if f.GetContext(name) == nil {
+		return fmt.Errorf("context '%s' does not exist", name)
+	}
+
+	isCurrent := name == f.CurrentContext
+	if isCurrent && !contextDeleteCmdForce {
+		return fmt.Errorf(
+			"context '%s' is the current context, switch to another context first or pass --force to delete it anyway",
+			name,
+		)
+	}
+
+	contexts := make([]config.Context, 0, len(f.Contexts)-1)
+	for _, c := range f.Contexts {
+		if c.Name != name {
+			contexts = append(contexts, c)
+		}
+	}
+	f.Contexts = contexts
+	if isCurrent {
+		f.CurrentContext = ""
+	}
+
+	if err := config.SaveFile(f); err != nil {
+		return err
+	}
+
+	p.Infof("Context '%s' deleted\n", name)
+	if isCurrent {
+		p.Infoln("There is no current context now, run `mcpjungle context use <name>` to select one")
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func TestContextCommandStructure(t *testing.T) {
+	testhelpers.AssertEqual(t, "context", contextCmd.Use)
+
+	annotationTests := []testhelpers.CommandAnnotationTest{
+		{Key: "group", Expected: string(subCommandGroupAdvanced)},
+		{Key: "order", Expected: "11"},
+	}
+	testhelpers.TestCommandAnnotations(t, contextCmd.Annotations, annotationTests)
+
+	subcommands := contextCmd.Commands()
+	testhelpers.AssertEqual(t, 4, len(subcommands))
+
+	testhelpers.AssertNotNil(t, contextCreateCmd.Flags().Lookup("registry"))
+	testhelpers.AssertNotNil(t, contextDeleteCmd.Flags().Lookup("force"))
+}
+
+func TestContextLifecycle(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() {
+		contextCreateCmdRegistryURL, contextCreateCmdUse, contextDeleteCmdForce = "", false, false
+	})
+
+	out := &bytes.Buffer{}
+	for _, c := range contextCmd.Commands() {
+		c.SetOut(out)
+		c.SetErr(out)
+	}
+
+	// the first context becomes current automatically
+	contextCreateCmdRegistryURL = "http://127.0.0.1:8080"
+	testhelpers.AssertNoError(t, runContextCreate(contextCreateCmd, []string{"local"}))
+
+	contextCreateCmdRegistryURL = "https://remote.example.com"
+	testhelpers.AssertNoError(t, runContextCreate(contextCreateCmd, []string{"remote"}))
+
+	// duplicate names are rejected
+	testhelpers.AssertError(t, runContextCreate(contextCreateCmd, []string{"remote"}))
+
+	f, err := config.LoadFile()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "local", f.CurrentContext)
+	testhelpers.AssertEqual(t, 2, len(f.Contexts))
+
+	out.Reset()
+	testhelpers.AssertNoError(t, runContextList(contextListCmd, nil))
+	testhelpers.AssertStringContains(t, out.String(), "*        local")
+	testhelpers.AssertStringContains(t, out.String(), "remote")
+
+	// switch
+	testhelpers.AssertNoError(t, runContextUse(contextUseCmd, []string{"remote"}))
+	testhelpers.AssertError(t, runContextUse(contextUseCmd, []string{"missing"}))
+
+	f, err = config.LoadFile()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "remote", f.CurrentContext)
+
+	// deleting the current context needs --force
+	testhelpers.AssertError(t, runContextDelete(contextDeleteCmd, []string{"remote"}))
+	testhelpers.AssertNoError(t, runContextDelete(contextDeleteCmd, []string{"local"}))
+
+	contextDeleteCmdForce = true
+	testhelpers.AssertNoError(t, runContextDelete(contextDeleteCmd, []string{"remote"}))
+
+	f, err = config.LoadFile()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "", f.CurrentContext)
+	testhelpers.AssertEqual(t, 0, len(f.Contexts))
+}
+
+func TestSelectContextOverride(t *testing.T) {
+	f := &config.File{
+		CurrentContext: "a",
+		Contexts:       []config.Context{{Name: "a"}, {Name: "b"}},
+	}
+
+	cmd := newSettingsTestCmd(t)
+	cmd.Flags().StringVar(&contextOverride, "context", "", "")
+
+	ctx, err := selectContext(cmd, f)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "a", ctx.Name)
+
+	t.Setenv(ContextEnvVar, "b")
+	ctx, err = selectContext(cmd, f)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "b", ctx.Name)
+
+	testhelpers.AssertNoError(t, cmd.ParseFlags([]string{"--context", "a"}))
+	ctx, err = selectContext(cmd, f)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "a", ctx.Name)
+
+	testhelpers.AssertNoError(t, cmd.ParseFlags([]string{"--context", "missing"}))
+	_, err = selectContext(cmd, f)
+	testhelpers.AssertError(t, err)
+}
@@ -1,9 +1,8 @@
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/mcpjungle/mcpjungle/pkg/types"
@@ -55,10 +54,33 @@ var createToolGroupCmd = &cobra.Command{
 		"  - Including all tools from servers with 'included_servers'\n" +
 		"  - Excluding specific tools with 'excluded_tools'\n\n" +
 		"Once you create a tool group, it is accessible as a streamable http MCP server at the following endpoint:\n" +
-		"    /v0/groups/{group_name}/mcp\n",
+		"    /v0/groups/{group_name}/mcp\n\n" +
+		"The configuration can also be piped into the CLI, eg- `cat groups.yaml | mcpjungle create group -c -`.\n" +
+		"A single configuration may define multiple groups.\n" +
+		"Use --check to validate a configuration without creating the groups.\n",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return requireConfigInput(createToolGroupConfigFilePath)
+	},
 	RunE: runCreateToolGroup,
 }
 
+var createWebhookCmd = &cobra.Command{
+	Use:   "webhook [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Subscribe a URL to registry events",
+	Long: "Create a webhook: mcpjungle sends a JSON POST request to its URL whenever an event it subscribes to happens,\n" +
+		"eg- when an MCP server is registered or a tool group is updated.\n" +
+		"Failed deliveries are retried with backoff, and a webhook whose deliveries keep failing is disabled automatically.\n\n" +
+		"Every delivery is signed with the webhook's secret: the " + types.WebhookSignatureHeader + " header holds\n" +
+		"'sha256=' followed by the hex-encoded HMAC-SHA256 of the body. If you don't provide a secret, one is generated.\n\n" +
+		"With --format slack or markdown, the events are sent as readable messages to chat webhooks instead, eg-\n" +
+		"\"⚠️ MCP server 'jira' unhealthy: connection refused\". slack sends {\"text\": \"...\"} in Slack's syntax,\n" +
+		"which Slack, Mattermost and Rocket.Chat incoming webhooks accept. markdown sends the message in Markdown\n" +
+		"along with the event: {\"text\": \"...\", \"event\": {...}}.\n\n" +
+		"Events: " + strings.Join(types.WebhookEventTypes, ", "),
+	RunE: runCreateWebhook,
+}
+
 var (
 	createMcpClientCmdAllowedServers string
 	createMcpClientCmdDescription    string
@@ -67,6 +89,12 @@ var (
 	createUserCmdAccessToken string
 
 	createToolGroupConfigFilePath string
+	createToolGroupCmdCheck       bool
+
+	createWebhookCmdURL    string
+	createWebhookCmdEvents string
+	createWebhookCmdSecret string
+	createWebhookCmdFormat string
 )
 
 func init() {
@@ -102,18 +130,48 @@ func init() {
 		"conf",
 		"c",
 		"",
-		"Path to a JSON configuration file for the Group",
+		"Path to a JSON or YAML configuration file for the Group, or '-' to read it from stdin",
+	)
+	createToolGroupCmd.Flags().BoolVar(
+		&createToolGroupCmdCheck,
+		"check",
+		false,
+		"Only validate the configuration, with the same checks as the server, without creating anything.\n"+
+			"Whether the tools and servers exist is not checked.",
 	)
-	_ = createToolGroupCmd.MarkFlagRequired("conf")
+
+	createWebhookCmd.Flags().StringVar(&createWebhookCmdURL, "url", "", "URL the events are sent to")
+	createWebhookCmd.Flags().StringVar(
+		&createWebhookCmdEvents,
+		"events",
+		types.AllWebhookEvents,
+		"Comma-separated list of the events to subscribe to, or '*' for all of them",
+	)
+	createWebhookCmd.Flags().StringVar(
+		&createWebhookCmdSecret,
+		"secret",
+		"",
+		"Secret used to sign the deliveries. If not provided, a random secret will be generated.",
+	)
+	createWebhookCmd.Flags().StringVar(
+		&createWebhookCmdFormat,
+		"format",
+		types.WebhookFormatJSON,
+		"Format of the deliveries, one of: "+strings.Join(types.WebhookFormats, ", "),
+	)
+	_ = createWebhookCmd.MarkFlagRequired("url")
 
 	createCmd.AddCommand(createMcpClientCmd)
 	createCmd.AddCommand(createUserCmd)
 	createCmd.AddCommand(createToolGroupCmd)
+	createCmd.AddCommand(createWebhookCmd)
 
 	rootCmd.AddCommand(createCmd)
 }
 
 func runCreateMcpClient(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+
 	// convert the comma-separated list of allowed servers into a slice
 	allowList := make([]string, 0)
 	for _, s := range strings.Split(createMcpClientCmdAllowedServers, ",") {
@@ -122,9 +180,9 @@ func runCreateMcpClient(cmd *cobra.Command, args []string) error {
 			allowList = append(allowList, trimmed)
 		}
 		if trimmed == types.AllowAllMcpServers {
-			cmd.Println("NOTE: This client will have access to all MCP Servers because a wildcard is used.")
-			cmd.Println("This practice is highly discouraged!")
-			cmd.Println()
+			p.Warnf("This client will have access to all MCP Servers because a wildcard is used.")
+			p.Infoln("This practice is highly discouraged!")
+			p.Infoln()
 		}
 	}
 
@@ -138,7 +196,7 @@ func runCreateMcpClient(cmd *cobra.Command, args []string) error {
 		c.IsCustomAccessToken = true
 	}
 
-	token, err := apiClient.CreateMcpClient(c)
+	token, err := apiClient.CreateMcpClientContext(commandContext(cmd), c)
 	if err != nil {
 		return fmt.Errorf("failed to create MCP client: %w", err)
 	}
@@ -147,19 +205,28 @@ func runCreateMcpClient(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("server returned an empty token, this was unexpected")
 	}
 
-	cmd.Printf("MCP client '%s' created successfully!\n", c.Name)
+	if isStructuredOutput() {
+		out := map[string]any{"name": c.Name, "allow_list": c.AllowList}
+		if !c.IsCustomAccessToken {
+			out["access_token"] = token
+		}
+		return printOutput(cmd, out)
+	}
+
+	p.Infof("MCP client '%s' created successfully!\n", c.Name)
 
 	if len(c.AllowList) > 0 {
-		cmd.Println("Servers accessible: " + strings.Join(c.AllowList, ","))
+		p.Infoln("Servers accessible: " + strings.Join(c.AllowList, ","))
 	} else {
-		cmd.Println("This client does not have access to any MCP servers.")
+		p.Infoln("This client does not have access to any MCP servers.")
 	}
 
 	if !c.IsCustomAccessToken {
 		// server generated the access token, display it to the user
-		cmd.Printf("\nAccess token: %s\n", token)
+		p.Infoln()
+		p.Value("Access token", token)
 	}
-	cmd.Println("Your client should send the access token in the `Authorization: Bearer {token}` HTTP header.")
+	p.Infoln("Your client should send the access token in the `Authorization: Bearer {token}` HTTP header.")
 
 	return nil
 }
@@ -169,7 +236,7 @@ func runCreateUser(cmd *cobra.Command, args []string) error {
 		Username:    args[0],
 		AccessToken: createUserCmdAccessToken,
 	}
-	resp, err := apiClient.CreateUser(u)
+	resp, err := apiClient.CreateUserContext(commandContext(cmd), u)
 	if err != nil {
 		return err
 	}
@@ -177,47 +244,142 @@ func runCreateUser(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("server returned an empty access token, this was unexpected")
 	}
 
-	cmd.Printf("User '%s' created successfully\n", u.Username)
-	cmd.Println("The user should now run the following command to log into mcpjungle:")
-	cmd.Println()
-	cmd.Printf("    mcpjungle login %s\n", resp.AccessToken)
-	cmd.Println()
+	if isStructuredOutput() {
+		return printOutput(cmd, map[string]any{"username": u.Username, "access_token": resp.AccessToken})
+	}
+
+	p := newPrinter(cmd)
+	if quietFlag {
+		p.Resultln(resp.AccessToken)
+		return nil
+	}
+	p.Infof("User '%s' created successfully\n", u.Username)
+	p.Infoln("The user should now run the following command to log into mcpjungle:")
+	p.Infoln()
+	p.Resultf("    mcpjungle login %s\n", resp.AccessToken)
+	p.Infoln()
 
 	return nil
 }
 
-func readToolGroupConfig(filePath string) (*types.ToolGroup, error) {
-	var input types.ToolGroup
-
-	data, err := os.ReadFile(filePath)
+// readToolGroupConfigs reads the configurations of one or more tool groups from a JSON or YAML file, or from stdin.
+func readToolGroupConfigs(cmd *cobra.Command, filePath string) ([]types.ToolGroup, error) {
+	data, err := readConfigInput(cmd, filePath)
 	if err != nil {
-		return &input, fmt.Errorf("failed to read config file %s: %w", filePath, err)
+		return nil, err
 	}
-	if err := json.Unmarshal(data, &input); err != nil {
-		return &input, fmt.Errorf("failed to parse config file: %w", err)
+	groups, err := decodeConfigEntities[types.ToolGroup](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration from %s: %w", configSourceName(filePath), err)
 	}
-
-	return &input, nil
+	return groups, nil
 }
 
 func runCreateToolGroup(cmd *cobra.Command, args []string) error {
-	group, err := readToolGroupConfig(createToolGroupConfigFilePath)
+	groups, err := readToolGroupConfigs(cmd, createToolGroupConfigFilePath)
 	if err != nil {
-		return fmt.Errorf("failed to read config file %s: %w", createToolGroupConfigFilePath, err)
+		return err
 	}
 
-	resp, err := apiClient.CreateToolGroup(group)
-	if err != nil {
-		return fmt.Errorf("failed to create tool group: %w", err)
+	source := configSourceName(createToolGroupConfigFilePath)
+	if createToolGroupCmdCheck {
+		names := make([]string, len(groups))
+		for i := range groups {
+			names[i] = groups[i].Name
+		}
+		return checkConfigEntities(cmd, "tool group", source, names, func(i int) error { return groups[i].Validate() })
 	}
 
-	cmd.Printf("Tool Group %s created successfully\n", group.Name)
-	cmd.Print("It is now accessible at the following streamable http endpoint:\n\n")
-	cmd.Println("    " + resp.StreamableHTTPEndpoint + "\n")
+	var created []*types.CreateToolGroupResponse
+	var failures []error
+	for i := range groups {
+		resp, err := apiClient.CreateToolGroupContext(commandContext(cmd), &groups[i])
+		if err != nil {
+			failures = append(failures, configEntityError(
+				err, "failed to create tool group "+groups[i].Name, source, i, len(groups),
+			))
+			continue
+		}
+		created = append(created, resp)
+		if !isStructuredOutput() {
+			printCreatedToolGroup(cmd, groups[i].Name, resp)
+		}
+	}
 
-	cmd.Print("Tools using the SSE (server-sent events) transport are accessible at:\n\n")
-	cmd.Println("    " + resp.SSEEndpoint)
-	cmd.Println("    " + resp.SSEMessageEndpoint + "\n")
+	if isStructuredOutput() {
+		var err error
+		if len(groups) == 1 && len(created) == 1 {
+			err = printOutput(cmd, created[0])
+		} else if len(created) > 0 {
+			err = printOutput(cmd, created)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(failures) == 1 && len(groups) == 1 {
+		return failures[0]
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf(
+			"%d of %d tool groups could not be created:\n%w", len(failures), len(groups), errors.Join(failures...),
+		)
+	}
+	return nil
+}
+
+// printCreatedToolGroup tells the user about a newly created tool group and the endpoints it is accessible at.
+func printCreatedToolGroup(cmd *cobra.Command, name string, resp *types.CreateToolGroupResponse) {
+	p := newPrinter(cmd)
+	if quietFlag {
+		// the endpoint is what the user needs to connect their MCP clients to the group
+		p.Resultln(resp.StreamableHTTPEndpoint)
+		return
+	}
+	p.Infof("Tool Group %s created successfully\n", name)
+	p.Infof("It is now accessible at the following streamable http endpoint:\n\n")
+	p.Resultln("    " + resp.StreamableHTTPEndpoint + "\n")
+
+	p.Infof("Tools using the SSE (server-sent events) transport are accessible at:\n\n")
+	p.Resultln("    " + resp.SSEEndpoint)
+	p.Resultln("    " + resp.SSEMessageEndpoint + "\n")
+}
 
+func runCreateWebhook(cmd *cobra.Command, args []string) error {
+	w := &types.Webhook{
+		Name: args[0], URL: createWebhookCmdURL, Secret: createWebhookCmdSecret, Format: createWebhookCmdFormat,
+	}
+	for _, e := range strings.Split(createWebhookCmdEvents, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			w.Events = append(w.Events, e)
+		}
+	}
+
+	created, err := apiClient.CreateWebhook(commandContext(cmd), w)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	if isStructuredOutput() {
+		return printOutput(cmd, created)
+	}
+
+	p := newPrinter(cmd)
+	if quietFlag {
+		p.Resultln(created.Secret)
+		return nil
+	}
+	p.Infof("Webhook '%s' created successfully!\n", created.Name)
+	if len(created.Events) > 0 {
+		p.Infoln("Events: " + strings.Join(created.Events, ","))
+	}
+	if createWebhookCmdSecret == "" {
+		// the secret was generated by the server, this is the only time it is shown
+		p.Infoln()
+		p.Value("Secret", created.Secret)
+	}
+	p.Infof("Deliveries are signed with the secret in the %s header.\n", types.WebhookSignatureHeader)
+	p.Infof("Run 'test webhook %s' to send it a test event.\n", created.Name)
 	return nil
 }
@@ -23,7 +23,7 @@ func TestCreateCommandStructure(t *testing.T) {
 
 	// Test subcommands count
 	subcommands := createCmd.Commands()
-	testhelpers.AssertEqual(t, 3, len(subcommands))
+	testhelpers.AssertEqual(t, 5, len(subcommands))
 }
 
 func TestCreateMcpClientSubcommand(t *testing.T) {
@@ -135,7 +135,7 @@ func TestCreateCommandIntegration(t *testing.T) {
 
 	// Test all create subcommands are properly configured
 	subcommands := createCmd.Commands()
-	expectedSubcommands := []string{"mcp-client", "user", "group"}
+	expectedSubcommands := []string{"mcp-client", "user", "group", "webhook", "webhook-tool"}
 
 	testhelpers.AssertEqual(t, len(expectedSubcommands), len(subcommands))
 
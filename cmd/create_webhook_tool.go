@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	createWebhookToolName         string
+	createWebhookToolDesc         string
+	createWebhookToolURL          string
+	createWebhookToolMethod       string
+	createWebhookToolSchema       string
+	createWebhookToolHeaders      []string
+	createWebhookToolBearerToken  string
+	createWebhookToolAPIKey       string
+	createWebhookToolAPIKeyHeader string
+)
+
+var createWebhookToolCmd = &cobra.Command{
+	Use:   "webhook-tool",
+	Short: "Serve a plain HTTP endpoint as an MCP tool",
+	Long: "Create a tool served by mcpjungle itself, that sends its arguments to a plain HTTP endpoint,\n" +
+		"eg- the webhook of an automation, without standing up an MCP server for it.\n" +
+		"Each tool call validates the arguments against the JSON schema given by --schema, and sends them\n" +
+		"as the JSON body of the request (as its query for GET requests) with the headers and credential of the tool.\n" +
+		"The status and body of the response are returned as the result, which is an error if the status isn't 2xx.\n\n" +
+		"The tool is registered as an MCP server with the webhook_tool transport and serves a single tool\n" +
+		"named after it, eg- deploy_notify__deploy_notify. It can be added to tool groups, allowed to MCP clients\n" +
+		"and exported like the tools of any other server.\n" +
+		"Deleting it with `mcpjungle delete webhook-tool` also removes it from the tool groups that include it.",
+	Example: "  mcpjungle create webhook-tool --name deploy_notify \\\n" +
+		"    --url https://automation.example.com/hooks/deploy --method POST --schema schema.json \\\n" +
+		"    --bearer-token $DEPLOY_TOKEN --header 'X-Source: mcpjungle'",
+	Args: cobra.NoArgs,
+	RunE: runCreateWebhookTool,
+}
+
+func init() {
+	createWebhookToolCmd.Flags().StringVar(
+		&createWebhookToolName,
+		"name",
+		"",
+		"Name of the tool, which is also the name of its MCP server",
+	)
+	_ = createWebhookToolCmd.MarkFlagRequired("name")
+	createWebhookToolCmd.Flags().StringVar(
+		&createWebhookToolDesc,
+		"description",
+		"",
+		"Description of the tool shown to MCP clients",
+	)
+	createWebhookToolCmd.Flags().StringVar(
+		&createWebhookToolURL,
+		"url",
+		"",
+		"http(s) URL of the endpoint the arguments are sent to",
+	)
+	_ = createWebhookToolCmd.MarkFlagRequired("url")
+	createWebhookToolCmd.Flags().StringVar(
+		&createWebhookToolMethod,
+		"method",
+		"POST",
+		"HTTP method of the requests to the endpoint",
+	)
+	createWebhookToolCmd.Flags().StringVar(
+		&createWebhookToolSchema,
+		"schema",
+		"",
+		"Path to the JSON schema of the arguments of the tool, or '-' to read it from stdin.\n"+
+			"By default, the tool accepts any object.",
+	)
+	createWebhookToolCmd.Flags().StringArrayVar(
+		&createWebhookToolHeaders,
+		"header",
+		nil,
+		"Header sent with every request, written 'Name: value', can be repeated",
+	)
+	createWebhookToolCmd.Flags().StringVar(
+		&createWebhookToolBearerToken,
+		"bearer-token",
+		"",
+		"Bearer token sent in the Authorization header of every request to the endpoint",
+	)
+	createWebhookToolCmd.Flags().StringVar(
+		&createWebhookToolAPIKey,
+		"api-key",
+		"",
+		"API key sent in the header given by --api-key-header with every request to the endpoint",
+	)
+	createWebhookToolCmd.Flags().StringVar(
+		&createWebhookToolAPIKeyHeader,
+		"api-key-header",
+		"X-API-Key",
+		"Header carrying the API key",
+	)
+	createWebhookToolCmd.MarkFlagsMutuallyExclusive("bearer-token", "api-key")
+
+	createCmd.AddCommand(createWebhookToolCmd)
+}
+
+func runCreateWebhookTool(cmd *cobra.Command, args []string) error {
+	input, err := newWebhookToolInput(cmd)
+	if err != nil {
+		return err
+	}
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	pr := newPrinter(cmd).Progress(fmt.Sprintf("Creating webhook tool %s", input.Name))
+	s, err := apiClient.RegisterServerContext(commandContext(cmd), input)
+	pr.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to create webhook tool %s: %w", input.Name, err)
+	}
+	if isStructuredOutput() {
+		return printOutput(cmd, s)
+	}
+	p := newPrinter(cmd)
+	p.Infof("Webhook tool %s created successfully!\n", s.Name)
+	p.Infof("MCP clients can call it as the tool %s__%s.\n", s.Name, s.Name)
+	return nil
+}
+
+// newWebhookToolInput converts the flags of the command into the registration of the server of the webhook tool.
+func newWebhookToolInput(cmd *cobra.Command) (*types.RegisterServerInput, error) {
+	conf := &types.WebhookToolConfig{
+		URL:         createWebhookToolURL,
+		Method:      strings.ToUpper(createWebhookToolMethod),
+		Description: createWebhookToolDesc,
+	}
+	for _, h := range createWebhookToolHeaders {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, usageErrorf("invalid header %q, expected 'Name: value'", h)
+		}
+		if conf.Headers == nil {
+			conf.Headers = make(map[string]string)
+		}
+		conf.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	switch {
+	case createWebhookToolBearerToken != "":
+		conf.Auth = &types.OpenAPIAuth{Type: types.OpenAPIAuthBearer, Value: createWebhookToolBearerToken}
+	case createWebhookToolAPIKey != "":
+		conf.Auth = &types.OpenAPIAuth{
+			Type: types.OpenAPIAuthAPIKey, Header: createWebhookToolAPIKeyHeader, Value: createWebhookToolAPIKey,
+		}
+	}
+	if createWebhookToolSchema != "" {
+		data, err := readConfigInput(cmd, createWebhookToolSchema)
+		if err != nil {
+			return nil, err
+		}
+		var schema map[string]any
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("invalid JSON schema in %s: %w", configSourceName(createWebhookToolSchema), err)
+		}
+		conf.InputSchema = json.RawMessage(data)
+	}
+	return &types.RegisterServerInput{
+		Name:        createWebhookToolName,
+		Transport:   string(types.TransportWebhookTool),
+		Description: createWebhookToolDesc,
+		WebhookTool: conf,
+	}, nil
+}
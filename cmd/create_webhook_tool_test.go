@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// withWebhookToolFlags sets the flags of `create webhook-tool` for the duration of a test.
+func withWebhookToolFlags(t *testing.T, name, url, method, schema string, headers []string) {
+	t.Helper()
+	origName, origURL, origMethod := createWebhookToolName, createWebhookToolURL, createWebhookToolMethod
+	origSchema, origHeaders, origToken := createWebhookToolSchema, createWebhookToolHeaders, createWebhookToolBearerToken
+	t.Cleanup(func() {
+		createWebhookToolName, createWebhookToolURL, createWebhookToolMethod = origName, origURL, origMethod
+		createWebhookToolSchema, createWebhookToolHeaders = origSchema, origHeaders
+		createWebhookToolBearerToken = origToken
+	})
+	createWebhookToolName, createWebhookToolURL, createWebhookToolMethod = name, url, method
+	createWebhookToolSchema, createWebhookToolHeaders = schema, headers
+}
+
+func TestNewWebhookToolInput(t *testing.T) {
+	schema := filepath.Join(t.TempDir(), "schema.json")
+	testhelpers.AssertNoError(t, os.WriteFile(schema, []byte(
+		`{"type":"object","properties":{"service":{"type":"string"}},"required":["service"]}`,
+	), 0o600))
+	withWebhookToolFlags(
+		t, "deploy_notify", "https://automation.example.com/hooks/deploy", "post", schema,
+		[]string{"X-Source: mcpjungle", "X-Team:platform"},
+	)
+	createWebhookToolBearerToken = "deploy-token"
+
+	input, err := newWebhookToolInput(&cobra.Command{})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, input.Validate())
+	testhelpers.AssertEqual(t, string(types.TransportWebhookTool), input.Transport)
+	conf := input.WebhookTool
+	testhelpers.AssertEqual(t, "POST", conf.Method)
+	testhelpers.AssertEqual(t, "mcpjungle", conf.Headers["X-Source"])
+	testhelpers.AssertEqual(t, "platform", conf.Headers["X-Team"])
+	testhelpers.AssertEqual(t, types.OpenAPIAuthBearer, conf.Auth.Type)
+	testhelpers.AssertStringContains(t, string(conf.InputSchema), `"required":["service"]`)
+
+	createWebhookToolHeaders = []string{"X-Source"}
+	_, err = newWebhookToolInput(&cobra.Command{})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), `invalid header "X-Source", expected 'Name: value'`)
+}
+
+func TestDeleteWebhookTool(t *testing.T) {
+	deregistered := false
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/servers/deploy_notify": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, types.McpServer{
+				Name: "deploy_notify", Transport: string(types.TransportWebhookTool),
+			})
+		},
+		"GET /api/v1/servers/github": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, types.McpServer{
+				Name: "github", Transport: string(types.TransportStreamableHTTP),
+			})
+		},
+		"GET /api/v1/tool-references": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, types.ToolReferences{
+				Tools:  []string{"deploy_notify__deploy_notify"},
+				Groups: []types.ToolGroupReference{{Name: "release", Tools: []string{"deploy_notify__deploy_notify"}}},
+			})
+		},
+		"DELETE /api/v1/servers/deploy_notify": func(w http.ResponseWriter, r *http.Request) {
+			deregistered = true
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+
+	withConfirmState(t, false, false)
+	cmd, stderr := newDeregisterTestCmd()
+	err := runDeleteWebhookTool(cmd, []string{"deploy_notify"})
+	testhelpers.AssertTrue(t, errors.Is(err, ErrConfirmationRequired), "delete should require confirmation")
+	testhelpers.AssertStringContains(t, stderr.String(), "remove the tool from groups: release")
+
+	withConfirmState(t, true, false)
+	cmd, stderr = newDeregisterTestCmd()
+	testhelpers.AssertNoError(t, runDeleteWebhookTool(cmd, []string{"deploy_notify"}))
+	testhelpers.AssertTrue(t, deregistered, "the webhook tool should be deleted")
+	testhelpers.AssertStringContains(t, stderr.String(), "Webhook tool 'deploy_notify' deleted successfully")
+
+	// the other servers are removed with deregister
+	err = runDeleteWebhookTool(cmd, []string{"github"})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "MCP server github is not a webhook tool")
+}
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
 )
 
@@ -44,37 +46,121 @@ var deleteToolGroupCmd = &cobra.Command{
 	RunE: runDeleteToolGroup,
 }
 
+var deleteWebhookCmd = &cobra.Command{
+	Use:   "webhook [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a webhook",
+	Long:  "Delete a webhook along with its delivery log. Events are no longer sent to its URL.",
+	RunE:  runDeleteWebhook,
+}
+
+var deleteWebhookToolCmd = &cobra.Command{
+	Use:   "webhook-tool [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a webhook tool",
+	Long: "Delete a tool created with `mcpjungle create webhook-tool`, along with its MCP server.\n" +
+		"It is also removed from the tool groups that include it.",
+	RunE: runDeleteWebhookTool,
+}
+
 func init() {
 	deleteCmd.AddCommand(deleteMcpClientCmd)
 	deleteCmd.AddCommand(deleteUserCmd)
 	deleteCmd.AddCommand(deleteToolGroupCmd)
+	deleteCmd.AddCommand(deleteWebhookCmd)
+	deleteCmd.AddCommand(deleteWebhookToolCmd)
 
 	rootCmd.AddCommand(deleteCmd)
 }
 
 func runDeleteMcpClient(cmd *cobra.Command, args []string) error {
 	name := args[0]
-	if err := apiClient.DeleteMcpClient(name); err != nil {
+	c := confirmation{
+		Action: fmt.Sprintf("delete MCP client '%s'", name),
+		Impact: []string{"instantly revoke all access of this client"},
+	}
+	if err := confirmDestructiveAction(cmd, c); err != nil {
+		return err
+	}
+	if err := apiClient.DeleteMcpClientContext(commandContext(cmd), name); err != nil {
 		return fmt.Errorf("failed to delete the client: %w", err)
 	}
-	fmt.Printf("MCP client '%s' deleted successfully (if it existed)!\n", name)
+	newPrinter(cmd).Infof("MCP client '%s' deleted successfully (if it existed)!\n", name)
 	return nil
 }
 
 func runDeleteUser(cmd *cobra.Command, args []string) error {
 	username := args[0]
-	if err := apiClient.DeleteUser(username); err != nil {
+	c := confirmation{
+		Action: fmt.Sprintf("delete user '%s'", username),
+		Impact: []string{"instantly revoke all access of this user"},
+	}
+	if err := confirmDestructiveAction(cmd, c); err != nil {
+		return err
+	}
+	if err := apiClient.DeleteUserContext(commandContext(cmd), username); err != nil {
 		return fmt.Errorf("failed to delete the user: %w", err)
 	}
-	cmd.Printf("User '%s' deleted successfully (if they existed)\n", username)
+	newPrinter(cmd).Infof("User '%s' deleted successfully (if they existed)\n", username)
 	return nil
 }
 
 func runDeleteToolGroup(cmd *cobra.Command, args []string) error {
 	name := args[0]
-	if err := apiClient.DeleteToolGroup(name); err != nil {
+	c := confirmation{
+		Action: fmt.Sprintf("delete tool group '%s'", name),
+		Impact: []string{"make the group's MCP endpoint unavailable to any clients relying on it"},
+	}
+	if err := confirmDestructiveAction(cmd, c); err != nil {
+		return err
+	}
+	if err := apiClient.DeleteToolGroupContext(commandContext(cmd), name); err != nil {
 		return fmt.Errorf("failed to delete the tool group: %w", err)
 	}
-	cmd.Printf("Tool group '%s' deleted successfully!\n", name)
+	newPrinter(cmd).Infof("Tool group '%s' deleted successfully!\n", name)
+	return nil
+}
+
+func runDeleteWebhook(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	c := confirmation{
+		Action: fmt.Sprintf("delete webhook '%s'", name),
+		Impact: []string{"stop sending events to its URL", "delete its delivery log"},
+	}
+	if err := confirmDestructiveAction(cmd, c); err != nil {
+		return err
+	}
+	if err := apiClient.DeleteWebhook(commandContext(cmd), name); err != nil {
+		return fmt.Errorf("failed to delete the webhook: %w", err)
+	}
+	newPrinter(cmd).Infof("Webhook '%s' deleted successfully!\n", name)
+	return nil
+}
+
+func runDeleteWebhookTool(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	s, err := apiClient.GetServerContext(commandContext(cmd), name)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook tool %s: %w", name, err)
+	}
+	if s.Transport != string(types.TransportWebhookTool) {
+		return fmt.Errorf(
+			"MCP server %s is not a webhook tool, deregister it with 'mcpjungle deregister %s' instead", name, name,
+		)
+	}
+
+	impact := []string{"remove the tool and its MCP server from the registry"}
+	refs, err := apiClient.GetToolReferencesContext(commandContext(cmd), s.Name, nil)
+	if err == nil && len(refs.Groups) > 0 {
+		impact = append(impact, "remove the tool from groups: "+strings.Join(referencingGroupNames(refs), ", "))
+	}
+	c := confirmation{Action: fmt.Sprintf("delete webhook tool '%s'", s.Name), Impact: impact}
+	if err := confirmDestructiveAction(cmd, c); err != nil {
+		return err
+	}
+	if err := apiClient.DeregisterServerContext(commandContext(cmd), s.Name); err != nil {
+		return fmt.Errorf("failed to delete webhook tool %s: %w", s.Name, err)
+	}
+	newPrinter(cmd).Infof("Webhook tool '%s' deleted successfully!\n", s.Name)
 	return nil
 }
@@ -22,7 +22,7 @@ func TestDeleteCommandStructure(t *testing.T) {
 
 	// Test subcommands count
 	subcommands := deleteCmd.Commands()
-	testhelpers.AssertEqual(t, 3, len(subcommands))
+	testhelpers.AssertEqual(t, 5, len(subcommands))
 }
 
 func TestDeleteMcpClientSubcommand(t *testing.T) {
@@ -108,7 +108,7 @@ func TestDeleteCommandIntegration(t *testing.T) {
 
 	// Test all delete subcommands are properly configured
 	subcommands := deleteCmd.Commands()
-	expectedSubcommands := []string{"mcp-client", "user", "group"}
+	expectedSubcommands := []string{"mcp-client", "user", "group", "webhook", "webhook-tool"}
 
 	testhelpers.AssertEqual(t, len(expectedSubcommands), len(subcommands))
 
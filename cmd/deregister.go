@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
 )
 
@@ -23,12 +27,81 @@ func init() {
 }
 
 func runDeregisterMCPServer(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
 	server := args[0]
-	if err := apiClient.DeregisterServer(server); err != nil {
+
+	// find out what depends on the server before removing it, so that the user knows what they are breaking
+	refs, err := apiClient.GetToolReferencesContext(commandContext(cmd), server, nil)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			return fmt.Errorf("failed to deregister MCP server %s: %w", server, err)
+		}
+		p.Warnf("Could not determine which tool groups use the tools of server %s: %v\n", server, err)
+		refs = nil
+	}
+
+	c := confirmation{
+		Action: fmt.Sprintf("deregister MCP server '%s'", server),
+		Impact: deregisterImpact(refs),
+	}
+	if err := confirmDestructiveAction(cmd, c); err != nil {
+		return err
+	}
+	if err := apiClient.DeregisterServerContext(commandContext(cmd), server); err != nil {
 		return fmt.Errorf("failed to deregister MCP server %s: %w", server, err)
 	}
-	fmt.Printf("Successfully deregistered MCP server %s\n", server)
-	fmt.Println("The tools provided by this server have also been deregistered.")
-	// TODO: Output the list of tools that were deregistered.
+
+	p.Infof("Successfully deregistered MCP server %s\n", server)
+	if refs == nil {
+		p.Infoln("The tools provided by this server have also been deregistered.")
+		return nil
+	}
+	p.Infof("The %s provided by this server have also been deregistered.\n", pluralize(len(refs.Tools), "tool"))
+	if len(refs.Groups) > 0 {
+		p.Warnf(
+			"These tool groups no longer have access to some of their tools: %s\n",
+			strings.Join(referencingGroupNames(refs), ", "),
+		)
+	}
 	return nil
 }
+
+// deregisterImpact describes the consequences of deregistering a server, given the groups that use its tools.
+// If refs is nil, ie, the references could not be determined, the description stays generic.
+func deregisterImpact(refs *types.ToolReferences) []string {
+	if refs == nil {
+		return []string{
+			"remove the server from the registry",
+			"deregister all tools and prompts provided by the server",
+		}
+	}
+
+	impact := []string{"remove the server from the registry"}
+	tools := pluralize(len(refs.Tools), "tool")
+	if len(refs.Groups) == 0 {
+		impact = append(impact, fmt.Sprintf("remove %s and all prompts provided by the server, no tool groups use them", tools))
+		return impact
+	}
+	impact = append(
+		impact,
+		fmt.Sprintf("remove %s used by groups: %s", tools, strings.Join(referencingGroupNames(refs), ", ")),
+		"remove all prompts provided by the server",
+	)
+	return impact
+}
+
+func referencingGroupNames(refs *types.ToolReferences) []string {
+	names := make([]string, len(refs.Groups))
+	for i, g := range refs.Groups {
+		names[i] = g.Name
+	}
+	return names
+}
+
+// pluralize returns the count followed by the noun, with an "s" appended unless the count is 1, eg- "14 tools".
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"bytes"
+	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
 )
 
 func TestDeregisterCommandStructure(t *testing.T) {
@@ -50,3 +55,67 @@ func TestDeregisterCommandArgumentValidation(t *testing.T) {
 	// Test that command properly validates arguments
 	testhelpers.AssertNotNil(t, deregisterMCPServerCmd.Args)
 }
+
+func newDeregisterTestCmd() (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	stderr := &bytes.Buffer{}
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(stderr)
+	return cmd, stderr
+}
+
+func TestDeregisterShowsBlastRadius(t *testing.T) {
+	deregistered := false
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-references": func(w http.ResponseWriter, r *http.Request) {
+			testhelpers.AssertEqual(t, "github", r.URL.Query().Get("server"))
+			writeTestJSON(w, http.StatusOK, types.ToolReferences{
+				Tools: []string{"github__git_commit", "github__git_push"},
+				Groups: []types.ToolGroupReference{
+					{Name: "ci-tools", Tools: []string{"github__git_push"}},
+					{Name: "support-agent", Tools: []string{"github__git_commit"}},
+				},
+			})
+		},
+		"DELETE /api/v1/servers/github": func(w http.ResponseWriter, r *http.Request) {
+			deregistered = true
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+
+	t.Run("confirmation required", func(t *testing.T) {
+		withConfirmState(t, false, false)
+		cmd, stderr := newDeregisterTestCmd()
+		err := runDeregisterMCPServer(cmd, []string{"github"})
+		testhelpers.AssertTrue(t, errors.Is(err, ErrConfirmationRequired), "deregister should require confirmation")
+		testhelpers.AssertStringContains(t, stderr.String(), "remove 2 tools used by groups: ci-tools, support-agent")
+		testhelpers.AssertFalse(t, deregistered, "the server must not be deregistered without confirmation")
+	})
+
+	t.Run("confirmed with --yes", func(t *testing.T) {
+		withConfirmState(t, true, false)
+		cmd, stderr := newDeregisterTestCmd()
+		testhelpers.AssertNoError(t, runDeregisterMCPServer(cmd, []string{"github"}))
+		testhelpers.AssertTrue(t, deregistered, "the server should be deregistered")
+		testhelpers.AssertStringContains(t, stderr.String(), "The 2 tools provided by this server have also been deregistered")
+		testhelpers.AssertStringContains(t, stderr.String(), "no longer have access to some of their tools: ci-tools, support-agent")
+	})
+}
+
+func TestDeregisterUnknownServer(t *testing.T) {
+	withConfirmState(t, true, false)
+	withFakeRegistry(t, http.StatusNotFound, `{"error":"failed to get MCP server github from DB: record not found"}`)
+
+	cmd, _ := newDeregisterTestCmd()
+	err := runDeregisterMCPServer(cmd, []string{"github"})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitNotFound, ExitCodeForError(err))
+}
+
+func TestDeregisterImpact(t *testing.T) {
+	// without references, the impact stays generic
+	testhelpers.AssertEqual(t, 2, len(deregisterImpact(nil)))
+
+	impact := deregisterImpact(&types.ToolReferences{Tools: []string{"time__now"}})
+	testhelpers.AssertStringContains(t, impact[1], "remove 1 tool and all prompts provided by the server, no tool groups use them")
+}
@@ -67,10 +67,19 @@ var disableServerCmd = &cobra.Command{
 	RunE: runDisableServer,
 }
 
+var disableWebhookCmd = &cobra.Command{
+	Use:   "webhook [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Disable a webhook",
+	Long:  "Events are no longer sent to a disabled webhook, until it is enabled again.",
+	RunE:  runDisableWebhook,
+}
+
 func init() {
 	disableCmd.AddCommand(disableToolsCmd)
 	disableCmd.AddCommand(disablePromptsCmd)
 	disableCmd.AddCommand(disableServerCmd)
+	disableCmd.AddCommand(disableWebhookCmd)
 	rootCmd.AddCommand(disableCmd)
 }
 
@@ -78,11 +87,11 @@ func init() {
 // and redirects to `mcpjungle disable tool [name]`.
 // This is to maintain backward compatibility with older versions of the CLI that only supported disabling tools & servers.
 func runDisable(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+
 	if len(args) == 1 && cmd.CalledAs() == "disable" {
-		cmd.Println(
-			"Warning: 'disable [name]' is deprecated. Please use 'disable tool [name]' or 'disable server [name]' instead.",
-		)
-		cmd.Println()
+		p.Warnf("'disable [name]' is deprecated. Please use 'disable tool [name]' or 'disable server [name]' instead.")
+		p.Infoln()
 
 		// only disable tools, because this was the behaviour before prompts were introduced
 		// to disable everything, users should now use `disable server [name]`
@@ -93,64 +102,79 @@ func runDisable(cmd *cobra.Command, args []string) error {
 }
 
 func runDisableTools(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+
 	name := args[0]
-	toolsDisabled, err := apiClient.DisableTools(name)
+	toolsDisabled, err := apiClient.DisableToolsContext(commandContext(cmd), name)
 	if err != nil {
 		return fmt.Errorf("failed to disable %s: %w", name, err)
 	}
 	if len(toolsDisabled) == 1 {
-		cmd.Printf("MCP tool '%s' disabled successfully!\n", toolsDisabled[0])
+		p.Infof("MCP tool '%s' disabled successfully!\n", toolsDisabled[0])
 		return nil
 	}
-	cmd.Println("Following MCP tools have been disabled successfully:")
+	p.Infoln("Following MCP tools have been disabled successfully:")
 	for _, tool := range toolsDisabled {
-		cmd.Printf("- %s\n", tool)
+		p.Infof("- %s\n", tool)
 	}
 	return nil
 }
 
 func runDisablePrompts(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+
 	name := args[0]
-	promptsDisabled, err := apiClient.DisablePrompts(name)
+	promptsDisabled, err := apiClient.DisablePromptsContext(commandContext(cmd), name)
 	if err != nil {
 		return fmt.Errorf("failed to disable %s: %w", name, err)
 	}
 	if len(promptsDisabled) == 1 {
-		cmd.Printf("MCP prompt '%s' disabled successfully!\n", promptsDisabled[0])
+		p.Infof("MCP prompt '%s' disabled successfully!\n", promptsDisabled[0])
 		return nil
 	}
-	cmd.Println("Following MCP prompts have been disabled successfully:")
+	p.Infoln("Following MCP prompts have been disabled successfully:")
 	for _, prompt := range promptsDisabled {
-		cmd.Printf("- %s\n", prompt)
+		p.Infof("- %s\n", prompt)
 	}
 	return nil
 }
 
 func runDisableServer(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+
 	name := args[0]
-	resp, err := apiClient.DisableServer(name)
+	resp, err := apiClient.DisableServerContext(commandContext(cmd), name)
 	if err != nil {
 		return fmt.Errorf("failed to disable server %s: %w", name, err)
 	}
 
-	cmd.Printf("MCP server '%s' disabled successfully!\n", resp.Name)
+	p.Infof("MCP server '%s' disabled successfully!\n", resp.Name)
 
 	if len(resp.ToolsAffected) > 0 {
-		cmd.Println()
-		cmd.Println("Following MCP tools have been disabled:")
+		p.Infoln()
+		p.Infoln("Following MCP tools have been disabled:")
 		for _, tool := range resp.ToolsAffected {
-			cmd.Printf("    - %s\n", tool)
+			p.Infof("    - %s\n", tool)
 		}
 	}
 
 	if len(resp.PromptsAffected) > 0 {
-		cmd.Println()
-		cmd.Println("Following MCP prompts have been disabled:")
+		p.Infoln()
+		p.Infoln("Following MCP prompts have been disabled:")
 		for _, prompt := range resp.PromptsAffected {
-			cmd.Printf("    - %s\n", prompt)
+			p.Infof("    - %s\n", prompt)
 		}
 	}
 
-	cmd.Println()
+	p.Infoln()
+	return nil
+}
+
+func runDisableWebhook(cmd *cobra.Command, args []string) error {
+	w, err := apiClient.DisableWebhook(commandContext(cmd), args[0])
+	if err != nil {
+		return fmt.Errorf("failed to disable webhook %s: %w", args[0], err)
+	}
+	newPrinter(cmd).Infof("Webhook '%s' disabled successfully!\n", w.Name)
 	return nil
 }
@@ -0,0 +1,423 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/mcpjungle/mcpjungle/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+// doctorRequestTimeout bounds every request made by the doctor, so that it never hangs on an unresponsive server.
+const doctorRequestTimeout = 5 * time.Second
+
+// maxClockSkew is the difference between the CLI's and the server's clocks above which the doctor warns.
+const maxClockSkew = time.Minute
+
+// checkStatus is the outcome of a doctor check.
+type checkStatus string
+
+const (
+	checkPass checkStatus = "pass"
+	checkWarn checkStatus = "warn"
+	checkFail checkStatus = "fail"
+	// checkSkip means the check could not run, eg- because the server is unreachable
+	checkSkip checkStatus = "skip"
+)
+
+// doctorCheck is the result of a single diagnosis made by the doctor.
+type doctorCheck struct {
+	Name    string      `json:"name"`
+	Status  checkStatus `json:"status"`
+	Message string      `json:"message"`
+	// Hint tells the user how to fix the problem, it is only set for checks that didn't pass
+	Hint string `json:"hint,omitempty"`
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose common setup problems",
+	Long: "Run a series of checks against the CLI configuration and the mcpjungle server, " +
+		"and print the result of each check along with a hint on how to fix any problem found.\n\n" +
+		"The following is checked: CLI configuration, registry reachability and version compatibility, " +
+		"database health, server initialization & mode, clock skew, authentication, " +
+		"health of the registered servers and tool groups referring to tools that don't exist.\n\n" +
+		"The command exits with a non-zero code if any check fails, so it can be used to gate deployment scripts.",
+	RunE: runDoctor,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "12",
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	checks := runDoctorChecks(cmd.Context())
+
+	if isStructuredOutput() {
+		if err := printOutput(cmd, checks); err != nil {
+			return err
+		}
+	} else {
+		printDoctorChecks(cmd, checks)
+	}
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == checkFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+// runDoctorChecks runs all checks in order.
+// Checks that need the server are skipped if it is unreachable, since they would all fail for the same reason.
+func runDoctorChecks(ctx context.Context) []doctorCheck {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	checks := []doctorCheck{checkCLIConfig()}
+
+	serverVersion, registry := checkRegistry(ctx)
+	checks = append(checks, registry)
+	if registry.Status == checkFail {
+		for _, name := range []string{"version", "database", "server mode", "clock", "authentication", "servers", "tool groups"} {
+			checks = append(checks, doctorCheck{Name: name, Status: checkSkip, Message: "the mcpjungle server is unreachable"})
+		}
+		return checks
+	}
+	checks = append(checks, checkVersion(serverVersion))
+
+	readiness, database := checkDatabase(ctx)
+	checks = append(checks, database)
+	checks = append(checks, checkServerMode(readiness), checkClock(readiness, time.Now()))
+
+	if readiness != nil && readiness.Ready && !readiness.Initialized {
+		// nothing but initialization works on an uninitialized server
+		for _, name := range []string{"authentication", "servers", "tool groups"} {
+			checks = append(checks, doctorCheck{Name: name, Status: checkSkip, Message: "the mcpjungle server is not initialized"})
+		}
+		return checks
+	}
+
+	return append(checks, checkAuthentication(ctx, readiness), checkServers(ctx), checkToolGroups(ctx))
+}
+
+func checkCLIConfig() doctorCheck {
+	c := doctorCheck{Name: "cli configuration"}
+
+	path, err := config.FilePath()
+	if err != nil {
+		c.Status, c.Message = checkFail, err.Error()
+		return c
+	}
+	f, err := config.LoadFile()
+	if err != nil {
+		c.Status, c.Message = checkFail, err.Error()
+		c.Hint = fmt.Sprintf("fix or remove the configuration file at %s", path)
+		return c
+	}
+
+	c.Status = checkPass
+	switch {
+	case activeSettings != nil && activeSettings.ContextName != "":
+		c.Message = fmt.Sprintf("using context %s from %s", activeSettings.ContextName, path)
+	case len(f.Contexts) == 0:
+		c.Message = "no contexts configured, using flags, env vars and defaults"
+	default:
+		c.Message = fmt.Sprintf("loaded %s", path)
+	}
+	return c
+}
+
+func checkRegistry(ctx context.Context) (*types.ServerVersion, doctorCheck) {
+	c := doctorCheck{Name: "registry"}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorRequestTimeout)
+	defer cancel()
+
+	v, err := apiClient.GetServerVersion(ctx)
+	if err != nil {
+		c.Status = checkFail
+		c.Message, c.Hint = describeDoctorError(err)
+		return nil, c
+	}
+
+	msg := fmt.Sprintf("reachable at %s", apiClient.BaseURL())
+	if activeSettings != nil {
+		msg += fmt.Sprintf(" (from %s)", activeSettings.RegistryURLSource)
+	}
+	c.Status, c.Message = checkPass, msg
+	return v, c
+}
+
+func checkVersion(serverVersion *types.ServerVersion) doctorCheck {
+	c := doctorCheck{
+		Name:    "version",
+		Status:  checkPass,
+		Message: fmt.Sprintf("client %s, server %s", version.GetVersion(), serverVersion.Version),
+	}
+	if warning := checkVersionCompatibility(version.GetVersion(), serverVersion); warning != "" {
+		c.Status, c.Message = checkWarn, warning
+		c.Hint = "upgrade the outdated side so that the CLI and the server run compatible versions"
+	}
+	return c
+}
+
+func checkDatabase(ctx context.Context) (*types.ServerReadiness, doctorCheck) {
+	c := doctorCheck{Name: "database"}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorRequestTimeout)
+	defer cancel()
+
+	r, err := apiClient.GetServerReadiness(ctx)
+	if errors.Is(err, client.ErrNotFound) {
+		c.Status, c.Message = checkSkip, "the server is too old to report its readiness"
+		return nil, c
+	}
+	if err != nil {
+		c.Status = checkFail
+		c.Message, c.Hint = describeDoctorError(err)
+		return nil, c
+	}
+	if r.ShuttingDown {
+		c.Status, c.Message = checkFail, "the server is shutting down"
+		c.Hint = "wait for the server to restart, or point the CLI to another instance with --registry"
+		return r, c
+	}
+	if !r.Ready {
+		c.Status, c.Message = checkFail, "the server cannot query its database"
+		// the server only logs the error, older servers reported it
+		if r.Database != types.DatabaseUnavailable {
+			c.Message += ": " + r.Database
+		}
+		c.Hint = "check that the database is running and that DATABASE_URL is set correctly on the server, " +
+			"the server's logs tell why it can't be queried"
+		return r, c
+	}
+
+	c.Status, c.Message = checkPass, "the server's database is reachable"
+	return r, c
+}
+
+func checkServerMode(r *types.ServerReadiness) doctorCheck {
+	c := doctorCheck{Name: "server mode"}
+	if r == nil || !r.Ready {
+		c.Status, c.Message = checkSkip, "the server did not report its mode"
+		return c
+	}
+	if !r.Initialized {
+		c.Status, c.Message = checkFail, "the mcpjungle server has not been initialized yet"
+		c.Hint = "run `mcpjungle init-server` to initialize it"
+		return c
+	}
+
+	c.Status, c.Message = checkPass, fmt.Sprintf("the server runs in %s mode", r.Mode)
+	if model.ServerMode(r.Mode) == model.ModeDev && activeSettings != nil && activeSettings.AccessToken != "" {
+		c.Status = checkWarn
+		c.Message += ", but an access token is configured in the CLI, which is ignored in this mode"
+		c.Hint = "if enterprise mode was intended, start the server with `mcpjungle start --enterprise` on a fresh database"
+	}
+	return c
+}
+
+func checkClock(r *types.ServerReadiness, now time.Time) doctorCheck {
+	c := doctorCheck{Name: "clock"}
+	if r == nil || r.Time.IsZero() {
+		c.Status, c.Message = checkSkip, "the server did not report its time"
+		return c
+	}
+
+	skew := now.Sub(r.Time)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew {
+		c.Status = checkWarn
+		c.Message = fmt.Sprintf("the clocks of this machine and the server differ by %s", skew.Round(time.Second))
+		c.Hint = "synchronize both clocks using NTP, time-sensitive features like token expiry depend on it"
+		return c
+	}
+	c.Status, c.Message = checkPass, "the clocks of this machine and the server are in sync"
+	return c
+}
+
+func checkAuthentication(ctx context.Context, r *types.ServerReadiness) doctorCheck {
+	c := doctorCheck{Name: "authentication"}
+	if r != nil && model.ServerMode(r.Mode) == model.ModeDev {
+		c.Status, c.Message = checkPass, "not required in development mode"
+		return c
+	}
+
+	token := ""
+	if activeSettings != nil {
+		token = activeSettings.AccessToken
+	}
+	if token == "" {
+		c.Status, c.Message = checkFail, "no access token is configured"
+		c.Hint = "run `mcpjungle login` to authenticate with the mcpjungle server"
+		return c
+	}
+
+	u, err := apiClient.WhoamiContext(ctx, token)
+	if err != nil {
+		c.Status = checkFail
+		c.Message, c.Hint = describeDoctorError(err)
+		return c
+	}
+	c.Status = checkPass
+	c.Message = fmt.Sprintf("authenticated as %s (role: %s, token from %s)", u.Username, u.Role, activeSettings.AccessTokenSource)
+	return c
+}
+
+func checkServers(ctx context.Context) doctorCheck {
+	c := doctorCheck{Name: "servers"}
+	health, err := apiClient.GetServersHealth(ctx, false)
+	if errors.Is(err, client.ErrNotFound) {
+		// servers older than the health endpoint don't keep track of the health of upstream servers
+		servers, err := apiClient.ListServersContext(ctx)
+		if err != nil {
+			c.Status = checkFail
+			c.Message, c.Hint = describeDoctorError(err)
+			return c
+		}
+		c.Status, c.Message = checkPass, fmt.Sprintf("%d MCP servers registered", len(servers))
+		return c
+	}
+	if err != nil {
+		c.Status = checkFail
+		c.Message, c.Hint = describeDoctorError(err)
+		return c
+	}
+
+	var unhealthy []string
+	for _, s := range health.Servers {
+		if s.Status == types.ServerUnhealthy {
+			unhealthy = append(unhealthy, s.Name)
+		}
+	}
+	c.Message = fmt.Sprintf("%d MCP servers registered", len(health.Servers))
+	switch health.Status {
+	case types.HealthCritical:
+		c.Status = checkFail
+	case types.HealthDegraded:
+		c.Status = checkWarn
+	default:
+		c.Status = checkPass
+		return c
+	}
+	c.Message += fmt.Sprintf(", unhealthy: %s", strings.Join(unhealthy, ", "))
+	c.Hint = "run `mcpjungle status` to see why their last health check failed"
+	return c
+}
+
+func checkToolGroups(ctx context.Context) doctorCheck {
+	c := doctorCheck{Name: "tool groups"}
+
+	groups, err := apiClient.ListToolGroupsContext(ctx)
+	if errors.Is(err, client.ErrForbidden) {
+		c.Status, c.Message = checkSkip, "listing tool groups requires the admin role"
+		return c
+	}
+	if err != nil {
+		c.Status = checkFail
+		c.Message, c.Hint = describeDoctorError(err)
+		return c
+	}
+	tools, err := apiClient.ListToolsContext(ctx, "")
+	if err != nil {
+		c.Status = checkFail
+		c.Message, c.Hint = describeDoctorError(err)
+		return c
+	}
+
+	unresolved := groupsWithUnresolvedTools(groups, tools)
+	if len(unresolved) == 0 {
+		c.Status, c.Message = checkPass, fmt.Sprintf("all %d tool groups refer to existing tools", len(groups))
+		return c
+	}
+
+	names := make([]string, 0, len(unresolved))
+	for name := range unresolved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	details := make([]string, len(names))
+	for i, name := range names {
+		details[i] = fmt.Sprintf("%s (%s)", name, strings.Join(unresolved[name], ", "))
+	}
+
+	c.Status = checkWarn
+	c.Message = "some tool groups include tools that don't exist: " + strings.Join(details, "; ")
+	c.Hint = "register the missing servers, or remove the tools from the groups with `mcpjungle update group`"
+	return c
+}
+
+// groupsWithUnresolvedTools returns the tools included by each group that don't exist in mcpjungle, by group name.
+// Groups whose tools all exist are left out.
+func groupsWithUnresolvedTools(groups []types.ToolGroup, tools []*types.Tool) map[string][]string {
+	existing := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		existing[t.Name] = true
+	}
+
+	unresolved := make(map[string][]string)
+	for _, g := range groups {
+		for _, name := range g.IncludedTools {
+			if !existing[name] {
+				unresolved[g.Name] = append(unresolved[g.Name], name)
+			}
+		}
+	}
+	return unresolved
+}
+
+// describeDoctorError returns the message and hint describing a failed request, using the same
+// explanations as the ones printed when a regular command fails.
+func describeDoctorError(err error) (string, string) {
+	h, ok := explainError(err, apiClient.BaseURL(), nil)
+	if !ok {
+		return err.Error(), ""
+	}
+	return h.Message, h.Hint
+}
+
+func printDoctorChecks(cmd *cobra.Command, checks []doctorCheck) {
+	p := newPrinter(cmd)
+	st := newStyler(cmd.OutOrStdout())
+
+	width := 0
+	for _, c := range checks {
+		width = max(width, len(c.Name))
+	}
+
+	counts := map[checkStatus]int{}
+	for _, c := range checks {
+		counts[c.Status]++
+		label := fmt.Sprintf("%-4s", strings.ToUpper(string(c.Status)))
+		p.Resultf("%s  %-*s  %s\n", st.Status(label), width, c.Name, c.Message)
+		if c.Hint != "" {
+			p.Resultf("      %*s  %s\n", width, "", st.Dim("hint: "+c.Hint))
+		}
+	}
+
+	p.Infof(
+		"\n%d passed, %d warnings, %d failed, %d skipped\n",
+		counts[checkPass], counts[checkWarn], counts[checkFail], counts[checkSkip],
+	)
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// fakeDoctorRegistry serves the endpoints queried by the doctor.
+type fakeDoctorRegistry struct {
+	readiness types.ServerReadiness
+	user      string
+	groups    []types.ToolGroup
+	tools     []*types.Tool
+	// health is served by the health endpoint, which doesn't exist if it is nil
+	health *types.ServersHealth
+}
+
+func (f *fakeDoctorRegistry) start(t *testing.T, token string) {
+	t.Helper()
+	writeJSON := func(w http.ResponseWriter, status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/version", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, types.ServerVersion{Version: "v0.0.0-dev"})
+	})
+	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		status := http.StatusOK
+		if !f.readiness.Ready {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, f.readiness)
+	})
+	mux.HandleFunc("/api/v1/users/whoami", func(w http.ResponseWriter, r *http.Request) {
+		if f.user == "" {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid access token"})
+			return
+		}
+		writeJSON(w, http.StatusOK, types.User{Username: f.user, Role: string(types.UserRoleAdmin)})
+	})
+	mux.HandleFunc("/api/v1/servers", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []*types.McpServer{{Name: "github"}})
+	})
+	mux.HandleFunc("/api/v1/servers/health", func(w http.ResponseWriter, r *http.Request) {
+		if f.health == nil {
+			http.NotFound(w, r)
+			return
+		}
+		status := http.StatusOK
+		if f.health.Status == types.HealthCritical {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, f.health)
+	})
+	mux.HandleFunc("/api/v1/tool-groups", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, f.groups)
+	})
+	mux.HandleFunc("/api/v1/tools", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, f.tools)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	origClient, origSettings := apiClient, activeSettings
+	apiClient = client.NewClient(srv.URL, token, srv.Client())
+	activeSettings = &cliSettings{
+		RegistryURL:       srv.URL,
+		RegistryURLSource: settingSourceFlag,
+		AccessToken:       token,
+		AccessTokenSource: settingSourceEnv,
+	}
+	t.Cleanup(func() { apiClient, activeSettings = origClient, origSettings })
+}
+
+func checksByName(checks []doctorCheck) map[string]doctorCheck {
+	m := make(map[string]doctorCheck, len(checks))
+	for _, c := range checks {
+		m[c.Name] = c
+	}
+	return m
+}
+
+func TestDoctorHealthyEnterpriseServer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	f := &fakeDoctorRegistry{
+		readiness: types.ServerReadiness{Ready: true, Database: "ok", Initialized: true, Mode: "enterprise", Time: time.Now()},
+		user:      "admin",
+		groups:    []types.ToolGroup{{Name: "claude", IncludedTools: []string{"github__git_commit"}}},
+		tools:     []*types.Tool{{Name: "github__git_commit"}},
+	}
+	f.start(t, "tok-123")
+
+	cmd := &cobra.Command{}
+	stdout := &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(&bytes.Buffer{})
+
+	err := runDoctor(cmd, nil)
+	testhelpers.AssertNoError(t, err)
+
+	checks := checksByName(runDoctorChecks(context.Background()))
+	for _, name := range []string{"cli configuration", "registry", "database", "server mode", "clock", "authentication", "servers", "tool groups"} {
+		testhelpers.AssertEqual(t, checkPass, checks[name].Status)
+	}
+	testhelpers.AssertStringContains(t, checks["authentication"].Message, "authenticated as admin")
+	testhelpers.AssertStringContains(t, stdout.String(), "PASS")
+}
+
+func TestDoctorReportsProblems(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	f := &fakeDoctorRegistry{
+		readiness: types.ServerReadiness{Ready: true, Database: "ok", Initialized: true, Mode: "enterprise", Time: time.Now().Add(-time.Hour)},
+		groups:    []types.ToolGroup{{Name: "claude", IncludedTools: []string{"github__git_commit", "slack__post"}}},
+		tools:     []*types.Tool{{Name: "github__git_commit"}},
+		health: &types.ServersHealth{Status: types.HealthDegraded, Servers: []types.ServerHealth{
+			{Name: "github", Status: types.ServerHealthy},
+			{Name: "slack", Status: types.ServerUnhealthy, ConsecutiveFailures: 3, Error: "connection refused"},
+		}},
+	}
+	f.start(t, "expired-token")
+
+	checks := checksByName(runDoctorChecks(context.Background()))
+	testhelpers.AssertEqual(t, checkWarn, checks["clock"].Status)
+	testhelpers.AssertEqual(t, checkFail, checks["authentication"].Status)
+	testhelpers.AssertStringContains(t, checks["authentication"].Hint, "mcpjungle login")
+	testhelpers.AssertEqual(t, checkWarn, checks["tool groups"].Status)
+	testhelpers.AssertStringContains(t, checks["tool groups"].Message, "claude (slack__post)")
+	testhelpers.AssertEqual(t, checkWarn, checks["servers"].Status)
+	testhelpers.AssertStringContains(t, checks["servers"].Message, "unhealthy: slack")
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	err := runDoctor(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitError, ExitCodeForError(err))
+}
+
+func TestDoctorDevModeWithAccessToken(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	f := &fakeDoctorRegistry{
+		readiness: types.ServerReadiness{Ready: true, Database: "ok", Initialized: true, Mode: "development", Time: time.Now()},
+	}
+	f.start(t, "tok-123")
+
+	checks := checksByName(runDoctorChecks(context.Background()))
+	testhelpers.AssertEqual(t, checkWarn, checks["server mode"].Status)
+	testhelpers.AssertEqual(t, checkPass, checks["authentication"].Status)
+}
+
+func TestDoctorDatabaseUnavailable(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	f := &fakeDoctorRegistry{readiness: types.ServerReadiness{Ready: false, Database: "connection refused"}}
+	f.start(t, "")
+
+	checks := checksByName(runDoctorChecks(context.Background()))
+	testhelpers.AssertEqual(t, checkFail, checks["database"].Status)
+	testhelpers.AssertStringContains(t, checks["database"].Message, "connection refused")
+	testhelpers.AssertEqual(t, checkSkip, checks["server mode"].Status)
+}
+
+func TestDoctorUnreachableRegistry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	origClient, origSettings := apiClient, activeSettings
+	apiClient, activeSettings = client.NewClient(srv.URL, "", http.DefaultClient), nil
+	t.Cleanup(func() { apiClient, activeSettings = origClient, origSettings })
+
+	checks := runDoctorChecks(context.Background())
+	byName := checksByName(checks)
+	testhelpers.AssertEqual(t, checkPass, byName["cli configuration"].Status)
+	testhelpers.AssertEqual(t, checkFail, byName["registry"].Status)
+	testhelpers.AssertStringContains(t, byName["registry"].Hint, "mcpjungle start")
+	testhelpers.AssertEqual(t, checkSkip, byName["tool groups"].Status)
+}
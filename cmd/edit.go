@@ -0,0 +1,458 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// redactedSecret is shown in place of secret values in the editor.
+// A secret that still holds this placeholder when the file is saved keeps its current value.
+const redactedSecret = "[REDACTED]"
+
+var editCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit resources in your text editor",
+	Long: "Edit the configuration of a resource in your text editor.\n" +
+		"The current configuration is opened as YAML in the editor set by the VISUAL or EDITOR env var " +
+		"(vi by default). When you save and close the file, the changes are validated, shown as a diff and applied.\n" +
+		"If the new configuration is invalid, the editor is opened again with the error at the top of the file, " +
+		"so that you can fix it without losing your changes. Saving an empty file cancels the edit.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "13",
+	},
+}
+
+var editToolGroupCmd = &cobra.Command{
+	Use:   "group [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Edit a tool group",
+	Long: "Edit the configuration of a Tool Group in your text editor.\n" +
+		"Like `update group`, the edited configuration completely overrides the existing one.",
+	RunE: runEditGroup,
+}
+
+var editServerCmd = &cobra.Command{
+	Use:   "server [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Edit an MCP server",
+	Long: "Edit the configuration of a registered MCP server in your text editor.\n" +
+		"Secrets (the bearer token and the values of env vars) are shown as " + redactedSecret + ". " +
+		"They keep their current value unless you replace the placeholder.\n" +
+		"Once the new configuration is saved, mcpjungle reconnects to the server and registers its tools and prompts again.",
+	RunE: runEditServer,
+}
+
+func init() {
+	editCmd.AddCommand(editToolGroupCmd)
+	editCmd.AddCommand(editServerCmd)
+	rootCmd.AddCommand(editCmd)
+}
+
+// launchEditor opens the file at path in the user's editor and waits for it to be closed.
+// It is a variable so that tests can simulate the user's edits.
+var launchEditor = func(path string) error {
+	editor := editorCommand()
+	c := exec.Command(editor[0], append(editor[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %s: %w", strings.Join(editor, " "), err)
+	}
+	return nil
+}
+
+// editorCommand returns the command, and its arguments, that opens the user's editor.
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// editSession describes the configuration of an entity being edited.
+type editSession[T any] struct {
+	// kind and name identify the entity, eg- "tool group" and "claude"
+	kind string
+	name string
+	// notes are extra lines of explanation added to the header of the file
+	notes []string
+
+	original T
+	// validate checks the edited configuration before it is applied.
+	// It may also complete it, eg- by restoring redacted secrets.
+	validate func(edited *T) error
+	// apply submits the edited configuration
+	apply func(edited *T) error
+}
+
+// runEditSession opens the configuration in the user's editor and applies it once it is saved.
+// Invalid configurations, including those rejected by the server as bad requests, re-open the editor with the error
+// embedded as comments. If the user saves the same invalid configuration twice, the edit is cancelled and the file
+// with their changes is kept so that nothing is lost.
+func runEditSession[T any](cmd *cobra.Command, s editSession[T]) error {
+	p := newPrinter(cmd)
+
+	original, err := marshalEditableYAML(s.original)
+	if err != nil {
+		return fmt.Errorf("failed to prepare the configuration of %s %s for editing: %w", s.kind, s.name, err)
+	}
+
+	f, err := os.CreateTemp("", "mcpjungle-edit-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary file for editing: %w", err)
+	}
+	path := f.Name()
+	_ = f.Close()
+	keepFile := false
+	defer func() {
+		if !keepFile {
+			_ = os.Remove(path)
+		}
+	}()
+
+	body := original
+	var lastInvalid string
+	var lastErr error
+	for {
+		content := editFileHeader(s.kind, s.name, s.notes, lastErr) + body
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			return fmt.Errorf("failed to write the configuration to %s: %w", path, err)
+		}
+		if err := launchEditor(path); err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read the edited configuration from %s: %w", path, err)
+		}
+
+		body = stripEditComments(string(data))
+		if strings.TrimSpace(body) == "" {
+			p.Infoln("Edit cancelled, no changes made.")
+			return nil
+		}
+		if strings.TrimSpace(body) == strings.TrimSpace(original) {
+			p.Infof("No changes made to %s %s.\n", s.kind, s.name)
+			return nil
+		}
+		if lastErr != nil && body == lastInvalid {
+			keepFile = true
+			return fmt.Errorf("edit cancelled, the configuration is still invalid (your changes were saved to %s): %w", path, lastErr)
+		}
+
+		edited, err := decodeEditedConfig[T](s.kind, body)
+		if err == nil {
+			err = s.validate(&edited)
+		}
+		if err == nil {
+			printEditDiff(cmd, original, body)
+			err = s.apply(&edited)
+			if err == nil {
+				return nil
+			}
+			if !errors.Is(err, client.ErrValidationFailed) && !errors.Is(err, client.ErrInvalidRequest) {
+				keepFile = true
+				return fmt.Errorf("%w\nYour changes were saved to %s", err, path)
+			}
+		}
+
+		p.Warnf("The configuration is invalid and the editor will be reopened: %v\n", err)
+		lastErr, lastInvalid = err, body
+	}
+}
+
+// editFileHeader returns the comments written at the top of the file opened in the editor.
+func editFileHeader(kind, name string, notes []string, err error) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Please edit the configuration of %s %s below.\n", kind, name)
+	b.WriteString("# Lines beginning with '#' are ignored, and an empty file cancels the edit.\n")
+	for _, note := range notes {
+		b.WriteString("# " + note + "\n")
+	}
+	if err != nil {
+		b.WriteString("#\n")
+		for _, line := range strings.Split(err.Error(), "\n") {
+			b.WriteString("# error: " + line + "\n")
+		}
+	}
+	b.WriteString("#\n")
+	return b.String()
+}
+
+// stripEditComments removes the lines starting with '#', ie, the header written by editFileHeader.
+// Indented comments are left alone, they are ignored by the YAML parser anyway.
+func stripEditComments(content string) string {
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+// marshalEditableYAML encodes v as block-style YAML.
+// v is encoded to JSON first so that the YAML uses the same field names, in the same order, as the JSON configuration.
+func marshalEditableYAML(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return "", err
+	}
+	clearYAMLStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// clearYAMLStyle switches a node parsed from JSON, and all its children, to the default YAML style.
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
+
+// decodeEditedConfig strictly decodes a single entity from the edited YAML: unknown fields are rejected so that typos
+// don't go unnoticed.
+func decodeEditedConfig[T any](kind, body string) (T, error) {
+	var entity T
+
+	var doc any
+	if err := yaml.Unmarshal([]byte(body), &doc); err != nil {
+		return entity, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if _, ok := doc.(map[string]any); !ok {
+		return entity, fmt.Errorf("the configuration must describe a single %s", kind)
+	}
+
+	// round-trip through JSON so that the entity's json tags apply
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return entity, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&entity); err != nil {
+		return entity, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return entity, nil
+}
+
+// printEditDiff shows the lines removed from and added to the configuration.
+func printEditDiff(cmd *cobra.Command, before, after string) {
+	p := newPrinter(cmd)
+	st := newStyler(cmd.ErrOrStderr())
+	for _, line := range diffLines(splitFrameLines([]byte(before)), splitFrameLines([]byte(after))) {
+		switch line[0] {
+		case '-':
+			p.Infoln(st.Red(line))
+		case '+':
+			p.Infoln(st.Green(line))
+		default:
+			p.Infoln(line)
+		}
+	}
+	p.Infoln()
+}
+
+// diffLines returns a line diff of a and b: every line is prefixed with "- " if it was removed, "+ " if it was added
+// and "  " if it is unchanged. It is based on the longest common subsequence, which is plenty for small configurations.
+func diffLines(a, b []string) []string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "- "+a[i])
+			i++
+		default:
+			diff = append(diff, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, "+ "+b[j])
+	}
+	return diff
+}
+
+func runEditGroup(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	group, err := apiClient.GetToolGroupContext(commandContext(cmd), name)
+	if err != nil {
+		return fmt.Errorf("failed to get tool group: %w", err)
+	}
+	original := *group.ToolGroup
+	// the version is managed by the server, it is not editable
+	original.Version = 0
+
+	return runEditSession(cmd, editSession[types.ToolGroup]{
+		kind:     "tool group",
+		name:     name,
+		notes:    []string{"The name of a tool group cannot be changed."},
+		original: original,
+		validate: func(edited *types.ToolGroup) error {
+			if edited.Name != name {
+				return fmt.Errorf("the name of tool group %s cannot be changed", name)
+			}
+			return nil
+		},
+		apply: func(edited *types.ToolGroup) error {
+			return applyToolGroupUpdate(cmd, edited)
+		},
+	})
+}
+
+func runEditServer(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	configs, err := apiClient.GetServerConfigsContext(commandContext(cmd))
+	if err != nil {
+		return fmt.Errorf("failed to get the configuration of MCP server %s: %w", name, err)
+	}
+	var current *types.RegisterServerInput
+	for _, c := range configs {
+		if c.Name == name {
+			current = c
+			break
+		}
+	}
+	if current == nil {
+		return &client.APIError{StatusCode: http.StatusNotFound, Code: types.ErrorCodeNotFound, Message: fmt.Sprintf("MCP server %s does not exist", name)}
+	}
+
+	redacted, err := redactServerSecrets(current)
+	if err != nil {
+		return err
+	}
+	return runEditSession(cmd, editSession[types.RegisterServerInput]{
+		kind: "MCP server",
+		name: name,
+		notes: []string{
+			"The name of an MCP server cannot be changed.",
+			fmt.Sprintf("Secrets are shown as %s, they keep their current value unless you replace it.", redactedSecret),
+		},
+		original: *redacted,
+		validate: func(edited *types.RegisterServerInput) error {
+			if edited.Name != name {
+				return fmt.Errorf("the name of MCP server %s cannot be changed", name)
+			}
+			if _, err := types.ValidateTransport(edited.Transport); err != nil {
+				return err
+			}
+			if _, err := types.ValidateSessionMode(edited.SessionMode); err != nil {
+				return err
+			}
+			return restoreServerSecrets(edited, current)
+		},
+		apply: func(edited *types.RegisterServerInput) error {
+			pr := newPrinter(cmd).Progress(fmt.Sprintf("Updating MCP server %s, validating upstream connectivity", name))
+			updated, err := apiClient.UpdateServerContext(commandContext(cmd), edited)
+			pr.Stop()
+			if err != nil {
+				return fmt.Errorf("failed to update MCP server %s: %w", name, err)
+			}
+			if isStructuredOutput() {
+				return printOutput(cmd, updated)
+			}
+			newPrinter(cmd).Infof("MCP server %s updated successfully\n", name)
+			return nil
+		},
+	})
+}
+
+// redactServerSecrets returns a copy of the server configuration with its secrets replaced by redactedSecret.
+// The secrets are the fields of types.RegisterServerInput.MapSecrets, the ones an export redacts too.
+func redactServerSecrets(conf *types.RegisterServerInput) (*types.RegisterServerInput, error) {
+	// MapSecrets replaces the secrets in place, so it runs on a deep copy of the configuration
+	data, err := json.Marshal(conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy the configuration of MCP server %s: %w", conf.Name, err)
+	}
+	var redacted types.RegisterServerInput
+	if err := json.Unmarshal(data, &redacted); err != nil {
+		return nil, fmt.Errorf("failed to copy the configuration of MCP server %s: %w", conf.Name, err)
+	}
+	redacted.MapSecrets(func(field, value string) string {
+		return redactedSecret
+	})
+	return &redacted, nil
+}
+
+// restoreServerSecrets replaces the secrets of an edited server configuration that still hold the redactedSecret
+// placeholder with their current value, matching them by the name of their field, eg- container.env.API_KEY.
+func restoreServerSecrets(edited, current *types.RegisterServerInput) error {
+	secrets := make(map[string]string)
+	current.MapSecrets(func(field, value string) string {
+		secrets[field] = value
+		return value
+	})
+	var added []string
+	edited.MapSecrets(func(field, value string) string {
+		if value != redactedSecret {
+			return value
+		}
+		secret, ok := secrets[field]
+		if !ok {
+			added = append(added, field)
+			return value
+		}
+		return secret
+	})
+	if len(added) > 0 {
+		return fmt.Errorf(
+			"the secrets %s are new, replace their %s placeholder with a value", strings.Join(added, ", "), redactedSecret,
+		)
+	}
+	return nil
+}
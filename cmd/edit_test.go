@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// withEditor replaces the user's editor with fake edits, one per time the editor is opened.
+// Every edit receives the current content of the file and returns its new content.
+// It returns a pointer to the contents the editor was opened with.
+func withEditor(t *testing.T, edits ...func(content string) string) *[]string {
+	t.Helper()
+	var opened []string
+	orig := launchEditor
+	launchEditor = func(path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		opened = append(opened, string(data))
+		if len(opened) > len(edits) {
+			t.Fatalf("the editor was opened %d times, expected %d", len(opened), len(edits))
+		}
+		return os.WriteFile(path, []byte(edits[len(opened)-1](string(data))), 0o600)
+	}
+	t.Cleanup(func() { launchEditor = orig })
+	return &opened
+}
+
+func newEditTestCmd() (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	stderr := &bytes.Buffer{}
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(stderr)
+	return cmd, stderr
+}
+
+func editTestGroupHandlers(updated *types.ToolGroup) map[string]http.HandlerFunc {
+	current := &types.ToolGroup{Name: "claude", Description: "tools for claude", IncludedTools: []string{"github__git_commit"}}
+	return map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups/claude": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, types.GetToolGroupResponse{ToolGroup: current, ToolGroupEndpoints: &types.ToolGroupEndpoints{}})
+		},
+		"PUT /api/v1/tool-groups/claude": func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(updated)
+			writeTestJSON(w, http.StatusOK, types.UpdateToolGroupResponse{Name: "claude", Old: current, New: updated})
+		},
+	}
+}
+
+func TestMarshalEditableYAML(t *testing.T) {
+	group := types.ToolGroup{Name: "claude", IncludedTools: []string{"github__git_commit", "true"}, Description: "123"}
+	out, err := marshalEditableYAML(group)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(
+		t,
+		"name: claude\nincluded_tools:\n  - github__git_commit\n  - \"true\"\ndescription: \"123\"\n",
+		out,
+	)
+
+	decoded, err := decodeEditedConfig[types.ToolGroup]("tool group", out)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "true", decoded.IncludedTools[1])
+	testhelpers.AssertEqual(t, "123", decoded.Description)
+}
+
+func TestDecodeEditedConfig(t *testing.T) {
+	_, err := decodeEditedConfig[types.ToolGroup]("tool group", "name: claude\nincluded_tool: [a]\n")
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), `unknown field "included_tool"`)
+
+	_, err = decodeEditedConfig[types.ToolGroup]("tool group", "- name: claude\n")
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "single tool group")
+}
+
+func TestDiffLines(t *testing.T) {
+	diff := diffLines(
+		[]string{"name: claude", "description: old", "included_tools:", "  - a"},
+		[]string{"name: claude", "description: new", "included_tools:", "  - a", "  - b"},
+	)
+	testhelpers.AssertEqual(
+		t,
+		"  name: claude\n- description: old\n+ description: new\n  included_tools:\n    - a\n+   - b",
+		strings.Join(diff, "\n"),
+	)
+}
+
+func TestEditGroup(t *testing.T) {
+	var updated types.ToolGroup
+	withRegistryHandlers(t, editTestGroupHandlers(&updated))
+	opened := withEditor(t, func(content string) string {
+		return strings.Replace(content, "tools for claude", "tools for claude desktop", 1)
+	})
+
+	cmd, stderr := newEditTestCmd()
+	testhelpers.AssertNoError(t, runEditGroup(cmd, []string{"claude"}))
+
+	testhelpers.AssertStringContains(t, (*opened)[0], "# Please edit the configuration of tool group claude")
+	testhelpers.AssertEqual(t, "tools for claude desktop", updated.Description)
+	testhelpers.AssertEqual(t, "github__git_commit", updated.IncludedTools[0])
+	testhelpers.AssertStringContains(t, stderr.String(), "- description: tools for claude\n+ description: tools for claude desktop")
+	testhelpers.AssertStringContains(t, stderr.String(), "Tool Group claude updated successfully")
+}
+
+func TestEditGroupReopensOnInvalidConfig(t *testing.T) {
+	var updated types.ToolGroup
+	withRegistryHandlers(t, editTestGroupHandlers(&updated))
+	opened := withEditor(t,
+		func(content string) string {
+			return strings.Replace(content, "included_tools:", "included_tool:", 1)
+		},
+		func(content string) string {
+			return strings.Replace(content, "included_tool:", "included_tools:", 1) + "excluded_tools:\n  - github__git_push\n"
+		},
+	)
+
+	cmd, _ := newEditTestCmd()
+	testhelpers.AssertNoError(t, runEditGroup(cmd, []string{"claude"}))
+
+	// the user's edits are kept and the error is shown as a comment
+	testhelpers.AssertStringContains(t, (*opened)[1], `# error: invalid configuration: json: unknown field "included_tool"`)
+	testhelpers.AssertStringContains(t, (*opened)[1], "included_tool:")
+	testhelpers.AssertEqual(t, "github__git_push", updated.ExcludedTools[0])
+}
+
+func TestEditGroupCancelled(t *testing.T) {
+	var updated types.ToolGroup
+	withRegistryHandlers(t, editTestGroupHandlers(&updated))
+
+	t.Run("empty file", func(t *testing.T) {
+		withEditor(t, func(content string) string { return "" })
+		cmd, stderr := newEditTestCmd()
+		testhelpers.AssertNoError(t, runEditGroup(cmd, []string{"claude"}))
+		testhelpers.AssertStringContains(t, stderr.String(), "Edit cancelled")
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		withEditor(t, func(content string) string { return content })
+		cmd, stderr := newEditTestCmd()
+		testhelpers.AssertNoError(t, runEditGroup(cmd, []string{"claude"}))
+		testhelpers.AssertStringContains(t, stderr.String(), "No changes made to tool group claude")
+	})
+
+	t.Run("invalid config saved twice", func(t *testing.T) {
+		rename := func(content string) string { return strings.Replace(content, "name: claude", "name: cursor", 1) }
+		withEditor(t, rename, func(content string) string { return content })
+		cmd, _ := newEditTestCmd()
+		err := runEditGroup(cmd, []string{"claude"})
+		testhelpers.AssertError(t, err)
+		testhelpers.AssertStringContains(t, err.Error(), "cannot be changed")
+	})
+
+	testhelpers.AssertEqual(t, "", updated.Name)
+}
+
+func TestEditServerKeepsRedactedSecrets(t *testing.T) {
+	var updated types.RegisterServerInput
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.RegisterServerInput{{
+				Name:        "github",
+				Transport:   "streamable_http",
+				URL:         "https://api.githubcopilot.com/mcp/",
+				BearerToken: "ghp_secret",
+			}})
+		},
+		"PUT /api/v1/servers/github": func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+			writeTestJSON(w, http.StatusOK, types.McpServer{Name: updated.Name, Transport: updated.Transport, URL: updated.URL})
+		},
+	})
+	opened := withEditor(t, func(content string) string {
+		return strings.Replace(content, "https://api.githubcopilot.com/mcp/", "https://api.githubcopilot.com/mcp/v2/", 1)
+	})
+
+	cmd, stderr := newEditTestCmd()
+	testhelpers.AssertNoError(t, runEditServer(cmd, []string{"github"}))
+
+	testhelpers.AssertStringNotContains(t, (*opened)[0], "ghp_secret")
+	testhelpers.AssertStringContains(t, (*opened)[0], "bearer_token: '[REDACTED]'")
+	testhelpers.AssertEqual(t, "ghp_secret", updated.BearerToken)
+	testhelpers.AssertEqual(t, "https://api.githubcopilot.com/mcp/v2/", updated.URL)
+	testhelpers.AssertStringNotContains(t, stderr.String(), "ghp_secret")
+}
+
+func TestEditServerRedactsContainerEnv(t *testing.T) {
+	var updated types.RegisterServerInput
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.RegisterServerInput{{
+				Name:      "crm",
+				Transport: "streamable_http",
+				URL:       "http://localhost:8080/mcp",
+				Container: &types.ContainerConfig{Image: "crm:latest", Env: map[string]string{"API_KEY": "crm_secret"}},
+			}})
+		},
+		"PUT /api/v1/servers/crm": func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+			writeTestJSON(w, http.StatusOK, types.McpServer{Name: updated.Name, Transport: updated.Transport, URL: updated.URL})
+		},
+	})
+	opened := withEditor(t, func(content string) string {
+		return strings.Replace(content, "http://localhost:8080/mcp", "http://localhost:9090/mcp", 1)
+	})
+
+	cmd, _ := newEditTestCmd()
+	testhelpers.AssertNoError(t, runEditServer(cmd, []string{"crm"}))
+
+	testhelpers.AssertStringNotContains(t, (*opened)[0], "crm_secret")
+	testhelpers.AssertStringContains(t, (*opened)[0], "API_KEY: '[REDACTED]'")
+	testhelpers.AssertEqual(t, "crm_secret", updated.Container.Env["API_KEY"])
+	testhelpers.AssertEqual(t, "http://localhost:9090/mcp", updated.URL)
+}
+
+func TestEditServerNotFound(t *testing.T) {
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.RegisterServerInput{})
+		},
+	})
+	withEditor(t)
+
+	cmd, _ := newEditTestCmd()
+	err := runEditServer(cmd, []string{"github"})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitNotFound, ExitCodeForError(err))
+}
+
+func TestRestoreServerSecrets(t *testing.T) {
+	current := &types.RegisterServerInput{Env: map[string]string{"API_KEY": "secret"}}
+
+	edited := &types.RegisterServerInput{Env: map[string]string{"API_KEY": redactedSecret, "DEBUG": "1"}}
+	testhelpers.AssertNoError(t, restoreServerSecrets(edited, current))
+	testhelpers.AssertEqual(t, "secret", edited.Env["API_KEY"])
+	testhelpers.AssertEqual(t, "1", edited.Env["DEBUG"])
+
+	edited = &types.RegisterServerInput{Env: map[string]string{"NEW_KEY": redactedSecret}}
+	err := restoreServerSecrets(edited, current)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "env.NEW_KEY")
+}
+
+func TestRedactOpenAPIServerSecrets(t *testing.T) {
+	current := &types.RegisterServerInput{
+		Name: "billing", Transport: string(types.TransportOpenAPI),
+		OpenAPI: &types.OpenAPIConfig{
+			BaseURL: "https://billing.example.com",
+			Auth:    &types.OpenAPIAuth{Type: types.OpenAPIAuthBearer, Value: "secret"},
+		},
+	}
+	redacted, err := redactServerSecrets(current)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, redactedSecret, redacted.OpenAPI.Auth.Value)
+	testhelpers.AssertEqual(t, "secret", current.OpenAPI.Auth.Value)
+
+	testhelpers.AssertNoError(t, restoreServerSecrets(redacted, current))
+	testhelpers.AssertEqual(t, "secret", redacted.OpenAPI.Auth.Value)
+}
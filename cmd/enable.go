@@ -67,10 +67,20 @@ var enableServerCmd = &cobra.Command{
 	RunE: runEnableServer,
 }
 
+var enableWebhookCmd = &cobra.Command{
+	Use:   "webhook [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Enable a webhook",
+	Long: "Enabling a webhook that was disabled automatically because its deliveries kept failing\n" +
+		"resets its failure count.",
+	RunE: runEnableWebhook,
+}
+
 func init() {
 	enableCmd.AddCommand(enableToolsCmd)
 	enableCmd.AddCommand(enablePromptsCmd)
 	enableCmd.AddCommand(enableServerCmd)
+	enableCmd.AddCommand(enableWebhookCmd)
 
 	rootCmd.AddCommand(enableCmd)
 }
@@ -79,11 +89,11 @@ func init() {
 // and redirects to `mcpjungle enable tool [name]`.
 // This is to maintain backward compatibility with older versions of the CLI that only supported enabling tools & servers.
 func runEnable(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+
 	if len(args) == 1 && cmd.CalledAs() == "enable" {
-		cmd.Println(
-			"Warning: 'enable [name]' is deprecated. Please use 'enable tool [name]' or 'enable server [name]' instead.",
-		)
-		cmd.Println()
+		p.Warnf("'enable [name]' is deprecated. Please use 'enable tool [name]' or 'enable server [name]' instead.")
+		p.Infoln()
 		return runEnableTools(cmd, args)
 	}
 	// Otherwise, just show help message
@@ -91,64 +101,79 @@ func runEnable(cmd *cobra.Command, args []string) error {
 }
 
 func runEnableTools(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+
 	name := args[0]
-	toolsEnabled, err := apiClient.EnableTools(name)
+	toolsEnabled, err := apiClient.EnableToolsContext(commandContext(cmd), name)
 	if err != nil {
 		return fmt.Errorf("failed to enable %s: %w", name, err)
 	}
 	if len(toolsEnabled) == 1 {
-		cmd.Printf("MCP tool '%s' enabled successfully!\n", toolsEnabled[0])
+		p.Infof("MCP tool '%s' enabled successfully!\n", toolsEnabled[0])
 		return nil
 	}
-	cmd.Println("Following MCP tools have been enabled successfully:")
+	p.Infoln("Following MCP tools have been enabled successfully:")
 	for _, tool := range toolsEnabled {
-		cmd.Printf("- %s\n", tool)
+		p.Infof("- %s\n", tool)
 	}
 	return nil
 }
 
 func runEnablePrompts(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+
 	name := args[0]
-	promptsEnabled, err := apiClient.EnablePrompts(name)
+	promptsEnabled, err := apiClient.EnablePromptsContext(commandContext(cmd), name)
 	if err != nil {
 		return fmt.Errorf("failed to enable %s: %w", name, err)
 	}
 	if len(promptsEnabled) == 1 {
-		cmd.Printf("MCP prompt '%s' enabled successfully!\n", promptsEnabled[0])
+		p.Infof("MCP prompt '%s' enabled successfully!\n", promptsEnabled[0])
 		return nil
 	}
-	cmd.Println("Following MCP prompts have been enabled successfully:")
+	p.Infoln("Following MCP prompts have been enabled successfully:")
 	for _, prompt := range promptsEnabled {
-		cmd.Printf("- %s\n", prompt)
+		p.Infof("- %s\n", prompt)
 	}
 	return nil
 }
 
 func runEnableServer(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+
 	name := args[0]
-	resp, err := apiClient.EnableServer(name)
+	resp, err := apiClient.EnableServerContext(commandContext(cmd), name)
 	if err != nil {
 		return fmt.Errorf("failed to enable server %s: %w", name, err)
 	}
 
-	cmd.Printf("MCP server '%s' enabled successfully!\n", resp.Name)
+	p.Infof("MCP server '%s' enabled successfully!\n", resp.Name)
 
 	if len(resp.ToolsAffected) > 0 {
-		cmd.Println()
-		cmd.Println("Following MCP tools have been enabled:")
+		p.Infoln()
+		p.Infoln("Following MCP tools have been enabled:")
 		for _, tool := range resp.ToolsAffected {
-			cmd.Printf("    - %s\n", tool)
+			p.Infof("    - %s\n", tool)
 		}
 	}
 
 	if len(resp.PromptsAffected) > 0 {
-		cmd.Println()
-		cmd.Println("Following MCP prompts have been enabled:")
+		p.Infoln()
+		p.Infoln("Following MCP prompts have been enabled:")
 		for _, prompt := range resp.PromptsAffected {
-			cmd.Printf("    - %s\n", prompt)
+			p.Infof("    - %s\n", prompt)
 		}
 	}
 
-	cmd.Println()
+	p.Infoln()
+	return nil
+}
+
+func runEnableWebhook(cmd *cobra.Command, args []string) error {
+	w, err := apiClient.EnableWebhook(commandContext(cmd), args[0])
+	if err != nil {
+		return fmt.Errorf("failed to enable webhook %s: %w", args[0], err)
+	}
+	newPrinter(cmd).Infof("Webhook '%s' enabled successfully!\n", w.Name)
 	return nil
 }
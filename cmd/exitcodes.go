@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the CLI.
+// They are part of the CLI's contract with scripts and automation, so existing codes must never change meaning.
+const (
+	ExitOK         = 0
+	ExitError      = 1
+	ExitUsage      = 2
+	ExitNotFound   = 3
+	ExitAuth       = 4
+	ExitConnection = 5
+	ExitConflict   = 6
+	// ExitInterrupted follows the shell convention of 128 + the number of SIGINT.
+	ExitInterrupted = 130
+)
+
+// exitCodes documents every exit code, it is the source of `mcpjungle help exit-codes`.
+var exitCodes = []struct {
+	code        int
+	description string
+}{
+	{ExitOK, "Success"},
+	{ExitError, "Generic or application error, eg- the server failed to process the request"},
+	{ExitUsage, "Usage error, eg- unknown command, invalid flag or missing argument"},
+	{ExitNotFound, "The requested entity (server, tool, group, etc) does not exist"},
+	{ExitAuth, "Authentication or permission failure, eg- missing access token or insufficient role"},
+	{ExitConnection, "Could not connect to the mcpjungle server, eg- server not running, DNS or TLS failure"},
+	{ExitConflict, "The entity already exists, or a backup is restored into a registry that is not empty"},
+	{ExitInterrupted, "The command was interrupted (eg- with Ctrl-C) before it completed"},
+}
+
+// usageError is returned when a command is invoked incorrectly.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+
+func (e *usageError) Unwrap() error { return e.err }
+
+// usageErrorf formats an error that is reported with the usage exit code.
+func usageErrorf(format string, args ...any) error {
+	return &usageError{err: fmt.Errorf(format, args...)}
+}
+
+// exitCodeError makes the CLI exit with a specific code, for the commands whose exit codes follow their own
+// documented contract, eg- `sync --check`.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string { return e.err.Error() }
+
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+// cobraUsageErrorPrefixes are the prefixes of the untyped errors cobra returns when a command is invoked incorrectly.
+var cobraUsageErrorPrefixes = []string{
+	"unknown command",
+	"unknown flag",
+	"unknown shorthand flag",
+	"flag needs an argument",
+	"invalid argument",
+	"required flag(s)",
+	"accepts ",
+	"requires at least",
+	"requires at most",
+	"if any flags in the group",
+}
+
+// ExitCodeForError returns the exit code the CLI should exit with after a command failed with err.
+// This is the only place errors are mapped to exit codes, commands just return descriptive errors.
+func ExitCodeForError(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var codeErr *exitCodeError
+	if errors.As(err, &codeErr) {
+		return codeErr.code
+	}
+
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case types.ErrorCodeNotFound:
+			return ExitNotFound
+		case types.ErrorCodeUnauthorized, types.ErrorCodeForbidden, types.ErrorCodeNotInitialized, types.ErrorCodeWrongMode:
+			return ExitAuth
+		case types.ErrorCodeAlreadyExists, types.ErrorCodeRegistryNotEmpty:
+			return ExitConflict
+		default:
+			return ExitError
+		}
+	}
+
+	var usageErr *usageError
+	// ErrSilent is returned after a flag parsing error has been printed along with the usage
+	if errors.As(err, &usageErr) || errors.Is(err, ErrSilent) || errors.Is(err, ErrConfirmationRequired) {
+		return ExitUsage
+	}
+	for _, prefix := range cobraUsageErrorPrefixes {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return ExitUsage
+		}
+	}
+
+	// an aborted request is reported as a *url.Error too, so this must be checked first
+	if errors.Is(err, context.Canceled) {
+		return ExitInterrupted
+	}
+	if isConnectionError(err) {
+		return ExitConnection
+	}
+	return ExitError
+}
+
+// isConnectionError reports whether err means that the CLI could not talk to the mcpjungle server at all.
+func isConnectionError(err error) bool {
+	var (
+		urlErr         *url.Error
+		opErr          *net.OpError
+		dnsErr         *net.DNSError
+		unknownAuthErr x509.UnknownAuthorityError
+		hostnameErr    x509.HostnameError
+		verifyErr      *tls.CertificateVerificationError
+		recordErr      tls.RecordHeaderError
+	)
+	// the http client reports every transport failure as a *url.Error
+	return errors.As(err, &urlErr) || errors.As(err, &opErr) || errors.As(err, &dnsErr) ||
+		errors.As(err, &unknownAuthErr) || errors.As(err, &hostnameErr) || errors.As(err, &verifyErr) ||
+		errors.As(err, &recordErr)
+}
+
+var exitCodesHelpCmd = &cobra.Command{
+	Use:   "exit-codes",
+	Short: "Exit codes of the mcpjungle CLI",
+	Long:  exitCodesHelp(),
+}
+
+// exitCodesHelp renders the exit codes table for `mcpjungle help exit-codes`.
+func exitCodesHelp() string {
+	var b strings.Builder
+	b.WriteString("Every mcpjungle command exits with one of the following codes, so that scripts can tell failures apart:\n\n")
+	for _, c := range exitCodes {
+		_, _ = fmt.Fprintf(&b, "  %d  %s\n", c.code, c.description)
+	}
+	_, _ = fmt.Fprintf(
+		&b, "\nExcept `sync --check`, which exits with %d if there are no changes, %d if there are pending changes "+
+			"and %d if it failed.\n", ExitOK, syncCheckExitChanges, syncCheckExitError,
+	)
+	return b.String()
+}
+
+func init() {
+	rootCmd.AddCommand(exitCodesHelpCmd)
+}
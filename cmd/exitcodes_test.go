@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// withFakeRegistry points the global API client to a server that responds to every request with status and body.
+func withFakeRegistry(t *testing.T, status int, body string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	orig := apiClient
+	apiClient = client.NewClient(srv.URL, "", srv.Client())
+	t.Cleanup(func() { apiClient = orig })
+}
+
+// withRegistryHandlers points the global API client to a registry that serves the given handlers.
+func withRegistryHandlers(t *testing.T, handlers map[string]http.HandlerFunc) {
+	t.Helper()
+	mux := http.NewServeMux()
+	for pattern, h := range handlers {
+		mux.HandleFunc(pattern, h)
+	}
+	if bulk, ok := handlers["GET /api/v1/server_configs"]; ok {
+		serveServerConfigsByName(mux, handlers, bulk)
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	orig := apiClient
+	apiClient = client.NewClient(srv.URL, "", srv.Client())
+	t.Cleanup(func() { apiClient = orig })
+}
+
+// serveServerConfigsByName serves the list of the servers and the configuration of every server from the handler
+// of all their configurations, unless the test has its own handlers for them, eg- for the exports to a directory,
+// which fetch the configurations one by one.
+func serveServerConfigsByName(mux *http.ServeMux, handlers map[string]http.HandlerFunc, bulk http.HandlerFunc) {
+	configs := func(w http.ResponseWriter, r *http.Request) ([]*types.RegisterServerInput, bool) {
+		rec := httptest.NewRecorder()
+		bulk(rec, r)
+		if rec.Code != http.StatusOK {
+			for k, v := range rec.Header() {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(rec.Code)
+			_, _ = w.Write(rec.Body.Bytes())
+			return nil, false
+		}
+		var servers []*types.RegisterServerInput
+		_ = json.Unmarshal(rec.Body.Bytes(), &servers)
+		return servers, true
+	}
+	if _, ok := handlers["GET /api/v1/servers"]; !ok {
+		mux.HandleFunc("GET /api/v1/servers", func(w http.ResponseWriter, r *http.Request) {
+			if servers, ok := configs(w, r); ok {
+				list := make([]*types.McpServer, len(servers))
+				for i, s := range servers {
+					// like the registry, the servers can be addressed by their UUID whatever their name
+					list[i] = &types.McpServer{Name: s.Name, UUID: "uuid-" + strconv.Itoa(i)}
+				}
+				writeTestJSON(w, http.StatusOK, list)
+			}
+		})
+	}
+	if _, ok := handlers["GET /api/v1/server_configs/{name}"]; !ok {
+		mux.HandleFunc("GET /api/v1/server_configs/{name}", func(w http.ResponseWriter, r *http.Request) {
+			servers, ok := configs(w, r)
+			if !ok {
+				return
+			}
+			for i, s := range servers {
+				if id := r.PathValue("name"); id == s.Name || id == "uuid-"+strconv.Itoa(i) {
+					writeTestJSON(w, http.StatusOK, s)
+					return
+				}
+			}
+			writeTestJSON(w, http.StatusNotFound, types.ErrorResponse{})
+		})
+	}
+}
+
+// writeTestJSON responds to a request of a fake registry with v encoded as JSON.
+func writeTestJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newExitCodeTestCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	return cmd
+}
+
+func TestExitCodesOfCommands(t *testing.T) {
+	setStdinIsPiped(t, false)
+	origName, origURL := registerCmdServerName, registerCmdServerURL
+	registerCmdServerName, registerCmdServerURL = "github", "https://api.githubcopilot.com/mcp/"
+	t.Cleanup(func() { registerCmdServerName, registerCmdServerURL = origName, origURL })
+
+	testCases := []struct {
+		name   string
+		status int
+		body   string
+		run    func(cmd *cobra.Command, args []string) error
+		args   []string
+		want   int
+	}{
+		{
+			name:   "server failure",
+			status: http.StatusInternalServerError,
+			body:   `{"error":"database is locked"}`,
+			run:    runListServers,
+			want:   ExitError,
+		},
+		{
+			name:   "group not found",
+			status: http.StatusNotFound,
+			body:   `{"error":"record not found"}`,
+			run:    runGetGroup,
+			args:   []string{"missing"},
+			want:   ExitNotFound,
+		},
+		{
+			name:   "not logged in",
+			status: http.StatusUnauthorized,
+			body:   `{"error":"missing access token"}`,
+			run:    runListServers,
+			want:   ExitAuth,
+		},
+		{
+			name:   "not an admin",
+			status: http.StatusForbidden,
+			body:   `{"error":"user is not authorized to perform this action","required_role":"admin"}`,
+			run:    runListUsers,
+			want:   ExitAuth,
+		},
+		{
+			name:   "server already registered",
+			status: http.StatusConflict,
+			body:   `{"error":"failed to register mcp server: duplicated key not allowed"}`,
+			run:    runRegisterMCPServer,
+			want:   ExitConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withFakeRegistry(t, tc.status, tc.body)
+			err := tc.run(newExitCodeTestCmd(), tc.args)
+			testhelpers.AssertError(t, err)
+			testhelpers.AssertEqual(t, tc.want, ExitCodeForError(err))
+		})
+	}
+}
+
+func TestExitCodeConnectionFailure(t *testing.T) {
+	// a server that is closed right away leaves behind a URL nothing listens on
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	orig := apiClient
+	apiClient = client.NewClient(srv.URL, "", http.DefaultClient)
+	t.Cleanup(func() { apiClient = orig })
+
+	err := runListServers(newExitCodeTestCmd(), nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitConnection, ExitCodeForError(err))
+}
+
+func TestExitCodeUsageErrors(t *testing.T) {
+	t.Run("cobra argument validation", func(t *testing.T) {
+		cmd := newExitCodeTestCmd()
+		cmd.Use = "usage"
+		cmd.Args = cobra.ExactArgs(1)
+		cmd.RunE = func(cmd *cobra.Command, args []string) error { return nil }
+		cmd.SetArgs([]string{})
+		cmd.SilenceErrors, cmd.SilenceUsage = true, true
+
+		err := cmd.Execute()
+		testhelpers.AssertError(t, err)
+		testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+	})
+
+	t.Run("conflicting flags", func(t *testing.T) {
+		origServer, origGroup := listToolsCmdServerName, listToolsCmdGroupName
+		listToolsCmdServerName, listToolsCmdGroupName = "github", "claude"
+		t.Cleanup(func() { listToolsCmdServerName, listToolsCmdGroupName = origServer, origGroup })
+
+		err := runListTools(newExitCodeTestCmd(), nil)
+		testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+	})
+
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(ErrSilent))
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(ErrConfirmationRequired))
+}
+
+func TestExitCodeForError(t *testing.T) {
+	testhelpers.AssertEqual(t, ExitOK, ExitCodeForError(nil))
+	testhelpers.AssertEqual(t, ExitError, ExitCodeForError(errors.New("failed to write resource to disk")))
+	testhelpers.AssertEqual(t, ExitError, ExitCodeForError(ErrAborted))
+
+	// the first API error found decides the exit code of a batch of failures
+	joined := errors.Join(errors.New("invalid config"), &client.APIError{StatusCode: http.StatusConflict, Code: types.ErrorCodeAlreadyExists})
+	testhelpers.AssertEqual(t, ExitConflict, ExitCodeForError(joined))
+}
+
+func TestExitCodesHelp(t *testing.T) {
+	help := exitCodesHelp()
+	for _, c := range exitCodes {
+		testhelpers.AssertStringContains(t, help, c.description)
+	}
+	testhelpers.AssertTrue(t, strings.Contains(help, "  6  "), "every code should be listed")
+	testhelpers.AssertTrue(t, exitCodesHelpCmd.IsAdditionalHelpTopicCommand(), "exit-codes should be a help topic")
+}
+
+func TestExitCodeInterrupted(t *testing.T) {
+	received := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	orig := apiClient
+	apiClient = client.NewClient(srv.URL, "", http.DefaultClient)
+	t.Cleanup(func() { apiClient = orig })
+
+	// cancelling the command's context is what Ctrl-C does
+	cmd := newExitCodeTestCmd()
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd.SetContext(ctx)
+	go func() {
+		<-received
+		cancel()
+	}()
+
+	err := runListServers(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitInterrupted, ExitCodeForError(err))
+
+	h, ok := explainError(err, srv.URL, nil)
+	testhelpers.AssertTrue(t, ok, "interruptions should be explained")
+	testhelpers.AssertEqual(t, "the command was interrupted before it completed", h.Message)
+}
@@ -1,12 +1,21 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	clientconfig "github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/mcpjungle/mcpjungle/internal/s3"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/mcpjungle/mcpjungle/pkg/version"
 	"github.com/spf13/cobra"
 )
 
@@ -17,85 +26,267 @@ const (
 	exportToolGroupsDir = "groups"
 )
 
+const (
+	// exportFormatJSON and exportFormatYAML write one JSON or YAML configuration file per entity,
+	// in a directory per kind of entity
+	exportFormatJSON = configFormatJSON
+	exportFormatYAML = configFormatYAML
+	// exportFormatDir is the name exportFormatJSON had before YAML was supported, it is still accepted
+	exportFormatDir = "dir"
+	// exportFormatMCPJSON writes the MCP servers to a single mcp.json file, see newMCPJSONDocument
+	exportFormatMCPJSON = importFormatMCPJSON
+)
+
 var exportCmd = &cobra.Command{
 	Use:   "export",
 	Short: "Export configuration files of all entities",
 	Long: "This command creates configuration files for all entities (mcp servers, groups) that exist in mcpjungle.\n" +
 		"This is useful when you want to track all the entities registered in mcpjungle as code.\n" +
 		fmt.Sprintf("By default, the configurations are exported to a directory named %s in the current working directory.\n\n", defaultExportTargetDir) +
+		"Every entity is written to a file named after it. The names that aren't safe file names, eg- team/api,\n" +
+		"or that only differ by case, get the characters other than letters, digits, -, _ and . replaced and\n" +
+		"a short hash appended, eg- team_api-1c5a9e0b.json. The files hold the names the entities are imported with.\n\n" +
+		"The secrets of the MCP servers, eg- bearer tokens, env vars and webhook headers, are replaced with\n" +
+		"placeholders like ${MCPJUNGLE_SECRET:github/bearer_token}, so that the export can be committed safely.\n" +
+		"`mcpjungle import` and `mcpjungle sync --check` read the secrets a placeholder names from the registry.\n" +
+		"--include-secrets exports them in plaintext instead.\n\n" +
+		"With --s3-url, they are uploaded to an S3-compatible object store instead, as a gzip compressed tar archive\n" +
+		"with the same layout. The URL names the object, {timestamp} in it is replaced with the current time.\n" +
+		"If it ends with a /, the object is named " + defaultBundleObjectName + " under it.\n" +
+		"The credentials are read from " + S3AccessKeyIDEnvVar + " and " + S3SecretAccessKeyEnvVar + " if set,\n" +
+		"otherwise from the default credential chain of AWS (env vars, shared credentials file, ECS task or EC2 role).\n\n" +
+		"The configuration files are written in JSON by default, --format yaml writes them in YAML instead,\n" +
+		"with the same field names, so that they can be registered again with `mcpjungle register -c`.\n\n" +
+		"With --format mcpjson, the MCP servers are exported to a single " + mcpJSONFileName + " file\n" +
+		"in the directory instead, with the mcpServers map read by MCP clients. The configuration the format\n" +
+		"can't express, eg- descriptions, is kept under the " + mcpJSONExtensionKey + " key of every entry,\n" +
+		"which `mcpjungle register` and `mcpjungle import` read back and other clients ignore.\n" +
+		"Tool groups, and the servers running in containers or serving an OpenAPI service or a webhook tool,\n" +
+		"are not exported in this format.\n\n" +
+		"--servers and --groups only export the MCP servers and tool groups whose names match any of their\n" +
+		"comma-separated glob patterns, eg- --servers 'github*,jira'. The other kind of entities is still exported\n" +
+		"in full unless its flag is set too. The patterns that match nothing are reported.\n\n" +
+		"--exclude leaves whole types of entities out of the export, eg- --exclude servers: they aren't fetched,\n" +
+		"and their directory isn't created. The types are " + strings.Join(exportEntityTypes, ", ") + ".\n\n" +
+		"With --archive, the export is written to a gzip compressed tar archive instead of a directory, with the\n" +
+		"same files. An existing archive is only overwritten with --force.\n\n" +
+		"The export directory must be empty, unless --force is set: the files of the exported entities are then\n" +
+		"overwritten, eg- to refresh an export kept in a git repository. The files of the entities that no longer\n" +
+		"exist are kept, --prune removes them too. With --incremental, only the files whose content changed are\n" +
+		"rewritten, so that the others keep their modification time, and the numbers of files updated, unchanged,\n" +
+		"new and removed are printed, eg- to run the export periodically into a git working tree.\n\n" +
+		"The configurations are fetched concurrently, --concurrency at a time, and every file is written as soon as\n" +
+		"its configuration arrives. An entity that can't be exported is reported at the end, with the time the\n" +
+		"export took, and doesn't stop the export of the others.\n\n" +
+		"The directory gets a " + exportManifestFile + " file too, with the time of the export, the version of the\n" +
+		"server, the number of entities exported, the warnings and the checksum of every file, which\n" +
+		"`mcpjungle import --dir` verifies.\n\n" +
+		"In enterprise mode, the users and MCP clients are exported too, to the " + exportUsersDir + " and " +
+		exportClientsDir + "\ndirectories, without their access tokens. They are skipped in development mode.\n\n" +
+		"With --stdout, nothing is written to the filesystem: the export is printed as a single JSON or YAML\n" +
+		"document with the servers and groups arrays, and the messages are written to stderr, eg- to pipe it.\n\n" +
+		"To export a single MCP server or tool group, use `mcpjungle export server` or `mcpjungle export group`.\n\n" +
 		"NOTE: In enterprise mode, you must be an admin to export all configurations successfully.",
 	Annotations: map[string]string{
 		"group": string(subCommandGroupAdvanced),
 		"order": "9",
 	},
+	Example: "  mcpjungle export --dir ./backup\n" +
+		"  mcpjungle export --format yaml --dir ./gitops\n" +
+		"  mcpjungle export --format mcpjson --dir ./project\n" +
+		"  mcpjungle export --servers 'github*,jira' --groups ops\n" +
+		"  mcpjungle export --exclude servers,users\n" +
+		"  mcpjungle export --stdout --format yaml > registry.yaml\n" +
+		"  mcpjungle export --dir ./gitops --force --prune\n" +
+		"  mcpjungle export --dir ./gitops --force --incremental\n" +
+		"  mcpjungle export --archive ./mcpjungle-export.tar.gz --force\n" +
+		"  mcpjungle export --s3-url s3://backups/mcpjungle/ --s3-sse aws:kms\n" +
+		"  mcpjungle export --s3-url s3://backups/nightly-{timestamp}.tar.gz --s3-endpoint http://localhost:9000",
 	RunE: runExport,
 }
 
-var exportCmdTargetDir string
+var (
+	exportCmdTargetDir string
+	exportCmdFormat    string
+	exportCmdStdout    bool
+	exportCmdArchive   string
+	exportCmdForce     bool
+	exportCmdPrune     bool
+	// exportCmdIncremental only rewrites the files whose content changed, see writeExportFile
+	exportCmdIncremental bool
+	// exportCmdConcurrency is how many configurations are fetched at the same time, see runExportJobs
+	exportCmdConcurrency int
+
+	exportCmdRedactSecrets  bool
+	exportCmdIncludeSecrets bool
+	exportCmdS3             s3Flags
+	exportCmdServers        []string
+	exportCmdGroups         []string
+	exportCmdExclude        []string
+)
 
 func init() {
-	exportCmd.Flags().StringVarP(
+	// the subcommands exporting a single entity share these flags, see export_entity.go
+	exportCmd.PersistentFlags().StringVarP(
 		&exportCmdTargetDir,
 		"dir",
 		"d",
 		defaultExportTargetDir,
 		"Directory to export configuration files to",
 	)
+	exportCmd.PersistentFlags().StringVar(
+		&exportCmdFormat,
+		"format",
+		exportFormatJSON,
+		fmt.Sprintf(
+			"Format of the export, one of: %s or %s (a file per entity), %s (a single %s file with the MCP servers)",
+			exportFormatJSON, exportFormatYAML, exportFormatMCPJSON, mcpJSONFileName,
+		),
+	)
+	exportCmd.PersistentFlags().BoolVar(
+		&exportCmdStdout,
+		"stdout",
+		false,
+		"Print the export to stdout as a single document instead of writing files",
+	)
+	exportCmd.PersistentFlags().BoolVar(
+		&exportCmdRedactSecrets,
+		"redact-secrets",
+		true,
+		"Replace the secrets of the MCP servers with placeholders, eg- ${MCPJUNGLE_SECRET:github/bearer_token}",
+	)
+	exportCmd.PersistentFlags().BoolVar(
+		&exportCmdIncludeSecrets,
+		"include-secrets",
+		false,
+		"Export the secrets of the MCP servers in plaintext instead of redacting them",
+	)
+	exportCmd.MarkFlagsMutuallyExclusive("redact-secrets", "include-secrets")
+	exportCmd.Flags().StringVar(
+		&exportCmdArchive,
+		"archive",
+		"",
+		"Write the export to this gzip compressed tar archive instead of a directory, eg- export.tar.gz",
+	)
+	exportCmd.Flags().BoolVar(
+		&exportCmdForce,
+		"force",
+		false,
+		"Export into a non-empty directory, overwriting the files of the exported entities, or overwrite the archive",
+	)
+	exportCmd.Flags().BoolVar(
+		&exportCmdPrune,
+		"prune",
+		false,
+		"With --force, remove the files of the entities that no longer exist from the export directory",
+	)
+	exportCmd.Flags().BoolVar(
+		&exportCmdIncremental,
+		"incremental",
+		false,
+		"With --force, only rewrite the files whose content changed, and summarize the changes",
+	)
+	exportCmd.Flags().IntVar(
+		&exportCmdConcurrency,
+		"concurrency",
+		defaultExportConcurrency,
+		"Number of configurations to fetch at the same time when exporting to a directory",
+	)
+	exportCmd.Flags().StringSliceVar(
+		&exportCmdExclude,
+		"exclude",
+		nil,
+		"Comma-separated types of entities not to export at all, among: "+strings.Join(exportEntityTypes, ", "),
+	)
+	exportCmd.Flags().StringSliceVar(
+		&exportCmdServers,
+		"servers",
+		nil,
+		"Only export the MCP servers whose names match these comma-separated glob patterns, eg- 'github*,jira'",
+	)
+	exportCmd.Flags().StringSliceVar(
+		&exportCmdGroups,
+		"groups",
+		nil,
+		"Only export the tool groups whose names match these comma-separated glob patterns",
+	)
+	addS3Flags(
+		exportCmd.Flags(),
+		&exportCmdS3,
+		"Upload the export to this S3 URL instead of a directory, eg- s3://bucket/prefix/",
+		true,
+	)
+	exportCmd.MarkFlagsMutuallyExclusive("dir", "s3-url")
+	exportCmd.MarkFlagsMutuallyExclusive("format", "s3-url")
+	exportCmd.MarkFlagsMutuallyExclusive("dir", "stdout")
+	exportCmd.MarkFlagsMutuallyExclusive("stdout", "s3-url")
+	exportCmd.MarkFlagsMutuallyExclusive("archive", "dir")
+	exportCmd.MarkFlagsMutuallyExclusive("archive", "stdout")
+	exportCmd.MarkFlagsMutuallyExclusive("archive", "s3-url")
 
 	rootCmd.AddCommand(exportCmd)
 }
 
-// resolveTargetDirForExport determines the target directory for to export the configurations to.
-// The "~" prefix is expanded to home directory, if it exists. The directory is created if it doesn't exist.
-func resolveTargetDirForExport() (string, error) {
+// exportTargetPath returns the absolute path of the directory to export the configurations to.
+// The "~" prefix is expanded to home directory, if it exists.
+func exportTargetPath() (string, error) {
 	// determine target directory (flag overrides default)
 	targetDir := exportCmdTargetDir
 	if targetDir == "" {
 		targetDir = defaultExportTargetDir
 	}
 
-	// expand ~ to user home
-	if strings.HasPrefix(targetDir, "~") {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return "", err
-		}
-		if targetDir == "~" {
-			targetDir = home
-		} else if strings.HasPrefix(targetDir, "~/") {
-			targetDir = filepath.Join(home, targetDir[2:])
-		}
+	// make absolute and clean, with ~ expanded to the user's home
+	absDir, err := filepath.Abs(clientconfig.ExpandHome(targetDir))
+	if err != nil {
+		return "", err
 	}
+	return filepath.Clean(absDir), nil
+}
 
-	// make absolute and clean
-	absDir, err := filepath.Abs(targetDir)
+// resolveTargetDirForExport determines the directory to export the configurations to, see exportTargetPath.
+// The directory is created if it doesn't exist, it must be empty otherwise unless --force is set.
+func resolveTargetDirForExport() (string, error) {
+	targetDir, err := exportTargetPath()
 	if err != nil {
 		return "", err
 	}
-	targetDir = filepath.Clean(absDir)
 
 	// create the directory if it doesn't exist
 	if err := os.MkdirAll(targetDir, 0o755); err != nil {
 		return "", err
 	}
 
-	// ensure the target directory is empty
+	if exportCmdForce {
+		return targetDir, nil
+	}
+
+	// ensure the target directory is empty, but for the manifest of a previous export
 	entries, err := os.ReadDir(targetDir)
 	if err != nil {
 		return "", fmt.Errorf("failed to read contents of target directory %s: %w", targetDir, err)
 	}
+	entries = slices.DeleteFunc(entries, func(e os.DirEntry) bool { return e.Name() == exportManifestFile })
 	if len(entries) > 0 {
-		return "", fmt.Errorf("target directory %s is not empty", targetDir)
+		return "", fmt.Errorf("target directory %s is not empty, use --force to export into it", targetDir)
 	}
 
 	return targetDir, nil
 }
 
-func writeJSONConfigFile(entityDir, entityName string, entity any) error {
-	filename := filepath.Join(entityDir, filepath.Base(entityName)+".json")
-	data, err := json.MarshalIndent(entity, "", "  ")
+// configFileName returns the name of the configuration file of an entity exported on its own, see safeFileName,
+// with the extension of the format.
+func configFileName(entityName, format string) string {
+	return safeFileName(entityName) + "." + format
+}
+
+// writeConfigFile writes the configuration of an entity to the file named fileName in entityDir,
+// in the given format, with its extension. fileName is one of configFileNames.
+func writeConfigFile(entityDir, fileName, format string, entity any) error {
+	filename := filepath.Join(entityDir, fileName+"."+format)
+	data, err := marshalConfigFile(format, entity)
 	if err != nil {
-		return fmt.Errorf("failed to serialize entity %s/%s: %w", entityDir, entityName, err)
+		return fmt.Errorf("failed to serialize entity %s/%s: %w", entityDir, fileName, err)
 	}
 	if err := os.WriteFile(filename, data, 0o644); err != nil {
 		return fmt.Errorf("failed to write entity file %s: %w", filename, err)
@@ -104,61 +295,467 @@ func writeJSONConfigFile(entityDir, entityName string, entity any) error {
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
+	filter, err := newExportFilter()
+	if err != nil {
+		return err
+	}
+	if exportCmdIncremental {
+		if !exportCmdForce {
+			return usageErrorf("--incremental requires --force")
+		}
+		if exportCmdStdout || exportCmdArchive != "" || exportCmdS3.url != "" || exportCmdFormat == exportFormatMCPJSON {
+			return usageErrorf("--incremental only applies to an export to a directory, with the json or yaml format")
+		}
+	}
+	format := exportCmdFormat
+	switch format {
+	case exportFormatJSON, exportFormatDir, "":
+		format = exportFormatJSON
+	case exportFormatYAML:
+	case exportFormatMCPJSON:
+		if exportCmdArchive != "" {
+			return usageErrorf("--archive doesn't support the %s format", exportFormatMCPJSON)
+		}
+		if !filter.includes(exportMcpServersDir) {
+			return usageErrorf("the %s format only exports MCP servers, they can't be excluded", exportFormatMCPJSON)
+		}
+		return runExportMCPJSON(cmd, filter)
+	default:
+		return usageErrorf(
+			"unsupported format '%s', supported formats: %s, %s, %s",
+			exportCmdFormat, exportFormatJSON, exportFormatYAML, exportFormatMCPJSON,
+		)
+	}
+	if exportCmdPrune && !exportCmdForce {
+		return usageErrorf("--prune requires --force")
+	}
+	if exportCmdConcurrency <= 0 {
+		return usageErrorf("--concurrency must be a positive number")
+	}
+	if exportCmdS3.url != "" {
+		return runExportToS3(cmd, filter)
+	}
+	if exportCmdStdout {
+		return runExportToStdout(cmd, format, filter)
+	}
+	if exportCmdArchive != "" {
+		return runExportToArchive(cmd, format, filter)
+	}
+	p := newPrinter(cmd)
+	start := time.Now()
+
 	targetDir, err := resolveTargetDirForExport()
 	if err != nil {
 		return fmt.Errorf("failed to resolve target directory for export: %w", err)
 	}
+	var changes *exportChanges
+	if exportCmdIncremental {
+		changes = &exportChanges{}
+	}
 
-	cmd.Printf("Creating subdirectories inside %s\n\n", targetDir)
+	p.Infof("Creating subdirectories inside %s\n\n", targetDir)
+	res, err := exportToDir(cmd, p, targetDir, format, filter, changes)
+	if err != nil {
+		return err
+	}
 
-	groupsDir := filepath.Join(targetDir, exportToolGroupsDir)
-	if err := os.Mkdir(groupsDir, 0o755); err != nil {
-		return fmt.Errorf("failed to create groups directory: %w", err)
+	if changes != nil {
+		p.Infof("\n%s\n", changes.summary())
 	}
-	serversDir := filepath.Join(targetDir, exportMcpServersDir)
-	if err := os.Mkdir(serversDir, 0o755); err != nil {
-		return fmt.Errorf("failed to create mcp servers directory: %w", err)
+	if len(res.failures) > 0 {
+		p.Infof("\nExport complete in %s, %d of %d entities could not be exported.\n",
+			exportElapsed(start), len(res.failures), res.entities)
+	} else {
+		p.Infof("\nExport complete in %s!\n", exportElapsed(start))
 	}
 
-	cmd.Println("Fetching Tool Group configurations...")
+	return nil
+}
+
+// dirExport is the outcome of an export to a directory.
+type dirExport struct {
+	manifest *exportManifest
+	// entities is the number of tool groups and MCP servers to export, failures are the ones that couldn't be
+	entities int
+	failures []error
+	// incomplete reports whether entities are missing from the export, because they could not be exported or even
+	// listed. The export goes on regardless, the reasons are printed as warnings.
+	incomplete bool
+}
 
-	groups, gErr := apiClient.GetToolGroupConfigs()
-	if gErr != nil {
-		cmd.Printf("warning: failed to fetch tool group configurations: %v\n", gErr)
+// exportToDir exports the configurations the filter selects to targetDir, with the manifest of the export.
+// Every kind of entity the filter includes has its own subdirectory, holding one file per entity.
+// It writes the directory of an export, as well as the content of its archive.
+func exportToDir(
+	cmd *cobra.Command, p *printer, targetDir, format string, filter *exportFilter, changes *exportChanges,
+) (*dirExport, error) {
+	ctx := commandContext(cmd)
+	res := &dirExport{}
+	manifest := &exportManifest{
+		Version:    exportManifestVersion,
+		ExportedAt: nowFunc().UTC(),
+		Registry:   apiClient.BaseURL(),
+		CLIVersion: version.GetVersion(),
+	}
+	if v, err := apiClient.GetServerVersion(ctx); err != nil {
+		p.Warnf("failed to get the version of the mcpjungle server: %v", err)
 	} else {
-		if len(groups) == 0 {
-			cmd.Println("No Tool Groups found.")
-		} else {
-			cmd.Printf("Writing Tool Groups configurations to %s\n", groupsDir)
+		manifest.ServerVersion = v.Version
+	}
+	res.manifest = manifest
 
-			for _, g := range groups {
-				if err := writeJSONConfigFile(groupsDir, g.Name, g); err != nil {
-					return err
+	var jobs []*exportJob
+
+	// with --force, the subdirectories may exist already
+	if !filter.includes(exportToolGroupsDir) {
+		p.Infoln("Skipping Tool Groups, excluded by --exclude.")
+	} else {
+		groupsDir := filepath.Join(targetDir, exportToolGroupsDir)
+		if err := os.MkdirAll(groupsDir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create groups directory: %w", err)
+		}
+
+		p.Infoln("Fetching Tool Group configurations...")
+
+		// the list of the groups holds their configurations already
+		groups, gErr := apiClient.GetToolGroupConfigsContext(ctx)
+		if gErr != nil {
+			p.Warnf("failed to fetch tool group configurations: %v", gErr)
+			res.incomplete = true
+		} else {
+			// the names of the files depend on the names of all the groups, so that filtering doesn't change them
+			fileNames, err := configFileNames(groups, func(g types.ToolGroup) string { return g.Name })
+			if err != nil {
+				return nil, err
+			}
+			existing := slices.Collect(maps.Values(fileNames))
+			groups = filter.filterGroups(p, groups)
+			if exportCmdPrune || changes != nil {
+				exported := make([]string, 0, len(groups))
+				for _, g := range groups {
+					exported = append(exported, fileNames[g.Name])
+				}
+				if err := pruneExportDir(p, groupsDir, format, exported, existing, changes); err != nil {
+					return nil, err
 				}
 			}
+			if len(groups) == 0 {
+				p.Infoln("No Tool Groups found.")
+			}
+			for _, g := range groups {
+				jobs = append(jobs, &exportJob{
+					entity: "tool group " + g.Name, dir: exportToolGroupsDir, fileName: fileNames[g.Name],
+					fetch: func(context.Context) (any, error) { return g, nil },
+				})
+			}
 		}
 	}
 
-	cmd.Println("Fetching MCP Server configurations...")
-
-	servers, sErr := apiClient.GetServerConfigs()
-	if sErr != nil {
-		cmd.Printf("warning: failed to fetch mcp server configurations: %v", sErr)
+	if !filter.includes(exportMcpServersDir) {
+		p.Infoln("Skipping MCP Servers, excluded by --exclude.")
 	} else {
-		if len(servers) == 0 {
-			cmd.Println("No MCP Servers found.")
-		} else {
-			cmd.Printf("Writing MCP Server configurations to %s\n", serversDir)
+		serversDir := filepath.Join(targetDir, exportMcpServersDir)
+		if err := os.MkdirAll(serversDir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create mcp servers directory: %w", err)
+		}
 
-			for _, s := range servers {
-				if err := writeJSONConfigFile(serversDir, s.Name, s); err != nil {
-					return err
+		p.Infoln("Listing MCP Servers...")
+
+		// the configurations are fetched one by one by the workers, only the names are listed here
+		servers, sErr := apiClient.ListServersContext(ctx)
+		if sErr != nil {
+			p.Warnf("failed to list mcp servers: %v", sErr)
+			res.incomplete = true
+		} else {
+			fileNames, err := configFileNames(servers, func(s *types.McpServer) string { return s.Name })
+			if err != nil {
+				return nil, err
+			}
+			existing := slices.Collect(maps.Values(fileNames))
+			servers = filterServers(filter, p, servers, func(s *types.McpServer) string { return s.Name })
+			if exportCmdPrune || changes != nil {
+				exported := make([]string, 0, len(servers))
+				for _, s := range servers {
+					exported = append(exported, fileNames[s.Name])
+				}
+				if err := pruneExportDir(p, serversDir, format, exported, existing, changes); err != nil {
+					return nil, err
 				}
 			}
+			if len(servers) == 0 {
+				p.Infoln("No MCP Servers found.")
+			}
+			for _, s := range servers {
+				jobs = append(jobs, &exportJob{
+					entity: "MCP server " + s.Name, dir: exportMcpServersDir, fileName: fileNames[s.Name],
+					fetch: func(ctx context.Context) (any, error) { return fetchExportServerConfig(ctx, s) },
+				})
+			}
+		}
+	}
+
+	res.entities = len(jobs)
+	if len(jobs) > 0 {
+		p.Infof("\nWriting %d configurations\n", len(jobs))
+		var exported map[string]int
+		exported, res.failures = runExportJobs(ctx, p, targetDir, format, jobs, exportCmdConcurrency, manifest, changes)
+		manifest.Counts.Groups, manifest.Counts.Servers = exported[exportToolGroupsDir], exported[exportMcpServersDir]
+	}
+	// the failures are warnings of the export, they are summarized at its end and recorded in the manifest
+	for _, err := range res.failures {
+		p.Warnf("%v", err)
+	}
+
+	missing, err := exportEnterpriseEntities(cmd, p, targetDir, format, filter, manifest, changes)
+	if err != nil {
+		return nil, err
+	}
+	res.incomplete = res.incomplete || len(res.failures) > 0 || missing
+
+	manifest.Warnings = p.warnings
+	unchanged := false
+	if changes != nil {
+		if unchanged, err = sameAsPreviousExport(targetDir, manifest); err != nil {
+			return nil, err
+		}
+	}
+	if !unchanged {
+		if err := writeExportManifest(targetDir, manifest); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// pruneExportDir removes the configuration files of entityDir that the export doesn't write: the files of the
+// entities that no longer exist, and the ones of the exported entities in another format, eg- github.json
+// once it is exported to github.yaml. The files of the entities left out by --servers or --groups are kept,
+// and so are the files that aren't configurations.
+// It is only called once the entities were fetched, so that a failed fetch never empties the directory.
+// In an incremental export without --prune, the files of the entities that no longer exist are only counted
+// and reported.
+func pruneExportDir(p *printer, entityDir, format string, exported, existing []string, changes *exportChanges) error {
+	entries, err := os.ReadDir(entityDir)
+	if err != nil {
+		return fmt.Errorf("failed to read the contents of %s: %w", entityDir, err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !isConfigFile(e.Name()) {
+			continue
+		}
+		ext := filepath.Ext(e.Name())
+		name := strings.TrimSuffix(e.Name(), ext)
+		if slices.Contains(exported, name) && ext == "."+format {
+			continue
+		}
+		if !slices.Contains(exported, name) && slices.Contains(existing, name) {
+			continue
+		}
+		path := filepath.Join(entityDir, e.Name())
+		removed := !slices.Contains(exported, name)
+		if removed && changes != nil {
+			changes.removed++
+		}
+		if !exportCmdPrune {
+			if removed {
+				p.Infof("%s belongs to an entity that no longer exists, use --prune to remove it\n", path)
+			}
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove stale file %s: %w", path, err)
 		}
+		p.Infof("Removed stale file %s\n", path)
 	}
+	return nil
+}
 
-	cmd.Println("\nExport complete!")
+// runExportToS3 uploads the export to the object store, as an archive streamed while it is written,
+// see stageExport.
+func runExportToS3(cmd *cobra.Command, filter *exportFilter) error {
+	ctx := commandContext(cmd)
+	bucket, key, err := s3.ParseURL(exportCmdS3.url)
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+	key = bundleObjectKey(key, nowFunc())
+	store, err := newS3Client(ctx, &exportCmdS3)
+	if err != nil {
+		return err
+	}
+
+	p := newPrinter(cmd)
+	staging, res, err := stageExport(cmd, p, configFormatJSON, filter)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+	pr := p.Progress(fmt.Sprintf("Uploading the export to s3://%s/%s", bucket, key))
+	archive, w := io.Pipe()
+	go func() {
+		_ = w.CloseWithError(writeDirArchive(w, staging))
+	}()
+	err = store.Upload(ctx, bucket, key, archive)
+	// stops the writer if the upload failed before reading the whole archive
+	_ = archive.CloseWithError(err)
+	pr.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to upload the export: %w", err)
+	}
+
+	p.Resultf(
+		"Exported %d tool groups and %d MCP servers to s3://%s/%s\n",
+		res.manifest.Counts.Groups, res.manifest.Counts.Servers, bucket, key,
+	)
+	return nil
+}
+
+// runExportToArchive writes the export to a gzip compressed tar archive, see stageExport.
+// The archive is written next to its destination and only renamed once it is complete,
+// so that an incomplete export never replaces an archive.
+func runExportToArchive(cmd *cobra.Command, format string, filter *exportFilter) error {
+	path, err := filepath.Abs(clientconfig.ExpandHome(exportCmdArchive))
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil && !exportCmdForce {
+		return usageErrorf("%s already exists, use --force to overwrite it", path)
+	}
+
+	p := newPrinter(cmd)
+	staging, res, err := stageExport(cmd, p, format, filter)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create the archive: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+	if err := writeDirArchive(f, staging); err != nil {
+		return fmt.Errorf("failed to write the archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write the archive: %w", err)
+	}
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to write the archive: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to write the archive: %w", err)
+	}
+
+	p.Resultf(
+		"Exported %d tool groups and %d MCP servers to %s\n", res.manifest.Counts.Groups, res.manifest.Counts.Servers, path,
+	)
+	return nil
+}
+
+// stageExport exports the configurations the filter selects to a temporary directory, to be archived: an archive
+// holds the same files as an export to a directory, the manifest included. The caller removes the directory.
+// Unlike an export to a directory, it fails if any entity can't be exported, so that an incomplete archive
+// isn't mistaken for a complete one.
+func stageExport(cmd *cobra.Command, p *printer, format string, filter *exportFilter) (string, *dirExport, error) {
+	dir, err := os.MkdirTemp("", "mcpjungle-export-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create the staging directory of the export: %w", err)
+	}
+	res, err := exportToDir(cmd, p, dir, format, filter, nil)
+	if err == nil && res.incomplete {
+		err = errors.New(
+			"the export is incomplete, see the warnings above, use --exclude to leave out the entities you can't export",
+		)
+	}
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return "", nil, err
+	}
+	return dir, res, nil
+}
+
+// fetchExportBundle fetches the configurations the filter selects, the types of entities it excludes aren't fetched.
+// Unlike an export to a directory, it fails if any of them can't be fetched, so that the bundle is complete.
+func fetchExportBundle(cmd *cobra.Command, filter *exportFilter) (*exportBundle, error) {
+	ctx := commandContext(cmd)
+	p := newPrinter(cmd)
+	b := &exportBundle{}
+	if filter.includes(exportToolGroupsDir) {
+		groups, err := apiClient.GetToolGroupConfigsContext(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tool group configurations: %w", err)
+		}
+		b.Groups = filter.filterGroups(p, groups)
+	}
+	if filter.includes(exportMcpServersDir) {
+		servers, err := apiClient.GetServerConfigsContext(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch mcp server configurations: %w", err)
+		}
+		redactExportSecrets(servers...)
+		b.Servers = filter.filterServers(p, servers)
+	}
+	return b, nil
+}
+
+// runExportToStdout prints the export as a single document, without touching the filesystem.
+// Only the document is written to stdout, so that it can be piped to other tools.
+func runExportToStdout(cmd *cobra.Command, format string, filter *exportFilter) error {
+	p := newPrinter(cmd)
+	p.Infoln("Fetching Tool Group and MCP Server configurations...")
+	bundle, err := fetchExportBundle(cmd, filter)
+	if err != nil {
+		return err
+	}
+	// the empty arrays are kept in the document
+	if bundle.Groups == nil {
+		bundle.Groups = []types.ToolGroup{}
+	}
+	if bundle.Servers == nil {
+		bundle.Servers = []*types.RegisterServerInput{}
+	}
+	if err := printConfigAs(p, format, bundle); err != nil {
+		return fmt.Errorf("failed to serialize the export: %w", err)
+	}
+	p.Infof("Exported %d tool groups and %d MCP servers\n", len(bundle.Groups), len(bundle.Servers))
+	return nil
+}
+
+// runExportMCPJSON exports the MCP servers to a single mcp.json file in the target directory.
+// The servers the format can't express are reported and left out.
+func runExportMCPJSON(cmd *cobra.Command, filter *exportFilter) error {
+	p := newPrinter(cmd)
+
+	targetDir, err := resolveTargetDirForExport()
+	if err != nil {
+		return fmt.Errorf("failed to resolve target directory for export: %w", err)
+	}
+	servers, err := apiClient.GetServerConfigsContext(commandContext(cmd))
+	if err != nil {
+		return fmt.Errorf("failed to fetch mcp server configurations: %w", err)
+	}
+	redactExportSecrets(servers...)
+
+	doc, skipped := newMCPJSONDocument(filter.filterServers(p, servers))
+	for _, err := range skipped {
+		p.Warnf("%v", err)
+	}
+	data, err := marshalConfig(doc)
+	if err != nil {
+		return fmt.Errorf("failed to serialize %s: %w", mcpJSONFileName, err)
+	}
+	if exportCmdStdout {
+		p.Resultln(string(data))
+		return nil
+	}
+	filename := filepath.Join(targetDir, mcpJSONFileName)
+	if err := os.WriteFile(filename, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filename, err)
+	}
 
+	p.Resultf("Exported %d MCP servers to %s\n", len(doc.McpServers), filename)
 	return nil
 }
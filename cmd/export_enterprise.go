@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	exportUsersDir   = "users"
+	exportClientsDir = "clients"
+)
+
+// exportEnterpriseEntities exports the users and MCP clients to their directories, they only exist if the server
+// runs in enterprise mode. Like the other entities, a kind of entity that can't be fetched, eg- because the caller
+// isn't an admin, is reported and skipped: missing reports whether it happened.
+// The access tokens are never exported: the users and clients keep theirs in the registry, and the ones they get
+// when created anew are printed by `mcpjungle create`.
+func exportEnterpriseEntities(
+	cmd *cobra.Command, p *printer, targetDir, format string, filter *exportFilter, manifest *exportManifest,
+	changes *exportChanges,
+) (missing bool, err error) {
+	exportUsers, exportClients := filter.includes(exportUsersDir), filter.includes(exportClientsDir)
+	if !exportUsers && !exportClients {
+		return false, nil
+	}
+	ctx := commandContext(cmd)
+	r, err := apiClient.GetServerReadiness(ctx)
+	switch {
+	case errors.Is(err, client.ErrNotFound):
+		p.Infoln("Skipping users and MCP clients, the server is too old to report its mode.")
+		return false, nil
+	case err != nil:
+		p.Warnf("failed to get the mode of the mcpjungle server, users and MCP clients are not exported: %v", err)
+		return true, nil
+	case !model.IsEnterpriseMode(model.ServerMode(r.Mode)):
+		p.Infoln("Skipping users and MCP clients, they only exist in enterprise mode.")
+		return false, nil
+	}
+
+	if exportUsers {
+		m, err := exportUserConfigs(ctx, p, targetDir, format, manifest, changes)
+		if err != nil {
+			return false, err
+		}
+		missing = missing || m
+	} else {
+		p.Infoln("Skipping users, excluded by --exclude.")
+	}
+	if exportClients {
+		m, err := exportClientConfigs(ctx, p, targetDir, format, manifest, changes)
+		if err != nil {
+			return false, err
+		}
+		missing = missing || m
+	} else {
+		p.Infoln("Skipping MCP clients, excluded by --exclude.")
+	}
+	return missing, nil
+}
+
+// exportUserConfigs exports the users, it only fails if they were fetched but can't be written.
+// missing reports whether they couldn't be fetched.
+func exportUserConfigs(
+	ctx context.Context, p *printer, targetDir, format string, manifest *exportManifest, changes *exportChanges,
+) (missing bool, err error) {
+	p.Infoln("Fetching users...")
+
+	users, err := apiClient.ListUsersContext(ctx)
+	if err != nil {
+		p.Warnf("failed to fetch users: %v", err)
+		return true, nil
+	}
+	manifest.Counts.Users = len(users)
+	name := func(u *types.User) string { return u.Username }
+	return false, writeExportEntities(p, targetDir, exportUsersDir, format, users, name, manifest, changes)
+}
+
+// exportClientConfigs exports the MCP clients without their access tokens, it only fails if they were fetched
+// but can't be written. missing reports whether they couldn't be fetched.
+func exportClientConfigs(
+	ctx context.Context, p *printer, targetDir, format string, manifest *exportManifest, changes *exportChanges,
+) (missing bool, err error) {
+	p.Infoln("Fetching MCP clients...")
+
+	clients, err := apiClient.ListMcpClientsContext(ctx)
+	if err != nil {
+		p.Warnf("failed to fetch mcp clients: %v", err)
+		return true, nil
+	}
+	for i := range clients {
+		clients[i].AccessToken = ""
+	}
+	manifest.Counts.Clients = len(clients)
+	name := func(c types.McpClient) string { return c.Name }
+	return false, writeExportEntities(p, targetDir, exportClientsDir, format, clients, name, manifest, changes)
+}
+
+// writeExportEntities writes the configuration file of every entity to dir, in the export directory, and records
+// the files in the manifest. With --prune, the files of the entities that no longer exist are removed.
+func writeExportEntities[T any](
+	p *printer, targetDir, dir, format string, entities []T, name func(T) string, manifest *exportManifest,
+	changes *exportChanges,
+) error {
+	entityDir := filepath.Join(targetDir, dir)
+	if err := os.MkdirAll(entityDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", dir, err)
+	}
+	fileNames, err := configFileNames(entities, name)
+	if err != nil {
+		return err
+	}
+	if exportCmdPrune || changes != nil {
+		exported := slices.Collect(maps.Values(fileNames))
+		if err := pruneExportDir(p, entityDir, format, exported, exported, changes); err != nil {
+			return err
+		}
+	}
+	if len(entities) == 0 {
+		p.Infof("No %s found.\n", dir)
+		return nil
+	}
+
+	p.Infof("Writing %s configurations\n", dir)
+
+	for _, e := range entities {
+		if err := writeExportFile(entityDir, fileNames[name(e)], format, e, changes); err != nil {
+			return err
+		}
+		if err := manifest.addFiles(targetDir, filepath.Join(dir, fileNames[name(e)]+"."+format)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
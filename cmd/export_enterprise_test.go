@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func TestExportEnterpriseEntities(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        model.ServerMode
+		usersStatus int
+		wantUsers   bool
+		wantClients bool
+		wantStderr  string
+	}{
+		{name: "enterprise mode", mode: model.ModeEnterprise, usersStatus: http.StatusOK, wantUsers: true, wantClients: true},
+		{
+			name: "not an admin", mode: model.ModeEnterprise, usersStatus: http.StatusForbidden,
+			wantClients: true, wantStderr: "failed to fetch users",
+		},
+		{name: "dev mode", mode: model.ModeDev, wantStderr: "they only exist in enterprise mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withRegistryHandlers(t, map[string]http.HandlerFunc{
+				"GET /ready": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, types.ServerReadiness{Ready: true, Initialized: true, Mode: string(tt.mode)})
+				},
+				"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, []types.ToolGroup{})
+				},
+				"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, []*types.RegisterServerInput{})
+				},
+				"GET /api/v1/users": func(w http.ResponseWriter, r *http.Request) {
+					if tt.usersStatus != http.StatusOK {
+						writeTestJSON(w, tt.usersStatus, types.ErrorResponse{Error: types.APIError{
+							Code: types.ErrorCodeForbidden, Message: "only admins can list users",
+						}})
+						return
+					}
+					writeTestJSON(w, http.StatusOK, []*types.User{{Username: "alice", Role: "user"}})
+				},
+				"GET /api/v1/clients": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, []types.McpClient{
+						{Name: "cursor", AccessToken: "cursor-token", AllowList: []string{"github"}},
+					})
+				},
+			})
+			origDir, origFormat := exportCmdTargetDir, exportCmdFormat
+			t.Cleanup(func() { exportCmdTargetDir, exportCmdFormat = origDir, origFormat })
+			dir := filepath.Join(t.TempDir(), "export")
+			exportCmdTargetDir, exportCmdFormat = dir, exportFormatJSON
+
+			var stderr bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(&stderr)
+			testhelpers.AssertNoError(t, runExport(cmd, nil))
+			if tt.wantStderr != "" {
+				testhelpers.AssertStringContains(t, stderr.String(), tt.wantStderr)
+			}
+
+			_, err := os.Stat(filepath.Join(dir, exportUsersDir, "alice.json"))
+			testhelpers.AssertEqual(t, tt.wantUsers, err == nil)
+			data, err := os.ReadFile(filepath.Join(dir, exportClientsDir, "cursor.json"))
+			testhelpers.AssertEqual(t, tt.wantClients, err == nil)
+			if tt.wantClients {
+				testhelpers.AssertStringContains(t, string(data), `"name": "cursor"`)
+				testhelpers.AssertStringNotContains(t, string(data), "cursor-token")
+			}
+
+			// the files of the users and clients aren't reported as missing by import, which doesn't read them
+			scanned, err := scanBundleDir(dir)
+			testhelpers.AssertNoError(t, err)
+			mismatches, err := verifyExportManifest(dir, scanned.files())
+			testhelpers.AssertNoError(t, err)
+			testhelpers.AssertEqual(t, 0, len(mismatches))
+		})
+	}
+}
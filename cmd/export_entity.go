@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var exportServerCmd = &cobra.Command{
+	Use:   "server [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Export the configuration file of an MCP server",
+	Long: "Export the complete configuration of a registered MCP server to a single file.\n" +
+		"The file is written to the servers subdirectory of --dir, like a full export does, so it can refresh\n" +
+		"a server in a directory exported before: unlike a full export, the directory doesn't have to be empty,\n" +
+		"and a file of the same server is overwritten. With --stdout, the configuration is printed instead.\n" +
+		"Like in a full export, the secrets are redacted unless --include-secrets is set.\n\n" +
+		"NOTE: In enterprise mode, you must be an admin to export the configuration of an MCP server.",
+	Example: "  mcpjungle export server github\n" +
+		"  mcpjungle export server github --format yaml --dir ./gitops\n" +
+		"  mcpjungle export server github --stdout > github.json",
+	RunE: runExportServer,
+}
+
+var exportGroupCmd = &cobra.Command{
+	Use:   "group [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Export the configuration file of a tool group",
+	Long: "Export the configuration of a tool group to a single file, in the groups subdirectory of --dir.\n" +
+		"Like for an MCP server, the directory doesn't have to be empty, and --stdout prints the configuration instead.",
+	Example: "  mcpjungle export group ops\n" +
+		"  mcpjungle export group ops --stdout",
+	RunE: runExportGroup,
+}
+
+func init() {
+	exportCmd.AddCommand(exportServerCmd)
+	exportCmd.AddCommand(exportGroupCmd)
+}
+
+// exportEntityFormat returns the format of the --format flag for the export of a single entity,
+// which is written in JSON or YAML.
+func exportEntityFormat() (string, error) {
+	switch exportCmdFormat {
+	case exportFormatJSON, exportFormatDir, "":
+		return exportFormatJSON, nil
+	case exportFormatYAML:
+		return exportFormatYAML, nil
+	default:
+		return "", usageErrorf(
+			"unsupported format '%s' for a single entity, supported formats: %s, %s",
+			exportCmdFormat, exportFormatJSON, exportFormatYAML,
+		)
+	}
+}
+
+func runExportServer(cmd *cobra.Command, args []string) error {
+	format, err := exportEntityFormat()
+	if err != nil {
+		return err
+	}
+	server, err := apiClient.GetServerConfig(commandContext(cmd), args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get the configuration of MCP server %s: %w", args[0], err)
+	}
+	redactExportSecrets(server)
+	return exportEntity(cmd, "MCP server", exportMcpServersDir, server.Name, format, server)
+}
+
+func runExportGroup(cmd *cobra.Command, args []string) error {
+	format, err := exportEntityFormat()
+	if err != nil {
+		return err
+	}
+	group, err := apiClient.GetToolGroupConfig(commandContext(cmd), args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get the configuration of tool group %s: %w", args[0], err)
+	}
+	return exportEntity(cmd, "tool group", exportToolGroupsDir, group.Name, format, group)
+}
+
+// exportEntity prints the configuration of an entity with --stdout, or writes it to the subdirectory of its kind
+// in the export directory, which is created if needed.
+func exportEntity(cmd *cobra.Command, kind, kindDir, name, format string, entity any) error {
+	p := newPrinter(cmd)
+	if exportCmdStdout {
+		if err := printConfigAs(p, format, entity); err != nil {
+			return fmt.Errorf("failed to serialize %s %s: %w", kind, name, err)
+		}
+		return nil
+	}
+
+	targetDir, err := exportTargetPath()
+	if err != nil {
+		return fmt.Errorf("failed to resolve target directory for export: %w", err)
+	}
+	entityDir := filepath.Join(targetDir, kindDir)
+	if err := os.MkdirAll(entityDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", kindDir, err)
+	}
+	if err := writeConfigFile(entityDir, safeFileName(name), format, entity); err != nil {
+		return err
+	}
+	// the manifest of a full export keeps matching the directory
+	if err := updateExportManifest(targetDir, filepath.Join(kindDir, configFileName(name, format))); err != nil {
+		return err
+	}
+	p.Resultf("Exported %s %s to %s\n", kind, name, filepath.Join(entityDir, configFileName(name, format)))
+	return nil
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func TestExportEntityCommandStructure(t *testing.T) {
+	for _, c := range []*cobra.Command{exportServerCmd, exportGroupCmd} {
+		testhelpers.AssertTrue(t, c.Parent() == exportCmd, c.Name()+" should be a subcommand of export")
+		// the directory, the format and --stdout are the flags of the full export
+		testhelpers.AssertNotNil(t, c.InheritedFlags().Lookup("stdout"))
+		testhelpers.AssertNotNil(t, c.InheritedFlags().Lookup("dir"))
+		testhelpers.AssertNotNil(t, c.InheritedFlags().Lookup("format"))
+	}
+}
+
+func withExportEntityFlags(t *testing.T, dir, format string, stdout bool) {
+	t.Helper()
+	origDir, origFormat, origStdout := exportCmdTargetDir, exportCmdFormat, exportCmdStdout
+	t.Cleanup(func() { exportCmdTargetDir, exportCmdFormat, exportCmdStdout = origDir, origFormat, origStdout })
+	exportCmdTargetDir, exportCmdFormat, exportCmdStdout = dir, format, stdout
+}
+
+func TestExportServer(t *testing.T) {
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/server_configs/{name}": func(w http.ResponseWriter, r *http.Request) {
+			for _, s := range testMCPJSONServers() {
+				if s.Name == r.PathValue("name") {
+					writeTestJSON(w, http.StatusOK, s)
+					return
+				}
+			}
+			writeTestJSON(w, http.StatusNotFound, types.ErrorResponse{
+				Error: types.APIError{Code: types.ErrorCodeNotFound, Message: "MCP server not found"},
+			})
+		},
+	})
+
+	// the directory already holds an export, the file of the server is overwritten
+	dir := t.TempDir()
+	testhelpers.AssertNoError(t, os.MkdirAll(filepath.Join(dir, exportMcpServersDir), 0o755))
+	testhelpers.AssertNoError(t, os.WriteFile(filepath.Join(dir, exportMcpServersDir, "context7.json"), []byte("{}"), 0o644))
+	testhelpers.AssertNoError(t, os.WriteFile(filepath.Join(dir, exportMcpServersDir, "linear.json"), []byte("{}"), 0o644))
+	withExportEntityFlags(t, dir, exportFormatJSON, false)
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	testhelpers.AssertNoError(t, runExportServer(cmd, []string{"context7"}))
+	testhelpers.AssertStringContains(t, out.String(), "Exported MCP server context7 to "+filepath.Join(dir, "servers", "context7.json"))
+	bundle, err := readBundleDir(dir)
+	testhelpers.AssertNoError(t, err)
+	// the secrets are redacted by default
+	testhelpers.AssertEqual(t, "${MCPJUNGLE_SECRET:context7/bearer_token}", bundle.Servers[0].BearerToken)
+
+	out.Reset()
+	withExportEntityFlags(t, dir, exportFormatYAML, true)
+	testhelpers.AssertNoError(t, runExportServer(cmd, []string{"filesystem"}))
+	testhelpers.AssertStringContains(t, out.String(), "name: filesystem\n")
+	testhelpers.AssertStringContains(t, out.String(), "lazy_start: true\n")
+	_, err = os.Stat(filepath.Join(dir, exportMcpServersDir, "filesystem.yaml"))
+	testhelpers.AssertTrue(t, os.IsNotExist(err), "--stdout should not write a file")
+
+	err = runExportServer(cmd, []string{"missing"})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitNotFound, ExitCodeForError(err))
+	testhelpers.AssertStringContains(t, err.Error(), "MCP server not found")
+
+	withExportEntityFlags(t, dir, exportFormatMCPJSON, true)
+	err = runExportServer(cmd, []string{"context7"})
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+}
+
+func TestExportGroup(t *testing.T) {
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups/ops": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, types.GetToolGroupResponse{
+				ToolGroup:          &types.ToolGroup{UUID: "0b9f7a52-3c1e-4f7c-8d1b-2a1d3f6e9c10", Name: "ops", IncludedServers: []string{"github"}},
+				ToolGroupEndpoints: &types.ToolGroupEndpoints{},
+			})
+		},
+		"GET /api/v1/tool-groups/missing": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusNotFound, types.ErrorResponse{
+				Error: types.APIError{Code: types.ErrorCodeNotFound, Message: "tool group missing not found"},
+			})
+		},
+	})
+	dir := filepath.Join(t.TempDir(), "export")
+	withExportEntityFlags(t, dir, exportFormatYAML, false)
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	testhelpers.AssertNoError(t, runExportGroup(cmd, []string{"ops"}))
+	bundle, err := readBundleDir(dir)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(bundle.Groups))
+	testhelpers.AssertEqual(t, "github", bundle.Groups[0].IncludedServers[0])
+	testhelpers.AssertEqual(t, "", bundle.Groups[0].UUID)
+
+	err = runExportGroup(cmd, []string{"missing"})
+	testhelpers.AssertEqual(t, ExitNotFound, ExitCodeForError(err))
+	testhelpers.AssertStringContains(t, err.Error(), "tool group missing not found")
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxFileNameBytes bounds the length of the names of configuration files, below the limit of 255 bytes of most
+// filesystems, leaving room for the hash and the extension.
+const maxFileNameBytes = 200
+
+// windowsReservedNames are the names of devices on Windows, which can't be the names of files, with any extension.
+var windowsReservedNames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+// safeFileName returns a name for the configuration file of an entity that is safe on every filesystem,
+// without extension. The letters and digits of any script are kept, as well as -, _ and ., the other characters,
+// including the path separators, are replaced with _. The name can't be . or .., so it never escapes the directory
+// of the file.
+// If the name had to be changed, the hash of the original name is appended, so that two names that are sanitized
+// the same way, eg- team/api and team_api, get different files. The files hold the names of their entities,
+// which are the ones imported.
+func safeFileName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	// Windows drops the trailing dots, and the names starting with a dot are hidden
+	safe := strings.Trim(b.String(), ".")
+	if len(safe) > maxFileNameBytes {
+		safe = safe[:maxFileNameBytes]
+		for !utf8.ValidString(safe) {
+			safe = safe[:len(safe)-1]
+		}
+	}
+	if safe != "" && safe == name && !isReservedFileName(safe) {
+		return safe
+	}
+	if safe == "" {
+		safe = "_"
+	}
+	return safe + "-" + fileNameHash(name)
+}
+
+// isReservedFileName reports whether a file can't have this name on Windows, eg- con or CON.json.
+func isReservedFileName(name string) bool {
+	base, _, _ := strings.Cut(name, ".")
+	return slices.ContainsFunc(windowsReservedNames, func(r string) bool { return strings.EqualFold(r, base) })
+}
+
+// fileNameHash returns a short hash of the name of an entity, to disambiguate the names of files.
+func fileNameHash(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:4])
+}
+
+// configFileNames returns the names of the configuration files of the entities exported together, without extension,
+// by the names of the entities. They are the names of safeFileName, except for the ones that would collide on
+// a case-insensitive filesystem, eg- GitHub and github: all of them get the hash of their name appended,
+// so that the names don't depend on the order of the entities.
+func configFileNames[T any](entities []T, name func(T) string) (map[string]string, error) {
+	names := make(map[string]string, len(entities))
+	byFolded := make(map[string][]string)
+	for _, e := range entities {
+		n := name(e)
+		if _, ok := names[n]; ok {
+			continue
+		}
+		names[n] = safeFileName(n)
+		folded := strings.ToLower(names[n])
+		byFolded[folded] = append(byFolded[folded], n)
+	}
+	for _, colliding := range byFolded {
+		if len(colliding) < 2 {
+			continue
+		}
+		for _, n := range colliding {
+			if names[n] == n {
+				names[n] += "-" + fileNameHash(n)
+			}
+		}
+	}
+
+	// the names can still collide by chance, eg- with an entity named after another one and its hash:
+	// the export fails then, rather than overwriting a file
+	used := make(map[string]string, len(names))
+	for _, n := range slices.Sorted(maps.Keys(names)) {
+		folded := strings.ToLower(names[n])
+		if other, ok := used[folded]; ok {
+			return nil, fmt.Errorf("the configuration files of %s and %s would have the same name", other, n)
+		}
+		used[folded] = n
+	}
+	return names, nil
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func TestSafeFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "github", want: "github"},
+		{name: "github-enterprise_v2.1", want: "github-enterprise_v2.1"},
+		{name: "日本語-サーバー", want: "日本語-サーバー"},
+		{name: "café", want: "café"},
+		{name: "team/api", want: "team_api-" + fileNameHash("team/api")},
+		{name: `team\api`, want: "team_api-" + fileNameHash(`team\api`)},
+		{name: "a:b*c?", want: "a_b_c_-" + fileNameHash("a:b*c?")},
+		{name: "CON", want: "CON-" + fileNameHash("CON")},
+		{name: "nul.txt", want: "nul.txt-" + fileNameHash("nul.txt")},
+		{name: "com1", want: "com1-" + fileNameHash("com1")},
+		{name: "console", want: "console"},
+		{name: "..", want: "_-" + fileNameHash("..")},
+		{name: "../../etc/passwd", want: "_.._etc_passwd-" + fileNameHash("../../etc/passwd")},
+		{name: ".hidden", want: "hidden-" + fileNameHash(".hidden")},
+		{name: "", want: "_-" + fileNameHash("")},
+		{name: strings.Repeat("é", 150), want: strings.Repeat("é", 100) + "-" + fileNameHash(strings.Repeat("é", 150))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := safeFileName(tt.name)
+			testhelpers.AssertEqual(t, tt.want, got)
+			// the name never escapes the directory of the file
+			testhelpers.AssertEqual(t, got, filepath.Base(got))
+			testhelpers.AssertFalse(t, strings.ContainsAny(got, `/\`), "the name should have no path separator")
+		})
+	}
+}
+
+func TestConfigFileNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  map[string]string
+	}{
+		{
+			name:  "no collision",
+			names: []string{"github", "jira"},
+			want:  map[string]string{"github": "github", "jira": "jira"},
+		},
+		{
+			name:  "sanitized the same way",
+			names: []string{"team/api", "team_api", "team:api"},
+			want: map[string]string{
+				"team/api": "team_api-" + fileNameHash("team/api"),
+				"team_api": "team_api",
+				"team:api": "team_api-" + fileNameHash("team:api"),
+			},
+		},
+		{
+			name:  "case-insensitive collision",
+			names: []string{"GitHub", "github", "jira"},
+			want: map[string]string{
+				"GitHub": "GitHub-" + fileNameHash("GitHub"),
+				"github": "github-" + fileNameHash("github"),
+				"jira":   "jira",
+			},
+		},
+		{
+			name:  "unicode names differing by case",
+			names: []string{"Ärzte", "ärzte"},
+			want: map[string]string{
+				"Ärzte": "Ärzte-" + fileNameHash("Ärzte"),
+				"ärzte": "ärzte-" + fileNameHash("ärzte"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := configFileNames(tt.names, func(n string) string { return n })
+			testhelpers.AssertNoError(t, err)
+			testhelpers.AssertEqual(t, len(tt.want), len(got))
+			for name, file := range tt.want {
+				testhelpers.AssertEqual(t, file, got[name])
+			}
+			// the names don't depend on the order of the entities
+			reversed := slices.Clone(tt.names)
+			slices.Reverse(reversed)
+			again, err := configFileNames(reversed, func(n string) string { return n })
+			testhelpers.AssertNoError(t, err)
+			for name, file := range got {
+				testhelpers.AssertEqual(t, file, again[name])
+			}
+		})
+	}
+
+	// an entity named after another one and its hash
+	got, err := configFileNames([]string{"CON", "CON-" + fileNameHash("CON")}, func(n string) string { return n })
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, got["CON"] != got["CON-"+fileNameHash("CON")], "the entities should get different files")
+}
+
+func TestExportCollidingNames(t *testing.T) {
+	servers := []*types.RegisterServerInput{
+		{Name: "team/api", Transport: "streamable_http", URL: "https://api-1.example.com/mcp"},
+		{Name: "team_api", Transport: "streamable_http", URL: "https://api-2.example.com/mcp"},
+		{Name: "CON", Transport: "streamable_http", URL: "https://con.example.com/mcp"},
+	}
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{Name: "../ops"}})
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, servers)
+		},
+	})
+	origDir, origFormat := exportCmdTargetDir, exportCmdFormat
+	t.Cleanup(func() { exportCmdTargetDir, exportCmdFormat = origDir, origFormat })
+	exportCmdTargetDir, exportCmdFormat = filepath.Join(t.TempDir(), "export"), exportFormatJSON
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+
+	// every entity has its own file, in the directory of its kind, and is read back with its original name
+	files, _ := filepath.Glob(filepath.Join(exportCmdTargetDir, exportMcpServersDir, "*.json"))
+	testhelpers.AssertEqual(t, 3, len(files))
+	files, _ = filepath.Glob(filepath.Join(exportCmdTargetDir, exportToolGroupsDir, "*.json"))
+	testhelpers.AssertEqual(t, 1, len(files))
+	bundle, err := readBundleDir(exportCmdTargetDir)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "../ops", bundle.Groups[0].Name)
+	for _, s := range servers {
+		i := slices.IndexFunc(bundle.Servers, func(read *types.RegisterServerInput) bool { return read.Name == s.Name })
+		testhelpers.AssertTrue(t, i >= 0, s.Name+" should be read back")
+		testhelpers.AssertEqual(t, s.URL, bundle.Servers[i].URL)
+	}
+}
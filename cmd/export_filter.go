@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// exportEntityTypes are the types of entities --exclude accepts, named after their directories in an export.
+var exportEntityTypes = []string{exportMcpServersDir, exportToolGroupsDir, exportUsersDir, exportClientsDir}
+
+// exportFilter selects the entities an export writes by their names, from the glob patterns of the --servers and
+// --groups flags, eg- "github*". Without patterns, all the entities of a kind are selected.
+// The types of entities of the --exclude flag aren't exported at all.
+type exportFilter struct {
+	servers  []string
+	groups   []string
+	excluded []string
+}
+
+// newExportFilter returns the filter of the export flags, or a usage error if a pattern is malformed.
+func newExportFilter() (*exportFilter, error) {
+	servers, err := parseNamePatterns("servers", exportCmdServers)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := parseNamePatterns("groups", exportCmdGroups)
+	if err != nil {
+		return nil, err
+	}
+	excluded, err := parseExcludedTypes(exportCmdExclude)
+	if err != nil {
+		return nil, err
+	}
+	return &exportFilter{servers: servers, groups: groups, excluded: excluded}, nil
+}
+
+// parseExcludedTypes checks the types of entities given to --exclude, eg- servers,users.
+// Excluding all of them is an error, since the export would be empty.
+func parseExcludedTypes(entityTypes []string) ([]string, error) {
+	var excluded []string
+	for _, t := range entityTypes {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if !slices.Contains(exportEntityTypes, t) {
+			return nil, usageErrorf(
+				"unknown entity type '%s' in --exclude, valid types: %s", t, strings.Join(exportEntityTypes, ", "),
+			)
+		}
+		if !slices.Contains(excluded, t) {
+			excluded = append(excluded, t)
+		}
+	}
+	if len(excluded) == len(exportEntityTypes) {
+		return nil, usageErrorf("--exclude excludes every type of entity, there would be nothing to export")
+	}
+	return excluded, nil
+}
+
+// includes reports whether the export writes the entities of the given type, one of exportEntityTypes.
+func (f *exportFilter) includes(entityType string) bool {
+	return !slices.Contains(f.excluded, entityType)
+}
+
+// parseNamePatterns trims the glob patterns given to a flag, and checks that they are well-formed.
+func parseNamePatterns(flag string, patterns []string) ([]string, error) {
+	var parsed []string
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, usageErrorf("invalid pattern '%s' in --%s: %v", pattern, flag, err)
+		}
+		parsed = append(parsed, pattern)
+	}
+	return parsed, nil
+}
+
+// filterServers returns the MCP servers the filter selects, and warns about the patterns that matched none.
+func (f *exportFilter) filterServers(p *printer, servers []*types.RegisterServerInput) []*types.RegisterServerInput {
+	return filterServers(f, p, servers, func(s *types.RegisterServerInput) string { return s.Name })
+}
+
+// filterServers is like exportFilter.filterServers, for any representation of the servers, eg- their list.
+func filterServers[T any](f *exportFilter, p *printer, servers []T, name func(T) string) []T {
+	selected, unmatched := filterByName(f.servers, servers, name)
+	if len(unmatched) > 0 {
+		p.Warnf("no MCP server matches the patterns: %s", strings.Join(unmatched, ", "))
+	}
+	return selected
+}
+
+// filterGroups returns the tool groups the filter selects, and warns about the patterns that matched none.
+func (f *exportFilter) filterGroups(p *printer, groups []types.ToolGroup) []types.ToolGroup {
+	selected, unmatched := filterByName(f.groups, groups, func(g types.ToolGroup) string { return g.Name })
+	if len(unmatched) > 0 {
+		p.Warnf("no tool group matches the patterns: %s", strings.Join(unmatched, ", "))
+	}
+	return selected
+}
+
+// filterByName returns the entities whose name matches any of the patterns, all of them if there are no patterns,
+// and the patterns that matched none of them.
+func filterByName[T any](patterns []string, entities []T, name func(T) string) ([]T, []string) {
+	if len(patterns) == 0 {
+		return entities, nil
+	}
+	matched := make([]bool, len(patterns))
+	var selected []T
+	for _, e := range entities {
+		selects := false
+		for i, pattern := range patterns {
+			// the patterns were checked by parseNamePatterns
+			if ok, _ := path.Match(pattern, name(e)); ok {
+				matched[i] = true
+				selects = true
+			}
+		}
+		if selects {
+			selected = append(selected, e)
+		}
+	}
+	var unmatched []string
+	for i, pattern := range patterns {
+		if !matched[i] && !slices.Contains(unmatched, pattern) {
+			unmatched = append(unmatched, pattern)
+		}
+	}
+	return selected, unmatched
+}
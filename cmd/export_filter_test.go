@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func TestFilterByName(t *testing.T) {
+	names := []string{"github", "github-enterprise", "jira", "linear"}
+	tests := []struct {
+		name      string
+		patterns  []string
+		selected  string
+		unmatched string
+	}{
+		{name: "no patterns", selected: "github,github-enterprise,jira,linear"},
+		{name: "glob and exact name", patterns: []string{"github*", "jira"}, selected: "github,github-enterprise,jira"},
+		{name: "overlapping patterns", patterns: []string{"github", "git*"}, selected: "github,github-enterprise"},
+		{name: "unmatched patterns", patterns: []string{"jira", "slack", "conf?"}, selected: "jira", unmatched: "slack,conf?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, unmatched := filterByName(tt.patterns, names, func(n string) string { return n })
+			testhelpers.AssertEqual(t, tt.selected, strings.Join(selected, ","))
+			testhelpers.AssertEqual(t, tt.unmatched, strings.Join(unmatched, ","))
+		})
+	}
+}
+
+func TestParseNamePatterns(t *testing.T) {
+	patterns, err := parseNamePatterns("servers", []string{" github* ", "", "jira"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "github*,jira", strings.Join(patterns, ","))
+
+	_, err = parseNamePatterns("servers", []string{"git[hub"})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+	testhelpers.AssertStringContains(t, err.Error(), "invalid pattern 'git[hub' in --servers")
+}
+
+func TestExportFiltered(t *testing.T) {
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{Name: "ops"}, {Name: "dev"}})
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, testMCPJSONServers())
+		},
+	})
+	origDir, origFormat := exportCmdTargetDir, exportCmdFormat
+	origServers, origGroups := exportCmdServers, exportCmdGroups
+	t.Cleanup(func() {
+		exportCmdTargetDir, exportCmdFormat = origDir, origFormat
+		exportCmdServers, exportCmdGroups = origServers, origGroups
+	})
+	exportCmdTargetDir, exportCmdFormat = filepath.Join(t.TempDir(), "export"), exportFormatJSON
+	exportCmdServers, exportCmdGroups = []string{"file*", "linear", "slack"}, nil
+
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(&stderr)
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+	testhelpers.AssertStringContains(t, stderr.String(), "no MCP server matches the patterns: slack")
+
+	bundle, err := readBundleDir(exportCmdTargetDir)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 2, len(bundle.Servers))
+	testhelpers.AssertEqual(t, "filesystem", bundle.Servers[0].Name)
+	testhelpers.AssertEqual(t, "linear", bundle.Servers[1].Name)
+	// the groups are exported in full without --groups
+	testhelpers.AssertEqual(t, 2, len(bundle.Groups))
+
+	// the MCP servers are filtered in the mcpjson format too
+	exportCmdTargetDir, exportCmdFormat = filepath.Join(t.TempDir(), "export"), exportFormatMCPJSON
+	exportCmdServers, exportCmdGroups = []string{"context7"}, []string{"ops"}
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+	data, err := os.ReadFile(filepath.Join(exportCmdTargetDir, mcpJSONFileName))
+	testhelpers.AssertNoError(t, err)
+	servers, err := parseClientConfig(data, importFormatMCPJSON)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(servers))
+
+	exportCmdServers = []string{"[github"}
+	err = runExport(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "invalid pattern")
+}
+
+func TestParseExcludedTypes(t *testing.T) {
+	excluded, err := parseExcludedTypes([]string{" Servers ", "", "users", "servers"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "servers,users", strings.Join(excluded, ","))
+
+	_, err = parseExcludedTypes([]string{"tools"})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+	testhelpers.AssertStringContains(t, err.Error(), "unknown entity type 'tools' in --exclude, valid types: servers, groups, users, clients")
+
+	_, err = parseExcludedTypes([]string{"servers", "groups", "users", "clients"})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+	testhelpers.AssertStringContains(t, err.Error(), "there would be nothing to export")
+}
+
+func TestExportExcluded(t *testing.T) {
+	fetched := 0
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{Name: "ops"}})
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			fetched++
+			writeTestJSON(w, http.StatusOK, testMCPJSONServers())
+		},
+	})
+	origDir, origFormat, origExclude := exportCmdTargetDir, exportCmdFormat, exportCmdExclude
+	t.Cleanup(func() { exportCmdTargetDir, exportCmdFormat, exportCmdExclude = origDir, origFormat, origExclude })
+	exportCmdTargetDir, exportCmdFormat = filepath.Join(t.TempDir(), "export"), exportFormatJSON
+	exportCmdExclude = []string{"servers"}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+	// the excluded servers are neither fetched nor given a directory
+	testhelpers.AssertEqual(t, 0, fetched)
+	_, err := os.Stat(filepath.Join(exportCmdTargetDir, exportMcpServersDir))
+	testhelpers.AssertTrue(t, os.IsNotExist(err), "the servers directory should not be created")
+	_, err = os.Stat(filepath.Join(exportCmdTargetDir, exportToolGroupsDir, "ops.json"))
+	testhelpers.AssertNoError(t, err)
+
+	// the mcpjson format only has servers
+	exportCmdTargetDir, exportCmdFormat = filepath.Join(t.TempDir(), "export"), exportFormatMCPJSON
+	err = runExport(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+}
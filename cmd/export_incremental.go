@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// exportChanges counts the configuration files of an incremental export by how they changed since the
+// previous export to the same directory.
+type exportChanges struct {
+	created   int
+	updated   int
+	unchanged int
+	// removed are the files of the entities that no longer exist, they are only deleted with --prune
+	removed int
+}
+
+// summary describes the changes, eg- 3 updated, 42 unchanged, 1 new.
+func (c *exportChanges) summary() string {
+	s := fmt.Sprintf("%d updated, %d unchanged, %d new", c.updated, c.unchanged, c.created)
+	if c.removed > 0 {
+		s += fmt.Sprintf(", %d removed", c.removed)
+	}
+	return s
+}
+
+// writeExportFile writes the configuration file of an entity in an export directory, see writeConfigFile.
+// In an incremental export, changes isn't nil: the file is only rewritten if its content differs from the
+// serialized entity, so that neither its content nor its modification time change otherwise.
+func writeExportFile(entityDir, fileName, format string, entity any, changes *exportChanges) error {
+	if changes == nil {
+		return writeConfigFile(entityDir, fileName, format, entity)
+	}
+	filename := filepath.Join(entityDir, fileName+"."+format)
+	data, err := marshalConfigFile(format, entity)
+	if err != nil {
+		return fmt.Errorf("failed to serialize entity %s/%s: %w", entityDir, fileName, err)
+	}
+	existing, err := os.ReadFile(filename)
+	switch {
+	case err == nil && bytes.Equal(existing, data):
+		changes.unchanged++
+		return nil
+	case err == nil:
+		changes.updated++
+	case errors.Is(err, os.ErrNotExist):
+		changes.created++
+	default:
+		return fmt.Errorf("failed to read entity file %s: %w", filename, err)
+	}
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write entity file %s: %w", filename, err)
+	}
+	return nil
+}
+
+// sameAsPreviousExport reports whether the manifest of an incremental export only differs from the one of the
+// previous export by its time. The previous one is then kept, so that an export that changes nothing doesn't
+// modify the directory at all.
+func sameAsPreviousExport(root string, m *exportManifest) (bool, error) {
+	previous, err := readExportManifest(root)
+	if err != nil || previous == nil {
+		return false, err
+	}
+	current := *m
+	current.ExportedAt = previous.ExportedAt
+	return reflect.DeepEqual(previous, &current), nil
+}
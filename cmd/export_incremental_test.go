@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func TestExportIncremental(t *testing.T) {
+	servers := testMCPJSONServers()
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{Name: "ops"}})
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, servers)
+		},
+	})
+	origNow := nowFunc
+	t.Cleanup(func() { nowFunc = origNow })
+	origDir, origFormat, origForce, origIncremental := exportCmdTargetDir, exportCmdFormat, exportCmdForce, exportCmdIncremental
+	t.Cleanup(func() {
+		exportCmdTargetDir, exportCmdFormat, exportCmdForce, exportCmdIncremental = origDir, origFormat, origForce, origIncremental
+	})
+	dir := filepath.Join(t.TempDir(), "export")
+	exportCmdTargetDir, exportCmdFormat, exportCmdForce, exportCmdIncremental = dir, exportFormatYAML, true, true
+
+	export := func(at time.Time) string {
+		t.Helper()
+		nowFunc = func() time.Time { return at }
+		var stderr bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(&stderr)
+		testhelpers.AssertNoError(t, runExport(cmd, nil))
+		return stderr.String()
+	}
+	modTime := func(file string) time.Time {
+		t.Helper()
+		info, err := os.Stat(filepath.Join(dir, file))
+		testhelpers.AssertNoError(t, err)
+		return info.ModTime()
+	}
+
+	exportedAt := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	testhelpers.AssertStringContains(t, export(exportedAt), "0 updated, 0 unchanged, 7 new")
+
+	// the unchanged files are left alone, including their modification time
+	past := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeFile := filepath.Join(exportMcpServersDir, "time.yaml")
+	testhelpers.AssertNoError(t, os.Chtimes(filepath.Join(dir, timeFile), past, past))
+	servers[0].URL = "https://mcp.context7.com/v2/mcp"
+	servers = append(servers[:2], servers[3:]...)
+	servers = append(servers, &types.RegisterServerInput{Name: "jira", Transport: "sse", URL: "https://mcp.atlassian.com/v1/sse"})
+	stderr := export(exportedAt.Add(time.Hour))
+	testhelpers.AssertStringContains(t, stderr, "1 updated, 5 unchanged, 1 new, 1 removed")
+	testhelpers.AssertStringContains(t, stderr, "linear.yaml belongs to an entity that no longer exists")
+	testhelpers.AssertTrue(t, modTime(timeFile).Equal(past), "an unchanged file should not be rewritten")
+	data, err := os.ReadFile(filepath.Join(dir, exportMcpServersDir, "context7.yaml"))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertStringContains(t, string(data), "https://mcp.context7.com/v2/mcp")
+	// the file of the removed server is only deleted with --prune
+	_, err = os.Stat(filepath.Join(dir, exportMcpServersDir, "linear.yaml"))
+	testhelpers.AssertNoError(t, err)
+
+	// an export that changes nothing keeps the manifest of the previous one
+	manifest, err := os.ReadFile(filepath.Join(dir, exportManifestFile))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertStringContains(t, export(exportedAt.Add(2*time.Hour)), "0 updated, 7 unchanged, 0 new, 1 removed")
+	again, err := os.ReadFile(filepath.Join(dir, exportManifestFile))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, string(manifest), string(again))
+	m, err := readExportManifest(dir)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, m.ExportedAt.Equal(exportedAt.Add(time.Hour)), "the manifest should keep the time of the last change")
+}
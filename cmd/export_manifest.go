@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// exportManifestFile is the file at the root of an export directory describing the export, see exportManifest.
+const exportManifestFile = "manifest.json"
+
+// exportManifestVersion is the version of the format of the manifest.
+const exportManifestVersion = 1
+
+// exportManifest records how an export directory was produced, and the checksums of the files it wrote,
+// so that `mcpjungle import` can tell whether they were modified afterwards.
+type exportManifest struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Registry   string    `json:"registry"`
+	// ServerVersion is the version of the mcpjungle server the entities were exported from, if it could be fetched
+	ServerVersion string `json:"server_version,omitempty"`
+	CLIVersion    string `json:"cli_version"`
+	Counts        struct {
+		Servers int `json:"servers"`
+		Groups  int `json:"groups"`
+		// Users and Clients are only exported in enterprise mode
+		Users   int `json:"users,omitempty"`
+		Clients int `json:"clients,omitempty"`
+	} `json:"counts"`
+	// Warnings are the warnings printed during the export, eg- the configurations that could not be fetched
+	Warnings []string `json:"warnings,omitempty"`
+	// Files are the SHA-256 checksums of the files of the export, by their slash-separated path in the directory
+	Files map[string]string `json:"files"`
+}
+
+// fileChecksum returns the hex-encoded SHA-256 checksum of a file.
+func fileChecksum(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// addFiles records the checksums of the files, given by their paths relative to the export directory.
+func (m *exportManifest) addFiles(root string, files ...string) error {
+	if m.Files == nil {
+		m.Files = make(map[string]string, len(files))
+	}
+	for _, f := range files {
+		sum, err := fileChecksum(filepath.Join(root, f))
+		if err != nil {
+			return fmt.Errorf("failed to compute the checksum of %s: %w", f, err)
+		}
+		m.Files[filepath.ToSlash(f)] = sum
+	}
+	return nil
+}
+
+// writeExportManifest writes the manifest at the root of the export directory, replacing the one of a previous export.
+func writeExportManifest(root string, m *exportManifest) error {
+	data, err := marshalConfig(m)
+	if err != nil {
+		return fmt.Errorf("failed to serialize %s: %w", exportManifestFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(root, exportManifestFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", exportManifestFile, err)
+	}
+	return nil
+}
+
+// readExportManifest reads the manifest of an export directory, it returns nil if the directory doesn't have one,
+// eg- if it was written by an older version of mcpjungle.
+func readExportManifest(root string) (*exportManifest, error) {
+	data, err := os.ReadFile(filepath.Join(root, exportManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", exportManifestFile, err)
+	}
+	var m exportManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", exportManifestFile, err)
+	}
+	return &m, nil
+}
+
+// updateExportManifest records the new checksum of a file rewritten in an export directory, eg- by
+// `mcpjungle export server`, if the directory has a manifest.
+func updateExportManifest(root, file string) error {
+	m, err := readExportManifest(root)
+	if err != nil || m == nil {
+		return err
+	}
+	if err := m.addFiles(root, file); err != nil {
+		return err
+	}
+	return writeExportManifest(root, m)
+}
+
+// verifyExportManifest checks the configuration files of an export directory against the checksums of its manifest,
+// and describes the files that were modified, removed or added since the export. A directory without a manifest
+// isn't checked.
+func verifyExportManifest(root string, files []string) ([]string, error) {
+	m, err := readExportManifest(root)
+	if err != nil || m == nil {
+		return nil, err
+	}
+	var mismatches []string
+	for _, f := range files {
+		want, ok := m.Files[filepath.ToSlash(f)]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s is not part of the export described by %s", f, exportManifestFile))
+			continue
+		}
+		sum, err := fileChecksum(filepath.Join(root, f))
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute the checksum of %s: %w", f, err)
+		}
+		if sum != want {
+			mismatches = append(mismatches, fmt.Sprintf("%s was modified since it was exported", f))
+		}
+	}
+	for _, f := range slices.Sorted(maps.Keys(m.Files)) {
+		// the users and clients aren't imported, so their files aren't looked for
+		if d := path.Dir(f); d != exportToolGroupsDir && d != exportMcpServersDir {
+			continue
+		}
+		if !slices.ContainsFunc(files, func(file string) bool { return filepath.ToSlash(file) == f }) {
+			mismatches = append(mismatches, fmt.Sprintf("%s was exported but is missing", f))
+		}
+	}
+	return mismatches, nil
+}
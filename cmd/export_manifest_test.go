@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func TestExportManifest(t *testing.T) {
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/version": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, types.ServerVersion{Version: "v0.9.0"})
+		},
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{Name: "ops"}})
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, testMCPJSONServers())
+		},
+		"GET /api/v1/server_configs/{name}": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, testMCPJSONServers()[0])
+		},
+	})
+	exportedAt := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	origNow := nowFunc
+	t.Cleanup(func() { nowFunc = origNow })
+	nowFunc = func() time.Time { return exportedAt }
+	origDir, origFormat, origServers, origForce := exportCmdTargetDir, exportCmdFormat, exportCmdServers, exportCmdForce
+	t.Cleanup(func() {
+		exportCmdTargetDir, exportCmdFormat, exportCmdServers, exportCmdForce = origDir, origFormat, origServers, origForce
+	})
+	dir := filepath.Join(t.TempDir(), "export")
+	exportCmdTargetDir, exportCmdFormat, exportCmdServers, exportCmdForce = dir, exportFormatJSON, []string{"*", "slack"}, false
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+
+	m, err := readExportManifest(dir)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNotNil(t, m)
+	testhelpers.AssertEqual(t, exportManifestVersion, m.Version)
+	testhelpers.AssertTrue(t, m.ExportedAt.Equal(exportedAt), "the manifest should record the time of the export")
+	testhelpers.AssertEqual(t, "v0.9.0", m.ServerVersion)
+	testhelpers.AssertEqual(t, len(testMCPJSONServers()), m.Counts.Servers)
+	testhelpers.AssertEqual(t, 1, m.Counts.Groups)
+	testhelpers.AssertEqual(t, 1, len(m.Warnings))
+	testhelpers.AssertStringContains(t, m.Warnings[0], "no MCP server matches the patterns: slack")
+	testhelpers.AssertEqual(t, len(testMCPJSONServers())+1, len(m.Files))
+	sum, err := fileChecksum(filepath.Join(dir, exportMcpServersDir, "context7.json"))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, sum, m.Files["servers/context7.json"])
+
+	// the files of the directory match the manifest
+	scanned, err := scanBundleDir(dir)
+	testhelpers.AssertNoError(t, err)
+	mismatches, err := verifyExportManifest(dir, scanned.files())
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 0, len(mismatches))
+
+	// refreshing a server keeps the manifest up to date
+	withExportEntityFlags(t, dir, exportFormatJSON, false)
+	testhelpers.AssertNoError(t, runExportServer(cmd, []string{"context7"}))
+	mismatches, err = verifyExportManifest(dir, scanned.files())
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 0, len(mismatches))
+
+	// the modified, removed and added files are reported
+	testhelpers.AssertNoError(t, os.WriteFile(filepath.Join(dir, exportMcpServersDir, "time.json"), []byte(`{"name": "time"}`), 0o644))
+	testhelpers.AssertNoError(t, os.Remove(filepath.Join(dir, exportMcpServersDir, "linear.json")))
+	testhelpers.AssertNoError(t, os.WriteFile(filepath.Join(dir, exportMcpServersDir, "jira.json"), []byte(`{"name": "jira"}`), 0o644))
+	scanned, err = scanBundleDir(dir)
+	testhelpers.AssertNoError(t, err)
+	mismatches, err = verifyExportManifest(dir, scanned.files())
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 3, len(mismatches))
+	testhelpers.AssertStringContains(t, mismatches[0], "servers/jira.json is not part of the export")
+	testhelpers.AssertStringContains(t, mismatches[1], "servers/time.json was modified since it was exported")
+	testhelpers.AssertStringContains(t, mismatches[2], "servers/linear.json was exported but is missing")
+
+	// a directory holding only a manifest is empty
+	testhelpers.AssertNoError(t, os.RemoveAll(filepath.Join(dir, exportMcpServersDir)))
+	testhelpers.AssertNoError(t, os.RemoveAll(filepath.Join(dir, exportToolGroupsDir)))
+	_, err = resolveTargetDirForExport()
+	testhelpers.AssertNoError(t, err)
+}
+
+func TestImportVerifiesManifest(t *testing.T) {
+	dir := t.TempDir()
+	testhelpers.AssertNoError(t, os.MkdirAll(filepath.Join(dir, exportMcpServersDir), 0o755))
+	file := filepath.Join(exportMcpServersDir, "time.json")
+	testhelpers.AssertNoError(t, os.WriteFile(filepath.Join(dir, file), []byte(`{"name": "time", "transport": "stdio", "command": "uvx"}`), 0o644))
+	m := &exportManifest{Version: exportManifestVersion}
+	testhelpers.AssertNoError(t, m.addFiles(dir, file))
+	testhelpers.AssertNoError(t, writeExportManifest(dir, m))
+	testhelpers.AssertNoError(t, os.WriteFile(filepath.Join(dir, file), []byte(`{"name": "time", "transport": "stdio", "command": "npx"}`), 0o644))
+
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []*types.McpServer{{Name: "time"}})
+		},
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{})
+		},
+	})
+	origDir, origUpsert, origDryRun := importBundleDir, importBundleUpsert, importBundleDryRun
+	t.Cleanup(func() { importBundleDir, importBundleUpsert, importBundleDryRun = origDir, origUpsert, origDryRun })
+	importBundleDir, importBundleUpsert, importBundleDryRun = dir, false, false
+
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(&stderr)
+	// the modified file is imported anyway
+	testhelpers.AssertNoError(t, runImportBundle(cmd, nil))
+	testhelpers.AssertStringContains(t, stderr.String(), "servers/time.json was modified since it was exported")
+}
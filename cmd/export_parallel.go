@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// defaultExportConcurrency is how many configurations an export fetches at the same time by default.
+const defaultExportConcurrency = 5
+
+// exportJob is the export of the configuration of an entity to its file in an export directory.
+type exportJob struct {
+	// entity describes the entity in the messages, eg- MCP server github
+	entity string
+	// dir is the subdirectory of the file, eg- servers
+	dir      string
+	fileName string
+	// fetch returns the configuration of the entity, as it is written to its file
+	fetch func(ctx context.Context) (any, error)
+}
+
+// file returns the path of the file of the entity, relative to the export directory.
+func (j *exportJob) file(format string) string {
+	return filepath.Join(j.dir, j.fileName+"."+format)
+}
+
+// exportJobResult is the configuration a worker fetched for a job, or the error it failed with.
+type exportJobResult struct {
+	job    *exportJob
+	config any
+	err    error
+}
+
+// fetchExportConfigs fetches the configurations of the jobs with a pool of concurrency workers.
+// The results are sent in the order they arrive, the channel is closed once all the jobs are done.
+func fetchExportConfigs(ctx context.Context, jobs []*exportJob, concurrency int) <-chan exportJobResult {
+	queue := make(chan *exportJob)
+	results := make(chan exportJobResult)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				config, err := j.fetch(ctx)
+				results <- exportJobResult{job: j, config: config, err: err}
+			}
+		}()
+	}
+	go func() {
+		for _, j := range jobs {
+			queue <- j
+		}
+		close(queue)
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// runExportJobs fetches the configurations of the jobs concurrently and writes every one of them to its file as soon
+// as it arrives, printing the progress of the export. The files are only written by the calling goroutine.
+// An entity that can't be exported doesn't stop the export of the others: the failures are returned, and the files
+// written are added to the manifest. It returns the number of entities exported to every subdirectory.
+func runExportJobs(
+	ctx context.Context, p *printer, targetDir, format string, jobs []*exportJob, concurrency int,
+	manifest *exportManifest, changes *exportChanges,
+) (map[string]int, []error) {
+	exported := make(map[string]int)
+	var failures []error
+	done := 0
+	for r := range fetchExportConfigs(ctx, jobs, concurrency) {
+		done++
+		err := r.err
+		if err == nil {
+			err = writeExportFile(filepath.Join(targetDir, r.job.dir), r.job.fileName, format, r.config, changes)
+		}
+		if err == nil {
+			err = manifest.addFiles(targetDir, r.job.file(format))
+		}
+		if err != nil {
+			failures = append(failures, fmt.Errorf("failed to export %s: %w", r.job.entity, err))
+			p.Infof("[%d/%d] failed to export %s\n", done, len(jobs), r.job.entity)
+			continue
+		}
+		exported[r.job.dir]++
+		p.Infof("[%d/%d] exported %s\n", done, len(jobs), filepath.ToSlash(r.job.file(format)))
+	}
+	return exported, failures
+}
+
+// fetchExportServerConfig fetches the configuration of a listed server, with its secrets redacted unless the export
+// writes them. The server is addressed by its UUID if it has one, which is safe in a URL whatever its name.
+func fetchExportServerConfig(ctx context.Context, s *types.McpServer) (*types.RegisterServerInput, error) {
+	id := s.Name
+	if s.UUID != "" {
+		id = s.UUID
+	}
+	config, err := apiClient.GetServerConfig(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	redactExportSecrets(config)
+	return config, nil
+}
+
+// exportElapsed formats the time an export took, eg- 1.2s.
+func exportElapsed(start time.Time) string {
+	return time.Since(start).Round(100 * time.Millisecond).String()
+}
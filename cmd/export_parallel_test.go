@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func TestExportConcurrently(t *testing.T) {
+	var servers []*types.McpServer
+	for i := range 12 {
+		servers = append(servers, &types.McpServer{Name: fmt.Sprintf("server-%02d", i)})
+	}
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{Name: "ops", IncludedServers: []string{"server-01"}}})
+		},
+		"GET /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, servers)
+		},
+		"GET /api/v1/server_configs/{name}": func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			inFlight++
+			maxInFlight = max(maxInFlight, inFlight)
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+
+			name := r.PathValue("name")
+			if name == "server-07" {
+				writeTestJSON(w, http.StatusInternalServerError, types.ErrorResponse{
+					Error: types.APIError{Message: "vault is sealed"},
+				})
+				return
+			}
+			writeTestJSON(w, http.StatusOK, &types.RegisterServerInput{
+				Name: name, Transport: "streamable_http", URL: "https://" + name + ".example.com/mcp",
+			})
+		},
+	})
+	origDir, origConcurrency := exportCmdTargetDir, exportCmdConcurrency
+	t.Cleanup(func() { exportCmdTargetDir, exportCmdConcurrency = origDir, origConcurrency })
+	dir := filepath.Join(t.TempDir(), "export")
+	exportCmdTargetDir, exportCmdConcurrency = dir, 3
+
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(&stderr)
+	// the entities that can't be exported don't stop the export of the others
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+
+	testhelpers.AssertTrue(t, maxInFlight <= 3, fmt.Sprintf("%d configurations fetched at the same time", maxInFlight))
+	testhelpers.AssertTrue(t, maxInFlight > 1, "the configurations should be fetched concurrently")
+	for _, want := range []string{
+		"exported groups/ops.json",
+		"exported servers/server-00.json",
+		"[13/13] ",
+		"failed to export MCP server server-07: vault is sealed",
+		"Export complete in ",
+		"1 of 13 entities could not be exported",
+	} {
+		testhelpers.AssertStringContains(t, stderr.String(), want)
+	}
+	_, err := os.Stat(filepath.Join(dir, exportMcpServersDir, "server-07.json"))
+	testhelpers.AssertTrue(t, os.IsNotExist(err), "the file of the server that failed should not be written")
+
+	m, err := readExportManifest(dir)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 11, m.Counts.Servers)
+	testhelpers.AssertEqual(t, 1, m.Counts.Groups)
+	testhelpers.AssertEqual(t, 12, len(m.Files))
+	testhelpers.AssertStringContains(t, fmt.Sprint(m.Warnings), "failed to export MCP server server-07")
+
+	exportCmdConcurrency = 0
+	testhelpers.AssertError(t, runExport(newExitCodeTestCmd(), nil))
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+const (
+	secretPlaceholderPrefix = "${MCPJUNGLE_SECRET:"
+	secretPlaceholderSuffix = "}"
+)
+
+// secretPlaceholder returns the placeholder an export writes instead of the value of a secret field of a server,
+// eg- ${MCPJUNGLE_SECRET:github/bearer_token}. It names the server and the field the secret is read from on import.
+func secretPlaceholder(server, field string) string {
+	return secretPlaceholderPrefix + server + "/" + field + secretPlaceholderSuffix
+}
+
+// parseSecretPlaceholder returns the server and the field a placeholder names, ok is false if v isn't a placeholder.
+func parseSecretPlaceholder(v string) (server, field string, ok bool) {
+	rest, ok := strings.CutPrefix(v, secretPlaceholderPrefix)
+	if !ok {
+		return "", "", false
+	}
+	rest, ok = strings.CutSuffix(rest, secretPlaceholderSuffix)
+	if !ok {
+		return "", "", false
+	}
+	server, field, ok = strings.Cut(rest, "/")
+	return server, field, ok && server != "" && field != ""
+}
+
+// exportsSecrets reports whether the export writes the secrets of the servers in plaintext, see redactSecrets.
+func exportsSecrets() bool {
+	return exportCmdIncludeSecrets || !exportCmdRedactSecrets
+}
+
+// redactExportSecrets replaces the secrets of the exported servers with placeholders, unless --include-secrets is set.
+func redactExportSecrets(servers ...*types.RegisterServerInput) {
+	if exportsSecrets() {
+		return
+	}
+	for _, s := range servers {
+		redactSecrets(s)
+	}
+}
+
+// redactSecrets replaces the value of every secret field of the server with a placeholder, see secretPlaceholder.
+// The Vault references are kept, since they don't hold the secrets.
+func redactSecrets(s *types.RegisterServerInput) {
+	s.MapSecrets(func(field, value string) string {
+		if types.IsVaultReference(value) {
+			return value
+		}
+		return secretPlaceholder(s.Name, field)
+	})
+}
+
+// hasSecretPlaceholders reports whether any of the servers has a secret redacted by an export.
+func hasSecretPlaceholders(servers []*types.RegisterServerInput) bool {
+	for _, s := range servers {
+		found := false
+		s.MapSecrets(func(field, value string) string {
+			_, _, ok := parseSecretPlaceholder(value)
+			found = found || ok
+			return value
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveSecretPlaceholders replaces the placeholders of the secrets of a server with the values of the fields they
+// name in the live configurations, so that importing a redacted export keeps the secrets of the registry.
+// It fails if a placeholder names a server or a field that has no value in the registry, eg- a server that
+// doesn't exist yet: its secrets must then be set in the file before it is imported.
+func resolveSecretPlaceholders(s *types.RegisterServerInput, live []*types.RegisterServerInput) error {
+	secrets := make(map[string]string)
+	for _, l := range live {
+		l.MapSecrets(func(field, value string) string {
+			secrets[l.Name+"/"+field] = value
+			return value
+		})
+	}
+	var missing []string
+	s.MapSecrets(func(field, value string) string {
+		server, from, ok := parseSecretPlaceholder(value)
+		if !ok {
+			return value
+		}
+		resolved, found := secrets[server+"/"+from]
+		if !found {
+			missing = append(missing, field)
+			return value
+		}
+		return resolved
+	})
+	if len(missing) > 0 {
+		return fmt.Errorf(
+			"the secrets %s are redacted and the registry has no value for them, set them in the configuration",
+			strings.Join(missing, ", "),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func withExportSecrets(t *testing.T, include bool) {
+	t.Helper()
+	origRedact, origInclude := exportCmdRedactSecrets, exportCmdIncludeSecrets
+	t.Cleanup(func() { exportCmdRedactSecrets, exportCmdIncludeSecrets = origRedact, origInclude })
+	exportCmdRedactSecrets, exportCmdIncludeSecrets = true, include
+}
+
+func TestParseSecretPlaceholder(t *testing.T) {
+	server, field, ok := parseSecretPlaceholder(secretPlaceholder("github", "env.GITHUB_TOKEN"))
+	testhelpers.AssertTrue(t, ok, "a placeholder should be parsed")
+	testhelpers.AssertEqual(t, "github", server)
+	testhelpers.AssertEqual(t, "env.GITHUB_TOKEN", field)
+
+	for _, v := range []string{"ghp_token", "${GITHUB_TOKEN}", "${MCPJUNGLE_SECRET:github}", "${MCPJUNGLE_SECRET:/token}"} {
+		_, _, ok := parseSecretPlaceholder(v)
+		testhelpers.AssertFalse(t, ok, v+" should not be a placeholder")
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	s := &types.RegisterServerInput{
+		Name:        "github",
+		Transport:   "stdio",
+		Command:     "github-mcp-server",
+		BearerToken: "ghp_token",
+		Env:         map[string]string{"GITHUB_TOKEN": "vault:secret/mcp/github#token", "LOG_LEVEL": "debug"},
+	}
+	redactSecrets(s)
+	testhelpers.AssertEqual(t, "${MCPJUNGLE_SECRET:github/bearer_token}", s.BearerToken)
+	testhelpers.AssertEqual(t, "${MCPJUNGLE_SECRET:github/env.LOG_LEVEL}", s.Env["LOG_LEVEL"])
+	// a Vault reference doesn't hold the secret
+	testhelpers.AssertEqual(t, "vault:secret/mcp/github#token", s.Env["GITHUB_TOKEN"])
+	testhelpers.AssertTrue(t, hasSecretPlaceholders([]*types.RegisterServerInput{s}), "the placeholders should be found")
+
+	live := []*types.RegisterServerInput{{
+		Name: "github", BearerToken: "ghp_live", Env: map[string]string{"LOG_LEVEL": "info"},
+	}}
+	testhelpers.AssertNoError(t, resolveSecretPlaceholders(s, live))
+	testhelpers.AssertEqual(t, "ghp_live", s.BearerToken)
+	testhelpers.AssertEqual(t, "info", s.Env["LOG_LEVEL"])
+	testhelpers.AssertFalse(t, hasSecretPlaceholders([]*types.RegisterServerInput{s}), "the placeholders should be resolved")
+
+	// the secrets of a server that doesn't exist can't be resolved
+	other := &types.RegisterServerInput{Name: "jira", BearerToken: secretPlaceholder("jira", "bearer_token")}
+	err := resolveSecretPlaceholders(other, live)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "the secrets bearer_token are redacted")
+}
+
+func TestExportSecrets(t *testing.T) {
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{})
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, testMCPJSONServers())
+		},
+	})
+	origDir, origFormat := exportCmdTargetDir, exportCmdFormat
+	t.Cleanup(func() { exportCmdTargetDir, exportCmdFormat = origDir, origFormat })
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	for _, include := range []bool{false, true} {
+		withExportSecrets(t, include)
+		exportCmdTargetDir, exportCmdFormat = filepath.Join(t.TempDir(), "export"), exportFormatJSON
+		testhelpers.AssertNoError(t, runExport(cmd, nil))
+		data, err := os.ReadFile(filepath.Join(exportCmdTargetDir, exportMcpServersDir, "context7.json"))
+		testhelpers.AssertNoError(t, err)
+		if include {
+			testhelpers.AssertStringContains(t, string(data), `"bearer_token": "ctx7-token"`)
+		} else {
+			testhelpers.AssertStringContains(t, string(data), `"bearer_token": "${MCPJUNGLE_SECRET:context7/bearer_token}"`)
+			testhelpers.AssertStringNotContains(t, string(data), "ctx7-token")
+		}
+	}
+
+	// --redact-secrets=false exports the secrets too
+	exportCmdRedactSecrets, exportCmdIncludeSecrets = false, false
+	testhelpers.AssertTrue(t, exportsSecrets(), "the secrets should be exported with --redact-secrets=false")
+}
+
+func TestImportRedactedSecrets(t *testing.T) {
+	dir := t.TempDir()
+	testhelpers.AssertNoError(t, os.MkdirAll(filepath.Join(dir, exportMcpServersDir), 0o755))
+	for name, url := range map[string]string{"github": "https://api.githubcopilot.com/mcp/", "jira": "https://mcp.atlassian.com/v1/sse"} {
+		data, err := marshalConfig(&types.RegisterServerInput{
+			Name: name, Transport: "streamable_http", URL: url, BearerToken: secretPlaceholder(name, "bearer_token"),
+		})
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertNoError(t, os.WriteFile(filepath.Join(dir, exportMcpServersDir, name+".json"), data, 0o644))
+	}
+
+	var updated []string
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []*types.McpServer{{Name: "github"}})
+		},
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{})
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []*types.RegisterServerInput{{
+				Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/", BearerToken: "ghp_live",
+			}})
+		},
+		"PUT /api/v1/servers/{name}": func(w http.ResponseWriter, r *http.Request) {
+			var input types.RegisterServerInput
+			_ = json.NewDecoder(r.Body).Decode(&input)
+			updated = append(updated, input.Name+":"+input.BearerToken)
+			writeTestJSON(w, http.StatusOK, &types.McpServer{Name: input.Name})
+		},
+	})
+	origDir, origUpsert, origDryRun := importBundleDir, importBundleUpsert, importBundleDryRun
+	t.Cleanup(func() { importBundleDir, importBundleUpsert, importBundleDryRun = origDir, origUpsert, origDryRun })
+	importBundleDir, importBundleUpsert, importBundleDryRun = dir, true, false
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	// the server that exists keeps its secret, the new one is skipped since it can't be registered without it
+	testhelpers.AssertNoError(t, runImportBundle(cmd, nil))
+	testhelpers.AssertEqual(t, "github:ghp_live", strings.Join(updated, ","))
+	testhelpers.AssertStringContains(t, out.String(), "skipped: the secrets bearer_token are redacted")
+}
@@ -1,10 +1,23 @@
 package cmd
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
 )
 
 func TestResolveTargetDirForExport(t *testing.T) {
@@ -12,6 +25,7 @@ func TestResolveTargetDirForExport(t *testing.T) {
 		name          string
 		setup         func() (string, error)
 		cleanup       func(string)
+		force         bool
 		expectedError bool
 		validateDir   func(t *testing.T, dir string)
 	}{
@@ -153,6 +167,46 @@ func TestResolveTargetDirForExport(t *testing.T) {
 			expectedError: true,
 			validateDir:   func(t *testing.T, dir string) {},
 		},
+		{
+			name: "directory not empty with force",
+			setup: func() (string, error) {
+				tmpDir := t.TempDir()
+				exportCmdTargetDir = tmpDir
+				_ = os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte("test"), 0o644)
+				return tmpDir, nil
+			},
+			cleanup: func(dir string) {
+				_ = os.RemoveAll(dir)
+			},
+			force:         true,
+			expectedError: false,
+			validateDir: func(t *testing.T, dir string) {
+				// the files of the directory are left as they are
+				if _, err := os.Stat(filepath.Join(dir, "test.txt")); err != nil {
+					t.Errorf("expected the existing file to be kept: %v", err)
+				}
+			},
+		},
+		{
+			name: "previous export with force",
+			setup: func() (string, error) {
+				tmpDir := t.TempDir()
+				exportCmdTargetDir = tmpDir
+				_ = os.MkdirAll(filepath.Join(tmpDir, exportMcpServersDir), 0o755)
+				_ = os.WriteFile(filepath.Join(tmpDir, exportMcpServersDir, "github.json"), []byte("{}"), 0o644)
+				return tmpDir, nil
+			},
+			cleanup: func(dir string) {
+				_ = os.RemoveAll(dir)
+			},
+			force:         true,
+			expectedError: false,
+			validateDir: func(t *testing.T, dir string) {
+				if _, err := os.Stat(filepath.Join(dir, exportMcpServersDir, "github.json")); err != nil {
+					t.Errorf("expected the previous export to be kept: %v", err)
+				}
+			},
+		},
 		{
 			name: "invalid permissions for directory creation",
 			setup: func() (string, error) {
@@ -193,6 +247,9 @@ func TestResolveTargetDirForExport(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			expectedDir, _ := tt.setup()
 			defer tt.cleanup(expectedDir)
+			origForce := exportCmdForce
+			defer func() { exportCmdForce = origForce }()
+			exportCmdForce = tt.force
 
 			result, err := resolveTargetDirForExport()
 
@@ -206,3 +263,333 @@ func TestResolveTargetDirForExport(t *testing.T) {
 		})
 	}
 }
+
+func TestServerConfigYAMLRoundTrip(t *testing.T) {
+	for _, server := range testMCPJSONServers() {
+		data, err := marshalConfigAs(configFormatYAML, server)
+		testhelpers.AssertNoError(t, err)
+		// the YAML has the field names of the API, so that it can be registered again
+		testhelpers.AssertStringContains(t, string(data), "transport: "+server.Transport)
+		testhelpers.AssertStringNotContains(t, string(data), "{")
+
+		inputs, err := decodeConfigEntities[types.RegisterServerInput](data)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 1, len(inputs))
+		want, _ := json.Marshal(server)
+		got, _ := json.Marshal(inputs[0])
+		testhelpers.AssertEqual(t, string(want), string(got))
+	}
+
+	data, err := marshalConfigAs(configFormatYAML, testMCPJSONServers()[0])
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertStringContains(t, string(data), "bearer_token: ctx7-token")
+	testhelpers.AssertStringContains(t, string(data), "max_idle_conns_per_host: 4")
+}
+
+func TestExportYAML(t *testing.T) {
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{Name: "ops", IncludedServers: []string{"context7"}}})
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, testMCPJSONServers())
+		},
+	})
+	origDir, origFormat := exportCmdTargetDir, exportCmdFormat
+	t.Cleanup(func() { exportCmdTargetDir, exportCmdFormat = origDir, origFormat })
+	exportCmdTargetDir, exportCmdFormat = filepath.Join(t.TempDir(), "export"), exportFormatYAML
+	withExportSecrets(t, true)
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+
+	// both kinds of entities are written in YAML
+	_, err := os.Stat(filepath.Join(exportCmdTargetDir, exportToolGroupsDir, "ops.yaml"))
+	testhelpers.AssertNoError(t, err)
+	data, err := os.ReadFile(filepath.Join(exportCmdTargetDir, exportMcpServersDir, "filesystem.yaml"))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertStringContains(t, string(data), "lazy_start: true")
+	matches, _ := filepath.Glob(filepath.Join(exportCmdTargetDir, "*", "*.json"))
+	testhelpers.AssertEqual(t, 0, len(matches))
+
+	// the export is read back like a JSON one
+	bundle, err := readBundleDir(exportCmdTargetDir)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(bundle.Groups))
+	testhelpers.AssertEqual(t, "context7", bundle.Groups[0].IncludedServers[0])
+	servers := testMCPJSONServers()
+	testhelpers.AssertEqual(t, len(servers), len(bundle.Servers))
+	for _, read := range bundle.Servers {
+		i := slices.IndexFunc(servers, func(s *types.RegisterServerInput) bool { return s.Name == read.Name })
+		want, _ := json.Marshal(servers[i])
+		got, _ := json.Marshal(read)
+		testhelpers.AssertEqual(t, string(want), string(got))
+	}
+
+	// the files can be registered again
+	inputs, err := readMcpServerConfigs(cmd, filepath.Join(exportCmdTargetDir, exportMcpServersDir, "filesystem.yaml"))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "npx", inputs[0].Command)
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	exportCmdFormat = exportFormatJSON
+	exportCmdTargetDir = filepath.Join(t.TempDir(), "export")
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+	_, err = os.Stat(filepath.Join(exportCmdTargetDir, exportMcpServersDir, "filesystem.json"))
+	testhelpers.AssertNoError(t, err)
+}
+
+func TestExportForce(t *testing.T) {
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{Name: "ops"}})
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, testMCPJSONServers())
+		},
+	})
+	origDir, origFormat, origServers := exportCmdTargetDir, exportCmdFormat, exportCmdServers
+	origForce, origPrune := exportCmdForce, exportCmdPrune
+	t.Cleanup(func() {
+		exportCmdTargetDir, exportCmdFormat, exportCmdServers = origDir, origFormat, origServers
+		exportCmdForce, exportCmdPrune = origForce, origPrune
+	})
+
+	// a previous export, kept in a git repository, with the file of a server that is gone
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		testhelpers.AssertNoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		testhelpers.AssertNoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	writeFile(".git/HEAD", "ref: refs/heads/main\n")
+	writeFile("servers/context7.json", "{}")
+	writeFile("servers/removed.json", `{"name": "removed"}`)
+	writeFile("servers/README.md", "the servers of the registry")
+	exportCmdTargetDir, exportCmdFormat, exportCmdServers = dir, exportFormatJSON, nil
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	exportCmdForce, exportCmdPrune = false, false
+	err := runExport(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "is not empty, use --force to export into it")
+
+	// the files of the exported servers are overwritten, the stale ones are kept
+	exportCmdForce = true
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+	data, err := os.ReadFile(filepath.Join(dir, exportMcpServersDir, "context7.json"))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertStringContains(t, string(data), "https://mcp.context7.com/mcp")
+	_, err = os.Stat(filepath.Join(dir, exportMcpServersDir, "removed.json"))
+	testhelpers.AssertNoError(t, err)
+
+	// --prune removes the files of the servers that are gone and the ones written in another format,
+	// but not the files of the servers the filter leaves out
+	exportCmdPrune, exportCmdFormat, exportCmdServers = true, exportFormatYAML, []string{"context7"}
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+	files, _ := filepath.Glob(filepath.Join(dir, exportMcpServersDir, "*"))
+	for i := range files {
+		files[i] = filepath.Base(files[i])
+	}
+	testhelpers.AssertEqual(
+		t, "README.md,context7.yaml,filesystem.json,linear.json,petstore.json,sandboxed.json,time.json",
+		strings.Join(files, ","),
+	)
+	_, err = os.Stat(filepath.Join(dir, ".git", "HEAD"))
+	testhelpers.AssertNoError(t, err)
+
+	exportCmdForce = false
+	err = runExport(cmd, nil)
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+}
+
+func TestExportToStdout(t *testing.T) {
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{Name: "ops", IncludedServers: []string{"context7"}}})
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, testMCPJSONServers())
+		},
+	})
+	origDir, origFormat, origStdout := exportCmdTargetDir, exportCmdFormat, exportCmdStdout
+	origGroups := exportCmdGroups
+	t.Cleanup(func() {
+		exportCmdTargetDir, exportCmdFormat, exportCmdStdout = origDir, origFormat, origStdout
+		exportCmdGroups = origGroups
+	})
+	exportCmdTargetDir, exportCmdFormat, exportCmdStdout = filepath.Join(t.TempDir(), "export"), exportFormatJSON, true
+
+	var out, stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetErr(&stderr)
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+	_, err := os.Stat(exportCmdTargetDir)
+	testhelpers.AssertTrue(t, os.IsNotExist(err), "the export to stdout should not write to the filesystem")
+	// stdout only holds the document, the messages are written to stderr
+	var bundle exportBundle
+	testhelpers.AssertNoError(t, json.Unmarshal(out.Bytes(), &bundle))
+	testhelpers.AssertEqual(t, len(testMCPJSONServers()), len(bundle.Servers))
+	testhelpers.AssertEqual(t, "ops", bundle.Groups[0].Name)
+	testhelpers.AssertStringContains(t, stderr.String(), "Fetching")
+
+	// the YAML document has the same shape, and the empty arrays are kept
+	out.Reset()
+	exportCmdFormat, exportCmdGroups = exportFormatYAML, []string{"dev"}
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+	testhelpers.AssertStringContains(t, out.String(), "servers:\n")
+	testhelpers.AssertStringContains(t, out.String(), "groups: []\n")
+	bundles, err := decodeConfigEntities[exportBundle](out.Bytes())
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, len(testMCPJSONServers()), len(bundles[0].Servers))
+	testhelpers.AssertEqual(t, 0, len(bundles[0].Groups))
+}
+
+// readArchiveFiles returns the content of the regular files of a gzip compressed tar archive by their names.
+func readArchiveFiles(t *testing.T, path string) map[string]string {
+	t.Helper()
+	f, err := os.Open(path)
+	testhelpers.AssertNoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	testhelpers.AssertNoError(t, err)
+	files := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		testhelpers.AssertNoError(t, err)
+		if hdr.Typeflag == tar.TypeReg {
+			data, err := io.ReadAll(tr)
+			testhelpers.AssertNoError(t, err)
+			files[hdr.Name] = string(data)
+		}
+	}
+}
+
+func TestExportToArchive(t *testing.T) {
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{Name: "ops", IncludedServers: []string{"context7"}}})
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, testMCPJSONServers())
+		},
+	})
+	origDir, origFormat, origArchive, origForce := exportCmdTargetDir, exportCmdFormat, exportCmdArchive, exportCmdForce
+	t.Cleanup(func() {
+		exportCmdTargetDir, exportCmdFormat, exportCmdArchive, exportCmdForce = origDir, origFormat, origArchive, origForce
+	})
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	for _, format := range []string{exportFormatJSON, exportFormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			// the archive holds the files of an export to a directory
+			exportCmdFormat, exportCmdArchive, exportCmdForce = format, "", false
+			exportCmdTargetDir = filepath.Join(t.TempDir(), "export")
+			testhelpers.AssertNoError(t, runExport(cmd, nil))
+			want := make(map[string]string)
+			err := filepath.WalkDir(exportCmdTargetDir, func(name string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				data, err := os.ReadFile(name)
+				rel, _ := filepath.Rel(exportCmdTargetDir, name)
+				want[filepath.ToSlash(rel)] = string(data)
+				return err
+			})
+			testhelpers.AssertNoError(t, err)
+
+			exportCmdArchive = filepath.Join(t.TempDir(), "export.tar.gz")
+			testhelpers.AssertNoError(t, runExport(cmd, nil))
+			got := readArchiveFiles(t, exportCmdArchive)
+			testhelpers.AssertEqual(t, len(want), len(got))
+			for name, data := range want {
+				if name == exportManifestFile {
+					// the manifests only differ by the time of the export
+					var wantManifest, gotManifest exportManifest
+					testhelpers.AssertNoError(t, json.Unmarshal([]byte(data), &wantManifest))
+					testhelpers.AssertNoError(t, json.Unmarshal([]byte(got[name]), &gotManifest))
+					testhelpers.AssertEqual(t, fmt.Sprint(wantManifest.Files), fmt.Sprint(gotManifest.Files))
+					continue
+				}
+				testhelpers.AssertEqual(t, data, got[name])
+			}
+
+			// an existing archive is only overwritten with --force
+			err = runExport(cmd, nil)
+			testhelpers.AssertError(t, err)
+			testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+			testhelpers.AssertStringContains(t, err.Error(), "use --force to overwrite it")
+			exportCmdForce = true
+			testhelpers.AssertNoError(t, runExport(cmd, nil))
+			leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(exportCmdArchive), ".*.tmp"))
+			testhelpers.AssertEqual(t, 0, len(leftovers))
+		})
+	}
+
+	t.Run("excluded types", func(t *testing.T) {
+		origExclude := exportCmdExclude
+		t.Cleanup(func() { exportCmdExclude = origExclude })
+		exportCmdFormat, exportCmdExclude, exportCmdForce = exportFormatJSON, []string{exportMcpServersDir}, false
+		exportCmdArchive = filepath.Join(t.TempDir(), "export.tar.gz")
+		testhelpers.AssertNoError(t, runExport(cmd, nil))
+
+		f, err := os.Open(exportCmdArchive)
+		testhelpers.AssertNoError(t, err)
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		testhelpers.AssertNoError(t, err)
+		var names []string
+		tr := tar.NewReader(gz)
+		for hdr, err := tr.Next(); err != io.EOF; hdr, err = tr.Next() {
+			testhelpers.AssertNoError(t, err)
+			names = append(names, hdr.Name)
+		}
+		// only the included types have a directory
+		testhelpers.AssertEqual(t, "[groups/ groups/ops.json manifest.json]", fmt.Sprint(names))
+	})
+
+	exportCmdFormat, exportCmdArchive = exportFormatMCPJSON, filepath.Join(t.TempDir(), "export.tar.gz")
+	err := runExport(cmd, nil)
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+}
+
+func TestExportToArchiveIncomplete(t *testing.T) {
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{})
+		},
+		"GET /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []*types.McpServer{{Name: "github"}})
+		},
+		"GET /api/v1/server_configs/{name}": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusInternalServerError, types.ErrorResponse{
+				Error: types.APIError{Message: "vault is sealed"},
+			})
+		},
+	})
+	origFormat, origArchive := exportCmdFormat, exportCmdArchive
+	t.Cleanup(func() { exportCmdFormat, exportCmdArchive = origFormat, origArchive })
+	exportCmdFormat, exportCmdArchive = exportFormatJSON, filepath.Join(t.TempDir(), "export.tar.gz")
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	// an archive missing entities isn't written, unlike a directory
+	err := runExport(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "the export is incomplete")
+	_, err = os.Stat(exportCmdArchive)
+	testhelpers.AssertTrue(t, os.IsNotExist(err), "no archive should be written")
+}
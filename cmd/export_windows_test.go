@@ -0,0 +1,31 @@
+//go:build windows
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func TestResolveTargetDirForExportWindowsPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("USERPROFILE", home)
+	t.Cleanup(func() { exportCmdTargetDir = "" })
+
+	exportCmdTargetDir = `~\exports\mcpjungle`
+	dir, err := resolveTargetDirForExport()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, filepath.Join(home, "exports", "mcpjungle"), dir)
+
+	// forward and back slashes can be mixed
+	exportCmdTargetDir = strings.ReplaceAll(filepath.Join(home, "mixed", "dir"), `\`, "/")
+	dir, err = resolveTargetDirForExport()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, filepath.Join(home, "mixed", "dir"), dir)
+	_, err = os.Stat(dir)
+	testhelpers.AssertNoError(t, err)
+}
@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// composeImage is the image of the mcpjungle services of the generated compose file.
+	// The stdio image is used since the gateway runs the stdio servers itself.
+	composeImage = "mcpjungle/mcpjungle:${MCPJUNGLE_IMAGE_TAG:-latest-stdio}"
+
+	composeDBService       = "db"
+	composeGatewayService  = "mcpjungle"
+	composeRegisterService = "mcpjungle-register"
+
+	// composeServersConfig is the compose config holding the registrations of the servers, and where it is mounted.
+	composeServersConfig       = "mcpjungle_servers"
+	composeServersConfigTarget = "/etc/mcpjungle/servers.json"
+)
+
+var (
+	generateComposeOutput string
+	generateComposeForce  bool
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate files from the entities registered in mcpjungle",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "18",
+	},
+}
+
+var generateComposeCmd = &cobra.Command{
+	Use:   "compose",
+	Short: "Generate a docker compose file running mcpjungle and the registered MCP servers",
+	Long: "Generate a docker compose file that runs the mcpjungle server with its database, and a service for every\n" +
+		"registered MCP server that has a container configuration (image, env, ports).\n" +
+		"The servers are registered in the new mcpjungle server when the compose file is first brought up,\n" +
+		"the URLs of the servers running in a container point to their compose services.\n" +
+		"The servers without a container configuration are listed in comments at the top of the file.\n\n" +
+		"NOTE: The file contains the configurations of the servers, including their secrets (eg- bearer tokens).",
+	Args: cobra.NoArgs,
+	RunE: runGenerateCompose,
+}
+
+func init() {
+	generateComposeCmd.Flags().StringVarP(
+		&generateComposeOutput,
+		"output",
+		"o",
+		"",
+		"File to write the compose file to, eg- docker-compose.yaml (default: print it)",
+	)
+	generateComposeCmd.Flags().BoolVar(
+		&generateComposeForce,
+		"force",
+		false,
+		"Overwrite the output file if it already exists",
+	)
+
+	generateCmd.AddCommand(generateComposeCmd)
+	rootCmd.AddCommand(generateCmd)
+}
+
+func runGenerateCompose(cmd *cobra.Command, args []string) error {
+	servers, err := apiClient.GetServerConfigsContext(commandContext(cmd))
+	if err != nil {
+		return fmt.Errorf("failed to fetch mcp server configurations: %w", err)
+	}
+	data, err := buildComposeFile(servers)
+	if err != nil {
+		return err
+	}
+
+	if generateComposeOutput == "" || generateComposeOutput == "-" {
+		_, err := cmd.OutOrStdout().Write(data)
+		return err
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !generateComposeForce {
+		flags |= os.O_EXCL
+	}
+	// the file holds the secrets of the servers
+	f, err := os.OpenFile(generateComposeOutput, flags, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", generateComposeOutput)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", generateComposeOutput, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", generateComposeOutput, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", generateComposeOutput, err)
+	}
+	p := newPrinter(cmd)
+	p.Infof("Wrote %s, run it with `docker compose -f %s up -d`\n", generateComposeOutput, generateComposeOutput)
+	return nil
+}
+
+// composeFile is the part of the compose file specification used by the generated files.
+type composeFile struct {
+	Services map[string]*composeService `yaml:"services"`
+	Configs  map[string]composeConfig   `yaml:"configs,omitempty"`
+	Volumes  map[string]*struct{}       `yaml:"volumes,omitempty"`
+}
+
+type composeService struct {
+	Image       string                       `yaml:"image"`
+	Command     []string                     `yaml:"command,omitempty"`
+	Environment map[string]string            `yaml:"environment,omitempty"`
+	Ports       []composePort                `yaml:"ports,omitempty"`
+	Volumes     []string                     `yaml:"volumes,omitempty"`
+	Configs     []composeServiceConfig       `yaml:"configs,omitempty"`
+	Healthcheck *composeHealthcheck          `yaml:"healthcheck,omitempty"`
+	DependsOn   map[string]composeDependency `yaml:"depends_on,omitempty"`
+	Restart     string                       `yaml:"restart,omitempty"`
+}
+
+// composePort is a port mapping, eg- "8000:8000".
+// It is always quoted, since YAML 1.1 parsers read some unquoted mappings as numbers in base 60.
+type composePort string
+
+func (p composePort) MarshalYAML() (any, error) {
+	return &yaml.Node{Kind: yaml.ScalarNode, Style: yaml.DoubleQuotedStyle, Value: string(p)}, nil
+}
+
+type composeServiceConfig struct {
+	Source string `yaml:"source"`
+	Target string `yaml:"target"`
+}
+
+type composeHealthcheck struct {
+	Test     []string `yaml:"test"`
+	Interval string   `yaml:"interval"`
+	Timeout  string   `yaml:"timeout"`
+	Retries  int      `yaml:"retries"`
+}
+
+type composeDependency struct {
+	Condition string `yaml:"condition"`
+}
+
+type composeConfig struct {
+	Content string `yaml:"content"`
+}
+
+// buildComposeFile generates the compose file running mcpjungle and the given servers.
+func buildComposeFile(servers []*types.RegisterServerInput) ([]byte, error) {
+	servers = slices.Clone(servers)
+	slices.SortFunc(servers, func(a, b *types.RegisterServerInput) int { return strings.Compare(a.Name, b.Name) })
+
+	file := composeFile{
+		Services: map[string]*composeService{
+			composeDBService: {
+				Image: "postgres:17",
+				Environment: map[string]string{
+					"POSTGRES_USER":     "mcpjungle",
+					"POSTGRES_PASSWORD": "mcpjungle",
+					"POSTGRES_DB":       "mcpjungle",
+				},
+				Volumes: []string{"db_data:/var/lib/postgresql/data"},
+				Healthcheck: &composeHealthcheck{
+					Test:     []string{"CMD-SHELL", "PGPASSWORD=mcpjungle pg_isready -U mcpjungle"},
+					Interval: "10s",
+					Timeout:  "5s",
+					Retries:  5,
+				},
+				Restart: "unless-stopped",
+			},
+			composeGatewayService: {
+				Image: composeImage,
+				Environment: map[string]string{
+					"DATABASE_URL": "postgres://mcpjungle:mcpjungle@db:5432/mcpjungle",
+					"SERVER_MODE":  "${SERVER_MODE:-development}",
+				},
+				Ports:     []composePort{"${HOST_PORT:-8080}:8080"},
+				DependsOn: map[string]composeDependency{composeDBService: {Condition: "service_healthy"}},
+				Restart:   "always",
+			},
+		},
+		Volumes: map[string]*struct{}{"db_data": nil},
+	}
+
+	var missing []string
+	registrations := make([]*types.RegisterServerInput, 0, len(servers))
+	registerDeps := map[string]composeDependency{composeGatewayService: {Condition: "service_started"}}
+	for _, s := range servers {
+		if s.Container == nil || s.Transport == string(types.TransportStdio) {
+			missing = append(missing, describeUncontainedServer(s))
+			registrations = append(registrations, s)
+			continue
+		}
+		name := composeServiceName(s.Name, file.Services)
+		registration := *s
+		u, err := composeServiceURL(s.URL, name, s.Container.Port)
+		if err != nil {
+			return nil, fmt.Errorf("server %s: %w", s.Name, err)
+		}
+		registration.URL = u
+		registrations = append(registrations, &registration)
+
+		service := &composeService{
+			Image:   composeEscape(s.Container.Image),
+			Restart: "unless-stopped",
+		}
+		if len(s.Container.Env) > 0 {
+			service.Environment = make(map[string]string, len(s.Container.Env))
+			for k, v := range s.Container.Env {
+				service.Environment[composeEscape(k)] = composeEscape(v)
+			}
+		}
+		for _, p := range s.Container.Ports {
+			service.Ports = append(service.Ports, composePort(composeEscape(p)))
+		}
+		file.Services[name] = service
+		registerDeps[name] = composeDependency{Condition: "service_started"}
+	}
+
+	if len(registrations) > 0 {
+		content, err := json.MarshalIndent(registrations, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize the server configurations: %w", err)
+		}
+		file.Configs = map[string]composeConfig{composeServersConfig: {Content: composeEscape(string(content)) + "\n"}}
+		file.Services[composeRegisterService] = &composeService{
+			Image: composeImage,
+			// the client retries while the gateway starts
+			Command: []string{
+				"--registry", "http://" + composeGatewayService + ":8080", "--retries", "10", "--timeout", "2m",
+				"register", "-c", composeServersConfigTarget,
+			},
+			Configs:   []composeServiceConfig{{Source: composeServersConfig, Target: composeServersConfigTarget}},
+			DependsOn: registerDeps,
+			Restart:   "no",
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by `mcpjungle generate compose` from the MCP servers registered in mcpjungle.\n")
+	buf.WriteString("# Run it with `docker compose up -d`.\n")
+	if len(registrations) > 0 {
+		fmt.Fprintf(
+			&buf, "# The %s service registers the servers in mcpjungle when it is first brought up,\n", composeRegisterService,
+		)
+		buf.WriteString("# it reports the servers already registered as failures on the later runs.\n")
+	}
+	if len(missing) > 0 {
+		buf.WriteString("#\n# These servers have no container configuration, they are registered but not run by this file:\n")
+		for _, m := range missing {
+			fmt.Fprintf(&buf, "#   - %s\n", m)
+		}
+	}
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(file); err != nil {
+		return nil, fmt.Errorf("failed to serialize the compose file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to serialize the compose file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// describeUncontainedServer describes a server the compose file doesn't run, in its comments.
+func describeUncontainedServer(s *types.RegisterServerInput) string {
+	// the comments must stay on a single line
+	name := strings.Join(strings.Fields(s.Name), " ")
+	if s.Transport == string(types.TransportStdio) {
+		return fmt.Sprintf("%s (stdio, run by the mcpjungle server)", name)
+	}
+	if s.Transport == string(types.TransportOpenAPI) && s.OpenAPI != nil {
+		return fmt.Sprintf("%s (openapi, served by the mcpjungle server from %s)", name, s.OpenAPI.BaseURL)
+	}
+	if s.Transport == string(types.TransportWebhookTool) && s.WebhookTool != nil {
+		return fmt.Sprintf("%s (webhook_tool, served by the mcpjungle server from %s)", name, s.WebhookTool.URL)
+	}
+	return fmt.Sprintf("%s (%s, %s)", name, s.Transport, strings.Join(strings.Fields(s.URL), " "))
+}
+
+// invalidServiceNameChars are the characters of server names that can't be used in compose service names,
+// which are also the host names of the services.
+var invalidServiceNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// composeServiceName returns the name of the compose service of a server, unique among the given services.
+func composeServiceName(serverName string, services map[string]*composeService) string {
+	name := strings.Trim(invalidServiceNameChars.ReplaceAllString(strings.ToLower(serverName), "-"), "-")
+	if name == "" {
+		name = "mcp"
+	}
+	candidate := name
+	for i := 2; ; i++ {
+		if _, taken := services[candidate]; !taken && candidate != composeRegisterService {
+			return candidate
+		}
+		candidate = name + "-" + strconv.Itoa(i)
+	}
+}
+
+// composeServiceURL returns the URL of a server running in the compose service named host.
+// port is the port the server listens on in its container, the port of rawURL is used if it is 0.
+func composeServiceURL(rawURL, host string, port int) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid url %q: %w", rawURL, err)
+	}
+	if port == 0 {
+		if p := u.Port(); p != "" {
+			u.Host = net.JoinHostPort(host, p)
+		} else {
+			u.Host = host
+		}
+		return u.String(), nil
+	}
+	u.Host = net.JoinHostPort(host, strconv.Itoa(port))
+	return u.String(), nil
+}
+
+// composeEscape escapes the "$" of a value, which docker compose would interpolate otherwise.
+func composeEscape(s string) string {
+	return strings.ReplaceAll(s, "$", "$$")
+}
@@ -0,0 +1,470 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// mcpRemotePackage is the npm package Claude Desktop runs to connect to the gateway,
+	// since it only runs stdio MCP servers from its configuration file.
+	mcpRemotePackage = "mcp-remote"
+
+	// clientConfigAuthHeaderEnvVar is the env var of the mcp-remote process holding the Authorization header.
+	// The header is passed through the env since Claude Desktop mangles the args containing spaces on some platforms.
+	clientConfigAuthHeaderEnvVar = "AUTH_HEADER"
+
+	// clientConfigTokenPlaceholder stands for the access token of an MCP client when none is given.
+	clientConfigTokenPlaceholder = "<MCP_CLIENT_ACCESS_TOKEN>"
+
+	// vscodeTokenInput is the id of the input VS Code prompts for the access token when none is given.
+	vscodeTokenInput = "mcpjungle-access-token"
+)
+
+// clientConnection is how an MCP client connects to mcpjungle, whatever the client.
+type clientConnection struct {
+	// Name is the name of the entry of the MCP server in the configuration of the client
+	Name string
+	// URL is the streamable HTTP endpoint of the gateway or of a tool group
+	URL string
+	// Token is the access token of the MCP client, empty if the server doesn't require one (development mode).
+	// It is clientConfigTokenPlaceholder if the server requires one but none was given.
+	Token string
+}
+
+// clientConfigEntry is what is added to the configuration of an MCP client to connect it to mcpjungle.
+type clientConfigEntry struct {
+	// ServersKey is the field of the configuration holding the MCP servers, by name
+	ServersKey string
+	Server     any
+	// Inputs are added to the "inputs" of the configuration, VS Code prompts for their values
+	Inputs []vscodeInput
+}
+
+// clientRenderer renders the configuration of an MCP client.
+type clientRenderer struct {
+	// displayName is the name of the client shown to the user
+	displayName string
+	// render returns the entry connecting the client to conn
+	render func(conn *clientConnection) *clientConfigEntry
+	// configPath returns the configuration file the entry is merged into by default
+	configPath func() (string, error)
+}
+
+// clientRenderers are the renderers of the MCP clients whose configuration can be generated, by client.
+var clientRenderers = map[string]*clientRenderer{
+	"claude-desktop": {
+		displayName: "Claude Desktop",
+		render:      renderClaudeDesktopConfig,
+		configPath:  claudeDesktopConfigPath,
+	},
+	"vscode": {
+		displayName: "VS Code",
+		render:      renderVSCodeConfig,
+		// the workspace configuration of the current directory
+		configPath: func() (string, error) { return filepath.Join(".vscode", "mcp.json"), nil },
+	},
+	"cursor": {
+		displayName: "Cursor",
+		render:      renderCursorConfig,
+		configPath:  cursorConfigPath,
+	},
+}
+
+// supportedClients returns the MCP clients whose configuration can be generated.
+func supportedClients() []string {
+	clients := make([]string, 0, len(clientRenderers))
+	for c := range clientRenderers {
+		clients = append(clients, c)
+	}
+	slices.Sort(clients)
+	return clients
+}
+
+var (
+	generateClientConfigClient      string
+	generateClientConfigGroup       string
+	generateClientConfigName        string
+	generateClientConfigAccessToken string
+	generateClientConfigStdout      bool
+	generateClientConfigWrite       bool
+	generateClientConfigPath        string
+)
+
+var generateClientConfigCmd = &cobra.Command{
+	Use:   "client-config",
+	Short: "Generate the configuration of an MCP client connecting to mcpjungle",
+	Long: "Generate the MCP server entry connecting an MCP client to the mcpjungle gateway,\n" +
+		"or to the endpoint of a tool group with --group.\n" +
+		"Claude Desktop only runs stdio servers, so its entry runs mcp-remote (with npx) to connect to the gateway.\n\n" +
+		"In enterprise mode, MCP clients need an access token: pass the one of an MCP client created with\n" +
+		"`mcpjungle create mcp-client` with --access-token, otherwise the entry refers to a placeholder to replace\n" +
+		"(VS Code prompts for it instead).\n\n" +
+		"The entry is printed by default (--stdout). With --write, it is merged into the configuration file of the client\n" +
+		"instead, keeping its other entries: the file of the platform for Claude Desktop, ~/.cursor/mcp.json for Cursor\n" +
+		"and the workspace file .vscode/mcp.json for VS Code. A backup of the file is made before it is changed.",
+	Example: "  mcpjungle generate client-config --client claude-desktop\n" +
+		"  mcpjungle generate client-config --client vscode --group claude-tools --write\n" +
+		"  mcpjungle generate client-config --client cursor --registry https://mcpjungle.example.com",
+	Args: cobra.NoArgs,
+	RunE: runGenerateClientConfig,
+}
+
+func init() {
+	generateClientConfigCmd.Flags().StringVar(
+		&generateClientConfigClient,
+		"client",
+		"",
+		"MCP client to generate the configuration of, one of: "+strings.Join(supportedClients(), ", "),
+	)
+	_ = generateClientConfigCmd.MarkFlagRequired("client")
+	generateClientConfigCmd.Flags().StringVar(
+		&generateClientConfigGroup,
+		"group",
+		"",
+		"Connect the client to the endpoint of this tool group instead of the main gateway endpoint",
+	)
+	generateClientConfigCmd.Flags().StringVar(
+		&generateClientConfigName,
+		"name",
+		"",
+		"Name of the entry in the configuration of the client (default: mcpjungle, or mcpjungle-<group> with --group)",
+	)
+	generateClientConfigCmd.Flags().StringVar(
+		&generateClientConfigAccessToken,
+		"access-token",
+		"",
+		"Access token of the MCP client to put in the entry in enterprise mode (default: a placeholder)",
+	)
+	generateClientConfigCmd.Flags().BoolVar(
+		&generateClientConfigStdout,
+		"stdout",
+		false,
+		"Print the entry (default)",
+	)
+	generateClientConfigCmd.Flags().BoolVar(
+		&generateClientConfigWrite,
+		"write",
+		false,
+		"Merge the entry into the configuration file of the client instead of printing it",
+	)
+	generateClientConfigCmd.Flags().StringVar(
+		&generateClientConfigPath,
+		"config-path",
+		"",
+		"Configuration file of the client to merge the entry into with --write (default: the file of the client)",
+	)
+	generateClientConfigCmd.MarkFlagsMutuallyExclusive("stdout", "write")
+
+	generateCmd.AddCommand(generateClientConfigCmd)
+}
+
+func runGenerateClientConfig(cmd *cobra.Command, args []string) error {
+	renderer, ok := clientRenderers[generateClientConfigClient]
+	if !ok {
+		return usageErrorf(
+			"unsupported client '%s', supported clients: %s",
+			generateClientConfigClient, strings.Join(supportedClients(), ", "),
+		)
+	}
+	ctx := commandContext(cmd)
+
+	conn := &clientConnection{Name: "mcpjungle", URL: strings.TrimSuffix(apiClient.BaseURL(), "/") + "/mcp"}
+	if generateClientConfigGroup != "" {
+		group, err := apiClient.GetToolGroupContext(ctx, generateClientConfigGroup)
+		if err != nil {
+			return fmt.Errorf("failed to get tool group %s: %w", generateClientConfigGroup, err)
+		}
+		conn.URL = group.StreamableHTTPEndpoint
+		conn.Name = "mcpjungle-" + generateClientConfigGroup
+	}
+	if generateClientConfigName != "" {
+		conn.Name = generateClientConfigName
+	}
+
+	readiness, err := apiClient.GetServerReadiness(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the mode of the server: %w", err)
+	}
+	// a server that is not initialized yet is waiting for `init-server`, in enterprise mode
+	if model.ServerMode(readiness.Mode) != model.ModeDev {
+		conn.Token = generateClientConfigAccessToken
+		if conn.Token == "" {
+			conn.Token = clientConfigTokenPlaceholder
+		}
+	}
+	entry := renderer.render(conn)
+
+	p := newPrinter(cmd)
+	if !generateClientConfigWrite {
+		// a printed configuration is usually shared with someone else
+		if isLoopbackURL(conn.URL) {
+			p.Warnf(
+				"the endpoint %s only works on this machine, "+
+					"pass the URL other machines reach mcpjungle at with --registry if the configuration is for them",
+				conn.URL,
+			)
+		}
+		doc := map[string]any{entry.ServersKey: map[string]any{conn.Name: entry.Server}}
+		if len(entry.Inputs) > 0 {
+			doc["inputs"] = entry.Inputs
+		}
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize the client configuration: %w", err)
+		}
+		p.Resultf("%s\n", data)
+	} else {
+		path := generateClientConfigPath
+		if path == "" {
+			if path, err = renderer.configPath(); err != nil {
+				return err
+			}
+		}
+		backup, replaced, err := mergeClientConfig(path, conn.Name, entry)
+		if err != nil {
+			return err
+		}
+		if replaced {
+			p.Warnf("replaced the existing %s entry of %s", conn.Name, path)
+		}
+		if backup != "" {
+			p.Infof("Backed up %s to %s\n", path, backup)
+		}
+		p.Infof("Added %s to %s, restart %s to use it\n", conn.Name, path, renderer.displayName)
+	}
+	if conn.Token == clientConfigTokenPlaceholder && len(entry.Inputs) == 0 {
+		p.Infof(
+			"Replace %s with the access token of an MCP client, create one with `mcpjungle create mcp-client <name>`\n",
+			clientConfigTokenPlaceholder,
+		)
+	}
+	return nil
+}
+
+// isLoopbackURL returns true if the host of rawURL is only reachable from this machine, eg- localhost.
+func isLoopbackURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// claudeDesktopServer is an MCP server entry of the configuration file of Claude Desktop.
+type claudeDesktopServer struct {
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// renderClaudeDesktopConfig returns the entry running mcp-remote to connect Claude Desktop to conn.
+func renderClaudeDesktopConfig(conn *clientConnection) *clientConfigEntry {
+	s := &claudeDesktopServer{Command: "npx", Args: []string{mcpRemotePackage, conn.URL}}
+	if u, err := url.Parse(conn.URL); err == nil && u.Scheme == "http" {
+		// mcp-remote refuses plain HTTP endpoints otherwise
+		s.Args = append(s.Args, "--allow-http")
+	}
+	if conn.Token != "" {
+		s.Args = append(s.Args, "--header", "Authorization:${"+clientConfigAuthHeaderEnvVar+"}")
+		s.Env = map[string]string{clientConfigAuthHeaderEnvVar: "Bearer " + conn.Token}
+	}
+	return &clientConfigEntry{ServersKey: "mcpServers", Server: s}
+}
+
+// claudeDesktopConfigPath returns the path of the configuration file of Claude Desktop on this platform,
+// eg- ~/Library/Application Support/Claude/claude_desktop_config.json on macOS.
+func claudeDesktopConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the configuration directory of Claude Desktop, use --config-path: %w", err)
+	}
+	return filepath.Join(dir, "Claude", "claude_desktop_config.json"), nil
+}
+
+// vscodeServer is an MCP server entry of the mcp.json configuration of VS Code.
+type vscodeServer struct {
+	Type    string            `json:"type"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// vscodeInput is a value VS Code prompts for the first time an MCP server using it is started.
+type vscodeInput struct {
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Password    bool   `json:"password,omitempty"`
+}
+
+// renderVSCodeConfig returns the entry connecting VS Code to conn.
+// If no access token was given, VS Code prompts for it and stores it securely.
+func renderVSCodeConfig(conn *clientConnection) *clientConfigEntry {
+	entry := &clientConfigEntry{ServersKey: "servers"}
+	s := &vscodeServer{Type: "http", URL: conn.URL}
+	switch conn.Token {
+	case "":
+	case clientConfigTokenPlaceholder:
+		s.Headers = map[string]string{"Authorization": "Bearer ${input:" + vscodeTokenInput + "}"}
+		entry.Inputs = []vscodeInput{{
+			Type:        "promptString",
+			ID:          vscodeTokenInput,
+			Description: "Access token of the MCP client in mcpjungle",
+			Password:    true,
+		}}
+	default:
+		s.Headers = map[string]string{"Authorization": "Bearer " + conn.Token}
+	}
+	entry.Server = s
+	return entry
+}
+
+// cursorServer is an MCP server entry of the mcp.json configuration of Cursor.
+type cursorServer struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// renderCursorConfig returns the entry connecting Cursor to conn.
+func renderCursorConfig(conn *clientConnection) *clientConfigEntry {
+	s := &cursorServer{URL: conn.URL}
+	if conn.Token != "" {
+		s.Headers = map[string]string{"Authorization": "Bearer " + conn.Token}
+	}
+	return &clientConfigEntry{ServersKey: "mcpServers", Server: s}
+}
+
+// cursorConfigPath returns the path of the global configuration file of Cursor, ~/.cursor/mcp.json.
+func cursorConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the configuration directory of Cursor, use --config-path: %w", err)
+	}
+	return filepath.Join(home, ".cursor", "mcp.json"), nil
+}
+
+// mergeClientConfig adds the MCP server entry named name to the client configuration file at path,
+// replacing the entry with the same name if there is one, and adds the inputs of the entry that are missing.
+// The other entries and settings of the file are kept.
+// The file is created if it doesn't exist, otherwise it is backed up first.
+// It returns the path of the backup, empty if the file didn't exist, and whether an entry was replaced.
+func mergeClientConfig(path, name string, entry *clientConfigEntry) (string, bool, error) {
+	doc := make(map[string]json.RawMessage)
+	servers := make(map[string]json.RawMessage)
+	var inputs []json.RawMessage
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		data = nil
+	case err != nil:
+		return "", false, fmt.Errorf("failed to read %s: %w", path, err)
+	case len(bytes.TrimSpace(data)) > 0:
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return "", false, fmt.Errorf("failed to parse %s, it was not changed: %w", path, err)
+		}
+		if raw, ok := doc[entry.ServersKey]; ok && string(raw) != "null" {
+			if err := json.Unmarshal(raw, &servers); err != nil {
+				return "", false, fmt.Errorf("failed to parse the %s of %s, it was not changed: %w", entry.ServersKey, path, err)
+			}
+		}
+		if raw, ok := doc["inputs"]; ok && len(entry.Inputs) > 0 && string(raw) != "null" {
+			if err := json.Unmarshal(raw, &inputs); err != nil {
+				return "", false, fmt.Errorf("failed to parse the inputs of %s, it was not changed: %w", path, err)
+			}
+		}
+	}
+
+	_, replaced := servers[name]
+	raw, err := json.Marshal(entry.Server)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to serialize the client configuration: %w", err)
+	}
+	servers[name] = raw
+	if doc[entry.ServersKey], err = json.Marshal(servers); err != nil {
+		return "", false, fmt.Errorf("failed to serialize the client configuration: %w", err)
+	}
+	if len(entry.Inputs) > 0 {
+		if inputs, err = mergeClientConfigInputs(inputs, entry.Inputs); err != nil {
+			return "", false, fmt.Errorf("failed to parse the inputs of %s, it was not changed: %w", path, err)
+		}
+		if doc["inputs"], err = json.Marshal(inputs); err != nil {
+			return "", false, fmt.Errorf("failed to serialize the client configuration: %w", err)
+		}
+	}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", false, fmt.Errorf("failed to serialize the client configuration: %w", err)
+	}
+
+	backup := ""
+	if data != nil {
+		backup = fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102-150405"))
+		// the file may hold access tokens
+		if err := os.WriteFile(backup, data, 0o600); err != nil {
+			return "", false, fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	} else if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", false, fmt.Errorf("failed to create the directory of %s: %w", path, err)
+	}
+
+	// the new file replaces the old one at once, so that the client never reads a partially written file
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(out, '\n')); err != nil {
+		_ = tmp.Close()
+		return "", false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return backup, replaced, nil
+}
+
+// mergeClientConfigInputs appends the inputs that are not in existing yet, by id.
+func mergeClientConfigInputs(existing []json.RawMessage, inputs []vscodeInput) ([]json.RawMessage, error) {
+	ids := make(map[string]bool, len(existing))
+	for _, raw := range existing {
+		var input struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &input); err != nil {
+			return nil, err
+		}
+		ids[input.ID] = true
+	}
+	for _, input := range inputs {
+		if ids[input.ID] {
+			continue
+		}
+		raw, err := json.Marshal(input)
+		if err != nil {
+			return nil, err
+		}
+		existing = append(existing, raw)
+	}
+	return existing, nil
+}
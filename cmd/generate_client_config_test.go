@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func TestRenderClaudeDesktopConfig(t *testing.T) {
+	entry := renderClaudeDesktopConfig(&clientConnection{Name: "mcpjungle", URL: "http://localhost:8080/mcp"})
+	testhelpers.AssertEqual(t, "mcpServers", entry.ServersKey)
+	s := entry.Server.(*claudeDesktopServer)
+	testhelpers.AssertEqual(t, "npx", s.Command)
+	testhelpers.AssertEqual(t, 3, len(s.Args))
+	testhelpers.AssertEqual(t, "http://localhost:8080/mcp", s.Args[1])
+	testhelpers.AssertEqual(t, "--allow-http", s.Args[2])
+	testhelpers.AssertTrue(t, s.Env == nil, "no token should be sent in development mode")
+
+	s = renderClaudeDesktopConfig(&clientConnection{
+		URL:   "https://mcpjungle.example.com/v0/groups/claude-tools/mcp",
+		Token: clientConfigTokenPlaceholder,
+	}).Server.(*claudeDesktopServer)
+	testhelpers.AssertEqual(t, 4, len(s.Args))
+	testhelpers.AssertEqual(t, "--header", s.Args[2])
+	testhelpers.AssertEqual(t, "Authorization:${AUTH_HEADER}", s.Args[3])
+	testhelpers.AssertEqual(t, "Bearer <MCP_CLIENT_ACCESS_TOKEN>", s.Env[clientConfigAuthHeaderEnvVar])
+}
+
+func TestRenderVSCodeConfig(t *testing.T) {
+	entry := renderVSCodeConfig(&clientConnection{URL: "http://localhost:8080/mcp"})
+	testhelpers.AssertEqual(t, "servers", entry.ServersKey)
+	s := entry.Server.(*vscodeServer)
+	testhelpers.AssertEqual(t, "http", s.Type)
+	testhelpers.AssertTrue(t, s.Headers == nil && entry.Inputs == nil, "no token should be sent in development mode")
+
+	// VS Code prompts for the token if none is given
+	entry = renderVSCodeConfig(&clientConnection{URL: "http://localhost:8080/mcp", Token: clientConfigTokenPlaceholder})
+	testhelpers.AssertEqual(t, "Bearer ${input:mcpjungle-access-token}", entry.Server.(*vscodeServer).Headers["Authorization"])
+	testhelpers.AssertEqual(t, 1, len(entry.Inputs))
+	testhelpers.AssertTrue(t, entry.Inputs[0].Password, "the token should be stored as a secret")
+
+	entry = renderVSCodeConfig(&clientConnection{URL: "http://localhost:8080/mcp", Token: "token"})
+	testhelpers.AssertEqual(t, "Bearer token", entry.Server.(*vscodeServer).Headers["Authorization"])
+	testhelpers.AssertEqual(t, 0, len(entry.Inputs))
+}
+
+func TestRenderCursorConfig(t *testing.T) {
+	entry := renderCursorConfig(&clientConnection{URL: "https://mcpjungle.example.com/mcp", Token: "token"})
+	testhelpers.AssertEqual(t, "mcpServers", entry.ServersKey)
+	s := entry.Server.(*cursorServer)
+	testhelpers.AssertEqual(t, "https://mcpjungle.example.com/mcp", s.URL)
+	testhelpers.AssertEqual(t, "Bearer token", s.Headers["Authorization"])
+}
+
+func TestIsLoopbackURL(t *testing.T) {
+	for u, want := range map[string]bool{
+		"http://localhost:8080/mcp":              true,
+		"http://127.0.0.1:8080/mcp":              true,
+		"http://[::1]:8080/mcp":                  true,
+		"https://mcpjungle.example.com/mcp":      false,
+		"http://10.0.0.12:8080/v0/groups/ts/mcp": false,
+	} {
+		testhelpers.AssertEqual(t, want, isLoopbackURL(u))
+	}
+}
+
+func TestMergeClientConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Claude", "claude_desktop_config.json")
+	entry := renderClaudeDesktopConfig(&clientConnection{URL: "http://localhost:8080/mcp"})
+
+	// the file is created if it doesn't exist
+	backup, replaced, err := mergeClientConfig(path, "mcpjungle", entry)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "", backup)
+	testhelpers.AssertFalse(t, replaced, "no entry should be replaced in a new file")
+
+	existing := `{
+  "globalShortcut": "Ctrl+Space",
+  "mcpServers": {
+    "filesystem": {"command": "npx", "args": ["-y", "@modelcontextprotocol/server-filesystem", "/tmp"]},
+    "mcpjungle": {"command": "old"}
+  }
+}`
+	testhelpers.AssertNoError(t, os.WriteFile(path, []byte(existing), 0o600))
+	backup, replaced, err = mergeClientConfig(path, "mcpjungle", entry)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, replaced, "the existing entry should be replaced")
+
+	saved, err := os.ReadFile(backup)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, existing, string(saved))
+
+	data, err := os.ReadFile(path)
+	testhelpers.AssertNoError(t, err)
+	var doc struct {
+		GlobalShortcut string                         `json:"globalShortcut"`
+		McpServers     map[string]claudeDesktopServer `json:"mcpServers"`
+	}
+	testhelpers.AssertNoError(t, json.Unmarshal(data, &doc))
+	testhelpers.AssertEqual(t, "Ctrl+Space", doc.GlobalShortcut)
+	testhelpers.AssertEqual(t, 2, len(doc.McpServers))
+	testhelpers.AssertEqual(t, "/tmp", doc.McpServers["filesystem"].Args[2])
+	testhelpers.AssertEqual(t, "npx", doc.McpServers["mcpjungle"].Command)
+
+	// an invalid file is left as it is
+	testhelpers.AssertNoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+	_, _, err = mergeClientConfig(path, "mcpjungle", entry)
+	testhelpers.AssertTrue(t, err != nil, "expected an error for an invalid file")
+	data, err = os.ReadFile(path)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "{not json", string(data))
+}
+
+func TestMergeClientConfigInputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".vscode", "mcp.json")
+	existing := `{"servers": {}, "inputs": [{"type": "promptString", "id": "github-token", "password": true}]}`
+	testhelpers.AssertNoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	testhelpers.AssertNoError(t, os.WriteFile(path, []byte(existing), 0o600))
+
+	entry := renderVSCodeConfig(&clientConnection{URL: "http://localhost:8080/mcp", Token: clientConfigTokenPlaceholder})
+	_, _, err := mergeClientConfig(path, "mcpjungle", entry)
+	testhelpers.AssertNoError(t, err)
+	// merging again doesn't add the input twice
+	_, _, err = mergeClientConfig(path, "mcpjungle-tools", entry)
+	testhelpers.AssertNoError(t, err)
+
+	data, err := os.ReadFile(path)
+	testhelpers.AssertNoError(t, err)
+	var doc struct {
+		Servers map[string]vscodeServer `json:"servers"`
+		Inputs  []vscodeInput           `json:"inputs"`
+	}
+	testhelpers.AssertNoError(t, json.Unmarshal(data, &doc))
+	testhelpers.AssertEqual(t, 2, len(doc.Servers))
+	testhelpers.AssertEqual(t, 2, len(doc.Inputs))
+	testhelpers.AssertEqual(t, "github-token", doc.Inputs[0].ID)
+	testhelpers.AssertEqual(t, vscodeTokenInput, doc.Inputs[1].ID)
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+func TestBuildComposeFile(t *testing.T) {
+	servers := []*types.RegisterServerInput{
+		{
+			Name:        "github",
+			Transport:   "streamable_http",
+			URL:         "http://localhost:3001/mcp",
+			BearerToken: "ghp_secret",
+			Container: &types.ContainerConfig{
+				Image: "ghcr.io/github/github-mcp-server:latest",
+				Env:   map[string]string{"GITHUB_TOKEN": "pa$$word"},
+				Ports: []string{"3001:8000"},
+				Port:  8000,
+			},
+		},
+		{Name: "deepwiki", Transport: "streamable_http", URL: "https://mcp.deepwiki.com/mcp"},
+		{Name: "filesystem", Transport: "stdio", Command: "npx", Args: []string{"-y", "@modelcontextprotocol/server-filesystem"}},
+		{Name: "Slack_Events", Transport: "sse", URL: "http://localhost:9000/sse", Container: &types.ContainerConfig{Image: "slack-mcp"}},
+		{Name: "db", Transport: "streamable_http", URL: "http://localhost/mcp", Container: &types.ContainerConfig{Image: "db-mcp"}},
+	}
+	data, err := buildComposeFile(servers)
+	testhelpers.AssertNoError(t, err)
+	out := string(data)
+
+	// the servers without a container are listed in the comments
+	testhelpers.AssertStringContains(t, out, "#   - deepwiki (streamable_http, https://mcp.deepwiki.com/mcp)\n")
+	testhelpers.AssertStringContains(t, out, "#   - filesystem (stdio, run by the mcpjungle server)\n")
+
+	var file composeFile
+	testhelpers.AssertNoError(t, yaml.Unmarshal(data, &file))
+	testhelpers.AssertEqual(t, 6, len(file.Services))
+	for _, name := range []string{"db", "mcpjungle", "mcpjungle-register", "github", "slack-events", "db-2"} {
+		testhelpers.AssertTrue(t, file.Services[name] != nil, "missing service "+name)
+	}
+	github := file.Services["github"]
+	testhelpers.AssertEqual(t, "ghcr.io/github/github-mcp-server:latest", github.Image)
+	// compose would interpolate the "$" otherwise
+	testhelpers.AssertEqual(t, "pa$$$$word", github.Environment["GITHUB_TOKEN"])
+	testhelpers.AssertEqual(t, composePort("3001:8000"), github.Ports[0])
+	testhelpers.AssertStringContains(t, out, `- "3001:8000"`)
+	testhelpers.AssertEqual(t, "service_started", file.Services["mcpjungle-register"].DependsOn["github"].Condition)
+
+	// every server is registered, those in a container at the URLs of their services
+	content := strings.ReplaceAll(file.Configs[composeServersConfig].Content, "$$", "$")
+	var registrations []types.RegisterServerInput
+	testhelpers.AssertNoError(t, json.Unmarshal([]byte(content), &registrations))
+	urls := make(map[string]string)
+	tokens := make(map[string]string)
+	for _, r := range registrations {
+		urls[r.Name] = r.URL
+		tokens[r.Name] = r.BearerToken
+	}
+	testhelpers.AssertEqual(t, 5, len(registrations))
+	testhelpers.AssertEqual(t, "http://github:8000/mcp", urls["github"])
+	testhelpers.AssertEqual(t, "http://slack-events:9000/sse", urls["Slack_Events"])
+	testhelpers.AssertEqual(t, "http://db-2/mcp", urls["db"])
+	testhelpers.AssertEqual(t, "https://mcp.deepwiki.com/mcp", urls["deepwiki"])
+	testhelpers.AssertEqual(t, "ghp_secret", tokens["github"])
+}
+
+func TestBuildComposeFileWithoutServers(t *testing.T) {
+	data, err := buildComposeFile(nil)
+	testhelpers.AssertNoError(t, err)
+
+	var file composeFile
+	testhelpers.AssertNoError(t, yaml.Unmarshal(data, &file))
+	testhelpers.AssertEqual(t, 2, len(file.Services))
+	testhelpers.AssertEqual(t, 0, len(file.Configs))
+	testhelpers.AssertFalse(t, strings.Contains(string(data), composeRegisterService), "nothing should be registered")
+}
@@ -56,68 +56,74 @@ func init() {
 }
 
 func runGetGroup(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
 	name := args[0]
-	group, err := apiClient.GetToolGroup(name)
+	group, err := apiClient.GetToolGroupContext(commandContext(cmd), name)
 	if err != nil {
 		return fmt.Errorf("failed to get tool group: %w", err)
 	}
+	if isStructuredOutput() {
+		return printOutput(cmd, group)
+	}
 
-	cmd.Println(group.Name)
+	st := newStyler(cmd.OutOrStdout())
+	p.Resultln(st.Bold(group.Name))
 	if group.Description != "" {
-		cmd.Println()
-		cmd.Println("Description: " + group.Description)
+		p.Resultln()
+		p.Resultln("Description: " + group.Description)
 	}
 
-	cmd.Println()
-	cmd.Println("MCP Server streamable http endpoint:")
-	cmd.Println(group.StreamableHTTPEndpoint)
-	cmd.Println()
-	cmd.Println("MCP server SSE endpoints:")
-	cmd.Println(group.SSEEndpoint)
-	cmd.Println(group.SSEMessageEndpoint)
-	cmd.Println()
+	p.Resultln()
+	p.Resultln("MCP Server streamable http endpoint:")
+	p.Resultln(group.StreamableHTTPEndpoint)
+	p.Resultln()
+	p.Resultln("MCP server SSE endpoints:")
+	p.Resultln(group.SSEEndpoint)
+	p.Resultln(group.SSEMessageEndpoint)
+	p.Resultln()
 
 	if len(group.IncludedTools) == 0 {
-		cmd.Println("Included Tools: None")
+		p.Resultln("Included Tools: None")
 	} else {
-		cmd.Println("Included Tools:")
+		p.Resultln("Included Tools:")
 		for i, t := range group.IncludedTools {
-			cmd.Printf("%d. %s\n", i+1, t)
+			p.Resultf("%d. %s\n", i+1, t)
 			// TODO: Also show whether the tool is still active, disabled, or deleted at the moment
 			// ie, is it practically available as part of this group?
 		}
 	}
-	cmd.Println()
+	p.Resultln()
 
 	if len(group.IncludedServers) == 0 {
-		cmd.Println("Included Servers: None")
+		p.Resultln("Included Servers: None")
 	} else {
-		cmd.Println("Included Servers:")
+		p.Resultln("Included Servers:")
 		for i, s := range group.IncludedServers {
-			cmd.Printf("%d. %s\n", i+1, s)
+			p.Resultf("%d. %s\n", i+1, s)
 		}
 	}
-	cmd.Println()
+	p.Resultln()
 
 	if len(group.ExcludedTools) == 0 {
-		cmd.Println("Excluded Tools: None")
+		p.Resultln("Excluded Tools: None")
 	} else {
-		cmd.Println("Excluded Tools:")
+		p.Resultln("Excluded Tools:")
 		for i, t := range group.ExcludedTools {
-			cmd.Printf("%d. %s\n", i+1, t)
+			p.Resultf("%d. %s\n", i+1, t)
 		}
 	}
-	cmd.Println()
+	p.Resultln()
 
-	cmd.Println(
+	p.Resultln(st.Dim(
 		"NOTE: If a tool in this group is disabled globally or has been deleted, " +
 			"then it will not be available via the group's MCP endpoint.",
-	)
+	))
 
 	return nil
 }
 
 func runGetPrompt(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
 	name := args[0]
 
 	// Convert CLI args to proper format
@@ -126,29 +132,32 @@ func runGetPrompt(cmd *cobra.Command, args []string) error {
 		arguments[k] = v
 	}
 
-	result, err := apiClient.GetPromptWithArgs(name, arguments)
+	result, err := apiClient.GetPromptWithArgsContext(commandContext(cmd), name, arguments)
 	if err != nil {
 		return fmt.Errorf("failed to get prompt: %w", err)
 	}
+	if isStructuredOutput() {
+		return printOutput(cmd, result)
+	}
 
 	// Pretty print the result
-	cmd.Printf("Prompt: %s\n", name)
+	p.Resultf("Prompt: %s\n", name)
 	if result.Description != "" {
-		cmd.Printf("Description: %s\n", result.Description)
+		p.Resultf("Description: %s\n", result.Description)
 	}
-	cmd.Println("\nGenerated Messages:")
-	cmd.Println("=" + strings.Repeat("=", 50))
+	p.Resultln("\nGenerated Messages:")
+	p.Resultln("=" + strings.Repeat("=", 50))
 
 	for i, message := range result.Messages {
-		cmd.Printf("\nMessage %d (%s):\n", i+1, message.Role)
-		cmd.Println("-" + strings.Repeat("-", 30))
+		p.Resultf("\nMessage %d (%s):\n", i+1, message.Role)
+		p.Resultln("-" + strings.Repeat("-", 30))
 
 		// Format the content nicely
 		contentBytes, err := json.MarshalIndent(message.Content, "", "  ")
 		if err != nil {
-			cmd.Printf("Content: %+v\n", message.Content)
+			p.Resultf("Content: %+v\n", message.Content)
 		} else {
-			cmd.Printf("Content: %s\n", string(contentBytes))
+			p.Resultf("Content: %s\n", string(contentBytes))
 		}
 	}
 
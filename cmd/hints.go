@@ -0,0 +1,404 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// errorHint is a user-friendly explanation of a failed command along with guidance on how to fix it.
+type errorHint struct {
+	// Message replaces the raw error message
+	Message string
+	// Hint tells the user what to do next
+	Hint string
+}
+
+// entityKind identifies the type of entity an API request was about.
+// Its value is the argument to pass to `mcpjungle list` to see all entities of this kind.
+type entityKind string
+
+const (
+	entityServers    entityKind = "servers"
+	entityTools      entityKind = "tools"
+	entityPrompts    entityKind = "prompts"
+	entityToolGroups entityKind = "groups"
+	entityMcpClients entityKind = "mcp-clients"
+	entityUsers      entityKind = "users"
+)
+
+// entityNameLister returns the names of all entities of the given kind.
+// It is used to suggest the closest match when the user refers to an entity that doesn't exist.
+type entityNameLister func(kind entityKind) ([]string, error)
+
+// explainError translates common failures (connection, authentication, TLS, missing entities)
+// into actionable guidance for the user.
+// It returns false if err is not one of the failures it knows about, in which case the error should be printed as-is.
+// names may be nil, in which case no closest-match suggestion is made for missing entities.
+func explainError(err error, registryURL string, names entityNameLister) (errorHint, bool) {
+	if err == nil {
+		return errorHint{}, false
+	}
+
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		return explainAPIError(apiErr, registryURL, names)
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return errorHint{
+			Message: "the command was interrupted before it completed",
+			Hint:    "the server may have already applied the change, check its current state before running the command again",
+		}, true
+	}
+
+	if h, ok := explainTLSError(err, registryURL); ok {
+		return h, true
+	}
+
+	if h, ok := explainTimeout(err); ok {
+		return h, true
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return errorHint{
+			Message: fmt.Sprintf("could not connect to the mcpjungle server at %s", registryURL),
+			Hint: fmt.Sprintf(
+				"is the mcpjungle server running at %s? Start it with `mcpjungle start` or check --registry",
+				registryURL,
+			),
+		}, true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return errorHint{
+			Message: fmt.Sprintf("could not resolve the host %s of the mcpjungle server", dnsErr.Name),
+			Hint:    fmt.Sprintf("check that the registry URL %s is correct, or pass the right one with --registry", registryURL),
+		}, true
+	}
+
+	return errorHint{}, false
+}
+
+func explainAPIError(apiErr *client.APIError, registryURL string, names entityNameLister) (errorHint, bool) {
+	switch apiErr.Code {
+	case types.ErrorCodeUnauthorized:
+		return errorHint{
+			Message: "authentication required: " + apiErr.Message,
+			Hint:    fmt.Sprintf("run `mcpjungle login` to authenticate with the mcpjungle server at %s", registryURL),
+		}, true
+
+	case types.ErrorCodeForbidden:
+		if apiErr.RequiredRole != "" {
+			return errorHint{
+				Message: fmt.Sprintf("permission denied: this action requires the %s role", apiErr.RequiredRole),
+				Hint: fmt.Sprintf(
+					"log in as a user with the %s role using `mcpjungle login`, or ask an administrator to perform it",
+					apiErr.RequiredRole,
+				),
+			}, true
+		}
+		return errorHint{
+			Message: "permission denied: " + apiErr.Message,
+			Hint:    "your access token does not grant access to this action, ask an administrator for permission",
+		}, true
+
+	case types.ErrorCodeNotInitialized:
+		return errorHint{
+			Message: "the mcpjungle server has not been initialized yet",
+			Hint:    "run `mcpjungle init-server` to initialize it",
+		}, true
+
+	case types.ErrorCodeWrongMode:
+		return errorHint{
+			Message: "permission denied: " + apiErr.Message,
+			Hint:    "this feature is not available in the mode the mcpjungle server is running in",
+		}, true
+
+	case types.ErrorCodeUpstreamUnreachable:
+		return errorHint{
+			Message: apiErr.Message,
+			Hint:    "the mcpjungle server could not reach the MCP server, check that it is running and that its URL or command is correct",
+		}, true
+
+	case types.ErrorCodeRateLimited:
+		h := errorHint{
+			Message: "rate limited: " + apiErr.Message,
+			Hint:    "you made too many requests to the mcpjungle server, wait a little before running the command again",
+		}
+		if apiErr.RetryAfter > 0 {
+			h.Hint = fmt.Sprintf(
+				"you made too many requests to the mcpjungle server, run the command again in %s",
+				apiErr.RetryAfter.Round(time.Second),
+			)
+		}
+		return h, true
+
+	case types.ErrorCodeRegistryNotEmpty:
+		return errorHint{
+			Message: apiErr.Message,
+			Hint:    "restore the backup with --force to replace the entities of the registry, or into a new registry",
+		}, true
+
+	case types.ErrorCodeIncompatibleBackup:
+		return errorHint{
+			Message: "the backup cannot be restored by this server: " + apiErr.Message,
+			Hint:    "nothing was restored, run `mcpjungle version` to compare the versions of the CLI and the server",
+		}, true
+
+	case types.ErrorCodeNotFound:
+		kind, name, ok := entityFromPath(apiErr)
+		if !ok {
+			return errorHint{}, false
+		}
+		h := errorHint{
+			Message: fmt.Sprintf("%s %q not found", kind.singular(), name),
+			Hint:    fmt.Sprintf("run `mcpjungle list %s` to see valid names", kind),
+		}
+		if names != nil {
+			if candidates, err := names(kind); err == nil {
+				if match := closestMatch(name, candidates); match != "" {
+					h.Hint = fmt.Sprintf("did you mean %q? Run `mcpjungle list %s` to see valid names", match, kind)
+				}
+			}
+		}
+		return h, true
+	}
+	return errorHint{}, false
+}
+
+func explainTLSError(err error, registryURL string) (errorHint, bool) {
+	var (
+		unknownAuthErr x509.UnknownAuthorityError
+		hostnameErr    x509.HostnameError
+		invalidErr     x509.CertificateInvalidError
+		verifyErr      *tls.CertificateVerificationError
+	)
+	if errors.As(err, &unknownAuthErr) {
+		return errorHint{
+			Message: fmt.Sprintf("the TLS certificate of the mcpjungle server at %s is signed by an unknown authority", registryURL),
+			Hint: "if the server uses a certificate signed by a private CA, pass the CA certificate with --ca-cert <path> " +
+				"or set ca_cert in your context",
+		}, true
+	}
+	if errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) || errors.As(err, &verifyErr) {
+		return errorHint{
+			Message: fmt.Sprintf("the TLS certificate of the mcpjungle server at %s could not be verified", registryURL),
+			Hint:    "check that the registry URL points to the right host and that the server's certificate is valid",
+		}, true
+	}
+
+	// net/http reports this as a plain error, not a tls.RecordHeaderError
+	var recordErr tls.RecordHeaderError
+	if errors.As(err, &recordErr) || strings.Contains(err.Error(), "server gave HTTP response to HTTPS client") {
+		return errorHint{
+			Message: fmt.Sprintf("the mcpjungle server at %s does not speak HTTPS", registryURL),
+			Hint:    "use an http:// registry URL with --registry",
+		}, true
+	}
+	return errorHint{}, false
+}
+
+// explainTimeout tells which request timed out and how to give it more time.
+func explainTimeout(err error) (errorHint, bool) {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) || !urlErr.Timeout() {
+		return errorHint{}, false
+	}
+
+	operation := strings.ToUpper(urlErr.Op)
+	if u, err := url.Parse(urlErr.URL); err == nil {
+		operation += " " + u.Path
+	}
+	h := errorHint{
+		Message: fmt.Sprintf("the request %s to the mcpjungle server timed out", operation),
+		Hint:    "the server took too long to respond, retry with a higher --timeout (eg- --timeout 2m)",
+	}
+	if activeRequestTimeout > 0 {
+		h.Message += " after " + activeRequestTimeout.String()
+		h.Hint = fmt.Sprintf(
+			"the server took too long to respond, retry with a higher --timeout (eg- --timeout %s)",
+			2*activeRequestTimeout,
+		)
+	}
+	return h, true
+}
+
+// entityFromPath determines which entity a failed API request was about from the request's path.
+func entityFromPath(apiErr *client.APIError) (entityKind, string, bool) {
+	parts := strings.Split(strings.Trim(apiErr.Path, "/"), "/")
+	// paths look like: api/<version>/<resource>[/<name>[/...]]
+	if len(parts) < 3 || parts[0] != "api" {
+		return "", "", false
+	}
+	resource, rest := parts[2], parts[3:]
+
+	pathName := func() (string, bool) {
+		if len(rest) == 0 || rest[0] == "" {
+			return "", false
+		}
+		return rest[0], true
+	}
+	queryName := func() (string, bool) {
+		name := apiErr.Query.Get("name")
+		return name, name != ""
+	}
+
+	var (
+		kind entityKind
+		name string
+		ok   bool
+	)
+	switch resource {
+	case "servers":
+		kind = entityServers
+		name, ok = pathName()
+	case "tool":
+		kind = entityTools
+		name, ok = queryName()
+	case "prompt":
+		kind = entityPrompts
+		name, ok = queryName()
+	case "tool-groups":
+		kind = entityToolGroups
+		name, ok = pathName()
+	case "clients":
+		kind = entityMcpClients
+		name, ok = pathName()
+	case "users":
+		kind = entityUsers
+		name, ok = pathName()
+	}
+	return kind, name, ok
+}
+
+// singular returns a human-readable singular name for the entity kind.
+func (k entityKind) singular() string {
+	switch k {
+	case entityServers:
+		return "MCP server"
+	case entityTools:
+		return "tool"
+	case entityPrompts:
+		return "prompt"
+	case entityToolGroups:
+		return "tool group"
+	case entityMcpClients:
+		return "MCP client"
+	case entityUsers:
+		return "user"
+	default:
+		return string(k)
+	}
+}
+
+// listEntityNames fetches the names of all entities of the given kind from the registry.
+// It runs after the command has failed, when the command's context may be done, so it doesn't use it.
+func listEntityNames(kind entityKind) ([]string, error) {
+	var names []string
+	switch kind {
+	case entityServers:
+		servers, err := apiClient.ListServersContext(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range servers {
+			names = append(names, s.Name)
+		}
+	case entityTools:
+		tools, err := apiClient.ListToolsContext(context.Background(), "")
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tools {
+			names = append(names, t.Name)
+		}
+	case entityPrompts:
+		prompts, err := apiClient.ListPromptsContext(context.Background(), "")
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range prompts {
+			names = append(names, p.Name)
+		}
+	case entityToolGroups:
+		groups, err := apiClient.ListToolGroupsContext(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range groups {
+			names = append(names, g.Name)
+		}
+	case entityMcpClients:
+		clients, err := apiClient.ListMcpClientsContext(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range clients {
+			names = append(names, c.Name)
+		}
+	case entityUsers:
+		users, err := apiClient.ListUsersContext(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			names = append(names, u.Username)
+		}
+	}
+	return names, nil
+}
+
+// closestMatch returns the candidate that is most similar to name, or an empty string
+// if none of the candidates is similar enough to be a likely typo.
+func closestMatch(name string, candidates []string) string {
+	best, bestDist := "", -1
+	for _, c := range candidates {
+		if c == name {
+			continue
+		}
+		d := editDistance(strings.ToLower(name), strings.ToLower(c))
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	// only suggest names that are a few edits away, relative to the length of the name
+	maxDist := max(2, len(name)/3)
+	if bestDist < 0 || bestDist > maxDist {
+		return ""
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
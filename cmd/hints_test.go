@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+const hintsTestRegistry = "http://localhost:8080"
+
+func TestExplainConnectionErrors(t *testing.T) {
+	t.Run("connection refused", func(t *testing.T) {
+		err := fmt.Errorf("failed to list servers: %w", &net.OpError{
+			Op:  "dial",
+			Net: "tcp",
+			Err: os.NewSyscallError("connect", syscall.ECONNREFUSED),
+		})
+		h, ok := explainError(err, hintsTestRegistry, nil)
+		testhelpers.AssertTrue(t, ok, "connection refused should be explained")
+		testhelpers.AssertStringContains(t, h.Message, hintsTestRegistry)
+		testhelpers.AssertStringContains(t, h.Hint, "mcpjungle start")
+		testhelpers.AssertStringContains(t, h.Hint, "--registry")
+	})
+
+	t.Run("unknown host", func(t *testing.T) {
+		err := &net.OpError{Op: "dial", Err: &net.DNSError{Name: "jungle.invalid", Err: "no such host"}}
+		h, ok := explainError(err, "http://jungle.invalid", nil)
+		testhelpers.AssertTrue(t, ok, "DNS errors should be explained")
+		testhelpers.AssertStringContains(t, h.Message, "jungle.invalid")
+		testhelpers.AssertStringContains(t, h.Hint, "--registry")
+	})
+
+	t.Run("unrelated errors are not explained", func(t *testing.T) {
+		_, ok := explainError(errors.New("something else"), hintsTestRegistry, nil)
+		testhelpers.AssertFalse(t, ok, "unknown errors should be printed as-is")
+	})
+}
+
+func TestExplainTLSErrors(t *testing.T) {
+	t.Run("untrusted certificate", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		_, err := http.Get(server.URL)
+		testhelpers.AssertError(t, err)
+
+		h, ok := explainError(err, server.URL, nil)
+		testhelpers.AssertTrue(t, ok, "certificate errors should be explained")
+		testhelpers.AssertStringContains(t, h.Message, "TLS certificate")
+		testhelpers.AssertStringContains(t, h.Hint, "CA")
+		testhelpers.AssertStringContains(t, h.Hint, "--ca-cert")
+	})
+
+	t.Run("https against a plain http server", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		u, _ := url.Parse(server.URL)
+		u.Scheme = "https"
+		_, err := http.Get(u.String())
+		testhelpers.AssertError(t, err)
+
+		h, ok := explainError(err, u.String(), nil)
+		testhelpers.AssertTrue(t, ok, "TLS handshake errors should be explained")
+		testhelpers.AssertStringContains(t, h.Hint, "http://")
+	})
+}
+
+func TestExplainTimeout(t *testing.T) {
+	orig := activeRequestTimeout
+	t.Cleanup(func() { activeRequestTimeout = orig })
+	activeRequestTimeout = 50 * time.Millisecond
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	c := client.NewClient(server.URL, "", &http.Client{Timeout: activeRequestTimeout})
+	_, err := c.ListServersContext(context.Background())
+	testhelpers.AssertError(t, err)
+
+	h, ok := explainError(err, server.URL, nil)
+	testhelpers.AssertTrue(t, ok, "timeouts should be explained")
+	testhelpers.AssertEqual(t, "the request GET /api/v1/servers to the mcpjungle server timed out after 50ms", h.Message)
+	testhelpers.AssertStringContains(t, h.Hint, "--timeout 100ms")
+	testhelpers.AssertEqual(t, ExitConnection, ExitCodeForError(err))
+}
+
+func TestExplainAuthErrors(t *testing.T) {
+	t.Run("401", func(t *testing.T) {
+		err := fmt.Errorf("failed to list users: %w", &client.APIError{StatusCode: 401, Code: types.ErrorCodeUnauthorized, Message: "missing access token"})
+		h, ok := explainError(err, hintsTestRegistry, nil)
+		testhelpers.AssertTrue(t, ok, "401 should be explained")
+		testhelpers.AssertStringContains(t, h.Message, "authentication required")
+		testhelpers.AssertStringContains(t, h.Hint, "mcpjungle login")
+	})
+
+	t.Run("403 names the missing role", func(t *testing.T) {
+		err := &client.APIError{StatusCode: 403, Code: types.ErrorCodeForbidden, Message: "user is not authorized", RequiredRole: "admin"}
+		h, ok := explainError(err, hintsTestRegistry, nil)
+		testhelpers.AssertTrue(t, ok, "403 should be explained")
+		testhelpers.AssertStringContains(t, h.Message, "requires the admin role")
+	})
+
+	t.Run("403 uninitialized server", func(t *testing.T) {
+		err := &client.APIError{StatusCode: 403, Code: types.ErrorCodeNotInitialized, Message: "server is not initialized"}
+		h, ok := explainError(err, hintsTestRegistry, nil)
+		testhelpers.AssertTrue(t, ok, "403 should be explained")
+		testhelpers.AssertStringContains(t, h.Hint, "mcpjungle init-server")
+	})
+
+	t.Run("403 without details", func(t *testing.T) {
+		err := &client.APIError{StatusCode: 403, Code: types.ErrorCodeForbidden, Message: "forbidden"}
+		h, ok := explainError(err, hintsTestRegistry, nil)
+		testhelpers.AssertTrue(t, ok, "403 should be explained")
+		testhelpers.AssertStringContains(t, h.Message, "permission denied")
+	})
+}
+
+func TestExplainRateLimitErrors(t *testing.T) {
+	err := &client.APIError{StatusCode: 429, Code: types.ErrorCodeRateLimited, Message: "rate limit of 60 requests per 1m0s exceeded", RetryAfter: 12 * time.Second}
+	h, ok := explainError(err, hintsTestRegistry, nil)
+	testhelpers.AssertTrue(t, ok, "429 should be explained")
+	testhelpers.AssertStringContains(t, h.Message, "rate limited")
+	testhelpers.AssertStringContains(t, h.Hint, "again in 12s")
+}
+
+func TestExplainNotFoundErrors(t *testing.T) {
+	names := func(kind entityKind) ([]string, error) {
+		switch kind {
+		case entityServers:
+			return []string{"github", "filesystem", "context7"}, nil
+		case entityTools:
+			return []string{"github__create_issue", "github__list_issues"}, nil
+		}
+		return nil, errors.New("unexpected kind")
+	}
+
+	t.Run("server with a close match", func(t *testing.T) {
+		err := &client.APIError{StatusCode: 404, Code: types.ErrorCodeNotFound, Method: "DELETE", Path: "/api/v1/servers/githb"}
+		h, ok := explainError(err, hintsTestRegistry, names)
+		testhelpers.AssertTrue(t, ok, "404 should be explained")
+		testhelpers.AssertStringContains(t, h.Message, `MCP server "githb" not found`)
+		testhelpers.AssertStringContains(t, h.Hint, `did you mean "github"?`)
+		testhelpers.AssertStringContains(t, h.Hint, "mcpjungle list servers")
+	})
+
+	t.Run("tool name from the query", func(t *testing.T) {
+		err := &client.APIError{
+			StatusCode: 404,
+			Code:       types.ErrorCodeNotFound,
+			Path:       "/api/v1/tool",
+			Query:      url.Values{"name": {"github__create_isue"}},
+		}
+		h, ok := explainError(err, hintsTestRegistry, names)
+		testhelpers.AssertTrue(t, ok, "404 should be explained")
+		testhelpers.AssertStringContains(t, h.Hint, `did you mean "github__create_issue"?`)
+	})
+
+	t.Run("no close match", func(t *testing.T) {
+		err := &client.APIError{StatusCode: 404, Code: types.ErrorCodeNotFound, Path: "/api/v1/servers/slack"}
+		h, ok := explainError(err, hintsTestRegistry, names)
+		testhelpers.AssertTrue(t, ok, "404 should be explained")
+		testhelpers.AssertStringNotContains(t, h.Hint, "did you mean")
+		testhelpers.AssertStringContains(t, h.Hint, "mcpjungle list servers")
+	})
+
+	t.Run("listing fails", func(t *testing.T) {
+		err := &client.APIError{StatusCode: 404, Code: types.ErrorCodeNotFound, Path: "/api/v1/tool-groups/foo"}
+		h, ok := explainError(err, hintsTestRegistry, names)
+		testhelpers.AssertTrue(t, ok, "404 should be explained")
+		testhelpers.AssertStringContains(t, h.Hint, "mcpjungle list groups")
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		err := &client.APIError{StatusCode: 404, Code: types.ErrorCodeNotFound, Path: "/api/v1/servers"}
+		_, ok := explainError(err, hintsTestRegistry, names)
+		testhelpers.AssertFalse(t, ok, "404 on a collection should not be explained")
+	})
+}
+
+func TestClosestMatch(t *testing.T) {
+	candidates := []string{"github", "gitlab", "filesystem"}
+	testhelpers.AssertEqual(t, "github", closestMatch("githib", candidates))
+	testhelpers.AssertEqual(t, "filesystem", closestMatch("FileSystm", candidates))
+	testhelpers.AssertEqual(t, "", closestMatch("slack", candidates))
+	testhelpers.AssertEqual(t, "", closestMatch("github", []string{"github"}))
+	testhelpers.AssertEqual(t, 3, editDistance("kitten", "sitting"))
+}
+
+func TestPrintErrorHints(t *testing.T) {
+	origVerbosity := verbosity
+	t.Cleanup(func() { verbosity = origVerbosity })
+
+	err := fmt.Errorf("failed to list users: %w", &client.APIError{StatusCode: 401, Code: types.ErrorCodeUnauthorized, Message: "missing access token"})
+
+	verbosity = 0
+	buf := &bytes.Buffer{}
+	PrintError(buf, err)
+	testhelpers.AssertStringContains(t, buf.String(), "hint: run `mcpjungle login`")
+	testhelpers.AssertStringNotContains(t, buf.String(), "failed to list users")
+
+	verbosity = 1
+	buf.Reset()
+	PrintError(buf, err)
+	testhelpers.AssertStringContains(t, buf.String(), "cause: failed to list users: missing access token")
+
+	buf.Reset()
+	PrintError(buf, errors.New("plain failure"))
+	testhelpers.AssertEqual(t, "plain failure\n", buf.String())
+}
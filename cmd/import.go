@@ -0,0 +1,885 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	clientconfig "github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/mcpjungle/mcpjungle/internal/s3"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	importFormatClaudeDesktop = "claude-desktop"
+	importFormatMCPJSON       = "mcpjson"
+)
+
+const (
+	importActionRegister = "register"
+	importActionCreate   = "create"
+	importActionUpdate   = "update"
+	importActionSkip     = "skip"
+	// importActionUnchanged is the action of the entities a dry run finds identical to their live configurations
+	importActionUnchanged = "unchanged"
+)
+
+// envReference matches the references to env vars and inputs in the configurations of MCP clients,
+// eg- ${GITHUB_TOKEN}, ${API_URL:-http://localhost} or ${input:github-token} (VS Code).
+var envReference = regexp.MustCompile(`\$\{[^}]+\}`)
+
+var (
+	importClientConfigFile   string
+	importClientConfigFormat string
+	importClientConfigUpsert bool
+	importClientConfigDryRun bool
+)
+
+var (
+	importBundleS3     s3Flags
+	importBundleDir    string
+	importBundleUpsert bool
+	importBundleDryRun bool
+
+	importBundleContinueOnError bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import entities into mcpjungle from other tools",
+	Long: "Import entities into mcpjungle from other tools, see the subcommands.\n\n" +
+		"With --dir, the MCP servers and tool groups of a directory written by `mcpjungle export` are imported\n" +
+		"instead, and with --s3-url, those of a bundle uploaded by `mcpjungle export --s3-url`.\n" +
+		"The entities are imported in the order of their dependencies, a group once the servers of its tools exist.\n" +
+		"The entities that already exist are skipped, unless --upsert is set to update them.\n\n" +
+		"All the files are read first, and a group referencing a server that is neither registered nor imported\n" +
+		"fails the import before any change. The import stops at the first entity that can't be imported, unless\n" +
+		"--continue-on-error is set to import the others, but for the ones depending on it. The command still fails.\n\n" +
+		"The files of a directory that aren't valid configurations are reported and skipped, the others are\n" +
+		"imported anyway. The outcome of every file is shown at the end, and the command fails if any of them\n" +
+		"could not be imported.\n\n" +
+		"The secrets an export replaced with placeholders are read from the registry, so that the servers that\n" +
+		"exist keep theirs. The servers whose placeholders name no secret of the registry are skipped.\n\n" +
+		"With --dry-run, nothing is imported: the configurations are compared field by field with the live ones,\n" +
+		"and the entities that would be created, updated or left untouched are shown, with the fields that change.\n" +
+		"The secrets, eg- bearer tokens and env vars, are compared too but masked.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "19",
+	},
+	Example: "  mcpjungle import --dir .mcpjungle --upsert\n" +
+		"  mcpjungle import --s3-url s3://backups/mcpjungle/mcpjungle-export-20250101T020000Z.tar.gz --dry-run",
+	Args: cobra.NoArgs,
+	RunE: runImportBundle,
+}
+
+var importClientConfigCmd = &cobra.Command{
+	Use:   "client-config",
+	Short: "Register the MCP servers configured in an MCP client",
+	Long: "Register the MCP servers configured in the configuration file of an MCP client in mcpjungle.\n" +
+		"The claude-desktop format is the claude_desktop_config.json file of Claude Desktop\n" +
+		"(its file is read by default). The mcpjson format is a project-level .mcp.json file (read by default),\n" +
+		"or the mcp.json file of Cursor or VS Code.\n\n" +
+		"Every entry is converted into a stdio, streamable_http or sse server, and the plan is shown before anything\n" +
+		"is registered. The entries mcpjungle can't serve (unsupported transports or headers) are reported and skipped.\n" +
+		"The servers that are already registered are skipped too, unless --upsert is set to update them.\n" +
+		"The " + mcpJSONExtensionKey + " key of the entries written by `mcpjungle export --format mcpjson` is read back.\n\n" +
+		"References to env vars, eg- ${GITHUB_TOKEN}, are kept as they are: mcpjungle doesn't expand them,\n" +
+		"so replace them with their values afterwards with `mcpjungle edit server`.",
+	Example: "  mcpjungle import client-config --format claude-desktop --dry-run\n" +
+		"  mcpjungle import client-config --format mcpjson --file ./.mcp.json --upsert",
+	Args: cobra.NoArgs,
+	RunE: runImportClientConfig,
+}
+
+func init() {
+	importClientConfigCmd.Flags().StringVar(
+		&importClientConfigFile,
+		"file",
+		"",
+		"Configuration file of the client (default: the file of Claude Desktop, or .mcp.json)",
+	)
+	importClientConfigCmd.Flags().StringVar(
+		&importClientConfigFormat,
+		"format",
+		"",
+		fmt.Sprintf("Format of the configuration file, one of: %s, %s", importFormatClaudeDesktop, importFormatMCPJSON),
+	)
+	_ = importClientConfigCmd.MarkFlagRequired("format")
+	importClientConfigCmd.Flags().BoolVar(
+		&importClientConfigUpsert,
+		"upsert",
+		false,
+		"Update the servers that are already registered instead of skipping them",
+	)
+	importClientConfigCmd.Flags().BoolVar(
+		&importClientConfigDryRun,
+		"dry-run",
+		false,
+		"Only show the plan, without registering anything",
+	)
+
+	importCmd.Flags().StringVarP(
+		&importBundleDir,
+		"dir",
+		"d",
+		"",
+		"Directory written by `mcpjungle export` to import, eg- .mcpjungle",
+	)
+	addS3Flags(importCmd.Flags(), &importBundleS3, "S3 URL of the bundle to import, eg- s3://bucket/key.tar.gz", false)
+	importCmd.MarkFlagsMutuallyExclusive("dir", "s3-url")
+	importCmd.Flags().BoolVar(
+		&importBundleUpsert,
+		"upsert",
+		false,
+		"Update the servers and groups that already exist instead of skipping them",
+	)
+	importCmd.Flags().BoolVar(
+		&importBundleDryRun,
+		"dry-run",
+		false,
+		"Only show the differences with the registry, without importing anything",
+	)
+	importCmd.Flags().BoolVar(
+		&importBundleContinueOnError,
+		"continue-on-error",
+		false,
+		"Keep importing the other entities when one of them can't be imported, the command still fails",
+	)
+
+	importCmd.AddCommand(importClientConfigCmd)
+	rootCmd.AddCommand(importCmd)
+}
+
+// importedServer is the import of an entry of the configuration of an MCP client.
+type importedServer struct {
+	Name      string `json:"name"`
+	Transport string `json:"transport,omitempty"`
+	Action    string `json:"action"`
+	// Reason is why the entry is skipped, or why its import failed
+	Reason string `json:"reason,omitempty"`
+	// Placeholders lists the fields of the server that reference env vars, eg- env.GITHUB_TOKEN
+	Placeholders []string `json:"placeholders,omitempty"`
+	// File is the configuration file the server was read from, for the imports of an export directory
+	File string `json:"file,omitempty"`
+	// Fields are the fields the import changes in the live configuration of the server, for the dry runs of a bundle
+	Fields []syncCheckFieldDiff `json:"fields,omitempty"`
+
+	input *types.RegisterServerInput
+}
+
+// target returns what the server connects to, its command or its URL.
+func (s *importedServer) target() string {
+	if s.input == nil {
+		return ""
+	}
+	command, args := s.input.Command, s.input.Args
+	if s.input.Package != nil {
+		command, args = s.input.Package.CommandLine(args)
+	}
+	if command != "" {
+		return strings.Join(append([]string{command}, args...), " ")
+	}
+	return s.input.URL
+}
+
+var importPlanColumns = []tableColumn[*importedServer]{
+	{name: "name", value: func(s *importedServer) string { return s.Name }},
+	{name: "transport", value: func(s *importedServer) string { return s.Transport }},
+	{name: "action", value: func(s *importedServer) string { return s.Action }},
+	{name: "details", value: func(s *importedServer) string {
+		if s.Reason != "" {
+			return s.Reason
+		}
+		return s.target()
+	}},
+}
+
+// clientConfigServer is an entry of the configuration file of an MCP client.
+// It covers the fields of the entries of Claude Desktop, Claude Code (.mcp.json), Cursor and VS Code.
+// mcpjungle writes its own extension, see mcpJSONExtensionKey.
+type clientConfigServer struct {
+	Type    string            `json:"type,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+
+	Extension json.RawMessage `json:"x-mcpjungle,omitempty"`
+}
+
+func runImportClientConfig(cmd *cobra.Command, args []string) error {
+	path := importClientConfigFile
+	switch importClientConfigFormat {
+	case importFormatClaudeDesktop:
+		if path == "" {
+			var err error
+			if path, err = claudeDesktopConfigPath(); err != nil {
+				return err
+			}
+		}
+	case importFormatMCPJSON:
+		if path == "" {
+			path = ".mcp.json"
+		}
+	default:
+		return usageErrorf(
+			"unsupported format '%s', supported formats: %s, %s",
+			importClientConfigFormat, importFormatClaudeDesktop, importFormatMCPJSON,
+		)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the configuration of the client: %w", err)
+	}
+	servers, err := parseClientConfig(data, importClientConfigFormat)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("%s doesn't configure any MCP server", path)
+	}
+
+	existing, err := apiClient.ListServersContext(commandContext(cmd))
+	if err != nil {
+		return fmt.Errorf("failed to list the registered servers: %w", err)
+	}
+	planImport(servers, existing, apiClient.BaseURL(), importClientConfigUpsert)
+
+	p := newPrinter(cmd)
+	if !isStructuredOutput() {
+		if err := renderTable(cmd.OutOrStdout(), importPlanColumns, servers); err != nil {
+			return err
+		}
+	}
+	for _, s := range servers {
+		for _, field := range s.Placeholders {
+			p.Warnf(
+				"%s of server %s references an env var, set its value with `mcpjungle edit server %s`", field, s.Name, s.Name,
+			)
+		}
+	}
+	if importClientConfigDryRun {
+		if isStructuredOutput() {
+			return printOutput(cmd, servers)
+		}
+		return nil
+	}
+
+	failures := applyImport(cmd, servers)
+	if isStructuredOutput() {
+		if err := printOutput(cmd, servers); err != nil {
+			return err
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf(
+			"%d of %d servers could not be imported:\n%w", len(failures), len(servers), errors.Join(failures...),
+		)
+	}
+	return nil
+}
+
+// parseClientConfig converts the entries of the configuration file of an MCP client into mcpjungle servers.
+// Entries that can't be converted are returned with the skip action and the reason why.
+func parseClientConfig(data []byte, format string) ([]*importedServer, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	keys := []string{"mcpServers"}
+	if format == importFormatMCPJSON {
+		// VS Code names the field "servers"
+		keys = append(keys, "servers")
+	}
+
+	var servers []*importedServer
+	for _, key := range keys {
+		raw, ok := doc[key]
+		if !ok {
+			continue
+		}
+		var entries map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		for _, name := range slices.Sorted(maps.Keys(entries)) {
+			servers = append(servers, convertClientConfigServer(name, entries[name]))
+		}
+	}
+	return servers, nil
+}
+
+// convertClientConfigServer converts an entry of the configuration of an MCP client into an mcpjungle server.
+func convertClientConfigServer(name string, raw json.RawMessage) *importedServer {
+	s := &importedServer{Name: name, Action: importActionRegister}
+	skip := func(format string, args ...any) *importedServer {
+		s.Action = importActionSkip
+		s.Reason = fmt.Sprintf(format, args...)
+		s.input = nil
+		return s
+	}
+
+	var entry clientConfigServer
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return skip("invalid entry: %v", err)
+	}
+
+	input := &types.RegisterServerInput{Name: name}
+	switch strings.ToLower(entry.Type) {
+	case "", "stdio":
+		switch {
+		case entry.Command != "":
+			input.Transport = string(types.TransportStdio)
+		case entry.URL != "" && strings.HasSuffix(strings.TrimSuffix(entry.URL, "/"), "/sse"):
+			input.Transport = string(types.TransportSSE)
+		case entry.URL != "":
+			input.Transport = string(types.TransportStreamableHTTP)
+		default:
+			return skip("the entry has neither a command nor a url")
+		}
+	case "http", "streamable-http", "streamable_http", "streamablehttp":
+		input.Transport = string(types.TransportStreamableHTTP)
+	case "sse":
+		input.Transport = string(types.TransportSSE)
+	default:
+		return skip("transport %s is not supported", entry.Type)
+	}
+	s.Transport = input.Transport
+	s.input = input
+
+	if input.Transport == string(types.TransportStdio) {
+		input.Command = entry.Command
+		input.Args = entry.Args
+		input.Env = entry.Env
+		for i, arg := range entry.Args {
+			if envReference.MatchString(arg) {
+				s.Placeholders = append(s.Placeholders, fmt.Sprintf("args[%d]", i))
+			}
+		}
+		for _, k := range slices.Sorted(maps.Keys(entry.Env)) {
+			if envReference.MatchString(entry.Env[k]) {
+				s.Placeholders = append(s.Placeholders, "env."+k)
+			}
+		}
+	} else {
+		input.URL = entry.URL
+		for _, h := range slices.Sorted(maps.Keys(entry.Headers)) {
+			token, isBearer := strings.CutPrefix(entry.Headers[h], "Bearer ")
+			if !strings.EqualFold(h, "Authorization") || !isBearer {
+				return skip("header %s is not supported, mcpjungle only sends a bearer token to HTTP servers", h)
+			}
+			input.BearerToken = strings.TrimSpace(token)
+			if envReference.MatchString(input.BearerToken) {
+				s.Placeholders = append(s.Placeholders, "bearer_token")
+			}
+		}
+		if envReference.MatchString(input.URL) {
+			s.Placeholders = append(s.Placeholders, "url")
+		}
+	}
+
+	if len(entry.Extension) > 0 {
+		if err := applyMCPJSONExtension(input, entry.Extension); err != nil {
+			return skip("invalid %s: %v", mcpJSONExtensionKey, err)
+		}
+	}
+
+	if err := input.Validate(); err != nil {
+		return skip("%v", err)
+	}
+	return s
+}
+
+// planImport decides what to do with every importable server: register it, update it if it is already registered
+// and upsert is set, or skip it.
+// The entries connecting the client to mcpjungle itself are skipped, mcpjungle would otherwise proxy itself.
+func planImport(servers []*importedServer, existing []*types.McpServer, gatewayURL string, upsert bool) {
+	registered := make(map[string]bool, len(existing))
+	for _, s := range existing {
+		registered[s.Name] = true
+	}
+	gatewayURL = strings.TrimSuffix(gatewayURL, "/")
+	for _, s := range servers {
+		if s.Action == importActionSkip {
+			continue
+		}
+		switch {
+		case gatewayURL != "" && connectsTo(s.input, gatewayURL):
+			s.Action, s.Reason = importActionSkip, "the entry connects to this mcpjungle server"
+		case registered[s.Name] && !upsert:
+			s.Action, s.Reason = importActionSkip, "already registered, use --upsert to update it"
+		case registered[s.Name]:
+			s.Action = importActionUpdate
+		}
+	}
+}
+
+// connectsTo reports whether a server connects to an endpoint of the mcpjungle server at url,
+// eg- an entry running mcp-remote to connect Claude Desktop to the gateway.
+func connectsTo(s *types.RegisterServerInput, url string) bool {
+	for _, v := range append([]string{s.URL}, s.Args...) {
+		if v == url || strings.HasPrefix(v, url+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// applyImport registers or updates the servers according to the plan.
+// All servers are imported even if some of them fail, the failures are returned.
+func applyImport(cmd *cobra.Command, servers []*importedServer) []error {
+	p := newPrinter(cmd)
+	var failures []error
+	for _, s := range servers {
+		if err := applyServerImport(cmd, p, s); err != nil {
+			failures = append(failures, err)
+		}
+	}
+	return failures
+}
+
+// applyServerImport registers or updates a server according to the plan, the servers it skips are left alone.
+// A failure is recorded as the reason of the server.
+func applyServerImport(cmd *cobra.Command, p *printer, s *importedServer) error {
+	var err error
+	switch s.Action {
+	case importActionRegister:
+		pr := p.Progress(fmt.Sprintf("Registering server %s, validating upstream connectivity", s.Name))
+		_, err = apiClient.RegisterServerContext(commandContext(cmd), s.input)
+		pr.Stop()
+	case importActionUpdate:
+		pr := p.Progress(fmt.Sprintf("Updating server %s, validating upstream connectivity", s.Name))
+		_, err = apiClient.UpdateServerContext(commandContext(cmd), s.input)
+		pr.Stop()
+	default:
+		return nil
+	}
+	if err != nil {
+		s.Reason = err.Error()
+		return fmt.Errorf("failed to %s server %s: %w", s.Action, s.Name, err)
+	}
+	if s.Action == importActionUpdate {
+		p.Infof("Server %s updated successfully\n", s.Name)
+	} else {
+		p.Infof("Server %s registered successfully\n", s.Name)
+	}
+	return nil
+}
+
+// importedGroup is the import of a tool group of a bundle.
+type importedGroup struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+	// File is the configuration file the group was read from, for the imports of an export directory
+	File string `json:"file,omitempty"`
+	// Fields are the fields the import changes in the live configuration of the group, for the dry runs
+	Fields []syncCheckFieldDiff `json:"fields,omitempty"`
+
+	group types.ToolGroup
+}
+
+var importGroupPlanColumns = []tableColumn[*importedGroup]{
+	{name: "group", value: func(g *importedGroup) string { return g.Name }},
+	{name: "action", value: func(g *importedGroup) string { return g.Action }},
+	{name: "details", value: func(g *importedGroup) string { return g.Reason }},
+}
+
+// importBundleResult is the result of importing a bundle, as printed in the structured output formats.
+type importBundleResult struct {
+	Servers []*importedServer `json:"servers"`
+	Groups  []*importedGroup  `json:"groups"`
+	// Invalid are the files of an export directory that aren't valid configurations, they are skipped
+	Invalid []*importInvalidFile `json:"invalid_files,omitempty"`
+}
+
+// importInvalidFile is a file of an export directory that isn't a valid configuration.
+type importInvalidFile struct {
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
+
+func runImportBundle(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+	var bundle *exportBundle
+	var scanned *scannedBundle
+	var err error
+	switch {
+	case importBundleDir != "":
+		root := clientconfig.ExpandHome(importBundleDir)
+		if scanned, err = scanBundleDir(root); err != nil {
+			return err
+		}
+		bundle = &scanned.exportBundle
+		// the files that don't match the manifest are imported anyway, the mismatches are only reported
+		mismatches, err := verifyExportManifest(root, scanned.files())
+		if err != nil {
+			p.Warnf("the files can't be verified: %v", err)
+		}
+		for _, m := range mismatches {
+			p.Warnf("%s", m)
+		}
+	case importBundleS3.url != "":
+		if bundle, err = downloadImportBundle(cmd); err != nil {
+			return err
+		}
+	default:
+		return cmd.Help()
+	}
+	ctx := commandContext(cmd)
+
+	existingServers, err := apiClient.ListServersContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the registered servers: %w", err)
+	}
+	existingGroups, err := apiClient.ListToolGroupsContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the tool groups: %w", err)
+	}
+	// the live configurations are compared in a dry run, and hold the secrets an export redacted
+	var liveServers []*types.RegisterServerInput
+	if importBundleDryRun || hasSecretPlaceholders(bundle.Servers) {
+		if liveServers, err = apiClient.GetServerConfigsContext(ctx); err != nil {
+			return fmt.Errorf("failed to get the configurations of the registered servers: %w", err)
+		}
+	}
+	res := &importBundleResult{Servers: make([]*importedServer, 0, len(bundle.Servers))}
+	if scanned != nil {
+		for _, f := range scanned.invalid {
+			res.Invalid = append(res.Invalid, &importInvalidFile{File: f.path, Reason: f.err.Error()})
+		}
+	}
+	for i, s := range bundle.Servers {
+		imported := &importedServer{Name: s.Name, Transport: s.Transport, Action: importActionRegister, input: s}
+		if scanned != nil {
+			imported.File = scanned.serverFiles[i]
+			if s.Name == "" {
+				res.Invalid = append(res.Invalid, &importInvalidFile{
+					File: imported.File, Reason: "it has no name, it is not the configuration of an MCP server",
+				})
+				continue
+			}
+		}
+		if err := resolveSecretPlaceholders(s, liveServers); err != nil {
+			imported.Action, imported.Reason, imported.input = importActionSkip, err.Error(), nil
+		} else if err := s.Validate(); err != nil {
+			imported.Action, imported.Reason, imported.input = importActionSkip, err.Error(), nil
+		}
+		res.Servers = append(res.Servers, imported)
+	}
+	planImport(res.Servers, existingServers, apiClient.BaseURL(), importBundleUpsert)
+	var groups []types.ToolGroup
+	var groupFiles []string
+	for i, g := range bundle.Groups {
+		if scanned != nil && g.Name == "" {
+			res.Invalid = append(res.Invalid, &importInvalidFile{
+				File: scanned.groupFiles[i], Reason: "it has no name, it is not the configuration of a tool group",
+			})
+			continue
+		}
+		groups = append(groups, g)
+		if scanned != nil {
+			groupFiles = append(groupFiles, scanned.groupFiles[i])
+		}
+	}
+	res.Groups = planGroupImport(groups, existingGroups, importBundleUpsert)
+	for i, f := range groupFiles {
+		res.Groups[i].File = f
+	}
+	for _, f := range res.Invalid {
+		p.Warnf("skipping %s: %s", f.File, f.Reason)
+	}
+	// the dependencies are resolved before anything is imported, so that an import that can't succeed changes nothing
+	graph, missing := resolveImportDeps(res, existingServers)
+	order, err := graph.order()
+	if err != nil {
+		return err
+	}
+	missingErr := missingReferenceErrors(res, missing)
+
+	if importBundleDryRun {
+		if err := diffImport(ctx, res, liveServers); err != nil {
+			return err
+		}
+		if isStructuredOutput() {
+			if err := printOutput(cmd, res); err != nil {
+				return err
+			}
+		} else {
+			renderImportDiff(p, res)
+		}
+		if len(res.Invalid) > 0 {
+			return fmt.Errorf("%d files are not valid configurations", len(res.Invalid))
+		}
+		return missingErr
+	}
+	var failures []error
+	if missingErr != nil {
+		if !importBundleContinueOnError {
+			return fmt.Errorf("nothing was imported:\n%w", missingErr)
+		}
+		// the groups with missing references are skipped, the import still fails because of them
+		for _, g := range res.Groups {
+			if err, ok := missing[g]; ok {
+				g.Action, g.Reason = importActionSkip, err.Error()
+				failures = append(failures, err)
+			}
+		}
+	}
+
+	if !isStructuredOutput() {
+		if err := renderTable(cmd.OutOrStdout(), importPlanColumns, res.Servers); err != nil {
+			return err
+		}
+		p.Resultln()
+		if err := renderTable(cmd.OutOrStdout(), importGroupPlanColumns, res.Groups); err != nil {
+			return err
+		}
+	}
+
+	// the groups are created once their servers are registered
+	failures = append(failures, applyImportInOrder(cmd, res, order, graph, importBundleContinueOnError)...)
+	if isStructuredOutput() {
+		if err := printOutput(cmd, res); err != nil {
+			return err
+		}
+	} else if scanned != nil {
+		p.Resultln()
+		if err := renderTable(cmd.OutOrStdout(), importFileColumns, res.fileResults()); err != nil {
+			return err
+		}
+	}
+	if scanned != nil && len(failures)+len(res.Invalid) > 0 {
+		for _, f := range res.Invalid {
+			failures = append(failures, fmt.Errorf("%s is not a valid configuration: %s", f.File, f.Reason))
+		}
+		return fmt.Errorf(
+			"%d of %d files could not be imported:\n%w",
+			len(failures), len(res.Servers)+len(res.Groups)+len(res.Invalid), errors.Join(failures...),
+		)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf(
+			"%d of %d entities could not be imported:\n%w",
+			len(failures), len(res.Servers)+len(res.Groups), errors.Join(failures...),
+		)
+	}
+	return nil
+}
+
+// downloadImportBundle downloads and reads the bundle of --s3-url.
+func downloadImportBundle(cmd *cobra.Command) (*exportBundle, error) {
+	ctx := commandContext(cmd)
+	bucket, key, err := s3.ParseURL(importBundleS3.url)
+	if err != nil {
+		return nil, usageErrorf("%v", err)
+	}
+	if key == "" || strings.HasSuffix(key, "/") {
+		return nil, usageErrorf(
+			"the S3 URL must name the object of the bundle, eg- s3://%s/mcpjungle-export.tar.gz", bucket,
+		)
+	}
+	store, err := newS3Client(ctx, &importBundleS3)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := newPrinter(cmd).Progress(fmt.Sprintf("Downloading s3://%s/%s", bucket, key))
+	defer pr.Stop()
+	body, err := store.Download(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return readBundleArchive(body)
+}
+
+// importFileResult is the outcome of the import of a configuration file of an export directory.
+type importFileResult struct {
+	file   string
+	entity string
+	result string
+}
+
+var importFileColumns = []tableColumn[importFileResult]{
+	{name: "file", value: func(r importFileResult) string { return r.file }},
+	{name: "entity", value: func(r importFileResult) string { return r.entity }},
+	{name: "result", value: func(r importFileResult) string { return r.result }},
+}
+
+// fileResults returns the outcome of the import of every file, once the import was applied.
+func (r *importBundleResult) fileResults() []importFileResult {
+	outcome := func(action, reason, done string) string {
+		switch {
+		case action == importActionSkip:
+			return "skipped: " + reason
+		case reason != "":
+			return "failed: " + reason
+		case action == importActionUpdate:
+			return "updated"
+		default:
+			return done
+		}
+	}
+	results := make([]importFileResult, 0, len(r.Servers)+len(r.Groups)+len(r.Invalid))
+	for _, s := range r.Servers {
+		results = append(results, importFileResult{
+			file: s.File, entity: "MCP server " + s.Name, result: outcome(s.Action, s.Reason, "registered"),
+		})
+	}
+	for _, g := range r.Groups {
+		results = append(results, importFileResult{
+			file: g.File, entity: "tool group " + g.Name, result: outcome(g.Action, g.Reason, "created"),
+		})
+	}
+	for _, f := range r.Invalid {
+		results = append(results, importFileResult{file: f.File, entity: "-", result: "invalid: " + f.Reason})
+	}
+	return results
+}
+
+// diffImport compares the servers and groups of a dry run with their live configurations, field by field.
+// The entities that exist get the fields the import would change, even the ones skipped without --upsert,
+// and the ones the import would leave as they are are marked unchanged.
+func diffImport(ctx context.Context, res *importBundleResult, liveServers []*types.RegisterServerInput) error {
+	liveGroups, err := apiClient.GetToolGroupConfigsContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the configurations of the tool groups: %w", err)
+	}
+
+	for _, s := range res.Servers {
+		i := slices.IndexFunc(liveServers, func(live *types.RegisterServerInput) bool { return live.Name == s.Name })
+		if i < 0 || s.input == nil {
+			continue
+		}
+		changes, err := diffEntities(
+			syncCheckKindServer,
+			[]*types.RegisterServerInput{s.input},
+			liveServers[i:i+1],
+			func(s *types.RegisterServerInput) string { return s.Name },
+		)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			s.Action, s.Reason = importActionUnchanged, ""
+			continue
+		}
+		s.Fields = changes[0].Fields
+	}
+	for _, g := range res.Groups {
+		i := slices.IndexFunc(liveGroups, func(live types.ToolGroup) bool { return live.Name == g.Name })
+		if i < 0 {
+			continue
+		}
+		changes, err := diffEntities(
+			syncCheckKindGroup,
+			[]types.ToolGroup{g.group},
+			liveGroups[i:i+1],
+			func(g types.ToolGroup) string { return g.Name },
+			"version", "uuid",
+		)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			g.Action, g.Reason = importActionUnchanged, ""
+			continue
+		}
+		g.Fields = changes[0].Fields
+	}
+	return nil
+}
+
+// renderImportDiff prints what the import of a dry run would do to every entity, with the fields it would change.
+// The secrets are masked, like in the reports of `sync check`.
+func renderImportDiff(p *printer, res *importBundleResult) {
+	signs := map[string]string{
+		importActionRegister:  "+",
+		importActionCreate:    "+",
+		importActionUpdate:    "~",
+		importActionUnchanged: "=",
+		importActionSkip:      "!",
+	}
+	counts := make(map[string]int)
+	render := func(kind, name, action, reason string, fields []syncCheckFieldDiff) {
+		if action == importActionRegister {
+			action = importActionCreate
+		}
+		counts[action]++
+		if reason != "" {
+			p.Resultf("%s %s %s (%s: %s)\n", signs[action], kind, name, action, reason)
+		} else {
+			p.Resultf("%s %s %s (%s)\n", signs[action], kind, name, action)
+		}
+		for _, f := range fields {
+			p.Resultf("    %s: %s -> %s\n", f.Field, formatConfigValue(f.Old), formatConfigValue(f.New))
+		}
+	}
+	for _, s := range res.Servers {
+		render(syncCheckKindServer, s.Name, s.Action, s.Reason, s.Fields)
+	}
+	for _, g := range res.Groups {
+		render(syncCheckKindGroup, g.Name, g.Action, g.Reason, g.Fields)
+	}
+	p.Resultf(
+		"\n%d to create, %d to update, %d unchanged, %d skipped\n",
+		counts[importActionCreate], counts[importActionUpdate], counts[importActionUnchanged], counts[importActionSkip],
+	)
+}
+
+// planGroupImport decides whether every group is created, updated if it exists and upsert is set, or skipped.
+func planGroupImport(groups, existing []types.ToolGroup, upsert bool) []*importedGroup {
+	exists := make(map[string]bool, len(existing))
+	for _, g := range existing {
+		exists[g.Name] = true
+	}
+	planned := make([]*importedGroup, 0, len(groups))
+	for _, g := range groups {
+		// the version of the group is the one of the exported server, it must not be sent as a precondition
+		g.Version = 0
+		imported := &importedGroup{Name: g.Name, Action: importActionCreate, group: g}
+		switch {
+		case exists[g.Name] && !upsert:
+			imported.Action, imported.Reason = importActionSkip, "already exists, use --upsert to update it"
+		case exists[g.Name]:
+			imported.Action = importActionUpdate
+		}
+		planned = append(planned, imported)
+	}
+	return planned
+}
+
+// applyToolGroupImport creates or updates a group according to the plan, the groups it skips are left alone.
+// A failure is recorded as the reason of the group.
+func applyToolGroupImport(cmd *cobra.Command, p *printer, g *importedGroup) error {
+	var err error
+	switch g.Action {
+	case importActionCreate:
+		_, err = apiClient.CreateToolGroupContext(commandContext(cmd), &g.group)
+	case importActionUpdate:
+		_, err = apiClient.UpdateToolGroupContext(commandContext(cmd), &g.group)
+	default:
+		return nil
+	}
+	if err != nil {
+		g.Reason = err.Error()
+		return fmt.Errorf("failed to %s group %s: %w", g.Action, g.Name, err)
+	}
+	if g.Action == importActionUpdate {
+		p.Infof("Tool group %s updated successfully\n", g.Name)
+	} else {
+		p.Infof("Tool group %s created successfully\n", g.Name)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// importNode is an entity of an import in its dependency graph, eg- the MCP server github.
+type importNode struct {
+	kind string
+	name string
+}
+
+func (n importNode) String() string {
+	if n.kind == syncCheckKindServer {
+		return "MCP server " + n.name
+	}
+	return "tool group " + n.name
+}
+
+// importGraph is the dependency graph of the entities of an import: an entity depends on the ones it references,
+// which must be imported before it, eg- a tool group depends on the servers of its tools.
+type importGraph struct {
+	nodes []importNode
+	deps  map[importNode][]importNode
+}
+
+func newImportGraph() *importGraph {
+	return &importGraph{deps: make(map[importNode][]importNode)}
+}
+
+// add adds an entity to the graph, with the entities it depends on. An entity added twice keeps its first place.
+func (g *importGraph) add(n importNode, deps ...importNode) {
+	if _, ok := g.deps[n]; !ok {
+		g.nodes = append(g.nodes, n)
+	}
+	for _, d := range deps {
+		if !slices.Contains(g.deps[n], d) {
+			g.deps[n] = append(g.deps[n], d)
+		}
+	}
+	if g.deps[n] == nil {
+		g.deps[n] = []importNode{}
+	}
+}
+
+// order returns the entities in the order they are imported, every entity after the ones it depends on.
+// The entities keep the order they were added in otherwise, so that the order is deterministic.
+// A cycle of dependencies is an error, since none of its entities can be imported first.
+func (g *importGraph) order() ([]importNode, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[importNode]int, len(g.nodes))
+	ordered := make([]importNode, 0, len(g.nodes))
+	var path []importNode
+	var visit func(n importNode) error
+	visit = func(n importNode) error {
+		switch state[n] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := append(path[slices.Index(path, n):], n)
+			names := make([]string, len(cycle))
+			for i, c := range cycle {
+				names[i] = c.String()
+			}
+			return fmt.Errorf("dependency cycle: %s", strings.Join(names, " -> "))
+		}
+		state[n] = visiting
+		path = append(path, n)
+		for _, d := range g.deps[n] {
+			// the references to entities outside of the import are resolved beforehand
+			if _, ok := g.deps[d]; !ok {
+				continue
+			}
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[n] = visited
+		ordered = append(ordered, n)
+		return nil
+	}
+	for _, n := range g.nodes {
+		if err := visit(n); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// groupServers returns the names of the servers a tool group references, through its servers or its tools.
+func groupServers(g types.ToolGroup) []string {
+	servers := slices.Clone(g.IncludedServers)
+	for _, t := range g.IncludedTools {
+		if server, _, ok := strings.Cut(t, "__"); ok {
+			servers = append(servers, server)
+		}
+	}
+	slices.Sort(servers)
+	return slices.Compact(servers)
+}
+
+// resolveImportDeps builds the dependency graph of the servers and groups of an import. The references to servers
+// that neither are registered nor will be by the import are returned as errors, by group.
+func resolveImportDeps(res *importBundleResult, existing []*types.McpServer) (*importGraph, map[*importedGroup]error) {
+	registered := make(map[string]bool, len(existing))
+	for _, s := range existing {
+		registered[s.Name] = true
+	}
+	imported := make(map[string]bool, len(res.Servers))
+	graph := newImportGraph()
+	for _, s := range res.Servers {
+		graph.add(importNode{kind: syncCheckKindServer, name: s.Name})
+		if s.Action != importActionSkip {
+			imported[s.Name] = true
+		}
+	}
+
+	missing := make(map[*importedGroup]error)
+	for _, g := range res.Groups {
+		node := importNode{kind: syncCheckKindGroup, name: g.Name}
+		var deps []importNode
+		var unresolved []string
+		for _, s := range groupServers(g.group) {
+			switch {
+			case imported[s]:
+				deps = append(deps, importNode{kind: syncCheckKindServer, name: s})
+			case !registered[s]:
+				unresolved = append(unresolved, s)
+			}
+		}
+		graph.add(node, deps...)
+		if len(unresolved) > 0 && g.Action != importActionSkip {
+			missing[g] = fmt.Errorf(
+				"%s references MCP servers that are neither registered nor imported: %s",
+				node, strings.Join(unresolved, ", "),
+			)
+		}
+	}
+	return graph, missing
+}
+
+// missingReferenceErrors returns the errors of the groups with missing references, in the order of the groups.
+func missingReferenceErrors(res *importBundleResult, missing map[*importedGroup]error) error {
+	var errs []error
+	for _, g := range res.Groups {
+		if err, ok := missing[g]; ok {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applyImportInOrder imports the servers and groups in the given order, the one of their dependencies in graph.
+// An entity whose dependency could not be imported is skipped. Unless continueOnError is set, the import stops at
+// the first failure and the entities that are left are skipped, otherwise all the others are imported.
+// The failures are returned.
+func applyImportInOrder(
+	cmd *cobra.Command, res *importBundleResult, order []importNode, graph *importGraph, continueOnError bool,
+) []error {
+	p := newPrinter(cmd)
+	servers := make(map[string][]*importedServer, len(res.Servers))
+	for _, s := range res.Servers {
+		servers[s.Name] = append(servers[s.Name], s)
+	}
+	groups := make(map[string][]*importedGroup, len(res.Groups))
+	for _, g := range res.Groups {
+		groups[g.Name] = append(groups[g.Name], g)
+	}
+
+	var failures []error
+	failed := make(map[importNode]bool)
+	stopped := false
+	// skipReason is why an entity that was to be imported is skipped, if it is
+	skipReason := func(n importNode) string {
+		if stopped {
+			return "the import stopped at the first failure, use --continue-on-error to import it anyway"
+		}
+		if i := slices.IndexFunc(graph.deps[n], func(d importNode) bool { return failed[d] }); i >= 0 {
+			failed[n] = true
+			return fmt.Sprintf("depends on %s, which could not be imported", graph.deps[n][i])
+		}
+		return ""
+	}
+	for _, n := range order {
+		var errs []error
+		if n.kind == syncCheckKindServer {
+			for _, s := range servers[n.name] {
+				if s.Action != importActionRegister && s.Action != importActionUpdate {
+					continue
+				}
+				if reason := skipReason(n); reason != "" {
+					s.Action, s.Reason = importActionSkip, reason
+				} else if err := applyServerImport(cmd, p, s); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		} else {
+			for _, g := range groups[n.name] {
+				if g.Action != importActionCreate && g.Action != importActionUpdate {
+					continue
+				}
+				if reason := skipReason(n); reason != "" {
+					g.Action, g.Reason = importActionSkip, reason
+				} else if err := applyToolGroupImport(cmd, p, g); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+		if len(errs) > 0 {
+			failed[n] = true
+			failures = append(failures, errs...)
+			stopped = !continueOnError
+		}
+	}
+	return failures
+}
@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+const testClaudeDesktopConfig = `{
+  "globalShortcut": "Ctrl+Space",
+  "mcpServers": {
+    "filesystem": {"command": "npx", "args": ["-y", "@modelcontextprotocol/server-filesystem", "/tmp"]},
+    "github": {
+      "command": "docker",
+      "args": ["run", "-i", "--rm", "-e", "GITHUB_PERSONAL_ACCESS_TOKEN", "ghcr.io/github/github-mcp-server"],
+      "env": {"GITHUB_PERSONAL_ACCESS_TOKEN": "${GITHUB_TOKEN}"}
+    },
+    "mcpjungle": {"command": "npx", "args": ["mcp-remote", "http://127.0.0.1:8080/mcp", "--allow-http"]},
+    "my server": {"command": "my-server"}
+  }
+}`
+
+const testMCPJSONConfig = `{
+  "mcpServers": {
+    "context7": {"type": "http", "url": "https://mcp.context7.com/mcp", "headers": {"Authorization": "Bearer ${CONTEXT7_TOKEN}"}},
+    "linear": {"type": "sse", "url": "https://mcp.linear.app/sse"},
+    "sentry": {"url": "https://mcp.sentry.dev/mcp", "headers": {"X-Org": "acme"}},
+    "remote": {"type": "ws", "url": "wss://example.com/mcp"}
+  }
+}`
+
+func TestParseClientConfig(t *testing.T) {
+	servers, err := parseClientConfig([]byte(testClaudeDesktopConfig), importFormatClaudeDesktop)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 4, len(servers))
+
+	fs := servers[0]
+	testhelpers.AssertEqual(t, "filesystem", fs.Name)
+	testhelpers.AssertEqual(t, string(types.TransportStdio), fs.Transport)
+	testhelpers.AssertEqual(t, importActionRegister, fs.Action)
+	testhelpers.AssertEqual(t, "npx -y @modelcontextprotocol/server-filesystem /tmp", fs.target())
+
+	// env var references are kept as they are, and reported
+	github := servers[1]
+	testhelpers.AssertEqual(t, "${GITHUB_TOKEN}", github.input.Env["GITHUB_PERSONAL_ACCESS_TOKEN"])
+	testhelpers.AssertEqual(t, 1, len(github.Placeholders))
+	testhelpers.AssertEqual(t, "env.GITHUB_PERSONAL_ACCESS_TOKEN", github.Placeholders[0])
+
+	invalid := servers[3]
+	testhelpers.AssertEqual(t, importActionSkip, invalid.Action)
+	testhelpers.AssertStringContains(t, invalid.Reason, "name")
+
+	servers, err = parseClientConfig([]byte(testMCPJSONConfig), importFormatMCPJSON)
+	testhelpers.AssertNoError(t, err)
+	byName := make(map[string]*importedServer)
+	for _, s := range servers {
+		byName[s.Name] = s
+	}
+	testhelpers.AssertEqual(t, string(types.TransportStreamableHTTP), byName["context7"].Transport)
+	testhelpers.AssertEqual(t, "${CONTEXT7_TOKEN}", byName["context7"].input.BearerToken)
+	testhelpers.AssertEqual(t, "bearer_token", byName["context7"].Placeholders[0])
+	testhelpers.AssertEqual(t, string(types.TransportSSE), byName["linear"].Transport)
+	testhelpers.AssertEqual(t, importActionSkip, byName["sentry"].Action)
+	testhelpers.AssertStringContains(t, byName["sentry"].Reason, "header X-Org is not supported")
+	testhelpers.AssertEqual(t, importActionSkip, byName["remote"].Action)
+	testhelpers.AssertEqual(t, "transport ws is not supported", byName["remote"].Reason)
+
+	_, err = parseClientConfig([]byte("{not json"), importFormatMCPJSON)
+	testhelpers.AssertTrue(t, err != nil, "expected an error for an invalid file")
+}
+
+func TestParseVSCodeConfig(t *testing.T) {
+	config := `{"servers": {"github": {"type": "stdio", "command": "github-mcp-server", "args": ["--token", "${input:github-token}"]}}}`
+	servers, err := parseClientConfig([]byte(config), importFormatMCPJSON)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(servers))
+	testhelpers.AssertEqual(t, "args[1]", servers[0].Placeholders[0])
+
+	// Claude Desktop has no "servers" field
+	servers, err = parseClientConfig([]byte(config), importFormatClaudeDesktop)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 0, len(servers))
+}
+
+func TestPlanImport(t *testing.T) {
+	servers, err := parseClientConfig([]byte(testClaudeDesktopConfig), importFormatClaudeDesktop)
+	testhelpers.AssertNoError(t, err)
+	existing := []*types.McpServer{{Name: "filesystem"}}
+
+	planImport(servers, existing, "http://127.0.0.1:8080", false)
+	testhelpers.AssertEqual(t, importActionSkip, servers[0].Action)
+	testhelpers.AssertStringContains(t, servers[0].Reason, "--upsert")
+	testhelpers.AssertEqual(t, importActionRegister, servers[1].Action)
+	testhelpers.AssertEqual(t, importActionSkip, servers[2].Action)
+	testhelpers.AssertEqual(t, "the entry connects to this mcpjungle server", servers[2].Reason)
+
+	servers, err = parseClientConfig([]byte(testClaudeDesktopConfig), importFormatClaudeDesktop)
+	testhelpers.AssertNoError(t, err)
+	planImport(servers, existing, "http://127.0.0.1:8080", true)
+	testhelpers.AssertEqual(t, importActionUpdate, servers[0].Action)
+}
+
+func TestImportClientConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".mcp.json")
+	testhelpers.AssertNoError(t, os.WriteFile(path, []byte(testMCPJSONConfig), 0o600))
+
+	var registered, updated []string
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []*types.McpServer{{Name: "linear", Transport: "sse"}})
+		},
+		"POST /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			var input types.RegisterServerInput
+			_ = json.NewDecoder(r.Body).Decode(&input)
+			registered = append(registered, input.Name)
+			writeTestJSON(w, http.StatusCreated, &types.McpServer{Name: input.Name, Transport: input.Transport})
+		},
+		"PUT /api/v1/servers/linear": func(w http.ResponseWriter, r *http.Request) {
+			updated = append(updated, "linear")
+			writeTestJSON(w, http.StatusOK, &types.McpServer{Name: "linear", Transport: "sse"})
+		},
+	})
+	origFile, origFormat, origUpsert := importClientConfigFile, importClientConfigFormat, importClientConfigUpsert
+	t.Cleanup(func() {
+		importClientConfigFile, importClientConfigFormat, importClientConfigUpsert = origFile, origFormat, origUpsert
+	})
+	importClientConfigFile, importClientConfigFormat, importClientConfigUpsert = path, importFormatMCPJSON, true
+
+	var out, stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetErr(&stderr)
+	testhelpers.AssertNoError(t, runImportClientConfig(cmd, nil))
+
+	testhelpers.AssertEqual(t, 1, len(registered))
+	testhelpers.AssertEqual(t, "context7", registered[0])
+	testhelpers.AssertEqual(t, 1, len(updated))
+	testhelpers.AssertStringContains(t, out.String(), "ACTION")
+	testhelpers.AssertStringContains(t, out.String(), "transport ws is not supported")
+	testhelpers.AssertStringContains(t, stderr.String(), "bearer_token of server context7 references an env var")
+}
+
+func TestImportDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		testhelpers.AssertNoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		testhelpers.AssertNoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	writeFile("servers/github.json", `{"name": "github", "transport": "streamable_http", "url": "https://api.githubcopilot.com/mcp/"}`)
+	writeFile("servers/linear.yaml", "name: linear\ntransport: sse\nurl: https://mcp.linear.app/sse\nbearer_token: lin-new-token-5678\n")
+	writeFile("servers/broken.json", `{"name": "broken",`)
+	writeFile("servers/unnamed.json", `{"transport": "stdio"}`)
+	writeFile("servers/README.md", "not a configuration")
+	writeFile("groups/ops.yaml", "name: ops\nincluded_servers:\n  - github\n")
+
+	var calls []string
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []*types.McpServer{{Name: "linear"}})
+		},
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{})
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []*types.RegisterServerInput{{
+				Name: "linear", Transport: "sse", URL: "https://mcp.linear.app/sse", BearerToken: "lin-old-token-1234",
+			}})
+		},
+		"POST /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			var input types.RegisterServerInput
+			_ = json.NewDecoder(r.Body).Decode(&input)
+			calls = append(calls, "register "+input.Name)
+			writeTestJSON(w, http.StatusCreated, &types.McpServer{Name: input.Name, Transport: input.Transport})
+		},
+		"PUT /api/v1/servers/{name}": func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, "update "+r.PathValue("name"))
+			writeTestJSON(w, http.StatusBadGateway, types.ErrorResponse{
+				Error: types.APIError{Code: types.ErrorCodeUpstreamUnreachable, Message: "failed to connect"},
+			})
+		},
+		"POST /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			var group types.ToolGroup
+			_ = json.NewDecoder(r.Body).Decode(&group)
+			calls = append(calls, "create "+group.Name)
+			writeTestJSON(w, http.StatusCreated, &types.CreateToolGroupResponse{})
+		},
+	})
+	origDir, origUpsert, origDryRun := importBundleDir, importBundleUpsert, importBundleDryRun
+	origContinue := importBundleContinueOnError
+	t.Cleanup(func() {
+		importBundleDir, importBundleUpsert, importBundleDryRun = origDir, origUpsert, origDryRun
+		importBundleContinueOnError = origContinue
+	})
+	importBundleDir, importBundleUpsert, importBundleDryRun, importBundleContinueOnError = dir, true, false, true
+
+	var out, stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetErr(&stderr)
+	err := runImportBundle(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "3 of 5 files could not be imported")
+	// the servers are imported before the groups, the group doesn't depend on the server that failed
+	testhelpers.AssertEqual(t, "register github,update linear,create ops", strings.Join(calls, ","))
+
+	// the invalid files are reported with their path, the summary has a row per file
+	testhelpers.AssertStringContains(t, stderr.String(), "skipping servers/broken.json")
+	testhelpers.AssertStringContains(t, stderr.String(), "skipping servers/unnamed.json: it has no name")
+	for _, want := range []string{
+		"servers/github.json   MCP server github",
+		"registered",
+		"failed: ",
+		"groups/ops.yaml       tool group ops",
+		"created",
+		"servers/broken.json",
+		"invalid: invalid MCP server",
+	} {
+		testhelpers.AssertStringContains(t, out.String(), want)
+	}
+	testhelpers.AssertStringNotContains(t, out.String(), "README")
+
+	// a dry run doesn't import anything, but still fails because of the invalid files
+	calls = nil
+	out.Reset()
+	importBundleDryRun = true
+	err = runImportBundle(cmd, nil)
+	testhelpers.AssertStringContains(t, err.Error(), "2 files are not valid configurations")
+	testhelpers.AssertEqual(t, 0, len(calls))
+	// it shows the changes to the live configurations, with the secrets masked
+	for _, want := range []string{
+		"+ server github (create)\n",
+		"~ server linear (update)\n    bearer_token: \"****1234\" -> \"****5678\"\n",
+		"+ group ops (create)\n",
+		"2 to create, 1 to update, 0 unchanged, 0 skipped",
+	} {
+		testhelpers.AssertStringContains(t, out.String(), want)
+	}
+	testhelpers.AssertStringNotContains(t, out.String(), "lin-new-token")
+
+	// without --upsert, the servers that exist are skipped, and left untouched if they don't change
+	writeFile("servers/linear.yaml", "name: linear\ntransport: sse\nurl: https://mcp.linear.app/sse\nbearer_token: lin-old-token-1234\n")
+	out.Reset()
+	importBundleUpsert = false
+	_ = runImportBundle(cmd, nil)
+	testhelpers.AssertStringContains(t, out.String(), "= server linear (unchanged)\n")
+	testhelpers.AssertStringContains(t, out.String(), "2 to create, 0 to update, 1 unchanged, 0 skipped")
+
+	importBundleDir = t.TempDir()
+	err = runImportBundle(cmd, nil)
+	testhelpers.AssertStringContains(t, err.Error(), "is not an export directory")
+}
+
+func TestImportGraphOrder(t *testing.T) {
+	server := func(name string) importNode { return importNode{kind: syncCheckKindServer, name: name} }
+	group := func(name string) importNode { return importNode{kind: syncCheckKindGroup, name: name} }
+
+	g := newImportGraph()
+	g.add(group("ops"), server("github"), server("linear"))
+	g.add(server("linear"))
+	g.add(group("dev"), server("github"))
+	g.add(server("github"))
+	order, err := g.order()
+	testhelpers.AssertNoError(t, err)
+	names := make([]string, len(order))
+	for i, n := range order {
+		names[i] = n.String()
+	}
+	// every entity comes after its dependencies, in the order it was added otherwise
+	testhelpers.AssertEqual(t,
+		"MCP server github,MCP server linear,tool group ops,tool group dev", strings.Join(names, ","))
+
+	g.add(server("github"), group("dev"))
+	_, err = g.order()
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(),
+		"dependency cycle: MCP server github -> tool group dev -> MCP server github")
+}
+
+func TestImportDirDependencies(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		testhelpers.AssertNoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		testhelpers.AssertNoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	writeFile("groups/dev.yaml", "name: dev\nincluded_tools:\n  - github__create_pr\n  - time__now\n")
+	writeFile("groups/ops.yaml", "name: ops\nincluded_servers:\n  - linear\n")
+	writeFile("servers/github.json", `{"name": "github", "transport": "streamable_http", "url": "https://api.githubcopilot.com/mcp/"}`)
+	writeFile("servers/linear.json", `{"name": "linear", "transport": "sse", "url": "https://mcp.linear.app/sse"}`)
+
+	var calls []string
+	registered := []*types.McpServer{{Name: "time"}}
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, registered)
+		},
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{})
+		},
+		"POST /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			var input types.RegisterServerInput
+			_ = json.NewDecoder(r.Body).Decode(&input)
+			calls = append(calls, "register "+input.Name)
+			if input.Name == "github" {
+				writeTestJSON(w, http.StatusBadGateway, types.ErrorResponse{
+					Error: types.APIError{Code: types.ErrorCodeUpstreamUnreachable, Message: "failed to connect"},
+				})
+				return
+			}
+			writeTestJSON(w, http.StatusCreated, &types.McpServer{Name: input.Name, Transport: input.Transport})
+		},
+		"POST /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			var group types.ToolGroup
+			_ = json.NewDecoder(r.Body).Decode(&group)
+			calls = append(calls, "create "+group.Name)
+			writeTestJSON(w, http.StatusCreated, &types.CreateToolGroupResponse{})
+		},
+	})
+	origDir, origContinue := importBundleDir, importBundleContinueOnError
+	t.Cleanup(func() { importBundleDir, importBundleContinueOnError = origDir, origContinue })
+	importBundleDir = dir
+
+	run := func(continueOnError bool) (string, error) {
+		t.Helper()
+		calls = nil
+		importBundleContinueOnError = continueOnError
+		var out bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+		err := runImportBundle(cmd, nil)
+		return out.String(), err
+	}
+
+	// the servers are registered before the groups that reference them, and the import stops at the first failure
+	out, err := run(false)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, "register github", strings.Join(calls, ","))
+	testhelpers.AssertStringContains(t, out, "skipped: the import stopped at the first failure")
+
+	// with --continue-on-error, the entities that don't depend on the failure are imported
+	out, err = run(true)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "1 of 4 files could not be imported")
+	testhelpers.AssertEqual(t, "register github,register linear,create ops", strings.Join(calls, ","))
+	testhelpers.AssertStringContains(t, out, "skipped: depends on MCP server github, which could not be imported")
+
+	// a reference to a server that neither is registered nor imported fails the import before any change
+	registered = nil
+	_, err = run(false)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "nothing was imported")
+	testhelpers.AssertStringContains(t, err.Error(),
+		"tool group dev references MCP servers that are neither registered nor imported: time")
+	testhelpers.AssertEqual(t, 0, len(calls))
+
+	// unless --continue-on-error is set, the group is then skipped
+	_, err = run(true)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "2 of 4 files could not be imported")
+	testhelpers.AssertEqual(t, "register github,register linear,create ops", strings.Join(calls, ","))
+}
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/mcpjungle/mcpjungle/cmd/config"
 	"github.com/spf13/cobra"
 )
 
@@ -25,8 +24,9 @@ func init() {
 }
 
 func runInitServer(cmd *cobra.Command, args []string) error {
-	fmt.Println("Initializing the MCPJungle Server in Enterprise Mode...")
-	resp, err := apiClient.InitServer()
+	p := newPrinter(cmd)
+	p.Infoln("Initializing the MCPJungle Server in Enterprise Mode...")
+	resp, err := apiClient.InitServerContext(commandContext(cmd))
 	if err != nil {
 		return fmt.Errorf("failed to initialize the server: %w", err)
 	}
@@ -35,21 +35,12 @@ func runInitServer(cmd *cobra.Command, args []string) error {
 		return errors.New("server initialization failed: no admin access token received")
 	}
 
-	// Create new client configuration
-	cfg := &config.ClientConfig{
-		RegistryURL: apiClient.BaseURL(),
-		AccessToken: resp.AdminAccessToken,
-	}
-	if err := config.Save(cfg); err != nil {
-		return fmt.Errorf("failed to create client configuration: %w", err)
-	}
-
-	cfgPath, err := config.AbsPath()
+	cfgPath, err := saveAccessTokenToActiveContext(activeContextName(), apiClient.BaseURL(), resp.AdminAccessToken)
 	if err != nil {
-		return fmt.Errorf("failed to get client configuration path: %w", err)
+		return err
 	}
-	fmt.Println("Your Admin access token has been saved to", cfgPath)
+	p.Infoln("Your Admin access token has been saved to", cfgPath)
 
-	fmt.Println("All done!")
+	p.Infoln("All done!")
 	return nil
 }
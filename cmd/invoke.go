@@ -12,6 +12,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// invokeDefaultTimeout is the default timeout of invoke, it can be overridden with --timeout.
+const invokeDefaultTimeout = 5 * time.Minute
+
 var (
 	invokeCmdInput     string
 	invokeCmdGroupName string
@@ -26,6 +29,8 @@ var invokeToolCmd = &cobra.Command{
 	Annotations: map[string]string{
 		"group": string(subCommandGroupBasic),
 		"order": "5",
+		// tools can take much longer than API calls to complete
+		timeoutAnnotation: invokeDefaultTimeout.String(),
 	},
 }
 
@@ -153,6 +158,7 @@ func getFileExtensionFromMimeType(mimeType string) string {
 // unpackResourceContent is the core implementation for processing resource content
 // It handles embedded resource content from MCP tool responses.
 func unpackResourceContent(cmd *cobra.Command, c map[string]any, tmpDir string, fs afero.Fs) error {
+	p := newPrinter(cmd)
 	resource, ok := c["resource"].(map[string]any)
 	if !ok {
 		return fmt.Errorf("resource content item does not have a valid 'resource' field: %v", c)
@@ -162,14 +168,14 @@ func unpackResourceContent(cmd *cobra.Command, c map[string]any, tmpDir string,
 	mimeType, _ := resource["mimeType"].(string)
 
 	// Display resource metadata
-	cmd.Printf("Resource URI: %s\n", uri)
+	p.Resultf("Resource URI: %s\n", uri)
 	if mimeType != "" {
-		cmd.Printf("MIME Type: %s\n", mimeType)
+		p.Resultf("MIME Type: %s\n", mimeType)
 	}
 
 	// Handle text resource content
 	if text, ok := resource["text"].(string); ok {
-		cmd.Printf("Text Content:\n%s\n", text)
+		p.Resultf("Text Content:\n%s\n", text)
 		return nil
 	}
 
@@ -183,6 +189,7 @@ func unpackResourceContent(cmd *cobra.Command, c map[string]any, tmpDir string,
 
 // handleBlobResource processes blob resource content by decoding base64 data and saving to file
 func handleBlobResource(cmd *cobra.Command, blobData, mimeType, tmpDir string, fs afero.Fs) error {
+	p := newPrinter(cmd)
 	// Decode base64 blob data
 	data, err := base64.StdEncoding.DecodeString(blobData)
 	if err != nil {
@@ -201,34 +208,36 @@ func handleBlobResource(cmd *cobra.Command, blobData, mimeType, tmpDir string, f
 		return fmt.Errorf("failed to write resource to disk: %w", err)
 	}
 
-	cmd.Printf("[Resource saved as %s]\n", filename)
+	p.Infof("[Resource saved as %s]\n", filename)
 	return nil
 }
 
 // unpackResourceLinkContent handles resource link content from MCP tool responses
 func unpackResourceLinkContent(cmd *cobra.Command, c map[string]any) error {
+	p := newPrinter(cmd)
 	// Extract the resource link content from the MCP tool response
 	uri, _ := c["uri"].(string)
 	name, _ := c["name"].(string)
 	description, _ := c["description"].(string)
 	mimeType, _ := c["mimeType"].(string)
 
-	cmd.Printf("Resource Link URI: %s\n", uri)
+	p.Resultf("Resource Link URI: %s\n", uri)
 	if name != "" {
-		cmd.Printf("Name: %s\n", name)
+		p.Resultf("Name: %s\n", name)
 	}
 	if description != "" {
-		cmd.Printf("Description: %s\n", description)
+		p.Resultf("Description: %s\n", description)
 	}
 	if mimeType != "" {
-		cmd.Printf("MIME Type: %s\n", mimeType)
+		p.Resultf("MIME Type: %s\n", mimeType)
 	}
 
-	cmd.Println("Resource link content handled correctly")
+	p.Infoln("Resource link content handled correctly")
 	return nil
 }
 
 func runInvokeTool(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
 	var input map[string]any
 	if err := json.Unmarshal([]byte(invokeCmdInput), &input); err != nil {
 		return fmt.Errorf("invalid input: %w", err)
@@ -238,7 +247,7 @@ func runInvokeTool(cmd *cobra.Command, args []string) error {
 
 	// If group is specified, validate that the tool is in the group
 	if invokeCmdGroupName != "" {
-		group, err := apiClient.GetToolGroup(invokeCmdGroupName)
+		group, err := apiClient.GetToolGroupContext(commandContext(cmd), invokeCmdGroupName)
 		if err != nil {
 			return fmt.Errorf("failed to get tool group '%s': %w", invokeCmdGroupName, err)
 		}
@@ -256,37 +265,37 @@ func runInvokeTool(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("tool '%s' is not available in group '%s'", toolName, invokeCmdGroupName)
 		}
 
-		cmd.Printf("Invoking tool '%s' from group '%s'\n", toolName, invokeCmdGroupName)
+		p.Infof("Invoking tool '%s' from group '%s'\n", toolName, invokeCmdGroupName)
 		if group.Description != "" {
-			cmd.Printf("Group description: %s\n", group.Description)
+			p.Infof("Group description: %s\n", group.Description)
 		}
-		cmd.Println()
+		p.Infoln()
 	}
 
-	result, err := apiClient.InvokeTool(toolName, input)
+	result, err := apiClient.InvokeToolContext(commandContext(cmd), toolName, input)
 	if err != nil {
 		return fmt.Errorf("failed to invoke tool: %w", err)
 	}
 
 	if result.IsError {
-		cmd.Println("The tool returned an error:")
+		p.Resultln("The tool returned an error:")
 		for k, v := range result.Meta {
-			cmd.Printf("%s: %v\n", k, v)
+			p.Resultf("%s: %v\n", k, v)
 		}
 	} else {
-		cmd.Println("Response from tool:")
+		p.Infoln("Response from tool:")
 	}
 
 	// result Content needs to be printed regardless of whether the tool returned an error or not
 	// because it may contain useful information
-	cmd.Println()
+	p.Resultln()
 	for _, c := range result.Content {
 		cType, ok := c["type"]
 		if !ok {
 			return fmt.Errorf("content item does not have a 'type' field: %v", c)
 		}
 
-		cmd.Printf("** Content [%s] **\n", cType)
+		p.Resultf("** Content [%s] **\n", cType)
 
 		switch cType {
 		case "text":
@@ -294,7 +303,7 @@ func runInvokeTool(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return err
 			}
-			cmd.Println(textContent)
+			p.Resultln(textContent)
 
 		case "image":
 			imgData, ext, err := getImageContent(c)
@@ -305,7 +314,7 @@ func runInvokeTool(cmd *cobra.Command, args []string) error {
 			if err := os.WriteFile(filename, imgData, 0o644); err != nil {
 				return fmt.Errorf("failed to write image to disk: %w", err)
 			}
-			cmd.Printf("[Image saved as %s]\n", filename)
+			p.Infof("[Image saved as %s]\n", filename)
 
 		case "audio":
 			audioData, ext, err := getAudioContent(c)
@@ -316,7 +325,7 @@ func runInvokeTool(cmd *cobra.Command, args []string) error {
 			if err := os.WriteFile(filename, audioData, 0o644); err != nil {
 				return fmt.Errorf("failed to write audio to disk: %w", err)
 			}
-			cmd.Printf("[Audio saved as %s]\n", filename)
+			p.Infof("[Audio saved as %s]\n", filename)
 
 		case "resource":
 			err := unpackResourceContent(cmd, c, ".", afero.NewOsFs())
@@ -332,27 +341,27 @@ func runInvokeTool(cmd *cobra.Command, args []string) error {
 
 		default:
 			// Handle unknown content types by displaying the raw content
-			cmd.Printf("[Unknown content type: %s]\n", cType)
+			p.Resultf("[Unknown content type: %s]\n", cType)
 			contentJSON, err := json.MarshalIndent(c, "", "  ")
 			if err != nil {
-				cmd.Printf("Raw content: %v\n", c)
+				p.Resultf("Raw content: %v\n", c)
 			} else {
-				cmd.Printf("Raw content:\n%s\n", string(contentJSON))
+				p.Resultf("Raw content:\n%s\n", string(contentJSON))
 			}
 		}
 
-		cmd.Println()
+		p.Resultln()
 	}
 
 	if result.StructuredContent != nil {
-		cmd.Println()
-		cmd.Println("** Structured Content **")
+		p.Resultln()
+		p.Resultln("** Structured Content **")
 		structuredJSON, err := json.MarshalIndent(result.StructuredContent, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to marshal structured content: %w", err)
 		}
-		cmd.Println(string(structuredJSON))
-		cmd.Println()
+		p.Resultln(string(structuredJSON))
+		p.Resultln()
 	}
 
 	return nil
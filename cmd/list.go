@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
 )
@@ -24,6 +27,8 @@ var (
 
 var listPromptsCmdServerName string
 
+var listServersCmdWide bool
+
 var listToolsCmd = &cobra.Command{
 	Use:   "tools",
 	Short: "List available tools",
@@ -33,7 +38,7 @@ var listToolsCmd = &cobra.Command{
 		"in mcpjungle and are part of the group.\n" +
 		"So if, for example, the group includes a tool that has been deleted, this command won't display it.\n" +
 		"To get the full list of tools included in a group, use the `get group` command instead.",
-	RunE: runListTools,
+	RunE: watchable(runListTools),
 }
 
 var listPromptsCmd = &cobra.Command{
@@ -46,7 +51,7 @@ var listPromptsCmd = &cobra.Command{
 var listServersCmd = &cobra.Command{
 	Use:   "servers",
 	Short: "List registered MCP servers",
-	RunE:  runListServers,
+	RunE:  watchable(runListServers),
 }
 
 var listMcpClientsCmd = &cobra.Command{
@@ -64,6 +69,12 @@ var listUsersCmd = &cobra.Command{
 	RunE:  runListUsers,
 }
 
+var listWebhooksCmd = &cobra.Command{
+	Use:   "webhooks",
+	Short: "List webhooks",
+	RunE:  runListWebhooks,
+}
+
 var listGroupsCmd = &cobra.Command{
 	Use:   "groups",
 	Short: "List tool groups",
@@ -91,247 +102,475 @@ func init() {
 		"Filter prompts by server name",
 	)
 
+	listServersCmd.Flags().BoolVar(
+		&listServersCmdWide,
+		"wide",
+		false,
+		"Display every column as a table, including where the credentials of the servers come from",
+	)
+
+	addColumnsFlags(listCmd)
+	addPaginationFlags(listCmd)
+	addWatchFlags(listServersCmd)
+	addWatchFlags(listToolsCmd)
+
 	listCmd.AddCommand(listToolsCmd)
 	listCmd.AddCommand(listPromptsCmd)
 	listCmd.AddCommand(listServersCmd)
 	listCmd.AddCommand(listMcpClientsCmd)
 	listCmd.AddCommand(listUsersCmd)
 	listCmd.AddCommand(listGroupsCmd)
+	listCmd.AddCommand(listWebhooksCmd)
 
 	rootCmd.AddCommand(listCmd)
 }
 
 func runListTools(cmd *cobra.Command, args []string) error {
+	if columnsHelpRequested(cmd, toolColumns) {
+		return nil
+	}
+
 	// If both server and group flags are provided, reject the request.
 	if listToolsCmdServerName != "" && listToolsCmdGroupName != "" {
-		return fmt.Errorf("using both --server and --group flags together is currently not supported")
+		return usageErrorf("using both --server and --group flags together is currently not supported")
 	}
 
-	var tools []*types.Tool
-	var err error
-	var contextInfo string
-
-	if listToolsCmdGroupName != "" {
-		// Get tools from specific group
-		group, err := apiClient.GetToolGroup(listToolsCmdGroupName)
-		if err != nil {
-			return fmt.Errorf("failed to get tool group '%s': %w", listToolsCmdGroupName, err)
-		}
+	l := listing[*types.Tool]{
+		spec:    toolColumns,
+		render:  renderTools,
+		empty:   "There are currently no tools in the registry",
+		trailer: "Run 'usage <tool name>' to see a tool's usage or 'invoke <tool name>' to call one",
+	}
 
-		// Get all tools first, then filter by group's included tools.
-		// This is necessary because a group might contain tools that do not currently exist in mcpjungle.
-		// for eg- the tool was deleted after group creation or the group includes a non-existent tool.
-		// ListTools only returns tools that actually exist in mcpjungle, so we must cross-check.
-		allTools, err := apiClient.ListTools("")
+	// the server filters the tools of a group, which only lists the ones that actually exist in mcpjungle:
+	// a group might include a tool that was deleted after the group's creation, or one that never existed.
+	filter := client.ToolFilter{Server: listToolsCmdServerName, Group: listToolsCmdGroupName}
+	l.fetch = cursorPager(func(opts client.PageOptions) (*types.Page[*types.Tool], error) {
+		pg, err := apiClient.ListToolsPage(commandContext(cmd), filter, opts)
 		if err != nil {
-			return fmt.Errorf("failed to list all tools: %w", err)
+			return nil, fmt.Errorf("failed to list tools: %w", err)
 		}
-
-		// Create a map for efficient lookup
-		includedToolsMap := make(map[string]bool)
-		for _, toolName := range group.IncludedTools {
-			includedToolsMap[toolName] = true
-		}
-
-		// Filter tools that are in the group
-		for _, tool := range allTools {
-			if includedToolsMap[tool.Name] {
-				tools = append(tools, tool)
-			}
-		}
-
-		contextInfo = fmt.Sprintf("Tools in group '%s'", listToolsCmdGroupName)
-		if group.Description != "" {
-			contextInfo += fmt.Sprintf(" (%s)", group.Description)
-		}
-	} else {
-		// no group specified, list tools from specific server (if flag is set) or all servers
-		tools, err = apiClient.ListTools(listToolsCmdServerName)
-		if err != nil {
+		return pg, nil
+	})
+	// dumps of every tool are streamed by the server, instead of being built page by page
+	l.stream = func(fn func(*types.Tool) error) error {
+		if err := apiClient.StreamTools(commandContext(cmd), filter, fn); err != nil {
 			return fmt.Errorf("failed to list tools: %w", err)
 		}
+		return nil
+	}
 
-		if listToolsCmdServerName != "" {
-			contextInfo = fmt.Sprintf("Tools from server '%s'", listToolsCmdServerName)
+	if listToolsCmdGroupName != "" {
+		// Get tools from specific group
+		group, err := apiClient.GetToolGroupContext(commandContext(cmd), listToolsCmdGroupName)
+		if err != nil {
+			return fmt.Errorf("failed to get tool group '%s': %w", listToolsCmdGroupName, err)
 		}
-	}
 
-	if len(tools) == 0 {
-		if listToolsCmdGroupName != "" {
-			cmd.Printf("There are no valid tools in group '%s'\n", listToolsCmdGroupName)
-		} else if listToolsCmdServerName != "" {
-			cmd.Printf("There are no tools from mcp server '%s'\n", listToolsCmdServerName)
-		} else {
-			cmd.Println("There are currently no tools in the registry")
+		l.empty = fmt.Sprintf("There are no valid tools in group '%s'", listToolsCmdGroupName)
+		l.header = fmt.Sprintf("Tools in group '%s'", listToolsCmdGroupName)
+		if group.Description != "" {
+			l.header += fmt.Sprintf(" (%s)", group.Description)
 		}
-		return nil
+	} else if listToolsCmdServerName != "" {
+		l.empty = fmt.Sprintf("There are no tools from mcp server '%s'", listToolsCmdServerName)
+		l.header = fmt.Sprintf("Tools from server '%s'", listToolsCmdServerName)
 	}
 
-	// Display context information if filtering is applied
-	if contextInfo != "" {
-		cmd.Printf("%s:\n\n", contextInfo)
-	}
+	return runListing(cmd, l)
+}
 
+func renderTools(cmd *cobra.Command, tools []*types.Tool, offset int) {
+	p := newPrinter(cmd)
+	st := newStyler(cmd.OutOrStdout())
 	for i, t := range tools {
 		ed := "ENABLED"
 		if !t.Enabled {
 			ed = "DISABLED"
 		}
-		cmd.Printf("%d. %s  [%s]\n", i+1, t.Name, ed)
-		cmd.Println(t.Description)
-		cmd.Println()
+		p.Resultf("%d. %s  [%s]\n", offset+i+1, st.Bold(t.Name), st.Status(ed))
+		p.Resultln(st.Dim(t.Description))
+		p.Resultln()
 	}
-
-	cmd.Println("Run 'usage <tool name>' to see a tool's usage or 'invoke <tool name>' to call one")
-
-	return nil
 }
 
 func runListServers(cmd *cobra.Command, args []string) error {
-	servers, err := apiClient.ListServers()
-	if err != nil {
-		return fmt.Errorf("failed to list servers: %w", err)
-	}
-
-	if len(servers) == 0 {
-		fmt.Println("There are no MCP servers in the registry")
+	if columnsHelpRequested(cmd, serverColumns) {
 		return nil
 	}
+	if listServersCmdWide {
+		if listColumnsFlag != "" || listSaveColumnsFlag {
+			return usageErrorf("--wide can't be used with --columns or --save-columns")
+		}
+		// --wide is a shortcut for selecting all the columns, it overrides the saved default
+		listColumnsFlag = strings.Join(serverColumns.names(), ",")
+		defer func() { listColumnsFlag = "" }()
+	}
+
+	return runListing(cmd, listing[*types.McpServer]{
+		spec: serverColumns,
+		fetch: cursorPager(func(opts client.PageOptions) (*types.Page[*types.McpServer], error) {
+			servers, err := apiClient.ListServersPage(commandContext(cmd), opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list servers: %w", err)
+			}
+			return servers, nil
+		}),
+		render: renderServers,
+		empty:  "There are no MCP servers in the registry",
+	})
+}
+
+func renderServers(cmd *cobra.Command, servers []*types.McpServer, offset int) {
+	p := newPrinter(cmd)
+	st := newStyler(cmd.OutOrStdout())
 	for i, s := range servers {
-		fmt.Printf("%d. %s\n", i+1, s.Name)
+		p.Resultf("%d. %s\n", offset+i+1, st.Bold(s.Name))
 
 		if s.Description != "" {
-			fmt.Println(s.Description)
+			p.Resultln(s.Description)
 		}
 
-		fmt.Println("Transport: " + s.Transport)
+		p.Resultln(st.Dim("Transport: ") + s.Transport)
 
 		t, _ := types.ValidateTransport(s.Transport)
-		if t == types.TransportStreamableHTTP || t == types.TransportSSE {
-			fmt.Println("URL: " + s.URL)
-		} else {
+		switch {
+		case t == types.TransportStreamableHTTP || t == types.TransportSSE:
+			p.Resultln(st.Dim("URL: ") + s.URL)
+		case t == types.TransportOpenAPI && s.OpenAPI != nil:
+			p.Resultln(st.Dim("Base URL: ") + s.OpenAPI.BaseURL)
+			p.Resultf("%s%d\n", st.Dim("Operations: "), len(s.OpenAPI.Operations))
+		case t == types.TransportWebhookTool && s.WebhookTool != nil:
+			p.Resultln(st.Dim("Endpoint: ") + s.WebhookTool.Method + " " + s.WebhookTool.URL)
+		default:
 			if len(s.Args) > 0 {
-				fmt.Println("Command: " + s.Command + " " + strings.Join(s.Args, " "))
+				p.Resultln(st.Dim("Command: ") + s.Command + " " + strings.Join(s.Args, " "))
 			} else {
-				fmt.Println("Command: " + s.Command)
+				p.Resultln(st.Dim("Command: ") + s.Command)
 			}
 
 			if len(s.Env) > 0 {
-				fmt.Printf("Environment variables: %s\n", s.Env)
+				p.Resultf("%s%s\n", st.Dim("Environment variables: "), s.Env)
+			}
+
+			if s.Container != nil {
+				p.Resultf("%s%s (%s)\n", st.Dim("Container: "), s.Container.Image, s.ContainerState)
+			}
+
+			if pkg := s.Package; pkg != nil {
+				version := pkg.Version
+				if pkg.RunsLatest() {
+					version = types.PackageVersionLatest
+				}
+				p.Resultf("%s%s %s (%s)\n", st.Dim("Package: "), pkg.Runtime, pkg.Name, version)
+			}
+		}
+
+		if s.State != "" {
+			state := string(s.State)
+			if s.LazyStart {
+				state += " (started lazily)"
 			}
+			p.Resultln(st.Dim("State: ") + state)
 		}
 
 		if i < len(servers)-1 {
-			fmt.Println()
+			p.Resultln()
 		}
 	}
-
-	return nil
 }
 
 func runListMcpClients(cmd *cobra.Command, args []string) error {
-	clients, err := apiClient.ListMcpClients()
-	if err != nil {
-		return fmt.Errorf("failed to list MCP clients: %w", err)
-	}
-
-	if len(clients) == 0 {
-		fmt.Println("There are no MCP clients in the registry")
+	if columnsHelpRequested(cmd, mcpClientColumns) {
 		return nil
 	}
+
+	return runListing(cmd, listing[types.McpClient]{
+		spec: mcpClientColumns,
+		fetch: cursorPager(func(opts client.PageOptions) (*types.Page[types.McpClient], error) {
+			clients, err := apiClient.ListMcpClientsPage(commandContext(cmd), opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list MCP clients: %w", err)
+			}
+			return clients, nil
+		}),
+		render: renderMcpClients,
+		empty:  "There are no MCP clients in the registry",
+	})
+}
+
+func renderMcpClients(cmd *cobra.Command, clients []types.McpClient, offset int) {
+	p := newPrinter(cmd)
+	st := newStyler(cmd.OutOrStdout())
 	for i, c := range clients {
-		fmt.Printf("%d. %s\n", i+1, c.Name)
+		p.Resultf("%d. %s\n", offset+i+1, st.Bold(c.Name))
 
 		if c.Description != "" {
-			fmt.Println("Description: ", c.Description)
+			p.Resultln("Description: ", c.Description)
 		}
 
 		if len(c.AllowList) > 0 {
-			fmt.Println("Allowed servers: " + strings.Join(c.AllowList, ","))
+			p.Resultln("Allowed servers: " + strings.Join(c.AllowList, ","))
 		} else {
-			fmt.Println("This client does not have access to any MCP servers.")
+			p.Resultln(st.Yellow("This client does not have access to any MCP servers."))
 		}
 
 		if i < len(clients)-1 {
-			fmt.Println()
+			p.Resultln()
 		}
 	}
-
-	return nil
 }
 
 func runListUsers(cmd *cobra.Command, args []string) error {
-	users, err := apiClient.ListUsers()
-	if err != nil {
-		return fmt.Errorf("failed to list users: %w", err)
-	}
-
-	if len(users) == 0 {
-		cmd.Println("There are no users in the registry")
+	if columnsHelpRequested(cmd, userColumns) {
 		return nil
 	}
+
+	return runListing(cmd, listing[*types.User]{
+		spec: userColumns,
+		fetch: cursorPager(func(opts client.PageOptions) (*types.Page[*types.User], error) {
+			users, err := apiClient.ListUsersPage(commandContext(cmd), opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list users: %w", err)
+			}
+			return users, nil
+		}),
+		render: renderUsers,
+		empty:  "There are no users in the registry",
+	})
+}
+
+func renderUsers(cmd *cobra.Command, users []*types.User, offset int) {
+	p := newPrinter(cmd)
+	st := newStyler(cmd.OutOrStdout())
 	for i, u := range users {
 		if u.Role == string(types.UserRoleAdmin) {
-			cmd.Printf("%d. %s  [ADMIN]\n", i+1, u.Username)
+			p.Resultf("%d. %s  [%s]\n", offset+i+1, st.Bold(u.Username), st.Yellow("ADMIN"))
 		} else {
-			cmd.Printf("%d. %s\n", i+1, u.Username)
+			p.Resultf("%d. %s\n", offset+i+1, st.Bold(u.Username))
 		}
 
 		if i < len(users)-1 {
-			cmd.Println()
+			p.Resultln()
 		}
 	}
-
-	return nil
 }
 
 func runListGroups(cmd *cobra.Command, args []string) error {
-	groups, err := apiClient.ListToolGroups()
-	if err != nil {
-		return fmt.Errorf("failed to list tool groups: %w", err)
-	}
-
-	if len(groups) == 0 {
-		cmd.Println("There are no tool groups in the registry")
+	if columnsHelpRequested(cmd, toolGroupColumns) {
 		return nil
 	}
+
+	return runListing(cmd, listing[types.ToolGroup]{
+		spec: toolGroupColumns,
+		fetch: cursorPager(func(opts client.PageOptions) (*types.Page[types.ToolGroup], error) {
+			groups, err := apiClient.ListToolGroupsPage(commandContext(cmd), opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list tool groups: %w", err)
+			}
+			return groups, nil
+		}),
+		render: renderToolGroups,
+		empty:  "There are no tool groups in the registry",
+	})
+}
+
+func renderToolGroups(cmd *cobra.Command, groups []types.ToolGroup, offset int) {
+	p := newPrinter(cmd)
+	st := newStyler(cmd.OutOrStdout())
 	for i, g := range groups {
-		cmd.Printf("%d. %s\n", i+1, g.Name)
+		p.Resultf("%d. %s\n", offset+i+1, st.Bold(g.Name))
 		if g.Description != "" {
-			cmd.Println(g.Description)
+			p.Resultln(st.Dim(g.Description))
 		}
 
 		if i < len(groups)-1 {
-			cmd.Println()
+			p.Resultln()
 		}
 	}
+}
 
-	return nil
+func runListWebhooks(cmd *cobra.Command, args []string) error {
+	if columnsHelpRequested(cmd, webhookColumns) {
+		return nil
+	}
+
+	return runListing(cmd, listing[*types.Webhook]{
+		spec: webhookColumns,
+		fetch: cursorPager(func(opts client.PageOptions) (*types.Page[*types.Webhook], error) {
+			webhooks, err := apiClient.ListWebhooksPage(commandContext(cmd), opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list webhooks: %w", err)
+			}
+			return webhooks, nil
+		}),
+		render: renderWebhooks,
+		empty:  "There are no webhooks in the registry",
+	})
 }
 
-func runListPrompts(cmd *cobra.Command, args []string) error {
-	prompts, err := apiClient.ListPrompts(listPromptsCmdServerName)
-	if err != nil {
-		return fmt.Errorf("failed to list prompts: %w", err)
+func renderWebhooks(cmd *cobra.Command, webhooks []*types.Webhook, offset int) {
+	p := newPrinter(cmd)
+	st := newStyler(cmd.OutOrStdout())
+	for i, w := range webhooks {
+		ed := "ENABLED"
+		if !w.Enabled {
+			ed = "DISABLED"
+		}
+		p.Resultf("%d. %s  [%s]\n", offset+i+1, st.Bold(w.Name), st.Status(ed))
+		p.Resultln(st.Dim("URL: ") + w.URL)
+		p.Resultln(st.Dim("Events: ") + webhookEventsLabel(w))
+		if f := webhookFormatLabel(w); f != types.WebhookFormatJSON {
+			p.Resultln(st.Dim("Format: ") + f)
+		}
+		if w.DisabledReason != "" {
+			p.Resultln(st.Yellow(w.DisabledReason))
+		}
+
+		if i < len(webhooks)-1 {
+			p.Resultln()
+		}
 	}
+}
+
+// webhookEventsLabel describes the events a webhook is subscribed to.
+func webhookEventsLabel(w *types.Webhook) string {
+	if len(w.Events) == 0 {
+		return types.AllWebhookEvents
+	}
+	return strings.Join(w.Events, ",")
+}
 
-	if len(prompts) == 0 {
-		cmd.Println("No prompts found")
+// webhookFormatLabel returns the format of the deliveries of a webhook.
+func webhookFormatLabel(w *types.Webhook) string {
+	if w.Format == "" {
+		return types.WebhookFormatJSON
+	}
+	return w.Format
+}
+
+func runListPrompts(cmd *cobra.Command, args []string) error {
+	if columnsHelpRequested(cmd, promptColumns) {
 		return nil
 	}
-	for i, p := range prompts {
+
+	return runListing(cmd, listing[model.Prompt]{
+		spec: promptColumns,
+		fetch: slicePager(func() ([]model.Prompt, error) {
+			prompts, err := apiClient.ListPromptsContext(commandContext(cmd), listPromptsCmdServerName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list prompts: %w", err)
+			}
+			return prompts, nil
+		}),
+		render:  renderPrompts,
+		empty:   "No prompts found",
+		trailer: "Run 'get prompt <prompt name>' to retrieve a prompt template",
+	})
+}
+
+func renderPrompts(cmd *cobra.Command, prompts []model.Prompt, offset int) {
+	p := newPrinter(cmd)
+	st := newStyler(cmd.OutOrStdout())
+	for i, prompt := range prompts {
 		ed := "ENABLED"
-		if !p.Enabled {
+		if !prompt.Enabled {
 			ed = "DISABLED"
 		}
-		cmd.Printf("%d. %s  [%s]\n", i+1, p.Name, ed)
-		if p.Description != "" {
-			cmd.Println(p.Description)
+		p.Resultf("%d. %s  [%s]\n", offset+i+1, st.Bold(prompt.Name), st.Status(ed))
+		if prompt.Description != "" {
+			p.Resultln(st.Dim(prompt.Description))
 		}
-		cmd.Println()
+		p.Resultln()
 	}
+}
+
+// splitCanonicalName splits a canonical tool or prompt name (<server>__<name>) into its server and name parts.
+func splitCanonicalName(canonical string) (server, name string) {
+	server, name, found := strings.Cut(canonical, "__")
+	if !found {
+		return "", canonical
+	}
+	return server, name
+}
+
+var toolColumns = tableSpec[*types.Tool]{
+	command: "list tools",
+	columns: []tableColumn[*types.Tool]{
+		{name: "name", value: func(t *types.Tool) string { return t.Name }},
+		{name: "server", value: func(t *types.Tool) string { s, _ := splitCanonicalName(t.Name); return s }},
+		{name: "tool", value: func(t *types.Tool) string { _, n := splitCanonicalName(t.Name); return n }},
+		{name: "enabled", value: func(t *types.Tool) string { return enabledLabel(t.Enabled) }},
+		{name: "description", value: func(t *types.Tool) string { return t.Description }},
+	},
+}
 
-	cmd.Println("Run 'get prompt <prompt name>' to retrieve a prompt template")
+var serverColumns = tableSpec[*types.McpServer]{
+	command: "list servers",
+	columns: []tableColumn[*types.McpServer]{
+		{name: "name", value: func(s *types.McpServer) string { return s.Name }},
+		{name: "transport", value: func(s *types.McpServer) string { return s.Transport }},
+		{name: "description", value: func(s *types.McpServer) string { return s.Description }},
+		{name: "url", value: func(s *types.McpServer) string { return s.URL }},
+		{name: "command", value: func(s *types.McpServer) string {
+			return strings.TrimSpace(s.Command + " " + strings.Join(s.Args, " "))
+		}},
+		{name: "session_mode", value: func(s *types.McpServer) string { return s.SessionMode }},
+		{name: "lazy_start", value: func(s *types.McpServer) string { return strconv.FormatBool(s.LazyStart) }},
+		{name: "state", value: func(s *types.McpServer) string { return string(s.State) }},
+		{name: "credential_source", value: func(s *types.McpServer) string { return string(s.CredentialSource) }},
+		{name: "container_state", value: func(s *types.McpServer) string { return string(s.ContainerState) }},
+	},
+}
+
+var promptColumns = tableSpec[model.Prompt]{
+	command: "list prompts",
+	columns: []tableColumn[model.Prompt]{
+		{name: "name", value: func(p model.Prompt) string { return p.Name }},
+		{name: "server", value: func(p model.Prompt) string { s, _ := splitCanonicalName(p.Name); return s }},
+		{name: "prompt", value: func(p model.Prompt) string { _, n := splitCanonicalName(p.Name); return n }},
+		{name: "enabled", value: func(p model.Prompt) string { return enabledLabel(p.Enabled) }},
+		{name: "description", value: func(p model.Prompt) string { return p.Description }},
+	},
+}
+
+var toolGroupColumns = tableSpec[types.ToolGroup]{
+	command: "list groups",
+	columns: []tableColumn[types.ToolGroup]{
+		{name: "name", value: func(g types.ToolGroup) string { return g.Name }},
+		{name: "description", value: func(g types.ToolGroup) string { return g.Description }},
+		{name: "included_tools", value: func(g types.ToolGroup) string { return strings.Join(g.IncludedTools, ",") }},
+		{name: "included_servers", value: func(g types.ToolGroup) string { return strings.Join(g.IncludedServers, ",") }},
+		{name: "excluded_tools", value: func(g types.ToolGroup) string { return strings.Join(g.ExcludedTools, ",") }},
+	},
+}
 
-	return nil
+var mcpClientColumns = tableSpec[types.McpClient]{
+	command: "list mcp-clients",
+	columns: []tableColumn[types.McpClient]{
+		{name: "name", value: func(c types.McpClient) string { return c.Name }},
+		{name: "description", value: func(c types.McpClient) string { return c.Description }},
+		{name: "allow_list", value: func(c types.McpClient) string { return strings.Join(c.AllowList, ",") }},
+	},
+}
+
+var userColumns = tableSpec[*types.User]{
+	command: "list users",
+	columns: []tableColumn[*types.User]{
+		{name: "username", value: func(u *types.User) string { return u.Username }},
+		{name: "role", value: func(u *types.User) string { return u.Role }},
+	},
+}
+
+var webhookColumns = tableSpec[*types.Webhook]{
+	command: "list webhooks",
+	columns: []tableColumn[*types.Webhook]{
+		{name: "name", value: func(w *types.Webhook) string { return w.Name }},
+		{name: "url", value: func(w *types.Webhook) string { return w.URL }},
+		{name: "format", value: webhookFormatLabel},
+		{name: "events", value: webhookEventsLabel},
+		{name: "enabled", value: func(w *types.Webhook) string { return enabledLabel(w.Enabled) }},
+		{name: "consecutive_failures", value: func(w *types.Webhook) string { return strconv.Itoa(w.ConsecutiveFailures) }},
+		{name: "disabled_reason", value: func(w *types.Webhook) string { return w.DisabledReason }},
+	},
 }
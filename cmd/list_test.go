@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
 func TestListCommandStructure(t *testing.T) {
@@ -100,7 +103,7 @@ func TestListCommandIntegration(t *testing.T) {
 
 	// Test all list subcommands are properly configured
 	subcommands := listCmd.Commands()
-	expectedSubcommands := []string{"tools", "prompts", "servers", "mcp-clients", "users", "groups"}
+	expectedSubcommands := []string{"tools", "prompts", "servers", "mcp-clients", "users", "groups", "webhooks"}
 
 	testhelpers.AssertEqual(t, len(expectedSubcommands), len(subcommands))
 
@@ -115,3 +118,33 @@ func TestListCommandIntegration(t *testing.T) {
 		testhelpers.AssertTrue(t, found, "Expected subcommand '"+expected+"' not found")
 	}
 }
+
+func TestListServersPagesOnServer(t *testing.T) {
+	withOutputFormat(t, outputFormatTable)
+	t.Setenv("HOME", t.TempDir())
+	withColumnsFlags(t, "", false)
+
+	var queries []url.Values
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			queries = append(queries, r.URL.Query())
+			writeTestJSON(w, http.StatusOK, types.Page[*types.McpServer]{
+				Items:      []*types.McpServer{{Name: "github", Transport: "streamable_http"}},
+				NextCursor: "c1",
+			})
+		},
+	})
+	cmd, stdout, stderr := newPaginationTestCmd()
+	withPaginationFlags(t, 1, 1, false)
+
+	testhelpers.AssertNoError(t, runListServers(cmd, nil))
+	testhelpers.AssertStringContains(t, stdout.String(), "github")
+	// only the page that is shown is requested, and the server doesn't count the servers
+	testhelpers.AssertEqual(t, 1, len(queries))
+	testhelpers.AssertEqual(t, "1", queries[0].Get(types.PageLimitParam))
+	testhelpers.AssertStringContains(t, stderr.String(), "Showing 1–1; use --after c1")
+
+	withAfterFlag(t, "c1")
+	testhelpers.AssertNoError(t, runListServers(cmd, nil))
+	testhelpers.AssertEqual(t, "c1", queries[1].Get(types.PageAfterParam))
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/mcpjungle/mcpjungle/cmd/config"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
 )
@@ -29,7 +28,7 @@ func init() {
 func runLogin(cmd *cobra.Command, args []string) error {
 	accessToken := args[0]
 
-	user, err := apiClient.Whoami(accessToken)
+	user, err := apiClient.WhoamiContext(commandContext(cmd), accessToken)
 	if err != nil {
 		return fmt.Errorf("failed to get user: %w", err)
 	}
@@ -37,24 +36,17 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid access token")
 	}
 
-	cmd.Println("You are now logged in as " + user.Username)
+	p := newPrinter(cmd)
+	p.Infoln("You are now logged in as " + user.Username)
 	if user.Role == string(types.UserRoleAdmin) {
-		cmd.Println("You are an administrator of MCPJungle")
+		p.Infoln("You are an administrator of MCPJungle")
 	}
 
-	cfg := &config.ClientConfig{
-		RegistryURL: apiClient.BaseURL(),
-		AccessToken: accessToken,
-	}
-	if err := config.Save(cfg); err != nil {
-		return fmt.Errorf("failed to create client configuration: %w", err)
-	}
-
-	cfgPath, err := config.AbsPath()
+	cfgPath, err := saveAccessTokenToActiveContext(activeContextName(), apiClient.BaseURL(), accessToken)
 	if err != nil {
-		return fmt.Errorf("failed to get client configuration path: %w", err)
+		return err
 	}
-	fmt.Println("Your access token has been saved to", cfgPath)
+	p.Infoln("Your access token has been saved to", cfgPath)
 
 	return nil
 }
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// mcpJSONFileName is the name of the file written by `mcpjungle export --format mcpjson`.
+const mcpJSONFileName = "mcp.json"
+
+// mcpJSONExtensionKey is the key of an entry of an mcp.json file holding the configuration of the server that the
+// format can't express, eg- its description or session mode. mcpjungle reads it back, other clients ignore it.
+const mcpJSONExtensionKey = "x-mcpjungle"
+
+// mcpJSONStandardFields are the fields of the configuration of a server that the entries of mcp.json express.
+var mcpJSONStandardFields = []string{"name", "transport", "url", "bearer_token", "command", "args", "env"}
+
+// mcpJSONDocument is an mcp.json file, eg- the .mcp.json file of a project.
+type mcpJSONDocument struct {
+	McpServers map[string]*clientConfigServer `json:"mcpServers"`
+}
+
+// newMCPJSONDocument converts the configurations of servers into an mcp.json document.
+// The servers the format can't express are left out, the reasons why are returned.
+func newMCPJSONDocument(servers []*types.RegisterServerInput) (*mcpJSONDocument, []error) {
+	doc := &mcpJSONDocument{McpServers: make(map[string]*clientConfigServer, len(servers))}
+	var skipped []error
+	for _, s := range servers {
+		entry, err := newMCPJSONEntry(s)
+		if err != nil {
+			skipped = append(skipped, fmt.Errorf("server %s is not exported: %w", s.Name, err))
+			continue
+		}
+		doc.McpServers[s.Name] = entry
+	}
+	return doc, skipped
+}
+
+// newMCPJSONEntry converts the configuration of a server into an entry of an mcp.json file:
+// url and type for HTTP servers, command, args and env for stdio servers.
+// The rest of the configuration goes under mcpJSONExtensionKey.
+func newMCPJSONEntry(s *types.RegisterServerInput) (*clientConfigServer, error) {
+	entry := &clientConfigServer{}
+	switch types.McpServerTransport(s.Transport) {
+	case types.TransportStdio:
+		if s.Container != nil {
+			return nil, errors.New("it runs in a container, which mcp.json can't express")
+		}
+		entry.Command, entry.Args, entry.Env = s.Command, s.Args, s.Env
+		if s.Package != nil {
+			entry.Command, entry.Args = s.Package.CommandLine(s.Args)
+		}
+	case types.TransportStreamableHTTP, types.TransportSSE:
+		entry.Type, entry.URL = "http", s.URL
+		if s.Transport == string(types.TransportSSE) {
+			entry.Type = "sse"
+		}
+		if s.BearerToken != "" {
+			entry.Headers = map[string]string{"Authorization": "Bearer " + s.BearerToken}
+		}
+	default:
+		return nil, fmt.Errorf("transport %s has no equivalent in mcp.json", s.Transport)
+	}
+
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var ext map[string]any
+	if err := json.Unmarshal(raw, &ext); err != nil {
+		return nil, err
+	}
+	for _, f := range mcpJSONStandardFields {
+		// the args of a package server follow its package, they are read back along with it
+		if f == "args" && s.Package != nil {
+			continue
+		}
+		delete(ext, f)
+	}
+	for k, v := range ext {
+		if v == nil || v == "" {
+			delete(ext, k)
+		}
+	}
+	if len(ext) > 0 {
+		if entry.Extension, err = json.Marshal(ext); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+// applyMCPJSONExtension sets the fields of the mcpJSONExtensionKey of an entry of an mcp.json file on the input
+// converted from the entry. The name and transport of the server are the ones of the entry.
+func applyMCPJSONExtension(input *types.RegisterServerInput, ext json.RawMessage) error {
+	var pkg struct {
+		Package *types.PackageConfig `json:"package"`
+	}
+	if err := json.Unmarshal(ext, &pkg); err != nil {
+		return err
+	}
+	if pkg.Package != nil {
+		// the command line of the entry is the one of the package, the args of the server are in the extension
+		input.Command, input.Args = "", nil
+	}
+	name, transport := input.Name, input.Transport
+	if err := json.Unmarshal(ext, input); err != nil {
+		return err
+	}
+	input.Name, input.Transport = name, transport
+	return nil
+}
+
+// isMCPJSONDocument reports whether a configuration is an mcp.json file rather than the configuration of servers.
+func isMCPJSONDocument(data []byte) bool {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return false
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return false
+	}
+	_, ok := doc["mcpServers"]
+	return ok
+}
+
+// mcpJSONServerInputs converts the servers of an mcp.json file into their configurations.
+// Unlike an import, it fails if any of them can't be registered in mcpjungle.
+func mcpJSONServerInputs(data []byte) ([]types.RegisterServerInput, error) {
+	servers, err := parseClientConfig(data, importFormatMCPJSON)
+	if err != nil {
+		return nil, err
+	}
+	var inputs []types.RegisterServerInput
+	var errs []error
+	for _, s := range servers {
+		if s.Action == importActionSkip {
+			errs = append(errs, fmt.Errorf("server %s: %s", s.Name, s.Reason))
+			continue
+		}
+		inputs = append(inputs, *s.input)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(inputs) == 0 {
+		return nil, errNoConfigProvided
+	}
+	return inputs, nil
+}
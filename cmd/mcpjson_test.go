@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func testMCPJSONServers() []*types.RegisterServerInput {
+	return []*types.RegisterServerInput{
+		{
+			Name:        "context7",
+			Transport:   string(types.TransportStreamableHTTP),
+			Description: "Docs of libraries",
+			URL:         "https://mcp.context7.com/mcp",
+			BearerToken: "ctx7-token",
+			SessionMode: "stateful",
+			HTTPPool:    &types.HTTPPoolConfig{MaxIdleConnsPerHost: 4},
+		},
+		{
+			Name:      "filesystem",
+			Transport: string(types.TransportStdio),
+			Command:   "npx",
+			Args:      []string{"-y", "@modelcontextprotocol/server-filesystem", "/tmp"},
+			Env:       map[string]string{"LOG_LEVEL": "debug"},
+			LazyStart: true,
+		},
+		{Name: "linear", Transport: string(types.TransportSSE), URL: "https://mcp.linear.app/sse"},
+		{
+			Name:      "time",
+			Transport: string(types.TransportStdio),
+			Package:   &types.PackageConfig{Runtime: types.PackageRuntimeUvx, Name: "mcp-server-time", Version: "latest"},
+			Args:      []string{"--local-timezone=UTC"},
+		},
+		{
+			Name:      "petstore",
+			Transport: string(types.TransportOpenAPI),
+			OpenAPI:   &types.OpenAPIConfig{BaseURL: "https://petstore.example.com"},
+		},
+		{
+			Name:      "sandboxed",
+			Transport: string(types.TransportStdio),
+			Container: &types.ContainerConfig{Image: "ghcr.io/example/mcp"},
+		},
+	}
+}
+
+func TestMCPJSONRoundTrip(t *testing.T) {
+	servers := testMCPJSONServers()
+	doc, skipped := newMCPJSONDocument(servers)
+	testhelpers.AssertEqual(t, 2, len(skipped))
+	testhelpers.AssertStringContains(t, skipped[0].Error(), "server petstore is not exported")
+	testhelpers.AssertStringContains(t, skipped[1].Error(), "server sandboxed is not exported: it runs in a container")
+	testhelpers.AssertEqual(t, 4, len(doc.McpServers))
+
+	data, err := marshalConfig(doc)
+	testhelpers.AssertNoError(t, err)
+	inputs, err := mcpJSONServerInputs(data)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 4, len(inputs))
+	for i, s := range servers[:4] {
+		want, _ := json.Marshal(s)
+		got, _ := json.Marshal(inputs[i])
+		testhelpers.AssertEqual(t, string(want), string(got))
+	}
+}
+
+func TestMCPJSONEntry(t *testing.T) {
+	doc, _ := newMCPJSONDocument(testMCPJSONServers())
+
+	// the standard fields are enough for other clients to connect to the servers
+	remote := doc.McpServers["context7"]
+	testhelpers.AssertEqual(t, "http", remote.Type)
+	testhelpers.AssertEqual(t, "https://mcp.context7.com/mcp", remote.URL)
+	testhelpers.AssertEqual(t, "Bearer ctx7-token", remote.Headers["Authorization"])
+	testhelpers.AssertEqual(
+		t,
+		`{"description":"Docs of libraries","http_pool":{"max_idle_conns_per_host":4},"session_mode":"stateful"}`,
+		string(remote.Extension),
+	)
+
+	pkg := doc.McpServers["time"]
+	testhelpers.AssertEqual(t, "uvx", pkg.Command)
+	testhelpers.AssertEqual(t, "mcp-server-time@latest --local-timezone=UTC", strings.Join(pkg.Args, " "))
+
+	// the entries of plain stdio servers have nothing else to keep
+	testhelpers.AssertEqual(t, "sse", doc.McpServers["linear"].Type)
+	testhelpers.AssertEqual(t, 0, len(doc.McpServers["linear"].Extension))
+}
+
+func TestReadMcpServerConfigsFromMCPJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	testhelpers.AssertNoError(t, os.WriteFile(path, []byte(`{
+  "mcpServers": {
+    "linear": {"type": "sse", "url": "https://mcp.linear.app/sse"},
+    "time": {"command": "uvx", "args": ["mcp-server-time"], "x-mcpjungle": {"description": "Current time"}}
+  }
+}`), 0o600))
+	inputs, err := readMcpServerConfigs(&cobra.Command{}, path)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 2, len(inputs))
+	testhelpers.AssertEqual(t, string(types.TransportSSE), inputs[0].Transport)
+	testhelpers.AssertEqual(t, "Current time", inputs[1].Description)
+
+	// unlike an import, the entries mcpjungle can't serve fail the registration
+	testhelpers.AssertNoError(t, os.WriteFile(path, []byte(testMCPJSONConfig), 0o600))
+	_, err = readMcpServerConfigs(&cobra.Command{}, path)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "server remote: transport ws is not supported")
+}
+
+func TestExportMCPJSON(t *testing.T) {
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, testMCPJSONServers())
+		},
+	})
+	origDir, origFormat := exportCmdTargetDir, exportCmdFormat
+	t.Cleanup(func() { exportCmdTargetDir, exportCmdFormat = origDir, origFormat })
+	exportCmdTargetDir, exportCmdFormat = filepath.Join(t.TempDir(), "export"), exportFormatMCPJSON
+
+	var out, stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetErr(&stderr)
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+	testhelpers.AssertStringContains(t, out.String(), "Exported 4 MCP servers")
+	testhelpers.AssertStringContains(t, stderr.String(), "server petstore is not exported")
+
+	data, err := os.ReadFile(filepath.Join(exportCmdTargetDir, mcpJSONFileName))
+	testhelpers.AssertNoError(t, err)
+	servers, err := parseClientConfig(data, importFormatMCPJSON)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 4, len(servers))
+	testhelpers.AssertEqual(t, "uvx mcp-server-time@latest --local-timezone=UTC", servers[3].target())
+
+	exportCmdFormat = "toml"
+	err = runExport(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "unsupported format 'toml'")
+}
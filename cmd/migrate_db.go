@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+var migrateDbCmd = &cobra.Command{
+	Use:   "migrate-db",
+	Short: "Copy the registry to another database",
+	Long: "Copy the whole registry from a database to another one, eg- from the embedded SQLite database\n" +
+		"to Postgres when outgrowing it. All the rows are copied as they are stored, with their IDs and credentials,\n" +
+		"table after table in batches of rows, and the number of rows of every table is compared once copied.\n\n" +
+		"The databases are given as the DATABASE_URL of the server: a sqlite:// URL for a SQLite file,\n" +
+		"a mysql:// URL for MySQL or MariaDB, and a Postgres DSN otherwise.\n" +
+		"The target database must not have any row, its schema is created if needed.\n\n" +
+		"The server must be stopped during the copy. A SQLite database is refused while a server is running on it,\n" +
+		"nothing prevents copying a Postgres or MySQL database while a server changes it.\n\n" +
+		"Start the server with DATABASE_URL set to the target database once it is copied.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "23",
+	},
+	Example: "  mcpjungle migrate-db --from sqlite://mcpjungle.db --to postgres://localhost/mcpjungle --dry-run\n" +
+		"  mcpjungle migrate-db --from sqlite:///var/lib/mcpjungle/mcpjungle.db --to postgres://user:pass@db/mcpjungle",
+	Args: cobra.NoArgs,
+	RunE: runMigrateDb,
+}
+
+var (
+	migrateDbCmdFrom   string
+	migrateDbCmdTo     string
+	migrateDbCmdDryRun bool
+)
+
+func init() {
+	migrateDbCmd.Flags().StringVar(&migrateDbCmdFrom, "from", "", "URL of the database to copy the registry from")
+	_ = migrateDbCmd.MarkFlagRequired("from")
+	migrateDbCmd.Flags().StringVar(&migrateDbCmdTo, "to", "", "URL of the empty database to copy the registry to")
+	_ = migrateDbCmd.MarkFlagRequired("to")
+	migrateDbCmd.Flags().BoolVar(
+		&migrateDbCmdDryRun,
+		"dry-run",
+		false,
+		"Only check both databases and report the rows that would be copied",
+	)
+
+	rootCmd.AddCommand(migrateDbCmd)
+}
+
+func runMigrateDb(cmd *cobra.Command, args []string) error {
+	if migrateDbCmdFrom == migrateDbCmdTo {
+		return usageErrorf("--from and --to must be different databases")
+	}
+	p := newPrinter(cmd)
+
+	// the SQLite files are locked like the server does, so that the copy never runs alongside a server
+	if path, ok := db.SQLitePath(migrateDbCmdFrom); ok {
+		// opening a file that doesn't exist would create an empty database
+		if _, err := os.Stat(path); err != nil {
+			return usageErrorf("the source database %s doesn't exist", path)
+		}
+		release, err := lockDatabase(path)
+		if err != nil {
+			return err
+		}
+		defer release()
+	} else {
+		p.Warnf("Make sure no mcpjungle server is running on the source database until the copy is done.\n")
+	}
+	if path, ok := db.SQLitePath(migrateDbCmdTo); ok {
+		release, err := lockDatabase(path)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	src, err := openMigrateDb("source", migrateDbCmdFrom)
+	if err != nil {
+		return err
+	}
+	defer closeMigrateDb(src)
+	dst, err := openMigrateDb("target", migrateDbCmdTo)
+	if err != nil {
+		return err
+	}
+	defer closeMigrateDb(dst)
+
+	ctx := commandContext(cmd)
+	plan, err := migrations.PlanCopy(ctx, src, dst)
+	if err != nil {
+		return migrateDbError(err)
+	}
+	if migrateDbCmdDryRun {
+		if isStructuredOutput() {
+			return printOutput(cmd, plan)
+		}
+		var rows int64
+		for _, t := range plan.Tables {
+			rows += t.SourceRows
+		}
+		p.Resultf("Would copy %d rows of %d tables\n", rows, len(plan.Tables))
+		for _, t := range plan.Tables {
+			p.Infof("  %s: %d\n", t.Table, t.SourceRows)
+		}
+		if plan.CreateSchema {
+			p.Infoln()
+			p.Infoln("The schema of the target database would be created first.")
+		}
+		return nil
+	}
+
+	pr := p.Progress("Copying the registry")
+	plan, err = migrations.CopyDatabase(ctx, src, dst, func(table string, copied, total int64) {
+		pr.Step("Copying %s %d/%d rows", table, copied, total)
+	})
+	pr.Stop()
+	if err != nil {
+		return migrateDbError(err)
+	}
+
+	if isStructuredOutput() {
+		return printOutput(cmd, plan)
+	}
+	var rows int64
+	for _, t := range plan.Tables {
+		rows += t.TargetRows
+	}
+	p.Resultf("Copied %d rows of %d tables\n", rows, len(plan.Tables))
+	for _, t := range plan.Tables {
+		p.Infof("  %s: %d/%d\n", t.Table, t.TargetRows, t.SourceRows)
+	}
+	p.Infoln()
+	p.Infoln("Verified that every table of the target database has as many rows as the source.")
+	p.Infoln("Start the server with DATABASE_URL set to the target database to serve the copied registry.")
+	return nil
+}
+
+// lockDatabase locks a SQLite database file for the time of the copy and returns the function releasing it.
+func lockDatabase(path string) (func(), error) {
+	lock, err := db.LockSQLite(path)
+	if errors.Is(err, db.ErrDatabaseInUse) {
+		return nil, fmt.Errorf("%w, stop the mcpjungle server using it before migrating its database", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return func() { _ = lock.Release() }, nil
+}
+
+func openMigrateDb(role, dsn string) (*gorm.DB, error) {
+	conn, err := db.NewDBConnection(dsn)
+	if err != nil {
+		// the DSN is not part of the error, it may hold a password
+		return nil, fmt.Errorf("failed to open the %s database: %w", role, err)
+	}
+	return conn, nil
+}
+
+func closeMigrateDb(conn *gorm.DB) {
+	if sqlDB, err := conn.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+}
+
+// migrateDbError reports the errors of the checks of the databases as usage errors, they can't be retried as is.
+func migrateDbError(err error) error {
+	if errors.Is(err, migrations.ErrSourceNotMigrated) || errors.Is(err, migrations.ErrTargetNotEmpty) {
+		return usageErrorf("%w", err)
+	}
+	return fmt.Errorf("failed to copy the registry: %w", err)
+}
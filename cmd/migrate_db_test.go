@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/spf13/cobra"
+)
+
+func TestMigrateDbCommandStructure(t *testing.T) {
+	testhelpers.TestCommandAnnotations(t, migrateDbCmd.Annotations, []testhelpers.CommandAnnotationTest{
+		{Key: "group", Expected: string(subCommandGroupAdvanced)},
+		{Key: "order", Expected: "23"},
+	})
+	testhelpers.AssertNotNil(t, migrateDbCmd.Flags().Lookup("from"))
+	testhelpers.AssertNotNil(t, migrateDbCmd.Flags().Lookup("to"))
+	testhelpers.AssertNotNil(t, migrateDbCmd.Flags().Lookup("dry-run"))
+}
+
+func TestRunMigrateDb(t *testing.T) {
+	dir := t.TempDir()
+	srcPath := filepath.Join(dir, "source.db")
+	src, err := db.NewDBConnection("sqlite://" + srcPath)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, migrations.Migrate(src))
+	testhelpers.AssertNoError(t, src.Create(&model.ServerConfig{Mode: model.ModeDev, Initialized: true}).Error)
+	sqlDB, _ := src.DB()
+	testhelpers.AssertNoError(t, sqlDB.Close())
+
+	origFrom, origTo, origDryRun := migrateDbCmdFrom, migrateDbCmdTo, migrateDbCmdDryRun
+	t.Cleanup(func() { migrateDbCmdFrom, migrateDbCmdTo, migrateDbCmdDryRun = origFrom, origTo, origDryRun })
+	migrateDbCmdFrom = "sqlite://" + srcPath
+	migrateDbCmdTo = "sqlite://" + filepath.Join(dir, "target.db")
+	newCmd := func() (*cobra.Command, *bytes.Buffer) {
+		cmd := &cobra.Command{}
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(out)
+		return cmd, out
+	}
+
+	migrateDbCmdDryRun = true
+	cmd, out := newCmd()
+	testhelpers.AssertNoError(t, runMigrateDb(cmd, nil))
+	testhelpers.AssertStringContains(t, out.String(), "Would copy 1 rows")
+	testhelpers.AssertStringContains(t, out.String(), "server_configs: 1")
+	testhelpers.AssertStringContains(t, out.String(), "would be created")
+
+	migrateDbCmdDryRun = false
+	cmd, out = newCmd()
+	testhelpers.AssertNoError(t, runMigrateDb(cmd, nil))
+	testhelpers.AssertStringContains(t, out.String(), "Copied 1 rows")
+	testhelpers.AssertStringContains(t, out.String(), "server_configs: 1/1")
+	testhelpers.AssertStringContains(t, out.String(), "Verified")
+
+	// the target is no longer empty
+	cmd, _ = newCmd()
+	err = runMigrateDb(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+	testhelpers.AssertStringContains(t, err.Error(), "not empty")
+
+	// a database a server is running on is refused
+	lock, err := db.LockSQLite(srcPath)
+	testhelpers.AssertNoError(t, err)
+	defer func() { _ = lock.Release() }()
+	migrateDbCmdTo = "sqlite://" + filepath.Join(dir, "other.db")
+	cmd, _ = newCmd()
+	err = runMigrateDb(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "stop the mcpjungle server")
+
+	migrateDbCmdFrom = "sqlite://" + filepath.Join(dir, "missing.db")
+	cmd, _ = newCmd()
+	err = runMigrateDb(cmd, nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "doesn't exist")
+}
@@ -0,0 +1,446 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// openAPISpecMaxBytes caps the size of the OpenAPI specs downloaded by the CLI.
+const openAPISpecMaxBytes = 20 << 20
+
+// openAPIBodyArgument is the tool argument holding the request body of an operation, the adapter reads it under
+// the same name.
+const openAPIBodyArgument = "body"
+
+// openAPIMethods are the methods of the operations of a path item, in the order they are converted.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// invalidToolNameChars are the characters of operationIds that can't be used in tool names.
+// They are replaced by an underscore, and consecutive underscores are collapsed, like in server names.
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9-]+`)
+
+// openAPIFilter selects the operations of a spec served as tools. Empty lists select all operations.
+type openAPIFilter struct {
+	// Tags selects the operations with at least one of the tags.
+	Tags []string
+
+	// Operations selects the operations whose operationId matches at least one of the glob patterns, eg- "list*".
+	Operations []string
+}
+
+// skippedOperation is an operation of a spec that can't be served as a tool.
+type skippedOperation struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	OperationID string `json:"operation_id"`
+	Reason      string `json:"reason"`
+}
+
+// openAPISpec is an OpenAPI 3 spec, decoded from JSON or YAML.
+type openAPISpec struct {
+	doc map[string]any
+
+	// location is where the spec was read from, the relative server URLs of the spec are resolved against it.
+	location string
+}
+
+// loadOpenAPISpec reads an OpenAPI spec from a file or an http(s) URL.
+func loadOpenAPISpec(ctx context.Context, location string) (*openAPISpec, error) {
+	var data []byte
+	if u, err := url.Parse(location); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download the spec from %s: %w", location, err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download the spec from %s: %w", location, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to download the spec from %s: %s", location, resp.Status)
+		}
+		data, err = io.ReadAll(io.LimitReader(resp.Body, openAPISpecMaxBytes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to download the spec from %s: %w", location, err)
+		}
+	} else {
+		data, err = os.ReadFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the spec: %w", err)
+		}
+	}
+	return parseOpenAPISpec(data, location)
+}
+
+// parseOpenAPISpec decodes a JSON or YAML OpenAPI spec. Only OpenAPI 3 is supported.
+func parseOpenAPISpec(data []byte, location string) (*openAPISpec, error) {
+	// JSON is a subset of YAML, so both are decoded the same way
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse the spec %s: %w", location, err)
+	}
+	version, _ := doc["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		if _, ok := doc["swagger"]; ok {
+			return nil, fmt.Errorf("%s is a Swagger 2.0 spec, only OpenAPI 3 specs are supported", location)
+		}
+		return nil, fmt.Errorf("%s is not an OpenAPI 3 spec", location)
+	}
+	return &openAPISpec{doc: doc, location: location}, nil
+}
+
+// title returns the title of the API described by the spec.
+func (s *openAPISpec) title() string {
+	info, _ := s.doc["info"].(map[string]any)
+	title, _ := info["title"].(string)
+	return title
+}
+
+// securitySchemeHeader returns the header of the first API key security scheme of the spec sent in a header,
+// or "" if there is none.
+func (s *openAPISpec) securitySchemeHeader() string {
+	components, _ := s.doc["components"].(map[string]any)
+	schemes, _ := components["securitySchemes"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(schemes)) {
+		scheme, _ := s.resolve(schemes[name], nil).(map[string]any)
+		if scheme["type"] == "apiKey" && scheme["in"] == "header" {
+			header, _ := scheme["name"].(string)
+			return header
+		}
+	}
+	return ""
+}
+
+// baseURL returns the URL of the first server of the spec, resolved against the location of the spec.
+func (s *openAPISpec) baseURL() (string, error) {
+	servers, _ := s.doc["servers"].([]any)
+	if len(servers) == 0 {
+		return "", fmt.Errorf("the spec doesn't declare any server, set the base URL of the API with --base-url")
+	}
+	server, _ := servers[0].(map[string]any)
+	raw, _ := server["url"].(string)
+	// server variables are replaced by their default values
+	variables, _ := server["variables"].(map[string]any)
+	for name, v := range variables {
+		variable, _ := v.(map[string]any)
+		if def, ok := variable["default"].(string); ok {
+			raw = strings.ReplaceAll(raw, "{"+name+"}", def)
+		}
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("the spec declares an invalid server URL %q: %w", raw, err)
+	}
+	if !u.IsAbs() {
+		location, err := url.Parse(s.location)
+		if err != nil || (location.Scheme != "http" && location.Scheme != "https") {
+			return "", fmt.Errorf("the spec declares a relative server URL %q, set the base URL of the API with --base-url", raw)
+		}
+		u = location.ResolveReference(u)
+	}
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// operations converts the operations of the spec selected by filter into the operations served as tools.
+// The operations that select unsupported features of OpenAPI are skipped, with the reason why.
+func (s *openAPISpec) operations(filter openAPIFilter) ([]types.OpenAPIOperation, []skippedOperation) {
+	var selected []types.OpenAPIOperation
+	var skipped []skippedOperation
+	tools := make(map[string]bool)
+
+	paths, _ := s.doc["paths"].(map[string]any)
+	for _, p := range slices.Sorted(maps.Keys(paths)) {
+		item, _ := s.resolve(paths[p], nil).(map[string]any)
+		for _, method := range openAPIMethods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			id, _ := op["operationId"].(string)
+			if id == "" {
+				id = method + "_" + p
+			}
+			if !filter.selects(id, op) {
+				continue
+			}
+
+			converted, reason := s.convertOperation(strings.ToUpper(method), p, id, item, op)
+			if reason == "" && tools[converted.Tool] {
+				reason = fmt.Sprintf("another operation is already served as tool %s", converted.Tool)
+			}
+			if reason != "" {
+				skipped = append(skipped, skippedOperation{
+					Method: strings.ToUpper(method), Path: p, OperationID: id, Reason: reason,
+				})
+				continue
+			}
+			tools[converted.Tool] = true
+			selected = append(selected, *converted)
+		}
+	}
+	return selected, skipped
+}
+
+// selects reports whether the filter selects an operation.
+func (f openAPIFilter) selects(id string, op map[string]any) bool {
+	if len(f.Tags) > 0 {
+		tags, _ := op["tags"].([]any)
+		if !slices.ContainsFunc(tags, func(t any) bool { return slices.Contains(f.Tags, fmt.Sprint(t)) }) {
+			return false
+		}
+	}
+	if len(f.Operations) > 0 {
+		return slices.ContainsFunc(f.Operations, func(pattern string) bool {
+			ok, _ := path.Match(pattern, id)
+			return ok
+		})
+	}
+	return true
+}
+
+// convertOperation converts an operation of the spec, or returns why it can't be served as a tool.
+func (s *openAPISpec) convertOperation(
+	method, p, id string, item, op map[string]any,
+) (*types.OpenAPIOperation, string) {
+	converted := &types.OpenAPIOperation{
+		Tool:        openAPIToolName(id),
+		Description: openAPIDescription(op),
+		Method:      method,
+		Path:        p,
+	}
+	if converted.Tool == "" {
+		return nil, "its operationId can't be converted into a tool name"
+	}
+	properties := make(map[string]any)
+	var required []string
+
+	for _, param := range s.parameters(item, op) {
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		isRequired, _ := param["required"].(bool)
+		switch {
+		case in == "cookie" && isRequired:
+			return nil, fmt.Sprintf("cookie parameter %s is not supported", name)
+		case in == "cookie":
+			// optional cookies are simply never sent
+			continue
+		case name == openAPIBodyArgument:
+			return nil, fmt.Sprintf("parameter %s conflicts with the argument holding the request body", name)
+		case properties[name] != nil:
+			return nil, fmt.Sprintf("parameter %s is defined in more than one location", name)
+		}
+
+		schema, _ := s.resolve(param["schema"], nil).(map[string]any)
+		if schema == nil {
+			schema = map[string]any{"type": "string"}
+		}
+		if d, ok := param["description"].(string); ok && d != "" {
+			schema = maps.Clone(schema)
+			schema["description"] = d
+		}
+		properties[name] = schema
+		if isRequired {
+			required = append(required, name)
+		}
+		converted.Parameters = append(converted.Parameters, types.OpenAPIParameter{Name: name, In: in, Required: isRequired})
+	}
+
+	if raw, ok := op["requestBody"]; ok {
+		body, _ := s.resolve(raw, nil).(map[string]any)
+		isRequired, _ := body["required"].(bool)
+		content, _ := body["content"].(map[string]any)
+		contentType := jsonContentType(content)
+		switch {
+		case contentType != "":
+			media, _ := content[contentType].(map[string]any)
+			schema, _ := s.resolve(media["schema"], nil).(map[string]any)
+			if schema == nil {
+				schema = map[string]any{}
+			}
+			if d, ok := body["description"].(string); ok && d != "" {
+				schema = maps.Clone(schema)
+				schema["description"] = d
+			}
+			properties[openAPIBodyArgument] = schema
+			if isRequired {
+				required = append(required, openAPIBodyArgument)
+			}
+			converted.BodyContentType = contentType
+		case isRequired:
+			return nil, fmt.Sprintf(
+				"request body content type %s is not supported", strings.Join(slices.Sorted(maps.Keys(content)), ", "),
+			)
+		}
+	}
+
+	if unsupported := s.unsupportedResponseTypes(op); len(unsupported) > 0 {
+		return nil, fmt.Sprintf("response content type %s is not supported", strings.Join(unsupported, ", "))
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Sprintf("failed to convert the schema of its arguments: %v", err)
+	}
+	converted.InputSchema = data
+	return converted, ""
+}
+
+// parameters returns the parameters of an operation, including those of the path item it belongs to.
+// Parameters defined by the operation override those of the path item with the same name and location.
+func (s *openAPISpec) parameters(item, op map[string]any) []map[string]any {
+	var params []map[string]any
+	index := make(map[string]int)
+	for _, list := range []any{item["parameters"], op["parameters"]} {
+		entries, _ := list.([]any)
+		for _, entry := range entries {
+			param, ok := s.resolve(entry, nil).(map[string]any)
+			if !ok {
+				continue
+			}
+			key := fmt.Sprint(param["in"], "/", param["name"])
+			if i, ok := index[key]; ok {
+				params[i] = param
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// unsupportedResponseTypes returns the content types of the successful responses of an operation if none of them
+// can be returned by a tool, ie, if they are neither JSON nor text.
+func (s *openAPISpec) unsupportedResponseTypes(op map[string]any) []string {
+	responses, _ := op["responses"].(map[string]any)
+	var contentTypes []string
+	for code, raw := range responses {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		response, _ := s.resolve(raw, nil).(map[string]any)
+		content, _ := response["content"].(map[string]any)
+		for contentType := range content {
+			mediaType, _, err := mime.ParseMediaType(contentType)
+			if err == nil && (isJSONMediaType(mediaType) || strings.HasPrefix(mediaType, "text/") || mediaType == "*/*") {
+				return nil
+			}
+			contentTypes = append(contentTypes, contentType)
+		}
+	}
+	slices.Sort(contentTypes)
+	return slices.Compact(contentTypes)
+}
+
+// resolve replaces the local references ($ref) of a value of the spec by what they point to, recursively.
+// A reference pointing to one of its parents is replaced by an empty schema, to break the cycle.
+// References to other documents are not supported and are also replaced by an empty schema.
+func (s *openAPISpec) resolve(v any, seen []string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			if !strings.HasPrefix(ref, "#/") || slices.Contains(seen, ref) {
+				return map[string]any{}
+			}
+			target, ok := s.pointer(ref)
+			if !ok {
+				return map[string]any{}
+			}
+			return s.resolve(target, append(seen, ref))
+		}
+		resolved := make(map[string]any, len(v))
+		for k, child := range v {
+			resolved[k] = s.resolve(child, seen)
+		}
+		return resolved
+	case []any:
+		resolved := make([]any, len(v))
+		for i, child := range v {
+			resolved[i] = s.resolve(child, seen)
+		}
+		return resolved
+	default:
+		return v
+	}
+}
+
+// pointer returns the value a local JSON pointer, eg- #/components/schemas/Pet, points to in the spec.
+func (s *openAPISpec) pointer(ref string) (any, bool) {
+	var cur any = s.doc
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if t, err := url.PathUnescape(token); err == nil {
+			token = t
+		}
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[token]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// jsonContentType returns the JSON content type of a request body, preferring application/json,
+// or "" if the body can't be sent as JSON.
+func jsonContentType(content map[string]any) string {
+	if _, ok := content["application/json"]; ok {
+		return "application/json"
+	}
+	for _, contentType := range slices.Sorted(maps.Keys(content)) {
+		if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && isJSONMediaType(mediaType) {
+			return contentType
+		}
+	}
+	return ""
+}
+
+// isJSONMediaType reports whether a media type is JSON, eg- application/json or application/problem+json.
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// openAPIToolName converts an operationId into a tool name.
+func openAPIToolName(id string) string {
+	name := invalidToolNameChars.ReplaceAllString(id, "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return strings.Trim(name, "_")
+}
+
+// openAPIDescription returns the description of the tool serving an operation, from its summary and description.
+func openAPIDescription(op map[string]any) string {
+	summary, _ := op["summary"].(string)
+	description, _ := op["description"].(string)
+	switch {
+	case summary == "":
+		return description
+	case description == "":
+		return summary
+	default:
+		return summary + "\n\n" + description
+	}
+}
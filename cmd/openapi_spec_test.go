@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+const testOpenAPISpec = `
+openapi: 3.0.3
+info:
+  title: Petstore
+servers:
+  - url: /api/{version}
+    variables:
+      version:
+        default: v1
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+  parameters:
+    PetID:
+      name: id
+      in: path
+      required: true
+      schema:
+        type: string
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        parent:
+          $ref: '#/components/schemas/Pet'
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List the pets
+      tags: [pets]
+      parameters:
+        - name: limit
+          in: query
+          description: Maximum number of pets to return
+          schema:
+            type: integer
+        - name: session
+          in: cookie
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      operationId: createPet
+      tags: [pets]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '201':
+          description: Created
+  /pets/{id}:
+    parameters:
+      - $ref: '#/components/parameters/PetID'
+    get:
+      operationId: getPet
+      tags: [pets]
+      responses:
+        '200':
+          content:
+            application/json: {}
+    put:
+      operationId: uploadPetPhoto
+      tags: [photos]
+      requestBody:
+        required: true
+        content:
+          image/png: {}
+      responses:
+        '204':
+          description: Uploaded
+  /pets/{id}/photo:
+    get:
+      tags: [photos]
+      parameters:
+        - $ref: '#/components/parameters/PetID'
+      responses:
+        '200':
+          content:
+            image/png: {}
+`
+
+func TestOpenAPISpecOperations(t *testing.T) {
+	spec, err := parseOpenAPISpec([]byte(testOpenAPISpec), "https://petstore.example.com/openapi.yaml")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "Petstore", spec.title())
+	testhelpers.AssertEqual(t, "X-API-Key", spec.securitySchemeHeader())
+
+	baseURL, err := spec.baseURL()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "https://petstore.example.com/api/v1", baseURL)
+
+	operations, skipped := spec.operations(openAPIFilter{})
+	testhelpers.AssertEqual(t, 3, len(operations))
+	testhelpers.AssertEqual(t, "listPets", operations[0].Tool)
+	testhelpers.AssertEqual(t, "createPet", operations[1].Tool)
+	testhelpers.AssertEqual(t, "getPet", operations[2].Tool)
+
+	// optional cookies are not arguments of the tool
+	list := operations[0]
+	testhelpers.AssertEqual(t, "List the pets", list.Description)
+	testhelpers.AssertEqual(t, 1, len(list.Parameters))
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+		Required   []string                  `json:"required"`
+	}
+	testhelpers.AssertNoError(t, json.Unmarshal(list.InputSchema, &schema))
+	testhelpers.AssertEqual(t, "Maximum number of pets to return", schema.Properties["limit"]["description"])
+
+	// the request body is the "body" argument, references are resolved and cycles broken
+	create := operations[1]
+	testhelpers.AssertEqual(t, "application/json", create.BodyContentType)
+	testhelpers.AssertNoError(t, json.Unmarshal(create.InputSchema, &schema))
+	testhelpers.AssertEqual(t, 1, len(schema.Required))
+	testhelpers.AssertEqual(t, "body", schema.Required[0])
+	testhelpers.AssertEqual(t, "object", schema.Properties["body"]["type"])
+
+	// the parameters of the path item apply to its operations
+	get := operations[2]
+	testhelpers.AssertEqual(t, 1, len(get.Parameters))
+	testhelpers.AssertEqual(t, "id", get.Parameters[0].Name)
+	testhelpers.AssertTrue(t, get.Parameters[0].Required, "path parameters are required")
+
+	testhelpers.AssertEqual(t, 2, len(skipped))
+	testhelpers.AssertEqual(t, "uploadPetPhoto", skipped[0].OperationID)
+	testhelpers.AssertEqual(t, "request body content type image/png is not supported", skipped[0].Reason)
+	testhelpers.AssertEqual(t, "get_/pets/{id}/photo", skipped[1].OperationID)
+	testhelpers.AssertEqual(t, "response content type image/png is not supported", skipped[1].Reason)
+}
+
+func TestOpenAPIFilter(t *testing.T) {
+	spec, err := parseOpenAPISpec([]byte(testOpenAPISpec), "petstore.yaml")
+	testhelpers.AssertNoError(t, err)
+
+	operations, skipped := spec.operations(openAPIFilter{Tags: []string{"pets"}, Operations: []string{"*Pet"}})
+	testhelpers.AssertEqual(t, 2, len(operations))
+	testhelpers.AssertEqual(t, "createPet", operations[0].Tool)
+	testhelpers.AssertEqual(t, "getPet", operations[1].Tool)
+	testhelpers.AssertEqual(t, 0, len(skipped))
+
+	_, err = spec.baseURL()
+	testhelpers.AssertTrue(t, err != nil, "a relative server URL can't be resolved against a file")
+}
+
+func TestParseOpenAPISpecVersion(t *testing.T) {
+	_, err := parseOpenAPISpec([]byte(`{"swagger": "2.0", "paths": {}}`), "petstore.json")
+	testhelpers.AssertTrue(t, err != nil, "expected an error for a Swagger 2.0 spec")
+	testhelpers.AssertStringContains(t, err.Error(), "only OpenAPI 3 specs are supported")
+}
+
+func TestOpenAPIToolName(t *testing.T) {
+	for id, want := range map[string]string{
+		"listPets":              "listPets",
+		"pets.list":             "pets_list",
+		"get_/pets/{id}/photo":  "get_pets_id_photo",
+		"delete__pet":           "delete_pet",
+		"Pets-Get (deprecated)": "Pets-Get_deprecated",
+	} {
+		testhelpers.AssertEqual(t, want, openAPIToolName(id))
+	}
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// outputFormatTable is the default, human-friendly output format.
+	outputFormatTable = "table"
+	// outputFormatJSON prints machine-readable JSON.
+	outputFormatJSON = "json"
+	// outputFormatName prints only the name of every item, one per line.
+	outputFormatName = "name"
+	// outputFormatTemplatePrefix precedes a Go template that is executed for every item, eg- template='{{.Name}} {{.URL}}'
+	outputFormatTemplatePrefix = "template="
+)
+
+// outputFormatFlag is set by the global --output flag.
+var outputFormatFlag string
+
+// validateOutputFormat returns an error if the given output format is not supported by the CLI.
+func validateOutputFormat(format string) error {
+	switch {
+	case format == outputFormatTable, format == outputFormatJSON, format == outputFormatName:
+		return nil
+	case strings.HasPrefix(format, outputFormatTemplatePrefix):
+		if _, err := parseOutputTemplate(strings.TrimPrefix(format, outputFormatTemplatePrefix)); err != nil {
+			return usageErrorf("invalid output template: %w", err)
+		}
+		return nil
+	default:
+		return usageErrorf(
+			"unsupported output format '%s', must be one of: %s, %s, %s, %s<go template>",
+			format, outputFormatTable, outputFormatJSON, outputFormatName, outputFormatTemplatePrefix,
+		)
+	}
+}
+
+// outputFormat returns the output format requested by the user, either via flag, env var or their active context.
+func outputFormat() string {
+	if activeSettings == nil || activeSettings.Output == "" {
+		return outputFormatTable
+	}
+	return activeSettings.Output
+}
+
+// isJSONOutput returns true if the user asked for JSON output.
+func isJSONOutput() bool {
+	return outputFormat() == outputFormatJSON
+}
+
+// isStructuredOutput returns true if the user asked for an output format meant for scripts (json, name or template)
+// rather than the human-friendly one.
+// Commands must print the same objects in all structured formats, using printOutput or newOutputWriter.
+func isStructuredOutput() bool {
+	return outputFormat() != outputFormatTable
+}
+
+// printJSON writes the given value to the command's output as indented JSON.
+func printJSON(cmd *cobra.Command, v any) error {
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode output as JSON: %w", err)
+	}
+	return nil
+}
+
+// printOutput writes v to the command's output in the structured format requested by the user.
+// In the name and template formats, each element of a slice is printed on its own line.
+func printOutput(cmd *cobra.Command, v any) error {
+	if isJSONOutput() {
+		return printJSON(cmd, v)
+	}
+
+	w, err := newOutputWriter(cmd)
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			if err := w.Write(rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	} else if err := w.Write(v); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// outputWriter writes a list of items one at a time in a structured output format.
+type outputWriter interface {
+	// Write prints an item.
+	Write(v any) error
+	// Close terminates the output. It must be called exactly once, even if no items were written.
+	Close() error
+}
+
+// newOutputWriter returns a writer that prints items in the structured format requested by the user.
+// In JSON format, items are written as a single JSON array.
+func newOutputWriter(cmd *cobra.Command) (outputWriter, error) {
+	format := outputFormat()
+	switch {
+	case format == outputFormatName:
+		return &lineWriter{w: cmd.OutOrStdout(), format: func(w io.Writer, v any) error {
+			name, err := itemName(v)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(w, name)
+			return err
+		}}, nil
+	case strings.HasPrefix(format, outputFormatTemplatePrefix):
+		tmpl, err := parseOutputTemplate(strings.TrimPrefix(format, outputFormatTemplatePrefix))
+		if err != nil {
+			return nil, usageErrorf("invalid output template: %w", err)
+		}
+		return &lineWriter{w: cmd.OutOrStdout(), format: func(w io.Writer, v any) error {
+			if err := tmpl.Execute(w, v); err != nil {
+				return err
+			}
+			_, err := io.WriteString(w, "\n")
+			return err
+		}}, nil
+	default:
+		return newJSONArrayWriter(cmd.OutOrStdout()), nil
+	}
+}
+
+// lineWriter prints every item on its own line.
+type lineWriter struct {
+	w      io.Writer
+	format func(w io.Writer, v any) error
+	count  int
+}
+
+func (l *lineWriter) Write(v any) error {
+	l.count++
+	if err := l.format(l.w, v); err != nil {
+		return fmt.Errorf("failed to print item %d: %w", l.count, err)
+	}
+	return nil
+}
+
+func (l *lineWriter) Close() error { return nil }
+
+// parseOutputTemplate parses the template of the template output format.
+// Besides Go's builtin functions, templates can use `json` to encode a value as JSON and `join` to join a list of strings.
+func parseOutputTemplate(text string) (*template.Template, error) {
+	return template.New("output").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"json": func(v any) (string, error) {
+				b, err := json.Marshal(v)
+				return string(b), err
+			},
+			"join": strings.Join,
+		}).
+		Parse(text)
+}
+
+// itemName returns the name of an item printed in the name output format, ie, its "name" or "username" JSON field.
+// Deriving it from the JSON encoding guarantees that it is the same name that JSON output shows.
+func itemName(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode output: %w", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err == nil {
+		for _, key := range []string{"name", "username"} {
+			if name, ok := fields[key].(string); ok {
+				return name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("the '%s' output format is not supported by this command, its output has no name", outputFormatName)
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func newOutputTestCmd() (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	stdout := &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	return cmd, stdout
+}
+
+var outputTestServers = []*types.McpServer{
+	{Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/"},
+	{Name: "filesystem", Transport: "stdio", Command: "npx", Args: []string{"-y", "@modelcontextprotocol/server-filesystem"}},
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, format := range []string{"table", "json", "name", "template={{.Name}} {{.URL}}"} {
+		testhelpers.AssertNoError(t, validateOutputFormat(format))
+	}
+
+	err := validateOutputFormat("yaml")
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+
+	err = validateOutputFormat("template={{.Name")
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "invalid output template")
+}
+
+func TestPrintOutputTemplate(t *testing.T) {
+	withOutputFormat(t, "template={{.Name}} {{.Transport}} {{join .Args \",\"}}")
+	cmd, stdout := newOutputTestCmd()
+
+	testhelpers.AssertNoError(t, printOutput(cmd, outputTestServers))
+	testhelpers.AssertEqual(
+		t,
+		"github streamable_http \nfilesystem stdio -y,@modelcontextprotocol/server-filesystem\n",
+		stdout.String(),
+	)
+}
+
+func TestPrintOutputTemplateOnMaps(t *testing.T) {
+	withOutputFormat(t, "template={{.access_token}}")
+	cmd, stdout := newOutputTestCmd()
+
+	testhelpers.AssertNoError(t, printOutput(cmd, map[string]any{"name": "cursor", "access_token": "tok-123"}))
+	testhelpers.AssertEqual(t, "tok-123\n", stdout.String())
+}
+
+func TestPrintOutputTemplateError(t *testing.T) {
+	withOutputFormat(t, "template={{.Name}} {{.Vendor}}")
+	cmd, _ := newOutputTestCmd()
+
+	err := printOutput(cmd, outputTestServers)
+	testhelpers.AssertError(t, err)
+	// the error tells which item and which field failed
+	testhelpers.AssertStringContains(t, err.Error(), "item 1")
+	testhelpers.AssertStringContains(t, err.Error(), "<.Vendor>")
+}
+
+func TestPrintOutputName(t *testing.T) {
+	withOutputFormat(t, outputFormatName)
+
+	t.Run("names of a list", func(t *testing.T) {
+		cmd, stdout := newOutputTestCmd()
+		testhelpers.AssertNoError(t, printOutput(cmd, outputTestServers))
+		testhelpers.AssertEqual(t, "github\nfilesystem\n", stdout.String())
+	})
+
+	t.Run("users are named by their username", func(t *testing.T) {
+		cmd, stdout := newOutputTestCmd()
+		testhelpers.AssertNoError(t, printOutput(cmd, &types.User{Username: "alice", Role: "user"}))
+		testhelpers.AssertEqual(t, "alice\n", stdout.String())
+	})
+
+	t.Run("output without a name", func(t *testing.T) {
+		cmd, _ := newOutputTestCmd()
+		testhelpers.AssertError(t, printOutput(cmd, []int{1, 2}))
+	})
+}
+
+func TestStructuredFormatsShareObjects(t *testing.T) {
+	group := &types.GetToolGroupResponse{
+		ToolGroup:          &types.ToolGroup{Name: "claude", IncludedTools: []string{"github__git_commit"}},
+		ToolGroupEndpoints: &types.ToolGroupEndpoints{StreamableHTTPEndpoint: "http://127.0.0.1:8080/v0/groups/claude/mcp"},
+	}
+
+	withOutputFormat(t, outputFormatJSON)
+	cmd, stdout := newOutputTestCmd()
+	testhelpers.AssertNoError(t, printOutput(cmd, group))
+	var decoded map[string]any
+	testhelpers.AssertNoError(t, json.Unmarshal(stdout.Bytes(), &decoded))
+	testhelpers.AssertEqual(t, "claude", decoded["name"])
+
+	withOutputFormat(t, "template={{.Name}} {{.StreamableHTTPEndpoint}}")
+	cmd, stdout = newOutputTestCmd()
+	testhelpers.AssertNoError(t, printOutput(cmd, group))
+	testhelpers.AssertEqual(t, "claude http://127.0.0.1:8080/v0/groups/claude/mcp\n", stdout.String())
+}
+
+func TestRunListingAllName(t *testing.T) {
+	withOutputFormat(t, outputFormatName)
+	withPaginationFlags(t, 1, 1, true)
+	cmd, stdout, stderr := newPaginationTestCmd()
+
+	l := listing[*types.McpServer]{
+		spec:   serverColumns,
+		fetch:  slicePager(func() ([]*types.McpServer, error) { return outputTestServers, nil }),
+		render: renderServers,
+		empty:  "There are no MCP servers in the registry",
+	}
+	testhelpers.AssertNoError(t, runListing(cmd, l))
+	testhelpers.AssertEqual(t, "github\nfilesystem\n", stdout.String())
+	testhelpers.AssertEqual(t, "", stderr.String())
+}
@@ -0,0 +1,413 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/internal/api"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// defaultTablePageSize is the number of items shown per page in table mode when --limit is not set.
+const defaultTablePageSize = 100
+
+// Pagination flags shared by all list commands
+var (
+	listLimitFlag int
+	listPageFlag  int
+	listAllFlag   bool
+	// listAfterFlag is the cursor to start the list after, printed by the footer of the previous page
+	listAfterFlag string
+)
+
+// unknownTotal is the Total of the pages of the lists the server doesn't count.
+const unknownTotal = -1
+
+// page is a single page of results returned by a pageFetcher.
+type page[T any] struct {
+	Items []T
+	// Total is the total number of items across all pages, unknownTotal if the server doesn't count them.
+	Total int
+	// More reports whether there are items after this page.
+	More bool
+	// Next is the cursor to pass to --after to list the items after this page, if there are any and the server
+	// paginates the list with cursors.
+	Next string
+}
+
+// pageFetcher fetches the page of items starting at offset, containing at most limit items.
+// A limit of 0 means no limit, ie, all items starting at offset.
+type pageFetcher[T any] func(offset, limit int) (page[T], error)
+
+// listing describes how a list command fetches and displays its items.
+type listing[T any] struct {
+	spec  tableSpec[T]
+	fetch pageFetcher[T]
+	// stream, if set, calls fn for every item in a single request. It is used instead of fetch to print all items
+	// in structured output formats, unless the server doesn't support it (ie, it fails with client.ErrNotFound).
+	stream func(fn func(T) error) error
+	// render prints items in the command's regular (non-table) output format.
+	// offset is the position of the first item in the full list, used for numbering.
+	render func(cmd *cobra.Command, items []T, offset int)
+	// empty is printed when there are no items at all.
+	empty string
+	// header is printed before the items in regular output format, if not empty.
+	header string
+	// trailer is printed after the items in regular output format, if not empty.
+	trailer string
+}
+
+// addPaginationFlags adds the --limit, --page and --all flags to a list command.
+func addPaginationFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().IntVar(
+		&listLimitFlag,
+		"limit",
+		0,
+		fmt.Sprintf("Maximum number of items to show per page (default %d in table mode, unlimited in structured output formats)", defaultTablePageSize),
+	)
+	cmd.PersistentFlags().IntVar(&listPageFlag, "page", 1, "Page of results to show, starting at 1")
+	cmd.PersistentFlags().BoolVar(&listAllFlag, "all", false, "Fetch and show all pages")
+	cmd.PersistentFlags().StringVar(
+		&listAfterFlag, "after", "", "Show the items after this cursor, printed at the end of the previous page",
+	)
+}
+
+// pageSize returns the effective number of items per page, 0 means unlimited.
+func pageSize() int {
+	if listLimitFlag > 0 {
+		return listLimitFlag
+	}
+	if isStructuredOutput() && !listAllFlag {
+		// scripts consuming structured output expect the full result unless they explicitly ask for a page
+		return 0
+	}
+	return defaultTablePageSize
+}
+
+func validatePaginationFlags(cmd *cobra.Command) error {
+	if listLimitFlag < 0 {
+		return usageErrorf("--limit must not be negative")
+	}
+	if listPageFlag < 1 {
+		return usageErrorf("--page must be 1 or greater")
+	}
+	if listAllFlag && cmd.Flags().Changed("page") {
+		return usageErrorf("--all and --page cannot be used together")
+	}
+	if listAfterFlag != "" && cmd.Flags().Changed("page") {
+		// the pages after a cursor are numbered from it, which would be misleading
+		return usageErrorf("--after and --page cannot be used together")
+	}
+	return nil
+}
+
+// runListing fetches and displays the items of a list command, taking care of
+// pagination, structured output and column selection.
+func runListing[T any](cmd *cobra.Command, l listing[T]) error {
+	if err := validatePaginationFlags(cmd); err != nil {
+		return err
+	}
+
+	if listAllFlag {
+		return runListingAll(cmd, l)
+	}
+
+	size := pageSize()
+	offset := (listPageFlag - 1) * size
+	p, err := l.fetch(offset, size)
+	if err != nil {
+		return err
+	}
+
+	if isStructuredOutput() {
+		return printOutput(cmd, nonNil(p.Items))
+	}
+	if err := displayItems(cmd, l, p.Items, offset, p.Total); err != nil {
+		return err
+	}
+	printPageFooter(cmd, offset, p)
+	return nil
+}
+
+// runListingAll fetches every page.
+// In structured output formats, items are streamed as pages arrive (as a single JSON array in JSON mode)
+// so that the full result is never held in memory twice, or in a single request if the listing can stream.
+func runListingAll[T any](cmd *cobra.Command, l listing[T]) error {
+	size := pageSize()
+
+	if isStructuredOutput() {
+		jw, err := newOutputWriter(cmd)
+		if err != nil {
+			return err
+		}
+		if l.stream != nil {
+			written := 0
+			err := l.stream(func(item T) error {
+				written++
+				return jw.Write(item)
+			})
+			if err == nil {
+				return jw.Close()
+			}
+			if written > 0 || !errors.Is(err, client.ErrNotFound) {
+				return err
+			}
+			// the server predates streaming, fetch the pages instead
+		}
+		for offset := 0; ; offset += size {
+			p, err := l.fetch(offset, size)
+			if err != nil {
+				return err
+			}
+			for _, item := range p.Items {
+				if err := jw.Write(item); err != nil {
+					return err
+				}
+			}
+			if len(p.Items) == 0 || !p.More {
+				break
+			}
+		}
+		return jw.Close()
+	}
+
+	var all []T
+	total := 0
+	for offset := 0; ; offset += size {
+		p, err := l.fetch(offset, size)
+		if err != nil {
+			return err
+		}
+		all = append(all, p.Items...)
+		total = p.Total
+		if len(p.Items) == 0 || !p.More {
+			break
+		}
+	}
+	if total == unknownTotal {
+		total = len(all)
+	}
+	return displayItems(cmd, l, all, 0, total)
+}
+
+func displayItems[T any](cmd *cobra.Command, l listing[T], items []T, offset, total int) error {
+	p := newPrinter(cmd)
+	if rendered, err := renderColumns(cmd, l.spec, items); rendered || err != nil {
+		return err
+	}
+	if total == 0 || (total == unknownTotal && offset == 0 && len(items) == 0) {
+		p.Infoln(l.empty)
+		return nil
+	}
+	if l.header != "" {
+		p.Infof("%s:\n\n", l.header)
+	}
+	l.render(cmd, items, offset)
+	if l.trailer != "" {
+		p.Infoln(l.trailer)
+	}
+	return nil
+}
+
+// printPageFooter tells the user which part of the results they are looking at, if there are more pages.
+// The lists the server doesn't count have no total, and the pages after --after are numbered from its cursor.
+func printPageFooter[T any](cmd *cobra.Command, offset int, pg page[T]) {
+	p := newPrinter(cmd)
+	count, total := len(pg.Items), pg.Total
+	if offset == 0 && !pg.More && listAfterFlag == "" {
+		// everything fits on one page, nothing to say
+		return
+	}
+	if count == 0 {
+		switch {
+		case listAfterFlag != "":
+			p.Infoln("\nNo items after the cursor")
+		case total == unknownTotal:
+			p.Infof("\nNo items on page %d\n", listPageFlag)
+		default:
+			p.Infof("\nNo items on page %d, there are %s items in total\n", listPageFlag, formatCount(total))
+		}
+		return
+	}
+	footer := fmt.Sprintf("\nShowing %s–%s", formatCount(offset+1), formatCount(offset+count))
+	if total != unknownTotal && listAfterFlag == "" {
+		footer += " of " + formatCount(total)
+	}
+	if pg.More {
+		switch {
+		case pg.Next != "" && (listAfterFlag != "" || total == unknownTotal):
+			footer += fmt.Sprintf("; use --after %s to see more or --all to see everything", pg.Next)
+		case listAfterFlag != "":
+			// the page doesn't end on a cursor, and page numbers don't follow one
+			footer += "; use --all to see everything"
+		default:
+			footer += fmt.Sprintf("; use --page %d to see more or --all to see everything", listPageFlag+1)
+		}
+	}
+	p.Infoln(footer)
+}
+
+// formatCount formats a count with thousands separators, eg- 8243 -> "8,243"
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// slicePager returns a pageFetcher that serves pages from a list that is loaded in full on first use,
+// for the lists the API doesn't paginate, eg- the prompts. Use cursorPager for the others.
+func slicePager[T any](load func() ([]T, error)) pageFetcher[T] {
+	var (
+		items  []T
+		loaded bool
+	)
+	return func(offset, limit int) (page[T], error) {
+		if listAfterFlag != "" {
+			return page[T]{}, usageErrorf("--after is not supported by this list, use --page instead")
+		}
+		if !loaded {
+			var err error
+			if items, err = load(); err != nil {
+				return page[T]{}, err
+			}
+			loaded = true
+		}
+		pageItems := paginate(items, offset, limit)
+		return page[T]{Items: pageItems, Total: len(items), More: offset+len(pageItems) < len(items)}, nil
+	}
+}
+
+// cursorPager returns a pageFetcher over a list the API paginates with cursors: only the pages that are shown are
+// fetched from the server. The pageFetcher keeps its position in the list, so that the pages of --all, which are
+// read in order, take a request each. A page further down the list, eg- --page 5, is reached by walking the pages
+// before it, without keeping their items. The list starts after the cursor of --after, if it is set.
+func cursorPager[T any](fetch func(opts client.PageOptions) (*types.Page[T], error)) pageFetcher[T] {
+	var (
+		pos    int // position of buf[0] in the list
+		buf    []T // items fetched but not served yet
+		cursor string
+		done   bool
+		total  = unknownTotal
+	)
+	reset := func() {
+		pos, buf, cursor, done = 0, nil, listAfterFlag, false
+	}
+	reset()
+	return func(offset, limit int) (page[T], error) {
+		if offset < pos {
+			reset()
+		}
+		size := limit
+		if size <= 0 || size > api.MaxPageLimit {
+			// the largest pages make for the fewest round trips
+			size = api.MaxPageLimit
+		}
+		var items []T
+		for limit <= 0 || len(items) < limit {
+			if len(buf) == 0 {
+				if done {
+					break
+				}
+				// the pages before offset are only walked, so they are requested at the size of the shown ones
+				pg, err := fetch(client.PageOptions{Limit: size, After: cursor})
+				if err != nil {
+					return page[T]{}, err
+				}
+				buf, cursor, done = pg.Items, pg.NextCursor, pg.NextCursor == ""
+				if pg.Total != nil && listAfterFlag == "" {
+					total = int(*pg.Total)
+				}
+				if len(buf) == 0 {
+					done = true
+					break
+				}
+			}
+			n := len(buf)
+			if pos < offset {
+				n = min(n, offset-pos)
+			} else {
+				if limit > 0 {
+					n = min(n, limit-len(items))
+				}
+				items = append(items, buf[:n]...)
+			}
+			buf, pos = buf[n:], pos+n
+		}
+		pg := page[T]{Items: items, Total: total, More: len(buf) > 0 || !done}
+		if pg.More && len(buf) == 0 {
+			// the page ends where the last response did, its cursor points right after it
+			pg.Next = cursor
+		}
+		return pg, nil
+	}
+}
+
+// paginate returns the items in [offset, offset+limit), a limit of 0 means no limit.
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return items[offset:end]
+}
+
+// nonNil makes sure an empty list is encoded as [] instead of null in JSON output.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// jsonArrayWriter writes a JSON array one element at a time.
+type jsonArrayWriter struct {
+	w     io.Writer
+	count int
+}
+
+func newJSONArrayWriter(w io.Writer) *jsonArrayWriter {
+	return &jsonArrayWriter{w: w}
+}
+
+// Write appends an element to the array.
+func (j *jsonArrayWriter) Write(v any) error {
+	data, err := json.MarshalIndent(v, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode output as JSON: %w", err)
+	}
+	sep := ",\n  "
+	if j.count == 0 {
+		sep = "[\n  "
+	}
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	if _, err := j.w.Write(data); err != nil {
+		return err
+	}
+	j.count++
+	return nil
+}
+
+// Close terminates the array. It must be called exactly once, even if no elements were written.
+func (j *jsonArrayWriter) Close() error {
+	end := "\n]\n"
+	if j.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
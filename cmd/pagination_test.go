@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/internal/api"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func withPaginationFlags(t *testing.T, limit, pageNum int, all bool) {
+	t.Helper()
+	origLimit, origPage, origAll := listLimitFlag, listPageFlag, listAllFlag
+	listLimitFlag, listPageFlag, listAllFlag = limit, pageNum, all
+	t.Cleanup(func() { listLimitFlag, listPageFlag, listAllFlag = origLimit, origPage, origAll })
+}
+
+func withAfterFlag(t *testing.T, after string) {
+	t.Helper()
+	orig := listAfterFlag
+	listAfterFlag = after
+	t.Cleanup(func() { listAfterFlag = orig })
+}
+
+func withOutputFormat(t *testing.T, format string) {
+	t.Helper()
+	orig := activeSettings
+	activeSettings = &cliSettings{Output: format}
+	t.Cleanup(func() { activeSettings = orig })
+}
+
+func newPaginationTestCmd() (*cobra.Command, *bytes.Buffer, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	addPaginationFlags(cmd)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	return cmd, stdout, stderr
+}
+
+// numberListing returns a listing over the numbers 1..n along with a counter of how many pages were fetched.
+func numberListing(n int) (listing[int], *int) {
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i + 1
+	}
+	fetches := 0
+	pager := slicePager(func() ([]int, error) { return items, nil })
+	return listing[int]{
+		spec: tableSpec[int]{command: "list numbers"},
+		fetch: func(offset, limit int) (page[int], error) {
+			fetches++
+			return pager(offset, limit)
+		},
+		render: func(cmd *cobra.Command, items []int, offset int) {
+			for i, v := range items {
+				cmd.Printf("%d=%d\n", offset+i+1, v)
+			}
+		},
+		empty: "nothing",
+	}, &fetches
+}
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	testhelpers.AssertEqual(t, "[1 2]", fmt.Sprint(paginate(items, 0, 2)))
+	testhelpers.AssertEqual(t, "[5]", fmt.Sprint(paginate(items, 4, 2)))
+	testhelpers.AssertEqual(t, "[]", fmt.Sprint(paginate(items, 5, 2)))
+	testhelpers.AssertEqual(t, "[2 3 4 5]", fmt.Sprint(paginate(items, 1, 0)))
+}
+
+func TestFormatCount(t *testing.T) {
+	testhelpers.AssertEqual(t, "0", formatCount(0))
+	testhelpers.AssertEqual(t, "999", formatCount(999))
+	testhelpers.AssertEqual(t, "8,243", formatCount(8243))
+	testhelpers.AssertEqual(t, "1,000,000", formatCount(1000000))
+}
+
+func TestRunListingTablePages(t *testing.T) {
+	withOutputFormat(t, outputFormatTable)
+	t.Setenv("HOME", t.TempDir())
+	withColumnsFlags(t, "", false)
+
+	t.Run("default page size with footer", func(t *testing.T) {
+		cmd, stdout, stderr := newPaginationTestCmd()
+		withPaginationFlags(t, 0, 1, false)
+		l, _ := numberListing(250)
+
+		testhelpers.AssertNoError(t, runListing(cmd, l))
+		out := stdout.String() + stderr.String()
+		testhelpers.AssertStringContains(t, out, "100=100")
+		testhelpers.AssertStringNotContains(t, out, "101=101")
+		testhelpers.AssertStringContains(t, stderr.String(), "Showing 1–100 of 250; use --page 2")
+	})
+
+	t.Run("explicit page", func(t *testing.T) {
+		cmd, stdout, stderr := newPaginationTestCmd()
+		withPaginationFlags(t, 10, 3, false)
+		l, _ := numberListing(25)
+
+		testhelpers.AssertNoError(t, runListing(cmd, l))
+		out := stdout.String() + stderr.String()
+		testhelpers.AssertStringContains(t, out, "21=21")
+		testhelpers.AssertStringContains(t, out, "25=25")
+		testhelpers.AssertStringNotContains(t, out, "20=20")
+		testhelpers.AssertStringContains(t, stderr.String(), "Showing 21–25 of 25")
+		testhelpers.AssertStringNotContains(t, stderr.String(), "--page 4")
+	})
+
+	t.Run("single page has no footer", func(t *testing.T) {
+		cmd, _, stderr := newPaginationTestCmd()
+		withPaginationFlags(t, 0, 1, false)
+		l, _ := numberListing(3)
+
+		testhelpers.AssertNoError(t, runListing(cmd, l))
+		testhelpers.AssertStringNotContains(t, stderr.String(), "Showing")
+	})
+
+	t.Run("invalid flags", func(t *testing.T) {
+		cmd, _, _ := newPaginationTestCmd()
+		withPaginationFlags(t, -1, 1, false)
+		l, _ := numberListing(3)
+		testhelpers.AssertError(t, runListing(cmd, l))
+	})
+}
+
+func TestRunListingJSON(t *testing.T) {
+	withOutputFormat(t, outputFormatJSON)
+
+	t.Run("returns everything by default", func(t *testing.T) {
+		cmd, stdout, _ := newPaginationTestCmd()
+		withPaginationFlags(t, 0, 1, false)
+		l, _ := numberListing(150)
+
+		testhelpers.AssertNoError(t, runListing(cmd, l))
+		var got []int
+		testhelpers.AssertNoError(t, json.Unmarshal(stdout.Bytes(), &got))
+		testhelpers.AssertEqual(t, 150, len(got))
+	})
+
+	t.Run("--all streams pages into one array", func(t *testing.T) {
+		cmd, stdout, _ := newPaginationTestCmd()
+		withPaginationFlags(t, 40, 1, true)
+		l, fetches := numberListing(100)
+
+		testhelpers.AssertNoError(t, runListing(cmd, l))
+		var got []int
+		testhelpers.AssertNoError(t, json.Unmarshal(stdout.Bytes(), &got))
+		testhelpers.AssertEqual(t, 100, len(got))
+		testhelpers.AssertEqual(t, 100, got[99])
+		testhelpers.AssertEqual(t, 3, *fetches)
+	})
+
+	t.Run("--all with no items is an empty array", func(t *testing.T) {
+		cmd, stdout, _ := newPaginationTestCmd()
+		withPaginationFlags(t, 0, 1, true)
+		l, _ := numberListing(0)
+
+		testhelpers.AssertNoError(t, runListing(cmd, l))
+		testhelpers.AssertEqual(t, "[]", strings.TrimSpace(stdout.String()))
+	})
+
+	t.Run("--all streams the items of listings that can", func(t *testing.T) {
+		cmd, stdout, _ := newPaginationTestCmd()
+		withPaginationFlags(t, 40, 1, true)
+		l, fetches := numberListing(100)
+		l.stream = func(fn func(int) error) error {
+			for i := 1; i <= 100; i++ {
+				if err := fn(i); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		testhelpers.AssertNoError(t, runListing(cmd, l))
+		var got []int
+		testhelpers.AssertNoError(t, json.Unmarshal(stdout.Bytes(), &got))
+		testhelpers.AssertEqual(t, 100, len(got))
+		testhelpers.AssertEqual(t, 0, *fetches)
+	})
+
+	t.Run("--all fetches pages from servers that can't stream", func(t *testing.T) {
+		cmd, stdout, _ := newPaginationTestCmd()
+		withPaginationFlags(t, 40, 1, true)
+		l, fetches := numberListing(100)
+		l.stream = func(fn func(int) error) error {
+			return fmt.Errorf("failed to list: %w", &client.APIError{StatusCode: 404, Code: types.ErrorCodeNotFound})
+		}
+
+		testhelpers.AssertNoError(t, runListing(cmd, l))
+		var got []int
+		testhelpers.AssertNoError(t, json.Unmarshal(stdout.Bytes(), &got))
+		testhelpers.AssertEqual(t, 100, len(got))
+		testhelpers.AssertEqual(t, 3, *fetches)
+	})
+
+	t.Run("stream errors after the first item are returned", func(t *testing.T) {
+		cmd, _, _ := newPaginationTestCmd()
+		withPaginationFlags(t, 0, 1, true)
+		l, fetches := numberListing(2)
+		l.stream = func(fn func(int) error) error {
+			_ = fn(1)
+			return &client.APIError{StatusCode: 404, Code: types.ErrorCodeNotFound}
+		}
+		testhelpers.AssertError(t, runListing(cmd, l))
+		testhelpers.AssertEqual(t, 0, *fetches)
+	})
+
+	t.Run("fetch errors are returned", func(t *testing.T) {
+		cmd, _, _ := newPaginationTestCmd()
+		withPaginationFlags(t, 0, 1, true)
+		l, _ := numberListing(1)
+		l.fetch = func(int, int) (page[int], error) { return page[int]{}, errors.New("boom") }
+		testhelpers.AssertError(t, runListing(cmd, l))
+	})
+}
+
+// cursorNumbers returns the fetch of a cursor-paginated API over the numbers 1..n, along with the options of the
+// requests it received. The cursor is the last number of the page. It counts the numbers if counted is set.
+func cursorNumbers(n int, counted bool) (func(client.PageOptions) (*types.Page[int], error), *[]client.PageOptions) {
+	var requests []client.PageOptions
+	return func(opts client.PageOptions) (*types.Page[int], error) {
+		requests = append(requests, opts)
+		start := 0
+		if opts.After != "" {
+			var err error
+			if start, err = strconv.Atoi(opts.After); err != nil {
+				return nil, err
+			}
+		}
+		limit := opts.Limit
+		if limit == 0 {
+			limit = api.DefaultPageLimit
+		}
+		p := &types.Page[int]{Items: []int{}}
+		for i := start + 1; i <= min(n, start+limit); i++ {
+			p.Items = append(p.Items, i)
+		}
+		if start+limit < n {
+			p.NextCursor = strconv.Itoa(start + limit)
+		}
+		if counted {
+			total := int64(n)
+			p.Total = &total
+		}
+		return p, nil
+	}, &requests
+}
+
+func TestCursorPager(t *testing.T) {
+	withAfterFlag(t, "")
+
+	t.Run("pages are fetched from the server", func(t *testing.T) {
+		fetch, requests := cursorNumbers(2500, true)
+		pager := cursorPager(fetch)
+
+		p, err := pager(0, 100)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 100, len(p.Items))
+		testhelpers.AssertEqual(t, 2500, p.Total)
+		testhelpers.AssertTrue(t, p.More, "there should be more pages")
+		testhelpers.AssertEqual(t, "100", p.Next)
+		// the first page takes a single request of its size, not a download of the whole list
+		testhelpers.AssertEqual(t, "[{100 }]", fmt.Sprint(*requests))
+
+		p, err = pager(100, 100)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 101, p.Items[0])
+		testhelpers.AssertEqual(t, 2, len(*requests))
+	})
+
+	t.Run("a page down the list is reached by walking the pages before it", func(t *testing.T) {
+		fetch, requests := cursorNumbers(250, false)
+		p, err := cursorPager(fetch)(200, 100)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "[201 202]", fmt.Sprint(p.Items[:2]))
+		testhelpers.AssertEqual(t, 50, len(p.Items))
+		testhelpers.AssertEqual(t, unknownTotal, p.Total)
+		testhelpers.AssertTrue(t, !p.More, "the last page should have no more after it")
+		testhelpers.AssertEqual(t, 3, len(*requests))
+	})
+
+	t.Run("the whole list is fetched in the largest pages", func(t *testing.T) {
+		fetch, requests := cursorNumbers(2500, false)
+		p, err := cursorPager(fetch)(0, 0)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 2500, len(p.Items))
+		testhelpers.AssertEqual(t, 3, len(*requests))
+		testhelpers.AssertEqual(t, api.MaxPageLimit, (*requests)[0].Limit)
+	})
+
+	t.Run("the list starts after --after", func(t *testing.T) {
+		withAfterFlag(t, "40")
+		fetch, requests := cursorNumbers(100, true)
+		p, err := cursorPager(fetch)(0, 10)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 41, p.Items[0])
+		testhelpers.AssertEqual(t, "40", (*requests)[0].After)
+		// the total of the whole list would be misleading after a cursor
+		testhelpers.AssertEqual(t, unknownTotal, p.Total)
+	})
+}
+
+func TestRunListingCursorPages(t *testing.T) {
+	withOutputFormat(t, outputFormatTable)
+	t.Setenv("HOME", t.TempDir())
+	withColumnsFlags(t, "", false)
+
+	newListing := func(n int, counted bool) listing[int] {
+		l, _ := numberListing(0)
+		fetch, _ := cursorNumbers(n, counted)
+		l.fetch = cursorPager(fetch)
+		return l
+	}
+
+	t.Run("counted lists are numbered by page", func(t *testing.T) {
+		cmd, _, stderr := newPaginationTestCmd()
+		withPaginationFlags(t, 10, 2, false)
+
+		testhelpers.AssertNoError(t, runListing(cmd, newListing(25, true)))
+		testhelpers.AssertStringContains(t, stderr.String(), "Showing 11–20 of 25; use --page 3")
+	})
+
+	t.Run("uncounted lists point to the next cursor", func(t *testing.T) {
+		cmd, stdout, stderr := newPaginationTestCmd()
+		withPaginationFlags(t, 10, 1, false)
+
+		testhelpers.AssertNoError(t, runListing(cmd, newListing(25, false)))
+		testhelpers.AssertStringContains(t, stdout.String(), "10=10")
+		testhelpers.AssertStringContains(t, stderr.String(), "Showing 1–10; use --after 10")
+	})
+
+	t.Run("--after continues from the cursor", func(t *testing.T) {
+		cmd, stdout, stderr := newPaginationTestCmd()
+		withPaginationFlags(t, 10, 1, false)
+		withAfterFlag(t, "20")
+
+		testhelpers.AssertNoError(t, runListing(cmd, newListing(25, true)))
+		testhelpers.AssertStringContains(t, stdout.String(), "1=21")
+		testhelpers.AssertStringNotContains(t, stderr.String(), "--after")
+	})
+
+	t.Run("--all follows the cursors", func(t *testing.T) {
+		cmd, stdout, _ := newPaginationTestCmd()
+		withPaginationFlags(t, 10, 1, true)
+
+		testhelpers.AssertNoError(t, runListing(cmd, newListing(25, false)))
+		testhelpers.AssertStringContains(t, stdout.String(), "25=25")
+	})
+
+	t.Run("--after and --page are exclusive", func(t *testing.T) {
+		cmd, _, _ := newPaginationTestCmd()
+		withPaginationFlags(t, 10, 1, false)
+		withAfterFlag(t, "20")
+		testhelpers.AssertNoError(t, cmd.ParseFlags([]string{"--page", "2"}))
+		testhelpers.AssertError(t, runListing(cmd, newListing(25, true)))
+	})
+
+	t.Run("--after is rejected by lists without cursors", func(t *testing.T) {
+		cmd, _, _ := newPaginationTestCmd()
+		withPaginationFlags(t, 10, 1, false)
+		withAfterFlag(t, "20")
+		l, _ := numberListing(25)
+		testhelpers.AssertError(t, runListing(cmd, l))
+	})
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// quietFlag is set by the global --quiet flag.
+// In quiet mode, commands only print their primary result (or an error), nothing else.
+var quietFlag bool
+
+// printer is the shared way for commands to write their output.
+// It keeps the primary result of a command separate from everything else it prints, so that
+// the output of a command can be captured by scripts:
+//   - results (tables, JSON, values like a newly created access token) are written to stdout
+//   - informational & progress messages are written to stderr, and dropped entirely in quiet mode
+//   - warnings are always written to stderr, even in quiet mode
+//
+// Messages never interleave with the progress indicator of a long-running operation, see Progress.
+type printer struct {
+	cmd *cobra.Command
+	// warnings are the warnings printed so far, eg- for a command to record them along with its result
+	warnings []string
+}
+
+func newPrinter(cmd *cobra.Command) *printer {
+	return &printer{cmd: cmd}
+}
+
+// out returns the writer for results.
+func (p *printer) out() io.Writer {
+	return p.cmd.OutOrStdout()
+}
+
+// info returns the writer for informational messages.
+func (p *printer) info() io.Writer {
+	if quietFlag {
+		return io.Discard
+	}
+	return p.cmd.ErrOrStderr()
+}
+
+// Resultf prints part of the primary result of the command.
+func (p *printer) Resultf(format string, args ...any) {
+	writeOutput(func() { _, _ = fmt.Fprintf(p.out(), format, args...) })
+}
+
+// Resultln prints part of the primary result of the command, followed by a newline.
+func (p *printer) Resultln(args ...any) {
+	writeOutput(func() { _, _ = fmt.Fprintln(p.out(), args...) })
+}
+
+// Value prints a single value produced by the command (eg- an access token) as "label: value".
+// In quiet mode only the value itself is printed, so that it can be captured directly by a script.
+func (p *printer) Value(label, value string) {
+	if quietFlag {
+		p.Resultln(value)
+		return
+	}
+	p.Resultf("%s: %s\n", label, value)
+}
+
+// Infof prints an informational message, unless quiet mode is on.
+func (p *printer) Infof(format string, args ...any) {
+	writeOutput(func() { _, _ = fmt.Fprintf(p.info(), format, args...) })
+}
+
+// Infoln prints an informational message followed by a newline, unless quiet mode is on.
+func (p *printer) Infoln(args ...any) {
+	writeOutput(func() { _, _ = fmt.Fprintln(p.info(), args...) })
+}
+
+// Warnf prints a warning to stderr, highlighted if stderr supports colors.
+// Warnings are printed even in quiet mode.
+func (p *printer) Warnf(format string, args ...any) {
+	w := p.cmd.ErrOrStderr()
+	msg := fmt.Sprintf(format, args...)
+	p.warnings = append(p.warnings, msg)
+	writeOutput(func() { _, _ = fmt.Fprintln(w, newStyler(w).Yellow("WARNING: "+msg)) })
+}
+
+// Debugf prints a diagnostic message to stderr, only in verbose mode.
+func (p *printer) Debugf(format string, args ...any) {
+	if verbosity == 0 {
+		return
+	}
+	writeOutput(func() { _, _ = fmt.Fprintf(p.cmd.ErrOrStderr(), format, args...) })
+}
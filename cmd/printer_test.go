@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/spf13/cobra"
+)
+
+func newPrinterTestCmd(t *testing.T, quiet bool, verbose int) (*printer, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	origQuiet, origVerbosity := quietFlag, verbosity
+	quietFlag, verbosity = quiet, verbose
+	t.Cleanup(func() { quietFlag, verbosity = origQuiet, origVerbosity })
+
+	cmd := &cobra.Command{}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	return newPrinter(cmd), stdout, stderr
+}
+
+func TestPrinterSeparatesResultsFromInfo(t *testing.T) {
+	p, stdout, stderr := newPrinterTestCmd(t, false, 0)
+
+	p.Infoln("Server github registered successfully!")
+	p.Resultln("github")
+	p.Value("Access token", "tok-123")
+	p.Debugf("not shown\n")
+
+	testhelpers.AssertEqual(t, "github\nAccess token: tok-123\n", stdout.String())
+	testhelpers.AssertEqual(t, "Server github registered successfully!\n", stderr.String())
+}
+
+func TestPrinterQuiet(t *testing.T) {
+	p, stdout, stderr := newPrinterTestCmd(t, true, 0)
+
+	p.Infof("Creating subdirectories inside %s\n", "/tmp")
+	p.Value("Access token", "tok-123")
+	p.Warnf("SSE is deprecated")
+
+	testhelpers.AssertEqual(t, "tok-123\n", stdout.String())
+	testhelpers.AssertStringNotContains(t, stderr.String(), "Creating subdirectories")
+	testhelpers.AssertStringContains(t, stderr.String(), "WARNING: SSE is deprecated")
+}
+
+func TestPrinterVerbose(t *testing.T) {
+	p, stdout, stderr := newPrinterTestCmd(t, true, 1)
+
+	p.Debugf("failed to fetch tools: %s\n", "boom")
+
+	testhelpers.AssertEqual(t, "", stdout.String())
+	testhelpers.AssertEqual(t, "failed to fetch tools: boom\n", stderr.String())
+}
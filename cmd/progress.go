@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// spinnerFrames are drawn one after the other in front of the current step of a long-running operation.
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+// Intervals at which progress is shown: the spinner is redrawn every spinnerInterval on a terminal,
+// otherwise a status line is printed every progressStatusInterval while a step lasts.
+var (
+	spinnerInterval        = 100 * time.Millisecond
+	progressStatusInterval = 5 * time.Second
+)
+
+// outputMu serializes everything written to the terminal by the printer and the progress indicator,
+// so that a spinner frame is never drawn in the middle of another message.
+var outputMu sync.Mutex
+
+// activeProgress is the progress indicator currently shown, if any. Only one is shown at a time.
+var activeProgress *progress
+
+// progress shows the current step of a long-running operation, so that users know the CLI did not hang.
+//
+// When both stdout and stderr are terminals, the step is shown next to a spinner on a single line of stderr,
+// which is erased before anything else is printed and when the operation is done.
+// Otherwise, a plain status line with the current step is printed periodically, so that operations which
+// complete quickly don't print anything. Nothing is shown in quiet mode.
+type progress struct {
+	w           io.Writer
+	interactive bool
+
+	step string
+	// start is when the operation started, status lines show how long it has been running
+	start time.Time
+	// drawn is true if a spinner line is currently on screen
+	drawn bool
+	frame int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Progress starts showing step as the current step of a long-running operation.
+// It must be stopped with Stop once the operation is done. It stops by itself if the command's context is cancelled.
+func (p *printer) Progress(step string) *progress {
+	pr := &progress{
+		w:           p.info(),
+		interactive: !quietFlag && isTerminalWriter(p.out()) && isTerminalWriter(p.cmd.ErrOrStderr()),
+		start:       time.Now(),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	outputMu.Lock()
+	if activeProgress != nil {
+		activeProgress.stopLocked()
+	}
+	activeProgress = pr
+	pr.setStepLocked(step)
+	outputMu.Unlock()
+
+	go pr.run(commandContext(p.cmd))
+	return pr
+}
+
+// Step changes the current step of the operation, eg- "syncing tools 34/120".
+func (pr *progress) Step(format string, args ...any) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	if activeProgress != pr {
+		return
+	}
+	pr.setStepLocked(fmt.Sprintf(format, args...))
+}
+
+// Stop erases the progress indicator. It is safe to call Stop more than once.
+func (pr *progress) Stop() {
+	outputMu.Lock()
+	if activeProgress == pr {
+		pr.stopLocked()
+		activeProgress = nil
+	}
+	outputMu.Unlock()
+	<-pr.done
+}
+
+func (pr *progress) run(ctx context.Context) {
+	defer close(pr.done)
+
+	interval := progressStatusInterval
+	if pr.interactive {
+		interval = spinnerInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pr.stop:
+			return
+		case <-ctx.Done():
+			outputMu.Lock()
+			if activeProgress == pr {
+				pr.stopLocked()
+				activeProgress = nil
+			}
+			outputMu.Unlock()
+			return
+		case <-ticker.C:
+			outputMu.Lock()
+			if activeProgress == pr {
+				pr.tickLocked()
+			}
+			outputMu.Unlock()
+		}
+	}
+}
+
+func (pr *progress) setStepLocked(step string) {
+	pr.step = step
+	if pr.interactive {
+		pr.drawLocked()
+	}
+}
+
+func (pr *progress) tickLocked() {
+	if pr.interactive {
+		pr.frame = (pr.frame + 1) % len(spinnerFrames)
+		pr.drawLocked()
+		return
+	}
+	_, _ = fmt.Fprintf(pr.w, "%s... (%s)\n", pr.step, time.Since(pr.start).Round(time.Second))
+}
+
+func (pr *progress) drawLocked() {
+	pr.clearLocked()
+	_, _ = fmt.Fprintf(pr.w, "%c %s...", spinnerFrames[pr.frame], pr.step)
+	pr.drawn = true
+}
+
+// clearLocked erases the spinner line, if it is on screen.
+func (pr *progress) clearLocked() {
+	if pr.drawn {
+		_, _ = io.WriteString(pr.w, "\r\033[2K")
+		pr.drawn = false
+	}
+}
+
+func (pr *progress) stopLocked() {
+	pr.clearLocked()
+	select {
+	case <-pr.stop:
+	default:
+		close(pr.stop)
+	}
+}
+
+// writeOutput runs write, which prints to the terminal, after erasing the spinner if one is shown.
+// The spinner is drawn again on its next frame, below what was printed.
+func writeOutput(write func()) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	if activeProgress != nil {
+		activeProgress.clearLocked()
+	}
+	write()
+}
+
+// syncedWriter is a writer whose writes never interleave with the progress indicator.
+// It is used for output that doesn't go through the printer, eg- the logs of HTTP requests.
+type syncedWriter struct {
+	w io.Writer
+}
+
+func (s syncedWriter) Write(b []byte) (n int, err error) {
+	writeOutput(func() { n, err = s.w.Write(b) })
+	return n, err
+}
+
+// stopProgress erases the progress indicator currently shown, if any, eg- before printing the error a command failed with.
+func stopProgress() {
+	outputMu.Lock()
+	pr := activeProgress
+	outputMu.Unlock()
+	if pr != nil {
+		pr.Stop()
+	}
+}
+
+// commandContext returns the context of cmd, or the background context if it has none (eg- in tests).
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
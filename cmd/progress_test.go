@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func withProgressIntervals(t *testing.T, spinner, status time.Duration) {
+	t.Helper()
+	origSpinner, origStatus := spinnerInterval, progressStatusInterval
+	spinnerInterval, progressStatusInterval = spinner, status
+	t.Cleanup(func() { spinnerInterval, progressStatusInterval = origSpinner, origStatus })
+}
+
+func TestProgressSpinner(t *testing.T) {
+	withTerminal(t, true)
+	withProgressIntervals(t, time.Millisecond, time.Hour)
+	p, stdout, stderr := newPrinterTestCmd(t, false, 0)
+
+	pr := p.Progress("Registering server github")
+	time.Sleep(10 * time.Millisecond)
+	p.Resultln("result")
+	pr.Step("syncing tools %d/%d", 34, 120)
+	pr.Stop()
+	pr.Stop()
+
+	testhelpers.AssertEqual(t, "result\n", stdout.String())
+	testhelpers.AssertStringContains(t, stderr.String(), "⠋ Registering server github...")
+	testhelpers.AssertStringContains(t, stderr.String(), "syncing tools 34/120...")
+	// the spinner is erased when the operation is done
+	testhelpers.AssertTrue(t, strings.HasSuffix(stderr.String(), "\r\033[2K"), "the spinner line should be erased")
+}
+
+func TestProgressStatusLines(t *testing.T) {
+	withTerminal(t, false)
+
+	t.Run("quick operations print nothing", func(t *testing.T) {
+		withProgressIntervals(t, time.Millisecond, time.Hour)
+		p, _, stderr := newPrinterTestCmd(t, false, 0)
+		p.Progress("Registering server github").Stop()
+		testhelpers.AssertEqual(t, "", stderr.String())
+	})
+
+	t.Run("slow operations print periodic status lines", func(t *testing.T) {
+		withProgressIntervals(t, time.Millisecond, 5*time.Millisecond)
+		p, _, stderr := newPrinterTestCmd(t, false, 0)
+		pr := p.Progress("Registering server github")
+		time.Sleep(20 * time.Millisecond)
+		pr.Stop()
+
+		testhelpers.AssertStringContains(t, stderr.String(), "Registering server github... (0s)\n")
+		testhelpers.AssertStringNotContains(t, stderr.String(), "\r")
+	})
+
+	t.Run("quiet mode", func(t *testing.T) {
+		withProgressIntervals(t, time.Millisecond, time.Millisecond)
+		p, _, stderr := newPrinterTestCmd(t, true, 0)
+		pr := p.Progress("Registering server github")
+		time.Sleep(10 * time.Millisecond)
+		pr.Stop()
+		testhelpers.AssertEqual(t, "", stderr.String())
+	})
+}
+
+func TestProgressStopsWhenCancelled(t *testing.T) {
+	withTerminal(t, true)
+	withProgressIntervals(t, time.Millisecond, time.Hour)
+	p, _, stderr := newPrinterTestCmd(t, false, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cmd.SetContext(ctx)
+
+	pr := p.Progress("Registering server github")
+	cancel()
+	<-pr.done
+
+	testhelpers.AssertTrue(t, strings.HasSuffix(stderr.String(), "\r\033[2K"), "the spinner line should be erased")
+	pr.Stop()
+}
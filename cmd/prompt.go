@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// readSecretFromTerminal reads a line from the terminal without echoing it.
+// It is a variable so that tests can override it.
+var readSecretFromTerminal = func() (string, error) {
+	b, err := term.ReadPassword(int(os.Stdin.Fd()))
+	return string(b), err
+}
+
+// prompter asks the user questions on an interactive terminal.
+// Questions are written to out (stderr) so that they never mix with the output of a command.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out}
+}
+
+// readLine reads a line of input, without the trailing newline.
+func (p *prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) && line != "" {
+			return strings.TrimSpace(line), nil
+		}
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// ask prompts for a value until the answer passes validate.
+// If the user enters nothing, def is used as the answer.
+// validate may be nil, in which case any answer is accepted.
+func (p *prompter) ask(question, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			_, _ = fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+		} else {
+			_, _ = fmt.Fprintf(p.out, "%s: ", question)
+		}
+
+		answer, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = def
+		}
+		if validate != nil {
+			if err := validate(answer); err != nil {
+				_, _ = fmt.Fprintf(p.out, "  %v\n", err)
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+// askSecret prompts for a value without echoing the user's input.
+func (p *prompter) askSecret(question string) (string, error) {
+	_, _ = fmt.Fprintf(p.out, "%s: ", question)
+	answer, err := readSecretFromTerminal()
+	_, _ = fmt.Fprintln(p.out)
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+// choose asks the user to pick one of the options and returns it.
+// def is the index of the option picked when the user enters nothing.
+func (p *prompter) choose(question string, options []string, def int) (string, error) {
+	_, _ = fmt.Fprintf(p.out, "%s\n", question)
+	for i, o := range options {
+		_, _ = fmt.Fprintf(p.out, "  %d) %s\n", i+1, o)
+	}
+
+	answer, err := p.ask("Choose an option", strconv.Itoa(def+1), func(s string) error {
+		if n, err := strconv.Atoi(s); err == nil && n >= 1 && n <= len(options) {
+			return nil
+		}
+		for _, o := range options {
+			if s == o {
+				return nil
+			}
+		}
+		return fmt.Errorf("please enter a number between 1 and %d", len(options))
+	})
+	if err != nil {
+		return "", err
+	}
+	if n, err := strconv.Atoi(answer); err == nil {
+		return options[n-1], nil
+	}
+	return answer, nil
+}
+
+// confirm asks a yes/no question, def is the answer used when the user enters nothing.
+func (p *prompter) confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	_, _ = fmt.Fprintf(p.out, "%s [%s]: ", question, hint)
+
+	answer, err := p.readLine()
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "":
+		return def, nil
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
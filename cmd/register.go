@@ -1,10 +1,10 @@
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
 
+	"github.com/mcpjungle/mcpjungle/client"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
 )
@@ -16,6 +16,9 @@ var (
 	registerCmdBearerToken string
 
 	registerCmdServerConfigFilePath string
+	registerCmdAtomic               bool
+	registerCmdCheck                bool
+	registerCmdFromSearch           string
 )
 
 var registerMCPServerCmd = &cobra.Command{
@@ -25,18 +28,24 @@ var registerMCPServerCmd = &cobra.Command{
 		"The recommended way is to specify the json configuration file for your mcp server.\n" +
 		"Flags are provided for convenience if you want to register a streamable http based server.\n" +
 		"But a config file is *required* if you want to register a server using stdio or sse transport.\n" +
+		"The configuration can also be piped into the CLI, eg- `some-generator | mcpjungle register -c -`.\n" +
+		"If no flags are provided and the CLI is run in a terminal, an interactive wizard guides you through registration.\n" +
+		"Use --check to validate a configuration without registering it, eg- in CI.\n" +
+		"Use --from-search to register a server found with `mcpjungle search registry`.\n" +
 		"\nNOTE: A server's name is unique across mcpjungle and must not contain\nany whitespaces, special characters or multiple consecutive underscores '__'.",
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		// Skip flag validation if config file is provided
-		if registerCmdServerConfigFilePath != "" {
+		// Skip flag validation if the configuration is supplied as a file or on stdin,
+		// or if the user is going to be prompted for it
+		if registerCmdServerConfigFilePath != "" || registerConfigFromStdin() || registerWizardRequested(cmd) ||
+			registerCmdFromSearch != "" {
 			return nil
 		}
 		// Otherwise, validate required flags
 		if registerCmdServerName == "" {
-			return fmt.Errorf("either supply a configuration file or set the required flag \"name\"")
+			return usageErrorf("either supply a configuration file or set the required flag \"name\"")
 		}
 		if registerCmdServerURL == "" {
-			return fmt.Errorf("required flag \"url\" not set")
+			return usageErrorf("required flag \"url\" not set")
 		}
 		return nil
 	},
@@ -78,94 +87,297 @@ func init() {
 		"conf",
 		"c",
 		"",
-		"Path to a JSON configuration file for the MCP server.\n"+
+		"Path to a JSON or YAML configuration file for the MCP server, or '-' to read it from stdin.\n"+
 			"If provided, the mcp server will be registered using the configuration in the file.\n"+
+			"The file may contain multiple servers, as a list or as multiple YAML documents,\n"+
+			"or be an mcp.json file with an mcpServers map.\n"+
 			"All other flags will be ignored.",
 	)
+	registerMCPServerCmd.Flags().BoolVar(
+		&registerCmdAtomic,
+		"atomic",
+		false,
+		"Register either all the servers of the configuration file or none of them, if any fails",
+	)
+	registerMCPServerCmd.Flags().BoolVar(
+		&registerCmdCheck,
+		"check",
+		false,
+		"Only validate the configuration, with the same checks as the server, without registering anything.\n"+
+			"Every invalid field is reported with its path in the configuration.",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdFromSearch,
+		"from-search",
+		"",
+		"Name of a server found with `mcpjungle search registry` to register.\n"+
+			"The values the server requires, like credentials, are asked interactively. --name overrides its name.",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&indexURLFlag,
+		"index",
+		"",
+		"Base URL of the index of MCP servers to look the server of --from-search up in",
+	)
+	registerMCPServerCmd.MarkFlagsMutuallyExclusive("from-search", "conf")
+	registerMCPServerCmd.MarkFlagsMutuallyExclusive("from-search", "url")
 
 	rootCmd.AddCommand(registerMCPServerCmd)
 }
 
-func readMcpServerConfig(filePath string) (types.RegisterServerInput, error) {
-	var input types.RegisterServerInput
+// registerConfigFromStdin reports whether the configuration to register is read from stdin: either it is asked for
+// with -c -, or stdin is piped and no server is given with --name or --url, which win over the piped data.
+func registerConfigFromStdin() bool {
+	if registerCmdServerConfigFilePath != "" {
+		return registerCmdServerConfigFilePath == stdinConfigPath
+	}
+	return configFromStdin("") && registerCmdServerName == "" && registerCmdServerURL == ""
+}
 
-	data, err := os.ReadFile(filePath)
+// readMcpServerConfigs reads the configurations of one or more MCP servers from a JSON or YAML file, or from stdin.
+// The file may also be an mcp.json file, whose servers are all registered.
+func readMcpServerConfigs(cmd *cobra.Command, filePath string) ([]types.RegisterServerInput, error) {
+	data, err := readConfigInput(cmd, filePath)
 	if err != nil {
-		return input, fmt.Errorf("failed to read config file %s: %w", filePath, err)
+		return nil, err
 	}
-	// Parse JSON config
-	if err := json.Unmarshal(data, &input); err != nil {
-		return input, fmt.Errorf("failed to parse config file: %w", err)
+	decode := decodeConfigEntities[types.RegisterServerInput]
+	if isMCPJSONDocument(data) {
+		decode = mcpJSONServerInputs
 	}
-
-	return input, nil
+	inputs, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration from %s: %w", configSourceName(filePath), err)
+	}
+	return inputs, nil
 }
 
 func runRegisterMCPServer(cmd *cobra.Command, args []string) error {
-	var input types.RegisterServerInput
+	var inputs []types.RegisterServerInput
 
-	if registerCmdServerConfigFilePath == "" {
+	switch {
+	case registerWizardRequested(cmd):
+		input, err := runRegisterWizard(cmd)
+		if err != nil {
+			return err
+		}
+		inputs = append(inputs, *input)
+	case registerCmdFromSearch != "":
+		s, err := findIndexServer(commandContext(cmd), activeIndexURL(), registerCmdFromSearch)
+		if err != nil {
+			return err
+		}
+		input, err := registerInputFromSearch(cmd, s, registerCmdServerName)
+		if err != nil {
+			return err
+		}
+		if registerCmdServerDesc != "" {
+			input.Description = registerCmdServerDesc
+		}
+		inputs = append(inputs, *input)
+	case registerCmdServerConfigFilePath == "" && !registerConfigFromStdin():
 		// If no config file is provided, use the flags to create the input for server registration
-		input = types.RegisterServerInput{
+		inputs = append(inputs, types.RegisterServerInput{
 			Name:        registerCmdServerName,
 			Transport:   string(types.TransportStreamableHTTP),
 			URL:         registerCmdServerURL,
 			Description: registerCmdServerDesc,
 			BearerToken: registerCmdBearerToken,
+		})
+	default:
+		// Otherwise, read the configuration of one or more servers from the file or stdin
+		var err error
+		inputs, err = readMcpServerConfigs(cmd, registerCmdServerConfigFilePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if registerCmdCheck {
+		names := make([]string, len(inputs))
+		for i := range inputs {
+			names[i] = inputs[i].Name
+		}
+		return checkConfigEntities(
+			cmd, "server", configSourceName(registerCmdServerConfigFilePath), names,
+			func(i int) error { return inputs[i].Validate() },
+		)
+	}
+
+	var registered []*types.McpServer
+	var failures []error
+	bulk, err := useBulkRegistration(cmd, len(inputs))
+	if err != nil {
+		return err
+	}
+	if bulk {
+		registered, failures, err = registerServersBulk(cmd, inputs)
+		if err != nil {
+			return err
 		}
 	} else {
-		// If a config file is provided, read the configuration from the file
-		var err error
-		input, err = readMcpServerConfig(registerCmdServerConfigFilePath)
+		registered, failures = registerServersOneByOne(cmd, inputs)
+	}
+
+	if isStructuredOutput() {
+		if len(inputs) == 1 && len(registered) == 1 {
+			err = printOutput(cmd, registered[0])
+		} else if len(registered) > 0 {
+			err = printOutput(cmd, registered)
+		}
 		if err != nil {
 			return err
 		}
 	}
 
-	s, err := apiClient.RegisterServer(&input)
+	if len(failures) == 1 && len(inputs) == 1 {
+		return failures[0]
+	}
+	if len(failures) > 0 && registerCmdAtomic {
+		return fmt.Errorf(
+			"%d of %d servers could not be registered, so none were:\n%w", len(failures), len(inputs), errors.Join(failures...),
+		)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf(
+			"%d of %d servers could not be registered:\n%w", len(failures), len(inputs), errors.Join(failures...),
+		)
+	}
+	return nil
+}
+
+// useBulkRegistration reports whether servers should be registered with a single bulk request,
+// which is the case when registering several of them, or atomically, with a server that supports it.
+func useBulkRegistration(cmd *cobra.Command, count int) (bool, error) {
+	if count < 2 && !registerCmdAtomic {
+		return false, nil
+	}
+	v, err := apiClient.GetServerVersion(commandContext(cmd))
+	if err == nil && v.Supports(types.FeatureBulkRegistration) {
+		return true, nil
+	}
+	if registerCmdAtomic {
+		// registering the servers one by one would leave the registry half-populated if one of them fails
+		return false, errors.New("the server does not support atomic registration, upgrade it to use --atomic")
+	}
 	if err != nil {
-		return fmt.Errorf("failed to register server: %w", err)
+		newPrinter(cmd).Debugf("failed to check whether the server supports bulk registration: %v\n", err)
 	}
-	fmt.Printf("Server %s registered successfully!\n", s.Name)
+	return false, nil
+}
+
+// registerServersOneByOne registers servers with a request each.
+// All servers are registered even if some of them fail, so that a whole environment can be bootstrapped in one go.
+func registerServersOneByOne(cmd *cobra.Command, inputs []types.RegisterServerInput) ([]*types.McpServer, []error) {
+	var registered []*types.McpServer
+	var failures []error
+	for i := range inputs {
+		// registration connects to the server to fetch its tools, which can take a while
+		step := fmt.Sprintf("Registering server %s, validating upstream connectivity", inputs[i].Name)
+		if len(inputs) > 1 {
+			step = fmt.Sprintf("Registering server %s (%d/%d), validating upstream connectivity", inputs[i].Name, i+1, len(inputs))
+		}
+		pr := newPrinter(cmd).Progress(step)
+		s, err := apiClient.RegisterServerContext(commandContext(cmd), &inputs[i])
+		pr.Stop()
+		if err != nil {
+			failures = append(failures, configEntityError(
+				err, "failed to register server "+inputs[i].Name, configSourceName(registerCmdServerConfigFilePath), i, len(inputs),
+			))
+			continue
+		}
+		registered = append(registered, s)
+		if !isStructuredOutput() {
+			printRegisteredServer(cmd, s)
+		}
+	}
+	return registered, failures
+}
+
+// registerServersBulk registers servers with a single request, atomically if --atomic is set.
+// The returned error is set if the request itself failed, failures holds the servers that could not be registered.
+func registerServersBulk(cmd *cobra.Command, inputs []types.RegisterServerInput) ([]*types.McpServer, []error, error) {
+	opts := client.BulkRegisterOptions{Mode: types.BulkRegistrationBestEffort}
+	if registerCmdAtomic {
+		opts.Mode = types.BulkRegistrationAtomic
+	}
+	pr := newPrinter(cmd).Progress(fmt.Sprintf("Registering %d servers, validating upstream connectivity", len(inputs)))
+	result, err := apiClient.RegisterServersBulk(commandContext(cmd), inputs, opts)
+	pr.Stop()
+	if result == nil {
+		return nil, nil, err
+	}
+
+	var registered []*types.McpServer
+	var failures []error
+	for _, r := range result.Results {
+		switch r.Status {
+		case types.BulkItemRegistered:
+			registered = append(registered, r.Server)
+			if !isStructuredOutput() {
+				printRegisteredServer(cmd, r.Server)
+			}
+		case types.BulkItemFailed:
+			if len(r.Errors) > 0 {
+				failures = append(failures, configEntityError(
+					r.Errors, "failed to register server "+r.Name, configSourceName(registerCmdServerConfigFilePath), r.Index, len(inputs),
+				))
+				continue
+			}
+			failures = append(failures, fmt.Errorf("failed to register server %s: %s", r.Name, r.Error))
+		}
+	}
+	return registered, failures, nil
+}
+
+// printRegisteredServer tells the user about a newly registered server and the tools & prompts it provides.
+func printRegisteredServer(cmd *cobra.Command, s *types.McpServer) {
+	p := newPrinter(cmd)
+	p.Infof("Server %s registered successfully!\n", s.Name)
 
 	if types.McpServerTransport(s.Transport) == types.TransportSSE {
-		cmd.Println()
-		cmd.Println("This MCP server uses the SSE (Server-sent events) transport.")
-		cmd.Println("So its tools will be accessible at the '/sse' endpoint")
-		cmd.Println("WARNING: SSE is deprecated, consider migrating this MCP server to streamable http transport.")
+		p.Infoln()
+		p.Infoln("This MCP server uses the SSE (Server-sent events) transport.")
+		p.Infoln("So its tools will be accessible at the '/sse' endpoint")
+		p.Warnf("SSE is deprecated, consider migrating this MCP server to streamable http transport.")
 	}
 
-	tools, err := apiClient.ListTools(s.Name)
+	if quietFlag {
+		// the lists of tools & prompts below are purely informational, no need to fetch them
+		return
+	}
+
+	tools, err := apiClient.ListToolsContext(commandContext(cmd), s.Name)
 	if err != nil {
 		// if we fail to fetch tool list, fail silently because this is not a must-have output
-		return nil
+		p.Debugf("failed to fetch the tools of server %s: %v\n", s.Name, err)
+		return
 	}
 
-	cmd.Println()
+	p.Infoln()
 	if len(tools) == 0 {
-		cmd.Println("This server does not provide any tools.")
-		return nil
+		p.Infoln("This server does not provide any tools.")
+		return
 	}
-	cmd.Println("The following tools are now available from this server:")
+	p.Infoln("The following tools are now available from this server:")
 	for i, tool := range tools {
-		cmd.Printf("%d. %s: %s\n\n", i+1, tool.Name, tool.Description)
+		p.Infof("%d. %s: %s\n\n", i+1, tool.Name, tool.Description)
 	}
 
-	prompts, err := apiClient.ListPrompts(s.Name)
+	prompts, err := apiClient.ListPromptsContext(commandContext(cmd), s.Name)
 	if err != nil {
-		return nil
+		p.Debugf("failed to fetch the prompts of server %s: %v\n", s.Name, err)
+		return
 	}
 	if len(prompts) > 0 {
-		cmd.Println()
-		cmd.Println("The following prompts are now available from this server:")
+		p.Infoln()
+		p.Infoln("The following prompts are now available from this server:")
 		for i, prompt := range prompts {
-			cmd.Printf("%d. %s\n", i+1, prompt.Name)
+			p.Infof("%d. %s\n", i+1, prompt.Name)
 			if prompt.Description != "" {
-				cmd.Printf("   %s\n", prompt.Description)
+				p.Infof("   %s\n", prompt.Description)
 			}
-			cmd.Println()
+			p.Infoln()
 		}
 	}
-
-	return nil
 }
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	registerOpenAPISpec        string
+	registerOpenAPIName        string
+	registerOpenAPIDesc        string
+	registerOpenAPIBaseURL     string
+	registerOpenAPITags        []string
+	registerOpenAPIOperations  []string
+	registerOpenAPIBearerToken string
+	registerOpenAPIKey         string
+	registerOpenAPIKeyHeader   string
+	registerOpenAPICheck       bool
+)
+
+var registerOpenAPICmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "Register a REST service described by an OpenAPI spec as an MCP server",
+	Long: "Register a REST service described by an OpenAPI 3 spec (JSON or YAML, from a file or a URL)\n" +
+		"as an MCP server.\n" +
+		"Each operation of the spec becomes a tool named after its operationId, whose arguments are the parameters\n" +
+		"of the operation and, if it takes one, its JSON request body as the \"body\" argument.\n" +
+		"mcpjungle serves the tools itself: each tool call is translated into an HTTP request to the service,\n" +
+		"and its response is returned as text, and as structured content if it is a JSON object.\n\n" +
+		"Select the operations to serve with --tag and --operation (a glob matching their operationId), all of them\n" +
+		"are served by default. Operations using unsupported content types or cookie parameters are skipped and\n" +
+		"reported. The requests are authenticated with --bearer-token or --api-key.",
+	Example: "  mcpjungle register openapi --spec https://petstore3.swagger.io/api/v3/openapi.json --name petstore\n" +
+		"  mcpjungle register openapi --spec ./billing.yaml --name billing --tag invoices --operation 'get*' \\\n" +
+		"    --api-key $BILLING_API_KEY --api-key-header X-API-Key",
+	Args: cobra.NoArgs,
+	RunE: runRegisterOpenAPI,
+}
+
+func init() {
+	registerOpenAPICmd.Flags().StringVar(
+		&registerOpenAPISpec,
+		"spec",
+		"",
+		"Path or http(s) URL of the OpenAPI spec of the service",
+	)
+	_ = registerOpenAPICmd.MarkFlagRequired("spec")
+	registerOpenAPICmd.Flags().StringVar(
+		&registerOpenAPIName,
+		"name",
+		"",
+		"MCP server name",
+	)
+	_ = registerOpenAPICmd.MarkFlagRequired("name")
+	registerOpenAPICmd.Flags().StringVar(
+		&registerOpenAPIDesc,
+		"description",
+		"",
+		"Server description (default: the title of the spec)",
+	)
+	registerOpenAPICmd.Flags().StringVar(
+		&registerOpenAPIBaseURL,
+		"base-url",
+		"",
+		"URL the paths of the operations are relative to (default: the first server of the spec)",
+	)
+	registerOpenAPICmd.Flags().StringSliceVar(
+		&registerOpenAPITags,
+		"tag",
+		nil,
+		"Only serve the operations with this tag, can be repeated",
+	)
+	registerOpenAPICmd.Flags().StringSliceVar(
+		&registerOpenAPIOperations,
+		"operation",
+		nil,
+		"Only serve the operations whose operationId matches this glob pattern (eg- 'list*'), can be repeated",
+	)
+	registerOpenAPICmd.Flags().StringVar(
+		&registerOpenAPIBearerToken,
+		"bearer-token",
+		"",
+		"Bearer token sent in the Authorization header of every request to the service",
+	)
+	registerOpenAPICmd.Flags().StringVar(
+		&registerOpenAPIKey,
+		"api-key",
+		"",
+		"API key sent in the header given by --api-key-header with every request to the service",
+	)
+	registerOpenAPICmd.Flags().StringVar(
+		&registerOpenAPIKeyHeader,
+		"api-key-header",
+		"",
+		"Header carrying the API key (default: the header of the API key security scheme of the spec)",
+	)
+	registerOpenAPICmd.Flags().BoolVar(
+		&registerOpenAPICheck,
+		"check",
+		false,
+		"Only convert the spec and report the operations that would be served, without registering anything",
+	)
+	registerOpenAPICmd.MarkFlagsMutuallyExclusive("bearer-token", "api-key")
+
+	registerMCPServerCmd.AddCommand(registerOpenAPICmd)
+}
+
+func runRegisterOpenAPI(cmd *cobra.Command, args []string) error {
+	spec, err := loadOpenAPISpec(commandContext(cmd), registerOpenAPISpec)
+	if err != nil {
+		return err
+	}
+	input, skipped, err := newOpenAPIServerInput(spec)
+	if err != nil {
+		return err
+	}
+	printOpenAPIReport(cmd, input.OpenAPI.Operations, skipped)
+	if len(input.OpenAPI.Operations) == 0 {
+		return errors.New("none of the selected operations of the spec can be served as tools")
+	}
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	if registerOpenAPICheck {
+		return nil
+	}
+
+	pr := newPrinter(cmd).Progress(fmt.Sprintf("Registering server %s", input.Name))
+	s, err := apiClient.RegisterServerContext(commandContext(cmd), input)
+	pr.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to register server %s: %w", input.Name, err)
+	}
+	if isStructuredOutput() {
+		return printOutput(cmd, s)
+	}
+	printRegisteredServer(cmd, s)
+	return nil
+}
+
+// newOpenAPIServerInput converts the spec into the registration of the server, using the flags of the command.
+// It also returns the selected operations that can't be served as tools.
+func newOpenAPIServerInput(spec *openAPISpec) (*types.RegisterServerInput, []skippedOperation, error) {
+	baseURL := registerOpenAPIBaseURL
+	if baseURL == "" {
+		var err error
+		if baseURL, err = spec.baseURL(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var auth *types.OpenAPIAuth
+	switch {
+	case registerOpenAPIBearerToken != "":
+		auth = &types.OpenAPIAuth{Type: types.OpenAPIAuthBearer, Value: registerOpenAPIBearerToken}
+	case registerOpenAPIKey != "":
+		header := registerOpenAPIKeyHeader
+		if header == "" {
+			header = spec.securitySchemeHeader()
+		}
+		if header == "" {
+			return nil, nil, usageErrorf(
+				"the spec doesn't declare the header carrying the API key, set it with --api-key-header",
+			)
+		}
+		auth = &types.OpenAPIAuth{Type: types.OpenAPIAuthAPIKey, Header: header, Value: registerOpenAPIKey}
+	}
+
+	description := registerOpenAPIDesc
+	if description == "" {
+		description = spec.title()
+	}
+	operations, skipped := spec.operations(openAPIFilter{Tags: registerOpenAPITags, Operations: registerOpenAPIOperations})
+	return &types.RegisterServerInput{
+		Name:        registerOpenAPIName,
+		Transport:   string(types.TransportOpenAPI),
+		Description: description,
+		OpenAPI: &types.OpenAPIConfig{
+			BaseURL:    baseURL,
+			Auth:       auth,
+			Operations: operations,
+		},
+	}, skipped, nil
+}
+
+// printOpenAPIReport tells the user which operations of the spec are served as tools, and which are skipped and why.
+func printOpenAPIReport(cmd *cobra.Command, operations []types.OpenAPIOperation, skipped []skippedOperation) {
+	p := newPrinter(cmd)
+	p.Infof("%d operations of the spec will be served as tools:\n", len(operations))
+	for _, op := range operations {
+		p.Infof("  %s %s -> %s\n", op.Method, op.Path, op.Tool)
+	}
+	for _, op := range skipped {
+		p.Warnf("skipping %s %s (%s): %s", op.Method, op.Path, op.OperationID, op.Reason)
+	}
+}
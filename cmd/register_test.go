@@ -2,8 +2,14 @@ package cmd
 
 import (
 	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
 func TestRegisterCommandStructure(t *testing.T) {
@@ -329,3 +335,112 @@ func TestRunRegisterMCPServerFunctional(t *testing.T) {
 		}
 	})
 }
+
+// withRegisterConfigFile makes the register command read the configurations of the given servers from a file.
+func withRegisterConfigFile(t *testing.T, names ...string) {
+	t.Helper()
+	var inputs []types.RegisterServerInput
+	for _, name := range names {
+		inputs = append(inputs, types.RegisterServerInput{Name: name, Transport: "streamable_http", URL: "http://" + name + "/mcp"})
+	}
+	data, err := json.Marshal(inputs)
+	testhelpers.AssertNoError(t, err)
+	path := filepath.Join(t.TempDir(), "servers.json")
+	testhelpers.AssertNoError(t, os.WriteFile(path, data, 0o600))
+
+	setStdinIsPiped(t, false)
+	origPath, origQuiet := registerCmdServerConfigFilePath, quietFlag
+	registerCmdServerConfigFilePath, quietFlag = path, true
+	t.Cleanup(func() { registerCmdServerConfigFilePath, quietFlag = origPath, origQuiet })
+}
+
+func TestRegisterUsesBulkRegistration(t *testing.T) {
+	withRegisterConfigFile(t, "github", "slack")
+	var mode string
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"/api/v1/version": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, types.ServerVersion{Version: "1.0.0", Features: []string{types.FeatureBulkRegistration}})
+		},
+		"/api/v1/servers/bulk": func(w http.ResponseWriter, r *http.Request) {
+			mode = r.URL.Query().Get("mode")
+			writeTestJSON(w, http.StatusMultiStatus, types.BulkRegistrationResult{
+				Mode: types.BulkRegistrationBestEffort, Registered: 1, Failed: 1,
+				Results: []types.BulkItemResult{
+					{Index: 0, Name: "github", Status: types.BulkItemRegistered, Server: &types.McpServer{Name: "github"}},
+					{Index: 1, Name: "slack", Status: types.BulkItemFailed, Error: "connection refused"},
+				},
+			})
+		},
+		"/api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			t.Error("servers must not be registered one by one when the server supports bulk registration")
+		},
+	})
+
+	err := runRegisterMCPServer(newExitCodeTestCmd(), nil)
+	testhelpers.AssertEqual(t, string(types.BulkRegistrationBestEffort), mode)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "1 of 2 servers could not be registered")
+	testhelpers.AssertStringContains(t, err.Error(), "failed to register server slack: connection refused")
+}
+
+func TestRegisterFallsBackToOneByOne(t *testing.T) {
+	withRegisterConfigFile(t, "github", "slack")
+	var registered []string
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"/api/v1/version": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, types.ServerVersion{Version: "0.9.0"})
+		},
+		"/api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			var input types.RegisterServerInput
+			_ = json.NewDecoder(r.Body).Decode(&input)
+			registered = append(registered, input.Name)
+			writeTestJSON(w, http.StatusCreated, types.McpServer{Name: input.Name})
+		},
+	})
+
+	testhelpers.AssertNoError(t, runRegisterMCPServer(newExitCodeTestCmd(), nil))
+	testhelpers.AssertEqual(t, "github,slack", strings.Join(registered, ","))
+
+	t.Run("atomic registration requires support", func(t *testing.T) {
+		registerCmdAtomic = true
+		t.Cleanup(func() { registerCmdAtomic = false })
+		err := runRegisterMCPServer(newExitCodeTestCmd(), nil)
+		testhelpers.AssertError(t, err)
+		testhelpers.AssertStringContains(t, err.Error(), "does not support atomic registration")
+	})
+}
+
+func TestRegisterFlagsWinOverPipedStdin(t *testing.T) {
+	setStdinIsPiped(t, true)
+	var registered []string
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"/api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			var input types.RegisterServerInput
+			_ = json.NewDecoder(r.Body).Decode(&input)
+			registered = append(registered, input.Name+" "+input.URL)
+			writeTestJSON(w, http.StatusCreated, types.McpServer{Name: input.Name})
+		},
+	})
+	origName, origURL, origPath, origQuiet := registerCmdServerName, registerCmdServerURL,
+		registerCmdServerConfigFilePath, quietFlag
+	t.Cleanup(func() {
+		registerCmdServerName, registerCmdServerURL = origName, origURL
+		registerCmdServerConfigFilePath, quietFlag = origPath, origQuiet
+	})
+	quietFlag = true
+
+	// eg- `echo | mcpjungle register --name github --url ...` in a CI job whose stdin is a pipe
+	registerCmdServerName, registerCmdServerURL = "github", "https://api.githubcopilot.com/mcp/"
+	cmd := newExitCodeTestCmd()
+	cmd.SetIn(strings.NewReader(""))
+	testhelpers.AssertNoError(t, registerMCPServerCmd.PreRunE(cmd, nil))
+	testhelpers.AssertNoError(t, runRegisterMCPServer(cmd, nil))
+	testhelpers.AssertEqual(t, "github https://api.githubcopilot.com/mcp/", strings.Join(registered, ","))
+
+	// -c - still reads the configuration from stdin
+	registered = nil
+	registerCmdServerConfigFilePath = stdinConfigPath
+	cmd.SetIn(strings.NewReader(`{"name": "linear", "transport": "streamable_http", "url": "https://mcp.linear.app/mcp"}`))
+	testhelpers.AssertNoError(t, runRegisterMCPServer(cmd, nil))
+	testhelpers.AssertEqual(t, "linear https://mcp.linear.app/mcp", strings.Join(registered, ","))
+}
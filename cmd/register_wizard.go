@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// registerCmdFlagNames are the flags that describe the server to register.
+// If none of them is set, the register command runs the interactive wizard instead.
+var registerCmdFlagNames = []string{"name", "url", "description", "bearer-token", "conf", "from-search"}
+
+// registerWizardRequested reports whether the register command should run the interactive wizard,
+// ie, no flags describing the server were passed and stdin is a terminal.
+// Non-interactive invocations must keep failing fast so that automation never hangs on a prompt.
+func registerWizardRequested(cmd *cobra.Command) bool {
+	for _, name := range registerCmdFlagNames {
+		if cmd.Flags().Changed(name) {
+			return false
+		}
+	}
+	return stdinIsTerminal()
+}
+
+// runRegisterWizard interactively asks the user for the configuration of the MCP server to register.
+// Every answer is validated according to the same rules the server applies, so that the registration
+// doesn't get rejected after the user has answered all questions.
+func runRegisterWizard(cmd *cobra.Command) (*types.RegisterServerInput, error) {
+	out := cmd.ErrOrStderr()
+	p := newPrompter(cmd.InOrStdin(), out)
+
+	_, _ = fmt.Fprintln(out, "No flags were provided, let's register an MCP server interactively (press Ctrl+C to quit).")
+	_, _ = fmt.Fprintln(out)
+
+	var input types.RegisterServerInput
+	var err error
+
+	input.Name, err = p.ask("Server name", "", types.ValidateServerName)
+	if err != nil {
+		return nil, err
+	}
+
+	transports := []string{string(types.TransportStreamableHTTP), string(types.TransportStdio), string(types.TransportSSE)}
+	input.Transport, err = p.choose("Transport used by the server:", transports, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	switch types.McpServerTransport(input.Transport) {
+	case types.TransportStdio:
+		if err := askStdioLaunch(p, &input); err != nil {
+			return nil, err
+		}
+
+		env, err := p.ask("Environment variables for the server (optional, eg- KEY1=value1,KEY2=value2)", "", validateEnvList)
+		if err != nil {
+			return nil, err
+		}
+		input.Env = parseEnvList(env)
+	default:
+		input.URL, err = p.ask("URL of the server (eg- http://localhost:8000/mcp)", "", validateServerURL)
+		if err != nil {
+			return nil, err
+		}
+		input.BearerToken, err = p.askSecret("Bearer token to authenticate with the server (optional, input is hidden)")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	input.Description, err = p.ask("Description (optional)", "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, err := json.MarshalIndent(maskRegisterInput(input), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the server configuration: %w", err)
+	}
+	_, _ = fmt.Fprintf(out, "\nThe following server will be registered:\n%s\n\n", preview)
+
+	ok, err := p.confirm("Register this server?", true)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrAborted
+	}
+
+	path, err := p.ask("Save this configuration to a file for reuse? Enter a file path or leave empty to skip", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		if err := saveRegisterInput(path, input); err != nil {
+			return nil, err
+		}
+		_, _ = fmt.Fprintf(out, "Configuration saved to %s, you can register it again using `mcpjungle register -c %s`\n\n", path, path)
+	}
+
+	return &input, nil
+}
+
+// stdioLaunchCommand is the option of the wizard to launch a stdio server with a command instead of from a package.
+const stdioLaunchCommand = "command"
+
+// askStdioLaunch asks how to launch a stdio server: with a command, or from an npm or PyPI package
+// that mcpjungle runs with npx or uvx.
+func askStdioLaunch(p *prompter, input *types.RegisterServerInput) error {
+	launch, err := p.choose(
+		"How to launch the server:",
+		[]string{stdioLaunchCommand, string(types.PackageRuntimeNpx), string(types.PackageRuntimeUvx)},
+		0,
+	)
+	if err != nil {
+		return err
+	}
+	if launch == stdioLaunchCommand {
+		commandLine, err := p.ask(
+			"Command to run the server (eg- npx -y @modelcontextprotocol/server-filesystem /tmp)",
+			"",
+			validateRequired("command"),
+		)
+		if err != nil {
+			return err
+		}
+		fields := strings.Fields(commandLine)
+		input.Command, input.Args = fields[0], fields[1:]
+		return nil
+	}
+
+	pkg := &types.PackageConfig{Runtime: types.PackageRuntime(launch)}
+	pkg.Name, err = p.ask("Package name (eg- @modelcontextprotocol/server-filesystem)", "", types.ValidatePackageName)
+	if err != nil {
+		return err
+	}
+	version, err := p.ask("Package version", types.PackageVersionLatest, nil)
+	if err != nil {
+		return err
+	}
+	if version != types.PackageVersionLatest {
+		pkg.Version = version
+	} else {
+		pkg.Pin, err = p.confirm("Pin the server to the current latest release?", false)
+		if err != nil {
+			return err
+		}
+	}
+	args, err := p.ask("Arguments passed to the server (optional, eg- /tmp)", "", nil)
+	if err != nil {
+		return err
+	}
+	input.Package, input.Args = pkg, strings.Fields(args)
+	return nil
+}
+
+// maskRegisterInput returns a copy of the input that is safe to display, with its bearer token masked.
+func maskRegisterInput(input types.RegisterServerInput) types.RegisterServerInput {
+	input.BearerToken = config.MaskSecret(input.BearerToken)
+	return input
+}
+
+// saveRegisterInput writes the server configuration to a JSON file that can be passed to `register -c`.
+// The file may contain a bearer token, so it is only readable by the current user.
+func saveRegisterInput(path string, input types.RegisterServerInput) error {
+	data, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize the server configuration: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to save the server configuration to %s: %w", path, err)
+	}
+	return nil
+}
+
+func validateRequired(field string) func(string) error {
+	return func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("%s is required", field)
+		}
+		return nil
+	}
+}
+
+// validateServerURL checks that s is a URL the server can connect to.
+func validateServerURL(s string) error {
+	if s == "" {
+		return fmt.Errorf("url is required")
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("'%s' is not a valid http or https URL", s)
+	}
+	return nil
+}
+
+// validateEnvList checks that s is a comma-separated list of KEY=VALUE pairs.
+func validateEnvList(s string) error {
+	for _, pair := range splitEnvList(s) {
+		if k, _, ok := strings.Cut(pair, "="); !ok || strings.TrimSpace(k) == "" {
+			return fmt.Errorf("'%s' is not in the KEY=VALUE format", pair)
+		}
+	}
+	return nil
+}
+
+// parseEnvList parses a comma-separated list of KEY=VALUE pairs, it returns nil if the list is empty.
+func parseEnvList(s string) map[string]string {
+	pairs := splitEnvList(s)
+	if len(pairs) == 0 {
+		return nil
+	}
+	env := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		k, v, _ := strings.Cut(pair, "=")
+		env[strings.TrimSpace(k)] = v
+	}
+	return env
+}
+
+func splitEnvList(s string) []string {
+	var pairs []string
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair != "" {
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs
+}
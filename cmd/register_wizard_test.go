@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// newWizardTestCmd returns a command that reads the given answers from stdin, simulating a terminal.
+func newWizardTestCmd(t *testing.T, answers []string, secret string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	origTTY, origSecret := stdinIsTerminal, readSecretFromTerminal
+	stdinIsTerminal = func() bool { return true }
+	readSecretFromTerminal = func() (string, error) { return secret, nil }
+	t.Cleanup(func() { stdinIsTerminal, readSecretFromTerminal = origTTY, origSecret })
+
+	cmd := &cobra.Command{}
+	for _, name := range registerCmdFlagNames {
+		cmd.Flags().String(name, "", "")
+	}
+	stderr := &bytes.Buffer{}
+	cmd.SetIn(strings.NewReader(strings.Join(answers, "\n") + "\n"))
+	cmd.SetErr(stderr)
+	return cmd, stderr
+}
+
+func TestRegisterWizardRequested(t *testing.T) {
+	cmd, _ := newWizardTestCmd(t, nil, "")
+	testhelpers.AssertTrue(t, registerWizardRequested(cmd), "wizard should run without flags on a terminal")
+
+	stdinIsTerminal = func() bool { return false }
+	testhelpers.AssertFalse(t, registerWizardRequested(cmd), "wizard must not run when stdin is not a terminal")
+
+	stdinIsTerminal = func() bool { return true }
+	testhelpers.AssertNoError(t, cmd.Flags().Set("name", "github"))
+	testhelpers.AssertFalse(t, registerWizardRequested(cmd), "wizard must not run when flags are provided")
+}
+
+func TestRegisterWizardStreamableHTTP(t *testing.T) {
+	savePath := filepath.Join(t.TempDir(), "github.json")
+	cmd, stderr := newWizardTestCmd(t, []string{
+		"bad name!",                          // rejected, asked again
+		"github",                             // name
+		"",                                   // default transport (streamable_http)
+		"localhost:8000",                     // rejected, not a URL
+		"https://api.githubcopilot.com/mcp/", // url
+		"GitHub tools",                       // description
+		"y",                                  // confirm
+		savePath,                             // save the config
+	}, "ghp_0123456789abcdef")
+
+	input, err := runRegisterWizard(cmd)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "github", input.Name)
+	testhelpers.AssertEqual(t, string(types.TransportStreamableHTTP), input.Transport)
+	testhelpers.AssertEqual(t, "https://api.githubcopilot.com/mcp/", input.URL)
+	testhelpers.AssertEqual(t, "ghp_0123456789abcdef", input.BearerToken)
+	testhelpers.AssertEqual(t, "GitHub tools", input.Description)
+
+	out := stderr.String()
+	testhelpers.AssertStringContains(t, out, "invalid server name")
+	testhelpers.AssertStringContains(t, out, "not a valid http or https URL")
+	// the token is never shown in the preview
+	testhelpers.AssertStringNotContains(t, out, "ghp_0123456789abcdef")
+
+	data, err := os.ReadFile(savePath)
+	testhelpers.AssertNoError(t, err)
+	var saved types.RegisterServerInput
+	testhelpers.AssertNoError(t, json.Unmarshal(data, &saved))
+	testhelpers.AssertEqual(t, "github", saved.Name)
+	testhelpers.AssertEqual(t, "ghp_0123456789abcdef", saved.BearerToken)
+}
+
+func TestRegisterWizardStdio(t *testing.T) {
+	cmd, _ := newWizardTestCmd(t, []string{
+		"filesystem",
+		"2", // stdio
+		"",  // launch with a command
+		"",  // rejected, command is required
+		"npx -y @modelcontextprotocol/server-filesystem /tmp",
+		"LOG_LEVEL=debug, HOME",  // rejected, not KEY=VALUE
+		"LOG_LEVEL=debug, A=b=c", // env
+		"",                       // no description
+		"",                       // confirm by default
+		"",                       // don't save
+	}, "")
+
+	input, err := runRegisterWizard(cmd)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, string(types.TransportStdio), input.Transport)
+	testhelpers.AssertEqual(t, "npx", input.Command)
+	testhelpers.AssertEqual(t, "-y @modelcontextprotocol/server-filesystem /tmp", strings.Join(input.Args, " "))
+	testhelpers.AssertEqual(t, "debug", input.Env["LOG_LEVEL"])
+	testhelpers.AssertEqual(t, "b=c", input.Env["A"])
+	testhelpers.AssertEqual(t, "", input.URL)
+}
+
+func TestRegisterWizardStdioPackage(t *testing.T) {
+	cmd, stderr := newWizardTestCmd(t, []string{
+		"time",
+		"2",                    // stdio
+		"3",                    // uvx
+		"mcp-server-time==1.0", // rejected, the version is asked separately
+		"mcp-server-time",
+		"",                     // latest
+		"y",                    // pin
+		"--local-timezone=UTC", // args
+		"",                     // no env
+		"",                     // no description
+		"",                     // confirm by default
+		"",                     // don't save
+	}, "")
+
+	input, err := runRegisterWizard(cmd)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "", input.Command)
+	testhelpers.AssertEqual(t, types.PackageConfig{Runtime: types.PackageRuntimeUvx, Name: "mcp-server-time", Pin: true}, *input.Package)
+	testhelpers.AssertEqual(t, "--local-timezone=UTC", strings.Join(input.Args, " "))
+	testhelpers.AssertStringContains(t, stderr.String(), "must not contain a version")
+}
+
+func TestRegisterWizardAborted(t *testing.T) {
+	cmd, _ := newWizardTestCmd(t, []string{"github", "1", "http://localhost:8000/mcp", "", "n"}, "")
+	_, err := runRegisterWizard(cmd)
+	testhelpers.AssertTrue(t, errors.Is(err, ErrAborted), "declining the confirmation should abort")
+}
+
+func TestRegisterWizardEndOfInput(t *testing.T) {
+	cmd, _ := newWizardTestCmd(t, []string{"github"}, "")
+	_, err := runRegisterWizard(cmd)
+	testhelpers.AssertError(t, err)
+}
@@ -0,0 +1,374 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// defaultIndexURL is the index of MCP servers searched when none is configured: the public MCP registry.
+const defaultIndexURL = "https://registry.modelcontextprotocol.io"
+
+const (
+	// indexCacheTTL is how long the results of a search of the index are reused.
+	indexCacheTTL = 10 * time.Minute
+	// indexCacheMaxAge is how long the results are kept, to fall back to when the index can't be reached.
+	indexCacheMaxAge = 24 * time.Hour
+	// indexRequestTimeout bounds the requests to the index, so that a slow index never hangs the CLI.
+	indexRequestTimeout = 15 * time.Second
+	// indexMaxResponseBytes caps the size of the responses of the index.
+	indexMaxResponseBytes = 10 << 20
+)
+
+// indexURLFlag is set by the --index flag of the commands querying the index.
+var indexURLFlag string
+
+// nowFunc returns the current time, it is a variable so that tests can control the expiry of the cache.
+var nowFunc = time.Now
+
+// indexServer is an MCP server listed in the index, in the format of the MCP registry API
+// (https://github.com/modelcontextprotocol/registry).
+type indexServer struct {
+	// Name identifies the server in the index, in reverse-DNS format (eg- io.github.github/github-mcp-server).
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description"`
+	Version     string           `json:"version,omitempty"`
+	Repository  *indexRepository `json:"repository,omitempty"`
+	Packages    []indexPackage   `json:"packages,omitempty"`
+	Remotes     []indexRemote    `json:"remotes,omitempty"`
+}
+
+type indexRepository struct {
+	URL string `json:"url"`
+}
+
+// indexPackage is a package that runs the server locally, over stdio.
+type indexPackage struct {
+	// RegistryType is the package registry the package is published to, eg- npm, pypi or oci.
+	RegistryType string `json:"registryType"`
+	Identifier   string `json:"identifier"`
+	Version      string `json:"version,omitempty"`
+	Transport    struct {
+		Type string `json:"type"`
+	} `json:"transport"`
+	PackageArguments     []indexArgument `json:"packageArguments,omitempty"`
+	EnvironmentVariables []indexInput    `json:"environmentVariables,omitempty"`
+}
+
+// indexRemote is a hosted deployment of the server.
+type indexRemote struct {
+	// Type is the transport of the deployment, eg- streamable-http or sse.
+	Type    string       `json:"type"`
+	URL     string       `json:"url"`
+	Headers []indexInput `json:"headers,omitempty"`
+}
+
+// indexInput is a value the user supplies to run or connect to a server, like an env var or a header.
+type indexInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	IsRequired  bool   `json:"isRequired,omitempty"`
+	IsSecret    bool   `json:"isSecret,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Value       string `json:"value,omitempty"`
+}
+
+// indexArgument is an argument passed to the package of a server.
+type indexArgument struct {
+	indexInput
+	// Type is either positional or named.
+	Type      string `json:"type"`
+	ValueHint string `json:"valueHint,omitempty"`
+}
+
+// ID returns the identifier of the result, to pass to `register --from-search`.
+func (s *indexServer) ID() string {
+	return s.Name
+}
+
+// connection returns the way mcpjungle connects to the server: a remote deployment if it has one,
+// otherwise a package run over stdio. Both are nil if mcpjungle supports none of them.
+func (s *indexServer) connection() (*indexRemote, *indexPackage) {
+	for i := range s.Remotes {
+		if indexRemoteTransport(s.Remotes[i].Type) != "" {
+			return &s.Remotes[i], nil
+		}
+	}
+	for i := range s.Packages {
+		p := &s.Packages[i]
+		if (p.Transport.Type == "" || p.Transport.Type == "stdio") && packageCommand(p) != "" {
+			return nil, p
+		}
+	}
+	return nil, nil
+}
+
+// transport returns the mcpjungle transport the server would be registered with, or "unsupported".
+func (s *indexServer) transport() string {
+	remote, pkg := s.connection()
+	switch {
+	case remote != nil:
+		return string(indexRemoteTransport(remote.Type))
+	case pkg != nil:
+		return string(types.TransportStdio)
+	default:
+		return "unsupported"
+	}
+}
+
+// maintainer returns who maintains the server: the owner of its repository if it is hosted on a well-known forge,
+// otherwise the namespace of its name.
+func (s *indexServer) maintainer() string {
+	if s.Repository != nil {
+		if u, err := url.Parse(s.Repository.URL); err == nil {
+			switch u.Host {
+			case "github.com", "gitlab.com", "bitbucket.org":
+				if owner, _, _ := strings.Cut(strings.Trim(u.Path, "/"), "/"); owner != "" {
+					return owner
+				}
+			}
+		}
+	}
+	namespace, _, _ := strings.Cut(s.Name, "/")
+	return namespace
+}
+
+// indexRemoteTransport maps the transport of a remote deployment to the mcpjungle one, or "" if it isn't supported.
+func indexRemoteTransport(t string) types.McpServerTransport {
+	switch t {
+	case "streamable-http", "streamable_http", "http":
+		return types.TransportStreamableHTTP
+	case "sse":
+		return types.TransportSSE
+	default:
+		return ""
+	}
+}
+
+// packageCommand returns the command running a package, or "" if mcpjungle doesn't know how to run it.
+func packageCommand(p *indexPackage) string {
+	switch p.RegistryType {
+	case "npm":
+		return "npx"
+	case "pypi":
+		return "uvx"
+	case "oci", "docker":
+		return "docker"
+	default:
+		return ""
+	}
+}
+
+// indexSearchResponse is the response of the index to a search.
+// Newer versions of the registry API wrap each server in a "server" field, older ones list them directly.
+type indexSearchResponse struct {
+	Servers []json.RawMessage `json:"servers"`
+}
+
+// indexCache holds the recent searches of an index, keyed by query.
+type indexCache struct {
+	IndexURL string                       `json:"index_url"`
+	Searches map[string]indexCachedSearch `json:"searches"`
+}
+
+type indexCachedSearch struct {
+	FetchedAt time.Time     `json:"fetched_at"`
+	Servers   []indexServer `json:"servers"`
+}
+
+// indexSearch is the outcome of a search of the index.
+type indexSearch struct {
+	Servers []indexServer
+	// Invalid is the number of entries of the index that could not be understood and were left out.
+	Invalid int
+	// Stale is set when the index could not be reached and expired results from the cache are returned instead.
+	Stale error
+}
+
+// searchIndex returns the servers of the index matching the query.
+// Recent results are served from the cache, and expired ones are used if the index can't be reached.
+func searchIndex(ctx context.Context, indexURL, query string, limit int) (*indexSearch, error) {
+	cache := loadIndexCache(indexURL)
+	key := strconv.Itoa(limit) + ":" + query
+	cached, ok := cache.Searches[key]
+	if ok && nowFunc().Sub(cached.FetchedAt) < indexCacheTTL {
+		return &indexSearch{Servers: cached.Servers}, nil
+	}
+
+	res, err := fetchIndex(ctx, indexURL, query, limit)
+	if err != nil {
+		if ok {
+			return &indexSearch{Servers: cached.Servers, Stale: err}, nil
+		}
+		return nil, err
+	}
+
+	cache.Searches[key] = indexCachedSearch{FetchedAt: nowFunc(), Servers: res.Servers}
+	// the cache is only an optimization, failing to write it doesn't fail the search
+	_ = saveIndexCache(cache)
+	return res, nil
+}
+
+// findIndexServer returns the server of the index with the given ID.
+// The results of recent searches are looked up first, so that registering a result doesn't query the index again.
+func findIndexServer(ctx context.Context, indexURL, id string) (*indexServer, error) {
+	cache := loadIndexCache(indexURL)
+	for _, search := range cache.Searches {
+		if nowFunc().Sub(search.FetchedAt) >= indexCacheTTL {
+			continue
+		}
+		for i := range search.Servers {
+			if search.Servers[i].ID() == id {
+				return &search.Servers[i], nil
+			}
+		}
+	}
+
+	res, err := searchIndex(ctx, indexURL, id, 100)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res.Servers {
+		if res.Servers[i].ID() == id {
+			return &res.Servers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("server %s was not found in the index at %s", id, indexURL)
+}
+
+// fetchIndex queries the search endpoint of the index.
+// Entries that are not valid servers are counted and left out, rather than failing the whole search.
+func fetchIndex(ctx context.Context, indexURL, query string, limit int) (*indexSearch, error) {
+	ctx, cancel := context.WithTimeout(ctx, indexRequestTimeout)
+	defer cancel()
+
+	u, err := url.Parse(strings.TrimSuffix(indexURL, "/") + "/v0/servers")
+	if err != nil {
+		return nil, fmt.Errorf("invalid index URL %s: %w", indexURL, err)
+	}
+	q := u.Query()
+	q.Set("search", query)
+	q.Set("version", "latest")
+	q.Set("limit", strconv.Itoa(limit))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the request to the index: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", cliUserAgent())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// %v rather than %w: failing to reach the index must not be reported as failing to reach mcpjungle
+		return nil, fmt.Errorf("failed to reach the index at %s: %v", indexURL, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, indexMaxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response of the index at %s: %v", indexURL, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the index at %s returned %s", indexURL, resp.Status)
+	}
+
+	var body indexSearchResponse
+	if err := json.Unmarshal(data, &body); err != nil || body.Servers == nil {
+		return nil, fmt.Errorf("the index at %s returned an unexpected response, is it an MCP registry?", indexURL)
+	}
+
+	res := &indexSearch{Servers: make([]indexServer, 0, len(body.Servers))}
+	for _, raw := range body.Servers {
+		s, ok := decodeIndexServer(raw)
+		if !ok {
+			res.Invalid++
+			continue
+		}
+		res.Servers = append(res.Servers, *s)
+	}
+	return res, nil
+}
+
+// decodeIndexServer decodes an entry of the index, which is either a server or a server wrapped in a "server" field.
+func decodeIndexServer(raw json.RawMessage) (*indexServer, bool) {
+	var wrapper struct {
+		Server *indexServer `json:"server"`
+	}
+	if err := json.Unmarshal(raw, &wrapper); err != nil {
+		return nil, false
+	}
+	s := wrapper.Server
+	if s == nil {
+		s = &indexServer{}
+		if err := json.Unmarshal(raw, s); err != nil {
+			return nil, false
+		}
+	}
+	if s.Name == "" {
+		return nil, false
+	}
+	return s, true
+}
+
+// indexCachePath returns the path of the file caching the searches of an index.
+func indexCachePath(indexURL string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(indexURL))
+	return filepath.Join(home, config.ConfigDirName, "cache", "index-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// loadIndexCache reads the cached searches of an index on a best-effort basis,
+// an unreadable cache is treated as an empty one.
+func loadIndexCache(indexURL string) *indexCache {
+	cache := &indexCache{IndexURL: indexURL}
+	if path, err := indexCachePath(indexURL); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			_ = json.Unmarshal(data, cache)
+		}
+	}
+	if cache.Searches == nil || cache.IndexURL != indexURL {
+		cache.IndexURL, cache.Searches = indexURL, make(map[string]indexCachedSearch)
+	}
+	return cache
+}
+
+// saveIndexCache writes the cached searches of an index, leaving out the ones that are too old to fall back to.
+func saveIndexCache(cache *indexCache) error {
+	path, err := indexCachePath(cache.IndexURL)
+	if err != nil {
+		return err
+	}
+	for q, search := range cache.Searches {
+		if nowFunc().Sub(search.FetchedAt) >= indexCacheMaxAge {
+			delete(cache.Searches, q)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return errors.New("failed to serialize the index cache")
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/api"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// reloadRestartRequired is the reason changed settings that are only read when the server starts are skipped.
+const reloadRestartRequired = "requires a restart"
+
+// reloadableSettings are the settings a running server applies when it reloads its config file, by setting name.
+// Each one validates the new value of its setting and returns the function that applies it.
+// The other settings are only read when the server starts.
+var reloadableSettings = map[string]func(r *configReloader) (func(), error){
+	APIRateLimitEnvVar: func(r *configReloader) (func(), error) {
+		limit, err := getAPIRateLimit()
+		if err != nil {
+			return nil, err
+		}
+		return func() { r.server.SetAPIRateLimit(limit) }, nil
+	},
+	HealthCheckIntervalSecEnvVar: func(r *configReloader) (func(), error) {
+		interval, err := getHealthCheckInterval()
+		if err != nil {
+			return nil, err
+		}
+		return func() { r.mcpService.SetHealthCheckInterval(interval) }, nil
+	},
+	MaxToolResultBytesEnvVar: func(r *configReloader) (func(), error) {
+		n, err := getMaxToolResultBytes()
+		if err != nil {
+			return nil, err
+		}
+		return func() { r.mcpService.SetMaxToolResultBytes(n) }, nil
+	},
+	PublicURLEnvVar: func(r *configReloader) (func(), error) {
+		publicURL, err := getPublicURL()
+		if err != nil {
+			return nil, err
+		}
+		return func() { r.webhookService.SetStatusURL(statusURLOf(publicURL)) }, nil
+	},
+	ShutdownTimeoutSecEnvVar: func(r *configReloader) (func(), error) {
+		timeout, err := getShutdownTimeout()
+		if err != nil {
+			return nil, err
+		}
+		return func() { r.shutdownTimeout.Store(int64(timeout)) }, nil
+	},
+}
+
+// configReloader applies the changes of the config file of the running server, on SIGHUP
+// or when POST /api/v1/server/reload is called.
+type configReloader struct {
+	// cfg is the configuration the server started with, the flags of the start command still override the file
+	cfg *serverConfig
+
+	server         *api.Server
+	mcpService     *mcp.MCPService
+	webhookService *webhook.WebhookService
+	// shutdownTimeout is read when the server shuts down, as a time.Duration
+	shutdownTimeout atomic.Int64
+
+	// mu serializes the reloads
+	mu sync.Mutex
+}
+
+// newConfigReloader creates the reloader of the server started with cfg.
+// Its server must be set before the first reload.
+func newConfigReloader(cfg *serverConfig, mcpService *mcp.MCPService, w *webhook.WebhookService) *configReloader {
+	r := &configReloader{cfg: cfg, mcpService: mcpService, webhookService: w}
+	r.shutdownTimeout.Store(int64(cfg.ShutdownTimeout))
+	return r
+}
+
+// ShutdownTimeout returns how long the requests in flight are given to complete when the server shuts down.
+func (r *configReloader) ShutdownTimeout() time.Duration {
+	return time.Duration(r.shutdownTimeout.Load())
+}
+
+// reload reads the config file of the server again and applies the settings that changed and can change at runtime.
+// The changed settings that can't are skipped, and keep the value the server started with.
+// If the file can't be read or any changed setting is invalid, nothing is applied.
+func (r *configReloader) reload() *types.ConfigReloadResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := &types.ConfigReloadResult{
+		ConfigFile: getServerConfigFilePath(),
+		Applied:    []types.ConfigSettingChange{},
+		Skipped:    []types.ConfigSettingChange{},
+		Errors:     []string{},
+	}
+	defer logConfigReload(res)
+	if res.ConfigFile == "" {
+		res.Errors = append(res.Errors, fmt.Sprintf(
+			"the server has no config file, start it with --config-file or %s to reload it", ServerConfigFileEnvVar,
+		))
+		return res
+	}
+	values, warnings, err := readServerConfigFile(res.ConfigFile)
+	if err != nil {
+		res.Errors = append(res.Errors, err.Error())
+		return res
+	}
+	for _, w := range warnings {
+		log.Printf("[WARN] %s\n", w)
+	}
+
+	previous := serverConfigFile
+	before := newServerConfigView(r.cfg).Settings
+	serverConfigFile = values
+	after := newServerConfigView(r.cfg).Settings
+
+	var apply []func()
+	for i, s := range serverSettings {
+		change := types.ConfigSettingChange{Key: s.Key, Old: before[i].Value, New: after[i].Value}
+		if change.Old == change.New {
+			continue
+		}
+		prepare, ok := reloadableSettings[s.Name]
+		if !ok {
+			// the running server keeps the value it started with, so that it is reported until it is restarted
+			if v, ok := previous[s.Name]; ok {
+				values[s.Name] = v
+			} else {
+				delete(values, s.Name)
+			}
+			change.Reason = reloadRestartRequired
+			res.Skipped = append(res.Skipped, change)
+			continue
+		}
+		f, err := prepare(r)
+		if err != nil {
+			res.Errors = append(res.Errors, err.Error())
+			continue
+		}
+		apply = append(apply, f)
+		res.Applied = append(res.Applied, change)
+	}
+
+	if len(res.Errors) > 0 {
+		serverConfigFile = previous
+		res.Applied = []types.ConfigSettingChange{}
+		return res
+	}
+	for _, f := range apply {
+		f()
+	}
+	return res
+}
+
+// logConfigReload logs the outcome of a reload of the config file of the server.
+func logConfigReload(res *types.ConfigReloadResult) {
+	if len(res.Errors) > 0 {
+		log.Printf(
+			"[WARN] failed to reload the server config file, the running configuration is unchanged: %s\n",
+			strings.Join(res.Errors, "; "),
+		)
+		return
+	}
+	if len(res.Applied) == 0 && len(res.Skipped) == 0 {
+		log.Printf("[server] reloaded the config file %s, no setting changed\n", res.ConfigFile)
+		return
+	}
+	for _, c := range res.Applied {
+		log.Printf("[server] reloaded %s: '%s' -> '%s'\n", c.Key, c.Old, c.New)
+	}
+	for _, c := range res.Skipped {
+		log.Printf(
+			"[WARN] changing %s from '%s' to '%s' %s, the server keeps using '%s'\n", c.Key, c.Old, c.New, c.Reason, c.Old,
+		)
+	}
+}
+
+// statusURLOf returns the URL of the health endpoint of the server reached at publicURL, empty if it is empty.
+func statusURLOf(publicURL string) string {
+	if publicURL == "" {
+		return ""
+	}
+	return publicURL + "/api/v1/servers/health"
+}
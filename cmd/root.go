@@ -18,11 +18,17 @@ Missing annotations will cause groupCommands() to return an error.
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
 	"sort"
 	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/mcpjungle/mcpjungle/client"
 	"github.com/mcpjungle/mcpjungle/cmd/config"
@@ -61,6 +67,30 @@ var ErrSilent = errors.New("SilentErr")
 
 var registryServerURL string
 
+// verbosity is set by the global --verbose flag, it can be repeated (-vv) to increase the level of detail.
+var verbosity int
+
+// Retries of requests that failed with a transient error are controlled by the global --retries and --no-retry flags.
+var (
+	maxRetriesFlag int
+	noRetryFlag    bool
+)
+
+// requestTimeoutFlag is set by the global --timeout flag.
+var requestTimeoutFlag time.Duration
+
+// defaultRequestTimeout is the deadline of every API request made by a command when --timeout is not set.
+const defaultRequestTimeout = client.DefaultTimeout
+
+// timeoutAnnotation overrides the default timeout of a command, eg- "5m" for commands that are expected to be slow.
+// The requests whose responses are streamed, eg- the dump of every tool, are bounded by the timeout until their
+// response starts only, the client reads them for as long as it takes.
+const timeoutAnnotation = "timeout"
+
+// activeRequestTimeout is the timeout applied to the requests of the running command, 0 if disabled.
+// It is used to explain timeout errors.
+var activeRequestTimeout time.Duration
+
 // apiClient is the global API client used by command handlers to interact with the MCPJungle registry server.
 // It is not the best choice to rely on a global variable, but cobra doesn't seem to provide any neat way to
 // pass an object down the command tree.
@@ -107,39 +137,216 @@ func Execute() error {
 		"http://127.0.0.1:"+BindPortDefault,
 		"Base URL of the MCPJungle registry server",
 	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&assumeYes,
+		"yes",
+		"y",
+		false,
+		"Skip confirmation prompts for destructive commands (required when stdin is not a terminal)",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&contextOverride,
+		"context",
+		"",
+		"Name of the CLI context to use for this command (overrides the current context)",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&outputFormatFlag,
+		"output",
+		outputFormatTable,
+		"Output format, one of: table, json, name (names only, one per line) or template=<go template> (executed for every item)",
+	)
+
+	rootCmd.PersistentFlags().CountVarP(
+		&verbosity,
+		"verbose",
+		"v",
+		"Log HTTP requests made by the CLI to stderr (-v), including headers and bodies with secrets redacted (-vv)",
+	)
+	rootCmd.PersistentFlags().IntVar(
+		&maxRetriesFlag,
+		"retries",
+		client.DefaultMaxRetries,
+		"Number of times to retry read requests that fail with a connection error, a timeout or a 502/503/504 response",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&noRetryFlag,
+		"no-retry",
+		false,
+		"Disable retries of failed requests",
+	)
+	rootCmd.PersistentFlags().DurationVar(
+		&requestTimeoutFlag,
+		"timeout",
+		defaultRequestTimeout,
+		"Deadline of every request made to the server, retries included (eg- 10s, 2m, 0 to disable). "+
+			"invoke defaults to "+invokeDefaultTimeout.String(),
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&caCertFlag,
+		"ca-cert",
+		"",
+		"Path to a PEM file with the CA certificates to trust for the registry's TLS certificate, eg- of a private CA",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&clientCertFlag,
+		"client-cert",
+		"",
+		"Path to the PEM encoded client certificate to present to a registry that requires mutual TLS",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&clientKeyFlag,
+		"client-key",
+		"",
+		"Path to the PEM encoded key of the client certificate",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&insecureSkipTLSVerifyFlag,
+		"insecure-skip-tls-verify",
+		false,
+		"Do not verify the registry's TLS certificate. This makes the connection insecure, only use it for testing",
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&quietFlag,
+		"quiet",
+		"q",
+		false,
+		"Only print the result of a command (or errors), suppress informational messages",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&noColorFlag,
+		"no-color",
+		false,
+		"Disable colored output (colors are also disabled when NO_COLOR is set or output is not a terminal)",
+	)
 
-	// Initialize the API client with the registry server URL & client configuration (if any)
-	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
-		cfg := config.Load()
-
-		// determine the registry server URL to use
-		// precedence: command line flag explicitly set by user > config file > flag default value
-		var u string
-		if cmd.Flags().Changed("registry") {
-			u = registryServerURL
-
-			// if the user explicitly set the --registry flag, but the config file doesn't have
-			// a registry_url entry, print a tip to let them know they can set it in the config file
-			if cfg.RegistryURL == "" {
-				if cfgFilePath, err := config.AbsPath(); err == nil {
-					cmd.Printf(
-						"TIP: You can set `registry_url: %s` in %s to avoid setting the --registry flag every time.\n\n",
+	// Initialize the API client with the connection settings resolved from flags, env vars and the active context
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		cfgFile, err := config.LoadFile()
+		if err != nil {
+			// a broken config file should not prevent the user from running commands (eg- to fix it)
+			newPrinter(cmd).Warnf("ignoring client configuration: %v\n", err)
+			cfgFile = &config.File{}
+		}
+
+		settings, err := resolveCLISettings(cmd, cfgFile)
+		if err != nil {
+			return err
+		}
+		activeSettings = settings
+
+		// if the user explicitly set the --registry flag, but their context doesn't have
+		// a registry_url entry, print a tip to let them know they can set it in the config file
+		// (context subcommands define their own --registry flag, the tip is not relevant for them)
+		if settings.RegistryURLSource == settingSourceFlag && cmd.Parent() != contextCmd {
+			if ctx := cfgFile.Current(); ctx == nil || ctx.RegistryURL == "" {
+				if cfgFilePath, err := config.FilePath(); err == nil {
+					newPrinter(cmd).Infof(
+						"TIP: You can set `registry_url: %s` in your context in %s to avoid setting the --registry flag every time.\n\n",
 						registryServerURL,
 						cfgFilePath,
 					)
 				}
 			}
+		}
 
-		} else if cfg.RegistryURL != "" {
-			u = cfg.RegistryURL
-		} else {
-			u = registryServerURL
+		if verbosity > 0 {
+			newPrinter(cmd).Debugf(
+				"Using context %s with registry %s (from %s)\n",
+				describeContext(settings), settings.RegistryURL, settings.RegistryURLSource,
+			)
+		}
+		c, err := newAPIClient(cmd, settings.RegistryURL, settings.AccessToken, settings.TLS)
+		if err != nil {
+			return err
 		}
+		apiClient = c
+		return nil
+	}
+
+	// Ctrl-C cancels the context of the command, which aborts its in-flight requests.
+	// Once cancelled, the handler is removed so that a second Ctrl-C kills the CLI right away.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
+
+	return rootCmd.ExecuteContext(ctx)
+}
 
-		apiClient = client.NewClient(u, cfg.AccessToken, http.DefaultClient)
+// newAPIClient returns the client used to talk to the registry, configured by the global flags:
+// requests are bounded by --timeout, logged in verbose mode, and retried on transient failures unless --no-retry is passed.
+// Connections use the TLS settings of tlsOpts.
+func newAPIClient(cmd *cobra.Command, registryURL, accessToken string, tlsOpts client.TLSOptions) (*client.Client, error) {
+	timeout, err := requestTimeout(cmd)
+	if err != nil {
+		return nil, err
 	}
+	activeRequestTimeout = timeout
 
-	return rootCmd.Execute()
+	if maxRetriesFlag < 0 {
+		return nil, usageErrorf("--retries must not be negative")
+	}
+	if noRetryFlag && cmd.Flags().Changed("retries") {
+		return nil, usageErrorf("--no-retry cannot be used together with --retries")
+	}
+
+	var transport http.RoundTripper = http.DefaultTransport
+	if !tlsOpts.IsZero() {
+		tlsConfig, err := tlsOpts.Config()
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS settings: %w", err)
+		}
+		if tlsOpts.InsecureSkipVerify {
+			newPrinter(cmd).Warnf(
+				"TLS certificate verification is disabled (--insecure-skip-tls-verify), " +
+					"the connection to the registry is not secure",
+			)
+		}
+		t := transport.(*http.Transport).Clone()
+		t.TLSClientConfig = tlsConfig
+		transport = t
+	}
+	opts := []client.Option{
+		client.WithTimeout(timeout),
+		client.WithUserAgent(cliUserAgent()),
+	}
+	if verbosity > 0 {
+		logOut := syncedWriter{w: cmd.ErrOrStderr()}
+		transport = client.NewLoggingTransport(transport, logOut, verbosity)
+		opts = append(opts, client.WithRetryLog(logOut))
+	}
+	retries := maxRetriesFlag
+	if noRetryFlag {
+		retries = 0
+	}
+	opts = append(opts, client.WithTransport(transport), client.WithRetries(retries))
+
+	return client.New(registryURL, accessToken, opts...), nil
+}
+
+// cliUserAgent is the User-Agent of the requests made by the CLI, eg- mcpjungle-cli/0.2.0 (linux/amd64).
+func cliUserAgent() string {
+	return fmt.Sprintf("mcpjungle-cli/%s (%s/%s)", version.GetVersion(), runtime.GOOS, runtime.GOARCH)
+}
+
+// requestTimeout returns the timeout of the requests made by cmd: the --timeout flag if it was set,
+// otherwise the command's own default, if any, or the global one.
+func requestTimeout(cmd *cobra.Command) (time.Duration, error) {
+	if cmd.Flags().Changed("timeout") {
+		if requestTimeoutFlag < 0 {
+			return 0, usageErrorf("--timeout must not be negative")
+		}
+		return requestTimeoutFlag, nil
+	}
+	if v, ok := cmd.Annotations[timeoutAnnotation]; ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid default timeout '%s' of command %s: %w", v, cmd.Name(), err)
+		}
+		return d, nil
+	}
+	return defaultRequestTimeout, nil
 }
 
 // displayRootCmdHelpMsg displays custom help message for the root command, ie,
@@ -163,6 +370,7 @@ func displayRootCmdHelpMsg(cmd *cobra.Command) {
 	cmd.Println("Flags:")
 	cmd.Print(cmd.LocalFlags().FlagUsages())
 	cmd.Printf("Use \"%s [command] --help\" for more information about a command.\n", cmd.CommandPath())
+	cmd.Printf("Use \"%s help exit-codes\" to see what the exit codes of the CLI mean.\n", cmd.CommandPath())
 }
 
 // customHelpFunc returns a help function that shows appropriate help content.
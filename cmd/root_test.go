@@ -1,7 +1,19 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/spf13/cobra"
 )
 
 func TestRootCommandStructure(t *testing.T) {
@@ -12,3 +24,124 @@ func TestRootCommandStructure(t *testing.T) {
 		t.Errorf("Expected root command Short to be 'MCP Gateway for AI Agents', got %s", rootCmd.Short)
 	}
 }
+
+func newTestAPIClient(cmd *cobra.Command) (*http.Client, error) {
+	c, err := newAPIClient(cmd, "http://127.0.0.1:8080", "", client.TLSOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return c.HTTPClient(), nil
+}
+
+func TestNewAPIClientRetries(t *testing.T) {
+	origRetries, origNoRetry, origVerbosity := maxRetriesFlag, noRetryFlag, verbosity
+	t.Cleanup(func() { maxRetriesFlag, noRetryFlag, verbosity = origRetries, origNoRetry, origVerbosity })
+	verbosity = 0
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().IntVar(&maxRetriesFlag, "retries", client.DefaultMaxRetries, "")
+		cmd.Flags().BoolVar(&noRetryFlag, "no-retry", false, "")
+		return cmd
+	}
+
+	cmd := newCmd()
+	httpClient, err := newTestAPIClient(cmd)
+	testhelpers.AssertNoError(t, err)
+	_, ok := httpClient.Transport.(*client.RetryTransport)
+	testhelpers.AssertTrue(t, ok, "requests should be retried by default")
+
+	cmd = newCmd()
+	testhelpers.AssertNoError(t, cmd.Flags().Set("no-retry", "true"))
+	httpClient, err = newTestAPIClient(cmd)
+	testhelpers.AssertNoError(t, err)
+	_, ok = httpClient.Transport.(*client.RetryTransport)
+	testhelpers.AssertFalse(t, ok, "--no-retry should disable retries")
+
+	testhelpers.AssertNoError(t, cmd.Flags().Set("retries", "3"))
+	_, err = newTestAPIClient(cmd)
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+
+	cmd = newCmd()
+	testhelpers.AssertNoError(t, cmd.Flags().Set("retries", "-1"))
+	_, err = newTestAPIClient(cmd)
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+}
+
+func TestNewAPIClientTimeout(t *testing.T) {
+	origTimeout, origActive, origVerbosity := requestTimeoutFlag, activeRequestTimeout, verbosity
+	t.Cleanup(func() { requestTimeoutFlag, activeRequestTimeout, verbosity = origTimeout, origActive, origVerbosity })
+	verbosity = 0
+
+	newCmd := func(annotations map[string]string) *cobra.Command {
+		cmd := &cobra.Command{Annotations: annotations}
+		cmd.Flags().DurationVar(&requestTimeoutFlag, "timeout", defaultRequestTimeout, "")
+		cmd.Flags().IntVar(&maxRetriesFlag, "retries", client.DefaultMaxRetries, "")
+		cmd.Flags().BoolVar(&noRetryFlag, "no-retry", false, "")
+		return cmd
+	}
+
+	httpClient, err := newTestAPIClient(newCmd(nil))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, defaultRequestTimeout, httpClient.Timeout)
+
+	t.Run("commands can have their own default", func(t *testing.T) {
+		cmd := newCmd(map[string]string{timeoutAnnotation: "5m"})
+		httpClient, err := newTestAPIClient(cmd)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 5*time.Minute, httpClient.Timeout)
+
+		testhelpers.AssertNoError(t, cmd.Flags().Set("timeout", "10s"))
+		httpClient, err = newTestAPIClient(cmd)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 10*time.Second, httpClient.Timeout)
+		testhelpers.AssertEqual(t, 10*time.Second, activeRequestTimeout)
+	})
+
+	t.Run("negative timeout", func(t *testing.T) {
+		cmd := newCmd(nil)
+		testhelpers.AssertNoError(t, cmd.Flags().Set("timeout", "-1s"))
+		_, err := newTestAPIClient(cmd)
+		testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+	})
+}
+
+func TestNewAPIClientTLS(t *testing.T) {
+	origVerbosity := verbosity
+	t.Cleanup(func() { verbosity = origVerbosity })
+	verbosity = 0
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ready":true,"initialized":true}`))
+	}))
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	testhelpers.AssertNoError(t, os.WriteFile(
+		caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600,
+	))
+
+	newCmd := func() (*cobra.Command, *bytes.Buffer) {
+		var stderr bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetErr(&stderr)
+		return cmd, &stderr
+	}
+
+	cmd, stderr := newCmd()
+	c, err := newAPIClient(cmd, server.URL, "", client.TLSOptions{CACertFile: caFile})
+	testhelpers.AssertNoError(t, err)
+	_, err = c.GetServerReadiness(context.Background())
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "", stderr.String())
+
+	cmd, stderr = newCmd()
+	c, err = newAPIClient(cmd, server.URL, "", client.TLSOptions{InsecureSkipVerify: true})
+	testhelpers.AssertNoError(t, err)
+	_, err = c.GetServerReadiness(context.Background())
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertStringContains(t, stderr.String(), "WARNING: TLS certificate verification is disabled")
+
+	cmd, _ = newCmd()
+	_, err = newAPIClient(cmd, server.URL, "", client.TLSOptions{CACertFile: filepath.Join(t.TempDir(), "missing.pem")})
+	testhelpers.AssertError(t, err)
+}
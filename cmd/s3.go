@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/s3"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// S3AccessKeyIDEnvVar and S3SecretAccessKeyEnvVar are the env vars for static credentials of the object store,
+	// eg- the API token of an R2 bucket. The default credential chain of AWS is used if they are not set.
+	S3AccessKeyIDEnvVar     = "MCPJUNGLE_S3_ACCESS_KEY_ID"
+	S3SecretAccessKeyEnvVar = "MCPJUNGLE_S3_SECRET_ACCESS_KEY"
+)
+
+// defaultBundleObjectName is the name of the object exported to an S3 URL that ends with a /.
+const defaultBundleObjectName = "mcpjungle-export-{timestamp}.tar.gz"
+
+// s3ServerSideEncryptions are the supported values of --s3-sse.
+var s3ServerSideEncryptions = []string{"AES256", "aws:kms", "aws:kms:dsse"}
+
+// s3Flags are the flags configuring the connection to an S3-compatible object store.
+type s3Flags struct {
+	url         string
+	endpoint    string
+	region      string
+	sse         string
+	sseKMSKeyID string
+}
+
+// addS3Flags adds the flags of the connection to the object store to flags, with the given usage of --s3-url.
+// The encryption flags are only added for uploads.
+func addS3Flags(flags *pflag.FlagSet, f *s3Flags, urlUsage string, upload bool) {
+	flags.StringVar(&f.url, "s3-url", "", urlUsage)
+	flags.StringVar(
+		&f.endpoint,
+		"s3-endpoint",
+		"",
+		"Endpoint of an S3-compatible object store, eg- MinIO or R2 (default: AWS_ENDPOINT_URL_S3, or AWS S3)",
+	)
+	flags.StringVar(&f.region, "s3-region", "", "Region of the bucket (default: AWS_REGION, or us-east-1)")
+	if upload {
+		flags.StringVar(
+			&f.sse,
+			"s3-sse",
+			"",
+			"Server-side encryption of the uploaded object, one of: "+strings.Join(s3ServerSideEncryptions, ", "),
+		)
+		flags.StringVar(
+			&f.sseKMSKeyID, "s3-sse-kms-key-id", "", "KMS key the object is encrypted with (requires --s3-sse aws:kms)",
+		)
+	}
+}
+
+// newS3Client creates the client of the object store configured by the flags.
+func newS3Client(ctx context.Context, f *s3Flags) (*s3.Client, error) {
+	if f.sse != "" && !slices.Contains(s3ServerSideEncryptions, f.sse) {
+		return nil, usageErrorf(
+			"unsupported server-side encryption '%s', must be one of: %s", f.sse, strings.Join(s3ServerSideEncryptions, ", "),
+		)
+	}
+	if f.sseKMSKeyID != "" && !strings.HasPrefix(f.sse, "aws:kms") {
+		return nil, usageErrorf("--s3-sse-kms-key-id requires --s3-sse aws:kms or aws:kms:dsse")
+	}
+
+	cfg := s3.Config{
+		Region:               f.region,
+		Endpoint:             f.endpoint,
+		ServerSideEncryption: f.sse,
+		SSEKMSKeyID:          f.sseKMSKeyID,
+	}
+	id, secret := os.Getenv(S3AccessKeyIDEnvVar), os.Getenv(S3SecretAccessKeyEnvVar)
+	if (id == "") != (secret == "") {
+		return nil, usageErrorf("%s and %s must be set together", S3AccessKeyIDEnvVar, S3SecretAccessKeyEnvVar)
+	}
+	if id != "" {
+		cfg.Credentials = &s3.Credentials{AccessKeyID: id, SecretAccessKey: secret}
+	}
+	return s3.NewClient(ctx, cfg)
+}
+
+// bundleObjectKey returns the key an export is uploaded to: the key of the URL, or the default object name
+// under it when it is a prefix ending with a /. {timestamp} is replaced with the current time.
+func bundleObjectKey(key string, now time.Time) string {
+	if key == "" || strings.HasSuffix(key, "/") {
+		key += defaultBundleObjectName
+	}
+	return strings.ReplaceAll(key, "{timestamp}", now.UTC().Format("20060102T150405Z"))
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// withTestObjectStore points the S3 flags to an in-memory object store, the objects are keyed by their path.
+func withTestObjectStore(t *testing.T, f *s3Flags) map[string][]byte {
+	t.Helper()
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			objects[r.URL.Path], _ = io.ReadAll(r.Body)
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>The key does not exist.</Message></Error>`)
+				return
+			}
+			_, _ = w.Write(data)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	orig := *f
+	t.Cleanup(func() { *f = orig })
+	f.endpoint, f.region = srv.URL, "auto"
+	t.Setenv(S3AccessKeyIDEnvVar, "id")
+	t.Setenv(S3SecretAccessKeyEnvVar, "secret")
+	return objects
+}
+
+func TestBundleObjectKey(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	testhelpers.AssertEqual(t, "mcpjungle-export-20250301T103000Z.tar.gz", bundleObjectKey("", now))
+	testhelpers.AssertEqual(t, "nightly/mcpjungle-export-20250301T103000Z.tar.gz", bundleObjectKey("nightly/", now))
+	testhelpers.AssertEqual(t, "prod-{x}.tar.gz", bundleObjectKey("prod-{x}.tar.gz", now))
+}
+
+func TestNewS3ClientValidatesFlags(t *testing.T) {
+	_, err := newS3Client(t.Context(), &s3Flags{sse: "aes"})
+	testhelpers.AssertStringContains(t, err.Error(), "unsupported server-side encryption 'aes'")
+
+	_, err = newS3Client(t.Context(), &s3Flags{sse: "AES256", sseKMSKeyID: "key"})
+	testhelpers.AssertStringContains(t, err.Error(), "--s3-sse-kms-key-id requires --s3-sse aws:kms")
+
+	t.Setenv(S3AccessKeyIDEnvVar, "id")
+	t.Setenv(S3SecretAccessKeyEnvVar, "")
+	_, err = newS3Client(t.Context(), &s3Flags{})
+	testhelpers.AssertStringContains(t, err.Error(), "must be set together")
+}
+
+func TestExportAndImportThroughS3(t *testing.T) {
+	objects := withTestObjectStore(t, &exportCmdS3)
+	origNow := nowFunc
+	t.Cleanup(func() { nowFunc = origNow })
+	nowFunc = func() time.Time { return time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC) }
+
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{Name: "ops", IncludedServers: []string{"github"}}})
+		},
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []*types.RegisterServerInput{
+				{Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/"},
+			})
+		},
+	})
+	exportCmdS3.url = "s3://backups/nightly/"
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	testhelpers.AssertNoError(t, runExport(cmd, nil))
+	testhelpers.AssertStringContains(
+		t, out.String(), "Exported 1 tool groups and 1 MCP servers to s3://backups/nightly/mcpjungle-export-20250301T103000Z.tar.gz",
+	)
+
+	bundle, err := readBundleArchive(bytes.NewReader(objects["/backups/nightly/mcpjungle-export-20250301T103000Z.tar.gz"]))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "ops", bundle.Groups[0].Name)
+	testhelpers.AssertEqual(t, "github", bundle.Servers[0].Name)
+
+	// the bundle is imported into a server that already has the group
+	importObjects := withTestObjectStore(t, &importBundleS3)
+	importObjects["/backups/export.tar.gz"] = objects["/backups/nightly/mcpjungle-export-20250301T103000Z.tar.gz"]
+	var registered, createdGroups []string
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []*types.McpServer{})
+		},
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{Name: "ops"}})
+		},
+		"POST /api/v1/servers": func(w http.ResponseWriter, r *http.Request) {
+			var input types.RegisterServerInput
+			_ = json.NewDecoder(r.Body).Decode(&input)
+			registered = append(registered, input.Name)
+			writeTestJSON(w, http.StatusCreated, &types.McpServer{Name: input.Name, Transport: input.Transport})
+		},
+		"POST /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			createdGroups = append(createdGroups, "ops")
+			writeTestJSON(w, http.StatusCreated, &types.CreateToolGroupResponse{})
+		},
+	})
+	origUpsert, origDryRun := importBundleUpsert, importBundleDryRun
+	t.Cleanup(func() { importBundleUpsert, importBundleDryRun = origUpsert, origDryRun })
+	importBundleUpsert, importBundleDryRun = false, false
+	importBundleS3.url = "s3://backups/export.tar.gz"
+
+	out.Reset()
+	testhelpers.AssertNoError(t, runImportBundle(cmd, nil))
+	testhelpers.AssertEqual(t, 1, len(registered))
+	testhelpers.AssertEqual(t, 0, len(createdGroups))
+	testhelpers.AssertStringContains(t, out.String(), "already exists, use --upsert to update it")
+
+	importBundleS3.url = "s3://backups/missing.tar.gz"
+	err = runImportBundle(cmd, nil)
+	testhelpers.AssertStringContains(t, err.Error(), "NoSuchKey")
+}
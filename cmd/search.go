@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var searchRegistryLimit int
+
+var searchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Search for MCP servers to register",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "20",
+	},
+}
+
+var searchRegistryCmd = &cobra.Command{
+	Use:   "registry [query]",
+	Short: "Search an index of MCP servers, like the public MCP registry",
+	Long: "Search an index of MCP servers for the ones whose name matches the query.\n" +
+		"The public MCP registry (" + defaultIndexURL + ") is searched by default.\n" +
+		"To search a private registry implementing the same API instead, set `index_url` in your context,\n" +
+		"the " + IndexURLEnvVar + " env var or the --index flag.\n\n" +
+		"Results are cached for a few minutes. If the index can't be reached, older cached results are shown.\n" +
+		"Register a result with `mcpjungle register --from-search <name>`.",
+	Example: "  mcpjungle search registry github\n" +
+		"  mcpjungle register --from-search io.github.github/github-mcp-server",
+	Args: cobra.ExactArgs(1),
+	RunE: runSearchRegistry,
+}
+
+func init() {
+	searchRegistryCmd.Flags().StringVar(
+		&indexURLFlag,
+		"index",
+		"",
+		"Base URL of the index of MCP servers to search (default: the index_url of the context, or the public MCP registry)",
+	)
+	searchRegistryCmd.Flags().IntVar(
+		&searchRegistryLimit,
+		"limit",
+		20,
+		"Maximum number of results",
+	)
+
+	searchCmd.AddCommand(searchRegistryCmd)
+	rootCmd.AddCommand(searchCmd)
+}
+
+// searchResult is a server found in the index, as printed by `search registry`.
+// Its name is the ID to pass to `register --from-search`.
+type searchResult struct {
+	ID          string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description"`
+	Version     string `json:"version,omitempty"`
+	Transport   string `json:"transport"`
+	Maintainer  string `json:"maintainer"`
+}
+
+var searchResultColumns = []tableColumn[searchResult]{
+	{name: "name", value: func(r searchResult) string { return r.ID }},
+	{name: "transport", value: func(r searchResult) string { return r.Transport }},
+	{name: "maintainer", value: func(r searchResult) string { return r.Maintainer }},
+	{name: "description", value: func(r searchResult) string { return r.Description }},
+}
+
+func runSearchRegistry(cmd *cobra.Command, args []string) error {
+	if searchRegistryLimit < 1 {
+		return usageErrorf("--limit must be at least 1")
+	}
+	p := newPrinter(cmd)
+	indexURL := activeIndexURL()
+
+	pr := p.Progress(fmt.Sprintf("Searching %s", indexURL))
+	res, err := searchIndex(commandContext(cmd), indexURL, args[0], searchRegistryLimit)
+	pr.Stop()
+	if err != nil {
+		return err
+	}
+	if res.Stale != nil {
+		p.Warnf("%v, showing results cached earlier", res.Stale)
+	}
+	if res.Invalid > 0 {
+		p.Warnf("%d entries returned by the index could not be understood and were left out", res.Invalid)
+	}
+
+	results := make([]searchResult, 0, len(res.Servers))
+	for i := range res.Servers {
+		s := &res.Servers[i]
+		results = append(results, searchResult{
+			ID:          s.ID(),
+			Title:       s.Title,
+			Description: s.Description,
+			Version:     s.Version,
+			Transport:   s.transport(),
+			Maintainer:  s.maintainer(),
+		})
+	}
+	if isStructuredOutput() {
+		return printOutput(cmd, results)
+	}
+
+	if len(results) == 0 {
+		p.Infof("No MCP servers matching '%s' were found in %s\n", args[0], indexURL)
+		return nil
+	}
+	if err := renderTable(cmd.OutOrStdout(), searchResultColumns, results); err != nil {
+		return err
+	}
+	p.Infoln("\nRegister a server with `mcpjungle register --from-search <name>`")
+	return nil
+}
+
+// activeIndexURL returns the index of MCP servers used by the running command.
+func activeIndexURL() string {
+	if activeSettings == nil || activeSettings.IndexURL == "" {
+		return defaultIndexURL
+	}
+	return activeSettings.IndexURL
+}
+
+// invalidServerNameChars matches the characters that are not allowed in the name of an MCP server.
+var invalidServerNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// indexServerName derives the name an index server is registered with from its ID,
+// eg- io.github.github/github-mcp-server becomes github-mcp-server.
+func indexServerName(id string) string {
+	name := id[strings.LastIndex(id, "/")+1:]
+	name = invalidServerNameChars.ReplaceAllString(name, "-")
+	return strings.Trim(name, "-_")
+}
+
+// registerInputFromSearch converts a server of the index into its registration.
+// The credentials and other values the server requires are asked from the user, which requires a terminal.
+func registerInputFromSearch(cmd *cobra.Command, s *indexServer, name string) (*types.RegisterServerInput, error) {
+	if name == "" {
+		name = indexServerName(s.ID())
+	}
+	input := &types.RegisterServerInput{Name: name, Description: s.Description}
+	p := newPrompter(cmd.InOrStdin(), cmd.ErrOrStderr())
+
+	remote, pkg := s.connection()
+	switch {
+	case remote != nil:
+		input.Transport = string(indexRemoteTransport(remote.Type))
+		input.URL = remote.URL
+		for _, h := range remote.Headers {
+			if !strings.EqualFold(h.Name, "Authorization") {
+				if h.IsRequired {
+					return nil, fmt.Errorf("server %s requires the %s header, mcpjungle can't send it", s.ID(), h.Name)
+				}
+				continue
+			}
+			value, err := indexInputValue(p, s, h, "bearer token")
+			if err != nil {
+				return nil, err
+			}
+			input.BearerToken = strings.TrimSpace(strings.TrimPrefix(value, "Bearer "))
+		}
+	case pkg != nil:
+		input.Transport = string(types.TransportStdio)
+		input.Command = packageCommand(pkg)
+		args, err := packageArgs(p, s, pkg)
+		if err != nil {
+			return nil, err
+		}
+		input.Args = args
+		for _, env := range pkg.EnvironmentVariables {
+			value, err := indexInputValue(p, s, env, "env var "+env.Name)
+			if err != nil {
+				return nil, err
+			}
+			if value != "" {
+				if input.Env == nil {
+					input.Env = make(map[string]string)
+				}
+				input.Env[env.Name] = value
+			}
+		}
+	default:
+		return nil, fmt.Errorf(
+			"server %s can't be registered: none of its packages or remotes use a transport mcpjungle supports", s.ID(),
+		)
+	}
+	return input, nil
+}
+
+// packageArgs returns the arguments of the command running a package: the package itself, pinned to its version,
+// followed by its own arguments.
+func packageArgs(p *prompter, s *indexServer, pkg *indexPackage) ([]string, error) {
+	var args []string
+	switch pkg.RegistryType {
+	case "npm":
+		id := pkg.Identifier
+		if pkg.Version != "" {
+			id += "@" + pkg.Version
+		}
+		args = []string{"-y", id}
+	case "pypi":
+		id := pkg.Identifier
+		if pkg.Version != "" {
+			id += "==" + pkg.Version
+		}
+		args = []string{id}
+	default:
+		// the env vars of the server must be passed to its container explicitly
+		args = []string{"run", "-i", "--rm"}
+		for _, env := range pkg.EnvironmentVariables {
+			args = append(args, "-e", env.Name)
+		}
+		id := pkg.Identifier
+		if pkg.Version != "" && !strings.Contains(id[strings.LastIndex(id, "/")+1:], ":") {
+			id += ":" + pkg.Version
+		}
+		args = append(args, id)
+	}
+
+	for _, a := range pkg.PackageArguments {
+		label := a.Name
+		if label == "" {
+			label = a.ValueHint
+		}
+		value, err := indexInputValue(p, s, a.indexInput, "argument "+label)
+		if err != nil {
+			return nil, err
+		}
+		if value == "" {
+			continue
+		}
+		if a.Type == "named" {
+			args = append(args, a.Name)
+		}
+		args = append(args, value)
+	}
+	return args, nil
+}
+
+// indexInputValue returns the value of an input of a server: its fixed value or default if it has one,
+// otherwise the answer of the user if it is required. Optional inputs without a default are left empty.
+// Values with {placeholders}, eg- "Bearer {token}", are templates that the user has to fill in.
+func indexInputValue(p *prompter, s *indexServer, in indexInput, label string) (string, error) {
+	if in.Value != "" && !strings.Contains(in.Value, "{") {
+		return in.Value, nil
+	}
+	if in.Default != "" || !in.IsRequired {
+		return in.Default, nil
+	}
+	if !stdinIsTerminal() {
+		return "", fmt.Errorf("server %s requires the %s, run the command in a terminal to enter it", s.ID(), label)
+	}
+
+	question := "Value of the " + label
+	if in.Description != "" {
+		question += " (" + in.Description + ")"
+	}
+	if in.IsSecret {
+		return p.askSecret(question + ", input is hidden")
+	}
+	return p.ask(question, "", validateRequired(label))
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+const testIndexResponse = `{
+  "servers": [
+    {
+      "server": {
+        "name": "io.github.github/github-mcp-server",
+        "description": "Connect AI assistants to GitHub",
+        "version": "0.20.1",
+        "repository": {"url": "https://github.com/github/github-mcp-server", "source": "github"},
+        "remotes": [{
+          "type": "streamable-http",
+          "url": "https://api.githubcopilot.com/mcp/",
+          "headers": [{"name": "Authorization", "value": "Bearer {token}", "isRequired": true, "isSecret": true}]
+        }]
+      },
+      "_meta": {"io.modelcontextprotocol.registry/official": {"isLatest": true}}
+    },
+    {
+      "name": "io.github.acme/weather",
+      "description": "Weather forecasts",
+      "version": "1.2.0",
+      "packages": [{
+        "registryType": "npm",
+        "identifier": "@acme/weather-mcp",
+        "version": "1.2.0",
+        "transport": {"type": "stdio"},
+        "packageArguments": [{"type": "named", "name": "--units", "default": "metric"}],
+        "environmentVariables": [
+          {"name": "WEATHER_API_KEY", "isRequired": true, "isSecret": true},
+          {"name": "WEATHER_REGION"}
+        ]
+      }]
+    },
+    {"server": {"name": "com.example/chat", "packages": [{"registryType": "nuget", "identifier": "Chat"}]}},
+    {"description": "an entry without a name"},
+    "not a server"
+  ]
+}`
+
+// withTestIndex serves testIndexResponse as an index of MCP servers, counting the searches it receives.
+func withTestIndex(t *testing.T) (*httptest.Server, *int) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	searches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0/servers" {
+			http.NotFound(w, r)
+			return
+		}
+		searches++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testIndexResponse))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &searches
+}
+
+func TestSearchIndex(t *testing.T) {
+	index, searches := withTestIndex(t)
+	indexURL := index.URL
+	ctx := context.Background()
+
+	res, err := searchIndex(ctx, indexURL, "github", 20)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 3, len(res.Servers))
+	testhelpers.AssertEqual(t, 2, res.Invalid)
+
+	github, weather, chat := res.Servers[0], res.Servers[1], res.Servers[2]
+	testhelpers.AssertEqual(t, "io.github.github/github-mcp-server", github.ID())
+	testhelpers.AssertEqual(t, string(types.TransportStreamableHTTP), github.transport())
+	testhelpers.AssertEqual(t, "github", github.maintainer())
+	testhelpers.AssertEqual(t, string(types.TransportStdio), weather.transport())
+	testhelpers.AssertEqual(t, "io.github.acme", weather.maintainer())
+	testhelpers.AssertEqual(t, "unsupported", chat.transport())
+
+	// recent results are served from the cache
+	_, err = searchIndex(ctx, indexURL, "github", 20)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, *searches)
+	s, err := findIndexServer(ctx, indexURL, "io.github.acme/weather")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "Weather forecasts", s.Description)
+	testhelpers.AssertEqual(t, 1, *searches)
+}
+
+func TestSearchIndexUnavailable(t *testing.T) {
+	index, searches := withTestIndex(t)
+	ctx := context.Background()
+	_, err := searchIndex(ctx, index.URL, "github", 20)
+	testhelpers.AssertNoError(t, err)
+
+	origNow := nowFunc
+	t.Cleanup(func() { nowFunc = origNow })
+
+	// expired results are searched again
+	nowFunc = func() time.Time { return time.Now().Add(indexCacheTTL) }
+	_, err = searchIndex(ctx, index.URL, "github", 20)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 2, *searches)
+
+	// and used when the index can't be reached
+	index.Close()
+	nowFunc = func() time.Time { return time.Now().Add(3 * indexCacheTTL) }
+	res, err := searchIndex(ctx, index.URL, "github", 20)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, res.Stale != nil, "the results should be marked as stale")
+	testhelpers.AssertEqual(t, 3, len(res.Servers))
+
+	_, err = searchIndex(ctx, index.URL, "slack", 20)
+	testhelpers.AssertStringContains(t, err.Error(), "failed to reach the index")
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"items": []}`))
+	}))
+	defer broken.Close()
+	_, err = searchIndex(ctx, broken.URL, "github", 20)
+	testhelpers.AssertStringContains(t, err.Error(), "unexpected response")
+}
+
+func TestRegisterInputFromSearch(t *testing.T) {
+	index, _ := withTestIndex(t)
+	res, err := searchIndex(context.Background(), index.URL, "", 20)
+	testhelpers.AssertNoError(t, err)
+
+	// required credentials can only be entered in a terminal
+	cmd, _ := newWizardTestCmd(t, nil, "gh-token")
+	stdinIsTerminal = func() bool { return false }
+	_, err = registerInputFromSearch(cmd, &res.Servers[0], "")
+	testhelpers.AssertStringContains(t, err.Error(), "run the command in a terminal")
+
+	stdinIsTerminal = func() bool { return true }
+	input, err := registerInputFromSearch(cmd, &res.Servers[0], "")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, input.Validate())
+	testhelpers.AssertEqual(t, "github-mcp-server", input.Name)
+	testhelpers.AssertEqual(t, "https://api.githubcopilot.com/mcp/", input.URL)
+	testhelpers.AssertEqual(t, "gh-token", input.BearerToken)
+
+	input, err = registerInputFromSearch(cmd, &res.Servers[1], "weather")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, input.Validate())
+	testhelpers.AssertEqual(t, "npx", input.Command)
+	testhelpers.AssertEqual(t, "-y @acme/weather-mcp@1.2.0 --units metric", strings.Join(input.Args, " "))
+	testhelpers.AssertEqual(t, 1, len(input.Env))
+	testhelpers.AssertEqual(t, "gh-token", input.Env["WEATHER_API_KEY"])
+
+	_, err = registerInputFromSearch(cmd, &res.Servers[2], "")
+	testhelpers.AssertStringContains(t, err.Error(), "none of its packages or remotes use a transport mcpjungle supports")
+}
+
+func TestIndexServerName(t *testing.T) {
+	for id, want := range map[string]string{
+		"io.github.github/github-mcp-server": "github-mcp-server",
+		"com.example/Weather.API":            "Weather-API",
+		"local":                              "local",
+	} {
+		testhelpers.AssertEqual(t, want, indexServerName(id))
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var serverCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Inspect the running mcpjungle server",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "24",
+	},
+}
+
+var serverInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show the version, mode and replica role of the server",
+	Long: "Show the version and mode of the mcpjungle server, when it started and its role among the replicas\n" +
+		"sharing its database (admin only).\n\n" +
+		"The leader runs the background jobs, eg- the health checks of MCP servers, the followers only serve requests.\n" +
+		"Behind a load balancer, every call may be answered by another replica, the one that answered is described.",
+	Args: cobra.NoArgs,
+	RunE: runServerInfo,
+}
+
+func init() {
+	serverCmd.AddCommand(serverInfoCmd)
+	rootCmd.AddCommand(serverCmd)
+}
+
+func runServerInfo(cmd *cobra.Command, args []string) error {
+	info, err := apiClient.GetServerInfo(commandContext(cmd))
+	if err != nil {
+		return fmt.Errorf("failed to get the server info: %w", err)
+	}
+	if isStructuredOutput() {
+		return printOutput(cmd, info)
+	}
+
+	p := newPrinter(cmd)
+	p.Resultf("Version:    %s\n", info.Version)
+	p.Resultf("Mode:       %s\n", info.Mode)
+	p.Resultf("Started at: %s\n", info.StartedAt.Local().Format(time.DateTime))
+	p.Resultf("Replica:    %s (%s)\n", info.Replica.ID, info.Replica.Role)
+	if info.Replica.Role == types.ReplicaFollower {
+		leader := info.Replica.Leader
+		if leader == "" {
+			leader = "none, a replica takes the lease over shortly"
+		}
+		p.Resultf("Leader:     %s\n", leader)
+	}
+	if info.Replica.LeaseExpiresAt != nil {
+		p.Resultf(
+			"Lease:      held since %s, expires at %s unless renewed\n",
+			info.Replica.LeaderSince.Local().Format(time.DateTime),
+			info.Replica.LeaseExpiresAt.Local().Format(time.DateTime),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func TestServerCommandStructure(t *testing.T) {
+	testhelpers.TestCommandAnnotations(t, serverCmd.Annotations, []testhelpers.CommandAnnotationTest{
+		{Key: "group", Expected: string(subCommandGroupAdvanced)},
+		{Key: "order", Expected: "24"},
+	})
+	testhelpers.AssertEqual(t, "info", serverInfoCmd.Name())
+	testhelpers.AssertTrue(t, serverInfoCmd.HasParent(), "info should be a subcommand of server")
+}
+
+func TestServerInfoCommand(t *testing.T) {
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expires := since.Add(15 * time.Second)
+	info := types.ServerInfo{
+		Version:   "1.2.3",
+		Mode:      "enterprise",
+		StartedAt: since,
+		Replica: types.ReplicaInfo{
+			ID: "mcpjungle-1", Role: types.ReplicaFollower, Leader: "mcpjungle-0",
+			LeaderSince: &since, LeaseExpiresAt: &expires,
+		},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(info)
+	}))
+	t.Cleanup(srv.Close)
+	orig := apiClient
+	apiClient = client.NewClient(srv.URL, "", srv.Client())
+	t.Cleanup(func() { apiClient = orig })
+
+	cmd := &cobra.Command{}
+	stdout := &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	testhelpers.AssertNoError(t, runServerInfo(cmd, nil))
+
+	out := stdout.String()
+	testhelpers.AssertStringContains(t, out, "1.2.3")
+	testhelpers.AssertStringContains(t, out, "mcpjungle-1 (follower)")
+	testhelpers.AssertStringContains(t, out, "Leader:     mcpjungle-0")
+	testhelpers.AssertStringContains(t, out, "unless renewed")
+}
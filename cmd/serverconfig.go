@@ -0,0 +1,519 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	clientconfig "github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/mcpjungle/mcpjungle/internal/api"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/docker"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/idempotency"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/replica"
+	"github.com/mcpjungle/mcpjungle/internal/vault"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// ServerConfigEnvPrefix is the prefix of the environment variables the settings of the server are read from,
+// eg- MCPJUNGLE_SHUTDOWN_TIMEOUT_SEC. Every setting can also be set by its environment variable without the prefix,
+// eg- SHUTDOWN_TIMEOUT_SEC, for compatibility. The prefixed one takes precedence if both are set.
+const ServerConfigEnvPrefix = "MCPJUNGLE_"
+
+// ServerConfigFileEnvVar is the environment variable for the path of the YAML file the settings of the server are
+// read from. The environment variables take precedence over the file, the --config-file flag overrides it.
+const ServerConfigFileEnvVar = "MCPJUNGLE_CONFIG_FILE"
+
+// settingSourceConfigFile means a server setting was read from the config file of the server
+const settingSourceConfigFile = "config file"
+
+// serverSetting describes a setting of the server.
+type serverSetting struct {
+	// Key is the path of the setting in the config file, nested fields are separated by dots.
+	Key string
+	// Name is the environment variable of the setting without the MCPJUNGLE_ prefix,
+	// its key in upper case with the dots replaced by underscores.
+	Name string
+	// Default is the value used when the setting is not configured, empty if the setting is unset by default.
+	Default string
+	// Secret settings are masked when the configuration is printed.
+	Secret bool
+}
+
+// serverSettings are all the settings of the server, in the order they are printed.
+var serverSettings = []serverSetting{
+	{Key: "port", Name: BindPortEnvVar, Default: BindPortDefault},
+	{Key: "grpc_port", Name: GRPCPortEnvVar},
+	{Key: "server_mode", Name: ServerModeEnvVar, Default: string(model.ModeDev)},
+	{Key: "otel.enabled", Name: TelemetryEnabledEnvVar},
+	{Key: "otel.legacy_metrics", Name: TelemetryLegacyMetricsEnvVar, Default: "false"},
+
+	{Key: "database_url", Name: DBUrlEnvVar, Secret: true},
+	{Key: "postgres.host", Name: PostgresHostEnvVar},
+	{Key: "postgres.port", Name: PostgresPortEnvVar, Default: "5432"},
+	{Key: "postgres.user", Name: PostgresUserEnvVar, Default: "postgres"},
+	{Key: "postgres.user_file", Name: PostgresUserEnvVar + "_FILE"},
+	{Key: "postgres.password", Name: PostgresPasswordEnvVar, Secret: true},
+	{Key: "postgres.password_file", Name: PostgresPasswordEnvVar + "_FILE"},
+	{Key: "postgres.db", Name: PostgresDBEnvVar, Default: "postgres"},
+	{Key: "postgres.db_file", Name: PostgresDBEnvVar + "_FILE"},
+	{Key: "postgres.ssl_mode", Name: PostgresSSLModeEnvVar},
+	{Key: "postgres.ssl_ca_cert", Name: PostgresSSLCACertEnvVar},
+	{Key: "postgres.ssl_client_cert", Name: PostgresSSLClientCertEnvVar},
+	{Key: "postgres.ssl_client_key", Name: PostgresSSLClientKeyEnvVar},
+	{Key: "database.max_open_conns", Name: DBMaxOpenConnsEnvVar},
+	{Key: "database.max_idle_conns", Name: DBMaxIdleConnsEnvVar},
+	{Key: "database.conn_max_lifetime_sec", Name: DBConnMaxLifetimeSecEnvVar},
+
+	{
+		Key:     "mcp_server.init_req_timeout_sec",
+		Name:    McpServerInitReqTimeoutSecEnvVar,
+		Default: strconv.Itoa(McpServerInitRequestTimeoutSecondsDefault),
+	},
+	{
+		Key:     "session.idle_timeout_sec",
+		Name:    SessionIdleTimeoutSecEnvVar,
+		Default: strconv.Itoa(SessionIdleTimeoutSecondsDefault),
+	},
+	{Key: "api.rate_limit_per_min", Name: APIRateLimitEnvVar, Default: "0"},
+	{
+		Key:     "idempotency_key.ttl_sec",
+		Name:    IdempotencyKeyTTLSecEnvVar,
+		Default: strconv.Itoa(int(idempotency.DefaultTTL.Seconds())),
+	},
+	{
+		Key:     "health_check.interval_sec",
+		Name:    HealthCheckIntervalSecEnvVar,
+		Default: strconv.Itoa(HealthCheckIntervalSecondsDefault),
+	},
+	{Key: "replica.id", Name: ReplicaIDEnvVar},
+	{
+		Key:     "replica.lease_ttl_sec",
+		Name:    ReplicaLeaseTTLSecEnvVar,
+		Default: strconv.Itoa(int(replica.DefaultLeaseTTL.Seconds())),
+	},
+	{
+		Key:     "replica.sync_interval_sec",
+		Name:    ReplicaSyncIntervalSecEnvVar,
+		Default: strconv.Itoa(int(replica.DefaultSyncInterval.Seconds())),
+	},
+	{Key: "public_url", Name: PublicURLEnvVar},
+	{Key: "shutdown.timeout_sec", Name: ShutdownTimeoutSecEnvVar, Default: strconv.Itoa(ShutdownTimeoutSecondsDefault)},
+
+	{Key: "cors.allowed_origins", Name: CORSAllowedOriginsEnvVar},
+	{Key: "cors.allowed_methods", Name: CORSAllowedMethodsEnvVar},
+	{Key: "cors.allowed_headers", Name: CORSAllowedHeadersEnvVar},
+	{Key: "cors.allow_credentials", Name: CORSAllowCredentialsEnvVar, Default: "false"},
+	{Key: "cors.max_age_sec", Name: CORSMaxAgeSecEnvVar},
+	{Key: "cors.include_mcp", Name: CORSIncludeMCPEnvVar, Default: "false"},
+
+	{Key: "tools_list_cache.enabled", Name: ToolsListCacheEnabledEnvVar, Default: "true"},
+	{Key: "tool_sync.on_startup", Name: ToolSyncOnStartupEnvVar, Default: "false"},
+	{Key: "tool_sync.concurrency", Name: ToolSyncConcurrencyEnvVar, Default: strconv.Itoa(mcp.DefaultSyncConcurrency)},
+	{Key: "tool_sync.blocking", Name: ToolSyncBlockingEnvVar, Default: "false"},
+	{Key: "max_tool_result_bytes", Name: MaxToolResultBytesEnvVar, Default: strconv.Itoa(mcp.DefaultMaxToolResultBytes)},
+	{
+		Key:     "upstream.max_concurrent_calls",
+		Name:    UpstreamMaxConcurrentCallsEnvVar,
+		Default: strconv.Itoa(mcp.DefaultMaxConcurrentUpstreamCalls),
+	},
+	{
+		Key:     "upstream.max_queued_calls",
+		Name:    UpstreamMaxQueuedCallsEnvVar,
+		Default: strconv.Itoa(mcp.DefaultMaxQueuedUpstreamCalls),
+	},
+
+	{Key: "vault.addr", Name: VaultAddrEnvVar},
+	{Key: "vault.namespace", Name: VaultNamespaceEnvVar},
+	{Key: "vault.ca_cert", Name: VaultCACertEnvVar},
+	{Key: "vault.auth_method", Name: VaultAuthMethodEnvVar, Default: string(vault.AuthToken)},
+	{Key: "vault.token", Name: VaultTokenEnvVar, Secret: true},
+	{Key: "vault.kubernetes_role", Name: VaultKubernetesRoleEnvVar},
+	{Key: "vault.kubernetes_mount", Name: VaultKubernetesMountEnvVar, Default: vault.DefaultKubernetesMount},
+	{
+		Key:     "vault.kubernetes_token_file",
+		Name:    VaultKubernetesTokenFileEnvVar,
+		Default: vault.DefaultKubernetesTokenFile,
+	},
+	{
+		Key:     "vault.cache_ttl_sec",
+		Name:    VaultCacheTTLSecEnvVar,
+		Default: strconv.Itoa(int(vault.DefaultCacheTTL.Seconds())),
+	},
+	{Key: "docker.host", Name: DockerHostEnvVar, Default: docker.DefaultHost},
+}
+
+// serverConfigFile holds the settings read from the config file of the server, by setting name.
+// It is nil if the server has no config file.
+var serverConfigFile map[string]string
+
+// lookupServerSetting returns the configured value of the setting with the given name and where it was read from.
+// precedence: MCPJUNGLE_ prefixed environment variable > environment variable without prefix > config file.
+// It returns an empty value if the setting is not configured, in which case its default applies.
+func lookupServerSetting(name string) (string, string) {
+	for _, envVar := range []string{ServerConfigEnvPrefix + name, name} {
+		if v := os.Getenv(envVar); v != "" {
+			return v, envVar
+		}
+	}
+	if v := serverConfigFile[name]; v != "" {
+		return v, settingSourceConfigFile
+	}
+	return "", settingSourceDefault
+}
+
+// serverSettingValue returns the configured value of the setting with the given name, empty if it is not configured.
+func serverSettingValue(name string) string {
+	v, _ := lookupServerSetting(name)
+	return v
+}
+
+// getServerConfigFilePath returns the path of the config file of the server, empty if it has none.
+// precedence: command line flag > environment variable
+func getServerConfigFilePath() string {
+	if startServerCmdConfigFile != "" {
+		return startServerCmdConfigFile
+	}
+	return strings.TrimSpace(os.Getenv(ServerConfigFileEnvVar))
+}
+
+// readServerConfigFile reads the settings of the server from the YAML file at path, by setting name.
+// Nested fields are joined with underscores to find the setting they are for, so `cors: {max_age_sec: 600}`
+// and `cors_max_age_sec: 600` both set CORS_MAX_AGE_SEC. Lists are joined with commas.
+// It also returns a warning for every field that is not a setting of the server.
+func readServerConfigFile(path string) (map[string]string, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the server config file: %w", err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the server config file %s: %w", path, err)
+	}
+
+	fields := make(map[string]string)
+	if err := flattenServerConfig("", doc, fields); err != nil {
+		return nil, nil, fmt.Errorf("invalid server config file %s: %w", path, err)
+	}
+
+	keys := make([]string, 0, len(serverSettings))
+	known := make(map[string]bool, len(serverSettings))
+	for _, s := range serverSettings {
+		keys = append(keys, s.Key)
+		known[s.Name] = true
+	}
+	values := make(map[string]string, len(fields))
+	var warnings []string
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		name := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+		if !known[name] {
+			warnings = append(warnings, unknownSettingWarning(fmt.Sprintf("setting '%s' in %s", key, path), key, keys))
+			continue
+		}
+		values[name] = fields[key]
+	}
+	return values, warnings, nil
+}
+
+// flattenServerConfig adds the scalar fields of doc to fields, by their dot-separated path prefixed with prefix.
+func flattenServerConfig(prefix string, doc map[string]any, fields map[string]string) error {
+	for k, v := range doc {
+		key := strings.ToLower(prefix + k)
+		switch v := v.(type) {
+		case nil:
+		case map[string]any:
+			if err := flattenServerConfig(key+".", v, fields); err != nil {
+				return err
+			}
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				if _, ok := item.(map[string]any); ok {
+					return fmt.Errorf("'%s' must be a list of values", key)
+				}
+				items = append(items, fmt.Sprint(item))
+			}
+			fields[key] = strings.Join(items, ",")
+		default:
+			fields[key] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
+
+// unknownServerEnvVarWarnings returns a warning for every MCPJUNGLE_ environment variable that mcpjungle doesn't use,
+// naming the closest known variable when it looks like a typo.
+func unknownServerEnvVarWarnings() []string {
+	known := []string{
+		ServerConfigFileEnvVar, ContextEnvVar, RegistryURLEnvVar, AccessTokenEnvVar, OutputEnvVar,
+		// interpolated by docker compose in the files created by `generate compose`
+		"MCPJUNGLE_IMAGE_TAG",
+	}
+	for _, s := range serverSettings {
+		known = append(known, ServerConfigEnvPrefix+s.Name)
+	}
+
+	var warnings []string
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		// like the settings, a variable set to an empty value is not set
+		if !strings.HasPrefix(name, ServerConfigEnvPrefix) || value == "" || slices.Contains(known, name) {
+			continue
+		}
+		warnings = append(warnings, unknownSettingWarning("environment variable "+name, name, known))
+	}
+	slices.Sort(warnings)
+	return warnings
+}
+
+// unknownSettingWarning returns the warning for the unknown setting name, described by what.
+// The closest of the known settings is suggested if it looks like a typo.
+func unknownSettingWarning(what, name string, known []string) string {
+	// the common prefix would make every variable look close to the others
+	trimmed := make([]string, 0, len(known))
+	for _, k := range known {
+		trimmed = append(trimmed, strings.TrimPrefix(k, ServerConfigEnvPrefix))
+	}
+	warning := "ignoring unknown " + what
+	if match := closestMatch(strings.TrimPrefix(name, ServerConfigEnvPrefix), trimmed); match != "" {
+		warning += fmt.Sprintf(", did you mean %s?", known[slices.Index(trimmed, match)])
+	}
+	return warning
+}
+
+// serverConfig is the effective configuration of the server, assembled from the flags of the start command,
+// the environment variables, the config file and the defaults.
+type serverConfig struct {
+	Mode             model.ServerMode
+	TelemetryEnabled bool
+	// TelemetryLegacyMetrics keeps the metrics of the tool and prompt calls used before the semantic conventions
+	TelemetryLegacyMetrics bool
+	BindPort               string
+	// GRPCPort is empty if the gRPC admin API is disabled
+	GRPCPort string
+	// DSN is empty for the default SQLite database
+	DSN string
+	// Postgres holds the TLS and connection pool settings of the Postgres database, nil if none is set
+	Postgres *db.PostgresOptions
+
+	McpServerInitReqTimeoutSec int
+	SessionIdleTimeoutSec      int
+	HealthCheckInterval        time.Duration
+	ToolsListCacheEnabled      bool
+	ToolSyncOnStartup          bool
+	ToolSyncBlocking           bool
+	ToolSyncConcurrency        int
+	MaxToolResultBytes         int64
+	MaxUpstreamCalls           int
+	MaxQueuedUpstreamCalls     int
+	// Replica holds the coordination settings of this server among those sharing the database
+	Replica *replica.Config
+
+	APIRateLimit      *api.RateLimit
+	IdempotencyKeyTTL time.Duration
+	// PublicURL is the base URL clients reach the server at, empty if it is not configured
+	PublicURL       string
+	ShutdownTimeout time.Duration
+	CORS            *api.CORSPolicy
+	// Vault is the Vault server the credentials of MCP servers are resolved from, nil if none is configured
+	Vault *vault.Config
+	// Docker is the client of the Docker daemon the stdio servers configured with a container are launched in
+	Docker *docker.Client
+}
+
+// loadServerConfig assembles the configuration of the server and validates all of its settings,
+// so that the server doesn't start with an invalid setting.
+func loadServerConfig(cmd *cobra.Command) (*serverConfig, error) {
+	c := &serverConfig{}
+	var err error
+	if c.Mode, err = getDesiredServerMode(cmd); err != nil {
+		return nil, err
+	}
+	if c.TelemetryEnabled, err = isTelemetryEnabled(c.Mode); err != nil {
+		return nil, err
+	}
+	if c.TelemetryLegacyMetrics, err = isTelemetryLegacyMetricsEnabled(); err != nil {
+		return nil, err
+	}
+
+	c.BindPort = getBindPort()
+	if err := validatePort(BindPortEnvVar, c.BindPort); err != nil {
+		return nil, err
+	}
+	if c.GRPCPort = getGRPCPort(); c.GRPCPort != "" {
+		if err := validatePort(GRPCPortEnvVar, c.GRPCPort); err != nil {
+			return nil, err
+		}
+	}
+	if port := serverSettingValue(PostgresPortEnvVar); port != "" {
+		if err := validatePort(PostgresPortEnvVar, port); err != nil {
+			return nil, err
+		}
+	}
+	if c.DSN, err = getDSN(); err != nil {
+		return nil, err
+	}
+	if c.Postgres, err = getPostgresOptions(c.DSN); err != nil {
+		return nil, err
+	}
+
+	if c.McpServerInitReqTimeoutSec, err = getMcpServerInitReqTimeout(); err != nil {
+		return nil, err
+	}
+	if c.SessionIdleTimeoutSec, err = getSessionIdleTimeout(); err != nil {
+		return nil, err
+	}
+	if c.HealthCheckInterval, err = getHealthCheckInterval(); err != nil {
+		return nil, err
+	}
+	if c.ToolsListCacheEnabled, err = isToolsListCacheEnabled(); err != nil {
+		return nil, err
+	}
+	if c.ToolSyncOnStartup, err = isToolSyncOnStartupEnabled(); err != nil {
+		return nil, err
+	}
+	if c.ToolSyncBlocking, err = isToolSyncBlocking(); err != nil {
+		return nil, err
+	}
+	if c.ToolSyncConcurrency, err = getToolSyncConcurrency(); err != nil {
+		return nil, err
+	}
+	if c.MaxToolResultBytes, err = getMaxToolResultBytes(); err != nil {
+		return nil, err
+	}
+	if c.MaxUpstreamCalls, err = getUpstreamMaxConcurrentCalls(); err != nil {
+		return nil, err
+	}
+	if c.MaxQueuedUpstreamCalls, err = getUpstreamMaxQueuedCalls(); err != nil {
+		return nil, err
+	}
+	if c.Replica, err = getReplicaConfig(); err != nil {
+		return nil, err
+	}
+
+	if c.APIRateLimit, err = getAPIRateLimit(); err != nil {
+		return nil, err
+	}
+	if c.IdempotencyKeyTTL, err = getIdempotencyKeyTTL(); err != nil {
+		return nil, err
+	}
+	if c.PublicURL, err = getPublicURL(); err != nil {
+		return nil, err
+	}
+	if c.ShutdownTimeout, err = getShutdownTimeout(); err != nil {
+		return nil, err
+	}
+	if c.CORS, err = getCORSPolicy(); err != nil {
+		return nil, err
+	}
+	if c.Vault, err = getVaultConfig(); err != nil {
+		return nil, err
+	}
+	if c.Docker, err = getDockerClient(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// validatePort returns an error if port, configured by the setting with the given name, is not a valid TCP port.
+func validatePort(name, port string) error {
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("invalid value for %s: '%s', must be a port number between 0 and 65535", name, port)
+	}
+	return nil
+}
+
+// serverConfigView is the effective configuration of the server printed by `start --print-config`.
+type serverConfigView struct {
+	// ConfigFile is the path of the config file of the server, empty if it has none.
+	ConfigFile string              `json:"config_file,omitempty"`
+	Settings   []serverSettingView `json:"settings"`
+}
+
+// serverSettingView is the effective value of a setting of the server and where it was read from.
+type serverSettingView struct {
+	Key    string `json:"key"`
+	EnvVar string `json:"env_var"`
+	Value  string `json:"value"`
+	// Source is the flag, the environment variable, the config file or the default the value comes from
+	Source string `json:"source"`
+}
+
+// newServerConfigView returns the effective value of every setting of the server, with the secrets masked.
+func newServerConfigView(c *serverConfig) *serverConfigView {
+	v := &serverConfigView{ConfigFile: getServerConfigFilePath()}
+	for _, s := range serverSettings {
+		value, source := lookupServerSetting(s.Name)
+		if value == "" {
+			value = s.Default
+		}
+		// the flags of the start command override some of the settings
+		switch s.Name {
+		case BindPortEnvVar:
+			if startServerCmdBindPort != "" {
+				value, source = startServerCmdBindPort, settingSourceFlag
+			}
+		case GRPCPortEnvVar:
+			if startServerCmdGRPCPort != "" {
+				value, source = startServerCmdGRPCPort, settingSourceFlag
+			}
+		case ServerModeEnvVar:
+			if startServerCmdEnterpriseEnabled || startServerCmdProdEnabled {
+				source = settingSourceFlag
+			}
+			value = string(c.Mode)
+		case TelemetryEnabledEnvVar:
+			// by default, it depends on the mode
+			value = strconv.FormatBool(c.TelemetryEnabled)
+		}
+		if s.Secret {
+			value = maskServerSetting(s.Name, value)
+		}
+		v.Settings = append(v.Settings, serverSettingView{
+			Key:    s.Key,
+			EnvVar: ServerConfigEnvPrefix + s.Name,
+			Value:  value,
+			Source: source,
+		})
+	}
+	return v
+}
+
+// maskServerSetting masks the secret value of the setting with the given name.
+// Only the password of a database URL is masked, so that operators can still check where it points to.
+func maskServerSetting(name, value string) string {
+	if name == DBUrlEnvVar {
+		if u, err := url.Parse(value); err == nil && u.Scheme != "" {
+			return u.Redacted()
+		}
+	}
+	return clientconfig.MaskSecret(value)
+}
+
+// serverSettingColumns are the columns of the table printed by `start --print-config`.
+var serverSettingColumns = []tableColumn[serverSettingView]{
+	{name: "setting", value: func(s serverSettingView) string { return s.Key }},
+	{name: "env var", value: func(s serverSettingView) string { return s.EnvVar }},
+	{name: "value", value: func(s serverSettingView) string { return s.Value }},
+	{name: "source", value: func(s serverSettingView) string { return s.Source }},
+}
+
+// printServerConfig prints the effective configuration of the server.
+func printServerConfig(cmd *cobra.Command, c *serverConfig) error {
+	v := newServerConfigView(c)
+	if isStructuredOutput() {
+		return printOutput(cmd, v)
+	}
+	if v.ConfigFile != "" {
+		newPrinter(cmd).Infof("Config file: %s\n\n", v.ConfigFile)
+	}
+	return renderTable(cmd.OutOrStdout(), serverSettingColumns, v.Settings)
+}
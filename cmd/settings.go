@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/spf13/cobra"
+)
+
+// Environment variables that override the connection settings of the active context.
+const (
+	ContextEnvVar     = "MCPJUNGLE_CONTEXT"
+	RegistryURLEnvVar = "MCPJUNGLE_REGISTRY_URL"
+	AccessTokenEnvVar = "MCPJUNGLE_ACCESS_TOKEN"
+	OutputEnvVar      = "MCPJUNGLE_OUTPUT"
+	IndexURLEnvVar    = "MCPJUNGLE_INDEX_URL"
+)
+
+// Sources a CLI setting can be resolved from, in decreasing order of precedence.
+const (
+	settingSourceFlag    = "flag"
+	settingSourceEnv     = "env"
+	settingSourceContext = "context"
+	// settingSourceLocalServer means the registry URL of a server running on this machine was discovered
+	settingSourceLocalServer = "local server"
+	settingSourceDefault     = "default"
+)
+
+// cliSettings holds the effective connection settings used by a CLI command,
+// along with where each of them was resolved from.
+type cliSettings struct {
+	// ContextName is the name of the active context, empty if no context is configured.
+	ContextName string `json:"context"`
+
+	RegistryURL       string `json:"registry_url"`
+	RegistryURLSource string `json:"registry_url_source"`
+
+	AccessToken       string `json:"access_token,omitempty"`
+	AccessTokenSource string `json:"access_token_source,omitempty"`
+
+	Output       string `json:"output"`
+	OutputSource string `json:"output_source"`
+
+	// IndexURL is the index of MCP servers searched by `search registry` and `register --from-search`.
+	IndexURL       string `json:"index_url"`
+	IndexURLSource string `json:"index_url_source"`
+
+	// TLS configures the connection to the registry, each of its settings comes from the flag or the active context.
+	TLS client.TLSOptions `json:"tls"`
+}
+
+// activeSettings holds the settings resolved for the currently running command.
+// Like apiClient, it is populated in the root command's PersistentPreRunE.
+var activeSettings *cliSettings
+
+// TLS settings of the connection to the registry, set by the global --ca-cert, --client-cert, --client-key
+// and --insecure-skip-tls-verify flags. They override the ones of the active context.
+var (
+	caCertFlag                string
+	clientCertFlag            string
+	clientKeyFlag             string
+	insecureSkipTLSVerifyFlag bool
+)
+
+// contextOverride is set by the global --context flag.
+// It selects a context for a single command without changing the current context in the config file.
+var contextOverride string
+
+// selectContext returns the context that the command should use.
+// Precedence: --context flag > MCPJUNGLE_CONTEXT env var > current-context in the config file.
+// Explicitly selecting a context that does not exist is an error.
+// nil is returned if no context is configured at all.
+func selectContext(cmd *cobra.Command, f *config.File) (*config.Context, error) {
+	name, source := "", ""
+	if cmd.Flags().Changed("context") {
+		name, source = contextOverride, "--context flag"
+	} else if v := os.Getenv(ContextEnvVar); v != "" {
+		name, source = v, ContextEnvVar+" env var"
+	}
+
+	if name == "" {
+		return f.Current(), nil
+	}
+	ctx := f.GetContext(name)
+	if ctx == nil {
+		return nil, fmt.Errorf("context '%s' (set via %s) does not exist, run `mcpjungle context list` to see available contexts", name, source)
+	}
+	return ctx, nil
+}
+
+// resolveCLISettings determines the effective connection settings for the command.
+// Precedence for each setting: command line flag explicitly set by user > env var > active context > default.
+// When the registry URL is not configured anywhere, the server running on this machine is used if there is one.
+func resolveCLISettings(cmd *cobra.Command, f *config.File) (*cliSettings, error) {
+	s := &cliSettings{}
+
+	ctx, err := selectContext(cmd, f)
+	if err != nil {
+		return nil, err
+	}
+	if ctx != nil {
+		s.ContextName = ctx.Name
+	} else {
+		ctx = &config.Context{}
+	}
+
+	// registry URL
+	switch {
+	case cmd.Flags().Changed("registry"):
+		s.RegistryURL, s.RegistryURLSource = registryServerURL, settingSourceFlag
+	case os.Getenv(RegistryURLEnvVar) != "":
+		s.RegistryURL, s.RegistryURLSource = os.Getenv(RegistryURLEnvVar), settingSourceEnv
+	case ctx.RegistryURL != "":
+		s.RegistryURL, s.RegistryURLSource = ctx.RegistryURL, settingSourceContext
+	default:
+		s.RegistryURL, s.RegistryURLSource = registryServerURL, settingSourceDefault
+		// discovery is best-effort, an unreadable local server file is no reason to fail the command
+		if local, err := config.DiscoverLocalServer(); err == nil && local != nil {
+			s.RegistryURL, s.RegistryURLSource = local.URL, settingSourceLocalServer
+		}
+	}
+
+	// access token
+	if t := os.Getenv(AccessTokenEnvVar); t != "" {
+		s.AccessToken, s.AccessTokenSource = t, settingSourceEnv
+	} else {
+		t, err := ctx.ResolveAccessToken()
+		if err != nil {
+			return nil, err
+		}
+		if t != "" {
+			s.AccessToken, s.AccessTokenSource = t, settingSourceContext
+		}
+	}
+
+	// output format
+	switch {
+	case cmd.Flags().Changed("output"):
+		s.Output, s.OutputSource = outputFormatFlag, settingSourceFlag
+	case os.Getenv(OutputEnvVar) != "":
+		s.Output, s.OutputSource = os.Getenv(OutputEnvVar), settingSourceEnv
+	case ctx.Output != "":
+		s.Output, s.OutputSource = ctx.Output, settingSourceContext
+	default:
+		s.Output, s.OutputSource = outputFormatTable, settingSourceDefault
+	}
+	if err := validateOutputFormat(s.Output); err != nil {
+		return nil, fmt.Errorf("invalid output format (from %s): %w", s.OutputSource, err)
+	}
+
+	// index of MCP servers
+	switch {
+	case cmd.Flags().Changed("index"):
+		s.IndexURL, s.IndexURLSource = indexURLFlag, settingSourceFlag
+	case os.Getenv(IndexURLEnvVar) != "":
+		s.IndexURL, s.IndexURLSource = os.Getenv(IndexURLEnvVar), settingSourceEnv
+	case ctx.IndexURL != "":
+		s.IndexURL, s.IndexURLSource = ctx.IndexURL, settingSourceContext
+	default:
+		s.IndexURL, s.IndexURLSource = defaultIndexURL, settingSourceDefault
+	}
+
+	// TLS
+	s.TLS.CACertFile, s.TLS.ClientCertFile, s.TLS.ClientKeyFile = ctx.TLSFiles()
+	if cmd.Flags().Changed("ca-cert") {
+		s.TLS.CACertFile = caCertFlag
+	}
+	// a certificate and its key go together, so the flags replace both files of the context
+	if cmd.Flags().Changed("client-cert") || cmd.Flags().Changed("client-key") {
+		s.TLS.ClientCertFile, s.TLS.ClientKeyFile = clientCertFlag, clientKeyFlag
+	}
+	s.TLS.InsecureSkipVerify = ctx.InsecureSkipTLSVerify
+	if cmd.Flags().Changed("insecure-skip-tls-verify") {
+		s.TLS.InsecureSkipVerify = insecureSkipTLSVerifyFlag
+	}
+
+	return s, nil
+}
+
+// activeContextName returns the name of the context used by the current command, or an empty string if none.
+func activeContextName() string {
+	if activeSettings == nil {
+		return ""
+	}
+	return activeSettings.ContextName
+}
+
+// saveAccessTokenToActiveContext stores the given access token in the named context of the CLI config file.
+// If contextName is empty, the current context is used.
+// If there is no such context yet, a default context pointing to registryURL is created.
+// It returns the path of the config file that was written.
+func saveAccessTokenToActiveContext(contextName, registryURL, accessToken string) (string, error) {
+	f, err := config.LoadFile()
+	if err != nil {
+		return "", err
+	}
+
+	ctx := f.Current()
+	if contextName != "" {
+		ctx = f.GetContext(contextName)
+	}
+	if ctx == nil {
+		name := contextName
+		if name == "" {
+			name = f.CurrentContext
+		}
+		if name == "" {
+			name = config.DefaultContextName
+		}
+		f.SetContext(config.Context{Name: name})
+		if f.CurrentContext == "" {
+			f.CurrentContext = name
+		}
+		ctx = f.GetContext(name)
+	}
+	if ctx.RegistryURL == "" {
+		ctx.RegistryURL = registryURL
+	}
+	ctx.AccessToken = accessToken
+	// an inline token now takes precedence, clear any stale references to avoid confusion
+	ctx.AccessTokenEnv = ""
+	ctx.AccessTokenFile = ""
+
+	if err := config.SaveFile(f); err != nil {
+		return "", fmt.Errorf("failed to save client configuration: %w", err)
+	}
+	return config.FilePath()
+}
+
+// describeContext returns a human-readable description of the context the settings were resolved from.
+func describeContext(s *cliSettings) string {
+	if s.ContextName == "" {
+		return "<none>"
+	}
+	return "'" + s.ContextName + "'"
+}
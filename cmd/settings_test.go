@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/spf13/cobra"
+)
+
+// newSettingsTestCmd returns a command that has the global connection flags defined on it.
+func newSettingsTestCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().StringVar(&registryServerURL, "registry", "http://127.0.0.1:"+BindPortDefault, "")
+	cmd.Flags().StringVar(&outputFormatFlag, "output", outputFormatTable, "")
+	cmd.Flags().StringVar(&caCertFlag, "ca-cert", "", "")
+	cmd.Flags().StringVar(&clientCertFlag, "client-cert", "", "")
+	cmd.Flags().StringVar(&clientKeyFlag, "client-key", "", "")
+	cmd.Flags().BoolVar(&insecureSkipTLSVerifyFlag, "insecure-skip-tls-verify", false, "")
+	cmd.Flags().StringVar(&indexURLFlag, "index", "", "")
+	testhelpers.AssertNoError(t, cmd.ParseFlags(args))
+	return cmd
+}
+
+func TestResolveCLISettings(t *testing.T) {
+	cfg := &config.File{
+		CurrentContext: "remote",
+		Contexts: []config.Context{
+			{Name: "remote", RegistryURL: "https://remote.example.com", AccessToken: "ctx-token", Output: "json"},
+		},
+	}
+
+	t.Run("defaults without any context", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		s, err := resolveCLISettings(newSettingsTestCmd(t), &config.File{})
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "http://127.0.0.1:"+BindPortDefault, s.RegistryURL)
+		testhelpers.AssertEqual(t, settingSourceDefault, s.RegistryURLSource)
+		testhelpers.AssertEqual(t, "", s.AccessToken)
+		testhelpers.AssertEqual(t, outputFormatTable, s.Output)
+	})
+
+	t.Run("context values are used", func(t *testing.T) {
+		s, err := resolveCLISettings(newSettingsTestCmd(t), cfg)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "remote", s.ContextName)
+		testhelpers.AssertEqual(t, "https://remote.example.com", s.RegistryURL)
+		testhelpers.AssertEqual(t, "ctx-token", s.AccessToken)
+		testhelpers.AssertEqual(t, outputFormatJSON, s.Output)
+	})
+
+	t.Run("env vars override context", func(t *testing.T) {
+		t.Setenv(RegistryURLEnvVar, "http://env:8080")
+		t.Setenv(AccessTokenEnvVar, "env-token")
+		t.Setenv(OutputEnvVar, outputFormatTable)
+
+		s, err := resolveCLISettings(newSettingsTestCmd(t), cfg)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "http://env:8080", s.RegistryURL)
+		testhelpers.AssertEqual(t, settingSourceEnv, s.RegistryURLSource)
+		testhelpers.AssertEqual(t, "env-token", s.AccessToken)
+		testhelpers.AssertEqual(t, outputFormatTable, s.Output)
+	})
+
+	t.Run("flags override env vars", func(t *testing.T) {
+		t.Setenv(RegistryURLEnvVar, "http://env:8080")
+
+		cmd := newSettingsTestCmd(t, "--registry", "http://flag:9090", "--output", "json")
+		s, err := resolveCLISettings(cmd, cfg)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "http://flag:9090", s.RegistryURL)
+		testhelpers.AssertEqual(t, settingSourceFlag, s.RegistryURLSource)
+		testhelpers.AssertEqual(t, outputFormatJSON, s.Output)
+	})
+
+	t.Run("local server is discovered when no registry is configured", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		testhelpers.AssertNoError(t, config.WriteLocalServer(&config.LocalServer{URL: "http://127.0.0.1:9090", PID: os.Getpid()}))
+
+		s, err := resolveCLISettings(newSettingsTestCmd(t), &config.File{})
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "http://127.0.0.1:9090", s.RegistryURL)
+		testhelpers.AssertEqual(t, settingSourceLocalServer, s.RegistryURLSource)
+
+		// an explicitly configured registry always wins
+		s, err = resolveCLISettings(newSettingsTestCmd(t), cfg)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "https://remote.example.com", s.RegistryURL)
+
+		s, err = resolveCLISettings(newSettingsTestCmd(t, "--registry", "http://flag:9090"), &config.File{})
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "http://flag:9090", s.RegistryURL)
+	})
+
+	t.Run("TLS flags override the context", func(t *testing.T) {
+		t.Setenv("HOME", "/home/alice")
+		tlsCfg := &config.File{
+			CurrentContext: "internal",
+			Contexts: []config.Context{{
+				Name: "internal", RegistryURL: "https://mcpjungle.internal",
+				CACert: "~/certs/ca.pem", ClientCert: "/etc/mcpjungle/client.crt", ClientKey: "/etc/mcpjungle/client.key",
+			}},
+		}
+		s, err := resolveCLISettings(newSettingsTestCmd(t), tlsCfg)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "/home/alice/certs/ca.pem", s.TLS.CACertFile)
+		testhelpers.AssertEqual(t, "/etc/mcpjungle/client.crt", s.TLS.ClientCertFile)
+		testhelpers.AssertFalse(t, s.TLS.InsecureSkipVerify, "verification should be enabled by default")
+
+		s, err = resolveCLISettings(
+			newSettingsTestCmd(t, "--ca-cert", "ca.pem", "--client-cert", "me.crt", "--insecure-skip-tls-verify"),
+			tlsCfg,
+		)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "ca.pem", s.TLS.CACertFile)
+		testhelpers.AssertEqual(t, "me.crt", s.TLS.ClientCertFile)
+		// the key of the context doesn't belong to the certificate of the flag
+		testhelpers.AssertEqual(t, "", s.TLS.ClientKeyFile)
+		testhelpers.AssertTrue(t, s.TLS.InsecureSkipVerify, "the flag should disable verification")
+	})
+
+	t.Run("index of MCP servers", func(t *testing.T) {
+		s, err := resolveCLISettings(newSettingsTestCmd(t), &config.File{})
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, defaultIndexURL, s.IndexURL)
+
+		indexCfg := &config.File{
+			CurrentContext: "acme",
+			Contexts:       []config.Context{{Name: "acme", IndexURL: "https://mcp-index.acme.internal"}},
+		}
+		s, err = resolveCLISettings(newSettingsTestCmd(t), indexCfg)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "https://mcp-index.acme.internal", s.IndexURL)
+		testhelpers.AssertEqual(t, settingSourceContext, s.IndexURLSource)
+
+		t.Setenv(IndexURLEnvVar, "http://env-index")
+		s, err = resolveCLISettings(newSettingsTestCmd(t, "--index", "http://flag-index"), indexCfg)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "http://flag-index", s.IndexURL)
+	})
+
+	t.Run("invalid output format", func(t *testing.T) {
+		t.Setenv(OutputEnvVar, "xml")
+		_, err := resolveCLISettings(newSettingsTestCmd(t), &config.File{})
+		testhelpers.AssertError(t, err)
+	})
+}
+
+func TestSaveAccessTokenToActiveContext(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	path, err := saveAccessTokenToActiveContext("", "http://127.0.0.1:8080", "tok-1")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, path != "", "expected config file path")
+
+	f, err := config.LoadFile()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, config.DefaultContextName, f.CurrentContext)
+	testhelpers.AssertEqual(t, "tok-1", f.Current().AccessToken)
+	testhelpers.AssertEqual(t, "http://127.0.0.1:8080", f.Current().RegistryURL)
+
+	// a second login only replaces the token of the active context
+	f.SetContext(config.Context{Name: "other", RegistryURL: "http://other"})
+	testhelpers.AssertNoError(t, config.SaveFile(f))
+
+	_, err = saveAccessTokenToActiveContext("", "http://ignored", "tok-2")
+	testhelpers.AssertNoError(t, err)
+
+	f, err = config.LoadFile()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "tok-2", f.Current().AccessToken)
+	testhelpers.AssertEqual(t, "http://127.0.0.1:8080", f.Current().RegistryURL)
+	testhelpers.AssertEqual(t, "", f.GetContext("other").AccessToken)
+}
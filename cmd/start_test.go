@@ -4,6 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/docker"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/vault"
 )
 
 func TestStartCommandStructure(t *testing.T) {
@@ -50,6 +55,16 @@ func TestStartCommandFlags(t *testing.T) {
 		}
 	})
 
+	t.Run("start command has grpc-port flag", func(t *testing.T) {
+		grpcPortFlag := startServerCmd.Flags().Lookup("grpc-port")
+		if grpcPortFlag == nil {
+			t.Fatal("Start command missing 'grpc-port' flag")
+		}
+		if grpcPortFlag.DefValue != "" {
+			t.Errorf("The gRPC API should be disabled by default, got port %s", grpcPortFlag.DefValue)
+		}
+	})
+
 	t.Run("start command has enterprise flag", func(t *testing.T) {
 		enterpriseFlag := startServerCmd.Flags().Lookup("enterprise")
 		prodFlag := startServerCmd.Flags().Lookup("enterprise")
@@ -314,3 +329,271 @@ func TestGetMcpServerInitReqTimeout(t *testing.T) {
 		}
 	})
 }
+
+func TestGetCORSPolicy(t *testing.T) {
+	withEnv(map[string]string{CORSAllowedOriginsEnvVar: ""}, func() {
+		p, err := getCORSPolicy()
+		if err != nil || p != nil {
+			t.Fatalf("expected no policy by default, got %+v, %v", p, err)
+		}
+	})
+
+	withEnv(map[string]string{
+		CORSAllowedOriginsEnvVar:   "https://dashboard.example.com, https://*.example.com",
+		CORSAllowedMethodsEnvVar:   "GET,POST",
+		CORSAllowCredentialsEnvVar: "true",
+		CORSMaxAgeSecEnvVar:        "600",
+	}, func() {
+		p, err := getCORSPolicy()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(p.AllowedOrigins) != 2 || p.AllowedOrigins[1] != "https://*.example.com" {
+			t.Errorf("expected 2 origins, got %v", p.AllowedOrigins)
+		}
+		if len(p.AllowedMethods) != 2 || len(p.AllowedHeaders) != 0 {
+			t.Errorf("expected 2 methods and the default headers, got %v and %v", p.AllowedMethods, p.AllowedHeaders)
+		}
+		if !p.AllowCredentials || p.IncludeMCP || p.MaxAge != 10*time.Minute {
+			t.Errorf("unexpected policy %+v", p)
+		}
+	})
+
+	for envVar, value := range map[string]string{CORSAllowCredentialsEnvVar: "sometimes", CORSMaxAgeSecEnvVar: "-1"} {
+		withEnv(map[string]string{CORSAllowedOriginsEnvVar: "https://dashboard.example.com", envVar: value}, func() {
+			if _, err := getCORSPolicy(); err == nil {
+				t.Errorf("expected an error for %s=%s", envVar, value)
+			}
+		})
+	}
+
+	// invalid settings are reported even if no origin is allowed yet
+	withEnv(map[string]string{CORSAllowedOriginsEnvVar: "", CORSMaxAgeSecEnvVar: "ten"}, func() {
+		if _, err := getCORSPolicy(); err == nil {
+			t.Error("expected an error for an invalid max age without origins")
+		}
+	})
+}
+
+func TestGetShutdownTimeout(t *testing.T) {
+	for value, want := range map[string]time.Duration{"": ShutdownTimeoutSecondsDefault * time.Second, "30": 30 * time.Second, "0": 0} {
+		withEnv(map[string]string{ShutdownTimeoutSecEnvVar: value}, func() {
+			got, err := getShutdownTimeout()
+			if err != nil || got != want {
+				t.Errorf("expected %s for %q, got %s, %v", want, value, got, err)
+			}
+		})
+	}
+	withEnv(map[string]string{ShutdownTimeoutSecEnvVar: "-1"}, func() {
+		if _, err := getShutdownTimeout(); err == nil {
+			t.Error("expected an error for a negative timeout")
+		}
+	})
+}
+
+func TestIsToolsListCacheEnabled(t *testing.T) {
+	for value, want := range map[string]bool{"": true, "true": true, "false": false, "0": false} {
+		withEnv(map[string]string{ToolsListCacheEnabledEnvVar: value}, func() {
+			got, err := isToolsListCacheEnabled()
+			if err != nil || got != want {
+				t.Errorf("expected %t for %q, got %t, %v", want, value, got, err)
+			}
+		})
+	}
+	withEnv(map[string]string{ToolsListCacheEnabledEnvVar: "sometimes"}, func() {
+		if _, err := isToolsListCacheEnabled(); err == nil {
+			t.Error("expected an error for an invalid value")
+		}
+	})
+}
+
+func TestIsToolSyncOnStartupEnabled(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "true": true, "false": false, "1": true} {
+		withEnv(map[string]string{ToolSyncOnStartupEnvVar: value}, func() {
+			got, err := isToolSyncOnStartupEnabled()
+			if err != nil || got != want {
+				t.Errorf("expected %t for %q, got %t, %v", want, value, got, err)
+			}
+		})
+	}
+	withEnv(map[string]string{ToolSyncOnStartupEnvVar: "sometimes"}, func() {
+		if _, err := isToolSyncOnStartupEnabled(); err == nil {
+			t.Error("expected an error for an invalid value")
+		}
+	})
+}
+
+func TestIsToolSyncBlocking(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "true": true, "false": false} {
+		withEnv(map[string]string{ToolSyncBlockingEnvVar: value}, func() {
+			got, err := isToolSyncBlocking()
+			if err != nil || got != want {
+				t.Errorf("expected %t for %q, got %t, %v", want, value, got, err)
+			}
+		})
+	}
+	withEnv(map[string]string{ToolSyncBlockingEnvVar: "always"}, func() {
+		if _, err := isToolSyncBlocking(); err == nil {
+			t.Error("expected an error for an invalid value")
+		}
+	})
+}
+
+func TestGetToolSyncConcurrency(t *testing.T) {
+	for value, want := range map[string]int{"": mcp.DefaultSyncConcurrency, "32": 32, " 1 ": 1} {
+		withEnv(map[string]string{ToolSyncConcurrencyEnvVar: value}, func() {
+			got, err := getToolSyncConcurrency()
+			if err != nil || got != want {
+				t.Errorf("expected %d for %q, got %d, %v", want, value, got, err)
+			}
+		})
+	}
+	for _, value := range []string{"0", "-1", "many"} {
+		withEnv(map[string]string{ToolSyncConcurrencyEnvVar: value}, func() {
+			if _, err := getToolSyncConcurrency(); err == nil {
+				t.Errorf("expected an error for %q", value)
+			}
+		})
+	}
+}
+
+func TestGetMaxToolResultBytes(t *testing.T) {
+	for value, want := range map[string]int64{"": mcp.DefaultMaxToolResultBytes, "0": 0, "1048576": 1 << 20} {
+		withEnv(map[string]string{MaxToolResultBytesEnvVar: value}, func() {
+			got, err := getMaxToolResultBytes()
+			if err != nil || got != want {
+				t.Errorf("expected %d for %q, got %d, %v", want, value, got, err)
+			}
+		})
+	}
+	for _, value := range []string{"-1", "64MB"} {
+		withEnv(map[string]string{MaxToolResultBytesEnvVar: value}, func() {
+			if _, err := getMaxToolResultBytes(); err == nil {
+				t.Errorf("expected an error for %q", value)
+			}
+		})
+	}
+}
+
+func TestGetUpstreamCallLimits(t *testing.T) {
+	for value, want := range map[string]int{"": mcp.DefaultMaxConcurrentUpstreamCalls, "64": 64} {
+		withEnv(map[string]string{UpstreamMaxConcurrentCallsEnvVar: value}, func() {
+			got, err := getUpstreamMaxConcurrentCalls()
+			if err != nil || got != want {
+				t.Errorf("expected %d for %q, got %d, %v", want, value, got, err)
+			}
+		})
+	}
+	for value, want := range map[string]int{"": mcp.DefaultMaxQueuedUpstreamCalls, "0": 0, "100": 100} {
+		withEnv(map[string]string{UpstreamMaxQueuedCallsEnvVar: value}, func() {
+			got, err := getUpstreamMaxQueuedCalls()
+			if err != nil || got != want {
+				t.Errorf("expected %d for %q, got %d, %v", want, value, got, err)
+			}
+		})
+	}
+	for _, value := range []string{"0", "-1", "many"} {
+		withEnv(map[string]string{UpstreamMaxConcurrentCallsEnvVar: value}, func() {
+			if _, err := getUpstreamMaxConcurrentCalls(); err == nil {
+				t.Errorf("expected an error for %q", value)
+			}
+		})
+	}
+	withEnv(map[string]string{UpstreamMaxQueuedCallsEnvVar: "-1"}, func() {
+		if _, err := getUpstreamMaxQueuedCalls(); err == nil {
+			t.Error("expected an error for a negative queue")
+		}
+	})
+}
+
+func TestGetReplicaConfig(t *testing.T) {
+	withEnv(map[string]string{ReplicaIDEnvVar: "", ReplicaLeaseTTLSecEnvVar: "", ReplicaSyncIntervalSecEnvVar: ""}, func() {
+		c, err := getReplicaConfig()
+		// the zero values are replaced by the defaults of the coordinator
+		if err != nil || c.ID != "" || c.LeaseTTL != 0 || c.SyncInterval != 0 {
+			t.Errorf("expected the defaults, got %+v, %v", c, err)
+		}
+	})
+	withEnv(map[string]string{ReplicaIDEnvVar: "pod-0", ReplicaLeaseTTLSecEnvVar: "30", ReplicaSyncIntervalSecEnvVar: "0"}, func() {
+		c, err := getReplicaConfig()
+		if err != nil || c.ID != "pod-0" || c.LeaseTTL != 30*time.Second || c.SyncInterval >= 0 {
+			t.Errorf("unexpected replica config %+v, %v", c, err)
+		}
+	})
+	for name, value := range map[string]string{ReplicaLeaseTTLSecEnvVar: "1", ReplicaSyncIntervalSecEnvVar: "-1"} {
+		withEnv(map[string]string{name: value}, func() {
+			if _, err := getReplicaConfig(); err == nil {
+				t.Errorf("expected an error for %s=%q", name, value)
+			}
+		})
+	}
+}
+
+func TestGetVaultConfig(t *testing.T) {
+	// the token alone is left to the vault CLI
+	withEnv(map[string]string{VaultAddrEnvVar: "", VaultTokenEnvVar: "root"}, func() {
+		c, err := getVaultConfig()
+		if err != nil || c != nil {
+			t.Errorf("expected no Vault, got %v, %v", c, err)
+		}
+	})
+	withEnv(map[string]string{VaultAddrEnvVar: "", VaultKubernetesRoleEnvVar: "mcpjungle"}, func() {
+		if _, err := getVaultConfig(); err == nil {
+			t.Error("expected an error for the Vault settings without an address")
+		}
+	})
+	withEnv(map[string]string{VaultAddrEnvVar: "https://vault:8200", VaultTokenEnvVar: "root"}, func() {
+		c, err := getVaultConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.AuthMethod != vault.AuthToken || c.Token != "root" || c.CacheTTL != 0 {
+			t.Errorf("unexpected config: %+v", c)
+		}
+	})
+	withEnv(map[string]string{
+		VaultAddrEnvVar:           "https://vault:8200",
+		VaultAuthMethodEnvVar:     "kubernetes",
+		VaultKubernetesRoleEnvVar: "mcpjungle",
+		VaultCacheTTLSecEnvVar:    "60",
+	}, func() {
+		c, err := getVaultConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.AuthMethod != vault.AuthKubernetes || c.KubernetesRole != "mcpjungle" || c.CacheTTL != time.Minute {
+			t.Errorf("unexpected config: %+v", c)
+		}
+	})
+	for _, env := range []map[string]string{
+		{VaultAddrEnvVar: "https://vault:8200"},
+		{VaultAddrEnvVar: "https://vault:8200", VaultTokenEnvVar: "root", VaultCacheTTLSecEnvVar: "0"},
+		{VaultAddrEnvVar: "https://vault:8200", VaultAuthMethodEnvVar: "approle"},
+	} {
+		withEnv(env, func() {
+			if _, err := getVaultConfig(); err == nil {
+				t.Errorf("expected an error for %v", env)
+			}
+		})
+	}
+}
+
+func TestGetDockerClient(t *testing.T) {
+	withEnv(map[string]string{DockerHostEnvVar: ""}, func() {
+		c, err := getDockerClient()
+		if err != nil || c.Host() != docker.DefaultHost {
+			t.Errorf("expected the local Docker daemon, got %v, %v", c, err)
+		}
+	})
+	withEnv(map[string]string{DockerHostEnvVar: "tcp://docker:2375"}, func() {
+		c, err := getDockerClient()
+		if err != nil || c.Host() != "tcp://docker:2375" {
+			t.Errorf("expected the configured Docker daemon, got %v, %v", c, err)
+		}
+	})
+	withEnv(map[string]string{DockerHostEnvVar: "ssh://docker"}, func() {
+		if _, err := getDockerClient(); err == nil {
+			t.Error("expected an error for an unsupported Docker host")
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var statusCmdRefresh bool
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the health of the registered MCP servers",
+	Long: "Show the aggregate health of the MCP servers registered in mcpjungle, and the outcome of the last health check of each of them.\n\n" +
+		"The server checks the health of MCP servers in the background, this command reports the result of the last checks.\n" +
+		"Admins can pass --refresh to have the servers checked right away.\n\n" +
+		"The command exits with a non-zero code if the health is critical, ie, all checked servers are unhealthy.",
+	Args: cobra.NoArgs,
+	RunE: runStatus,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "15",
+	},
+}
+
+func init() {
+	statusCmd.Flags().BoolVar(
+		&statusCmdRefresh,
+		"refresh",
+		false,
+		"Check the health of the servers now instead of reporting the last background checks (admin only)",
+	)
+	rootCmd.AddCommand(statusCmd)
+}
+
+// serverHealthColumns are the columns of the table of servers printed by the status command.
+var serverHealthColumns = []tableColumn[types.ServerHealth]{
+	{name: "server", value: func(h types.ServerHealth) string { return h.Name }},
+	{name: "status", value: func(h types.ServerHealth) string { return string(h.Status) }},
+	{name: "latency", value: func(h types.ServerHealth) string {
+		if h.CheckedAt == nil {
+			return "-"
+		}
+		return (time.Duration(h.LatencyMs) * time.Millisecond).String()
+	}},
+	{name: "failures", value: func(h types.ServerHealth) string { return strconv.Itoa(h.ConsecutiveFailures) }},
+	{name: "last check", value: func(h types.ServerHealth) string {
+		if h.CheckedAt == nil {
+			return "never"
+		}
+		return h.CheckedAt.Local().Format(time.DateTime)
+	}},
+	{name: "error", value: func(h types.ServerHealth) string { return h.Error }},
+	{name: "last sync", value: func(h types.ServerHealth) string {
+		switch {
+		case h.WarmingUp:
+			return "warming up"
+		case h.SyncedAt == nil:
+			return "-"
+		case h.SyncError != "":
+			return "failed"
+		}
+		return h.SyncedAt.Local().Format(time.DateTime)
+	}},
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	health, err := apiClient.GetServersHealth(commandContext(cmd), statusCmdRefresh)
+	if err != nil {
+		return fmt.Errorf("failed to get the health of MCP servers: %w", err)
+	}
+
+	if isStructuredOutput() {
+		if err := printOutput(cmd, health); err != nil {
+			return err
+		}
+	} else {
+		printServersHealth(cmd, health)
+	}
+
+	if health.Status == types.HealthCritical {
+		return fmt.Errorf("the health of MCP servers is %s", health.Status)
+	}
+	return nil
+}
+
+func printServersHealth(cmd *cobra.Command, health *types.ServersHealth) {
+	p := newPrinter(cmd)
+	st := newStyler(cmd.OutOrStdout())
+
+	unhealthy := 0
+	for _, s := range health.Servers {
+		if s.Status == types.ServerUnhealthy {
+			unhealthy++
+		}
+	}
+	p.Resultf("MCP servers: %s (%d of %d unhealthy)\n", st.Status(string(health.Status)), unhealthy, len(health.Servers))
+	if len(health.Servers) == 0 {
+		return
+	}
+	p.Resultln()
+	_ = renderTable(cmd.OutOrStdout(), serverHealthColumns, health.Servers)
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func withServersHealth(t *testing.T, health types.ServersHealth) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := http.StatusOK
+		if health.Status == types.HealthCritical {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(health)
+	}))
+	t.Cleanup(srv.Close)
+	orig := apiClient
+	apiClient = client.NewClient(srv.URL, "", srv.Client())
+	t.Cleanup(func() { apiClient = orig })
+}
+
+func TestStatusCommand(t *testing.T) {
+	checkedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("degraded", func(t *testing.T) {
+		withServersHealth(t, types.ServersHealth{Status: types.HealthDegraded, Servers: []types.ServerHealth{
+			{Name: "github", Status: types.ServerHealthy, CheckedAt: &checkedAt, LatencyMs: 42},
+			{Name: "slack", Status: types.ServerUnhealthy, CheckedAt: &checkedAt, ConsecutiveFailures: 3, Error: "connection refused"},
+			{Name: "notion", Status: types.ServerHealthUnknown},
+		}})
+
+		cmd := &cobra.Command{}
+		stdout := &bytes.Buffer{}
+		cmd.SetOut(stdout)
+		testhelpers.AssertNoError(t, runStatus(cmd, nil))
+
+		out := stdout.String()
+		testhelpers.AssertStringContains(t, out, "MCP servers: degraded (1 of 3 unhealthy)")
+		testhelpers.AssertStringContains(t, out, "42ms")
+		testhelpers.AssertStringContains(t, out, "connection refused")
+		testhelpers.AssertStringContains(t, out, "never")
+	})
+
+	t.Run("critical health is an error", func(t *testing.T) {
+		withServersHealth(t, types.ServersHealth{Status: types.HealthCritical, Servers: []types.ServerHealth{
+			{Name: "slack", Status: types.ServerUnhealthy, CheckedAt: &checkedAt, ConsecutiveFailures: 1},
+		}})
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(&bytes.Buffer{})
+		err := runStatus(cmd, nil)
+		testhelpers.AssertError(t, err)
+		testhelpers.AssertStringContains(t, err.Error(), "critical")
+	})
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// NoColorEnvVar is the conventional env var (see https://no-color.org) that disables colored output when set.
+const NoColorEnvVar = "NO_COLOR"
+
+// noColorFlag is set by the global --no-color flag.
+var noColorFlag bool
+
+// ANSI escape sequences used by the styler
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiDim    = "\033[2m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiInvert = "\033[7m"
+)
+
+// isTerminalWriter reports whether w is an interactive terminal.
+// It is a variable so that tests can override it.
+var isTerminalWriter = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// colorEnabled decides whether output written to w may contain ANSI color codes.
+// Colors are disabled when --no-color is passed, NO_COLOR is set, a structured output format is requested
+// or w is not a terminal (eg- output is piped to a file or another program).
+func colorEnabled(w io.Writer) bool {
+	if noColorFlag {
+		return false
+	}
+	if _, set := os.LookupEnv(NoColorEnvVar); set {
+		return false
+	}
+	if isStructuredOutput() {
+		return false
+	}
+	return isTerminalWriter(w)
+}
+
+// styler applies terminal styles to text.
+// A disabled styler returns all text unchanged, so callers never need to check whether colors are on.
+type styler struct {
+	enabled bool
+}
+
+// newStyler returns a styler for output written to w.
+func newStyler(w io.Writer) styler {
+	return styler{enabled: colorEnabled(w)}
+}
+
+func (s styler) wrap(code, text string) string {
+	if !s.enabled || text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// Green styles text that represents a good state, eg- healthy, enabled
+func (s styler) Green(text string) string { return s.wrap(ansiGreen, text) }
+
+// Yellow styles text that represents a state needing attention, eg- disabled, degraded
+func (s styler) Yellow(text string) string { return s.wrap(ansiYellow, text) }
+
+// Red styles text that represents a bad state or an error, eg- unhealthy
+func (s styler) Red(text string) string { return s.wrap(ansiRed, text) }
+
+// Dim styles secondary information that is less important than its surroundings
+func (s styler) Dim(text string) string { return s.wrap(ansiDim, text) }
+
+// Bold styles headings and primary identifiers like entity names
+func (s styler) Bold(text string) string { return s.wrap(ansiBold, text) }
+
+// Highlight makes text stand out from its surroundings, eg- a row that changed since the last refresh
+func (s styler) Highlight(text string) string { return s.wrap(ansiInvert, text) }
+
+// Status colors a status label according to the state it represents.
+// Unknown labels are returned unchanged.
+func (s styler) Status(label string) string {
+	switch strings.ToLower(label) {
+	case "enabled", "healthy", "ok", "pass", "up":
+		return s.Green(label)
+	case "disabled", "degraded", "warn", "warning", "unknown":
+		return s.Yellow(label)
+	case "unhealthy", "critical", "error", "fail", "failed", "down":
+		return s.Red(label)
+	default:
+		return label
+	}
+}
+
+// PrintError prints a command error to w, in red if w supports colors.
+// It is used by main to report errors returned by Execute.
+// Common failures are explained along with a hint on how to fix them, in which case
+// the underlying error is only printed in verbose mode.
+func PrintError(w io.Writer, err error) {
+	stopProgress()
+	st := newStyler(w)
+
+	registryURL := ""
+	var names entityNameLister
+	if apiClient != nil {
+		registryURL = apiClient.BaseURL()
+		names = listEntityNames
+	}
+
+	h, ok := explainError(err, registryURL, names)
+	if !ok {
+		_, _ = fmt.Fprintln(w, st.Red(err.Error()))
+		return
+	}
+	_, _ = fmt.Fprintln(w, st.Red(h.Message))
+	_, _ = fmt.Fprintln(w, "hint: "+h.Hint)
+	if verbosity > 0 {
+		_, _ = fmt.Fprintln(w, st.Dim("cause: "+err.Error()))
+	} else {
+		_, _ = fmt.Fprintln(w, st.Dim("(run with -v to see the underlying error)"))
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+// withTerminal makes every writer look like a terminal (or not) for the duration of a test.
+func withTerminal(t *testing.T, tty bool) {
+	t.Helper()
+	orig := isTerminalWriter
+	isTerminalWriter = func(io.Writer) bool { return tty }
+	t.Cleanup(func() { isTerminalWriter = orig })
+}
+
+func TestColorEnabled(t *testing.T) {
+	origNoColor, origSettings := noColorFlag, activeSettings
+	t.Cleanup(func() { noColorFlag, activeSettings = origNoColor, origSettings })
+
+	// make sure NO_COLOR from the environment running the tests doesn't interfere
+	if v, ok := os.LookupEnv(NoColorEnvVar); ok {
+		_ = os.Unsetenv(NoColorEnvVar)
+		t.Cleanup(func() { _ = os.Setenv(NoColorEnvVar, v) })
+	}
+
+	t.Run("non-terminal writer falls back to plain text", func(t *testing.T) {
+		// use the real TTY detection: a bytes.Buffer is never a terminal
+		testhelpers.AssertFalse(t, colorEnabled(&bytes.Buffer{}), "buffer should not be treated as a terminal")
+	})
+
+	t.Run("terminal writer enables colors", func(t *testing.T) {
+		withTerminal(t, true)
+		noColorFlag, activeSettings = false, nil
+		testhelpers.AssertTrue(t, colorEnabled(&bytes.Buffer{}), "expected colors on a terminal")
+	})
+
+	t.Run("--no-color disables colors", func(t *testing.T) {
+		withTerminal(t, true)
+		noColorFlag = true
+		t.Cleanup(func() { noColorFlag = false })
+		testhelpers.AssertFalse(t, colorEnabled(&bytes.Buffer{}), "expected no colors with --no-color")
+	})
+
+	t.Run("NO_COLOR disables colors", func(t *testing.T) {
+		withTerminal(t, true)
+		t.Setenv(NoColorEnvVar, "1")
+		testhelpers.AssertFalse(t, colorEnabled(&bytes.Buffer{}), "expected no colors with NO_COLOR set")
+	})
+
+	t.Run("JSON output never has colors", func(t *testing.T) {
+		withTerminal(t, true)
+		activeSettings = &cliSettings{Output: outputFormatJSON}
+		t.Cleanup(func() { activeSettings = nil })
+		testhelpers.AssertFalse(t, colorEnabled(&bytes.Buffer{}), "expected no colors in JSON mode")
+	})
+}
+
+func TestStyler(t *testing.T) {
+	plain := styler{enabled: false}
+	testhelpers.AssertEqual(t, "ENABLED", plain.Status("ENABLED"))
+	testhelpers.AssertEqual(t, "name", plain.Bold("name"))
+
+	colored := styler{enabled: true}
+	testhelpers.AssertEqual(t, ansiGreen+"ENABLED"+ansiReset, colored.Status("ENABLED"))
+	testhelpers.AssertEqual(t, ansiYellow+"DISABLED"+ansiReset, colored.Status("DISABLED"))
+	testhelpers.AssertEqual(t, ansiRed+"unhealthy"+ansiReset, colored.Status("unhealthy"))
+	testhelpers.AssertEqual(t, "custom", colored.Status("custom"))
+	testhelpers.AssertEqual(t, "", colored.Dim(""))
+}
+
+func TestPrintError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	PrintError(buf, errors.New("boom"))
+	testhelpers.AssertEqual(t, "boom\n", buf.String())
+	testhelpers.AssertFalse(t, strings.Contains(buf.String(), "\033["), "non-terminal output must not contain ANSI codes")
+}
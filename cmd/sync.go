@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var syncServerCmdAll bool
+
+var (
+	syncCmdDir      string
+	syncCmdCheck    bool
+	syncCmdPrune    bool
+	syncCmdWatch    bool
+	syncCmdDebounce time.Duration
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Synchronize MCP entities with the upstream MCP servers",
+	Long: "Synchronize MCP entities with the upstream MCP servers, see the subcommands.\n\n" +
+		"With --dir, the registry is reconciled against a directory with the layout of an export\n" +
+		"(see `mcpjungle export`), which is the source of truth: the servers and groups missing from the registry\n" +
+		"are created and the ones that drifted are updated. The entities of the registry that the directory\n" +
+		"doesn't hold are only deleted with --prune, they are reported otherwise. Every change is attempted\n" +
+		"even if some fail, and a report of the changes is printed.\n\n" +
+		"With --watch, the directory is reconciled again whenever its files change, once they stayed unchanged\n" +
+		"for the --debounce delay, so that a git checkout triggers a single reconcile, until interrupted.\n\n" +
+		"With --check, the configurations of a directory with the layout of an export (see `mcpjungle export`)\n" +
+		"are compared with the ones of the registry instead, without changing anything. The servers and groups\n" +
+		"that would be created, updated or deleted are reported along with the fields that differ,\n" +
+		"the values of secrets are masked. With -o json, the report is a JSON document meant for CI jobs,\n" +
+		"eg- to comment on a pull request changing the directory.\n\n" +
+		"The check exits with code 0 if the registry matches the directory, 1 if there are pending changes\n" +
+		"and 2 if it failed. The error field of the JSON report tells why it failed: invalid_config,\n" +
+		"registry_unreachable, unauthorized or registry_error.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "16",
+	},
+	Example: "  mcpjungle sync --dir ./configs\n" +
+		"  mcpjungle sync --dir ./configs --prune --watch\n" +
+		"  mcpjungle sync --dir .mcpjungle --check\n" +
+		"  mcpjungle sync --dir . --check --output json",
+	Args: cobra.NoArgs,
+	RunE: runSync,
+}
+
+var syncServerCmd = &cobra.Command{
+	Use:   "server [name...]",
+	Short: "Synchronize the tools and prompts of MCP servers",
+	Long: "Connect to the given MCP servers, or to all of them with --all, and update their tools and prompts\n" +
+		"in mcpjungle to match those they currently provide.\n\n" +
+		"The servers are synchronized concurrently. A server that cannot be synchronized is left unchanged and\n" +
+		"doesn't affect the others. Tools and prompts that were disabled remain disabled.\n\n" +
+		"The command exits with a non-zero code if any of the servers could not be synchronized.",
+	Example: "  mcpjungle sync server github slack\n" +
+		"  mcpjungle sync server --all",
+	RunE: runSyncServer,
+}
+
+func init() {
+	syncServerCmd.Flags().BoolVar(&syncServerCmdAll, "all", false, "Synchronize all registered MCP servers")
+
+	syncCmd.Flags().StringVarP(
+		&syncCmdDir, "dir", "d", defaultExportTargetDir, "Directory of configuration files to reconcile the registry with",
+	)
+	syncCmd.Flags().BoolVar(&syncCmdCheck, "check", false, "Only report the changes reconciling would make")
+	syncCmd.Flags().BoolVar(
+		&syncCmdPrune, "prune", false, "Delete the servers and groups of the registry that the directory doesn't hold",
+	)
+	syncCmd.Flags().BoolVar(&syncCmdWatch, "watch", false, "Reconcile again whenever the files of the directory change")
+	syncCmd.Flags().DurationVar(
+		&syncCmdDebounce,
+		"debounce",
+		defaultSyncDebounce,
+		"With --watch, how long the directory must stay unchanged before it is reconciled",
+	)
+	syncCmd.AddCommand(syncServerCmd)
+	rootCmd.AddCommand(syncCmd)
+}
+
+// serverSyncColumns are the columns of the table of servers printed by the sync server command.
+var serverSyncColumns = []tableColumn[types.ServerSyncResult]{
+	{name: "server", value: func(r types.ServerSyncResult) string { return r.Name }},
+	{name: "status", value: func(r types.ServerSyncResult) string { return string(r.Status) }},
+	{name: "tools", value: func(r types.ServerSyncResult) string {
+		if r.Status != types.ServerSynced {
+			return "-"
+		}
+		return fmt.Sprintf("+%d ~%d -%d", len(r.ToolsAdded), len(r.ToolsUpdated), len(r.ToolsRemoved))
+	}},
+	{name: "duration", value: func(r types.ServerSyncResult) string {
+		return (time.Duration(r.DurationMs) * time.Millisecond).String()
+	}},
+	{name: "error", value: func(r types.ServerSyncResult) string { return r.Error }},
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	if !syncCmdCheck {
+		if !cmd.Flags().Changed("dir") {
+			if syncCmdPrune || syncCmdWatch {
+				// the directory to reconcile with is never implied
+				return usageErrorf("--prune and --watch require --dir")
+			}
+			return cmd.Help()
+		}
+		return runSyncApply(cmd)
+	}
+	if syncCmdPrune || syncCmdWatch {
+		return usageErrorf("--check doesn't change anything, it can't be used with --prune or --watch")
+	}
+
+	report, err := checkSyncDir(commandContext(cmd), syncCmdDir)
+	if isStructuredOutput() {
+		if printErr := printOutput(cmd, report); printErr != nil {
+			return &exitCodeError{code: syncCheckExitError, err: printErr}
+		}
+	} else if err == nil {
+		renderSyncCheckReport(newPrinter(cmd), report)
+	}
+	switch {
+	case err != nil:
+		return &exitCodeError{code: syncCheckExitError, err: err}
+	case report.Status == syncCheckChanges:
+		// the changes were reported already
+		return &exitCodeError{code: syncCheckExitChanges, err: ErrSilent}
+	}
+	return nil
+}
+
+func runSyncServer(cmd *cobra.Command, args []string) error {
+	if syncServerCmdAll == (len(args) > 0) {
+		return usageErrorf("specify the names of the servers to synchronize, or --all to synchronize all of them")
+	}
+
+	result, err := apiClient.SyncServers(commandContext(cmd), args...)
+	if err != nil {
+		return fmt.Errorf("failed to synchronize MCP servers: %w", err)
+	}
+
+	if isStructuredOutput() {
+		if err := printOutput(cmd, result); err != nil {
+			return err
+		}
+	} else {
+		p := newPrinter(cmd)
+		p.Resultf(
+			"Synchronized %d MCP servers, %d failed, in %s\n",
+			result.Synced, result.Failed, time.Duration(result.DurationMs)*time.Millisecond,
+		)
+		if len(result.Servers) > 0 {
+			p.Resultln()
+			_ = renderTable(cmd.OutOrStdout(), serverSyncColumns, result.Servers)
+		}
+	}
+
+	if result.Failed > 0 {
+		return fmt.Errorf("%d of %d MCP servers could not be synchronized", result.Failed, len(result.Servers))
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// Statuses of the changes of a reconcile report.
+const (
+	syncChangeApplied = "applied"
+	syncChangeFailed  = "failed"
+	// syncChangeKept is the status of the deletions, which are only applied with --prune
+	syncChangeKept = "kept"
+)
+
+// defaultSyncDebounce is how long the directory must stay unchanged before `sync --watch` reconciles it,
+// so that a checkout rewriting many files triggers a single reconcile.
+const defaultSyncDebounce = 2 * time.Second
+
+// syncWatchPollInterval is how often `sync --watch` looks for changes in the directory.
+const syncWatchPollInterval = 500 * time.Millisecond
+
+// syncReport is the report of a reconcile of the registry against a directory, printed as is with -o json.
+type syncReport struct {
+	Dir      string       `json:"dir"`
+	Registry string       `json:"registry"`
+	Time     time.Time    `json:"time"`
+	Applied  int          `json:"applied"`
+	Failed   int          `json:"failed"`
+	Kept     int          `json:"kept"`
+	Changes  []syncChange `json:"changes"`
+}
+
+// syncChange is a change of a reconcile, with its outcome.
+type syncChange struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// runSyncApply reconciles the registry against the directory once, or every time it changes with --watch.
+func runSyncApply(cmd *cobra.Command) error {
+	if syncCmdDebounce <= 0 {
+		return usageErrorf("--debounce must be a positive duration")
+	}
+	if !syncCmdWatch {
+		report, err := reconcileSyncDir(commandContext(cmd), syncCmdDir, syncCmdPrune)
+		if err != nil {
+			return err
+		}
+		if err := printSyncReport(cmd, report); err != nil {
+			return err
+		}
+		if report.Failed > 0 {
+			return fmt.Errorf("%d of %d changes could not be applied", report.Failed, len(report.Changes))
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return watchSyncDir(ctx, syncCmdDir, syncCmdDebounce, func() {
+		// a failed reconcile doesn't stop the watch, the next change of the directory may well fix it
+		report, err := reconcileSyncDir(ctx, syncCmdDir, syncCmdPrune)
+		if err != nil {
+			if ctx.Err() == nil {
+				newPrinter(cmd).Warnf("failed to reconcile %s: %v", syncCmdDir, err)
+			}
+			return
+		}
+		_ = printSyncReport(cmd, report)
+	})
+}
+
+// reconcileSyncDir applies the changes that make the registry match the directory: the servers and groups that are
+// missing are created and the ones that drifted are updated. The entities of the registry that the directory
+// doesn't hold are only deleted if prune is set.
+// Every change is applied even if some of them fail, the failures are reported. The groups are created after the
+// servers and deleted before them, since they reference their tools.
+func reconcileSyncDir(ctx context.Context, dir string, prune bool) (*syncReport, error) {
+	diff, local, err := diffSyncDir(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	report := &syncReport{Dir: dir, Registry: diff.Registry, Time: nowFunc(), Changes: []syncChange{}}
+
+	servers := make(map[string]*types.RegisterServerInput, len(local.Servers))
+	for _, s := range local.Servers {
+		servers[s.Name] = s
+	}
+	groups := make(map[string]types.ToolGroup, len(local.Groups))
+	for _, g := range local.Groups {
+		// the version of the group is the one of the exported server, it must not be sent as a precondition
+		g.Version = 0
+		groups[g.Name] = g
+	}
+
+	apply := func(c syncCheckChange) error {
+		switch {
+		case c.Kind == syncCheckKindServer && c.Action == importActionDelete:
+			return apiClient.DeregisterServerContext(ctx, c.Name)
+		case c.Kind == syncCheckKindGroup && c.Action == importActionDelete:
+			return apiClient.DeleteToolGroupContext(ctx, c.Name)
+		case c.Kind == syncCheckKindServer:
+			s := servers[c.Name]
+			if hasSecretPlaceholders([]*types.RegisterServerInput{s}) {
+				return errors.New("its secrets are redacted and the registry has no value for them")
+			}
+			if c.Action == importActionCreate {
+				_, err := apiClient.RegisterServerContext(ctx, s)
+				return err
+			}
+			_, err := apiClient.UpdateServerContext(ctx, s)
+			return err
+		default:
+			g := groups[c.Name]
+			if c.Action == importActionCreate {
+				_, err := apiClient.CreateToolGroupContext(ctx, &g)
+				return err
+			}
+			_, err := apiClient.UpdateToolGroupContext(ctx, &g)
+			return err
+		}
+	}
+
+	for _, c := range syncApplyOrder(diff.Changes) {
+		change := syncChange{Kind: c.Kind, Name: c.Name, Action: c.Action, Status: syncChangeApplied}
+		switch {
+		case c.Action == importActionDelete && !prune:
+			change.Status = syncChangeKept
+			report.Kept++
+		default:
+			if err := apply(c); err != nil {
+				change.Status, change.Error = syncChangeFailed, err.Error()
+				report.Failed++
+			} else {
+				report.Applied++
+			}
+		}
+		report.Changes = append(report.Changes, change)
+	}
+	return report, nil
+}
+
+// syncApplyOrder returns the changes in the order they are applied: the creations and updates of servers, then of
+// groups, then the deletions of groups, then of servers. The changes of a kind keep their order, by name.
+func syncApplyOrder(changes []syncCheckChange) []syncCheckChange {
+	rank := func(c syncCheckChange) int {
+		switch {
+		case c.Action != importActionDelete && c.Kind == syncCheckKindServer:
+			return 0
+		case c.Action != importActionDelete:
+			return 1
+		case c.Kind == syncCheckKindGroup:
+			return 2
+		default:
+			return 3
+		}
+	}
+	ordered := slices.Clone(changes)
+	slices.SortStableFunc(ordered, func(a, b syncCheckChange) int { return rank(a) - rank(b) })
+	return ordered
+}
+
+// printSyncReport prints the report of a reconcile, as JSON with -o json.
+func printSyncReport(cmd *cobra.Command, r *syncReport) error {
+	if isStructuredOutput() {
+		return printOutput(cmd, r)
+	}
+	p := newPrinter(cmd)
+	if len(r.Changes) == 0 {
+		p.Resultf("[%s] The registry matches %s, there are no changes\n", r.Time.Format(time.TimeOnly), r.Dir)
+		return nil
+	}
+	p.Resultf("[%s] Reconciled the registry with %s\n", r.Time.Format(time.TimeOnly), r.Dir)
+	signs := map[string]string{importActionCreate: "+", importActionUpdate: "~", importActionDelete: "-"}
+	for _, c := range r.Changes {
+		switch c.Status {
+		case syncChangeFailed:
+			p.Resultf("! %s %s (%s): failed: %s\n", c.Kind, c.Name, c.Action, c.Error)
+		case syncChangeKept:
+			p.Resultf("  %s %s (%s): kept, use --prune to delete it\n", c.Kind, c.Name, c.Action)
+		default:
+			p.Resultf("%s %s %s (%s)\n", signs[c.Action], c.Kind, c.Name, c.Action)
+		}
+	}
+	p.Resultf("%d applied, %d failed, %d kept\n", r.Applied, r.Failed, r.Kept)
+	return nil
+}
+
+// watchSyncDir runs reconcile once, then again every time the configuration files of the directory change,
+// until ctx is cancelled. A change is only acted upon once the directory stayed unchanged for the debounce delay,
+// so that a checkout rewriting many files triggers a single reconcile.
+// The directory is polled, since the CLI doesn't depend on a file notification library.
+func watchSyncDir(ctx context.Context, dir string, debounce time.Duration, reconcile func()) error {
+	last, err := syncDirFingerprint(dir)
+	if err != nil {
+		return err
+	}
+	reconcile()
+
+	ticker := time.NewTicker(syncWatchPollInterval)
+	defer ticker.Stop()
+	pending, changedAt := false, time.Time{}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		current, err := syncDirFingerprint(dir)
+		if err != nil {
+			// eg- the directory is being replaced by a checkout, it is looked at again on the next tick
+			continue
+		}
+		if current != last {
+			last, pending, changedAt = current, true, time.Now()
+			continue
+		}
+		if pending && time.Since(changedAt) >= debounce {
+			pending = false
+			reconcile()
+		}
+	}
+}
+
+// syncDirFingerprint returns a fingerprint of the configuration files of the directory, which changes whenever
+// one of them is added, removed or modified.
+func syncDirFingerprint(dir string) (string, error) {
+	var fingerprint string
+	for _, sub := range []string{exportToolGroupsDir, exportMcpServersDir} {
+		files, err := filepath.Glob(filepath.Join(dir, sub, "*"))
+		if err != nil {
+			return "", err
+		}
+		for _, f := range files {
+			if !isConfigFile(f) {
+				continue
+			}
+			info, err := os.Stat(f)
+			if err != nil {
+				return "", err
+			}
+			fingerprint += fmt.Sprintf("%s:%d:%d\n", f, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return fingerprint, nil
+}
@@ -0,0 +1,423 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// syncCheckReportVersion is the version of the format of the report of `sync --check`.
+// It is bumped on every change that isn't backwards compatible, ie- anything but adding fields.
+const syncCheckReportVersion = 1
+
+// Statuses of the report of `sync --check`.
+const (
+	syncCheckInSync  = "in_sync"
+	syncCheckChanges = "changes"
+	syncCheckFailed  = "error"
+)
+
+// Exit codes of `sync --check`, which exits with ExitOK if there are no changes. They deviate from the exit codes
+// of the other commands so that CI jobs can tell drift apart from failures, without needing to parse the report.
+const (
+	syncCheckExitChanges = 1
+	syncCheckExitError   = 2
+)
+
+// Kinds of the errors of the report of `sync --check`.
+const (
+	// syncCheckErrInvalidConfig means that the directory could not be read, or holds invalid configurations.
+	syncCheckErrInvalidConfig = "invalid_config"
+	// syncCheckErrRegistryUnreachable means that the mcpjungle server could not be reached at all.
+	syncCheckErrRegistryUnreachable = "registry_unreachable"
+	// syncCheckErrUnauthorized means that the registry rejected the credentials of the CLI.
+	syncCheckErrUnauthorized = "unauthorized"
+	// syncCheckErrRegistry means that the registry failed to return the configurations.
+	syncCheckErrRegistry = "registry_error"
+)
+
+// Kinds of the entities of the report of `sync --check`.
+const (
+	syncCheckKindServer = "server"
+	syncCheckKindGroup  = "group"
+)
+
+// importActionDelete is the action of the entities of the registry that the directory doesn't hold anymore.
+const importActionDelete = "delete"
+
+// syncCheckReport is the report of `mcpjungle sync --dir <dir> --check`, printed as is with `-o json`.
+// Its format is stable, so that CI jobs can render it, eg- as a comment on a pull request:
+//
+//	{
+//	  "version": 1,
+//	  "dir": ".mcpjungle",
+//	  "registry": "http://127.0.0.1:8080",
+//	  "status": "changes",
+//	  "summary": {"create": 1, "update": 1, "delete": 0},
+//	  "changes": [
+//	    {"kind": "group", "name": "ci-tools", "action": "create", "fields": [
+//	      {"field": "included_servers", "new": ["github"]}
+//	    ]},
+//	    {"kind": "server", "name": "github", "action": "update", "fields": [
+//	      {"field": "bearer_token", "old": "****abcd", "new": "****wxyz", "secret": true},
+//	      {"field": "description", "old": "GitHub", "new": "GitHub tools"}
+//	    ]}
+//	  ]
+//	}
+//
+// status is in_sync, changes or error. The changes are sorted by kind, then name, and their fields by name.
+// A field is the path of a value in the configuration of the entity, eg- env.GITHUB_TOKEN. old is left out if the
+// field isn't set in the registry, new if it isn't set in the directory. The values of secret fields are masked.
+// If status is error, changes is empty and error holds the kind of the failure and its message, eg-
+// {"kind": "registry_unreachable", "message": "..."}; kind is one of invalid_config, registry_unreachable,
+// unauthorized or registry_error.
+type syncCheckReport struct {
+	Version  int               `json:"version"`
+	Dir      string            `json:"dir"`
+	Registry string            `json:"registry"`
+	Status   string            `json:"status"`
+	Summary  syncCheckSummary  `json:"summary"`
+	Changes  []syncCheckChange `json:"changes"`
+	Error    *syncCheckError   `json:"error,omitempty"`
+}
+
+// syncCheckSummary counts the changes of a report by action.
+type syncCheckSummary struct {
+	Create int `json:"create"`
+	Update int `json:"update"`
+	Delete int `json:"delete"`
+}
+
+// syncCheckChange is the change of an entity that applying the directory would make to the registry.
+type syncCheckChange struct {
+	Kind   string               `json:"kind"`
+	Name   string               `json:"name"`
+	Action string               `json:"action"`
+	Fields []syncCheckFieldDiff `json:"fields"`
+}
+
+// syncCheckFieldDiff is the change of a field of the configuration of an entity.
+type syncCheckFieldDiff struct {
+	Field  string `json:"field"`
+	Old    any    `json:"old,omitempty"`
+	New    any    `json:"new,omitempty"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+// syncCheckError is the failure of a check.
+type syncCheckError struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// checkSyncDir compares the configurations of the directory with the ones of the registry, without changing anything.
+// If the check fails, the returned report holds the failure too.
+func checkSyncDir(ctx context.Context, dir string) (*syncCheckReport, error) {
+	report, _, err := diffSyncDir(ctx, dir)
+	return report, err
+}
+
+// diffSyncDir is checkSyncDir, it also returns the configurations read from the directory, with the secrets
+// redacted by the export resolved, for `sync` to apply the changes.
+func diffSyncDir(ctx context.Context, dir string) (*syncCheckReport, *exportBundle, error) {
+	report := &syncCheckReport{
+		Version:  syncCheckReportVersion,
+		Dir:      dir,
+		Registry: apiClient.BaseURL(),
+		Changes:  []syncCheckChange{},
+	}
+	fail := func(kind string, err error) (*syncCheckReport, *exportBundle, error) {
+		report.Status = syncCheckFailed
+		report.Error = &syncCheckError{Kind: kind, Message: err.Error()}
+		return report, nil, err
+	}
+
+	local, err := readBundleDir(dir)
+	if err != nil {
+		return fail(syncCheckErrInvalidConfig, err)
+	}
+	if err := validateSyncDir(local); err != nil {
+		return fail(syncCheckErrInvalidConfig, err)
+	}
+	servers, err := apiClient.GetServerConfigsContext(ctx)
+	if err != nil {
+		return fail(syncCheckRegistryErrorKind(err), fmt.Errorf("failed to fetch mcp server configurations: %w", err))
+	}
+	groups, err := apiClient.GetToolGroupConfigsContext(ctx)
+	if err != nil {
+		return fail(syncCheckRegistryErrorKind(err), fmt.Errorf("failed to fetch tool group configurations: %w", err))
+	}
+
+	// the secrets redacted by the export are the ones of the registry, the placeholders that name none of them are
+	// compared as they are
+	for _, s := range local.Servers {
+		_ = resolveSecretPlaceholders(s, servers)
+	}
+
+	serverChanges, err := diffEntities(
+		syncCheckKindServer, local.Servers, servers, func(s *types.RegisterServerInput) string { return s.Name },
+	)
+	if err != nil {
+		return fail(syncCheckErrInvalidConfig, err)
+	}
+	// the version and the UUID of a group are assigned by the registry
+	groupChanges, err := diffEntities(
+		syncCheckKindGroup, local.Groups, groups, func(g types.ToolGroup) string { return g.Name }, "version", "uuid",
+	)
+	if err != nil {
+		return fail(syncCheckErrInvalidConfig, err)
+	}
+
+	for _, c := range append(groupChanges, serverChanges...) {
+		switch c.Action {
+		case importActionCreate:
+			report.Summary.Create++
+		case importActionUpdate:
+			report.Summary.Update++
+		case importActionDelete:
+			report.Summary.Delete++
+		}
+		report.Changes = append(report.Changes, c)
+	}
+	report.Status = syncCheckInSync
+	if len(report.Changes) > 0 {
+		report.Status = syncCheckChanges
+	}
+	return report, local, nil
+}
+
+// validateSyncDir checks the configurations of the directory like the registry would, so that a pull request
+// breaking them fails the check instead of reporting changes that could never be applied.
+func validateSyncDir(b *exportBundle) error {
+	var errs []error
+	servers := make(map[string]bool, len(b.Servers))
+	for _, s := range b.Servers {
+		if err := s.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid configuration of MCP server %s: %w", s.Name, err))
+		} else if servers[s.Name] {
+			errs = append(errs, fmt.Errorf("MCP server %s is configured more than once", s.Name))
+		}
+		servers[s.Name] = true
+	}
+	groups := make(map[string]bool, len(b.Groups))
+	for i := range b.Groups {
+		g := &b.Groups[i]
+		if err := g.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid configuration of tool group %s: %w", g.Name, err))
+		} else if groups[g.Name] {
+			errs = append(errs, fmt.Errorf("tool group %s is configured more than once", g.Name))
+		}
+		groups[g.Name] = true
+	}
+	return errors.Join(errs...)
+}
+
+// syncCheckRegistryErrorKind returns the kind of the failure of a request to the registry.
+func syncCheckRegistryErrorKind(err error) string {
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.Code == types.ErrorCodeUnauthorized || apiErr.Code == types.ErrorCodeForbidden {
+			return syncCheckErrUnauthorized
+		}
+		return syncCheckErrRegistry
+	}
+	if isConnectionError(err) {
+		return syncCheckErrRegistryUnreachable
+	}
+	return syncCheckErrRegistry
+}
+
+// diffEntities returns the changes that would make the live entities match the local ones, sorted by name.
+// The ignored fields are left out of the comparison.
+func diffEntities[T any](
+	kind string, local, live []T, name func(T) string, ignored ...string,
+) ([]syncCheckChange, error) {
+	fields := func(entities []T) (map[string]map[string]any, error) {
+		m := make(map[string]map[string]any, len(entities))
+		for _, e := range entities {
+			f, err := flattenEntityConfig(e)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compare %s %s: %w", kind, name(e), err)
+			}
+			for _, field := range ignored {
+				delete(f, field)
+			}
+			m[name(e)] = f
+		}
+		return m, nil
+	}
+	localFields, err := fields(local)
+	if err != nil {
+		return nil, err
+	}
+	liveFields, err := fields(live)
+	if err != nil {
+		return nil, err
+	}
+
+	names := slices.Sorted(maps.Keys(localFields))
+	for n := range liveFields {
+		if _, ok := localFields[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []syncCheckChange
+	for _, n := range names {
+		oldFields, exists := liveFields[n]
+		newFields, wanted := localFields[n]
+		c := syncCheckChange{Kind: kind, Name: n, Action: importActionUpdate, Fields: diffFields(oldFields, newFields)}
+		switch {
+		case !exists:
+			c.Action = importActionCreate
+		case !wanted:
+			c.Action = importActionDelete
+		case len(c.Fields) == 0:
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// flattenEntityConfig returns the fields of the configuration of an entity that are set, by their path,
+// eg- env.GITHUB_TOKEN. Lists are compared as a whole, so they are not flattened.
+// Fields set to their zero value are left out, so that omitting a field is the same as setting it to its default.
+// The entity is compared in its canonical form, so that eg- the tools of a group can be listed in any order.
+func flattenEntityConfig(entity any) (map[string]any, error) {
+	data, err := json.Marshal(canonicalConfig(entity))
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]any)
+	var flatten func(prefix string, m map[string]any)
+	flatten = func(prefix string, m map[string]any) {
+		for k, v := range m {
+			if nested, ok := v.(map[string]any); ok {
+				flatten(prefix+k+".", nested)
+				continue
+			}
+			if !isZeroConfigValue(v) {
+				fields[prefix+k] = v
+			}
+		}
+	}
+	flatten("", doc)
+	return fields, nil
+}
+
+// isZeroConfigValue reports whether v, decoded from JSON, is the zero value of its type.
+func isZeroConfigValue(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// diffFields returns the fields whose values differ between old and new, sorted by path.
+func diffFields(oldFields, newFields map[string]any) []syncCheckFieldDiff {
+	paths := slices.Collect(maps.Keys(oldFields))
+	for p := range newFields {
+		if _, ok := oldFields[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	slices.Sort(paths)
+
+	diffs := []syncCheckFieldDiff{}
+	for _, p := range paths {
+		o, n := oldFields[p], newFields[p]
+		if reflect.DeepEqual(o, n) {
+			continue
+		}
+		d := syncCheckFieldDiff{Field: p, Old: o, New: n}
+		if isSecretConfigField(p) {
+			d.Secret = true
+			d.Old, d.New = maskConfigValue(o), maskConfigValue(n)
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs
+}
+
+// isSecretConfigField reports whether the field at path holds a secret, whose value must not be shown.
+// The values of env vars are all treated as secrets, since they often hold the credentials of stdio servers.
+func isSecretConfigField(path string) bool {
+	if strings.HasPrefix(path, "env.") {
+		return true
+	}
+	name := strings.ToLower(path[strings.LastIndex(path, ".")+1:])
+	for _, s := range []string{"token", "secret", "password"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskConfigValue masks a secret value, nil is kept so that an unset field is still left out of the report.
+func maskConfigValue(v any) any {
+	if v == nil {
+		return nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		data, _ := json.Marshal(v)
+		s = string(data)
+	}
+	return config.MaskSecret(s)
+}
+
+// renderSyncCheckReport prints the changes of a report in a human-friendly format.
+func renderSyncCheckReport(p *printer, r *syncCheckReport) {
+	if r.Status == syncCheckInSync {
+		p.Resultf("The registry matches %s, there are no changes\n", r.Dir)
+		return
+	}
+	signs := map[string]string{importActionCreate: "+", importActionUpdate: "~", importActionDelete: "-"}
+	for _, c := range r.Changes {
+		p.Resultf("%s %s %s (%s)\n", signs[c.Action], c.Kind, c.Name, c.Action)
+		for _, f := range c.Fields {
+			p.Resultf("    %s: %s -> %s\n", f.Field, formatConfigValue(f.Old), formatConfigValue(f.New))
+		}
+	}
+	p.Resultf(
+		"\n%d pending changes: %d to create, %d to update, %d to delete\n",
+		len(r.Changes), r.Summary.Create, r.Summary.Update, r.Summary.Delete,
+	)
+}
+
+// formatConfigValue formats the value of a field of a report, as JSON.
+func formatConfigValue(v any) string {
+	if v == nil {
+		return "(unset)"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestSyncServerCommand(t *testing.T) {
+	var input types.SyncServersInput
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"/api/v1/servers/sync": func(w http.ResponseWriter, r *http.Request) {
+			input = types.SyncServersInput{}
+			_ = json.NewDecoder(r.Body).Decode(&input)
+			writeTestJSON(w, http.StatusMultiStatus, types.SyncServersResult{Synced: 1, Failed: 1, DurationMs: 1500, Servers: []types.ServerSyncResult{
+				{Name: "github", Status: types.ServerSynced, ToolsAdded: []string{"github__git_commit"}, DurationMs: 42},
+				{Name: "slack", Status: types.ServerSyncFailed, Error: "connection refused"},
+			}})
+		},
+	})
+	t.Cleanup(func() { syncServerCmdAll = false })
+
+	cmd := &cobra.Command{}
+	stdout := &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	err := runSyncServer(cmd, []string{"github", "slack"})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "1 of 2 MCP servers could not be synchronized")
+	testhelpers.AssertEqual(t, 2, len(input.Servers))
+
+	out := stdout.String()
+	testhelpers.AssertStringContains(t, out, "Synchronized 1 MCP servers, 1 failed, in 1.5s")
+	testhelpers.AssertStringContains(t, out, "+1 ~0 -0")
+	testhelpers.AssertStringContains(t, out, "connection refused")
+
+	// --all sends no names, so that the registry synchronizes all of its servers
+	syncServerCmdAll = true
+	_ = runSyncServer(newExitCodeTestCmd(), nil)
+	testhelpers.AssertEqual(t, 0, len(input.Servers))
+
+	// either names or --all must be given
+	err = runSyncServer(newExitCodeTestCmd(), []string{"github"})
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+	syncServerCmdAll = false
+	err = runSyncServer(newExitCodeTestCmd(), nil)
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+}
+
+// writeSyncTestDir writes a directory with the layout of an export, holding the given server and group configurations.
+func writeSyncTestDir(t *testing.T, servers []*types.RegisterServerInput, groups []types.ToolGroup) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, sub := range []string{exportMcpServersDir, exportToolGroupsDir} {
+		testhelpers.AssertNoError(t, os.Mkdir(filepath.Join(dir, sub), 0o755))
+	}
+	for _, s := range servers {
+		testhelpers.AssertNoError(t, writeConfigFile(filepath.Join(dir, exportMcpServersDir), s.Name, configFormatJSON, s))
+	}
+	for _, g := range groups {
+		testhelpers.AssertNoError(t, writeConfigFile(filepath.Join(dir, exportToolGroupsDir), g.Name, configFormatJSON, g))
+	}
+	return dir
+}
+
+// newSyncTestCmd returns a command running `mcpjungle sync` with the given arguments, parsed by the flags of the
+// sync command. The flags are reset to their defaults at the end of the test.
+func newSyncTestCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	t.Cleanup(func() {
+		syncCmd.Flags().VisitAll(func(f *pflag.Flag) {
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		})
+	})
+	cmd := &cobra.Command{Use: "sync", Args: cobra.NoArgs, RunE: runSync, SilenceErrors: true, SilenceUsage: true}
+	cmd.Flags().AddFlagSet(syncCmd.Flags())
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	return cmd
+}
+
+func TestSyncCheck(t *testing.T) {
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []*types.RegisterServerInput{
+				{Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/", BearerToken: "ghp_old_token_abcd"},
+				{Name: "slack", Transport: "stdio", Command: "npx", Args: []string{"-y", "slack-mcp"}},
+			})
+		},
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{UUID: "9a7b330a-a736-41e5-8b6c-2e8f8e6d2b1c", Name: "ops", IncludedServers: []string{"github"}, Version: 3}})
+		},
+		"/api/v1/": func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request %s %s, the check must not change anything", r.Method, r.URL.Path)
+		},
+	})
+	github := &types.RegisterServerInput{
+		Name: "github", Transport: "streamable_http", Description: "GitHub tools",
+		URL: "https://api.githubcopilot.com/mcp/", BearerToken: "ghp_new_token_wxyz",
+	}
+	ops := types.ToolGroup{Name: "ops", IncludedServers: []string{"github"}}
+	ci := types.ToolGroup{Name: "ci", IncludedTools: []string{"github__git_commit"}}
+	dir := writeSyncTestDir(t, []*types.RegisterServerInput{github}, []types.ToolGroup{ops, ci})
+	withOutputFormat(t, outputFormatJSON)
+
+	cmd := newSyncTestCmd(t, "--dir", dir, "--check")
+	stdout := &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	err := cmd.Execute()
+	testhelpers.AssertEqual(t, syncCheckExitChanges, ExitCodeForError(err))
+	testhelpers.AssertTrue(t, errors.Is(err, ErrSilent), "expected the pending changes not to be printed as an error")
+
+	var report syncCheckReport
+	testhelpers.AssertNoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	testhelpers.AssertEqual(t, syncCheckReportVersion, report.Version)
+	testhelpers.AssertEqual(t, syncCheckChanges, report.Status)
+	testhelpers.AssertEqual(t, syncCheckSummary{Create: 1, Update: 1, Delete: 1}, report.Summary)
+	var changes []string
+	for _, c := range report.Changes {
+		changes = append(changes, c.Action+" "+c.Kind+" "+c.Name)
+	}
+	// the group is unchanged, its version and UUID are assigned by the registry
+	testhelpers.AssertEqual(t, "create group ci,update server github,delete server slack", strings.Join(changes, ","))
+
+	fields := report.Changes[1].Fields
+	testhelpers.AssertEqual(t, 2, len(fields))
+	testhelpers.AssertEqual(t, syncCheckFieldDiff{Field: "bearer_token", Old: "****abcd", New: "****wxyz", Secret: true}, fields[0])
+	testhelpers.AssertEqual(t, syncCheckFieldDiff{Field: "description", New: "GitHub tools"}, fields[1])
+	testhelpers.AssertStringNotContains(t, stdout.String(), "ghp_")
+	testhelpers.AssertEqual(t, "[-y slack-mcp]", fmt.Sprint(report.Changes[2].Fields[0].Old))
+
+	// the human-friendly report
+	withOutputFormat(t, outputFormatTable)
+	stdout.Reset()
+	err = cmd.Execute()
+	testhelpers.AssertEqual(t, syncCheckExitChanges, ExitCodeForError(err))
+	out := stdout.String()
+	testhelpers.AssertStringContains(t, out, "~ server github (update)")
+	testhelpers.AssertStringContains(t, out, `bearer_token: "****abcd" -> "****wxyz"`)
+	testhelpers.AssertStringContains(t, out, "3 pending changes: 1 to create, 1 to update, 1 to delete")
+}
+
+func TestSyncCheckInSync(t *testing.T) {
+	github := &types.RegisterServerInput{Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/"}
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []*types.RegisterServerInput{github})
+		},
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{})
+		},
+	})
+	dir := writeSyncTestDir(t, []*types.RegisterServerInput{github}, nil)
+	withOutputFormat(t, outputFormatJSON)
+
+	cmd := newSyncTestCmd(t, "-d", dir, "--check")
+	stdout := &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	testhelpers.AssertNoError(t, cmd.Execute())
+	var report syncCheckReport
+	testhelpers.AssertNoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	testhelpers.AssertEqual(t, syncCheckInSync, report.Status)
+	testhelpers.AssertStringContains(t, stdout.String(), `"changes": []`)
+}
+
+func TestSyncCheckErrors(t *testing.T) {
+	withOutputFormat(t, outputFormatJSON)
+	run := func(t *testing.T, dir string) (*syncCheckReport, error) {
+		t.Helper()
+		cmd := newSyncTestCmd(t, "--dir", dir, "--check")
+		stdout := &bytes.Buffer{}
+		cmd.SetOut(stdout)
+		err := cmd.Execute()
+		testhelpers.AssertEqual(t, syncCheckExitError, ExitCodeForError(err))
+		var report syncCheckReport
+		testhelpers.AssertNoError(t, json.Unmarshal(stdout.Bytes(), &report))
+		testhelpers.AssertEqual(t, syncCheckFailed, report.Status)
+		return &report, err
+	}
+	dir := writeSyncTestDir(t, []*types.RegisterServerInput{
+		{Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/"},
+	}, nil)
+
+	t.Run("unreachable registry", func(t *testing.T) {
+		orig := apiClient
+		t.Cleanup(func() { apiClient = orig })
+		apiClient = client.NewClient("http://127.0.0.1:1", "", &http.Client{})
+		report, err := run(t, dir)
+		testhelpers.AssertEqual(t, syncCheckErrRegistryUnreachable, report.Error.Kind)
+		// the error is still printed, with the hints to reach the registry
+		testhelpers.AssertFalse(t, errors.Is(err, ErrSilent), "expected the error to be printed")
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		withRegistryHandlers(t, map[string]http.HandlerFunc{
+			"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+				writeTestJSON(w, http.StatusUnauthorized, map[string]any{
+					"error": map[string]string{"code": string(types.ErrorCodeUnauthorized), "message": "invalid access token"},
+				})
+			},
+		})
+		report, _ := run(t, dir)
+		testhelpers.AssertEqual(t, syncCheckErrUnauthorized, report.Error.Kind)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		withRegistryHandlers(t, map[string]http.HandlerFunc{})
+		invalid := writeSyncTestDir(t, []*types.RegisterServerInput{{Name: "github", Transport: "streamable_http"}}, nil)
+		report, _ := run(t, invalid)
+		testhelpers.AssertEqual(t, syncCheckErrInvalidConfig, report.Error.Kind)
+		testhelpers.AssertStringContains(t, report.Error.Message, "invalid configuration of MCP server github")
+
+		report, _ = run(t, t.TempDir())
+		testhelpers.AssertEqual(t, syncCheckErrInvalidConfig, report.Error.Kind)
+		testhelpers.AssertStringContains(t, report.Error.Message, "is not an export directory")
+	})
+}
+
+func TestSyncApply(t *testing.T) {
+	var requests []string
+	record := func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		status := http.StatusOK
+		if r.Method == http.MethodPost {
+			status = http.StatusCreated
+		}
+		writeTestJSON(w, status, map[string]any{})
+	}
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/server_configs": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []*types.RegisterServerInput{
+				{Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/", BearerToken: "ghp_live"},
+				{Name: "slack", Transport: "stdio", Command: "npx", Args: []string{"-y", "slack-mcp"}},
+			})
+		},
+		"GET /api/v1/tool-groups": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []types.ToolGroup{{Name: "chat", IncludedServers: []string{"slack"}}})
+		},
+		"POST /api/v1/servers":              record,
+		"PUT /api/v1/servers/{name}":        record,
+		"DELETE /api/v1/servers/{name}":     record,
+		"POST /api/v1/tool-groups":          record,
+		"DELETE /api/v1/tool-groups/{name}": record,
+	})
+	dir := writeSyncTestDir(t, []*types.RegisterServerInput{
+		{
+			Name: "github", Transport: "streamable_http", Description: "GitHub tools",
+			URL: "https://api.githubcopilot.com/mcp/", BearerToken: secretPlaceholder("github", "bearer_token"),
+		},
+		{Name: "jira", Transport: "sse", URL: "https://mcp.atlassian.com/v1/sse"},
+		{Name: "linear", Transport: "sse", URL: "https://mcp.linear.app/sse", BearerToken: secretPlaceholder("linear", "bearer_token")},
+	}, []types.ToolGroup{{Name: "ops", IncludedServers: []string{"github", "jira"}}})
+
+	// without --prune, the entities missing from the directory are kept
+	cmd := newSyncTestCmd(t, "--dir", dir)
+	stdout := &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	err := cmd.Execute()
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "1 of 6 changes could not be applied")
+	// the servers are created before the groups that reference them
+	testhelpers.AssertEqual(t, "PUT /api/v1/servers/github,POST /api/v1/servers,POST /api/v1/tool-groups", strings.Join(requests, ","))
+	out := stdout.String()
+	testhelpers.AssertStringContains(t, out, "~ server github (update)")
+	testhelpers.AssertStringContains(t, out, "! server linear (create): failed: its secrets are redacted")
+	testhelpers.AssertStringContains(t, out, "  server slack (delete): kept, use --prune to delete it")
+	testhelpers.AssertStringContains(t, out, "3 applied, 1 failed, 2 kept")
+
+	// with --prune, the groups are deleted before the servers they reference
+	requests = nil
+	_ = newSyncTestCmd(t, "--dir", dir, "--prune").Execute()
+	testhelpers.AssertEqual(t, "DELETE /api/v1/tool-groups/chat,DELETE /api/v1/servers/slack", strings.Join(requests[len(requests)-2:], ","))
+
+	// reconciling needs an explicit directory, and --check never changes anything
+	err = newSyncTestCmd(t, "--dir", dir, "--check", "--prune").Execute()
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+	err = newSyncTestCmd(t, "--prune").Execute()
+	testhelpers.AssertEqual(t, ExitUsage, ExitCodeForError(err))
+}
+
+func TestWatchSyncDir(t *testing.T) {
+	dir := writeSyncTestDir(t, []*types.RegisterServerInput{
+		{Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/"},
+	}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	reconciled := make(chan struct{}, 10)
+	done := make(chan error)
+	go func() {
+		done <- watchSyncDir(ctx, dir, 50*time.Millisecond, func() { reconciled <- struct{}{} })
+	}()
+	<-reconciled
+
+	// a burst of changes triggers a single reconcile once the directory settles
+	for _, name := range []string{"jira", "linear", "slack"} {
+		s := &types.RegisterServerInput{Name: name, Transport: "sse", URL: "https://" + name + ".example.com/sse"}
+		testhelpers.AssertNoError(t, writeConfigFile(filepath.Join(dir, exportMcpServersDir), name, configFormatJSON, s))
+	}
+	select {
+	case <-reconciled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change of the directory should trigger a reconcile")
+	}
+	time.Sleep(2 * syncWatchPollInterval)
+	testhelpers.AssertEqual(t, 0, len(reconciled))
+
+	cancel()
+	testhelpers.AssertNoError(t, <-done)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Check that an integration of the registry works",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "14",
+	},
+}
+
+var testWebhookCmd = &cobra.Command{
+	Use:   "webhook [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Send a test event to a webhook",
+	Long: "Make the server send a 'webhook.test' event to a webhook right away, and report how the delivery went.\n" +
+		"The webhook is tested even if it is disabled, and a failed test delivery doesn't count towards disabling it.",
+	RunE: runTestWebhook,
+}
+
+func init() {
+	testCmd.AddCommand(testWebhookCmd)
+	rootCmd.AddCommand(testCmd)
+}
+
+func runTestWebhook(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	d, err := apiClient.TestWebhook(commandContext(cmd), name)
+	if err != nil {
+		return fmt.Errorf("failed to test webhook %s: %w", name, err)
+	}
+
+	if isStructuredOutput() {
+		if err := printOutput(cmd, d); err != nil {
+			return err
+		}
+	} else {
+		p := newPrinter(cmd)
+		status := "-"
+		if d.StatusCode != 0 {
+			status = strconv.Itoa(d.StatusCode)
+		}
+		p.Value("Event ID", d.EventID)
+		p.Value("Status", status)
+		if d.Succeeded {
+			p.Infof("Webhook '%s' received the test event successfully!\n", name)
+		}
+	}
+	if !d.Succeeded {
+		return errors.New("the test delivery failed: " + d.Error)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func TestTestCommandStructure(t *testing.T) {
+	annotationTests := []testhelpers.CommandAnnotationTest{
+		{Key: "group", Expected: string(subCommandGroupAdvanced)},
+		{Key: "order", Expected: "14"},
+	}
+	testhelpers.TestCommandAnnotations(t, testCmd.Annotations, annotationTests)
+	testhelpers.AssertEqual(t, 1, len(testCmd.Commands()))
+	testhelpers.AssertEqual(t, "webhook [name]", testWebhookCmd.Use)
+}
+
+func TestRunTestWebhook(t *testing.T) {
+	delivery := types.WebhookDelivery{EventID: "abc", Event: types.EventWebhookTest, Attempts: 1}
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"POST /api/v1/webhooks/ci/test": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, delivery)
+		},
+	})
+
+	newCmd := func() (*cobra.Command, *bytes.Buffer) {
+		cmd := &cobra.Command{}
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(out)
+		return cmd, out
+	}
+
+	delivery.Succeeded, delivery.StatusCode = true, http.StatusNoContent
+	cmd, out := newCmd()
+	testhelpers.AssertNoError(t, runTestWebhook(cmd, []string{"ci"}))
+	testhelpers.AssertStringContains(t, out.String(), "Status: 204")
+	testhelpers.AssertStringContains(t, out.String(), "received the test event successfully")
+
+	delivery.Succeeded, delivery.StatusCode = false, http.StatusInternalServerError
+	delivery.Error = "the webhook responded with status 500"
+	cmd, _ = newCmd()
+	err := runTestWebhook(cmd, []string{"ci"})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "status 500")
+}
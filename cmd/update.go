@@ -28,6 +28,9 @@ var updateToolGroupCmd = &cobra.Command{
 		"CAUTION: If you remove any tools from the configuration (by removing them from include or adding them to exclude), " +
 		"calling update will immediately remove them from the group. " +
 		"They will no longer be accessible by MCP clients using the group's MCP server.",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return requireConfigInput(updateToolGroupConfigFilePath)
+	},
 	RunE: runUpdateGroup,
 }
 
@@ -64,9 +67,8 @@ func init() {
 		"conf",
 		"c",
 		"",
-		"Path to new JSON configuration file for the Tool Group",
+		"Path to new JSON or YAML configuration file for the Tool Group, or '-' to read it from stdin",
 	)
-	_ = updateToolGroupCmd.MarkFlagRequired("conf")
 
 	updateMcpClientCmd.Flags().StringVar(
 		&updateMcpClientAccessToken,
@@ -92,12 +94,24 @@ func init() {
 }
 
 func runUpdateGroup(cmd *cobra.Command, args []string) error {
-	updatedConf, err := readToolGroupConfig(updateToolGroupConfigFilePath)
+	groups, err := readToolGroupConfigs(cmd, updateToolGroupConfigFilePath)
 	if err != nil {
-		return fmt.Errorf("failed to read config file %s: %w", updateToolGroupConfigFilePath, err)
+		return err
+	}
+	if len(groups) != 1 {
+		return fmt.Errorf(
+			"the configuration from %s defines %d groups, but only one group can be updated at a time",
+			configSourceName(updateToolGroupConfigFilePath), len(groups),
+		)
 	}
+	return applyToolGroupUpdate(cmd, &groups[0])
+}
 
-	resp, err := apiClient.UpdateToolGroup(updatedConf)
+// applyToolGroupUpdate submits the new configuration of a tool group and reports what changed.
+func applyToolGroupUpdate(cmd *cobra.Command, updatedConf *types.ToolGroup) error {
+	p := newPrinter(cmd)
+
+	resp, err := apiClient.UpdateToolGroupContext(commandContext(cmd), updatedConf)
 	if err != nil {
 		return fmt.Errorf("failed to update tool group %s: %w", updatedConf.Name, err)
 	}
@@ -112,95 +126,99 @@ func runUpdateGroup(cmd *cobra.Command, args []string) error {
 	noChangeInExcluded := len(excludedAdded) == 0 && len(excludedRemoved) == 0
 
 	if resp.Old.Description == resp.New.Description && noChangeInTools && noChangeInServers && noChangeInExcluded {
-		cmd.Printf("No changes detected for Tool Group %s. Nothing was updated.\n", resp.Name)
+		p.Infof("No changes detected for Tool Group %s. Nothing was updated.\n", resp.Name)
 		return nil
 	}
 
-	cmd.Printf("Tool Group %s updated successfully\n\n", resp.Name)
+	p.Infof("Tool Group %s updated successfully\n\n", resp.Name)
 
 	if resp.Old.Description != resp.New.Description {
-		cmd.Printf("* Description updated from:\n    %s\nto:\n    %s\n\n", resp.Old.Description, resp.New.Description)
+		p.Infof("* Description updated from:\n    %s\nto:\n    %s\n\n", resp.Old.Description, resp.New.Description)
 	}
 
 	// Report changes in included_tools
 	if noChangeInTools {
-		cmd.Println("* No changes in included_tools")
+		p.Infoln("* No changes in included_tools")
 	} else {
 		if len(toolsRemoved) > 0 {
-			cmd.Println("* Tools removed from included_tools:")
+			p.Infoln("* Tools removed from included_tools:")
 			for _, t := range toolsRemoved {
-				cmd.Printf("    - %s\n", t)
+				p.Infof("    - %s\n", t)
 			}
 		}
 		if len(toolsAdded) > 0 {
-			cmd.Println("* Tools added to included_tools:")
+			p.Infoln("* Tools added to included_tools:")
 			for _, t := range toolsAdded {
-				cmd.Printf("    - %s\n", t)
+				p.Infof("    - %s\n", t)
 			}
 		}
 	}
-	cmd.Println()
+	p.Infoln()
 
 	// Report changes in included_servers
 	if !noChangeInServers {
 		if len(serversRemoved) > 0 {
-			cmd.Println("* Servers removed from included_servers:")
+			p.Infoln("* Servers removed from included_servers:")
 			for _, s := range serversRemoved {
-				cmd.Printf("    - %s\n", s)
+				p.Infof("    - %s\n", s)
 			}
 		}
 		if len(serversAdded) > 0 {
-			cmd.Println("* Servers added to included_servers:")
+			p.Infoln("* Servers added to included_servers:")
 			for _, s := range serversAdded {
-				cmd.Printf("    - %s\n", s)
+				p.Infof("    - %s\n", s)
 			}
 		}
-		cmd.Println()
+		p.Infoln()
 	}
 
 	// Report changes in excluded_tools
 	if !noChangeInExcluded {
 		if len(excludedRemoved) > 0 {
-			cmd.Println("* Tools removed from excluded_tools:")
+			p.Infoln("* Tools removed from excluded_tools:")
 			for _, e := range excludedRemoved {
-				cmd.Printf("    - %s\n", e)
+				p.Infof("    - %s\n", e)
 			}
 		}
 		if len(excludedAdded) > 0 {
-			cmd.Println("* Tools added to excluded_tools:")
+			p.Infoln("* Tools added to excluded_tools:")
 			for _, e := range excludedAdded {
-				cmd.Printf("    - %s\n", e)
+				p.Infof("    - %s\n", e)
 			}
 		}
-		cmd.Println()
+		p.Infoln()
 	}
 
 	return nil
 }
 
 func runUpdateMcpClient(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+
 	client := &types.McpClient{
 		Name:                args[0],
 		AccessToken:         updateMcpClientAccessToken,
 		IsCustomAccessToken: true,
 	}
-	if err := apiClient.UpdateMcpClient(client); err != nil {
+	if err := apiClient.UpdateMcpClientContext(commandContext(cmd), client); err != nil {
 		return fmt.Errorf("failed to update MCP client %s: %w", client.Name, err)
 	}
 
-	cmd.Printf("MCP client %s access token updated successfully.\n", client.Name)
+	p.Infof("MCP client %s access token updated successfully.\n", client.Name)
 	return nil
 }
 
 func runUpdateUser(cmd *cobra.Command, args []string) error {
+	p := newPrinter(cmd)
+
 	user := &types.CreateOrUpdateUserRequest{
 		Username:    args[0],
 		AccessToken: updateUserAccessToken,
 	}
-	_, err := apiClient.UpdateUser(user)
+	_, err := apiClient.UpdateUserContext(commandContext(cmd), user)
 	if err != nil {
 		return fmt.Errorf("failed to update user %s: %w", user.Username, err)
 	}
-	cmd.Printf("User %s access token updated successfully.\n", user.Username)
+	p.Infof("User %s access token updated successfully.\n", user.Username)
 	return nil
 }
@@ -25,21 +25,22 @@ func init() {
 }
 
 func runGetToolUsage(cmd *cobra.Command, args []string) error {
-	t, err := apiClient.GetTool(args[0])
+	p := newPrinter(cmd)
+	t, err := apiClient.GetToolContext(commandContext(cmd), args[0])
 	if err != nil {
 		return fmt.Errorf("failed to get tool '%s': %w", args[0], err)
 	}
 
-	cmd.Println(t.Name)
-	cmd.Println(t.Description)
+	p.Resultln(t.Name)
+	p.Resultln(t.Description)
 
 	if len(t.InputSchema.Properties) == 0 {
-		cmd.Println("This tool does not require any input parameters.")
+		p.Resultln("This tool does not require any input parameters.")
 		return nil
 	}
 
-	cmd.Println()
-	cmd.Println("Input Parameters:")
+	p.Resultln()
+	p.Resultln("Input Parameters:")
 	for k, v := range t.InputSchema.Properties {
 		requiredOrOptional := "optional"
 		if slices.Contains(t.InputSchema.Required, k) {
@@ -48,27 +49,27 @@ func runGetToolUsage(cmd *cobra.Command, args []string) error {
 
 		boundary := strings.Repeat("=", len(k)+len(requiredOrOptional)+20)
 
-		cmd.Println(boundary)
-		fmt.Printf("%s (%s)\n", k, requiredOrOptional)
+		p.Resultln(boundary)
+		p.Resultf("%s (%s)\n", k, requiredOrOptional)
 
 		j, err := json.MarshalIndent(v, "", "  ")
 		if err != nil {
 			// Simply print the raw object if we fail to marshal it
-			cmd.Println(v)
+			p.Resultln(v)
 		} else {
-			cmd.Println(string(j))
+			p.Resultln(string(j))
 		}
-		cmd.Println(boundary)
+		p.Resultln(boundary)
 
-		cmd.Println()
+		p.Resultln()
 	}
 
 	// Print annotations if present
 	if len(t.Annotations) > 0 {
-		cmd.Println()
-		cmd.Println("Annotations:")
+		p.Resultln()
+		p.Resultln("Annotations:")
 		for k, v := range t.Annotations {
-			cmd.Printf("* %s = %v\n", k, v)
+			p.Resultf("* %s = %v\n", k, v)
 		}
 	}
 
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// configValidationError reports the invalid fields of an entity read from a configuration,
+// as a bullet list of their paths in it, so that the user can fix all of them at once.
+type configValidationError struct {
+	// action is what failed, eg- "failed to register server github"
+	action string
+	// source is where the configuration was read from, see configSourceName. It is empty for flags.
+	source string
+	// path is the path of the entity in the configuration, eg- "[2]", empty if the configuration holds a single entity
+	path string
+	errs types.ValidationErrors
+	// cause is the error the invalid fields were reported with, eg- the client.APIError of the server's response
+	cause error
+}
+
+func (e *configValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.action + ": invalid configuration")
+	if e.source != "" {
+		b.WriteString(" in " + e.source)
+	}
+	b.WriteString(":")
+	for _, fe := range e.errs {
+		field := fe.Field
+		if e.path != "" {
+			field = e.path + "." + field
+		}
+		fmt.Fprintf(&b, "\n  - %s: %s", field, fe.Message)
+	}
+	return b.String()
+}
+
+func (e *configValidationError) Unwrap() error { return e.cause }
+
+// fieldErrorsOf returns the invalid fields reported by err, either by a local check or by the server.
+func fieldErrorsOf(err error) types.ValidationErrors {
+	var errs types.ValidationErrors
+	if errors.As(err, &errs) {
+		return errs
+	}
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.FieldErrors()
+	}
+	return nil
+}
+
+// configEntityError describes the failure of the index-th of count entities read from the configuration at source.
+// If err reports invalid fields, they are listed with their paths in the configuration.
+func configEntityError(err error, action, source string, index, count int) error {
+	errs := fieldErrorsOf(err)
+	if len(errs) == 0 {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	e := &configValidationError{action: action, source: source, errs: errs, cause: err}
+	if count > 1 {
+		e.path = fmt.Sprintf("[%d]", index)
+	}
+	return e
+}
+
+// checkConfigEntities validates the entities of a configuration locally, without sending them to the server.
+// validate is the exact validation the server applies to them, so a configuration that passes the check only fails
+// on the server for reasons it alone can tell, eg- because an entity already exists.
+// kind names the entities in messages, eg- "server".
+func checkConfigEntities(cmd *cobra.Command, kind, source string, names []string, validate func(i int) error) error {
+	var failures []error
+	for i, name := range names {
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if err := validate(i); err != nil {
+			failures = append(failures, configEntityError(err, fmt.Sprintf("%s %s", kind, name), source, i, len(names)))
+		}
+	}
+	if len(failures) == 1 && len(names) == 1 {
+		return failures[0]
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf(
+			"%d of %d %ss of %s are invalid:\n%w", len(failures), len(names), kind, source, errors.Join(failures...),
+		)
+	}
+	newPrinter(cmd).Infof("The configuration of %d %s(s) in %s is valid\n", len(names), kind, source)
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestRegisterCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.yaml")
+	testhelpers.AssertNoError(t, os.WriteFile(path, []byte(`
+- name: github
+  transport: streamable_http
+  url: https://api.githubcopilot.com/mcp/
+- name: filesystem
+  transport: stdio
+  session_mode: sticky
+`), 0o600))
+	setStdinIsPiped(t, false)
+	origPath, origCheck := registerCmdServerConfigFilePath, registerCmdCheck
+	registerCmdServerConfigFilePath, registerCmdCheck = path, true
+	t.Cleanup(func() { registerCmdServerConfigFilePath, registerCmdCheck = origPath, origCheck })
+	withRegistryHandlers(t, map[string]http.HandlerFunc{
+		"/": func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("the configuration must be checked without contacting the server, got %s %s", r.Method, r.URL.Path)
+		},
+	})
+
+	err := runRegisterMCPServer(newExitCodeTestCmd(), nil)
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "1 of 2 servers of "+path+" are invalid")
+	testhelpers.AssertStringContains(t, err.Error(), "server filesystem: invalid configuration in "+path+":")
+	testhelpers.AssertStringContains(t, err.Error(), "\n  - [1].session_mode: has an unsupported value \"sticky\"")
+	testhelpers.AssertStringContains(t, err.Error(), "\n  - [1].command: is required for stdio transport")
+
+	testhelpers.AssertNoError(t, os.WriteFile(path, []byte("name: github\ntransport: streamable_http\nurl: https://api.githubcopilot.com/mcp/\n"), 0o600))
+	testhelpers.AssertNoError(t, runRegisterMCPServer(newExitCodeTestCmd(), nil))
+}
+
+func TestConfigEntityError(t *testing.T) {
+	t.Parallel()
+
+	apiErr := &client.APIError{
+		StatusCode: http.StatusBadRequest,
+		Code:       types.ErrorCodeValidationFailed,
+		Message:    "url is required for SSE transport",
+		Details:    map[string]any{"errors": []any{map[string]any{"field": "url", "message": "is required for SSE transport"}}},
+	}
+	err := configEntityError(apiErr, "failed to register server slack", "servers.json", 0, 1)
+	testhelpers.AssertEqual(
+		t,
+		"failed to register server slack: invalid configuration in servers.json:\n  - url: is required for SSE transport",
+		err.Error(),
+	)
+	testhelpers.AssertTrue(t, errors.Is(err, client.ErrValidationFailed), "the error of the server should be wrapped")
+
+	err = configEntityError(errors.New("connection refused"), "failed to register server slack", "servers.json", 2, 3)
+	testhelpers.AssertEqual(t, "failed to register server slack: connection refused", err.Error())
+}
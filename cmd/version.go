@@ -2,33 +2,71 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/mcpjungle/mcpjungle/pkg/version"
 	"github.com/spf13/cobra"
 )
 
+var versionCmdClientOnly bool
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
-	Long:  `Print version information for the CLI and the connected mcpjungle server.`,
+	Long: "Print version information for the CLI and the connected mcpjungle server.\n" +
+		"A warning is printed if the CLI and server versions are not compatible with each other.",
 	Run: func(cmd *cobra.Command, args []string) {
-		// We want the extra newline for proper formatting
-		cmd.Print(asciiArt) //nolint:staticcheck
+		cliVersion := &types.ServerVersion{
+			Version:   version.GetVersion(),
+			Commit:    version.GetCommit(),
+			BuildDate: version.GetBuildDate(),
+		}
+
+		var serverVersion *types.ServerVersion
+		serverReachable := false
+		if !versionCmdClientOnly {
+			serverVersion, serverReachable = getServerVersion()
+		}
 
-		// Display CLI version
-		cliVersion := version.GetVersion()
-		cmd.Printf("CLI Version: %s\n", cliVersion)
+		if isStructuredOutput() {
+			out := map[string]any{"client": cliVersion}
+			if !versionCmdClientOnly {
+				if serverReachable {
+					out["server"] = serverVersion
+				} else {
+					out["server"] = nil
+				}
+				out["server_url"] = apiClient.BaseURL()
+			}
+			_ = printOutput(cmd, out)
+			return
+		}
 
-		// Try to fetch server version
-		serverVersion, ok := getServerVersion()
-		if ok {
-			cmd.Printf("Server Version: %s\n", serverVersion)
+		p := newPrinter(cmd)
+		if !quietFlag {
+			p.Resultf("%s", asciiArt)
+		}
+
+		p.Resultf("client: %s\n", describeVersion(cliVersion))
+		if versionCmdClientOnly {
+			return
+		}
+
+		if !serverReachable {
+			p.Resultln("server: unreachable")
 		} else {
-			cmd.Printf("Couldn't retrieve Server version at this time\n")
+			p.Resultf("server: %s\n", describeVersion(serverVersion))
 		}
+		p.Resultln("Server URL: ", apiClient.BaseURL())
 
-		cmd.Println("Server URL: ", apiClient.BaseURL())
+		if serverReachable {
+			if warning := checkVersionCompatibility(cliVersion.Version, serverVersion); warning != "" {
+				p.Infoln()
+				p.Warnf("%s", warning)
+			}
+		}
 	},
 	Annotations: map[string]string{
 		"group": string(subCommandGroupBasic),
@@ -37,21 +75,72 @@ var versionCmd = &cobra.Command{
 }
 
 func init() {
+	versionCmd.Flags().BoolVar(
+		&versionCmdClientOnly,
+		"client-only",
+		false,
+		"Only print the CLI version, without contacting the server",
+	)
 	rootCmd.AddCommand(versionCmd)
-	rootCmd.Flags().BoolP("version", "v", false, "Display version information")
+	// -v is reserved for the global --verbose flag
+	rootCmd.Flags().Bool("version", false, "Display version information")
 }
 
 // getServerVersion attempts to fetch the server version from the configured server.
-// Returns the version string and a boolean indicating success.
-func getServerVersion() (string, bool) {
-	// Try to get server metadata with timeout
+// Returns the version info and a boolean indicating success.
+func getServerVersion() (*types.ServerVersion, bool) {
+	// Try to get server version with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	metadata, err := apiClient.GetServerMetadata(ctx)
+	v, err := apiClient.GetServerVersion(ctx)
 	if err != nil {
-		return "", false
+		return nil, false
 	}
+	return v, true
+}
 
-	return metadata.Version, true
+// describeVersion formats a version along with its commit and build date (if known) on one line.
+func describeVersion(v *types.ServerVersion) string {
+	s := v.Version
+	if v.Commit != "" {
+		commit := v.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		s += fmt.Sprintf(" (commit %s", commit)
+		if v.BuildDate != "" {
+			s += ", built " + v.BuildDate
+		}
+		s += ")"
+	} else if v.BuildDate != "" {
+		s += fmt.Sprintf(" (built %s)", v.BuildDate)
+	}
+	return s
+}
+
+// checkVersionCompatibility returns a warning message if the CLI version is not compatible with the server.
+// The CLI is considered incompatible if it is older than the minimum client version supported by the server,
+// or if it is newer than the server by a major version.
+// Non-semver versions (eg- dev builds) are never reported as incompatible.
+func checkVersionCompatibility(cliVersion string, server *types.ServerVersion) string {
+	cli, ok := version.Parse(cliVersion)
+	if !ok {
+		return ""
+	}
+
+	if minVersion, ok := version.Parse(server.MinClientVersion); ok && cli.Compare(minVersion) < 0 {
+		return fmt.Sprintf(
+			"this CLI (%s) is older than the minimum version supported by the server (%s), please upgrade the CLI",
+			cliVersion, server.MinClientVersion,
+		)
+	}
+
+	if srv, ok := version.Parse(server.Version); ok && cli.Major > srv.Major {
+		return fmt.Sprintf(
+			"this CLI (%s) is a newer major version than the server (%s), some commands may not work as expected",
+			cliVersion, server.Version,
+		)
+	}
+	return ""
 }
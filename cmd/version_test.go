@@ -3,6 +3,8 @@ package cmd
 import (
 	"testing"
 
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/mcpjungle/mcpjungle/pkg/version"
 )
 
@@ -53,3 +55,38 @@ func TestGetServerVersion(t *testing.T) {
 		t.Log("Server version retrieval failed as expected in test environment")
 	}
 }
+
+func TestCheckVersionCompatibility(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cli         string
+		server      types.ServerVersion
+		wantWarning bool
+	}{
+		{"compatible", "v0.3.0", types.ServerVersion{Version: "v0.3.1", MinClientVersion: "v0.1.0"}, false},
+		{"older than minimum", "v0.1.0", types.ServerVersion{Version: "v0.5.0", MinClientVersion: "v0.2.0"}, true},
+		{"newer major", "v2.0.0", types.ServerVersion{Version: "v1.4.0", MinClientVersion: "v1.0.0"}, true},
+		{"newer minor is fine", "v1.5.0", types.ServerVersion{Version: "v1.4.0", MinClientVersion: "v1.0.0"}, false},
+		{"dev cli is never flagged", "dev", types.ServerVersion{Version: "v1.4.0", MinClientVersion: "v1.0.0"}, false},
+		{"old server without min version", "v0.3.0", types.ServerVersion{Version: "v0.3.0"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			warning := checkVersionCompatibility(tc.cli, &tc.server)
+			testhelpers.AssertEqual(t, tc.wantWarning, warning != "")
+		})
+	}
+}
+
+func TestDescribeVersion(t *testing.T) {
+	testhelpers.AssertEqual(t, "v1.0.0", describeVersion(&types.ServerVersion{Version: "v1.0.0"}))
+	testhelpers.AssertEqual(t,
+		"v1.0.0 (commit 0123456789ab, built 2025-01-01T00:00:00Z)",
+		describeVersion(&types.ServerVersion{
+			Version:   "v1.0.0",
+			Commit:    "0123456789abcdef",
+			BuildDate: "2025-01-01T00:00:00Z",
+		}),
+	)
+}
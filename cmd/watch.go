@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultWatchInterval is the time between two refreshes in watch mode when --interval is not set.
+const defaultWatchInterval = 2 * time.Second
+
+// ansiClearScreen moves the cursor to the top left corner and clears the terminal, like watch(1).
+const ansiClearScreen = "\033[H\033[2J"
+
+// Watch flags shared by the list commands that support watch mode
+var (
+	watchFlag         bool
+	watchIntervalFlag time.Duration
+)
+
+// addWatchFlags adds the --watch and --interval flags to a command.
+func addWatchFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&watchFlag, "watch", "w", false, "Keep refreshing the output until interrupted with Ctrl+C")
+	cmd.Flags().DurationVar(
+		&watchIntervalFlag,
+		"interval",
+		defaultWatchInterval,
+		"Time between two refreshes in watch mode (eg- 500ms, 5s)",
+	)
+}
+
+func validateWatchFlags(cmd *cobra.Command) error {
+	if !watchFlag {
+		if cmd.Flags().Changed("interval") {
+			return usageErrorf("--interval can only be used together with --watch")
+		}
+		return nil
+	}
+	if watchIntervalFlag <= 0 {
+		return usageErrorf("--interval must be a positive duration")
+	}
+	if isStructuredOutput() {
+		return usageErrorf("--watch can only be used with the %s output format", outputFormatTable)
+	}
+	return nil
+}
+
+// watchable wraps the RunE function of a command so that it is re-run on an interval when --watch is passed.
+// Every run is rendered off-screen first, then drawn in place of the previous one with the lines
+// that changed since the previous refresh highlighted.
+//
+// The server does not publish change events yet, so watch mode polls.
+// Once it does, refreshes should be driven by those events, with polling as the fallback.
+func watchable(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := validateWatchFlags(cmd); err != nil {
+			return err
+		}
+		if !watchFlag {
+			return run(cmd, args)
+		}
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return watch(ctx, cmd, watchIntervalFlag, func() ([]byte, error) {
+			return renderOffscreen(cmd, func() error { return run(cmd, args) })
+		})
+	}
+}
+
+// watch draws a frame produced by render every interval, until ctx is cancelled.
+// If the very first render fails, its error is returned because it most likely is a usage error.
+// Later failures are displayed in place of the output, the next refresh may well succeed (eg- during a rollout).
+func watch(ctx context.Context, cmd *cobra.Command, interval time.Duration, render func() ([]byte, error)) error {
+	out := cmd.OutOrStdout()
+	st := newStyler(out)
+	title := fmt.Sprintf("Every %s: %s", interval, cmd.CommandPath())
+
+	frame, err := render()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous []string
+	for {
+		if isTerminalWriter(out) {
+			_, _ = io.WriteString(out, ansiClearScreen)
+		} else if previous != nil {
+			_, _ = io.WriteString(out, "\n")
+		}
+		_, _ = fmt.Fprintf(out, "%s    %s\n\n", st.Bold(title), st.Dim(time.Now().Format(time.TimeOnly)))
+
+		lines := splitFrameLines(frame)
+		for _, line := range highlightChanges(st, previous, lines) {
+			_, _ = fmt.Fprintln(out, line)
+		}
+		if err != nil {
+			_, _ = fmt.Fprintln(out, st.Red(err.Error()))
+		}
+		previous = lines
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		frame, err = render()
+	}
+}
+
+// renderOffscreen runs fn with the command's output and error streams redirected to a buffer and returns what it wrote.
+func renderOffscreen(cmd *cobra.Command, fn func() error) ([]byte, error) {
+	out, errOut := cmd.OutOrStdout(), cmd.ErrOrStderr()
+	defer func() {
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+	}()
+
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	err := fn()
+	return buf.Bytes(), err
+}
+
+func splitFrameLines(frame []byte) []string {
+	s := strings.TrimRight(string(frame), "\n")
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, "\n")
+}
+
+// highlightChanges returns the lines of the current frame, with the ones that were not part of the previous frame highlighted.
+// Nothing is highlighted on the first frame, ie, when previous is nil.
+func highlightChanges(st styler, previous, current []string) []string {
+	if previous == nil {
+		return current
+	}
+
+	// count the occurrences of every line so that a repeated line is only matched once
+	seen := make(map[string]int, len(previous))
+	for _, line := range previous {
+		seen[line]++
+	}
+
+	result := make([]string, len(current))
+	for i, line := range current {
+		if seen[line] > 0 {
+			seen[line]--
+			result[i] = line
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			result[i] = line
+			continue
+		}
+		result[i] = st.Highlight(line)
+	}
+	return result
+}
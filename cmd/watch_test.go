@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/spf13/cobra"
+)
+
+func TestHighlightChanges(t *testing.T) {
+	st := styler{enabled: true}
+
+	first := []string{"1. github", "Transport: streamable_http", ""}
+	testhelpers.AssertEqual(t, strings.Join(first, "\n"), strings.Join(highlightChanges(st, nil, first), "\n"))
+
+	second := []string{"1. github", "Transport: sse", "", "2. calculator"}
+	got := highlightChanges(st, first, second)
+	testhelpers.AssertEqual(t, "1. github", got[0])
+	testhelpers.AssertEqual(t, st.Highlight("Transport: sse"), got[1])
+	testhelpers.AssertEqual(t, "", got[2])
+	testhelpers.AssertEqual(t, st.Highlight("2. calculator"), got[3])
+}
+
+func TestValidateWatchFlags(t *testing.T) {
+	origWatch, origInterval := watchFlag, watchIntervalFlag
+	t.Cleanup(func() { watchFlag, watchIntervalFlag = origWatch, origInterval })
+
+	cmd := &cobra.Command{}
+	addWatchFlags(cmd)
+	testhelpers.AssertNoError(t, validateWatchFlags(cmd))
+
+	testhelpers.AssertNoError(t, cmd.Flags().Set("interval", "5s"))
+	testhelpers.AssertError(t, validateWatchFlags(cmd))
+
+	testhelpers.AssertNoError(t, cmd.Flags().Set("watch", "true"))
+	testhelpers.AssertNoError(t, validateWatchFlags(cmd))
+
+	watchIntervalFlag = 0
+	testhelpers.AssertError(t, validateWatchFlags(cmd))
+}
+
+func TestWatchRefreshesUntilCancelled(t *testing.T) {
+	cmd := &cobra.Command{Use: "servers"}
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := 0
+	err := watch(ctx, cmd, time.Millisecond, func() ([]byte, error) {
+		runs++
+		switch runs {
+		case 1:
+			return []byte("1. github\n"), nil
+		case 2:
+			return nil, errors.New("connection refused")
+		default:
+			cancel()
+			return []byte("1. github\n2. calculator\n"), nil
+		}
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 3, runs)
+
+	s := out.String()
+	testhelpers.AssertStringContains(t, s, "Every 1ms: servers")
+	testhelpers.AssertStringContains(t, s, "connection refused")
+	testhelpers.AssertStringContains(t, s, "2. calculator")
+	// output is not a terminal, so frames are not separated by escape sequences
+	testhelpers.AssertStringNotContains(t, s, ansiClearScreen)
+}
+
+func TestWatchReturnsFirstError(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+
+	err := watch(context.Background(), cmd, time.Millisecond, func() ([]byte, error) {
+		return nil, errors.New("using both --server and --group flags together is currently not supported")
+	})
+	testhelpers.AssertError(t, err)
+}
+
+func TestRenderOffscreen(t *testing.T) {
+	cmd := &cobra.Command{}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+
+	frame, err := renderOffscreen(cmd, func() error {
+		p := newPrinter(cmd)
+		p.Infoln("There are no MCP servers in the registry")
+		p.Resultln("1. github")
+		return nil
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "There are no MCP servers in the registry\n1. github\n", string(frame))
+
+	// the original streams are restored afterwards
+	newPrinter(cmd).Resultln("done")
+	testhelpers.AssertEqual(t, "done\n", stdout.String())
+	testhelpers.AssertEqual(t, "", stderr.String())
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/backup"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// backupHandler streams a backup of the registry, the response starts before the whole backup is read.
+func (s *Server) backupHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.backupService == nil {
+			respondError(c, newAPIError(
+				http.StatusServiceUnavailable, types.ErrorCodeUnavailable, "this server cannot back up its registry",
+			))
+			return
+		}
+		var req types.BackupRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, invalidRequest("invalid request body: %v", err))
+			return
+		}
+
+		c.Header("Content-Type", types.BackupContentType)
+		c.Header("Content-Disposition", fmt.Sprintf(
+			`attachment; filename="mcpjungle-%s.mcpj"`, time.Now().UTC().Format("20060102-150405"),
+		))
+		err := s.backupService.Backup(c.Request.Context(), c.Writer, &req)
+		if err != nil && !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+			respondError(c, err)
+			return
+		}
+		if err != nil {
+			// the backup ends with an error record, so the client knows it is incomplete
+			log.Printf("[ERROR] backup failed after it was partially sent: %v", err)
+		}
+	}
+}
+
+// restoreHandler restores the backup sent as the request body, it is read as it is received.
+func (s *Server) restoreHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.backupService == nil {
+			respondError(c, newAPIError(
+				http.StatusServiceUnavailable, types.ErrorCodeUnavailable, "this server cannot restore backups",
+			))
+			return
+		}
+		force := false
+		if v, ok := c.GetQuery("force"); ok {
+			switch v {
+			case "true", "false":
+				force = v == "true"
+			default:
+				invalidParam(c, "force", "must be true or false")
+				return
+			}
+		}
+		passphrase := c.GetHeader(types.BackupPassphraseHeader)
+		if passphrase == "" {
+			respondError(c, validationFailed(
+				"the passphrase of the backup is required in the %s header", types.BackupPassphraseHeader,
+			).with("header", types.BackupPassphraseHeader))
+			return
+		}
+
+		result, err := s.backupService.Restore(c.Request.Context(), c.Request.Body, passphrase, force)
+		var notEmpty *backup.NotEmptyError
+		switch {
+		case errors.As(err, &notEmpty):
+			respondError(c, newAPIError(
+				http.StatusConflict, types.ErrorCodeRegistryNotEmpty, "%s, restore the backup with force to replace them", err,
+			).with("entities", notEmpty.Entities))
+		case errors.Is(err, backup.ErrWrongPassphrase):
+			respondError(c, validationFailed("%s", err).with("header", types.BackupPassphraseHeader))
+		case err != nil:
+			respondError(c, err)
+		default:
+			log.Printf("[INFO] restored a backup made by mcpjungle %s at %s", result.Version, result.CreatedAt)
+			c.JSON(http.StatusOK, result)
+		}
+	}
+}
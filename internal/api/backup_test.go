@@ -0,0 +1,71 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/backup"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestBackupAndRestoreHandlers(t *testing.T) {
+	setup := testhelpers.SetupTestDB(t)
+	testhelpers.AssertNoError(t, setup.DB.Create(&model.ServerConfig{Mode: model.ModeDev, Initialized: true}).Error)
+	testhelpers.AssertNoError(t, setup.DB.Create(&model.ToolGroup{Name: "dev"}).Error)
+	s := &Server{backupService: backup.NewBackupService(setup.DB)}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/backup", s.backupHandler())
+	router.POST("/restore", s.restoreHandler())
+	restore := func(body []byte, passphrase string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/restore", bytes.NewReader(body))
+		req.Header.Set("Content-Type", types.BackupContentType)
+		if passphrase != "" {
+			req.Header.Set(types.BackupPassphraseHeader, passphrase)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// the passphrase is checked before the backup starts, the error is a regular error response
+	w := sendWebhookRequest(router, http.MethodPost, "/backup", `{"passphrase": "short"}`)
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+	testhelpers.AssertStringContains(t, w.Header().Get("Content-Type"), "application/json")
+	testhelpers.AssertStringContains(t, w.Body.String(), string(types.ErrorCodeValidationFailed))
+
+	w = sendWebhookRequest(router, http.MethodPost, "/backup", `{"passphrase": "correct horse"}`)
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	testhelpers.AssertEqual(t, types.BackupContentType, w.Header().Get("Content-Type"))
+	testhelpers.AssertStringContains(t, w.Header().Get("Content-Disposition"), ".mcpj")
+	backupFile := w.Body.Bytes()
+
+	w = restore(backupFile, "")
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+	testhelpers.AssertStringContains(t, w.Body.String(), types.BackupPassphraseHeader)
+
+	w = restore([]byte("not a backup"), "correct horse")
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+	testhelpers.AssertStringContains(t, w.Body.String(), string(types.ErrorCodeInvalidRequest))
+
+	// the tool group is still there
+	w = restore(backupFile, "correct horse")
+	testhelpers.AssertEqual(t, http.StatusConflict, w.Code)
+	var resp types.ErrorResponse
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	testhelpers.AssertEqual(t, types.ErrorCodeRegistryNotEmpty, resp.Error.Code)
+	testhelpers.AssertEqual(t, float64(1), resp.Error.Details["entities"].(map[string]any)["tool groups"])
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/restore?force=yes", strings.NewReader(""))
+	router.ServeHTTP(w, req)
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+}
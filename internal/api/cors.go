@@ -0,0 +1,201 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultCORSMethods are the methods allowed in cross-origin requests if a CORSPolicy doesn't list any.
+var DefaultCORSMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+// DefaultCORSHeaders are the request headers allowed in cross-origin requests if a CORSPolicy doesn't list any.
+var DefaultCORSHeaders = []string{
+	"Authorization", "Content-Type", "If-Match", "If-None-Match", "Idempotency-Key",
+}
+
+// corsExposedHeaders are the response headers of the API that browsers let cross-origin scripts read.
+var corsExposedHeaders = []string{
+	"ETag", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset",
+	"Idempotent-Replayed", "Deprecation", "Link",
+}
+
+// CORSPolicy describes which web pages, served from other origins, can call the API from a browser.
+// A policy without any allowed origin allows none, ie, cross-origin requests are left to the browser to block.
+type CORSPolicy struct {
+	// AllowedOrigins are the origins allowed to call the API, eg- "https://dashboard.example.com".
+	// A "*." in place of the first label of the host allows all its subdomains, eg- "https://*.example.com",
+	// and "*" allows any origin.
+	AllowedOrigins []string
+	// AllowedMethods are the methods allowed in cross-origin requests, DefaultCORSMethods if empty.
+	AllowedMethods []string
+	// AllowedHeaders are the request headers allowed in cross-origin requests, DefaultCORSHeaders if empty.
+	AllowedHeaders []string
+	// AllowCredentials lets browsers send cookies and the Authorization header along with cross-origin requests.
+	AllowCredentials bool
+	// MaxAge is how long browsers can cache the response to a preflight request. They use their default if 0.
+	MaxAge time.Duration
+	// IncludeMCP applies the policy to the MCP proxy endpoints (/mcp, /sse, /message and those of tool groups) too.
+	// They are left out by default, so that allowing a dashboard to call the API doesn't open the gateway to it.
+	IncludeMCP bool
+}
+
+// corsPolicy is a CORSPolicy ready to be applied to requests.
+type corsPolicy struct {
+	origins          []originPattern
+	methods          string
+	headers          string
+	allowCredentials bool
+	maxAge           string
+	includeMCP       bool
+}
+
+// originPattern is an allowed origin, either exact or matching all subdomains of a host.
+type originPattern struct {
+	// any matches every origin
+	any bool
+	// exact is the whole origin, for exact matches
+	exact string
+	// scheme and suffix are the scheme of the origin, with "://", and the host that follows "*", with its leading dot,
+	// for subdomain matches
+	scheme, suffix string
+}
+
+func (p originPattern) matches(origin string) bool {
+	switch {
+	case p.any:
+		return true
+	case p.exact != "":
+		return origin == p.exact
+	default:
+		host, ok := strings.CutPrefix(origin, p.scheme)
+		return ok && len(host) > len(p.suffix) && strings.HasSuffix(host, p.suffix)
+	}
+}
+
+// newCORSPolicy validates cfg and prepares it to be applied. It returns nil if cfg allows no origin.
+func newCORSPolicy(cfg CORSPolicy) (*corsPolicy, error) {
+	if len(cfg.AllowedOrigins) == 0 {
+		return nil, nil
+	}
+	if cfg.MaxAge < 0 {
+		return nil, fmt.Errorf("invalid CORS max age %s, must not be negative", cfg.MaxAge)
+	}
+
+	p := &corsPolicy{allowCredentials: cfg.AllowCredentials, includeMCP: cfg.IncludeMCP}
+	for _, o := range cfg.AllowedOrigins {
+		o = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(o), "/"))
+		switch {
+		case o == "*":
+			if cfg.AllowCredentials {
+				// the origin is echoed back, so this would let every web page call the API on behalf of its visitors
+				return nil, fmt.Errorf("CORS credentials can't be allowed for any origin, list the allowed origins instead")
+			}
+			p.origins = append(p.origins, originPattern{any: true})
+		case strings.Contains(o, "://*."):
+			scheme, host, _ := strings.Cut(o, "*")
+			if strings.Contains(host, "*") {
+				return nil, fmt.Errorf("invalid CORS origin %q, only the first label of the host can be a wildcard", o)
+			}
+			p.origins = append(p.origins, originPattern{scheme: scheme, suffix: host})
+		case strings.Contains(o, "*") || !strings.Contains(o, "://"):
+			return nil, fmt.Errorf("invalid CORS origin %q, must be like https://example.com or https://*.example.com", o)
+		default:
+			p.origins = append(p.origins, originPattern{exact: o})
+		}
+	}
+
+	methods := cfg.AllowedMethods
+	if len(methods) == 0 {
+		methods = DefaultCORSMethods
+	}
+	p.methods = strings.ToUpper(strings.Join(methods, ", "))
+	headers := cfg.AllowedHeaders
+	if len(headers) == 0 {
+		headers = DefaultCORSHeaders
+	}
+	p.headers = strings.Join(headers, ", ")
+	if cfg.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+	return p, nil
+}
+
+// allowsOrigin returns true if origin can make cross-origin requests to the server.
+func (p *corsPolicy) allowsOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, o := range p.origins {
+		if o.matches(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// appliesTo returns true if the policy covers requests to path.
+func (p *corsPolicy) appliesTo(path string) bool {
+	if strings.HasPrefix(path, "/api/") {
+		return true
+	}
+	return p.includeMCP && isMCPProxyPath(path)
+}
+
+// isMCPProxyPath returns true if path is served by the MCP proxy, globally or for a tool group.
+func isMCPProxyPath(path string) bool {
+	switch path {
+	case "/mcp", "/sse", "/message":
+		return true
+	}
+	group, ok := strings.CutPrefix(path, V0PathPrefix+"/groups/")
+	if !ok {
+		return false
+	}
+	_, endpoint, ok := strings.Cut(group, "/")
+	return ok && (endpoint == "mcp" || endpoint == "sse" || endpoint == "message")
+}
+
+// cors applies the CORS policy of the server. It must run before authentication, because browsers
+// don't send credentials with preflight requests: those are answered right away for allowed origins.
+// Requests from other origins, or to paths the policy doesn't cover, get no CORS headers at all.
+func (s *Server) cors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := s.corsPolicy
+		origin := c.GetHeader("Origin")
+		if p == nil || origin == "" || !p.appliesTo(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+		if !p.allowsOrigin(origin) {
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		if p.allowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			h.Set("Access-Control-Allow-Methods", p.methods)
+			h.Set("Access-Control-Allow-Headers", p.headers)
+			if p.maxAge != "" {
+				h.Set("Access-Control-Max-Age", p.maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		h.Set("Access-Control-Expose-Headers", strings.Join(corsExposedHeaders, ", "))
+		c.Next()
+	}
+}
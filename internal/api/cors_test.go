@@ -0,0 +1,149 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func TestNewCORSPolicy(t *testing.T) {
+	p, err := newCORSPolicy(CORSPolicy{})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, p == nil, "a policy without origins should allow nothing")
+
+	for _, origins := range [][]string{{"dashboard.example.com"}, {"https://dash*.example.com"}, {"https://*.*.example.com"}} {
+		_, err := newCORSPolicy(CORSPolicy{AllowedOrigins: origins})
+		testhelpers.AssertError(t, err)
+	}
+	_, err = newCORSPolicy(CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+	testhelpers.AssertError(t, err)
+
+	p, err = newCORSPolicy(CORSPolicy{AllowedOrigins: []string{"https://dashboard.example.com/", "https://*.internal.example.com"}})
+	testhelpers.AssertNoError(t, err)
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://dashboard.example.com", true},
+		{"https://Dashboard.Example.com", true},
+		{"http://dashboard.example.com", false},
+		{"https://dashboard.example.com.evil.com", false},
+		{"https://ops.internal.example.com", true},
+		{"https://a.b.internal.example.com", true},
+		{"https://internal.example.com", false},
+		{"https://.internal.example.com", false},
+		{"https://evilinternal.example.com", false},
+		{"http://ops.internal.example.com", false},
+	}
+	for _, tt := range tests {
+		testhelpers.AssertEqual(t, tt.want, p.allowsOrigin(tt.origin))
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newRouter := func(cfg *CORSPolicy) *gin.Engine {
+		s := &Server{}
+		if cfg != nil {
+			p, err := newCORSPolicy(*cfg)
+			testhelpers.AssertNoError(t, err)
+			s.corsPolicy = p
+		}
+		r := gin.New()
+		r.Use(s.cors())
+		// the API and the MCP proxy require a token, preflight requests must not get that far
+		requireToken := func(c *gin.Context) {
+			if c.GetHeader("Authorization") == "" {
+				c.AbortWithStatus(http.StatusUnauthorized)
+			}
+		}
+		r.GET(V1ApiPathPrefix+"/tools", requireToken, func(c *gin.Context) { c.Status(http.StatusOK) })
+		r.Any("/mcp", requireToken, func(c *gin.Context) { c.Status(http.StatusOK) })
+		return r
+	}
+	send := func(r *gin.Engine, method, path, origin string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		} else {
+			req.Header.Set("Authorization", "Bearer token")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	const origin = "https://dashboard.example.com"
+	cfg := &CORSPolicy{AllowedOrigins: []string{origin}, AllowCredentials: true, MaxAge: 10 * time.Minute}
+
+	t.Run("closed by default", func(t *testing.T) {
+		r := newRouter(nil)
+		w := send(r, http.MethodGet, V1ApiPathPrefix+"/tools", origin)
+		testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+		testhelpers.AssertEqual(t, "", w.Header().Get("Access-Control-Allow-Origin"))
+		testhelpers.AssertEqual(t, "", send(r, http.MethodOptions, V1ApiPathPrefix+"/tools", origin).Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		w := send(newRouter(cfg), http.MethodOptions, V1ApiPathPrefix+"/tools", origin)
+		testhelpers.AssertEqual(t, http.StatusNoContent, w.Code)
+		testhelpers.AssertEqual(t, origin, w.Header().Get("Access-Control-Allow-Origin"))
+		testhelpers.AssertEqual(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		testhelpers.AssertEqual(t, "GET, POST, PUT, PATCH, DELETE", w.Header().Get("Access-Control-Allow-Methods"))
+		testhelpers.AssertStringContains(t, w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+		testhelpers.AssertEqual(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("actual request", func(t *testing.T) {
+		w := send(newRouter(cfg), http.MethodGet, V1ApiPathPrefix+"/tools", origin)
+		testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+		testhelpers.AssertEqual(t, origin, w.Header().Get("Access-Control-Allow-Origin"))
+		testhelpers.AssertStringContains(t, w.Header().Get("Access-Control-Expose-Headers"), "ETag")
+		testhelpers.AssertEqual(t, "", w.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("disallowed origin", func(t *testing.T) {
+		r := newRouter(cfg)
+		for _, method := range []string{http.MethodGet, http.MethodOptions} {
+			w := send(r, method, V1ApiPathPrefix+"/tools", "https://evil.example.com")
+			for name := range w.Header() {
+				testhelpers.AssertFalse(t, strings.HasPrefix(name, "Access-Control-"), "unexpected header "+name)
+			}
+		}
+	})
+
+	t.Run("MCP endpoints", func(t *testing.T) {
+		w := send(newRouter(cfg), http.MethodOptions, "/mcp", origin)
+		testhelpers.AssertEqual(t, http.StatusUnauthorized, w.Code)
+		testhelpers.AssertEqual(t, "", w.Header().Get("Access-Control-Allow-Origin"))
+
+		withMCP := *cfg
+		withMCP.IncludeMCP = true
+		w = send(newRouter(&withMCP), http.MethodOptions, "/mcp", origin)
+		testhelpers.AssertEqual(t, http.StatusNoContent, w.Code)
+		testhelpers.AssertEqual(t, origin, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestIsMCPProxyPath(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/mcp":                     true,
+		"/sse":                     true,
+		"/message":                 true,
+		"/v0/groups/ci/mcp":        true,
+		"/v0/groups/ci/sse":        true,
+		"/v0/groups/ci/message":    true,
+		"/api/v1/tools":            false,
+		"/api/v1/tools/git/invoke": false,
+		"/v0/groups/ci":            false,
+		"/health":                  false,
+	} {
+		testhelpers.AssertEqual(t, want, isMCPProxyPath(path))
+	}
+}
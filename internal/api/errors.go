@@ -0,0 +1,158 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/backup"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// legacyErrorsKey is set in the context of the requests to deprecated API versions,
+// whose clients expect the error message as a plain string in the "error" field of error responses.
+const legacyErrorsKey = "legacy_errors"
+
+// serviceErrors maps the errors returned by the services to the status and code they are reported with.
+var serviceErrors = []struct {
+	err    error
+	status int
+	code   types.ErrorCode
+}{
+	{gorm.ErrRecordNotFound, http.StatusNotFound, types.ErrorCodeNotFound},
+	{toolgroup.ErrToolGroupNotFound, http.StatusNotFound, types.ErrorCodeNotFound},
+	{gorm.ErrDuplicatedKey, http.StatusConflict, types.ErrorCodeAlreadyExists},
+	{model.ErrVersionConflict, http.StatusPreconditionFailed, types.ErrorCodeVersionConflict},
+	{webhook.ErrInvalidWebhook, http.StatusBadRequest, types.ErrorCodeValidationFailed},
+	{backup.ErrInvalidBackup, http.StatusBadRequest, types.ErrorCodeInvalidRequest},
+	{backup.ErrIncompatibleBackup, http.StatusUnprocessableEntity, types.ErrorCodeIncompatibleBackup},
+	// servers launched in a container are unreachable too when Docker is
+	{mcp.ErrDockerUnavailable, http.StatusServiceUnavailable, types.ErrorCodeDockerUnavailable},
+	{mcp.ErrMcpServerUnreachable, http.StatusBadGateway, types.ErrorCodeUpstreamUnreachable},
+	{mcp.ErrCredentialUnavailable, http.StatusBadGateway, types.ErrorCodeCredentialUnavailable},
+	{mcp.ErrServerAccessDenied, http.StatusForbidden, types.ErrorCodeForbidden},
+	{mcp.ErrServerWarmingUp, http.StatusServiceUnavailable, types.ErrorCodeWarmingUp},
+	{mcp.ErrUpstreamCallsSaturated, http.StatusServiceUnavailable, types.ErrorCodeUnavailable},
+	{mcp.ErrShuttingDown, http.StatusServiceUnavailable, types.ErrorCodeUnavailable},
+}
+
+// classifyError returns the HTTP status code and the error code to respond with when a service call fails with err.
+func classifyError(err error) (int, types.ErrorCode) {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.status, apiErr.code
+	}
+	var fieldErrs types.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		return http.StatusBadRequest, types.ErrorCodeValidationFailed
+	}
+	for _, e := range serviceErrors {
+		if errors.Is(err, e.err) {
+			return e.status, e.code
+		}
+	}
+	// the SQLite database was locked by other writes for longer than they should take, the request can be retried
+	if errors.Is(err, db.ErrDatabaseBusy) || db.IsBusy(err) {
+		return http.StatusServiceUnavailable, types.ErrorCodeUnavailable
+	}
+	return http.StatusInternalServerError, types.ErrorCodeInternal
+}
+
+// statusForError returns the HTTP status code to respond with when a service call fails with err.
+// Lookups of entities that don't exist are reported as 404 and attempts to create an entity that already exists as 409,
+// so that clients can tell them apart from server failures.
+func statusForError(err error) int {
+	status, _ := classifyError(err)
+	return status
+}
+
+// apiError is a failure of a request that is reported with a specific status and error code.
+type apiError struct {
+	status  int
+	code    types.ErrorCode
+	message string
+	details map[string]any
+}
+
+func (e *apiError) Error() string { return e.message }
+
+// newAPIError creates an apiError with a formatted message.
+func newAPIError(status int, code types.ErrorCode, format string, args ...any) *apiError {
+	return &apiError{status: status, code: code, message: fmt.Sprintf(format, args...)}
+}
+
+// with adds a detail to the error.
+func (e *apiError) with(key string, value any) *apiError {
+	if e.details == nil {
+		e.details = make(map[string]any)
+	}
+	e.details[key] = value
+	return e
+}
+
+// invalidRequest is the error of a request that could not be parsed.
+func invalidRequest(format string, args ...any) *apiError {
+	return newAPIError(http.StatusBadRequest, types.ErrorCodeInvalidRequest, format, args...)
+}
+
+// validationFailed is the error of a request with an invalid value.
+// Use with("field", name) to name the field of the body that has it.
+func validationFailed(format string, args ...any) *apiError {
+	return newAPIError(http.StatusBadRequest, types.ErrorCodeValidationFailed, format, args...)
+}
+
+// unauthorized is the error of a request without a valid access token.
+func unauthorized(format string, args ...any) *apiError {
+	return newAPIError(http.StatusUnauthorized, types.ErrorCodeUnauthorized, format, args...)
+}
+
+// roleRequired is the error of a request made by a user who lacks the role it requires.
+func roleRequired(role types.UserRole, message string) *apiError {
+	return newAPIError(http.StatusForbidden, types.ErrorCodeForbidden, "%s", message).with("required_role", role)
+}
+
+// respondError aborts the request with the error response for err, in the envelope of types.ErrorResponse.
+// The status and code are the ones of err if it is an apiError, otherwise they are derived from the service error.
+func respondError(c *gin.Context, err error) {
+	status, _ := classifyError(err)
+	e := toAPIErrorType(err)
+	if c.GetBool(legacyErrorsKey) {
+		body := gin.H{"error": e.Message}
+		for k, v := range e.Details {
+			body[k] = v
+		}
+		c.AbortWithStatusJSON(status, body)
+		return
+	}
+	c.AbortWithStatusJSON(status, types.ErrorResponse{Error: e})
+}
+
+// toAPIErrorType converts err into the representation sent to clients.
+// The invalid fields of types.ValidationErrors are listed in the "errors" detail, the first one is also the "field".
+func toAPIErrorType(err error) types.APIError {
+	_, code := classifyError(err)
+	e := types.APIError{Code: code, Message: err.Error()}
+	var apiErr *apiError
+	var fieldErrs types.ValidationErrors
+	switch {
+	case errors.As(err, &apiErr):
+		e.Details = apiErr.details
+	case errors.As(err, &fieldErrs) && len(fieldErrs) > 0:
+		e.Details = map[string]any{"field": fieldErrs[0].Field, "errors": fieldErrs}
+	}
+	return e
+}
+
+// invalidParam responds with a 400 error naming the query parameter whose value is invalid.
+func invalidParam(c *gin.Context, param, reason string) {
+	respondError(c, newAPIError(
+		http.StatusBadRequest, types.ErrorCodeValidationFailed, "invalid value for parameter %s: %s", param, reason,
+	).with("parameter", param))
+}
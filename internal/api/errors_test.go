@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+func TestStatusForError(t *testing.T) {
+	if got := statusForError(fmt.Errorf("failed to get MCP server foo from DB: %w", gorm.ErrRecordNotFound)); got != http.StatusNotFound {
+		t.Errorf("expected %d for a missing record, got %d", http.StatusNotFound, got)
+	}
+	if got := statusForError(fmt.Errorf("failed to register mcp server: %w", gorm.ErrDuplicatedKey)); got != http.StatusConflict {
+		t.Errorf("expected %d for a duplicate record, got %d", http.StatusConflict, got)
+	}
+	if got := statusForError(errors.New("database is locked")); got != http.StatusInternalServerError {
+		t.Errorf("expected %d for other errors, got %d", http.StatusInternalServerError, got)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   types.ErrorCode
+	}{
+		{fmt.Errorf("failed to get tool group: %w", gorm.ErrRecordNotFound), http.StatusNotFound, types.ErrorCodeNotFound},
+		{fmt.Errorf("failed to connect: %w", mcp.ErrMcpServerUnreachable), http.StatusBadGateway, types.ErrorCodeUpstreamUnreachable},
+		{fmt.Errorf("failed to connect: %w", mcp.ErrCredentialUnavailable), http.StatusBadGateway, types.ErrorCodeCredentialUnavailable},
+		{
+			fmt.Errorf("failed to connect: %w", errors.Join(mcp.ErrDockerUnavailable, mcp.ErrMcpServerUnreachable)),
+			http.StatusServiceUnavailable, types.ErrorCodeDockerUnavailable,
+		},
+		{fmt.Errorf("failed to invoke tool: %w", mcp.ErrServerWarmingUp), http.StatusServiceUnavailable, types.ErrorCodeWarmingUp},
+		{fmt.Errorf("failed to invoke tool: %w", mcp.ErrUpstreamCallsSaturated), http.StatusServiceUnavailable, types.ErrorCodeUnavailable},
+		{validationFailed("name is required").with("field", "name"), http.StatusBadRequest, types.ErrorCodeValidationFailed},
+		{fmt.Errorf("invalid server: %w", types.ValidationErrors{{Field: "url", Message: "is required"}}), http.StatusBadRequest, types.ErrorCodeValidationFailed},
+		{errors.New("database is locked"), http.StatusInternalServerError, types.ErrorCodeInternal},
+		{fmt.Errorf("failed to save: %w", db.ErrDatabaseBusy), http.StatusServiceUnavailable, types.ErrorCodeUnavailable},
+	}
+	for _, tt := range tests {
+		status, code := classifyError(tt.err)
+		testhelpers.AssertEqual(t, tt.status, status)
+		testhelpers.AssertEqual(t, tt.code, code)
+	}
+}
+
+func TestRespondError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	respond := func(legacy bool) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		if legacy {
+			c.Set(legacyErrorsKey, true)
+		}
+		respondError(c, validationFailed("name is required").with("field", "name"))
+		return w
+	}
+
+	w := respond(false)
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+	var resp types.ErrorResponse
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	testhelpers.AssertEqual(t, types.ErrorCodeValidationFailed, resp.Error.Code)
+	testhelpers.AssertEqual(t, "name is required", resp.Error.Message)
+	testhelpers.AssertEqual(t, "name", resp.Error.Details["field"])
+
+	// deprecated API versions keep the message as a string
+	w = respond(true)
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+	testhelpers.AssertEqual(t, `{"error":"name is required","field":"name"}`, w.Body.String())
+}
+
+func TestToAPIErrorTypeListsInvalidFields(t *testing.T) {
+	errs := types.ValidationErrors{{Field: "name", Message: "is required"}, {Field: "url", Message: "is required"}}
+	e := toAPIErrorType(errs)
+	testhelpers.AssertEqual(t, types.ErrorCodeValidationFailed, e.Code)
+	testhelpers.AssertEqual(t, "name is required; url is required", e.Message)
+	testhelpers.AssertEqual(t, "name", e.Details["field"])
+	testhelpers.AssertEqual(t, 2, len(e.Details["errors"].(types.ValidationErrors)))
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tablesETag returns the ETag of a response computed from tables at the given versions.
+func tablesETag(versions []uint) string {
+	parts := make([]string, len(versions))
+	for i, v := range versions {
+		parts[i] = strconv.FormatUint(uint64(v), 10)
+	}
+	return strconv.Quote("t" + strings.Join(parts, "."))
+}
+
+// conditionalOnTables returns a middleware for the routes whose response only depends on the rows of tables.
+// It sets the ETag of the response from the versions of the tables, and answers 304 Not Modified without
+// running the handler if the request's If-None-Match header holds that ETag.
+// If state is not nil, it returns the version of the in-memory state the response also depends on,
+// which is part of the ETag too.
+// The versions are read before the handler reads the rows, so a change made in between can only make the ETag
+// older than the response, which costs the client another download but never hides the change from it.
+func (s *Server) conditionalOnTables(tables []string, state func() uint64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.tableVersions == nil {
+			c.Next()
+			return
+		}
+		versions, err := s.tableVersions.Get(tables...)
+		if err != nil {
+			// the response can still be served, just not cached
+			log.Printf("[WARN] failed to compute the ETag of %s: %v", c.Request.URL.Path, err)
+			c.Next()
+			return
+		}
+		if state != nil {
+			versions = append(versions, uint(state()))
+		}
+		if notModified(c, tablesETag(versions)) {
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// notModified sets the ETag header of the response and returns true if the request's If-None-Match header
+// matches it, in which case a 304 Not Modified response has been sent and the handler must not write a body.
+func notModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		// If-None-Match uses the weak comparison
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == strings.TrimPrefix(etag, "W/") {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
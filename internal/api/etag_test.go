@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	mcpservice "github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+)
+
+func TestNotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		ifNoneMatch string
+		want        bool
+	}{
+		{"", false},
+		{`"t1.2"`, true},
+		{`W/"t1.2"`, true},
+		{`"t1.1", "t1.2"`, true},
+		{"*", true},
+		{`"t1.1"`, false},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/tools", nil)
+		if tt.ifNoneMatch != "" {
+			c.Request.Header.Set("If-None-Match", tt.ifNoneMatch)
+		}
+		testhelpers.AssertEqual(t, tt.want, notModified(c, tablesETag([]uint{1, 2})))
+		testhelpers.AssertEqual(t, `"t1.2"`, w.Header().Get("ETag"))
+	}
+}
+
+func echoTool(name string) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool(name), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(name), nil
+	}
+}
+
+// TestListETagsChangeOnMutations checks that every kind of change to the registry changes the ETag
+// of the lists that show it, so that clients revalidating their cached lists never miss a change.
+func TestListETagsChangeOnMutations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setup := testhelpers.SetupTestDB(t)
+	testhelpers.AssertNoError(t, db.TrackTableVersions(setup.DB))
+
+	upstream := server.NewMCPServer("github", "0.0.0")
+	upstream.AddTool(echoTool("git_commit"))
+	upstreamServer := server.NewTestStreamableHTTPServer(upstream)
+	defer upstreamServer.Close()
+
+	proxy := server.NewMCPServer("test", "0.0.0")
+	mcpService, err := mcpservice.NewMCPService(&mcpservice.ServiceConfig{
+		DB:                      setup.DB,
+		McpProxyServer:          proxy,
+		SseMcpProxyServer:       proxy,
+		Metrics:                 telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout: 5,
+	})
+	testhelpers.AssertNoError(t, err)
+	toolGroupService, err := toolgroup.NewToolGroupService(setup.DB, mcpService)
+	testhelpers.AssertNoError(t, err)
+
+	s := &Server{mcpService: mcpService, toolGroupService: toolGroupService, tableVersions: db.NewTableVersions(setup.DB)}
+	router := gin.New()
+	router.GET("/servers", s.conditionalOnTables([]string{model.TableMcpServers}, nil), s.listServersHandler())
+	router.GET("/tools", s.conditionalOnTables([]string{model.TableTools, model.TableToolGroups}, nil), s.listToolsHandler())
+	router.GET("/tool-groups", s.conditionalOnTables([]string{model.TableToolGroups}, nil), s.listToolGroupsHandler())
+
+	etags := map[string]string{}
+	// get fetches a list, revalidating the previous response, and returns true if the list changed since
+	get := func(path string) bool {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		if etag, ok := etags[path]; ok {
+			req.Header.Set("If-None-Match", etag)
+		}
+		router.ServeHTTP(w, req)
+		if w.Code == http.StatusNotModified {
+			testhelpers.AssertEqual(t, 0, w.Body.Len())
+			return false
+		}
+		testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+		testhelpers.AssertTrue(t, w.Header().Get("ETag") != "", "lists should have an ETag")
+		etags[path] = w.Header().Get("ETag")
+		return true
+	}
+	for _, path := range []string{"/servers", "/tools", "/tool-groups"} {
+		get(path)
+		testhelpers.AssertFalse(t, get(path), path+" should not have changed")
+	}
+
+	// registration
+	github, err := model.NewStreamableHTTPServer("github", "", upstreamServer.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, mcpService.RegisterMcpServer(context.Background(), github))
+	testhelpers.AssertTrue(t, get("/servers"), "registering a server should change the servers list")
+	testhelpers.AssertTrue(t, get("/tools"), "registering a server should change the tools list")
+	testhelpers.AssertFalse(t, get("/tool-groups"), "registering a server should not change the groups list")
+
+	// disabling and enabling tools
+	_, err = mcpService.DisableTools("github")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, get("/tools"), "disabling tools should change the tools list")
+	testhelpers.AssertFalse(t, get("/servers"), "disabling tools should not change the servers list")
+	_, err = mcpService.EnableTools("github__git_commit")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, get("/tools"), "enabling a tool should change the tools list")
+
+	// syncing the tools of a server whose connection settings changed
+	upstream.AddTool(echoTool("git_push"))
+	updated, err := model.NewStreamableHTTPServer("github", "", upstreamServer.URL+"/v2/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, mcpService.UpdateMcpServer(context.Background(), updated))
+	testhelpers.AssertTrue(t, get("/servers"), "updating a server should change the servers list")
+	testhelpers.AssertTrue(t, get("/tools"), "syncing the tools of a server should change the tools list")
+
+	// creating and updating a group
+	group := &model.ToolGroup{Name: "ci-tools", IncludedTools: datatypes.JSON(`["github__git_commit"]`)}
+	testhelpers.AssertNoError(t, toolGroupService.CreateToolGroup(group))
+	testhelpers.AssertTrue(t, get("/tool-groups"), "creating a group should change the groups list")
+	testhelpers.AssertTrue(t, get("/tools"), "the tools list depends on the groups, for its group filter")
+	_, err = toolGroupService.UpdateToolGroup("ci-tools", &model.ToolGroup{
+		Name: "ci-tools", Description: "Tools of the CI", IncludedTools: datatypes.JSON(`["github__git_push"]`),
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, get("/tool-groups"), "updating a group should change the groups list")
+
+	// deregistration
+	testhelpers.AssertNoError(t, mcpService.DeregisterMcpServer("github"))
+	testhelpers.AssertTrue(t, get("/servers"), "deregistering a server should change the servers list")
+	testhelpers.AssertTrue(t, get("/tools"), "deregistering a server should change the tools list")
+}
@@ -0,0 +1,418 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	adminv1 "github.com/mcpjungle/mcpjungle/proto/admin/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// grpcAdminMethods are the methods of the gRPC admin API that require the admin role in enterprise mode,
+// like their HTTP counterparts. The other methods only require an authenticated user.
+var grpcAdminMethods = map[string]bool{
+	adminv1.AdminService_RegisterServer_FullMethodName:   true,
+	adminv1.AdminService_DeregisterServer_FullMethodName: true,
+	adminv1.AdminService_CreateToolGroup_FullMethodName:  true,
+	adminv1.AdminService_UpdateToolGroup_FullMethodName:  true,
+	adminv1.AdminService_DeleteToolGroup_FullMethodName:  true,
+}
+
+// NewGRPCServer creates the gRPC server of the admin API defined in proto/admin/v1.
+// It calls the same services as the HTTP API and applies the same authorization,
+// with the user's access token passed in the "authorization" metadata.
+// Reflection is enabled so that the API can be explored with tools like grpcurl.
+func (s *Server) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := s.authorizeGRPCCall(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := s.authorizeGRPCCall(ss.Context(), info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	)
+	g := grpc.NewServer(opts...)
+	adminv1.RegisterAdminServiceServer(g, &grpcAdminService{s: s})
+	reflection.Register(g)
+	return g
+}
+
+// authorizeGRPCCall rejects calls to the admin API made before the server is initialized,
+// and in enterprise mode, calls without a valid user token or by a user without the role the method requires.
+// Calls to other services, ie, reflection, are not subject to authorization.
+func (s *Server) authorizeGRPCCall(ctx context.Context, method string) error {
+	if !strings.HasPrefix(method, "/"+adminv1.AdminService_ServiceDesc.ServiceName+"/") {
+		return nil
+	}
+	cfg, err := s.configService.GetConfig()
+	if err != nil || !cfg.Initialized {
+		return status.Error(codes.FailedPrecondition, "server is not initialized")
+	}
+	if cfg.Mode == model.ModeDev {
+		// no auth is required in case of dev mode
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var token string
+	if v := md.Get("authorization"); len(v) > 0 {
+		token = strings.TrimPrefix(v[0], "Bearer ")
+	}
+	if token == "" {
+		return status.Error(codes.Unauthenticated, "missing access token")
+	}
+	u, err := s.userService.GetUserByAccessToken(token)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "invalid access token: "+err.Error())
+	}
+	if grpcAdminMethods[method] && u.Role != types.UserRoleAdmin {
+		return status.Errorf(codes.PermissionDenied, "user is not authorized to perform this action, the %s role is required", types.UserRoleAdmin)
+	}
+	return nil
+}
+
+// grpcError converts the error of a service call into a gRPC status, with the code matching the error code
+// the HTTP API responds with for the same error.
+func grpcError(err error) error {
+	// the HTTP API answers 503 to both, but gRPC tells a busy server from an unavailable one
+	if errors.Is(err, mcp.ErrUpstreamCallsSaturated) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	switch _, code := classifyError(err); code {
+	case types.ErrorCodeNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case types.ErrorCodeAlreadyExists:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case types.ErrorCodeVersionConflict:
+		return status.Error(codes.Aborted, err.Error())
+	case types.ErrorCodeInvalidRequest, types.ErrorCodeValidationFailed:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case types.ErrorCodeUpstreamUnreachable, types.ErrorCodeCredentialUnavailable, types.ErrorCodeUnavailable,
+		types.ErrorCodeDockerUnavailable, types.ErrorCodeWarmingUp:
+		return status.Error(codes.Unavailable, err.Error())
+	case types.ErrorCodeForbidden:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case types.ErrorCodeRateLimited:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// grpcPage reads the page requested by a list call, with the same defaults and limits as the HTTP API.
+func grpcPage(size int32, token string) (model.Page, error) {
+	p := model.Page{Limit: DefaultPageLimit}
+	if size < 0 || size > MaxPageLimit {
+		return p, status.Errorf(codes.InvalidArgument, "page_size must be between 0 and %d", MaxPageLimit)
+	}
+	if size > 0 {
+		p.Limit = int(size)
+	}
+	if token != "" {
+		after, err := decodeCursor(token)
+		if err != nil {
+			return p, status.Error(codes.InvalidArgument, "page_token must be a token returned by a previous page")
+		}
+		p.After = after
+	}
+	return p, nil
+}
+
+// nextPageToken returns the token of the page after the one that ends before next, empty if there are no more items.
+func nextPageToken(next uint) string {
+	if next == 0 {
+		return ""
+	}
+	return encodeCursor(next)
+}
+
+// toStruct converts a JSON object, either a Go value or its encoding, into a protobuf Struct.
+// Empty and null values are converted to nil.
+func toStruct(v any) (*structpb.Struct, error) {
+	data, ok := v.([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, nil
+	}
+	return structpb.NewStruct(m)
+}
+
+// grpcAdminService implements the gRPC admin API on top of the services of the API server.
+type grpcAdminService struct {
+	adminv1.UnimplementedAdminServiceServer
+
+	s *Server
+}
+
+func (g *grpcAdminService) ListServers(ctx context.Context, req *adminv1.ListServersRequest) (*adminv1.ListServersResponse, error) {
+	page, err := grpcPage(req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	records, next, err := g.s.mcpService.ListMcpServersPage(page)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &adminv1.ListServersResponse{NextPageToken: nextPageToken(next)}
+	for i := range records {
+		server, err := toMcpServerType(&records[i])
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		resp.Servers = append(resp.Servers, toMcpServerProto(server))
+	}
+	return resp, nil
+}
+
+func (g *grpcAdminService) RegisterServer(ctx context.Context, req *adminv1.RegisterServerRequest) (*adminv1.McpServer, error) {
+	server, err := newMcpServerFromInput(&types.RegisterServerInput{
+		Name:        req.GetName(),
+		Transport:   req.GetTransport(),
+		Description: req.GetDescription(),
+		URL:         req.GetUrl(),
+		BearerToken: req.GetBearerToken(),
+		Command:     req.GetCommand(),
+		Args:        req.GetArgs(),
+		Env:         req.GetEnv(),
+		SessionMode: req.GetSessionMode(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := g.s.mcpService.RegisterMcpServer(ctx, server); err != nil {
+		return nil, grpcError(err)
+	}
+	g.s.publishServerEvent(types.EventServerRegistered, server)
+
+	registered, err := toMcpServerType(server)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return toMcpServerProto(registered), nil
+}
+
+func (g *grpcAdminService) DeregisterServer(ctx context.Context, req *adminv1.DeregisterServerRequest) (*adminv1.DeregisterServerResponse, error) {
+	if err := g.s.mcpService.DeregisterMcpServer(req.GetName()); err != nil {
+		return nil, grpcError(err)
+	}
+	g.s.webhookService.Publish(types.EventServerDeregistered, map[string]string{"name": req.GetName()})
+	return &adminv1.DeregisterServerResponse{}, nil
+}
+
+func (g *grpcAdminService) ListTools(ctx context.Context, req *adminv1.ListToolsRequest) (*adminv1.ListToolsResponse, error) {
+	page, err := grpcPage(req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	if len(req.GetQuery()) > maxToolSearchLength {
+		return nil, status.Errorf(codes.InvalidArgument, "query must not be longer than %d characters", maxToolSearchLength)
+	}
+	filter := mcp.ToolFilter{Server: req.GetServer(), Query: req.GetQuery()}
+	if req.Enabled != nil {
+		enabled := req.GetEnabled()
+		filter.Enabled = &enabled
+	}
+	if name := req.GetGroup(); name != "" {
+		group, err := g.s.toolGroupService.GetToolGroup(name)
+		if errors.Is(err, toolgroup.ErrToolGroupNotFound) {
+			return nil, status.Errorf(codes.InvalidArgument, "tool group %s does not exist", name)
+		}
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		filter.Names, err = g.s.toolGroupService.ResolveGroupTools(group)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to resolve the tools of group %s: %v", name, err)
+		}
+		if filter.Names == nil {
+			filter.Names = []string{}
+		}
+	}
+
+	tools, next, total, err := g.s.mcpService.ListToolsPage(filter, page)
+	var filterErr *mcp.InvalidFilterError
+	if errors.As(err, &filterErr) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid value for %s: %v", filterErr.Field, filterErr.Err)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &adminv1.ListToolsResponse{NextPageToken: nextPageToken(next), Total: total}
+	for i := range tools {
+		tool := &adminv1.Tool{Name: tools[i].Name, Enabled: tools[i].Enabled, Description: tools[i].Description}
+		if tool.InputSchema, err = toStruct([]byte(tools[i].InputSchema)); err != nil {
+			return nil, status.Errorf(codes.Internal, "invalid input schema of tool %s: %v", tool.Name, err)
+		}
+		if tool.Annotations, err = toStruct([]byte(tools[i].Annotations)); err != nil {
+			return nil, status.Errorf(codes.Internal, "invalid annotations of tool %s: %v", tool.Name, err)
+		}
+		resp.Tools = append(resp.Tools, tool)
+	}
+	return resp, nil
+}
+
+// InvokeTool calls the tool, then sends every content item of its result in a message of its own,
+// so that clients can process them as they arrive, and ends the stream with the outcome of the call.
+func (g *grpcAdminService) InvokeTool(req *adminv1.InvokeToolRequest, stream grpc.ServerStreamingServer[adminv1.InvokeToolResponse]) error {
+	if req.GetName() == "" {
+		return status.Error(codes.InvalidArgument, "missing tool name")
+	}
+	result, err := g.s.mcpService.InvokeTool(stream.Context(), req.GetName(), req.GetArguments().AsMap())
+	if err != nil {
+		// the same failures as the HTTP API, so that both can't diverge
+		return grpcError(fmt.Errorf("failed to invoke tool: %w", err))
+	}
+
+	for _, item := range result.Content {
+		content, err := toStruct(item)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to convert the result of the tool: %v", err)
+		}
+		err = stream.Send(&adminv1.InvokeToolResponse{Event: &adminv1.InvokeToolResponse_Content{Content: content}})
+		if err != nil {
+			return err
+		}
+	}
+
+	outcome := &adminv1.ToolInvokeResult{IsError: result.IsError}
+	if outcome.Meta, err = toStruct(result.Meta); err != nil {
+		return status.Errorf(codes.Internal, "failed to convert the metadata of the result: %v", err)
+	}
+	if result.StructuredContent != nil {
+		data, err := json.Marshal(result.StructuredContent)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to convert the structured content of the result: %v", err)
+		}
+		outcome.StructuredContent = &structpb.Value{}
+		if err := outcome.StructuredContent.UnmarshalJSON(data); err != nil {
+			return status.Errorf(codes.Internal, "failed to convert the structured content of the result: %v", err)
+		}
+	}
+	return stream.Send(&adminv1.InvokeToolResponse{Event: &adminv1.InvokeToolResponse_Result{Result: outcome}})
+}
+
+func (g *grpcAdminService) CreateToolGroup(ctx context.Context, req *adminv1.CreateToolGroupRequest) (*adminv1.ToolGroup, error) {
+	group, err := newToolGroupModel(fromToolGroupProto(req.GetGroup()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := g.s.toolGroupService.CreateToolGroup(group); err != nil {
+		return nil, grpcError(err)
+	}
+	created, err := toToolGroupType(group)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	g.s.webhookService.Publish(types.EventGroupCreated, created)
+	return toToolGroupProto(created), nil
+}
+
+func (g *grpcAdminService) UpdateToolGroup(ctx context.Context, req *adminv1.UpdateToolGroupRequest) (*adminv1.UpdateToolGroupResponse, error) {
+	name := req.GetGroup().GetName()
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "group name is required")
+	}
+	updated, err := newToolGroupModel(fromToolGroupProto(req.GetGroup()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	updated.Version = uint(req.GetExpectedVersion())
+
+	originalConf, err := g.s.toolGroupService.UpdateToolGroup(name, updated)
+	if errors.Is(err, toolgroup.ErrToolGroupNotFound) {
+		return nil, status.Errorf(codes.NotFound, "tool group %s does not exist", name)
+	}
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &types.UpdateToolGroupResponse{Name: name}
+	if resp.Old, err = toToolGroupType(originalConf); err != nil {
+		return nil, status.Errorf(codes.Internal, "error reading the original group config: %v", err)
+	}
+	if resp.New, err = toToolGroupType(updated); err != nil {
+		return nil, status.Errorf(codes.Internal, "error reading the new group config: %v", err)
+	}
+	if updated.Version != originalConf.Version {
+		// the group was actually changed
+		g.s.webhookService.Publish(types.EventGroupUpdated, resp)
+	}
+	return &adminv1.UpdateToolGroupResponse{Previous: toToolGroupProto(resp.Old), Current: toToolGroupProto(resp.New)}, nil
+}
+
+func (g *grpcAdminService) DeleteToolGroup(ctx context.Context, req *adminv1.DeleteToolGroupRequest) (*adminv1.DeleteToolGroupResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if err := g.s.toolGroupService.DeleteToolGroup(req.GetName()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	g.s.webhookService.Publish(types.EventGroupDeleted, map[string]string{"name": req.GetName()})
+	return &adminv1.DeleteToolGroupResponse{}, nil
+}
+
+func toMcpServerProto(server *types.McpServer) *adminv1.McpServer {
+	return &adminv1.McpServer{
+		Name:        server.Name,
+		Transport:   server.Transport,
+		Description: server.Description,
+		Url:         server.URL,
+		Command:     server.Command,
+		Args:        server.Args,
+		Env:         server.Env,
+		SessionMode: server.SessionMode,
+		Version:     uint64(server.Version),
+	}
+}
+
+func toToolGroupProto(group *types.ToolGroup) *adminv1.ToolGroup {
+	return &adminv1.ToolGroup{
+		Name:            group.Name,
+		Description:     group.Description,
+		IncludedTools:   group.IncludedTools,
+		IncludedServers: group.IncludedServers,
+		ExcludedTools:   group.ExcludedTools,
+		Version:         uint64(group.Version),
+	}
+}
+
+func fromToolGroupProto(group *adminv1.ToolGroup) *types.ToolGroup {
+	return &types.ToolGroup{
+		Name:            group.GetName(),
+		Description:     group.GetDescription(),
+		IncludedTools:   group.GetIncludedTools(),
+		IncludedServers: group.GetIncludedServers(),
+		ExcludedTools:   group.GetExcludedTools(),
+	}
+}
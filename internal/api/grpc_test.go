@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	mcpservice "github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	adminv1 "github.com/mcpjungle/mcpjungle/proto/admin/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newGRPCTestServer creates an API server in the given mode backed by an in-memory database.
+// If mode is empty, the server is not initialized.
+func newGRPCTestServer(t *testing.T, mode model.ServerMode) *Server {
+	t.Helper()
+	setup := testhelpers.SetupTestDB(t)
+	proxy := server.NewMCPServer("test", "0.0.0")
+	mcpService, err := mcpservice.NewMCPService(&mcpservice.ServiceConfig{
+		DB:                      setup.DB,
+		McpProxyServer:          proxy,
+		SseMcpProxyServer:       proxy,
+		Metrics:                 telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout: 5,
+	})
+	testhelpers.AssertNoError(t, err)
+	toolGroupService, err := toolgroup.NewToolGroupService(setup.DB, mcpService)
+	testhelpers.AssertNoError(t, err)
+
+	configService := config.NewServerConfigService(setup.DB)
+	if mode != "" {
+		_, err = configService.Init(mode)
+		testhelpers.AssertNoError(t, err)
+	}
+	return &Server{
+		mcpService:       mcpService,
+		toolGroupService: toolGroupService,
+		configService:    configService,
+		userService:      user.NewUserService(setup.DB),
+	}
+}
+
+// dialGRPC serves the gRPC API of s in memory and returns a connection to it.
+func dialGRPC(t *testing.T, s *Server) *grpc.ClientConn {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	g := s.NewGRPCServer()
+	go func() { _ = g.Serve(ln) }()
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	testhelpers.AssertNoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func assertCode(t *testing.T, want codes.Code, err error) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("expected code %s, got %s (%v)", want, got, err)
+	}
+}
+
+func TestGRPCAdminAPI(t *testing.T) {
+	upstream := server.NewMCPServer("github", "0.0.0")
+	upstream.AddTool(echoTool("git_commit"))
+	upstreamServer := server.NewTestStreamableHTTPServer(upstream)
+	defer upstreamServer.Close()
+
+	s := newGRPCTestServer(t, model.ModeDev)
+	client := adminv1.NewAdminServiceClient(dialGRPC(t, s))
+	ctx := context.Background()
+
+	_, err := client.RegisterServer(ctx, &adminv1.RegisterServerRequest{Name: "github", Transport: "carrier_pigeon"})
+	assertCode(t, codes.InvalidArgument, err)
+	registered, err := client.RegisterServer(ctx, &adminv1.RegisterServerRequest{
+		Name: "github", Transport: "streamable_http", Url: upstreamServer.URL + "/mcp",
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "github", registered.GetName())
+
+	servers, err := client.ListServers(ctx, &adminv1.ListServersRequest{})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(servers.GetServers()))
+	testhelpers.AssertEqual(t, upstreamServer.URL+"/mcp", servers.GetServers()[0].GetUrl())
+
+	tools, err := client.ListTools(ctx, &adminv1.ListToolsRequest{Server: "github"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, int64(1), tools.GetTotal())
+	testhelpers.AssertEqual(t, "github__git_commit", tools.GetTools()[0].GetName())
+	_, err = client.ListTools(ctx, &adminv1.ListToolsRequest{Group: "missing"})
+	assertCode(t, codes.InvalidArgument, err)
+
+	// the content of the result is streamed before its outcome
+	stream, err := client.InvokeTool(ctx, &adminv1.InvokeToolRequest{Name: "github__git_commit"})
+	testhelpers.AssertNoError(t, err)
+	msg, err := stream.Recv()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "git_commit", msg.GetContent().GetFields()["text"].GetStringValue())
+	msg, err = stream.Recv()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, msg.GetResult() != nil, "the stream should end with the outcome of the call")
+	testhelpers.AssertFalse(t, msg.GetResult().GetIsError(), "the call should have succeeded")
+	_, err = stream.Recv()
+	testhelpers.AssertTrue(t, errors.Is(err, io.EOF), "the stream should be over")
+
+	// the calls fail like they do through the HTTP API
+	stream, err = client.InvokeTool(ctx, &adminv1.InvokeToolRequest{Name: "gitlab__git_commit"})
+	testhelpers.AssertNoError(t, err)
+	_, err = stream.Recv()
+	assertCode(t, codes.NotFound, err)
+
+	group, err := client.CreateToolGroup(ctx, &adminv1.CreateToolGroupRequest{
+		Group: &adminv1.ToolGroup{Name: "ci-tools", IncludedTools: []string{"github__git_commit"}},
+	})
+	testhelpers.AssertNoError(t, err)
+	_, err = client.UpdateToolGroup(ctx, &adminv1.UpdateToolGroupRequest{
+		Group:           &adminv1.ToolGroup{Name: "ci-tools", Description: "Tools of the CI", IncludedServers: []string{"github"}},
+		ExpectedVersion: group.GetVersion() + 1,
+	})
+	assertCode(t, codes.Aborted, err)
+	updated, err := client.UpdateToolGroup(ctx, &adminv1.UpdateToolGroupRequest{
+		Group:           &adminv1.ToolGroup{Name: "ci-tools", Description: "Tools of the CI", IncludedServers: []string{"github"}},
+		ExpectedVersion: group.GetVersion(),
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "", updated.GetPrevious().GetDescription())
+	testhelpers.AssertEqual(t, "Tools of the CI", updated.GetCurrent().GetDescription())
+	_, err = client.UpdateToolGroup(ctx, &adminv1.UpdateToolGroupRequest{Group: &adminv1.ToolGroup{Name: "missing"}})
+	assertCode(t, codes.NotFound, err)
+	_, err = client.DeleteToolGroup(ctx, &adminv1.DeleteToolGroupRequest{Name: "ci-tools"})
+	testhelpers.AssertNoError(t, err)
+
+	_, err = client.DeregisterServer(ctx, &adminv1.DeregisterServerRequest{Name: "github"})
+	testhelpers.AssertNoError(t, err)
+	_, err = client.DeregisterServer(ctx, &adminv1.DeregisterServerRequest{Name: "github"})
+	assertCode(t, codes.NotFound, err)
+}
+
+func TestGRPCAuthorization(t *testing.T) {
+	s := newGRPCTestServer(t, model.ModeEnterprise)
+	conn := dialGRPC(t, s)
+	client := adminv1.NewAdminServiceClient(conn)
+
+	admin, err := s.userService.CreateAdminUser()
+	testhelpers.AssertNoError(t, err)
+	u, err := s.userService.CreateUser(&model.User{Username: "alice"})
+	testhelpers.AssertNoError(t, err)
+	withToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+
+	_, err = client.ListServers(context.Background(), &adminv1.ListServersRequest{})
+	assertCode(t, codes.Unauthenticated, err)
+	_, err = client.ListServers(withToken("not-a-token"), &adminv1.ListServersRequest{})
+	assertCode(t, codes.Unauthenticated, err)
+
+	// users can list, only admins can make changes
+	_, err = client.ListServers(withToken(u.AccessToken), &adminv1.ListServersRequest{})
+	testhelpers.AssertNoError(t, err)
+	_, err = client.DeleteToolGroup(withToken(u.AccessToken), &adminv1.DeleteToolGroupRequest{Name: "ci-tools"})
+	assertCode(t, codes.PermissionDenied, err)
+	_, err = client.DeleteToolGroup(withToken(admin.AccessToken), &adminv1.DeleteToolGroupRequest{Name: "ci-tools"})
+	testhelpers.AssertNoError(t, err)
+
+	// reflection doesn't require a token
+	refl, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, refl.Send(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := refl.Recv()
+	testhelpers.AssertNoError(t, err)
+	var services []string
+	for _, svc := range resp.GetListServicesResponse().GetService() {
+		services = append(services, svc.GetName())
+	}
+	testhelpers.AssertStringContains(t, strings.Join(services, ","), adminv1.AdminService_ServiceDesc.ServiceName)
+
+	// the API is unavailable until the server is initialized
+	uninitialized := newGRPCTestServer(t, "")
+	_, err = adminv1.NewAdminServiceClient(dialGRPC(t, uninitialized)).ListServers(context.Background(), &adminv1.ListServersRequest{})
+	assertCode(t, codes.FailedPrecondition, err)
+}
+
+func TestGRPCError(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want codes.Code
+	}{
+		{fmt.Errorf("failed to invoke tool: %w", mcpservice.ErrMcpServerUnreachable), codes.Unavailable},
+		{fmt.Errorf("failed to invoke tool: %w", mcpservice.ErrCredentialUnavailable), codes.Unavailable},
+		{fmt.Errorf("failed to invoke tool: %w", mcpservice.ErrServerWarmingUp), codes.Unavailable},
+		{fmt.Errorf("failed to invoke tool: %w", mcpservice.ErrUpstreamCallsSaturated), codes.ResourceExhausted},
+		{fmt.Errorf("failed to invoke tool: %w", mcpservice.ErrServerAccessDenied), codes.PermissionDenied},
+		{errors.New("boom"), codes.Internal},
+	} {
+		assertCode(t, tt.want, grpcError(tt.err))
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// maxIdempotencyKeyLength is the length of the longest idempotency key accepted.
+const maxIdempotencyKeyLength = 255
+
+// idempotent is middleware that applies the requests carrying an Idempotency-Key header at most once.
+// The response to the first request with a key is stored, and replayed with an Idempotent-Replayed header
+// when a request is sent again with the same key and the same method, URL and body.
+// Reusing a key for a different request fails with status 422, and sending it while the first request
+// is still being processed fails with status 409.
+//
+// Responses with a 5xx or 429 status are not stored, since the request can be retried with the same key.
+// It assumes that verifyUserAuthForAPIAccess middleware has already run and set the user in context.
+func (s *Server) idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(types.IdempotencyKeyHeader)
+		if s.idempotencyService == nil || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			respondError(c, validationFailed(
+				"%s header must be at most %d characters long", types.IdempotencyKeyHeader, maxIdempotencyKeyLength,
+			).with("header", types.IdempotencyKeyHeader))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			respondError(c, invalidRequest("failed to read request body: %v", err))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := ""
+		if u, ok := c.Get("user"); ok {
+			if user, ok := u.(*model.User); ok {
+				scope = user.Username
+			}
+		} else if client := authenticatedMcpClient(c); client != nil {
+			// keys of MCP clients must not collide with the ones of users with the same name
+			scope = "mcp-client:" + client.Name
+		}
+		hash := requestHash(c.Request, body)
+
+		inFlight := scope + "\x00" + key
+		if _, busy := s.idempotencyInFlight.LoadOrStore(inFlight, struct{}{}); busy {
+			respondError(c, newAPIError(
+				http.StatusConflict, types.ErrorCodeRequestInProgress,
+				"a request with %s %q is already being processed", types.IdempotencyKeyHeader, key,
+			))
+			return
+		}
+		defer s.idempotencyInFlight.Delete(inFlight)
+
+		stored, err := s.idempotencyService.Lookup(scope, key)
+		if err != nil {
+			respondError(c, fmt.Errorf("failed to look up idempotency key: %w", err))
+			return
+		}
+		if stored != nil {
+			if stored.RequestHash != hash {
+				respondError(c, newAPIError(
+					http.StatusUnprocessableEntity, types.ErrorCodeIdempotencyKeyReused,
+					"%s %q was already used for a different request, use a new key for every request",
+					types.IdempotencyKeyHeader, key,
+				))
+				return
+			}
+			c.Header(types.IdempotentReplayedHeader, "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		w := &capturingResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		status := w.Status()
+		if status >= 500 || status == http.StatusTooManyRequests {
+			return
+		}
+		err = s.idempotencyService.Save(&model.IdempotencyKey{
+			Scope:        scope,
+			Key:          key,
+			RequestHash:  hash,
+			StatusCode:   status,
+			ContentType:  w.Header().Get("Content-Type"),
+			ResponseBody: w.body.Bytes(),
+		})
+		if err != nil {
+			log.Printf("[WARN] failed to store the response for idempotency key %q: %v", key, err)
+		}
+	}
+}
+
+// requestHash returns a hash of the method, URL and body of req, to tell requests reusing an idempotency key apart.
+func requestHash(req *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// capturingResponseWriter keeps a copy of the response body written through it.
+type capturingResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingResponseWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *capturingResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
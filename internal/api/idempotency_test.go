@@ -0,0 +1,119 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/idempotency"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestIdempotentRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setup := testhelpers.SetupTestDB(t)
+	// every connection to an in-memory database opens a new, empty one
+	sqlDB, err := setup.DB.DB()
+	testhelpers.AssertNoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	idempotencyService := idempotency.NewIdempotencyService(setup.DB, nil)
+	defer idempotencyService.Close()
+	s := &Server{idempotencyService: idempotencyService}
+
+	var calls atomic.Int32
+	// release blocks the handler while a request is being processed, if set
+	var release chan struct{}
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if name := c.GetHeader("X-User"); name != "" {
+			c.Set("user", &model.User{Username: name})
+		}
+	})
+	router.POST("/tool-groups", s.idempotent(), func(c *gin.Context) {
+		if release != nil {
+			<-release
+		}
+		n := calls.Add(1)
+		if c.Query("fail") != "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "unavailable"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"created": n})
+	})
+	send := func(key, user, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(types.IdempotencyKeyHeader, key)
+		}
+		if user != "" {
+			req.Header.Set("X-User", user)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// requests without a key are applied every time
+	send("", "", "/tool-groups", `{"name": "ci-tools"}`)
+	send("", "", "/tool-groups", `{"name": "ci-tools"}`)
+	testhelpers.AssertEqual(t, int32(2), calls.Load())
+
+	w := send("k1", "alice", "/tool-groups", `{"name": "ci-tools"}`)
+	testhelpers.AssertEqual(t, http.StatusCreated, w.Code)
+	testhelpers.AssertEqual(t, "", w.Header().Get(types.IdempotentReplayedHeader))
+	first := w.Body.String()
+
+	// the same request is replayed
+	w = send("k1", "alice", "/tool-groups", `{"name": "ci-tools"}`)
+	testhelpers.AssertEqual(t, http.StatusCreated, w.Code)
+	testhelpers.AssertEqual(t, "true", w.Header().Get(types.IdempotentReplayedHeader))
+	testhelpers.AssertEqual(t, first, w.Body.String())
+	testhelpers.AssertEqual(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	testhelpers.AssertEqual(t, int32(3), calls.Load())
+
+	// a different request with the same key is rejected
+	w = send("k1", "alice", "/tool-groups", `{"name": "cd-tools"}`)
+	testhelpers.AssertEqual(t, http.StatusUnprocessableEntity, w.Code)
+	testhelpers.AssertStringContains(t, w.Body.String(), "already used for a different request")
+	w = send("k1", "alice", "/tool-groups?dry_run=true", `{"name": "ci-tools"}`)
+	testhelpers.AssertEqual(t, http.StatusUnprocessableEntity, w.Code)
+
+	// keys are per user
+	w = send("k1", "bob", "/tool-groups", `{"name": "ci-tools"}`)
+	testhelpers.AssertEqual(t, http.StatusCreated, w.Code)
+	testhelpers.AssertEqual(t, "", w.Header().Get(types.IdempotentReplayedHeader))
+	testhelpers.AssertEqual(t, int32(4), calls.Load())
+
+	// server errors are not stored, so that the request can be retried
+	send("k2", "alice", "/tool-groups?fail=true", `{}`)
+	w = send("k2", "alice", "/tool-groups?fail=true", `{}`)
+	testhelpers.AssertEqual(t, http.StatusServiceUnavailable, w.Code)
+	testhelpers.AssertEqual(t, int32(6), calls.Load())
+
+	w = send(strings.Repeat("k", maxIdempotencyKeyLength+1), "alice", "/tool-groups", `{}`)
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+
+	// a duplicate sent while the first request is being processed is rejected
+	release = make(chan struct{})
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- send("k3", "alice", "/tool-groups", `{}`) }()
+	for {
+		if _, busy := s.idempotencyInFlight.Load("alice\x00k3"); busy {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	w = send("k3", "alice", "/tool-groups", `{}`)
+	testhelpers.AssertEqual(t, http.StatusConflict, w.Code)
+	close(release)
+	w = <-done
+	testhelpers.AssertEqual(t, http.StatusCreated, w.Code)
+	testhelpers.AssertEqual(t, fmt.Sprintf(`{"created":%d}`, calls.Load()), w.Body.String())
+}
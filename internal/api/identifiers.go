@@ -0,0 +1,24 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+// pathName returns the name of the entity addressed by the path parameter param, which holds either its UUID
+// or its name, using resolve to look up the name of a UUID.
+// If the lookup fails, an error response is sent and false is returned.
+func pathName(c *gin.Context, param string, resolve func(ident string) (string, error)) (string, bool) {
+	ident := c.Param(param)
+	if !model.IsUUID(ident) {
+		return ident, true
+	}
+	name, err := resolve(ident)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to look up %s: %w", ident, err))
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcpclient"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// newIdentifiersTestRouter serves the routes addressing entities by name or UUID, for a registry with a git server
+// providing a commit tool, a tool group, a user and an MCP client.
+func newIdentifiersTestRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(
+		&model.McpServer{}, &model.Tool{}, &model.Prompt{}, &model.ToolGroup{}, &model.User{}, &model.McpClient{},
+	))
+
+	mcpService, err := mcp.NewMCPService(&mcp.ServiceConfig{
+		DB:                      db,
+		McpProxyServer:          &server.MCPServer{},
+		SseMcpProxyServer:       &server.MCPServer{},
+		Metrics:                 telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout: 10,
+	})
+	testhelpers.AssertNoError(t, err)
+	git := &model.McpServer{Name: "git", Transport: types.TransportStdio, Config: []byte(`{"command":"git-mcp"}`)}
+	testhelpers.AssertNoError(t, db.Create(git).Error)
+	testhelpers.AssertNoError(t, db.Create(&model.Tool{ServerID: git.ID, Name: "commit"}).Error)
+	testhelpers.AssertNoError(t, db.Create(&model.ToolGroup{Name: "review"}).Error)
+	testhelpers.AssertNoError(t, db.Create(&model.User{Username: "alice", Role: types.UserRoleUser, AccessToken: "alice-token"}).Error)
+	testhelpers.AssertNoError(t, db.Create(&model.McpClient{Name: "cursor", AccessToken: "cursor-token", AllowList: []byte("[]")}).Error)
+	toolGroupService, err := toolgroup.NewToolGroupService(db, mcpService)
+	testhelpers.AssertNoError(t, err)
+
+	s := &Server{
+		mcpService:       mcpService,
+		toolGroupService: toolGroupService,
+		userService:      user.NewUserService(db),
+		mcpClientService: mcpclient.NewMCPClientService(db),
+	}
+	router := gin.New()
+	router.GET("/servers/:name", s.getServerHandler())
+	router.GET("/server_configs/:name", s.getServerConfigHandler())
+	router.GET("/tool", s.getToolHandler())
+	router.GET("/tool-groups/:name", s.getToolGroupHandler())
+	router.PUT("/users/:username", s.updateUserHandler())
+	router.DELETE("/clients/:name", s.deleteMcpClientHandler())
+	return router, db
+}
+
+func serveIdentifiersTest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// uuidOf returns the UUID of the entity whose name column holds name.
+func uuidOf(t *testing.T, db *gorm.DB, entity any, nameColumn, name string) string {
+	t.Helper()
+	var ids []string
+	testhelpers.AssertNoError(t, db.Model(entity).Where(nameColumn+" = ?", name).Pluck("uuid", &ids).Error)
+	testhelpers.AssertEqual(t, 1, len(ids))
+	testhelpers.AssertTrue(t, model.IsUUID(ids[0]), "expected a UUID, got "+ids[0])
+	return ids[0]
+}
+
+// rename changes the name of an entity in the DB, its UUID must keep addressing it.
+func rename(t *testing.T, db *gorm.DB, entity any, nameColumn, from, to string) {
+	t.Helper()
+	testhelpers.AssertNoError(t, db.Model(entity).Where(nameColumn+" = ?", from).Update(nameColumn, to).Error)
+}
+
+func TestServerLookupByUUID(t *testing.T) {
+	router, db := newIdentifiersTestRouter(t)
+	id := uuidOf(t, db, &model.McpServer{}, "name", "git")
+
+	for _, ident := range []string{"git", id} {
+		w := serveIdentifiersTest(router, http.MethodGet, "/servers/"+ident, "")
+		testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+		var s types.McpServer
+		testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &s))
+		testhelpers.AssertEqual(t, id, s.UUID)
+		testhelpers.AssertEqual(t, "git", s.Name)
+	}
+
+	rename(t, db, &model.McpServer{}, "name", "git", "github")
+	w := serveIdentifiersTest(router, http.MethodGet, "/servers/"+id, "")
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	var s types.McpServer
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &s))
+	testhelpers.AssertEqual(t, id, s.UUID)
+	testhelpers.AssertEqual(t, "github", s.Name)
+
+	// a UUID that addresses no server is looked up as a name
+	w = serveIdentifiersTest(router, http.MethodGet, "/servers/00000000-0000-4000-8000-000000000000", "")
+	testhelpers.AssertEqual(t, http.StatusNotFound, w.Code)
+}
+
+func TestServerConfigLookup(t *testing.T) {
+	router, db := newIdentifiersTestRouter(t)
+	id := uuidOf(t, db, &model.McpServer{}, "name", "git")
+
+	for _, ident := range []string{"git", id} {
+		w := serveIdentifiersTest(router, http.MethodGet, "/server_configs/"+ident, "")
+		testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+		var s types.RegisterServerInput
+		testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &s))
+		testhelpers.AssertEqual(t, "git", s.Name)
+		testhelpers.AssertEqual(t, "git-mcp", s.Command)
+	}
+
+	w := serveIdentifiersTest(router, http.MethodGet, "/server_configs/missing", "")
+	testhelpers.AssertEqual(t, http.StatusNotFound, w.Code)
+}
+
+func TestToolLookupByUUID(t *testing.T) {
+	router, db := newIdentifiersTestRouter(t)
+	id := uuidOf(t, db, &model.Tool{}, "name", "commit")
+
+	rename(t, db, &model.McpServer{}, "name", "git", "github")
+	w := serveIdentifiersTest(router, http.MethodGet, "/tool?name="+id, "")
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	var tool types.Tool
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &tool))
+	testhelpers.AssertEqual(t, id, tool.UUID)
+	testhelpers.AssertEqual(t, "github__commit", tool.Name)
+}
+
+func TestToolGroupLookupByUUID(t *testing.T) {
+	router, db := newIdentifiersTestRouter(t)
+	id := uuidOf(t, db, &model.ToolGroup{}, "name", "review")
+
+	rename(t, db, &model.ToolGroup{}, "name", "review", "code-review")
+	w := serveIdentifiersTest(router, http.MethodGet, "/tool-groups/"+id, "")
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	var g types.GetToolGroupResponse
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &g))
+	testhelpers.AssertEqual(t, id, g.UUID)
+	testhelpers.AssertEqual(t, "code-review", g.Name)
+}
+
+func TestUserLookupByUUID(t *testing.T) {
+	router, db := newIdentifiersTestRouter(t)
+	id := uuidOf(t, db, &model.User{}, "username", "alice")
+
+	rename(t, db, &model.User{}, "username", "alice", "alice.smith")
+	w := serveIdentifiersTest(router, http.MethodPut, "/users/"+id, `{"access_token": "alice-new-token"}`)
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	var resp types.CreateOrUpdateUserResponse
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	testhelpers.AssertEqual(t, id, resp.UUID)
+	testhelpers.AssertEqual(t, "alice.smith", resp.Username)
+	testhelpers.AssertEqual(t, "alice-new-token", resp.AccessToken)
+}
+
+func TestMcpClientLookupByUUID(t *testing.T) {
+	router, db := newIdentifiersTestRouter(t)
+	id := uuidOf(t, db, &model.McpClient{}, "name", "cursor")
+
+	rename(t, db, &model.McpClient{}, "name", "cursor", "cursor-ide")
+	w := serveIdentifiersTest(router, http.MethodDelete, "/clients/"+id, "")
+	testhelpers.AssertEqual(t, http.StatusNoContent, w.Code)
+	var count int64
+	testhelpers.AssertNoError(t, db.Unscoped().Model(&model.McpClient{}).Count(&count).Error)
+	testhelpers.AssertEqual(t, int64(0), count)
+}
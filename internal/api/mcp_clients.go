@@ -1,38 +1,52 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
 func (s *Server) listMcpClientsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clients, err := s.mcpClientService.ListClients()
+		page, ok := parsePage(c)
+		if !ok {
+			return
+		}
+		clients, next, err := s.mcpClientService.ListClientsPage(page)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
-		c.JSON(http.StatusOK, clients)
+		c.JSON(http.StatusOK, newPage(clients, next))
 	}
 }
 
 func (s *Server) createMcpClientHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var req model.McpClient
-		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		var input types.McpClient
+		if err := c.ShouldBindJSON(&input); err != nil {
+			respondError(c, invalidRequest("invalid request body: %v", err))
 			return
 		}
-		if req.Name == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		if err := input.Validate(); err != nil {
+			respondError(c, err)
 			return
 		}
-		// TODO: if allow list in the request is null, convert it to an empty JSON array
+		req := model.McpClient{Name: input.Name, Description: input.Description, AccessToken: input.AccessToken}
+		if input.AllowList != nil {
+			allowList, err := json.Marshal(input.AllowList)
+			if err != nil {
+				respondError(c, err)
+				return
+			}
+			req.AllowList = allowList
+		}
 		client, err := s.mcpClientService.CreateClient(req)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 		c.JSON(http.StatusCreated, client)
@@ -41,13 +55,16 @@ func (s *Server) createMcpClientHandler() gin.HandlerFunc {
 
 func (s *Server) deleteMcpClientHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		name := c.Param("name")
+		name, ok := pathName(c, "name", s.mcpClientService.ResolveClientName)
+		if !ok {
+			return
+		}
 		if name == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+			respondError(c, validationFailed("name is required").with("field", "name"))
 			return
 		}
 		if err := s.mcpClientService.DeleteClient(name); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 		c.Status(http.StatusNoContent)
@@ -56,21 +73,24 @@ func (s *Server) deleteMcpClientHandler() gin.HandlerFunc {
 
 func (s *Server) updateMcpClientHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		name := c.Param("name")
+		name, ok := pathName(c, "name", s.mcpClientService.ResolveClientName)
+		if !ok {
+			return
+		}
 		if name == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+			respondError(c, validationFailed("name is required").with("field", "name"))
 			return
 		}
 		var req model.McpClient
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			respondError(c, invalidRequest("invalid request body: %v", err))
 			return
 		}
 		req.Name = name // Ensure the name from the URL is used
 
 		resp, err := s.mcpClientService.UpdateClient(req)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 		c.JSON(http.StatusOK, resp)
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -24,7 +25,7 @@ func (s *Server) listPromptsHandler() gin.HandlerFunc {
 			prompts, err = s.mcpService.ListPromptsByServer(server)
 		}
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 		c.JSON(http.StatusOK, prompts)
@@ -38,12 +39,12 @@ func (s *Server) getPromptHandler() gin.HandlerFunc {
 		// cannot be supplied as a path param.
 		name := c.Query("name")
 		if name == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'name' query parameter"})
+			invalidParam(c, "name", "is required")
 			return
 		}
 		prompt, err := s.mcpService.GetPrompt(name)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get prompt: " + err.Error()})
+			respondError(c, fmt.Errorf("failed to get prompt: %w", err))
 			return
 		}
 
@@ -56,15 +57,12 @@ func (s *Server) getPromptWithArgsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var request types.PromptGetRequest
 		if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
-			c.JSON(
-				http.StatusBadRequest,
-				gin.H{"error": "failed to decode request body: " + err.Error()},
-			)
+			respondError(c, invalidRequest("failed to decode request body: %v", err))
 			return
 		}
 
 		if request.Name == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'name' field in request body"})
+			respondError(c, validationFailed("missing 'name' field in request body").with("field", "name"))
 			return
 		}
 
@@ -76,7 +74,7 @@ func (s *Server) getPromptWithArgsHandler() gin.HandlerFunc {
 
 		resp, err := s.mcpService.GetPromptWithArgs(c, request.Name, args)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get prompt: " + err.Error()})
+			respondError(c, fmt.Errorf("failed to get prompt: %w", err))
 			return
 		}
 
@@ -89,12 +87,12 @@ func (s *Server) enablePromptsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		entity := c.Query("entity")
 		if entity == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'entity' query parameter"})
+			invalidParam(c, "entity", "is required")
 			return
 		}
 		enabledPrompts, err := s.mcpService.EnablePrompts(entity)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to enable prompt(s): " + err.Error()})
+			respondError(c, fmt.Errorf("failed to enable prompt(s): %w", err))
 			return
 		}
 		c.JSON(http.StatusOK, enabledPrompts)
@@ -106,12 +104,12 @@ func (s *Server) disablePromptsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		entity := c.Query("entity")
 		if entity == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'entity' query parameter"})
+			invalidParam(c, "entity", "is required")
 			return
 		}
 		disabledPrompts, err := s.mcpService.DisablePrompts(entity)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to disable prompt(s): " + err.Error()})
+			respondError(c, fmt.Errorf("failed to disable prompt(s): %w", err))
 			return
 		}
 		c.JSON(http.StatusOK, disabledPrompts)
@@ -1,180 +1,573 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"maps"
 	"net/http"
+	"slices"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/mcpjungle/mcpjungle/pkg/util"
 )
 
-func (s *Server) registerServerHandler() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		var input types.RegisterServerInput
-		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
+// newMcpServerFromInput validates a server configuration supplied by a client and creates the model it describes.
+// Errors returned by this function are caused by invalid input.
+func newMcpServerFromInput(input *types.RegisterServerInput) (*model.McpServer, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
 
-		transport, err := types.ValidateTransport(input.Transport)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
+	transport, err := types.ValidateTransport(input.Transport)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionMode, err := types.ValidateSessionMode(input.SessionMode)
+	if err != nil {
+		return nil, err
+	}
 
-		sessionMode, err := types.ValidateSessionMode(input.SessionMode)
+	switch transport {
+	case types.TransportStreamableHTTP:
+		server, err := model.NewStreamableHTTPServer(
+			input.Name,
+			input.Description,
+			input.URL,
+			input.BearerToken,
+			sessionMode,
+		)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
+			return nil, fmt.Errorf("Error creating streamable http server: %v", err)
 		}
-
+		if input.HTTPPool != nil {
+			if err := server.SetHTTPPoolConfig(input.HTTPPool); err != nil {
+				return nil, fmt.Errorf("Error creating streamable http server: %v", err)
+			}
+		}
+		if input.KeepAlive != nil {
+			if err := server.SetKeepAliveConfig(input.KeepAlive); err != nil {
+				return nil, fmt.Errorf("Error creating streamable http server: %v", err)
+			}
+		}
+		if input.Container != nil {
+			if err := server.SetContainerConfig(input.Container); err != nil {
+				return nil, fmt.Errorf("Error creating streamable http server: %v", err)
+			}
+		}
+		return server, nil
+	case types.TransportStdio:
 		var server *model.McpServer
-
-		switch transport {
-		case types.TransportStreamableHTTP:
-			server, err = model.NewStreamableHTTPServer(
+		if input.Package != nil {
+			server, err = model.NewPackageStdioServer(
 				input.Name,
 				input.Description,
-				input.URL,
-				input.BearerToken,
+				input.Package,
+				input.Args,
+				input.Env,
 				sessionMode,
 			)
-			if err != nil {
-				c.JSON(
-					http.StatusBadRequest,
-					gin.H{"error": fmt.Sprintf("Error creating streamable http server: %v", err)},
-				)
-				return
-			}
-		case types.TransportStdio:
-			server, err = model.NewStdioServer(
+		} else if input.Container != nil {
+			server, err = model.NewContainerStdioServer(
 				input.Name,
 				input.Description,
+				input.Container,
 				input.Command,
 				input.Args,
 				input.Env,
 				sessionMode,
 			)
-			if err != nil {
-				c.JSON(
-					http.StatusBadRequest,
-					gin.H{"error": fmt.Sprintf("Error creating stdio server: %v", err)},
-				)
-				return
-			}
-		default:
-			// transport is SSE
-			server, err = model.NewSSEServer(
+		} else {
+			server, err = model.NewStdioServer(
 				input.Name,
 				input.Description,
-				input.URL,
-				input.BearerToken,
+				input.Command,
+				input.Args,
+				input.Env,
 				sessionMode,
 			)
-			if err != nil {
-				c.JSON(
-					http.StatusBadRequest,
-					gin.H{"error": fmt.Sprintf("Error creating SSE server: %v", err)},
-				)
-				return
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error creating stdio server: %v", err)
+		}
+		server.LazyStart = input.LazyStart
+		return server, nil
+	case types.TransportOpenAPI:
+		server, err := model.NewOpenAPIServer(input.Name, input.Description, input.OpenAPI)
+		if err != nil {
+			return nil, fmt.Errorf("Error creating openapi server: %v", err)
+		}
+		return server, nil
+	case types.TransportWebhookTool:
+		server, err := model.NewWebhookToolServer(input.Name, input.Description, input.WebhookTool)
+		if err != nil {
+			return nil, fmt.Errorf("Error creating webhook_tool server: %v", err)
+		}
+		return server, nil
+	default:
+		// transport is SSE
+		server, err := model.NewSSEServer(
+			input.Name,
+			input.Description,
+			input.URL,
+			input.BearerToken,
+			sessionMode,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("Error creating SSE server: %v", err)
+		}
+		if input.Container != nil {
+			if err := server.SetContainerConfig(input.Container); err != nil {
+				return nil, fmt.Errorf("Error creating SSE server: %v", err)
 			}
 		}
+		return server, nil
+	}
+}
+
+func (s *Server) registerServerHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.RegisterServerInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			respondError(c, invalidRequest("invalid request body: %v", err))
+			return
+		}
+
+		server, err := newMcpServerFromInput(&input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
 
 		if err := s.mcpService.RegisterMcpServer(c, server); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
+		s.publishServerEvent(types.EventServerRegistered, server)
 
 		c.JSON(http.StatusCreated, server)
 	}
 }
 
+// maxBulkRegistrationSize is the largest number of servers that can be registered in a single bulk request.
+const maxBulkRegistrationSize = 1000
+
+// registerServersBulkHandler registers a batch of MCP servers in a single request.
+// In atomic mode, either all of them are registered or none are. In best_effort mode, every server is registered
+// independently of the others. The response reports the outcome of every server of the batch.
+func (s *Server) registerServersBulkHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		mode := types.BulkRegistrationMode(c.DefaultQuery("mode", string(types.BulkRegistrationAtomic)))
+		if mode != types.BulkRegistrationAtomic && mode != types.BulkRegistrationBestEffort {
+			invalidParam(c, "mode", fmt.Sprintf("must be %s or %s", types.BulkRegistrationAtomic, types.BulkRegistrationBestEffort))
+			return
+		}
+		deferValidation := false
+		if v, ok := c.GetQuery("defer_validation"); ok {
+			if v != "true" && v != "false" {
+				invalidParam(c, "defer_validation", "must be true or false")
+				return
+			}
+			if mode != types.BulkRegistrationAtomic {
+				invalidParam(c, "defer_validation", "is only supported in atomic mode")
+				return
+			}
+			deferValidation = v == "true"
+		}
+
+		var inputs []types.RegisterServerInput
+		if err := c.ShouldBindJSON(&inputs); err != nil {
+			respondError(c, invalidRequest("invalid request body: %v", err))
+			return
+		}
+		if len(inputs) == 0 || len(inputs) > maxBulkRegistrationSize {
+			respondError(c, validationFailed(
+				"a batch must contain between 1 and %d servers, got %d", maxBulkRegistrationSize, len(inputs),
+			))
+			return
+		}
+
+		result := &types.BulkRegistrationResult{Mode: mode, Results: make([]types.BulkItemResult, len(inputs))}
+		servers := make([]*model.McpServer, len(inputs))
+		for i := range inputs {
+			result.Results[i] = types.BulkItemResult{Index: i, Name: inputs[i].Name}
+			server, err := newMcpServerFromInput(&inputs[i])
+			if err != nil {
+				result.Results[i].Status, result.Results[i].Error = types.BulkItemFailed, err.Error()
+				errors.As(err, &result.Results[i].Errors)
+				continue
+			}
+			servers[i] = server
+		}
+
+		if mode == types.BulkRegistrationAtomic {
+			s.registerServersAtomically(c, servers, deferValidation, result)
+		} else {
+			s.registerServersIndependently(c, servers, result)
+		}
+
+		for i, r := range result.Results {
+			switch r.Status {
+			case types.BulkItemFailed:
+				result.Failed++
+			case types.BulkItemRegistered:
+				result.Registered++
+				server, err := toMcpServerType(servers[i])
+				if err != nil {
+					respondError(c, err)
+					return
+				}
+				result.Results[i].Server = server
+				s.webhookService.Publish(types.EventServerRegistered, server)
+			}
+		}
+
+		switch {
+		case result.Failed == 0:
+			c.JSON(http.StatusCreated, result)
+		case mode == types.BulkRegistrationAtomic:
+			// the error is included for clients that only look for it in error responses
+			err := toAPIErrorType(newAPIError(
+				http.StatusUnprocessableEntity, types.ErrorCodeBatchFailed,
+				"%d of %d servers could not be registered, none were registered", result.Failed, len(inputs),
+			))
+			var errField any = err
+			if c.GetBool(legacyErrorsKey) {
+				errField = err.Message
+			}
+			c.JSON(http.StatusUnprocessableEntity, struct {
+				Error any `json:"error"`
+				*types.BulkRegistrationResult
+			}{
+				Error:                  errField,
+				BulkRegistrationResult: result,
+			})
+		default:
+			c.JSON(http.StatusMultiStatus, result)
+		}
+	}
+}
+
+// registerServersAtomically registers all the valid servers of a batch in one go, if none of the batch is invalid.
+// Servers that are not registered are marked as skipped in the results.
+func (s *Server) registerServersAtomically(c *gin.Context, servers []*model.McpServer, deferValidation bool, result *types.BulkRegistrationResult) {
+	defer func() {
+		for i := range result.Results {
+			if result.Results[i].Status == "" {
+				result.Results[i].Status = types.BulkItemSkipped
+			}
+		}
+	}()
+	for _, r := range result.Results {
+		if r.Status == types.BulkItemFailed {
+			return
+		}
+	}
+
+	err := s.mcpService.RegisterMcpServers(c, servers, deferValidation)
+	var batchErr *mcp.BatchError
+	switch {
+	case errors.As(err, &batchErr):
+		for i, err := range batchErr.Errors {
+			if err != nil {
+				result.Results[i].Status, result.Results[i].Error = types.BulkItemFailed, err.Error()
+			}
+		}
+	case err != nil:
+		// the failure is not specific to a server, so it is reported for all of them
+		for i := range result.Results {
+			result.Results[i].Status, result.Results[i].Error = types.BulkItemFailed, err.Error()
+		}
+	default:
+		for i := range result.Results {
+			result.Results[i].Status = types.BulkItemRegistered
+		}
+	}
+}
+
+// registerServersIndependently registers the valid servers of a batch one by one.
+func (s *Server) registerServersIndependently(c *gin.Context, servers []*model.McpServer, result *types.BulkRegistrationResult) {
+	for i, server := range servers {
+		if server == nil {
+			continue
+		}
+		if err := s.mcpService.RegisterMcpServer(c, server); err != nil {
+			result.Results[i].Status, result.Results[i].Error = types.BulkItemFailed, err.Error()
+			continue
+		}
+		result.Results[i].Status = types.BulkItemRegistered
+	}
+}
+
+// updateServerHandler replaces the configuration of a registered MCP server.
+// The name of a server cannot be changed, so the name in the body must either be empty or match the one in the path.
+func (s *Server) updateServerHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name, ok := pathName(c, "name", s.mcpService.ResolveServerName)
+		if !ok {
+			return
+		}
+
+		var input types.RegisterServerInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			respondError(c, invalidRequest("invalid request body: %v", err))
+			return
+		}
+		if input.Name == "" {
+			input.Name = name
+		}
+		if input.Name != name {
+			respondError(c, validationFailed(
+				"the name of server %s cannot be changed to %s", name, input.Name,
+			).with("field", "name"))
+			return
+		}
+
+		version, ok := ifMatchVersion(c)
+		if !ok {
+			return
+		}
+
+		server, err := newMcpServerFromInput(&input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		server.Version = version
+
+		s.updateServer(c, server)
+	}
+}
+
+// patchServerHandler applies a JSON merge patch to the configuration of a registered MCP server.
+// The patched configuration is validated like a complete one, and the server is only reconnected to
+// if its connection settings changed.
+func (s *Server) patchServerHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name, ok := pathName(c, "name", s.mcpService.ResolveServerName)
+		if !ok {
+			return
+		}
+
+		version, ok := ifMatchVersion(c)
+		if !ok {
+			return
+		}
+		existing, err := s.mcpService.GetMcpServer(name)
+		if err != nil {
+			respondError(c, fmt.Errorf("failed to get MCP server %s: %w", name, err))
+			return
+		}
+		if version != 0 && version != existing.Version {
+			respondError(c, model.ErrVersionConflict)
+			return
+		}
+
+		current, err := toRegisterServerInput(existing)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		var input types.RegisterServerInput
+		if err := applyMergePatch(c, current, &input); err != nil {
+			respondError(c, invalidRequest("invalid request body: %v", err))
+			return
+		}
+		if input.Name != name {
+			respondError(c, validationFailed(
+				"the name of server %s cannot be changed to %s", name, input.Name,
+			).with("field", "name"))
+			return
+		}
+
+		server, err := newMcpServerFromInput(&input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		// the patch was applied to this version, it must not overwrite a concurrent update
+		server.Version = existing.Version
+
+		s.updateServer(c, server)
+	}
+}
+
+// updateServer stores the new configuration of a server and responds with the updated server.
+func (s *Server) updateServer(c *gin.Context, server *model.McpServer) {
+	// the tools are compared before and after the update to tell webhooks whether they changed
+	toolsBefore, toolsErr := s.serverToolNames(server.Name)
+	if err := s.mcpService.UpdateMcpServer(c, server); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	updated, err := toMcpServerType(server)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	s.webhookService.Publish(types.EventServerUpdated, updated)
+	if toolsAfter, err := s.serverToolNames(server.Name); err == nil && toolsErr == nil {
+		added, removed := util.DiffTools(toolsBefore, toolsAfter)
+		if len(added) > 0 || len(removed) > 0 {
+			s.webhookService.Publish(
+				types.EventToolSyncChanged,
+				types.ToolSyncChange{Server: server.Name, Added: added, Removed: removed},
+			)
+		}
+	}
+
+	setVersionETag(c, server.Version)
+	c.JSON(http.StatusOK, updated)
+}
+
+// serverToolNames returns the canonical names of the tools of an MCP server.
+func (s *Server) serverToolNames(name string) ([]string, error) {
+	tools, err := s.mcpService.ListToolsByServer(name)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(tools))
+	for i, t := range tools {
+		names[i] = t.Name
+	}
+	return names, nil
+}
+
+// publishServerEvent delivers an event about an MCP server to the webhooks subscribed to it.
+func (s *Server) publishServerEvent(eventType string, server *model.McpServer) {
+	if s.webhookService == nil {
+		return
+	}
+	data, err := toMcpServerType(server)
+	if err != nil {
+		log.Printf("[WARN] failed to describe MCP server %s in the %s event: %v", server.Name, eventType, err)
+		return
+	}
+	s.webhookService.Publish(eventType, data)
+}
+
+// getServerHandler returns a registered MCP server, with its version as ETag.
+func (s *Server) getServerHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name, ok := pathName(c, "name", s.mcpService.ResolveServerName)
+		if !ok {
+			return
+		}
+
+		record, err := s.mcpService.GetMcpServer(name)
+		if err != nil {
+			respondError(c, fmt.Errorf("failed to get MCP server %s: %w", name, err))
+			return
+		}
+		if notModified(c, versionETag(record.Version)) {
+			return
+		}
+		server, err := toMcpServerType(record)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, server)
+	}
+}
+
 func (s *Server) deregisterServerHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		name := c.Param("name")
+		name, ok := pathName(c, "name", s.mcpService.ResolveServerName)
+		if !ok {
+			return
+		}
+
+		record, err := s.mcpService.GetMcpServer(name)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if record.Transport == types.TransportWebhookTool {
+			// the tool of a webhook tool is gone for good, the groups using it no longer reference it
+			if err := s.removeGroupReferences(name); err != nil {
+				respondError(c, err)
+				return
+			}
+		}
 
 		if err := s.mcpService.DeregisterMcpServer(name); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
+		s.webhookService.Publish(types.EventServerDeregistered, map[string]string{"name": name})
 
 		c.Status(http.StatusNoContent)
 	}
 }
 
+// removeGroupReferences removes a server and its tools from the configuration of the tool groups naming them.
+func (s *Server) removeGroupReferences(serverName string) error {
+	tools, err := s.mcpService.ListToolsByServer(serverName)
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(tools))
+	for i, t := range tools {
+		names[i] = t.Name
+	}
+	// the groups updated before a failure are reported too
+	updates, removeErr := s.toolGroupService.RemoveServerReferences(serverName, names)
+	for _, u := range updates {
+		resp := &types.UpdateToolGroupResponse{Name: u.New.Name}
+		if resp.Old, err = toToolGroupType(u.Old); err != nil {
+			return err
+		}
+		if resp.New, err = toToolGroupType(u.New); err != nil {
+			return err
+		}
+		s.webhookService.Publish(types.EventGroupUpdated, resp)
+	}
+	return removeErr
+}
+
 func (s *Server) listServersHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		records, err := s.mcpService.ListMcpServers()
+		page, ok := parsePage(c)
+		if !ok {
+			return
+		}
+		records, next, err := s.mcpService.ListMcpServersPage(page)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 
 		servers := make([]*types.McpServer, len(records))
-
-		for i, record := range records {
-			servers[i] = &types.McpServer{
-				Name:        record.Name,
-				Transport:   string(record.Transport),
-				Description: record.Description,
-				SessionMode: string(record.SessionMode),
-			}
-
-			switch record.Transport {
-			case types.TransportStreamableHTTP:
-				conf, err := record.GetStreamableHTTPConfig()
-				if err != nil {
-					c.JSON(
-						http.StatusInternalServerError,
-						gin.H{
-							"error": fmt.Sprintf("Error getting streamable HTTP config for server %s: %v", record.Name, err),
-						},
-					)
-					return
-				}
-				servers[i].URL = conf.URL
-			case types.TransportStdio:
-				conf, err := record.GetStdioConfig()
-				if err != nil {
-					c.JSON(
-						http.StatusInternalServerError,
-						gin.H{
-							"error": fmt.Sprintf("Error getting stdio config for server %s: %v", record.Name, err),
-						},
-					)
-					return
-				}
-				servers[i].Command = conf.Command
-				servers[i].Args = conf.Args
-				servers[i].Env = conf.Env
-			default:
-				// transport is SSE
-				conf, err := record.GetSSEConfig()
-				if err != nil {
-					c.JSON(
-						http.StatusInternalServerError,
-						gin.H{
-							"error": fmt.Sprintf("Error getting SSE config for server %s: %v", record.Name, err),
-						},
-					)
-					return
-				}
-				servers[i].URL = conf.URL
+		for i := range records {
+			servers[i], err = toMcpServerType(&records[i])
+			if err != nil {
+				respondError(c, err)
+				return
 			}
+			servers[i].State = s.mcpService.ServerState(&records[i])
+			servers[i].ContainerState = s.mcpService.ContainerState(&records[i])
 		}
 
-		c.JSON(http.StatusOK, servers)
+		c.JSON(http.StatusOK, newPage(servers, next))
 	}
 }
 
 func (s *Server) enableServerHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		name := c.Param("name")
+		name, ok := pathName(c, "name", s.mcpService.ResolveServerName)
+		if !ok {
+			return
+		}
 
 		tools, prompts, err := s.mcpService.EnableMcpServer(name)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 
@@ -189,11 +582,14 @@ func (s *Server) enableServerHandler() gin.HandlerFunc {
 
 func (s *Server) disableServerHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		name := c.Param("name")
+		name, ok := pathName(c, "name", s.mcpService.ResolveServerName)
+		if !ok {
+			return
+		}
 
 		tools, prompts, err := s.mcpService.DisableMcpServer(name)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 
@@ -214,65 +610,172 @@ func (s *Server) getServerConfigsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		records, err := s.mcpService.ListMcpServers()
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 
 		servers := make([]*types.RegisterServerInput, len(records))
-
-		for i, record := range records {
-			servers[i] = &types.RegisterServerInput{
-				Name:        record.Name,
-				Transport:   string(record.Transport),
-				Description: record.Description,
-				SessionMode: string(record.SessionMode),
-			}
-
-			switch record.Transport {
-			case types.TransportStreamableHTTP:
-				conf, err := record.GetStreamableHTTPConfig()
-				if err != nil {
-					c.JSON(
-						http.StatusInternalServerError,
-						gin.H{
-							"error": fmt.Sprintf("Error getting streamable HTTP config for server %s: %v", record.Name, err),
-						},
-					)
-					return
-				}
-				servers[i].URL = conf.URL
-				servers[i].BearerToken = conf.BearerToken
-			case types.TransportStdio:
-				conf, err := record.GetStdioConfig()
-				if err != nil {
-					c.JSON(
-						http.StatusInternalServerError,
-						gin.H{
-							"error": fmt.Sprintf("Error getting stdio config for server %s: %v", record.Name, err),
-						},
-					)
-					return
-				}
-				servers[i].Command = conf.Command
-				servers[i].Args = conf.Args
-				servers[i].Env = conf.Env
-			default:
-				// transport is SSE
-				conf, err := record.GetSSEConfig()
-				if err != nil {
-					c.JSON(
-						http.StatusInternalServerError,
-						gin.H{
-							"error": fmt.Sprintf("Error getting SSE config for server %s: %v", record.Name, err),
-						},
-					)
-					return
-				}
-				servers[i].URL = conf.URL
-				servers[i].BearerToken = conf.BearerToken
+		for i := range records {
+			servers[i], err = toRegisterServerInput(&records[i])
+			if err != nil {
+				respondError(c, err)
+				return
 			}
 		}
 
 		c.JSON(http.StatusOK, servers)
 	}
 }
+
+// getServerConfigHandler returns the complete configuration of a registered MCP server, like getServerConfigsHandler.
+func (s *Server) getServerConfigHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name, ok := pathName(c, "name", s.mcpService.ResolveServerName)
+		if !ok {
+			return
+		}
+
+		record, err := s.mcpService.GetMcpServer(name)
+		if err != nil {
+			respondError(c, fmt.Errorf("failed to get MCP server %s: %w", name, err))
+			return
+		}
+		server, err := toRegisterServerInput(record)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, server)
+	}
+}
+
+// serverStatesVersion returns the version of the lifecycle states of the MCP servers, which the server list includes.
+func (s *Server) serverStatesVersion() uint64 {
+	return s.mcpService.ServerStatesVersion()
+}
+
+// toRegisterServerInput converts a server record into the complete configuration it was registered with,
+// including secrets.
+func toRegisterServerInput(record *model.McpServer) (*types.RegisterServerInput, error) {
+	server := &types.RegisterServerInput{
+		Name:        record.Name,
+		Transport:   string(record.Transport),
+		Description: record.Description,
+		SessionMode: string(record.SessionMode),
+		LazyStart:   record.LazyStart,
+	}
+
+	switch record.Transport {
+	case types.TransportStreamableHTTP:
+		conf, err := record.GetStreamableHTTPConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting streamable HTTP config for server %s: %v", record.Name, err)
+		}
+		server.URL = conf.URL
+		server.BearerToken = conf.BearerToken
+		server.HTTPPool = conf.Pool
+		server.KeepAlive = conf.KeepAlive
+		server.Container = conf.Container
+	case types.TransportStdio:
+		conf, err := record.GetStdioConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting stdio config for server %s: %v", record.Name, err)
+		}
+		server.Command = conf.Command
+		server.Args = conf.Args
+		server.Env = conf.Env
+		server.Container = conf.Container
+		// the package is kept rather than its command line, the version it resolved to is recorded by the server
+		server.Package = conf.Package
+	case types.TransportOpenAPI:
+		conf, err := record.GetOpenAPIConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting openapi config for server %s: %v", record.Name, err)
+		}
+		server.OpenAPI = conf
+	case types.TransportWebhookTool:
+		conf, err := record.GetWebhookToolConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting webhook_tool config for server %s: %v", record.Name, err)
+		}
+		server.WebhookTool = conf
+	default:
+		// transport is SSE
+		conf, err := record.GetSSEConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting SSE config for server %s: %v", record.Name, err)
+		}
+		server.URL = conf.URL
+		server.BearerToken = conf.BearerToken
+		server.Container = conf.Container
+	}
+	return server, nil
+}
+
+// toMcpServerType converts a server record into the representation sent to clients, which contains no secrets.
+func toMcpServerType(record *model.McpServer) (*types.McpServer, error) {
+	server := &types.McpServer{
+		UUID:        record.UUID,
+		Name:        record.Name,
+		Transport:   string(record.Transport),
+		Description: record.Description,
+		SessionMode: string(record.SessionMode),
+		LazyStart:   record.LazyStart,
+		Version:     record.Version,
+	}
+
+	switch record.Transport {
+	case types.TransportStreamableHTTP:
+		conf, err := record.GetStreamableHTTPConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting streamable HTTP config for server %s: %v", record.Name, err)
+		}
+		server.URL = conf.URL
+		server.HTTPPool = conf.Pool
+		server.KeepAlive = conf.KeepAlive
+		server.CredentialSource = types.CredentialSourceOf(conf.BearerToken)
+	case types.TransportStdio:
+		conf, err := record.GetStdioConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting stdio config for server %s: %v", record.Name, err)
+		}
+		server.Command, server.Args = conf.CommandLine()
+		server.Env = conf.Env
+		server.Container = conf.Container
+		server.Package = conf.Package
+		server.CredentialSource = types.CredentialSourceOf(slices.Collect(maps.Values(conf.Env))...)
+	case types.TransportOpenAPI:
+		conf, err := record.GetOpenAPIConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting openapi config for server %s: %v", record.Name, err)
+		}
+		server.CredentialSource = types.CredentialSourceNone
+		if conf.Auth != nil {
+			server.CredentialSource = types.CredentialSourceOf(conf.Auth.Value)
+			// the credential of the REST service is a secret
+			conf.Auth = &types.OpenAPIAuth{Type: conf.Auth.Type, Header: conf.Auth.Header}
+		}
+		server.OpenAPI = conf
+	case types.TransportWebhookTool:
+		conf, err := record.GetWebhookToolConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting webhook_tool config for server %s: %v", record.Name, err)
+		}
+		server.CredentialSource = types.CredentialSourceNone
+		if conf.Auth != nil {
+			server.CredentialSource = types.CredentialSourceOf(conf.Auth.Value)
+			// the credential of the endpoint is a secret
+			conf.Auth = &types.OpenAPIAuth{Type: conf.Auth.Type, Header: conf.Auth.Header}
+		}
+		server.WebhookTool = conf
+	default:
+		// transport is SSE
+		conf, err := record.GetSSEConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting SSE config for server %s: %v", record.Name, err)
+		}
+		server.URL = conf.URL
+		server.CredentialSource = types.CredentialSourceOf(conf.BearerToken)
+	}
+	return server, nil
+}
@@ -1,10 +1,17 @@
 package api
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
 	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
@@ -247,3 +254,240 @@ func TestSessionModeValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestNewMcpServerFromInput(t *testing.T) {
+	server, err := newMcpServerFromInput(&types.RegisterServerInput{
+		Name:      "github",
+		Transport: "streamable_http",
+		URL:       "https://api.githubcopilot.com/mcp/",
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, types.TransportStreamableHTTP, server.Transport)
+	testhelpers.AssertEqual(t, types.SessionModeStateless, server.SessionMode)
+
+	pool := &types.HTTPPoolConfig{MaxIdleConnsPerHost: 100, DisableHTTP2: true}
+	server, err = newMcpServerFromInput(&types.RegisterServerInput{
+		Name:      "github",
+		Transport: "streamable_http",
+		URL:       "https://api.githubcopilot.com/mcp/",
+		HTTPPool:  pool,
+	})
+	testhelpers.AssertNoError(t, err)
+	input, err := toRegisterServerInput(server)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, *pool, *input.HTTPPool)
+
+	keepAlive := &types.KeepAliveConfig{IntervalSec: 30, MaxIdleSec: 600}
+	server, err = newMcpServerFromInput(&types.RegisterServerInput{
+		Name:        "github",
+		Transport:   "streamable_http",
+		URL:         "https://api.githubcopilot.com/mcp/",
+		SessionMode: "stateful",
+		KeepAlive:   keepAlive,
+	})
+	testhelpers.AssertNoError(t, err)
+	input, err = toRegisterServerInput(server)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, *keepAlive, *input.KeepAlive)
+
+	server, err = newMcpServerFromInput(&types.RegisterServerInput{
+		Name:      "slack",
+		Transport: "sse",
+		URL:       "http://localhost:9000/sse",
+		Container: &types.ContainerConfig{Image: "slack-mcp:latest", Env: map[string]string{"SLACK_TOKEN": "xoxb"}, Port: 9000},
+	})
+	testhelpers.AssertNoError(t, err)
+	input, err = toRegisterServerInput(server)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "slack-mcp:latest", input.Container.Image)
+	testhelpers.AssertEqual(t, "xoxb", input.Container.Env["SLACK_TOKEN"])
+	testhelpers.AssertEqual(t, 9000, input.Container.Port)
+
+	pkg := &types.PackageConfig{Runtime: types.PackageRuntimeUvx, Name: "mcp-server-time", Version: "2025.9.25"}
+	server, err = newMcpServerFromInput(&types.RegisterServerInput{
+		Name:      "time",
+		Transport: "stdio",
+		Args:      []string{"--local-timezone=UTC"},
+		Package:   pkg,
+	})
+	testhelpers.AssertNoError(t, err)
+	input, err = toRegisterServerInput(server)
+	testhelpers.AssertNoError(t, err)
+	// the package is exported rather than the command line it expands into
+	testhelpers.AssertEqual(t, *pkg, *input.Package)
+	testhelpers.AssertEqual(t, "", input.Command)
+	testhelpers.AssertEqual(t, "--local-timezone=UTC", strings.Join(input.Args, " "))
+	view, err := toMcpServerType(server)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "uvx", view.Command)
+	testhelpers.AssertEqual(t, "mcp-server-time==2025.9.25 --local-timezone=UTC", strings.Join(view.Args, " "))
+
+	_, err = newMcpServerFromInput(&types.RegisterServerInput{Name: "filesystem", Transport: "stdio"})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "command is required for stdio transport")
+
+	_, err = newMcpServerFromInput(&types.RegisterServerInput{Name: "github", Transport: "grpc"})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertStringContains(t, err.Error(), "unsupported transport type")
+}
+
+func TestUpdateServerHandlerRejectsRename(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &Server{}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "name", Value: "github"}}
+	c.Request = httptest.NewRequest(
+		http.MethodPut,
+		"/api/v0/servers/github",
+		strings.NewReader(`{"name": "gitlab", "transport": "streamable_http", "url": "https://gitlab.com/mcp"}`),
+	)
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	s.updateServerHandler()(c)
+
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+	testhelpers.AssertStringContains(t, w.Body.String(), "cannot be changed")
+}
+
+func TestRegisterServerHandlerReportsEveryInvalidField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &Server{}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(
+		http.MethodPost,
+		"/api/v1/servers",
+		strings.NewReader(`{"name": "git__hub", "transport": "streamable_http", "url": "github.com/mcp", "session_mode": "sticky"}`),
+	)
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	s.registerServerHandler()(c)
+
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+	var resp struct {
+		Error struct {
+			Code    types.ErrorCode `json:"code"`
+			Details struct {
+				Field  string                 `json:"field"`
+				Errors types.ValidationErrors `json:"errors"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	testhelpers.AssertEqual(t, types.ErrorCodeValidationFailed, resp.Error.Code)
+	testhelpers.AssertEqual(t, "name", resp.Error.Details.Field)
+	testhelpers.AssertEqual(t, 3, len(resp.Error.Details.Errors))
+	testhelpers.AssertEqual(t, "session_mode", resp.Error.Details.Errors[1].Field)
+	testhelpers.AssertEqual(t, "url", resp.Error.Details.Errors[2].Field)
+	testhelpers.AssertStringContains(t, resp.Error.Details.Errors[2].Message, "must be an http or https URL")
+}
+
+// newBulkTestRouter serves the bulk registration API of an empty registry.
+func newBulkTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(&model.McpServer{}, &model.Tool{}, &model.Prompt{}))
+	mcpService, err := mcp.NewMCPService(&mcp.ServiceConfig{
+		DB:                      db,
+		McpProxyServer:          &server.MCPServer{},
+		SseMcpProxyServer:       &server.MCPServer{},
+		Metrics:                 telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout: 1,
+	})
+	testhelpers.AssertNoError(t, err)
+
+	s := &Server{mcpService: mcpService}
+	router := gin.New()
+	router.POST("/servers/bulk", s.registerServersBulkHandler())
+	return router
+}
+
+func postBulk(t *testing.T, router *gin.Engine, query, body string) (int, types.BulkRegistrationResult, map[string]any) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/servers/bulk?"+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	var result types.BulkRegistrationResult
+	var raw map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &result)
+	_ = json.Unmarshal(w.Body.Bytes(), &raw)
+	return w.Code, result, raw
+}
+
+func TestRegisterServersBulkHandler(t *testing.T) {
+	// upstreams that cannot be connected to
+	const batch = `[
+		{"name": "github", "transport": "streamable_http", "url": "http://127.0.0.1:1/mcp"},
+		{"name": "slack", "transport": "streamable_http", "url": "http://127.0.0.1:1/mcp"}
+	]`
+
+	t.Run("atomic batch fails as a whole", func(t *testing.T) {
+		router := newBulkTestRouter(t)
+		status, result, raw := postBulk(t, router, "", `[
+			{"name": "github", "transport": "streamable_http", "url": "http://127.0.0.1:1/mcp"},
+			{"name": "slack", "transport": "carrier_pigeon"}
+		]`)
+		testhelpers.AssertEqual(t, http.StatusUnprocessableEntity, status)
+		apiErr := raw["error"].(map[string]any)
+		testhelpers.AssertEqual(t, string(types.ErrorCodeBatchFailed), apiErr["code"])
+		testhelpers.AssertStringContains(t, apiErr["message"].(string), "1 of 2 servers could not be registered")
+		testhelpers.AssertEqual(t, types.BulkRegistrationAtomic, result.Mode)
+		testhelpers.AssertEqual(t, types.BulkItemSkipped, result.Results[0].Status)
+		testhelpers.AssertEqual(t, types.BulkItemFailed, result.Results[1].Status)
+		testhelpers.AssertEqual(t, 1, result.Failed)
+		testhelpers.AssertEqual(t, 1, len(result.Results[1].Errors))
+		testhelpers.AssertEqual(t, "transport", result.Results[1].Errors[0].Field)
+	})
+
+	t.Run("atomic batch with deferred validation", func(t *testing.T) {
+		router := newBulkTestRouter(t)
+		status, result, _ := postBulk(t, router, "mode=atomic&defer_validation=true", batch)
+		testhelpers.AssertEqual(t, http.StatusCreated, status)
+		testhelpers.AssertEqual(t, 2, result.Registered)
+		testhelpers.AssertEqual(t, "slack", result.Results[1].Server.Name)
+		testhelpers.AssertEqual(t, "http://127.0.0.1:1/mcp", result.Results[1].Server.URL)
+
+		// the servers now exist, so registering them again fails
+		status, result, _ = postBulk(t, router, "mode=atomic&defer_validation=true", batch)
+		testhelpers.AssertEqual(t, http.StatusUnprocessableEntity, status)
+		testhelpers.AssertEqual(t, types.BulkItemFailed, result.Results[0].Status)
+		testhelpers.AssertEqual(t, types.BulkItemSkipped, result.Results[1].Status)
+	})
+
+	t.Run("best effort batch reports every server", func(t *testing.T) {
+		router := newBulkTestRouter(t)
+		status, result, _ := postBulk(t, router, "mode=best_effort", batch)
+		testhelpers.AssertEqual(t, http.StatusMultiStatus, status)
+		testhelpers.AssertEqual(t, 2, result.Failed)
+		for _, r := range result.Results {
+			testhelpers.AssertEqual(t, types.BulkItemFailed, r.Status)
+			testhelpers.AssertTrue(t, r.Error != "", "failed servers must have an error")
+		}
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		router := newBulkTestRouter(t)
+		for _, tt := range []struct{ query, body, param string }{
+			{query: "mode=yolo", body: batch, param: "mode"},
+			{query: "defer_validation=maybe", body: batch, param: "defer_validation"},
+			{query: "mode=best_effort&defer_validation=true", body: batch, param: "defer_validation"},
+			{query: "", body: `[]`},
+			{query: "", body: `{"name": "github"}`},
+		} {
+			status, _, raw := postBulk(t, router, tt.query, tt.body)
+			testhelpers.AssertEqual(t, http.StatusBadRequest, status)
+			if tt.param != "" {
+				apiErr := raw["error"].(map[string]any)
+				testhelpers.AssertEqual(t, string(types.ErrorCodeValidationFailed), apiErr["code"])
+				testhelpers.AssertEqual(t, tt.param, apiErr["details"].(map[string]any)["parameter"])
+			}
+		}
+	})
+}
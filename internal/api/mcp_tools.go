@@ -1,56 +1,160 @@
 package api
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
-// listToolsHandler returns a list of all tools, or all tools for a given mcp server if "server" query param is provided
+// maxToolSearchLength is the longest search string accepted by the tools list API.
+const maxToolSearchLength = 256
+
+// toolStreamBufferSize is the size of the buffer tools are encoded into before being written to the connection.
+const toolStreamBufferSize = 64 << 10
+
+// eventStreamContentType is the media type of server-sent events.
+const eventStreamContentType = "text/event-stream"
+
+// toolCallKeepAliveInterval is how often a comment is sent on the event stream of a tool call while the tool runs.
+const toolCallKeepAliveInterval = 15 * time.Second
+
+// listToolsHandler returns a page of the tools matching the filters given as query params:
+// the tools of a server, of a tool group, enabled or disabled ones, and the ones matching a search string.
 func (s *Server) listToolsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		server := c.Query("server")
-		var (
-			tools []model.Tool
-			err   error
-		)
-		if server == "" {
-			// no server specified, list all tools
-			tools, err = s.mcpService.ListTools()
-		} else {
-			// server specified, list tools for that server
-			tools, err = s.mcpService.ListToolsByServer(server)
+		page, ok := parsePage(c)
+		if !ok {
+			return
+		}
+		filter, ok := s.parseToolFilter(c)
+		if !ok {
+			return
+		}
+
+		tools, next, total, err := s.mcpService.ListToolsPage(filter, page)
+		var filterErr *mcp.InvalidFilterError
+		if errors.As(err, &filterErr) {
+			invalidParam(c, filterErr.Field, filterErr.Err.Error())
+			return
 		}
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
-		c.JSON(http.StatusOK, tools)
+		resp := newPage(tools, next)
+		resp.Total = &total
+		c.JSON(http.StatusOK, resp)
 	}
 }
 
+// streamToolsHandler writes every tool matching the filters of the tools list as newline-delimited JSON,
+// one tool per line, as they are read from the database. Dumps of the whole registry are never held in memory.
+func (s *Server) streamToolsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, ok := s.parseToolFilter(c)
+		if !ok {
+			return
+		}
+
+		c.Header("Content-Type", types.NDJSONContentType)
+		w := bufio.NewWriterSize(c.Writer, toolStreamBufferSize)
+		enc := json.NewEncoder(w)
+		err := s.mcpService.StreamTools(c.Request.Context(), filter, func(t *model.Tool) error {
+			return enc.Encode(t)
+		})
+		if err != nil && !c.Writer.Written() {
+			// nothing was sent yet, the buffered tools are dropped in favor of a regular error response
+			c.Header("Content-Type", "")
+			var filterErr *mcp.InvalidFilterError
+			if errors.As(err, &filterErr) {
+				invalidParam(c, filterErr.Field, filterErr.Err.Error())
+				return
+			}
+			respondError(c, err)
+			return
+		}
+		if err != nil {
+			// the status was sent already, the client learns that the stream is incomplete from its last line
+			_ = enc.Encode(types.ErrorResponse{Error: toAPIErrorType(err)})
+		}
+		_ = w.Flush()
+	}
+}
+
+// parseToolFilter reads the filters of the tools list API from the query params.
+// If one is invalid, an error response is sent and false is returned.
+func (s *Server) parseToolFilter(c *gin.Context) (mcp.ToolFilter, bool) {
+	filter := mcp.ToolFilter{Server: c.Query("server"), Query: c.Query("q")}
+
+	if len(filter.Query) > maxToolSearchLength {
+		invalidParam(c, "q", fmt.Sprintf("must not be longer than %d characters", maxToolSearchLength))
+		return filter, false
+	}
+
+	if v, ok := c.GetQuery("enabled"); ok {
+		switch v {
+		case "true", "false":
+			enabled := v == "true"
+			filter.Enabled = &enabled
+		default:
+			invalidParam(c, "enabled", "must be true or false")
+			return filter, false
+		}
+	}
+
+	if name := c.Query("group"); name != "" {
+		group, err := s.toolGroupService.GetToolGroup(name)
+		if errors.Is(err, toolgroup.ErrToolGroupNotFound) {
+			invalidParam(c, "group", fmt.Sprintf("tool group %s does not exist", name))
+			return filter, false
+		}
+		if err != nil {
+			respondError(c, err)
+			return filter, false
+		}
+		names, err := s.toolGroupService.ResolveGroupTools(group)
+		if err != nil {
+			respondError(c, fmt.Errorf("failed to resolve the tools of group %s: %w", name, err))
+			return filter, false
+		}
+		filter.Names = names
+		if filter.Names == nil {
+			filter.Names = []string{}
+		}
+	}
+	return filter, true
+}
+
 // invokeToolHandler forwards the JSON body to the tool URL and streams response back.
 func (s *Server) invokeToolHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var args map[string]any
 		if err := json.NewDecoder(c.Request.Body).Decode(&args); err != nil {
-			c.JSON(
-				http.StatusBadRequest,
-				gin.H{"error": "failed to decode request body: " + err.Error()},
-			)
+			respondError(c, invalidRequest("failed to decode request body: %v", err))
 			return
 		}
 
 		rawName, ok := args["name"]
 		if !ok {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'name' field in request body"})
+			respondError(c, validationFailed("missing 'name' field in request body").with("field", "name"))
 			return
 		}
 		name, ok := rawName.(string)
 		if !ok {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "'name' field must be a string"})
+			respondError(c, validationFailed("'name' field must be a string").with("field", "name"))
 			return
 		}
 
@@ -59,7 +163,7 @@ func (s *Server) invokeToolHandler() gin.HandlerFunc {
 
 		resp, err := s.mcpService.InvokeTool(c, name, args)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to invoke tool: " + err.Error()})
+			respondError(c, fmt.Errorf("failed to invoke tool: %w", err))
 			return
 		}
 
@@ -67,6 +171,114 @@ func (s *Server) invokeToolHandler() gin.HandlerFunc {
 	}
 }
 
+// callToolHandler calls a tool with the arguments in the request body, through the tool call handler of the MCP proxy,
+// so that the call goes through the same access checks and metrics as the calls of MCP clients connected to /mcp.
+// Clients that accept text/event-stream get the result as server-sent events, see streamToolCall.
+func (s *Server) callToolHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			respondError(c, invalidRequest("failed to read request body: %v", err))
+			return
+		}
+		var args map[string]any
+		if len(bytes.TrimSpace(body)) > 0 {
+			if err := json.Unmarshal(body, &args); err != nil {
+				respondError(c, invalidRequest("the request body must be a JSON object holding the arguments of the tool: %v", err))
+				return
+			}
+		}
+
+		// the MCP proxy only routes calls to the tools it serves, ie, the enabled ones
+		if _, ok := s.mcpService.GetToolInstance(name); !ok {
+			respondError(c, newAPIError(http.StatusNotFound, types.ErrorCodeNotFound, "tool %s does not exist or is disabled", name))
+			return
+		}
+
+		req := mcpgo.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = args
+
+		if strings.Contains(c.GetHeader("Accept"), eventStreamContentType) {
+			s.streamToolCall(c, req)
+			return
+		}
+		res, err := s.mcpService.MCPProxyToolCallHandler(c.Request.Context(), req)
+		if err != nil {
+			respondError(c, fmt.Errorf("failed to call tool: %w", err))
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+// streamToolCall calls a tool and sends its result as server-sent events.
+// While the tool runs, a comment is sent every toolCallKeepAliveInterval so that proxies don't close the connection.
+// The result is sent as a content event for every content block, followed by a result event with the rest of it.
+// If the call fails once the stream started, it ends with an error event holding a types.ErrorResponse.
+// Failures before the first event are reported like those of a regular request.
+func (s *Server) streamToolCall(c *gin.Context, req mcpgo.CallToolRequest) {
+	type outcome struct {
+		res *mcpgo.CallToolResult
+		err error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		res, err := s.mcpService.MCPProxyToolCallHandler(c.Request.Context(), req)
+		done <- outcome{res, err}
+	}()
+
+	ticker := time.NewTicker(toolCallKeepAliveInterval)
+	defer ticker.Stop()
+	started := false
+	start := func() {
+		if !started {
+			started = true
+			c.Header("Content-Type", eventStreamContentType)
+			c.Header("Cache-Control", "no-cache")
+			c.Status(http.StatusOK)
+		}
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			start()
+			_, _ = io.WriteString(c.Writer, ": the tool is running\n\n")
+			c.Writer.Flush()
+
+		case o := <-done:
+			if o.err != nil {
+				err := fmt.Errorf("failed to call tool: %w", o.err)
+				if !started {
+					respondError(c, err)
+					return
+				}
+				c.SSEvent("error", types.ErrorResponse{Error: toAPIErrorType(err)})
+				c.Writer.Flush()
+				return
+			}
+
+			start()
+			for _, content := range o.res.Content {
+				c.SSEvent("content", content)
+			}
+			c.SSEvent("result", toolCallOutcome{Meta: o.res.Meta, IsError: o.res.IsError, StructuredContent: o.res.StructuredContent})
+			c.Writer.Flush()
+			return
+		}
+	}
+}
+
+// toolCallOutcome is the data of the result event of a streamed tool call: the result without its content.
+type toolCallOutcome struct {
+	Meta              *mcpgo.Meta `json:"_meta,omitempty"`
+	IsError           bool        `json:"isError"`
+	StructuredContent any         `json:"structuredContent,omitempty"`
+}
+
 // getToolHandler returns the tool with the given name.
 func (s *Server) getToolHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -74,13 +286,19 @@ func (s *Server) getToolHandler() gin.HandlerFunc {
 		// cannot be supplied as a path param.
 		name := c.Query("name")
 		if name == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'name' query parameter"})
+			invalidParam(c, "name", "is required")
 			return
 		}
 
+		// the tool can also be identified by its UUID
+		name, err := s.mcpService.ResolveToolName(name)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
 		tool, err := s.mcpService.GetTool(name)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get tool: " + err.Error()})
+			respondError(c, fmt.Errorf("failed to get tool: %w", err))
 			return
 		}
 
@@ -93,12 +311,12 @@ func (s *Server) enableToolsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		entity := c.Query("entity")
 		if entity == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'entity' query parameter"})
+			invalidParam(c, "entity", "is required")
 			return
 		}
 		enabledTools, err := s.mcpService.EnableTools(entity)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to enable tool(s): " + err.Error()})
+			respondError(c, fmt.Errorf("failed to enable tool(s): %w", err))
 			return
 		}
 		c.JSON(http.StatusOK, enabledTools)
@@ -110,12 +328,12 @@ func (s *Server) disableToolsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		entity := c.Query("entity")
 		if entity == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'entity' query parameter"})
+			invalidParam(c, "entity", "is required")
 			return
 		}
 		disabledTools, err := s.mcpService.DisableTools(entity)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to disable tool(s): " + err.Error()})
+			respondError(c, fmt.Errorf("failed to disable tool(s): %w", err))
 			return
 		}
 		c.JSON(http.StatusOK, disabledTools)
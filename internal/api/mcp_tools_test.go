@@ -0,0 +1,264 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcpclient"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// newToolsTestRouter serves the tools list of a registry with a git server providing 3 tools,
+// one of them disabled, and a tool group including 2 of them.
+func newToolsTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(&model.McpServer{}, &model.Tool{}, &model.Prompt{}, &model.ToolGroup{}))
+
+	mcpService, err := mcp.NewMCPService(&mcp.ServiceConfig{
+		DB:                      db,
+		McpProxyServer:          &server.MCPServer{},
+		SseMcpProxyServer:       &server.MCPServer{},
+		Metrics:                 telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout: 10,
+	})
+	testhelpers.AssertNoError(t, err)
+	git := &model.McpServer{Name: "git", Transport: types.TransportStdio, Config: []byte("{}")}
+	testhelpers.AssertNoError(t, db.Create(git).Error)
+	for _, name := range []string{"commit", "push", "status"} {
+		tool := &model.Tool{ServerID: git.ID, Name: name, Description: "git " + name}
+		testhelpers.AssertNoError(t, db.Create(tool).Error)
+		if name == "push" {
+			testhelpers.AssertNoError(t, db.Model(tool).Update("enabled", false).Error)
+		}
+	}
+	group := &model.ToolGroup{Name: "review", IncludedTools: []byte(`["git__commit", "git__push"]`)}
+	testhelpers.AssertNoError(t, db.Create(group).Error)
+	toolGroupService, err := toolgroup.NewToolGroupService(db, mcpService)
+	testhelpers.AssertNoError(t, err)
+
+	s := &Server{mcpService: mcpService, toolGroupService: toolGroupService}
+	router := gin.New()
+	router.GET("/tools", s.listToolsHandler())
+	router.GET("/tools/stream", s.streamToolsHandler())
+	return router
+}
+
+func TestListToolsFilters(t *testing.T) {
+	router := newToolsTestRouter(t)
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{query: "", expected: "git__commit,git__push,git__status"},
+		{query: "server=git", expected: "git__commit,git__push,git__status"},
+		{query: "enabled=false", expected: "git__push"},
+		{query: "group=review", expected: "git__commit,git__push"},
+		{query: "group=review&enabled=true", expected: "git__commit"},
+		{query: "q=STAT", expected: "git__status"},
+		{query: "q=nothing", expected: ""},
+		{query: "q=git&limit=1", expected: "git__commit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/tools?"+tt.query, nil)
+			router.ServeHTTP(w, req)
+			testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+
+			var page types.Page[model.Tool]
+			testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+			var names []string
+			for _, tool := range page.Items {
+				names = append(names, tool.Name)
+			}
+			testhelpers.AssertEqual(t, tt.expected, strings.Join(names, ","))
+			testhelpers.AssertNotNil(t, page.Total)
+			if tt.query == "q=git&limit=1" {
+				testhelpers.AssertEqual(t, int64(3), *page.Total)
+			} else {
+				testhelpers.AssertEqual(t, int64(len(page.Items)), *page.Total)
+			}
+		})
+	}
+}
+
+func TestListToolsInvalidFilters(t *testing.T) {
+	router := newToolsTestRouter(t)
+
+	tests := []struct {
+		query string
+		param string
+	}{
+		{query: "server=unknown", param: "server"},
+		{query: "server=not%20valid", param: "server"},
+		{query: "group=unknown", param: "group"},
+		{query: "enabled=yes", param: "enabled"},
+		{query: "q=" + strings.Repeat("a", maxToolSearchLength+1), param: "q"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.param, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/tools?"+tt.query, nil)
+			router.ServeHTTP(w, req)
+			testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+
+			var body types.ErrorResponse
+			testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			testhelpers.AssertEqual(t, types.ErrorCodeValidationFailed, body.Error.Code)
+			testhelpers.AssertEqual(t, tt.param, body.Error.Details["parameter"])
+			testhelpers.AssertTrue(t, strings.Contains(body.Error.Message, "parameter "+tt.param), "the error must name the parameter: "+body.Error.Message)
+		})
+	}
+}
+
+func TestStreamTools(t *testing.T) {
+	router := newToolsTestRouter(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/tools/stream?group=review", nil)
+	router.ServeHTTP(w, req)
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	testhelpers.AssertEqual(t, types.NDJSONContentType, w.Header().Get("Content-Type"))
+
+	var names []string
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var tool model.Tool
+		testhelpers.AssertNoError(t, json.Unmarshal(scanner.Bytes(), &tool))
+		names = append(names, tool.Name)
+	}
+	testhelpers.AssertEqual(t, "git__commit,git__push", strings.Join(names, ","))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/tools/stream?server=unknown", nil)
+	router.ServeHTTP(w, req)
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+	testhelpers.AssertStringContains(t, w.Header().Get("Content-Type"), "application/json")
+	var body types.ErrorResponse
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	testhelpers.AssertEqual(t, "server", body.Error.Details["parameter"])
+}
+
+func TestCallTool(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setup := testhelpers.SetupTestDB(t)
+
+	upstream := server.NewMCPServer("github", "0.0.0")
+	upstream.AddTool(echoTool("git_commit"))
+	upstreamServer := server.NewTestStreamableHTTPServer(upstream)
+	defer upstreamServer.Close()
+
+	proxy := server.NewMCPServer("test", "0.0.0")
+	mcpService, err := mcp.NewMCPService(&mcp.ServiceConfig{
+		DB:                      setup.DB,
+		McpProxyServer:          proxy,
+		SseMcpProxyServer:       proxy,
+		Metrics:                 telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout: 5,
+	})
+	testhelpers.AssertNoError(t, err)
+	github, err := model.NewStreamableHTTPServer("github", "", upstreamServer.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, mcpService.RegisterMcpServer(t.Context(), github))
+
+	mcpClientService := mcpclient.NewMCPClientService(setup.DB)
+	allowed, err := mcpClientService.CreateClient(model.McpClient{Name: "ci", AllowList: []byte(`["github"]`)})
+	testhelpers.AssertNoError(t, err)
+	denied, err := mcpClientService.CreateClient(model.McpClient{Name: "chat", AllowList: []byte(`["slack"]`)})
+	testhelpers.AssertNoError(t, err)
+
+	s := &Server{mcpService: mcpService, mcpClientService: mcpClientService}
+	newRouter := func(mode model.ServerMode) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) { c.Set("mode", mode) })
+		router.POST("/tools/:name/invoke", s.checkAuthForMcpProxyAccess(), s.callToolHandler())
+		return router
+	}
+	call := func(router *gin.Engine, name, body, token, accept string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/tools/"+name+"/invoke", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("dev mode", func(t *testing.T) {
+		router := newRouter(model.ModeDev)
+
+		w := call(router, "github__git_commit", `{"message": "fix"}`, "", "")
+		testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+		var res types.ToolInvokeResult
+		testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		testhelpers.AssertFalse(t, res.IsError, "the call should have succeeded")
+		testhelpers.AssertEqual(t, 1, len(res.Content))
+		testhelpers.AssertEqual(t, "git_commit", res.Content[0]["text"])
+
+		// no body means no arguments
+		testhelpers.AssertEqual(t, http.StatusOK, call(router, "github__git_commit", "", "", "").Code)
+		testhelpers.AssertEqual(t, http.StatusBadRequest, call(router, "github__git_commit", `["fix"]`, "", "").Code)
+
+		w = call(router, "github__git_push", "{}", "", "")
+		testhelpers.AssertEqual(t, http.StatusNotFound, w.Code)
+		testhelpers.AssertStringContains(t, w.Body.String(), string(types.ErrorCodeNotFound))
+	})
+
+	t.Run("enterprise mode", func(t *testing.T) {
+		router := newRouter(model.ModeEnterprise)
+
+		testhelpers.AssertEqual(t, http.StatusUnauthorized, call(router, "github__git_commit", "{}", "", "").Code)
+		testhelpers.AssertEqual(t, http.StatusUnauthorized, call(router, "github__git_commit", "{}", "not-a-token", "").Code)
+
+		w := call(router, "github__git_commit", "{}", denied.AccessToken, "")
+		testhelpers.AssertEqual(t, http.StatusForbidden, w.Code)
+		testhelpers.AssertStringContains(t, w.Body.String(), string(types.ErrorCodeForbidden))
+
+		testhelpers.AssertEqual(t, http.StatusOK, call(router, "github__git_commit", "{}", allowed.AccessToken, "").Code)
+	})
+
+	t.Run("event stream", func(t *testing.T) {
+		router := newRouter(model.ModeDev)
+
+		w := call(router, "github__git_commit", "{}", "", "text/event-stream")
+		testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+		testhelpers.AssertStringContains(t, w.Header().Get("Content-Type"), "text/event-stream")
+
+		var events []string
+		var data []string
+		scanner := bufio.NewScanner(w.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if event, ok := strings.CutPrefix(line, "event:"); ok {
+				events = append(events, event)
+			} else if d, ok := strings.CutPrefix(line, "data:"); ok {
+				data = append(data, d)
+			}
+		}
+		testhelpers.AssertEqual(t, "content,result", strings.Join(events, ","))
+		testhelpers.AssertStringContains(t, data[0], `"text":"git_commit"`)
+		testhelpers.AssertStringContains(t, data[1], `"isError":false`)
+
+		// errors before the stream starts are regular responses
+		testhelpers.AssertEqual(t, http.StatusNotFound, call(router, "github__git_push", "{}", "", "text/event-stream").Code)
+	})
+}
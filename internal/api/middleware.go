@@ -2,7 +2,7 @@ package api
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -16,7 +16,7 @@ func (s *Server) requireInitialized() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		cfg, err := s.configService.GetConfig()
 		if err != nil || !cfg.Initialized {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "server is not initialized"})
+			respondError(c, newAPIError(http.StatusForbidden, types.ErrorCodeNotInitialized, "server is not initialized"))
 			return
 		}
 		// propagate the server mode in context for other middleware/handlers to use
@@ -31,12 +31,12 @@ func (s *Server) verifyUserAuthForAPIAccess() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		mode, exists := c.Get("mode")
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "server mode not found in context"})
+			respondError(c, errors.New("server mode not found in context"))
 			return
 		}
 		m, ok := mode.(model.ServerMode)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "invalid server mode in context"})
+			respondError(c, errors.New("invalid server mode in context"))
 			return
 		}
 		if m == model.ModeDev {
@@ -48,14 +48,14 @@ func (s *Server) verifyUserAuthForAPIAccess() gin.HandlerFunc {
 		authHeader := c.GetHeader("Authorization")
 		token := strings.TrimPrefix(authHeader, "Bearer ")
 		if token == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing access token"})
+			respondError(c, unauthorized("missing access token"))
 			return
 		}
 
 		// Verify that the token is valid and corresponds to a user
 		authenticatedUser, err := s.userService.GetUserByAccessToken(token)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid access token: " + err.Error()})
+			respondError(c, unauthorized("invalid access token: %v", err))
 			return
 		}
 
@@ -71,12 +71,12 @@ func (s *Server) requireAdminUser() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		mode, exists := c.Get("mode")
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "server mode not found in context"})
+			respondError(c, errors.New("server mode not found in context"))
 			return
 		}
 		m, ok := mode.(model.ServerMode)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "invalid server mode in context"})
+			respondError(c, errors.New("invalid server mode in context"))
 			return
 		}
 		if m == model.ModeDev {
@@ -87,7 +87,7 @@ func (s *Server) requireAdminUser() gin.HandlerFunc {
 
 		authenticatedUser, exists := c.Get("user")
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "user is not authenticated"})
+			respondError(c, unauthorized("user is not authenticated"))
 			return
 		}
 
@@ -97,10 +97,32 @@ func (s *Server) requireAdminUser() gin.HandlerFunc {
 			return
 		}
 
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "user is not authorized to perform this action"})
+		respondError(c, roleRequired(types.UserRoleAdmin, "user is not authorized to perform this action"))
 	}
 }
 
+// isAdmin reports whether the request is made with admin privileges: always in dev mode,
+// and by an authenticated admin user in enterprise mode.
+// It assumes that verifyUserAuthForAPIAccess middleware has already run and set the user in context.
+func isAdmin(c *gin.Context) bool {
+	if mode, _ := c.Get("mode"); mode == model.ModeDev {
+		return true
+	}
+	u, ok := c.Get("user")
+	if !ok {
+		return false
+	}
+	user, ok := u.(*model.User)
+	return ok && user.Role == types.UserRoleAdmin
+}
+
+// authenticatedMcpClient returns the MCP client that made the request, nil if there is none, eg- in development mode.
+// It assumes that checkAuthForMcpProxyAccess middleware has already run and set the client in the request's context.
+func authenticatedMcpClient(c *gin.Context) *model.McpClient {
+	client, _ := c.Request.Context().Value("client").(*model.McpClient)
+	return client
+}
+
 // requireServerMode is middleware that checks if the server is in a specific mode.
 // If not, the request is rejected with a 403 Forbidden status.
 // This is useful for routes that should only be accessible in certain modes (e.g., enterprise-only features).
@@ -109,12 +131,12 @@ func (s *Server) requireServerMode(m model.ServerMode) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		mode, exists := c.Get("mode")
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "server mode not found in context"})
+			respondError(c, errors.New("server mode not found in context"))
 			return
 		}
 		currentMode, ok := mode.(model.ServerMode)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "invalid server mode in context"})
+			respondError(c, errors.New("invalid server mode in context"))
 			return
 		}
 
@@ -129,10 +151,9 @@ func (s *Server) requireServerMode(m model.ServerMode) gin.HandlerFunc {
 			return
 		}
 		// current mode does not match the required mode, reject the request
-		c.AbortWithStatusJSON(
-			http.StatusForbidden,
-			gin.H{"error": fmt.Sprintf("this request is only allowed in %s mode", m)},
-		)
+		respondError(c, newAPIError(
+			http.StatusForbidden, types.ErrorCodeWrongMode, "this request is only allowed in %s mode", m,
+		).with("required_mode", m))
 	}
 }
 
@@ -143,12 +164,12 @@ func (s *Server) checkAuthForMcpProxyAccess() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		mode, exists := c.Get("mode")
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "server mode not found in context"})
+			respondError(c, errors.New("server mode not found in context"))
 			return
 		}
 		m, ok := mode.(model.ServerMode)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "invalid server mode in context"})
+			respondError(c, errors.New("invalid server mode in context"))
 			return
 		}
 
@@ -166,12 +187,12 @@ func (s *Server) checkAuthForMcpProxyAccess() gin.HandlerFunc {
 		authHeader := c.GetHeader("Authorization")
 		token := strings.TrimPrefix(authHeader, "Bearer ")
 		if token == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing MCP client access token"})
+			respondError(c, unauthorized("missing MCP client access token"))
 			return
 		}
 		client, err := s.mcpClientService.GetClientByToken(token)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid MCP client token"})
+			respondError(c, unauthorized("invalid MCP client token"))
 			return
 		}
 
@@ -44,7 +44,7 @@ func TestRequireInitialized(t *testing.T) {
 				return testDB.Create(&cfg).Error
 			},
 			expectedStatus: http.StatusForbidden,
-			expectedBody:   `{"error":"server is not initialized"}`,
+			expectedBody:   `{"error":{"code":"not_initialized","message":"server is not initialized"}}`,
 		},
 	}
 
@@ -132,7 +132,7 @@ func TestVerifyUserAuthForAPIAccess(t *testing.T) {
 			authHeader:     "",
 			setupUser:      func() error { return nil },
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"missing access token"}`,
+			expectedBody:   `{"error":{"code":"unauthorized","message":"missing access token"}}`,
 		},
 	}
 
@@ -213,7 +213,7 @@ func TestRequireAdminUser(t *testing.T) {
 				Role:     types.UserRoleUser,
 			},
 			expectedStatus: http.StatusForbidden,
-			expectedBody:   `{"error":"user is not authorized to perform this action"}`,
+			expectedBody:   `{"error":{"code":"forbidden","message":"user is not authorized to perform this action","details":{"required_role":"admin"}}}`,
 		},
 	}
 
@@ -271,14 +271,14 @@ func TestRequireServerMode(t *testing.T) {
 			contextMode:    model.ModeEnterprise,
 			requiredMode:   model.ModeDev,
 			expectedStatus: http.StatusForbidden,
-			expectedBody:   `{"error":"this request is only allowed in development mode"}`,
+			expectedBody:   `{"error":{"code":"wrong_mode","message":"this request is only allowed in development mode","details":{"required_mode":"development"}}}`,
 		},
 		{
 			name:           "non-matching mode - dev required, prod context",
 			contextMode:    model.ModeProd,
 			requiredMode:   model.ModeDev,
 			expectedStatus: http.StatusForbidden,
-			expectedBody:   `{"error":"this request is only allowed in development mode"}`,
+			expectedBody:   `{"error":{"code":"wrong_mode","message":"this request is only allowed in development mode","details":{"required_mode":"development"}}}`,
 		},
 		{
 			name:           "enterprise required, prod context (deprecated)",
@@ -394,7 +394,7 @@ func TestCheckAuthForMcpProxyAccess(t *testing.T) {
 			authHeader:     "",
 			setupClient:    func() error { return nil },
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"missing MCP client access token"}`,
+			expectedBody:   `{"error":{"code":"unauthorized","message":"missing MCP client access token"}}`,
 		},
 	}
 
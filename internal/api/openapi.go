@@ -0,0 +1,412 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/mcpjungle/mcpjungle/pkg/version"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification the API document conforms to.
+const OpenAPIVersion = "3.0.3"
+
+// bearerAuthScheme is the name of the security scheme of authenticated API routes in the OpenAPI document.
+const bearerAuthScheme = "bearerAuth"
+
+// openAPIHandler serves the OpenAPI document describing the HTTP API.
+// The document is generated from the same route definitions that the router is set up with,
+// on the first request since the routes include this handler.
+func (s *Server) openAPIHandler() gin.HandlerFunc {
+	var (
+		once sync.Once
+		doc  map[string]any
+	)
+	return func(c *gin.Context) {
+		once.Do(func() { doc = buildOpenAPIDocument(s.apiRoutes(), s.publicAPIRoutes(), s.mcpClientAPIRoutes()) })
+		c.JSON(http.StatusOK, doc)
+	}
+}
+
+// buildOpenAPIDocument generates the OpenAPI document of the /api/v1 routes.
+// Schemas of request and response bodies are derived from their Go types with reflection.
+func buildOpenAPIDocument(routes ...[]apiRoute) map[string]any {
+	schemas := newSchemaRegistry()
+	schemas.define("Error", map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"error": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"code": map[string]any{
+						"type": "string", "enum": types.ErrorCodes,
+						"description": "Machine-readable kind of the failure, clients should rely on it rather than on the message",
+					},
+					"message": map[string]any{"type": "string", "description": "Description of what went wrong"},
+					"details": map[string]any{
+						"type": "object", "additionalProperties": true,
+						"description": "Information specific to the code, eg- the fields that failed validation or the role the request requires",
+					},
+				},
+				"required": []string{"code", "message"},
+			},
+		},
+		"required": []string{"error"},
+	})
+
+	paths := map[string]map[string]any{}
+	for _, group := range routes {
+		for _, r := range group {
+			path := openAPIPath(r.path)
+			if paths[path] == nil {
+				paths[path] = map[string]any{}
+			}
+			paths[path][strings.ToLower(r.method)] = r.operation(schemas)
+		}
+	}
+
+	return map[string]any{
+		"openapi": OpenAPIVersion,
+		"info": map[string]any{
+			"title":       "MCPJungle API",
+			"description": "HTTP API of the MCPJungle registry server, used by the mcpjungle CLI to manage MCP servers, tools, prompts, tool groups, clients and users.",
+			"version":     version.GetVersion(),
+		},
+		"servers": []map[string]any{{"url": V1ApiPathPrefix}},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": schemas.schemas,
+			"responses": map[string]any{
+				"Error": map[string]any{
+					"description": "The request failed",
+					"content":     jsonContent(schemaRef("Error")),
+				},
+			},
+			"securitySchemes": map[string]any{
+				bearerAuthScheme: map[string]any{
+					"type":   "http",
+					"scheme": "bearer",
+					"description": "Access token of a user or an admin, or of an MCP client for the routes that call tools through the MCP proxy. " +
+						"It is only required when the server runs in enterprise mode, in development mode all requests are allowed.",
+				},
+			},
+		},
+	}
+}
+
+// operation returns the OpenAPI operation object of the route.
+func (r apiRoute) operation(schemas *schemaRegistry) map[string]any {
+	op := map[string]any{
+		"operationId": r.doc.operationID,
+		"summary":     r.doc.summary,
+		"tags":        []string{r.doc.tag},
+	}
+	if r.doc.description != "" || r.enterpriseOnly {
+		desc := r.doc.description
+		if r.enterpriseOnly {
+			desc = strings.TrimSpace(desc + " Only available when the server runs in enterprise mode.")
+		}
+		op["description"] = desc
+	}
+
+	var params []map[string]any
+	for _, name := range pathParams(r.path) {
+		param := map[string]any{"name": name, "in": "path", "required": true, "schema": map[string]any{"type": "string"}}
+		if r.doc.pathUUID {
+			param["description"] = "The name or the UUID of the entity"
+		}
+		params = append(params, param)
+	}
+	for _, q := range r.doc.query {
+		schema := map[string]any{"type": "string"}
+		if q.schemaType != "" {
+			schema = map[string]any{"type": q.schemaType}
+		}
+		if q.array {
+			schema = map[string]any{"type": "array", "items": schema}
+		}
+		params = append(params, map[string]any{
+			"name": q.name, "in": "query", "required": q.required, "description": q.description, "schema": schema,
+		})
+	}
+	for _, h := range r.doc.header {
+		params = append(params, map[string]any{
+			"name": h.name, "in": "header", "required": h.required, "description": h.description,
+			"schema": map[string]any{"type": "string"},
+		})
+	}
+	if r.doc.ifMatch {
+		params = append(params, map[string]any{
+			"name": "If-Match", "in": "header", "required": false, "schema": map[string]any{"type": "string"},
+			"description": "ETag of the version of the entity the request was computed against. " +
+				"The request fails with status 412 if the entity was changed since.",
+		})
+	}
+	if r.method == http.MethodPost && !r.streamed {
+		params = append(params, map[string]any{
+			"name": types.IdempotencyKeyHeader, "in": "header", "required": false,
+			"schema": map[string]any{"type": "string", "maxLength": maxIdempotencyKeyLength},
+			"description": "Unique key of the request. A request sent again with the same key is answered with the response " +
+				"to the first one instead of being applied twice. The request fails with status 422 if the key was used for a different request.",
+		})
+	}
+	ifNoneMatch := r.doc.ifNoneMatch || len(r.etagTables) > 0
+	if ifNoneMatch {
+		params = append(params, map[string]any{
+			"name": "If-None-Match", "in": "header", "required": false, "schema": map[string]any{"type": "string"},
+			"description": "ETag of a previous response. The response has status 304 and no body if it didn't change since.",
+		})
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	if r.doc.request != nil {
+		content := jsonContent(schemas.schemaFor(r.doc.request))
+		if r.doc.requestType != "" {
+			content = map[string]any{r.doc.requestType: content["application/json"]}
+		}
+		op["requestBody"] = map[string]any{"required": true, "content": content}
+	}
+
+	status := r.doc.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := map[string]any{"description": http.StatusText(status)}
+	if r.doc.response != nil {
+		content := jsonContent(schemas.schemaFor(r.doc.response))
+		if r.doc.responseType != "" {
+			content = map[string]any{r.doc.responseType: content["application/json"]}
+		}
+		success["content"] = content
+	}
+	errorResponse := map[string]any{"$ref": "#/components/responses/Error"}
+	responses := map[string]any{
+		strconv.Itoa(status): success,
+		"default":            errorResponse,
+	}
+	if r.doc.request != nil || len(r.doc.query) > 0 {
+		responses["400"] = errorResponse
+	}
+	if len(pathParams(r.path)) > 0 {
+		responses["404"] = errorResponse
+	}
+	if r.doc.ifMatch {
+		responses["412"] = errorResponse
+	}
+	if r.method == http.MethodPost {
+		responses["422"] = errorResponse
+	}
+	if ifNoneMatch {
+		responses["304"] = map[string]any{"description": http.StatusText(http.StatusNotModified)}
+	}
+	if r.access != publicAccess {
+		responses["401"] = errorResponse
+		responses["403"] = errorResponse
+		responses["429"] = errorResponse
+		op["security"] = []map[string][]string{{bearerAuthScheme: {}}}
+	}
+	op["responses"] = responses
+	return op
+}
+
+// rawSchema is a hand-written JSON schema, for request bodies that don't have a Go type.
+type rawSchema map[string]any
+
+// schemaRegistry generates JSON schemas from Go types and collects the schemas of named types
+// so that they are defined once in the document's components.
+type schemaRegistry struct {
+	schemas map[string]any
+	// names maps every named type seen so far to the name of its schema
+	names map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: map[string]any{}, names: map[reflect.Type]string{}}
+}
+
+func (r *schemaRegistry) define(name string, schema map[string]any) {
+	r.schemas[name] = schema
+}
+
+// schemaFor returns the schema of a value, which is either a rawSchema or a value of the type to describe.
+func (r *schemaRegistry) schemaFor(v any) map[string]any {
+	if s, ok := v.(rawSchema); ok {
+		return s
+	}
+	return r.schemaOf(reflect.TypeOf(v))
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaOf returns the schema of the JSON encoding of values of type t.
+// Structs are defined in the components and referenced, everything else is inlined.
+func (r *schemaRegistry) schemaOf(t reflect.Type) map[string]any {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t, nullable = t.Elem(), true
+	}
+
+	var s map[string]any
+	switch {
+	case t == timeType:
+		s = map[string]any{"type": "string", "format": "date-time"}
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		// custom encodings (eg- raw JSON columns) can hold any value
+		return map[string]any{}
+	case t.Kind() == reflect.Struct:
+		s = schemaRef(r.structSchemaName(t))
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		s = map[string]any{"type": "string", "format": "byte"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		s = map[string]any{"type": "array", "items": r.schemaOf(t.Elem())}
+	case t.Kind() == reflect.Map:
+		s = map[string]any{"type": "object", "additionalProperties": r.schemaOf(t.Elem())}
+	case t.Kind() == reflect.Interface:
+		return map[string]any{}
+	case t.Kind() == reflect.String:
+		s = map[string]any{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		s = map[string]any{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		s = map[string]any{"type": "integer"}
+		if t.Kind() == reflect.Int64 || t.Kind() == reflect.Uint64 || t.Kind() == reflect.Int || t.Kind() == reflect.Uint {
+			s["format"] = "int64"
+		} else {
+			s["format"] = "int32"
+		}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		s = map[string]any{"type": "number"}
+	default:
+		return map[string]any{}
+	}
+
+	// siblings of $ref are ignored in OpenAPI 3.0, so only inline schemas can be marked as nullable
+	if _, isRef := s["$ref"]; nullable && !isRef {
+		s["nullable"] = true
+	}
+	return s
+}
+
+// structSchemaName defines the schema of struct type t in the components, if not done yet, and returns its name.
+// Types of the public types package are named after the type, others are prefixed with their package's name
+// (eg- ModelTool) so that they don't clash with the types of the same name.
+func (r *schemaRegistry) structSchemaName(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := schemaName(t.PkgPath(), t.Name())
+	if name == "" {
+		name = "Object"
+	}
+	for base, i := name, 2; r.schemas[name] != nil; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	r.names[t] = name
+	// reserve the name before generating the properties, in case the type is recursive
+	r.schemas[name] = map[string]any{}
+
+	properties := map[string]any{}
+	r.collectProperties(t, properties)
+	r.schemas[name] = map[string]any{"type": "object", "properties": properties}
+	return name
+}
+
+// schemaName returns the name of the schema of the type with the given package path and name.
+// Instances of generic types are named after their type arguments, eg- types.Page[*types.User] is UserPage.
+func schemaName(pkg, name string) string {
+	if base, args, ok := strings.Cut(name, "["); ok {
+		var b strings.Builder
+		for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+			arg = strings.TrimLeft(arg, "*[]")
+			dot := strings.LastIndex(arg, ".")
+			b.WriteString(schemaName(arg[:max(dot, 0)], arg[dot+1:]))
+		}
+		return b.String() + schemaName(pkg, base)
+	}
+	if pkg != "" && !strings.HasSuffix(pkg, "/pkg/types") {
+		parts := strings.Split(pkg, "/")
+		name = capitalize(parts[len(parts)-1]) + name
+	}
+	return name
+}
+
+// collectProperties adds the JSON fields of struct type t to properties, the way encoding/json encodes them.
+func (r *schemaRegistry) collectProperties(t reflect.Type, properties map[string]any) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct && !ft.Implements(marshalerType) {
+			// fields of embedded structs are promoted to the parent object
+			r.collectProperties(ft, properties)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = r.schemaOf(f.Type)
+	}
+}
+
+// openAPIPath converts a gin route path into an OpenAPI path, eg- /servers/:name into /servers/{name}.
+func openAPIPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if strings.HasPrefix(p, ":") {
+			parts[i] = "{" + p[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// pathParams returns the names of the parameters of a gin route path, in order.
+func pathParams(path string) []string {
+	var params []string
+	for _, p := range strings.Split(path, "/") {
+		if strings.HasPrefix(p, ":") {
+			params = append(params, p[1:])
+		}
+	}
+	return params
+}
+
+func schemaRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func getOpenAPIDocument(t *testing.T) map[string]any {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	server, err := NewServer(&ServerOptions{})
+	testhelpers.AssertNoError(t, err)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, V1ApiPathPrefix+"/openapi.json", nil)
+	server.Router().ServeHTTP(w, req)
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+
+	var doc map[string]any
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	return doc
+}
+
+// TestOpenAPIDocumentIsValid checks the served document against the rules of the OpenAPI 3.0 specification
+// that matter to code generators: required fields, resolvable references, unique operation IDs and
+// path parameters that match the path templates.
+func TestOpenAPIDocumentIsValid(t *testing.T) {
+	doc := getOpenAPIDocument(t)
+
+	testhelpers.AssertEqual(t, OpenAPIVersion, doc["openapi"])
+	info := doc["info"].(map[string]any)
+	testhelpers.AssertTrue(t, info["title"] != "" && info["version"] != "", "info must have a title and a version")
+
+	components := doc["components"].(map[string]any)
+	resolve := func(ref string) bool {
+		parts := strings.Split(strings.TrimPrefix(ref, "#/components/"), "/")
+		if len(parts) != 2 || !strings.HasPrefix(ref, "#/components/") {
+			return false
+		}
+		section, ok := components[parts[0]].(map[string]any)
+		return ok && section[parts[1]] != nil
+	}
+	var checkRefs func(path string, v any)
+	checkRefs = func(path string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				testhelpers.AssertTrue(t, resolve(ref), path+": unresolvable reference "+ref)
+				testhelpers.AssertEqual(t, 1, len(v))
+			}
+			for k, child := range v {
+				checkRefs(path+"/"+k, child)
+			}
+		case []any:
+			for _, child := range v {
+				checkRefs(path, child)
+			}
+		}
+	}
+	checkRefs("#", doc)
+
+	paramPattern := regexp.MustCompile(`\{([^}]+)\}`)
+	methods := map[string]bool{"get": true, "put": true, "post": true, "delete": true, "patch": true}
+	operationIDs := map[string]bool{}
+	paths := doc["paths"].(map[string]any)
+	testhelpers.AssertTrue(t, len(paths) > 0, "the document must describe some paths")
+	for path, item := range paths {
+		testhelpers.AssertTrue(t, strings.HasPrefix(path, "/"), "paths must start with a slash: "+path)
+		for method, v := range item.(map[string]any) {
+			testhelpers.AssertTrue(t, methods[method], "unexpected method "+method+" in "+path)
+			op := v.(map[string]any)
+
+			id, _ := op["operationId"].(string)
+			testhelpers.AssertTrue(t, id != "", "operations must have an operationId: "+method+" "+path)
+			testhelpers.AssertFalse(t, operationIDs[id], "duplicate operationId "+id)
+			operationIDs[id] = true
+
+			declared := map[string]bool{}
+			if params, ok := op["parameters"].([]any); ok {
+				for _, p := range params {
+					p := p.(map[string]any)
+					if p["in"] == "path" {
+						testhelpers.AssertEqual(t, true, p["required"])
+						declared[p["name"].(string)] = true
+					}
+				}
+			}
+			templated := paramPattern.FindAllStringSubmatch(path, -1)
+			testhelpers.AssertEqual(t, len(templated), len(declared))
+			for _, m := range templated {
+				testhelpers.AssertTrue(t, declared[m[1]], "path parameter "+m[1]+" of "+path+" is not declared")
+			}
+
+			responses := op["responses"].(map[string]any)
+			hasSuccess := false
+			for code := range responses {
+				hasSuccess = hasSuccess || strings.HasPrefix(code, "2")
+			}
+			testhelpers.AssertTrue(t, hasSuccess, "operations must have a success response: "+id)
+		}
+	}
+}
+
+func TestOpenAPIDocumentDescribesRoutes(t *testing.T) {
+	doc := getOpenAPIDocument(t)
+	paths := doc["paths"].(map[string]any)
+
+	server, err := NewServer(&ServerOptions{})
+	testhelpers.AssertNoError(t, err)
+	for _, r := range append(server.apiRoutes(), server.publicAPIRoutes()...) {
+		item, ok := paths[openAPIPath(r.path)].(map[string]any)
+		testhelpers.AssertTrue(t, ok, "route is not documented: "+r.path)
+		testhelpers.AssertNotNil(t, item[strings.ToLower(r.method)])
+	}
+
+	servers := paths["/servers"].(map[string]any)
+	register := servers["post"].(map[string]any)
+	testhelpers.AssertNotNil(t, register["responses"].(map[string]any)["201"])
+	testhelpers.AssertNotNil(t, register["security"])
+
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	input := schemas["RegisterServerInput"].(map[string]any)["properties"].(map[string]any)
+	testhelpers.AssertEqual(t, "string", input["bearer_token"].(map[string]any)["type"])
+	testhelpers.AssertEqual(t, "array", input["args"].(map[string]any)["type"])
+	// model types are documented separately from the public types of the same name
+	tool := schemas["ModelTool"].(map[string]any)["properties"].(map[string]any)
+	testhelpers.AssertNotNil(t, tool["CreatedAt"])
+	testhelpers.AssertTrue(t, tool["ServerID"] == nil, "fields excluded from JSON must not be documented")
+
+	// list endpoints respond with a page of items
+	page := schemas["McpServerPage"].(map[string]any)["properties"].(map[string]any)
+	testhelpers.AssertEqual(t, "#/components/schemas/McpServer", page["items"].(map[string]any)["items"].(map[string]any)["$ref"])
+	testhelpers.AssertNotNil(t, page["next_cursor"])
+
+	version := paths["/version"].(map[string]any)["get"].(map[string]any)
+	testhelpers.AssertTrue(t, version["security"] == nil, "public routes don't require authentication")
+}
+
+func TestV0IsDeprecatedAliasOfV1(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(&model.ServerConfig{}))
+	server, err := NewServer(&ServerOptions{ConfigService: config.NewServerConfigService(db)})
+	testhelpers.AssertNoError(t, err)
+
+	routes := map[string]bool{}
+	for _, r := range server.Router().(*gin.Engine).Routes() {
+		routes[r.Method+" "+r.Path] = true
+	}
+	for _, r := range server.apiRoutes() {
+		testhelpers.AssertTrue(t, routes[r.method+" "+V1ApiPathPrefix+r.path], "missing v1 route "+r.path)
+		testhelpers.AssertTrue(t, routes[r.method+" "+V0ApiPathPrefix+r.path], "missing v0 alias of "+r.path)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, V0ApiPathPrefix+"/servers", nil)
+	server.Router().ServeHTTP(w, req)
+	testhelpers.AssertEqual(t, "true", w.Header().Get("Deprecation"))
+	testhelpers.AssertEqual(t, `</api/v1/servers>; rel="successor-version"`, w.Header().Get("Link"))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, V1ApiPathPrefix+"/servers", nil)
+	server.Router().ServeHTTP(w, req)
+	testhelpers.AssertEqual(t, "", w.Header().Get("Deprecation"))
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// Page sizes of the paginated list endpoints.
+const (
+	// DefaultPageLimit is the number of items returned when the request doesn't specify a limit.
+	DefaultPageLimit = 100
+	// MaxPageLimit is the largest number of items a page can contain.
+	MaxPageLimit = 1000
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// pageQueryParams documents the query parameters of the paginated list endpoints.
+var pageQueryParams = []queryParam{
+	{
+		name:        types.PageLimitParam,
+		schemaType:  "integer",
+		description: fmt.Sprintf("Maximum number of items to return, %d by default and at most %d. Admins can pass 0 to get all items at once.", DefaultPageLimit, MaxPageLimit),
+	},
+	{name: types.PageAfterParam, description: "Cursor returned as next_cursor by the previous page"},
+}
+
+// parsePage reads the page requested with the limit and after query parameters.
+// If they are invalid, an error response is sent and false is returned.
+func parsePage(c *gin.Context) (model.Page, bool) {
+	p := model.Page{Limit: DefaultPageLimit}
+	if v, ok := c.GetQuery(types.PageLimitParam); ok {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			invalidParam(c, types.PageLimitParam, "must be a non-negative integer")
+			return p, false
+		}
+		if limit > MaxPageLimit {
+			invalidParam(c, types.PageLimitParam, fmt.Sprintf("must not be greater than %d", MaxPageLimit))
+			return p, false
+		}
+		if limit == 0 && !isAdmin(c) {
+			// listing everything at once is only kept for admins while clients migrate to pagination
+			respondError(c, roleRequired(types.UserRoleAdmin, "only admins can list all items at once with limit=0"))
+			return p, false
+		}
+		p.Limit = limit
+	}
+	if v := c.Query(types.PageAfterParam); v != "" {
+		after, err := decodeCursor(v)
+		if err != nil {
+			invalidParam(c, types.PageAfterParam, "must be a cursor returned by a previous page")
+			return p, false
+		}
+		p.After = after
+	}
+	return p, true
+}
+
+// newPage builds the response of a paginated list endpoint.
+// next is the ID to list the next page after, 0 if there are no more items.
+func newPage[T any](items []T, next uint) types.Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	page := types.Page[T]{Items: items}
+	if next > 0 {
+		page.NextCursor = encodeCursor(next)
+	}
+	return page
+}
+
+// encodeCursor returns the opaque cursor pointing after the record with the given ID.
+// Clients must not interpret cursors, so that their format can change.
+func encodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeCursor(cursor string) (uint, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errInvalidCursor
+	}
+	id, err := strconv.ParseUint(string(b), 10, 0)
+	if err != nil || id == 0 {
+		return 0, errInvalidCursor
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcpclient"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// newPaginationTestRouter serves the MCP clients list of a registry with n clients,
+// to requests made by a user with the given role in enterprise mode.
+func newPaginationTestRouter(t *testing.T, n int, role types.UserRole) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(&model.McpClient{}))
+	svc := mcpclient.NewMCPClientService(db)
+	for i := 1; i <= n; i++ {
+		_, err := svc.CreateClient(model.McpClient{Name: "client-" + strconv.Itoa(i)})
+		testhelpers.AssertNoError(t, err)
+	}
+
+	s := &Server{mcpClientService: svc}
+	router := gin.New()
+	router.GET("/clients", func(c *gin.Context) {
+		c.Set("mode", model.ModeEnterprise)
+		c.Set("user", &model.User{Username: "alice", Role: role})
+	}, s.listMcpClientsHandler())
+	return router
+}
+
+func getClientsPage(t *testing.T, router *gin.Engine, query string) (int, types.Page[model.McpClient]) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/clients?"+query, nil)
+	router.ServeHTTP(w, req)
+
+	var page types.Page[model.McpClient]
+	if w.Code == http.StatusOK {
+		testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	}
+	return w.Code, page
+}
+
+func TestListPagination(t *testing.T) {
+	router := newPaginationTestRouter(t, 5, types.UserRoleUser)
+
+	var names []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		testhelpers.AssertTrue(t, pages < 5, "pagination should terminate")
+		status, page := getClientsPage(t, router, "limit=2&after="+cursor)
+		testhelpers.AssertEqual(t, http.StatusOK, status)
+		testhelpers.AssertTrue(t, len(page.Items) <= 2, "pages must not exceed the limit")
+		for _, c := range page.Items {
+			names = append(names, c.Name)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	testhelpers.AssertEqual(t, "client-1,client-2,client-3,client-4,client-5", strings.Join(names, ","))
+
+	t.Run("default limit", func(t *testing.T) {
+		status, page := getClientsPage(t, router, "")
+		testhelpers.AssertEqual(t, http.StatusOK, status)
+		testhelpers.AssertEqual(t, 5, len(page.Items))
+		testhelpers.AssertEqual(t, "", page.NextCursor)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"limit=-1", "limit=ten", "limit=" + strconv.Itoa(MaxPageLimit+1), "after=not-a-cursor"} {
+			status, _ := getClientsPage(t, router, query)
+			testhelpers.AssertEqual(t, http.StatusBadRequest, status)
+		}
+	})
+
+	t.Run("listing everything is reserved to admins", func(t *testing.T) {
+		status, _ := getClientsPage(t, router, "limit=0")
+		testhelpers.AssertEqual(t, http.StatusForbidden, status)
+
+		admin := newPaginationTestRouter(t, 5, types.UserRoleAdmin)
+		status, page := getClientsPage(t, admin, "limit=0")
+		testhelpers.AssertEqual(t, http.StatusOK, status)
+		testhelpers.AssertEqual(t, 5, len(page.Items))
+	})
+}
+
+func TestCursorEncoding(t *testing.T) {
+	id, err := decodeCursor(encodeCursor(42))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, uint(42), id)
+
+	for _, cursor := range []string{"", "!!", encodeCursor(0)} {
+		_, err := decodeCursor(cursor)
+		testhelpers.AssertError(t, err)
+	}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// MergePatchContentType is the media type of JSON merge patches (RFC 7386), accepted by the PATCH routes
+// along with application/json.
+const MergePatchContentType = "application/merge-patch+json"
+
+// versionETag returns the ETag of the given version of an entity.
+func versionETag(version uint) string {
+	return strconv.Quote(strconv.FormatUint(uint64(version), 10))
+}
+
+// setVersionETag sets the ETag header of the response to the given version of the entity it describes.
+func setVersionETag(c *gin.Context, version uint) {
+	c.Header("ETag", versionETag(version))
+}
+
+// ifMatchVersion returns the version of the entity the request was computed against, from its If-Match header.
+// It returns 0 if the request is unconditional. If the header doesn't hold the ETag of a version, it can never match,
+// so a 412 error response is sent and false is returned.
+func ifMatchVersion(c *gin.Context) (uint, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	tag, err := strconv.Unquote(strings.TrimPrefix(header, "W/"))
+	if err == nil {
+		var v uint64
+		if v, err = strconv.ParseUint(tag, 10, 0); err == nil && v > 0 {
+			return uint(v), true
+		}
+	}
+	respondError(c, newAPIError(
+		http.StatusPreconditionFailed, types.ErrorCodeVersionConflict, "If-Match header %s is not the ETag of a version", header,
+	))
+	return 0, false
+}
+
+// applyMergePatch applies the JSON merge patch (RFC 7386) in the body of the request to current,
+// and decodes the result into out. Fields of the patch that are null are removed from current,
+// which clears them. The patch must be a JSON object, and must not introduce unknown fields.
+func applyMergePatch(c *gin.Context, current any, out any) error {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read the request body: %w", err)
+	}
+	var patch any
+	if err := json.Unmarshal(body, &patch); err != nil {
+		return fmt.Errorf("invalid JSON merge patch: %w", err)
+	}
+	if _, ok := patch.(map[string]any); !ok {
+		return errors.New("invalid JSON merge patch: it must be a JSON object")
+	}
+
+	data, err := json.Marshal(current)
+	if err != nil {
+		return fmt.Errorf("failed to encode the current entity: %w", err)
+	}
+	var target any
+	if err := json.Unmarshal(data, &target); err != nil {
+		return fmt.Errorf("failed to decode the current entity: %w", err)
+	}
+
+	merged, err := json.Marshal(mergePatch(target, patch))
+	if err != nil {
+		return fmt.Errorf("failed to encode the patched entity: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(merged))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(out); err != nil {
+		return fmt.Errorf("invalid patched entity: %w", err)
+	}
+	return nil
+}
+
+// mergePatch applies a JSON merge patch to target, as described in RFC 7386.
+// Both are values decoded from JSON with encoding/json.
+func mergePatch(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = map[string]any{}
+	}
+	for k, v := range patchObj {
+		if v == nil {
+			delete(targetObj, k)
+			continue
+		}
+		targetObj[k] = mergePatch(targetObj[k], v)
+	}
+	return targetObj
+}
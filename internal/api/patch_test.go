@@ -0,0 +1,189 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestMergePatch(t *testing.T) {
+	// examples of RFC 7386, appendix A
+	tests := []struct {
+		target, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		var target, patch any
+		testhelpers.AssertNoError(t, json.Unmarshal([]byte(tt.target), &target))
+		testhelpers.AssertNoError(t, json.Unmarshal([]byte(tt.patch), &patch))
+		got, err := json.Marshal(mergePatch(target, patch))
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, tt.want, string(got))
+	}
+}
+
+// newPatchTestRouter serves the update APIs of a registry holding the github MCP server, whose upstream
+// cannot be connected to, and the ci-tools tool group.
+func newPatchTestRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(&model.McpServer{}, &model.Tool{}, &model.Prompt{}, &model.ToolGroup{}))
+
+	github := &model.McpServer{
+		Name:        "github",
+		Description: "GitHub tools",
+		Transport:   types.TransportStreamableHTTP,
+		Config:      datatypes.JSON(`{"url":"http://127.0.0.1:1/mcp"}`),
+	}
+	testhelpers.AssertNoError(t, db.Create(github).Error)
+	testhelpers.AssertNoError(t, db.Create(&model.Tool{
+		ServerID: github.ID, Name: "git_commit", InputSchema: model.CompressedJSON(`{"type":"object"}`),
+	}).Error)
+	testhelpers.AssertNoError(t, db.Create(&model.ToolGroup{
+		Name:          "ci-tools",
+		Description:   "Tools of the CI",
+		IncludedTools: datatypes.JSON(`["time__now"]`),
+	}).Error)
+
+	proxy := server.NewMCPServer("test", "0.0.0")
+	mcpService, err := mcp.NewMCPService(&mcp.ServiceConfig{
+		DB:                      db,
+		McpProxyServer:          proxy,
+		SseMcpProxyServer:       proxy,
+		Metrics:                 telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout: 1,
+	})
+	testhelpers.AssertNoError(t, err)
+	toolGroupService, err := toolgroup.NewToolGroupService(db, mcpService)
+	testhelpers.AssertNoError(t, err)
+
+	s := &Server{mcpService: mcpService, toolGroupService: toolGroupService}
+	router := gin.New()
+	router.GET("/servers/:name", s.getServerHandler())
+	router.PATCH("/servers/:name", s.patchServerHandler())
+	router.PATCH("/tool-groups/:name", s.patchToolGroupHandler())
+	return router, db
+}
+
+func sendPatch(router *gin.Engine, path, ifMatch, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", MergePatchContentType)
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPatchServerHandler(t *testing.T) {
+	router, db := newPatchTestRouter(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/servers/github", nil)
+	router.ServeHTTP(w, req)
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	testhelpers.AssertEqual(t, `"1"`, w.Header().Get("ETag"))
+
+	w = httptest.NewRecorder()
+	req.Header.Set("If-None-Match", `"1"`)
+	router.ServeHTTP(w, req)
+	testhelpers.AssertEqual(t, http.StatusNotModified, w.Code)
+
+	// the connection settings are unchanged, so the unreachable upstream is not connected to
+	w = sendPatch(router, "/servers/github", `"1"`, `{"description": "Tools to work with GitHub"}`)
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	testhelpers.AssertEqual(t, `"2"`, w.Header().Get("ETag"))
+	var updated types.McpServer
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	testhelpers.AssertEqual(t, "Tools to work with GitHub", updated.Description)
+	testhelpers.AssertEqual(t, "http://127.0.0.1:1/mcp", updated.URL)
+	testhelpers.AssertEqual(t, uint(2), updated.Version)
+
+	var tools int64
+	testhelpers.AssertNoError(t, db.Model(&model.Tool{}).Count(&tools).Error)
+	testhelpers.AssertEqual(t, int64(1), tools)
+
+	// null clears a field
+	w = sendPatch(router, "/servers/github", "", `{"description": null}`)
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	testhelpers.AssertEqual(t, "", updated.Description)
+
+	// the patch was computed against an outdated version
+	w = sendPatch(router, "/servers/github", `"1"`, `{"description": "stale"}`)
+	testhelpers.AssertEqual(t, http.StatusPreconditionFailed, w.Code)
+
+	w = sendPatch(router, "/servers/github", "", `{"name": "gitlab"}`)
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+	testhelpers.AssertStringContains(t, w.Body.String(), "cannot be changed")
+
+	w = sendPatch(router, "/servers/github", "", `{"unknown": true}`)
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+
+	// the merged configuration is validated
+	w = sendPatch(router, "/servers/github", "", `{"url": null}`)
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+
+	// new connection settings are checked by connecting to the upstream
+	w = sendPatch(router, "/servers/github", "", `{"url": "http://127.0.0.1:1/v2/mcp"}`)
+	testhelpers.AssertTrue(t, w.Code >= http.StatusBadRequest, "the upstream cannot be connected to")
+
+	w = sendPatch(router, "/servers/gitlab", "", `{"description": "GitLab tools"}`)
+	testhelpers.AssertEqual(t, http.StatusNotFound, w.Code)
+}
+
+func TestPatchToolGroupHandler(t *testing.T) {
+	router, _ := newPatchTestRouter(t)
+
+	w := sendPatch(router, "/tool-groups/ci-tools", `"1"`, `{"description": null}`)
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	testhelpers.AssertEqual(t, `"2"`, w.Header().Get("ETag"))
+	var resp types.UpdateToolGroupResponse
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	testhelpers.AssertEqual(t, "Tools of the CI", resp.Old.Description)
+	testhelpers.AssertEqual(t, "", resp.New.Description)
+	testhelpers.AssertEqual(t, 1, len(resp.New.IncludedTools))
+	testhelpers.AssertEqual(t, uint(2), resp.New.Version)
+
+	w = sendPatch(router, "/tool-groups/ci-tools", `"1"`, `{"description": "stale"}`)
+	testhelpers.AssertEqual(t, http.StatusPreconditionFailed, w.Code)
+
+	w = sendPatch(router, "/tool-groups/ci-tools", `not-an-etag`, `{"description": "stale"}`)
+	testhelpers.AssertEqual(t, http.StatusPreconditionFailed, w.Code)
+
+	w = sendPatch(router, "/tool-groups/ci-tools", "", `["not", "an", "object"]`)
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+
+	w = sendPatch(router, "/tool-groups/ci-tools", "", `{"name": "cd-tools"}`)
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+
+	w = sendPatch(router, "/tool-groups/cd-tools", "", `{"description": "Tools of the CD"}`)
+	testhelpers.AssertEqual(t, http.StatusNotFound, w.Code)
+}
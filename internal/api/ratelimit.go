@@ -0,0 +1,170 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// RateLimit is the number of API requests every caller can make per window.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// rateLimiter counts the requests of every caller in fixed windows of time.
+type rateLimiter struct {
+	limit  int
+	window time.Duration
+	// now returns the current time, it is a field so that tests can control the clock.
+	now func() time.Time
+	// store counts the requests in the database shared by the replicas, in the windows that start at the multiples
+	// of the window. If nil, they are counted in windows, whose first request starts them.
+	store *db.RateLimitWindows
+
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+	// nextSweep is when the windows that ended are next removed, so that callers that went away are forgotten.
+	nextSweep time.Time
+}
+
+type rateWindow struct {
+	reset time.Time
+	count int
+}
+
+func newRateLimiter(cfg RateLimit, store *db.RateLimitWindows) *rateLimiter {
+	return &rateLimiter{
+		limit:   cfg.Requests,
+		window:  cfg.Window,
+		now:     time.Now,
+		store:   store,
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// take counts a request of the caller identified by key. It returns the number of requests the caller can still make
+// in the current window, when the window ends, and false if the caller has no requests left.
+func (l *rateLimiter) take(key string) (int, time.Time, bool, error) {
+	if l.store != nil {
+		return l.takeShared(key)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !now.Before(l.nextSweep) {
+		for k, w := range l.windows {
+			if !now.Before(w.reset) {
+				delete(l.windows, k)
+			}
+		}
+		l.nextSweep = now.Add(l.window)
+	}
+
+	w, ok := l.windows[key]
+	if !ok || !now.Before(w.reset) {
+		w = &rateWindow{reset: now.Add(l.window)}
+		l.windows[key] = w
+	}
+	if w.count >= l.limit {
+		return 0, w.reset, false, nil
+	}
+	w.count++
+	return l.limit - w.count, w.reset, true, nil
+}
+
+// takeShared counts a request of the caller in the database, see take.
+// The requests over the limit are counted too, the caller has no requests left either way.
+func (l *rateLimiter) takeShared(key string) (int, time.Time, bool, error) {
+	now := l.now()
+	start := now.Truncate(l.window)
+	reset := start.Add(l.window)
+
+	l.mu.Lock()
+	sweep := !now.Before(l.nextSweep)
+	if sweep {
+		l.nextSweep = reset
+	}
+	l.mu.Unlock()
+	if sweep {
+		// every replica prunes the windows that ended, deleting them again does no harm
+		if err := l.store.Prune(start); err != nil {
+			log.Printf("[WARN] %v", err)
+		}
+	}
+
+	requests, err := l.store.Take(key, start)
+	if err != nil {
+		return 0, reset, false, err
+	}
+	if requests > l.limit {
+		return 0, reset, false, nil
+	}
+	return l.limit - requests, reset, true, nil
+}
+
+// rateLimitAPI is middleware that limits the number of API requests every caller can make,
+// and reports the caller's budget in the X-RateLimit-* headers of the response.
+// Requests over the limit are rejected with status 429 and a Retry-After header.
+// Callers are identified by their user or MCP client in enterprise mode, and by their IP address in dev mode.
+// It assumes that the authentication middleware has already run and set the caller in context.
+func (s *Server) rateLimitAPI() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limiter := s.rateLimiter.Load()
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		key := "ip:" + c.ClientIP()
+		if u, ok := c.Get("user"); ok {
+			if user, ok := u.(*model.User); ok {
+				key = "user:" + user.Username
+			}
+		} else if client := authenticatedMcpClient(c); client != nil {
+			key = "client:" + client.Name
+		}
+		remaining, reset, ok, err := limiter.take(key)
+		if err != nil {
+			// the requests are let through rather than failing the API while the database can't count them
+			log.Printf("[WARN] API request not rate limited: %v", err)
+			c.Next()
+			return
+		}
+		c.Header(types.RateLimitLimitHeader, strconv.Itoa(limiter.limit))
+		c.Header(types.RateLimitRemainingHeader, strconv.Itoa(remaining))
+		c.Header(types.RateLimitResetHeader, strconv.FormatInt(reset.Unix(), 10))
+		if ok {
+			c.Next()
+			return
+		}
+
+		// round up, so that the caller doesn't retry before the window ends
+		retryAfter := int((reset.Sub(limiter.now()) + time.Second - 1) / time.Second)
+		c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		respondError(c, newAPIError(
+			http.StatusTooManyRequests, types.ErrorCodeRateLimited,
+			"rate limit of %d requests per %s exceeded, retry in %d seconds", limiter.limit, limiter.window, max(retryAfter, 1),
+		).with("retry_after", max(retryAfter, 1)))
+	}
+}
+
+// SetAPIRateLimit replaces the limit of the number of API requests every caller can make, nil disables it.
+// The requests counted in memory are forgotten, the callers start over with a full budget, while those counted in
+// the database shared by the replicas are kept until their window ends.
+func (s *Server) SetAPIRateLimit(limit *RateLimit) {
+	if limit == nil {
+		s.rateLimiter.Store(nil)
+		return
+	}
+	s.rateLimiter.Store(newRateLimiter(*limit, s.rateLimitWindows))
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestRateLimitAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Unix(1_700_000_000, 0)
+	limiter := newRateLimiter(RateLimit{Requests: 2, Window: time.Minute}, nil)
+	limiter.now = func() time.Time { return now }
+	s := &Server{}
+	s.rateLimiter.Store(limiter)
+
+	router := gin.New()
+	router.GET("/tools", func(c *gin.Context) {
+		if username := c.GetHeader("X-Test-User"); username != "" {
+			c.Set("user", &model.User{Username: username})
+		}
+		c.Next()
+	}, s.rateLimitAPI(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	get := func(username string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/tools", nil)
+		req.Header.Set("X-Test-User", username)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	reset := strconv.FormatInt(now.Add(time.Minute).Unix(), 10)
+	for _, remaining := range []string{"1", "0"} {
+		w := get("alice")
+		testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+		testhelpers.AssertEqual(t, "2", w.Header().Get(types.RateLimitLimitHeader))
+		testhelpers.AssertEqual(t, remaining, w.Header().Get(types.RateLimitRemainingHeader))
+		testhelpers.AssertEqual(t, reset, w.Header().Get(types.RateLimitResetHeader))
+	}
+
+	now = now.Add(45 * time.Second)
+	w := get("alice")
+	testhelpers.AssertEqual(t, http.StatusTooManyRequests, w.Code)
+	testhelpers.AssertEqual(t, "15", w.Header().Get("Retry-After"))
+	testhelpers.AssertEqual(t, "0", w.Header().Get(types.RateLimitRemainingHeader))
+	testhelpers.AssertStringContains(t, w.Body.String(), "rate limit of 2 requests")
+
+	// every user has a budget of their own
+	testhelpers.AssertEqual(t, http.StatusOK, get("bob").Code)
+
+	// the budget is reset when the window ends
+	now = now.Add(15 * time.Second)
+	w = get("alice")
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	testhelpers.AssertEqual(t, "1", w.Header().Get(types.RateLimitRemainingHeader))
+}
+
+func TestRateLimitAPIDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/tools", (&Server{}).rateLimitAPI(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/tools", nil)
+	router.ServeHTTP(w, req)
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	testhelpers.AssertEqual(t, "", w.Header().Get(types.RateLimitLimitHeader))
+}
+
+func TestRateLimiterForgetsIdleCallers(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := newRateLimiter(RateLimit{Requests: 10, Window: time.Minute}, nil)
+	l.now = func() time.Time { return now }
+	l.take("ip:10.0.0.1")
+	l.take("ip:10.0.0.2")
+
+	now = now.Add(2 * time.Minute)
+	l.take("ip:10.0.0.3")
+	testhelpers.AssertEqual(t, 1, len(l.windows))
+}
+
+func TestRateLimitAPISharedByReplicas(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setup := testhelpers.SetupTestDB(t)
+	now := time.Unix(1_700_000_000, 0)
+
+	// the replicas share the database the requests are counted in
+	var routers []*gin.Engine
+	for range 2 {
+		s := &Server{rateLimitWindows: db.NewRateLimitWindows(setup.DB)}
+		s.SetAPIRateLimit(&RateLimit{Requests: 3, Window: time.Minute})
+		s.rateLimiter.Load().now = func() time.Time { return now }
+		router := gin.New()
+		router.GET("/tools", s.rateLimitAPI(), func(c *gin.Context) { c.Status(http.StatusOK) })
+		routers = append(routers, router)
+	}
+	get := func(replica int) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/tools", nil)
+		req.RemoteAddr = "10.0.0.1:51234"
+		routers[replica].ServeHTTP(w, req)
+		return w
+	}
+
+	// the windows start at the multiples of their length
+	reset := strconv.FormatInt(now.Truncate(time.Minute).Add(time.Minute).Unix(), 10)
+	for i, remaining := range []string{"2", "1", "0"} {
+		w := get(i % 2)
+		testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+		testhelpers.AssertEqual(t, remaining, w.Header().Get(types.RateLimitRemainingHeader))
+		testhelpers.AssertEqual(t, reset, w.Header().Get(types.RateLimitResetHeader))
+	}
+	for replica := range 2 {
+		w := get(replica)
+		testhelpers.AssertEqual(t, http.StatusTooManyRequests, w.Code)
+		testhelpers.AssertEqual(t, "0", w.Header().Get(types.RateLimitRemainingHeader))
+	}
+
+	// the budget is reset on every replica when the window ends
+	now = now.Truncate(time.Minute).Add(time.Minute)
+	w := get(1)
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	testhelpers.AssertEqual(t, "2", w.Header().Get(types.RateLimitRemainingHeader))
+
+	// the windows that ended are pruned
+	var windows int64
+	testhelpers.AssertNoError(t, setup.DB.Model(&model.RateLimitWindow{}).Count(&windows).Error)
+	testhelpers.AssertEqual(t, int64(1), windows)
+}
+
+func TestSetAPIRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &Server{}
+	router := gin.New()
+	router.GET("/tools", s.rateLimitAPI(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/tools", nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	s.SetAPIRateLimit(&RateLimit{Requests: 1, Window: time.Minute})
+	testhelpers.AssertEqual(t, http.StatusOK, get().Code)
+	testhelpers.AssertEqual(t, http.StatusTooManyRequests, get().Code)
+
+	// a new limit gives the callers a full budget
+	s.SetAPIRateLimit(&RateLimit{Requests: 5, Window: time.Minute})
+	w := get()
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	testhelpers.AssertEqual(t, "5", w.Header().Get(types.RateLimitLimitHeader))
+
+	s.SetAPIRateLimit(nil)
+	w = get()
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	testhelpers.AssertEqual(t, "", w.Header().Get(types.RateLimitLimitHeader))
+}
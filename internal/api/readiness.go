@@ -0,0 +1,43 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// readinessHandler reports whether the server is able to serve requests.
+// Unlike /health, which only tells that the process is up, it queries the database.
+// It doesn't depend on the upstream MCP servers: those that are warming up are listed, but the server is ready.
+// It responds with 503 if the server is not ready, or shutting down, so that load balancers can take it out of rotation.
+func (s *Server) readinessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r := &types.ServerReadiness{Time: time.Now().UTC()}
+		if s.draining.Load() {
+			r.ShuttingDown = true
+			c.JSON(http.StatusServiceUnavailable, r)
+			return
+		}
+
+		cfg, err := s.configService.GetConfig()
+		if err != nil {
+			// the endpoint is public, the details of the error are only logged
+			log.Printf("[ERROR] readiness check failed to query the database: %v", err)
+			r.Database = types.DatabaseUnavailable
+			c.JSON(http.StatusServiceUnavailable, r)
+			return
+		}
+
+		r.Ready = true
+		r.Database = "ok"
+		r.Initialized = cfg.Initialized
+		r.Mode = string(cfg.Mode)
+		if s.mcpService != nil {
+			r.WarmingUp = s.mcpService.WarmingUpServers()
+		}
+		c.JSON(http.StatusOK, r)
+	}
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// reloadConfigHandler reloads the config file of the server and applies the settings that can change at runtime.
+// The outcome is returned even if the config file is rejected, with the reasons in its errors.
+func (s *Server) reloadConfigHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.reloadConfig == nil {
+			respondError(c, newAPIError(
+				http.StatusServiceUnavailable, types.ErrorCodeUnavailable, "this server cannot reload its configuration",
+			))
+			return
+		}
+		c.JSON(http.StatusOK, s.reloadConfig())
+	}
+}
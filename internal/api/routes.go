@@ -0,0 +1,603 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// routeAccess is who is allowed to call an API route.
+type routeAccess int
+
+const (
+	// publicAccess routes can be called by anyone, even before the server is initialized.
+	publicAccess routeAccess = iota
+	// userAccess routes can be called by a standard user in enterprise mode or anyone in development mode.
+	userAccess
+	// adminAccess routes can only be called by an admin user in enterprise mode or anyone in development mode.
+	adminAccess
+	// mcpClientAccess routes are called like the MCP proxy: with the access token of an MCP client in enterprise mode,
+	// and by anyone in development mode. The MCP servers the client is allowed to access are checked by the proxy.
+	mcpClientAccess
+)
+
+// apiRoute describes an endpoint of the HTTP API.
+// The router and the OpenAPI document are both generated from these definitions, so that the document
+// always describes the API that is actually served.
+type apiRoute struct {
+	method string
+	// path is relative to the API prefix, in gin syntax, eg- /servers/:name
+	path    string
+	handler gin.HandlerFunc
+	access  routeAccess
+	// enterpriseOnly routes are rejected when the server runs in development mode
+	enterpriseOnly bool
+	// etagTables are the tables the response is read from, if set its ETag is computed from their versions
+	// and the route answers 304 Not Modified to a request whose If-None-Match header holds it.
+	etagTables []string
+	// etagState, if set, returns the version of the in-memory state the response also depends on,
+	// which is part of the ETag computed from etagTables.
+	etagState func() uint64
+	// streamed routes read or write bodies of any size as they go, the responses to their POST requests
+	// are not stored for idempotency.
+	streamed bool
+	doc      routeDoc
+}
+
+// routeDoc documents an API route in the OpenAPI document.
+type routeDoc struct {
+	operationID string
+	summary     string
+	description string
+	tag         string
+	query       []queryParam
+	// header documents the request headers the route reads, like query does for the query parameters.
+	header []queryParam
+	// request is a value of the type of the JSON request body (or a rawSchema), nil if the route takes no body.
+	request any
+	// requestType is the media type of the request body, application/json if empty.
+	requestType string
+	// response is a value of the type of the JSON response body, nil if the route responds without a body.
+	response any
+	// responseType is the media type of the response body, application/json if empty.
+	// For types.NDJSONContentType, response is the type of every line.
+	responseType string
+	// status is the status code of a successful response, 200 if not set.
+	status int
+	// ifMatch is true if the route only applies the request if the If-Match header matches the entity's ETag.
+	ifMatch bool
+	// pathUUID is true if the path parameter addressing the entity also accepts its UUID.
+	pathUUID bool
+	// ifNoneMatch is true if the route answers 304 Not Modified if the If-None-Match header matches the entity's ETag.
+	// It is implied by apiRoute.etagTables.
+	ifNoneMatch bool
+}
+
+// queryParam documents a query parameter of an API route.
+type queryParam struct {
+	name        string
+	description string
+	required    bool
+	// array is true if the parameter can be repeated
+	array bool
+	// schemaType is the JSON schema type of the parameter's value, string if empty
+	schemaType string
+}
+
+// toolFilterQueryParams documents the filters of the tools list.
+var toolFilterQueryParams = []queryParam{
+	{name: "server", description: "Only list the tools of this MCP server"},
+	{name: "group", description: "Only list the tools of this tool group"},
+	{name: "enabled", description: "Only list the enabled tools if true, the disabled ones if false", schemaType: "boolean"},
+	{name: "q", description: "Only list the tools whose name or description contains this string, ignoring case"},
+}
+
+// Tags grouping the operations of the OpenAPI document
+const (
+	tagServers    = "servers"
+	tagTools      = "tools"
+	tagPrompts    = "prompts"
+	tagToolGroups = "tool-groups"
+	tagClients    = "clients"
+	tagUsers      = "users"
+	tagWebhooks   = "webhooks"
+	tagMeta       = "meta"
+)
+
+// toolInvokeRequestSchema describes the body of a tool invocation: the name of the tool along with its arguments.
+var toolInvokeRequestSchema = rawSchema{
+	"type": "object",
+	"properties": map[string]any{
+		"name": map[string]any{"type": "string", "description": "Canonical name of the tool to invoke, eg- github__git_commit"},
+	},
+	"required":             []string{"name"},
+	"additionalProperties": map[string]any{"description": "Arguments passed to the tool"},
+}
+
+// entityQueryParam is the query parameter of the routes that enable or disable tools & prompts.
+var entityQueryParam = queryParam{
+	name:        "entity",
+	description: "Name of a tool or prompt, or of an MCP server to affect all of its tools or prompts",
+	required:    true,
+}
+
+// apiRoutes returns the authenticated routes of the HTTP API.
+func (s *Server) apiRoutes() []apiRoute {
+	return []apiRoute{
+		// MCP servers
+		{
+			method: http.MethodGet, path: "/servers", handler: s.listServersHandler(), access: userAccess, etagTables: []string{model.TableMcpServers},
+			etagState: s.serverStatesVersion,
+			doc:       routeDoc{operationID: "listServers", summary: "List registered MCP servers", tag: tagServers, query: pageQueryParams, response: types.Page[*types.McpServer]{}},
+		},
+		{
+			method: http.MethodPost, path: "/servers", handler: s.registerServerHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "registerServer", summary: "Register an MCP server", tag: tagServers,
+				description: "The server is connected to, and its tools and prompts are registered.",
+				request:     types.RegisterServerInput{}, response: model.McpServer{}, status: http.StatusCreated,
+			},
+		},
+		{
+			method: http.MethodPost, path: "/servers/bulk", handler: s.registerServersBulkHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "registerServersBulk", summary: "Register a batch of MCP servers", tag: tagServers,
+				description: "In atomic mode, every server is connected to first, then all of them are registered in a single transaction: " +
+					"if any of them fails, none are registered and the response has status 422. " +
+					"In best_effort mode, every server is registered independently and the response has status 207 if some of them failed. " +
+					"The response reports the outcome of every server of the batch.",
+				query: []queryParam{
+					{name: "mode", description: "atomic (the default) or best_effort"},
+					{
+						name:        "defer_validation",
+						description: "In atomic mode, register the servers without connecting to them first. Their tools are then registered on a best-effort basis.",
+						schemaType:  "boolean",
+					},
+				},
+				request: []types.RegisterServerInput{}, response: types.BulkRegistrationResult{}, status: http.StatusCreated,
+			},
+		},
+		{
+			method: http.MethodPost, path: "/servers/sync", handler: s.syncServersHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "syncServers", summary: "Synchronize the tools and prompts of MCP servers", tag: tagServers,
+				description: "The servers named in the body, or all of them if it is empty, are connected to concurrently " +
+					"and their tools and prompts are updated to match those they provide. Disabled tools and prompts remain disabled. " +
+					"A server that fails is left unchanged and doesn't affect the others, the response has status 207 if some of them failed.",
+				request: types.SyncServersInput{}, response: types.SyncServersResult{},
+			},
+		},
+		{
+			method: http.MethodGet, path: "/servers/health", handler: s.serversHealthHandler(), access: userAccess,
+			doc: routeDoc{
+				operationID: "getServersHealth", summary: "Get the health of the registered MCP servers", tag: tagServers,
+				description: "Reports the outcome of the last background health check of every server, without checking them. " +
+					"The status is healthy if no server failed its last check, critical if all the checked servers failed it and degraded otherwise. " +
+					"The response has status 503 when it is critical.",
+				query: []queryParam{
+					{
+						name:        "refresh",
+						description: "Check the servers before responding. Admin only, at most once every 10 seconds.",
+						schemaType:  "boolean",
+					},
+				},
+				response: types.ServersHealth{},
+			},
+		},
+		{
+			method: http.MethodDelete, path: "/servers/:name", handler: s.deregisterServerHandler(), access: adminAccess,
+			doc: routeDoc{operationID: "deregisterServer", summary: "Deregister an MCP server and its tools and prompts", tag: tagServers, pathUUID: true, status: http.StatusNoContent},
+		},
+		{
+			method: http.MethodGet, path: "/servers/:name", handler: s.getServerHandler(), access: userAccess,
+			doc: routeDoc{
+				operationID: "getServer", summary: "Get a registered MCP server", tag: tagServers, pathUUID: true,
+				description: "The response's ETag is the version of the server, to update it conditionally with If-Match.",
+				response:    types.McpServer{}, ifNoneMatch: true,
+			},
+		},
+		{
+			method: http.MethodPut, path: "/servers/:name", handler: s.updateServerHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "updateServer", summary: "Update the configuration of an MCP server", tag: tagServers, pathUUID: true,
+				description: "If its connection settings changed, the server is reconnected to and its tools and prompts are refreshed. Its name cannot be changed.",
+				request:     types.RegisterServerInput{}, response: types.McpServer{}, ifMatch: true,
+			},
+		},
+		{
+			method: http.MethodPatch, path: "/servers/:name", handler: s.patchServerHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "patchServer", summary: "Change some fields of the configuration of an MCP server", tag: tagServers,
+				description: "The body is a JSON merge patch (RFC 7386) of the server's configuration: fields set to null are cleared, " +
+					"fields that are left out keep their value. The patched configuration is validated like a complete one. " +
+					"If its connection settings changed, the server is reconnected to and its tools and prompts are refreshed.",
+				request: types.RegisterServerInput{}, response: types.McpServer{}, ifMatch: true, pathUUID: true,
+			},
+		},
+		{
+			method: http.MethodPost, path: "/servers/:name/enable", handler: s.enableServerHandler(), access: adminAccess,
+			doc: routeDoc{operationID: "enableServer", summary: "Enable all tools and prompts of an MCP server", tag: tagServers, pathUUID: true, response: types.EnableDisableServerResult{}},
+		},
+		{
+			method: http.MethodPost, path: "/servers/:name/disable", handler: s.disableServerHandler(), access: adminAccess,
+			doc: routeDoc{operationID: "disableServer", summary: "Disable all tools and prompts of an MCP server", tag: tagServers, pathUUID: true, response: types.EnableDisableServerResult{}},
+		},
+		{
+			// restricted to admins because it exposes sensitive information like bearer tokens
+			method: http.MethodGet, path: "/server_configs", handler: s.getServerConfigsHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "listServerConfigs", summary: "Get the complete configurations of all MCP servers", tag: tagServers,
+				description: "Unlike listServers, the configurations include secrets like bearer tokens.",
+				response:    []*types.RegisterServerInput{},
+			},
+		},
+		{
+			method: http.MethodGet, path: "/server_configs/:name", handler: s.getServerConfigHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "getServerConfig", summary: "Get the complete configuration of an MCP server", tag: tagServers,
+				description: "Unlike getServer, the configuration includes secrets like bearer tokens.",
+				pathUUID:    true, response: types.RegisterServerInput{},
+			},
+		},
+
+		// tools
+		{
+			method: http.MethodGet, path: "/tools", handler: s.listToolsHandler(), access: userAccess, etagTables: []string{model.TableTools, model.TableToolGroups},
+			doc: routeDoc{
+				operationID: "listTools", summary: "List tools", tag: tagTools,
+				description: "Filters are combined, only the tools matching all of them are listed. " +
+					"The response includes the total number of matching tools.",
+				query:    append(toolFilterQueryParams, pageQueryParams...),
+				response: types.Page[model.Tool]{},
+			},
+		},
+		{
+			method: http.MethodGet, path: "/tools/stream", handler: s.streamToolsHandler(), access: userAccess,
+			doc: routeDoc{
+				operationID: "streamTools", summary: "Stream all tools as newline-delimited JSON", tag: tagTools,
+				description: "Takes the same filters as the tools list, and writes every matching tool on its own line " +
+					"as it is read from the database, for dumps of the whole registry. " +
+					"If the stream fails after it started, its last line is an error response instead of a tool.",
+				query:        toolFilterQueryParams,
+				response:     model.Tool{},
+				responseType: types.NDJSONContentType,
+			},
+		},
+		{
+			method: http.MethodPost, path: "/tools/invoke", handler: s.invokeToolHandler(), access: userAccess,
+			doc: routeDoc{operationID: "invokeTool", summary: "Invoke a tool", tag: tagTools, request: toolInvokeRequestSchema, response: types.ToolInvokeResult{}},
+		},
+		{
+			method: http.MethodGet, path: "/tool", handler: s.getToolHandler(), access: userAccess, etagTables: []string{model.TableTools},
+			doc: routeDoc{
+				operationID: "getTool", summary: "Get a tool", tag: tagTools,
+				query:    []queryParam{{name: "name", description: "Canonical name or UUID of the tool", required: true}},
+				response: model.Tool{},
+			},
+		},
+		{
+			method: http.MethodPost, path: "/tools/enable", handler: s.enableToolsHandler(), access: adminAccess,
+			doc: routeDoc{operationID: "enableTools", summary: "Enable a tool, or all tools of an MCP server", tag: tagTools, query: []queryParam{entityQueryParam}, response: []string{}},
+		},
+		{
+			method: http.MethodPost, path: "/tools/disable", handler: s.disableToolsHandler(), access: adminAccess,
+			doc: routeDoc{operationID: "disableTools", summary: "Disable a tool, or all tools of an MCP server", tag: tagTools, query: []queryParam{entityQueryParam}, response: []string{}},
+		},
+
+		// prompts
+		{
+			method: http.MethodGet, path: "/prompts", handler: s.listPromptsHandler(), access: userAccess, etagTables: []string{model.TablePrompts},
+			doc: routeDoc{
+				operationID: "listPrompts", summary: "List prompts", tag: tagPrompts,
+				query:    []queryParam{{name: "server", description: "Only list the prompts of this MCP server"}},
+				response: []model.Prompt{},
+			},
+		},
+		{
+			method: http.MethodGet, path: "/prompt", handler: s.getPromptHandler(), access: userAccess, etagTables: []string{model.TablePrompts},
+			doc: routeDoc{
+				operationID: "getPrompt", summary: "Get a prompt", tag: tagPrompts,
+				query:    []queryParam{{name: "name", description: "Canonical name of the prompt", required: true}},
+				response: model.Prompt{},
+			},
+		},
+		{
+			method: http.MethodPost, path: "/prompts/render", handler: s.getPromptWithArgsHandler(), access: userAccess,
+			doc: routeDoc{operationID: "renderPrompt", summary: "Render a prompt with arguments", tag: tagPrompts, request: types.PromptGetRequest{}, response: types.PromptResult{}},
+		},
+		{
+			method: http.MethodPost, path: "/prompts/enable", handler: s.enablePromptsHandler(), access: adminAccess,
+			doc: routeDoc{operationID: "enablePrompts", summary: "Enable a prompt, or all prompts of an MCP server", tag: tagPrompts, query: []queryParam{entityQueryParam}, response: []string{}},
+		},
+		{
+			method: http.MethodPost, path: "/prompts/disable", handler: s.disablePromptsHandler(), access: adminAccess,
+			doc: routeDoc{operationID: "disablePrompts", summary: "Disable a prompt, or all prompts of an MCP server", tag: tagPrompts, query: []queryParam{entityQueryParam}, response: []string{}},
+		},
+
+		// MCP clients
+		{
+			method: http.MethodGet, path: "/clients", handler: s.listMcpClientsHandler(), access: adminAccess, enterpriseOnly: true,
+			doc: routeDoc{operationID: "listClients", summary: "List MCP clients", tag: tagClients, query: pageQueryParams, response: types.Page[*model.McpClient]{}},
+		},
+		{
+			method: http.MethodPost, path: "/clients", handler: s.createMcpClientHandler(), access: adminAccess, enterpriseOnly: true,
+			doc: routeDoc{
+				operationID: "createClient", summary: "Create an MCP client", tag: tagClients,
+				request: types.McpClient{}, response: model.McpClient{}, status: http.StatusCreated,
+			},
+		},
+		{
+			method: http.MethodPut, path: "/clients/:name", handler: s.updateMcpClientHandler(), access: adminAccess, enterpriseOnly: true,
+			doc: routeDoc{operationID: "updateClient", summary: "Update an MCP client", tag: tagClients, pathUUID: true, request: types.McpClient{}, response: model.McpClient{}},
+		},
+		{
+			method: http.MethodDelete, path: "/clients/:name", handler: s.deleteMcpClientHandler(), access: adminAccess, enterpriseOnly: true,
+			doc: routeDoc{operationID: "deleteClient", summary: "Delete an MCP client", tag: tagClients, pathUUID: true, status: http.StatusNoContent},
+		},
+
+		// users
+		{
+			method: http.MethodGet, path: "/users/whoami", handler: s.whoAmIHandler(), access: userAccess, enterpriseOnly: true,
+			doc: routeDoc{operationID: "whoAmI", summary: "Get the user making the request", tag: tagUsers, response: types.User{}},
+		},
+		{
+			method: http.MethodPost, path: "/users", handler: s.createUserHandler(), access: adminAccess, enterpriseOnly: true,
+			doc: routeDoc{
+				operationID: "createUser", summary: "Create a user", tag: tagUsers,
+				request: types.CreateOrUpdateUserRequest{}, response: types.CreateOrUpdateUserResponse{}, status: http.StatusCreated,
+			},
+		},
+		{
+			method: http.MethodGet, path: "/users", handler: s.listUsersHandler(), access: adminAccess, enterpriseOnly: true,
+			doc: routeDoc{operationID: "listUsers", summary: "List users", tag: tagUsers, query: pageQueryParams, response: types.Page[*types.User]{}},
+		},
+		{
+			method: http.MethodDelete, path: "/users/:username", handler: s.deleteUserHandler(), access: adminAccess, enterpriseOnly: true,
+			doc: routeDoc{operationID: "deleteUser", summary: "Delete a user", tag: tagUsers, pathUUID: true, status: http.StatusNoContent},
+		},
+		{
+			method: http.MethodPut, path: "/users/:username", handler: s.updateUserHandler(), access: adminAccess, enterpriseOnly: true,
+			doc: routeDoc{
+				operationID: "updateUser", summary: "Update a user", tag: tagUsers, pathUUID: true,
+				request: types.CreateOrUpdateUserRequest{}, response: types.CreateOrUpdateUserResponse{},
+			},
+		},
+
+		// tool groups
+		{
+			method: http.MethodPost, path: "/tool-groups", handler: s.createToolGroupHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "createToolGroup", summary: "Create a tool group", tag: tagToolGroups,
+				request: types.ToolGroup{}, response: types.CreateToolGroupResponse{}, status: http.StatusCreated,
+			},
+		},
+		{
+			method: http.MethodGet, path: "/tool-groups/:name", handler: s.getToolGroupHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "getToolGroup", summary: "Get a tool group", tag: tagToolGroups, pathUUID: true,
+				description: "The response's ETag is the version of the group, to update it conditionally with If-Match.",
+				response:    types.GetToolGroupResponse{}, ifNoneMatch: true,
+			},
+		},
+		{
+			method: http.MethodGet, path: "/tool-groups", handler: s.listToolGroupsHandler(), access: adminAccess, etagTables: []string{model.TableToolGroups},
+			doc: routeDoc{operationID: "listToolGroups", summary: "List tool groups", tag: tagToolGroups, query: pageQueryParams, response: types.Page[*types.ToolGroup]{}},
+		},
+		{
+			method: http.MethodDelete, path: "/tool-groups/:name", handler: s.deleteToolGroupHandler(), access: adminAccess,
+			doc: routeDoc{operationID: "deleteToolGroup", summary: "Delete a tool group", tag: tagToolGroups, pathUUID: true, status: http.StatusNoContent},
+		},
+		{
+			method: http.MethodPut, path: "/tool-groups/:name", handler: s.updateToolGroupHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "updateToolGroup", summary: "Update a tool group", tag: tagToolGroups, pathUUID: true,
+				request: types.ToolGroup{}, response: types.UpdateToolGroupResponse{}, ifMatch: true,
+			},
+		},
+		{
+			method: http.MethodPatch, path: "/tool-groups/:name", handler: s.patchToolGroupHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "patchToolGroup", summary: "Change some fields of a tool group", tag: tagToolGroups, pathUUID: true,
+				description: "The body is a JSON merge patch (RFC 7386) of the group's configuration: fields set to null are cleared, " +
+					"fields that are left out keep their value.",
+				request: types.ToolGroup{}, response: types.UpdateToolGroupResponse{}, ifMatch: true,
+			},
+		},
+		{
+			// resolves which tool groups reference a set of tools, eg- before deregistering the server providing them
+			method: http.MethodGet, path: "/tool-references", handler: s.getToolReferencesHandler(), access: adminAccess, etagTables: []string{model.TableTools, model.TableToolGroups},
+			doc: routeDoc{
+				operationID: "getToolReferences", summary: "Find the tool groups that reference tools", tag: tagToolGroups,
+				query: []queryParam{
+					{name: "server", description: "Check all the tools of this MCP server"},
+					{name: "tool", description: "Canonical name of a tool to check", array: true},
+				},
+				response: types.ToolReferences{},
+			},
+		},
+
+		// webhooks
+		{
+			method: http.MethodGet, path: "/webhooks", handler: s.listWebhooksHandler(), access: adminAccess,
+			doc: routeDoc{operationID: "listWebhooks", summary: "List webhooks", tag: tagWebhooks, query: pageQueryParams, response: types.Page[*types.Webhook]{}},
+		},
+		{
+			method: http.MethodPost, path: "/webhooks", handler: s.createWebhookHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "createWebhook", summary: "Subscribe a URL to registry events", tag: tagWebhooks,
+				description: "Events are delivered as JSON POST requests, signed with an HMAC-SHA256 of the body in the " +
+					types.WebhookSignatureHeader + " header. If no secret is given, one is generated. " +
+					"The response is the only one that includes the secret.",
+				request: types.Webhook{}, response: types.Webhook{}, status: http.StatusCreated,
+			},
+		},
+		{
+			method: http.MethodGet, path: "/webhooks/:name", handler: s.getWebhookHandler(), access: adminAccess,
+			doc: routeDoc{operationID: "getWebhook", summary: "Get a webhook", tag: tagWebhooks, response: types.Webhook{}},
+		},
+		{
+			method: http.MethodDelete, path: "/webhooks/:name", handler: s.deleteWebhookHandler(), access: adminAccess,
+			doc: routeDoc{operationID: "deleteWebhook", summary: "Delete a webhook and its delivery log", tag: tagWebhooks, status: http.StatusNoContent},
+		},
+		{
+			method: http.MethodPost, path: "/webhooks/:name/enable", handler: s.setWebhookEnabledHandler(true), access: adminAccess,
+			doc: routeDoc{
+				operationID: "enableWebhook", summary: "Enable a webhook", tag: tagWebhooks,
+				description: "Webhooks are disabled automatically when their deliveries keep failing. Enabling one resets its failure count.",
+				response:    types.Webhook{},
+			},
+		},
+		{
+			method: http.MethodPost, path: "/webhooks/:name/disable", handler: s.setWebhookEnabledHandler(false), access: adminAccess,
+			doc: routeDoc{operationID: "disableWebhook", summary: "Disable a webhook", tag: tagWebhooks, response: types.Webhook{}},
+		},
+		{
+			method: http.MethodPost, path: "/webhooks/:name/test", handler: s.testWebhookHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "testWebhook", summary: "Send a test event to a webhook", tag: tagWebhooks,
+				description: "The event is sent once, right away, even if the webhook is disabled. The response is the outcome of the delivery.",
+				response:    types.WebhookDelivery{},
+			},
+		},
+		{
+			method: http.MethodGet, path: "/webhooks/:name/deliveries", handler: s.listWebhookDeliveriesHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "listWebhookDeliveries", summary: "List the latest deliveries of a webhook", tag: tagWebhooks,
+				description: "Deliveries are listed oldest first. Only the latest 100 deliveries of every webhook are kept.",
+				query:       pageQueryParams, response: types.Page[types.WebhookDelivery]{},
+			},
+		},
+
+		// the server itself
+		{
+			method: http.MethodGet, path: "/server/info", handler: s.serverInfoHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "getServerInfo", summary: "Describe the server that answers the request", tag: tagMeta,
+				description: "Returns the version and mode of the server, when it started and its role among the replicas " +
+					"sharing its database: the leader runs the background jobs, eg- the health checks of MCP servers, " +
+					"the followers only serve requests. The leader is named along with when its lease expires.",
+				response: types.ServerInfo{},
+			},
+		},
+		{
+			method: http.MethodPost, path: "/server/reload", handler: s.reloadConfigHandler(), access: adminAccess,
+			doc: routeDoc{
+				operationID: "reloadServerConfig", summary: "Reload the config file of the server", tag: tagMeta,
+				description: "Reads the config file of the server again and applies the changed settings that can change at runtime: " +
+					"the API rate limit, the health check interval, the maximum size of tool results, the public URL and the shutdown timeout. " +
+					"The other changed settings are reported as skipped, they take effect once the server is restarted. " +
+					"If the config file can't be read or holds an invalid value, it is rejected as a whole and the reasons are listed in errors. " +
+					"Sending SIGHUP to the server process does the same.",
+				response: types.ConfigReloadResult{},
+			},
+		},
+		{
+			method: http.MethodPost, path: "/backup", handler: s.backupHandler(), access: adminAccess, streamed: true,
+			doc: routeDoc{
+				operationID: "backupRegistry", summary: "Back up the registry", tag: tagMeta,
+				description: "Streams a consistent backup of the registry: its MCP servers, tools, prompts, tool groups, " +
+					"MCP clients, users and webhooks, along with the delivery log of the webhooks if include_audit is true. " +
+					"The backup is a gzip compressed stream of JSON lines, starting with a header that describes it. " +
+					"The credentials it holds are encrypted with a key derived from the passphrase, which is needed to restore it. " +
+					"A backup that failed after it started ends with a record holding the error instead of the end record.",
+				request:      types.BackupRequest{},
+				response:     rawSchema{"type": "string", "format": "binary"},
+				responseType: types.BackupContentType,
+			},
+		},
+		{
+			method: http.MethodPost, path: "/restore", handler: s.restoreHandler(), access: adminAccess, streamed: true,
+			doc: routeDoc{
+				operationID: "restoreRegistry", summary: "Restore a backup of the registry", tag: tagMeta,
+				description: "Restores the backup sent as the request body into the registry, as a whole or not at all. " +
+					"The registry must not have any entity unless force is true, in which case they are replaced by the backup. " +
+					"The users are always replaced by the ones of the backup. " +
+					"The backup must have been made by a server with the same schema version and mode, " +
+					"it is rejected with status 422 otherwise. " +
+					"The server keeps serving the entities it loaded until it is restarted.",
+				query: []queryParam{
+					{name: "force", description: "Replace the entities of a registry that is not empty", schemaType: "boolean"},
+				},
+				header: []queryParam{
+					{name: types.BackupPassphraseHeader, description: "The passphrase the backup was made with", required: true},
+				},
+				request:     rawSchema{"type": "string", "format": "binary"},
+				requestType: types.BackupContentType,
+				response:    types.RestoreResult{},
+			},
+		},
+	}
+}
+
+// publicAPIRoutes returns the routes of the HTTP API that don't require authentication.
+// They are only served under /api/v1.
+func (s *Server) publicAPIRoutes() []apiRoute {
+	return []apiRoute{
+		{
+			// version info is public so that any client can check compatibility before authenticating
+			method: http.MethodGet, path: "/version", handler: versionHandler, access: publicAccess,
+			doc: routeDoc{operationID: "getVersion", summary: "Get the version of the server", tag: tagMeta, response: types.ServerVersion{}},
+		},
+		{
+			method: http.MethodGet, path: "/openapi.json", handler: s.openAPIHandler(), access: publicAccess,
+			doc: routeDoc{operationID: "getOpenAPIDocument", summary: "Get this OpenAPI document", tag: tagMeta, response: rawSchema{"type": "object"}},
+		},
+	}
+}
+
+// mcpClientAPIRoutes returns the routes of the HTTP API that MCP clients call with their own access token.
+// They are served with the same authentication as the MCP proxy, see checkAuthForMcpProxyAccess.
+func (s *Server) mcpClientAPIRoutes() []apiRoute {
+	return []apiRoute{
+		{
+			method: http.MethodPost, path: "/tools/:name/invoke", handler: s.callToolHandler(), access: mcpClientAccess,
+			doc: routeDoc{
+				operationID: "callTool", summary: "Call a tool through the MCP proxy", tag: tagTools,
+				description: "Calls the tool exactly like an MCP client connected to /mcp would, for callers that don't speak MCP. " +
+					"The body holds the arguments of the tool. " +
+					"With an Accept: text/event-stream header, the response is a stream of server-sent events instead: " +
+					"the connection is kept alive with comments while the tool runs, then every content block of the result is sent " +
+					"in a content event, followed by a result event with the rest of the result, or an error event if the call failed.",
+				request:  rawSchema{"type": "object", "additionalProperties": true},
+				response: types.ToolInvokeResult{},
+			},
+		},
+	}
+}
+
+// registerAPIRoutes adds routes to an API router group, along with the middleware that enforces their access rules.
+func (s *Server) registerAPIRoutes(g *gin.RouterGroup, routes []apiRoute) {
+	requireEnterpriseMode := s.requireServerMode(model.ModeEnterprise)
+	for _, r := range routes {
+		var handlers []gin.HandlerFunc
+		if r.access == adminAccess {
+			handlers = append(handlers, s.requireAdminUser())
+		}
+		if r.enterpriseOnly {
+			handlers = append(handlers, requireEnterpriseMode)
+		}
+		if len(r.etagTables) > 0 {
+			handlers = append(handlers, s.conditionalOnTables(r.etagTables, r.etagState))
+		}
+		if r.method == http.MethodPost && !r.streamed {
+			handlers = append(handlers, s.idempotent())
+		}
+		handlers = append(handlers, r.handler)
+		g.Handle(r.method, r.path, handlers...)
+	}
+}
+
+// deprecatedAPI marks the responses of a deprecated API version, pointing clients to the successor version.
+// Errors are reported in the format of the deprecated version, with their message as the "error" field.
+func deprecatedAPI(prefix, successorPrefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		successor := successorPrefix + strings.TrimPrefix(c.Request.URL.Path, prefix)
+		c.Set(legacyErrorsKey, true)
+		c.Header("Deprecation", "true")
+		c.Header("Link", "<"+successor+`>; rel="successor-version"`)
+		c.Next()
+	}
+}
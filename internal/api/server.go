@@ -2,18 +2,26 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/db"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/backup"
 	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	"github.com/mcpjungle/mcpjungle/internal/service/idempotency"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcpclient"
+	"github.com/mcpjungle/mcpjungle/internal/service/replica"
 	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
 	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
 	"github.com/mcpjungle/mcpjungle/internal/telemetry"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/mcpjungle/mcpjungle/pkg/version"
@@ -24,6 +32,9 @@ import (
 const (
 	V0PathPrefix    = "/v0"
 	V0ApiPathPrefix = "/api" + V0PathPrefix
+
+	V1PathPrefix    = "/v1"
+	V1ApiPathPrefix = "/api" + V1PathPrefix
 )
 
 type ServerOptions struct {
@@ -42,6 +53,33 @@ type ServerOptions struct {
 	ConfigService    *config.ServerConfigService
 	UserService      *user.UserService
 	ToolGroupService *toolgroup.ToolGroupService
+	// WebhookService delivers the registry's lifecycle events to webhooks.
+	// If nil, the webhooks API is unavailable and no events are delivered.
+	WebhookService *webhook.WebhookService
+	// BackupService backs up the registry and restores its backups.
+	// If nil, the backup and restore API is unavailable.
+	BackupService *backup.BackupService
+	// TableVersions are the versions of the tables the list endpoints read from, returned as the ETag of lists.
+	// If nil, lists are served without an ETag.
+	TableVersions *db.TableVersions
+	// APIRateLimit limits the number of API requests every caller can make.
+	// If nil, API requests are not rate limited.
+	APIRateLimit *RateLimit
+	// RateLimitWindows counts the API requests of the callers in the database, so that the replicas sharing it
+	// enforce APIRateLimit together. If nil, every replica counts the requests it serves in memory.
+	RateLimitWindows *db.RateLimitWindows
+	// IdempotencyService stores the responses to POST requests that carry an Idempotency-Key header.
+	// If nil, the header is ignored.
+	IdempotencyService *idempotency.IdempotencyService
+	// Replica takes part in the election of the leader among the servers sharing the database.
+	// If nil, the server is reported as the only replica, ie- the leader.
+	Replica *replica.Coordinator
+	// CORS is the policy for cross-origin requests from browsers.
+	// If nil, no cross-origin request is allowed.
+	CORS *CORSPolicy
+	// ReloadConfig reloads the config file of the server and applies the settings that can change at runtime,
+	// it is called by POST /api/v1/server/reload. If nil, the endpoint is unavailable.
+	ReloadConfig func() *types.ConfigReloadResult
 
 	OtelProviders *telemetry.Providers
 	Metrics       telemetry.CustomMetrics
@@ -60,6 +98,36 @@ type Server struct {
 	configService    *config.ServerConfigService
 	userService      *user.UserService
 	toolGroupService *toolgroup.ToolGroupService
+	webhookService   *webhook.WebhookService
+	backupService    *backup.BackupService
+
+	tableVersions *db.TableVersions
+	// rateLimiter is nil if API requests are not rate limited, it is replaced by SetAPIRateLimit
+	rateLimiter      atomic.Pointer[rateLimiter]
+	rateLimitWindows *db.RateLimitWindows
+	corsPolicy       *corsPolicy
+	// reloadConfig is nil if the server can't reload its configuration
+	reloadConfig func() *types.ConfigReloadResult
+
+	idempotencyService *idempotency.IdempotencyService
+	// idempotencyInFlight holds the idempotency keys of the requests being processed, to reject concurrent duplicates.
+	idempotencyInFlight sync.Map
+
+	replica *replica.Coordinator
+	// startedAt is when the server was created, reported by GET /server/info
+	startedAt time.Time
+
+	healthRefreshMu sync.Mutex
+	// lastHealthRefresh is when the health of MCP servers was last checked on demand, to rate limit those checks.
+	lastHealthRefresh time.Time
+
+	// draining is set once the server started to shut down, see Shutdown.
+	draining         atomic.Bool
+	inFlightRequests atomic.Int64
+	// streamsCtx is the base context of the requests served by NewHTTPServer, closeStreams ends it
+	// to close the long-lived MCP sessions once the server drained.
+	streamsCtx   context.Context
+	closeStreams context.CancelFunc
 
 	otelProviders *telemetry.Providers
 	metrics       telemetry.CustomMetrics
@@ -73,15 +141,32 @@ type Server struct {
 // NewServer initializes a new Gin server for MCPJungle registry and MCP proxy
 func NewServer(opts *ServerOptions) (*Server, error) {
 	s := &Server{
-		mcpProxyServer:    opts.MCPProxyServer,
-		sseMcpProxyServer: opts.SseMcpProxyServer,
-		mcpService:        opts.MCPService,
-		mcpClientService:  opts.MCPClientService,
-		configService:     opts.ConfigService,
-		userService:       opts.UserService,
-		toolGroupService:  opts.ToolGroupService,
-		otelProviders:     opts.OtelProviders,
-		metrics:           opts.Metrics,
+		mcpProxyServer:     opts.MCPProxyServer,
+		sseMcpProxyServer:  opts.SseMcpProxyServer,
+		mcpService:         opts.MCPService,
+		mcpClientService:   opts.MCPClientService,
+		configService:      opts.ConfigService,
+		userService:        opts.UserService,
+		toolGroupService:   opts.ToolGroupService,
+		webhookService:     opts.WebhookService,
+		backupService:      opts.BackupService,
+		tableVersions:      opts.TableVersions,
+		rateLimitWindows:   opts.RateLimitWindows,
+		idempotencyService: opts.IdempotencyService,
+		replica:            opts.Replica,
+		startedAt:          time.Now().UTC(),
+		reloadConfig:       opts.ReloadConfig,
+		otelProviders:      opts.OtelProviders,
+		metrics:            opts.Metrics,
+	}
+	s.streamsCtx, s.closeStreams = context.WithCancel(context.Background())
+	s.SetAPIRateLimit(opts.APIRateLimit)
+	if opts.CORS != nil {
+		p, err := newCORSPolicy(*opts.CORS)
+		if err != nil {
+			return nil, err
+		}
+		s.corsPolicy = p
 	}
 
 	// Set up the router after the server is fully initialized
@@ -140,6 +225,10 @@ func (s *Server) setupRouter() (*gin.Engine, error) {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.Default()
 
+	// CORS runs before everything else, so that preflight requests are answered without authentication
+	r.Use(s.cors())
+	r.Use(s.trackInFlight())
+
 	// if otel is enabled, setup prometheus metrics endpoint
 	if s.otelProviders != nil && s.otelProviders.IsEnabled() {
 		// instrument gin
@@ -156,6 +245,8 @@ func (s *Server) setupRouter() (*gin.Engine, error) {
 		},
 	)
 
+	r.GET("/ready", s.readinessHandler())
+
 	r.GET(
 		"/metadata",
 		func(c *gin.Context) {
@@ -168,15 +259,17 @@ func (s *Server) setupRouter() (*gin.Engine, error) {
 
 	r.POST("/init", s.registerInitServerHandler())
 
-	requireEnterpriseMode := s.requireServerMode(model.ModeEnterprise)
-
 	// Set up the MCP proxy server on /mcp
 	streamableHTTPServer := server.NewStreamableHTTPServer(s.mcpProxyServer)
 	r.Any(
 		"/mcp",
 		s.requireInitialized(),
 		s.checkAuthForMcpProxyAccess(),
-		gin.WrapH(streamableHTTPServer),
+		func(c *gin.Context) {
+			if !s.serveCachedToolsList(c, "", s.mcpProxyServer) {
+				streamableHTTPServer.ServeHTTP(c.Writer, c.Request)
+			}
+		},
 	)
 
 	r.Any(
@@ -214,99 +307,44 @@ func (s *Server) setupRouter() (*gin.Engine, error) {
 		s.toolGroupSseMCPServerCallMessageHandler(),
 	)
 
-	// Setup /v0 API endpoints
+	// Setup /api/v1 endpoints, the public ones don't require the server to be initialized
+	apiV1Public := r.Group(V1ApiPathPrefix)
+	s.registerAPIRoutes(apiV1Public, s.publicAPIRoutes())
+	apiV1 := r.Group(
+		V1ApiPathPrefix,
+		s.requireInitialized(),
+		s.verifyUserAuthForAPIAccess(),
+		s.rateLimitAPI(),
+	)
+	s.registerAPIRoutes(apiV1, s.apiRoutes())
+	apiV1MCPClients := r.Group(
+		V1ApiPathPrefix,
+		s.requireInitialized(),
+		s.checkAuthForMcpProxyAccess(),
+		s.rateLimitAPI(),
+	)
+	s.registerAPIRoutes(apiV1MCPClients, s.mcpClientAPIRoutes())
+
+	// /api/v0 is a deprecated alias of /api/v1, kept so that older clients keep working for one more release
 	apiV0 := r.Group(
 		V0ApiPathPrefix,
+		deprecatedAPI(V0ApiPathPrefix, V1ApiPathPrefix),
 		s.requireInitialized(),
 		s.verifyUserAuthForAPIAccess(),
+		s.rateLimitAPI(),
 	)
-
-	// endpoints accessible by a standard user in enterprise mode or anyone in development mode
-	userAPI := apiV0.Group("/")
-	{
-		userAPI.GET("/servers", s.listServersHandler())
-
-		userAPI.GET("/tools", s.listToolsHandler())
-		userAPI.POST("/tools/invoke", s.invokeToolHandler())
-		userAPI.GET("/tool", s.getToolHandler())
-
-		// Prompt endpoints
-		userAPI.GET("/prompts", s.listPromptsHandler())
-		userAPI.GET("/prompt", s.getPromptHandler())
-		userAPI.POST("/prompts/render", s.getPromptWithArgsHandler())
-
-		userAPI.GET("/users/whoami", requireEnterpriseMode, s.whoAmIHandler())
-	}
-
-	// endpoints only accessible by an admin user in enterprise mode or anyone in development mode
-	adminAPI := apiV0.Group("/", s.requireAdminUser())
-	{
-		adminAPI.POST("/servers", s.registerServerHandler())
-		adminAPI.DELETE("/servers/:name", s.deregisterServerHandler())
-		adminAPI.POST("/servers/:name/enable", s.enableServerHandler())
-		adminAPI.POST("/servers/:name/disable", s.disableServerHandler())
-
-		// this endpoint is restricted to admins only because it can potentially expose sensitive information
-		// like bearer tokens.
-		adminAPI.GET("/server_configs", s.getServerConfigsHandler())
-
-		adminAPI.POST("/tools/enable", s.enableToolsHandler())
-		adminAPI.POST("/tools/disable", s.disableToolsHandler())
-
-		adminAPI.POST("/prompts/enable", s.enablePromptsHandler())
-		adminAPI.POST("/prompts/disable", s.disablePromptsHandler())
-
-		// endpoints for managing MCP clients (enterprise mode only)
-		adminAPI.GET(
-			"/clients",
-			requireEnterpriseMode,
-			s.listMcpClientsHandler(),
-		)
-		adminAPI.POST(
-			"/clients",
-			requireEnterpriseMode,
-			s.createMcpClientHandler(),
-		)
-		adminAPI.PUT(
-			"/clients/:name",
-			requireEnterpriseMode,
-			s.updateMcpClientHandler(),
-		)
-		adminAPI.DELETE(
-			"/clients/:name",
-			requireEnterpriseMode,
-			s.deleteMcpClientHandler(),
-		)
-
-		// endpoints for managing human users (enterprise mode only)
-		adminAPI.POST(
-			"/users",
-			requireEnterpriseMode,
-			s.createUserHandler(),
-		)
-		adminAPI.GET(
-			"/users",
-			requireEnterpriseMode,
-			s.listUsersHandler(),
-		)
-		adminAPI.DELETE(
-			"/users/:username",
-			requireEnterpriseMode,
-			s.deleteUserHandler(),
-		)
-		adminAPI.PUT(
-			"/users/:username",
-			requireEnterpriseMode,
-			s.updateUserHandler(),
-		)
-
-		// endpoints for managing tool groups
-		adminAPI.POST("/tool-groups", s.createToolGroupHandler())
-		adminAPI.GET("/tool-groups/:name", s.getToolGroupHandler())
-		adminAPI.GET("/tool-groups", s.listToolGroupsHandler())
-		adminAPI.DELETE("/tool-groups/:name", s.deleteToolGroupHandler())
-		adminAPI.PUT("/tool-groups/:name", s.updateToolGroupHandler())
-	}
+	s.registerAPIRoutes(apiV0, s.apiRoutes())
 
 	return r, nil
 }
+
+// versionHandler returns the version of the server.
+func versionHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, &types.ServerVersion{
+		Version:          version.GetVersion(),
+		Commit:           version.GetCommit(),
+		BuildDate:        version.GetBuildDate(),
+		MinClientVersion: version.MinClientVersion,
+		Features:         []string{types.FeatureBulkRegistration},
+	})
+}
@@ -1,10 +1,12 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
 func (s *Server) registerInitServerHandler() gin.HandlerFunc {
@@ -13,16 +15,16 @@ func (s *Server) registerInitServerHandler() gin.HandlerFunc {
 			Mode model.ServerMode `json:"mode" binding:"required,oneof=development enterprise production"`
 		}
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			respondError(c, invalidRequest("Invalid request body: %v", err))
 			return
 		}
 		ok, err := s.configService.Init(req.Mode)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to initialize server: " + err.Error()})
+			respondError(c, fmt.Errorf("Failed to initialize server: %w", err))
 			return
 		}
 		if !ok {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Server is already initialized"})
+			respondError(c, newAPIError(http.StatusBadRequest, types.ErrorCodeAlreadyExists, "Server is already initialized"))
 			return
 		}
 		if req.Mode == model.ModeDev {
@@ -35,10 +37,7 @@ func (s *Server) registerInitServerHandler() gin.HandlerFunc {
 		// create an admin user and return its access token
 		admin, err := s.userService.CreateAdminUser()
 		if err != nil {
-			c.JSON(
-				http.StatusInternalServerError,
-				gin.H{"error": "Initialization succeeded but failed to create admin user: " + err.Error()},
-			)
+			respondError(c, fmt.Errorf("Initialization succeeded but failed to create admin user: %w", err))
 			return
 		}
 		payload := gin.H{
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/mcpjungle/mcpjungle/pkg/version"
+)
+
+// serverInfoHandler describes the server that answers the request, among the replicas sharing its database.
+func (s *Server) serverInfoHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg, err := s.configService.GetConfig()
+		if err != nil {
+			respondError(c, fmt.Errorf("failed to get server config: %w", err))
+			return
+		}
+		info := &types.ServerInfo{
+			Version:   version.GetVersion(),
+			Mode:      string(cfg.Mode),
+			StartedAt: s.startedAt,
+			// without a coordinator, this server is the only replica
+			Replica: types.ReplicaInfo{Role: types.ReplicaLeader},
+		}
+		if s.replica != nil {
+			info.Replica = s.replica.Info()
+		}
+		c.JSON(http.StatusOK, info)
+	}
+}
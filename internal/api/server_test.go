@@ -1,13 +1,18 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/config"
 	"github.com/mcpjungle/mcpjungle/internal/telemetry"
 	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/mcpjungle/mcpjungle/pkg/version"
 )
 
 func TestNewServer(t *testing.T) {
@@ -60,3 +65,64 @@ func TestRouterSetup(t *testing.T) {
 	router.ServeHTTP(w, req)
 	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
 }
+
+func TestVersionEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	server, err := NewServer(&ServerOptions{})
+	testhelpers.AssertNoError(t, err)
+	router, err := server.setupRouter()
+	testhelpers.AssertNoError(t, err)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, V1ApiPathPrefix+"/version", nil)
+	router.ServeHTTP(w, req)
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+
+	var v types.ServerVersion
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &v))
+	testhelpers.AssertEqual(t, version.GetVersion(), v.Version)
+	testhelpers.AssertEqual(t, version.MinClientVersion, v.MinClientVersion)
+}
+
+func TestReadinessEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(&model.ServerConfig{}))
+	configService := config.NewServerConfigService(db)
+
+	server, err := NewServer(&ServerOptions{ConfigService: configService})
+	testhelpers.AssertNoError(t, err)
+
+	getReadiness := func() (int, types.ServerReadiness) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/ready", nil)
+		server.Router().ServeHTTP(w, req)
+		var r types.ServerReadiness
+		testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &r))
+		return w.Code, r
+	}
+
+	code, r := getReadiness()
+	testhelpers.AssertEqual(t, http.StatusOK, code)
+	testhelpers.AssertTrue(t, r.Ready, "server should be ready")
+	testhelpers.AssertFalse(t, r.Initialized, "server should not be initialized yet")
+	testhelpers.AssertEqual(t, "ok", r.Database)
+
+	_, err = configService.Init(model.ModeEnterprise)
+	testhelpers.AssertNoError(t, err)
+	_, r = getReadiness()
+	testhelpers.AssertTrue(t, r.Initialized, "server should be initialized")
+	testhelpers.AssertEqual(t, string(model.ModeEnterprise), r.Mode)
+
+	sqlDB, err := db.DB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, sqlDB.Close())
+	code, r = getReadiness()
+	testhelpers.AssertEqual(t, http.StatusServiceUnavailable, code)
+	testhelpers.AssertFalse(t, r.Ready, "server must not be ready without a database")
+	// the error of the database isn't disclosed to the unauthenticated callers
+	testhelpers.AssertEqual(t, types.DatabaseUnavailable, r.Database)
+}
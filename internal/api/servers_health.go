@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// healthRefreshInterval is the minimum time between two health checks requested with ?refresh=true,
+// so that the API can't be used to flood the upstream MCP servers.
+const healthRefreshInterval = 10 * time.Second
+
+// serversHealthHandler reports the health of the registered MCP servers, as of their last background health check.
+// Admins can pass ?refresh=true to check the servers before responding.
+// It responds with 503 if the health is critical so that load balancers can take the registry out of rotation.
+func (s *Server) serversHealthHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		refresh := false
+		if v, ok := c.GetQuery("refresh"); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				invalidParam(c, "refresh", "must be true or false")
+				return
+			}
+			refresh = b
+		}
+
+		if refresh {
+			if !isAdmin(c) {
+				respondError(c, roleRequired(types.UserRoleAdmin, "only admins can refresh the health of MCP servers"))
+				return
+			}
+			if wait, ok := s.takeHealthRefresh(); !ok {
+				retryAfter := max(int((wait+time.Second-1)/time.Second), 1)
+				c.Header("Retry-After", strconv.Itoa(retryAfter))
+				respondError(c, newAPIError(
+					http.StatusTooManyRequests, types.ErrorCodeRateLimited,
+					"the health of MCP servers can be refreshed once every %s, retry in %d seconds", healthRefreshInterval, retryAfter,
+				).with("retry_after", retryAfter))
+				return
+			}
+			if err := s.mcpService.CheckServersHealth(c.Request.Context()); err != nil {
+				respondError(c, err)
+				return
+			}
+		}
+
+		health, err := s.mcpService.ServersHealth()
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		status := http.StatusOK
+		if health.Status == types.HealthCritical {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, health)
+	}
+}
+
+// takeHealthRefresh reports whether a health refresh can start now, and otherwise how long until the next one can.
+func (s *Server) takeHealthRefresh() (time.Duration, bool) {
+	s.healthRefreshMu.Lock()
+	defer s.healthRefreshMu.Unlock()
+	now := time.Now()
+	if next := s.lastHealthRefresh.Add(healthRefreshInterval); now.Before(next) {
+		return next.Sub(now), false
+	}
+	s.lastHealthRefresh = now
+	return 0, true
+}
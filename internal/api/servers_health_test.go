@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestServersHealthHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	upstream := server.NewMCPServer("github", "0.0.0")
+	upstream.AddTool(echoTool("git_commit"))
+	upstreamServer := server.NewTestStreamableHTTPServer(upstream)
+
+	s := newGRPCTestServer(t, model.ModeEnterprise)
+	github, err := model.NewStreamableHTTPServer("github", "", upstreamServer.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, s.mcpService.RegisterMcpServer(context.Background(), github))
+	upstreamServer.Close()
+
+	admin := &model.User{Username: "admin", Role: types.UserRoleAdmin}
+	alice := &model.User{Username: "alice", Role: types.UserRoleUser}
+	get := func(u *model.User, query string) (*httptest.ResponseRecorder, types.ServersHealth) {
+		t.Helper()
+		router := gin.New()
+		router.GET("/servers/health", func(c *gin.Context) {
+			c.Set("mode", model.ModeEnterprise)
+			c.Set("user", u)
+		}, s.serversHealthHandler())
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/servers/health"+query, nil)
+		router.ServeHTTP(w, req)
+		var health types.ServersHealth
+		_ = json.Unmarshal(w.Body.Bytes(), &health)
+		return w, health
+	}
+
+	// the server wasn't checked yet
+	w, health := get(alice, "")
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	testhelpers.AssertEqual(t, types.ServerHealthUnknown, health.Servers[0].Status)
+
+	w, _ = get(alice, "?refresh=true")
+	testhelpers.AssertEqual(t, http.StatusForbidden, w.Code)
+	w, _ = get(admin, "?refresh=maybe")
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+
+	w, health = get(admin, "?refresh=true")
+	testhelpers.AssertEqual(t, http.StatusServiceUnavailable, w.Code)
+	testhelpers.AssertEqual(t, types.HealthCritical, health.Status)
+	testhelpers.AssertEqual(t, types.ServerUnhealthy, health.Servers[0].Status)
+	testhelpers.AssertEqual(t, 1, health.Servers[0].ConsecutiveFailures)
+
+	// refreshes are rate limited
+	w, _ = get(admin, "?refresh=true")
+	testhelpers.AssertEqual(t, http.StatusTooManyRequests, w.Code)
+	testhelpers.AssertTrue(t, w.Header().Get("Retry-After") != "", "the response should tell when to retry")
+
+	// everyone sees the outcome of the last check
+	w, health = get(alice, "")
+	testhelpers.AssertEqual(t, http.StatusServiceUnavailable, w.Code)
+	testhelpers.AssertEqual(t, types.ServerUnhealthy, health.Servers[0].Status)
+}
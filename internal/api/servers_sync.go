@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// syncServersHandler synchronizes the tools and prompts of the MCP servers named in the body, all of them if the
+// body is empty. It responds with 207 if some of the servers could not be synchronized.
+func (s *Server) syncServersHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.SyncServersInput
+		if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+			respondError(c, invalidRequest("invalid request body: %v", err))
+			return
+		}
+
+		result, err := s.SyncServers(c.Request.Context(), input.Servers, true)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		status := http.StatusOK
+		if result.Failed > 0 {
+			status = http.StatusMultiStatus
+		}
+		c.JSON(status, result)
+	}
+}
+
+// SyncServers synchronizes the tools and prompts of the MCP servers with the given names, all of them if names
+// is empty, and notifies the webhooks of the servers whose tools changed.
+// Unless startLazy is true, the lazily started servers that are not running are skipped.
+func (s *Server) SyncServers(ctx context.Context, names []string, startLazy bool) (*types.SyncServersResult, error) {
+	result, err := s.mcpService.SyncServers(ctx, names, startLazy)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range result.Servers {
+		if len(r.ToolsAdded) > 0 || len(r.ToolsRemoved) > 0 {
+			s.webhookService.Publish(
+				types.EventToolSyncChanged,
+				types.ToolSyncChange{Server: r.Name, Added: r.ToolsAdded, Removed: r.ToolsRemoved},
+			)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// drainPollInterval is how often Shutdown checks whether the requests in flight completed.
+const drainPollInterval = 50 * time.Millisecond
+
+// shutdownNotification is the MCP logging notification sent to the clients of long-lived MCP sessions
+// when the server shuts down, so that they know to reconnect rather than report a random failure.
+var shutdownNotification = map[string]any{
+	"level":  "warning",
+	"logger": "mcpjungle",
+	"data":   "the MCPJungle server is shutting down, reconnect to continue",
+}
+
+// NewHTTPServer returns an HTTP server listening on addr that serves s.
+// Use Shutdown to stop it, so that the long-lived MCP sessions it serves are closed once the server drained.
+func (s *Server) NewHTTPServer(addr string) *http.Server {
+	return &http.Server{
+		Addr:        addr,
+		Handler:     s.router,
+		BaseContext: func(net.Listener) context.Context { return s.streamsCtx },
+	}
+}
+
+// Shutdown gracefully stops httpServer, which must have been created by NewHTTPServer.
+// It stops accepting connections and reports the server as unready right away,
+// then lets the requests and the tool calls in flight complete until ctx is done.
+// The calls waiting to be proxied to the MCP servers fail right away, instead of being made while the server drains.
+// Finally, it tells the clients of long-lived MCP sessions that the server is going away and closes their streams.
+// It returns ctx's error if the requests were canceled before they completed.
+func (s *Server) Shutdown(ctx context.Context, httpServer *http.Server) error {
+	s.draining.Store(true)
+	s.mcpService.DrainUpstreamCalls()
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- httpServer.Shutdown(ctx) }()
+
+	drainErr := s.waitForInFlight(ctx)
+	s.mcpProxyServer.SendNotificationToAllClients("notifications/message", shutdownNotification)
+	s.sseMcpProxyServer.SendNotificationToAllClients("notifications/message", shutdownNotification)
+	if s.toolGroupService != nil {
+		s.toolGroupService.SendNotificationToAllClients("notifications/message", shutdownNotification)
+	}
+	// MCP sessions' streams never end by themselves, ending their context is what makes the HTTP server idle
+	s.closeStreams()
+
+	if err := <-stopped; err != nil {
+		_ = httpServer.Close()
+		return errors.Join(drainErr, err)
+	}
+	return drainErr
+}
+
+// waitForInFlight waits until no request, nor any tool call, is in flight, or until ctx is done.
+// Tool calls are waited for separately, because the result of those made over SSE is sent after their request returned.
+func (s *Server) waitForInFlight(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		requests, calls := s.inFlightRequests.Load(), s.mcpService.InFlightToolCalls()
+		if requests == 0 && calls == 0 {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Printf("[server] %d requests and %d tool calls still in flight were canceled\n", requests, calls)
+			return ctx.Err()
+		}
+	}
+}
+
+// trackInFlight counts the requests in flight, for Shutdown to wait for.
+// The streams of MCP sessions are left out, they only end when the server closes them.
+func (s *Server) trackInFlight() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet && isMCPProxyPath(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+		s.inFlightRequests.Add(1)
+		defer s.inFlightRequests.Add(-1)
+		c.Next()
+	}
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	mcpservice "github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// TestShutdownDrainsToolCalls checks that a tool call started before the server is asked to shut down
+// completes successfully, while the server stops accepting connections and reports itself unready.
+func TestShutdownDrainsToolCalls(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	upstream := server.NewMCPServer("ci", "0.0.0")
+	upstream.AddTool(mcp.NewTool("slow_build"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-release
+		return mcp.NewToolResultText("built"), nil
+	})
+	upstreamServer := server.NewTestStreamableHTTPServer(upstream)
+	defer upstreamServer.Close()
+
+	setup := testhelpers.SetupTestDB(t)
+	proxy := server.NewMCPServer("test", "0.0.0")
+	sseProxy := server.NewMCPServer("test-sse", "0.0.0")
+	mcpService, err := mcpservice.NewMCPService(&mcpservice.ServiceConfig{
+		DB:                      setup.DB,
+		McpProxyServer:          proxy,
+		SseMcpProxyServer:       sseProxy,
+		Metrics:                 telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout: 5,
+	})
+	testhelpers.AssertNoError(t, err)
+	ci, err := model.NewStreamableHTTPServer("ci", "", upstreamServer.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, mcpService.RegisterMcpServer(context.Background(), ci))
+	toolGroupService, err := toolgroup.NewToolGroupService(setup.DB, mcpService)
+	testhelpers.AssertNoError(t, err)
+	configService := config.NewServerConfigService(setup.DB)
+	_, err = configService.Init(model.ModeDev)
+	testhelpers.AssertNoError(t, err)
+
+	s, err := NewServer(&ServerOptions{
+		MCPProxyServer:    proxy,
+		SseMcpProxyServer: sseProxy,
+		MCPService:        mcpService,
+		ConfigService:     configService,
+		UserService:       user.NewUserService(setup.DB),
+		ToolGroupService:  toolGroupService,
+		Metrics:           telemetry.NewNoopCustomMetrics(),
+	})
+	testhelpers.AssertNoError(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	testhelpers.AssertNoError(t, err)
+	httpServer := s.NewHTTPServer(ln.Addr().String())
+	go func() { _ = httpServer.Serve(ln) }()
+	baseURL := "http://" + ln.Addr().String()
+
+	// a long-lived SSE session, that must not keep the server from stopping
+	sseResp, err := http.Get(baseURL + "/sse")
+	testhelpers.AssertNoError(t, err)
+	defer sseResp.Body.Close()
+
+	mcpClient, err := client.NewStreamableHttpClient(baseURL + "/mcp")
+	testhelpers.AssertNoError(t, err)
+	defer mcpClient.Close()
+	initReq := mcp.InitializeRequest{}
+	initReq.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = mcpClient.Initialize(context.Background(), initReq)
+	testhelpers.AssertNoError(t, err)
+
+	type outcome struct {
+		res *mcp.CallToolResult
+		err error
+	}
+	called := make(chan outcome, 1)
+	go func() {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "ci__slow_build"
+		res, err := mcpClient.CallTool(context.Background(), req)
+		called <- outcome{res, err}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() { stopped <- s.Shutdown(ctx, httpServer) }()
+
+	// the server drains: it is unready, and doesn't accept connections anymore
+	for !s.draining.Load() {
+		time.Sleep(time.Millisecond)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	testhelpers.AssertEqual(t, http.StatusServiceUnavailable, w.Code)
+	var r types.ServerReadiness
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &r))
+	testhelpers.AssertTrue(t, r.ShuttingDown, "the server should report that it is shutting down")
+	select {
+	case err := <-stopped:
+		t.Fatalf("the server stopped before the tool call completed: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	o := <-called
+	testhelpers.AssertNoError(t, o.err)
+	testhelpers.AssertFalse(t, o.res.IsError, "the tool call should have succeeded")
+	testhelpers.AssertEqual(t, "built", o.res.Content[0].(mcp.TextContent).Text)
+
+	testhelpers.AssertNoError(t, <-stopped)
+	_, err = http.Get(baseURL + "/health")
+	testhelpers.AssertError(t, err)
+}
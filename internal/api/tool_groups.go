@@ -1,31 +1,43 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
 )
 
 func (s *Server) createToolGroupHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var input model.ToolGroup
+		var input types.ToolGroup
 		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, invalidRequest("invalid request body: %v", err))
 			return
 		}
-		if err := s.toolGroupService.CreateToolGroup(&input); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		group, err := newToolGroupModel(&input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if err := s.toolGroupService.CreateToolGroup(group); err != nil {
+			respondError(c, err)
 			return
 		}
+		if created, err := toToolGroupType(group); err == nil {
+			s.webhookService.Publish(types.EventGroupCreated, created)
+		}
 		resp := &types.CreateToolGroupResponse{
-			ToolGroupEndpoints: getToolGroupEndpoints(c, input.Name),
+			UUID:               group.UUID,
+			ToolGroupEndpoints: getToolGroupEndpoints(c, group.Name),
 		}
 		c.JSON(http.StatusCreated, resp)
 	}
@@ -35,133 +47,104 @@ func (s *Server) createToolGroupHandler() gin.HandlerFunc {
 // This API only provides basic information about each tool group, ie, name and description.
 func (s *Server) listToolGroupsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		groups, err := s.toolGroupService.ListToolGroups()
+		page, ok := parsePage(c)
+		if !ok {
+			return
+		}
+		groups, next, err := s.toolGroupService.ListToolGroupsPage(page)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 
 		resp := make([]*types.ToolGroup, len(groups))
 		for i, g := range groups {
 			resp[i] = &types.ToolGroup{
+				UUID:        g.UUID,
 				Name:        g.Name,
 				Description: g.Description,
 			}
 
 			gTools, err := g.GetTools()
 			if err != nil {
-				c.JSON(
-					http.StatusInternalServerError,
-					gin.H{"error": fmt.Sprintf("error getting included tools of group %s: %s", g.Name, err.Error())},
-				)
+				respondError(c, fmt.Errorf("error getting included tools of group %s: %w", g.Name, err))
 				return
 			}
 			resp[i].IncludedTools = gTools
 
 			gServers, err := g.GetServers()
 			if err != nil {
-				c.JSON(
-					http.StatusInternalServerError,
-					gin.H{"error": fmt.Sprintf("error getting included servers of group %s: %s", g.Name, err.Error())},
-				)
+				respondError(c, fmt.Errorf("error getting included servers of group %s: %w", g.Name, err))
 				return
 			}
 			resp[i].IncludedServers = gServers
 
 			gExcluded, err := g.GetExcludedTools()
 			if err != nil {
-				c.JSON(
-					http.StatusInternalServerError,
-					gin.H{"error": fmt.Sprintf("error getting excluded tools of group %s: %s", g.Name, err.Error())},
-				)
+				respondError(c, fmt.Errorf("error getting excluded tools of group %s: %w", g.Name, err))
 				return
 			}
 			resp[i].ExcludedTools = gExcluded
 		}
 
-		c.JSON(http.StatusOK, resp)
+		c.JSON(http.StatusOK, newPage(resp, next))
 	}
 }
 
 func (s *Server) getToolGroupHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		name := c.Param("name")
+		name, ok := pathName(c, "name", s.toolGroupService.ResolveToolGroupName)
+		if !ok {
+			return
+		}
 		if name == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+			respondError(c, validationFailed("name is required").with("field", "name"))
 			return
 		}
 
 		group, err := s.toolGroupService.GetToolGroup(name)
 		if err != nil {
 			if errors.Is(err, toolgroup.ErrToolGroupNotFound) {
-				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("tool group %s not found", name)})
+				respondError(c, newAPIError(http.StatusNotFound, types.ErrorCodeNotFound, "tool group %s not found", name))
 				return
 			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 
-		resp := &types.GetToolGroupResponse{
-			ToolGroup: &types.ToolGroup{
-				Name:        group.Name,
-				Description: group.Description,
-			},
-			ToolGroupEndpoints: getToolGroupEndpoints(c, group.Name),
-		}
-
-		// Get included tools
-		var tools []string
-		tools, err = group.GetTools()
-		if err != nil {
-			c.JSON(
-				http.StatusInternalServerError,
-				gin.H{"error": fmt.Sprintf("error getting included tools of group: %s", err.Error())},
-			)
+		if notModified(c, versionETag(group.Version)) {
 			return
 		}
-		resp.IncludedTools = tools
-
-		// Get included servers
-		var servers []string
-		servers, err = group.GetServers()
+		g, err := toToolGroupType(group)
 		if err != nil {
-			c.JSON(
-				http.StatusInternalServerError,
-				gin.H{"error": fmt.Sprintf("error getting included servers of group: %s", err.Error())},
-			)
+			respondError(c, err)
 			return
 		}
-		resp.IncludedServers = servers
-
-		// Get excluded tools
-		var excludedTools []string
-		excludedTools, err = group.GetExcludedTools()
-		if err != nil {
-			c.JSON(
-				http.StatusInternalServerError,
-				gin.H{"error": fmt.Sprintf("error getting excluded tools of group: %s", err.Error())},
-			)
-			return
+		resp := &types.GetToolGroupResponse{
+			ToolGroup:          g,
+			ToolGroupEndpoints: getToolGroupEndpoints(c, group.Name),
 		}
-		resp.ExcludedTools = excludedTools
-
 		c.JSON(http.StatusOK, resp)
 	}
 }
 
 func (s *Server) deleteToolGroupHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		name := c.Param("name")
+		name, ok := pathName(c, "name", s.toolGroupService.ResolveToolGroupName)
+		if !ok {
+			return
+		}
 		if name == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+			respondError(c, validationFailed("name is required").with("field", "name"))
 			return
 		}
 
 		err := s.toolGroupService.DeleteToolGroup(name)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
+		s.webhookService.Publish(types.EventGroupDeleted, map[string]string{"name": name})
 
 		// TODO: return 404 if the group did not exist.
 		//  The tool group service should return ErrToolGroupNotFound if the group does not exist.
@@ -172,109 +155,177 @@ func (s *Server) deleteToolGroupHandler() gin.HandlerFunc {
 
 func (s *Server) updateToolGroupHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		name := c.Param("name")
+		name, ok := pathName(c, "name", s.toolGroupService.ResolveToolGroupName)
+		if !ok {
+			return
+		}
 		if name == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "group name is required"})
+			respondError(c, validationFailed("group name is required").with("field", "name"))
 			return
 		}
 
-		var input model.ToolGroup
+		var input types.ToolGroup
 		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, invalidRequest("invalid request body: %v", err))
 			return
 		}
+		// the name of a group cannot be changed, it is the one of the path
+		input.Name = name
 
-		originalConf, err := s.toolGroupService.UpdateToolGroup(name, &input)
+		version, ok := ifMatchVersion(c)
+		if !ok {
+			return
+		}
+		updated, err := newToolGroupModel(&input)
 		if err != nil {
-			if errors.Is(err, toolgroup.ErrToolGroupNotFound) {
-				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("tool group %s does not exist", name)})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
+		updated.Version = version
 
-		// create and send response object
-		resp := &types.UpdateToolGroupResponse{
-			Name: name,
-			Old: &types.ToolGroup{
-				Name:        originalConf.Name,
-				Description: originalConf.Description,
-			},
-			New: &types.ToolGroup{
-				Name:        input.Name,
-				Description: input.Description,
-			},
-		}
+		s.updateToolGroup(c, name, updated)
+	}
+}
 
-		var origTools []string
-		origTools, err = originalConf.GetTools()
-		if err != nil {
-			c.JSON(
-				http.StatusInternalServerError,
-				gin.H{"error": fmt.Sprintf("error getting included tools of the original group config: %s", err.Error())},
-			)
+// patchToolGroupHandler applies a JSON merge patch to the configuration of a tool group.
+// The tools of the group's MCP servers are only changed if the tools it effectively includes changed.
+func (s *Server) patchToolGroupHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name, ok := pathName(c, "name", s.toolGroupService.ResolveToolGroupName)
+		if !ok {
 			return
 		}
-		resp.Old.IncludedTools = origTools
 
-		var origServers []string
-		origServers, err = originalConf.GetServers()
-		if err != nil {
-			c.JSON(
-				http.StatusInternalServerError,
-				gin.H{"error": fmt.Sprintf("error getting included servers of the original group config: %s", err.Error())},
-			)
+		version, ok := ifMatchVersion(c)
+		if !ok {
+			return
+		}
+		existing, err := s.toolGroupService.GetToolGroup(name)
+		if errors.Is(err, toolgroup.ErrToolGroupNotFound) {
+			respondError(c, newAPIError(http.StatusNotFound, types.ErrorCodeNotFound, "tool group %s does not exist", name))
 			return
 		}
-		resp.Old.IncludedServers = origServers
-
-		var origExcluded []string
-		origExcluded, err = originalConf.GetExcludedTools()
 		if err != nil {
-			c.JSON(
-				http.StatusInternalServerError,
-				gin.H{"error": fmt.Sprintf("error getting excluded tools of the original group config: %s", err.Error())},
-			)
+			respondError(c, err)
+			return
+		}
+		if version != 0 && version != existing.Version {
+			respondError(c, model.ErrVersionConflict)
 			return
 		}
-		resp.Old.ExcludedTools = origExcluded
 
-		var newTools []string
-		newTools, err = input.GetTools()
+		current, err := toToolGroupType(existing)
 		if err != nil {
-			c.JSON(
-				http.StatusInternalServerError,
-				gin.H{"error": fmt.Sprintf("error getting included tools of the new group config: %s", err.Error())},
-			)
+			respondError(c, err)
+			return
+		}
+		// the version is not part of the configuration that can be patched
+		current.Version = 0
+		var input types.ToolGroup
+		if err := applyMergePatch(c, current, &input); err != nil {
+			respondError(c, invalidRequest("invalid request body: %v", err))
+			return
+		}
+		if input.Name != name {
+			respondError(c, validationFailed(
+				"the name of tool group %s cannot be changed to %s", name, input.Name,
+			).with("field", "name"))
 			return
 		}
-		resp.New.IncludedTools = newTools
 
-		var newServers []string
-		newServers, err = input.GetServers()
+		updated, err := newToolGroupModel(&input)
 		if err != nil {
-			c.JSON(
-				http.StatusInternalServerError,
-				gin.H{"error": fmt.Sprintf("error getting included servers of the new group config: %s", err.Error())},
-			)
+			respondError(c, err)
 			return
 		}
-		resp.New.IncludedServers = newServers
+		// the patch was applied to this version, it must not overwrite a concurrent update
+		updated.Version = existing.Version
 
-		var newExcluded []string
-		newExcluded, err = input.GetExcludedTools()
-		if err != nil {
-			c.JSON(
-				http.StatusInternalServerError,
-				gin.H{"error": fmt.Sprintf("error getting excluded tools of the new group config: %s", err.Error())},
-			)
+		s.updateToolGroup(c, name, updated)
+	}
+}
+
+// updateToolGroup stores the new configuration of a tool group and responds with its old and new configurations.
+func (s *Server) updateToolGroup(c *gin.Context, name string, updated *model.ToolGroup) {
+	originalConf, err := s.toolGroupService.UpdateToolGroup(name, updated)
+	if err != nil {
+		if errors.Is(err, toolgroup.ErrToolGroupNotFound) {
+			respondError(c, newAPIError(http.StatusNotFound, types.ErrorCodeNotFound, "tool group %s does not exist", name))
+			return
+		}
+		if errors.Is(err, model.ErrVersionConflict) {
+			respondError(c, err)
 			return
 		}
-		resp.New.ExcludedTools = newExcluded
+		respondError(c, err)
+		return
+	}
 
-		c.JSON(http.StatusOK, resp)
+	// create and send response object
+	resp := &types.UpdateToolGroupResponse{Name: name}
+	if resp.Old, err = toToolGroupType(originalConf); err != nil {
+		respondError(c, fmt.Errorf("error reading the original group config: %w", err))
+		return
+	}
+	if resp.New, err = toToolGroupType(updated); err != nil {
+		respondError(c, fmt.Errorf("error reading the new group config: %w", err))
+		return
+	}
+
+	if updated.Version != originalConf.Version {
+		// the group was actually changed
+		s.webhookService.Publish(types.EventGroupUpdated, resp)
+	}
+	setVersionETag(c, updated.Version)
+	c.JSON(http.StatusOK, resp)
+}
+
+// toToolGroupType converts a tool group record into the representation sent to clients.
+func toToolGroupType(group *model.ToolGroup) (*types.ToolGroup, error) {
+	g := &types.ToolGroup{
+		UUID:        group.UUID,
+		Name:        group.Name,
+		Description: group.Description,
+		Version:     group.Version,
+	}
+	var err error
+	if g.IncludedTools, err = group.GetTools(); err != nil {
+		return nil, fmt.Errorf("error getting included tools of group %s: %w", group.Name, err)
+	}
+	if g.IncludedServers, err = group.GetServers(); err != nil {
+		return nil, fmt.Errorf("error getting included servers of group %s: %w", group.Name, err)
+	}
+	if g.ExcludedTools, err = group.GetExcludedTools(); err != nil {
+		return nil, fmt.Errorf("error getting excluded tools of group %s: %w", group.Name, err)
+	}
+	return g, nil
+}
+
+// newToolGroupModel creates the record of a tool group from the configuration supplied by a client.
+// It returns types.ValidationErrors if the configuration is invalid.
+func newToolGroupModel(group *types.ToolGroup) (*model.ToolGroup, error) {
+	if err := group.Validate(); err != nil {
+		return nil, err
+	}
+	g := &model.ToolGroup{Name: group.Name, Description: group.Description}
+	for _, list := range []struct {
+		values []string
+		column *datatypes.JSON
+	}{
+		{group.IncludedTools, &g.IncludedTools},
+		{group.IncludedServers, &g.IncludedServers},
+		{group.ExcludedTools, &g.ExcludedTools},
+	} {
+		if len(list.values) == 0 {
+			continue
+		}
+		data, err := json.Marshal(list.values)
+		if err != nil {
+			return nil, err
+		}
+		*list.column = data
 	}
+	return g, nil
 }
 
 // toolGroupMCPServerCallHandler handles incoming MCP requests from for a specific tool group.
@@ -284,7 +335,11 @@ func (s *Server) toolGroupMCPServerCallHandler() gin.HandlerFunc {
 		groupName := c.Param("name")
 		groupMcpServer, exists := s.toolGroupService.GetToolGroupMCPServer(groupName)
 		if !exists {
-			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("tool group not found: %s", groupName)})
+			respondError(c, newAPIError(http.StatusNotFound, types.ErrorCodeNotFound, "tool group not found: %s", groupName))
+			return
+		}
+
+		if s.serveCachedToolsList(c, groupName, groupMcpServer) {
 			return
 		}
 
@@ -334,10 +389,9 @@ func (s *Server) toolGroupSseMCPServerCallHandler() gin.HandlerFunc {
 
 		groupSseMcpServer, err := s.getGroupSseServer(groupName)
 		if err != nil {
-			c.JSON(
-				http.StatusNotFound,
-				gin.H{"error": fmt.Sprintf("failed to get sse server for group %s: %v", groupName, err)},
-			)
+			respondError(c, newAPIError(
+				http.StatusNotFound, types.ErrorCodeNotFound, "failed to get sse server for group %s: %v", groupName, err,
+			))
 			return
 		}
 
@@ -352,10 +406,9 @@ func (s *Server) toolGroupSseMCPServerCallMessageHandler() gin.HandlerFunc {
 
 		groupSseMcpServer, err := s.getGroupSseServer(groupName)
 		if err != nil {
-			c.JSON(
-				http.StatusNotFound,
-				gin.H{"error": fmt.Sprintf("failed to get sse server for group: %s", groupName)},
-			)
+			respondError(c, newAPIError(
+				http.StatusNotFound, types.ErrorCodeNotFound, "failed to get sse server for group: %s", groupName,
+			))
 			return
 		}
 
@@ -385,3 +438,40 @@ func getToolGroupEndpoints(c *gin.Context, groupName string) *types.ToolGroupEnd
 		SSEMessageEndpoint:     baseEndpoint + "/message",
 	}
 }
+
+// getToolReferencesHandler returns the tool groups that reference the tools given by the "tool" query parameter,
+// which can be repeated, and the tools provided by the server given by the "server" query parameter.
+func (s *Server) getToolReferencesHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tools := c.QueryArray("tool")
+		serverName := c.Query("server")
+		if serverName == "" && len(tools) == 0 {
+			respondError(c, validationFailed("either a server or at least one tool must be specified"))
+			return
+		}
+
+		if serverName != "" {
+			serverTools, err := s.mcpService.ListToolsByServer(serverName)
+			if err != nil {
+				respondError(c, err)
+				return
+			}
+			for _, t := range serverTools {
+				tools = append(tools, t.Name)
+			}
+		}
+
+		refs, err := s.toolGroupService.GroupsReferencingTools(tools)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		resp := &types.ToolReferences{Tools: tools, Groups: make([]types.ToolGroupReference, 0, len(refs))}
+		for name, groupTools := range refs {
+			resp.Groups = append(resp.Groups, types.ToolGroupReference{Name: name, Tools: groupTools})
+		}
+		sort.Slice(resp.Groups, func(i, j int) bool { return resp.Groups[i].Name < resp.Groups[j].Name })
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// mcpSessionIDs validates the session IDs of the MCP clients of the streamable HTTP proxy servers.
+// It is the session ID manager server.StreamableHTTPServer uses by default.
+var mcpSessionIDs = &server.InsecureStatefulSessionIdManager{}
+
+// toolsListMessage is the part of a JSON-RPC message needed to tell if it can be answered from the tools list cache.
+type toolsListMessage struct {
+	ID     mcp.RequestId `json:"id"`
+	Method mcp.MCPMethod `json:"method"`
+	Params struct {
+		Cursor mcp.Cursor `json:"cursor"`
+	} `json:"params"`
+}
+
+// serveCachedToolsList answers a tools/list request sent to the streamable HTTP proxy server mcpServer
+// with the cached result, and reports whether it did.
+// group is the tool group served by mcpServer, empty for the main proxy server.
+// Any other request, including one the proxy server would reject, is left to the proxy server.
+func (s *Server) serveCachedToolsList(c *gin.Context, group string, mcpServer *server.MCPServer) bool {
+	cache := s.mcpService.ToolsListCache()
+	if cache == nil || c.Request.Method != http.MethodPost {
+		return false
+	}
+	if mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type")); err != nil || mediaType != "application/json" {
+		return false
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	// the proxy server reads the body again if the request is not answered here
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	var msg toolsListMessage
+	if err := json.Unmarshal(body, &msg); err != nil ||
+		msg.Method != mcp.MethodToolsList || msg.ID.IsNil() || msg.Params.Cursor != "" {
+		return false
+	}
+	if terminated, err := mcpSessionIDs.Validate(c.GetHeader(server.HeaderKeySessionID)); err != nil || terminated {
+		return false
+	}
+
+	resp, err := cache.ToolsListResponse(c.Request.Context(), group, mcpServer, msg.ID)
+	if err != nil {
+		log.Printf("[ERROR] failed to get the cached tools list: %v", err)
+		return false
+	}
+	c.Header("Content-Type", "application/json")
+	c.Status(http.StatusOK)
+	// the response is written as is, the cached result is not serialized again
+	if _, err := resp.WriteTo(c.Writer); err != nil {
+		log.Printf("[ERROR] failed to write the tools list: %v", err)
+	}
+	return true
+}
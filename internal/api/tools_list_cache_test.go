@@ -0,0 +1,146 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	mcpservice "github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// TestServeCachedToolsList checks that the tools/list requests to the streamable HTTP proxy servers are answered
+// from the cache, which reflects the changes to the tools, and that other requests are left to the proxy servers.
+func TestServeCachedToolsList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setup := testhelpers.SetupTestDB(t)
+	db := setup.DB
+	git := &model.McpServer{Name: "git", Transport: types.TransportStdio, Config: []byte("{}")}
+	testhelpers.AssertNoError(t, db.Create(git).Error)
+	for _, name := range []string{"commit", "status"} {
+		testhelpers.AssertNoError(t, db.Create(&model.Tool{ServerID: git.ID, Name: name, InputSchema: []byte(`{"type":"object"}`)}).Error)
+	}
+	testhelpers.AssertNoError(t, db.Create(&model.ToolGroup{Name: "review", IncludedTools: []byte(`["git__commit"]`)}).Error)
+
+	proxy := server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true))
+	mcpService, err := mcpservice.NewMCPService(&mcpservice.ServiceConfig{
+		DB:                db,
+		McpProxyServer:    proxy,
+		SseMcpProxyServer: server.NewMCPServer("test-sse", "0.0.0"),
+		Metrics:           telemetry.NewNoopCustomMetrics(),
+	})
+	testhelpers.AssertNoError(t, err)
+	toolGroupService, err := toolgroup.NewToolGroupService(db, mcpService)
+	testhelpers.AssertNoError(t, err)
+
+	s := &Server{mcpService: mcpService, toolGroupService: toolGroupService, mcpProxyServer: proxy}
+	streamableHTTPServer := server.NewStreamableHTTPServer(proxy)
+	router := gin.New()
+	router.POST("/mcp", func(c *gin.Context) {
+		if !s.serveCachedToolsList(c, "", proxy) {
+			streamableHTTPServer.ServeHTTP(c.Writer, c.Request)
+		}
+	})
+	router.POST("/groups/:name/mcp", s.toolGroupMCPServerCallHandler())
+
+	sessionID := mcpSessionIDs.Generate()
+	post := func(path, sessionID, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(server.HeaderKeySessionID, sessionID)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	listTools := func(path string) string {
+		t.Helper()
+		w := post(path, sessionID, `{"jsonrpc":"2.0","id":"list-1","method":"tools/list"}`)
+		testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+		var resp struct {
+			ID     string              `json:"id"`
+			Result mcp.ListToolsResult `json:"result"`
+		}
+		testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		testhelpers.AssertEqual(t, "list-1", resp.ID)
+		names := make([]string, len(resp.Result.Tools))
+		for i, tool := range resp.Result.Tools {
+			names[i] = tool.Name
+		}
+		return strings.Join(names, ",")
+	}
+
+	testhelpers.AssertEqual(t, "git__commit,git__status", listTools("/mcp"))
+	testhelpers.AssertEqual(t, "git__commit,git__status", listTools("/mcp"))
+	testhelpers.AssertEqual(t, "git__commit", listTools("/groups/review/mcp"))
+
+	_, err = mcpService.DisableTools("git__commit")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "git__status", listTools("/mcp"))
+	testhelpers.AssertEqual(t, "", listTools("/groups/review/mcp"))
+
+	// the cached response is the one the proxy server would have sent
+	w := post("/mcp", sessionID, `{"jsonrpc":"2.0","id":7,"method":"tools/list"}`)
+	var expected bytes.Buffer
+	resp := proxy.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":7,"method":"tools/list"}`))
+	testhelpers.AssertNoError(t, json.NewEncoder(&expected).Encode(resp))
+	testhelpers.AssertEqual(t, expected.String(), w.Body.String())
+
+	// the proxy server rejects the requests of a client without a valid session
+	w = post("/mcp", "not-a-session", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+}
+
+// BenchmarkServeCachedToolsList compares writing the cached tools/list response as is with serializing it again
+// for every request, on a proxy server with thousands of tools.
+func BenchmarkServeCachedToolsList(b *testing.B) {
+	proxy := server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true))
+	for i := range 2000 {
+		proxy.AddTool(mcp.NewTool(
+			fmt.Sprintf("server__tool_%d", i),
+			mcp.WithDescription(strings.Repeat("Does something useful with its arguments. ", 10)),
+			mcp.WithString("path", mcp.Required(), mcp.Description("The path of the file")),
+			mcp.WithNumber("limit", mcp.Description("The maximum number of results")),
+		), nil)
+	}
+	cache := mcpservice.NewToolsListCache(telemetry.NewNoopCustomMetrics())
+	id := mcp.NewRequestId("list-1")
+
+	b.Run("reencoded", func(b *testing.B) {
+		result, err := cache.ToolsList(context.Background(), "", proxy)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		for b.Loop() {
+			if err := json.NewEncoder(io.Discard).Encode(mcp.NewJSONRPCResultResponse(id, result)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("precomputed", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			resp, err := cache.ToolsListResponse(context.Background(), "", proxy, id)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := resp.WriteTo(io.Discard); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
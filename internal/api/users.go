@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,19 +11,24 @@ import (
 
 func (s *Server) createUserHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var input model.User
+		var input types.CreateOrUpdateUserRequest
 		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, invalidRequest("invalid request body: %v", err))
+			return
+		}
+		if err := input.Validate(); err != nil {
+			respondError(c, err)
 			return
 		}
 
-		newUser, err := s.userService.CreateUser(&input)
+		newUser, err := s.userService.CreateUser(&model.User{Username: input.Username, AccessToken: input.AccessToken})
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 
 		resp := &types.CreateOrUpdateUserResponse{
+			UUID:        newUser.UUID,
 			Username:    newUser.Username,
 			Role:        string(newUser.Role),
 			AccessToken: newUser.AccessToken,
@@ -33,45 +39,57 @@ func (s *Server) createUserHandler() gin.HandlerFunc {
 
 func (s *Server) listUsersHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		users, err := s.userService.ListUsers()
+		page, ok := parsePage(c)
+		if !ok {
+			return
+		}
+		users, next, err := s.userService.ListUsersPage(page)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 
 		resp := make([]*types.User, len(users))
 		for i, u := range users {
 			resp[i] = &types.User{
+				UUID:     u.UUID,
 				Username: u.Username,
 				Role:     string(u.Role),
 			}
 		}
 
-		c.JSON(http.StatusOK, resp)
+		c.JSON(http.StatusOK, newPage(resp, next))
 	}
 }
 
 func (s *Server) updateUserHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		username := c.Param("username")
+		username, ok := pathName(c, "username", s.userService.ResolveUsername)
+		if !ok {
+			return
+		}
 		if username == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+			respondError(c, validationFailed("username is required").with("field", "username"))
 			return
 		}
 
 		var input model.User
 		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, invalidRequest("invalid request body: %v", err))
 			return
 		}
+		if input.Username == "" {
+			input.Username = username
+		}
 
 		updatedUser, err := s.userService.UpdateUser(&input)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 
 		resp := &types.CreateOrUpdateUserResponse{
+			UUID:        updatedUser.UUID,
 			Username:    updatedUser.Username,
 			Role:        string(updatedUser.Role),
 			AccessToken: updatedUser.AccessToken,
@@ -82,15 +100,18 @@ func (s *Server) updateUserHandler() gin.HandlerFunc {
 
 func (s *Server) deleteUserHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		username := c.Param("username")
+		username, ok := pathName(c, "username", s.userService.ResolveUsername)
+		if !ok {
+			return
+		}
 		if username == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+			respondError(c, validationFailed("username is required").with("field", "username"))
 			return
 		}
 
 		err := s.userService.DeleteUser(username)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 
@@ -102,17 +123,18 @@ func (s *Server) whoAmIHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		currentUser, exists := c.Get("user")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			respondError(c, newAPIError(http.StatusUnauthorized, types.ErrorCodeUnauthorized, "unauthorized"))
 			return
 		}
 
 		u, ok := currentUser.(*model.User)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user from context"})
+			respondError(c, errors.New("failed to get user from context"))
 			return
 		}
 
 		resp := types.User{
+			UUID:     u.UUID,
 			Username: u.Username,
 			Role:     string(u.Role),
 		}
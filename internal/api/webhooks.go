@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+)
+
+// webhooksAvailable responds with an error and returns false if the server doesn't deliver webhooks.
+func (s *Server) webhooksAvailable(c *gin.Context) bool {
+	if s.webhookService == nil {
+		respondError(c, newAPIError(
+			http.StatusServiceUnavailable, types.ErrorCodeUnavailable, "webhooks are not available on this server",
+		))
+		return false
+	}
+	return true
+}
+
+func (s *Server) listWebhooksHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.webhooksAvailable(c) {
+			return
+		}
+		page, ok := parsePage(c)
+		if !ok {
+			return
+		}
+		records, next, err := s.webhookService.ListWebhooksPage(page)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		webhooks := make([]*types.Webhook, len(records))
+		for i, w := range records {
+			webhooks[i] = toWebhookType(w)
+		}
+		c.JSON(http.StatusOK, newPage(webhooks, next))
+	}
+}
+
+// createWebhookHandler subscribes a URL to events.
+// The response is the only one that includes the webhook's secret, since it may have been generated.
+func (s *Server) createWebhookHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.webhooksAvailable(c) {
+			return
+		}
+		var input types.Webhook
+		if err := c.ShouldBindJSON(&input); err != nil {
+			respondError(c, invalidRequest("invalid request body: %v", err))
+			return
+		}
+		record := &model.Webhook{Name: input.Name, URL: input.URL, Secret: input.Secret, Format: input.Format}
+		if len(input.Events) > 0 {
+			events, err := json.Marshal(input.Events)
+			if err != nil {
+				respondError(c, validationFailed("%v", err))
+				return
+			}
+			record.Events = datatypes.JSON(events)
+		}
+
+		if err := s.webhookService.CreateWebhook(record); err != nil {
+			respondError(c, err)
+			return
+		}
+		created := toWebhookType(record)
+		created.Secret = record.Secret
+		c.JSON(http.StatusCreated, created)
+	}
+}
+
+func (s *Server) getWebhookHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.webhooksAvailable(c) {
+			return
+		}
+		w, err := s.webhookService.GetWebhook(c.Param("name"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, toWebhookType(w))
+	}
+}
+
+func (s *Server) deleteWebhookHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.webhooksAvailable(c) {
+			return
+		}
+		if err := s.webhookService.DeleteWebhook(c.Param("name")); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// setWebhookEnabledHandler enables or disables a webhook.
+// Enabling a webhook that was disabled automatically gives it a fresh start.
+func (s *Server) setWebhookEnabledHandler(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.webhooksAvailable(c) {
+			return
+		}
+		w, err := s.webhookService.SetWebhookEnabled(c.Param("name"), enabled)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, toWebhookType(w))
+	}
+}
+
+// testWebhookHandler sends a test event to a webhook and responds with the outcome of the delivery.
+// A failed delivery is not an error of the request, it is reported in the response.
+func (s *Server) testWebhookHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.webhooksAvailable(c) {
+			return
+		}
+		d, err := s.webhookService.TestWebhook(c, c.Param("name"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, toWebhookDeliveryType(d))
+	}
+}
+
+func (s *Server) listWebhookDeliveriesHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.webhooksAvailable(c) {
+			return
+		}
+		page, ok := parsePage(c)
+		if !ok {
+			return
+		}
+		records, next, err := s.webhookService.ListDeliveriesPage(c.Param("name"), page)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		deliveries := make([]types.WebhookDelivery, len(records))
+		for i, d := range records {
+			deliveries[i] = toWebhookDeliveryType(d)
+		}
+		c.JSON(http.StatusOK, newPage(deliveries, next))
+	}
+}
+
+// toWebhookType converts a webhook record into the representation sent to clients, without its secret.
+func toWebhookType(w *model.Webhook) *types.Webhook {
+	resp := &types.Webhook{
+		Name:                w.Name,
+		URL:                 w.URL,
+		Format:              w.Format,
+		Enabled:             w.Enabled,
+		ConsecutiveFailures: w.ConsecutiveFailures,
+		DisabledReason:      w.DisabledReason,
+	}
+	// the events are validated when the webhook is created, so they always decode
+	_ = json.Unmarshal(w.Events, &resp.Events)
+	return resp
+}
+
+func toWebhookDeliveryType(d *model.WebhookDelivery) types.WebhookDelivery {
+	return types.WebhookDelivery{
+		EventID:    d.EventID,
+		Event:      d.Event,
+		Attempts:   d.Attempts,
+		Succeeded:  d.Succeeded,
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		CreatedAt:  d.CreatedAt,
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func newWebhookTestRouter(t *testing.T, s *Server) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/webhooks", s.listWebhooksHandler())
+	router.POST("/webhooks", s.createWebhookHandler())
+	router.GET("/webhooks/:name", s.getWebhookHandler())
+	router.DELETE("/webhooks/:name", s.deleteWebhookHandler())
+	router.POST("/webhooks/:name/disable", s.setWebhookEnabledHandler(false))
+	router.GET("/webhooks/:name/deliveries", s.listWebhookDeliveriesHandler())
+	return router
+}
+
+func sendWebhookRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestWebhookHandlers(t *testing.T) {
+	setup := testhelpers.SetupTestDB(t)
+	webhookService := webhook.NewWebhookService(setup.DB, nil)
+	defer webhookService.Close()
+	router := newWebhookTestRouter(t, &Server{webhookService: webhookService})
+
+	w := sendWebhookRequest(
+		router, http.MethodPost, "/webhooks",
+		`{"name": "ci", "url": "https://ci.example.com/hook", "events": ["server.registered"]}`,
+	)
+	testhelpers.AssertEqual(t, http.StatusCreated, w.Code)
+	var created types.Webhook
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	testhelpers.AssertTrue(t, created.Secret != "", "the generated secret should be returned on creation")
+	testhelpers.AssertEqual(t, types.EventServerRegistered, strings.Join(created.Events, ","))
+	testhelpers.AssertTrue(t, created.Enabled, "the webhook should be enabled")
+
+	w = sendWebhookRequest(router, http.MethodPost, "/webhooks", `{"name": "cd", "url": "not a url"}`)
+	testhelpers.AssertEqual(t, http.StatusBadRequest, w.Code)
+
+	// the secret is never returned again
+	w = sendWebhookRequest(router, http.MethodGet, "/webhooks", "")
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	testhelpers.AssertStringNotContains(t, w.Body.String(), created.Secret)
+	var page types.Page[*types.Webhook]
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	testhelpers.AssertEqual(t, 1, len(page.Items))
+
+	w = sendWebhookRequest(router, http.MethodPost, "/webhooks/ci/disable", "")
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+	var disabled types.Webhook
+	testhelpers.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &disabled))
+	testhelpers.AssertFalse(t, disabled.Enabled, "the webhook should be disabled")
+	testhelpers.AssertEqual(t, "", disabled.Secret)
+
+	w = sendWebhookRequest(router, http.MethodGet, "/webhooks/ci/deliveries", "")
+	testhelpers.AssertEqual(t, http.StatusOK, w.Code)
+
+	w = sendWebhookRequest(router, http.MethodDelete, "/webhooks/ci", "")
+	testhelpers.AssertEqual(t, http.StatusNoContent, w.Code)
+	w = sendWebhookRequest(router, http.MethodGet, "/webhooks/ci", "")
+	testhelpers.AssertEqual(t, http.StatusNotFound, w.Code)
+	w = sendWebhookRequest(router, http.MethodGet, "/webhooks/ci/deliveries", "")
+	testhelpers.AssertEqual(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhookHandlersUnavailable(t *testing.T) {
+	router := newWebhookTestRouter(t, &Server{})
+	w := sendWebhookRequest(router, http.MethodGet, "/webhooks", "")
+	testhelpers.AssertEqual(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
-	"github.com/glebarez/sqlite"
-	"gorm.io/driver/postgres"
+	"github.com/mcpjungle/mcpjungle/internal/db/dialect"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -38,26 +38,70 @@ func getSQLiteDBPath() string {
 	return dbFilename
 }
 
+// sqliteURLPrefix is the prefix of the URLs of SQLite database files, eg- sqlite:///var/lib/mcpjungle.db
+// for an absolute path or sqlite://mcpjungle.db for a path relative to the working directory.
+const sqliteURLPrefix = "sqlite://"
+
+// SQLitePath returns the path of the SQLite database file the DSN refers to, and false if it refers to
+// another database. The empty DSN refers to the embedded database in the working directory.
+func SQLitePath(dsn string) (string, bool) {
+	if dsn == "" {
+		return getSQLiteDBPath(), true
+	}
+	if path, ok := strings.CutPrefix(dsn, sqliteURLPrefix); ok {
+		return path, true
+	}
+	return "", false
+}
+
 // NewDBConnection creates a new database connection based on the provided DSN.
+// The database is MySQL (or MariaDB) for mysql:// and mariadb:// URLs, a SQLite file for sqlite:// URLs
+// and Postgres for the other DSNs.
 // If the DSN is empty, it falls back to an embedded SQLite database.
 // For backward compatibility, it will use an existing "mcp.db" file if present,
 // otherwise it creates/uses "mcpjungle.db".
 func NewDBConnection(dsn string) (*gorm.DB, error) {
-	var dialector gorm.Dialector
-	if dsn == "" {
-		dbPath := getSQLiteDBPath()
-		log.Printf("[db] DATABASE_URL not set – falling back to embedded SQLite ./%s", dbPath)
-		dialector = sqlite.Open(fmt.Sprintf("%s?_busy_timeout=5000&_journal_mode=WAL", dbPath))
-	} else {
-		dialector = postgres.Open(dsn)
-	}
+	return NewDBConnectionWithOptions(dsn, nil)
+}
 
+// NewDBConnectionWithOptions is like NewDBConnection, it connects to the database of the DSN
+// with the given TLS and connection pool options, if any.
+func NewDBConnectionWithOptions(dsn string, pg *PostgresOptions) (*gorm.DB, error) {
 	c := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
+		// translate driver-specific errors (eg- unique constraint violations) into gorm's generic ones
+		// so that the API can report them with the appropriate status code regardless of the database
+		TranslateError: true,
+	}
+
+	var db *gorm.DB
+	var err error
+	switch {
+	case dsn == "":
+		dbPath := getSQLiteDBPath()
+		log.Printf("[db] DATABASE_URL not set – falling back to embedded SQLite ./%s", dbPath)
+		db, err = openSQLite(dbPath, c)
+	case strings.HasPrefix(dsn, sqliteURLPrefix):
+		dbPath, _ := SQLitePath(dsn)
+		db, err = openSQLite(dbPath, c)
+	case dialect.IsMySQLURL(dsn):
+		db, err = openMySQL(dsn, pg, c)
+	default:
+		db, err = openPostgres(dsn, pg, c)
 	}
-	db, err := gorm.Open(dialector, c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
+	backend, err := dialect.Describe(db)
+	if err != nil {
+		return nil, err
+	}
+	if dialect.Of(db) == dialect.SQLite {
+		backend += ", driver: " + sqliteDriver
+	}
+	log.Printf("[db] Connected to the %s database", backend)
+	if err := TrackTableVersions(db); err != nil {
+		return nil, fmt.Errorf("failed to track the versions of the tables: %w", err)
+	}
 	return db, nil
 }
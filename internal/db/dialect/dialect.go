@@ -0,0 +1,110 @@
+// Package dialect holds the SQL that differs between the databases supported by MCPJungle,
+// so that the rest of the code doesn't need to check which database it runs on.
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Dialect is the SQL dialect of a database, named like its gorm dialector.
+type Dialect string
+
+const (
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+	MySQL    Dialect = "mysql"
+)
+
+// mysqlTableOptions makes the string columns of MySQL tables case and trailing space sensitive, like in the
+// other databases. Otherwise, names that only differ by case would conflict and access tokens would be
+// matched case-insensitively.
+const mysqlTableOptions = "ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"
+
+// Of returns the dialect of the database db is connected to.
+func Of(db *gorm.DB) Dialect {
+	return Dialect(db.Dialector.Name())
+}
+
+// BinaryType returns the type of the columns holding binary documents of any size.
+func (d Dialect) BinaryType() string {
+	switch d {
+	case Postgres:
+		return "bytea"
+	case MySQL:
+		// a blob is limited to 64KB in MySQL
+		return "longblob"
+	default:
+		return "blob"
+	}
+}
+
+// LikeEscape returns the ESCAPE clause that makes the backslash the escape character of a LIKE pattern.
+func (d Dialect) LikeEscape() string {
+	if d == MySQL {
+		// MySQL treats the backslash as an escape character in string literals too
+		return `ESCAPE '\\'`
+	}
+	return `ESCAPE '\'`
+}
+
+// OnConflictUpdate returns the clause of an INSERT that updates the existing row instead when the key conflicts,
+// to be followed by the assignments of the update.
+func (d Dialect) OnConflictUpdate(key string) string {
+	if d == MySQL {
+		return "ON DUPLICATE KEY UPDATE"
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET", key)
+}
+
+// ResetSequenceSQL returns the statement that makes the next values generated for the auto-incremented column of
+// a table follow its largest one, once rows were inserted with their own values, eg- restored from a backup.
+// It is empty for the databases that keep track of the inserted values by themselves.
+func (d Dialect) ResetSequenceSQL(table, column string) string {
+	if d != Postgres {
+		return ""
+	}
+	return fmt.Sprintf(
+		"SELECT setval(pg_get_serial_sequence('%s', '%s'), COALESCE(MAX(%s), 0) + 1, false) FROM %s",
+		table, column, column, table,
+	)
+}
+
+// MigrationSession returns the session migrations must run in, so that the tables are created with the options
+// of the database.
+func MigrationSession(db *gorm.DB) *gorm.DB {
+	if Of(db) == MySQL {
+		return db.Set("gorm:table_options", mysqlTableOptions)
+	}
+	return db
+}
+
+// Describe returns the name and version of the database server db is connected to, eg- "MariaDB 11.4.2".
+func Describe(db *gorm.DB) (string, error) {
+	var query, name string
+	switch Of(db) {
+	case SQLite:
+		query, name = "SELECT sqlite_version()", "SQLite"
+	case Postgres:
+		query, name = "SHOW server_version", "Postgres"
+	case MySQL:
+		query, name = "SELECT VERSION()", "MySQL"
+	default:
+		return db.Dialector.Name(), nil
+	}
+
+	var version string
+	if err := db.Raw(query).Scan(&version).Error; err != nil {
+		return "", fmt.Errorf("failed to get the version of the database: %w", err)
+	}
+	// MariaDB reports itself as a MySQL database, eg- 11.4.2-MariaDB-ubu2404
+	if name == "MySQL" && strings.Contains(version, "MariaDB") {
+		name = "MariaDB"
+		if i := strings.Index(version, "-MariaDB"); i > 0 {
+			version = version[:i]
+		}
+	}
+	return name + " " + version, nil
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrDatabaseInUse is returned by LockSQLite when another process holds the lock of the SQLite database.
+var ErrDatabaseInUse = errors.New("the SQLite database is in use by another mcpjungle process")
+
+// errLocked is returned by lockFile when the file is locked by another process.
+var errLocked = errors.New("the file is locked")
+
+// SQLiteLock is the lock of a SQLite database file, held by the process that serves it or copies it
+// so that no other mcpjungle process uses the file meanwhile. SQLite itself lets several processes use it.
+// The lock is released when the process exits, even if it crashed.
+type SQLiteLock struct {
+	f *os.File
+}
+
+// LockSQLite takes the lock of the SQLite database file at path, on the file next to it with the .lock suffix.
+// If another process holds it, the error wraps ErrDatabaseInUse.
+func LockSQLite(path string) (*SQLiteLock, error) {
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the lock file of the SQLite database: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		_ = f.Close()
+		if errors.Is(err, errLocked) {
+			return nil, fmt.Errorf("%w: %s", ErrDatabaseInUse, path)
+		}
+		return nil, fmt.Errorf("failed to lock the SQLite database %s: %w", path, err)
+	}
+	return &SQLiteLock{f: f}, nil
+}
+
+// Release releases the lock. The lock file is left in place, removing it could let two processes lock
+// different files of the same name.
+func (l *SQLiteLock) Release() error {
+	err := unlockFile(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
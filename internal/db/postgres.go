@@ -0,0 +1,166 @@
+package db
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// postgresSSLModes are the values of sslmode supported by the Postgres driver.
+var postgresSSLModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// PostgresOptions configures the TLS and the connection pool of a Postgres database.
+// The pool settings also apply to MySQL databases.
+// The TLS settings that are set override the ones of the DSN, the zero value of a pool setting keeps its default.
+type PostgresOptions struct {
+	// SSLMode is the sslmode of the connection, eg- verify-full.
+	SSLMode string
+	// CACert is the path of a PEM file with the CA certificates the certificate of the server is verified with.
+	CACert string
+	// ClientCert and ClientKey are the paths of the certificate and key presented to the servers that require one.
+	ClientCert string
+	ClientKey  string
+
+	// MaxOpenConns bounds the connections open to the database.
+	MaxOpenConns int
+	// MaxIdleConns bounds the idle connections kept in the pool.
+	MaxIdleConns int
+	// ConnMaxLifetime is how long a connection is reused before it is closed.
+	ConnMaxLifetime time.Duration
+}
+
+// Validate checks the options, including that the certificate and key files exist and can be parsed,
+// so that a misconfigured server fails at startup rather than when it first connects.
+func (o *PostgresOptions) Validate() error {
+	if o.SSLMode != "" && !slices.Contains(postgresSSLModes, o.SSLMode) {
+		return fmt.Errorf("invalid sslmode '%s', must be one of: %s", o.SSLMode, strings.Join(postgresSSLModes, ", "))
+	}
+	if o.SSLMode == "disable" && (o.CACert != "" || o.ClientCert != "" || o.ClientKey != "") {
+		return errors.New("TLS certificates are configured but sslmode is disable")
+	}
+	if o.CACert != "" {
+		data, err := os.ReadFile(o.CACert)
+		if err != nil {
+			return fmt.Errorf("failed to read the CA certificate: %w", err)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(data) {
+			return fmt.Errorf("no PEM encoded certificate found in the CA certificate %s", o.CACert)
+		}
+	}
+	if (o.ClientCert == "") != (o.ClientKey == "") {
+		return errors.New("the client certificate and its key must be set together")
+	}
+	if o.ClientCert != "" {
+		if _, err := tls.LoadX509KeyPair(o.ClientCert, o.ClientKey); err != nil {
+			return fmt.Errorf("failed to load the client certificate: %w", err)
+		}
+	}
+	if o.MaxOpenConns < 0 || o.MaxIdleConns < 0 || o.ConnMaxLifetime < 0 {
+		return errors.New("the connection pool settings must not be negative")
+	}
+	return nil
+}
+
+// applyTo returns the DSN with the TLS settings of the options, in either the URL or the key/value format.
+func (o *PostgresOptions) applyTo(dsn string) (string, error) {
+	params := [][2]string{
+		{"sslmode", o.SSLMode}, {"sslrootcert", o.CACert}, {"sslcert", o.ClientCert}, {"sslkey", o.ClientKey},
+	}
+
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("invalid database URL: %w", err)
+		}
+		q := u.Query()
+		for _, p := range params {
+			if p[1] != "" {
+				q.Set(p[0], p[1])
+			}
+		}
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	}
+
+	// in the key/value format, the last occurrence of a key wins
+	quote := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	for _, p := range params {
+		if p[1] != "" {
+			dsn += fmt.Sprintf(" %s='%s'", p[0], quote.Replace(p[1]))
+		}
+	}
+	return dsn, nil
+}
+
+// openPostgres connects to the Postgres database of the DSN with the given options.
+func openPostgres(dsn string, opts *PostgresOptions, c *gorm.Config) (*gorm.DB, error) {
+	if opts == nil {
+		opts = &PostgresOptions{}
+	}
+	dsn, err := opts.applyTo(dsn)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := gorm.Open(postgres.Open(dsn), c)
+	if err != nil {
+		return nil, describePostgresError(err)
+	}
+
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return nil, err
+	}
+	opts.applyPool(sqlDB)
+	return conn, nil
+}
+
+// applyPool configures the connection pool of sqlDB with the pool settings that are set.
+func (o *PostgresOptions) applyPool(sqlDB *sql.DB) {
+	if o.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(o.MaxOpenConns)
+	}
+	if o.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(o.MaxIdleConns)
+	}
+	if o.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(o.ConnMaxLifetime)
+	}
+}
+
+// describePostgresError tells whether a connection to Postgres failed during the TLS negotiation
+// or the authentication, which are the usual suspects when connecting to a managed database.
+func describePostgresError(err error) error {
+	var (
+		pgErr          *pgconn.PgError
+		unknownAuthErr x509.UnknownAuthorityError
+		hostnameErr    x509.HostnameError
+		invalidCertErr x509.CertificateInvalidError
+		verifyErr      *tls.CertificateVerificationError
+		recordErr      tls.RecordHeaderError
+		alertErr       tls.AlertError
+	)
+	switch {
+	// class 28 is "invalid authorization specification", eg- a wrong password or no matching pg_hba.conf entry
+	case errors.As(err, &pgErr) && strings.HasPrefix(pgErr.Code, "28"):
+		return fmt.Errorf("authentication with the database failed: %w", err)
+	case strings.Contains(err.Error(), "failed SASL auth"):
+		return fmt.Errorf("authentication with the database failed: %w", err)
+	case errors.As(err, &unknownAuthErr), errors.As(err, &hostnameErr), errors.As(err, &invalidCertErr),
+		errors.As(err, &verifyErr), errors.As(err, &recordErr), errors.As(err, &alertErr),
+		strings.Contains(err.Error(), "tls error"):
+		return fmt.Errorf("TLS negotiation with the database failed: %w", err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/db/dialect"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+)
+
+// countRequestSQL returns the statement counting a request of a caller in a window,
+// which creates the counter of the window on its first request.
+func countRequestSQL(d dialect.Dialect) string {
+	return "INSERT INTO rate_limit_windows (caller, window_start, requests) VALUES (?, ?, 1) " +
+		d.OnConflictUpdate("caller, window_start") + " requests = rate_limit_windows.requests + 1"
+}
+
+// RateLimitWindows counts the requests of the callers of the API in windows of time stored in the database,
+// so that the replicas sharing it count the requests of a caller together.
+type RateLimitWindows struct {
+	db *gorm.DB
+}
+
+// NewRateLimitWindows creates a RateLimitWindows counting in conn.
+func NewRateLimitWindows(conn *gorm.DB) *RateLimitWindows {
+	return &RateLimitWindows{db: conn}
+}
+
+// Take counts a request of the caller in the window that started at start, and returns the number of requests
+// the caller made in the window so far, this one included.
+func (w *RateLimitWindows) Take(caller string, start time.Time) (int, error) {
+	start = start.UTC()
+	var requests int
+	err := RetryOnBusy(func() error {
+		// the row stays locked until the transaction ends, so the count read is the one this request made
+		return w.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(countRequestSQL(dialect.Of(tx)), caller, start).Error; err != nil {
+				return err
+			}
+			return tx.Model(&model.RateLimitWindow{}).
+				Where("caller = ? AND window_start = ?", caller, start).
+				Select("requests").Scan(&requests).Error
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count the request of %s: %w", caller, err)
+	}
+	return requests, nil
+}
+
+// Prune deletes the windows that started before the given time, ie- those of the callers that went away.
+func (w *RateLimitWindows) Prune(before time.Time) error {
+	err := RetryOnBusy(func() error {
+		return w.db.Where("window_start < ?", before.UTC()).Delete(&model.RateLimitWindow{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prune the rate limit windows: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func TestRateLimitWindows(t *testing.T) {
+	testhelpers.ForEachTestDB(t, func(t *testing.T, setup *testhelpers.TestDBSetup) {
+		windows := NewRateLimitWindows(setup.DB)
+		start := time.Unix(1_700_000_040, 0)
+
+		take := func(caller string, start time.Time) int {
+			t.Helper()
+			requests, err := windows.Take(caller, start)
+			testhelpers.AssertNoError(t, err)
+			return requests
+		}
+		testhelpers.AssertEqual(t, 1, take("user:alice", start))
+		testhelpers.AssertEqual(t, 2, take("user:alice", start))
+		testhelpers.AssertEqual(t, 1, take("user:bob", start))
+		// the windows are the same whatever the time zone of the replica
+		testhelpers.AssertEqual(t, 3, take("user:alice", start.In(time.FixedZone("UTC+2", 2*60*60))))
+		testhelpers.AssertEqual(t, 1, take("user:alice", start.Add(time.Minute)))
+
+		testhelpers.AssertNoError(t, windows.Prune(start.Add(time.Minute)))
+		var rows []model.RateLimitWindow
+		testhelpers.AssertNoError(t, setup.DB.Find(&rows).Error)
+		testhelpers.AssertEqual(t, 1, len(rows))
+		testhelpers.AssertEqual(t, 1, rows[0].Requests)
+	})
+}
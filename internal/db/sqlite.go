@@ -0,0 +1,152 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+const (
+	// sqliteBusyTimeoutMs is how long a connection waits for the write lock of the SQLite database before failing.
+	sqliteBusyTimeoutMs = 5000
+
+	// sqliteMaxOpenConns bounds the connections to the SQLite database. SQLite has a single writer anyway,
+	// the requests over the limit wait for a connection in the pool rather than for the lock in SQLite.
+	sqliteMaxOpenConns = 8
+
+	// sqliteWritesWarningPerMinute is the number of writes per minute above which Postgres is recommended.
+	sqliteWritesWarningPerMinute = 6000
+)
+
+const (
+	// busyRetries is the number of times RetryOnBusy runs a function again while the database is busy.
+	busyRetries = 5
+	// busyRetryDelay is the delay before the first retry of RetryOnBusy, it doubles with every retry.
+	busyRetryDelay = 20 * time.Millisecond
+)
+
+// sqliteDriver describes the driver of the embedded SQLite database. It is written in Go (a translation of the
+// C library), so mcpjungle builds without CGO and cross-compiles to static binaries.
+const sqliteDriver = "pure Go (modernc.org/sqlite)"
+
+// ErrDatabaseBusy is returned by RetryOnBusy when the SQLite database stayed locked by other connections.
+var ErrDatabaseBusy = errors.New("the database is busy")
+
+// sqliteDSN returns the DSN of the SQLite database file at path.
+//
+// The database is in WAL mode so that reads are not blocked by writes, and write transactions take the write lock
+// when they begin (BEGIN IMMEDIATE). That way it is the single writer: a transaction that reads before it writes
+// waits for the lock instead of failing right away with "database is locked", when another one committed in between.
+func sqliteDSN(path string) string {
+	q := url.Values{}
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", sqliteBusyTimeoutMs))
+	// safe in WAL mode, a crash may lose the last transactions but never corrupts the database
+	q.Add("_pragma", "synchronous(NORMAL)")
+	q.Set("_txlock", "immediate")
+	return path + "?" + q.Encode()
+}
+
+// openSQLite opens the SQLite database file at path, creating it if it doesn't exist.
+func openSQLite(path string, c *gorm.Config) (*gorm.DB, error) {
+	conn, err := gorm.Open(sqlite.Open(sqliteDSN(path)), c)
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(sqliteMaxOpenConns)
+	// the pragmas are run for every new connection, keep them open
+	sqlDB.SetMaxIdleConns(sqliteMaxOpenConns)
+
+	if err := warnOnHighWriteRate(conn); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// warnOnHighWriteRate logs a recommendation to use Postgres, once, if the SQLite database receives more than
+// sqliteWritesWarningPerMinute writes in a minute.
+func warnOnHighWriteRate(conn *gorm.DB) error {
+	var (
+		mu          sync.Mutex
+		windowStart time.Time
+		writes      int
+		warned      bool
+	)
+	count := func(tx *gorm.DB) {
+		if tx.Error != nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if warned {
+			return
+		}
+		now := time.Now()
+		if now.Sub(windowStart) > time.Minute {
+			windowStart, writes = now, 0
+		}
+		writes++
+		if writes > sqliteWritesWarningPerMinute {
+			warned = true
+			log.Printf(
+				"[db] WARNING: the SQLite database received more than %d writes in a minute, "+
+					"consider using Postgres (DATABASE_URL) for this volume of writes",
+				sqliteWritesWarningPerMinute,
+			)
+		}
+	}
+
+	cb := conn.Callback()
+	if err := cb.Create().After("gorm:create").Register("mcpjungle:count_writes", count); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("mcpjungle:count_writes", count); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Register("mcpjungle:count_writes", count)
+}
+
+// IsBusy reports whether err is SQLite failing because the database is locked by another connection.
+// It is false for the errors of other databases.
+func IsBusy(err error) bool {
+	var coded interface{ Code() int }
+	if !errors.As(err, &coded) {
+		return false
+	}
+	// the primary result code is in the lower byte of the extended ones
+	switch coded.Code() & 0xff {
+	case 5, 6: // SQLITE_BUSY, SQLITE_LOCKED
+		return true
+	}
+	return false
+}
+
+// RetryOnBusy runs fn, and runs it again with an increasing delay while it fails because the SQLite database is busy.
+// It is meant for short transactions, which give up the lock quickly: fn must be safe to run again, eg- a transaction
+// that only changes the database. If the database is still busy after the retries, the error wraps ErrDatabaseBusy.
+func RetryOnBusy(fn func() error) error {
+	delay := busyRetryDelay
+	for i := 0; ; i++ {
+		err := fn()
+		if !IsBusy(err) {
+			return err
+		}
+		if i == busyRetries {
+			return fmt.Errorf("%w: %w", ErrDatabaseBusy, err)
+		}
+		// the jitter keeps the transactions that failed together from retrying together
+		time.Sleep(delay + rand.N(delay))
+		delay *= 2
+	}
+}
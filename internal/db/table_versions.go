@@ -0,0 +1,67 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mcpjungle/mcpjungle/internal/db/dialect"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+)
+
+// bumpTableVersionSQL returns the statement incrementing the version of a table,
+// which creates its counter on the first change.
+func bumpTableVersionSQL(d dialect.Dialect) string {
+	return "INSERT INTO table_versions (name, version) VALUES (?, 1) " +
+		d.OnConflictUpdate("name") + " version = table_versions.version + 1"
+}
+
+// TrackTableVersions registers callbacks on conn that increment the version of model.VersionedTables
+// on every create, update or delete that affects their rows.
+// The counter is updated in the same transaction as the change, so it can't miss one, whichever code path made it.
+func TrackTableVersions(conn *gorm.DB) error {
+	cb := conn.Callback()
+	if err := cb.Create().After("gorm:create").Register("mcpjungle:bump_table_version", bumpTableVersion); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("mcpjungle:bump_table_version", bumpTableVersion); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Register("mcpjungle:bump_table_version", bumpTableVersion)
+}
+
+func bumpTableVersion(tx *gorm.DB) {
+	table := tx.Statement.Table
+	if tx.Error != nil || tx.RowsAffected == 0 || !slices.Contains(model.VersionedTables, table) {
+		return
+	}
+	err := tx.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Exec(bumpTableVersionSQL(dialect.Of(tx)), table).Error
+	if err != nil {
+		// fail the change rather than leaving clients with a stale list
+		_ = tx.AddError(fmt.Errorf("failed to increment the version of table %s: %w", table, err))
+	}
+}
+
+// TableVersions reads the versions of the tables tracked by TrackTableVersions.
+type TableVersions struct {
+	db *gorm.DB
+}
+
+// NewTableVersions creates a TableVersions reading from conn.
+func NewTableVersions(conn *gorm.DB) *TableVersions {
+	return &TableVersions{db: conn}
+}
+
+// Get returns the versions of the given tables, in the same order.
+// A table that was never changed is at version 0.
+func (v *TableVersions) Get(tables ...string) ([]uint, error) {
+	var rows []model.TableVersion
+	if err := v.db.Where("name IN ?", tables).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get the versions of tables %v: %w", tables, err)
+	}
+	versions := make([]uint, len(tables))
+	for _, r := range rows {
+		versions[slices.Index(tables, r.Name)] = r.Version
+	}
+	return versions, nil
+}
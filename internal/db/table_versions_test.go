@@ -0,0 +1,59 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestTrackTableVersions(t *testing.T) {
+	testhelpers.ForEachTestDB(t, func(t *testing.T, setup *testhelpers.TestDBSetup) {
+		conn := setup.DB
+		testhelpers.AssertNoError(t, TrackTableVersions(conn))
+		versions := NewTableVersions(conn)
+
+		// get returns the versions of the tool groups and tools tables
+		get := func() [2]uint {
+			t.Helper()
+			v, err := versions.Get(model.TableToolGroups, model.TableTools)
+			testhelpers.AssertNoError(t, err)
+			return [2]uint{v[0], v[1]}
+		}
+		testhelpers.AssertEqual(t, [2]uint{0, 0}, get())
+
+		group := &model.ToolGroup{Name: "ci-tools", IncludedTools: datatypes.JSON(`[]`)}
+		testhelpers.AssertNoError(t, conn.Create(group).Error)
+		testhelpers.AssertEqual(t, [2]uint{1, 0}, get())
+
+		testhelpers.AssertNoError(t, conn.Model(group).Update("description", "Tools of the CI").Error)
+		testhelpers.AssertEqual(t, [2]uint{2, 0}, get())
+
+		// an update that matches no row doesn't change the table
+		testhelpers.AssertNoError(t, conn.Model(&model.ToolGroup{}).Where("name = ?", "cd-tools").Update("description", "").Error)
+		testhelpers.AssertEqual(t, [2]uint{2, 0}, get())
+
+		// a change that is rolled back doesn't count
+		errRollback := errors.New("rollback")
+		err := conn.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Delete(group).Error; err != nil {
+				return err
+			}
+			return errRollback
+		})
+		testhelpers.AssertTrue(t, errors.Is(err, errRollback), "the transaction should be rolled back")
+		testhelpers.AssertEqual(t, [2]uint{2, 0}, get())
+
+		testhelpers.AssertNoError(t, conn.Delete(group).Error)
+		testhelpers.AssertEqual(t, [2]uint{3, 0}, get())
+
+		// tables that are not versioned are not counted
+		testhelpers.AssertNoError(t, conn.Create(&model.Webhook{Name: "ci", URL: "https://ci.example.com/hook"}).Error)
+		var count int64
+		testhelpers.AssertNoError(t, conn.Model(&model.TableVersion{}).Count(&count).Error)
+		testhelpers.AssertEqual(t, int64(1), count)
+	})
+}
@@ -0,0 +1,244 @@
+// Package docker is a small client of the Docker Engine API, limited to what mcpjungle needs to launch stdio
+// MCP servers in containers: pulling their image, then creating, attaching to, starting and removing their containers.
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// DefaultHost is the socket of the local Docker daemon.
+	DefaultHost = "unix:///var/run/docker.sock"
+
+	// apiVersion is the version of the Engine API the requests are sent with, supported since Docker 20.10.
+	apiVersion = "v1.41"
+
+	// maxErrorBodyBytes bounds how much of an error response is read.
+	maxErrorBodyBytes = 64 << 10
+)
+
+// ErrDaemonUnavailable is matched by the errors returned when the Docker daemon can't be reached,
+// eg- because Docker is not installed or not running.
+var ErrDaemonUnavailable = errors.New("the Docker daemon is unavailable")
+
+// daemonError is the failure to reach the Docker daemon, it matches ErrDaemonUnavailable.
+type daemonError struct {
+	host string
+	err  error
+}
+
+func (e *daemonError) Error() string {
+	return fmt.Sprintf(
+		"the Docker daemon is not reachable at %s, make sure that Docker is running or set DOCKER_HOST: %v",
+		e.host, e.err,
+	)
+}
+
+func (e *daemonError) Unwrap() []error { return []error{e.err, ErrDaemonUnavailable} }
+
+// Error is an error response of the Docker daemon.
+type Error struct {
+	StatusCode int
+	Message    string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("docker returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("docker returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 response of the Docker daemon, eg- for a container that doesn't exist.
+func IsNotFound(err error) bool {
+	var dErr *Error
+	return errors.As(err, &dErr) && dErr.StatusCode == http.StatusNotFound
+}
+
+// Client sends requests to a Docker daemon.
+type Client struct {
+	host string
+	dial func(ctx context.Context) (net.Conn, error)
+	http *http.Client
+}
+
+// NewClient creates a Client of the Docker daemon listening at host, a unix:// or tcp:// address like DOCKER_HOST.
+// DefaultHost is used if host is empty. The daemon is only contacted by the first request.
+func NewClient(host string) (*Client, error) {
+	if host == "" {
+		host = DefaultHost
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Docker host '%s': %w", host, err)
+	}
+	var network, addr string
+	switch u.Scheme {
+	case "unix":
+		network, addr = "unix", u.Path
+	case "tcp", "http":
+		network, addr = "tcp", u.Host
+	default:
+		return nil, fmt.Errorf("unsupported Docker host '%s', must be a unix:// or tcp:// address", host)
+	}
+	if addr == "" {
+		return nil, fmt.Errorf("invalid Docker host '%s', it has no address", host)
+	}
+
+	c := &Client{host: host}
+	c.dial = func(ctx context.Context) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, network, addr)
+	}
+	// the requests have no timeout, pulling an image can take minutes, they are bounded by their context instead
+	c.http = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) { return c.dial(ctx) },
+	}}
+	return c, nil
+}
+
+// Host returns the address of the Docker daemon.
+func (c *Client) Host() string {
+	return c.host
+}
+
+// Ping checks that the Docker daemon is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := c.do(ctx, http.MethodGet, "/_ping", nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// newRequest creates a request to the path of the Engine API, eg- /containers/create.
+func (c *Client) newRequest(
+	ctx context.Context, method, path string, query url.Values, body any,
+) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	// the host of the URL is ignored, the requests are sent to the daemon's address
+	u := "http://docker/" + apiVersion + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// do sends a request to the Docker daemon, returning the response if its status is 2xx or 304 and an *Error otherwise.
+// The caller must close the body of the response.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body any) (*http.Response, error) {
+	req, err := c.newRequest(ctx, method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &daemonError{host: c.host, err: err}
+	}
+	if resp.StatusCode >= 400 {
+		return nil, readError(resp)
+	}
+	return resp, nil
+}
+
+// readError reads the error response of the Docker daemon and closes its body.
+func readError(resp *http.Response) error {
+	defer resp.Body.Close()
+	dErr := &Error{StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	if json.Unmarshal(data, dErr) != nil {
+		dErr.Message = strings.TrimSpace(string(data))
+	}
+	return dErr
+}
+
+// PullProgress is a step of the pull of an image, eg- {ID: "a3ed95caeb02", Status: "Pull complete"}.
+// ID is the layer the step is about, empty for the steps about the whole image.
+type PullProgress struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// ImageExists reports whether the image is present on the Docker host.
+func (c *Client) ImageExists(ctx context.Context, image string) (bool, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/images/"+image+"/json", nil, nil)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+// PullImage pulls the image, reporting every step of the pull to progress if it is not nil.
+// An image without a tag or digest is pulled with the latest tag, like docker pull does.
+func (c *Client) PullImage(ctx context.Context, image string, progress func(PullProgress)) error {
+	query := url.Values{"fromImage": {image}}
+	if !hasTagOrDigest(image) {
+		query.Set("tag", "latest")
+	}
+	resp, err := c.do(ctx, http.MethodPost, "/images/create", query, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// the daemon streams the progress of the pull, its failures are reported in the stream
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var msg struct {
+			PullProgress
+			Error string `json:"error"`
+		}
+		if err := dec.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to read the progress of the pull of %s: %w", image, err)
+		}
+		if msg.Error != "" {
+			return fmt.Errorf("failed to pull the image %s: %s", image, msg.Error)
+		}
+		if progress != nil {
+			progress(msg.PullProgress)
+		}
+	}
+}
+
+// hasTagOrDigest reports whether the reference of an image has a tag or a digest, eg- node:22 or node@sha256:...
+// The port of a registry, eg- in localhost:5000/node, is not a tag.
+func hasTagOrDigest(image string) bool {
+	if strings.Contains(image, "@") {
+		return true
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	return strings.Contains(name, ":")
+}
@@ -0,0 +1,173 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+// newTestClient returns a client of a fake daemon listening on a unix socket, like the local Docker daemon.
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	l, err := net.Listen("unix", socket)
+	testhelpers.AssertNoError(t, err)
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Listener = l
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient("unix://" + socket)
+	testhelpers.AssertNoError(t, err)
+	return c
+}
+
+// writeFrame writes a frame of the multiplexed output of a container.
+func writeFrame(w io.Writer, stream byte, p []byte) error {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(p)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(p)
+	return err
+}
+
+func TestNewClient(t *testing.T) {
+	c, err := NewClient("")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, DefaultHost, c.Host())
+
+	_, err = NewClient("tcp://docker:2375")
+	testhelpers.AssertNoError(t, err)
+
+	for _, host := range []string{"ssh://docker", "tcp://", "unix://"} {
+		_, err := NewClient(host)
+		testhelpers.AssertError(t, err)
+	}
+}
+
+func TestDaemonUnavailable(t *testing.T) {
+	c, err := NewClient("unix://" + filepath.Join(t.TempDir(), "missing.sock"))
+	testhelpers.AssertNoError(t, err)
+
+	err = c.Ping(context.Background())
+	testhelpers.AssertTrue(t, errors.Is(err, ErrDaemonUnavailable), "expected ErrDaemonUnavailable")
+	testhelpers.AssertStringContains(t, err.Error(), "the Docker daemon is not reachable at unix://")
+
+	_, err = c.AttachContainer(context.Background(), "abc")
+	testhelpers.AssertTrue(t, errors.Is(err, ErrDaemonUnavailable), "expected ErrDaemonUnavailable")
+}
+
+func TestPullImage(t *testing.T) {
+	var queries []string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.41/images/mcp/time/json":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"No such image: mcp/time"}`))
+		case "/v1.41/images/create":
+			queries = append(queries, r.URL.RawQuery)
+			enc := json.NewEncoder(w)
+			_ = enc.Encode(map[string]string{"status": "Pulling from mcp/time", "id": "latest"})
+			_ = enc.Encode(map[string]string{"status": "Pull complete", "id": "a3ed95caeb02"})
+			if r.URL.Query().Get("fromImage") == "mcp/broken:1" {
+				_ = enc.Encode(map[string]string{"error": "manifest unknown"})
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	exists, err := c.ImageExists(ctx, "mcp/time")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, !exists, "expected the image to be missing")
+
+	var steps []PullProgress
+	testhelpers.AssertNoError(t, c.PullImage(ctx, "mcp/time", func(p PullProgress) { steps = append(steps, p) }))
+	testhelpers.AssertEqual(t, 2, len(steps))
+	testhelpers.AssertEqual(t, PullProgress{ID: "a3ed95caeb02", Status: "Pull complete"}, steps[1])
+	testhelpers.AssertEqual(t, "fromImage=mcp%2Ftime&tag=latest", queries[0])
+
+	err = c.PullImage(ctx, "mcp/broken:1", nil)
+	testhelpers.AssertStringContains(t, err.Error(), "failed to pull the image mcp/broken:1: manifest unknown")
+	testhelpers.AssertEqual(t, "fromImage=mcp%2Fbroken%3A1", queries[1])
+}
+
+func TestHasTagOrDigest(t *testing.T) {
+	tests := map[string]bool{
+		"node":                     false,
+		"node:22":                  true,
+		"localhost:5000/node":      false,
+		"localhost:5000/node:22":   true,
+		"ghcr.io/org/node@sha256:": true,
+	}
+	for image, want := range tests {
+		testhelpers.AssertEqual(t, want, hasTagOrDigest(image))
+	}
+}
+
+func TestAttachContainer(t *testing.T) {
+	var wg sync.WaitGroup
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1.41/containers/abc/attach" || r.Header.Get("Upgrade") != "tcp" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			_, _ = conn.Write([]byte("HTTP/1.1 101 UPGRADED\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n"))
+			_ = writeFrame(conn, 2, []byte("starting\n"))
+			// the container echoes its stdin until it is closed
+			scanner := bufio.NewScanner(buf)
+			for scanner.Scan() {
+				_ = writeFrame(conn, 1, append(scanner.Bytes(), '\n'))
+			}
+		}()
+	}))
+
+	s, err := c.AttachContainer(context.Background(), "abc")
+	testhelpers.AssertNoError(t, err)
+	defer s.Close()
+
+	stderr := bufio.NewReader(s.Stderr)
+	line, err := stderr.ReadString('\n')
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "starting\n", line)
+
+	_, err = s.Write([]byte("ping\n"))
+	testhelpers.AssertNoError(t, err)
+	stdout := bufio.NewReader(s.Stdout)
+	line, err = stdout.ReadString('\n')
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "ping\n", line)
+
+	// closing the stdin makes the container exit, which ends the stream
+	testhelpers.AssertNoError(t, s.CloseStdin())
+	<-s.Done()
+	_, err = stdout.ReadString('\n')
+	testhelpers.AssertEqual(t, io.EOF, err)
+	wg.Wait()
+
+	_, err = c.AttachContainer(context.Background(), "missing")
+	testhelpers.AssertTrue(t, IsNotFound(err), "expected a not found error")
+}
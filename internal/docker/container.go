@@ -0,0 +1,267 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ContainerSpec describes a container attached to by its creator, whose stdin is open until the creator closes it.
+type ContainerSpec struct {
+	// Name of the container, generated by Docker if empty.
+	Name  string
+	Image string
+	// Cmd overrides the default command of the image if not empty.
+	Cmd []string
+	// Env is the environment of the container, in the KEY=VALUE format.
+	Env    []string
+	Labels map[string]string
+
+	// Binds are the volumes of the container, eg- /srv/data:/data:ro
+	Binds []string
+	// NetworkMode is the network of the container, eg- none or host, the default bridge network if empty.
+	NetworkMode string
+	// MemoryBytes limits the memory of the container, 0 for no limit.
+	MemoryBytes int64
+	// NanoCPUs limits the CPUs of the container, in billionths of a CPU, 0 for no limit.
+	NanoCPUs int64
+}
+
+// CreateContainer creates the container described by spec and returns its ID.
+func (c *Client) CreateContainer(ctx context.Context, spec ContainerSpec) (string, error) {
+	type hostConfig struct {
+		Binds       []string `json:",omitempty"`
+		NetworkMode string   `json:",omitempty"`
+		Memory      int64    `json:",omitempty"`
+		NanoCpus    int64    `json:",omitempty"`
+	}
+	body := struct {
+		Image        string
+		Cmd          []string          `json:",omitempty"`
+		Env          []string          `json:",omitempty"`
+		Labels       map[string]string `json:",omitempty"`
+		AttachStdin  bool
+		AttachStdout bool
+		AttachStderr bool
+		OpenStdin    bool
+		// the stdin of the container is closed when its attached client closes it, which stops stdio servers
+		StdinOnce  bool
+		Tty        bool
+		HostConfig hostConfig
+	}{
+		Image: spec.Image, Cmd: spec.Cmd, Env: spec.Env, Labels: spec.Labels,
+		AttachStdin: true, AttachStdout: true, AttachStderr: true, OpenStdin: true, StdinOnce: true,
+		HostConfig: hostConfig{
+			Binds: spec.Binds, NetworkMode: spec.NetworkMode, Memory: spec.MemoryBytes, NanoCpus: spec.NanoCPUs,
+		},
+	}
+	var query url.Values
+	if spec.Name != "" {
+		query = url.Values{"name": {spec.Name}}
+	}
+	resp, err := c.do(ctx, http.MethodPost, "/containers/create", query, body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var created struct {
+		ID string `json:"Id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil || created.ID == "" {
+		return "", fmt.Errorf("invalid response of Docker to the creation of a container: %v", err)
+	}
+	return created.ID, nil
+}
+
+// StartContainer starts a created container.
+func (c *Client) StartContainer(ctx context.Context, id string) error {
+	resp, err := c.do(ctx, http.MethodPost, "/containers/"+id+"/start", nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// RemoveContainer removes the container along with its anonymous volumes, killing it if it is running.
+// A container that doesn't exist anymore is not an error.
+func (c *Client) RemoveContainer(ctx context.Context, id string) error {
+	resp, err := c.do(ctx, http.MethodDelete, "/containers/"+id, url.Values{"force": {"1"}, "v": {"1"}}, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// ContainerExitCode returns the exit code of a container that exited.
+func (c *Client) ContainerExitCode(ctx context.Context, id string) (int, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/containers/"+id+"/json", nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	var inspect struct {
+		State struct {
+			ExitCode int `json:"ExitCode"`
+		} `json:"State"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&inspect); err != nil {
+		return 0, fmt.Errorf("invalid response of Docker to the inspection of container %s: %w", id, err)
+	}
+	return inspect.State.ExitCode, nil
+}
+
+// ListContainers returns the IDs of the containers, running or not, that have the label with the given value.
+func (c *Client) ListContainers(ctx context.Context, label, value string) ([]string, error) {
+	filters, err := json.Marshal(map[string][]string{"label": {label + "=" + value}})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(ctx, http.MethodGet, "/containers/json", url.Values{"all": {"1"}, "filters": {string(filters)}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var containers []struct {
+		ID string `json:"Id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&containers); err != nil {
+		return nil, fmt.Errorf("invalid response of Docker to the listing of containers: %w", err)
+	}
+	ids := make([]string, len(containers))
+	for i, ctr := range containers {
+		ids[i] = ctr.ID
+	}
+	return ids, nil
+}
+
+// Stream is the connection to the stdio of a container, opened by AttachContainer.
+// Writing to it writes to the stdin of the container.
+type Stream struct {
+	conn net.Conn
+	// Stdout and Stderr read what the container writes to them, until it exits
+	Stdout io.Reader
+	Stderr io.ReadCloser
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// AttachContainer attaches to the stdin, stdout and stderr of the container.
+// It is called before the container is started, so that none of its output is missed.
+func (c *Client) AttachContainer(ctx context.Context, id string) (*Stream, error) {
+	query := url.Values{"stream": {"1"}, "stdin": {"1"}, "stdout": {"1"}, "stderr": {"1"}}
+	req, err := c.newRequest(ctx, http.MethodPost, "/containers/"+id+"/attach", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	// the connection is hijacked by the daemon to carry the stdio of the container
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "tcp")
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &daemonError{host: c.host, err: err}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := func() (*http.Response, error) {
+		if err := req.Write(conn); err != nil {
+			return nil, err
+		}
+		return http.ReadResponse(br, req)
+	}()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to attach to container %s: %w", id, err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols && resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, readError(resp)
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	stdout, stdoutW := io.Pipe()
+	stderr, stderrW := io.Pipe()
+	s := &Stream{conn: conn, Stdout: stdout, Stderr: stderr, done: make(chan struct{})}
+	go s.demux(br, stdoutW, stderrW)
+	return s, nil
+}
+
+// demux splits the output of the container, multiplexed in frames of stdout and stderr, until it exits.
+// A frame starts with a header of 8 bytes: the stream it belongs to, 3 zero bytes and its size in big endian.
+// The output written to a stream that is not read anymore is discarded.
+func (s *Stream) demux(r io.Reader, stdout, stderr *io.PipeWriter) {
+	defer close(s.done)
+	var err error
+	defer func() {
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		stdout.CloseWithError(err)
+		stderr.CloseWithError(err)
+	}()
+
+	header := make([]byte, 8)
+	for {
+		if _, err = io.ReadFull(r, header); err != nil {
+			return
+		}
+		var w io.Writer = io.Discard
+		switch header[0] {
+		case 1:
+			w = stdout
+		case 2:
+			w = stderr
+		}
+		frame := io.LimitReader(r, int64(binary.BigEndian.Uint32(header[4:])))
+		if _, werr := io.Copy(w, frame); werr != nil {
+			// the reader of the stream is gone, the rest of the frame is skipped
+			if _, err = io.Copy(io.Discard, frame); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Write writes to the stdin of the container.
+func (s *Stream) Write(p []byte) (int, error) {
+	return s.conn.Write(p)
+}
+
+// CloseStdin closes the stdin of the container, which makes stdio servers exit.
+func (s *Stream) CloseStdin() error {
+	if cw, ok := s.conn.(interface{ CloseWrite() error }); ok {
+		return cw.CloseWrite()
+	}
+	return nil
+}
+
+// Done is closed once the container exited, or the stream was closed.
+func (s *Stream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close closes the connection to the container, it doesn't stop it.
+func (s *Stream) Close() error {
+	var err error
+	s.closeOnce.Do(func() { err = s.conn.Close() })
+	return err
+}
@@ -0,0 +1,230 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/mcpjungle/mcpjungle/internal/db/dialect"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// copyBatchSize is the number of rows CopyDatabase copies in every transaction.
+const copyBatchSize = 500
+
+// copiedTables are the tables CopyDatabase copies, in the order they are copied, the referenced ones first.
+// The versions of the tables are not copied, the target database counts the changes of its tables from the copy on,
+// and neither is the state of the running servers, ie- their leader lease, the outcomes of their health checks
+// and the requests counted by their rate limits.
+var copiedTables = []any{
+	&model.ServerConfig{},
+	&model.User{},
+	&model.McpServer{},
+	&model.Tool{},
+	&model.Prompt{},
+	&model.ToolGroup{},
+	&model.McpClient{},
+	&model.Webhook{},
+	&model.WebhookDelivery{},
+	&model.IdempotencyKey{},
+}
+
+var (
+	// ErrSourceNotMigrated is returned when the schema of the source database of a copy is not up to date.
+	ErrSourceNotMigrated = errors.New("the schema of the source database is not up to date")
+	// ErrTargetNotEmpty is returned when the target database of a copy already has rows.
+	ErrTargetNotEmpty = errors.New("the target database is not empty")
+	// ErrCopyMismatch is returned when the tables of the target database don't have as many rows as the source
+	// once copied.
+	ErrCopyMismatch = errors.New("the copied tables don't match the source")
+)
+
+// TableCopy is a table copied by CopyDatabase, with its number of rows in both databases.
+type TableCopy struct {
+	Table      string `json:"table"`
+	SourceRows int64  `json:"source_rows"`
+	// TargetRows is the number of rows of the table in the target database, once copied.
+	TargetRows int64 `json:"target_rows"`
+}
+
+// CopyPlan describes the copy of a database to another one.
+type CopyPlan struct {
+	Tables []TableCopy `json:"tables"`
+	// CreateSchema is true if the target database lacks tables, the schema is migrated before the copy.
+	CreateSchema bool `json:"create_schema"`
+}
+
+// PlanCopy checks that src can be copied to dst, and returns the number of rows of every table to copy.
+// The schema of src must be up to date, and dst must not have any row in the copied tables.
+// Neither database is changed.
+func PlanCopy(ctx context.Context, src, dst *gorm.DB) (*CopyPlan, error) {
+	src, dst = src.WithContext(ctx), dst.WithContext(ctx)
+	plan := &CopyPlan{}
+	for _, m := range copiedTables {
+		sch, err := parseSchema(src, m)
+		if err != nil {
+			return nil, err
+		}
+		if !src.Migrator().HasTable(sch.Table) {
+			return nil, fmt.Errorf(
+				"%w: it has no %s table, start an mcpjungle server of this version on it once to migrate it",
+				ErrSourceNotMigrated, sch.Table,
+			)
+		}
+		var n int64
+		if err := src.Table(sch.Table).Count(&n).Error; err != nil {
+			return nil, fmt.Errorf("failed to count the rows of %s in the source database: %w", sch.Table, err)
+		}
+		plan.Tables = append(plan.Tables, TableCopy{Table: sch.Table, SourceRows: n})
+
+		if !dst.Migrator().HasTable(sch.Table) {
+			plan.CreateSchema = true
+			continue
+		}
+		if err := dst.Table(sch.Table).Count(&n).Error; err != nil {
+			return nil, fmt.Errorf("failed to count the rows of %s in the target database: %w", sch.Table, err)
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("%w: its %s table has %d rows", ErrTargetNotEmpty, sch.Table, n)
+		}
+	}
+	return plan, nil
+}
+
+// CopyDatabase copies all the rows of src to dst, including the soft-deleted ones, table after table in batches
+// of copyBatchSize rows with a transaction per batch. Every value is copied as it is stored, so the IDs are kept
+// and the credentials are copied as they are, whether they are stored in clear or as references to Vault secrets.
+//
+// The schema of dst is migrated first, it must not have any row. progress, if not nil, is called after every batch.
+// If the copy fails, the rows copied so far are deleted from dst, so that it can be started again.
+// Once copied, the rows of every table are counted again in both databases, and ErrCopyMismatch is returned
+// if they differ. The source database must not change during the copy, ie- no server must be running on it.
+func CopyDatabase(
+	ctx context.Context, src, dst *gorm.DB, progress func(table string, copied, total int64),
+) (*CopyPlan, error) {
+	plan, err := PlanCopy(ctx, src, dst)
+	if err != nil {
+		return nil, err
+	}
+	if err := Migrate(dst.WithContext(ctx)); err != nil {
+		return nil, fmt.Errorf("failed to migrate the schema of the target database: %w", err)
+	}
+
+	for i, m := range copiedTables {
+		sch, err := parseSchema(src, m)
+		if err != nil {
+			return nil, err
+		}
+		err = copyTable(ctx, src, dst, sch, func(copied int64) {
+			if progress != nil {
+				progress(sch.Table, copied, plan.Tables[i].SourceRows)
+			}
+		})
+		if err != nil {
+			if cerr := clearCopiedTables(dst); cerr != nil {
+				return nil, fmt.Errorf("%w, and the rows copied so far could not be deleted: %w", err, cerr)
+			}
+			return nil, err
+		}
+	}
+
+	// the counts are compared against the source as it is now, in case it changed during the copy
+	var mismatched []string
+	for i := range plan.Tables {
+		t := &plan.Tables[i]
+		if err := src.WithContext(ctx).Table(t.Table).Count(&t.SourceRows).Error; err != nil {
+			return nil, fmt.Errorf("failed to count the rows of %s in the source database: %w", t.Table, err)
+		}
+		if err := dst.WithContext(ctx).Table(t.Table).Count(&t.TargetRows).Error; err != nil {
+			return nil, fmt.Errorf("failed to count the rows of %s in the target database: %w", t.Table, err)
+		}
+		if t.SourceRows != t.TargetRows {
+			mismatched = append(mismatched, fmt.Sprintf("%s has %d rows instead of %d", t.Table, t.TargetRows, t.SourceRows))
+		}
+	}
+	if len(mismatched) > 0 {
+		return plan, fmt.Errorf("%w: %v", ErrCopyMismatch, mismatched)
+	}
+	return plan, nil
+}
+
+// copyTable copies the rows of a table in the order of their primary key, calling progress after every batch
+// with the number of rows copied so far.
+func copyTable(ctx context.Context, src, dst *gorm.DB, sch *schema.Schema, progress func(copied int64)) error {
+	pk := sch.PrioritizedPrimaryField
+	rows := reflect.New(reflect.SliceOf(sch.ModelType))
+	var last any
+	var copied int64
+	for {
+		rows.Elem().SetLen(0)
+		q := src.WithContext(ctx).Unscoped().Order(pk.DBName).Limit(copyBatchSize)
+		if last != nil {
+			q = q.Where(clause.Gt{Column: clause.Column{Name: pk.DBName}, Value: last})
+		}
+		if err := q.Find(rows.Interface()).Error; err != nil {
+			return fmt.Errorf("failed to read the rows of %s: %w", sch.Table, err)
+		}
+		n := rows.Elem().Len()
+		if n == 0 {
+			break
+		}
+
+		// the rows are inserted as maps, so that the values equal to the defaults of the columns are kept,
+		// eg- a disabled tool whose enabled column defaults to true
+		batch := make([]map[string]any, 0, n)
+		for i := range n {
+			v := rows.Elem().Index(i)
+			row := make(map[string]any, len(sch.DBNames))
+			for _, name := range sch.DBNames {
+				row[name], _ = sch.FieldsByDBName[name].ValueOf(ctx, v)
+			}
+			batch = append(batch, row)
+		}
+		last = batch[n-1][pk.DBName]
+		err := dst.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return tx.Table(sch.Table).Create(&batch).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy the rows of %s: %w", sch.Table, err)
+		}
+		copied += int64(n)
+		progress(copied)
+		if n < copyBatchSize {
+			break
+		}
+	}
+
+	// the IDs were inserted as they are, the next ones generated by the target database must follow them
+	if pk.AutoIncrement {
+		if q := dialect.Of(dst).ResetSequenceSQL(sch.Table, pk.DBName); q != "" {
+			if err := dst.WithContext(ctx).Exec(q).Error; err != nil {
+				return fmt.Errorf("failed to reset the sequence of %s: %w", sch.Table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// clearCopiedTables deletes the rows of the copied tables from the target database of a failed copy.
+// It doesn't use the context of the copy, which may be the reason it failed.
+func clearCopiedTables(dst *gorm.DB) error {
+	for _, m := range slices.Backward(copiedTables) {
+		if err := dst.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(m).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseSchema(db *gorm.DB, m any) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(m); err != nil {
+		return nil, fmt.Errorf("failed to parse the schema of %T: %w", m, err)
+	}
+	return stmt.Schema, nil
+}
@@ -0,0 +1,144 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// openTestFileDB opens a new SQLite database in a file, unlike an in-memory one it is shared by the connections.
+func openTestFileDB(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), name)), &gorm.Config{})
+	testhelpers.AssertNoError(t, err)
+	sqlDB, err := db.DB()
+	testhelpers.AssertNoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return db
+}
+
+func seedCopySource(t *testing.T) *gorm.DB {
+	t.Helper()
+	src := openTestFileDB(t, "source.db")
+	testhelpers.AssertNoError(t, Migrate(src))
+
+	testhelpers.AssertNoError(t, src.Create(&model.ServerConfig{Mode: model.ModeDev, Initialized: true}).Error)
+	servers := make([]*model.McpServer, 0, 2)
+	for _, name := range []string{"github", "slack"} {
+		s, err := model.NewStreamableHTTPServer(name, "", "https://"+name+".example.com/mcp", "token-"+name, types.SessionModeStateless)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertNoError(t, src.Create(s).Error)
+		servers = append(servers, s)
+	}
+	// a gap in the IDs, which must be kept
+	testhelpers.AssertNoError(t, src.Unscoped().Delete(servers[0]).Error)
+
+	tools := []*model.Tool{
+		{Name: "post_message", Enabled: true, ServerID: servers[1].ID},
+		{Name: "delete_channel", Enabled: true, ServerID: servers[1].ID},
+		{Name: "archive_channel", Enabled: true, ServerID: servers[1].ID},
+	}
+	for _, tool := range tools {
+		testhelpers.AssertNoError(t, src.Create(tool).Error)
+	}
+	testhelpers.AssertNoError(t, src.Model(tools[1]).Update("enabled", false).Error)
+	testhelpers.AssertNoError(t, src.Delete(tools[2]).Error)
+
+	testhelpers.AssertNoError(t, src.Create(&model.Webhook{Name: "audit", URL: "https://hooks.example.com", Secret: "s3cr3t"}).Error)
+	return src
+}
+
+func TestCopyDatabase(t *testing.T) {
+	src := seedCopySource(t)
+	dst := openTestFileDB(t, "target.db")
+	ctx := context.Background()
+
+	plan, err := PlanCopy(ctx, src, dst)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, plan.CreateSchema, "expected the schema of the empty target to be created")
+	testhelpers.AssertEqual(t, len(copiedTables), len(plan.Tables))
+	testhelpers.AssertFalse(t, dst.Migrator().HasTable("mcp_servers"), "planning must not change the target")
+
+	var progressed []string
+	plan, err = CopyDatabase(ctx, src, dst, func(table string, copied, total int64) {
+		progressed = append(progressed, table)
+		if copied > total {
+			t.Errorf("copied %d rows of %s out of %d", copied, table, total)
+		}
+	})
+	testhelpers.AssertNoError(t, err)
+	rows := map[string]int64{}
+	for _, table := range plan.Tables {
+		testhelpers.AssertEqual(t, table.SourceRows, table.TargetRows)
+		rows[table.Table] = table.TargetRows
+	}
+	testhelpers.AssertEqual(t, int64(1), rows["mcp_servers"])
+	// the soft-deleted tool is copied too
+	testhelpers.AssertEqual(t, int64(3), rows["tools"])
+	testhelpers.AssertEqual(t, "server_configs,mcp_servers,tools,webhooks", strings.Join(progressed, ","))
+
+	var server model.McpServer
+	testhelpers.AssertNoError(t, dst.First(&server, "name = ?", "slack").Error)
+	var srcServer model.McpServer
+	testhelpers.AssertNoError(t, src.First(&srcServer, "name = ?", "slack").Error)
+	testhelpers.AssertEqual(t, srcServer.ID, server.ID)
+	testhelpers.AssertEqual(t, srcServer.UUID, server.UUID)
+	testhelpers.AssertEqual(t, string(srcServer.Config), string(server.Config))
+
+	var disabled model.Tool
+	testhelpers.AssertNoError(t, dst.First(&disabled, "name = ?", "delete_channel").Error)
+	testhelpers.AssertFalse(t, disabled.Enabled, "expected the disabled tool to stay disabled")
+	var deleted model.Tool
+	testhelpers.AssertNoError(t, dst.Unscoped().First(&deleted, "name = ?", "archive_channel").Error)
+	testhelpers.AssertTrue(t, deleted.DeletedAt.Valid, "expected the deleted tool to stay deleted")
+
+	var webhook model.Webhook
+	testhelpers.AssertNoError(t, dst.First(&webhook).Error)
+	testhelpers.AssertEqual(t, "s3cr3t", webhook.Secret)
+
+	// the IDs generated by the target follow the copied ones
+	tool := &model.Tool{Name: "list_channels", Enabled: true, ServerID: server.ID}
+	testhelpers.AssertNoError(t, dst.Create(tool).Error)
+	testhelpers.AssertTrue(t, tool.ID > deleted.ID, "expected the ID of a new tool to follow the copied ones")
+
+	// the target is no longer empty
+	_, err = CopyDatabase(ctx, src, dst, nil)
+	testhelpers.AssertTrue(t, errors.Is(err, ErrTargetNotEmpty), fmt.Sprintf("expected ErrTargetNotEmpty, got %v", err))
+}
+
+func TestCopyDatabaseInBatches(t *testing.T) {
+	src := openTestFileDB(t, "source.db")
+	testhelpers.AssertNoError(t, Migrate(src))
+	keys := make([]model.IdempotencyKey, copyBatchSize+1)
+	for i := range keys {
+		keys[i] = model.IdempotencyKey{Key: fmt.Sprintf("key-%d", i), ExpiresAt: time.Now().Add(time.Hour)}
+	}
+	testhelpers.AssertNoError(t, src.CreateInBatches(keys, 100).Error)
+
+	var batches []int64
+	dst := openTestFileDB(t, "target.db")
+	_, err := CopyDatabase(context.Background(), src, dst, func(_ string, copied, _ int64) {
+		batches = append(batches, copied)
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 2, len(batches))
+	testhelpers.AssertEqual(t, int64(copyBatchSize), batches[0])
+	testhelpers.AssertEqual(t, int64(copyBatchSize+1), batches[1])
+}
+
+func TestPlanCopySourceNotMigrated(t *testing.T) {
+	src := openTestFileDB(t, "source.db")
+	testhelpers.AssertNoError(t, src.AutoMigrate(&model.McpServer{}))
+	_, err := PlanCopy(context.Background(), src, openTestFileDB(t, "target.db"))
+	testhelpers.AssertTrue(t, errors.Is(err, ErrSourceNotMigrated), fmt.Sprintf("expected ErrSourceNotMigrated, got %v", err))
+}
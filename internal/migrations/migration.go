@@ -3,19 +3,38 @@ package migrations
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/google/uuid"
+	"github.com/mcpjungle/mcpjungle/internal/db/dialect"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"gorm.io/gorm"
 )
 
+// SchemaVersion is the version of the database schema Migrate creates.
+// It must be incremented whenever a change of the models or of Migrate changes what the tables hold,
+// eg- a column is renamed or its values are stored differently, since the rows of the backups follow the schema
+// and a backup can only be restored by the servers with the same schema version.
+const SchemaVersion = 1
+
 // Migrate performs the database migration for the application.
 func Migrate(db *gorm.DB) error {
+	db = dialect.MigrationSession(db)
 	if err := db.AutoMigrate(&model.McpServer{}); err != nil {
 		return fmt.Errorf("auto‑migration failed for McpServer model: %v", err)
 	}
+	legacySchemas, err := prepareToolSchemaColumn(db)
+	if err != nil {
+		return fmt.Errorf("failed to prepare the input schema column of tools: %v", err)
+	}
 	if err := db.AutoMigrate(&model.Tool{}); err != nil {
 		return fmt.Errorf("auto‑migration failed for Tool model: %v", err)
 	}
+	if legacySchemas {
+		if err := compressToolSchemas(db); err != nil {
+			return fmt.Errorf("failed to compress the input schemas of tools: %v", err)
+		}
+	}
 	if err := db.AutoMigrate(&model.ServerConfig{}); err != nil {
 		return fmt.Errorf("auto‑migration failed for ServerConfig model: %v", err)
 	}
@@ -31,5 +50,110 @@ func Migrate(db *gorm.DB) error {
 	if err := db.AutoMigrate(&model.Prompt{}); err != nil {
 		return fmt.Errorf("auto‑migration failed for Prompt model: %v", err)
 	}
+	if err := db.AutoMigrate(&model.Webhook{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for Webhook model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.WebhookDelivery{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for WebhookDelivery model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.IdempotencyKey{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for IdempotencyKey model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.TableVersion{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for TableVersion model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ServerHealthCheck{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ServerHealthCheck model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.LeaderLease{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for LeaderLease model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.RateLimitWindow{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for RateLimitWindow model: %v", err)
+	}
+	entities := []any{&model.McpServer{}, &model.Tool{}, &model.ToolGroup{}, &model.User{}, &model.McpClient{}}
+	for _, entity := range entities {
+		if err := assignUUIDs(db, entity); err != nil {
+			return fmt.Errorf("failed to assign UUIDs to %T: %v", entity, err)
+		}
+	}
 	return nil
 }
+
+// assignUUIDs gives a UUID to the entities stored before entities had one, including the soft-deleted ones.
+func assignUUIDs(db *gorm.DB, entity any) error {
+	var ids []uint
+	if err := db.Unscoped().Model(entity).Where("uuid IS NULL OR uuid = ''").Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, id := range ids {
+			if err := tx.Unscoped().Model(entity).Where("id = ?", id).UpdateColumn("uuid", uuid.NewString()).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// toolSchemaBatchSize is the number of tools whose input schema is compressed at once.
+const toolSchemaBatchSize = 500
+
+// prepareToolSchemaColumn reports whether the input schemas of tools are still stored as uncompressed JSON.
+// Postgres can't convert a jsonb column to bytea by itself, so the column is converted here before auto-migration,
+// keeping the uncompressed documents as they are.
+func prepareToolSchemaColumn(db *gorm.DB) (bool, error) {
+	if !db.Migrator().HasTable(&model.Tool{}) {
+		return false, nil
+	}
+	columns, err := db.Migrator().ColumnTypes(&model.Tool{})
+	if err != nil {
+		return false, err
+	}
+	for _, c := range columns {
+		if c.Name() != "input_schema" {
+			continue
+		}
+		if t := strings.ToLower(c.DatabaseTypeName()); t != "jsonb" && t != "json" {
+			return false, nil
+		}
+		if dialect.Of(db) == dialect.Postgres {
+			err := db.Exec(
+				"ALTER TABLE tools ALTER COLUMN input_schema TYPE bytea USING convert_to(input_schema::text, 'UTF8')",
+			).Error
+			if err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// compressToolSchemas compresses the input schemas of the existing tools, in place.
+func compressToolSchemas(db *gorm.DB) error {
+	var rows []struct {
+		ID          uint
+		InputSchema []byte
+	}
+	// soft-deleted tools are compressed as well, the table is queried directly
+	return db.Table("tools").Select("id", "input_schema").Where("input_schema IS NOT NULL").
+		FindInBatches(&rows, toolSchemaBatchSize, func(tx *gorm.DB, batch int) error {
+			return db.Transaction(func(tx *gorm.DB) error {
+				for _, r := range rows {
+					if len(r.InputSchema) == 0 || model.IsCompressedJSON(r.InputSchema) {
+						continue
+					}
+					err := tx.Table("tools").Where("id = ?", r.ID).
+						Update("input_schema", model.CompressedJSON(r.InputSchema)).Error
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}).Error
+}
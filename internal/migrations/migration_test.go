@@ -0,0 +1,129 @@
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// legacyTool is a tool as it was stored before its input schema was compressed.
+type legacyTool struct {
+	gorm.Model
+	Name        string
+	ServerID    uint
+	Enabled     bool
+	InputSchema datatypes.JSON `gorm:"type:jsonb"`
+}
+
+func (legacyTool) TableName() string {
+	return "tools"
+}
+
+func TestMigrateCompressesToolSchemas(t *testing.T) {
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(&legacyTool{}))
+	for _, name := range []string{"git_commit", "git_push"} {
+		tool := &legacyTool{Name: name, InputSchema: datatypes.JSON(`{"type":"object"}`)}
+		testhelpers.AssertNoError(t, db.Create(tool).Error)
+	}
+	testhelpers.AssertNoError(t, db.Delete(&legacyTool{}, "name = ?", "git_push").Error)
+
+	testhelpers.AssertNoError(t, Migrate(db))
+
+	var stored []struct {
+		Name        string
+		InputSchema []byte
+	}
+	testhelpers.AssertNoError(t, db.Table("tools").Select("name", "input_schema").Find(&stored).Error)
+	testhelpers.AssertEqual(t, 2, len(stored))
+	for _, s := range stored {
+		testhelpers.AssertTrue(t, model.IsCompressedJSON(s.InputSchema), "the input schema of "+s.Name+" should be compressed")
+	}
+
+	var tool model.Tool
+	testhelpers.AssertNoError(t, db.Where("name = ?", "git_commit").First(&tool).Error)
+	testhelpers.AssertEqual(t, `{"type":"object"}`, string(tool.InputSchema))
+
+	// migrating again leaves the compressed schemas as they are
+	testhelpers.AssertNoError(t, Migrate(db))
+	testhelpers.AssertNoError(t, db.Where("name = ?", "git_commit").First(&tool).Error)
+	testhelpers.AssertEqual(t, `{"type":"object"}`, string(tool.InputSchema))
+}
+
+func TestMigrateAssignsUUIDs(t *testing.T) {
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, Migrate(db))
+	for _, name := range []string{"github", "slack"} {
+		s := &model.McpServer{Name: name, Transport: "streamable_http", Config: datatypes.JSON(`{}`)}
+		testhelpers.AssertNoError(t, db.Create(s).Error)
+	}
+	var kept string
+	testhelpers.AssertNoError(t, db.Model(&model.McpServer{}).Select("uuid").Where("name = ?", "slack").Scan(&kept).Error)
+	// the servers stored before servers had a UUID
+	testhelpers.AssertNoError(t, db.Model(&model.McpServer{}).Where("name = ?", "github").UpdateColumn("uuid", nil).Error)
+	testhelpers.AssertNoError(t, db.Delete(&model.McpServer{}, "name = ?", "github").Error)
+
+	testhelpers.AssertNoError(t, Migrate(db))
+
+	var servers []model.McpServer
+	testhelpers.AssertNoError(t, db.Unscoped().Order("name").Find(&servers).Error)
+	testhelpers.AssertEqual(t, 2, len(servers))
+	testhelpers.AssertTrue(t, model.IsUUID(servers[0].UUID), "the soft-deleted server should have a UUID")
+	testhelpers.AssertEqual(t, kept, servers[1].UUID)
+}
+
+// seedLegacyTools stores n tools the way they were stored before their input schemas were compressed.
+// Their schemas are typical of large MCP servers, with many properties that share descriptions and constraints.
+func seedLegacyTools(b *testing.B, db *gorm.DB, n int) {
+	b.Helper()
+	tools := make([]legacyTool, 0, n)
+	for i := 0; i < n; i++ {
+		properties := make(map[string]any)
+		for p := 0; p < 10+i%30; p++ {
+			properties[fmt.Sprintf("param_%d", p)] = map[string]any{
+				"type":        "string",
+				"description": "The identifier of the resource, as returned by the list operation of the API.",
+				"maxLength":   256,
+			}
+		}
+		schema, _ := json.Marshal(map[string]any{"type": "object", "properties": properties})
+		tools = append(tools, legacyTool{Name: fmt.Sprintf("tool_%d", i), InputSchema: schema})
+	}
+	if err := db.CreateInBatches(tools, 500).Error; err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkMigrateToolSchemas(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		db, err := testhelpers.CreateTestDB()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := db.AutoMigrate(&legacyTool{}); err != nil {
+			b.Fatal(err)
+		}
+		seedLegacyTools(b, db, 10000)
+		var before, after int64
+		db.Table("tools").Select("SUM(LENGTH(input_schema))").Scan(&before)
+		b.StartTimer()
+
+		if err := Migrate(db); err != nil {
+			b.Fatal(err)
+		}
+
+		b.StopTimer()
+		db.Table("tools").Select("SUM(LENGTH(input_schema))").Scan(&after)
+		b.ReportMetric(float64(before), "json-bytes")
+		b.ReportMetric(float64(after), "stored-bytes")
+		b.StartTimer()
+	}
+}
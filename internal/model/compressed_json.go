@@ -0,0 +1,114 @@
+package model
+
+import (
+	"bytes"
+	"compress/gzip"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/mcpjungle/mcpjungle/internal/db/dialect"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// gzipMagic are the first bytes of gzip data. A JSON document cannot start with them.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// gzipWriters pools the gzip writers, they are costly to allocate.
+var gzipWriters = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+
+// CompressedJSON is a JSON document stored gzip compressed in the DB.
+// It holds the uncompressed document in memory, so it is used like datatypes.JSON.
+//
+// Tool input schemas are large and repetitive, they usually shrink to a fraction of their size once compressed.
+// Values stored uncompressed, before the column was compressed, are read as they are.
+type CompressedJSON []byte
+
+// Value compresses the document to store it in the DB.
+func (j CompressedJSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return compressJSON(j)
+}
+
+// Scan decompresses a document read from the DB.
+func (j *CompressedJSON) Scan(value any) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*j = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("failed to scan CompressedJSON: unsupported type %T", value)
+	}
+	if !IsCompressedJSON(data) {
+		*j = CompressedJSON(bytes.Clone(data))
+		return nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decompress JSON: %w", err)
+	}
+	defer r.Close()
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to decompress JSON: %w", err)
+	}
+	*j = decompressed
+	return nil
+}
+
+// MarshalJSON returns the document, or null if it is empty.
+func (j CompressedJSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+// UnmarshalJSON stores a copy of the document.
+func (j *CompressedJSON) UnmarshalJSON(data []byte) error {
+	if j == nil {
+		return errors.New("CompressedJSON: UnmarshalJSON on nil pointer")
+	}
+	*j = bytes.Clone(data)
+	return nil
+}
+
+// GormDataType returns the generic data type of the column.
+func (CompressedJSON) GormDataType() string {
+	return "bytes"
+}
+
+// GormDBDataType returns the type of the column in the DB.
+func (CompressedJSON) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return dialect.Of(db).BinaryType()
+}
+
+// IsCompressedJSON reports whether data read from the DB is a compressed JSON document.
+func IsCompressedJSON(data []byte) bool {
+	return bytes.HasPrefix(data, gzipMagic)
+}
+
+// compressJSON compresses a JSON document.
+func compressJSON(doc []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzipWriters.Get().(*gzip.Writer)
+	defer gzipWriters.Put(w)
+	w.Reset(&buf)
+	if _, err := w.Write(doc); err != nil {
+		return nil, fmt.Errorf("failed to compress JSON: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestCompressedJSON(t *testing.T) {
+	doc := CompressedJSON(`{"type":"object","properties":{"repo":{"type":"string"}}}`)
+	value, err := doc.Value()
+	if err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	stored, ok := value.([]byte)
+	if !ok || !IsCompressedJSON(stored) {
+		t.Fatalf("expected the stored value to be compressed, got %v", value)
+	}
+
+	var scanned CompressedJSON
+	if err := scanned.Scan(stored); err != nil {
+		t.Fatalf("failed to decompress: %v", err)
+	}
+	if !bytes.Equal(doc, scanned) {
+		t.Errorf("expected %s, got %s", doc, scanned)
+	}
+
+	// documents stored before the column was compressed are read as they are
+	var legacy CompressedJSON
+	if err := legacy.Scan(`{"type":"object"}`); err != nil {
+		t.Fatalf("failed to scan legacy value: %v", err)
+	}
+	if string(legacy) != `{"type":"object"}` {
+		t.Errorf("expected the legacy document, got %s", legacy)
+	}
+
+	var empty CompressedJSON
+	if value, _ := empty.Value(); value != nil {
+		t.Errorf("expected an empty document to be stored as NULL, got %v", value)
+	}
+	if err := empty.Scan(nil); err != nil || empty != nil {
+		t.Errorf("expected NULL to be scanned as an empty document, got %s, %v", empty, err)
+	}
+
+	out, err := json.Marshal(struct {
+		Schema CompressedJSON `json:"schema"`
+	}{doc})
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if string(out) != `{"schema":`+string(doc)+`}` {
+		t.Errorf("expected the document to be marshalled as JSON, got %s", out)
+	}
+}
+
+// benchmarkSchema returns an input schema typical of the tools of large MCP servers,
+// with many properties that share their descriptions and constraints.
+func benchmarkSchema() []byte {
+	properties := make(map[string]any)
+	for i := 0; i < 40; i++ {
+		properties[fmt.Sprintf("field_%d", i)] = map[string]any{
+			"type":        "string",
+			"description": "The identifier of the resource, as returned by the list operation of the API.",
+			"maxLength":   256,
+			"pattern":     "^[a-zA-Z0-9_-]+$",
+		}
+	}
+	schema, _ := json.Marshal(map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             []string{"field_0", "field_1"},
+		"additionalProperties": false,
+	})
+	return schema
+}
+
+func BenchmarkCompressedJSON(b *testing.B) {
+	schema := CompressedJSON(benchmarkSchema())
+	var stored []byte
+	b.Run("compress", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			value, err := schema.Value()
+			if err != nil {
+				b.Fatal(err)
+			}
+			stored = value.([]byte)
+		}
+		b.ReportMetric(float64(len(schema))/float64(len(stored)), "ratio")
+	})
+	b.Run("decompress", func(b *testing.B) {
+		var scanned CompressedJSON
+		for i := 0; i < b.N; i++ {
+			if err := scanned.Scan(stored); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EntityID is the immutable identifier of an entity that is addressed by its name in the API.
+// Unlike its name, it never changes, so that integrations can keep referencing an entity that is renamed.
+// It is returned by the API and accepted in place of the name to address the entity.
+type EntityID struct {
+	UUID string `json:"uuid" gorm:"column:uuid;type:varchar(36);uniqueIndex"`
+}
+
+// BeforeCreate assigns a new UUID to the entity being created, unless it already has one.
+func (e *EntityID) BeforeCreate(*gorm.DB) error {
+	if e.UUID == "" {
+		e.UUID = uuid.NewString()
+	}
+	return nil
+}
+
+// IsUUID reports whether s is a UUID in its canonical form, eg- 9a7b330a-a736-41e5-8b6c-2e8f8e6d2b1c.
+func IsUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	_, err := uuid.Parse(s)
+	return err == nil
+}
+
+// ResolveName returns the value of the name column of the entity identified by ident, which is either its UUID
+// or its name. entity is a pointer to the model of the entity, eg- &McpServer{}.
+// An ident that is not the UUID of any entity is returned as is, so that it is looked up by name.
+func ResolveName(db *gorm.DB, entity any, nameColumn, ident string) (string, error) {
+	if !IsUUID(ident) {
+		return ident, nil
+	}
+	var names []string
+	if err := db.Model(entity).Where("uuid = ?", ident).Limit(1).Pluck(nameColumn, &names).Error; err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return ident, nil
+	}
+	return names[0], nil
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// IdempotencyKey records the response to a mutating API request that carried an Idempotency-Key header,
+// so that the response is replayed when the request is sent again with the same key.
+type IdempotencyKey struct {
+	ID        uint      `gorm:"primarykey"`
+	CreatedAt time.Time `gorm:"not null"`
+	// ExpiresAt is when the key can be used again for a different request, and the record is pruned.
+	ExpiresAt time.Time `gorm:"index;not null"`
+
+	// Scope is the name of the user who sent the request, empty if the request was not authenticated.
+	// Keys are unique per scope, so that users can't see each other's responses.
+	Scope string `gorm:"uniqueIndex:idx_idempotency_keys_scope_key;not null;default:''"`
+	Key   string `gorm:"uniqueIndex:idx_idempotency_keys_scope_key;not null"`
+	// RequestHash is a hash of the method, URL and body of the request,
+	// to tell the same request apart from a different one reusing the key.
+	RequestHash string `gorm:"not null"`
+
+	StatusCode   int    `gorm:"not null"`
+	ContentType  string `gorm:"not null;default:''"`
+	ResponseBody []byte
+}
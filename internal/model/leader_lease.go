@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// LeaderLease is held by the replica of the registry that runs the background jobs, eg- the health checks,
+// when several replicas share the database. The replicas renew the lease they hold and take it over once it expired.
+type LeaderLease struct {
+	Name string `gorm:"primaryKey"`
+	// Holder is the ID of the replica holding the lease.
+	Holder    string    `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	// AcquiredAt is when the holder took the lease, it is kept when the lease is renewed.
+	AcquiredAt time.Time `gorm:"not null"`
+	// Term is incremented every time the lease is written, a replica only writes it at the term it read,
+	// so that two replicas can't both take over an expired lease.
+	Term uint64 `gorm:"not null;default:0"`
+}
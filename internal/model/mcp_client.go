@@ -11,6 +11,7 @@ import (
 // McpClient represents MCP clients and their access to the MCP Servers provided MCPJungle MCP server
 type McpClient struct {
 	gorm.Model
+	EntityID
 
 	Name        string `json:"name" gorm:"uniqueIndex;not null"`
 	Description string `json:"description"`
@@ -13,11 +13,11 @@ type Prompt struct {
 	// A prompt name is unique only within the context of a server.
 	// This means that two prompts in mcpjungle DB CAN have the same name because
 	// they belong to different servers, identified by server ID.
-	Name string `json:"name" gorm:"not null"`
+	Name string `json:"name" gorm:"not null;index:idx_prompts_server_name,priority:2"`
 
 	// Enabled indicates whether the prompt is enabled or not.
 	// If a prompt is disabled, it cannot be viewed or retrieved from the MCP proxy.
-	Enabled bool `json:"enabled" gorm:"default:true"`
+	Enabled bool `json:"enabled" gorm:"default:true;index"`
 
 	Description string `json:"description"`
 
@@ -25,6 +25,6 @@ type Prompt struct {
 	Arguments datatypes.JSON `json:"arguments" gorm:"type:jsonb"`
 
 	// ServerID is the ID of the MCP server that provides this prompt.
-	ServerID uint      `json:"-" gorm:"not null"`
+	ServerID uint      `json:"-" gorm:"not null;index:idx_prompts_server_name,priority:1"`
 	Server   McpServer `json:"-" gorm:"foreignKey:ServerID;references:ID"`
 }
@@ -17,6 +17,15 @@ type StreamableHTTPConfig struct {
 	// BearerToken is an optional token used for authenticating requests to the MCP server.
 	// If present, it will be used to set the Authorization header in all requests to this MCP server.
 	BearerToken string `json:"bearer_token,omitempty"`
+
+	// Pool tunes the pool of connections to the MCP server, nil to use the defaults.
+	Pool *types.HTTPPoolConfig `json:"pool,omitempty"`
+
+	// KeepAlive pings the session of the MCP server while it is idle, nil if disabled.
+	KeepAlive *types.KeepAliveConfig `json:"keep_alive,omitempty"`
+
+	// Container describes the container the MCP server runs in, nil if unknown.
+	Container *types.ContainerConfig `json:"container,omitempty"`
 }
 
 type StdioConfig struct {
@@ -28,6 +37,25 @@ type StdioConfig struct {
 
 	// Env describes the environment variables to pass to the MCP server
 	Env map[string]string `json:"env,omitempty"`
+
+	// Container is the container the MCP server is launched in, nil to run it as a process.
+	Container *types.ContainerConfig `json:"container,omitempty"`
+
+	// Package is the package the MCP server is launched from, in which case Command is empty.
+	Package *types.PackageConfig `json:"package,omitempty"`
+
+	// PackageVersion is the version the latest release of Package was when the server was registered,
+	// empty if it is not known. A server running the latest release logs it when it changes.
+	PackageVersion string `json:"package_version,omitempty"`
+}
+
+// CommandLine returns the command running the MCP server and its arguments,
+// expanded from its package if it is launched from one.
+func (c *StdioConfig) CommandLine() (string, []string) {
+	if c.Package != nil {
+		return c.Package.CommandLine(c.Args)
+	}
+	return c.Command, c.Args
 }
 
 type SSEConfig struct {
@@ -35,11 +63,15 @@ type SSEConfig struct {
 	URL string `json:"url"`
 
 	BearerToken string `json:"bearer_token,omitempty"`
+
+	// Container describes the container the MCP server runs in, nil if unknown.
+	Container *types.ContainerConfig `json:"container,omitempty"`
 }
 
 // McpServer represents a MCP server registered in mcpjungle
 type McpServer struct {
 	gorm.Model
+	EntityID
 
 	Name      string                   `json:"name" gorm:"uniqueIndex;not null"`
 	Transport types.McpServerTransport `json:"transport" gorm:"type:varchar(30);not null"`
@@ -54,6 +86,14 @@ type McpServer struct {
 	// "stateless" (default): Creates a new connection for each tool call.
 	// "stateful": Maintains a persistent connection across tool calls.
 	SessionMode types.SessionMode `json:"session_mode" gorm:"type:varchar(20);default:'stateless'"`
+
+	// LazyStart defers starting a stdio server until it is first used, see types.RegisterServerInput.LazyStart.
+	// The process of a lazily started server is managed like a stateful session.
+	LazyStart bool `json:"lazy_start" gorm:"not null;default:false"`
+
+	// Version is incremented every time the server's configuration changes.
+	// It lets concurrent updates detect that they were computed from a stale configuration.
+	Version uint `json:"version" gorm:"not null;default:1"`
 }
 
 // NewStreamableHTTPServer creates a new MCP server with streamable HTTP transport configuration.
@@ -107,6 +147,67 @@ func NewStdioServer(name, description, command string, args []string, env map[st
 	}, nil
 }
 
+// NewContainerStdioServer creates a new MCP server with stdio transport configuration, launched in a container.
+// The command may be empty to run the default command of the image.
+func NewContainerStdioServer(
+	name, description string, container *types.ContainerConfig,
+	command string, args []string, env map[string]string, sessionMode types.SessionMode,
+) (*McpServer, error) {
+	if container == nil || container.Image == "" {
+		return nil, errors.New("image is required for a stdio server launched in a container")
+	}
+	config := StdioConfig{
+		Command:   command,
+		Args:      args,
+		Env:       env,
+		Container: container,
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	if sessionMode == "" {
+		sessionMode = types.SessionModeStateless
+	}
+	return &McpServer{
+		Name:        name,
+		Description: description,
+		Transport:   types.TransportStdio,
+		Config:      datatypes.JSON(configJSON),
+		SessionMode: sessionMode,
+	}, nil
+}
+
+// NewPackageStdioServer creates a new MCP server with stdio transport configuration, launched from a package
+// by its runtime. The args follow the package on the command line.
+func NewPackageStdioServer(
+	name, description string, pkg *types.PackageConfig,
+	args []string, env map[string]string, sessionMode types.SessionMode,
+) (*McpServer, error) {
+	if pkg == nil || pkg.Name == "" {
+		return nil, errors.New("package name is required for a stdio server launched from a package")
+	}
+	config := StdioConfig{
+		Args:    args,
+		Env:     env,
+		Package: pkg,
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	if sessionMode == "" {
+		sessionMode = types.SessionModeStateless
+	}
+	return &McpServer{
+		Name:        name,
+		Description: description,
+		Transport:   types.TransportStdio,
+		Config:      datatypes.JSON(configJSON),
+		SessionMode: sessionMode,
+	}, nil
+}
+
 // NewSSEServer creates a new MCP server with SSE transport configuration.
 func NewSSEServer(name, description, url, bearerToken string, sessionMode types.SessionMode) (*McpServer, error) {
 	if url == "" {
@@ -132,6 +233,44 @@ func NewSSEServer(name, description, url, bearerToken string, sessionMode types.
 	}, nil
 }
 
+// NewOpenAPIServer creates a new MCP server serving the operations of a REST service as its tools.
+// openapi servers are served in-process, so they are always stateless.
+func NewOpenAPIServer(name, description string, config *types.OpenAPIConfig) (*McpServer, error) {
+	if config == nil {
+		return nil, errors.New("openapi configuration is required for openapi transport")
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return &McpServer{
+		Name:        name,
+		Description: description,
+		Transport:   types.TransportOpenAPI,
+		Config:      configJSON,
+		SessionMode: types.SessionModeStateless,
+	}, nil
+}
+
+// NewWebhookToolServer creates a new MCP server serving an HTTP endpoint as its single tool.
+// webhook_tool servers are served in-process, so they are always stateless.
+func NewWebhookToolServer(name, description string, config *types.WebhookToolConfig) (*McpServer, error) {
+	if config == nil {
+		return nil, errors.New("webhook tool configuration is required for webhook_tool transport")
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return &McpServer{
+		Name:        name,
+		Description: description,
+		Transport:   types.TransportWebhookTool,
+		Config:      configJSON,
+		SessionMode: types.SessionModeStateless,
+	}, nil
+}
+
 // GetStreamableHTTPConfig returns the configuration if this is a streamable HTTP server
 func (s *McpServer) GetStreamableHTTPConfig() (*StreamableHTTPConfig, error) {
 	if s.Transport != types.TransportStreamableHTTP {
@@ -144,6 +283,89 @@ func (s *McpServer) GetStreamableHTTPConfig() (*StreamableHTTPConfig, error) {
 	return &config, nil
 }
 
+// SetHTTPPoolConfig sets the connection pool configuration of a streamable HTTP server.
+func (s *McpServer) SetHTTPPoolConfig(pool *types.HTTPPoolConfig) error {
+	config, err := s.GetStreamableHTTPConfig()
+	if err != nil {
+		return err
+	}
+	config.Pool = pool
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	s.Config = configJSON
+	return nil
+}
+
+// SetKeepAliveConfig sets the keep-alive configuration of the session of a streamable HTTP server.
+func (s *McpServer) SetKeepAliveConfig(keepAlive *types.KeepAliveConfig) error {
+	config, err := s.GetStreamableHTTPConfig()
+	if err != nil {
+		return err
+	}
+	config.KeepAlive = keepAlive
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	s.Config = configJSON
+	return nil
+}
+
+// SetContainerConfig sets the container a streamable HTTP or SSE server runs in,
+// or the container a stdio server is launched in.
+func (s *McpServer) SetContainerConfig(container *types.ContainerConfig) error {
+	var config any
+	switch s.Transport {
+	case types.TransportStdio:
+		c, err := s.GetStdioConfig()
+		if err != nil {
+			return err
+		}
+		c.Container = container
+		config = c
+	case types.TransportStreamableHTTP:
+		c, err := s.GetStreamableHTTPConfig()
+		if err != nil {
+			return err
+		}
+		c.Container = container
+		config = c
+	case types.TransportSSE:
+		c, err := s.GetSSEConfig()
+		if err != nil {
+			return err
+		}
+		c.Container = container
+		config = c
+	default:
+		return errors.New("only streamable HTTP, SSE and stdio servers can run in a container")
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	s.Config = configJSON
+	return nil
+}
+
+// SetPackage sets the package a stdio server is launched from, and the version its latest release is.
+func (s *McpServer) SetPackage(pkg *types.PackageConfig, version string) error {
+	config, err := s.GetStdioConfig()
+	if err != nil {
+		return err
+	}
+	config.Package = pkg
+	config.PackageVersion = version
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	s.Config = configJSON
+	return nil
+}
+
 // GetStdioConfig returns the configuration if this is a stdio server
 func (s *McpServer) GetStdioConfig() (*StdioConfig, error) {
 	if s.Transport != types.TransportStdio {
@@ -167,3 +389,33 @@ func (s *McpServer) GetSSEConfig() (*SSEConfig, error) {
 	}
 	return &config, nil
 }
+
+// GetOpenAPIConfig returns the configuration if this is an openapi server
+func (s *McpServer) GetOpenAPIConfig() (*types.OpenAPIConfig, error) {
+	if s.Transport != types.TransportOpenAPI {
+		return nil, errors.New("server is not an openapi transport type")
+	}
+	var config types.OpenAPIConfig
+	if err := json.Unmarshal(s.Config, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetWebhookToolConfig returns the configuration if this is a webhook_tool server
+func (s *McpServer) GetWebhookToolConfig() (*types.WebhookToolConfig, error) {
+	if s.Transport != types.TransportWebhookTool {
+		return nil, errors.New("server is not a webhook_tool transport type")
+	}
+	var config types.WebhookToolConfig
+	if err := json.Unmarshal(s.Config, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// HasPersistentSession reports whether mcpjungle keeps a connection to the server open across tool calls,
+// either because it is in stateful mode or because it is started lazily.
+func (s *McpServer) HasPersistentSession() bool {
+	return s.SessionMode == types.SessionModeStateful || s.LazyStart
+}
@@ -449,3 +449,30 @@ func TestNewSSEServerWithSessionMode(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPackageStdioServer(t *testing.T) {
+	pkg := &types.PackageConfig{Runtime: types.PackageRuntimeNpx, Name: "@modelcontextprotocol/server-filesystem"}
+	server, err := NewPackageStdioServer("filesystem", "", pkg, []string{"/tmp"}, nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := server.SetPackage(pkg, "2025.8.21"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	config, err := server.GetStdioConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Command != "" || config.PackageVersion != "2025.8.21" {
+		t.Errorf("expected the package to be stored instead of its command, got %+v", config)
+	}
+	command, args := config.CommandLine()
+	if command != "npx" || len(args) != 3 || args[1] != "@modelcontextprotocol/server-filesystem@latest" || args[2] != "/tmp" {
+		t.Errorf("unexpected command line: %s %v", command, args)
+	}
+
+	if _, err := NewPackageStdioServer("filesystem", "", &types.PackageConfig{Runtime: types.PackageRuntimeNpx}, nil, nil, ""); err == nil {
+		t.Error("expected an error for a package without a name")
+	}
+}
@@ -8,27 +8,29 @@ import (
 // Tool represents a tool provided by an MCP server.
 type Tool struct {
 	gorm.Model
+	EntityID
 
 	// Name is just the name of the tool, without the server name prefix.
 	// A tool name is unique only within the context of a server.
 	// This means that two tools in mcpjungle DB CAN have the same name because
 	// they belong to different servers, identified by server ID.
-	Name string `json:"name" gorm:"not null"`
+	Name string `json:"name" gorm:"not null;index:idx_tools_server_name,priority:2"`
 
 	// Enabled indicates whether the tool is enabled or not.
 	// If a tool is disabled, it cannot be viewed or called from the MCP proxy.
-	Enabled bool `json:"enabled" gorm:"default:true"`
+	Enabled bool `json:"enabled" gorm:"default:true;index"`
 
 	Description string `json:"description"`
 
 	// InputSchema is a JSON schema that describes the input parameters for the tool.
-	InputSchema datatypes.JSON `json:"input_schema" gorm:"type:jsonb"`
+	// It is stored compressed.
+	InputSchema CompressedJSON `json:"input_schema"`
 
 	// Annotations stores tool annotation hints from the upstream MCP server.
 	// These hints help LLMs understand tool behavior (e.g., read-only vs destructive).
 	Annotations datatypes.JSON `json:"annotations" gorm:"type:jsonb"`
 
 	// ServerID is the ID of the MCP server that provides this tool.
-	ServerID uint      `json:"-" gorm:"not null"`
+	ServerID uint      `json:"-" gorm:"not null;index:idx_tools_server_name,priority:1"`
 	Server   McpServer `json:"-" gorm:"foreignKey:ServerID;references:ID"`
 }
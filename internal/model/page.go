@@ -0,0 +1,35 @@
+package model
+
+import "gorm.io/gorm"
+
+// Page selects a window of a list of records for cursor-based pagination.
+// Records are listed in the order of their IDs, so that pages stay stable while records are added or removed.
+type Page struct {
+	// Limit is the maximum number of records in the page, 0 means no limit.
+	Limit int
+	// After is the ID of the last record of the previous page, 0 to start from the beginning.
+	After uint
+}
+
+// Apply restricts query to the records of the page.
+// If the page is limited, one extra record is fetched so that NextPage can tell whether there are more.
+func (p Page) Apply(query *gorm.DB) *gorm.DB {
+	query = query.Order("id")
+	if p.After > 0 {
+		query = query.Where("id > ?", p.After)
+	}
+	if p.Limit > 0 {
+		query = query.Limit(p.Limit + 1)
+	}
+	return query
+}
+
+// NextPage trims the extra record fetched by Page.Apply off records.
+// It returns the records of the page and the ID to list the next page after, which is 0 if this is the last page.
+func NextPage[T any](p Page, records []T, id func(T) uint) ([]T, uint) {
+	if p.Limit <= 0 || len(records) <= p.Limit {
+		return records, 0
+	}
+	records = records[:p.Limit]
+	return records, id(records[len(records)-1])
+}
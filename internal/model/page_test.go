@@ -0,0 +1,23 @@
+package model
+
+import "testing"
+
+func TestNextPage(t *testing.T) {
+	id := func(i int) uint { return uint(i) }
+
+	records, next := NextPage(Page{Limit: 2}, []int{1, 2, 3}, id)
+	if len(records) != 2 || next != 2 {
+		t.Errorf("Expected 2 records and next page after 2, got %v and %d", records, next)
+	}
+
+	// the extra record is missing on the last page
+	records, next = NextPage(Page{Limit: 2, After: 2}, []int{3, 4}, id)
+	if len(records) != 2 || next != 0 {
+		t.Errorf("Expected 2 records and no next page, got %v and %d", records, next)
+	}
+
+	records, next = NextPage(Page{}, []int{1, 2, 3}, id)
+	if len(records) != 3 || next != 0 {
+		t.Errorf("Expected all records of an unlimited page, got %v and %d", records, next)
+	}
+}
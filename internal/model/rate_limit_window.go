@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// RateLimitWindow counts the API requests a caller made in a window of time, when API requests are rate limited.
+// The replicas of the registry sharing the database count the requests in the same rows, so the limit applies to
+// the requests a caller sends to any of them.
+type RateLimitWindow struct {
+	// Caller identifies the caller, eg- user:alice or ip:10.0.0.1.
+	Caller string `gorm:"primaryKey"`
+	// WindowStart is when the window started, in UTC. Windows start at the multiples of their length.
+	WindowStart time.Time `gorm:"primaryKey"`
+	Requests    int       `gorm:"not null;default:0"`
+}
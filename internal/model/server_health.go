@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// ServerHealthCheck is the outcome of the last health check of an MCP server.
+// It is stored so that the replicas of the registry share it: the replica that checks the servers next carries on
+// from the outcomes of the previous checks, and the others report them.
+type ServerHealthCheck struct {
+	ServerName string    `gorm:"primaryKey"`
+	CheckedAt  time.Time `gorm:"not null"`
+	LatencyMs  int64     `gorm:"not null;default:0"`
+
+	ConsecutiveFailures int `gorm:"not null;default:0"`
+	// Error is why the check failed, empty if the server passed it.
+	Error string
+	// UnhealthySince is when the first of the checks the server failed in a row started, nil if it passed the last one.
+	UnhealthySince *time.Time
+}
@@ -0,0 +1,20 @@
+package model
+
+// Names of the tables whose changes are counted in TableVersion.
+const (
+	TableMcpServers = "mcp_servers"
+	TableTools      = "tools"
+	TablePrompts    = "prompts"
+	TableToolGroups = "tool_groups"
+)
+
+// VersionedTables are the tables whose changes are counted in TableVersion.
+// They hold what the list endpoints of the API return, so their versions tell clients whether a list changed.
+var VersionedTables = []string{TableMcpServers, TableTools, TablePrompts, TableToolGroups}
+
+// TableVersion counts the changes made to the rows of a table.
+// It is incremented in the same transaction as every create, update or delete that affects the table.
+type TableVersion struct {
+	Name    string `gorm:"primaryKey"`
+	Version uint   `gorm:"not null;default:0"`
+}
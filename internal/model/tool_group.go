@@ -18,6 +18,7 @@ type ToolResolver interface {
 // It is useful when the user wants to expose only a subset of all tools to MCP clients.
 type ToolGroup struct {
 	gorm.Model
+	EntityID
 
 	Name        string `json:"name" gorm:"unique; not null"`
 	Description string `json:"description"`
@@ -31,6 +32,10 @@ type ToolGroup struct {
 
 	// ExcludedTools contains a list of tool names to exclude from the group.
 	ExcludedTools datatypes.JSON `json:"excluded_tools" gorm:"type:jsonb"`
+
+	// Version is incremented every time the group changes.
+	// It lets concurrent updates detect that they were computed from a stale group.
+	Version uint `json:"version" gorm:"not null;default:1"`
 }
 
 // GetTools unmarshals the IncludedTools JSON array into a slice of strings.
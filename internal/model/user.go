@@ -11,6 +11,7 @@ import (
 // There are no users if mcpjungle is running in development mode.
 type User struct {
 	gorm.Model
+	EntityID
 
 	Username    string         `json:"username" gorm:"unique; not null"`
 	Role        types.UserRole `json:"role" gorm:"not null"`
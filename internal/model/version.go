@@ -0,0 +1,7 @@
+package model
+
+import "errors"
+
+// ErrVersionConflict is returned when an entity is updated on the condition that it is still at a given version,
+// and it has been changed since.
+var ErrVersionConflict = errors.New("the entity was changed since it was read, fetch it again and retry")
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Webhook is a subscription to the registry's lifecycle events, delivered to a URL.
+type Webhook struct {
+	gorm.Model
+
+	Name string `json:"name" gorm:"uniqueIndex;not null"`
+	URL  string `json:"url" gorm:"not null"`
+	// Events is a JSON array of the types of events delivered to the webhook, all of them if empty or "*"
+	Events datatypes.JSON `json:"events" gorm:"type:jsonb"`
+	// Secret is the key of the HMAC signatures of the deliveries
+	Secret string `json:"-" gorm:"not null"`
+	// Format is the format of the body of the deliveries, one of types.WebhookFormats
+	Format string `json:"format" gorm:"type:varchar(20);not null;default:'json'"`
+
+	Enabled bool `json:"enabled" gorm:"not null;default:true"`
+	// ConsecutiveFailures counts the deliveries in a row that failed after all their retries.
+	// The webhook is disabled once it reaches the threshold, and the count is reset by a successful delivery.
+	ConsecutiveFailures int        `json:"consecutive_failures" gorm:"not null;default:0"`
+	DisabledReason      string     `json:"disabled_reason"`
+	DisabledAt          *time.Time `json:"disabled_at"`
+}
+
+// WebhookDelivery records the delivery of an event to a webhook.
+type WebhookDelivery struct {
+	ID        uint      `json:"-" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+
+	WebhookID uint   `json:"-" gorm:"index;not null"`
+	EventID   string `json:"event_id" gorm:"not null"`
+	Event     string `json:"event" gorm:"not null"`
+
+	Attempts   int    `json:"attempts"`
+	Succeeded  bool   `json:"succeeded"`
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error"`
+}
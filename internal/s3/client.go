@@ -0,0 +1,308 @@
+// Package s3 is a small client of S3-compatible object stores (AWS S3, MinIO, Cloudflare R2...),
+// limited to what mcpjungle needs: streaming an object up with a multipart upload and downloading one.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPartSize is the size of the parts of multipart uploads, the objects smaller than it are uploaded at once.
+	DefaultPartSize = 8 << 20
+	// minPartSize is the minimum size of the parts of a multipart upload, except the last one.
+	minPartSize = 5 << 20
+
+	defaultRegion = "us-east-1"
+
+	// maxErrorBodyBytes bounds how much of an error response is read.
+	maxErrorBodyBytes = 64 << 10
+)
+
+// Config configures a Client.
+type Config struct {
+	// Region of the bucket, the default chain (AWS_REGION, AWS_DEFAULT_REGION) or us-east-1 if empty.
+	// Cloudflare R2 uses "auto".
+	Region string
+	// Endpoint overrides the endpoint of AWS, eg- http://localhost:9000 for MinIO.
+	// The buckets of a custom endpoint are addressed in the path of the URLs rather than in the host.
+	Endpoint string
+	// Credentials are static credentials, the default credential chain of AWS is used if nil.
+	Credentials *Credentials
+
+	// ServerSideEncryption is the server-side encryption of the uploaded objects, eg- AES256 or aws:kms.
+	ServerSideEncryption string
+	// SSEKMSKeyID is the KMS key the objects are encrypted with, when ServerSideEncryption is aws:kms.
+	SSEKMSKeyID string
+
+	// PartSize is the size of the parts of multipart uploads, DefaultPartSize if 0.
+	PartSize int
+	// HTTPClient sends the requests, http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+// Client sends requests to an S3-compatible object store.
+type Client struct {
+	cfg    Config
+	creds  *Credentials
+	region string
+	http   *http.Client
+}
+
+// Error is an error response of the object store.
+type Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("S3 returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("S3 returned %s: %s", e.Code, e.Message)
+}
+
+// NewClient creates a Client, resolving its credentials with the default chain of AWS if they are not set.
+func NewClient(ctx context.Context, cfg Config) (*Client, error) {
+	c := &Client{cfg: cfg, creds: cfg.Credentials, region: cfg.Region, http: cfg.HTTPClient}
+	if c.http == nil {
+		c.http = http.DefaultClient
+	}
+	if c.region == "" {
+		c.region = firstEnv("AWS_REGION", "AWS_DEFAULT_REGION")
+	}
+	if c.region == "" {
+		c.region = defaultRegion
+	}
+	if c.cfg.Endpoint == "" {
+		c.cfg.Endpoint = firstEnv("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL")
+	}
+	if c.cfg.PartSize == 0 {
+		c.cfg.PartSize = DefaultPartSize
+	}
+	if c.cfg.PartSize < minPartSize {
+		return nil, fmt.Errorf("the part size must be at least %d bytes", minPartSize)
+	}
+	if c.creds == nil {
+		creds, err := DefaultCredentials(ctx, c.http)
+		if err != nil {
+			return nil, err
+		}
+		c.creds = creds
+	}
+	return c, nil
+}
+
+// ParseURL splits an s3://bucket/key URL into its bucket and key. The key may be empty or a prefix ending with /.
+func ParseURL(raw string) (bucket, key string, err error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid S3 URL %s: %w", raw, err)
+	}
+	if u.Scheme != "s3" || u.Host == "" {
+		return "", "", fmt.Errorf("invalid S3 URL %s, expected s3://bucket/key", raw)
+	}
+	return u.Host, strings.TrimPrefix(u.Path, "/"), nil
+}
+
+// objectURL returns the URL of an object, in the host of the bucket for AWS and in the path for custom endpoints.
+func (c *Client) objectURL(bucket, key string, query url.Values) (*url.URL, error) {
+	var u *url.URL
+	if c.cfg.Endpoint != "" {
+		var err error
+		if u, err = url.Parse(strings.TrimSuffix(c.cfg.Endpoint, "/")); err != nil {
+			return nil, fmt.Errorf("invalid S3 endpoint %s: %w", c.cfg.Endpoint, err)
+		}
+		u.Path += "/" + bucket + "/" + key
+	} else if strings.Contains(bucket, ".") {
+		// the certificate of the S3 hosts doesn't match the buckets with dots in their names
+		u = &url.URL{Scheme: "https", Host: "s3." + c.region + ".amazonaws.com", Path: "/" + bucket + "/" + key}
+	} else {
+		u = &url.URL{Scheme: "https", Host: bucket + ".s3." + c.region + ".amazonaws.com", Path: "/" + key}
+	}
+	u.RawQuery = query.Encode()
+	return u, nil
+}
+
+// do sends a signed request, returning the response if its status is 2xx and an *Error otherwise.
+func (c *Client) do(
+	ctx context.Context, method, bucket, key string, query url.Values, header http.Header, body []byte,
+) (*http.Response, error) {
+	u, err := c.objectURL(bucket, key, query)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the request to S3: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	c.creds.sign(req, body, c.region, time.Now())
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// %v rather than %w: failing to reach the object store must not be reported as failing to reach mcpjungle
+		return nil, fmt.Errorf("failed to reach S3 at %s: %v", u.Host, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, readError(resp)
+	}
+	return resp, nil
+}
+
+// readError reads the error of an S3 response.
+func readError(resp *http.Response) error {
+	e := &Error{StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	_ = xml.Unmarshal(data, e)
+	return e
+}
+
+// encryptionHeader returns the headers of the server-side encryption of the uploaded objects.
+func (c *Client) encryptionHeader() http.Header {
+	h := http.Header{}
+	if c.cfg.ServerSideEncryption != "" {
+		h.Set("X-Amz-Server-Side-Encryption", c.cfg.ServerSideEncryption)
+	}
+	if c.cfg.SSEKMSKeyID != "" {
+		h.Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", c.cfg.SSEKMSKeyID)
+	}
+	return h
+}
+
+// Upload streams r to the object at key. An object smaller than the part size is uploaded with a single request,
+// a larger one with a multipart upload, which is aborted if the upload fails so that no partial upload is left.
+func (c *Client) Upload(ctx context.Context, bucket, key string, r io.Reader) error {
+	buf := make([]byte, c.cfg.PartSize)
+	n, err := io.ReadFull(r, buf)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return c.putObject(ctx, bucket, key, buf[:n])
+	}
+	if err != nil {
+		return err
+	}
+
+	uploadID, err := c.createMultipartUpload(ctx, bucket, key)
+	if err != nil {
+		return err
+	}
+	if err := c.uploadParts(ctx, bucket, key, uploadID, buf, r); err != nil {
+		// the upload may have been aborted because ctx is done, the parts must be removed anyway
+		abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if abortErr := c.abortMultipartUpload(abortCtx, bucket, key, uploadID); abortErr != nil {
+			return fmt.Errorf("%w (and failed to abort the multipart upload %s: %v)", err, uploadID, abortErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// putObject uploads the object at key with a single request.
+func (c *Client) putObject(ctx context.Context, bucket, key string, data []byte) error {
+	resp, err := c.do(ctx, http.MethodPut, bucket, key, nil, c.encryptionHeader(), data)
+	if err != nil {
+		return fmt.Errorf("failed to upload s3://%s/%s: %w", bucket, key, err)
+	}
+	return resp.Body.Close()
+}
+
+func (c *Client) createMultipartUpload(ctx context.Context, bucket, key string) (string, error) {
+	resp, err := c.do(ctx, http.MethodPost, bucket, key, url.Values{"uploads": {""}}, c.encryptionHeader(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to start the upload of s3://%s/%s: %w", bucket, key, err)
+	}
+	defer resp.Body.Close()
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil || result.UploadID == "" {
+		return "", fmt.Errorf("failed to start the upload of s3://%s/%s: invalid response", bucket, key)
+	}
+	return result.UploadID, nil
+}
+
+// completedPart is a part of a multipart upload, as listed when the upload is completed.
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// uploadParts uploads first and the rest of r as the parts of a multipart upload, then completes it.
+func (c *Client) uploadParts(ctx context.Context, bucket, key, uploadID string, first []byte, r io.Reader) error {
+	var parts []completedPart
+	part := first
+	for {
+		query := url.Values{"partNumber": {strconv.Itoa(len(parts) + 1)}, "uploadId": {uploadID}}
+		resp, err := c.do(ctx, http.MethodPut, bucket, key, query, nil, part)
+		if err != nil {
+			return fmt.Errorf("failed to upload part %d of s3://%s/%s: %w", len(parts)+1, bucket, key, err)
+		}
+		_ = resp.Body.Close()
+		parts = append(parts, completedPart{PartNumber: len(parts) + 1, ETag: resp.Header.Get("ETag")})
+
+		// the buffer of the part is reused for the next one, it was sent
+		n, err := io.ReadFull(r, part[:cap(part)])
+		if n == 0 && (errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)) {
+			break
+		}
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+		part = part[:n]
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(ctx, http.MethodPost, bucket, key, url.Values{"uploadId": {uploadID}}, nil, body)
+	if err != nil {
+		return fmt.Errorf("failed to complete the upload of s3://%s/%s: %w", bucket, key, err)
+	}
+	defer resp.Body.Close()
+	// the completion can fail after S3 answered 200, the error is in the body then
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to complete the upload of s3://%s/%s: %w", bucket, key, err)
+	}
+	if bytes.Contains(data, []byte("<Error>")) {
+		e := &Error{StatusCode: resp.StatusCode}
+		_ = xml.Unmarshal(data, e)
+		return fmt.Errorf("failed to complete the upload of s3://%s/%s: %w", bucket, key, e)
+	}
+	return nil
+}
+
+func (c *Client) abortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	resp, err := c.do(ctx, http.MethodDelete, bucket, key, url.Values{"uploadId": {uploadID}}, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Download returns the content of the object at key, the caller must close it.
+func (c *Client) Download(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, bucket, key, nil, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download s3://%s/%s: %w", bucket, key, err)
+	}
+	return resp.Body, nil
+}
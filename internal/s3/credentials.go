@@ -0,0 +1,383 @@
+package s3
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	ecsCredentialsHost = "http://169.254.170.2"
+	imdsEndpoint       = "http://169.254.169.254"
+	// metadataTimeout bounds the requests to the metadata services, which only exist on AWS compute.
+	metadataTimeout = 2 * time.Second
+)
+
+// Credentials sign the requests to the object store.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// DefaultCredentials resolves the credentials like the AWS SDKs do, from the first source that has them:
+// the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY env vars, a web identity token (eg- IAM roles for service accounts
+// of EKS), the shared credentials file (~/.aws/credentials, with AWS_PROFILE), the credentials of the ECS task,
+// then the instance profile of the EC2 instance.
+func DefaultCredentials(ctx context.Context, hc *http.Client) (*Credentials, error) {
+	if id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); id != "" && secret != "" {
+		return &Credentials{AccessKeyID: id, SecretAccessKey: secret, SessionToken: os.Getenv("AWS_SESSION_TOKEN")}, nil
+	}
+	tokenFile, role := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"), os.Getenv("AWS_ROLE_ARN")
+	if tokenFile != "" && role != "" {
+		return webIdentityCredentials(ctx, hc, tokenFile, role)
+	}
+	creds, err := sharedFileCredentials()
+	if creds != nil || err != nil {
+		return creds, err
+	}
+	if os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI") != "" || os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI") != "" {
+		return containerCredentials(ctx, hc)
+	}
+	if !strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+		if creds, err := instanceCredentials(ctx, hc); err == nil {
+			return creds, nil
+		}
+	}
+	return nil, errors.New(
+		"no S3 credentials found, set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY " +
+			"or configure a profile in the shared credentials file",
+	)
+}
+
+// sharedFileCredentials reads the credentials of the profile from the shared credentials file, nil if there are none.
+func sharedFileCredentials() (*Credentials, error) {
+	path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+	profile := os.Getenv("AWS_PROFILE")
+	if profile == "" {
+		profile = "default"
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the shared credentials file: %w", err)
+	}
+	defer f.Close()
+
+	creds := &Credentials{}
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok || section != profile {
+			continue
+		}
+		switch strings.TrimSpace(k) {
+		case "aws_access_key_id":
+			creds.AccessKeyID = strings.TrimSpace(v)
+		case "aws_secret_access_key":
+			creds.SecretAccessKey = strings.TrimSpace(v)
+		case "aws_session_token":
+			creds.SessionToken = strings.TrimSpace(v)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the shared credentials file: %w", err)
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		if os.Getenv("AWS_PROFILE") != "" {
+			return nil, fmt.Errorf("profile %s has no credentials in the shared credentials file %s", profile, path)
+		}
+		return nil, nil
+	}
+	return creds, nil
+}
+
+// metadataCredentials are the temporary credentials returned by the ECS and EC2 metadata services.
+type metadataCredentials struct {
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	Token           string `json:"Token"`
+}
+
+// getMetadata sends a request to a metadata service and returns the body of its response.
+func getMetadata(ctx context.Context, hc *http.Client, method, u string, header http.Header) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the credentials from %s: %v", u, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get the credentials from %s: %s", u, resp.Status)
+	}
+	return data, nil
+}
+
+// decodeMetadataCredentials decodes the credentials returned by a metadata service.
+func decodeMetadataCredentials(data []byte) (*Credentials, error) {
+	var m metadataCredentials
+	if err := json.Unmarshal(data, &m); err != nil || m.AccessKeyID == "" {
+		return nil, errors.New("the metadata service returned invalid credentials")
+	}
+	return &Credentials{AccessKeyID: m.AccessKeyID, SecretAccessKey: m.SecretAccessKey, SessionToken: m.Token}, nil
+}
+
+// containerCredentials gets the credentials of the role of the ECS task (or of EKS pod identities).
+func containerCredentials(ctx context.Context, hc *http.Client) (*Credentials, error) {
+	u := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+	if rel := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); rel != "" {
+		u = ecsCredentialsHost + rel
+	}
+	header := http.Header{}
+	token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	if file := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the authorization token of the container: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		header.Set("Authorization", token)
+	}
+	data, err := getMetadata(ctx, hc, http.MethodGet, u, header)
+	if err != nil {
+		return nil, err
+	}
+	return decodeMetadataCredentials(data)
+}
+
+// instanceCredentials gets the credentials of the instance profile of the EC2 instance, with IMDSv2.
+func instanceCredentials(ctx context.Context, hc *http.Client) (*Credentials, error) {
+	endpoint := os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT")
+	if endpoint == "" {
+		endpoint = imdsEndpoint
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+
+	token, err := getMetadata(ctx, hc, http.MethodPut, endpoint+"/latest/api/token",
+		http.Header{"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": {"21600"}})
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{"X-Aws-Ec2-Metadata-Token": {string(token)}}
+	role, err := getMetadata(ctx, hc, http.MethodGet, endpoint+"/latest/meta-data/iam/security-credentials/", header)
+	if err != nil {
+		return nil, err
+	}
+	name, _, _ := strings.Cut(strings.TrimSpace(string(role)), "\n")
+	data, err := getMetadata(ctx, hc, http.MethodGet, endpoint+"/latest/meta-data/iam/security-credentials/"+name, header)
+	if err != nil {
+		return nil, err
+	}
+	return decodeMetadataCredentials(data)
+}
+
+// webIdentityCredentials assumes the role with the web identity token in tokenFile, with STS.
+func webIdentityCredentials(ctx context.Context, hc *http.Client, tokenFile, role string) (*Credentials, error) {
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the web identity token: %w", err)
+	}
+	session := os.Getenv("AWS_ROLE_SESSION_NAME")
+	if session == "" {
+		session = fmt.Sprintf("mcpjungle-%d", time.Now().Unix())
+	}
+	endpoint := "https://sts.amazonaws.com"
+	if region := firstEnv("AWS_REGION", "AWS_DEFAULT_REGION"); region != "" {
+		endpoint = "https://sts." + region + ".amazonaws.com"
+	}
+	u := endpoint + "/?" + url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {role},
+		"RoleSessionName":  {session},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}.Encode()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assume role %s: %v", role, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to assume role %s: %w", role, readError(resp))
+	}
+	var result struct {
+		Credentials struct {
+			AccessKeyID     string `xml:"AccessKeyId"`
+			SecretAccessKey string `xml:"SecretAccessKey"`
+			SessionToken    string `xml:"SessionToken"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil || result.Credentials.AccessKeyID == "" {
+		return nil, fmt.Errorf("failed to assume role %s: invalid response", role)
+	}
+	c := result.Credentials
+	return &Credentials{AccessKeyID: c.AccessKeyID, SecretAccessKey: c.SecretAccessKey, SessionToken: c.SessionToken}, nil
+}
+
+// firstEnv returns the value of the first of the env vars that is set.
+func firstEnv(names ...string) string {
+	for _, n := range names {
+		if v := os.Getenv(n); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// unsignedHeaders are the headers left out of the signature, they may be changed on the way.
+var unsignedHeaders = []string{"authorization", "user-agent", "content-length", "accept-encoding", "expect"}
+
+// sign signs req with AWS Signature Version 4.
+func (c *Credentials) sign(req *http.Request, body []byte, region string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if c.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		k = strings.ToLower(k)
+		if !slices.Contains(unsignedHeaders, k) {
+			headers[k] = strings.Join(v, ",")
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + strings.TrimSpace(headers[k]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+c.SecretAccessKey), date)
+	for _, s := range []string{region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, s)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// escapePath URI-encodes the segments of a path like SigV4 expects, every byte but the unreserved characters.
+func escapePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = escape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery returns the query sorted by name, with its names and values URI-encoded.
+func canonicalQuery(q url.Values) string {
+	pairs := make([]string, 0, len(q))
+	for k, vs := range q {
+		for _, v := range vs {
+			pairs = append(pairs, escape(k)+"="+escape(v))
+		}
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// escape URI-encodes s, every byte but the unreserved characters A-Z, a-z, 0-9, -, ., _ and ~.
+func escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
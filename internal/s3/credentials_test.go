@@ -0,0 +1,107 @@
+package s3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+// clearAWSEnv unsets the env vars of the credential chain, so that the tests don't use the ones of the host.
+func clearAWSEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{
+		"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_ROLE_ARN",
+		"AWS_PROFILE", "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "AWS_CONTAINER_CREDENTIALS_FULL_URI",
+		"AWS_CONTAINER_AUTHORIZATION_TOKEN", "AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE",
+	} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+}
+
+func TestDefaultCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("env vars", func(t *testing.T) {
+		clearAWSEnv(t)
+		t.Setenv("AWS_ACCESS_KEY_ID", "env-id")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+		creds, err := DefaultCredentials(ctx, http.DefaultClient)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "env-id", creds.AccessKeyID)
+	})
+
+	t.Run("shared credentials file", func(t *testing.T) {
+		clearAWSEnv(t)
+		file := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+		testhelpers.AssertNoError(t, os.WriteFile(file, []byte(
+			"[default]\naws_access_key_id = default-id\naws_secret_access_key = default-secret\n\n"+
+				"# the backups profile\n[backups]\naws_access_key_id=backups-id\naws_secret_access_key=backups-secret\n",
+		), 0o600))
+		creds, err := DefaultCredentials(ctx, http.DefaultClient)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "default-id", creds.AccessKeyID)
+
+		t.Setenv("AWS_PROFILE", "backups")
+		creds, err = DefaultCredentials(ctx, http.DefaultClient)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "backups-secret", creds.SecretAccessKey)
+
+		t.Setenv("AWS_PROFILE", "unknown")
+		_, err = DefaultCredentials(ctx, http.DefaultClient)
+		testhelpers.AssertStringContains(t, err.Error(), "profile unknown has no credentials")
+	})
+
+	t.Run("container credentials", func(t *testing.T) {
+		clearAWSEnv(t)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "container-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"AccessKeyId":"task-id","SecretAccessKey":"task-secret","Token":"task-token"}`))
+		}))
+		defer srv.Close()
+		t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", srv.URL+"/creds")
+		t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN", "container-token")
+		creds, err := DefaultCredentials(ctx, http.DefaultClient)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "task-token", creds.SessionToken)
+	})
+
+	t.Run("instance profile", func(t *testing.T) {
+		clearAWSEnv(t)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+				_, _ = w.Write([]byte("imds-token"))
+			case r.Header.Get("X-Aws-Ec2-Metadata-Token") != "imds-token":
+				w.WriteHeader(http.StatusUnauthorized)
+			case r.URL.Path == "/latest/meta-data/iam/security-credentials/":
+				_, _ = w.Write([]byte("backup-role"))
+			case r.URL.Path == "/latest/meta-data/iam/security-credentials/backup-role":
+				_, _ = w.Write([]byte(`{"AccessKeyId":"role-id","SecretAccessKey":"role-secret","Token":"role-token"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer srv.Close()
+		t.Setenv("AWS_EC2_METADATA_DISABLED", "")
+		t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", srv.URL)
+		creds, err := DefaultCredentials(ctx, http.DefaultClient)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "role-id", creds.AccessKeyID)
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		clearAWSEnv(t)
+		_, err := DefaultCredentials(ctx, http.DefaultClient)
+		testhelpers.AssertStringContains(t, err.Error(), "no S3 credentials found")
+	})
+}
@@ -0,0 +1,478 @@
+// Package backup makes consistent logical backups of the registry and restores them,
+// whichever database mcpjungle runs on.
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/db/dialect"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/mcpjungle/mcpjungle/pkg/version"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// restoreBatchSize is the number of rows inserted at once when a backup is restored.
+const restoreBatchSize = 500
+
+var (
+	// ErrInvalidBackup is returned when a backup can't be read, eg- because it is truncated.
+	ErrInvalidBackup = errors.New("invalid backup")
+	// ErrIncompatibleBackup is returned when a backup can't be restored by this server, the error tells why.
+	ErrIncompatibleBackup = errors.New("incompatible backup")
+	// ErrWrongPassphrase is returned when a backup is restored with another passphrase than the one it was made with.
+	ErrWrongPassphrase = errors.New("the passphrase is not the one the backup was made with")
+)
+
+// NotEmptyError is returned when a backup is restored into a registry that already has entities, without force.
+type NotEmptyError struct {
+	// Entities are the number of entities of the registry, by kind
+	Entities map[string]int64
+}
+
+func (e *NotEmptyError) Error() string {
+	var counts []string
+	for _, t := range tables {
+		if n := e.Entities[t.entity]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, t.entity))
+		}
+	}
+	return "the registry is not empty, it has " + strings.Join(counts, ", ")
+}
+
+// table is a table of the backups.
+type table struct {
+	model any
+	// credentials are the columns holding credentials, their values are encrypted in the backups
+	credentials []string
+	// audit tables are only backed up on request
+	audit bool
+	// entity is the kind of entity the rows are, a registry that has some is not empty.
+	// The rows of the other tables are replaced by the restores, eg- the user created when the server was initialized.
+	entity string
+}
+
+// tables are the tables of the backups, in the order they are restored, the referenced ones first.
+// The other tables hold the state of the running server, eg- its mode or the idempotency keys, they are not backed up.
+var tables = []table{
+	// the configuration of a server holds its bearer token, the env of its command or its API keys
+	{model: &model.McpServer{}, credentials: []string{"config"}, entity: "MCP servers"},
+	{model: &model.Tool{}},
+	{model: &model.Prompt{}},
+	{model: &model.ToolGroup{}, entity: "tool groups"},
+	{model: &model.McpClient{}, credentials: []string{"access_token"}, entity: "MCP clients"},
+	{model: &model.User{}, credentials: []string{"access_token"}},
+	{model: &model.Webhook{}, credentials: []string{"secret"}, entity: "webhooks"},
+	{model: &model.WebhookDelivery{}, audit: true},
+}
+
+// BackupService makes backups of the registry and restores them.
+type BackupService struct {
+	db *gorm.DB
+	// kdfIterations is the cost of deriving the keys of new backups, it is a field so that tests can lower it
+	kdfIterations int
+	now           func() time.Time
+}
+
+func NewBackupService(db *gorm.DB) *BackupService {
+	return &BackupService{db: db, kdfIterations: defaultKDFIterations, now: time.Now}
+}
+
+// Backup writes a backup of the registry to w, read in a single transaction so that it is consistent.
+// The credentials are encrypted with a key derived from the passphrase of the request.
+//
+// Nothing is written to w if it fails before the backup started. If it fails afterward, the backup ends with
+// a record holding the error instead of its last record, so that it can't be mistaken for a complete one.
+func (s *BackupService) Backup(ctx context.Context, w io.Writer, req *types.BackupRequest) error {
+	if len(req.Passphrase) < types.MinBackupPassphraseLength {
+		return types.ValidationErrors{{
+			Field:   "passphrase",
+			Message: fmt.Sprintf("must be at least %d characters long", types.MinBackupPassphraseLength),
+		}}
+	}
+	sealer, encryption, err := newEncryption(req.Passphrase, s.kdfIterations)
+	if err != nil {
+		return err
+	}
+
+	tx := s.db.WithContext(ctx).Begin(snapshotTxOptions(s.db))
+	if tx.Error != nil {
+		return fmt.Errorf("failed to begin the transaction of the backup: %w", tx.Error)
+	}
+	defer tx.Rollback()
+
+	var config model.ServerConfig
+	if err := tx.First(&config).Error; err != nil {
+		return fmt.Errorf("failed to get the mode of the server: %w", err)
+	}
+	header := &types.BackupHeader{
+		Format:        types.BackupFormat,
+		FormatVersion: types.BackupFormatVersion,
+		SchemaVersion: migrations.SchemaVersion,
+		Version:       version.GetVersion(),
+		ServerMode:    string(normalizeMode(config.Mode)),
+		CreatedAt:     s.now().UTC(),
+		IncludeAudit:  req.IncludeAudit,
+		Encryption:    *encryption,
+	}
+	for _, t := range tables {
+		if t.audit && !req.IncludeAudit {
+			continue
+		}
+		var n int64
+		if err := tx.Unscoped().Model(t.model).Count(&n).Error; err != nil {
+			return fmt.Errorf("failed to count the rows of %s: %w", tableName(tx, t), err)
+		}
+		header.Tables = append(header.Tables, types.BackupTable{Name: tableName(tx, t), Rows: n})
+	}
+
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+	if err := enc.Encode(header); err != nil {
+		return err
+	}
+	for _, t := range tables {
+		if t.audit && !req.IncludeAudit {
+			continue
+		}
+		if err := writeRows(tx, enc, t, sealer); err != nil {
+			_ = enc.Encode(types.BackupRecord{Error: &types.APIError{Code: types.ErrorCodeInternal, Message: err.Error()}})
+			_ = zw.Close()
+			return err
+		}
+	}
+	if err := enc.Encode(types.BackupRecord{End: true}); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// writeRows writes a record for every row of a table, including the soft-deleted ones, as they are read.
+func writeRows(tx *gorm.DB, enc *json.Encoder, t table, s *sealer) error {
+	sch, err := tableSchema(tx, t)
+	if err != nil {
+		return err
+	}
+	rows, err := tx.Unscoped().Model(t.model).Order(sch.PrioritizedPrimaryField.DBName).Rows()
+	if err != nil {
+		return fmt.Errorf("failed to read the rows of %s: %w", sch.Table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		v := reflect.New(sch.ModelType)
+		if err := tx.ScanRows(rows, v.Interface()); err != nil {
+			return fmt.Errorf("failed to read a row of %s: %w", sch.Table, err)
+		}
+		row, err := encodeRow(tx.Statement.Context, sch, t, v.Elem(), s)
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(types.BackupRecord{Table: sch.Table, Row: row}); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// encodeRow returns the values of the columns of a row, the credentials encrypted.
+func encodeRow(ctx context.Context, sch *schema.Schema, t table, v reflect.Value, s *sealer) (json.RawMessage, error) {
+	row := make(map[string]json.RawMessage, len(sch.DBNames))
+	for _, name := range sch.DBNames {
+		value, _ := sch.FieldsByDBName[name].ValueOf(ctx, v)
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s.%s: %w", sch.Table, name, err)
+		}
+		if slices.Contains(t.credentials, name) && string(data) != "null" {
+			sealed, err := s.seal(data, sch.Table+"."+name)
+			if err != nil {
+				return nil, err
+			}
+			data, _ = json.Marshal(sealed)
+		}
+		row[name] = data
+	}
+	return json.Marshal(row)
+}
+
+// Restore replaces the registry with the backup read from r, in a single transaction.
+// The passphrase must be the one the backup was made with.
+//
+// Unless force is true, the registry must be empty: a *NotEmptyError is returned if it has any MCP server,
+// tool group, MCP client or webhook. Its users are always replaced by the ones of the backup.
+// The running server keeps serving the entities it loaded until it is restarted.
+func (s *BackupService) Restore(
+	ctx context.Context, r io.Reader, passphrase string, force bool,
+) (*types.RestoreResult, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: it is not a backup of mcpjungle", ErrInvalidBackup)
+	}
+	dec := json.NewDecoder(zr)
+	var header types.BackupHeader
+	if err := dec.Decode(&header); err != nil || header.Format != types.BackupFormat {
+		return nil, fmt.Errorf("%w: it is not a backup of mcpjungle", ErrInvalidBackup)
+	}
+	if err := checkCompatibility(&header); err != nil {
+		return nil, err
+	}
+	sealer, err := openEncryption(passphrase, &header.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.RestoreResult{Version: header.Version, CreatedAt: header.CreatedAt, Tables: []types.BackupTable{}}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var config model.ServerConfig
+		if err := tx.First(&config).Error; err != nil {
+			return fmt.Errorf("failed to get the mode of the server: %w", err)
+		}
+		if mode := normalizeMode(config.Mode); string(mode) != header.ServerMode {
+			return fmt.Errorf(
+				"%w: it was made by a server in %s mode and this one runs in %s mode, "+
+					"restore it into a server started in %s mode",
+				ErrIncompatibleBackup, header.ServerMode, mode, header.ServerMode,
+			)
+		}
+		if !force {
+			if err := checkEmpty(tx); err != nil {
+				return err
+			}
+		}
+		if err := clearTables(tx); err != nil {
+			return err
+		}
+		restored, err := restoreRows(tx, dec, &header, sealer)
+		if err != nil {
+			return err
+		}
+		result.Tables = restored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkCompatibility checks that this server can restore the rows of a backup, telling how to restore it otherwise.
+func checkCompatibility(h *types.BackupHeader) error {
+	if h.FormatVersion != types.BackupFormatVersion {
+		return fmt.Errorf(
+			"%w: it is in version %d of the backup format, this server (mcpjungle %s) reads version %d: "+
+				"restore it with mcpjungle %s, which made it",
+			ErrIncompatibleBackup, h.FormatVersion, version.GetVersion(), types.BackupFormatVersion, h.Version,
+		)
+	}
+	switch {
+	case h.SchemaVersion > migrations.SchemaVersion:
+		return fmt.Errorf(
+			"%w: it was made by mcpjungle %s with version %d of the database schema, this server (mcpjungle %s) "+
+				"has version %d: upgrade the server to mcpjungle %s or later to restore it",
+			ErrIncompatibleBackup, h.Version, h.SchemaVersion, version.GetVersion(), migrations.SchemaVersion, h.Version,
+		)
+	case h.SchemaVersion < migrations.SchemaVersion:
+		return fmt.Errorf(
+			"%w: it was made by mcpjungle %s with version %d of the database schema, this server (mcpjungle %s) "+
+				"has version %d: restore it into a server running mcpjungle %s, then upgrade that server",
+			ErrIncompatibleBackup, h.Version, h.SchemaVersion, version.GetVersion(), migrations.SchemaVersion, h.Version,
+		)
+	}
+	return nil
+}
+
+// checkEmpty returns a *NotEmptyError if the registry has entities.
+func checkEmpty(tx *gorm.DB) error {
+	entities := make(map[string]int64)
+	for _, t := range tables {
+		if t.entity == "" {
+			continue
+		}
+		var n int64
+		if err := tx.Model(t.model).Count(&n).Error; err != nil {
+			return fmt.Errorf("failed to count the %s of the registry: %w", t.entity, err)
+		}
+		if n > 0 {
+			entities[t.entity] = n
+		}
+	}
+	if len(entities) > 0 {
+		return &NotEmptyError{Entities: entities}
+	}
+	return nil
+}
+
+// clearTables deletes all the rows of the tables of the backups, including the soft-deleted ones.
+// The audit tables are cleared even if the backup doesn't include them, since their rows belong to the entities.
+func clearTables(tx *gorm.DB) error {
+	for _, t := range slices.Backward(tables) {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(t.model).Error; err != nil {
+			return fmt.Errorf("failed to clear %s: %w", tableName(tx, t), err)
+		}
+	}
+	return nil
+}
+
+// restoreRows inserts the rows of the records read from dec, and returns the number of rows restored in each table.
+// It fails if the backup doesn't end with its last record or doesn't hold the rows its header announces.
+func restoreRows(
+	tx *gorm.DB, dec *json.Decoder, h *types.BackupHeader, s *sealer,
+) ([]types.BackupTable, error) {
+	schemas := make(map[string]*schema.Schema)
+	byName := make(map[string]table)
+	for _, t := range tables {
+		sch, err := tableSchema(tx, t)
+		if err != nil {
+			return nil, err
+		}
+		schemas[sch.Table], byName[sch.Table] = sch, t
+	}
+
+	restored := make(map[string]int64)
+	var batch []map[string]any
+	var current string
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := tx.Table(current).Create(&batch).Error; err != nil {
+			return fmt.Errorf("failed to restore the rows of %s: %w", current, err)
+		}
+		restored[current] += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		var rec types.BackupRecord
+		if err := dec.Decode(&rec); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("%w: it is incomplete, it ends before its last record", ErrInvalidBackup)
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+		}
+		if rec.Error != nil {
+			return nil, fmt.Errorf("%w: it is incomplete, making it failed: %s", ErrInvalidBackup, rec.Error.Message)
+		}
+		if rec.End {
+			break
+		}
+		sch, ok := schemas[rec.Table]
+		if !ok || !slices.ContainsFunc(h.Tables, func(t types.BackupTable) bool { return t.Name == rec.Table }) {
+			return nil, fmt.Errorf("%w: it holds rows of an unknown table %q", ErrInvalidBackup, rec.Table)
+		}
+		if rec.Table != current || len(batch) == restoreBatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			current = rec.Table
+		}
+		row, err := decodeRow(sch, byName[rec.Table], rec.Row, s)
+		if err != nil {
+			return nil, err
+		}
+		batch = append(batch, row)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	result := make([]types.BackupTable, 0, len(h.Tables))
+	for _, t := range h.Tables {
+		if restored[t.Name] != t.Rows {
+			return nil, fmt.Errorf(
+				"%w: it holds %d rows of %s instead of %d", ErrInvalidBackup, restored[t.Name], t.Name, t.Rows,
+			)
+		}
+		// the IDs of the restored rows were inserted as they are, the next ones must follow them
+		if q := dialect.Of(tx).ResetSequenceSQL(t.Name, schemas[t.Name].PrioritizedPrimaryField.DBName); q != "" {
+			if err := tx.Exec(q).Error; err != nil {
+				return nil, fmt.Errorf("failed to reset the sequence of %s: %w", t.Name, err)
+			}
+		}
+		result = append(result, types.BackupTable{Name: t.Name, Rows: restored[t.Name]})
+	}
+	return result, nil
+}
+
+// decodeRow returns the values of the columns of a row of a backup, as the types of the fields of its model.
+func decodeRow(sch *schema.Schema, t table, data json.RawMessage, s *sealer) (map[string]any, error) {
+	var columns map[string]json.RawMessage
+	if err := json.Unmarshal(data, &columns); err != nil {
+		return nil, fmt.Errorf("%w: a row of %s is not a JSON object", ErrInvalidBackup, sch.Table)
+	}
+	row := make(map[string]any, len(columns))
+	for name, value := range columns {
+		field, ok := sch.FieldsByDBName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has no column %s", ErrInvalidBackup, sch.Table, name)
+		}
+		if string(value) == "null" {
+			row[name] = nil
+			continue
+		}
+		if slices.Contains(t.credentials, name) {
+			var sealed string
+			if err := json.Unmarshal(value, &sealed); err != nil {
+				return nil, fmt.Errorf("%w: %s.%s is not encrypted", ErrInvalidBackup, sch.Table, name)
+			}
+			plaintext, err := s.open(sealed, sch.Table+"."+name)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+			}
+			value = plaintext
+		}
+		v := reflect.New(field.FieldType)
+		if err := json.Unmarshal(value, v.Interface()); err != nil {
+			return nil, fmt.Errorf("%w: invalid value of %s.%s: %v", ErrInvalidBackup, sch.Table, name, err)
+		}
+		row[name] = v.Elem().Interface()
+	}
+	return row, nil
+}
+
+// snapshotTxOptions returns the options of a read-only transaction that sees a snapshot of the database.
+// SQLite transactions always do, the isolation level of the others is raised from read committed.
+func snapshotTxOptions(db *gorm.DB) *sql.TxOptions {
+	opts := &sql.TxOptions{ReadOnly: true}
+	if dialect.Of(db) != dialect.SQLite {
+		opts.Isolation = sql.LevelRepeatableRead
+	}
+	return opts
+}
+
+func tableSchema(db *gorm.DB, t table) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(t.model); err != nil {
+		return nil, fmt.Errorf("failed to parse the schema of %T: %w", t.model, err)
+	}
+	return stmt.Schema, nil
+}
+
+func tableName(db *gorm.DB, t table) string {
+	sch, err := tableSchema(db, t)
+	if err != nil {
+		return fmt.Sprintf("%T", t.model)
+	}
+	return sch.Table
+}
+
+// normalizeMode returns the mode of a server, the legacy production mode being the enterprise mode.
+func normalizeMode(mode model.ServerMode) model.ServerMode {
+	if model.IsEnterpriseMode(mode) {
+		return model.ModeEnterprise
+	}
+	return mode
+}
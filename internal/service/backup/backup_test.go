@@ -0,0 +1,271 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const testPassphrase = "correct horse battery staple"
+
+func newTestBackupService(t *testing.T, mode model.ServerMode) *BackupService {
+	t.Helper()
+	setup := testhelpers.SetupTestDB(t)
+	testhelpers.AssertNoError(t, setup.DB.Create(&model.ServerConfig{Mode: mode, Initialized: true}).Error)
+	s := NewBackupService(setup.DB)
+	// the default cost makes every test take seconds
+	s.kdfIterations = 1000
+	return s
+}
+
+// seedRegistry creates an entity of every kind, with the values that are easy to lose: a disabled tool and webhook,
+// whose enabled columns default to true, a compressed input schema and a soft-deleted tool.
+func seedRegistry(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	server, err := model.NewStreamableHTTPServer(
+		"github", "GitHub", "https://api.github.com/mcp", "ghp_secret", types.SessionModeStateless,
+	)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.Create(server).Error)
+	tools := []model.Tool{
+		{Name: "create_issue", InputSchema: model.CompressedJSON(`{"type":"object"}`), ServerID: server.ID},
+		{Name: "list_issues", Enabled: true, ServerID: server.ID},
+	}
+	testhelpers.AssertNoError(t, db.Create(&tools).Error)
+	testhelpers.AssertNoError(t, db.Model(&tools[0]).Update("enabled", false).Error)
+	testhelpers.AssertNoError(t, db.Delete(&tools[1]).Error)
+	testhelpers.AssertNoError(t, db.Create(&model.Prompt{Name: "review", Enabled: true, ServerID: server.ID}).Error)
+	testhelpers.AssertNoError(t, db.Create(&model.ToolGroup{
+		Name: "triage", IncludedTools: datatypes.JSON(`["github__create_issue"]`),
+	}).Error)
+	testhelpers.AssertNoError(t, db.Create(&model.McpClient{
+		Name: "cursor", AccessToken: "client_secret", AllowList: datatypes.JSON(`["github"]`),
+	}).Error)
+	testhelpers.AssertNoError(t, db.Create(&model.User{
+		Username: "alice", Role: types.UserRoleAdmin, AccessToken: "alice_secret",
+	}).Error)
+	webhook := &model.Webhook{Name: "audit", URL: "https://hooks.example.com", Secret: "whsec_secret", Format: "json"}
+	testhelpers.AssertNoError(t, db.Create(webhook).Error)
+	testhelpers.AssertNoError(t, db.Model(webhook).Update("enabled", false).Error)
+	testhelpers.AssertNoError(t, db.Create(&model.WebhookDelivery{
+		WebhookID: webhook.ID, EventID: "evt_1", Event: "server.registered", Attempts: 1, Succeeded: true,
+	}).Error)
+}
+
+func makeTestBackup(t *testing.T, s *BackupService, includeAudit bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	err := s.Backup(context.Background(), &buf, &types.BackupRequest{Passphrase: testPassphrase, IncludeAudit: includeAudit})
+	testhelpers.AssertNoError(t, err)
+	return buf.Bytes()
+}
+
+// editTestBackup rewrites the lines of a backup with edit, the header being line 0.
+func editTestBackup(t *testing.T, data []byte, edit func(i int, line []byte) []byte) []byte {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	testhelpers.AssertNoError(t, err)
+	var out bytes.Buffer
+	zw := gzip.NewWriter(&out)
+	scanner := bufio.NewScanner(zr)
+	for i := 0; scanner.Scan(); i++ {
+		if line := edit(i, scanner.Bytes()); line != nil {
+			_, _ = zw.Write(append(line, '\n'))
+		}
+	}
+	testhelpers.AssertNoError(t, scanner.Err())
+	testhelpers.AssertNoError(t, zw.Close())
+	return out.Bytes()
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	source := newTestBackupService(t, model.ModeEnterprise)
+	seedRegistry(t, source.db)
+	data := makeTestBackup(t, source, true)
+
+	var header types.BackupHeader
+	plain := editTestBackup(t, data, func(i int, line []byte) []byte {
+		if i == 0 {
+			testhelpers.AssertNoError(t, json.Unmarshal(line, &header))
+		}
+		return line
+	})
+	testhelpers.AssertEqual(t, migrations.SchemaVersion, header.SchemaVersion)
+	testhelpers.AssertEqual(t, "enterprise", header.ServerMode)
+	testhelpers.AssertEqual(t, 8, len(header.Tables))
+	testhelpers.AssertEqual(t, int64(9), header.Rows())
+	zr, err := gzip.NewReader(bytes.NewReader(plain))
+	testhelpers.AssertNoError(t, err)
+	content, err := io.ReadAll(zr)
+	testhelpers.AssertNoError(t, err)
+	for _, secret := range []string{"ghp_secret", "client_secret", "alice_secret", "whsec_secret"} {
+		testhelpers.AssertFalse(t, strings.Contains(string(content), secret), secret+" should be encrypted in the backup")
+	}
+
+	// the user created when the target was initialized doesn't make its registry non-empty
+	target := newTestBackupService(t, model.ModeEnterprise)
+	admin := &model.User{Username: "admin", Role: types.UserRoleAdmin, AccessToken: "admin_token"}
+	testhelpers.AssertNoError(t, target.db.Create(admin).Error)
+	result, err := target.Restore(context.Background(), bytes.NewReader(data), testPassphrase, false)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, header.CreatedAt, result.CreatedAt)
+	testhelpers.AssertEqual(t, 8, len(result.Tables))
+
+	var server model.McpServer
+	testhelpers.AssertNoError(t, target.db.First(&server, "name = ?", "github").Error)
+	config, err := server.GetStreamableHTTPConfig()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "ghp_secret", config.BearerToken)
+
+	var tool model.Tool
+	testhelpers.AssertNoError(t, target.db.First(&tool, "name = ?", "create_issue").Error)
+	testhelpers.AssertFalse(t, tool.Enabled, "the disabled tool should remain disabled")
+	testhelpers.AssertEqual(t, `{"type":"object"}`, string(tool.InputSchema))
+	var deleted int64
+	testhelpers.AssertNoError(t, target.db.Unscoped().Model(&model.Tool{}).Where("deleted_at IS NOT NULL").Count(&deleted).Error)
+	testhelpers.AssertEqual(t, int64(1), deleted)
+
+	var users []model.User
+	testhelpers.AssertNoError(t, target.db.Find(&users).Error)
+	testhelpers.AssertEqual(t, 1, len(users))
+	testhelpers.AssertEqual(t, "alice_secret", users[0].AccessToken)
+
+	var webhook model.Webhook
+	testhelpers.AssertNoError(t, target.db.First(&webhook).Error)
+	testhelpers.AssertFalse(t, webhook.Enabled, "the disabled webhook should remain disabled")
+	testhelpers.AssertEqual(t, "whsec_secret", webhook.Secret)
+	var deliveries int64
+	testhelpers.AssertNoError(t, target.db.Model(&model.WebhookDelivery{}).Count(&deliveries).Error)
+	testhelpers.AssertEqual(t, int64(1), deliveries)
+
+	// the entities created afterward get new IDs
+	testhelpers.AssertNoError(t, target.db.Create(&model.Tool{Name: "close_issue", ServerID: server.ID}).Error)
+
+	// a registry with entities is only replaced on demand
+	_, err = target.Restore(context.Background(), bytes.NewReader(data), testPassphrase, false)
+	var notEmpty *NotEmptyError
+	testhelpers.AssertTrue(t, errors.As(err, &notEmpty), "restoring into a registry with entities should fail")
+	testhelpers.AssertEqual(t, "the registry is not empty, it has 1 MCP servers, 1 tool groups, 1 MCP clients, 1 webhooks", err.Error())
+	_, err = target.Restore(context.Background(), bytes.NewReader(data), testPassphrase, true)
+	testhelpers.AssertNoError(t, err)
+	var tools int64
+	testhelpers.AssertNoError(t, target.db.Model(&model.Tool{}).Count(&tools).Error)
+	testhelpers.AssertEqual(t, int64(1), tools)
+}
+
+func TestBackupWithoutAudit(t *testing.T) {
+	source := newTestBackupService(t, model.ModeDev)
+	seedRegistry(t, source.db)
+	data := makeTestBackup(t, source, false)
+
+	target := newTestBackupService(t, model.ModeDev)
+	result, err := target.Restore(context.Background(), bytes.NewReader(data), testPassphrase, false)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 7, len(result.Tables))
+	var deliveries int64
+	testhelpers.AssertNoError(t, target.db.Model(&model.WebhookDelivery{}).Count(&deliveries).Error)
+	testhelpers.AssertEqual(t, int64(0), deliveries)
+
+	err = source.Backup(context.Background(), io.Discard, &types.BackupRequest{Passphrase: "short"})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, "passphrase must be at least 8 characters long", err.Error())
+}
+
+func TestRestoreRejectsBackups(t *testing.T) {
+	source := newTestBackupService(t, model.ModeEnterprise)
+	seedRegistry(t, source.db)
+	data := makeTestBackup(t, source, true)
+	withHeader := func(edit func(h map[string]any)) []byte {
+		return editTestBackup(t, data, func(i int, line []byte) []byte {
+			if i > 0 {
+				return line
+			}
+			var h map[string]any
+			testhelpers.AssertNoError(t, json.Unmarshal(line, &h))
+			edit(h)
+			line, _ = json.Marshal(h)
+			return line
+		})
+	}
+
+	cases := []struct {
+		name   string
+		backup []byte
+		mode   model.ServerMode
+		err    error
+		msg    string
+	}{
+		{name: "not a backup", backup: []byte("mcpServers: {}"), err: ErrInvalidBackup, msg: "it is not a backup of mcpjungle"},
+		{
+			name: "newer schema",
+			backup: withHeader(func(h map[string]any) {
+				h["schema_version"], h["version"] = migrations.SchemaVersion+1, "v9.0.0"
+			}),
+			err: ErrIncompatibleBackup, msg: "upgrade the server to mcpjungle v9.0.0 or later to restore it",
+		},
+		{
+			name: "older schema",
+			backup: withHeader(func(h map[string]any) {
+				h["schema_version"], h["version"] = migrations.SchemaVersion-1, "v0.1.0"
+			}),
+			err: ErrIncompatibleBackup, msg: "restore it into a server running mcpjungle v0.1.0, then upgrade that server",
+		},
+		{name: "other mode", backup: data, mode: model.ModeDev, err: ErrIncompatibleBackup, msg: "this one runs in development mode"},
+		{
+			name: "truncated",
+			backup: editTestBackup(t, data, func(i int, line []byte) []byte {
+				if bytes.Contains(line, []byte(`"end":true`)) {
+					return nil
+				}
+				return line
+			}),
+			err: ErrInvalidBackup, msg: "it is incomplete",
+		},
+		{
+			name: "missing rows",
+			backup: editTestBackup(t, data, func(i int, line []byte) []byte {
+				if bytes.Contains(line, []byte(`"table":"prompts"`)) {
+					return nil
+				}
+				return line
+			}),
+			err: ErrInvalidBackup, msg: "it holds 0 rows of prompts instead of 1",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mode := tc.mode
+			if mode == "" {
+				mode = model.ModeEnterprise
+			}
+			target := newTestBackupService(t, mode)
+			testhelpers.AssertNoError(t, target.db.Create(&model.User{Username: "admin", AccessToken: "admin_token"}).Error)
+
+			_, err := target.Restore(context.Background(), bytes.NewReader(tc.backup), testPassphrase, false)
+			testhelpers.AssertTrue(t, errors.Is(err, tc.err), "unexpected error: "+err.Error())
+			testhelpers.AssertStringContains(t, err.Error(), tc.msg)
+
+			// nothing was restored
+			var users []model.User
+			testhelpers.AssertNoError(t, target.db.Find(&users).Error)
+			testhelpers.AssertEqual(t, "admin", users[0].Username)
+		})
+	}
+
+	target := newTestBackupService(t, model.ModeEnterprise)
+	_, err := target.Restore(context.Background(), bytes.NewReader(data), "wrong passphrase", false)
+	testhelpers.AssertTrue(t, errors.Is(err, ErrWrongPassphrase), "a wrong passphrase should be rejected")
+}
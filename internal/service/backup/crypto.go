@@ -0,0 +1,120 @@
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+const (
+	encryptionAlgorithm = "AES-256-GCM"
+	encryptionKDF       = "PBKDF2-SHA256"
+	// defaultKDFIterations is the cost OWASP recommends for PBKDF2-SHA256
+	defaultKDFIterations = 600_000
+	// maxKDFIterations bounds the cost of the key of a backup being restored, which is read from the backup
+	maxKDFIterations = 10_000_000
+	saltSize         = 16
+	keySize          = 32
+
+	// checkPlaintext is encrypted in the header of every backup, with the label checkLabel
+	checkPlaintext = types.BackupFormat
+	checkLabel     = "check"
+)
+
+// sealer encrypts and decrypts the credentials of a backup with the key derived from its passphrase.
+// Every value is encrypted with a label naming where it is stored, eg- users.access_token, as additional data,
+// so that encrypted values can't be moved around in the backup.
+type sealer struct {
+	aead cipher.AEAD
+}
+
+// newEncryption derives a key from the passphrase with a new salt, and returns it along with the description
+// of the encryption written in the header of a new backup.
+func newEncryption(passphrase string, iterations int) (*sealer, *types.BackupEncryption, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate the salt of the key: %w", err)
+	}
+	enc := &types.BackupEncryption{
+		Algorithm:  encryptionAlgorithm,
+		KDF:        encryptionKDF,
+		Iterations: iterations,
+		Salt:       salt,
+	}
+	s, err := newSealer(passphrase, enc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if enc.Check, err = s.seal([]byte(checkPlaintext), checkLabel); err != nil {
+		return nil, nil, err
+	}
+	return s, enc, nil
+}
+
+// openEncryption derives the key of a backup from the passphrase, ErrWrongPassphrase is returned if it is not
+// the one the backup was made with.
+func openEncryption(passphrase string, enc *types.BackupEncryption) (*sealer, error) {
+	if enc.Algorithm != encryptionAlgorithm || enc.KDF != encryptionKDF {
+		return nil, fmt.Errorf(
+			"%w: its credentials are encrypted with %s and a key derived with %s, only %s with %s is supported",
+			ErrIncompatibleBackup, enc.Algorithm, enc.KDF, encryptionAlgorithm, encryptionKDF,
+		)
+	}
+	if enc.Iterations <= 0 || enc.Iterations > maxKDFIterations || len(enc.Salt) == 0 {
+		return nil, fmt.Errorf("%w: the parameters of its encryption are invalid", ErrInvalidBackup)
+	}
+	s, err := newSealer(passphrase, enc)
+	if err != nil {
+		return nil, err
+	}
+	check, err := s.open(enc.Check, checkLabel)
+	if err != nil || string(check) != checkPlaintext {
+		return nil, ErrWrongPassphrase
+	}
+	return s, nil
+}
+
+func newSealer(passphrase string, enc *types.BackupEncryption) (*sealer, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, enc.Salt, enc.Iterations, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive the key from the passphrase: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &sealer{aead: aead}, nil
+}
+
+// seal encrypts plaintext, the result is the base64 encoding of a random nonce followed by the ciphertext.
+func (s *sealer) seal(plaintext []byte, label string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize(), s.aead.NonceSize()+len(plaintext)+s.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate a nonce: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(s.aead.Seal(nonce, nonce, plaintext, []byte(label))), nil
+}
+
+// open decrypts a value encrypted by seal with the same label.
+func (s *sealer) open(sealed, label string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < s.aead.NonceSize() {
+		return nil, fmt.Errorf("%s is not an encrypted value", label)
+	}
+	nonce, ciphertext := data[:s.aead.NonceSize()], data[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, []byte(label))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", label, err)
+	}
+	return plaintext, nil
+}
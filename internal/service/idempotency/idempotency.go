@@ -0,0 +1,134 @@
+// Package idempotency stores the responses to API requests that carry an idempotency key,
+// so that a request sent again with the same key is answered with the stored response instead of being applied twice.
+package idempotency
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+)
+
+// Defaults of the storage settings.
+const (
+	DefaultTTL           = 24 * time.Hour
+	DefaultPruneInterval = time.Hour
+)
+
+// Config holds the storage settings of an IdempotencyService. Zero values are replaced by the defaults.
+type Config struct {
+	// TTL is how long a key is remembered after the response to its request was stored.
+	TTL time.Duration
+	// PruneInterval is how often the expired keys are deleted from the database.
+	PruneInterval time.Duration
+	// IsLeader reports whether this replica of the registry is the leader among those sharing the database,
+	// only the leader prunes the expired keys. If nil, this is the only replica.
+	IsLeader func() bool
+}
+
+// IdempotencyService stores the responses to requests per idempotency key, and prunes the expired keys in the background.
+type IdempotencyService struct {
+	db            *gorm.DB
+	ttl           time.Duration
+	pruneInterval time.Duration
+	isLeader      func() bool
+	// now returns the current time, it is a field so that tests can control the clock.
+	now func() time.Time
+
+	cancel context.CancelFunc
+	pruner sync.WaitGroup
+}
+
+// NewIdempotencyService creates an IdempotencyService and starts pruning the expired keys.
+// Call Close to stop pruning.
+func NewIdempotencyService(db *gorm.DB, cfg *Config) *IdempotencyService {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	s := &IdempotencyService{db: db, ttl: cfg.TTL, pruneInterval: cfg.PruneInterval, isLeader: cfg.IsLeader, now: time.Now}
+	if s.ttl <= 0 {
+		s.ttl = DefaultTTL
+	}
+	if s.pruneInterval <= 0 {
+		s.pruneInterval = DefaultPruneInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.pruner.Add(1)
+	go func() {
+		defer s.pruner.Done()
+		ticker := time.NewTicker(s.pruneInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if s.isLeader != nil && !s.isLeader() {
+					continue
+				}
+				if _, err := s.Prune(); err != nil {
+					log.Printf("[WARN] failed to prune the expired idempotency keys: %v", err)
+				}
+			}
+		}
+	}()
+	return s
+}
+
+// Close stops pruning the expired keys.
+func (s *IdempotencyService) Close() {
+	s.cancel()
+	s.pruner.Wait()
+}
+
+// TTL returns how long a key is remembered.
+func (s *IdempotencyService) TTL() time.Duration {
+	return s.ttl
+}
+
+// Lookup returns the response stored for key in scope, nil if there is none or it expired.
+func (s *IdempotencyService) Lookup(scope, key string) (*model.IdempotencyKey, error) {
+	// most keys are new, Find doesn't log them as missing records like First does
+	var keys []model.IdempotencyKey
+	err := s.db.
+		Where(map[string]any{"scope": scope, "key": key}).
+		Where("expires_at > ?", s.now()).
+		Limit(1).
+		Find(&keys).Error
+	if err != nil || len(keys) == 0 {
+		return nil, err
+	}
+	return &keys[0], nil
+}
+
+// Save stores the response to the request sent with k's key, until the TTL runs out.
+// A record of the key that expired but was not pruned yet is replaced.
+func (s *IdempotencyService) Save(k *model.IdempotencyKey) error {
+	now := s.now()
+	k.CreatedAt = now
+	k.ExpiresAt = now.Add(s.ttl)
+	return db.RetryOnBusy(func() error {
+		return s.db.Transaction(func(tx *gorm.DB) error {
+			err := tx.
+				Where(map[string]any{"scope": k.Scope, "key": k.Key}).
+				Where("expires_at <= ?", now).
+				Delete(&model.IdempotencyKey{}).Error
+			if err != nil {
+				return err
+			}
+			return tx.Create(k).Error
+		})
+	})
+}
+
+// Prune deletes the expired keys and returns how many were deleted.
+func (s *IdempotencyService) Prune() (int64, error) {
+	result := s.db.Where("expires_at <= ?", s.now()).Delete(&model.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,51 @@
+package idempotency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func TestIdempotencyService(t *testing.T) {
+	setup := testhelpers.SetupTestDB(t)
+	s := NewIdempotencyService(setup.DB, &Config{TTL: time.Hour})
+	defer s.Close()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	k, err := s.Lookup("alice", "f81d4fae")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, k == nil, "no response should be stored for an unknown key")
+
+	testhelpers.AssertNoError(t, s.Save(&model.IdempotencyKey{
+		Scope: "alice", Key: "f81d4fae", RequestHash: "h1", StatusCode: 201, ContentType: "application/json", ResponseBody: []byte(`{}`),
+	}))
+	k, err = s.Lookup("alice", "f81d4fae")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 201, k.StatusCode)
+	testhelpers.AssertEqual(t, `{}`, string(k.ResponseBody))
+	testhelpers.AssertTrue(t, k.ExpiresAt.Equal(now.Add(time.Hour)), "the key should expire after the TTL")
+
+	// keys are per user
+	k, err = s.Lookup("bob", "f81d4fae")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, k == nil, "the keys of other users should not be visible")
+
+	// expired keys are forgotten, and can be used again before they are pruned
+	now = now.Add(time.Hour)
+	k, err = s.Lookup("alice", "f81d4fae")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, k == nil, "an expired key should be forgotten")
+	testhelpers.AssertNoError(t, s.Save(&model.IdempotencyKey{Scope: "alice", Key: "f81d4fae", RequestHash: "h2", StatusCode: 200}))
+	testhelpers.AssertNoError(t, s.Save(&model.IdempotencyKey{Scope: "bob", Key: "f81d4fae", RequestHash: "h3", StatusCode: 200}))
+
+	now = now.Add(2 * time.Hour)
+	pruned, err := s.Prune()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, int64(2), pruned)
+	pruned, err = s.Prune()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, int64(0), pruned)
+}
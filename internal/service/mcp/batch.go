@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// dbBatchSize is the number of records inserted by a single INSERT statement.
+// It keeps the statements of servers with hundreds of tools well below the bind parameter limits of the databases.
+const dbBatchSize = 100
+
+// insertInBatches inserts the n records built by newRecord in the DB with multi-row INSERTs, in a single transaction.
+// It reports which records were inserted.
+//
+// Registration is best-effort for each record: if the batch fails, the records are built again and inserted one at
+// a time, the ones that fail are logged and skipped. name returns the name of the i-th record to log.
+func insertInBatches[T any](db *gorm.DB, n int, newRecord func(i int) *T, name func(i int) string) []bool {
+	inserted := make([]bool, n)
+	if n == 0 {
+		return inserted
+	}
+	records := make([]*T, n)
+	for i := range records {
+		records[i] = newRecord(i)
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(records, dbBatchSize).Error
+	})
+	if err == nil {
+		for i := range inserted {
+			inserted[i] = true
+		}
+		return inserted
+	}
+
+	log.Printf("[WARN] failed to insert %d records in batches, inserting them one at a time: %v", n, err)
+	for i := range records {
+		// the records of the batch may hold the IDs assigned before the transaction was rolled back
+		if err := db.Create(newRecord(i)).Error; err != nil {
+			log.Printf("[ERROR] failed to register %s in DB: %v", name(i), err)
+			continue
+		}
+		inserted[i] = true
+	}
+	return inserted
+}
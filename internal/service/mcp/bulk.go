@@ -0,0 +1,180 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+)
+
+// maxConcurrentUpstreamValidations is how many MCP servers of a batch are connected to at the same time.
+const maxConcurrentUpstreamValidations = 8
+
+// BatchError is returned by RegisterMcpServers when some servers of the batch cannot be registered.
+// None of the servers of the batch are registered then.
+type BatchError struct {
+	// Errors holds the reason every server of the batch failed, at the server's index. It is nil for the others.
+	Errors []error
+}
+
+func (e *BatchError) Error() string {
+	failed := 0
+	for _, err := range e.Errors {
+		if err != nil {
+			failed++
+		}
+	}
+	return fmt.Sprintf("%d of %d servers could not be registered, none were registered", failed, len(e.Errors))
+}
+
+// serverEntities holds the tools and prompts fetched from an MCP server.
+type serverEntities struct {
+	tools   []mcp.Tool
+	prompts []mcp.Prompt
+}
+
+// RegisterMcpServers registers a batch of MCP servers atomically: either all of them are registered, or none are.
+//
+// Every server is connected to first to fetch its tools and prompts, then everything is stored in a single
+// DB transaction and the tools and prompts are added to the MCP proxy server once it is committed.
+// If deferValidation is true, the servers are stored without connecting to them, and their tools and prompts
+// are registered afterwards on a best-effort basis: a server that cannot be reached is registered without tools.
+func (m *MCPService) RegisterMcpServers(ctx context.Context, servers []*model.McpServer, deferValidation bool) error {
+	errs := make([]error, len(servers))
+	failed := false
+	seen := make(map[string]bool, len(servers))
+	for i, s := range servers {
+		errs[i] = validateServerName(s.Name)
+		if errs[i] == nil && seen[s.Name] {
+			errs[i] = fmt.Errorf("server %s appears more than once in the batch", s.Name)
+		}
+		if errs[i] == nil {
+			errs[i] = m.sessionManager.packages.prepare(ctx, s)
+		}
+		seen[s.Name] = true
+		failed = failed || errs[i] != nil
+	}
+	if failed {
+		return &BatchError{Errors: errs}
+	}
+
+	entities := make([]serverEntities, len(servers))
+	if !deferValidation {
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, maxConcurrentUpstreamValidations)
+		for i, s := range servers {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				entities[i], errs[i] = m.fetchServerEntities(ctx, s)
+			}()
+		}
+		wg.Wait()
+		for _, err := range errs {
+			failed = failed || err != nil
+		}
+		if failed {
+			return &BatchError{Errors: errs}
+		}
+	}
+
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		for i, s := range servers {
+			if err := tx.Create(s).Error; err != nil {
+				errs[i] = fmt.Errorf("failed to register mcp server: %w", err)
+				return &BatchError{Errors: errs}
+			}
+			for _, tool := range entities[i].tools {
+				if err := tx.Create(newToolModel(s, tool)).Error; err != nil {
+					errs[i] = fmt.Errorf("failed to register tool %s in DB: %w", mergeServerToolNames(s.Name, tool.GetName()), err)
+					return &BatchError{Errors: errs}
+				}
+			}
+			for _, prompt := range entities[i].prompts {
+				if err := tx.Create(newPromptModel(s, prompt)).Error; err != nil {
+					errs[i] = fmt.Errorf("failed to register prompt %s in DB: %w", mergeServerPromptNames(s.Name, prompt.GetName()), err)
+					return &BatchError{Errors: errs}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// the servers were not created, clear the IDs the transaction assigned them
+		for _, s := range servers {
+			s.Model = gorm.Model{}
+		}
+		return err
+	}
+
+	for i, s := range servers {
+		if deferValidation {
+			if err := m.registerServerEntities(ctx, s); err != nil {
+				log.Printf("[WARN] failed to register the tools and prompts of MCP server %s: %v", s.Name, err)
+			}
+			continue
+		}
+		for _, tool := range entities[i].tools {
+			m.publishTool(s, tool)
+		}
+		for _, prompt := range entities[i].prompts {
+			m.publishPrompt(s, prompt)
+		}
+	}
+	return nil
+}
+
+// fetchServerEntities connects to an MCP server and fetches its tools and prompts.
+// Prompts are fetched on a best-effort basis, like when registering a single server.
+func (m *MCPService) fetchServerEntities(ctx context.Context, s *model.McpServer) (serverEntities, error) {
+	mcpClient, err := newMcpServerSession(
+		ctx, s, m.mcpServerInitReqTimeoutSec, m.sessionManager.httpPools,
+		m.sessionManager.secrets, m.sessionManager.containers, m.sessionManager.packages,
+		m.sessionManager.maxResultBytes.Load,
+	)
+	if err != nil {
+		return serverEntities{}, err
+	}
+	defer mcpClient.Close()
+
+	tools, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return serverEntities{}, fmt.Errorf("failed to fetch tools from MCP server %s: %w", s.Name, err)
+	}
+	e := serverEntities{tools: tools.Tools}
+
+	prompts, err := mcpClient.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		log.Printf("[WARN] failed to fetch prompts from MCP server %s: %v", s.Name, err)
+	} else {
+		e.prompts = prompts.Prompts
+	}
+	return e, nil
+}
+
+// registerServerEntities connects to an MCP server that is already stored in the DB and registers its tools and prompts.
+func (m *MCPService) registerServerEntities(ctx context.Context, s *model.McpServer) error {
+	mcpClient, err := newMcpServerSession(
+		ctx, s, m.mcpServerInitReqTimeoutSec, m.sessionManager.httpPools,
+		m.sessionManager.secrets, m.sessionManager.containers, m.sessionManager.packages,
+		m.sessionManager.maxResultBytes.Load,
+	)
+	if err != nil {
+		return err
+	}
+	defer mcpClient.Close()
+
+	if err := m.registerServerTools(ctx, s, mcpClient); err != nil {
+		return err
+	}
+	if err := m.registerServerPrompts(ctx, s, mcpClient); err != nil {
+		log.Printf("[WARN] failed to register prompts for MCP server %s: %v", s.Name, err)
+	}
+	return nil
+}
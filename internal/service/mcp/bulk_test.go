@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func newBulkTestService(t *testing.T) *MCPService {
+	t.Helper()
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(&model.McpServer{}, &model.Tool{}, &model.Prompt{}, &model.ServerHealthCheck{}))
+	mcpService, err := NewMCPService(&ServiceConfig{
+		DB:                      db,
+		McpProxyServer:          &server.MCPServer{},
+		SseMcpProxyServer:       &server.MCPServer{},
+		Metrics:                 telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout: 1,
+	})
+	testhelpers.AssertNoError(t, err)
+	return mcpService
+}
+
+// newUnreachableServers creates the models of streamable http servers that cannot be connected to.
+func newUnreachableServers(t *testing.T, names ...string) []*model.McpServer {
+	t.Helper()
+	servers := make([]*model.McpServer, len(names))
+	for i, name := range names {
+		s, err := model.NewStreamableHTTPServer(name, "", "http://127.0.0.1:1/mcp", "", types.SessionModeStateless)
+		testhelpers.AssertNoError(t, err)
+		servers[i] = s
+	}
+	return servers
+}
+
+func countServers(t *testing.T, m *MCPService) int {
+	t.Helper()
+	servers, err := m.ListMcpServers()
+	testhelpers.AssertNoError(t, err)
+	return len(servers)
+}
+
+func TestRegisterMcpServersValidatesUpstreams(t *testing.T) {
+	m := newBulkTestService(t)
+
+	err := m.RegisterMcpServers(context.Background(), newUnreachableServers(t, "github", "slack"), false)
+	var batchErr *BatchError
+	testhelpers.AssertTrue(t, errors.As(err, &batchErr), "expected a BatchError")
+	testhelpers.AssertEqual(t, 2, len(batchErr.Errors))
+	testhelpers.AssertError(t, batchErr.Errors[0])
+	testhelpers.AssertError(t, batchErr.Errors[1])
+	testhelpers.AssertEqual(t, 0, countServers(t, m))
+}
+
+func TestRegisterMcpServersDeferredValidation(t *testing.T) {
+	m := newBulkTestService(t)
+
+	servers := newUnreachableServers(t, "github", "slack")
+	testhelpers.AssertNoError(t, m.RegisterMcpServers(context.Background(), servers, true))
+	testhelpers.AssertEqual(t, 2, countServers(t, m))
+	testhelpers.AssertTrue(t, servers[0].ID != 0, "registered servers must have an ID")
+
+	t.Run("batch is rolled back if a server cannot be stored", func(t *testing.T) {
+		err := m.RegisterMcpServers(context.Background(), newUnreachableServers(t, "jira", "github"), true)
+		var batchErr *BatchError
+		testhelpers.AssertTrue(t, errors.As(err, &batchErr), "expected a BatchError")
+		testhelpers.AssertNoError(t, batchErr.Errors[0])
+		testhelpers.AssertError(t, batchErr.Errors[1])
+		_, err = m.GetMcpServer("jira")
+		testhelpers.AssertError(t, err)
+		testhelpers.AssertEqual(t, 2, countServers(t, m))
+	})
+
+	t.Run("invalid batches are rejected before connecting", func(t *testing.T) {
+		err := m.RegisterMcpServers(context.Background(), newUnreachableServers(t, "jira", "in valid", "jira"), false)
+		var batchErr *BatchError
+		testhelpers.AssertTrue(t, errors.As(err, &batchErr), "expected a BatchError")
+		testhelpers.AssertNoError(t, batchErr.Errors[0])
+		testhelpers.AssertError(t, batchErr.Errors[1])
+		testhelpers.AssertStringContains(t, batchErr.Errors[2].Error(), "more than once")
+		testhelpers.AssertEqual(t, 2, countServers(t, m))
+	})
+}
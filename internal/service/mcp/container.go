@@ -0,0 +1,355 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mcpjungle/mcpjungle/internal/docker"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ErrDockerUnavailable is matched by the errors returned when a stdio server launched in a container can't be run
+// because the Docker daemon is unreachable.
+var ErrDockerUnavailable = docker.ErrDaemonUnavailable
+
+const (
+	// containerStopTimeout is how long a container is given to exit once its stdin is closed, before it is killed.
+	containerStopTimeout = 5 * time.Second
+	// containerRemoveTimeout bounds the requests removing the containers.
+	containerRemoveTimeout = 10 * time.Second
+)
+
+// containerRuntime launches the stdio servers configured with a container in containers of the Docker daemon,
+// and keeps track of the state of their containers.
+// Every connection to a server gets its own container, like it would get its own process,
+// which is removed when the connection is closed.
+type containerRuntime struct {
+	docker *docker.Client
+
+	mu      sync.Mutex
+	servers map[string]*serverContainers // key: server name
+	// containers holds the container of every client connected to one, until the container exits
+	containers map[*client.Client]*container
+	// pulls holds the images being pulled, so that concurrent connections wait for the same pull
+	pulls map[string]*pendingTransition
+	// stateVersion is incremented every time the state of the containers of a server changes
+	stateVersion atomic.Uint64
+}
+
+// serverContainers is the state of the containers of a server.
+type serverContainers struct {
+	pulling, starting, running int
+	// state is the state of the server while none of its containers is pulled, started or running
+	state types.ContainerState
+}
+
+// container is the container of a connection to a stdio server.
+type container struct {
+	id     string
+	server string
+	stream *docker.Stream
+
+	mu sync.Mutex
+	// closing is set once the connection is closed, the container then exits as expected
+	closing bool
+}
+
+// crashed reports whether the container exited while its connection was still open.
+func (c *container) crashed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.closing
+}
+
+func newContainerRuntime(d *docker.Client) *containerRuntime {
+	return &containerRuntime{
+		docker:     d,
+		servers:    make(map[string]*serverContainers),
+		containers: make(map[*client.Client]*container),
+		pulls:      make(map[string]*pendingTransition),
+	}
+}
+
+// State returns the state of the containers of a server.
+func (r *containerRuntime) State(serverName string) types.ContainerState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sc, ok := r.servers[serverName]
+	switch {
+	case !ok:
+		return types.ContainerStateStopped
+	case sc.pulling > 0:
+		return types.ContainerStatePulling
+	case sc.starting > 0:
+		return types.ContainerStateStarting
+	case sc.running > 0:
+		return types.ContainerStateRunning
+	case sc.state != "":
+		return sc.state
+	}
+	return types.ContainerStateStopped
+}
+
+// setState sets the state of a server while none of its containers is running, eg- while it is restarted.
+func (r *containerRuntime) setState(serverName string, state types.ContainerState) {
+	r.update(serverName, func(sc *serverContainers) { sc.state = state })
+}
+
+// update changes the state of the containers of a server, under the lock of the runtime.
+func (r *containerRuntime) update(serverName string, f func(sc *serverContainers)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sc, ok := r.servers[serverName]
+	if !ok {
+		sc = &serverContainers{}
+		r.servers[serverName] = sc
+	}
+	f(sc)
+	r.stateVersion.Add(1)
+}
+
+// track records the container the client is connected to, unless it already exited.
+func (r *containerRuntime) track(c *client.Client, ctr *container) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	select {
+	case <-ctr.stream.Done():
+	default:
+		r.containers[c] = ctr
+	}
+}
+
+// containerOf returns the container the client is connected to, nil if it isn't connected to one
+// or if the container exited.
+func (r *containerRuntime) containerOf(c *client.Client) *container {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.containers[c]
+}
+
+// start creates and starts a container running the stdio server, pulling its image first if the Docker host
+// doesn't have it, and returns its stream once it is running.
+func (r *containerRuntime) start(ctx context.Context, s *model.McpServer, conf *model.StdioConfig) (*container, error) {
+	if err := r.ensureImage(ctx, s.Name, conf.Container.Image); err != nil {
+		return nil, err
+	}
+
+	r.update(s.Name, func(sc *serverContainers) { sc.starting++ })
+	defer r.update(s.Name, func(sc *serverContainers) { sc.starting-- })
+
+	spec := containerSpec(s.Name, conf)
+	id, err := r.docker.CreateContainer(ctx, spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the container of the server: %w", err)
+	}
+	ctr := &container{id: id, server: s.Name}
+	fail := func(err error) (*container, error) {
+		r.remove(ctr)
+		return nil, err
+	}
+	// the container is attached to before it starts, so that none of its output is missed
+	if ctr.stream, err = r.docker.AttachContainer(ctx, id); err != nil {
+		return fail(fmt.Errorf("failed to attach to the container of the server: %w", err))
+	}
+	if err := r.docker.StartContainer(ctx, id); err != nil {
+		ctr.stream.Close()
+		return fail(fmt.Errorf("failed to start the container of the server: %w", err))
+	}
+	log.Printf("[docker] started container %s (%s) of MCP server %s", spec.Name, shortID(id), s.Name)
+
+	r.update(s.Name, func(sc *serverContainers) {
+		sc.running++
+		sc.state = ""
+	})
+	go r.watch(ctr)
+	return ctr, nil
+}
+
+// watch forgets the container once it exits. If it exited while its connection was open, its exit code is logged
+// and it is removed, otherwise stop removes it.
+func (r *containerRuntime) watch(ctr *container) {
+	<-ctr.stream.Done()
+
+	r.mu.Lock()
+	for c, other := range r.containers {
+		if other == ctr {
+			delete(r.containers, c)
+		}
+	}
+	r.mu.Unlock()
+	r.update(ctr.server, func(sc *serverContainers) { sc.running-- })
+
+	if ctr.crashed() {
+		ctx, cancel := context.WithTimeout(context.Background(), containerRemoveTimeout)
+		defer cancel()
+		if code, err := r.docker.ContainerExitCode(ctx, ctr.id); err == nil {
+			log.Printf("[WARN] the container %s of MCP server %s exited with code %d", shortID(ctr.id), ctr.server, code)
+		} else {
+			log.Printf("[WARN] the container %s of MCP server %s exited: %v", shortID(ctr.id), ctr.server, err)
+		}
+		r.remove(ctr)
+	}
+}
+
+// stop closes the stdin of the container of a connection being closed, so that the server exits,
+// then removes the container once it exited, or after containerStopTimeout.
+func (r *containerRuntime) stop(ctr *container) error {
+	ctr.mu.Lock()
+	ctr.closing = true
+	ctr.mu.Unlock()
+
+	err := ctr.stream.CloseStdin()
+	select {
+	case <-ctr.stream.Done():
+	case <-time.After(containerStopTimeout):
+	}
+	r.remove(ctr)
+	ctr.stream.Close()
+	return err
+}
+
+// remove removes the container, logging the failures since there is nothing else to do about them.
+func (r *containerRuntime) remove(ctr *container) {
+	ctx, cancel := context.WithTimeout(context.Background(), containerRemoveTimeout)
+	defer cancel()
+	if err := r.docker.RemoveContainer(ctx, ctr.id); err != nil {
+		log.Printf("[WARN] failed to remove the container %s of MCP server %s: %v", shortID(ctr.id), ctr.server, err)
+	}
+}
+
+// removeAll removes every container of a server, including those left behind by a previous run of mcpjungle,
+// and forgets its state. It is called once the server is deregistered.
+func (r *containerRuntime) removeAll(ctx context.Context, serverName string) {
+	r.mu.Lock()
+	delete(r.servers, serverName)
+	r.mu.Unlock()
+	r.stateVersion.Add(1)
+
+	ids, err := r.docker.ListContainers(ctx, containerLabelServer, serverName)
+	if err != nil {
+		log.Printf("[WARN] failed to list the containers of MCP server %s: %v", serverName, err)
+		return
+	}
+	for _, id := range ids {
+		r.remove(&container{id: id, server: serverName})
+	}
+}
+
+// ensureImage pulls the image if the Docker host doesn't have it, logging the progress of the pull.
+// Concurrent calls for the same image wait for a single pull.
+func (r *containerRuntime) ensureImage(ctx context.Context, serverName, image string) error {
+	exists, err := r.docker.ImageExists(ctx, image)
+	if err != nil {
+		return fmt.Errorf("failed to look up the image %s: %w", image, err)
+	}
+	if exists {
+		return nil
+	}
+
+	r.update(serverName, func(sc *serverContainers) { sc.pulling++ })
+	defer r.update(serverName, func(sc *serverContainers) { sc.pulling-- })
+
+	r.mu.Lock()
+	pending, pulling := r.pulls[image]
+	if !pulling {
+		pending = &pendingTransition{done: make(chan struct{})}
+		r.pulls[image] = pending
+	}
+	r.mu.Unlock()
+	if pulling {
+		select {
+		case <-pending.done:
+			return pending.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	log.Printf("[docker] pulling image %s for MCP server %s", image, serverName)
+	start := time.Now()
+	// every layer reports its progress many times per second, only the changes of its status are logged
+	statuses := make(map[string]string)
+	pending.err = r.docker.PullImage(ctx, image, func(p docker.PullProgress) {
+		if statuses[p.ID] == p.Status {
+			return
+		}
+		statuses[p.ID] = p.Status
+		if p.ID != "" {
+			log.Printf("[docker] pulling image %s: %s: %s", image, p.ID, p.Status)
+		} else {
+			log.Printf("[docker] pulling image %s: %s", image, p.Status)
+		}
+	})
+	if pending.err == nil {
+		log.Printf("[docker] pulled image %s in %v", image, time.Since(start).Round(time.Millisecond))
+	}
+
+	r.mu.Lock()
+	delete(r.pulls, image)
+	r.mu.Unlock()
+	close(pending.done)
+	return pending.err
+}
+
+// containerLabelServer is the label of the containers launched by mcpjungle, holding the name of their server.
+const containerLabelServer = "io.mcpjungle.server"
+
+// containerSpec returns the specification of a container running the stdio server.
+func containerSpec(serverName string, conf *model.StdioConfig) docker.ContainerSpec {
+	c := conf.Container
+	spec := docker.ContainerSpec{
+		Name:        "mcpjungle-" + serverName + "-" + randomSuffix(),
+		Image:       c.Image,
+		Labels:      map[string]string{containerLabelServer: serverName},
+		Binds:       c.Volumes,
+		NetworkMode: c.Network,
+		MemoryBytes: int64(c.MemoryMB) << 20,
+		NanoCPUs:    int64(c.CPUs * 1e9),
+	}
+	if conf.Command != "" {
+		spec.Cmd = append([]string{conf.Command}, conf.Args...)
+	} else {
+		// the arguments are passed to the entrypoint of the image
+		spec.Cmd = conf.Args
+	}
+	for _, k := range slices.Sorted(maps.Keys(conf.Env)) {
+		spec.Env = append(spec.Env, k+"="+conf.Env[k])
+	}
+	return spec
+}
+
+// randomSuffix tells apart the names of the containers of the same server.
+func randomSuffix() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// shortID returns the short form of the ID of a container, like the docker CLI displays it.
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// containerStdin is the stdin of the connection to a stdio server launched in a container.
+// Closing it stops and removes the container.
+type containerStdin struct {
+	runtime *containerRuntime
+	ctr     *container
+}
+
+func (w *containerStdin) Write(p []byte) (int, error) { return w.ctr.stream.Write(p) }
+
+func (w *containerStdin) Close() error { return w.runtime.stop(w.ctr) }
@@ -0,0 +1,301 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/docker"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDocker is a Docker daemon whose containers run a minimal stdio MCP server with a single tool.
+type fakeDocker struct {
+	mu         sync.Mutex
+	images     map[string]bool
+	pulls      int
+	nextID     int
+	specs      map[string]json.RawMessage
+	labels     map[string]string // key: container ID, value: server label
+	containers map[string]*fakeContainer
+	removed    []string
+}
+
+// fakeContainer is a container the daemon attached to, its stdio is the hijacked connection.
+type fakeContainer struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+func newFakeDocker(t *testing.T) (*fakeDocker, *docker.Client) {
+	t.Helper()
+	f := &fakeDocker{
+		images:     make(map[string]bool),
+		specs:      make(map[string]json.RawMessage),
+		labels:     make(map[string]string),
+		containers: make(map[string]*fakeContainer),
+	}
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	l, err := net.Listen("unix", socket)
+	testhelpers.AssertNoError(t, err)
+	srv := httptest.NewUnstartedServer(f)
+	srv.Listener = l
+	srv.Start()
+	t.Cleanup(srv.Close)
+	c, err := docker.NewClient("unix://" + socket)
+	testhelpers.AssertNoError(t, err)
+	return f, c
+}
+
+func (f *fakeDocker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v1.41")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && parts[0] == "images":
+		if !f.images[strings.Join(parts[1:len(parts)-1], "/")] {
+			http.Error(w, `{"message":"No such image"}`, http.StatusNotFound)
+		}
+	case r.Method == http.MethodPost && path == "/images/create":
+		f.pulls++
+		f.images[r.URL.Query().Get("fromImage")] = true
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": "a3ed95caeb02", "status": "Pull complete"})
+	case r.Method == http.MethodPost && path == "/containers/create":
+		var spec struct {
+			Labels map[string]string
+		}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &spec)
+		f.nextID++
+		id := fmt.Sprintf("%064d", f.nextID)
+		f.specs[id] = body
+		f.labels[id] = spec.Labels[containerLabelServer]
+		_ = json.NewEncoder(w).Encode(map[string]string{"Id": id})
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "attach":
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("HTTP/1.1 101 UPGRADED\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n"))
+		f.containers[parts[1]] = &fakeContainer{conn: conn, rw: rw}
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "start":
+		ctr, ok := f.containers[parts[1]]
+		if !ok {
+			http.Error(w, `{"message":"not attached"}`, http.StatusConflict)
+			return
+		}
+		go ctr.serve()
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[2] == "json":
+		_ = json.NewEncoder(w).Encode(map[string]any{"State": map[string]int{"ExitCode": 137}})
+	case r.Method == http.MethodGet && path == "/containers/json":
+		var filters map[string][]string
+		_ = json.Unmarshal([]byte(r.URL.Query().Get("filters")), &filters)
+		var list []map[string]string
+		for id, label := range f.labels {
+			if filters["label"][0] == containerLabelServer+"="+label {
+				list = append(list, map[string]string{"Id": id})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case r.Method == http.MethodDelete && len(parts) == 2:
+		if _, ok := f.labels[parts[1]]; !ok {
+			http.Error(w, `{"message":"No such container"}`, http.StatusNotFound)
+			return
+		}
+		if ctr, ok := f.containers[parts[1]]; ok {
+			ctr.conn.Close()
+		}
+		delete(f.labels, parts[1])
+		delete(f.containers, parts[1])
+		f.removed = append(f.removed, parts[1])
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, `{"message":"page not found"}`, http.StatusNotFound)
+	}
+}
+
+// serve answers the JSON-RPC requests written to the stdin of the container until it is closed, then exits.
+func (c *fakeContainer) serve() {
+	defer c.conn.Close()
+	scanner := bufio.NewScanner(c.rw)
+	for scanner.Scan() {
+		var req struct {
+			ID     any    `json:"id"`
+			Method string `json:"method"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &req) != nil || req.ID == nil {
+			continue
+		}
+		var result any
+		switch req.Method {
+		case "initialize":
+			result = map[string]any{
+				"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+				"capabilities":    map[string]any{"tools": map[string]any{}},
+				"serverInfo":      map[string]string{"name": "time", "version": "1.0.0"},
+			}
+		case "tools/list":
+			result = map[string]any{"tools": []map[string]any{{"name": "now", "inputSchema": map[string]string{"type": "object"}}}}
+		default:
+			result = map[string]any{}
+		}
+		resp, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+		resp = append(resp, '\n')
+		header := make([]byte, 8)
+		header[0] = 1
+		binary.BigEndian.PutUint32(header[4:], uint32(len(resp)))
+		if _, err := c.conn.Write(append(header, resp...)); err != nil {
+			return
+		}
+	}
+}
+
+// crash makes every running container exit.
+func (f *fakeDocker) crash() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, ctr := range f.containers {
+		ctr.conn.Close()
+	}
+}
+
+func (f *fakeDocker) count() (running, removed int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.labels), len(f.removed)
+}
+
+func newTestContainerServer(t *testing.T, sessionMode types.SessionMode) *model.McpServer {
+	t.Helper()
+	s, err := model.NewContainerStdioServer(
+		"time", "",
+		&types.ContainerConfig{Image: "mcp/time", Volumes: []string{"/srv/tz:/tz:ro"}, Network: "none", MemoryMB: 256, CPUs: 0.5},
+		"", []string{"--local-timezone=UTC"}, map[string]string{"TZ": "UTC"}, sessionMode,
+	)
+	testhelpers.AssertNoError(t, err)
+	return s
+}
+
+func TestRunStdioServerInContainer(t *testing.T) {
+	f, d := newFakeDocker(t)
+	runtime := newContainerRuntime(d)
+	s := newTestContainerServer(t, types.SessionModeStateless)
+	testhelpers.AssertEqual(t, types.ContainerStateStopped, runtime.State("time"))
+
+	c, err := runStdioServer(context.Background(), s, 5, runtime, nil)
+	testhelpers.AssertNoError(t, err)
+	tools, err := c.ListTools(context.Background(), mcp.ListToolsRequest{})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "now", tools.Tools[0].Name)
+	testhelpers.AssertEqual(t, types.ContainerStateRunning, runtime.State("time"))
+	testhelpers.AssertEqual(t, 1, f.pulls)
+
+	var spec struct {
+		Image      string
+		Cmd, Env   []string
+		OpenStdin  bool
+		StdinOnce  bool
+		HostConfig struct {
+			Binds       []string
+			NetworkMode string
+			Memory      int64
+			NanoCpus    int64
+		}
+	}
+	f.mu.Lock()
+	for _, body := range f.specs {
+		testhelpers.AssertNoError(t, json.Unmarshal(body, &spec))
+	}
+	f.mu.Unlock()
+	testhelpers.AssertEqual(t, "mcp/time", spec.Image)
+	testhelpers.AssertEqual(t, "--local-timezone=UTC", strings.Join(spec.Cmd, " "))
+	testhelpers.AssertEqual(t, "TZ=UTC", strings.Join(spec.Env, " "))
+	testhelpers.AssertTrue(t, spec.OpenStdin && spec.StdinOnce, "expected the stdin of the container to stay open")
+	testhelpers.AssertEqual(t, "/srv/tz:/tz:ro", strings.Join(spec.HostConfig.Binds, " "))
+	testhelpers.AssertEqual(t, "none", spec.HostConfig.NetworkMode)
+	testhelpers.AssertEqual(t, int64(256<<20), spec.HostConfig.Memory)
+	testhelpers.AssertEqual(t, int64(5e8), spec.HostConfig.NanoCpus)
+
+	// closing the connection stops and removes the container
+	testhelpers.AssertNoError(t, c.Close())
+	running, removed := f.count()
+	testhelpers.AssertEqual(t, 0, running)
+	testhelpers.AssertEqual(t, 1, removed)
+	require.Eventually(t, func() bool { return runtime.State("time") == types.ContainerStateStopped }, 5*time.Second, 5*time.Millisecond)
+
+	// the image is only pulled once
+	c, err = runStdioServer(context.Background(), s, 5, runtime, nil)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, f.pulls)
+	_ = c.Close()
+}
+
+func TestSessionManagerRestartsExitedContainer(t *testing.T) {
+	f, d := newFakeDocker(t)
+	sm := NewSessionManager(&SessionManagerConfig{InitReqTimeoutSec: 5, Docker: d})
+	defer sm.Shutdown()
+	sm.restartBackoff = 10 * time.Millisecond
+	s := newTestContainerServer(t, types.SessionModeStateful)
+
+	_, err := sm.GetOrCreateSession(context.Background(), s)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, types.ContainerStateRunning, sm.containers.State("time"))
+
+	version := sm.StateVersion()
+	f.crash()
+	require.Eventually(t, func() bool {
+		running, removed := f.count()
+		return sm.HasSession("time") && running == 1 && removed == 1
+	}, 5*time.Second, 5*time.Millisecond)
+	testhelpers.AssertEqual(t, types.ContainerStateRunning, sm.containers.State("time"))
+	testhelpers.AssertTrue(t, sm.StateVersion() > version, "expected the restart to change the state version")
+	c, err := sm.GetOrCreateSession(context.Background(), s)
+	testhelpers.AssertNoError(t, err)
+	_, err = c.ListTools(context.Background(), mcp.ListToolsRequest{})
+	testhelpers.AssertNoError(t, err)
+
+	sm.CloseSession("time")
+	require.Eventually(t, func() bool { running, _ := f.count(); return running == 0 }, 5*time.Second, 5*time.Millisecond)
+	testhelpers.AssertEqual(t, types.ContainerStateStopped, sm.containers.State("time"))
+}
+
+func TestRemoveAllContainers(t *testing.T) {
+	f, d := newFakeDocker(t)
+	runtime := newContainerRuntime(d)
+	// a container left behind by a previous run of mcpjungle
+	f.labels["leftover"] = "time"
+	f.labels["other"] = "github"
+
+	runtime.removeAll(context.Background(), "time")
+	testhelpers.AssertEqual(t, "leftover", strings.Join(f.removed, " "))
+	testhelpers.AssertEqual(t, types.ContainerStateStopped, runtime.State("time"))
+}
+
+func TestRunStdioServerWithoutDocker(t *testing.T) {
+	d, err := docker.NewClient("unix://" + filepath.Join(t.TempDir(), "docker.sock"))
+	testhelpers.AssertNoError(t, err)
+	s := newTestContainerServer(t, types.SessionModeStateless)
+
+	_, err = runStdioServer(context.Background(), s, 5, newContainerRuntime(d), nil)
+	testhelpers.AssertTrue(t, errors.Is(err, ErrDockerUnavailable), "expected ErrDockerUnavailable")
+	testhelpers.AssertStringContains(t, err.Error(), "the Docker daemon is not reachable")
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ErrCredentialUnavailable is matched by the errors returned when a credential of an MCP server references a secret
+// that can't be resolved, eg- because Vault is unreachable or the secret doesn't exist.
+var ErrCredentialUnavailable = errors.New("credential unavailable")
+
+// SecretResolver resolves the references to secrets held by the credential fields of MCP servers.
+type SecretResolver interface {
+	Resolve(ctx context.Context, ref types.VaultReference) (string, error)
+}
+
+// credentialError is the failure to resolve a credential of an MCP server, it matches ErrCredentialUnavailable.
+type credentialError struct {
+	server string
+	err    error
+}
+
+func (e *credentialError) Error() string {
+	return fmt.Sprintf("credential unavailable for MCP server %s: %v", e.server, e.err)
+}
+
+func (e *credentialError) Unwrap() []error { return []error{e.err, ErrCredentialUnavailable} }
+
+// withResolvedCredentials returns the server with the secrets its credential fields reference in place of
+// the references. The server itself is returned if none of its credentials references a secret.
+// The resolved server is only used to connect to the MCP server and must never be saved,
+// so that the secrets are only ever held in memory.
+func withResolvedCredentials(
+	ctx context.Context, s *model.McpServer, secrets SecretResolver,
+) (*model.McpServer, error) {
+	resolved := false
+	resolve := func(v string) (string, error) {
+		if !types.IsVaultReference(v) {
+			return v, nil
+		}
+		ref, err := types.ParseVaultReference(v)
+		if err != nil {
+			return "", err
+		}
+		if secrets == nil {
+			return "", errors.New("mcpjungle has no Vault configured to resolve " + v + ", set VAULT_ADDR")
+		}
+		resolved = true
+		return secrets.Resolve(ctx, ref)
+	}
+
+	// a configuration that can't be read is reported by the connection to the server
+	var conf any
+	var err error
+	switch s.Transport {
+	case types.TransportStreamableHTTP:
+		c, cErr := s.GetStreamableHTTPConfig()
+		if cErr != nil {
+			return s, nil
+		}
+		c.BearerToken, err = resolve(c.BearerToken)
+		conf = c
+	case types.TransportSSE:
+		c, cErr := s.GetSSEConfig()
+		if cErr != nil {
+			return s, nil
+		}
+		c.BearerToken, err = resolve(c.BearerToken)
+		conf = c
+	case types.TransportStdio:
+		c, cErr := s.GetStdioConfig()
+		if cErr != nil {
+			return s, nil
+		}
+		for k, v := range c.Env {
+			if c.Env[k], err = resolve(v); err != nil {
+				break
+			}
+		}
+		conf = c
+	case types.TransportOpenAPI:
+		c, cErr := s.GetOpenAPIConfig()
+		if cErr != nil {
+			return s, nil
+		}
+		if c.Auth != nil {
+			c.Auth.Value, err = resolve(c.Auth.Value)
+		}
+		conf = c
+	case types.TransportWebhookTool:
+		c, cErr := s.GetWebhookToolConfig()
+		if cErr != nil {
+			return s, nil
+		}
+		if c.Auth != nil {
+			c.Auth.Value, err = resolve(c.Auth.Value)
+		}
+		conf = c
+	default:
+		return s, nil
+	}
+	if err != nil {
+		return nil, &credentialError{server: s.Name, err: err}
+	}
+	if !resolved {
+		return s, nil
+	}
+
+	data, err := json.Marshal(conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize the configuration of MCP server %s: %w", s.Name, err)
+	}
+	copied := *s
+	copied.Config = data
+	return &copied, nil
+}
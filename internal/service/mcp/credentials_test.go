@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// fakeSecrets resolves the references to the secrets it holds, by reference.
+type fakeSecrets map[string]string
+
+func (f fakeSecrets) Resolve(ctx context.Context, ref types.VaultReference) (string, error) {
+	v, ok := f[ref.String()]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return v, nil
+}
+
+func TestWithResolvedCredentials(t *testing.T) {
+	ctx := context.Background()
+	secrets := fakeSecrets{"vault:secret/mcp/github#token": "ghp_secret", "vault:secret/mcp/db#password": "hunter2"}
+
+	s, err := model.NewStreamableHTTPServer("github", "", "https://api.example.com/mcp", "vault:secret/mcp/github#token", "")
+	testhelpers.AssertNoError(t, err)
+	resolved, err := withResolvedCredentials(ctx, s, secrets)
+	testhelpers.AssertNoError(t, err)
+	conf, err := resolved.GetStreamableHTTPConfig()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "ghp_secret", conf.BearerToken)
+	// the registered server keeps the reference
+	conf, err = s.GetStreamableHTTPConfig()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "vault:secret/mcp/github#token", conf.BearerToken)
+
+	env := map[string]string{"DB_PASSWORD": "vault:secret/mcp/db#password", "DB_USER": "mcp"}
+	s, err = model.NewStdioServer("db", "", "db-mcp", nil, env, "")
+	testhelpers.AssertNoError(t, err)
+	resolved, err = withResolvedCredentials(ctx, s, secrets)
+	testhelpers.AssertNoError(t, err)
+	stdio, err := resolved.GetStdioConfig()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "hunter2", stdio.Env["DB_PASSWORD"])
+	testhelpers.AssertEqual(t, "mcp", stdio.Env["DB_USER"])
+
+	s, err = model.NewSSEServer("static", "", "https://example.com/sse", "static-token", "")
+	testhelpers.AssertNoError(t, err)
+	resolved, err = withResolvedCredentials(ctx, s, nil)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, resolved == s, "a server without references should be used as-is")
+}
+
+func TestWithResolvedCredentialsUnavailable(t *testing.T) {
+	ctx := context.Background()
+	s, err := model.NewStreamableHTTPServer("github", "", "https://api.example.com/mcp", "vault:secret/mcp/github#token", "")
+	testhelpers.AssertNoError(t, err)
+
+	_, err = withResolvedCredentials(ctx, s, fakeSecrets{})
+	testhelpers.AssertTrue(t, errors.Is(err, ErrCredentialUnavailable), "expected ErrCredentialUnavailable")
+	testhelpers.AssertStringContains(t, err.Error(), "credential unavailable for MCP server github: secret not found")
+
+	_, err = withResolvedCredentials(ctx, s, nil)
+	testhelpers.AssertTrue(t, errors.Is(err, ErrCredentialUnavailable), "expected ErrCredentialUnavailable")
+	testhelpers.AssertStringContains(t, err.Error(), "set VAULT_ADDR")
+}
@@ -0,0 +1,359 @@
+package mcp
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxConcurrentHealthChecks is how many MCP servers are checked at the same time.
+const maxConcurrentHealthChecks = 8
+
+// serverHealth is the outcome of the last health check of an MCP server.
+type serverHealth struct {
+	checkedAt           time.Time
+	latency             time.Duration
+	consecutiveFailures int
+	err                 string
+	// unhealthySince is when the first of the checks the server failed in a row started, if it failed the last one
+	unhealthySince time.Time
+}
+
+// ServerHealthChangeCallback is called when an MCP server fails a health check after passing the previous one,
+// or passes one after failing the previous ones.
+type ServerHealthChangeCallback func(change types.ServerHealthChange)
+
+// healthChecks keeps the outcome of the health checks of the registered MCP servers, keyed by server name.
+// They are also stored in the DB, so that the replicas of the registry sharing it report the same outcomes:
+// only the leader checks the servers in the background, the others load the outcomes it stored.
+type healthChecks struct {
+	mu      sync.RWMutex
+	servers map[string]*serverHealth
+
+	// round serializes the rounds of checks, so that a server is never checked twice at the same time
+	round sync.Mutex
+
+	// loop guards stop, which is closed to stop the background checks, nil if they are not running
+	loop sync.Mutex
+	stop chan struct{}
+
+	// onChange is called for every server whose health changed after a round of checks, it may be nil
+	onChange ServerHealthChangeCallback
+	// isLeader reports whether this replica runs the background checks, nil if it always does
+	isLeader func() bool
+}
+
+// SetHealthCheckInterval changes how often the health of the registered MCP servers is checked in the background,
+// 0 stops the background checks. The next round of checks runs right away.
+func (m *MCPService) SetHealthCheckInterval(interval time.Duration) {
+	m.stopHealthChecks()
+	if interval > 0 {
+		m.startHealthChecks(interval)
+	}
+}
+
+// startHealthChecks checks the health of all registered MCP servers in the background, once every interval.
+// The first round runs right away. While this replica is not the leader, it loads the outcomes of the checks
+// of the leader instead.
+func (m *MCPService) startHealthChecks(interval time.Duration) {
+	m.health.loop.Lock()
+	defer m.health.loop.Unlock()
+	stop := make(chan struct{})
+	m.health.stop = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if m.health.isLeader == nil || m.health.isLeader() {
+				if err := m.checkServersHealth(context.Background(), false); err != nil {
+					log.Printf("[WARN] failed to check the health of MCP servers: %v", err)
+				}
+			} else if err := m.loadServersHealth(); err != nil {
+				log.Printf("[WARN] failed to load the health of MCP servers checked by the leader: %v", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// stopHealthChecks stops the background health checks, if they are running.
+func (m *MCPService) stopHealthChecks() {
+	m.health.loop.Lock()
+	defer m.health.loop.Unlock()
+	if m.health.stop != nil {
+		close(m.health.stop)
+		m.health.stop = nil
+	}
+}
+
+// CheckServersHealth checks the health of all registered MCP servers now, and stores the outcome for ServersHealth.
+// Checking a server connects to it and pings it. Stdio servers in stateless mode are started for the check,
+// and so are the stopped servers that are started lazily.
+func (m *MCPService) CheckServersHealth(ctx context.Context) error {
+	return m.checkServersHealth(ctx, true)
+}
+
+// checkServersHealth does the work of CheckServersHealth.
+// Unless startLazy is true, the lazily started servers that are not running are not checked and keep the
+// outcome of their last check: the background checks must not start them, that's what lazy start avoids.
+func (m *MCPService) checkServersHealth(ctx context.Context, startLazy bool) error {
+	m.health.round.Lock()
+	defer m.health.round.Unlock()
+
+	servers, err := m.ListMcpServers()
+	if err != nil {
+		return err
+	}
+
+	results := make([]*serverHealth, len(servers))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentHealthChecks)
+	for i := range servers {
+		if !startLazy && servers[i].LazyStart && m.sessionManager.State(servers[i].Name) != types.ServerStateRunning {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			h := m.checkServerHealth(ctx, &servers[i])
+			results[i] = &h
+		}()
+	}
+	wg.Wait()
+
+	// the outcomes of the previous checks are those stored, which another replica may have made,
+	// so that a server that kept failing since then is not reported as a change again
+	stored, err := m.storedServersHealth()
+	if err != nil {
+		log.Printf("[WARN] failed to load the stored health of MCP servers: %v", err)
+	}
+
+	m.health.mu.Lock()
+	previous := m.health.servers
+	if err == nil {
+		previous = stored
+	}
+	m.health.servers = make(map[string]*serverHealth, len(servers))
+	var changes []types.ServerHealthChange
+	for i, s := range servers {
+		h := results[i]
+		p, checkedBefore := previous[s.Name]
+		if h == nil {
+			// not checked this round
+			if checkedBefore {
+				m.health.servers[s.Name] = p
+			}
+			continue
+		}
+		failedBefore := checkedBefore && p.err != ""
+		switch {
+		case h.err != "" && failedBefore:
+			h.consecutiveFailures = p.consecutiveFailures + 1
+			h.unhealthySince = p.unhealthySince
+		case h.err != "":
+			h.consecutiveFailures = 1
+			h.unhealthySince = h.checkedAt
+			changes = append(changes, types.ServerHealthChange{
+				Server: s.Name, Status: types.ServerUnhealthy, Error: h.err,
+				CheckedAt: h.checkedAt.UTC(), UnhealthySince: h.unhealthySince.UTC(),
+			})
+		case failedBefore:
+			changes = append(changes, types.ServerHealthChange{
+				Server: s.Name, Status: types.ServerHealthy, CheckedAt: h.checkedAt.UTC(), UnhealthySince: p.unhealthySince.UTC(),
+			})
+		}
+		m.health.servers[s.Name] = h
+	}
+	current := m.health.servers
+	m.health.mu.Unlock()
+
+	if err := m.storeServersHealth(current); err != nil {
+		log.Printf("[WARN] failed to store the health of MCP servers: %v", err)
+	}
+
+	if m.health.onChange != nil {
+		for _, c := range changes {
+			m.health.onChange(c)
+		}
+	}
+	return nil
+}
+
+// checkServerHealth connects to s and pings it, within the timeout of initialization requests.
+// The check doesn't count as a use of the persistent session of s, so it doesn't keep it from being idle.
+func (m *MCPService) checkServerHealth(ctx context.Context, s *model.McpServer) serverHealth {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(m.mcpServerInitReqTimeoutSec)*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	session, err := m.openSession(ctx, s, false)
+	if err == nil {
+		err = session.client.Ping(ctx)
+		session.invalidateOnError(err)
+		session.closeIfApplicable()
+	}
+	h := serverHealth{checkedAt: start, latency: time.Since(start)}
+	if err != nil {
+		h.err = err.Error()
+	}
+	return h
+}
+
+// storedServersHealth returns the outcomes of the last health checks stored in the DB, keyed by server name.
+func (m *MCPService) storedServersHealth() (map[string]*serverHealth, error) {
+	var records []model.ServerHealthCheck
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	servers := make(map[string]*serverHealth, len(records))
+	for _, r := range records {
+		h := &serverHealth{
+			checkedAt:           r.CheckedAt,
+			latency:             time.Duration(r.LatencyMs) * time.Millisecond,
+			consecutiveFailures: r.ConsecutiveFailures,
+			err:                 r.Error,
+		}
+		if r.UnhealthySince != nil {
+			h.unhealthySince = *r.UnhealthySince
+		}
+		servers[r.ServerName] = h
+	}
+	return servers, nil
+}
+
+// storeServersHealth replaces the outcomes stored in the DB with servers, the outcomes of the latest checks.
+func (m *MCPService) storeServersHealth(servers map[string]*serverHealth) error {
+	records := make([]model.ServerHealthCheck, 0, len(servers))
+	names := make([]string, 0, len(servers))
+	for name, h := range servers {
+		r := model.ServerHealthCheck{
+			ServerName:          name,
+			CheckedAt:           h.checkedAt,
+			LatencyMs:           h.latency.Milliseconds(),
+			ConsecutiveFailures: h.consecutiveFailures,
+			Error:               h.err,
+		}
+		if !h.unhealthySince.IsZero() {
+			since := h.unhealthySince
+			r.UnhealthySince = &since
+		}
+		records = append(records, r)
+		names = append(names, name)
+	}
+	return db.RetryOnBusy(func() error {
+		return m.db.Transaction(func(tx *gorm.DB) error {
+			// the outcomes of the servers deregistered since the previous round are dropped
+			q := tx.Session(&gorm.Session{AllowGlobalUpdate: true})
+			if len(names) > 0 {
+				q = q.Where("server_name NOT IN ?", names)
+			}
+			if err := q.Delete(&model.ServerHealthCheck{}).Error; err != nil {
+				return err
+			}
+			if len(records) == 0 {
+				return nil
+			}
+			return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&records).Error
+		})
+	})
+}
+
+// loadServersHealth replaces the outcomes kept in memory with those stored in the DB by the leader.
+func (m *MCPService) loadServersHealth() error {
+	stored, err := m.storedServersHealth()
+	if err != nil {
+		return err
+	}
+	m.health.mu.Lock()
+	defer m.health.mu.Unlock()
+	m.health.servers = stored
+	return nil
+}
+
+// forgetServerHealth drops the outcome of the last health check of an MCP server,
+// eg- because it was deregistered or its connection settings changed.
+func (m *MCPService) forgetServerHealth(name string) {
+	m.dropServerHealth(name)
+	if err := m.db.Where("server_name = ?", name).Delete(&model.ServerHealthCheck{}).Error; err != nil {
+		log.Printf("[WARN] failed to delete the stored health of MCP server %s: %v", name, err)
+	}
+}
+
+// dropServerHealth drops the outcome of the last health check of an MCP server from memory only,
+// eg- because another replica changed the server and already dropped the stored one.
+func (m *MCPService) dropServerHealth(name string) {
+	m.health.mu.Lock()
+	defer m.health.mu.Unlock()
+	delete(m.health.servers, name)
+}
+
+// ServersHealth returns the outcome of the last health check of every registered MCP server, sorted by name,
+// and their aggregate status. It doesn't check the servers, see CheckServersHealth.
+func (m *MCPService) ServersHealth() (*types.ServersHealth, error) {
+	servers, err := m.ListMcpServers()
+	if err != nil {
+		return nil, err
+	}
+
+	m.health.mu.RLock()
+	defer m.health.mu.RUnlock()
+	m.syncs.mu.RLock()
+	defer m.syncs.mu.RUnlock()
+	result := &types.ServersHealth{Servers: make([]types.ServerHealth, 0, len(servers))}
+	for _, s := range servers {
+		sh := types.ServerHealth{Name: s.Name, Status: types.ServerHealthUnknown}
+		if h, ok := m.health.servers[s.Name]; ok {
+			checkedAt := h.checkedAt.UTC()
+			sh.CheckedAt = &checkedAt
+			sh.LatencyMs = h.latency.Milliseconds()
+			sh.ConsecutiveFailures = h.consecutiveFailures
+			sh.Error = h.err
+			sh.Status = types.ServerHealthy
+			if h.err != "" {
+				sh.Status = types.ServerUnhealthy
+			}
+		}
+		m.serverSyncHealth(&sh)
+		result.Servers = append(result.Servers, sh)
+	}
+	sort.Slice(result.Servers, func(i, j int) bool { return result.Servers[i].Name < result.Servers[j].Name })
+	result.Status = aggregateHealth(result.Servers)
+	return result, nil
+}
+
+// aggregateHealth returns the status of a registry whose MCP servers are in the given health.
+// Servers that weren't checked yet don't count.
+func aggregateHealth(servers []types.ServerHealth) types.HealthStatus {
+	var checked, unhealthy int
+	for _, s := range servers {
+		switch s.Status {
+		case types.ServerHealthy:
+			checked++
+		case types.ServerUnhealthy:
+			checked++
+			unhealthy++
+		}
+	}
+	switch {
+	case unhealthy == 0:
+		return types.HealthHealthy
+	case unhealthy == checked:
+		return types.HealthCritical
+	default:
+		return types.HealthDegraded
+	}
+}
@@ -0,0 +1,234 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestServersHealth(t *testing.T) {
+	m := newBulkTestService(t)
+	upstream := server.NewTestStreamableHTTPServer(server.NewMCPServer("github", "0.0.0"))
+	defer upstream.Close()
+
+	github, err := model.NewStreamableHTTPServer("github", "", upstream.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	for _, s := range append([]*model.McpServer{github}, newUnreachableServers(t, "slack")...) {
+		testhelpers.AssertNoError(t, m.db.Create(s).Error)
+	}
+
+	// the servers are not checked until asked to
+	health, err := m.ServersHealth()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, types.HealthHealthy, health.Status)
+	testhelpers.AssertEqual(t, 2, len(health.Servers))
+	testhelpers.AssertEqual(t, types.ServerHealthUnknown, health.Servers[0].Status)
+
+	for range 2 {
+		testhelpers.AssertNoError(t, m.CheckServersHealth(context.Background()))
+	}
+	health, err = m.ServersHealth()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, types.HealthDegraded, health.Status)
+
+	healthy, slack := health.Servers[0], health.Servers[1]
+	testhelpers.AssertEqual(t, "github", healthy.Name)
+	testhelpers.AssertEqual(t, types.ServerHealthy, healthy.Status)
+	testhelpers.AssertEqual(t, 0, healthy.ConsecutiveFailures)
+	testhelpers.AssertNotNil(t, healthy.CheckedAt)
+	testhelpers.AssertEqual(t, "slack", slack.Name)
+	testhelpers.AssertEqual(t, types.ServerUnhealthy, slack.Status)
+	testhelpers.AssertEqual(t, 2, slack.ConsecutiveFailures)
+	testhelpers.AssertTrue(t, slack.Error != "", "the error of the failed check should be reported")
+
+	// the health of deregistered servers is forgotten
+	testhelpers.AssertNoError(t, m.DeregisterMcpServer("github"))
+	health, err = m.ServersHealth()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(health.Servers))
+	testhelpers.AssertEqual(t, types.HealthCritical, health.Status)
+}
+
+func TestServerHealthChanges(t *testing.T) {
+	m := newBulkTestService(t)
+	var changes []types.ServerHealthChange
+	m.health.onChange = func(c types.ServerHealthChange) { changes = append(changes, c) }
+	mcpHandler := server.NewStreamableHTTPServer(server.NewMCPServer("jira", "0.0.0"))
+	var down atomic.Bool
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		mcpHandler.ServeHTTP(w, r)
+	}))
+	defer upstream.Close()
+
+	jira, err := model.NewStreamableHTTPServer("jira", "", upstream.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, m.db.Create(jira).Error)
+
+	// a healthy server doesn't change
+	testhelpers.AssertNoError(t, m.CheckServersHealth(context.Background()))
+	testhelpers.AssertEqual(t, 0, len(changes))
+
+	// only the first failed check is reported
+	down.Store(true)
+	for range 2 {
+		testhelpers.AssertNoError(t, m.CheckServersHealth(context.Background()))
+	}
+	testhelpers.AssertEqual(t, 1, len(changes))
+	unhealthy := changes[0]
+	testhelpers.AssertEqual(t, "jira", unhealthy.Server)
+	testhelpers.AssertEqual(t, types.ServerUnhealthy, unhealthy.Status)
+	testhelpers.AssertTrue(t, unhealthy.Error != "", "the error of the failed check should be reported")
+	testhelpers.AssertEqual(t, unhealthy.CheckedAt, unhealthy.UnhealthySince)
+
+	down.Store(false)
+	testhelpers.AssertNoError(t, m.CheckServersHealth(context.Background()))
+	testhelpers.AssertEqual(t, 2, len(changes))
+	recovered := changes[1]
+	testhelpers.AssertEqual(t, types.ServerHealthy, recovered.Status)
+	testhelpers.AssertEqual(t, "", recovered.Error)
+	// the server was unhealthy since the first of the checks it failed
+	testhelpers.AssertEqual(t, unhealthy.UnhealthySince, recovered.UnhealthySince)
+	testhelpers.AssertTrue(t, recovered.CheckedAt.After(recovered.UnhealthySince), "expected the recovery to be later")
+}
+
+func TestSetHealthCheckInterval(t *testing.T) {
+	m := newBulkTestService(t)
+	defer m.Shutdown()
+	testhelpers.AssertNoError(t, m.db.Create(newUnreachableServers(t, "slack")[0]).Error)
+
+	// the first round of checks runs as soon as the background checks start
+	m.SetHealthCheckInterval(time.Hour)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		health, err := m.ServersHealth()
+		testhelpers.AssertNoError(t, err)
+		if health.Servers[0].Status == types.ServerUnhealthy {
+			break
+		}
+		testhelpers.AssertTrue(t, time.Now().Before(deadline), "expected the server to be checked in the background")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	m.SetHealthCheckInterval(0)
+	m.health.loop.Lock()
+	defer m.health.loop.Unlock()
+	testhelpers.AssertTrue(t, m.health.stop == nil, "expected the background checks to be stopped")
+}
+
+func TestServersHealthOfLazyServer(t *testing.T) {
+	m := newBulkTestService(t)
+	defer m.Shutdown()
+	upstream := server.NewTestStreamableHTTPServer(server.NewMCPServer("heavy", "0.0.0"))
+	defer upstream.Close()
+
+	heavy, err := model.NewStreamableHTTPServer("heavy", "", upstream.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	heavy.LazyStart = true
+	testhelpers.AssertNoError(t, m.db.Create(heavy).Error)
+
+	// the background checks don't start a stopped server
+	testhelpers.AssertNoError(t, m.checkServersHealth(context.Background(), false))
+	testhelpers.AssertEqual(t, types.ServerStateStopped, m.ServerState(heavy))
+	health, err := m.ServersHealth()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, types.ServerHealthUnknown, health.Servers[0].Status)
+
+	// an explicit check does, and the server keeps running afterwards
+	testhelpers.AssertNoError(t, m.CheckServersHealth(context.Background()))
+	testhelpers.AssertEqual(t, types.ServerStateRunning, m.ServerState(heavy))
+	health, err = m.ServersHealth()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, types.ServerHealthy, health.Servers[0].Status)
+
+	// once running, the background checks check it
+	testhelpers.AssertNoError(t, m.checkServersHealth(context.Background(), false))
+	health, err = m.ServersHealth()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, types.ServerHealthy, health.Servers[0].Status)
+}
+
+func TestAggregateHealth(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []types.ServerHealthStatus
+		expected types.HealthStatus
+	}{
+		{name: "no servers", expected: types.HealthHealthy},
+		{name: "all healthy", statuses: []types.ServerHealthStatus{types.ServerHealthy, types.ServerHealthy}, expected: types.HealthHealthy},
+		{name: "not checked yet", statuses: []types.ServerHealthStatus{types.ServerHealthUnknown}, expected: types.HealthHealthy},
+		{name: "some unhealthy", statuses: []types.ServerHealthStatus{types.ServerHealthy, types.ServerUnhealthy}, expected: types.HealthDegraded},
+		{
+			name:     "all checked unhealthy",
+			statuses: []types.ServerHealthStatus{types.ServerUnhealthy, types.ServerHealthUnknown},
+			expected: types.HealthCritical,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			servers := make([]types.ServerHealth, len(tt.statuses))
+			for i, s := range tt.statuses {
+				servers[i].Status = s
+			}
+			testhelpers.AssertEqual(t, tt.expected, aggregateHealth(servers))
+		})
+	}
+}
+
+func TestHealthChecksFailover(t *testing.T) {
+	replicas := newReplicaTestServices(t, 2, 20*time.Millisecond)
+	leader, follower := replicas[0], replicas[1]
+	testhelpers.AssertTrue(t, leader.coordinator.IsLeader(), "the first replica should lead")
+	testhelpers.AssertFalse(t, follower.coordinator.IsLeader(), "the second replica should follow")
+	testhelpers.AssertNoError(t, leader.db.Create(newUnreachableServers(t, "slack")[0]).Error)
+
+	// failures reads the consecutive failed checks of the server stored in the DB, once they reached at least min
+	failures := func(min int) int {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			var check model.ServerHealthCheck
+			err := leader.db.Where("server_name = ?", "slack").Limit(1).Find(&check).Error
+			testhelpers.AssertNoError(t, err)
+			if check.ConsecutiveFailures >= min {
+				return check.ConsecutiveFailures
+			}
+			testhelpers.AssertTrue(t, time.Now().Before(deadline), "expected the server to be checked in the background")
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// the follower reports the outcomes of the checks of the leader
+	failures(2)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		health, err := follower.ServersHealth()
+		testhelpers.AssertNoError(t, err)
+		if health.Servers[0].Status == types.ServerUnhealthy {
+			break
+		}
+		testhelpers.AssertTrue(t, time.Now().Before(deadline), "expected the follower to load the health checks")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// once the leader stopped, the follower takes the lease over and carries on with the checks
+	leader.stop()
+	checked := failures(0)
+	deadline = time.Now().Add(5 * time.Second)
+	for !follower.coordinator.IsLeader() {
+		testhelpers.AssertTrue(t, time.Now().Before(deadline), "expected the follower to take the lease over")
+		time.Sleep(10 * time.Millisecond)
+	}
+	testhelpers.AssertTrue(t, failures(checked+2) >= checked+2, "the new leader should keep checking the server")
+}
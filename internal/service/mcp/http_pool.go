@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+const (
+	// DefaultHTTPMaxIdleConnsPerHost is the default number of idle connections kept open to a streamable HTTP server.
+	// Go's default of 2 makes concurrent tool calls open and close a connection each.
+	DefaultHTTPMaxIdleConnsPerHost = 32
+
+	// DefaultHTTPIdleConnTimeoutSec is the default number of seconds an idle connection to a server is kept open.
+	DefaultHTTPIdleConnTimeoutSec = 90
+)
+
+// httpPools holds the connection pools of the streamable HTTP MCP servers, keyed by server name.
+// Every connection to a server, stateless or stateful, sends its requests through the server's pool,
+// so that they reuse the connections opened by the previous calls.
+type httpPools struct {
+	mu    sync.Mutex
+	pools map[string]*httpPool
+
+	metrics telemetry.CustomMetrics
+}
+
+// httpPool is the connection pool of a streamable HTTP server.
+type httpPool struct {
+	// config is the configuration the pool was created with, defaults applied
+	config    types.HTTPPoolConfig
+	transport *http.Transport
+}
+
+func newHTTPPools(metrics telemetry.CustomMetrics) *httpPools {
+	return &httpPools{
+		pools:   make(map[string]*httpPool),
+		metrics: metrics,
+	}
+}
+
+// client returns an HTTP client sending the requests to the server through its pool.
+// The pool is created on first use, and replaced if the server's pool configuration changed.
+// A nil httpPools returns nil, in which case the MCP client uses its own HTTP client.
+func (p *httpPools) client(serverName string, conf *types.HTTPPoolConfig) *http.Client {
+	if p == nil {
+		return nil
+	}
+	config := withHTTPPoolDefaults(conf)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pool, ok := p.pools[serverName]
+	if !ok || pool.config != config {
+		if ok {
+			pool.transport.CloseIdleConnections()
+		}
+		pool = &httpPool{config: config, transport: newPoolTransport(config)}
+		p.pools[serverName] = pool
+	}
+	return &http.Client{
+		Transport: &countingTransport{serverName: serverName, base: pool.transport, metrics: p.metrics},
+	}
+}
+
+// close closes the idle connections of the pool of a server and forgets it.
+// The requests in flight complete, their connections are closed once done.
+func (p *httpPools) close(serverName string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pool, ok := p.pools[serverName]; ok {
+		pool.transport.CloseIdleConnections()
+		delete(p.pools, serverName)
+	}
+}
+
+// closeAll closes the idle connections of all the pools.
+func (p *httpPools) closeAll() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for name, pool := range p.pools {
+		pool.transport.CloseIdleConnections()
+		delete(p.pools, name)
+	}
+}
+
+// withHTTPPoolDefaults returns conf with the defaults applied to the fields left to zero.
+func withHTTPPoolDefaults(conf *types.HTTPPoolConfig) types.HTTPPoolConfig {
+	var config types.HTTPPoolConfig
+	if conf != nil {
+		config = *conf
+	}
+	if config.MaxIdleConnsPerHost == 0 {
+		config.MaxIdleConnsPerHost = DefaultHTTPMaxIdleConnsPerHost
+	}
+	if config.IdleConnTimeoutSec == 0 {
+		config.IdleConnTimeoutSec = DefaultHTTPIdleConnTimeoutSec
+	}
+	return config
+}
+
+// newPoolTransport creates the transport of a pool.
+// It keeps the proxy and dial settings of http.DefaultTransport.
+func newPoolTransport(config types.HTTPPoolConfig) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = 0 // the pool only serves a single server, which is limited by MaxIdleConnsPerHost
+	t.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	t.IdleConnTimeout = time.Duration(config.IdleConnTimeoutSec) * time.Second
+	if config.DisableHTTP2 {
+		// a non-nil empty map disables the HTTP/2 upgrade negotiated over TLS
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	} else {
+		t.ForceAttemptHTTP2 = true
+	}
+	return t
+}
+
+// countingTransport records whether every request to a server opened a new connection or reused one.
+// It also counts the bytes of the responses read during tool calls, see resultSize.
+type countingTransport struct {
+	serverName string
+	base       http.RoundTripper
+	metrics    telemetry.CustomMetrics
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			conn := telemetry.UpstreamConnectionNew
+			if info.Reused {
+				conn = telemetry.UpstreamConnectionReused
+			}
+			t.metrics.RecordUpstreamConnection(ctx, t.serverName, conn)
+		},
+	}
+	resp, err := t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(ctx, trace)))
+	if size := resultSizeFrom(ctx); size != nil && resp != nil {
+		resp.Body = &countedBody{ReadCloser: resp.Body, size: size}
+	}
+	return resp, err
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// upstreamConnections records the connections used by the requests to upstream servers, by server name.
+type upstreamConnections struct {
+	telemetry.NoopCustomMetrics
+	mu    sync.Mutex
+	conns map[string][]telemetry.UpstreamConnection
+}
+
+func (m *upstreamConnections) RecordUpstreamConnection(ctx context.Context, serverName string, conn telemetry.UpstreamConnection) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.conns[serverName] = append(m.conns[serverName], conn)
+}
+
+func (m *upstreamConnections) count(serverName string, conn telemetry.UpstreamConnection) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, c := range m.conns[serverName] {
+		if c == conn {
+			n++
+		}
+	}
+	return n
+}
+
+func TestHTTPPoolsReuseConnections(t *testing.T) {
+	upstream := server.NewTestStreamableHTTPServer(server.NewMCPServer("github", "0.0.0"))
+	defer upstream.Close()
+	github, err := model.NewStreamableHTTPServer("github", "", upstream.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+
+	metrics := &upstreamConnections{conns: make(map[string][]telemetry.UpstreamConnection)}
+	pools := newHTTPPools(metrics)
+	defer pools.closeAll()
+
+	for range 3 {
+		c, err := newMcpServerSession(context.Background(), github, 5, pools, nil, nil, nil, nil)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertNoError(t, c.Ping(context.Background()))
+		testhelpers.AssertNoError(t, c.Close())
+	}
+	testhelpers.AssertTrue(t, metrics.count("github", telemetry.UpstreamConnectionNew) >= 1, "the first call should open a connection")
+	testhelpers.AssertTrue(
+		t,
+		metrics.count("github", telemetry.UpstreamConnectionReused) > 0,
+		"the subsequent calls should reuse the connections of the pool",
+	)
+
+	// the pool of a deregistered server is closed
+	pools.close("github")
+	_, ok := pools.pools["github"]
+	testhelpers.AssertFalse(t, ok, "the pool of the server should be forgotten")
+}
+
+func TestHTTPPoolsClient(t *testing.T) {
+	pools := newHTTPPools(telemetry.NewNoopCustomMetrics())
+	defer pools.closeAll()
+
+	pools.client("github", nil)
+	pool := pools.pools["github"]
+	testhelpers.AssertEqual(t, DefaultHTTPMaxIdleConnsPerHost, pool.transport.MaxIdleConnsPerHost)
+	testhelpers.AssertTrue(t, pool.transport.ForceAttemptHTTP2, "HTTP/2 should be attempted by default")
+
+	// the pool is shared until its configuration changes
+	pools.client("github", &types.HTTPPoolConfig{})
+	testhelpers.AssertTrue(t, pool == pools.pools["github"], "equivalent configurations should share the pool")
+
+	pools.client("github", &types.HTTPPoolConfig{MaxIdleConnsPerHost: 100, IdleConnTimeoutSec: 10, DisableHTTP2: true})
+	pool = pools.pools["github"]
+	testhelpers.AssertEqual(t, 100, pool.transport.MaxIdleConnsPerHost)
+	testhelpers.AssertEqual(t, 10.0, pool.transport.IdleConnTimeout.Seconds())
+	testhelpers.AssertFalse(t, pool.transport.ForceAttemptHTTP2, "HTTP/2 should be disabled")
+
+	var nilPools *httpPools
+	testhelpers.AssertTrue(t, nilPools.client("github", nil) == nil, "nil pools should leave the client to the MCP client")
+}
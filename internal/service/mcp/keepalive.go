@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// keepAlivePingTimeout is how long an MCP server has to answer a keep-alive ping.
+const keepAlivePingTimeout = 10 * time.Second
+
+// keepAliveConfig returns the keep-alive configuration of the session of a server, nil if it has none.
+// Only the stateful streamable HTTP servers support it.
+func keepAliveConfig(s *model.McpServer) *types.KeepAliveConfig {
+	if s.Transport != types.TransportStreamableHTTP || s.SessionMode != types.SessionModeStateful {
+		return nil
+	}
+	conf, err := s.GetStreamableHTTPConfig()
+	if err != nil || conf.KeepAlive == nil || conf.KeepAlive.IntervalSec <= 0 {
+		return nil
+	}
+	return conf.KeepAlive
+}
+
+// keepAlive pings the session every keep-alive interval while it is idle, until it is closed.
+func (sm *SessionManager) keepAlive(session *ManagedSession) {
+	ticker := time.NewTicker(time.Duration(session.keepAlive.IntervalSec) * time.Second)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		if !sm.keepAliveTick(session, now) {
+			return
+		}
+	}
+}
+
+// keepAliveTick pings the session if it was not used for an interval, and closes it once it has been idle
+// for its maximum idle lifetime. A session the server fails to answer is evicted, the next call starts a new one.
+// It returns false once the session is closed, whether by the keep-alive or not.
+func (sm *SessionManager) keepAliveTick(session *ManagedSession, now time.Time) bool {
+	name := session.ServerName
+	interval := time.Duration(session.keepAlive.IntervalSec) * time.Second
+	maxIdle := time.Duration(session.keepAlive.MaxIdleSec) * time.Second
+
+	sm.mu.Lock()
+	if sm.sessions[name] != session {
+		sm.mu.Unlock()
+		return false
+	}
+	idle := now.Sub(session.LastUsedAt)
+	if session.inUse > 0 || idle < interval {
+		// the calls keep the session open
+		sm.mu.Unlock()
+		return true
+	}
+	if maxIdle > 0 && idle >= maxIdle {
+		delete(sm.sessions, name)
+		sm.stopping[name] = &pendingTransition{done: make(chan struct{})}
+		sm.stateVersion.Add(1)
+		sm.mu.Unlock()
+
+		log.Printf("[SessionManager] Closing session for server '%s' kept alive for %v", name, idle.Round(time.Second))
+		sm.stopSessions(map[string]*ManagedSession{name: session})
+		sm.metrics.RecordKeepAliveEviction(context.Background(), name, telemetry.KeepAliveEvictionMaxIdle)
+		return false
+	}
+	// the session is held during the ping, so that it isn't closed meanwhile
+	session.inUse++
+	sm.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), keepAlivePingTimeout)
+	err := session.Client.Ping(ctx)
+	cancel()
+	sm.releaseSession(session, false)
+
+	if err == nil {
+		sm.metrics.RecordKeepAlivePing(context.Background(), name, telemetry.KeepAlivePingSuccess)
+		return true
+	}
+	sm.metrics.RecordKeepAlivePing(context.Background(), name, telemetry.KeepAlivePingError)
+	if sm.evictSession(session) {
+		log.Printf("[SessionManager] Evicted session for server '%s' after a failed keep-alive ping: %v", name, err)
+		sm.metrics.RecordKeepAliveEviction(context.Background(), name, telemetry.KeepAliveEvictionPingFailed)
+	}
+	return false
+}
+
+// evictSession removes the session, unless it was closed already, and closes it.
+// Unlike InvalidateSession, a session started since for the same server is kept.
+func (sm *SessionManager) evictSession(session *ManagedSession) bool {
+	sm.mu.Lock()
+	if sm.sessions[session.ServerName] != session {
+		sm.mu.Unlock()
+		return false
+	}
+	delete(sm.sessions, session.ServerName)
+	sm.stateVersion.Add(1)
+	sm.mu.Unlock()
+
+	// the server didn't answer, failing to close the session is expected
+	_ = session.Client.Close()
+	return true
+}
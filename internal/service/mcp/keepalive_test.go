@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// keepAlives counts the keep-alive pings and evictions, by outcome and reason.
+type keepAlives struct {
+	telemetry.NoopCustomMetrics
+	mu        sync.Mutex
+	pings     map[telemetry.KeepAlivePingOutcome]int
+	evictions map[telemetry.KeepAliveEvictionReason]int
+}
+
+func (m *keepAlives) RecordKeepAlivePing(ctx context.Context, serverName string, outcome telemetry.KeepAlivePingOutcome) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pings[outcome]++
+}
+
+func (m *keepAlives) RecordKeepAliveEviction(
+	ctx context.Context, serverName string, reason telemetry.KeepAliveEvictionReason,
+) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.evictions[reason]++
+}
+
+func TestKeepAliveConfig(t *testing.T) {
+	conf := &types.KeepAliveConfig{IntervalSec: 30}
+	stateful, err := model.NewStreamableHTTPServer("github", "", "https://example.com/mcp", "", types.SessionModeStateful)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, keepAliveConfig(stateful) == nil, "keep-alive should be off by default")
+	testhelpers.AssertNoError(t, stateful.SetKeepAliveConfig(conf))
+	testhelpers.AssertEqual(t, 30, keepAliveConfig(stateful).IntervalSec)
+
+	stateless, err := model.NewStreamableHTTPServer("github", "", "https://example.com/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, stateless.SetKeepAliveConfig(conf))
+	testhelpers.AssertTrue(t, keepAliveConfig(stateless) == nil, "stateless servers have no session to keep alive")
+}
+
+func TestSessionManagerKeepAlive(t *testing.T) {
+	var down atomic.Bool
+	handler := server.NewStreamableHTTPServer(server.NewMCPServer("github", "0.0.0"))
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer upstream.Close()
+
+	github, err := model.NewStreamableHTTPServer("github", "", upstream.URL+"/mcp", "", types.SessionModeStateful)
+	testhelpers.AssertNoError(t, err)
+	// the intervals are long, the test runs the ticks of the keep-alive itself
+	testhelpers.AssertNoError(t, github.SetKeepAliveConfig(&types.KeepAliveConfig{IntervalSec: 3600, MaxIdleSec: 7200}))
+
+	metrics := &keepAlives{
+		pings:     make(map[telemetry.KeepAlivePingOutcome]int),
+		evictions: make(map[telemetry.KeepAliveEvictionReason]int),
+	}
+	sm := NewSessionManager(&SessionManagerConfig{IdleTimeoutSec: 1, InitReqTimeoutSec: 5, Metrics: metrics})
+	defer sm.Shutdown()
+
+	session, err := sm.acquireSession(context.Background(), github, true)
+	testhelpers.AssertNoError(t, err)
+	sm.releaseSession(session, true)
+	used := session.LastUsedAt
+
+	// a session used recently is not pinged
+	testhelpers.AssertTrue(t, sm.keepAliveTick(session, used.Add(time.Minute)), "the session should be kept")
+	testhelpers.AssertEqual(t, 0, metrics.pings[telemetry.KeepAlivePingSuccess])
+
+	// an idle one is, and it outlives the session idle timeout
+	testhelpers.AssertTrue(t, sm.keepAliveTick(session, used.Add(time.Hour)), "the session should be kept")
+	testhelpers.AssertEqual(t, 1, metrics.pings[telemetry.KeepAlivePingSuccess])
+	session.LastUsedAt = used.Add(-time.Hour)
+	sm.cleanupIdleSessions()
+	testhelpers.AssertTrue(t, sm.HasSession("github"), "the keep-alive should replace the session idle timeout")
+	session.LastUsedAt = used
+
+	// until its maximum idle lifetime, then it is closed
+	testhelpers.AssertFalse(t, sm.keepAliveTick(session, used.Add(2*time.Hour)), "the session should be closed")
+	testhelpers.AssertEqual(t, types.ServerStateStopped, sm.State("github"))
+	testhelpers.AssertEqual(t, 1, metrics.evictions[telemetry.KeepAliveEvictionMaxIdle])
+
+	// a session the server dropped is evicted, and the next call starts a new one
+	session, err = sm.acquireSession(context.Background(), github, true)
+	testhelpers.AssertNoError(t, err)
+	sm.releaseSession(session, true)
+	down.Store(true)
+	testhelpers.AssertFalse(t, sm.keepAliveTick(session, session.LastUsedAt.Add(time.Hour)), "the session should be evicted")
+	testhelpers.AssertFalse(t, sm.HasSession("github"), "the session should be evicted")
+	testhelpers.AssertEqual(t, 1, metrics.pings[telemetry.KeepAlivePingError])
+	testhelpers.AssertEqual(t, 1, metrics.evictions[telemetry.KeepAliveEvictionPingFailed])
+
+	down.Store(false)
+	client, err := sm.GetOrCreateSession(context.Background(), github)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, client.Ping(context.Background()))
+
+	// a closed session is not kept alive anymore
+	sm.CloseSession("github")
+	testhelpers.AssertFalse(t, sm.keepAliveTick(session, time.Now()), "a closed session should not be pinged")
+}
@@ -4,10 +4,14 @@ package mcp
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"gorm.io/gorm"
 )
 
@@ -25,6 +29,38 @@ type ServiceConfig struct {
 	// SessionManager manages persistent connections for MCP servers configured in stateful mode.
 	// If nil, a default SessionManager will be created.
 	SessionManager *SessionManager
+
+	// HealthCheckInterval is how often the health of the registered MCP servers is checked in the background.
+	// If 0, they are only checked on demand, with CheckServersHealth.
+	HealthCheckInterval time.Duration
+	// OnServerHealthChange is called when an MCP server fails a health check after passing the previous one,
+	// or recovers, eg- to notify the webhooks. It may be nil.
+	OnServerHealthChange ServerHealthChangeCallback
+	// IsLeader reports whether this replica of the registry is the leader among those sharing the DB.
+	// Only the leader checks the health of the servers in the background, the others report the outcomes it stored.
+	// If nil, this is the only replica.
+	IsLeader func() bool
+
+	// DisableToolsListCache disables the caching of the tools/list results of the MCP proxy servers,
+	// so that they are built again for every request.
+	DisableToolsListCache bool
+
+	// SyncConcurrency is how many MCP servers SyncServers synchronizes at the same time.
+	// If 0, DefaultSyncConcurrency is used.
+	SyncConcurrency int
+
+	// MaxToolResultBytes is the maximum size of the response of an MCP server to a tool call, larger results fail
+	// with ErrToolResultTooLarge. It also limits the other messages of the stdio and SSE servers, whose responses
+	// share a stream. If 0, the results are not limited.
+	MaxToolResultBytes int64
+
+	// MaxConcurrentUpstreamCalls is how many tool and prompt calls are proxied to the MCP servers at the same time,
+	// across all servers. If 0, DefaultMaxConcurrentUpstreamCalls is used.
+	MaxConcurrentUpstreamCalls int
+	// MaxQueuedUpstreamCalls is how many calls wait for the calls in flight to complete, once the concurrency limit
+	// is reached. The calls over it fail with ErrUpstreamCallsSaturated.
+	// If 0, DefaultMaxQueuedUpstreamCalls is used, if negative the calls fail as soon as the limit is reached.
+	MaxQueuedUpstreamCalls int
 }
 
 // MCPService coordinates operations amongst the registry database, mcp proxy server and upstream MCP servers.
@@ -52,6 +88,28 @@ type MCPService struct {
 
 	// sessionManager manages persistent connections for MCP servers configured in stateful mode.
 	sessionManager *SessionManager
+
+	health healthChecks
+
+	// toolsListCache caches the tools/list results of the MCP proxy servers, nil if caching is disabled.
+	toolsListCache *ToolsListCache
+
+	// syncConcurrency is how many MCP servers are synchronized at the same time
+	syncConcurrency int
+	// syncRound serializes the synchronizations, so that a server is never synchronized twice at the same time
+	syncRound sync.Mutex
+	syncs     syncStates
+
+	// upstreamCalls bounds the calls proxied to the MCP servers at the same time
+	upstreamCalls *upstreamLimiter
+
+	// inFlightToolCalls is the number of tool calls being proxied, so that shutdown can wait for them.
+	inFlightToolCalls atomic.Int64
+
+	// refreshMu serializes the refreshes of the MCP proxy servers from the DB, see RefreshFromDB
+	refreshMu sync.Mutex
+	// published is the state of the DB the MCP proxy servers were last refreshed from
+	published *proxySnapshot
 }
 
 // NewMCPService creates a new instance of MCPService.
@@ -63,6 +121,7 @@ func NewMCPService(c *ServiceConfig) (*MCPService, error) {
 		sessionManager = NewSessionManager(&SessionManagerConfig{
 			IdleTimeoutSec:    DefaultSessionIdleTimeoutSec,
 			InitReqTimeoutSec: c.McpServerInitReqTimeout,
+			Metrics:           c.Metrics,
 		})
 	}
 
@@ -84,15 +143,82 @@ func NewMCPService(c *ServiceConfig) (*MCPService, error) {
 		mcpServerInitReqTimeoutSec: c.McpServerInitReqTimeout,
 
 		sessionManager: sessionManager,
+
+		syncConcurrency: c.SyncConcurrency,
+
+		upstreamCalls: newUpstreamLimiter(c.MaxConcurrentUpstreamCalls, c.MaxQueuedUpstreamCalls, c.Metrics),
+	}
+	sessionManager.maxResultBytes.Store(c.MaxToolResultBytes)
+	s.health.onChange = c.OnServerHealthChange
+	s.health.isLeader = c.IsLeader
+	if s.syncConcurrency <= 0 {
+		s.syncConcurrency = DefaultSyncConcurrency
+	}
+	if !c.DisableToolsListCache {
+		s.toolsListCache = NewToolsListCache(c.Metrics)
 	}
 	if err := s.initMCPProxyServer(); err != nil {
 		return nil, fmt.Errorf("failed to initialize MCP proxy server: %w", err)
 	}
+	if c.HealthCheckInterval > 0 {
+		s.startHealthChecks(c.HealthCheckInterval)
+	}
 	return s, nil
 }
 
-// Shutdown gracefully shuts down the MCP service, closing all stateful sessions.
+// SetMaxToolResultBytes changes the maximum size of the response of an MCP server to a tool call,
+// 0 doesn't limit it. The calls in progress to streamable HTTP servers keep the limit they started with,
+// while the messages of the stdio and SSE servers are limited from the next one on.
+func (m *MCPService) SetMaxToolResultBytes(n int64) {
+	m.sessionManager.maxResultBytes.Store(n)
+}
+
+// InFlightToolCalls returns the number of tool calls being proxied to MCP servers.
+func (m *MCPService) InFlightToolCalls() int64 {
+	return m.inFlightToolCalls.Load()
+}
+
+// ServerState returns the lifecycle state of the persistent session of an MCP server,
+// or "" if it has none because every call opens its own connection.
+func (m *MCPService) ServerState(s *model.McpServer) types.ServerState {
+	if !s.HasPersistentSession() {
+		return ""
+	}
+	return m.sessionManager.State(s.Name)
+}
+
+// ContainerState returns the state of the containers of a stdio server launched in a container,
+// or "" if the server is not launched in a container.
+func (m *MCPService) ContainerState(s *model.McpServer) types.ContainerState {
+	if s.Transport != types.TransportStdio {
+		return ""
+	}
+	conf, err := s.GetStdioConfig()
+	if err != nil || conf.Container == nil {
+		return ""
+	}
+	return m.sessionManager.containers.State(s.Name)
+}
+
+// ServerStatesVersion is incremented every time the lifecycle state of an MCP server changes.
+func (m *MCPService) ServerStatesVersion() uint64 {
+	return m.sessionManager.StateVersion()
+}
+
+// ToolsListCache returns the cache of the tools/list results of the MCP proxy servers, nil if caching is disabled.
+// The tool group proxy servers share it with the main one.
+func (m *MCPService) ToolsListCache() *ToolsListCache {
+	return m.toolsListCache
+}
+
+// Metrics returns the recorder of the custom metrics of mcpjungle, shared with the services built on top of this one.
+func (m *MCPService) Metrics() telemetry.CustomMetrics {
+	return m.metrics
+}
+
+// Shutdown gracefully shuts down the MCP service, stopping the health checks and closing all stateful sessions.
 func (m *MCPService) Shutdown() {
+	m.stopHealthChecks()
 	if m.sessionManager != nil {
 		m.sessionManager.Shutdown()
 	}
@@ -0,0 +1,259 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+const (
+	// messageIDBytes is how many bytes of the start and of the end of a message too large to be relayed are kept,
+	// to find the ID of the request it answers. The SDKs write the ID either before or after the result.
+	messageIDBytes = 256
+
+	// messageTooLargeCode is the code of the JSON-RPC error replacing a message too large to be relayed.
+	// It is in the range of the errors defined by the implementation, which the MCP client returns with their message.
+	messageTooLargeCode = -32000
+)
+
+// messageLimiter bounds the size of the messages an MCP server sends on a stream, ie- the lines of the stdout of a
+// stdio server and of the event stream of an SSE server. The MCP client reads a line as a whole before decoding it,
+// so a line larger than the limit is cut once it reaches it instead of being held in memory, the rest of it is
+// skipped as it is read, and it is replaced by a JSON-RPC error response to the request it answers.
+// The stream is not broken, so the other calls of a stateful session go on.
+type messageLimiter struct {
+	r io.Reader
+	// limit returns the maximum size of a line, 0 for no limit. It is read at the start of every line,
+	// so that a change applies to the sessions already open.
+	limit func() int64
+	// sse is true for the event stream of an SSE server, whose messages are the data lines of the events
+	sse bool
+	// serverName is the name of the MCP server, for the logs
+	serverName string
+
+	scratch []byte
+	// buf holds the bytes read from r that are not returned yet, err the error r returned with them
+	buf []byte
+	err error
+	// pending is returned before the rest of buf, ie- the end of a line cut or its replacement
+	pending []byte
+
+	// lineLen is the number of bytes of the current line returned, lineLimit the limit read at its start
+	lineLen   int64
+	lineLimit int64
+	// skipping is true while the rest of a line larger than the limit is read and dropped
+	skipping bool
+	// head and tail are the first and last messageIDBytes of the line being skipped
+	head []byte
+	tail []byte
+}
+
+// newMessageLimiter returns r with its lines limited to the size returned by limit, see messageLimiter.
+// A nil limit doesn't limit them.
+func newMessageLimiter(r io.Reader, limit func() int64, sse bool, serverName string) io.Reader {
+	if limit == nil {
+		return r
+	}
+	return &messageLimiter{r: r, limit: limit, sse: sse, serverName: serverName, scratch: make([]byte, 32<<10)}
+}
+
+func (l *messageLimiter) Read(p []byte) (int, error) {
+	for {
+		if len(l.pending) > 0 {
+			n := copy(p, l.pending)
+			l.pending = l.pending[n:]
+			return n, nil
+		}
+		if len(l.buf) == 0 {
+			if l.err != nil {
+				return 0, l.err
+			}
+			n, err := l.r.Read(l.scratch)
+			l.buf, l.err = l.scratch[:n], err
+			continue
+		}
+
+		if l.skipping {
+			i := bytes.IndexByte(l.buf, '\n')
+			if i < 0 {
+				l.keep(l.buf)
+				l.buf = nil
+				continue
+			}
+			l.keep(l.buf[:i])
+			l.buf = l.buf[i+1:]
+			l.pending = l.replacement()
+			l.skipping, l.lineLen, l.head, l.tail = false, 0, l.head[:0], l.tail[:0]
+			continue
+		}
+
+		if l.lineLen == 0 {
+			l.lineLimit = l.limit()
+		}
+		chunk := l.buf
+		end := bytes.IndexByte(chunk, '\n')
+		if end >= 0 {
+			chunk = chunk[:end+1]
+		}
+		content := int64(len(chunk))
+		if end >= 0 {
+			content--
+		}
+		if l.lineLimit > 0 && l.lineLen+content > l.lineLimit {
+			// the line ends where the limit is reached, the MCP client drops it as it isn't valid JSON
+			allowed := l.lineLimit - l.lineLen
+			l.keep(chunk)
+			l.pending = append(append(l.pending[:0], chunk[:allowed]...), '\n')
+			l.buf = l.buf[len(chunk):]
+			if end >= 0 {
+				// the whole line was read already
+				l.pending = append(l.pending, l.replacement()...)
+				l.lineLen, l.head, l.tail = 0, l.head[:0], l.tail[:0]
+			} else {
+				l.skipping = true
+			}
+			continue
+		}
+
+		n := copy(p, chunk)
+		if len(l.head) < messageIDBytes {
+			l.head = append(l.head, chunk[:min(n, messageIDBytes-len(l.head))]...)
+		}
+		l.buf = l.buf[n:]
+		if end >= 0 && n == len(chunk) {
+			l.lineLen, l.head = 0, l.head[:0]
+		} else {
+			l.lineLen += int64(n)
+		}
+		return n, nil
+	}
+}
+
+// keep records the bytes of a line too large to be relayed in its head and tail.
+func (l *messageLimiter) keep(b []byte) {
+	if len(l.head) < messageIDBytes {
+		l.head = append(l.head, b[:min(len(b), messageIDBytes-len(l.head))]...)
+	}
+	if len(b) >= messageIDBytes {
+		l.tail = append(l.tail[:0], b[len(b)-messageIDBytes:]...)
+		return
+	}
+	l.tail = append(l.tail, b...)
+	if len(l.tail) > messageIDBytes {
+		l.tail = append(l.tail[:0], l.tail[len(l.tail)-messageIDBytes:]...)
+	}
+}
+
+// replacement returns the line replacing the line too large to be relayed, a JSON-RPC error response to the request
+// it answers. It is empty if the line isn't a response or its ID can't be found: the line is then dropped.
+func (l *messageLimiter) replacement() []byte {
+	head := l.head
+	if l.sse {
+		data, ok := bytes.CutPrefix(head, []byte("data:"))
+		if !ok {
+			return nil
+		}
+		head = bytes.TrimLeft(data, " ")
+	}
+
+	id, ok := messageID(head, l.tail)
+	if !ok {
+		log.Printf(
+			"[WARN] dropped a message of MCP server %s larger than %d bytes, it answers no request it can be matched with",
+			l.serverName, l.lineLimit,
+		)
+		if l.sse {
+			// clears the data of the event cut, so that it isn't handled
+			return []byte("data:\n")
+		}
+		return nil
+	}
+	msg, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error": map[string]any{
+			"code": messageTooLargeCode,
+			"message": fmt.Sprintf(
+				"%s: the response of MCP server %s is larger than %d bytes", ErrToolResultTooLarge, l.serverName, l.lineLimit,
+			),
+		},
+	})
+	if l.sse {
+		msg = append([]byte("data: "), msg...)
+	}
+	return append(msg, '\n')
+}
+
+// messageID returns the ID of the JSON-RPC response whose first and last bytes are head and tail.
+// The ID is looked up among the members of the response read before its result, and at its end.
+func messageID(head, tail []byte) (json.RawMessage, bool) {
+	dec := json.NewDecoder(bytes.NewReader(head))
+	if tok, err := dec.Token(); err == nil && tok == json.Delim('{') {
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				break
+			}
+			var value json.RawMessage
+			if err := dec.Decode(&value); err != nil {
+				// the value is cut, eg- the result
+				break
+			}
+			if key == "id" {
+				return value, isRequestID(value)
+			}
+		}
+	}
+
+	for end := len(tail); end > 0; {
+		i := bytes.LastIndex(tail[:end], []byte(`"id":`))
+		if i < 0 {
+			break
+		}
+		end = i
+		dec := json.NewDecoder(bytes.NewReader(tail[i+len(`"id":`):]))
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil || !isRequestID(value) {
+			continue
+		}
+		// the ID is a member of the response if only the end of the response follows it
+		rest := strings.Join(strings.Fields(string(tail[i+len(`"id":`)+int(dec.InputOffset()):])), "")
+		if rest == "}" || rest == `,"jsonrpc":"2.0"}` {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// isRequestID reports whether the JSON value is a valid ID of a JSON-RPC request, ie- a number or a string.
+func isRequestID(value json.RawMessage) bool {
+	return len(value) > 0 && (value[0] == '"' || value[0] == '-' || value[0] >= '0' && value[0] <= '9')
+}
+
+// messageLimitTransport limits the messages of the event streams of an SSE server, see messageLimiter.
+type messageLimitTransport struct {
+	base       http.RoundTripper
+	limit      func() int64
+	serverName string
+}
+
+func (t *messageLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil && strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		resp.Body = &limitedBody{
+			Reader: newMessageLimiter(resp.Body, t.limit, true, t.serverName),
+			Closer: resp.Body,
+		}
+	}
+	return resp, err
+}
+
+// limitedBody is the body of a response whose messages are limited by a messageLimiter.
+type limitedBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func TestMessageLimiter(t *testing.T) {
+	limit := func() int64 { return 64 }
+	large := strings.Repeat("x", 1000)
+	tooLarge := func(id string) string {
+		return `{"error":{"code":-32000,"message":"tool result is too large: the response of MCP server github is ` +
+			`larger than 64 bytes"},"id":` + id + `,"jsonrpc":"2.0"}` + "\n"
+	}
+
+	// cut returns the first 64 bytes of a line, which end it once it is cut
+	cut := func(line string) string { return line[:64] + "\n" }
+	response := func(id string) string { return `{"jsonrpc":"2.0","id":` + id + `,"result":{"text":"` + large + `"}}` }
+
+	tests := []struct {
+		name  string
+		sse   bool
+		input string
+		want  string
+	}{
+		{
+			name:  "small messages",
+			input: `{"jsonrpc":"2.0","id":1,"result":{}}` + "\n" + `{"jsonrpc":"2.0","id":2,"result":{}}` + "\n",
+			want:  `{"jsonrpc":"2.0","id":1,"result":{}}` + "\n" + `{"jsonrpc":"2.0","id":2,"result":{}}` + "\n",
+		},
+		{
+			name:  "ID before the result",
+			input: response("7") + "\n" + `{"jsonrpc":"2.0","id":8,"result":{}}` + "\n",
+			want:  cut(response("7")) + tooLarge("7") + `{"jsonrpc":"2.0","id":8,"result":{}}` + "\n",
+		},
+		{
+			name:  "ID after the result",
+			input: `{"result":{"text":"` + large + `","id":3},"jsonrpc":"2.0","id":"call-7"}` + "\n",
+			want:  cut(`{"result":{"text":"`+large) + tooLarge(`"call-7"`),
+		},
+		{
+			name:  "notification",
+			input: `{"jsonrpc":"2.0","method":"notifications/message","params":{"data":"` + large + `"}}` + "\n",
+			want:  cut(`{"jsonrpc":"2.0","method":"notifications/message","params":{"data":"` + large),
+		},
+		{
+			name:  "SSE event",
+			sse:   true,
+			input: "event: message\ndata: " + response("5") + "\n\n",
+			want:  "event: message\n" + cut("data: "+response("5")) + "data: " + tooLarge("5") + "\n",
+		},
+		{
+			name:  "SSE event without ID",
+			sse:   true,
+			input: "event: message\ndata: {\"result\":{\"text\":\"" + large + "\"}}\n\n",
+			want:  "event: message\n" + cut("data: {\"result\":{\"text\":\""+large) + "data:\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// small reads, so that the lines span several of them
+			r := newMessageLimiter(&smallReader{r: strings.NewReader(tt.input), n: 100}, limit, tt.sse, "github")
+			got, err := io.ReadAll(r)
+			testhelpers.AssertNoError(t, err)
+			testhelpers.AssertEqual(t, tt.want, string(got))
+		})
+	}
+
+	// no limit
+	input := `{"jsonrpc":"2.0","id":1,"result":{"text":"` + large + `"}}` + "\n"
+	got, err := io.ReadAll(newMessageLimiter(strings.NewReader(input), func() int64 { return 0 }, false, "github"))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, input, string(got))
+}
+
+func TestMessageID(t *testing.T) {
+	tests := []struct {
+		name       string
+		head, tail string
+		want       string
+	}{
+		{name: "first member", head: `{"jsonrpc":"2.0","id":12,"result":{"content":[{"te`, want: "12"},
+		{name: "string ID", head: `{"id":"a-1","jsonrpc":"2.0","result":{"con`, want: `"a-1"`},
+		{name: "last member", head: `{"result":{"content":[{"te`, tail: `xt"}]},"jsonrpc":"2.0","id":4}`, want: "4"},
+		{name: "before jsonrpc", head: `{"result":{"con`, tail: `xt"}]},"id":-2, "jsonrpc": "2.0"}` + "\r\n", want: "-2"},
+		{name: "ID of the result", head: `{"result":{"id":9,"con`, tail: `xt"}]}}`},
+		{name: "nested ID at the end", head: `{"result":{"con`, tail: `tent":{"id":9}}}`},
+		{name: "null ID", head: `{"jsonrpc":"2.0","id":null,"error":{"co`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := messageID([]byte(tt.head), []byte(tt.tail))
+			testhelpers.AssertEqual(t, tt.want != "", ok)
+			if ok {
+				testhelpers.AssertEqual(t, tt.want, string(id))
+			}
+		})
+	}
+}
+
+// smallReader reads at most n bytes at a time from r.
+type smallReader struct {
+	r io.Reader
+	n int
+}
+
+func (s *smallReader) Read(p []byte) (int, error) {
+	return s.r.Read(p[:min(len(p), s.n)])
+}
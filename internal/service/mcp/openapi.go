@@ -0,0 +1,216 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// openAPIMaxResponseBytes caps the size of the responses of a REST service returned to the caller of a tool.
+const openAPIMaxResponseBytes = 10 << 20
+
+// openAPIBodyArgument is the tool argument holding the request body of an operation.
+const openAPIBodyArgument = "body"
+
+// createOpenAPIMcpServerConn serves the operations of the REST service of an openapi server as tools of an
+// in-process MCP server, and returns a client connected to it.
+// Each tool call is translated into an HTTP request to the REST service.
+func createOpenAPIMcpServerConn(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+	conf, err := s.GetOpenAPIConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get openapi config for MCP server %s: %w", s.Name, err)
+	}
+
+	// the adapter declares prompts so that listing them returns none instead of failing
+	srv := server.NewMCPServer(
+		s.Name, "0.1.0", server.WithToolCapabilities(false), server.WithPromptCapabilities(false),
+	)
+	for i := range conf.Operations {
+		op := &conf.Operations[i]
+		schema := op.InputSchema
+		if len(schema) == 0 {
+			schema = json.RawMessage(`{"type":"object"}`)
+		}
+		srv.AddTool(mcp.NewToolWithRawSchema(op.Tool, op.Description, schema), openAPIToolHandler(conf, op))
+	}
+
+	return connectInProcessAdapter(ctx, srv, "openapi")
+}
+
+// connectInProcessAdapter returns a client connected to the in-process MCP server of an adapter built into
+// mcpjungle, eg- the openapi adapter.
+func connectInProcessAdapter(ctx context.Context, srv *server.MCPServer, adapter string) (*client.Client, error) {
+	c, err := client.NewInProcessClient(srv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s adapter for MCP server: %w", adapter, err)
+	}
+	if err = c.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to start %s adapter for MCP server: %w", adapter, err)
+	}
+
+	initReq := mcp.InitializeRequest{
+		Params: mcp.InitializeParams{
+			ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+			Capabilities:    mcp.ClientCapabilities{},
+			ClientInfo: mcp.Implementation{
+				Name: "mcpjungle-" + strings.ReplaceAll(adapter, " ", "-") + "-adapter", Version: "0.1.0",
+			},
+		},
+	}
+	if _, err = c.Initialize(ctx, initReq); err != nil {
+		return nil, fmt.Errorf("client failed to initialize connection with %s adapter: %w", adapter, err)
+	}
+	return c, nil
+}
+
+// openAPIToolHandler calls an operation of a REST service with the arguments of a tool call.
+// Failures of the REST service are returned as error results, so that the caller can see them.
+func openAPIToolHandler(conf *types.OpenAPIConfig, op *types.OpenAPIOperation) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		req, err := newOpenAPIRequest(ctx, conf, op, request.GetArguments())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return mcp.NewToolResultErrorf("request to %s %s failed: %v", op.Method, op.Path, err), nil
+		}
+		defer resp.Body.Close()
+		return openAPIToolResult(op, resp)
+	}
+}
+
+// newOpenAPIRequest builds the HTTP request of an operation from the arguments of a tool call.
+func newOpenAPIRequest(
+	ctx context.Context, conf *types.OpenAPIConfig, op *types.OpenAPIOperation, args map[string]any,
+) (*http.Request, error) {
+	path := op.Path
+	query := url.Values{}
+	header := http.Header{}
+	for _, p := range op.Parameters {
+		v, ok := args[p.Name]
+		if !ok || v == nil {
+			if p.Required {
+				return nil, fmt.Errorf("missing required argument %q", p.Name)
+			}
+			continue
+		}
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(openAPIParamValue(v)))
+		case "query":
+			if values, ok := v.([]any); ok {
+				for _, item := range values {
+					query.Add(p.Name, openAPIParamValue(item))
+				}
+			} else {
+				query.Add(p.Name, openAPIParamValue(v))
+			}
+		case "header":
+			header.Set(p.Name, openAPIParamValue(v))
+		}
+	}
+
+	u := strings.TrimSuffix(conf.BaseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if b, ok := args[openAPIBodyArgument]; ok && op.BodyContentType != "" {
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request body: %w", err)
+		}
+		body = bytes.NewReader(data)
+		header.Set("Content-Type", op.BodyContentType)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, op.Method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s %s: %w", op.Method, op.Path, err)
+	}
+	req.Header = header
+	req.Header.Set("Accept", "application/json, text/*;q=0.9")
+	setOpenAPIAuth(req, conf.Auth)
+	return req, nil
+}
+
+// setOpenAPIAuth sets the credential of a REST service or of the endpoint of a webhook tool on a request.
+func setOpenAPIAuth(req *http.Request, a *types.OpenAPIAuth) {
+	if a == nil {
+		return
+	}
+	switch a.Type {
+	case types.OpenAPIAuthBearer:
+		req.Header.Set("Authorization", "Bearer "+a.Value)
+	case types.OpenAPIAuthAPIKey:
+		req.Header.Set(a.Header, a.Value)
+	}
+}
+
+// openAPIParamValue formats the argument of a parameter the way it is sent in a URL or a header.
+func openAPIParamValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// openAPIToolResult converts the response of a REST service into the result of a tool call.
+// JSON objects are also returned as structured content, any other response is returned as text.
+func openAPIToolResult(op *types.OpenAPIOperation, resp *http.Response) (*mcp.CallToolResult, error) {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, openAPIMaxResponseBytes+1))
+	if err != nil {
+		return mcp.NewToolResultErrorf("failed to read the response of %s %s: %v", op.Method, op.Path, err), nil
+	}
+	if len(data) > openAPIMaxResponseBytes {
+		return mcp.NewToolResultErrorf(
+			"the response of %s %s is larger than %d bytes", op.Method, op.Path, openAPIMaxResponseBytes,
+		), nil
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return mcp.NewToolResultErrorf("%s %s returned %s: %s", op.Method, op.Path, resp.Status, data), nil
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return mcp.NewToolResultText(resp.Status), nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if isJSONMediaType(mediaType) {
+		var obj map[string]any
+		if err := json.Unmarshal(data, &obj); err == nil {
+			return mcp.NewToolResultStructured(obj, string(data)), nil
+		}
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// isJSONMediaType reports whether a media type is JSON, eg- application/json or application/problem+json.
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestOpenAPIServer(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		switch r.URL.Path {
+		case "/v1/pets/rex%2F1", "/v1/pets/rex/1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"rex/1","name":"Rex"}`))
+		case "/v1/pets":
+			if r.Method == http.MethodPost {
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("Rex, Felix"))
+		default:
+			http.Error(w, "no such pet", http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(&model.McpServer{}, &model.Tool{}, &model.Prompt{}))
+	m, err := NewMCPService(&ServiceConfig{
+		DB:                      db,
+		McpProxyServer:          server.NewMCPServer("proxy", "0.0.0"),
+		SseMcpProxyServer:       server.NewMCPServer("sse-proxy", "0.0.0"),
+		Metrics:                 telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout: 5,
+	})
+	testhelpers.AssertNoError(t, err)
+	defer m.Shutdown()
+
+	petstore, err := model.NewOpenAPIServer("petstore", "", &types.OpenAPIConfig{
+		BaseURL: api.URL + "/v1/",
+		Auth:    &types.OpenAPIAuth{Type: types.OpenAPIAuthAPIKey, Header: "X-API-Key", Value: "secret"},
+		Operations: []types.OpenAPIOperation{
+			{
+				Tool: "getPet", Method: "GET", Path: "/pets/{id}",
+				Parameters: []types.OpenAPIParameter{{Name: "id", In: "path", Required: true}},
+				InputSchema: json.RawMessage(
+					`{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`,
+				),
+			},
+			{
+				Tool: "listPets", Method: "GET", Path: "/pets",
+				Parameters: []types.OpenAPIParameter{
+					{Name: "tag", In: "query"}, {Name: "limit", In: "query"}, {Name: "X-Request-Id", In: "header"},
+				},
+			},
+			{Tool: "createPet", Method: "POST", Path: "/pets", BodyContentType: "application/json"},
+		},
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, m.RegisterMcpServer(context.Background(), petstore))
+
+	tools, err := m.ListToolsByServer("petstore")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 3, len(tools))
+
+	// JSON objects are returned as structured content
+	res, err := m.InvokeTool(context.Background(), "petstore__getPet", map[string]any{"id": "rex/1"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertFalse(t, res.IsError, "the call should succeed")
+	testhelpers.AssertEqual(t, "/v1/pets/rex%2F1", got.URL.EscapedPath())
+	testhelpers.AssertEqual(t, "secret", got.Header.Get("X-API-Key"))
+	testhelpers.AssertEqual(t, "Rex", res.StructuredContent.(map[string]any)["name"])
+
+	res, err = m.InvokeTool(context.Background(), "petstore__listPets", map[string]any{
+		"tag": []any{"dog", "cat"}, "limit": float64(10), "X-Request-Id": "42",
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "limit=10&tag=dog&tag=cat", got.URL.RawQuery)
+	testhelpers.AssertEqual(t, "42", got.Header.Get("X-Request-Id"))
+	testhelpers.AssertEqual(t, "Rex, Felix", res.Content[0]["text"])
+	testhelpers.AssertTrue(t, res.StructuredContent == nil, "text responses have no structured content")
+
+	res, err = m.InvokeTool(context.Background(), "petstore__createPet", map[string]any{"body": map[string]any{"name": "Felix"}})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, `{"name":"Felix"}`, gotBody)
+	testhelpers.AssertEqual(t, "application/json", got.Header.Get("Content-Type"))
+	testhelpers.AssertEqual(t, "201 Created", res.Content[0]["text"])
+
+	// failures of the service and missing arguments are returned as error results
+	res, err = m.InvokeTool(context.Background(), "petstore__getPet", map[string]any{"id": "felix"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, res.IsError, "a 404 response should be an error result")
+	testhelpers.AssertStringContains(t, res.Content[0]["text"].(string), "no such pet")
+
+	res, err = m.InvokeTool(context.Background(), "petstore__getPet", nil)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, res.IsError, "a missing path parameter should be an error result")
+	testhelpers.AssertStringContains(t, res.Content[0]["text"].(string), `missing required argument "id"`)
+}
@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+const (
+	defaultNpmRegistryURL = "https://registry.npmjs.org"
+	defaultPyPIURL        = "https://pypi.org"
+
+	// packageLookupTimeout bounds the lookups of the latest version of a package.
+	packageLookupTimeout = 5 * time.Second
+	// packageDriftCheckInterval is how often the latest version of the package of a server is looked up again
+	// when the server is started, so that stateless servers don't look it up on every call.
+	packageDriftCheckInterval = time.Hour
+)
+
+// packageRegistry looks up the versions of the packages stdio servers are launched from, in the npm registry
+// or PyPI depending on their runtime.
+type packageRegistry struct {
+	http    *http.Client
+	npmURL  string
+	pypiURL string
+
+	mu sync.Mutex
+	// checks holds the last lookup of the latest version of the package of every server, by server name
+	checks map[string]*packageCheck
+}
+
+type packageCheck struct {
+	at time.Time
+	// latest is the latest version found by the lookup, empty if it failed
+	latest string
+}
+
+func newPackageRegistry() *packageRegistry {
+	return &packageRegistry{
+		http:    &http.Client{Timeout: packageLookupTimeout},
+		npmURL:  defaultNpmRegistryURL,
+		pypiURL: defaultPyPIURL,
+		checks:  make(map[string]*packageCheck),
+	}
+}
+
+// latestVersion looks up the version of the latest release of the package.
+func (r *packageRegistry) latestVersion(ctx context.Context, pkg *types.PackageConfig) (string, error) {
+	var u string
+	if pkg.Runtime == types.PackageRuntimeNpx {
+		// the slash of scoped packages is escaped, eg- @scope%2Fname
+		u = r.npmURL + "/" + url.PathEscape(pkg.Name) + "/latest"
+	} else {
+		u = r.pypiURL + "/pypi/" + url.PathEscape(pkg.Name) + "/json"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s returned status %d", u, resp.StatusCode)
+	}
+
+	var release struct {
+		// the npm registry returns the manifest of the release, PyPI the metadata of the project
+		Version string `json:"version"`
+		Info    struct {
+			Version string `json:"version"`
+		} `json:"info"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("invalid response of %s: %w", u, err)
+	}
+	version := release.Version
+	if pkg.Runtime == types.PackageRuntimeUvx {
+		version = release.Info.Version
+	}
+	if version == "" {
+		return "", fmt.Errorf("%s returned no version", u)
+	}
+	return version, nil
+}
+
+// prepare checks that the runtime of a stdio server launched from a package is installed, so that its registration
+// fails with a clear error otherwise, and records the version of the latest release of its package.
+// A pinned server is pinned to that version.
+func (r *packageRegistry) prepare(ctx context.Context, s *model.McpServer) error {
+	if s.Transport != types.TransportStdio {
+		return nil
+	}
+	conf, err := s.GetStdioConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get stdio config for MCP server %s: %w", s.Name, err)
+	}
+	if conf.Package == nil {
+		return nil
+	}
+	pkg := *conf.Package
+	if _, err := exec.LookPath(string(pkg.Runtime)); err != nil {
+		var errs types.ValidationErrors
+		errs.Add(
+			"package.runtime", "%s is not installed on the mcpjungle server, it is required to launch %s",
+			pkg.Runtime, pkg.Name,
+		)
+		return errs
+	}
+	if !pkg.RunsLatest() {
+		return nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, packageLookupTimeout)
+	defer cancel()
+	latest, err := r.latestVersion(lookupCtx, &pkg)
+	if err != nil {
+		if pkg.Pin {
+			return fmt.Errorf(
+				"failed to look up the latest version of package %s to pin MCP server %s to it: %w", pkg.Name, s.Name, err,
+			)
+		}
+		// eg- a private registry, the server still runs the latest release, its changes are not logged
+		log.Printf("[WARN] failed to look up the latest version of package %s of MCP server %s: %v", pkg.Name, s.Name, err)
+		return nil
+	}
+	if pkg.Pin {
+		pkg.Version = latest
+		log.Printf("[server] MCP server %s is pinned to version %s of package %s", s.Name, latest, pkg.Name)
+	}
+	r.mu.Lock()
+	r.checks[s.Name] = &packageCheck{at: time.Now(), latest: latest}
+	r.mu.Unlock()
+	return s.SetPackage(&pkg, latest)
+}
+
+// keepPackageVersion carries the version recorded for the package of a stdio server over to its new configuration
+// if its package didn't change, since the configurations sent by clients don't hold it.
+func keepPackageVersion(existing, s *model.McpServer) error {
+	if existing.Transport != types.TransportStdio || s.Transport != types.TransportStdio {
+		return nil
+	}
+	old, err := existing.GetStdioConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get stdio config for MCP server %s: %w", existing.Name, err)
+	}
+	conf, err := s.GetStdioConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get stdio config for MCP server %s: %w", s.Name, err)
+	}
+	if old.Package == nil || conf.Package == nil || *old.Package != *conf.Package || conf.PackageVersion != "" {
+		return nil
+	}
+	return s.SetPackage(conf.Package, old.PackageVersion)
+}
+
+// checkDrift logs, in the background, when the latest release of the package of a stdio server that runs the latest
+// release is not the version it was registered with anymore. It is called every time the server is started.
+func (r *packageRegistry) checkDrift(s *model.McpServer) {
+	if r == nil || s.Transport != types.TransportStdio {
+		return
+	}
+	conf, err := s.GetStdioConfig()
+	if err != nil || conf.Package == nil || !conf.Package.RunsLatest() || conf.PackageVersion == "" {
+		return
+	}
+	go func() {
+		if latest, drifted := r.lookupDrift(s.Name, conf); drifted {
+			log.Printf(
+				"[WARN] MCP server %s runs the latest release of package %s, which is now version %s instead of "+
+					"version %s when the server was registered, register it again with a pinned version to keep it",
+				s.Name, conf.Package.Name, latest, conf.PackageVersion,
+			)
+		}
+	}()
+}
+
+// lookupDrift looks up the latest version of the package of a server, unless it was looked up less than
+// packageDriftCheckInterval ago. It reports whether the version differs from the one the server was registered with,
+// only once per new version.
+func (r *packageRegistry) lookupDrift(serverName string, conf *model.StdioConfig) (string, bool) {
+	r.mu.Lock()
+	previous := r.checks[serverName]
+	if previous != nil && time.Since(previous.at) < packageDriftCheckInterval {
+		r.mu.Unlock()
+		return "", false
+	}
+	// concurrent starts don't look it up again meanwhile
+	check := &packageCheck{at: time.Now()}
+	if previous != nil {
+		check.latest = previous.latest
+	}
+	r.checks[serverName] = check
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), packageLookupTimeout)
+	defer cancel()
+	latest, err := r.latestVersion(ctx, conf.Package)
+	if err != nil {
+		log.Printf(
+			"[WARN] failed to look up the latest version of package %s of MCP server %s: %v",
+			conf.Package.Name, serverName, err,
+		)
+		return "", false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	logged := check.latest == latest
+	check.latest = latest
+	return latest, latest != conf.PackageVersion && !logged
+}
+
+// forget drops the lookups of the package of a server, eg- once it is deregistered.
+func (r *packageRegistry) forget(serverName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.checks, serverName)
+}
@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// newTestPackageRegistry serves the latest version of @modelcontextprotocol/server-filesystem from a fake npm registry
+// and of mcp-server-time from a fake PyPI, and counts the lookups.
+func newTestPackageRegistry(t *testing.T, latest *atomic.Value) (*packageRegistry, *atomic.Int32) {
+	t.Helper()
+	lookups := &atomic.Int32{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups.Add(1)
+		version := latest.Load().(string)
+		switch r.URL.EscapedPath() {
+		case "/npm/@modelcontextprotocol%2Fserver-filesystem/latest":
+			_ = json.NewEncoder(w).Encode(map[string]string{"name": "@modelcontextprotocol/server-filesystem", "version": version})
+		case "/pypi/mcp-server-time/json":
+			_ = json.NewEncoder(w).Encode(map[string]any{"info": map[string]string{"version": version}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	r := newPackageRegistry()
+	r.npmURL, r.pypiURL = srv.URL+"/npm", srv.URL
+	return r, lookups
+}
+
+// withRuntimes makes the given runtimes the only executables on the PATH.
+func withRuntimes(t *testing.T, runtimes ...string) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range runtimes {
+		testhelpers.AssertNoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0o755))
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestLatestPackageVersion(t *testing.T) {
+	latest := &atomic.Value{}
+	latest.Store("2025.8.21")
+	r, _ := newTestPackageRegistry(t, latest)
+	ctx := context.Background()
+
+	v, err := r.latestVersion(ctx, &types.PackageConfig{Runtime: types.PackageRuntimeNpx, Name: "@modelcontextprotocol/server-filesystem"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "2025.8.21", v)
+	v, err = r.latestVersion(ctx, &types.PackageConfig{Runtime: types.PackageRuntimeUvx, Name: "mcp-server-time"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "2025.8.21", v)
+
+	_, err = r.latestVersion(ctx, &types.PackageConfig{Runtime: types.PackageRuntimeUvx, Name: "unknown"})
+	testhelpers.AssertStringContains(t, err.Error(), "returned status 404")
+}
+
+func TestPreparePackage(t *testing.T) {
+	latest := &atomic.Value{}
+	latest.Store("2025.8.21")
+	r, _ := newTestPackageRegistry(t, latest)
+	ctx := context.Background()
+	withRuntimes(t, "npx")
+
+	pkg := &types.PackageConfig{Runtime: types.PackageRuntimeNpx, Name: "@modelcontextprotocol/server-filesystem"}
+	s, err := model.NewPackageStdioServer("filesystem", "", pkg, []string{"/tmp"}, nil, "")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, r.prepare(ctx, s))
+	conf, err := s.GetStdioConfig()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "2025.8.21", conf.PackageVersion)
+	testhelpers.AssertEqual(t, "", conf.Package.Version)
+
+	// a pinned server runs the version the latest release is when it is registered
+	pinned := &types.PackageConfig{Runtime: types.PackageRuntimeNpx, Name: pkg.Name, Pin: true}
+	s, err = model.NewPackageStdioServer("filesystem", "", pinned, nil, nil, "")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, r.prepare(ctx, s))
+	conf, err = s.GetStdioConfig()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "2025.8.21", conf.Package.Version)
+	_, args := conf.CommandLine()
+	testhelpers.AssertEqual(t, "@modelcontextprotocol/server-filesystem@2025.8.21", args[1])
+
+	// the runtime must be installed on the mcpjungle server
+	s, err = model.NewPackageStdioServer(
+		"time", "", &types.PackageConfig{Runtime: types.PackageRuntimeUvx, Name: "mcp-server-time"}, nil, nil, "",
+	)
+	testhelpers.AssertNoError(t, err)
+	err = r.prepare(ctx, s)
+	var fieldErrs types.ValidationErrors
+	testhelpers.AssertTrue(t, errors.As(err, &fieldErrs), "expected a validation error")
+	testhelpers.AssertEqual(t, "package.runtime", fieldErrs[0].Field)
+	testhelpers.AssertStringContains(t, err.Error(), "uvx is not installed on the mcpjungle server")
+}
+
+func TestKeepPackageVersion(t *testing.T) {
+	pkg := &types.PackageConfig{Runtime: types.PackageRuntimeNpx, Name: "@modelcontextprotocol/server-filesystem"}
+	existing, err := model.NewPackageStdioServer("filesystem", "", pkg, []string{"/tmp"}, nil, "")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, existing.SetPackage(pkg, "2025.8.21"))
+
+	// the configuration sent back by a client, eg- to change the description, doesn't hold the version
+	s, err := model.NewPackageStdioServer("filesystem", "files", pkg, []string{"/tmp"}, nil, "")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, keepPackageVersion(existing, s))
+	changed, err := connectionChanged(existing, s)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, !changed, "expected the connection to be unchanged")
+
+	other := &types.PackageConfig{Runtime: types.PackageRuntimeNpx, Name: pkg.Name, Version: "2025.7.1"}
+	s, err = model.NewPackageStdioServer("filesystem", "", other, []string{"/tmp"}, nil, "")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, keepPackageVersion(existing, s))
+	conf, err := s.GetStdioConfig()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "", conf.PackageVersion)
+}
+
+func TestLookupPackageDrift(t *testing.T) {
+	latest := &atomic.Value{}
+	latest.Store("2025.8.21")
+	r, lookups := newTestPackageRegistry(t, latest)
+	conf := &model.StdioConfig{
+		Package:        &types.PackageConfig{Runtime: types.PackageRuntimeUvx, Name: "mcp-server-time"},
+		PackageVersion: "2025.8.21",
+	}
+
+	_, drifted := r.lookupDrift("time", conf)
+	testhelpers.AssertTrue(t, !drifted, "expected no drift")
+
+	// the latest version is looked up at most once per interval
+	latest.Store("2025.9.25")
+	_, drifted = r.lookupDrift("time", conf)
+	testhelpers.AssertTrue(t, !drifted, "expected no lookup within the interval")
+	testhelpers.AssertEqual(t, int32(1), lookups.Load())
+
+	r.checks["time"].at = time.Now().Add(-2 * packageDriftCheckInterval)
+	version, drifted := r.lookupDrift("time", conf)
+	testhelpers.AssertTrue(t, drifted, "expected the new release to be reported")
+	testhelpers.AssertEqual(t, "2025.9.25", version)
+
+	// the same release is only reported once
+	r.checks["time"].at = time.Now().Add(-2 * packageDriftCheckInterval)
+	_, drifted = r.lookupDrift("time", conf)
+	testhelpers.AssertTrue(t, !drifted, "expected the release to be reported once")
+	testhelpers.AssertEqual(t, int32(3), lookups.Load())
+}
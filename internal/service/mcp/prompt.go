@@ -5,11 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/db"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
 )
 
 // ListPrompts returns all prompts registered in the registry.
@@ -19,12 +23,20 @@ func (m *MCPService) ListPrompts() ([]model.Prompt, error) {
 		return nil, err
 	}
 	// prepend server name to prompt names to ensure we only return the unique names of prompts to user
+	serverIDs := make([]uint, len(prompts))
+	for i, p := range prompts {
+		serverIDs[i] = p.ServerID
+	}
+	serverNames, err := m.serverNamesByID(serverIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers of prompts: %w", err)
+	}
 	for i := range prompts {
-		var s model.McpServer
-		if err := m.db.First(&s, "id = ?", prompts[i].ServerID).Error; err != nil {
-			return nil, fmt.Errorf("failed to get server for prompt %s: %w", prompts[i].Name, err)
+		serverName, ok := serverNames[prompts[i].ServerID]
+		if !ok {
+			return nil, fmt.Errorf("failed to get server for prompt %s: %w", prompts[i].Name, gorm.ErrRecordNotFound)
 		}
-		prompts[i].Name = mergeServerPromptNames(s.Name, prompts[i].Name)
+		prompts[i].Name = mergeServerPromptNames(serverName, prompts[i].Name)
 	}
 	return prompts, nil
 }
@@ -90,6 +102,12 @@ func (m *MCPService) GetPromptWithArgs(ctx context.Context, name string, args ma
 		)
 	}
 
+	release, err := m.upstreamCalls.acquire(ctx, serverName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	session, err := m.getSession(ctx, serverModel)
 	if err != nil {
 		return nil, err
@@ -113,7 +131,11 @@ func (m *MCPService) GetPromptWithArgs(ctx context.Context, name string, args ma
 	}
 	getPromptReq.Params.Arguments = stringArgs
 
+	callStarted := time.Now()
 	getPromptResp, err := session.client.GetPrompt(ctx, getPromptReq)
+	m.metrics.RecordUpstreamOperation(
+		ctx, serverName, telemetry.MCPMethodPromptsGet, promptName, err, time.Since(callStarted),
+	)
 	if err != nil {
 		session.invalidateOnError(err) // Invalidate unhealthy stateful sessions
 		return nil, fmt.Errorf("failed to get prompt %s from MCP server %s: %w", promptName, serverName, err)
@@ -221,20 +243,30 @@ func (m *MCPService) setPromptsEnabled(entity string, enabled bool) ([]string, e
 		return nil, fmt.Errorf("failed to get MCP server %s: %w", serverName, err)
 	}
 
+	// only the prompts that need a change are fetched, and they are all updated with a single query
 	var prompts []model.Prompt
-	if err := m.db.Where("server_id = ?", s.ID).Find(&prompts).Error; err != nil {
-		return nil, fmt.Errorf("failed to get prompts for server %s: %w", entity, err)
+	err = db.RetryOnBusy(func() error {
+		return m.db.Transaction(func(tx *gorm.DB) error {
+			changing := tx.Model(&model.Prompt{}).Where("server_id = ? AND enabled = ?", s.ID, !enabled)
+			if err := changing.Session(&gorm.Session{}).Find(&prompts).Error; err != nil {
+				return fmt.Errorf("failed to get prompts for server %s: %w", entity, err)
+			}
+			if len(prompts) == 0 {
+				return nil
+			}
+			if err := changing.Update("enabled", enabled).Error; err != nil {
+				return fmt.Errorf("failed to set prompts of server %s enabled=%t: %w", entity, enabled, err)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	var changedPromptNames []string
 	for i := range prompts {
-		if prompts[i].Enabled == enabled {
-			continue // no change needed
-		}
 		prompts[i].Enabled = enabled
-		if err := m.db.Save(&prompts[i]).Error; err != nil {
-			return nil, fmt.Errorf("failed to set prompt %s enabled=%t: %w", prompts[i].Name, enabled, err)
-		}
 		canonicalPromptName := mergeServerPromptNames(s.Name, prompts[i].Name)
 
 		if enabled {
@@ -271,36 +303,59 @@ func (m *MCPService) registerServerPrompts(ctx context.Context, s *model.McpServ
 	if err != nil {
 		return fmt.Errorf("failed to fetch prompts from MCP server %s: %w", s.Name, err)
 	}
-	for _, prompt := range resp.Prompts {
-		canonicalPromptName := mergeServerPromptNames(s.Name, prompt.GetName())
-
-		// extracting json schema is currently on best-effort basis
-		// if it fails, we log the error and continue with the next prompt
-		jsonArguments, _ := json.Marshal(prompt.Arguments)
-
-		p := &model.Prompt{
-			ServerID:    s.ID,
-			Name:        prompt.GetName(),
-			Description: prompt.Description,
-			Arguments:   jsonArguments,
+	prompts := uniquePrompts(s, resp.Prompts)
+	inserted := insertInBatches(m.db, len(prompts),
+		func(i int) *model.Prompt { return newPromptModel(s, prompts[i]) },
+		func(i int) string { return "prompt " + mergeServerPromptNames(s.Name, prompts[i].GetName()) },
+	)
+	for i, prompt := range prompts {
+		if inserted[i] {
+			m.publishPrompt(s, prompt)
 		}
-		if err := m.db.Create(p).Error; err != nil {
-			// If registration of a prompt fails, we should not fail the entire server registration.
-			// Instead, continue with the next prompt.
-			log.Printf("[ERROR] failed to register prompt %s in DB: %v", canonicalPromptName, err)
-		} else {
-			// Set prompt name to include the server name prefix to make it recognizable by MCPJungle
-			// then add the prompt to the MCP proxy server
-			prompt.Name = canonicalPromptName
+	}
+	return nil
+}
 
-			if s.Transport == types.TransportSSE {
-				m.sseMcpProxyServer.AddPrompt(prompt, m.mcpProxyPromptHandler)
-			} else {
-				m.mcpProxyServer.AddPrompt(prompt, m.mcpProxyPromptHandler)
-			}
+// uniquePrompts returns the prompts listed by an MCP server without the ones whose name was already listed.
+func uniquePrompts(s *model.McpServer, prompts []mcp.Prompt) []mcp.Prompt {
+	seen := make(map[string]bool, len(prompts))
+	unique := make([]mcp.Prompt, 0, len(prompts))
+	for _, prompt := range prompts {
+		if seen[prompt.GetName()] {
+			log.Printf("[WARN] MCP server %s lists the prompt %s more than once, only the first one is registered", s.Name, prompt.GetName())
+			continue
 		}
+		seen[prompt.GetName()] = true
+		unique = append(unique, prompt)
+	}
+	return unique
+}
+
+// newPromptModel creates the DB record of a prompt provided by an MCP server.
+func newPromptModel(s *model.McpServer, prompt mcp.Prompt) *model.Prompt {
+	// extracting json schema is currently on best-effort basis
+	// if it fails, we log the error and continue with the next prompt
+	jsonArguments, _ := json.Marshal(prompt.Arguments)
+
+	return &model.Prompt{
+		ServerID:    s.ID,
+		Name:        prompt.GetName(),
+		Description: prompt.Description,
+		Arguments:   jsonArguments,
+	}
+}
+
+// publishPrompt adds a prompt stored in the DB to the appropriate MCP proxy server.
+func (m *MCPService) publishPrompt(s *model.McpServer, prompt mcp.Prompt) {
+	// Set prompt name to include the server name prefix to make it recognizable by MCPJungle
+	// then add the prompt to the MCP proxy server
+	prompt.Name = mergeServerPromptNames(s.Name, prompt.GetName())
+
+	if s.Transport == types.TransportSSE {
+		m.sseMcpProxyServer.AddPrompt(prompt, m.mcpProxyPromptHandler)
+	} else {
+		m.mcpProxyServer.AddPrompt(prompt, m.mcpProxyPromptHandler)
 	}
-	return nil
 }
 
 // deregisterServerPrompts deletes all prompts that belong to an MCP server from the DB.
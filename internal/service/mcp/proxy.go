@@ -2,19 +2,37 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/internal/telemetry"
-	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
+// ErrServerAccessDenied is matched by the errors returned when an MCP client calls a tool or a prompt
+// of an MCP server it is not allowed to access.
+var ErrServerAccessDenied = errors.New("MCP client is not authorized to access the MCP server")
+
+// accessDeniedError is the refusal of a call of an MCP client to an MCP server, it matches ErrServerAccessDenied.
+type accessDeniedError struct {
+	client, server string
+}
+
+func (e *accessDeniedError) Error() string {
+	return fmt.Sprintf("client %s is not authorized to access MCP server %s", e.client, e.server)
+}
+
+func (e *accessDeniedError) Is(target error) bool { return target == ErrServerAccessDenied }
+
 // MCPProxyToolCallHandler handles tool calls for the MCP proxy server
 // by forwarding the request to the appropriate upstream MCP server and
 // relaying the response back.
 func (m *MCPService) MCPProxyToolCallHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	m.inFlightToolCalls.Add(1)
+	defer m.inFlightToolCalls.Add(-1)
+
 	started := time.Now()
 	outcome := telemetry.ToolCallOutcomeSuccess
 
@@ -30,9 +48,7 @@ func (m *MCPService) MCPProxyToolCallHandler(ctx context.Context, request mcp.Ca
 		// If not, return error Unauthorized.
 		c := ctx.Value("client").(*model.McpClient)
 		if !c.CheckHasServerAccess(serverName) {
-			return nil, fmt.Errorf(
-				"client %s is not authorized to access MCP server %s", c.Name, serverName,
-			)
+			return nil, &accessDeniedError{client: c.Name, server: serverName}
 		}
 	}
 
@@ -52,6 +68,17 @@ func (m *MCPService) MCPProxyToolCallHandler(ctx context.Context, request mcp.Ca
 			"failed to get details about MCP server %s from DB: %w", serverName, err,
 		)
 	}
+	if err := m.checkWarmedUp(serverName); err != nil {
+		outcome = telemetry.ToolCallOutcomeError
+		return nil, err
+	}
+
+	release, err := m.upstreamCalls.acquire(ctx, serverName)
+	if err != nil {
+		outcome = telemetry.ToolCallOutcomeError
+		return nil, err
+	}
+	defer release()
 
 	session, err := m.getSession(ctx, server)
 	if err != nil {
@@ -63,10 +90,23 @@ func (m *MCPService) MCPProxyToolCallHandler(ctx context.Context, request mcp.Ca
 	// Ensure the tool name is set correctly, ie, without the server name prefix
 	request.Params.Name = toolName
 
-	res, err := session.client.CallTool(ctx, request)
+	// the size of the result is counted as it is read from the server, which fails once it exceeds the limit
+	callCtx, size := withResultSize(ctx, m.sessionManager.maxResultBytes.Load())
+	callStarted := time.Now()
+	res, err := session.client.CallTool(callCtx, request)
+	err = size.check(name, err)
+	m.metrics.RecordUpstreamOperation(
+		ctx, serverName, telemetry.MCPMethodToolsCall, toolName, err, time.Since(callStarted),
+	)
 	if err != nil {
 		outcome = telemetry.ToolCallOutcomeError
-		session.invalidateOnError(err) // Invalidate unhealthy stateful sessions
+		res = nil
+		if !errors.Is(err, ErrToolResultTooLarge) {
+			session.invalidateOnError(err) // Invalidate unhealthy stateful sessions
+		}
+	}
+	if size.bytes() > 0 {
+		m.metrics.RecordToolResultSize(ctx, serverName, toolName, size.bytes())
 	}
 
 	// forward the request to the upstream MCP server and relay the response back
@@ -92,9 +132,7 @@ func (m *MCPService) mcpProxyPromptHandler(ctx context.Context, request mcp.GetP
 		// If not, return error Unauthorized.
 		c := ctx.Value("client").(*model.McpClient)
 		if !c.CheckHasServerAccess(serverName) {
-			return nil, fmt.Errorf(
-				"client %s is not authorized to access MCP server %s", c.Name, serverName,
-			)
+			return nil, &accessDeniedError{client: c.Name, server: serverName}
 		}
 	}
 
@@ -115,6 +153,13 @@ func (m *MCPService) mcpProxyPromptHandler(ctx context.Context, request mcp.GetP
 		)
 	}
 
+	release, err := m.upstreamCalls.acquire(ctx, serverName)
+	if err != nil {
+		outcome = telemetry.PromptCallOutcomeError
+		return nil, err
+	}
+	defer release()
+
 	session, err := m.getSession(ctx, server)
 	if err != nil {
 		outcome = telemetry.PromptCallOutcomeError
@@ -126,7 +171,11 @@ func (m *MCPService) mcpProxyPromptHandler(ctx context.Context, request mcp.GetP
 	request.Params.Name = promptName
 
 	// forward the request to the upstream MCP server and relay the response back
+	callStarted := time.Now()
 	res, err := session.client.GetPrompt(ctx, request)
+	m.metrics.RecordUpstreamOperation(
+		ctx, serverName, telemetry.MCPMethodPromptsGet, promptName, err, time.Since(callStarted),
+	)
 	if err != nil {
 		outcome = telemetry.PromptCallOutcomeError
 		session.invalidateOnError(err) // Invalidate unhealthy stateful sessions
@@ -138,92 +187,11 @@ func (m *MCPService) mcpProxyPromptHandler(ctx context.Context, request mcp.GetP
 // initMCPProxyServer initializes the MCP proxy server.
 // It loads all the registered MCP tools and prompts from the database into the proxy server.
 func (m *MCPService) initMCPProxyServer() error {
-	mcpServerModelsCache := make(map[string]*model.McpServer)
-
-	// Load Tools
-	tools, err := m.ListTools()
+	snapshot, err := m.loadProxySnapshot()
 	if err != nil {
-		return fmt.Errorf("failed to list tools from DB: %w", err)
+		return err
 	}
-
-	for _, tm := range tools {
-		if !tm.Enabled {
-			// do not add disabled tools to the proxy
-			continue
-		}
-
-		// Add tool to the MCP proxy server
-		tool, err := convertToolModelToMcpObject(&tm)
-		if err != nil {
-			return fmt.Errorf("failed to convert tool model to MCP object for tool %s: %w", tm.Name, err)
-		}
-
-		// get the tool's MCP server so we can determine the transport type
-		// use a cache to avoid querying the DB multiple times for the same server
-		// since multiple tools can belong to the same server
-		var server *model.McpServer
-		serverName, _, _ := splitServerToolName(tool.Name)
-
-		server, exists := mcpServerModelsCache[serverName]
-		if !exists {
-			server, err = m.GetMcpServer(serverName)
-			if err != nil {
-				return fmt.Errorf(
-					"init mcp proxy server: failed to get MCP server %s for tool %s from DB: %w", serverName, tool.Name, err,
-				)
-			}
-			// store the server model in cache so we don't have to query the DB again for the same server
-			mcpServerModelsCache[serverName] = server
-		}
-
-		if server.Transport == types.TransportSSE {
-			m.sseMcpProxyServer.AddTool(tool, m.MCPProxyToolCallHandler)
-		} else {
-			m.mcpProxyServer.AddTool(tool, m.MCPProxyToolCallHandler)
-		}
-
-		m.addToolInstance(tool)
-	}
-
-	// Load prompts
-	prompts, err := m.ListPrompts()
-	if err != nil {
-		return fmt.Errorf("failed to list prompts from DB: %w", err)
-	}
-
-	for _, pm := range prompts {
-		if !pm.Enabled {
-			// do not add disabled prompts to the proxy
-			continue
-		}
-
-		// Add prompt to the MCP proxy server
-		prompt, err := convertPromptModelToMcpObject(&pm)
-		if err != nil {
-			return fmt.Errorf("failed to convert prompt model to MCP object for prompt %s: %w", pm.Name, err)
-		}
-
-		// get the prompt's MCP server from cache so we can determine the transport type
-		var server *model.McpServer
-		serverName, _, _ := splitServerPromptName(prompt.Name)
-
-		server, exists := mcpServerModelsCache[serverName]
-		if !exists {
-			server, err = m.GetMcpServer(serverName)
-			if err != nil {
-				return fmt.Errorf(
-					"init mcp proxy server: failed to get MCP server %s for tool %s from DB: %w", serverName, prompt.Name, err,
-				)
-			}
-			mcpServerModelsCache[serverName] = server
-		}
-
-		if server.Transport == types.TransportSSE {
-			m.sseMcpProxyServer.AddPrompt(prompt, m.mcpProxyPromptHandler)
-		} else {
-			m.mcpProxyServer.AddPrompt(prompt, m.mcpProxyPromptHandler)
-		}
-	}
-
+	m.applyProxySnapshot(&proxySnapshot{}, snapshot)
+	m.published = snapshot
 	return nil
 }
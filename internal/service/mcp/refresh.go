@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// proxySnapshot is the state of the DB that the MCP proxy servers were built from.
+type proxySnapshot struct {
+	// servers are the registered MCP servers, by name
+	servers map[string]*model.McpServer
+	// tools and prompts are the enabled ones, by canonical name
+	tools   map[string]publishedEntity[mcp.Tool]
+	prompts map[string]publishedEntity[mcp.Prompt]
+}
+
+// publishedEntity is a tool or a prompt published on an MCP proxy server.
+type publishedEntity[T any] struct {
+	entity T
+	// sse is true if the entity is published on the SSE proxy server
+	sse bool
+	// definition is the JSON encoding of the entity, to tell whether it changed
+	definition string
+}
+
+// loadProxySnapshot reads the MCP servers and their enabled tools and prompts from the DB.
+func (m *MCPService) loadProxySnapshot() (*proxySnapshot, error) {
+	servers, err := m.ListMcpServers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MCP servers from DB: %w", err)
+	}
+	snapshot := &proxySnapshot{
+		servers: make(map[string]*model.McpServer, len(servers)),
+		tools:   make(map[string]publishedEntity[mcp.Tool]),
+		prompts: make(map[string]publishedEntity[mcp.Prompt]),
+	}
+	for i := range servers {
+		snapshot.servers[servers[i].Name] = &servers[i]
+	}
+
+	tools, err := m.ListTools()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tools from DB: %w", err)
+	}
+	for _, tm := range tools {
+		if !tm.Enabled {
+			// do not add disabled tools to the proxy
+			continue
+		}
+		tool, err := convertToolModelToMcpObject(&tm)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert tool model to MCP object for tool %s: %w", tm.Name, err)
+		}
+		s, ok := snapshot.servers[ToolServerName(tool.Name)]
+		if !ok {
+			// the server was registered after it was listed
+			continue
+		}
+		snapshot.tools[tool.Name] = newPublishedEntity(tool, s)
+	}
+
+	prompts, err := m.ListPrompts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompts from DB: %w", err)
+	}
+	for _, pm := range prompts {
+		if !pm.Enabled {
+			// do not add disabled prompts to the proxy
+			continue
+		}
+		prompt, err := convertPromptModelToMcpObject(&pm)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert prompt model to MCP object for prompt %s: %w", pm.Name, err)
+		}
+		serverName, _, _ := splitServerPromptName(prompt.Name)
+		s, ok := snapshot.servers[serverName]
+		if !ok {
+			continue
+		}
+		snapshot.prompts[prompt.Name] = newPublishedEntity(prompt, s)
+	}
+	return snapshot, nil
+}
+
+func newPublishedEntity[T any](entity T, s *model.McpServer) publishedEntity[T] {
+	// the entity was just decoded from JSON, so it can't fail to be encoded again
+	definition, _ := json.Marshal(entity)
+	return publishedEntity[T]{entity: entity, sse: s.Transport == types.TransportSSE, definition: string(definition)}
+}
+
+// RefreshFromDB updates the MCP proxy servers with the changes the other replicas of the registry made to the DB
+// since they were last refreshed: the tools and prompts that were added, changed, enabled, disabled or removed
+// are published or withdrawn, and the stateful sessions of the servers whose configuration changed are closed.
+// The tool groups are notified of the tools that were published or withdrawn.
+//
+// The changes this replica made itself are found again, applying them again makes no difference.
+func (m *MCPService) RefreshFromDB() error {
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+	next, err := m.loadProxySnapshot()
+	if err != nil {
+		return err
+	}
+	m.applyProxySnapshot(m.published, next)
+	m.published = next
+	return nil
+}
+
+// applyProxySnapshot updates the MCP proxy servers built from prev to serve the servers, tools and prompts of next.
+func (m *MCPService) applyProxySnapshot(prev, next *proxySnapshot) {
+	for name, s := range prev.servers {
+		ns, ok := next.servers[name]
+		switch {
+		case !ok:
+			m.releaseServer(s)
+			m.dropServerHealth(name)
+		case ns.ID != s.ID || ns.Version != s.Version:
+			// the server was registered again, or its configuration changed
+			if changed, err := connectionChanged(s, ns); err != nil || changed || ns.ID != s.ID {
+				m.sessionManager.CloseStaleSession(ns)
+				m.dropServerHealth(name)
+			}
+		}
+	}
+
+	proxyOf := func(sse bool) *server.MCPServer {
+		if sse {
+			return m.sseMcpProxyServer
+		}
+		return m.mcpProxyServer
+	}
+
+	var removed []string
+	for name, t := range prev.tools {
+		if nt, ok := next.tools[name]; !ok || nt.sse != t.sse {
+			proxyOf(t.sse).DeleteTools(name)
+			if !ok {
+				removed = append(removed, name)
+			}
+		}
+	}
+	if len(removed) > 0 {
+		m.deleteToolInstances(removed...)
+		m.notifyToolDeletion(removed...)
+	}
+	for name, t := range next.tools {
+		proxy := proxyOf(t.sse)
+		if published := proxy.GetTool(name); published != nil && isPublished(published.Tool, t.definition) {
+			continue
+		}
+		proxy.AddTool(t.entity, m.MCPProxyToolCallHandler)
+		m.addToolInstance(t.entity)
+		m.notifyToolAddition(name)
+	}
+
+	// the proxy servers don't tell which prompts they publish, the changed ones are published again
+	for name, p := range prev.prompts {
+		if np, ok := next.prompts[name]; !ok || np.sse != p.sse {
+			proxyOf(p.sse).DeletePrompts(name)
+		}
+	}
+	for name, p := range next.prompts {
+		if pp, ok := prev.prompts[name]; ok && pp.sse == p.sse && pp.definition == p.definition {
+			continue
+		}
+		proxyOf(p.sse).AddPrompt(p.entity, m.mcpProxyPromptHandler)
+	}
+}
+
+// isPublished reports whether the tool published on a proxy server matches the definition of a tool.
+func isPublished(tool mcp.Tool, definition string) bool {
+	published, err := json.Marshal(tool)
+	if err != nil {
+		log.Printf("[WARN] failed to encode the published tool %s: %v", tool.Name, err)
+		return false
+	}
+	return string(published) == definition
+}
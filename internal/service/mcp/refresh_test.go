@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/replica"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// replicaTestService is an MCP service running as one of the replicas sharing a database.
+type replicaTestService struct {
+	*MCPService
+	coordinator *replica.Coordinator
+	stopped     sync.Once
+}
+
+// stop stops the replica as the server does when it shuts down, once.
+func (r *replicaTestService) stop() {
+	r.stopped.Do(func() {
+		r.Shutdown()
+		r.coordinator.Close()
+	})
+}
+
+// newReplicaTestServices creates the MCP services of n replicas, each with its own connection to the same
+// SQLite database, in the order they take part in the election. They check the health of MCP servers every
+// healthCheckInterval, if it is not 0.
+func newReplicaTestServices(t *testing.T, n int, healthCheckInterval time.Duration) []*replicaTestService {
+	t.Helper()
+	dsn := "sqlite://" + filepath.Join(t.TempDir(), "mcpjungle.db")
+	replicas := make([]*replicaTestService, n)
+	for i := range replicas {
+		conn, err := db.NewDBConnection(dsn)
+		testhelpers.AssertNoError(t, err)
+		// closed once the replica stopped
+		t.Cleanup(func() {
+			if sqlDB, err := conn.DB(); err == nil {
+				_ = sqlDB.Close()
+			}
+		})
+		testhelpers.AssertNoError(t, migrations.Migrate(conn))
+		coordinator, err := replica.NewCoordinator(conn, &replica.Config{
+			ID: string(rune('a' + i)), LeaseTTL: 300 * time.Millisecond, SyncInterval: -1,
+		})
+		testhelpers.AssertNoError(t, err)
+		m, err := NewMCPService(&ServiceConfig{
+			DB:                      conn,
+			McpProxyServer:          server.NewMCPServer("proxy", "0.0.0"),
+			SseMcpProxyServer:       server.NewMCPServer("sse-proxy", "0.0.0"),
+			Metrics:                 telemetry.NewNoopCustomMetrics(),
+			McpServerInitReqTimeout: 1,
+			HealthCheckInterval:     healthCheckInterval,
+			IsLeader:                coordinator.IsLeader,
+		})
+		testhelpers.AssertNoError(t, err)
+		replicas[i] = &replicaTestService{MCPService: m, coordinator: coordinator}
+		t.Cleanup(replicas[i].stop)
+	}
+	return replicas
+}
+
+func TestRefreshFromDB(t *testing.T) {
+	replicas := newReplicaTestServices(t, 2, 0)
+	a, b := replicas[0], replicas[1]
+	upstream := server.NewMCPServer("github", "0.0.0")
+	upstream.AddTool(mcp.NewTool("git_commit"), noopToolHandler)
+	upstream.AddTool(mcp.NewTool("git_push"), noopToolHandler)
+	upstreamServer := server.NewTestStreamableHTTPServer(upstream)
+	defer upstreamServer.Close()
+
+	// the tools registered through one replica are published by the other once it refreshed
+	github, err := model.NewStreamableHTTPServer("github", "", upstreamServer.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, a.RegisterMcpServer(context.Background(), github))
+	testhelpers.AssertTrue(t, b.mcpProxyServer.GetTool("github__git_commit") == nil, "the tool should not be published yet")
+	testhelpers.AssertNoError(t, b.RefreshFromDB())
+	testhelpers.AssertNotNil(t, b.mcpProxyServer.GetTool("github__git_commit"))
+	testhelpers.AssertNotNil(t, b.mcpProxyServer.GetTool("github__git_push"))
+
+	// the replica that made the changes finds them again
+	testhelpers.AssertNoError(t, a.RefreshFromDB())
+	testhelpers.AssertEqual(t, 2, len(a.mcpProxyServer.ListTools()))
+
+	_, err = a.DisableTools("github__git_push")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, b.RefreshFromDB())
+	testhelpers.AssertTrue(t, b.mcpProxyServer.GetTool("github__git_push") == nil, "the disabled tool should be withdrawn")
+	testhelpers.AssertNotNil(t, b.mcpProxyServer.GetTool("github__git_commit"))
+
+	testhelpers.AssertNoError(t, a.DeregisterMcpServer("github"))
+	testhelpers.AssertNoError(t, b.RefreshFromDB())
+	testhelpers.AssertEqual(t, 0, len(b.mcpProxyServer.ListTools()))
+}
@@ -2,10 +2,15 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"reflect"
+	"slices"
 
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
 )
 
 // RegisterMcpServer registers a new MCP server in the database.
@@ -16,8 +21,15 @@ func (m *MCPService) RegisterMcpServer(ctx context.Context, s *model.McpServer)
 	if err := validateServerName(s.Name); err != nil {
 		return err
 	}
+	if err := m.sessionManager.packages.prepare(ctx, s); err != nil {
+		return err
+	}
 
-	mcpClient, err := newMcpServerSession(ctx, s, m.mcpServerInitReqTimeoutSec)
+	mcpClient, err := newMcpServerSession(
+		ctx, s, m.mcpServerInitReqTimeoutSec, m.sessionManager.httpPools,
+		m.sessionManager.secrets, m.sessionManager.containers, m.sessionManager.packages,
+		m.sessionManager.maxResultBytes.Load,
+	)
 	if err != nil {
 		return err
 	}
@@ -44,7 +56,7 @@ func (m *MCPService) RegisterMcpServer(ctx context.Context, s *model.McpServer)
 // It also deregisters all the tools and prompts registered by the server.
 // If even a single tool or prompt fails to deregister, the server deregistration fails.
 // Deregistered tools and prompts are also removed from the MCP proxy server.
-// Any stateful sessions associated with this server are also closed.
+// Any stateful sessions associated with this server are also closed, and the containers it was launched in removed.
 func (m *MCPService) DeregisterMcpServer(name string) error {
 	s, err := m.GetMcpServer(name)
 	if err != nil {
@@ -68,18 +80,226 @@ func (m *MCPService) DeregisterMcpServer(name string) error {
 		return fmt.Errorf("failed to deregister server %s: %w", name, err)
 	}
 
-	// Close any stateful session associated with this server
-	m.sessionManager.CloseSession(name)
+	m.releaseServer(s)
+	m.forgetServerHealth(name)
 
 	return nil
 }
 
+// releaseServer closes any stateful session of a deregistered MCP server, removes the containers it was launched in
+// and forgets the state kept about it in memory.
+func (m *MCPService) releaseServer(s *model.McpServer) {
+	m.sessionManager.CloseSession(s.Name)
+	if conf, err := s.GetStdioConfig(); err == nil && conf.Container != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), containerRemoveTimeout)
+		m.sessionManager.containers.removeAll(ctx, s.Name)
+		cancel()
+	}
+	m.sessionManager.packages.forget(s.Name)
+	m.forgetServerSync(s.Name)
+}
+
+// UpdateMcpServer replaces the configuration of a registered MCP server with that of s, matched by name.
+// If s.Version is set, the server is only updated if it is still at that version, otherwise model.ErrVersionConflict
+// is returned. On success, s holds the updated server, including its new version.
+//
+// If the connection settings changed, the new configuration is verified by connecting to the server before anything
+// is changed. The server's tools and prompts are then registered again from the updated server, so tools that no
+// longer exist are removed from the MCP proxy server. Tools and prompts that were disabled remain disabled.
+// Any stateful session opened with the previous configuration is closed.
+// If only the description changed, the server's tools and prompts are left as they are.
+func (m *MCPService) UpdateMcpServer(ctx context.Context, s *model.McpServer) error {
+	existing, err := m.GetMcpServer(s.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get MCP server %s from DB: %w", s.Name, err)
+	}
+	if s.Version != 0 && s.Version != existing.Version {
+		return model.ErrVersionConflict
+	}
+
+	if err := keepPackageVersion(existing, s); err != nil {
+		return err
+	}
+	changed, err := connectionChanged(existing, s)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return m.updateServerRecord(existing, s)
+	}
+	if err := m.sessionManager.packages.prepare(ctx, s); err != nil {
+		return err
+	}
+
+	mcpClient, err := newMcpServerSession(
+		ctx, s, m.mcpServerInitReqTimeoutSec, m.sessionManager.httpPools,
+		m.sessionManager.secrets, m.sessionManager.containers, m.sessionManager.packages,
+		m.sessionManager.maxResultBytes.Load,
+	)
+	if err != nil {
+		return err
+	}
+	defer mcpClient.Close()
+
+	disabledTools, disabledPrompts, err := m.disabledServerEntities(existing)
+	if err != nil {
+		return err
+	}
+
+	// the record is updated first, so that a concurrent update of the same server fails before any tool is touched
+	if err := m.updateServerRecord(existing, s); err != nil {
+		return err
+	}
+
+	if err := m.deregisterServerTools(existing); err != nil {
+		return fmt.Errorf("failed to deregister tools for server %s, cannot proceed with server update: %w", s.Name, err)
+	}
+	if err := m.deregisterServerPrompts(existing); err != nil {
+		return fmt.Errorf("failed to deregister prompts for server %s, cannot proceed with server update: %w", s.Name, err)
+	}
+
+	// the session and the last health check used the previous configuration
+	m.sessionManager.CloseSession(s.Name)
+	m.forgetServerHealth(s.Name)
+
+	if err := m.registerServerTools(ctx, s, mcpClient); err != nil {
+		return fmt.Errorf("failed to register tools for MCP server %s: %w", s.Name, err)
+	}
+	if err := m.registerServerPrompts(ctx, s, mcpClient); err != nil {
+		log.Printf("[WARN] failed to register prompts for MCP server %s: %v", s.Name, err)
+	}
+
+	// restore the disabled state of the tools and prompts that still exist
+	for _, name := range disabledTools {
+		if _, err := m.DisableTools(name); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("[WARN] failed to disable tool %s after updating MCP server %s: %v", name, s.Name, err)
+		}
+	}
+	for _, name := range disabledPrompts {
+		if _, err := m.DisablePrompts(name); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("[WARN] failed to disable prompt %s after updating MCP server %s: %v", name, s.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// updateServerRecord stores the configuration of s in the record of existing and increments its version.
+// It fails with model.ErrVersionConflict if the record was changed since existing was read.
+func (m *MCPService) updateServerRecord(existing, s *model.McpServer) error {
+	updates := map[string]any{
+		"description":  s.Description,
+		"transport":    s.Transport,
+		"config":       s.Config,
+		"session_mode": s.SessionMode,
+		"lazy_start":   s.LazyStart,
+		"version":      gorm.Expr("version + 1"),
+	}
+	result := m.db.Model(&model.McpServer{}).
+		Where("id = ? AND version = ?", existing.ID, existing.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update mcp server %s: %w", s.Name, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrVersionConflict
+	}
+	s.Model = existing.Model
+	s.EntityID = existing.EntityID
+	s.Version = existing.Version + 1
+	return nil
+}
+
+// connectionChanged reports whether the settings used to connect to an MCP server differ between two configurations.
+func connectionChanged(a, b *model.McpServer) (bool, error) {
+	if a.Transport != b.Transport || a.SessionMode != b.SessionMode || a.LazyStart != b.LazyStart {
+		return true, nil
+	}
+	// the stored configuration may have been re-encoded by the DB, so configurations are compared by value
+	var confA, confB any
+	if err := json.Unmarshal(a.Config, &confA); err != nil {
+		return false, fmt.Errorf("failed to decode the configuration of MCP server %s: %w", a.Name, err)
+	}
+	if err := json.Unmarshal(b.Config, &confB); err != nil {
+		return false, fmt.Errorf("failed to decode the configuration of MCP server %s: %w", b.Name, err)
+	}
+	return !reflect.DeepEqual(confA, confB), nil
+}
+
+// disabledServerEntities returns the canonical names of the disabled tools and prompts of an MCP server.
+func (m *MCPService) disabledServerEntities(s *model.McpServer) ([]string, []string, error) {
+	tools, err := m.ListToolsByServer(s.Name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list tools for server %s: %w", s.Name, err)
+	}
+	prompts, err := m.ListPromptsByServer(s.Name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list prompts for server %s: %w", s.Name, err)
+	}
+
+	var disabledTools, disabledPrompts []string
+	for _, t := range tools {
+		if !t.Enabled {
+			disabledTools = append(disabledTools, t.Name)
+		}
+	}
+	for _, p := range prompts {
+		if !p.Enabled {
+			disabledPrompts = append(disabledPrompts, p.Name)
+		}
+	}
+	return disabledTools, disabledPrompts, nil
+}
+
 // ListMcpServers returns all registered MCP servers.
 func (m *MCPService) ListMcpServers() ([]model.McpServer, error) {
+	servers, _, err := m.ListMcpServersPage(model.Page{})
+	return servers, err
+}
+
+// ListMcpServersPage returns a page of the registered MCP servers, along with the ID to list the next page after
+// (0 if there are no more servers).
+func (m *MCPService) ListMcpServersPage(p model.Page) ([]model.McpServer, uint, error) {
+	var servers []model.McpServer
+	if err := p.Apply(m.db).Find(&servers).Error; err != nil {
+		return nil, 0, err
+	}
+	servers, next := model.NextPage(p, servers, func(s model.McpServer) uint { return s.ID })
+	return servers, next, nil
+}
+
+// serverNamesByID returns the names of the servers with the given IDs, keyed by ID.
+// The IDs may repeat, eg- those of the servers of a list of tools, each server is only fetched once.
+func (m *MCPService) serverNamesByID(ids []uint) (map[uint]string, error) {
+	names := make(map[uint]string)
+	if len(ids) == 0 {
+		return names, nil
+	}
+	unique := slices.Compact(slices.Sorted(slices.Values(ids)))
 	var servers []model.McpServer
-	if err := m.db.Find(&servers).Error; err != nil {
+	if err := m.db.Select("id", "name").Where("id IN ?", unique).Find(&servers).Error; err != nil {
+		return nil, err
+	}
+	for _, s := range servers {
+		names[s.ID] = s.Name
+	}
+	return names, nil
+}
+
+// GetMcpServersByName fetches the servers with the given names from the database in a single query, keyed by name.
+// The names that are not registered are left out.
+func (m *MCPService) GetMcpServersByName(names []string) (map[string]*model.McpServer, error) {
+	servers := make(map[string]*model.McpServer)
+	if len(names) == 0 {
+		return servers, nil
+	}
+	var records []model.McpServer
+	if err := m.db.Where("name IN ?", names).Find(&records).Error; err != nil {
 		return nil, err
 	}
+	for i := range records {
+		servers[records[i].Name] = &records[i]
+	}
 	return servers, nil
 }
 
@@ -92,6 +312,11 @@ func (m *MCPService) GetMcpServer(name string) (*model.McpServer, error) {
 	return &serverModel, nil
 }
 
+// ResolveServerName returns the name of the MCP server identified by ident, which is either its UUID or its name.
+func (m *MCPService) ResolveServerName(ident string) (string, error) {
+	return model.ResolveName(m.db, &model.McpServer{}, "name", ident)
+}
+
 // EnableMcpServer enables all tools and prompts registered by the given MCP server.
 // It returns the names of the enabled tools and prompts.
 // If even a single tool or prompt fails to enable, the operation fails.
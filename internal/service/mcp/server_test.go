@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestUpdateMcpServerVersion(t *testing.T) {
+	m := newBulkTestService(t)
+	existing := newUnreachableServers(t, "github")[0]
+	testhelpers.AssertNoError(t, m.RegisterMcpServers(context.Background(), []*model.McpServer{existing}, true))
+	testhelpers.AssertEqual(t, uint(1), existing.Version)
+
+	// the connection settings are unchanged, so the update doesn't need to reach the upstream
+	updated, err := model.NewStreamableHTTPServer("github", "GitHub tools", "http://127.0.0.1:1/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	updated.Version = 1
+	testhelpers.AssertNoError(t, m.UpdateMcpServer(context.Background(), updated))
+	testhelpers.AssertEqual(t, uint(2), updated.Version)
+
+	stored, err := m.GetMcpServer("github")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "GitHub tools", stored.Description)
+	testhelpers.AssertEqual(t, uint(2), stored.Version)
+
+	// an update computed against the first version would overwrite the description
+	stale, err := model.NewStreamableHTTPServer("github", "", "http://127.0.0.1:1/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	stale.Version = 1
+	err = m.UpdateMcpServer(context.Background(), stale)
+	testhelpers.AssertTrue(t, errors.Is(err, model.ErrVersionConflict), "expected a version conflict")
+
+	// unconditional updates always apply
+	stale.Version = 0
+	testhelpers.AssertNoError(t, m.UpdateMcpServer(context.Background(), stale))
+	testhelpers.AssertEqual(t, uint(3), stale.Version)
+}
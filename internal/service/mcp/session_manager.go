@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/mark3labs/mcp-go/client"
+	"github.com/mcpjungle/mcpjungle/internal/docker"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
@@ -19,6 +22,11 @@ const (
 
 	// sessionCleanupIntervalSec is the interval at which the session manager checks for idle sessions.
 	sessionCleanupIntervalSec = 60 // 1 minute
+
+	// containerRestartAttempts is how many times the session of a server whose container exited is restarted,
+	// waiting containerRestartBackoff between the first attempts and twice as long every time after.
+	containerRestartAttempts = 5
+	containerRestartBackoff  = time.Second
 )
 
 // ManagedSession represents a persistent connection to an MCP server.
@@ -27,18 +35,63 @@ type ManagedSession struct {
 	Client     *client.Client
 	CreatedAt  time.Time
 	LastUsedAt time.Time
+
+	// inUse is the number of calls using the session, it is never closed for being idle while in use
+	inUse int
+	// keepAlive pings the session while it is idle, nil if disabled for the server
+	keepAlive *types.KeepAliveConfig
+	// serverID and serverVersion identify the configuration of the server the session was started from
+	serverID      uint
+	serverVersion uint
+}
+
+// sessionRestart is the restart of a session whose container exited.
+type sessionRestart struct {
+	cancel context.CancelFunc
 }
 
-// SessionManager manages persistent connections to MCP servers configured in stateful mode.
+// pendingTransition lets callers wait for a server whose session is being started or stopped.
+type pendingTransition struct {
+	done chan struct{}
+	// err is the error that prevented the session from starting, set before done is closed
+	err error
+}
+
+// SessionManager manages persistent connections to MCP servers configured in stateful mode or started lazily.
+// A session is only started when a call first needs it, and stopped once it has been idle for the idle timeout.
+// Concurrent calls to a server whose session is being started or stopped wait for it, so that a server never
+// has two sessions at once.
 type SessionManager struct {
 	mu       sync.RWMutex
 	sessions map[string]*ManagedSession // key: server name
+	// starting and stopping hold the servers whose session is being started or closed for being idle
+	starting map[string]*pendingTransition
+	stopping map[string]*pendingTransition
 
 	idleTimeoutSec    int
 	initReqTimeoutSec int
 	cleanupTicker     *time.Ticker
 	cleanupStopChan   chan struct{}
 	createSessionFunc func(ctx context.Context, s *model.McpServer, initReqTimeoutSec int) (*client.Client, error)
+
+	metrics telemetry.CustomMetrics
+	// httpPools holds the connection pools of the streamable HTTP servers, shared by all the connections to them
+	httpPools *httpPools
+	// secrets resolves the secrets referenced by the credentials of the servers, nil if no secret store is configured
+	secrets SecretResolver
+	// containers launches the stdio servers configured with a container
+	containers *containerRuntime
+	// packages looks up the versions of the packages stdio servers are launched from
+	packages *packageRegistry
+	// maxResultBytes is the maximum size of the response of an MCP server to a tool call, 0 for no limit,
+	// see MCPService.SetMaxToolResultBytes. It limits the messages of the stdio and SSE servers too.
+	maxResultBytes atomic.Int64
+	// restarts holds the sessions being restarted because their container exited, to cancel them
+	restarts map[string]*sessionRestart
+	// restartBackoff is the delay before the first restart of a session whose container exited
+	restartBackoff time.Duration
+	// stateVersion is incremented every time the lifecycle state of a server changes
+	stateVersion atomic.Uint64
 }
 
 // SessionManagerConfig holds configuration for the SessionManager.
@@ -50,6 +103,17 @@ type SessionManagerConfig struct {
 
 	// InitReqTimeoutSec is the timeout for MCP server initialization requests.
 	InitReqTimeoutSec int
+
+	// Metrics records how long sessions take to start. If nil, nothing is recorded.
+	Metrics telemetry.CustomMetrics
+
+	// Secrets resolves the references to secrets held by the credentials of the MCP servers, eg- to Vault.
+	// If nil, connecting to a server whose credentials reference a secret fails with ErrCredentialUnavailable.
+	Secrets SecretResolver
+
+	// Docker is the Docker daemon the stdio servers configured with a container are launched in.
+	// If nil, the local Docker daemon at docker.DefaultHost is used.
+	Docker *docker.Client
 }
 
 // NewSessionManager creates a new SessionManager instance.
@@ -58,14 +122,39 @@ func NewSessionManager(cfg *SessionManagerConfig) *SessionManager {
 	if idleTimeout < 0 {
 		idleTimeout = DefaultSessionIdleTimeoutSec
 	}
+	metrics := cfg.Metrics
+	if metrics == nil {
+		metrics = telemetry.NewNoopCustomMetrics()
+	}
+	dockerClient := cfg.Docker
+	if dockerClient == nil {
+		// the default host is always valid
+		dockerClient, _ = docker.NewClient("")
+	}
 
 	sm := &SessionManager{
 		sessions:          make(map[string]*ManagedSession),
+		starting:          make(map[string]*pendingTransition),
+		stopping:          make(map[string]*pendingTransition),
 		idleTimeoutSec:    idleTimeout,
 		initReqTimeoutSec: cfg.InitReqTimeoutSec,
 		cleanupStopChan:   make(chan struct{}),
-		// Use the actual session creation function by default
-		createSessionFunc: createMcpServerConnection,
+		metrics:           metrics,
+		httpPools:         newHTTPPools(metrics),
+		secrets:           cfg.Secrets,
+		containers:        newContainerRuntime(dockerClient),
+		packages:          newPackageRegistry(),
+		restarts:          make(map[string]*sessionRestart),
+		restartBackoff:    containerRestartBackoff,
+	}
+	// Use the actual session creation function by default
+	sm.createSessionFunc = func(ctx context.Context, s *model.McpServer, initReqTimeoutSec int) (*client.Client, error) {
+		s, err := withResolvedCredentials(ctx, s, sm.secrets)
+		if err != nil {
+			return nil, err
+		}
+		sm.packages.checkDrift(s)
+		return createMcpServerConnection(ctx, s, initReqTimeoutSec, sm.httpPools, sm.containers, sm.maxResultBytes.Load)
 	}
 
 	// Start cleanup goroutine if idle timeout is enabled
@@ -77,39 +166,163 @@ func NewSessionManager(cfg *SessionManagerConfig) *SessionManager {
 }
 
 // GetOrCreateSession returns an existing session for the server or creates a new one.
-// This method should only be called for servers with SessionMode set to stateful.
+// This method should only be called for servers with a persistent session, see model.McpServer.HasPersistentSession.
+// The session is not held in use, so it can be closed for being idle while the caller uses it.
 func (sm *SessionManager) GetOrCreateSession(ctx context.Context, server *model.McpServer) (*client.Client, error) {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
+	session, err := sm.acquireSession(ctx, server, true)
+	if err != nil {
+		return nil, err
+	}
+	sm.releaseSession(session, false)
+	return session.Client, nil
+}
 
-	// Check if we have an existing session
-	if session, exists := sm.sessions[server.Name]; exists {
-		session.LastUsedAt = time.Now()
-		return session.Client, nil
+// acquireSession returns the session of the server, starting it if needed, and holds it in use until
+// releaseSession is called.
+// If use is false, acquiring the session doesn't count as using it, eg- for health checks,
+// so that they don't keep an otherwise idle server running.
+func (sm *SessionManager) acquireSession(ctx context.Context, server *model.McpServer, use bool) (*ManagedSession, error) {
+	for {
+		sm.mu.Lock()
+		if session, exists := sm.sessions[server.Name]; exists {
+			session.inUse++
+			if use {
+				session.LastUsedAt = time.Now()
+			}
+			sm.mu.Unlock()
+			return session, nil
+		}
+		pending, exists := sm.starting[server.Name]
+		if !exists {
+			pending, exists = sm.stopping[server.Name]
+		}
+		if exists {
+			// wait for the other transition to complete, then look again
+			sm.mu.Unlock()
+			select {
+			case <-pending.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if pending.err != nil {
+				return nil, pending.err
+			}
+			continue
+		}
+
+		pending = &pendingTransition{done: make(chan struct{})}
+		sm.starting[server.Name] = pending
+		sm.stateVersion.Add(1)
+		sm.mu.Unlock()
+
+		return sm.startSession(ctx, server, pending)
 	}
+}
 
-	// Create a new session
+// startSession creates the session of the server on behalf of the callers waiting for pending.
+func (sm *SessionManager) startSession(
+	ctx context.Context, server *model.McpServer, pending *pendingTransition,
+) (*ManagedSession, error) {
+	start := time.Now()
 	mcpClient, err := sm.createSessionFunc(ctx, server, sm.initReqTimeoutSec)
+	elapsed := time.Since(start)
+
+	outcome := telemetry.ServerStartOutcomeSuccess
 	if err != nil {
-		return nil, fmt.Errorf("failed to create session for server '%s': %w", server.Name, err)
+		outcome = telemetry.ServerStartOutcomeError
 	}
+	sm.metrics.RecordServerStart(ctx, server.Name, outcome, elapsed)
 
-	sm.sessions[server.Name] = &ManagedSession{
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	delete(sm.starting, server.Name)
+	sm.stateVersion.Add(1)
+	defer close(pending.done)
+	if err == nil && ctx.Err() != nil {
+		// the session was canceled while it started, eg- the restart of a server being deregistered
+		_ = mcpClient.Close()
+		err = ctx.Err()
+	}
+	if err != nil {
+		pending.err = fmt.Errorf("failed to create session for server '%s': %w", server.Name, err)
+		return nil, pending.err
+	}
+
+	now := time.Now()
+	session := &ManagedSession{
 		ServerName: server.Name,
 		Client:     mcpClient,
-		CreatedAt:  time.Now(),
-		LastUsedAt: time.Now(),
+		CreatedAt:  now,
+		LastUsedAt: now,
+		inUse:      1,
+		keepAlive:  keepAliveConfig(server),
+
+		serverID:      server.ID,
+		serverVersion: server.Version,
 	}
+	sm.sessions[server.Name] = session
+	if session.keepAlive != nil {
+		go sm.keepAlive(session)
+	}
+	if ctr := sm.containers.containerOf(mcpClient); ctr != nil {
+		go sm.restartOnExit(server, session, ctr)
+	}
+
+	log.Printf("[SessionManager] Created new stateful session for server '%s' in %v", server.Name, elapsed.Round(time.Millisecond))
+
+	return session, nil
+}
+
+// releaseSession ends a use of a session acquired with acquireSession.
+// The idle timeout of the session starts from its last release.
+func (sm *SessionManager) releaseSession(session *ManagedSession, use bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if session.inUse > 0 {
+		session.inUse--
+	}
+	if use {
+		session.LastUsedAt = time.Now()
+	}
+}
 
-	log.Printf("[SessionManager] Created new stateful session for server '%s'", server.Name)
+// State returns the lifecycle state of the session of a server.
+func (sm *SessionManager) State(serverName string) types.ServerState {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	if _, exists := sm.starting[serverName]; exists {
+		return types.ServerStateStarting
+	}
+	if _, exists := sm.stopping[serverName]; exists {
+		return types.ServerStateIdleStopping
+	}
+	if _, exists := sm.sessions[serverName]; exists {
+		return types.ServerStateRunning
+	}
+	return types.ServerStateStopped
+}
 
-	return mcpClient, nil
+// StateVersion is incremented every time the lifecycle state of a server changes.
+// It tells whether the states returned by State may have changed since it was last read.
+func (sm *SessionManager) StateVersion() uint64 {
+	// the states of the containers are included, both versions are only incremented
+	return sm.stateVersion.Load() + sm.containers.stateVersion.Load()
 }
 
 // CloseSession closes and removes the session for the given server.
 func (sm *SessionManager) CloseSession(serverName string) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
+	sm.closeSession(serverName)
+}
+
+// closeSession does the work of CloseSession, sm.mu must be held.
+func (sm *SessionManager) closeSession(serverName string) {
+	if restart, exists := sm.restarts[serverName]; exists {
+		restart.cancel()
+		delete(sm.restarts, serverName)
+		sm.containers.setState(serverName, types.ContainerStateStopped)
+	}
 
 	if session, exists := sm.sessions[serverName]; exists {
 		if session.Client != nil {
@@ -118,8 +331,20 @@ func (sm *SessionManager) CloseSession(serverName string) {
 			}
 		}
 		delete(sm.sessions, serverName)
+		sm.stateVersion.Add(1)
 		log.Printf("[SessionManager] Closed session for server '%s'", serverName)
 	}
+	sm.httpPools.close(serverName)
+}
+
+// CloseStaleSession closes the session of a server if it was started from another configuration of the server
+// than s, eg- because another replica of the registry changed it since.
+func (sm *SessionManager) CloseStaleSession(s *model.McpServer) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if session, exists := sm.sessions[s.Name]; exists && (session.serverID != s.ID || session.serverVersion != s.Version) {
+		sm.closeSession(s.Name)
+	}
 }
 
 // InvalidateSession closes and removes a session due to a detected error.
@@ -136,6 +361,7 @@ func (sm *SessionManager) InvalidateSession(serverName string, reason string) {
 			}
 		}
 		delete(sm.sessions, serverName)
+		sm.stateVersion.Add(1)
 		log.Printf("[SessionManager] Invalidated unhealthy session for server '%s': %s", serverName, reason)
 	}
 }
@@ -146,6 +372,11 @@ func (sm *SessionManager) CloseAllSessions() {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	for name, restart := range sm.restarts {
+		restart.cancel()
+		delete(sm.restarts, name)
+	}
+
 	for name, session := range sm.sessions {
 		if session.Client != nil {
 			if err := session.Client.Close(); err != nil {
@@ -154,6 +385,7 @@ func (sm *SessionManager) CloseAllSessions() {
 		}
 		delete(sm.sessions, name)
 	}
+	sm.stateVersion.Add(1)
 
 	log.Printf("[SessionManager] Closed all sessions")
 }
@@ -167,6 +399,7 @@ func (sm *SessionManager) Shutdown() {
 
 	// Close all sessions
 	sm.CloseAllSessions()
+	sm.httpPools.closeAll()
 }
 
 // HasSession returns true if a session exists for the given server.
@@ -202,10 +435,9 @@ func (sm *SessionManager) startCleanupRoutine() {
 }
 
 // cleanupIdleSessions closes sessions that have been idle for longer than the idle timeout.
+// A session in use is never idle. While an idle session is being closed, calls to its server wait for it
+// to be closed before starting a new one.
 func (sm *SessionManager) cleanupIdleSessions() {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
-
 	if sm.idleTimeoutSec == 0 {
 		return // No timeout configured
 	}
@@ -213,30 +445,128 @@ func (sm *SessionManager) cleanupIdleSessions() {
 	now := time.Now()
 	idleThreshold := time.Duration(sm.idleTimeoutSec) * time.Second
 
+	sm.mu.Lock()
+	idle := make(map[string]*ManagedSession)
 	for name, session := range sm.sessions {
-		if now.Sub(session.LastUsedAt) > idleThreshold {
-			log.Printf("[SessionManager] Closing idle session for server '%s' (idle for %v)", name, now.Sub(session.LastUsedAt))
-			if session.Client != nil {
-				if err := session.Client.Close(); err != nil {
-					log.Printf("[SessionManager] Error closing session for server '%s': %v", name, err)
-				}
-			}
+		if session.keepAlive != nil && session.keepAlive.MaxIdleSec > 0 {
+			continue // its keep-alive closes it after its own maximum idle lifetime
+		}
+		if session.inUse == 0 && now.Sub(session.LastUsedAt) > idleThreshold {
+			idle[name] = session
 			delete(sm.sessions, name)
+			sm.stopping[name] = &pendingTransition{done: make(chan struct{})}
 		}
 	}
+	if len(idle) > 0 {
+		sm.stateVersion.Add(1)
+	}
+	sm.mu.Unlock()
+
+	for name, session := range idle {
+		log.Printf("[SessionManager] Closing idle session for server '%s' (idle for %v)", name, now.Sub(session.LastUsedAt))
+	}
+	sm.stopSessions(idle)
+}
+
+// stopSessions closes the given sessions, which were moved from the sessions to the stopping ones,
+// then lets the calls waiting for them start new ones.
+func (sm *SessionManager) stopSessions(stopping map[string]*ManagedSession) {
+	for name, session := range stopping {
+		if session.Client != nil {
+			if err := session.Client.Close(); err != nil {
+				log.Printf("[SessionManager] Error closing session for server '%s': %v", name, err)
+			}
+		}
+	}
+
+	if len(stopping) == 0 {
+		return
+	}
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	for name := range stopping {
+		close(sm.stopping[name].done)
+		delete(sm.stopping, name)
+	}
+	sm.stateVersion.Add(1)
 }
 
 // createMcpServerConnection creates a new MCP client connection based on the server's transport type.
 // This is a wrapper around the transport-specific connection functions.
-func createMcpServerConnection(ctx context.Context, s *model.McpServer, initReqTimeoutSec int) (*client.Client, error) {
+func createMcpServerConnection(
+	ctx context.Context, s *model.McpServer, initReqTimeoutSec int, pools *httpPools, containers *containerRuntime,
+	maxMessageBytes func() int64,
+) (*client.Client, error) {
 	switch s.Transport {
 	case types.TransportStreamableHTTP:
-		return createHTTPMcpServerConn(ctx, s, initReqTimeoutSec)
+		return createHTTPMcpServerConn(ctx, s, initReqTimeoutSec, pools)
 	case types.TransportSSE:
-		return createSSEMcpServerConn(ctx, s)
+		return createSSEMcpServerConn(ctx, s, maxMessageBytes)
 	case types.TransportStdio:
-		return runStdioServer(ctx, s, initReqTimeoutSec)
+		return runStdioServer(ctx, s, initReqTimeoutSec, containers, maxMessageBytes)
+	case types.TransportOpenAPI:
+		return createOpenAPIMcpServerConn(ctx, s)
+	case types.TransportWebhookTool:
+		return createWebhookToolMcpServerConn(ctx, s)
 	default:
 		return nil, fmt.Errorf("unsupported transport type: %s", s.Transport)
 	}
 }
+
+// restartOnExit restarts the session of a server if its container exits while the session is open,
+// retrying with an exponential backoff up to containerRestartAttempts times.
+// The restart is canceled if the session is closed meanwhile, eg- because the server is deregistered.
+func (sm *SessionManager) restartOnExit(server *model.McpServer, session *ManagedSession, ctr *container) {
+	<-ctr.stream.Done()
+	if !ctr.crashed() {
+		return
+	}
+
+	sm.mu.Lock()
+	if sm.sessions[server.Name] != session {
+		sm.mu.Unlock()
+		return
+	}
+	delete(sm.sessions, server.Name)
+	ctx, cancel := context.WithCancel(context.Background())
+	restart := &sessionRestart{cancel: cancel}
+	sm.restarts[server.Name] = restart
+	sm.stateVersion.Add(1)
+	sm.mu.Unlock()
+	defer func() {
+		cancel()
+		sm.mu.Lock()
+		defer sm.mu.Unlock()
+		if sm.restarts[server.Name] == restart {
+			delete(sm.restarts, server.Name)
+		}
+	}()
+
+	_ = session.Client.Close()
+	sm.containers.setState(server.Name, types.ContainerStateRestarting)
+	delay := sm.restartBackoff
+	for attempt := 1; attempt <= containerRestartAttempts; attempt++ {
+		log.Printf(
+			"[SessionManager] The container of server '%s' exited, restarting it in %v (attempt %d of %d)",
+			server.Name, delay, attempt, containerRestartAttempts,
+		)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		restarted, err := sm.acquireSession(ctx, server, false)
+		if err == nil {
+			sm.releaseSession(restarted, false)
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("[SessionManager] Failed to restart the session of server '%s': %v", server.Name, err)
+		sm.containers.setState(server.Name, types.ContainerStateRestarting)
+		delay *= 2
+	}
+	log.Printf("[SessionManager] Gave up restarting the session of server '%s', the next call starts it", server.Name)
+	sm.containers.setState(server.Name, types.ContainerStateExited)
+}
@@ -404,3 +404,72 @@ func TestSessionResult_InvalidateOnError(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionManager_ConcurrentStartsSpawnOnce(t *testing.T) {
+	sm := NewSessionManager(&SessionManagerConfig{IdleTimeoutSec: 3600, InitReqTimeoutSec: 10})
+	defer sm.Shutdown()
+
+	var starts int
+	release := make(chan struct{})
+	sm.createSessionFunc = func(ctx context.Context, s *model.McpServer, initReqTimeoutSec int) (*client.Client, error) {
+		starts++
+		<-release
+		return (*client.Client)(nil), nil
+	}
+	server := &model.McpServer{Name: "heavy", Transport: types.TransportStdio, LazyStart: true}
+	assert.Equal(t, types.ServerStateStopped, sm.State("heavy"))
+
+	var wg sync.WaitGroup
+	sessions := make([]*ManagedSession, 5)
+	for i := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			session, err := sm.acquireSession(context.Background(), server, true)
+			assert.NoError(t, err)
+			sessions[i] = session
+		}()
+	}
+	require.Eventually(t, func() bool { return sm.State("heavy") == types.ServerStateStarting }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, 1, starts, "concurrent first calls should start a single session")
+	assert.Equal(t, types.ServerStateRunning, sm.State("heavy"))
+	for _, session := range sessions {
+		assert.Same(t, sessions[0], session)
+	}
+	assert.Equal(t, 5, sessions[0].inUse)
+}
+
+func TestSessionManager_IdleSessionsInUseAreKept(t *testing.T) {
+	sm := NewSessionManager(&SessionManagerConfig{IdleTimeoutSec: 1, InitReqTimeoutSec: 10})
+	defer sm.Shutdown()
+	sm.createSessionFunc = func(ctx context.Context, s *model.McpServer, initReqTimeoutSec int) (*client.Client, error) {
+		return (*client.Client)(nil), nil
+	}
+	server := &model.McpServer{Name: "heavy", Transport: types.TransportStdio, LazyStart: true}
+
+	session, err := sm.acquireSession(context.Background(), server, true)
+	require.NoError(t, err)
+	session.LastUsedAt = time.Now().Add(-2 * time.Second)
+
+	// a long tool call is still using the session
+	sm.cleanupIdleSessions()
+	assert.Equal(t, types.ServerStateRunning, sm.State("heavy"))
+
+	// the idle timeout starts when the call completes
+	sm.releaseSession(session, true)
+	sm.cleanupIdleSessions()
+	assert.Equal(t, types.ServerStateRunning, sm.State("heavy"))
+
+	// a health check doesn't count as a use
+	session, err = sm.acquireSession(context.Background(), server, false)
+	require.NoError(t, err)
+	sm.releaseSession(session, false)
+	session.LastUsedAt = time.Now().Add(-2 * time.Second)
+	version := sm.StateVersion()
+	sm.cleanupIdleSessions()
+	assert.Equal(t, types.ServerStateStopped, sm.State("heavy"))
+	assert.True(t, sm.StateVersion() > version, "stopping the session should change the state version")
+}
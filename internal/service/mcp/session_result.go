@@ -9,7 +9,6 @@ import (
 
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mcpjungle/mcpjungle/internal/model"
-	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
 // connectionErrorPatterns contains common error substrings that indicate a connection problem.
@@ -40,13 +39,22 @@ type sessionResult struct {
 	// For stateful sessions, these are used for reactive invalidation on errors
 	serverName     string
 	sessionManager *SessionManager
+
+	// session is the persistent session held in use, released by closeIfApplicable
+	session *ManagedSession
+	// use tells whether the call counts as a use of the persistent session, see SessionManager.acquireSession
+	use bool
 }
 
 // closeIfApplicable closes the session if it should be closed (stateless mode).
+// A persistent session is released instead, so that its idle timeout starts.
 func (sr *sessionResult) closeIfApplicable() {
 	if sr.shouldClose && sr.client != nil {
 		sr.client.Close()
 	}
+	if !sr.shouldClose && sr.session != nil && sr.sessionManager != nil {
+		sr.sessionManager.releaseSession(sr.session, sr.use)
+	}
 }
 
 // invalidateOnError checks if the error indicates a connection problem and
@@ -64,25 +72,38 @@ func (sr *sessionResult) invalidateOnError(err error) {
 }
 
 // getSession returns a session for the given MCP server.
-// For stateful servers, it returns a persistent session from the SessionManager.
+// For servers with a persistent session (stateful or started lazily), it returns the session from the SessionManager,
+// starting it if needed.
 // For stateless servers, it creates a new session that should be closed after use.
 func (m *MCPService) getSession(ctx context.Context, server *model.McpServer) (*sessionResult, error) {
-	if server.SessionMode == types.SessionModeStateful {
+	return m.openSession(ctx, server, true)
+}
+
+// openSession is like getSession, but if use is false the call doesn't count as a use of a persistent session,
+// so that it doesn't keep an otherwise idle server running.
+func (m *MCPService) openSession(ctx context.Context, server *model.McpServer, use bool) (*sessionResult, error) {
+	if server.HasPersistentSession() {
 		// Use the session manager for stateful sessions
-		mcpClient, err := m.sessionManager.GetOrCreateSession(ctx, server)
+		session, err := m.sessionManager.acquireSession(ctx, server, use)
 		if err != nil {
 			return nil, err
 		}
 		return &sessionResult{
-			client:         mcpClient,
+			client:         session.Client,
 			shouldClose:    false, // Don't close stateful sessions after each call
 			serverName:     server.Name,
 			sessionManager: m.sessionManager,
+			session:        session,
+			use:            use,
 		}, nil
 	}
 
 	// Default: stateless mode - create a new session for each call
-	mcpClient, err := newMcpServerSession(ctx, server, m.mcpServerInitReqTimeoutSec)
+	mcpClient, err := newMcpServerSession(
+		ctx, server, m.mcpServerInitReqTimeoutSec, m.sessionManager.httpPools,
+		m.sessionManager.secrets, m.sessionManager.containers, m.sessionManager.packages,
+		m.sessionManager.maxResultBytes.Load,
+	)
 	if err != nil {
 		return nil, err
 	}
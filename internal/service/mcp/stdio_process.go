@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stdioExitTimeout is how long a stdio server is given to exit once its stdin is closed,
+// before its process tree is killed.
+const stdioExitTimeout = 5 * time.Second
+
+// stdioProcess is the process of a stdio MCP server, started by mcpjungle with pipes to its standard streams.
+// Writing to it writes to the stdin of the server, closing it stops the server along with all the processes it
+// started, eg- the node process started by the npx shim, so that none of them is left running.
+type stdioProcess struct {
+	cmd  *exec.Cmd
+	tree *processTree
+
+	stdin  *os.File
+	stdout *os.File
+	stderr *os.File
+
+	// exited is closed once the server process exited
+	exited    chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// startStdioProcess starts the stdio server command with the given arguments,
+// env holds the KEY=VALUE variables set on top of the environment of mcpjungle.
+// The command is looked up in the PATH, using the extensions of PATHEXT on Windows, eg- npx runs npx.cmd.
+func startStdioProcess(command string, args, env []string) (*stdioProcess, error) {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return nil, err
+	}
+	cmd := newStdioCommand(path, args)
+	cmd.Env = append(os.Environ(), env...)
+
+	// the pipes are passed as files, so that the server writes to them directly
+	// and Wait doesn't close them before all their output is read
+	var files []*os.File
+	closeFiles := func(files ...*os.File) {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}
+	pipe := func() (*os.File, *os.File, error) {
+		r, w, err := os.Pipe()
+		if err != nil {
+			closeFiles(files...)
+			return nil, nil, fmt.Errorf("failed to create pipe: %w", err)
+		}
+		files = append(files, r, w)
+		return r, w, nil
+	}
+	stdinR, stdinW, err := pipe()
+	if err != nil {
+		return nil, err
+	}
+	stdoutR, stdoutW, err := pipe()
+	if err != nil {
+		return nil, err
+	}
+	stderrR, stderrW, err := pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdinR, stdoutW, stderrW
+
+	err = cmd.Start()
+	// the server has its own copies of its ends of the pipes
+	closeFiles(stdinR, stdoutW, stderrW)
+	if err != nil {
+		closeFiles(stdinW, stdoutR, stderrR)
+		return nil, err
+	}
+	p := &stdioProcess{cmd: cmd, stdin: stdinW, stdout: stdoutR, stderr: stderrR, exited: make(chan struct{})}
+
+	p.tree, err = newProcessTree(cmd.Process)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		closeFiles(stdinW, stdoutR, stderrR)
+		return nil, fmt.Errorf("failed to track the processes of the server: %w", err)
+	}
+	go func() {
+		_ = cmd.Wait()
+		close(p.exited)
+	}()
+	return p, nil
+}
+
+// Stdout returns the stdout of the server. It is closed once it has been read entirely.
+func (p *stdioProcess) Stdout() io.Reader {
+	return &closeOnEOF{p.stdout}
+}
+
+// Stderr returns the stderr of the server.
+func (p *stdioProcess) Stderr() io.ReadCloser {
+	return p.stderr
+}
+
+func (p *stdioProcess) Write(b []byte) (int, error) {
+	return p.stdin.Write(b)
+}
+
+// Close closes the stdin of the server, which tells it to exit, and kills its process tree once it exited
+// or stdioExitTimeout passed. It returns once the server process exited.
+func (p *stdioProcess) Close() error {
+	p.closeOnce.Do(func() {
+		_ = p.stdin.Close()
+		select {
+		case <-p.exited:
+		case <-time.After(stdioExitTimeout):
+		}
+		// the processes started by the server outlive it otherwise
+		if err := p.tree.kill(); err != nil {
+			p.closeErr = fmt.Errorf("failed to stop the processes of the stdio server: %w", err)
+		}
+		<-p.exited
+		p.tree.release()
+	})
+	return p.closeErr
+}
+
+// closeOnEOF closes a file once it has been read to its end.
+type closeOnEOF struct {
+	f *os.File
+}
+
+func (r *closeOnEOF) Read(b []byte) (int, error) {
+	n, err := r.f.Read(b)
+	if errors.Is(err, io.EOF) {
+		_ = r.f.Close()
+	}
+	return n, err
+}
+
+// windowsBatchCommandLine returns the command line that runs a Windows batch file, eg- npx.cmd, through cmd.exe.
+// cmd.exe parses the command line of batch files itself, so the arguments are quoted like for any other program
+// and the characters that mean something to cmd.exe are escaped with ^, so that they are passed to the script as is.
+func windowsBatchCommandLine(comspec, script string, args []string) string {
+	var b strings.Builder
+	b.WriteString(quoteWindowsArg(comspec))
+	b.WriteString(` /d /s /c "`)
+	b.WriteString(escapeCmdMetaChars(quoteWindowsArg(script)))
+	for _, arg := range args {
+		b.WriteByte(' ')
+		b.WriteString(escapeCmdMetaChars(quoteWindowsArg(arg)))
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// quoteWindowsArg quotes an argument of a Windows command line, so that CommandLineToArgvW parses it back as is.
+func quoteWindowsArg(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\v\"") {
+		return arg
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	backslashes := 0
+	for _, c := range arg {
+		switch c {
+		case '\\':
+			backslashes++
+			continue
+		case '"':
+			// the backslashes before a quote are escaped, and so is the quote
+			b.WriteString(strings.Repeat(`\`, 2*backslashes+1))
+		default:
+			b.WriteString(strings.Repeat(`\`, backslashes))
+		}
+		backslashes = 0
+		b.WriteRune(c)
+	}
+	// the backslashes before the closing quote are escaped
+	b.WriteString(strings.Repeat(`\`, 2*backslashes))
+	b.WriteByte('"')
+	return b.String()
+}
+
+// escapeCmdMetaChars escapes the characters cmd.exe interprets, eg- & or %, with ^.
+func escapeCmdMetaChars(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if strings.ContainsRune(`()%!^"<>&|`, c) {
+			b.WriteByte('^')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
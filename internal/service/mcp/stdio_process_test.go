@@ -0,0 +1,35 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func TestQuoteWindowsArg(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"-y", "-y"},
+		{"", `""`},
+		{`C:\Program Files\nodejs`, `"C:\Program Files\nodejs"`},
+		{`C:\data\`, `C:\data\`},
+		{`C:\my data\`, `"C:\my data\\"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`a\"b`, `"a\\\"b"`},
+	}
+	for _, tt := range tests {
+		testhelpers.AssertEqual(t, tt.want, quoteWindowsArg(tt.arg))
+	}
+}
+
+func TestWindowsBatchCommandLine(t *testing.T) {
+	got := windowsBatchCommandLine(
+		`C:\Windows\System32\cmd.exe`, `C:\Program Files\nodejs\npx.cmd`,
+		[]string{"-y", "@modelcontextprotocol/server-filesystem", `C:\Users\me\My Documents`, "a&b|c", "100%"},
+	)
+	want := `C:\Windows\System32\cmd.exe /d /s /c "^"C:\Program Files\nodejs\npx.cmd^" -y ` +
+		`@modelcontextprotocol/server-filesystem ^"C:\Users\me\My Documents^" a^&b^|c 100^%"`
+	testhelpers.AssertEqual(t, want, got)
+}
@@ -0,0 +1,37 @@
+//go:build !windows
+
+package mcp
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// newStdioCommand returns the command that runs the executable at path with the given arguments,
+// in a process group of its own so that the processes it starts can be killed along with it.
+func newStdioCommand(path string, args []string) *exec.Cmd {
+	cmd := exec.Command(path, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return cmd
+}
+
+// processTree is the process group of a stdio server.
+type processTree struct {
+	pgid int
+}
+
+func newProcessTree(p *os.Process) (*processTree, error) {
+	return &processTree{pgid: p.Pid}, nil
+}
+
+// kill kills all the processes of the group that are still running.
+func (t *processTree) kill() error {
+	if err := syscall.Kill(-t.pgid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	return nil
+}
+
+func (t *processTree) release() {}
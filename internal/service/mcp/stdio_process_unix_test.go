@@ -0,0 +1,51 @@
+//go:build !windows
+
+package mcp
+
+import (
+	"bufio"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func TestStdioProcessCloseKillsProcessTree(t *testing.T) {
+	// the background sleep keeps the stdout of the server open for as long as it runs
+	p, err := startStdioProcess("sh", []string{"-c", "sleep 300 & echo started; cat"}, nil)
+	testhelpers.AssertNoError(t, err)
+	stdout := bufio.NewReader(p.Stdout())
+	line, err := stdout.ReadString('\n')
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "started\n", line)
+
+	testhelpers.AssertNoError(t, p.Close())
+	read := make(chan error, 1)
+	go func() {
+		_, err := io.ReadAll(stdout)
+		read <- err
+	}()
+	select {
+	case err := <-read:
+		testhelpers.AssertNoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the processes started by the server to be killed")
+	}
+	// closing it again is a no-op
+	testhelpers.AssertNoError(t, p.Close())
+}
+
+func TestStdioProcessEnv(t *testing.T) {
+	p, err := startStdioProcess("sh", []string{"-c", `echo "$GREETING"`}, []string{"GREETING=hello"})
+	testhelpers.AssertNoError(t, err)
+	defer p.Close()
+	out, err := io.ReadAll(p.Stdout())
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "hello\n", string(out))
+}
+
+func TestStartStdioProcessUnknownCommand(t *testing.T) {
+	_, err := startStdioProcess("mcpjungle-no-such-command", nil, nil)
+	testhelpers.AssertError(t, err)
+}
@@ -0,0 +1,80 @@
+//go:build windows
+
+package mcp
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// newStdioCommand returns the command that runs the executable at path with the given arguments, without a console
+// window. Batch files, like the npx.cmd and uvx.cmd shims, are run through cmd.exe with their arguments escaped for it.
+func newStdioCommand(path string, args []string) *exec.Cmd {
+	attr := &syscall.SysProcAttr{CreationFlags: windows.CREATE_NO_WINDOW}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".cmd" && ext != ".bat" {
+		cmd := exec.Command(path, args...)
+		cmd.SysProcAttr = attr
+		return cmd
+	}
+
+	comspec := os.Getenv("ComSpec")
+	if comspec == "" {
+		comspec = filepath.Join(os.Getenv("SystemRoot"), "System32", "cmd.exe")
+	}
+	cmd := exec.Command(comspec)
+	attr.CmdLine = windowsBatchCommandLine(comspec, path, args)
+	cmd.SysProcAttr = attr
+	return cmd
+}
+
+// processTree is the job object holding the process of a stdio server and all the processes it starts.
+// The job kills them when it is closed, so that none of them outlives mcpjungle either.
+type processTree struct {
+	job windows.Handle
+}
+
+func newProcessTree(p *os.Process) (*processTree, error) {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+			LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+		},
+	}
+	if _, err := windows.SetInformationJobObject(
+		job, windows.JobObjectExtendedLimitInformation, uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info)),
+	); err != nil {
+		_ = windows.CloseHandle(job)
+		return nil, err
+	}
+
+	h, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(p.Pid))
+	if err != nil {
+		_ = windows.CloseHandle(job)
+		return nil, err
+	}
+	defer windows.CloseHandle(h)
+	if err := windows.AssignProcessToJobObject(job, h); err != nil {
+		_ = windows.CloseHandle(job)
+		return nil, err
+	}
+	return &processTree{job: job}, nil
+}
+
+// kill kills all the processes of the job that are still running.
+func (t *processTree) kill() error {
+	return windows.TerminateJobObject(t.job, 1)
+}
+
+func (t *processTree) release() {
+	_ = windows.CloseHandle(t.job)
+}
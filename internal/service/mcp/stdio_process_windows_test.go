@@ -0,0 +1,58 @@
+//go:build windows
+
+package mcp
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+)
+
+func TestStartStdioProcessRunsBatchShims(t *testing.T) {
+	// like npx, the command is a .cmd shim found through PATH and PATHEXT
+	dir := t.TempDir()
+	script := "@echo off\r\necho %~1\r\necho %~2\r\n"
+	testhelpers.AssertNoError(t, os.WriteFile(filepath.Join(dir, "echo-args.cmd"), []byte(script), 0o644))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("PATHEXT", ".COM;.EXE;.BAT;.CMD")
+
+	p, err := startStdioProcess("echo-args", []string{`C:\My Documents\file.txt`, "a&b"}, nil)
+	testhelpers.AssertNoError(t, err)
+	defer p.Close()
+	out, err := io.ReadAll(p.Stdout())
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "C:\\My Documents\\file.txt\r\na&b\r\n", string(out))
+}
+
+func TestStdioProcessCloseKillsProcessTree(t *testing.T) {
+	// the background ping keeps the stdout of the server open for as long as it runs
+	p, err := startStdioProcess("cmd.exe", []string{"/c", "start /b ping -n 300 127.0.0.1 & echo started& more"}, nil)
+	testhelpers.AssertNoError(t, err)
+	stdout := bufio.NewReader(p.Stdout())
+	for {
+		line, err := stdout.ReadString('\n')
+		testhelpers.AssertNoError(t, err)
+		if strings.HasPrefix(line, "started") {
+			break
+		}
+	}
+
+	testhelpers.AssertNoError(t, p.Close())
+	read := make(chan error, 1)
+	go func() {
+		_, err := io.ReadAll(stdout)
+		read <- err
+	}()
+	select {
+	case err := <-read:
+		testhelpers.AssertNoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the processes started by the server to be killed")
+	}
+}
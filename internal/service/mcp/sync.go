@@ -0,0 +1,322 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// DefaultSyncConcurrency is the default number of MCP servers synchronized at the same time.
+const DefaultSyncConcurrency = 8
+
+// serverSync holds the changes made to the DB by the synchronization of an MCP server,
+// to apply them to the MCP proxy server once they are committed.
+type serverSync struct {
+	result types.ServerSyncResult
+
+	// publishedTools and publishedPrompts are the enabled tools and prompts that were added or updated
+	publishedTools   []mcp.Tool
+	publishedPrompts []mcp.Prompt
+}
+
+// SyncServers synchronizes the tools and prompts of the MCP servers with the given names, all registered servers
+// if names is empty. The servers are synchronized concurrently, at most ServiceConfig.SyncConcurrency at a time,
+// and a server that fails doesn't affect the others: its tools and prompts are left as they are.
+//
+// Unless startLazy is true, the lazily started servers that are not running are skipped rather than started.
+// A name that is not registered fails the whole call with gorm.ErrRecordNotFound before any server is synchronized.
+func (m *MCPService) SyncServers(ctx context.Context, names []string, startLazy bool) (*types.SyncServersResult, error) {
+	m.syncRound.Lock()
+	defer m.syncRound.Unlock()
+
+	servers, err := m.serversToSync(names)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	results := make([]types.ServerSyncResult, len(servers))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, m.syncConcurrency)
+	for i := range servers {
+		if !startLazy && servers[i].LazyStart && m.sessionManager.State(servers[i].Name) != types.ServerStateRunning {
+			results[i] = types.ServerSyncResult{Name: servers[i].Name, Status: types.ServerSyncSkipped}
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = m.syncServer(ctx, &servers[i])
+			m.recordSync(results[i])
+		}()
+	}
+	wg.Wait()
+
+	result := &types.SyncServersResult{DurationMs: time.Since(start).Milliseconds(), Servers: results}
+	skipped := 0
+	for _, r := range results {
+		switch r.Status {
+		case types.ServerSynced:
+			result.Synced++
+		case types.ServerSyncFailed:
+			result.Failed++
+			log.Printf("[WARN] failed to synchronize MCP server %s: %s", r.Name, r.Error)
+		case types.ServerSyncSkipped:
+			skipped++
+		}
+	}
+	sort.Slice(result.Servers, func(i, j int) bool { return result.Servers[i].Name < result.Servers[j].Name })
+
+	summary := fmt.Sprintf("synced %d MCP servers, %d failed, in %s", result.Synced, result.Failed, time.Since(start).Round(time.Millisecond))
+	if skipped > 0 {
+		summary += fmt.Sprintf(" (%d lazily started servers skipped)", skipped)
+	}
+	log.Printf("[sync] %s", summary)
+	return result, nil
+}
+
+// serversToSync returns the servers with the given names, all registered servers if names is empty.
+func (m *MCPService) serversToSync(names []string) ([]model.McpServer, error) {
+	if len(names) == 0 {
+		return m.ListMcpServers()
+	}
+	byName, err := m.GetMcpServersByName(names)
+	if err != nil {
+		return nil, err
+	}
+	servers := make([]model.McpServer, 0, len(byName))
+	for _, name := range slices.Compact(slices.Sorted(slices.Values(names))) {
+		s, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("failed to get MCP server %s from DB: %w", name, gorm.ErrRecordNotFound)
+		}
+		servers = append(servers, *s)
+	}
+	return servers, nil
+}
+
+// syncServer fetches the tools and prompts of s, within the timeout of initialization requests, and updates those
+// stored in the DB to match them in a single transaction. The MCP proxy server is updated once it is committed.
+// Disabled tools and prompts that still exist remain disabled.
+// Like when a server is registered, prompts are fetched on a best-effort basis: if they cannot be listed,
+// they are left as they are and only the tools are synchronized.
+func (m *MCPService) syncServer(ctx context.Context, s *model.McpServer) types.ServerSyncResult {
+	start := time.Now()
+	changes, err := m.fetchAndStoreServerEntities(ctx, s)
+	if err != nil {
+		return types.ServerSyncResult{
+			Name:       s.Name,
+			Status:     types.ServerSyncFailed,
+			DurationMs: time.Since(start).Milliseconds(),
+			Error:      err.Error(),
+		}
+	}
+
+	proxy := m.mcpProxyServer
+	if s.Transport == types.TransportSSE {
+		proxy = m.sseMcpProxyServer
+	}
+	if removed := changes.result.ToolsRemoved; len(removed) > 0 {
+		proxy.DeleteTools(removed...)
+		m.deleteToolInstances(removed...)
+		m.notifyToolDeletion(removed...)
+	}
+	for _, tool := range changes.publishedTools {
+		m.publishTool(s, tool)
+	}
+	if removed := changes.result.PromptsRemoved; len(removed) > 0 {
+		proxy.DeletePrompts(removed...)
+	}
+	for _, prompt := range changes.publishedPrompts {
+		m.publishPrompt(s, prompt)
+	}
+
+	changes.result.DurationMs = time.Since(start).Milliseconds()
+	return changes.result
+}
+
+// fetchAndStoreServerEntities does the part of syncServer that fetches the tools and prompts of s and stores them.
+func (m *MCPService) fetchAndStoreServerEntities(ctx context.Context, s *model.McpServer) (*serverSync, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(m.mcpServerInitReqTimeoutSec)*time.Second)
+	defer cancel()
+
+	// the sync doesn't count as a use of the persistent session of s, so it doesn't keep it from being idle
+	session, err := m.openSession(ctx, s, false)
+	if err != nil {
+		return nil, err
+	}
+	defer session.closeIfApplicable()
+
+	tools, err := session.client.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		session.invalidateOnError(err)
+		return nil, fmt.Errorf("failed to fetch tools from MCP server %s: %w", s.Name, err)
+	}
+	var prompts []mcp.Prompt
+	promptsResp, err := session.client.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		log.Printf("[WARN] failed to fetch prompts from MCP server %s, only its tools are synchronized: %v", s.Name, err)
+	} else {
+		prompts = promptsResp.Prompts
+	}
+
+	changes := &serverSync{result: types.ServerSyncResult{Name: s.Name, Status: types.ServerSynced}}
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		if err := syncServerTools(tx, s, tools.Tools, changes); err != nil {
+			return err
+		}
+		if promptsResp == nil {
+			return nil
+		}
+		return syncServerPrompts(tx, s, prompts, changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// syncServerTools updates the tools of s stored in the DB to match those fetched from the server, in tx.
+func syncServerTools(tx *gorm.DB, s *model.McpServer, tools []mcp.Tool, changes *serverSync) error {
+	var records []model.Tool
+	if err := tx.Where("server_id = ?", s.ID).Find(&records).Error; err != nil {
+		return fmt.Errorf("failed to list tools for server %s: %w", s.Name, err)
+	}
+	existing := make(map[string]*model.Tool, len(records))
+	for i := range records {
+		existing[records[i].Name] = &records[i]
+	}
+
+	var added []*model.Tool
+	for _, tool := range uniqueTools(s, tools) {
+		record := newToolModel(s, tool)
+		canonicalName := mergeServerToolNames(s.Name, tool.GetName())
+		current, ok := existing[record.Name]
+		if !ok {
+			added = append(added, record)
+			changes.result.ToolsAdded = append(changes.result.ToolsAdded, canonicalName)
+			changes.publishedTools = append(changes.publishedTools, tool)
+			continue
+		}
+		delete(existing, record.Name)
+		if current.Description == record.Description &&
+			jsonEqual(current.InputSchema, record.InputSchema) && jsonEqual(current.Annotations, record.Annotations) {
+			continue
+		}
+		err := tx.Model(current).Updates(map[string]any{
+			"description":  record.Description,
+			"input_schema": record.InputSchema,
+			"annotations":  record.Annotations,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update tool %s in DB: %w", canonicalName, err)
+		}
+		changes.result.ToolsUpdated = append(changes.result.ToolsUpdated, canonicalName)
+		if current.Enabled {
+			changes.publishedTools = append(changes.publishedTools, tool)
+		}
+	}
+
+	if len(added) > 0 {
+		if err := tx.CreateInBatches(added, dbBatchSize).Error; err != nil {
+			return fmt.Errorf("failed to register the new tools of server %s in DB: %w", s.Name, err)
+		}
+	}
+
+	if len(existing) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(existing))
+	for name, record := range existing {
+		ids = append(ids, record.ID)
+		changes.result.ToolsRemoved = append(changes.result.ToolsRemoved, mergeServerToolNames(s.Name, name))
+	}
+	sort.Strings(changes.result.ToolsRemoved)
+	if err := tx.Unscoped().Delete(&model.Tool{}, ids).Error; err != nil {
+		return fmt.Errorf("failed to delete the removed tools of server %s: %w", s.Name, err)
+	}
+	return nil
+}
+
+// syncServerPrompts updates the prompts of s stored in the DB to match those fetched from the server, in tx.
+func syncServerPrompts(tx *gorm.DB, s *model.McpServer, prompts []mcp.Prompt, changes *serverSync) error {
+	var records []model.Prompt
+	if err := tx.Where("server_id = ?", s.ID).Find(&records).Error; err != nil {
+		return fmt.Errorf("failed to list prompts for server %s: %w", s.Name, err)
+	}
+	existing := make(map[string]*model.Prompt, len(records))
+	for i := range records {
+		existing[records[i].Name] = &records[i]
+	}
+
+	var added []*model.Prompt
+	for _, prompt := range uniquePrompts(s, prompts) {
+		record := newPromptModel(s, prompt)
+		canonicalName := mergeServerPromptNames(s.Name, prompt.GetName())
+		current, ok := existing[record.Name]
+		if !ok {
+			added = append(added, record)
+			changes.result.PromptsAdded = append(changes.result.PromptsAdded, canonicalName)
+			changes.publishedPrompts = append(changes.publishedPrompts, prompt)
+			continue
+		}
+		delete(existing, record.Name)
+		if current.Description == record.Description && jsonEqual(current.Arguments, record.Arguments) {
+			continue
+		}
+		err := tx.Model(current).Updates(map[string]any{
+			"description": record.Description,
+			"arguments":   record.Arguments,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update prompt %s in DB: %w", canonicalName, err)
+		}
+		changes.result.PromptsUpdated = append(changes.result.PromptsUpdated, canonicalName)
+		if current.Enabled {
+			changes.publishedPrompts = append(changes.publishedPrompts, prompt)
+		}
+	}
+
+	if len(added) > 0 {
+		if err := tx.CreateInBatches(added, dbBatchSize).Error; err != nil {
+			return fmt.Errorf("failed to register the new prompts of server %s in DB: %w", s.Name, err)
+		}
+	}
+
+	if len(existing) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(existing))
+	for name, record := range existing {
+		ids = append(ids, record.ID)
+		changes.result.PromptsRemoved = append(changes.result.PromptsRemoved, mergeServerPromptNames(s.Name, name))
+	}
+	sort.Strings(changes.result.PromptsRemoved)
+	if err := tx.Unscoped().Delete(&model.Prompt{}, ids).Error; err != nil {
+		return fmt.Errorf("failed to delete the removed prompts of server %s: %w", s.Name, err)
+	}
+	return nil
+}
+
+// jsonEqual reports whether two JSON documents hold the same value.
+// The DB may re-encode the JSON it stores, so the stored documents cannot be compared byte for byte.
+func jsonEqual(a, b []byte) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return string(a) == string(b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,300 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+func newSyncTestService(t *testing.T) *MCPService {
+	t.Helper()
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(&model.McpServer{}, &model.Tool{}, &model.Prompt{}, &model.ServerHealthCheck{}))
+	mcpService, err := NewMCPService(&ServiceConfig{
+		DB:                      db,
+		McpProxyServer:          server.NewMCPServer("proxy", "0.0.0"),
+		SseMcpProxyServer:       server.NewMCPServer("sse-proxy", "0.0.0"),
+		Metrics:                 telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout: 1,
+		SyncConcurrency:         2,
+	})
+	testhelpers.AssertNoError(t, err)
+	t.Cleanup(mcpService.Shutdown)
+	return mcpService
+}
+
+func noopToolHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText("ok"), nil
+}
+
+func TestSyncServers(t *testing.T) {
+	m := newSyncTestService(t)
+	upstream := server.NewMCPServer("github", "0.0.0")
+	upstream.AddTool(mcp.NewTool("git_commit", mcp.WithDescription("Commit")), noopToolHandler)
+	upstream.AddTool(mcp.NewTool("git_push", mcp.WithDescription("Push")), noopToolHandler)
+	upstreamServer := server.NewTestStreamableHTTPServer(upstream)
+	defer upstreamServer.Close()
+
+	github, err := model.NewStreamableHTTPServer("github", "", upstreamServer.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, m.RegisterMcpServer(context.Background(), github))
+	_, err = m.DisableTools("github__git_push")
+	testhelpers.AssertNoError(t, err)
+	for _, s := range newUnreachableServers(t, "slack") {
+		testhelpers.AssertNoError(t, m.db.Create(s).Error)
+	}
+
+	// the tools of the server changed since it was registered
+	upstream.DeleteTools("git_commit")
+	upstream.AddTool(mcp.NewTool("git_push", mcp.WithDescription("Push the commits")), noopToolHandler)
+	upstream.AddTool(mcp.NewTool("git_pull", mcp.WithDescription("Pull")), noopToolHandler)
+
+	result, err := m.SyncServers(context.Background(), nil, true)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, result.Synced)
+	testhelpers.AssertEqual(t, 1, result.Failed)
+	testhelpers.AssertEqual(t, 2, len(result.Servers))
+
+	synced, slack := result.Servers[0], result.Servers[1]
+	testhelpers.AssertEqual(t, types.ServerSynced, synced.Status)
+	testhelpers.AssertEqual(t, "github__git_pull", strings.Join(synced.ToolsAdded, ","))
+	testhelpers.AssertEqual(t, "github__git_push", strings.Join(synced.ToolsUpdated, ","))
+	testhelpers.AssertEqual(t, "github__git_commit", strings.Join(synced.ToolsRemoved, ","))
+	testhelpers.AssertEqual(t, types.ServerSyncFailed, slack.Status)
+	testhelpers.AssertTrue(t, slack.Error != "", "the reason the server failed should be reported")
+
+	// the disabled tool was updated but remains disabled, and out of the proxy
+	push, err := m.GetTool("github__git_push")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertFalse(t, push.Enabled, "the disabled tool should remain disabled")
+	testhelpers.AssertEqual(t, "Push the commits", push.Description)
+	_, ok := m.GetToolInstance("github__git_push")
+	testhelpers.AssertFalse(t, ok, "the disabled tool should not be published")
+	_, ok = m.GetToolInstance("github__git_pull")
+	testhelpers.AssertTrue(t, ok, "the new tool should be published")
+	_, ok = m.GetToolInstance("github__git_commit")
+	testhelpers.AssertFalse(t, ok, "the removed tool should be removed from the proxy")
+
+	// nothing changes when the server is synchronized again
+	result, err = m.SyncServers(context.Background(), []string{"github"}, true)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(result.Servers))
+	testhelpers.AssertEqual(t, 0, len(result.Servers[0].ToolsAdded)+len(result.Servers[0].ToolsUpdated)+len(result.Servers[0].ToolsRemoved))
+
+	_, err = m.SyncServers(context.Background(), []string{"github", "notion"}, true)
+	testhelpers.AssertTrue(t, errors.Is(err, gorm.ErrRecordNotFound), "an unknown server should fail the sync")
+}
+
+func TestSyncServersSkipsStoppedLazyServers(t *testing.T) {
+	m := newSyncTestService(t)
+	heavyServer := server.NewMCPServer("heavy", "0.0.0")
+	heavyServer.AddTool(mcp.NewTool("index"), noopToolHandler)
+	upstream := server.NewTestStreamableHTTPServer(heavyServer)
+	defer upstream.Close()
+
+	heavy, err := model.NewStreamableHTTPServer("heavy", "", upstream.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	heavy.LazyStart = true
+	testhelpers.AssertNoError(t, m.db.Create(heavy).Error)
+
+	result, err := m.SyncServers(context.Background(), nil, false)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, types.ServerSyncSkipped, result.Servers[0].Status)
+	testhelpers.AssertEqual(t, types.ServerStateStopped, m.ServerState(heavy))
+
+	result, err = m.SyncServers(context.Background(), nil, true)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, types.ServerSynced, result.Servers[0].Status)
+	testhelpers.AssertEqual(t, "heavy__index", strings.Join(result.Servers[0].ToolsAdded, ","))
+}
+
+func TestWarmUp(t *testing.T) {
+	m := newSyncTestService(t)
+	upstream := server.NewMCPServer("github", "0.0.0")
+	upstream.AddTool(mcp.NewTool("git_commit"), noopToolHandler)
+	upstreamServer := server.NewTestStreamableHTTPServer(upstream)
+	defer upstreamServer.Close()
+
+	github, err := model.NewStreamableHTTPServer("github", "", upstreamServer.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, m.RegisterMcpServer(context.Background(), github))
+	for _, s := range newUnreachableServers(t, "slack") {
+		testhelpers.AssertNoError(t, m.db.Create(s).Error)
+	}
+	heavy, err := model.NewStreamableHTTPServer("heavy", "", upstreamServer.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	heavy.LazyStart = true
+	testhelpers.AssertNoError(t, m.db.Create(heavy).Error)
+
+	// the lazily started server is not synchronized on startup, so it doesn't warm up
+	testhelpers.AssertNoError(t, m.WarmUp())
+	testhelpers.AssertEqual(t, "github,slack", strings.Join(m.WarmingUpServers(), ","))
+	_, err = m.InvokeTool(context.Background(), "github__git_commit", nil)
+	testhelpers.AssertTrue(t, errors.Is(err, ErrServerWarmingUp), "calls should fail until the server is synchronized")
+	ctx := context.WithValue(context.Background(), "mode", model.ModeDev)
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "github__git_commit"
+	_, err = m.MCPProxyToolCallHandler(ctx, req)
+	testhelpers.AssertTrue(t, errors.Is(err, ErrServerWarmingUp), "proxied calls should fail until the server is synchronized")
+
+	// the warm-up of a server ends once it is synchronized, even if the synchronization fails
+	_, err = m.SyncServers(context.Background(), nil, false)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 0, len(m.WarmingUpServers()))
+	_, err = m.InvokeTool(context.Background(), "github__git_commit", nil)
+	testhelpers.AssertNoError(t, err)
+
+	health, err := m.ServersHealth()
+	testhelpers.AssertNoError(t, err)
+	byName := make(map[string]types.ServerHealth)
+	for _, h := range health.Servers {
+		byName[h.Name] = h
+	}
+	testhelpers.AssertTrue(t, byName["github"].SyncedAt != nil && byName["github"].SyncError == "", "the sync of github should be reported")
+	testhelpers.AssertTrue(t, byName["slack"].SyncError != "", "the failed sync of slack should be reported")
+	testhelpers.AssertTrue(t, byName["heavy"].SyncedAt == nil, "the skipped server was never synchronized")
+
+	// the servers that weren't synchronized stop warming up once the synchronization is over
+	testhelpers.AssertNoError(t, m.WarmUp())
+	m.EndWarmUp()
+	testhelpers.AssertEqual(t, 0, len(m.WarmingUpServers()))
+}
+
+// newLargeUpstream returns an MCP server providing n tools.
+func newLargeUpstream(n int) *server.MCPServer {
+	upstream := server.NewMCPServer("large", "0.0.0")
+	for i := 0; i < n; i++ {
+		upstream.AddTool(mcp.NewTool(
+			fmt.Sprintf("tool_%03d", i),
+			mcp.WithDescription("A tool of a large server"),
+			mcp.WithString("query", mcp.Required(), mcp.Description("What to look for")),
+			mcp.WithNumber("limit", mcp.Description("The maximum number of results")),
+		), noopToolHandler)
+	}
+	return upstream
+}
+
+// countWrites counts the INSERT, UPDATE and DELETE statements run on db from now on.
+func countWrites(t *testing.T, db *gorm.DB) func() int {
+	t.Helper()
+	var count atomic.Int64
+	name := "mcp:count_writes:" + t.Name()
+	count1 := func(*gorm.DB) { count.Add(1) }
+	cb := db.Callback()
+	testhelpers.AssertNoError(t, cb.Create().After("gorm:create").Register(name, count1))
+	testhelpers.AssertNoError(t, cb.Update().After("gorm:update").Register(name, count1))
+	testhelpers.AssertNoError(t, cb.Delete().After("gorm:delete").Register(name, count1))
+	return func() int {
+		return int(count.Swap(0))
+	}
+}
+
+func TestRegistrationAndSyncBatchWrites(t *testing.T) {
+	m := newSyncTestService(t)
+	upstream := newLargeUpstream(300)
+	upstreamServer := server.NewTestStreamableHTTPServer(upstream)
+	defer upstreamServer.Close()
+
+	large, err := model.NewStreamableHTTPServer("large", "", upstreamServer.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	writes := countWrites(t, m.db)
+	testhelpers.AssertNoError(t, m.RegisterMcpServer(context.Background(), large))
+	// the server, then its tools in batches of dbBatchSize
+	testhelpers.AssertEqual(t, 1+300/dbBatchSize, writes())
+
+	tools, err := m.ListToolsByServer("large")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 300, len(tools))
+	_, ok := m.GetToolInstance("large__tool_299")
+	testhelpers.AssertTrue(t, ok, "all the tools should be published")
+
+	// the unchanged tools are not written again
+	result, err := m.SyncServers(context.Background(), []string{"large"}, true)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, types.ServerSynced, result.Servers[0].Status)
+	testhelpers.AssertEqual(t, 0, writes())
+
+	// only the changes are
+	upstream.DeleteTools("tool_000")
+	upstream.AddTool(mcp.NewTool("tool_001", mcp.WithDescription("Changed")), noopToolHandler)
+	for i := 300; i < 450; i++ {
+		upstream.AddTool(mcp.NewTool(fmt.Sprintf("tool_%03d", i)), noopToolHandler)
+	}
+	result, err = m.SyncServers(context.Background(), []string{"large"}, true)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 150, len(result.Servers[0].ToolsAdded))
+	testhelpers.AssertEqual(t, "large__tool_001", strings.Join(result.Servers[0].ToolsUpdated, ","))
+	testhelpers.AssertEqual(t, "large__tool_000", strings.Join(result.Servers[0].ToolsRemoved, ","))
+	// an update, a delete and the new tools in batches
+	testhelpers.AssertEqual(t, 1+1+2, writes())
+}
+
+func TestUniqueTools(t *testing.T) {
+	s := &model.McpServer{Name: "github"}
+	tools := uniqueTools(s, []mcp.Tool{
+		mcp.NewTool("git_commit", mcp.WithDescription("first")),
+		mcp.NewTool("git_push"),
+		mcp.NewTool("git_commit", mcp.WithDescription("second")),
+	})
+	testhelpers.AssertEqual(t, 2, len(tools))
+	testhelpers.AssertEqual(t, "first", tools[0].Description)
+	testhelpers.AssertEqual(t, "git_push", tools[1].Name)
+}
+
+func BenchmarkRegisterLargeServer(b *testing.B) {
+	const tools = 300
+	db, err := testhelpers.CreateTestDB()
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := db.AutoMigrate(&model.McpServer{}, &model.Tool{}, &model.Prompt{}); err != nil {
+		b.Fatal(err)
+	}
+	m, err := NewMCPService(&ServiceConfig{
+		DB:                      db,
+		McpProxyServer:          server.NewMCPServer("proxy", "0.0.0"),
+		SseMcpProxyServer:       server.NewMCPServer("sse-proxy", "0.0.0"),
+		Metrics:                 telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout: 1,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer m.Shutdown()
+	upstreamServer := server.NewTestStreamableHTTPServer(newLargeUpstream(tools))
+	defer upstreamServer.Close()
+
+	var inserts atomic.Int64
+	if err := db.Callback().Create().After("gorm:create").Register("bench:count_inserts", func(*gorm.DB) {
+		inserts.Add(1)
+	}); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s, err := model.NewStreamableHTTPServer(
+			fmt.Sprintf("large-%d", i), "", upstreamServer.URL+"/mcp", "", types.SessionModeStateless,
+		)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(inserts.Load())/float64(b.N), "inserts/op")
+}
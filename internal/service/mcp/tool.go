@@ -3,15 +3,21 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/db/dialect"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/internal/telemetry"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
 )
 
 // ToolDeletionCallback is a function type that can be registered to be called
@@ -29,43 +35,207 @@ type ToolAdditionCallback func(toolName string) error
 // For example, if a tool named "commit" is provided by a server named "git",
 // its name will be set to "git__commit".
 func (m *MCPService) ListTools() ([]model.Tool, error) {
+	return m.listTools(ToolFilter{})
+}
+
+// ListToolsByServer fetches tools provided by an MCP server from the registry.
+func (m *MCPService) ListToolsByServer(name string) ([]model.Tool, error) {
+	return m.listTools(ToolFilter{Server: name})
+}
+
+// listTools returns all the tools matching filter with their canonical names.
+// Unlike ListToolsPage, it doesn't count them.
+func (m *MCPService) listTools(filter ToolFilter) ([]model.Tool, error) {
+	query, err := m.filterTools(filter)
+	if err != nil {
+		return nil, err
+	}
 	var tools []model.Tool
-	if err := m.db.Find(&tools).Error; err != nil {
+	if err := query.Find(&tools).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tools from DB: %w", err)
+	}
+	if err := m.setCanonicalToolNames(tools); err != nil {
 		return nil, err
 	}
-	// prepend server name to tool names to ensure we only return the unique names of tools to user
+	return tools, nil
+}
+
+// ToolFilter narrows down the tools listed by ListToolsPage.
+// All the conditions that are set must match, the zero value matches every tool.
+type ToolFilter struct {
+	// Server only matches the tools of the MCP server with this name.
+	Server string
+	// Names only matches the tools with these canonical names, if not nil.
+	Names []string
+	// Enabled only matches the enabled or the disabled tools, if not nil.
+	Enabled *bool
+	// Query only matches the tools whose name or description contains it, ignoring case.
+	Query string
+}
+
+// InvalidFilterError is returned by ListToolsPage when a condition of the filter is invalid.
+type InvalidFilterError struct {
+	// Field is the name of the invalid condition, in lowercase (eg- server).
+	Field string
+	Err   error
+}
+
+func (e *InvalidFilterError) Error() string {
+	return fmt.Sprintf("invalid %s filter: %v", e.Field, e.Err)
+}
+
+func (e *InvalidFilterError) Unwrap() error { return e.Err }
+
+// ListToolsPage returns a page of the tools matching filter, with their canonical names,
+// along with the ID to list the next page after (0 if there are no more tools)
+// and the total number of matching tools across all pages.
+func (m *MCPService) ListToolsPage(filter ToolFilter, p model.Page) ([]model.Tool, uint, int64, error) {
+	query, err := m.filterTools(filter)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to count tools: %w", err)
+	}
+	var tools []model.Tool
+	if err := p.Apply(query).Find(&tools).Error; err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to get tools from DB: %w", err)
+	}
+	tools, next := model.NextPage(p, tools, func(t model.Tool) uint { return t.ID })
+	if err := m.setCanonicalToolNames(tools); err != nil {
+		return nil, 0, 0, err
+	}
+	return tools, next, total, nil
+}
+
+// setCanonicalToolNames prepends the name of their server to the names of tools,
+// to ensure we only return the unique names of tools to user.
+// The servers of all the tools are fetched in a single query.
+func (m *MCPService) setCanonicalToolNames(tools []model.Tool) error {
+	serverIDs := make([]uint, len(tools))
+	for i, t := range tools {
+		serverIDs[i] = t.ServerID
+	}
+	serverNames, err := m.serverNamesByID(serverIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get servers of tools: %w", err)
+	}
 	for i := range tools {
-		var s model.McpServer
-		if err := m.db.First(&s, "id = ?", tools[i].ServerID).Error; err != nil {
-			return nil, fmt.Errorf("failed to get server for tool %s: %w", tools[i].Name, err)
+		serverName, ok := serverNames[tools[i].ServerID]
+		if !ok {
+			return fmt.Errorf("failed to get server for tool %s: %w", tools[i].Name, gorm.ErrRecordNotFound)
 		}
-		tools[i].Name = mergeServerToolNames(s.Name, tools[i].Name)
+		tools[i].Name = mergeServerToolNames(serverName, tools[i].Name)
 	}
-	return tools, nil
+	return nil
 }
 
-// ListToolsByServer fetches tools provided by an MCP server from the registry.
-func (m *MCPService) ListToolsByServer(name string) ([]model.Tool, error) {
-	if err := validateServerName(name); err != nil {
-		return nil, err
+// StreamTools calls fn for every tool matching filter, in the order they were registered, with their canonical names.
+// The tools are read from a database cursor one at a time, so that they never all have to fit in memory.
+// It stops at the first error returned by fn and returns it.
+func (m *MCPService) StreamTools(ctx context.Context, filter ToolFilter, fn func(*model.Tool) error) error {
+	query, err := m.filterTools(filter)
+	if err != nil {
+		return err
 	}
 
-	s, err := m.GetMcpServer(name)
+	// servers are few compared to tools, their names are loaded upfront
+	// so that no other query is needed while the cursor is open
+	var servers []model.McpServer
+	if err := m.db.WithContext(ctx).Select("id", "name").Find(&servers).Error; err != nil {
+		return fmt.Errorf("failed to get MCP servers from DB: %w", err)
+	}
+	serverNames := make(map[uint]string, len(servers))
+	for _, s := range servers {
+		serverNames[s.ID] = s.Name
+	}
+
+	rows, err := query.WithContext(ctx).Order("id").Rows()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get MCP server %s from DB: %w", name, err)
+		return fmt.Errorf("failed to get tools from DB: %w", err)
 	}
+	defer rows.Close()
+	for rows.Next() {
+		var t model.Tool
+		if err := m.db.ScanRows(rows, &t); err != nil {
+			return fmt.Errorf("failed to read tool from DB: %w", err)
+		}
+		serverName, ok := serverNames[t.ServerID]
+		if !ok {
+			// the server was registered after the stream started, its tools are not part of it
+			continue
+		}
+		t.Name = mergeServerToolNames(serverName, t.Name)
+		if err := fn(&t); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get tools from DB: %w", err)
+	}
+	return nil
+}
 
-	var tools []model.Tool
-	if err := m.db.Where("server_id = ?", s.ID).Find(&tools).Error; err != nil {
-		return nil, fmt.Errorf("failed to get tools for server %s from DB: %w", name, err)
+// filterTools returns the query selecting the tools that match filter.
+func (m *MCPService) filterTools(filter ToolFilter) (*gorm.DB, error) {
+	query := m.db.Model(&model.Tool{})
+
+	if filter.Server != "" {
+		if err := validateServerName(filter.Server); err != nil {
+			return nil, &InvalidFilterError{Field: "server", Err: err}
+		}
+		s, err := m.GetMcpServer(filter.Server)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &InvalidFilterError{Field: "server", Err: fmt.Errorf("MCP server %s does not exist: %w", filter.Server, err)}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get MCP server %s from DB: %w", filter.Server, err)
+		}
+		query = query.Where("server_id = ?", s.ID)
 	}
 
-	// prepend server name to tool names to ensure we only return the unique names of tools to user
-	for i := range tools {
-		tools[i].Name = mergeServerToolNames(s.Name, tools[i].Name)
+	if filter.Names != nil {
+		// tools are stored with their server's ID and their name within the server,
+		// the canonical names are matched server by server so that the (server_id, name) index is used
+		toolsByServer := make(map[string][]string)
+		for _, name := range filter.Names {
+			if serverName, toolName, ok := splitServerToolName(name); ok {
+				toolsByServer[serverName] = append(toolsByServer[serverName], toolName)
+			}
+		}
+		serverNames := make([]string, 0, len(toolsByServer))
+		for name := range toolsByServer {
+			serverNames = append(serverNames, name)
+		}
+		var servers []model.McpServer
+		if err := m.db.Where("name IN ?", serverNames).Find(&servers).Error; err != nil {
+			return nil, fmt.Errorf("failed to get MCP servers from DB: %w", err)
+		}
+
+		match := m.db.Where("1 = 0")
+		for _, s := range servers {
+			match = match.Or("server_id = ? AND name IN ?", s.ID, toolsByServer[s.Name])
+		}
+		query = query.Where(match)
 	}
 
-	return tools, nil
+	if filter.Enabled != nil {
+		query = query.Where("enabled = ?", *filter.Enabled)
+	}
+
+	if filter.Query != "" {
+		pattern := "%" + escapeLike(strings.ToLower(filter.Query)) + "%"
+		escape := dialect.Of(m.db).LikeEscape()
+		query = query.Where("(LOWER(name) LIKE ? "+escape+" OR LOWER(description) LIKE ? "+escape+")", pattern, pattern)
+	}
+	return query, nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern, so that s is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 func (m *MCPService) GetTool(name string) (*model.Tool, error) {
@@ -88,6 +258,22 @@ func (m *MCPService) GetTool(name string) (*model.Tool, error) {
 	return &tool, nil
 }
 
+// ResolveToolName returns the canonical name of the tool identified by ident,
+// which is either its UUID or its canonical name.
+func (m *MCPService) ResolveToolName(ident string) (string, error) {
+	if !model.IsUUID(ident) {
+		return ident, nil
+	}
+	var tools []model.Tool
+	if err := m.db.Preload("Server").Where("uuid = ?", ident).Limit(1).Find(&tools).Error; err != nil {
+		return "", fmt.Errorf("failed to get tool %s from DB: %w", ident, err)
+	}
+	if len(tools) == 0 {
+		return ident, nil
+	}
+	return mergeServerToolNames(tools[0].Server.Name, tools[0].Name), nil
+}
+
 // GetToolInstance returns the in-memory mcp.Tool instance for the given tool name.
 // Returns the tool instance and a boolean indicating if it was found.
 func (m *MCPService) GetToolInstance(name string) (mcp.Tool, bool) {
@@ -107,6 +293,29 @@ func (m *MCPService) GetToolParentServer(name string) (*model.McpServer, error)
 	return m.GetMcpServer(serverName)
 }
 
+// GetToolsParentServers returns the MCP servers that provide the given tools, keyed by canonical tool name.
+// All the servers are fetched in a single query. The tools whose server is not registered are left out.
+func (m *MCPService) GetToolsParentServers(names []string) (map[string]*model.McpServer, error) {
+	serverNames := make([]string, 0)
+	for _, name := range names {
+		if serverName, _, ok := splitServerToolName(name); ok {
+			serverNames = append(serverNames, serverName)
+		}
+	}
+	servers, err := m.GetMcpServersByName(slices.Compact(slices.Sorted(slices.Values(serverNames))))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP servers from DB: %w", err)
+	}
+	parents := make(map[string]*model.McpServer, len(names))
+	for _, name := range names {
+		serverName, _, _ := splitServerToolName(name)
+		if s, ok := servers[serverName]; ok {
+			parents[name] = s
+		}
+	}
+	return parents, nil
+}
+
 // InvokeTool invokes a tool from a registered MCP server and returns its response.
 func (m *MCPService) InvokeTool(ctx context.Context, name string, args map[string]any) (*types.ToolInvokeResult, error) {
 	started := time.Now()
@@ -130,6 +339,15 @@ func (m *MCPService) InvokeTool(ctx context.Context, name string, args map[strin
 			err,
 		)
 	}
+	if err := m.checkWarmedUp(serverName); err != nil {
+		return nil, err
+	}
+
+	release, err := m.upstreamCalls.acquire(ctx, serverName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	session, err := m.getSession(ctx, serverModel)
 	if err != nil {
@@ -141,7 +359,19 @@ func (m *MCPService) InvokeTool(ctx context.Context, name string, args map[strin
 	callToolReq.Params.Name = toolName
 	callToolReq.Params.Arguments = args
 
-	callToolResp, err := session.client.CallTool(ctx, callToolReq)
+	callCtx, size := withResultSize(ctx, m.sessionManager.maxResultBytes.Load())
+	callStarted := time.Now()
+	callToolResp, err := session.client.CallTool(callCtx, callToolReq)
+	err = size.check(name, err)
+	m.metrics.RecordUpstreamOperation(
+		ctx, serverName, telemetry.MCPMethodToolsCall, toolName, err, time.Since(callStarted),
+	)
+	if size.bytes() > 0 {
+		m.metrics.RecordToolResultSize(ctx, serverName, toolName, size.bytes())
+	}
+	if errors.Is(err, ErrToolResultTooLarge) {
+		return nil, err
+	}
 	if err != nil {
 		session.invalidateOnError(err) // Invalidate unhealthy stateful sessions
 		return nil, fmt.Errorf("failed to call tool %s on MCP server %s: %w", toolName, serverName, err)
@@ -268,20 +498,30 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 		return nil, fmt.Errorf("failed to get MCP server %s: %w", serverName, err)
 	}
 
+	// only the tools that need a change are fetched, and they are all updated with a single query
 	var tools []model.Tool
-	if err := m.db.Where("server_id = ?", s.ID).Find(&tools).Error; err != nil {
-		return nil, fmt.Errorf("failed to get tools for server %s: %w", entity, err)
+	err = db.RetryOnBusy(func() error {
+		return m.db.Transaction(func(tx *gorm.DB) error {
+			changing := tx.Model(&model.Tool{}).Where("server_id = ? AND enabled = ?", s.ID, !enabled)
+			if err := changing.Session(&gorm.Session{}).Find(&tools).Error; err != nil {
+				return fmt.Errorf("failed to get tools for server %s: %w", entity, err)
+			}
+			if len(tools) == 0 {
+				return nil
+			}
+			if err := changing.Update("enabled", enabled).Error; err != nil {
+				return fmt.Errorf("failed to set tools of server %s enabled=%t: %w", entity, enabled, err)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	var changedToolNames []string
 	for i := range tools {
-		if tools[i].Enabled == enabled {
-			continue // no change needed
-		}
 		tools[i].Enabled = enabled
-		if err := m.db.Save(&tools[i]).Error; err != nil {
-			return nil, fmt.Errorf("failed to set tool %s enabled=%t: %w", tools[i].Name, enabled, err)
-		}
 		canonicalToolName := mergeServerToolNames(s.Name, tools[i].Name)
 
 		if enabled {
@@ -324,46 +564,70 @@ func (m *MCPService) registerServerTools(ctx context.Context, s *model.McpServer
 	if err != nil {
 		return fmt.Errorf("failed to fetch tools from MCP server %s: %w", s.Name, err)
 	}
-	for _, tool := range resp.Tools {
-		canonicalToolName := mergeServerToolNames(s.Name, tool.GetName())
-
-		// extracting json schema is currently on best-effort basis
-		// if it fails, we log the error and continue with the next tool
-		jsonSchema, _ := json.Marshal(tool.InputSchema)
-
-		// extracting annotations is also on best-effort basis
-		annotationsJSON, _ := json.Marshal(tool.Annotations)
-
-		t := &model.Tool{
-			ServerID:    s.ID,
-			Name:        tool.GetName(),
-			Description: tool.Description,
-			InputSchema: jsonSchema,
-			Annotations: annotationsJSON,
+	tools := uniqueTools(s, resp.Tools)
+	inserted := insertInBatches(m.db, len(tools),
+		func(i int) *model.Tool { return newToolModel(s, tools[i]) },
+		func(i int) string { return "tool " + mergeServerToolNames(s.Name, tools[i].GetName()) },
+	)
+	// the tools are published once they are all stored, so that the proxy never serves a tool missing from the DB
+	for i, tool := range tools {
+		if inserted[i] {
+			m.publishTool(s, tool)
 		}
-		if err := m.db.Create(t).Error; err != nil {
-			// If registration of a tool fails, we should not fail the entire server registration.
-			// Instead, continue with the next tool.
-			log.Printf("[ERROR] failed to register tool %s in DB: %v", canonicalToolName, err)
+	}
+	return nil
+}
+
+// uniqueTools returns the tools listed by an MCP server without the ones whose name was already listed.
+// Their canonical names would collide, only the first tool with a name is registered.
+func uniqueTools(s *model.McpServer, tools []mcp.Tool) []mcp.Tool {
+	seen := make(map[string]bool, len(tools))
+	unique := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if seen[tool.GetName()] {
+			log.Printf("[WARN] MCP server %s lists the tool %s more than once, only the first one is registered", s.Name, tool.GetName())
 			continue
 		}
+		seen[tool.GetName()] = true
+		unique = append(unique, tool)
+	}
+	return unique
+}
 
-		// Set tool name to include the server name prefix to make it recognizable by MCPJungle
-		// then add the tool to the appropriate MCP proxy server
-		tool.Name = canonicalToolName
+// newToolModel creates the DB record of a tool provided by an MCP server.
+func newToolModel(s *model.McpServer, tool mcp.Tool) *model.Tool {
+	// extracting json schema is currently on best-effort basis
+	// if it fails, we log the error and continue with the next tool
+	jsonSchema, _ := json.Marshal(tool.InputSchema)
 
-		if s.Transport == types.TransportSSE {
-			m.sseMcpProxyServer.AddTool(tool, m.MCPProxyToolCallHandler)
-		} else {
-			m.mcpProxyServer.AddTool(tool, m.MCPProxyToolCallHandler)
-		}
+	// extracting annotations is also on best-effort basis
+	annotationsJSON, _ := json.Marshal(tool.Annotations)
 
-		// also add the tool to the in-memory tool instance tracker
-		m.addToolInstance(tool)
-		// notify any registered callbacks about the tool addition
-		m.notifyToolAddition(tool.Name)
+	return &model.Tool{
+		ServerID:    s.ID,
+		Name:        tool.GetName(),
+		Description: tool.Description,
+		InputSchema: jsonSchema,
+		Annotations: annotationsJSON,
 	}
-	return nil
+}
+
+// publishTool adds a tool stored in the DB to the appropriate MCP proxy server.
+func (m *MCPService) publishTool(s *model.McpServer, tool mcp.Tool) {
+	// Set tool name to include the server name prefix to make it recognizable by MCPJungle
+	// then add the tool to the appropriate MCP proxy server
+	tool.Name = mergeServerToolNames(s.Name, tool.GetName())
+
+	if s.Transport == types.TransportSSE {
+		m.sseMcpProxyServer.AddTool(tool, m.MCPProxyToolCallHandler)
+	} else {
+		m.mcpProxyServer.AddTool(tool, m.MCPProxyToolCallHandler)
+	}
+
+	// also add the tool to the in-memory tool instance tracker
+	m.addToolInstance(tool)
+	// notify any registered callbacks about the tool addition
+	m.notifyToolAddition(tool.Name)
 }
 
 // deregisterServerTools deletes all tools that belong to an MCP server from the DB.
@@ -405,19 +669,24 @@ func (m *MCPService) deregisterServerTools(s *model.McpServer) error {
 // addToolInstance adds a tool instance to the in-memory tool instance tracker.
 // This method does not check for duplicates.
 // If a tool with the same name already exists, it is overwritten.
+// Since it is called right after every addition of a tool to the MCP proxy server,
+// it also invalidates the cached tools/list result of the proxy server.
 func (m *MCPService) addToolInstance(tool mcp.Tool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.toolInstances[tool.GetName()] = tool
+	m.toolsListCache.Invalidate(m.mcpProxyServer)
 }
 
 // deleteToolInstances deletes one or more tool instances from the in-memory tool instance tracker.
+// Like addToolInstance, it also invalidates the cached tools/list result of the MCP proxy server.
 func (m *MCPService) deleteToolInstances(toolNames ...string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	for _, name := range toolNames {
 		delete(m.toolInstances, name)
 	}
+	m.toolsListCache.Invalidate(m.mcpProxyServer)
 }
 
 // notifyToolDeletion calls all registered tool deletion callbacks with the given tool names.
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultMaxToolResultBytes is the default maximum size of the response of an MCP server to a tool call.
+const DefaultMaxToolResultBytes = 64 << 20
+
+// ErrToolResultTooLarge is returned when the result of a tool call is larger than ServiceConfig.MaxToolResultBytes.
+var ErrToolResultTooLarge = errors.New("tool result is too large")
+
+// resultSizeKey is the context key of the resultSize of a tool call.
+type resultSizeKey struct{}
+
+// resultSize counts the bytes of the responses of a streamable HTTP server read during a tool call, as they are read.
+// The MCP client decodes the JSON-RPC response as it reads it from the connection, so reading stops as soon as
+// the limit is reached instead of the whole result being held in memory first.
+type resultSize struct {
+	// limit is the maximum number of bytes that can be read, 0 for no limit
+	limit int64
+
+	n        atomic.Int64
+	exceeded atomic.Bool
+}
+
+// withResultSize returns a context that counts the bytes of the upstream responses read with it.
+func withResultSize(ctx context.Context, limit int64) (context.Context, *resultSize) {
+	size := &resultSize{limit: limit}
+	return context.WithValue(ctx, resultSizeKey{}, size), size
+}
+
+// resultSizeFrom returns the resultSize of the tool call ctx belongs to, nil if it doesn't belong to one.
+func resultSizeFrom(ctx context.Context) *resultSize {
+	size, _ := ctx.Value(resultSizeKey{}).(*resultSize)
+	return size
+}
+
+// bytes returns the number of bytes read so far.
+func (s *resultSize) bytes() int64 {
+	return s.n.Load()
+}
+
+// check returns ErrToolResultTooLarge if the limit was reached while reading the result of the tool name.
+// The MCP client may not preserve the error of the response body, so the call's error is replaced by it.
+// The result of a stdio or SSE server is replaced by an error response instead, see messageLimiter, whose message
+// is the only part the MCP client returns.
+func (s *resultSize) check(name string, err error) error {
+	replaced := err != nil && strings.HasPrefix(err.Error(), ErrToolResultTooLarge.Error()+":")
+	if s.exceeded.Load() || replaced {
+		return fmt.Errorf("%w: the result of tool %s is larger than %d bytes", ErrToolResultTooLarge, name, s.limit)
+	}
+	return err
+}
+
+// countedBody is the body of an upstream response read during a tool call.
+type countedBody struct {
+	io.ReadCloser
+	size *resultSize
+}
+
+func (b *countedBody) Read(p []byte) (int, error) {
+	if b.size.exceeded.Load() {
+		return 0, ErrToolResultTooLarge
+	}
+	if b.size.limit > 0 {
+		// one byte more than the limit is read, to tell a result of exactly the limit from a larger one
+		if remaining := b.size.limit - b.size.n.Load() + 1; int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+	n, err := b.ReadCloser.Read(p)
+	if total := b.size.n.Add(int64(n)); b.size.limit > 0 && total > b.size.limit {
+		b.size.exceeded.Store(true)
+		return 0, ErrToolResultTooLarge
+	}
+	return n, err
+}
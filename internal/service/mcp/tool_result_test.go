@@ -0,0 +1,274 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// toolResultSizes records the sizes of the tool results, by canonical tool name.
+type toolResultSizes struct {
+	telemetry.NoopCustomMetrics
+	mu    sync.Mutex
+	sizes map[string]int64
+}
+
+func (m *toolResultSizes) RecordToolResultSize(ctx context.Context, serverName, toolName string, bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sizes[mergeServerToolNames(serverName, toolName)] = bytes
+}
+
+func TestToolResultSizeLimit(t *testing.T) {
+	const limit = 64 << 10
+	upstream := server.NewMCPServer("github", "0.0.0")
+	upstream.AddTool(mcp.NewTool("small"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	upstream.AddTool(mcp.NewTool("large"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(strings.Repeat("x", 4<<20)), nil
+	})
+	upstreamServer := server.NewTestStreamableHTTPServer(upstream)
+	defer upstreamServer.Close()
+
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(&model.McpServer{}, &model.Tool{}, &model.Prompt{}))
+	metrics := &toolResultSizes{sizes: make(map[string]int64)}
+	m, err := NewMCPService(&ServiceConfig{
+		DB:                      db,
+		McpProxyServer:          server.NewMCPServer("proxy", "0.0.0"),
+		SseMcpProxyServer:       server.NewMCPServer("sse-proxy", "0.0.0"),
+		Metrics:                 metrics,
+		McpServerInitReqTimeout: 5,
+		MaxToolResultBytes:      limit,
+	})
+	testhelpers.AssertNoError(t, err)
+	defer m.Shutdown()
+
+	github, err := model.NewStreamableHTTPServer("github", "", upstreamServer.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, m.RegisterMcpServer(context.Background(), github))
+
+	_, err = m.InvokeTool(context.Background(), "github__small", nil)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, metrics.sizes["github__small"] > 0, "the size of the result should be recorded")
+
+	// the result is only read up to the limit, it is never held in memory as a whole
+	_, err = m.InvokeTool(context.Background(), "github__large", nil)
+	testhelpers.AssertTrue(t, errors.Is(err, ErrToolResultTooLarge), "a result over the limit should fail the call")
+	testhelpers.AssertEqual(t, int64(limit+1), metrics.sizes["github__large"])
+
+	// the proxy fails the call the same way
+	ctx := context.WithValue(context.Background(), "mode", model.ModeDev)
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "github__large"
+	res, err := m.MCPProxyToolCallHandler(ctx, req)
+	testhelpers.AssertTrue(t, errors.Is(err, ErrToolResultTooLarge), "a result over the limit should fail the proxied call")
+	testhelpers.AssertTrue(t, res == nil, "no partial result should be relayed")
+}
+
+// fakeStdioServerEnvVar makes TestFakeStdioServer run as the stdio MCP server of TestToolResultMemoryStaysFlat.
+const fakeStdioServerEnvVar = "MCPJUNGLE_TEST_FAKE_STDIO_SERVER"
+
+func TestToolResultMemoryStaysFlat(t *testing.T) {
+	const limit = 1 << 20
+	maxMessageBytes := func() int64 { return limit }
+
+	httpServer := httptest.NewServer(http.HandlerFunc(serveFakeStreamableHTTP))
+	defer httpServer.Close()
+	sseServer := newFakeSSEServer()
+	defer sseServer.Close()
+
+	connect := map[string]func(t *testing.T) *client.Client{
+		"streamable_http": func(t *testing.T) *client.Client {
+			s, err := model.NewStreamableHTTPServer("fake", "", httpServer.URL+"/mcp", "", types.SessionModeStateless)
+			testhelpers.AssertNoError(t, err)
+			pools := newHTTPPools(telemetry.NewNoopCustomMetrics())
+			t.Cleanup(pools.closeAll)
+			c, err := connectMcpServer(context.Background(), s, 5, pools, nil, maxMessageBytes)
+			testhelpers.AssertNoError(t, err)
+			return c
+		},
+		"sse": func(t *testing.T) *client.Client {
+			s, err := model.NewSSEServer("fake", "", sseServer.URL+"/sse", "", types.SessionModeStateless)
+			testhelpers.AssertNoError(t, err)
+			c, err := connectMcpServer(context.Background(), s, 5, nil, nil, maxMessageBytes)
+			testhelpers.AssertNoError(t, err)
+			return c
+		},
+		"stdio": func(t *testing.T) *client.Client {
+			s, err := model.NewStdioServer(
+				"fake", "", os.Args[0], []string{"-test.run=^TestFakeStdioServer$"},
+				map[string]string{fakeStdioServerEnvVar: "1"}, types.SessionModeStateless,
+			)
+			testhelpers.AssertNoError(t, err)
+			c, err := connectMcpServer(context.Background(), s, 5, nil, nil, maxMessageBytes)
+			testhelpers.AssertNoError(t, err)
+			return c
+		},
+	}
+	for _, transport := range []string{"streamable_http", "sse", "stdio"} {
+		t.Run(transport, func(t *testing.T) {
+			c := connect[transport](t)
+			defer c.Close()
+
+			call := func(size int) error {
+				req := mcp.CallToolRequest{}
+				req.Params.Name = "large"
+				req.Params.Arguments = map[string]any{"size": size}
+				ctx, resultSize := withResultSize(context.Background(), limit)
+				_, err := c.CallTool(ctx, req)
+				return resultSize.check("fake__large", err)
+			}
+			testhelpers.AssertNoError(t, call(limit/2))
+
+			// the memory allocated by a call whose result is over the limit doesn't grow with the result,
+			// which is never held in memory as a whole
+			var allocated []uint64
+			for _, size := range []int{4 << 20, 16 << 20, 64 << 20} {
+				var before, after runtime.MemStats
+				runtime.GC()
+				runtime.ReadMemStats(&before)
+				err := call(size)
+				runtime.ReadMemStats(&after)
+				testhelpers.AssertTrue(t, errors.Is(err, ErrToolResultTooLarge), fmt.Sprintf("a result of %d bytes should fail the call, got %v", size, err))
+				allocated = append(allocated, after.TotalAlloc-before.TotalAlloc)
+			}
+			for i, a := range allocated[1:] {
+				testhelpers.AssertTrue(
+					t, a < allocated[0]+limit,
+					fmt.Sprintf("the memory allocated by the calls should stay flat as the results grow, got %v", allocated[:i+2]),
+				)
+			}
+
+			// the session is still usable
+			testhelpers.AssertNoError(t, call(1024))
+		})
+	}
+}
+
+// TestFakeStdioServer is the stdio MCP server of TestToolResultMemoryStaysFlat, run in a process of its own.
+func TestFakeStdioServer(t *testing.T) {
+	if os.Getenv(fakeStdioServerEnvVar) == "" {
+		t.Skip("only runs as the stdio MCP server of TestToolResultMemoryStaysFlat")
+	}
+	stdout := bufio.NewWriter(os.Stdout)
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req fakeRequest
+		if json.Unmarshal(scanner.Bytes(), &req) != nil || req.ID == nil {
+			continue
+		}
+		if writeFakeResponse(stdout, req) != nil || stdout.WriteByte('\n') != nil || stdout.Flush() != nil {
+			os.Exit(1)
+		}
+	}
+	os.Exit(0)
+}
+
+// fakeRequest is a JSON-RPC request of the MCP client to the fake MCP servers.
+type fakeRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params struct {
+		Arguments struct {
+			Size int `json:"size"`
+		} `json:"arguments"`
+	} `json:"params"`
+}
+
+// writeFakeResponse writes the response of the fake MCP servers to req. A tool call is answered with a text result
+// of the size in its arguments, written in chunks so that the server doesn't hold it in memory.
+func writeFakeResponse(w io.Writer, req fakeRequest) error {
+	switch req.Method {
+	case "initialize":
+		_, err := fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":"2025-03-26",`+
+			`"capabilities":{"tools":{}},"serverInfo":{"name":"fake","version":"0.0.0"}}}`, req.ID)
+		return err
+	case "tools/call":
+		if _, err := fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"content":[{"type":"text","text":"`, req.ID); err != nil {
+			return err
+		}
+		chunk := bytes.Repeat([]byte("x"), 32<<10)
+		for size := req.Params.Arguments.Size; size > 0; size -= len(chunk) {
+			if _, err := w.Write(chunk[:min(size, len(chunk))]); err != nil {
+				return err
+			}
+		}
+		_, err := io.WriteString(w, `"}]}}`)
+		return err
+	default:
+		_, err := fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{}}`, req.ID)
+		return err
+	}
+}
+
+// serveFakeStreamableHTTP serves the fake streamable HTTP MCP server.
+func serveFakeStreamableHTTP(w http.ResponseWriter, r *http.Request) {
+	var req fakeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.ID == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = writeFakeResponse(w, req)
+}
+
+// newFakeSSEServer starts the fake SSE MCP server, which answers the requests posted to /message on
+// the event stream of /sse.
+func newFakeSSEServer() *httptest.Server {
+	responses := make(chan fakeRequest)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sse", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: endpoint\ndata: /message\n\n")
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case req := <-responses:
+				_, _ = io.WriteString(w, "event: message\ndata: ")
+				_ = writeFakeResponse(w, req)
+				_, _ = io.WriteString(w, "\n\n")
+				w.(http.Flusher).Flush()
+			}
+		}
+	})
+	mux.HandleFunc("POST /message", func(w http.ResponseWriter, r *http.Request) {
+		var req fakeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		if req.ID != nil {
+			go func() { responses <- req }()
+		}
+	})
+	return httptest.NewServer(mux)
+}
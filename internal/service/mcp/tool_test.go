@@ -2,10 +2,15 @@ package mcp
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
@@ -470,3 +475,89 @@ func TestConvertMCPMeta(t *testing.T) {
 		})
 	}
 }
+
+func TestListToolsPage(t *testing.T) {
+	testhelpers.ForEachTestDB(t, func(t *testing.T, setup *testhelpers.TestDBSetup) {
+		db := setup.DB
+		mcpService, err := NewMCPService(&ServiceConfig{
+			DB:                      db,
+			McpProxyServer:          &server.MCPServer{},
+			SseMcpProxyServer:       &server.MCPServer{},
+			Metrics:                 telemetry.NewNoopCustomMetrics(),
+			McpServerInitReqTimeout: 10,
+		})
+		testhelpers.AssertNoError(t, err)
+
+		for _, s := range []string{"git", "fs"} {
+			testhelpers.AssertNoError(t, db.Create(&model.McpServer{Name: s, Transport: types.TransportStdio, Config: []byte("{}")}).Error)
+		}
+		git, err := mcpService.GetMcpServer("git")
+		testhelpers.AssertNoError(t, err)
+		fs, err := mcpService.GetMcpServer("fs")
+		testhelpers.AssertNoError(t, err)
+		tools := []model.Tool{
+			{ServerID: git.ID, Name: "commit", Description: "Record changes to the repository"},
+			{ServerID: git.ID, Name: "push", Description: "Update remote refs"},
+			{ServerID: git.ID, Name: "status", Description: "Show the working tree status (100%_done)"},
+			{ServerID: fs.ID, Name: "read_file", Description: "Read a file"},
+			{ServerID: fs.ID, Name: "write_file", Description: "Write a file"},
+		}
+		for i := range tools {
+			testhelpers.AssertNoError(t, db.Create(&tools[i]).Error)
+		}
+		testhelpers.AssertNoError(t, db.Model(&tools[1]).Update("enabled", false).Error)
+		testhelpers.AssertNoError(t, db.Model(&tools[4]).Update("enabled", false).Error)
+
+		enabled, disabled := true, false
+		tests := []struct {
+			name     string
+			filter   ToolFilter
+			expected []string
+		}{
+			{name: "no filter", filter: ToolFilter{}, expected: []string{"git__commit", "git__push", "git__status", "fs__read_file", "fs__write_file"}},
+			{name: "server", filter: ToolFilter{Server: "fs"}, expected: []string{"fs__read_file", "fs__write_file"}},
+			{name: "enabled", filter: ToolFilter{Enabled: &enabled}, expected: []string{"git__commit", "git__status", "fs__read_file"}},
+			{name: "disabled", filter: ToolFilter{Enabled: &disabled}, expected: []string{"git__push", "fs__write_file"}},
+			{name: "query matches names", filter: ToolFilter{Query: "FILE"}, expected: []string{"fs__read_file", "fs__write_file"}},
+			{name: "query matches descriptions", filter: ToolFilter{Query: "remote"}, expected: []string{"git__push"}},
+			{name: "query escapes wildcards", filter: ToolFilter{Query: "%_"}, expected: []string{"git__status"}},
+			{name: "names", filter: ToolFilter{Names: []string{"git__push", "fs__read_file", "unknown__tool", "invalid"}}, expected: []string{"git__push", "fs__read_file"}},
+			{name: "no names", filter: ToolFilter{Names: []string{}}, expected: []string{}},
+			{
+				name:     "combined",
+				filter:   ToolFilter{Server: "git", Names: []string{"git__commit", "git__push", "fs__read_file"}, Enabled: &enabled, Query: "o"},
+				expected: []string{"git__commit"},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, next, total, err := mcpService.ListToolsPage(tt.filter, model.Page{})
+				testhelpers.AssertNoError(t, err)
+				names := []string{}
+				for _, tool := range got {
+					names = append(names, tool.Name)
+				}
+				if !reflect.DeepEqual(tt.expected, names) {
+					t.Errorf("expected tools %v, got %v", tt.expected, names)
+				}
+				testhelpers.AssertEqual(t, uint(0), next)
+				testhelpers.AssertEqual(t, int64(len(tt.expected)), total)
+			})
+		}
+
+		t.Run("total counts all pages", func(t *testing.T) {
+			got, next, total, err := mcpService.ListToolsPage(ToolFilter{Enabled: &enabled}, model.Page{Limit: 2})
+			testhelpers.AssertNoError(t, err)
+			testhelpers.AssertEqual(t, 2, len(got))
+			testhelpers.AssertEqual(t, tools[2].ID, next)
+			testhelpers.AssertEqual(t, int64(3), total)
+		})
+
+		t.Run("unknown server", func(t *testing.T) {
+			_, _, _, err := mcpService.ListToolsPage(ToolFilter{Server: "unknown"}, model.Page{})
+			var filterErr *InvalidFilterError
+			testhelpers.AssertTrue(t, errors.As(err, &filterErr), "expected an InvalidFilterError")
+			testhelpers.AssertEqual(t, "server", filterErr.Field)
+		})
+	})
+}
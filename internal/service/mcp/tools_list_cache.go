@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+)
+
+// toolsListRequest is the request used to build the results of tools/list stored in ToolsListCache.
+// It has no cursor since tools/list lists all the tools of the proxy servers in a single page.
+// It has no session either: the result is the same for every client, whatever the protocol version it negotiated.
+var toolsListRequest = json.RawMessage(`{"jsonrpc":"2.0","id":0,"method":"tools/list"}`)
+
+// The JSON-RPC response to tools/list is written as toolsListResponseHead, the request ID, then the cached tail,
+// so that the result doesn't have to be serialized again for every request. The fields are in the order of
+// mcp.JSONRPCResponse.
+var (
+	toolsListResponseHead = []byte(`{"jsonrpc":"2.0","id":`)
+	toolsListResultPrefix = []byte(`,"result":`)
+	toolsListResponseEnd  = []byte("}\n")
+)
+
+// ToolsListCache caches the serialized result of tools/list of MCP proxy servers.
+// MCP clients list the tools of a proxy server far more often than they change, and building the result
+// sorts and serializes the schemas of all of its tools. The result is only serialized again after it was invalidated.
+// The services that add or remove tools of a proxy server must call Invalidate right after every change.
+// Invalidate and Forget do nothing on a nil ToolsListCache, which is what MCPService uses when caching is disabled.
+type ToolsListCache struct {
+	mu      sync.Mutex
+	entries map[*server.MCPServer]*toolsListEntry
+
+	metrics telemetry.CustomMetrics
+}
+
+// toolsListEntry is the cached tools/list result of a proxy server.
+type toolsListEntry struct {
+	// version is incremented every time the entry is invalidated,
+	// so that a result built while the tools of the server changed is not stored.
+	version uint64
+	// tail is the end of the JSON-RPC response after the request ID, it contains the serialized result.
+	// It is nil if the result must be built again.
+	tail []byte
+}
+
+// result returns the serialized result held in the tail of the entry.
+func (e *toolsListEntry) result() json.RawMessage {
+	return e.tail[len(toolsListResultPrefix) : len(e.tail)-len(toolsListResponseEnd)]
+}
+
+// NewToolsListCache creates an empty ToolsListCache that records its hits and misses in metrics.
+func NewToolsListCache(metrics telemetry.CustomMetrics) *ToolsListCache {
+	return &ToolsListCache{
+		entries: make(map[*server.MCPServer]*toolsListEntry),
+		metrics: metrics,
+	}
+}
+
+// ToolsList returns the serialized result of tools/list of the proxy server s.
+// group is the tool group s serves, empty for the main proxy server, it is only used to label the metrics.
+// The result is shared by all the callers, it must not be modified.
+func (c *ToolsListCache) ToolsList(ctx context.Context, group string, s *server.MCPServer) (json.RawMessage, error) {
+	e, err := c.entry(ctx, group, s)
+	if err != nil {
+		return nil, err
+	}
+	return e.result(), nil
+}
+
+// ToolsListResponse returns the serialized JSON-RPC response to the tools/list request id sent to the proxy server s,
+// terminated by a newline. Its buffers are shared by all the callers, they must not be modified.
+func (c *ToolsListCache) ToolsListResponse(
+	ctx context.Context, group string, s *server.MCPServer, id mcp.RequestId,
+) (net.Buffers, error) {
+	idJSON, err := json.Marshal(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize the request ID: %w", err)
+	}
+	e, err := c.entry(ctx, group, s)
+	if err != nil {
+		return nil, err
+	}
+	return net.Buffers{toolsListResponseHead, idJSON, e.tail}, nil
+}
+
+// entry returns the entry of the proxy server s, building its result if it is not cached.
+// The returned entry is a snapshot, it is not changed by the invalidations.
+func (c *ToolsListCache) entry(ctx context.Context, group string, s *server.MCPServer) (toolsListEntry, error) {
+	c.mu.Lock()
+	e, ok := c.entries[s]
+	if !ok {
+		e = &toolsListEntry{}
+		c.entries[s] = e
+	}
+	cached := *e
+	c.mu.Unlock()
+
+	if cached.tail != nil {
+		c.metrics.RecordToolsListCacheLookup(ctx, group, telemetry.ToolsListCacheHit)
+		return cached, nil
+	}
+	c.metrics.RecordToolsListCacheLookup(ctx, group, telemetry.ToolsListCacheMiss)
+
+	result, err := listTools(ctx, s)
+	if err != nil {
+		return toolsListEntry{}, err
+	}
+	tail := make([]byte, 0, len(toolsListResultPrefix)+len(result)+len(toolsListResponseEnd))
+	tail = append(append(append(tail, toolsListResultPrefix...), result...), toolsListResponseEnd...)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e.version == cached.version && c.entries[s] == e {
+		e.tail = tail
+	}
+	return toolsListEntry{version: cached.version, tail: tail}, nil
+}
+
+// Invalidate drops the cached tools/list results of the given proxy servers.
+func (c *ToolsListCache) Invalidate(servers ...*server.MCPServer) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range servers {
+		if e, ok := c.entries[s]; ok {
+			e.version++
+			e.tail = nil
+		}
+	}
+}
+
+// Forget removes the entries of proxy servers that are not served anymore, eg- those of a deleted tool group.
+func (c *ToolsListCache) Forget(servers ...*server.MCPServer) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range servers {
+		delete(c.entries, s)
+	}
+}
+
+// listTools builds the serialized result of tools/list of the proxy server s.
+func listTools(ctx context.Context, s *server.MCPServer) (json.RawMessage, error) {
+	switch resp := s.HandleMessage(ctx, toolsListRequest).(type) {
+	case mcp.JSONRPCResponse:
+		result, err := json.Marshal(resp.Result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize the tools list: %w", err)
+		}
+		return result, nil
+	case mcp.JSONRPCError:
+		return nil, fmt.Errorf("failed to list tools: %s", resp.Error.Message)
+	default:
+		return nil, fmt.Errorf("unexpected response to tools/list: %T", resp)
+	}
+}
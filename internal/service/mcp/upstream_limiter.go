@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	// DefaultMaxConcurrentUpstreamCalls is the default number of tool and prompt calls proxied to the MCP servers
+	// at the same time, across all servers.
+	DefaultMaxConcurrentUpstreamCalls = 1024
+	// DefaultMaxQueuedUpstreamCalls is the default number of calls waiting for one of the calls in flight to complete.
+	DefaultMaxQueuedUpstreamCalls = 4096
+)
+
+// upstreamCallWeight is the share of the concurrency limit taken by a call to an MCP server.
+const upstreamCallWeight = 1
+
+var (
+	// ErrUpstreamCallsSaturated is returned when a call to an MCP server is rejected because too many calls are
+	// already waiting for the calls in flight to complete.
+	ErrUpstreamCallsSaturated = errors.New("too many calls to MCP servers are in flight, try again later")
+	// ErrShuttingDown is returned for the calls to MCP servers that were waiting to be made when the server
+	// began shutting down.
+	ErrShuttingDown = errors.New("the server is shutting down")
+)
+
+// upstreamLimiter bounds the number of calls proxied to the MCP servers at the same time.
+// It is global, so that a burst of calls can't open an unbounded number of upstream connections, whatever the
+// servers they are made to. The calls over the limit wait in a queue, until the queue is full too.
+type upstreamLimiter struct {
+	sem       *semaphore.Weighted
+	maxQueued int64
+	queued    atomic.Int64
+
+	// draining is canceled once the server shuts down
+	draining context.Context
+	drain    context.CancelFunc
+
+	metrics telemetry.CustomMetrics
+}
+
+// newUpstreamLimiter creates an upstreamLimiter letting maxConcurrent calls in flight and maxQueued waiting.
+// The defaults are used if they are 0, the calls over the limit are rejected right away if maxQueued is negative.
+func newUpstreamLimiter(maxConcurrent, maxQueued int, metrics telemetry.CustomMetrics) *upstreamLimiter {
+	if maxConcurrent <= 0 {
+		maxConcurrent = DefaultMaxConcurrentUpstreamCalls
+	}
+	if maxQueued == 0 {
+		maxQueued = DefaultMaxQueuedUpstreamCalls
+	}
+	draining, drain := context.WithCancel(context.Background())
+	return &upstreamLimiter{
+		sem:       semaphore.NewWeighted(int64(maxConcurrent)),
+		maxQueued: int64(max(maxQueued, 0)),
+		draining:  draining,
+		drain:     drain,
+		metrics:   metrics,
+	}
+}
+
+// acquire waits until a call can be made to serverName, or fails if ctx is done, if the queue is full or if the
+// server is shutting down. The returned function must be called once the call completed.
+func (l *upstreamLimiter) acquire(ctx context.Context, serverName string) (func(), error) {
+	release := func() { l.sem.Release(upstreamCallWeight) }
+	if l.sem.TryAcquire(upstreamCallWeight) {
+		return release, nil
+	}
+	if l.draining.Err() != nil {
+		return nil, ErrShuttingDown
+	}
+	if l.queued.Add(1) > l.maxQueued {
+		l.queued.Add(-1)
+		return nil, ErrUpstreamCallsSaturated
+	}
+	defer l.queued.Add(-1)
+
+	started := time.Now()
+	waitCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stop := context.AfterFunc(l.draining, func() { cancel(ErrShuttingDown) })
+	defer stop()
+
+	err := l.sem.Acquire(waitCtx, upstreamCallWeight)
+	l.metrics.RecordUpstreamQueueWait(ctx, serverName, time.Since(started))
+	if err != nil {
+		return nil, context.Cause(waitCtx)
+	}
+	return release, nil
+}
+
+// queuedCalls returns the number of calls waiting for a call in flight to complete.
+func (l *upstreamLimiter) queuedCalls() int64 {
+	return l.queued.Load()
+}
+
+// DrainUpstreamCalls fails the calls to the MCP servers that are waiting for the calls in flight to complete,
+// and the ones that would have to wait from now on, with ErrShuttingDown.
+// It is called when the server shuts down, so that it only waits for the calls already made to the MCP servers.
+func (m *MCPService) DrainUpstreamCalls() {
+	m.upstreamCalls.drain()
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// queueWaits records the waits of the calls to the MCP servers, by server.
+type queueWaits struct {
+	telemetry.NoopCustomMetrics
+	mu    sync.Mutex
+	waits map[string][]time.Duration
+}
+
+func (m *queueWaits) RecordUpstreamQueueWait(ctx context.Context, serverName string, elapsedTime time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.waits[serverName] = append(m.waits[serverName], elapsedTime)
+}
+
+// waitForQueued waits until n calls wait in the queue of l.
+func waitForQueued(t *testing.T, l *upstreamLimiter, n int64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for l.queuedCalls() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d queued calls, got %d", n, l.queuedCalls())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestUpstreamLimiter(t *testing.T) {
+	metrics := &queueWaits{waits: make(map[string][]time.Duration)}
+	l := newUpstreamLimiter(2, 1, metrics)
+
+	release1, err := l.acquire(context.Background(), "github")
+	testhelpers.AssertNoError(t, err)
+	release2, err := l.acquire(context.Background(), "slack")
+	testhelpers.AssertNoError(t, err)
+
+	// the third call waits for a slot, the fourth one doesn't fit in the queue
+	acquired := make(chan error, 1)
+	go func() {
+		release, err := l.acquire(context.Background(), "github")
+		if err == nil {
+			defer release()
+		}
+		acquired <- err
+	}()
+	waitForQueued(t, l, 1)
+	_, err = l.acquire(context.Background(), "notion")
+	testhelpers.AssertTrue(t, errors.Is(err, ErrUpstreamCallsSaturated), "a call over the queue should fail right away")
+
+	release1()
+	testhelpers.AssertNoError(t, <-acquired)
+	testhelpers.AssertEqual(t, 1, len(metrics.waits["github"]))
+	testhelpers.AssertEqual(t, 0, len(metrics.waits["slack"]))
+
+	// a queued call gives up with its context
+	release1, err = l.acquire(context.Background(), "github")
+	testhelpers.AssertNoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		_, err := l.acquire(ctx, "github")
+		acquired <- err
+	}()
+	waitForQueued(t, l, 1)
+	cancel()
+	testhelpers.AssertTrue(t, errors.Is(<-acquired, context.Canceled), "a canceled call should stop waiting")
+
+	// once draining, the queued calls fail and no call waits anymore
+	go func() {
+		_, err := l.acquire(context.Background(), "github")
+		acquired <- err
+	}()
+	waitForQueued(t, l, 1)
+	l.drain()
+	testhelpers.AssertTrue(t, errors.Is(<-acquired, ErrShuttingDown), "the queued calls should fail when draining")
+	_, err = l.acquire(context.Background(), "github")
+	testhelpers.AssertTrue(t, errors.Is(err, ErrShuttingDown), "no call should wait while draining")
+
+	// the calls in flight can still complete, and free their slots for new calls
+	release1()
+	release2()
+	release, err := l.acquire(context.Background(), "github")
+	testhelpers.AssertNoError(t, err)
+	release()
+}
+
+func TestInvokeToolIsLimited(t *testing.T) {
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(&model.McpServer{}, &model.Tool{}, &model.Prompt{}))
+	m, err := NewMCPService(&ServiceConfig{
+		DB:                         db,
+		McpProxyServer:             server.NewMCPServer("proxy", "0.0.0"),
+		SseMcpProxyServer:          server.NewMCPServer("sse-proxy", "0.0.0"),
+		Metrics:                    telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout:    1,
+		MaxConcurrentUpstreamCalls: 1,
+		MaxQueuedUpstreamCalls:     -1,
+	})
+	testhelpers.AssertNoError(t, err)
+	t.Cleanup(m.Shutdown)
+
+	started, unblock := make(chan struct{}), make(chan struct{})
+	upstream := server.NewMCPServer("slow", "0.0.0")
+	upstream.AddTool(mcp.NewTool("wait"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-unblock
+		return mcp.NewToolResultText("done"), nil
+	})
+	upstreamServer := server.NewTestStreamableHTTPServer(upstream)
+	defer upstreamServer.Close()
+	slow, err := model.NewStreamableHTTPServer("slow", "", upstreamServer.URL+"/mcp", "", types.SessionModeStateless)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, m.RegisterMcpServer(context.Background(), slow))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.InvokeTool(context.Background(), "slow__wait", nil)
+		done <- err
+	}()
+	<-started
+	// the only slot is taken and calls can't wait for it
+	_, err = m.InvokeTool(context.Background(), "slow__wait", nil)
+	testhelpers.AssertTrue(t, errors.Is(err, ErrUpstreamCallsSaturated), "the call over the limit should fail")
+
+	close(unblock)
+	testhelpers.AssertNoError(t, <-done)
+}
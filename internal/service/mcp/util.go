@@ -8,9 +8,9 @@ import (
 	"io"
 	"log"
 	"net"
+	"net/http"
 	"net/url"
 	"os"
-	"regexp"
 	"strings"
 	"syscall"
 	"time"
@@ -32,32 +32,10 @@ const (
 	serverPromptNameSep = "__"
 )
 
-// Only allow letters, numbers, hyphens, and underscores
-var validServerName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
-
 // validateServerName checks if the server name is valid.
-// Server name must not contain double underscores `__`.
-// Tools in mcpjungle are identified by `<server_name>__<tool_name>` (eg- `github__git_commit`)
-// When a tool is invoked, the text before the first __ is treated as the server name.
-// eg- In `aws__ec2__create_sg`, `aws` is the MCP server's name and `ec2__create_sg` is the tool.
+// The rules are defined in the types package so that clients can validate names before sending them to the server.
 func validateServerName(name string) error {
-	if name == "" {
-		return fmt.Errorf("invalid server name: '%s' must not be empty", name)
-	}
-	if !validServerName.MatchString(name) {
-		return fmt.Errorf("invalid server name: '%s' must follow the regular expression %s", name, validServerName)
-	}
-	if strings.Contains(name, serverToolNameSep) {
-		return fmt.Errorf("invalid server name: '%s' must not contain multiple consecutive underscores", name)
-	}
-	if strings.HasSuffix(name, string(serverToolNameSep[0])) {
-		// Don't allow a trailing underscore in server name.
-		// This avoids situations like this: `aws_` + `ec2_create_sg` -> `aws___ec2_create_sg`
-		//  splitting this would result in: `aws` + `_ec2_create_sg` because we always split on
-		//  the first occurrence of `__`
-		return fmt.Errorf("invalid server name: '%s' must not end with an underscore", name)
-	}
-	return nil
+	return types.ValidateServerName(name)
 }
 
 // mergeServerToolNames combines the server name and tool name into a single tool name unique across the registry.
@@ -70,6 +48,13 @@ func splitServerToolName(name string) (string, string, bool) {
 	return strings.Cut(name, serverToolNameSep)
 }
 
+// ToolServerName returns the name of the MCP server providing the tool with the given canonical name.
+// It is empty if the name is not a canonical tool name.
+func ToolServerName(name string) string {
+	serverName, _, _ := splitServerToolName(name)
+	return serverName
+}
+
 // mergeServerPromptNames combines the server name and prompt name into a single prompt name unique across the registry.
 func mergeServerPromptNames(s, p string) string {
 	return s + serverPromptNameSep + p
@@ -152,7 +137,10 @@ func convertPromptModelToMcpObject(p *model.Prompt) (mcp.Prompt, error) {
 }
 
 // createHTTPMcpServerConn creates a new connection with a streamable http MCP server and returns the client.
-func createHTTPMcpServerConn(ctx context.Context, s *model.McpServer, initReqTimeoutSec int) (*client.Client, error) {
+// Its requests are sent through the server's connection pool in pools.
+func createHTTPMcpServerConn(
+	ctx context.Context, s *model.McpServer, initReqTimeoutSec int, pools *httpPools,
+) (*client.Client, error) {
 	conf, err := s.GetStreamableHTTPConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get streamable HTTP config for MCP server %s: %w", s.Name, err)
@@ -166,6 +154,9 @@ func createHTTPMcpServerConn(ctx context.Context, s *model.McpServer, initReqTim
 		})
 		opts = append(opts, o)
 	}
+	if httpClient := pools.client(s.Name, conf.Pool); httpClient != nil {
+		opts = append(opts, transport.WithHTTPBasicClient(httpClient))
+	}
 
 	c, err := client.NewStreamableHttpClient(conf.URL, opts...)
 	if err != nil {
@@ -228,23 +219,51 @@ func captureStdioServerStderr(name string, c *client.Client) {
 }
 
 // runStdioServer runs a stdio MCP server and returns the client.
-func runStdioServer(ctx context.Context, s *model.McpServer, initReqTimeoutSec int) (*client.Client, error) {
+// The servers configured with a container are launched in a container of the Docker daemon of containers.
+// The messages the server writes are limited to the size returned by maxMessageBytes, see messageLimiter.
+func runStdioServer(
+	ctx context.Context, s *model.McpServer, initReqTimeoutSec int, containers *containerRuntime,
+	maxMessageBytes func() int64,
+) (*client.Client, error) {
 	conf, err := s.GetStdioConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stdio config for MCP server %s: %w", s.Name, err)
 	}
 
-	// Convert the environment map to a slice of strings in the format "KEY=VALUE"
-	envVars := make([]string, 0)
-	if conf.Env != nil {
-		for k, v := range conf.Env {
-			envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
+	var c *client.Client
+	if conf.Container != nil {
+		ctr, err := containers.start(ctx, s, conf)
+		if err != nil {
+			return nil, err
+		}
+		stdout := newMessageLimiter(ctr.stream.Stdout, maxMessageBytes, false, s.Name)
+		t := transport.NewIO(stdout, &containerStdin{runtime: containers, ctr: ctr}, ctr.stream.Stderr)
+		if err := t.Start(context.Background()); err != nil {
+			_ = t.Close()
+			return nil, fmt.Errorf("failed to start stdio transport for MCP server: %w", err)
+		}
+		c = client.NewClient(t)
+		containers.track(c, ctr)
+	} else {
+		// Convert the environment map to a slice of strings in the format "KEY=VALUE"
+		envVars := make([]string, 0)
+		if conf.Env != nil {
+			for k, v := range conf.Env {
+				envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
+			}
 		}
-	}
 
-	c, err := client.NewStdioMCPClient(conf.Command, envVars, conf.Args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create stdio client for MCP server: %w", err)
+		command, args := conf.CommandLine()
+		p, err := startStdioProcess(command, args, envVars)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start the command of the stdio MCP server: %w", err)
+		}
+		t := transport.NewIO(newMessageLimiter(p.Stdout(), maxMessageBytes, false, s.Name), p, p.Stderr())
+		if err := t.Start(context.Background()); err != nil {
+			_ = t.Close()
+			return nil, fmt.Errorf("failed to start stdio transport for MCP server: %w", err)
+		}
+		c = client.NewClient(t)
 	}
 
 	// currently, we only capture the stderr output in the mcpjungle server logs.
@@ -264,6 +283,8 @@ func runStdioServer(ctx context.Context, s *model.McpServer, initReqTimeoutSec i
 
 	_, err = c.Initialize(initCtx, initRequest)
 	if err != nil {
+		// the process or the container would keep running otherwise
+		_ = c.Close()
 		if errors.Is(err, context.DeadlineExceeded) {
 			return nil, fmt.Errorf(
 				"initialization request to MCP server timed out after %d seconds,"+
@@ -278,13 +299,20 @@ func runStdioServer(ctx context.Context, s *model.McpServer, initReqTimeoutSec i
 }
 
 // createSSEMcpServerConn creates a new connection with an SSE transport-based MCP server and returns the client.
-func createSSEMcpServerConn(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+// The messages of its event stream are limited to the size returned by maxMessageBytes, see messageLimiter.
+func createSSEMcpServerConn(
+	ctx context.Context, s *model.McpServer, maxMessageBytes func() int64,
+) (*client.Client, error) {
 	conf, err := s.GetSSEConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get SSE transport config for MCP server %s: %w", s.Name, err)
 	}
 
-	var opts []transport.ClientOption
+	opts := []transport.ClientOption{
+		transport.WithHTTPClient(&http.Client{
+			Transport: &messageLimitTransport{base: http.DefaultTransport, limit: maxMessageBytes, serverName: s.Name},
+		}),
+	}
 	if conf.BearerToken != "" {
 		// If bearer token is provided, set the Authorization header
 		o := transport.WithHeaders(map[string]string{
@@ -317,9 +345,47 @@ func createSSEMcpServerConn(ctx context.Context, s *model.McpServer) (*client.Cl
 	return c, nil
 }
 
-func newMcpServerSession(ctx context.Context, s *model.McpServer, initReqTimeoutSec int) (*client.Client, error) {
+// ErrMcpServerUnreachable is matched by the errors returned when a connection to an MCP server cannot be established.
+var ErrMcpServerUnreachable = errors.New("MCP server is unreachable")
+
+// unreachableError is the failure to connect to an MCP server, it matches ErrMcpServerUnreachable.
+type unreachableError struct {
+	err error
+}
+
+func (e *unreachableError) Error() string { return e.err.Error() }
+
+func (e *unreachableError) Unwrap() []error { return []error{e.err, ErrMcpServerUnreachable} }
+
+// newMcpServerSession connects to an MCP server. The errors it returns match ErrMcpServerUnreachable,
+// or ErrCredentialUnavailable if a secret its credentials reference can't be resolved with secrets.
+// The requests to streamable http servers are sent through their connection pool in pools,
+// and the stdio servers configured with a container are launched by containers.
+// For the stdio servers launched from the latest release of a package, the changes of the release are looked up
+// in packages.
+func newMcpServerSession(
+	ctx context.Context, s *model.McpServer, initReqTimeoutSec int,
+	pools *httpPools, secrets SecretResolver, containers *containerRuntime, packages *packageRegistry,
+	maxMessageBytes func() int64,
+) (*client.Client, error) {
+	s, err := withResolvedCredentials(ctx, s, secrets)
+	if err != nil {
+		return nil, err
+	}
+	packages.checkDrift(s)
+	mcpClient, err := connectMcpServer(ctx, s, initReqTimeoutSec, pools, containers, maxMessageBytes)
+	if err != nil {
+		return nil, &unreachableError{err: err}
+	}
+	return mcpClient, nil
+}
+
+func connectMcpServer(
+	ctx context.Context, s *model.McpServer, initReqTimeoutSec int, pools *httpPools, containers *containerRuntime,
+	maxMessageBytes func() int64,
+) (*client.Client, error) {
 	if s.Transport == types.TransportStreamableHTTP {
-		mcpClient, err := createHTTPMcpServerConn(ctx, s, initReqTimeoutSec)
+		mcpClient, err := createHTTPMcpServerConn(ctx, s, initReqTimeoutSec, pools)
 		if err != nil {
 			return nil, fmt.Errorf(
 				"failed to create connection to streamable http MCP server %s: %w", s.Name, err,
@@ -329,7 +395,7 @@ func newMcpServerSession(ctx context.Context, s *model.McpServer, initReqTimeout
 	}
 
 	if s.Transport == types.TransportSSE {
-		mcpClient, err := createSSEMcpServerConn(ctx, s)
+		mcpClient, err := createSSEMcpServerConn(ctx, s, maxMessageBytes)
 		if err != nil {
 			return nil, fmt.Errorf(
 				"failed to create connection to SSE MCP server %s: %w", s.Name, err,
@@ -338,11 +404,27 @@ func newMcpServerSession(ctx context.Context, s *model.McpServer, initReqTimeout
 		return mcpClient, nil
 	}
 
+	if s.Transport == types.TransportOpenAPI {
+		mcpClient, err := createOpenAPIMcpServerConn(ctx, s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create openapi adapter for MCP server %s: %w", s.Name, err)
+		}
+		return mcpClient, nil
+	}
+
+	if s.Transport == types.TransportWebhookTool {
+		mcpClient, err := createWebhookToolMcpServerConn(ctx, s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create webhook tool adapter for MCP server %s: %w", s.Name, err)
+		}
+		return mcpClient, nil
+	}
+
 	// A new sub-process is spun up for each call to a STDIO mcp server.
 	// This is especially a problem for the MCP proxy server, which is expected to call tools frequently.
 	// This causes a serious performance hit, but is easy to implement so it is used for now.
 	// For stateful sessions, use the SessionManager to keep the process running.
-	mcpClient, err := runStdioServer(ctx, s, initReqTimeoutSec)
+	mcpClient, err := runStdioServer(ctx, s, initReqTimeoutSec, containers, maxMessageBytes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run stdio MCP server %s: %w", s.Name, err)
 	}
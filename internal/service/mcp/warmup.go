@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ErrServerWarmingUp is returned by the calls to the tools of an MCP server whose first synchronization
+// since the registry started is not done yet, see WarmUp.
+var ErrServerWarmingUp = errors.New("warming up")
+
+// serverSyncState is the outcome of the last synchronization of an MCP server.
+type serverSyncState struct {
+	syncedAt time.Time
+	err      string
+}
+
+// syncStates keeps the outcome of the last synchronization of the registered MCP servers, keyed by server name,
+// and the servers whose first synchronization is pending. Like the health checks, they are only kept in memory.
+type syncStates struct {
+	mu        sync.RWMutex
+	servers   map[string]*serverSyncState
+	warmingUp map[string]struct{}
+}
+
+// WarmUp marks the registered MCP servers that SyncServers synchronizes without starting them as warming up:
+// calls to their tools fail with ErrServerWarmingUp until their synchronization is done, whether it succeeds or not.
+// It is meant to be called when the registry starts, right before the servers are synchronized in the background,
+// so that clients don't get their calls routed by tools that may be stale or to servers that can't be reached yet.
+func (m *MCPService) WarmUp() error {
+	servers, err := m.ListMcpServers()
+	if err != nil {
+		return err
+	}
+	m.syncs.mu.Lock()
+	defer m.syncs.mu.Unlock()
+	m.syncs.warmingUp = make(map[string]struct{}, len(servers))
+	for _, s := range servers {
+		if s.LazyStart && m.sessionManager.State(s.Name) != types.ServerStateRunning {
+			continue
+		}
+		m.syncs.warmingUp[s.Name] = struct{}{}
+	}
+	return nil
+}
+
+// EndWarmUp ends the warm-up of the servers whose synchronization was not done, eg- because it was canceled.
+func (m *MCPService) EndWarmUp() {
+	m.syncs.mu.Lock()
+	defer m.syncs.mu.Unlock()
+	m.syncs.warmingUp = nil
+}
+
+// WarmingUpServers returns the names of the MCP servers that are warming up, sorted.
+func (m *MCPService) WarmingUpServers() []string {
+	m.syncs.mu.RLock()
+	defer m.syncs.mu.RUnlock()
+	names := make([]string, 0, len(m.syncs.warmingUp))
+	for name := range m.syncs.warmingUp {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkWarmedUp returns ErrServerWarmingUp if the MCP server is warming up.
+func (m *MCPService) checkWarmedUp(serverName string) error {
+	m.syncs.mu.RLock()
+	defer m.syncs.mu.RUnlock()
+	if _, ok := m.syncs.warmingUp[serverName]; ok {
+		return fmt.Errorf(
+			"%w: MCP server %s is being synchronized since the registry started, retry shortly",
+			ErrServerWarmingUp, serverName,
+		)
+	}
+	return nil
+}
+
+// recordSync stores the outcome of the synchronization of an MCP server, which ends its warm-up.
+// Skipped servers keep the outcome of their last synchronization.
+func (m *MCPService) recordSync(r types.ServerSyncResult) {
+	if r.Status == types.ServerSyncSkipped {
+		return
+	}
+	m.syncs.mu.Lock()
+	defer m.syncs.mu.Unlock()
+	if m.syncs.servers == nil {
+		m.syncs.servers = make(map[string]*serverSyncState)
+	}
+	m.syncs.servers[r.Name] = &serverSyncState{syncedAt: time.Now(), err: r.Error}
+	delete(m.syncs.warmingUp, r.Name)
+}
+
+// forgetServerSync drops the outcome of the last synchronization of an MCP server,
+// eg- because it was deregistered, and ends its warm-up.
+func (m *MCPService) forgetServerSync(name string) {
+	m.syncs.mu.Lock()
+	defer m.syncs.mu.Unlock()
+	delete(m.syncs.servers, name)
+	delete(m.syncs.warmingUp, name)
+}
+
+// serverSyncHealth fills the synchronization fields of the health of an MCP server.
+// The caller must hold m.syncs.mu for reading.
+func (m *MCPService) serverSyncHealth(sh *types.ServerHealth) {
+	_, sh.WarmingUp = m.syncs.warmingUp[sh.Name]
+	if s, ok := m.syncs.servers[sh.Name]; ok {
+		syncedAt := s.syncedAt.UTC()
+		sh.SyncedAt = &syncedAt
+		sh.SyncError = s.err
+	}
+}
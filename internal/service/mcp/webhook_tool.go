@@ -0,0 +1,240 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// createWebhookToolMcpServerConn serves the endpoint of a webhook_tool server as the single tool of an in-process
+// MCP server, named after the server, and returns a client connected to it.
+// Each tool call sends its arguments to the endpoint.
+func createWebhookToolMcpServerConn(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+	conf, err := s.GetWebhookToolConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook tool config for MCP server %s: %w", s.Name, err)
+	}
+	schema := conf.InputSchema
+	if len(schema) == 0 {
+		schema = json.RawMessage(`{"type":"object"}`)
+	}
+	var parsed map[string]any
+	if err := json.Unmarshal(schema, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid input schema of webhook tool %s: %w", s.Name, err)
+	}
+	description := conf.Description
+	if description == "" {
+		description = s.Description
+	}
+
+	// the adapter declares prompts so that listing them returns none instead of failing
+	srv := server.NewMCPServer(
+		s.Name, "0.1.0", server.WithToolCapabilities(false), server.WithPromptCapabilities(false),
+	)
+	srv.AddTool(mcp.NewToolWithRawSchema(s.Name, description, schema), webhookToolHandler(conf, parsed))
+	return connectInProcessAdapter(ctx, srv, "webhook tool")
+}
+
+// webhookToolHandler sends the arguments of a tool call to the endpoint of a webhook tool, once they are validated
+// against its schema. Invalid arguments and failures of the endpoint are returned as error results.
+func webhookToolHandler(conf *types.WebhookToolConfig, schema map[string]any) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		if args == nil {
+			args = map[string]any{}
+		}
+		if err := validateAgainstSchema(schema, args, "arguments"); err != nil {
+			return mcp.NewToolResultErrorf("invalid arguments: %v", err), nil
+		}
+		req, err := newWebhookToolRequest(ctx, conf, args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return mcp.NewToolResultErrorf("request to %s %s failed: %v", conf.Method, conf.URL, err), nil
+		}
+		defer resp.Body.Close()
+		return webhookToolResult(conf, resp)
+	}
+}
+
+// newWebhookToolRequest builds the request sending the arguments of a tool call to the endpoint of a webhook tool:
+// as its JSON body, or as its query for GET and HEAD requests, which have none.
+func newWebhookToolRequest(
+	ctx context.Context, conf *types.WebhookToolConfig, args map[string]any,
+) (*http.Request, error) {
+	u := conf.URL
+	var body io.Reader
+	if conf.Method == http.MethodGet || conf.Method == http.MethodHead {
+		query := url.Values{}
+		for k, v := range args {
+			query.Set(k, openAPIParamValue(v))
+		}
+		if len(query) > 0 {
+			sep := "?"
+			if strings.Contains(u, "?") {
+				sep = "&"
+			}
+			u += sep + query.Encode()
+		}
+	} else {
+		data, err := json.Marshal(args)
+		if err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, conf.Method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s %s: %w", conf.Method, conf.URL, err)
+	}
+	for k, v := range conf.Headers {
+		req.Header.Set(k, v)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json, text/*;q=0.9")
+	setOpenAPIAuth(req, conf.Auth)
+	return req, nil
+}
+
+// webhookToolResult converts the response of the endpoint of a webhook tool into the result of the tool call:
+// its status and body, as text and as structured content. Responses with a non-2xx status are error results.
+func webhookToolResult(conf *types.WebhookToolConfig, resp *http.Response) (*mcp.CallToolResult, error) {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, openAPIMaxResponseBytes+1))
+	if err != nil {
+		return mcp.NewToolResultErrorf("failed to read the response of %s %s: %v", conf.Method, conf.URL, err), nil
+	}
+	if len(data) > openAPIMaxResponseBytes {
+		return mcp.NewToolResultErrorf(
+			"the response of %s %s is larger than %d bytes", conf.Method, conf.URL, openAPIMaxResponseBytes,
+		), nil
+	}
+
+	var body any = string(data)
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if isJSONMediaType(mediaType) {
+		var v any
+		if err := json.Unmarshal(data, &v); err == nil {
+			body = v
+		}
+	}
+	text := resp.Status
+	if len(bytes.TrimSpace(data)) > 0 {
+		text += "\n\n" + string(data)
+	}
+	result := mcp.NewToolResultStructured(map[string]any{"status": resp.StatusCode, "body": body}, text)
+	result.IsError = resp.StatusCode < 200 || resp.StatusCode > 299
+	return result, nil
+}
+
+// validateAgainstSchema checks a value decoded from JSON against a JSON schema, path names the value in the errors.
+// It supports the keywords describing the shape of the arguments of tools: type, enum, properties, required,
+// additionalProperties and items. The other keywords are ignored.
+func validateAgainstSchema(schema map[string]any, value any, path string) error {
+	if t, ok := schema["type"]; ok && !matchesSchemaType(t, value) {
+		return fmt.Errorf("%s must be of type %v", path, t)
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := slices.ContainsFunc(enum, func(e any) bool {
+			a, _ := json.Marshal(e)
+			b, _ := json.Marshal(value)
+			return bytes.Equal(a, b)
+		})
+		if !found {
+			return fmt.Errorf("%s must be one of %v", path, enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		var errs []error
+		if required, ok := schema["required"].([]any); ok {
+			for _, r := range required {
+				if name, ok := r.(string); ok {
+					if _, present := v[name]; !present {
+						errs = append(errs, fmt.Errorf("%s.%s is required", path, name))
+					}
+				}
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			sub, ok := properties[name].(map[string]any)
+			if !ok {
+				if schema["additionalProperties"] == false {
+					errs = append(errs, fmt.Errorf("%s.%s is not an accepted property", path, name))
+				}
+				continue
+			}
+			if err := validateAgainstSchema(sub, v[name], path+"."+name); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	case []any:
+		items, ok := schema["items"].(map[string]any)
+		if !ok {
+			return nil
+		}
+		var errs []error
+		for i, item := range v {
+			if err := validateAgainstSchema(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// matchesSchemaType reports whether a value decoded from JSON has the type of a schema,
+// which is either a type name or a list of them.
+func matchesSchemaType(t any, value any) bool {
+	if names, ok := t.([]any); ok {
+		return slices.ContainsFunc(names, func(t any) bool { return matchesSchemaType(t, value) })
+	}
+	switch t {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "null":
+		return value == nil
+	}
+	// unknown types are left to the endpoint to check
+	return true
+}
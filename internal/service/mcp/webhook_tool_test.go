@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestWebhookToolServer(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		if r.URL.Query().Get("env") == "prod" || gotBody == `{"env":"prod","service":"api"}` {
+			http.Error(w, "prod deploys are frozen", http.StatusConflict)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"notified":true}`))
+	}))
+	defer hook.Close()
+
+	db, err := testhelpers.CreateTestDB()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.AutoMigrate(&model.McpServer{}, &model.Tool{}, &model.Prompt{}))
+	m, err := NewMCPService(&ServiceConfig{
+		DB:                      db,
+		McpProxyServer:          server.NewMCPServer("proxy", "0.0.0"),
+		SseMcpProxyServer:       server.NewMCPServer("sse-proxy", "0.0.0"),
+		Metrics:                 telemetry.NewNoopCustomMetrics(),
+		McpServerInitReqTimeout: 5,
+	})
+	testhelpers.AssertNoError(t, err)
+	defer m.Shutdown()
+
+	conf := &types.WebhookToolConfig{
+		URL: hook.URL + "/hooks/deploy", Method: http.MethodPost,
+		Headers:     map[string]string{"X-Source": "mcpjungle"},
+		Auth:        &types.OpenAPIAuth{Type: types.OpenAPIAuthBearer, Value: "secret"},
+		Description: "Notify the team of a deploy",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"service": {"type": "string"},
+				"env": {"enum": ["staging", "prod"]},
+				"replicas": {"type": "integer"}
+			},
+			"required": ["service"],
+			"additionalProperties": false
+		}`),
+	}
+	deploy, err := model.NewWebhookToolServer("deploy_notify", "", conf)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, m.RegisterMcpServer(context.Background(), deploy))
+
+	// the single tool is named after the server
+	tools, err := m.ListToolsByServer("deploy_notify")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(tools))
+	testhelpers.AssertEqual(t, "deploy_notify__deploy_notify", tools[0].Name)
+	testhelpers.AssertEqual(t, "Notify the team of a deploy", tools[0].Description)
+
+	res, err := m.InvokeTool(context.Background(), "deploy_notify__deploy_notify", map[string]any{
+		"service": "api", "replicas": float64(3),
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertFalse(t, res.IsError, "the call should succeed")
+	testhelpers.AssertEqual(t, `{"replicas":3,"service":"api"}`, gotBody)
+	testhelpers.AssertEqual(t, "Bearer secret", got.Header.Get("Authorization"))
+	testhelpers.AssertEqual(t, "mcpjungle", got.Header.Get("X-Source"))
+	testhelpers.AssertEqual(t, "application/json", got.Header.Get("Content-Type"))
+	structured := res.StructuredContent.(map[string]any)
+	testhelpers.AssertEqual(t, float64(http.StatusOK), structured["status"])
+	testhelpers.AssertEqual(t, true, structured["body"].(map[string]any)["notified"])
+
+	// non-2xx responses are error results holding the response
+	res, err = m.InvokeTool(context.Background(), "deploy_notify__deploy_notify", map[string]any{
+		"service": "api", "env": "prod",
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, res.IsError, "a 409 response should be an error result")
+	testhelpers.AssertStringContains(t, res.Content[0]["text"].(string), "409 Conflict")
+	testhelpers.AssertStringContains(t, res.Content[0]["text"].(string), "prod deploys are frozen")
+
+	// invalid arguments are rejected before anything is sent
+	got = nil
+	res, err = m.InvokeTool(context.Background(), "deploy_notify__deploy_notify", map[string]any{
+		"env": "dev", "replicas": 1.5, "force": true,
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, res.IsError, "invalid arguments should be an error result")
+	text := res.Content[0]["text"].(string)
+	for _, problem := range []string{
+		"arguments.service is required",
+		"arguments.env must be one of [staging prod]",
+		"arguments.replicas must be of type integer",
+		"arguments.force is not an accepted property",
+	} {
+		testhelpers.AssertStringContains(t, text, problem)
+	}
+	testhelpers.AssertTrue(t, got == nil, "invalid arguments must not be sent to the endpoint")
+}
+
+func TestWebhookToolGetRequest(t *testing.T) {
+	conf := &types.WebhookToolConfig{URL: "https://automation.example.com/status?team=platform", Method: "GET"}
+	req, err := newWebhookToolRequest(context.Background(), conf, map[string]any{"service": "api"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "team=platform&service=api", req.URL.RawQuery)
+	testhelpers.AssertTrue(t, req.Body == nil, "GET requests have no body")
+}
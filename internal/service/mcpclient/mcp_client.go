@@ -21,11 +21,19 @@ func NewMCPClientService(db *gorm.DB) *McpClientService {
 
 // ListClients retrieves all MCP clients known to mcpjungle from the database
 func (m *McpClientService) ListClients() ([]*model.McpClient, error) {
+	clients, _, err := m.ListClientsPage(model.Page{})
+	return clients, err
+}
+
+// ListClientsPage retrieves a page of the MCP clients, along with the ID to list the next page after
+// (0 if there are no more clients).
+func (m *McpClientService) ListClientsPage(p model.Page) ([]*model.McpClient, uint, error) {
 	var clients []*model.McpClient
-	if err := m.db.Find(&clients).Error; err != nil {
-		return nil, err
+	if err := p.Apply(m.db).Find(&clients).Error; err != nil {
+		return nil, 0, err
 	}
-	return clients, nil
+	clients, next := model.NextPage(p, clients, func(c *model.McpClient) uint { return c.ID })
+	return clients, next, nil
 }
 
 // CreateClient creates a new MCP client in the database.
@@ -70,6 +78,11 @@ func (m *McpClientService) GetClientByToken(token string) (*model.McpClient, err
 	return &client, nil
 }
 
+// ResolveClientName returns the name of the MCP client identified by ident, which is either its UUID or its name.
+func (m *McpClientService) ResolveClientName(ident string) (string, error) {
+	return model.ResolveName(m.db, &model.McpClient{}, "name", ident)
+}
+
 // DeleteClient removes an MCP client from the database and immediately revokes its access.
 // It is an idempotent operation. Deleting a client that does not exist will not return an error.
 func (m *McpClientService) DeleteClient(name string) error {
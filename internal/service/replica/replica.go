@@ -0,0 +1,307 @@
+// Package replica coordinates the replicas of the registry that share a database, eg- behind a load balancer.
+// The replicas elect a leader through a lease stored in the database, which is the only one to run the background
+// jobs, and they keep the state they hold in memory up to date with the changes the others make to the database.
+package replica
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LeaseName is the name of the lease held by the leader.
+const LeaseName = "background_jobs"
+
+// Defaults of the coordination settings.
+const (
+	DefaultLeaseTTL     = 15 * time.Second
+	DefaultSyncInterval = 5 * time.Second
+)
+
+// Config holds the coordination settings of a Coordinator. Zero values are replaced by the defaults.
+type Config struct {
+	// ID identifies the replica among those sharing the database.
+	// If empty, an ID made of the host name and a random suffix is used.
+	ID string
+	// LeaseTTL is how long the lease of the leader lasts unless it is renewed, ie- how long the background jobs stop
+	// for when the leader fails. The leader renews it three times per TTL.
+	LeaseTTL time.Duration
+	// SyncInterval is how often the versions of model.VersionedTables are read to detect the changes made by the
+	// other replicas, see WatchTables. If negative, they are not read.
+	SyncInterval time.Duration
+}
+
+// Coordinator takes part in the election of the leader on behalf of a replica, and watches the versions of the tables
+// for the changes made by the other replicas.
+//
+// The expiry of the lease is compared with the local clock of every replica, so the clocks of the replicas must be
+// synchronized, eg- with NTP, within a fraction of the TTL.
+type Coordinator struct {
+	db           *gorm.DB
+	id           string
+	leaseTTL     time.Duration
+	syncInterval time.Duration
+	versions     *db.TableVersions
+	// now returns the current time, it is a field so that tests can control the clock.
+	now func() time.Time
+
+	mu sync.RWMutex
+	// lease is the lease as of the last election, nil if it couldn't be read yet
+	lease *model.LeaderLease
+	// leading is true while this replica holds the lease
+	leading bool
+	// tableVersions are the versions of the watched tables when they were last read
+	tableVersions []uint
+	// onTablesChanged is called with the tables that changed since they were last read, set by WatchTables
+	onTablesChanged func(tables []string)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	loops  sync.WaitGroup
+}
+
+// NewCoordinator creates a Coordinator, runs the first election and starts renewing or taking over the lease
+// in the background. Call Close to stop.
+// The first election is over when it returns, so IsLeader tells whether this replica is the leader right away.
+// The versions of the tables are read too, WatchTables reports the changes made from then on.
+func NewCoordinator(conn *gorm.DB, cfg *Config) (*Coordinator, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	c := &Coordinator{
+		db:           conn,
+		id:           cfg.ID,
+		leaseTTL:     cfg.LeaseTTL,
+		syncInterval: cfg.SyncInterval,
+		versions:     db.NewTableVersions(conn),
+		now:          time.Now,
+	}
+	if c.id == "" {
+		c.id = defaultID()
+	}
+	if c.leaseTTL <= 0 {
+		c.leaseTTL = DefaultLeaseTTL
+	}
+	if c.syncInterval == 0 {
+		c.syncInterval = DefaultSyncInterval
+	}
+
+	if c.syncInterval > 0 {
+		versions, err := c.versions.Get(model.VersionedTables...)
+		if err != nil {
+			return nil, err
+		}
+		c.tableVersions = versions
+	}
+	c.elect()
+
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	c.every(c.leaseTTL/3, c.elect)
+	return c, nil
+}
+
+// WatchTables calls fn with the tables of model.VersionedTables that changed, once every SyncInterval, from the
+// versions read by NewCoordinator on. It is meant to be called once, when the state fn updates was loaded.
+// It does nothing if SyncInterval is negative.
+func (c *Coordinator) WatchTables(fn func(tables []string)) {
+	if c.syncInterval < 0 {
+		return
+	}
+	c.mu.Lock()
+	c.onTablesChanged = fn
+	c.mu.Unlock()
+	c.every(c.syncInterval, c.syncTables)
+}
+
+// defaultID returns an ID made of the host name, which tells the operators which replica it is,
+// and a random suffix, which tells the replicas started on the same host apart.
+func defaultID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "mcpjungle"
+	}
+	return host + "-" + uuid.NewString()[:8]
+}
+
+// every runs fn once every interval in the background, until the Coordinator is closed.
+func (c *Coordinator) every(interval time.Duration, fn func()) {
+	c.loops.Add(1)
+	go func() {
+		defer c.loops.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				fn()
+			}
+		}
+	}()
+}
+
+// Close stops the background work and releases the lease if this replica holds it,
+// so that another replica takes over right away instead of once it expired.
+func (c *Coordinator) Close() {
+	c.cancel()
+	c.loops.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.leading {
+		return
+	}
+	c.leading = false
+	err := db.RetryOnBusy(func() error {
+		return c.db.Model(&model.LeaderLease{}).
+			Where("name = ? AND holder = ?", LeaseName, c.id).
+			Updates(map[string]any{"expires_at": c.now(), "term": gorm.Expr("term + 1")}).Error
+	})
+	if err != nil {
+		log.Printf("[WARN] failed to release the leader lease, another replica takes over once it expired: %v", err)
+	}
+}
+
+// ID returns the ID of this replica.
+func (c *Coordinator) ID() string {
+	return c.id
+}
+
+// IsLeader reports whether this replica holds the lease, ie- whether it runs the background jobs.
+func (c *Coordinator) IsLeader() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.leading
+}
+
+// Info describes this replica and the leader as of the last election.
+func (c *Coordinator) Info() types.ReplicaInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	info := types.ReplicaInfo{ID: c.id, Role: types.ReplicaFollower}
+	if c.leading {
+		info.Role = types.ReplicaLeader
+	}
+	if c.lease != nil && c.lease.ExpiresAt.After(c.now()) {
+		since, expires := c.lease.AcquiredAt.UTC(), c.lease.ExpiresAt.UTC()
+		info.Leader = c.lease.Holder
+		info.LeaderSince = &since
+		info.LeaseExpiresAt = &expires
+	}
+	return info
+}
+
+// elect renews the lease if this replica holds it, or takes it over if it expired.
+func (c *Coordinator) elect() {
+	now := c.now()
+	lease, err := c.acquire(now)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	wasLeading := c.leading
+	if err != nil {
+		// the leader can't tell whether another replica took the lease over, so it keeps leading only until
+		// the lease it last renewed expires, by when the others may take it over
+		log.Printf("[WARN] failed to renew or take over the leader lease: %v", err)
+		c.leading = c.leading && now.Before(c.lease.ExpiresAt)
+	} else {
+		c.lease = lease
+		c.leading = lease.Holder == c.id
+	}
+
+	switch {
+	case c.leading && !wasLeading:
+		log.Printf("[INFO] replica %s is now the leader, it runs the background jobs", c.id)
+	case !c.leading && wasLeading:
+		log.Printf("[INFO] replica %s is no longer the leader", c.id)
+	}
+}
+
+// acquire writes the lease for this replica if it holds it or it expired, and returns the lease as it is now.
+// The lease is only written at the term it was read, so only one of the replicas trying to take it over succeeds.
+func (c *Coordinator) acquire(now time.Time) (*model.LeaderLease, error) {
+	var lease model.LeaderLease
+	err := db.RetryOnBusy(func() error {
+		lease = model.LeaderLease{}
+		var leases []model.LeaderLease
+		if err := c.db.Where("name = ?", LeaseName).Limit(1).Find(&leases).Error; err != nil {
+			return err
+		}
+
+		if len(leases) == 0 {
+			lease = model.LeaderLease{
+				Name: LeaseName, Holder: c.id, ExpiresAt: now.Add(c.leaseTTL), AcquiredAt: now, Term: 1,
+			}
+			res := c.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&lease)
+			if res.Error != nil || res.RowsAffected == 1 {
+				return res.Error
+			}
+			// another replica created it first
+			return c.db.Where("name = ?", LeaseName).First(&lease).Error
+		}
+
+		lease = leases[0]
+		if lease.Holder != c.id && lease.ExpiresAt.After(now) {
+			return nil
+		}
+		acquiredAt := lease.AcquiredAt
+		if lease.Holder != c.id {
+			acquiredAt = now
+		}
+		res := c.db.Model(&model.LeaderLease{}).
+			Where("name = ? AND term = ?", LeaseName, lease.Term).
+			Updates(map[string]any{
+				"holder":      c.id,
+				"expires_at":  now.Add(c.leaseTTL),
+				"acquired_at": acquiredAt,
+				"term":        lease.Term + 1,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			// another replica wrote the lease since it was read
+			return c.db.Where("name = ?", LeaseName).First(&lease).Error
+		}
+		lease.Holder, lease.ExpiresAt, lease.AcquiredAt, lease.Term = c.id, now.Add(c.leaseTTL), acquiredAt, lease.Term+1
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &lease, nil
+}
+
+// syncTables reads the versions of the watched tables, and calls OnTablesChanged with those that changed.
+func (c *Coordinator) syncTables() {
+	versions, err := c.versions.Get(model.VersionedTables...)
+	if err != nil {
+		log.Printf("[WARN] failed to read the versions of the tables to detect the changes of the other replicas: %v", err)
+		return
+	}
+
+	c.mu.Lock()
+	var changed []string
+	for i, table := range model.VersionedTables {
+		if versions[i] != c.tableVersions[i] {
+			changed = append(changed, table)
+		}
+	}
+	c.tableVersions = versions
+	onTablesChanged := c.onTablesChanged
+	c.mu.Unlock()
+
+	if len(changed) > 0 && onTablesChanged != nil {
+		onTablesChanged(changed)
+	}
+}
@@ -0,0 +1,87 @@
+package replica
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestLeaderElection(t *testing.T) {
+	testhelpers.ForEachTestDB(t, func(t *testing.T, setup *testhelpers.TestDBSetup) {
+		// the lease outlasts the test, so that the elections only run when the test asks for them
+		cfg := func(id string) *Config { return &Config{ID: id, LeaseTTL: time.Hour, SyncInterval: -1} }
+		a, err := NewCoordinator(setup.DB, cfg("a"))
+		testhelpers.AssertNoError(t, err)
+		defer a.Close()
+		b, err := NewCoordinator(setup.DB, cfg("b"))
+		testhelpers.AssertNoError(t, err)
+		defer b.Close()
+
+		testhelpers.AssertTrue(t, a.IsLeader(), "the first replica should take the lease")
+		testhelpers.AssertFalse(t, b.IsLeader(), "the second replica should follow while the lease is held")
+		info := b.Info()
+		testhelpers.AssertEqual(t, types.ReplicaFollower, info.Role)
+		testhelpers.AssertEqual(t, "a", info.Leader)
+		testhelpers.AssertEqual(t, types.ReplicaLeader, a.Info().Role)
+
+		// renewing keeps the time the lease was acquired
+		a.elect()
+		testhelpers.AssertTrue(t, a.IsLeader(), "the leader should renew its lease")
+		testhelpers.AssertTrue(t, a.Info().LeaderSince.Equal(*info.LeaderSince), "renewing should keep the acquisition time")
+
+		// once the lease expired, the follower takes it over and the former leader steps down
+		later := time.Now().Add(2 * time.Hour)
+		a.now = func() time.Time { return later }
+		b.now = func() time.Time { return later }
+		b.elect()
+		testhelpers.AssertTrue(t, b.IsLeader(), "the follower should take the expired lease over")
+		a.elect()
+		testhelpers.AssertFalse(t, a.IsLeader(), "the former leader should step down once the lease was taken over")
+		testhelpers.AssertEqual(t, "b", a.Info().Leader)
+
+		// the leader releases the lease when it is closed, the follower takes over without waiting for it to expire
+		b.Close()
+		a.elect()
+		testhelpers.AssertTrue(t, a.IsLeader(), "the follower should take the released lease over")
+
+		var lease model.LeaderLease
+		testhelpers.AssertNoError(t, setup.DB.First(&lease, "name = ?", LeaseName).Error)
+		testhelpers.AssertEqual(t, "a", lease.Holder)
+		testhelpers.AssertEqual(t, uint64(5), lease.Term)
+	})
+}
+
+func TestLeaderElectionDefaultID(t *testing.T) {
+	setup := testhelpers.SetupTestDB(t)
+	c, err := NewCoordinator(setup.DB, &Config{SyncInterval: -1})
+	testhelpers.AssertNoError(t, err)
+	defer c.Close()
+	testhelpers.AssertTrue(t, c.ID() != "", "a replica should have an ID")
+	other, err := NewCoordinator(setup.DB, &Config{SyncInterval: -1})
+	testhelpers.AssertNoError(t, err)
+	defer other.Close()
+	testhelpers.AssertTrue(t, c.ID() != other.ID(), "the replicas started on the same host should have different IDs")
+}
+
+func TestWatchTables(t *testing.T) {
+	setup := testhelpers.SetupTestDB(t)
+	var changed []string
+	c, err := NewCoordinator(setup.DB, &Config{ID: "a", SyncInterval: time.Hour})
+	testhelpers.AssertNoError(t, err)
+	defer c.Close()
+	// the changes made before watching are reported too
+	testhelpers.AssertNoError(t, setup.DB.Create(&model.TableVersion{Name: model.TableTools, Version: 1}).Error)
+	c.WatchTables(func(tables []string) { changed = append(changed, tables...) })
+
+	testhelpers.AssertNoError(t, setup.DB.Create(&model.TableVersion{Name: model.TableToolGroups, Version: 3}).Error)
+	c.syncTables()
+	testhelpers.AssertEqual(t, "tools,tool_groups", strings.Join(changed, ","))
+
+	changed = nil
+	c.syncTables()
+	testhelpers.AssertEqual(t, 0, len(changed))
+}
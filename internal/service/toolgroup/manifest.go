@@ -0,0 +1,127 @@
+package toolgroup
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+)
+
+// manifestCache caches the effective tools of the tool groups, resolved from their included tools, included servers
+// and exclusions.
+//
+// A resolved manifest is only valid for the version of its group and for the versions of the tools of the servers
+// the group includes. The version of the tools of a server is incremented every time one of its tools is added,
+// changed or removed, so a change to a server only causes the groups that include it to be resolved again.
+// Tools included by name don't depend on the registry, they are never resolved again.
+type manifestCache struct {
+	mu sync.Mutex
+	// serverVersions counts the changes to the tools of every server, by server name
+	serverVersions map[string]uint64
+	// manifests are the resolved manifests, by group name
+	manifests map[string]*groupManifest
+
+	metrics telemetry.CustomMetrics
+}
+
+// groupManifest holds the effective tools of a tool group and the versions they were resolved at.
+type groupManifest struct {
+	groupID      uint
+	groupVersion uint
+	// serverVersions are the versions of the tools of the included servers when the manifest was resolved
+	serverVersions map[string]uint64
+	tools          []string
+}
+
+func newManifestCache(metrics telemetry.CustomMetrics) *manifestCache {
+	return &manifestCache{
+		serverVersions: make(map[string]uint64),
+		manifests:      make(map[string]*groupManifest),
+		metrics:        metrics,
+	}
+}
+
+// resolve returns the effective tools of group, from the cache if they were resolved since the group and the tools of
+// its servers last changed, otherwise it resolves them using resolver.
+// A group that is not stored in the DB (eg- a new configuration being validated) is always resolved.
+func (c *manifestCache) resolve(group *model.ToolGroup, resolver model.ToolResolver) ([]string, error) {
+	if group.ID == 0 {
+		return c.resolveUncached(group, resolver)
+	}
+	servers, err := group.GetServers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get included servers: %w", err)
+	}
+
+	c.mu.Lock()
+	if m, ok := c.manifests[group.Name]; ok && c.isCurrent(m, group) {
+		c.mu.Unlock()
+		return slices.Clone(m.tools), nil
+	}
+	// the versions are read before resolving: if the tools of a server change meanwhile, the manifest is stale already
+	versions := make(map[string]uint64, len(servers))
+	for _, name := range servers {
+		versions[name] = c.serverVersions[name]
+	}
+	c.mu.Unlock()
+
+	tools, err := c.resolveUncached(group, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.manifests[group.Name] = &groupManifest{
+		groupID:        group.ID,
+		groupVersion:   group.Version,
+		serverVersions: versions,
+		tools:          tools,
+	}
+	return slices.Clone(tools), nil
+}
+
+// isCurrent reports whether m was resolved from the current version of group and of the tools of its servers.
+// c.mu must be held.
+func (c *manifestCache) isCurrent(m *groupManifest, group *model.ToolGroup) bool {
+	if m.groupID != group.ID || m.groupVersion != group.Version {
+		return false
+	}
+	for name, version := range m.serverVersions {
+		if c.serverVersions[name] != version {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *manifestCache) resolveUncached(group *model.ToolGroup, resolver model.ToolResolver) ([]string, error) {
+	start := time.Now()
+	tools, err := group.ResolveEffectiveTools(resolver)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(tools)
+	c.metrics.RecordToolGroupResolution(context.Background(), group.Name, time.Since(start))
+	return tools, nil
+}
+
+// serverToolsChanged records that tools of the given servers were added, changed or removed.
+func (c *manifestCache) serverToolsChanged(servers ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range servers {
+		c.serverVersions[name]++
+	}
+}
+
+// forget removes the manifest of a deleted group.
+func (c *manifestCache) forget(group string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.manifests, group)
+}
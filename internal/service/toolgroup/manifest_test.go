@@ -0,0 +1,237 @@
+package toolgroup
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// groupResolutions counts the resolutions of the tool groups, by group.
+type groupResolutions struct {
+	telemetry.NoopCustomMetrics
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (m *groupResolutions) RecordToolGroupResolution(ctx context.Context, group string, elapsedTime time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[group]++
+}
+
+// take returns the resolutions counted since the last call.
+func (m *groupResolutions) take() map[string]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts := m.counts
+	m.counts = make(map[string]int)
+	return counts
+}
+
+// newManifestTestService creates a tool group service on db, with the given servers each providing the given tools.
+func newManifestTestService(t *testing.T, db *gorm.DB, servers, tools []string) (*ToolGroupService, *groupResolutions) {
+	t.Helper()
+	for _, name := range servers {
+		s := &model.McpServer{Name: name, Transport: "streamable_http", Config: datatypes.JSON(`{"url":"https://example.com/mcp"}`)}
+		testhelpers.AssertNoError(t, db.Create(s).Error)
+		for _, tool := range tools {
+			testhelpers.AssertNoError(t, db.Create(&model.Tool{ServerID: s.ID, Name: tool, InputSchema: model.CompressedJSON(`{"type":"object"}`)}).Error)
+		}
+	}
+	metrics := &groupResolutions{counts: make(map[string]int)}
+	proxy := server.NewMCPServer("test", "0.0.0")
+	mcpService, err := mcp.NewMCPService(&mcp.ServiceConfig{
+		DB:                db,
+		McpProxyServer:    proxy,
+		SseMcpProxyServer: proxy,
+		Metrics:           metrics,
+	})
+	testhelpers.AssertNoError(t, err)
+	s, err := NewToolGroupService(db, mcpService)
+	testhelpers.AssertNoError(t, err)
+	return s, metrics
+}
+
+func TestResolveGroupToolsCache(t *testing.T) {
+	setup := testhelpers.SetupMCPTest(t)
+	db := setup.DB
+	for _, g := range []*model.ToolGroup{
+		{Name: "ci-tools", IncludedServers: datatypes.JSON(`["github"]`), ExcludedTools: datatypes.JSON(`["github__delete_repo"]`)},
+		{Name: "support-agent", IncludedServers: datatypes.JSON(`["slack"]`), IncludedTools: datatypes.JSON(`["github__git_pull"]`)},
+		{Name: "readers", IncludedTools: datatypes.JSON(`["github__git_pull","slack__git_pull"]`)},
+	} {
+		testhelpers.AssertNoError(t, db.Create(g).Error)
+	}
+	s, resolutions := newManifestTestService(t, db, []string{"github", "slack"}, []string{"git_pull", "delete_repo"})
+
+	resolve := func(name string) string {
+		t.Helper()
+		group, err := s.GetToolGroup(name)
+		testhelpers.AssertNoError(t, err)
+		tools, err := s.ResolveGroupTools(group)
+		testhelpers.AssertNoError(t, err)
+		return strings.Join(tools, ",")
+	}
+	// the groups were resolved once to create their MCP servers, they are served from the cache since
+	testhelpers.AssertEqual(t, 3, len(resolutions.take()))
+	testhelpers.AssertEqual(t, "github__git_pull", resolve("ci-tools"))
+	testhelpers.AssertEqual(t, "github__git_pull,slack__delete_repo,slack__git_pull", resolve("support-agent"))
+	testhelpers.AssertEqual(t, "github__git_pull,slack__git_pull", resolve("readers"))
+	testhelpers.AssertEqual(t, 0, len(resolutions.take()))
+
+	// a new tool of github only rebuilds the group that includes the server
+	github, err := s.mcpService.GetMcpServer("github")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.Create(&model.Tool{ServerID: github.ID, Name: "git_push"}).Error)
+	s.handleToolDeletion("github__git_push") // the callbacks of the MCP service are called once a tool changed
+	testhelpers.AssertEqual(t, "github__git_pull,github__git_push", resolve("ci-tools"))
+	testhelpers.AssertEqual(t, "github__git_pull,slack__delete_repo,slack__git_pull", resolve("support-agent"))
+	testhelpers.AssertEqual(t, "github__git_pull,slack__git_pull", resolve("readers"))
+	testhelpers.AssertEqual(t, 1, resolutions.take()["ci-tools"])
+
+	// so does an update of the group
+	_, err = s.UpdateToolGroup("ci-tools", &model.ToolGroup{
+		IncludedServers: datatypes.JSON(`["github"]`),
+		ExcludedTools:   datatypes.JSON(`["github__delete_repo","github__git_push"]`),
+	})
+	testhelpers.AssertNoError(t, err)
+	resolutions.take()
+	testhelpers.AssertEqual(t, "github__git_pull", resolve("ci-tools"))
+	testhelpers.AssertEqual(t, "github__git_pull,slack__git_pull", resolve("readers"))
+	counts := resolutions.take()
+	testhelpers.AssertEqual(t, 1, len(counts))
+	testhelpers.AssertEqual(t, 1, counts["ci-tools"])
+
+	// a group created again with the same name is resolved again
+	testhelpers.AssertNoError(t, s.DeleteToolGroup("readers"))
+	testhelpers.AssertNoError(t, s.CreateToolGroup(&model.ToolGroup{Name: "readers", IncludedTools: datatypes.JSON(`["slack__git_pull"]`)}))
+	testhelpers.AssertEqual(t, "slack__git_pull", resolve("readers"))
+
+	// the cached manifests can't be changed by the callers
+	group, err := s.GetToolGroup("support-agent")
+	testhelpers.AssertNoError(t, err)
+	tools, err := s.ResolveGroupTools(group)
+	testhelpers.AssertNoError(t, err)
+	tools[0] = "changed"
+	testhelpers.AssertEqual(t, "github__git_pull,slack__delete_repo,slack__git_pull", resolve("support-agent"))
+}
+
+func TestResolveGroupToolsConcurrentUpdates(t *testing.T) {
+	db := testhelpers.SetupMCPTest(t).DB
+	// every connection to an in-memory database opens a different one
+	sqlDB, err := db.DB()
+	testhelpers.AssertNoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	servers := []string{"server0", "server1", "server2", "server3"}
+	for _, name := range servers {
+		g := &model.ToolGroup{Name: "group-" + name, IncludedServers: datatypes.JSON(fmt.Sprintf(`["%s"]`, name))}
+		testhelpers.AssertNoError(t, db.Create(g).Error)
+	}
+	all := &model.ToolGroup{
+		Name:            "everything",
+		IncludedServers: datatypes.JSON(`["server0","server1","server2","server3"]`),
+		ExcludedTools:   datatypes.JSON(`["server0__tool0"]`),
+	}
+	testhelpers.AssertNoError(t, db.Create(all).Error)
+	s, _ := newManifestTestService(t, db, servers, []string{"tool0"})
+
+	const writes, readers = 50, 8
+	var wg sync.WaitGroup
+	errs := make(chan error, len(servers)*writes+readers)
+	done := make(chan struct{})
+	// every server gains and loses tools, while the groups are read
+	for _, name := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			srv, err := s.mcpService.GetMcpServer(name)
+			if err != nil {
+				errs <- err
+				return
+			}
+			for i := 1; i <= writes; i++ {
+				tool := fmt.Sprintf("tool%d", i)
+				if err := db.Create(&model.Tool{ServerID: srv.ID, Name: tool}).Error; err != nil {
+					errs <- err
+					return
+				}
+				s.handleToolDeletion(name + "__" + tool)
+				if i%3 == 0 {
+					if err := db.Unscoped().Where("server_id = ? AND name = ?", srv.ID, tool).Delete(&model.Tool{}).Error; err != nil {
+						errs <- err
+						return
+					}
+					s.handleToolDeletion(name + "__" + tool)
+				}
+			}
+		}()
+	}
+	var readersWg sync.WaitGroup
+	for r := 0; r < readers; r++ {
+		readersWg.Add(1)
+		go func() {
+			defer readersWg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				name := servers[i%len(servers)]
+				group, err := s.GetToolGroup("group-" + name)
+				if err != nil {
+					errs <- err
+					return
+				}
+				tools, err := s.ResolveGroupTools(group)
+				if err != nil {
+					errs <- err
+					return
+				}
+				for _, tool := range tools {
+					if mcp.ToolServerName(tool) != name {
+						errs <- fmt.Errorf("group %s resolved the tool %s of another server", group.Name, tool)
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	readersWg.Wait()
+	close(errs)
+	for err := range errs {
+		testhelpers.AssertNoError(t, err)
+	}
+
+	// once the updates are over, the cached manifests are those of the final state
+	groups, err := s.ListToolGroups()
+	testhelpers.AssertNoError(t, err)
+	for i := range groups {
+		cached, err := s.ResolveGroupTools(&groups[i])
+		testhelpers.AssertNoError(t, err)
+		fresh, err := groups[i].ResolveEffectiveTools(s.mcpService)
+		testhelpers.AssertNoError(t, err)
+		slices.Sort(fresh)
+		testhelpers.AssertEqual(t, strings.Join(fresh, ","), strings.Join(cached, ","))
+	}
+	everything, err := s.GetToolGroup("everything")
+	testhelpers.AssertNoError(t, err)
+	tools, err := s.ResolveGroupTools(everything)
+	testhelpers.AssertNoError(t, err)
+	// every server has tool0 and the two thirds of the tools added, less the excluded tool
+	testhelpers.AssertEqual(t, len(servers)*(1+writes-writes/3)-1, len(tools))
+}
@@ -2,9 +2,12 @@
 package toolgroup
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
+	"reflect"
+	"slices"
+	"sort"
 	"sync"
 
 	mcpgo "github.com/mark3labs/mcp-go/mcp"
@@ -13,16 +16,14 @@ import (
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/mcpjungle/mcpjungle/pkg/util"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
 var ErrToolGroupNotFound = errors.New("tool group not found")
 
-// ValidGroupName is a regex that matches valid tool group names.
-// A valid tool group name must start with an alphanumeric character and can contain
-// alphanumeric characters, underscores, and hyphens.
-// This ensures that the group name can be safely used in URLs.
-var ValidGroupName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+// ValidGroupName is a regex that matches valid tool group names, see types.ValidGroupName.
+var ValidGroupName = types.ValidGroupName
 
 // ToolGroupService provides methods to manage tool groups and their associated MCP proxy servers.
 type ToolGroupService struct {
@@ -41,6 +42,9 @@ type ToolGroupService struct {
 	sseMcpServers map[string]*server.MCPServer
 	// sseMcpServerMu protects access to the sseMcpServers map
 	sseMcpServerMu sync.RWMutex
+
+	// manifests caches the effective tools of the groups
+	manifests *manifestCache
 }
 
 func NewToolGroupService(db *gorm.DB, mcpService *mcp.MCPService) (*ToolGroupService, error) {
@@ -53,6 +57,8 @@ func NewToolGroupService(db *gorm.DB, mcpService *mcp.MCPService) (*ToolGroupSer
 
 		sseMcpServers:  make(map[string]*server.MCPServer),
 		sseMcpServerMu: sync.RWMutex{},
+
+		manifests: newManifestCache(mcpService.Metrics()),
 	}
 
 	// register callbacks with mcp service to be notified when a tool gets added/removed
@@ -79,7 +85,7 @@ func (s *ToolGroupService) CreateToolGroup(group *model.ToolGroup) error {
 	}
 
 	// resolve all effective tools for this group
-	toolNames, err := group.ResolveEffectiveTools(s.mcpService)
+	toolNames, err := s.ResolveGroupTools(group)
 	if err != nil {
 		return fmt.Errorf("failed to resolve effective tools: %w", err)
 	}
@@ -91,6 +97,11 @@ func (s *ToolGroupService) CreateToolGroup(group *model.ToolGroup) error {
 	mcpServer := s.newMCPServer(group.Name)
 	sseMcpServer := s.newSseMCPServer(group.Name)
 
+	parentServers, err := s.mcpService.GetToolsParentServers(toolNames)
+	if err != nil {
+		return fmt.Errorf("failed to get parent MCP servers of the tools: %w", err)
+	}
+
 	// populate the MCP servers with the specified tools
 	// this also has a side effect of validating that the tools exist in mcpjungle.
 	// if a tool does not exist, return an error without creating the group.
@@ -100,9 +111,9 @@ func (s *ToolGroupService) CreateToolGroup(group *model.ToolGroup) error {
 			return fmt.Errorf("tool %s does not exist or is disabled", name)
 		}
 
-		parentServer, err := s.mcpService.GetToolParentServer(name)
+		parentServer, err := parentServerOf(parentServers, name)
 		if err != nil {
-			return fmt.Errorf("failed to get parent MCP server of the tool %s: %w", name, err)
+			return err
 		}
 
 		if parentServer.Transport == types.TransportSSE {
@@ -128,6 +139,8 @@ func (s *ToolGroupService) CreateToolGroup(group *model.ToolGroup) error {
 // UpdateToolGroup updates an existing tool group without causing any downtime for its MCP proxy servers.
 // It returns the configuration of the original tool group before the update.
 // If the tool group does not exist, it returns ErrToolGroupNotFound.
+// If updatedGroup.Version is set, the group is only updated if it is still at that version, otherwise
+// model.ErrVersionConflict is returned. On success, updatedGroup.Version is set to the new version.
 func (s *ToolGroupService) UpdateToolGroup(name string, updatedGroup *model.ToolGroup) (*model.ToolGroup, error) {
 	oldGroup, err := s.GetToolGroup(name)
 	if err != nil {
@@ -136,13 +149,18 @@ func (s *ToolGroupService) UpdateToolGroup(name string, updatedGroup *model.Tool
 		}
 		return nil, fmt.Errorf("failed to retrieve the tool group: %w", err)
 	}
+	if updatedGroup.Version != 0 && updatedGroup.Version != oldGroup.Version {
+		return nil, model.ErrVersionConflict
+	}
+	// the UUID of a group never changes
+	updatedGroup.EntityID = oldGroup.EntityID
 
 	// determine which tools were added or removed from the group
-	oldToolNames, err := oldGroup.ResolveEffectiveTools(s.mcpService)
+	oldToolNames, err := s.ResolveGroupTools(oldGroup)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve effective tools of original group: %w", err)
 	}
-	updatedToolNames, err := updatedGroup.ResolveEffectiveTools(s.mcpService)
+	updatedToolNames, err := s.ResolveGroupTools(updatedGroup)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve effective tools of the updated group: %w", err)
 	}
@@ -150,7 +168,12 @@ func (s *ToolGroupService) UpdateToolGroup(name string, updatedGroup *model.Tool
 	toolsAdded, toolsRemoved := util.DiffTools(oldToolNames, updatedToolNames)
 
 	// if nothing was actually changed in the group, no need to proceed further
-	if updatedGroup.Description == oldGroup.Description && len(toolsAdded) == 0 && len(toolsRemoved) == 0 {
+	same, err := sameGroupConfig(oldGroup, updatedGroup)
+	if err != nil {
+		return nil, err
+	}
+	if same {
+		updatedGroup.Version = oldGroup.Version
 		return oldGroup, nil
 	}
 
@@ -165,6 +188,11 @@ func (s *ToolGroupService) UpdateToolGroup(name string, updatedGroup *model.Tool
 		return nil, fmt.Errorf("SSE MCP server for tool group %s does not exist", name)
 	}
 
+	parentServers, err := s.mcpService.GetToolsParentServers(append(slices.Clone(toolsAdded), toolsRemoved...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent MCP servers of the tools: %w", err)
+	}
+
 	// tools added to the group must be added to its MCP server instances
 	var sseToolsToAdd, normalToolsToAdd []mcpgo.Tool
 	for _, toolName := range toolsAdded {
@@ -173,9 +201,9 @@ func (s *ToolGroupService) UpdateToolGroup(name string, updatedGroup *model.Tool
 			return nil, fmt.Errorf("tool %s does not exist or is disabled", toolName)
 		}
 
-		parentServer, err := s.mcpService.GetToolParentServer(toolName)
+		parentServer, err := parentServerOf(parentServers, toolName)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get parent MCP server of the tool %s: %w", toolName, err)
+			return nil, err
 		}
 
 		if parentServer.Transport == types.TransportSSE {
@@ -188,9 +216,9 @@ func (s *ToolGroupService) UpdateToolGroup(name string, updatedGroup *model.Tool
 	// tools removed from the group must be removed from its MCP server instances
 	var sseToolsToRemove, normalToolsToRemove []string
 	for _, toolName := range toolsRemoved {
-		parentServer, err := s.mcpService.GetToolParentServer(toolName)
+		parentServer, err := parentServerOf(parentServers, toolName)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get parent MCP server of the tool %s: %w", toolName, err)
+			return nil, err
 		}
 
 		if parentServer.Transport == types.TransportSSE {
@@ -200,7 +228,27 @@ func (s *ToolGroupService) UpdateToolGroup(name string, updatedGroup *model.Tool
 		}
 	}
 
-	// make all the changes together to avoid inconsistent state in case of errors
+	// the DB record is updated first since, unlike the changes to the in-memory state, it can fail,
+	// eg- if the group was updated concurrently
+	updates := map[string]any{
+		"description":      updatedGroup.Description,
+		"included_tools":   updatedGroup.IncludedTools,
+		"included_servers": updatedGroup.IncludedServers,
+		"excluded_tools":   updatedGroup.ExcludedTools,
+		"version":          gorm.Expr("version + 1"),
+	}
+	result := s.db.Model(&model.ToolGroup{}).Where("id = ? AND version = ?", oldGroup.ID, oldGroup.Version).Updates(updates)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to update tool group in DB: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, model.ErrVersionConflict
+	}
+	// ensure the group name remains unchanged
+	updatedGroup.Name = name
+	updatedGroup.Version = oldGroup.Version + 1
+
+	// make all the changes to the MCP servers together to avoid inconsistent state in case of errors
 	mcpServer.DeleteTools(normalToolsToRemove...)
 	sseMcpServer.DeleteTools(sseToolsToRemove...)
 
@@ -210,17 +258,32 @@ func (s *ToolGroupService) UpdateToolGroup(name string, updatedGroup *model.Tool
 	for _, tool := range sseToolsToAdd {
 		sseMcpServer.AddTool(tool, s.mcpService.MCPProxyToolCallHandler)
 	}
+	s.mcpService.ToolsListCache().Invalidate(mcpServer)
 
-	// as a final step, update the tool group record in the database
-	// we only persist this update after successfully updating the in-memory state
+	return oldGroup, nil
+}
 
-	// ensure the group name remains unchanged in the db record
-	updatedGroup.Name = name
-	if err := s.db.Model(&model.ToolGroup{}).Where("name = ?", name).Updates(updatedGroup).Error; err != nil {
-		return nil, fmt.Errorf("failed to update tool group in DB: %w", err)
+// sameGroupConfig reports whether two configurations of a tool group are the same.
+func sameGroupConfig(a, b *model.ToolGroup) (bool, error) {
+	if a.Description != b.Description {
+		return false, nil
 	}
-
-	return oldGroup, nil
+	for _, get := range []func(*model.ToolGroup) ([]string, error){
+		(*model.ToolGroup).GetTools, (*model.ToolGroup).GetServers, (*model.ToolGroup).GetExcludedTools,
+	} {
+		listA, err := get(a)
+		if err != nil {
+			return false, fmt.Errorf("invalid configuration of tool group %s: %w", a.Name, err)
+		}
+		listB, err := get(b)
+		if err != nil {
+			return false, fmt.Errorf("invalid configuration of tool group %s: %w", b.Name, err)
+		}
+		if !slices.Equal(listA, listB) {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
 // GetToolGroup retrieves a tool group by name from the database.
@@ -235,17 +298,31 @@ func (s *ToolGroupService) GetToolGroup(name string) (*model.ToolGroup, error) {
 	return &group, nil
 }
 
+// ResolveToolGroupName returns the name of the tool group identified by ident, which is either its UUID or its name.
+func (s *ToolGroupService) ResolveToolGroupName(ident string) (string, error) {
+	return model.ResolveName(s.db, &model.ToolGroup{}, "name", ident)
+}
+
 // ListToolGroups retrieves all tool groups from the database.
 func (s *ToolGroupService) ListToolGroups() ([]model.ToolGroup, error) {
+	groups, _, err := s.ListToolGroupsPage(model.Page{})
+	return groups, err
+}
+
+// ListToolGroupsPage retrieves a page of the tool groups, along with the ID to list the next page after
+// (0 if there are no more groups).
+func (s *ToolGroupService) ListToolGroupsPage(p model.Page) ([]model.ToolGroup, uint, error) {
 	var groups []model.ToolGroup
-	if err := s.db.Find(&groups).Error; err != nil {
-		return nil, err
+	if err := p.Apply(s.db).Find(&groups).Error; err != nil {
+		return nil, 0, err
 	}
-	return groups, nil
+	groups, next := model.NextPage(p, groups, func(g model.ToolGroup) uint { return g.ID })
+	return groups, next, nil
 }
 
 func (s *ToolGroupService) DeleteToolGroup(name string) error {
 	s.deleteToolGroupMCPServers(name)
+	s.manifests.forget(name)
 
 	err := s.db.Unscoped().Where("name = ?", name).Delete(&model.ToolGroup{}).Error
 	if err != nil {
@@ -254,6 +331,13 @@ func (s *ToolGroupService) DeleteToolGroup(name string) error {
 	return nil
 }
 
+// ResolveGroupTools returns the effective tools of a tool group, sorted by name.
+// They are resolved once after the group or the tools of the servers it includes change, and served from a cache
+// until the next change.
+func (s *ToolGroupService) ResolveGroupTools(group *model.ToolGroup) ([]string, error) {
+	return s.manifests.resolve(group, s.mcpService)
+}
+
 // GetToolGroupMCPServer retrieves the MCP proxy server for a given tool group name.
 func (s *ToolGroupService) GetToolGroupMCPServer(name string) (*server.MCPServer, bool) {
 	s.mcpServersMu.RLock()
@@ -318,6 +402,9 @@ func (s *ToolGroupService) deleteToolGroupMCPServers(name string) {
 	defer s.sseMcpServerMu.Unlock()
 
 	// proceed to delete both normal & sse proxies for the group, then release the locks
+	if mcpServer, exists := s.mcpServers[name]; exists {
+		s.mcpService.ToolsListCache().Forget(mcpServer)
+	}
 	delete(s.mcpServers, name)
 	delete(s.sseMcpServers, name)
 }
@@ -331,12 +418,17 @@ func (s *ToolGroupService) initToolGroupMCPServers() error {
 	}
 
 	for _, group := range groups {
-		toolNames, err := group.ResolveEffectiveTools(s.mcpService)
+		toolNames, err := s.ResolveGroupTools(&group)
 		if err != nil {
 			return fmt.Errorf("failed to resolve effective tools for group %s: %w", group.Name, err)
 		}
 		// TODO: Log a warning if a group has no tools, ie, len(toolNames) == 0
 
+		parentServers, err := s.mcpService.GetToolsParentServers(toolNames)
+		if err != nil {
+			return fmt.Errorf("failed to get parent MCP servers of the tools of group %s: %w", group.Name, err)
+		}
+
 		mcpServer := s.newMCPServer(group.Name)
 		sseMcpServer := s.newSseMCPServer(group.Name)
 
@@ -349,9 +441,9 @@ func (s *ToolGroupService) initToolGroupMCPServers() error {
 				continue
 			}
 
-			parentServer, err := s.mcpService.GetToolParentServer(name)
+			parentServer, err := parentServerOf(parentServers, name)
 			if err != nil {
-				return fmt.Errorf("failed to get parent MCP server of the tool %s: %w", name, err)
+				return err
 			}
 
 			if parentServer.Transport == types.TransportSSE {
@@ -368,9 +460,119 @@ func (s *ToolGroupService) initToolGroupMCPServers() error {
 	return nil
 }
 
+// RefreshFromDB updates the MCP proxy servers of the tool groups with the changes the other replicas of the registry
+// made to the DB: the proxy servers of the created groups are built, those of the deleted groups are removed,
+// and the tools of the others are made to match their effective tools.
+// It must be called after mcp.MCPService.RefreshFromDB, so that the tools published since are found.
+func (s *ToolGroupService) RefreshFromDB() error {
+	groups, err := s.ListToolGroups()
+	if err != nil {
+		return fmt.Errorf("failed to list tool groups from DB: %w", err)
+	}
+
+	stored := make(map[string]bool, len(groups))
+	for i := range groups {
+		group := &groups[i]
+		stored[group.Name] = true
+		toolNames, err := s.ResolveGroupTools(group)
+		if err != nil {
+			return fmt.Errorf("failed to resolve effective tools for group %s: %w", group.Name, err)
+		}
+		parentServers, err := s.mcpService.GetToolsParentServers(toolNames)
+		if err != nil {
+			return fmt.Errorf("failed to get parent MCP servers of the tools of group %s: %w", group.Name, err)
+		}
+
+		tools := make(map[string]mcpgo.Tool, len(toolNames))
+		sseTools := make(map[string]mcpgo.Tool)
+		for _, name := range toolNames {
+			tool, exists := s.mcpService.GetToolInstance(name)
+			parentServer, ok := parentServers[name]
+			if !exists || !ok {
+				// like at startup, the tools that don't exist are skipped
+				continue
+			}
+			if parentServer.Transport == types.TransportSSE {
+				sseTools[name] = tool
+			} else {
+				tools[name] = tool
+			}
+		}
+
+		mcpServer, exists := s.GetToolGroupMCPServer(group.Name)
+		sseMcpServer, sseExists := s.GetToolGroupSseMCPServer(group.Name)
+		if !exists || !sseExists {
+			mcpServer, sseMcpServer = s.newMCPServer(group.Name), s.newSseMCPServer(group.Name)
+			s.setGroupTools(mcpServer, tools)
+			s.setGroupTools(sseMcpServer, sseTools)
+			s.addToolGroupMCPServer(group.Name, mcpServer)
+			s.addToolGroupSseMCPServer(group.Name, sseMcpServer)
+			continue
+		}
+		if s.setGroupTools(mcpServer, tools) {
+			s.mcpService.ToolsListCache().Invalidate(mcpServer)
+		}
+		s.setGroupTools(sseMcpServer, sseTools)
+	}
+
+	s.mcpServersMu.RLock()
+	var deleted []string
+	for name := range s.mcpServers {
+		if !stored[name] {
+			deleted = append(deleted, name)
+		}
+	}
+	s.mcpServersMu.RUnlock()
+	for _, name := range deleted {
+		s.deleteToolGroupMCPServers(name)
+		s.manifests.forget(name)
+	}
+	return nil
+}
+
+// setGroupTools makes the tools exposed by the MCP proxy server of a group those given, and reports whether
+// they changed.
+func (s *ToolGroupService) setGroupTools(mcpServer *server.MCPServer, tools map[string]mcpgo.Tool) bool {
+	var removed []string
+	for name := range mcpServer.ListTools() {
+		if _, ok := tools[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	if len(removed) > 0 {
+		mcpServer.DeleteTools(removed...)
+	}
+	changed := len(removed) > 0
+	for name, tool := range tools {
+		if exposed := mcpServer.GetTool(name); exposed == nil || !reflect.DeepEqual(exposed.Tool, tool) {
+			mcpServer.AddTool(tool, s.mcpService.MCPProxyToolCallHandler)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// SendNotificationToAllClients sends a notification to all the MCP clients connected to the proxy servers of tool groups.
+func (s *ToolGroupService) SendNotificationToAllClients(method string, params map[string]any) {
+	s.mcpServersMu.RLock()
+	defer s.mcpServersMu.RUnlock()
+
+	s.sseMcpServerMu.Lock()
+	defer s.sseMcpServerMu.Unlock()
+
+	for _, mcpServer := range s.mcpServers {
+		mcpServer.SendNotificationToAllClients(method, params)
+	}
+	for _, sseMcpServer := range s.sseMcpServers {
+		sseMcpServer.SendNotificationToAllClients(method, params)
+	}
+}
+
 // handleToolDeletion is a callback that is called when one or more tools is deleted or disabled.
 // It removes the tools from all tool group MCP proxy servers.
 func (s *ToolGroupService) handleToolDeletion(tools ...string) {
+	s.manifests.serverToolsChanged(serversOf(tools)...)
+
 	s.mcpServersMu.RLock()
 	defer s.mcpServersMu.RUnlock()
 
@@ -378,7 +580,11 @@ func (s *ToolGroupService) handleToolDeletion(tools ...string) {
 	defer s.sseMcpServerMu.Unlock()
 
 	for _, mcpServer := range s.mcpServers {
-		mcpServer.DeleteTools(tools...)
+		// only the groups that exposed one of the tools have a different tools list now
+		if hasAnyTool(mcpServer, tools) {
+			mcpServer.DeleteTools(tools...)
+			s.mcpService.ToolsListCache().Invalidate(mcpServer)
+		}
 	}
 
 	for _, sseMcpServer := range s.sseMcpServers {
@@ -389,29 +595,16 @@ func (s *ToolGroupService) handleToolDeletion(tools ...string) {
 // handleToolAddition is a callback that is called when a tool is added or (re)enabled in mcpjungle.
 // this callback adds the new tool to MCP proxy servers of all groups that include it.
 func (s *ToolGroupService) handleToolAddition(newTool string) error {
-	// get all tool groups from the database
-	groups, err := s.ListToolGroups()
-	if err != nil {
-		return fmt.Errorf("failed to list tool groups from DB: %w", err)
-	}
+	s.manifests.serverToolsChanged(mcp.ToolServerName(newTool))
 
 	// find all groups that include the added tool
-	groupsToUpdate := make([]string, 0, len(groups))
-	for i := range groups {
-		name := groups[i].Name
-		groupTools, err := groups[i].ResolveEffectiveTools(s.mcpService)
-		if err != nil {
-			return fmt.Errorf("failed to resolve effective tools for group %s: %w", name, err)
-		}
-		for _, t := range groupTools {
-			if t != newTool {
-				continue
-			}
-			// current group includes the added tool, so add the tool instance to the group's MCP server
-			groupsToUpdate = append(groupsToUpdate, name)
-			// no need to check other tools in this group anymore, so exit the loop and move on to the next group
-			break
-		}
+	refs, err := s.GroupsReferencingTools([]string{newTool})
+	if err != nil {
+		return err
+	}
+	groupsToUpdate := make([]string, 0, len(refs))
+	for name := range refs {
+		groupsToUpdate = append(groupsToUpdate, name)
 	}
 
 	newToolInstance, exists := s.mcpService.GetToolInstance(newTool)
@@ -444,8 +637,154 @@ func (s *ToolGroupService) handleToolAddition(newTool string) error {
 		mcpServer, exists := s.mcpServers[name]
 		if exists {
 			mcpServer.AddTool(newToolInstance, s.mcpService.MCPProxyToolCallHandler)
+			s.mcpService.ToolsListCache().Invalidate(mcpServer)
 		}
 	}
 
 	return nil
 }
+
+// parentServerOf returns the MCP server of a tool from the servers returned by mcp.MCPService.GetToolsParentServers.
+func parentServerOf(parentServers map[string]*model.McpServer, tool string) (*model.McpServer, error) {
+	parentServer, ok := parentServers[tool]
+	if !ok {
+		return nil, fmt.Errorf("failed to get parent MCP server of the tool %s: %w", tool, gorm.ErrRecordNotFound)
+	}
+	return parentServer, nil
+}
+
+// serversOf returns the names of the MCP servers providing the given tools, without duplicates.
+func serversOf(tools []string) []string {
+	servers := make([]string, 0, 1)
+	for _, name := range tools {
+		if server := mcp.ToolServerName(name); !slices.Contains(servers, server) {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
+
+// hasAnyTool reports whether the MCP server exposes any of the given tools.
+func hasAnyTool(mcpServer *server.MCPServer, tools []string) bool {
+	for _, name := range tools {
+		if mcpServer.GetTool(name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupsReferencingTools resolves the reverse references from tools to groups: it returns the groups whose
+// effective tools include any of the given tools, mapped to the tools each of them references.
+// Groups that reference none of the tools are left out.
+// The groups are resolved in a single pass, the cached manifests are used for those whose servers didn't change.
+func (s *ToolGroupService) GroupsReferencingTools(tools []string) (map[string][]string, error) {
+	refs := make(map[string][]string)
+	if len(tools) == 0 {
+		return refs, nil
+	}
+
+	groups, err := s.ListToolGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool groups from DB: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		wanted[t] = true
+	}
+
+	// the groups that aren't cached fetch the tools of every included server only once
+	resolver := newCachingToolResolver(s.mcpService)
+	for i := range groups {
+		groupTools, err := s.manifests.resolve(&groups[i], resolver)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve effective tools for group %s: %w", groups[i].Name, err)
+		}
+		for _, t := range groupTools {
+			if wanted[t] {
+				refs[groups[i].Name] = append(refs[groups[i].Name], t)
+			}
+		}
+		sort.Strings(refs[groups[i].Name])
+	}
+	return refs, nil
+}
+
+// ToolGroupUpdate is the configuration of a tool group before and after it was changed.
+type ToolGroupUpdate struct {
+	Old *model.ToolGroup
+	New *model.ToolGroup
+}
+
+// RemoveServerReferences removes a server and the given tools from the configuration of every tool group that
+// names them in its included_servers, included_tools or excluded_tools. It returns the groups that changed.
+// The references are otherwise kept when a server is deregistered, so that its tools are back in the groups once
+// it is registered again. This is used for webhook tools, whose tool only exists as long as they do.
+func (s *ToolGroupService) RemoveServerReferences(serverName string, tools []string) ([]ToolGroupUpdate, error) {
+	groups, err := s.ListToolGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool groups from DB: %w", err)
+	}
+
+	var updates []ToolGroupUpdate
+	for i := range groups {
+		g := &groups[i]
+		updated := &model.ToolGroup{Name: g.Name, Description: g.Description, Version: g.Version}
+		changed := false
+		for _, list := range []struct {
+			get     func(*model.ToolGroup) ([]string, error)
+			set     *datatypes.JSON
+			removed []string
+		}{
+			{(*model.ToolGroup).GetTools, &updated.IncludedTools, tools},
+			{(*model.ToolGroup).GetServers, &updated.IncludedServers, []string{serverName}},
+			{(*model.ToolGroup).GetExcludedTools, &updated.ExcludedTools, tools},
+		} {
+			names, err := list.get(g)
+			if err != nil {
+				return nil, fmt.Errorf("invalid configuration of tool group %s: %w", g.Name, err)
+			}
+			kept := slices.DeleteFunc(slices.Clone(names), func(n string) bool { return slices.Contains(list.removed, n) })
+			changed = changed || len(kept) != len(names)
+			data, err := json.Marshal(kept)
+			if err != nil {
+				return nil, err
+			}
+			*list.set = data
+		}
+		if !changed {
+			continue
+		}
+		old, err := s.UpdateToolGroup(g.Name, updated)
+		if err != nil {
+			return updates, fmt.Errorf("failed to remove the references to server %s from group %s: %w", serverName, g.Name, err)
+		}
+		updates = append(updates, ToolGroupUpdate{Old: old, New: updated})
+	}
+	return updates, nil
+}
+
+// cachingToolResolver remembers the tools of every server it looks up, so that resolving many groups that include
+// the same servers only queries the DB once per server.
+// It is meant to be used for a single batch of resolutions, it is not invalidated when tools change.
+type cachingToolResolver struct {
+	resolver model.ToolResolver
+	tools    map[string][]model.Tool
+}
+
+func newCachingToolResolver(resolver model.ToolResolver) *cachingToolResolver {
+	return &cachingToolResolver{resolver: resolver, tools: make(map[string][]model.Tool)}
+}
+
+func (r *cachingToolResolver) ListToolsByServer(serverName string) ([]model.Tool, error) {
+	if tools, ok := r.tools[serverName]; ok {
+		return tools, nil
+	}
+	tools, err := r.resolver.ListToolsByServer(serverName)
+	if err != nil {
+		return nil, err
+	}
+	r.tools[serverName] = tools
+	return tools, nil
+}
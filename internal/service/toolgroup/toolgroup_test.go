@@ -1,9 +1,18 @@
 package toolgroup
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/telemetry"
 	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 func TestValidGroupNameRegex(t *testing.T) {
@@ -129,3 +138,324 @@ func TestValidGroupNameConsistency(t *testing.T) {
 		}
 	}
 }
+
+// countingToolResolver counts the lookups of every server.
+type countingToolResolver struct {
+	lookups map[string]int
+}
+
+func (r *countingToolResolver) ListToolsByServer(serverName string) ([]model.Tool, error) {
+	r.lookups[serverName]++
+	return []model.Tool{{Name: serverName + "__tool"}}, nil
+}
+
+func TestCachingToolResolver(t *testing.T) {
+	counting := &countingToolResolver{lookups: map[string]int{}}
+	resolver := newCachingToolResolver(counting)
+
+	for i := 0; i < 3; i++ {
+		tools, err := resolver.ListToolsByServer("github")
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, "github__tool", tools[0].Name)
+	}
+	testhelpers.AssertEqual(t, 1, counting.lookups["github"])
+}
+
+func TestGroupsReferencingTools(t *testing.T) {
+	setup := testhelpers.SetupMCPTest(t)
+	db := setup.DB
+
+	proxy := server.NewMCPServer("test", "0.0.0")
+	mcpService, err := mcp.NewMCPService(&mcp.ServiceConfig{
+		DB:                db,
+		McpProxyServer:    proxy,
+		SseMcpProxyServer: proxy,
+		Metrics:           telemetry.NewNoopCustomMetrics(),
+	})
+	testhelpers.AssertNoError(t, err)
+
+	github := &model.McpServer{Name: "github", Transport: "streamable_http", Config: datatypes.JSON(`{"url":"https://api.githubcopilot.com/mcp/"}`)}
+	testhelpers.AssertNoError(t, db.Create(github).Error)
+	for _, name := range []string{"git_commit", "git_push"} {
+		testhelpers.AssertNoError(t, db.Create(&model.Tool{ServerID: github.ID, Name: name}).Error)
+	}
+
+	groups := []*model.ToolGroup{
+		{Name: "ci-tools", IncludedTools: datatypes.JSON(`["github__git_push"]`)},
+		{Name: "support-agent", IncludedServers: datatypes.JSON(`["github"]`), ExcludedTools: datatypes.JSON(`["github__git_push"]`)},
+		{Name: "unrelated", IncludedTools: datatypes.JSON(`["time__now"]`)},
+	}
+	for _, g := range groups {
+		testhelpers.AssertNoError(t, db.Create(g).Error)
+	}
+
+	s, err := NewToolGroupService(db, mcpService)
+	testhelpers.AssertNoError(t, err)
+
+	refs, err := s.GroupsReferencingTools([]string{"github__git_commit", "github__git_push"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 2, len(refs))
+	testhelpers.AssertEqual(t, 1, len(refs["ci-tools"]))
+	testhelpers.AssertEqual(t, "github__git_push", refs["ci-tools"][0])
+	testhelpers.AssertEqual(t, 1, len(refs["support-agent"]))
+	testhelpers.AssertEqual(t, "github__git_commit", refs["support-agent"][0])
+
+	refs, err = s.GroupsReferencingTools(nil)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 0, len(refs))
+}
+
+func TestRemoveServerReferences(t *testing.T) {
+	setup := testhelpers.SetupMCPTest(t)
+	db := setup.DB
+
+	deploy := &model.McpServer{Name: "deploy_notify", Transport: "webhook_tool", Config: datatypes.JSON(`{"url":"https://automation.example.com/hooks/deploy","method":"POST"}`)}
+	testhelpers.AssertNoError(t, db.Create(deploy).Error)
+	tool := &model.Tool{ServerID: deploy.ID, Name: "deploy_notify", InputSchema: model.CompressedJSON(`{"type":"object"}`)}
+	testhelpers.AssertNoError(t, db.Create(tool).Error)
+	for _, g := range []*model.ToolGroup{
+		{Name: "release", IncludedTools: datatypes.JSON(`["deploy_notify__deploy_notify", "time__now"]`)},
+		{Name: "ops", IncludedServers: datatypes.JSON(`["deploy_notify"]`)},
+		{Name: "unrelated", IncludedTools: datatypes.JSON(`["time__now"]`)},
+	} {
+		testhelpers.AssertNoError(t, db.Create(g).Error)
+	}
+
+	proxy := server.NewMCPServer("test", "0.0.0")
+	mcpService, err := mcp.NewMCPService(&mcp.ServiceConfig{
+		DB:                db,
+		McpProxyServer:    proxy,
+		SseMcpProxyServer: proxy,
+		Metrics:           telemetry.NewNoopCustomMetrics(),
+	})
+	testhelpers.AssertNoError(t, err)
+	s, err := NewToolGroupService(db, mcpService)
+	testhelpers.AssertNoError(t, err)
+
+	updates, err := s.RemoveServerReferences("deploy_notify", []string{"deploy_notify__deploy_notify"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 2, len(updates))
+
+	release, err := s.GetToolGroup("release")
+	testhelpers.AssertNoError(t, err)
+	tools, err := release.GetTools()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, `[time__now]`, fmt.Sprint(tools))
+	testhelpers.AssertEqual(t, uint(2), release.Version)
+
+	ops, err := s.GetToolGroup("ops")
+	testhelpers.AssertNoError(t, err)
+	servers, err := ops.GetServers()
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 0, len(servers))
+
+	unrelated, err := s.GetToolGroup("unrelated")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, uint(1), unrelated.Version)
+}
+
+// cacheLookups records the results of the tools list cache lookups, by tool group.
+type cacheLookups struct {
+	telemetry.NoopCustomMetrics
+	results map[string][]telemetry.ToolsListCacheResult
+}
+
+func (m *cacheLookups) RecordToolsListCacheLookup(ctx context.Context, group string, result telemetry.ToolsListCacheResult) {
+	m.results[group] = append(m.results[group], result)
+}
+
+func TestUpdateToolGroupInvalidatesOnlyItsToolsList(t *testing.T) {
+	setup := testhelpers.SetupMCPTest(t)
+	db := setup.DB
+
+	github := &model.McpServer{Name: "github", Transport: "streamable_http", Config: datatypes.JSON(`{"url":"https://api.githubcopilot.com/mcp/"}`)}
+	testhelpers.AssertNoError(t, db.Create(github).Error)
+	for _, name := range []string{"git_commit", "git_push"} {
+		tool := &model.Tool{ServerID: github.ID, Name: name, InputSchema: model.CompressedJSON(`{"type":"object"}`)}
+		testhelpers.AssertNoError(t, db.Create(tool).Error)
+	}
+	for _, g := range []*model.ToolGroup{
+		{Name: "ci-tools", IncludedTools: datatypes.JSON(`["github__git_push"]`)},
+		{Name: "support-agent", IncludedTools: datatypes.JSON(`["github__git_commit"]`)},
+	} {
+		testhelpers.AssertNoError(t, db.Create(g).Error)
+	}
+
+	metrics := &cacheLookups{results: map[string][]telemetry.ToolsListCacheResult{}}
+	proxy := server.NewMCPServer("test", "0.0.0")
+	mcpService, err := mcp.NewMCPService(&mcp.ServiceConfig{
+		DB:                db,
+		McpProxyServer:    proxy,
+		SseMcpProxyServer: proxy,
+		Metrics:           metrics,
+	})
+	testhelpers.AssertNoError(t, err)
+	s, err := NewToolGroupService(db, mcpService)
+	testhelpers.AssertNoError(t, err)
+	cache := mcpService.ToolsListCache()
+
+	listTools := func(group string) string {
+		t.Helper()
+		groupServer, exists := s.GetToolGroupMCPServer(group)
+		testhelpers.AssertTrue(t, exists, "the group should have an MCP server")
+		result, err := cache.ToolsList(context.Background(), group, groupServer)
+		testhelpers.AssertNoError(t, err)
+		return string(result)
+	}
+	for _, group := range []string{"ci-tools", "support-agent"} {
+		listTools(group)
+		listTools(group)
+	}
+	ciTools := listTools("ci-tools")
+	testhelpers.AssertStringContains(t, ciTools, "github__git_push")
+	testhelpers.AssertFalse(t, strings.Contains(ciTools, "github__git_commit"), "ci-tools should not list git_commit yet")
+
+	_, err = s.UpdateToolGroup("ci-tools", &model.ToolGroup{
+		Name:          "ci-tools",
+		IncludedTools: datatypes.JSON(`["github__git_push", "github__git_commit"]`),
+	})
+	testhelpers.AssertNoError(t, err)
+
+	testhelpers.AssertStringContains(t, listTools("ci-tools"), "github__git_commit")
+	listTools("support-agent")
+
+	hit, miss := telemetry.ToolsListCacheHit, telemetry.ToolsListCacheMiss
+	testhelpers.AssertEqual(t, fmt.Sprint([]telemetry.ToolsListCacheResult{miss, hit, hit, miss}), fmt.Sprint(metrics.results["ci-tools"]))
+	testhelpers.AssertEqual(t, fmt.Sprint([]telemetry.ToolsListCacheResult{miss, hit, hit}), fmt.Sprint(metrics.results["support-agent"]))
+}
+
+func TestRefreshFromDB(t *testing.T) {
+	setup := testhelpers.SetupMCPTest(t)
+	db := setup.DB
+
+	github := &model.McpServer{Name: "github", Transport: "streamable_http", Config: datatypes.JSON(`{"url":"https://api.githubcopilot.com/mcp/"}`)}
+	testhelpers.AssertNoError(t, db.Create(github).Error)
+	for _, name := range []string{"git_commit", "git_push"} {
+		tool := &model.Tool{ServerID: github.ID, Name: name, InputSchema: model.CompressedJSON(`{"type":"object"}`)}
+		testhelpers.AssertNoError(t, db.Create(tool).Error)
+	}
+	for _, g := range []*model.ToolGroup{
+		{Name: "ci-tools", IncludedTools: datatypes.JSON(`["github__git_push"]`)},
+		{Name: "support-agent", IncludedTools: datatypes.JSON(`["github__git_commit"]`)},
+	} {
+		testhelpers.AssertNoError(t, db.Create(g).Error)
+	}
+
+	proxy := server.NewMCPServer("test", "0.0.0")
+	mcpService, err := mcp.NewMCPService(&mcp.ServiceConfig{
+		DB:                db,
+		McpProxyServer:    proxy,
+		SseMcpProxyServer: proxy,
+		Metrics:           telemetry.NewNoopCustomMetrics(),
+	})
+	testhelpers.AssertNoError(t, err)
+	s, err := NewToolGroupService(db, mcpService)
+	testhelpers.AssertNoError(t, err)
+
+	// another replica changes a group, creates one and deletes one
+	err = db.Model(&model.ToolGroup{}).Where("name = ?", "ci-tools").Updates(map[string]any{
+		"included_tools": datatypes.JSON(`["github__git_push", "github__git_commit"]`),
+		"version":        gorm.Expr("version + 1"),
+	}).Error
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNoError(t, db.Create(&model.ToolGroup{Name: "release", IncludedTools: datatypes.JSON(`["github__git_push"]`)}).Error)
+	testhelpers.AssertNoError(t, db.Where("name = ?", "support-agent").Delete(&model.ToolGroup{}).Error)
+
+	testhelpers.AssertNoError(t, mcpService.RefreshFromDB())
+	testhelpers.AssertNoError(t, s.RefreshFromDB())
+
+	ciTools, exists := s.GetToolGroupMCPServer("ci-tools")
+	testhelpers.AssertTrue(t, exists, "the changed group should still have an MCP server")
+	testhelpers.AssertEqual(t, 2, len(ciTools.ListTools()))
+	release, exists := s.GetToolGroupMCPServer("release")
+	testhelpers.AssertTrue(t, exists, "the created group should have an MCP server")
+	testhelpers.AssertNotNil(t, release.GetTool("github__git_push"))
+	_, exists = s.GetToolGroupMCPServer("support-agent")
+	testhelpers.AssertFalse(t, exists, "the deleted group should no longer have an MCP server")
+}
+
+func TestLargeInventoryQueryCount(t *testing.T) {
+	if testing.Short() {
+		t.Skip("seeding a large tool inventory is slow")
+	}
+	const servers, toolsPerServer = 20, 500
+
+	testhelpers.ForEachTestDB(t, func(t *testing.T, setup *testhelpers.TestDBSetup) {
+		db := setup.DB
+		for _, index := range []struct {
+			model any
+			name  string
+		}{
+			{&model.Tool{}, "idx_tools_server_name"},
+			{&model.Tool{}, "idx_tools_enabled"},
+			{&model.Prompt{}, "idx_prompts_server_name"},
+			{&model.Prompt{}, "idx_prompts_enabled"},
+		} {
+			testhelpers.AssertTrue(t, db.Migrator().HasIndex(index.model, index.name), "missing index "+index.name)
+		}
+
+		for i := range servers {
+			s := &model.McpServer{
+				Name:      fmt.Sprintf("server%d", i),
+				Transport: "streamable_http",
+				Config:    datatypes.JSON(`{"url":"https://example.com/mcp"}`),
+			}
+			testhelpers.AssertNoError(t, db.Create(s).Error)
+			tools := make([]model.Tool, toolsPerServer)
+			for j := range tools {
+				tools[j] = model.Tool{
+					ServerID:    s.ID,
+					Name:        fmt.Sprintf("tool%d", j),
+					Enabled:     true,
+					InputSchema: model.CompressedJSON(`{"type":"object"}`),
+				}
+			}
+			testhelpers.AssertNoError(t, db.CreateInBatches(tools, 250).Error)
+		}
+
+		proxy := server.NewMCPServer("test", "0.0.0")
+		mcpService, err := mcp.NewMCPService(&mcp.ServiceConfig{
+			DB:                    db,
+			McpProxyServer:        proxy,
+			SseMcpProxyServer:     proxy,
+			Metrics:               telemetry.NewNoopCustomMetrics(),
+			DisableToolsListCache: true,
+		})
+		testhelpers.AssertNoError(t, err)
+		s, err := NewToolGroupService(db, mcpService)
+		testhelpers.AssertNoError(t, err)
+		queries := testhelpers.CountQueries(t, db)
+
+		tools, err := mcpService.ListTools()
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, servers*toolsPerServer, len(tools))
+		testhelpers.AssertEqual(t, 2, queries())
+
+		page, _, total, err := mcpService.ListToolsPage(mcp.ToolFilter{}, model.Page{Limit: 100})
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 100, len(page))
+		testhelpers.AssertEqual(t, int64(servers*toolsPerServer), total)
+		testhelpers.AssertEqual(t, 3, queries())
+
+		// resolving a group costs a few queries per included server, whatever the number of tools
+		group := &model.ToolGroup{
+			Name:            "everything",
+			IncludedServers: datatypes.JSON(`["server0", "server1", "server2", "server3"]`),
+			IncludedTools:   datatypes.JSON(`["server4__tool0", "server5__tool1"]`),
+			ExcludedTools:   datatypes.JSON(`["server0__tool0"]`),
+		}
+		resolved, err := group.ResolveEffectiveTools(mcpService)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, 4*toolsPerServer+1, len(resolved))
+		testhelpers.AssertTrue(t, queries() <= 3*4, "resolving the group should not query every tool")
+
+		testhelpers.AssertNoError(t, s.CreateToolGroup(group))
+		testhelpers.AssertTrue(t, queries() <= 3*4+3, "creating the group should not query the server of every tool")
+
+		// disabling a server updates all its tools at once
+		disabled, err := mcpService.DisableTools("server0")
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, toolsPerServer, len(disabled))
+		testhelpers.AssertTrue(t, queries() <= 3, "disabling a server should not update its tools one by one")
+	})
+}
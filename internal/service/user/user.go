@@ -105,13 +105,26 @@ func (u *UserService) UpdateUser(input *model.User) (*model.User, error) {
 	return &user, nil
 }
 
+// ResolveUsername returns the username of the user identified by ident, which is either their UUID or their username.
+func (u *UserService) ResolveUsername(ident string) (string, error) {
+	return model.ResolveName(u.db, &model.User{}, "username", ident)
+}
+
 // ListUsers retrieves all users from the database.
 func (u *UserService) ListUsers() ([]model.User, error) {
+	users, _, err := u.ListUsersPage(model.Page{})
+	return users, err
+}
+
+// ListUsersPage retrieves a page of the users, along with the ID to list the next page after
+// (0 if there are no more users).
+func (u *UserService) ListUsersPage(p model.Page) ([]model.User, uint, error) {
 	var users []model.User
-	if err := u.db.Find(&users).Error; err != nil {
-		return nil, fmt.Errorf("failed to list users: %w", err)
+	if err := p.Apply(u.db).Find(&users).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
 	}
-	return users, nil
+	users, next := model.NextPage(p, users, func(user model.User) uint { return user.ID })
+	return users, next, nil
 }
 
 // DeleteUser removes a user with the specified username from the database.
@@ -0,0 +1,229 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/mcpjungle/mcpjungle/pkg/version"
+	"gorm.io/gorm"
+)
+
+// Publish delivers an event to every enabled webhook subscribed to its type, in the background.
+// data is the JSON-encodable description of the entity the event is about.
+// It is a no-op on a nil service, so that callers don't need to check whether webhooks are set up.
+func (s *WebhookService) Publish(eventType string, data any) {
+	if s == nil {
+		return
+	}
+	event, err := newEvent(eventType, data)
+	if err != nil {
+		log.Printf("[WARN] failed to create %s webhook event: %v", eventType, err)
+		return
+	}
+
+	var webhooks []*model.Webhook
+	if err := s.db.Where("enabled = ?", true).Find(&webhooks).Error; err != nil {
+		log.Printf("[WARN] failed to list the webhooks to deliver %s event %s to: %v", eventType, event.ID, err)
+		return
+	}
+	for _, w := range webhooks {
+		if !subscribed(w, eventType) {
+			continue
+		}
+		s.deliveries.Add(1)
+		go func() {
+			defer s.deliveries.Done()
+			s.sem <- struct{}{}
+			defer func() { <-s.sem }()
+
+			d := s.deliver(s.ctx, w, event, s.maxAttempts)
+			s.recordDelivery(w, d, true)
+		}()
+	}
+}
+
+// TestWebhook sends a test event to a webhook right away, once, and returns the outcome.
+// The webhook is tested even if it is disabled, and the outcome doesn't count towards disabling it.
+func (s *WebhookService) TestWebhook(ctx context.Context, name string) (*model.WebhookDelivery, error) {
+	w, err := s.GetWebhook(name)
+	if err != nil {
+		return nil, err
+	}
+	event, err := newEvent(types.EventWebhookTest, map[string]string{"webhook": w.Name})
+	if err != nil {
+		return nil, err
+	}
+	d := s.deliver(ctx, w, event, 1)
+	s.recordDelivery(w, d, false)
+	return d, nil
+}
+
+// newEvent creates an event of the given type about data, with a random ID.
+func newEvent(eventType string, data any) (*types.WebhookEvent, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the event data: %w", err)
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate the event ID: %w", err)
+	}
+	return &types.WebhookEvent{
+		ID:        hex.EncodeToString(id),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      raw,
+	}, nil
+}
+
+// deliver sends an event to a webhook until it is accepted or maxAttempts attempts failed,
+// waiting longer before every retry.
+func (s *WebhookService) deliver(ctx context.Context, w *model.Webhook, event *types.WebhookEvent, maxAttempts int) *model.WebhookDelivery {
+	d := &model.WebhookDelivery{WebhookID: w.ID, EventID: event.ID, Event: event.Type}
+	body, err := render(w.Format, event, *s.statusURL.Load())
+	if err != nil {
+		d.Error = fmt.Sprintf("failed to encode the event: %v", err)
+		return d
+	}
+
+	backoff := s.retryBackoff
+	for d.Attempts < maxAttempts {
+		if d.Attempts > 0 {
+			select {
+			case <-ctx.Done():
+				d.Error = fmt.Sprintf("gave up after %d attempts: %v (last error: %s)", d.Attempts, ctx.Err(), d.Error)
+				return d
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		d.Attempts++
+		// an attempt in progress is completed even if ctx is canceled, it is bounded by the client's timeout
+		d.StatusCode, err = s.send(context.WithoutCancel(ctx), w, event, body)
+		if err == nil {
+			d.Succeeded, d.Error = true, ""
+			return d
+		}
+		d.Error = err.Error()
+	}
+	return d
+}
+
+// send makes a single attempt at delivering an event, and returns the status code of the response.
+func (s *WebhookService) send(ctx context.Context, w *model.Webhook, event *types.WebhookEvent, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MCPJungle-Webhook/"+version.GetVersion())
+	req.Header.Set(types.WebhookEventHeader, event.Type)
+	req.Header.Set(types.WebhookDeliveryHeader, event.ID)
+	req.Header.Set(types.WebhookSignatureHeader, Sign(w.Secret, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	// drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("the webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature of a delivery's body, as sent in the types.WebhookSignatureHeader header.
+// Receivers compute it from the body they received and compare it to the header to authenticate deliveries.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// recordDelivery adds a delivery to the log of its webhook, dropping the oldest ones beyond the retention limit.
+// If countFailures is true, the webhook's failure count is updated, and it is disabled once it reaches the threshold.
+func (s *WebhookService) recordDelivery(w *model.Webhook, d *model.WebhookDelivery, countFailures bool) {
+	var exists int64
+	if err := s.db.Model(&model.Webhook{}).Where("id = ?", w.ID).Count(&exists).Error; err != nil || exists == 0 {
+		// the webhook was deleted during the delivery
+		return
+	}
+	if err := s.db.Create(d).Error; err != nil {
+		log.Printf("[WARN] failed to record the delivery of event %s to webhook %s: %v", d.EventID, w.Name, err)
+	}
+	var cutoff []uint
+	err := s.db.Model(&model.WebhookDelivery{}).
+		Where("webhook_id = ?", w.ID).
+		Order("id DESC").
+		Offset(maxDeliveriesPerWebhook).
+		Limit(1).
+		Pluck("id", &cutoff).Error
+	if err == nil && len(cutoff) > 0 {
+		err = s.db.Where("webhook_id = ? AND id <= ?", w.ID, cutoff[0]).Delete(&model.WebhookDelivery{}).Error
+	}
+	if err != nil {
+		log.Printf("[WARN] failed to prune the delivery log of webhook %s: %v", w.Name, err)
+	}
+
+	if !countFailures {
+		return
+	}
+	if d.Succeeded {
+		err = s.db.Model(&model.Webhook{}).Where("id = ?", w.ID).Update("consecutive_failures", 0).Error
+	} else {
+		err = s.recordFailure(w)
+	}
+	if err != nil {
+		log.Printf("[WARN] failed to update the failure count of webhook %s: %v", w.Name, err)
+	}
+}
+
+// recordFailure increments the failure count of a webhook and disables it if it reached the threshold.
+func (s *WebhookService) recordFailure(w *model.Webhook) error {
+	return db.RetryOnBusy(func() error {
+		return s.db.Transaction(func(tx *gorm.DB) error {
+			err := tx.Model(&model.Webhook{}).
+				Where("id = ?", w.ID).
+				Update("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error
+			if err != nil {
+				return err
+			}
+			var current model.Webhook
+			if err := tx.First(&current, w.ID).Error; err != nil {
+				return err
+			}
+			if !current.Enabled || current.ConsecutiveFailures < s.disableAfterFailures {
+				return nil
+			}
+
+			now := time.Now()
+			reason := fmt.Sprintf("disabled automatically after %d deliveries in a row failed", current.ConsecutiveFailures)
+			err = tx.Model(&current).Updates(map[string]any{
+				"enabled":         false,
+				"disabled_reason": reason,
+				"disabled_at":     &now,
+			}).Error
+			if err != nil {
+				return err
+			}
+			log.Printf("[AUDIT] webhook %s (%s) was %s", current.Name, current.URL, reason)
+			return nil
+		})
+	})
+}
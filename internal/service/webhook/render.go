@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// markup is the syntax of the messages sent to chat webhooks.
+type markup struct {
+	// escape escapes the characters of text that have a meaning in the syntax
+	escape func(text string) string
+	link   func(text, url string) string
+}
+
+// slackMarkup is Slack's mrkdwn, see https://api.slack.com/reference/surfaces/formatting.
+var slackMarkup = markup{
+	escape: strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace,
+	link:   func(text, url string) string { return "<" + url + "|" + text + ">" },
+}
+
+// markdownMarkup is CommonMark.
+var markdownMarkup = markup{
+	escape: strings.NewReplacer(
+		`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`,
+	).Replace,
+	link: func(text, url string) string { return "[" + text + "](" + url + ")" },
+}
+
+// validFormat returns true if format is one of the formats of the body of deliveries, the default if empty.
+func validFormat(format string) bool {
+	return format == "" || slices.Contains(types.WebhookFormats, format)
+}
+
+// render returns the body of a delivery of an event in the given format.
+// statusURL is linked from the messages about the health of MCP servers, unless it is empty.
+func render(format string, event *types.WebhookEvent, statusURL string) ([]byte, error) {
+	switch format {
+	case types.WebhookFormatSlack:
+		return json.Marshal(map[string]string{"text": message(event, slackMarkup, statusURL)})
+	case types.WebhookFormatMarkdown:
+		return json.Marshal(struct {
+			Text  string              `json:"text"`
+			Event *types.WebhookEvent `json:"event"`
+		}{message(event, markdownMarkup, statusURL), event})
+	default:
+		return json.Marshal(event)
+	}
+}
+
+// message describes an event in a line a person can read in a chat channel,
+// eg- "⚠️ MCP server 'jira' unhealthy: connection refused".
+// The events whose data can't be decoded are described by their type.
+func message(event *types.WebhookEvent, m markup, statusURL string) string {
+	var entity struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(event.Data, &entity)
+	name := "'" + m.escape(entity.Name) + "'"
+
+	switch event.Type {
+	case types.EventServerUnhealthy, types.EventServerRecovered:
+		var c types.ServerHealthChange
+		if err := json.Unmarshal(event.Data, &c); err != nil {
+			break
+		}
+		var msg string
+		if event.Type == types.EventServerUnhealthy {
+			msg = fmt.Sprintf("⚠️ MCP server '%s' unhealthy: %s", m.escape(c.Server), m.escape(c.Error))
+		} else {
+			msg = fmt.Sprintf(
+				"✅ MCP server '%s' recovered after %s", m.escape(c.Server), formatDuration(c.CheckedAt.Sub(c.UnhealthySince)),
+			)
+		}
+		if statusURL != "" {
+			msg += " (" + m.link("status", statusURL) + ")"
+		}
+		return msg
+	case types.EventToolSyncChanged:
+		var c types.ToolSyncChange
+		if err := json.Unmarshal(event.Data, &c); err != nil {
+			break
+		}
+		var changes []string
+		if len(c.Added) > 0 {
+			changes = append(changes, fmt.Sprintf("%d added (%s)", len(c.Added), m.escape(strings.Join(c.Added, ", "))))
+		}
+		if len(c.Removed) > 0 {
+			changes = append(changes, fmt.Sprintf("%d removed (%s)", len(c.Removed), m.escape(strings.Join(c.Removed, ", "))))
+		}
+		return fmt.Sprintf("🔄 The tools of MCP server '%s' changed: %s", m.escape(c.Server), strings.Join(changes, ", "))
+	case types.EventServerRegistered:
+		return "🆕 MCP server " + name + " registered"
+	case types.EventServerUpdated:
+		return "✏️ MCP server " + name + " updated"
+	case types.EventServerDeregistered:
+		return "🗑️ MCP server " + name + " deregistered"
+	case types.EventGroupCreated:
+		return "🆕 Tool group " + name + " created"
+	case types.EventGroupUpdated:
+		return "✏️ Tool group " + name + " updated"
+	case types.EventGroupDeleted:
+		return "🗑️ Tool group " + name + " deleted"
+	case types.EventWebhookTest:
+		var test struct {
+			Webhook string `json:"webhook"`
+		}
+		_ = json.Unmarshal(event.Data, &test)
+		return fmt.Sprintf("🔔 Test event of MCPJungle webhook '%s', it is set up correctly", m.escape(test.Webhook))
+	}
+	return "MCPJungle event " + m.escape(event.Type)
+}
+
+// formatDuration formats a duration to the largest units that matter to a person, eg- 42s, 5m or 2h5m.
+func formatDuration(d time.Duration) string {
+	if d = d.Round(time.Second); d < time.Minute {
+		return d.String()
+	}
+	if d = d.Round(time.Minute); d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	if m == 0 {
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh%dm", h, m)
+}
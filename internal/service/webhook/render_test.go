@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// update rewrites the golden files with the current renderings, run `go test ./internal/service/webhook -update`
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func newTestEvent(t *testing.T, eventType string, data any) *types.WebhookEvent {
+	t.Helper()
+	raw, err := json.Marshal(data)
+	testhelpers.AssertNoError(t, err)
+	return &types.WebhookEvent{
+		ID:        "evt-1",
+		Type:      eventType,
+		CreatedAt: time.Date(2025, 3, 1, 12, 5, 0, 0, time.UTC),
+		Data:      raw,
+	}
+}
+
+func TestRenderGolden(t *testing.T) {
+	since := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	unhealthy := newTestEvent(t, types.EventServerUnhealthy, types.ServerHealthChange{
+		Server:         "jira",
+		Status:         types.ServerUnhealthy,
+		Error:          "dial tcp <jira>:443: connection refused",
+		CheckedAt:      since,
+		UnhealthySince: since,
+	})
+	recovered := newTestEvent(t, types.EventServerRecovered, types.ServerHealthChange{
+		Server:         "jira",
+		Status:         types.ServerHealthy,
+		CheckedAt:      since.Add(5 * time.Minute),
+		UnhealthySince: since,
+	})
+	toolSync := newTestEvent(t, types.EventToolSyncChanged, types.ToolSyncChange{
+		Server:  "github",
+		Added:   []string{"github__create_issue", "github__merge_pr"},
+		Removed: []string{"github__old_tool"},
+	})
+	registered := newTestEvent(t, types.EventServerRegistered, types.McpServer{Name: "my_server"})
+
+	tests := []struct {
+		golden    string
+		format    string
+		event     *types.WebhookEvent
+		statusURL string
+	}{
+		{"slack_server_unhealthy", types.WebhookFormatSlack, unhealthy, "https://mcp.example.com/api/v1/servers/health"},
+		{"slack_server_recovered", types.WebhookFormatSlack, recovered, ""},
+		{"slack_tool_sync_changed", types.WebhookFormatSlack, toolSync, ""},
+		{"slack_server_registered", types.WebhookFormatSlack, registered, ""},
+		{"markdown_server_unhealthy", types.WebhookFormatMarkdown, unhealthy, "https://mcp.example.com/api/v1/servers/health"},
+		{"markdown_server_recovered", types.WebhookFormatMarkdown, recovered, ""},
+		{"markdown_server_registered", types.WebhookFormatMarkdown, registered, ""},
+		{"json_server_unhealthy", types.WebhookFormatJSON, unhealthy, "https://mcp.example.com/api/v1/servers/health"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			body, err := render(tt.format, tt.event, tt.statusURL)
+			testhelpers.AssertNoError(t, err)
+
+			path := filepath.Join("testdata", tt.golden+".golden")
+			if *update {
+				testhelpers.AssertNoError(t, os.WriteFile(path, append(body, '\n'), 0o644))
+			}
+			want, err := os.ReadFile(path)
+			testhelpers.AssertNoError(t, err)
+			testhelpers.AssertEqual(t, string(want), string(body)+"\n")
+		})
+	}
+}
+
+func TestRenderJSONIsTheEvent(t *testing.T) {
+	event := newTestEvent(t, types.EventGroupDeleted, types.ToolGroup{Name: "review"})
+	want, err := json.Marshal(event)
+	testhelpers.AssertNoError(t, err)
+
+	for _, format := range []string{"", types.WebhookFormatJSON} {
+		body, err := render(format, event, "https://mcp.example.com/api/v1/servers/health")
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, string(want), string(body))
+	}
+}
+
+func TestMessageOfUnknownEvent(t *testing.T) {
+	event := newTestEvent(t, "server.exploded", map[string]string{"name": "jira"})
+	testhelpers.AssertEqual(t, "MCPJungle event server.exploded", message(event, slackMarkup, ""))
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{42*time.Second + 300*time.Millisecond, "42s"},
+		{5*time.Minute + 10*time.Second, "5m"},
+		{2 * time.Hour, "2h"},
+		{2*time.Hour + 5*time.Minute, "2h5m"},
+		{50 * time.Hour, "50h"},
+	}
+	for _, tt := range tests {
+		testhelpers.AssertEqual(t, tt.want, formatDuration(tt.d))
+	}
+}
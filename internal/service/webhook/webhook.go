@@ -0,0 +1,274 @@
+// Package webhook delivers the registry's lifecycle events to the URLs subscribed to them.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Defaults of the delivery settings.
+const (
+	DefaultMaxAttempts          = 5
+	DefaultRetryBackoff         = 2 * time.Second
+	DefaultDisableAfterFailures = 10
+	defaultDeliveryTimeout      = 10 * time.Second
+
+	// maxConcurrentDeliveries is how many deliveries are sent at the same time, across all webhooks.
+	maxConcurrentDeliveries = 16
+	// maxDeliveriesPerWebhook is how many deliveries of every webhook are kept in its delivery log.
+	maxDeliveriesPerWebhook = 100
+	// minSecretLength is the minimum length of a secret chosen by the user.
+	minSecretLength = 16
+)
+
+// ErrInvalidWebhook is wrapped by the errors returned when a webhook's configuration is invalid.
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+// ValidWebhookName is a regex that matches valid webhook names.
+// Like tool group names, they must start with an alphanumeric character and can contain
+// alphanumeric characters, underscores, and hyphens, so that they can be used in URLs.
+var ValidWebhookName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// Config holds the delivery settings of the WebhookService.
+type Config struct {
+	// HTTPClient sends the deliveries. If nil, a client with a timeout of 10 seconds is used.
+	HTTPClient *http.Client
+	// MaxAttempts is the number of times an event is sent to a webhook before giving up, DefaultMaxAttempts if 0.
+	MaxAttempts int
+	// RetryBackoff is the delay before the first retry of a delivery, doubled for each of the next ones.
+	// DefaultRetryBackoff if 0.
+	RetryBackoff time.Duration
+	// DisableAfterFailures is the number of deliveries in a row that must fail for a webhook to be disabled.
+	// DefaultDisableAfterFailures if 0.
+	DisableAfterFailures int
+	// StatusURL is the URL of the health endpoint of the gateway, eg- https://mcp.example.com/api/v1/servers/health.
+	// The messages sent to chat webhooks about the health of MCP servers link to it, unless it is empty.
+	StatusURL string
+}
+
+// WebhookService manages the webhook subscriptions and delivers events to them.
+type WebhookService struct {
+	db *gorm.DB
+
+	httpClient           *http.Client
+	maxAttempts          int
+	retryBackoff         time.Duration
+	disableAfterFailures int
+	// statusURL is replaced by SetStatusURL
+	statusURL atomic.Pointer[string]
+
+	// ctx is canceled when the service is closed, which aborts the pending retries
+	ctx    context.Context
+	cancel context.CancelFunc
+	// deliveries tracks the deliveries in progress
+	deliveries sync.WaitGroup
+	// sem bounds the number of concurrent deliveries
+	sem chan struct{}
+}
+
+// NewWebhookService creates a WebhookService. cfg may be nil to use the default delivery settings.
+func NewWebhookService(db *gorm.DB, cfg *Config) *WebhookService {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	s := &WebhookService{
+		db:                   db,
+		httpClient:           cfg.HTTPClient,
+		maxAttempts:          cfg.MaxAttempts,
+		retryBackoff:         cfg.RetryBackoff,
+		disableAfterFailures: cfg.DisableAfterFailures,
+		sem:                  make(chan struct{}, maxConcurrentDeliveries),
+	}
+	if s.httpClient == nil {
+		s.httpClient = &http.Client{Timeout: defaultDeliveryTimeout}
+	}
+	if s.maxAttempts <= 0 {
+		s.maxAttempts = DefaultMaxAttempts
+	}
+	if s.retryBackoff <= 0 {
+		s.retryBackoff = DefaultRetryBackoff
+	}
+	if s.disableAfterFailures <= 0 {
+		s.disableAfterFailures = DefaultDisableAfterFailures
+	}
+	s.statusURL.Store(&cfg.StatusURL)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s
+}
+
+// SetStatusURL changes the URL of the health endpoint of the gateway linked from the chat messages,
+// empty to not link it. The deliveries in progress keep the URL they started with.
+func (s *WebhookService) SetStatusURL(statusURL string) {
+	s.statusURL.Store(&statusURL)
+}
+
+// Close stops the service. Deliveries in progress are given up on after their current attempt.
+// It returns once all of them are over.
+func (s *WebhookService) Close() {
+	s.cancel()
+	s.deliveries.Wait()
+}
+
+// CreateWebhook validates the configuration of a webhook and stores it.
+// If the webhook has no secret, one is generated.
+func (s *WebhookService) CreateWebhook(w *model.Webhook) error {
+	if !ValidWebhookName.MatchString(w.Name) {
+		return fmt.Errorf(
+			"%w: name %q must start with an alphanumeric character and only contain alphanumeric characters, underscores and hyphens",
+			ErrInvalidWebhook, w.Name,
+		)
+	}
+	if err := validateURL(w.URL); err != nil {
+		return err
+	}
+	events, err := webhookEvents(w)
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		if e != types.AllWebhookEvents && !slices.Contains(types.WebhookEventTypes, e) {
+			return fmt.Errorf("%w: unknown event %s, valid events are %v", ErrInvalidWebhook, e, types.WebhookEventTypes)
+		}
+	}
+	if !validFormat(w.Format) {
+		return fmt.Errorf("%w: unknown format %s, valid formats are %v", ErrInvalidWebhook, w.Format, types.WebhookFormats)
+	}
+	if w.Format == "" {
+		w.Format = types.WebhookFormatJSON
+	}
+
+	if w.Secret == "" {
+		if w.Secret, err = internal.GenerateAccessToken(); err != nil {
+			return fmt.Errorf("failed to generate the webhook secret: %w", err)
+		}
+	} else if len(w.Secret) < minSecretLength {
+		return fmt.Errorf("%w: the secret should be at least %d characters in length", ErrInvalidWebhook, minSecretLength)
+	}
+	if w.Events == nil {
+		w.Events = datatypes.JSON("[]")
+	}
+	w.Enabled = true
+
+	if err := s.db.Create(w).Error; err != nil {
+		return fmt.Errorf("failed to create webhook %s: %w", w.Name, err)
+	}
+	return nil
+}
+
+// GetWebhook fetches a webhook by name.
+func (s *WebhookService) GetWebhook(name string) (*model.Webhook, error) {
+	var w model.Webhook
+	if err := s.db.Where("name = ?", name).First(&w).Error; err != nil {
+		return nil, fmt.Errorf("failed to get webhook %s: %w", name, err)
+	}
+	return &w, nil
+}
+
+// ListWebhooksPage retrieves a page of the webhooks, along with the ID to list the next page after
+// (0 if there are no more webhooks).
+func (s *WebhookService) ListWebhooksPage(p model.Page) ([]*model.Webhook, uint, error) {
+	var webhooks []*model.Webhook
+	if err := p.Apply(s.db).Find(&webhooks).Error; err != nil {
+		return nil, 0, err
+	}
+	webhooks, next := model.NextPage(p, webhooks, func(w *model.Webhook) uint { return w.ID })
+	return webhooks, next, nil
+}
+
+// DeleteWebhook deletes a webhook along with its delivery log.
+// Deliveries in progress are still completed, but not recorded.
+func (s *WebhookService) DeleteWebhook(name string) error {
+	w, err := s.GetWebhook(name)
+	if err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", w.ID).Delete(&model.WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("failed to delete the delivery log of webhook %s: %w", name, err)
+		}
+		if err := tx.Unscoped().Delete(w).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook %s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+// SetWebhookEnabled enables or disables a webhook.
+// Enabling a webhook that was disabled because its deliveries kept failing resets its failure count.
+func (s *WebhookService) SetWebhookEnabled(name string, enabled bool) (*model.Webhook, error) {
+	w, err := s.GetWebhook(name)
+	if err != nil {
+		return nil, err
+	}
+	updates := map[string]any{"enabled": enabled}
+	if enabled {
+		updates["consecutive_failures"] = 0
+		updates["disabled_reason"] = ""
+		updates["disabled_at"] = nil
+	}
+	if err := s.db.Model(w).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to update webhook %s: %w", name, err)
+	}
+	return s.GetWebhook(name)
+}
+
+// ListDeliveriesPage retrieves a page of the delivery log of a webhook, oldest first,
+// along with the ID to list the next page after (0 if there are no more deliveries).
+// Only the latest deliveries of every webhook are kept.
+func (s *WebhookService) ListDeliveriesPage(name string, p model.Page) ([]*model.WebhookDelivery, uint, error) {
+	w, err := s.GetWebhook(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	var deliveries []*model.WebhookDelivery
+	if err := p.Apply(s.db.Where("webhook_id = ?", w.ID)).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	deliveries, next := model.NextPage(p, deliveries, func(d *model.WebhookDelivery) uint { return d.ID })
+	return deliveries, next, nil
+}
+
+// validateURL checks that a webhook URL is an absolute http(s) URL.
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url %q must be an absolute http or https URL", ErrInvalidWebhook, raw)
+	}
+	return nil
+}
+
+// webhookEvents returns the event filter of a webhook.
+func webhookEvents(w *model.Webhook) ([]string, error) {
+	var events []string
+	if len(w.Events) == 0 {
+		return events, nil
+	}
+	if err := json.Unmarshal(w.Events, &events); err != nil {
+		return nil, fmt.Errorf("%w: events must be a list of event types: %v", ErrInvalidWebhook, err)
+	}
+	return events, nil
+}
+
+// subscribed returns true if the webhook is subscribed to events of the given type.
+func subscribed(w *model.Webhook, eventType string) bool {
+	events, err := webhookEvents(w)
+	if err != nil {
+		return false
+	}
+	return len(events) == 0 || slices.Contains(events, types.AllWebhookEvents) || slices.Contains(events, eventType)
+}
@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+)
+
+// newTestService creates a WebhookService that retries quickly, backed by an in-memory database.
+func newTestService(t *testing.T, cfg *Config) *WebhookService {
+	t.Helper()
+	setup := testhelpers.SetupTestDB(t)
+	// every connection to an in-memory database opens a new, empty one
+	sqlDB, err := setup.DB.DB()
+	testhelpers.AssertNoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = time.Millisecond
+	}
+	s := NewWebhookService(setup.DB, cfg)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func createTestWebhook(t *testing.T, s *WebhookService, name, url string, events ...string) *model.Webhook {
+	t.Helper()
+	w := &model.Webhook{Name: name, URL: url, Secret: "0123456789abcdef"}
+	if len(events) > 0 {
+		raw, _ := json.Marshal(events)
+		w.Events = datatypes.JSON(raw)
+	}
+	testhelpers.AssertNoError(t, s.CreateWebhook(w))
+	return w
+}
+
+func TestCreateWebhookValidation(t *testing.T) {
+	s := newTestService(t, &Config{})
+
+	tests := []struct {
+		name    string
+		webhook *model.Webhook
+	}{
+		{"invalid name", &model.Webhook{Name: "-hook", URL: "https://example.com/hook"}},
+		{"relative url", &model.Webhook{Name: "hook", URL: "/hook"}},
+		{"unsupported scheme", &model.Webhook{Name: "hook", URL: "ftp://example.com/hook"}},
+		{"unknown event", &model.Webhook{Name: "hook", URL: "https://example.com/hook", Events: datatypes.JSON(`["server.exploded"]`)}},
+		{"short secret", &model.Webhook{Name: "hook", URL: "https://example.com/hook", Secret: "secret"}},
+		{"unknown format", &model.Webhook{Name: "hook", URL: "https://example.com/hook", Format: "teams"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.CreateWebhook(tt.webhook)
+			testhelpers.AssertTrue(t, errors.Is(err, ErrInvalidWebhook), "expected an invalid webhook error")
+		})
+	}
+
+	w := &model.Webhook{Name: "hook", URL: "https://example.com/hook"}
+	testhelpers.AssertNoError(t, s.CreateWebhook(w))
+	testhelpers.AssertTrue(t, len(w.Secret) >= minSecretLength, "a secret should be generated")
+	testhelpers.AssertTrue(t, w.Enabled, "a new webhook should be enabled")
+	testhelpers.AssertEqual(t, types.WebhookFormatJSON, w.Format)
+}
+
+func TestPublishSignsDeliveries(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer receiver.Close()
+
+	s := newTestService(t, &Config{})
+	w := createTestWebhook(t, s, "ci", receiver.URL, types.EventServerRegistered)
+
+	// the webhook is not subscribed to this event
+	s.Publish(types.EventGroupCreated, map[string]string{"name": "ci-tools"})
+	s.Publish(types.EventServerRegistered, map[string]string{"name": "github"})
+
+	r, body := <-received, <-bodies
+	testhelpers.AssertEqual(t, types.EventServerRegistered, r.Header.Get(types.WebhookEventHeader))
+	testhelpers.AssertEqual(t, Sign(w.Secret, body), r.Header.Get(types.WebhookSignatureHeader))
+
+	var event types.WebhookEvent
+	testhelpers.AssertNoError(t, json.Unmarshal(body, &event))
+	testhelpers.AssertEqual(t, types.EventServerRegistered, event.Type)
+	testhelpers.AssertEqual(t, event.ID, r.Header.Get(types.WebhookDeliveryHeader))
+	testhelpers.AssertEqual(t, `{"name":"github"}`, string(event.Data))
+
+	s.deliveries.Wait()
+	deliveries, _, err := s.ListDeliveriesPage("ci", model.Page{})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(deliveries))
+	testhelpers.AssertTrue(t, deliveries[0].Succeeded, "the delivery should have succeeded")
+	testhelpers.AssertEqual(t, http.StatusOK, deliveries[0].StatusCode)
+}
+
+func TestPublishRetries(t *testing.T) {
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer receiver.Close()
+
+	s := newTestService(t, &Config{MaxAttempts: 3})
+	createTestWebhook(t, s, "ci", receiver.URL)
+	s.Publish(types.EventGroupDeleted, map[string]string{"name": "ci-tools"})
+	s.deliveries.Wait()
+
+	testhelpers.AssertEqual(t, int32(3), calls.Load())
+	deliveries, _, err := s.ListDeliveriesPage("ci", model.Page{})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(deliveries))
+	testhelpers.AssertEqual(t, 3, deliveries[0].Attempts)
+	testhelpers.AssertTrue(t, deliveries[0].Succeeded, "the last attempt should have succeeded")
+}
+
+func TestPublishDisablesFailingWebhook(t *testing.T) {
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	s := newTestService(t, &Config{MaxAttempts: 1, DisableAfterFailures: 2})
+	createTestWebhook(t, s, "ci", receiver.URL)
+
+	// a failed test delivery doesn't count towards disabling the webhook
+	d, err := s.TestWebhook(context.Background(), "ci")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertFalse(t, d.Succeeded, "the test delivery should have failed")
+	testhelpers.AssertEqual(t, http.StatusInternalServerError, d.StatusCode)
+
+	for range 2 {
+		s.Publish(types.EventServerDeregistered, map[string]string{"name": "github"})
+		s.deliveries.Wait()
+	}
+	w, err := s.GetWebhook("ci")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertFalse(t, w.Enabled, "the webhook should have been disabled")
+	testhelpers.AssertEqual(t, 2, w.ConsecutiveFailures)
+	testhelpers.AssertStringContains(t, w.DisabledReason, "disabled automatically")
+	testhelpers.AssertTrue(t, w.DisabledAt != nil, "the time the webhook was disabled should be recorded")
+
+	// events are no longer delivered to the disabled webhook
+	s.Publish(types.EventServerDeregistered, map[string]string{"name": "github"})
+	s.deliveries.Wait()
+	testhelpers.AssertEqual(t, int32(3), calls.Load())
+
+	w, err = s.SetWebhookEnabled("ci", true)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, w.Enabled, "the webhook should be enabled again")
+	testhelpers.AssertEqual(t, 0, w.ConsecutiveFailures)
+	testhelpers.AssertEqual(t, "", w.DisabledReason)
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	s := newTestService(t, &Config{})
+	createTestWebhook(t, s, "ci", "http://127.0.0.1:1/hook")
+
+	testhelpers.AssertNoError(t, s.DeleteWebhook("ci"))
+	_, err := s.GetWebhook("ci")
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertError(t, s.DeleteWebhook("ci"))
+
+	// the name can be reused
+	createTestWebhook(t, s, "ci", "http://127.0.0.1:1/hook")
+}
+
+func TestCloseGivesUpRetries(t *testing.T) {
+	attempted := make(chan struct{}, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		attempted <- struct{}{}
+	}))
+	defer receiver.Close()
+
+	s := newTestService(t, &Config{RetryBackoff: time.Hour})
+	createTestWebhook(t, s, "ci", receiver.URL)
+	s.Publish(types.EventServerUpdated, map[string]string{"name": "github"})
+	<-attempted
+	s.Close()
+
+	deliveries, _, err := s.ListDeliveriesPage("ci", model.Page{})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(deliveries))
+	testhelpers.AssertEqual(t, 1, deliveries[0].Attempts)
+	testhelpers.AssertStringContains(t, deliveries[0].Error, "gave up after 1 attempts")
+}
@@ -11,6 +11,21 @@ type (
 	PromptCallOutcome string
 )
 
+// ServerStartOutcome represents the outcome of starting the persistent session of an MCP server.
+type ServerStartOutcome string
+
+// ToolsListCacheResult tells whether a tools/list result was served from the cache.
+type ToolsListCacheResult string
+
+// KeepAlivePingOutcome represents the outcome of a ping sent to keep the idle session of an MCP server open.
+type KeepAlivePingOutcome string
+
+// KeepAliveEvictionReason tells why the keep-alive of the session of an MCP server closed it.
+type KeepAliveEvictionReason string
+
+// UpstreamConnection tells whether a request to an upstream MCP server opened a new connection or reused one.
+type UpstreamConnection string
+
 const (
 	// ToolCallOutcomeSuccess indicates a successful tool call
 	ToolCallOutcomeSuccess ToolCallOutcome = "success"
@@ -25,6 +40,41 @@ const (
 	PromptCallOutcomeError PromptCallOutcome = "error"
 )
 
+const (
+	// ServerStartOutcomeSuccess indicates that the session of the server was started
+	ServerStartOutcomeSuccess ServerStartOutcome = "success"
+	// ServerStartOutcomeError indicates that the session of the server failed to start
+	ServerStartOutcomeError ServerStartOutcome = "error"
+)
+
+const (
+	// ToolsListCacheHit indicates that the tools/list result was served from the cache
+	ToolsListCacheHit ToolsListCacheResult = "hit"
+	// ToolsListCacheMiss indicates that the tools/list result had to be built
+	ToolsListCacheMiss ToolsListCacheResult = "miss"
+)
+
+const (
+	// UpstreamConnectionNew indicates that the request opened a new connection to the server
+	UpstreamConnectionNew UpstreamConnection = "new"
+	// UpstreamConnectionReused indicates that the request reused an idle connection from the pool of the server
+	UpstreamConnectionReused UpstreamConnection = "reused"
+)
+
+const (
+	// KeepAlivePingSuccess indicates that the server answered the ping
+	KeepAlivePingSuccess KeepAlivePingOutcome = "success"
+	// KeepAlivePingError indicates that the ping failed
+	KeepAlivePingError KeepAlivePingOutcome = "error"
+)
+
+const (
+	// KeepAliveEvictionPingFailed indicates that the session was closed because the server didn't answer a ping
+	KeepAliveEvictionPingFailed KeepAliveEvictionReason = "ping_failed"
+	// KeepAliveEvictionMaxIdle indicates that the session was closed because it was idle for the maximum idle lifetime
+	KeepAliveEvictionMaxIdle KeepAliveEvictionReason = "max_idle"
+)
+
 // CustomMetrics defines the interface for recording custom metrics from mcpjungle.
 // It provides convenience methods for recording metrics related to http server, mcp servers, tools, usage, etc.
 type CustomMetrics interface {
@@ -33,4 +83,40 @@ type CustomMetrics interface {
 
 	// RecordPromptCall records a prompt invocation, its latency, and its outcome (success or error).
 	RecordPromptCall(ctx context.Context, serverName, promptName string, outcome PromptCallOutcome, elapsedTime time.Duration)
+
+	// RecordUpstreamOperation records how long an MCP request mcpjungle sent to an upstream MCP server took,
+	// eg- the tools/call request of a tool call, and whether it failed.
+	// target is the name of the tool or prompt of the request, without the name of the server.
+	RecordUpstreamOperation(
+		ctx context.Context, serverName string, method MCPMethod, target string, err error, elapsedTime time.Duration,
+	)
+
+	// RecordServerStart records how long it took to start the persistent session of an MCP server,
+	// eg- to spawn a lazily started stdio server on its first tool call, and whether it succeeded.
+	RecordServerStart(ctx context.Context, serverName string, outcome ServerStartOutcome, elapsedTime time.Duration)
+
+	// RecordToolsListCacheLookup records whether the tools/list result of a proxy server was served from the cache.
+	// group is the tool group served by the proxy server, empty for the main proxy server.
+	RecordToolsListCacheLookup(ctx context.Context, group string, result ToolsListCacheResult)
+
+	// RecordUpstreamConnection records whether a request to a streamable HTTP MCP server opened a new connection
+	// or reused one from the server's connection pool.
+	RecordUpstreamConnection(ctx context.Context, serverName string, conn UpstreamConnection)
+
+	// RecordUpstreamQueueWait records how long a tool or prompt call to an MCP server waited for the calls in flight
+	// to complete, when the global limit of concurrent upstream calls was reached.
+	RecordUpstreamQueueWait(ctx context.Context, serverName string, elapsedTime time.Duration)
+
+	// RecordToolGroupResolution records how long it took to resolve the effective tools of a tool group,
+	// every time they were resolved rather than served from the cache.
+	RecordToolGroupResolution(ctx context.Context, group string, elapsedTime time.Duration)
+
+	// RecordKeepAlivePing records a ping sent to keep the idle session of an MCP server open, and its outcome.
+	RecordKeepAlivePing(ctx context.Context, serverName string, outcome KeepAlivePingOutcome)
+
+	// RecordKeepAliveEviction records that the keep-alive of the session of an MCP server closed it, and why.
+	RecordKeepAliveEviction(ctx context.Context, serverName string, reason KeepAliveEvictionReason)
+
+	// RecordToolResultSize records the size in bytes of the response of a streamable HTTP MCP server to a tool call.
+	RecordToolResultSize(ctx context.Context, serverName, toolName string, bytes int64)
 }
@@ -25,3 +25,45 @@ func (m *NoopCustomMetrics) RecordPromptCall(
 ) {
 	// No-op
 }
+
+func (m *NoopCustomMetrics) RecordUpstreamOperation(
+	ctx context.Context, serverName string, method MCPMethod, target string, err error, elapsedTime time.Duration,
+) {
+	// No-op
+}
+
+func (m *NoopCustomMetrics) RecordToolsListCacheLookup(ctx context.Context, group string, result ToolsListCacheResult) {
+	// No-op
+}
+
+func (m *NoopCustomMetrics) RecordServerStart(
+	ctx context.Context, serverName string, outcome ServerStartOutcome, elapsedTime time.Duration,
+) {
+	// No-op
+}
+
+func (m *NoopCustomMetrics) RecordUpstreamConnection(ctx context.Context, serverName string, conn UpstreamConnection) {
+	// No-op
+}
+
+func (m *NoopCustomMetrics) RecordUpstreamQueueWait(ctx context.Context, serverName string, elapsedTime time.Duration) {
+	// No-op
+}
+
+func (m *NoopCustomMetrics) RecordToolGroupResolution(ctx context.Context, group string, elapsedTime time.Duration) {
+	// No-op
+}
+
+func (m *NoopCustomMetrics) RecordKeepAlivePing(ctx context.Context, serverName string, outcome KeepAlivePingOutcome) {
+	// No-op
+}
+
+func (m *NoopCustomMetrics) RecordKeepAliveEviction(
+	ctx context.Context, serverName string, reason KeepAliveEvictionReason,
+) {
+	// No-op
+}
+
+func (m *NoopCustomMetrics) RecordToolResultSize(ctx context.Context, serverName, toolName string, bytes int64) {
+	// No-op
+}
@@ -9,13 +9,17 @@ import (
 	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdkresource "go.opentelemetry.io/otel/sdk/resource"
-	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 )
 
 // Config holds otel configuration options
 type Config struct {
-	ServiceName string
-	Enabled     bool
+	ServiceName    string
+	ServiceVersion string
+	Enabled        bool
+	// LegacyMetrics keeps recording the tool and prompt calls in the metrics used before the semantic conventions,
+	// see NewOtelCustomMetrics
+	LegacyMetrics bool
 }
 
 // Providers holds the Otel configuration and metrics provider.
@@ -35,16 +39,7 @@ func Init(ctx context.Context, config *Config) (*Providers, error) {
 		}, nil
 	}
 
-	// Create resource with service information
-	res, err := sdkresource.New(
-		ctx,
-		sdkresource.WithFromEnv(),
-		sdkresource.WithHost(),
-		sdkresource.WithProcess(),
-		sdkresource.WithAttributes(
-			semconv.ServiceName(config.ServiceName),
-		),
-	)
+	res, err := newResource(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create otel resource: %w", err)
 	}
@@ -75,6 +70,21 @@ func Init(ctx context.Context, config *Config) (*Providers, error) {
 	return providers, nil
 }
 
+// newResource returns the resource identifying the gateway in the telemetry: its service name and version,
+// the host and process it runs on, and the attributes of OTEL_RESOURCE_ATTRIBUTES.
+func newResource(ctx context.Context, config *Config) (*sdkresource.Resource, error) {
+	return sdkresource.New(
+		ctx,
+		sdkresource.WithFromEnv(),
+		sdkresource.WithHost(),
+		sdkresource.WithProcess(),
+		sdkresource.WithAttributes(
+			semconv.ServiceName(config.ServiceName),
+			semconv.ServiceVersion(config.ServiceVersion),
+		),
+	)
+}
+
 // Shutdown gracefully shuts down the otel providers
 func (p *Providers) Shutdown(ctx context.Context) error {
 	if p == nil {
@@ -13,6 +13,10 @@ const (
 	labelMCPServerName   = "mcp_server_name"
 	labelToolName        = "tool_name"
 	labelToolCallOutcome = "outcome"
+	labelToolGroupName   = "tool_group_name"
+	labelCacheResult     = "result"
+	labelConnection      = "connection"
+	labelEvictionReason  = "reason"
 )
 
 const (
@@ -24,65 +28,271 @@ const (
 // OtelCustomMetrics bundles all the OpenTelemetry metric instruments used for MCPJungle.
 // It implements the CustomMetrics interface.
 type OtelCustomMetrics struct {
+	serverOperationDuration metric.Float64Histogram
+	clientOperationDuration metric.Float64Histogram
+
+	// toolCalls and toolCallLatency are the instruments of the tool calls before the semantic conventions,
+	// they are nil unless the legacy metrics are kept
 	toolCalls       metric.Int64Counter
 	toolCallLatency metric.Float64Histogram
+
+	serverStartLatency     metric.Float64Histogram
+	toolsListCacheLookups  metric.Int64Counter
+	upstreamConnections    metric.Int64Counter
+	toolResultSize         metric.Int64Histogram
+	groupResolutionLatency metric.Float64Histogram
+	upstreamQueueWait      metric.Float64Histogram
+	keepAlivePings         metric.Int64Counter
+	keepAliveEvictions     metric.Int64Counter
 }
 
 // NewOtelCustomMetrics initializes all metric instruments required by MCPJungle.
 // Returns an CustomMetrics instance ready for use, or an error if any instrument
 // could not be created.
-func NewOtelCustomMetrics(meter metric.Meter) (CustomMetrics, error) {
+// The tool and prompt calls are recorded in the metrics of the semantic conventions for MCP. With legacyMetrics,
+// they are also recorded in the metrics mcpjungle used before, so that dashboards can be migrated in the meantime.
+func NewOtelCustomMetrics(meter metric.Meter, legacyMetrics bool) (CustomMetrics, error) {
 	if meter == nil {
 		return nil, fmt.Errorf("meter cannot be nil")
 	}
 
-	toolInv, err := meter.Int64Counter(
-		"mcpjungle_tool_calls_total",
-		metric.WithDescription("Total number of tool calls"),
+	serverOpDuration, err := meter.Float64Histogram(
+		MetricMCPServerOperationDuration,
+		metric.WithDescription("Duration of the MCP requests served by mcpjungle, eg- the tool calls of its clients"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(mcpOperationDurationBuckets...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server operation duration histogram: %w", err)
+	}
+
+	clientOpDuration, err := meter.Float64Histogram(
+		MetricMCPClientOperationDuration,
+		metric.WithDescription("Duration of the MCP requests sent by mcpjungle to the upstream MCP servers"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(mcpOperationDurationBuckets...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client operation duration histogram: %w", err)
+	}
+
+	var toolInv metric.Int64Counter
+	var toolLat metric.Float64Histogram
+	if legacyMetrics {
+		toolInv, err = meter.Int64Counter(
+			"mcpjungle_tool_calls_total",
+			metric.WithDescription("Total number of tool calls"),
+			metric.WithUnit("1"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tool calls counter: %w", err)
+		}
+
+		toolLat, err = meter.Float64Histogram(
+			"mcpjungle_tool_call_latency_seconds",
+			metric.WithDescription("Latency of tool calls in seconds"),
+			metric.WithUnit("s"),
+			metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 20, 30),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tool latency histogram: %w", err)
+		}
+	}
+
+	startLat, err := meter.Float64Histogram(
+		"mcpjungle_server_start_latency_seconds",
+		metric.WithDescription("Time taken to start the persistent sessions of MCP servers in seconds, eg- to spawn stdio servers"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 20, 30, 60),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server start latency histogram: %w", err)
+	}
+
+	cacheLookups, err := meter.Int64Counter(
+		"mcpjungle_tools_list_cache_lookups_total",
+		metric.WithDescription("Total number of tools/list requests to the MCP proxy servers, by cache result (hit or miss)"),
 		metric.WithUnit("1"),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create tool calls counter: %w", err)
+		return nil, fmt.Errorf("failed to create tools list cache lookups counter: %w", err)
 	}
 
-	toolLat, err := meter.Float64Histogram(
-		"mcpjungle_tool_call_latency_seconds",
-		metric.WithDescription("Latency of tool calls in seconds"),
+	upstreamConns, err := meter.Int64Counter(
+		"mcpjungle_upstream_connections_total",
+		metric.WithDescription("Total number of requests to streamable HTTP MCP servers, by connection (new or reused)"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upstream connections counter: %w", err)
+	}
+
+	resultSize, err := meter.Int64Histogram(
+		"mcpjungle_tool_result_bytes",
+		metric.WithDescription("Size of the responses of streamable HTTP MCP servers to tool calls in bytes"),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(1<<10, 16<<10, 128<<10, 1<<20, 8<<20, 32<<20, 64<<20, 128<<20),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tool result size histogram: %w", err)
+	}
+
+	groupResolution, err := meter.Float64Histogram(
+		"mcpjungle_tool_group_resolution_seconds",
+		metric.WithDescription("Time taken to resolve the effective tools of tool groups in seconds, when they were not cached"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tool group resolution latency histogram: %w", err)
+	}
+
+	queueWait, err := meter.Float64Histogram(
+		"mcpjungle_upstream_queue_wait_seconds",
+		metric.WithDescription("Time the calls to MCP servers waited for a slot once the limit of concurrent calls was reached, in seconds"),
 		metric.WithUnit("s"),
-		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 20, 30),
+		metric.WithExplicitBucketBoundaries(0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 30),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create tool latency histogram: %w", err)
+		return nil, fmt.Errorf("failed to create upstream queue wait histogram: %w", err)
+	}
+
+	keepAlivePings, err := meter.Int64Counter(
+		"mcpjungle_keepalive_pings_total",
+		metric.WithDescription("Total number of pings sent to keep the idle sessions of MCP servers open, by outcome"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keep-alive pings counter: %w", err)
+	}
+
+	keepAliveEvictions, err := meter.Int64Counter(
+		"mcpjungle_keepalive_evictions_total",
+		metric.WithDescription("Total number of sessions of MCP servers closed by their keep-alive, by reason (ping_failed or max_idle)"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keep-alive evictions counter: %w", err)
 	}
 
 	return &OtelCustomMetrics{
-		toolCalls:       toolInv,
-		toolCallLatency: toolLat,
+		serverOperationDuration: serverOpDuration,
+		clientOperationDuration: clientOpDuration,
+		toolCalls:               toolInv,
+		toolCallLatency:         toolLat,
+		serverStartLatency:      startLat,
+		toolsListCacheLookups:   cacheLookups,
+		upstreamConnections:     upstreamConns,
+		toolResultSize:          resultSize,
+		groupResolutionLatency:  groupResolution,
+		upstreamQueueWait:       queueWait,
+		keepAlivePings:          keepAlivePings,
+		keepAliveEvictions:      keepAliveEvictions,
 	}, nil
 }
 
 func (m *OtelCustomMetrics) RecordToolCall(
 	ctx context.Context, mcpServerName, toolName string, outcome ToolCallOutcome, elapsedTime time.Duration,
 ) {
+	attrs := mcpOperationAttributes(mcpServerName, MCPMethodToolsCall, toolName, outcome == ToolCallOutcomeError)
+	m.serverOperationDuration.Record(ctx, elapsedTime.Seconds(), metric.WithAttributes(attrs...))
+	m.recordLegacyCall(ctx, mcpServerName, toolName, string(outcome), elapsedTime)
+}
+
+func (m *OtelCustomMetrics) RecordPromptCall(
+	ctx context.Context, mcpServerName, promptName string, outcome PromptCallOutcome, elapsedTime time.Duration,
+) {
+	attrs := mcpOperationAttributes(mcpServerName, MCPMethodPromptsGet, promptName, outcome == PromptCallOutcomeError)
+	m.serverOperationDuration.Record(ctx, elapsedTime.Seconds(), metric.WithAttributes(attrs...))
+	m.recordLegacyCall(ctx, mcpServerName, promptName, string(outcome), elapsedTime)
+}
+
+// recordLegacyCall records a tool or prompt call in the metrics used before the semantic conventions, if kept.
+func (m *OtelCustomMetrics) recordLegacyCall(
+	ctx context.Context, mcpServerName, name, outcome string, elapsedTime time.Duration,
+) {
+	if m.toolCalls == nil {
+		return
+	}
 	attrs := []attribute.KeyValue{
 		attribute.String(labelMCPServerName, boundString(mcpServerName)),
-		attribute.String(labelToolName, boundString(toolName)),
-		attribute.String(labelToolCallOutcome, string(outcome)),
+		attribute.String(labelToolName, boundString(name)),
+		attribute.String(labelToolCallOutcome, outcome),
 	}
 	m.toolCalls.Add(ctx, 1, metric.WithAttributes(attrs...))
 	m.toolCallLatency.Record(ctx, elapsedTime.Seconds(), metric.WithAttributes(attrs...))
 }
 
-func (m *OtelCustomMetrics) RecordPromptCall(
-	ctx context.Context, mcpServerName, promptName string, outcome PromptCallOutcome, elapsedTime time.Duration,
+func (m *OtelCustomMetrics) RecordUpstreamOperation(
+	ctx context.Context, mcpServerName string, method MCPMethod, target string, err error, elapsedTime time.Duration,
+) {
+	attrs := mcpOperationAttributes(mcpServerName, method, target, err != nil)
+	m.clientOperationDuration.Record(ctx, elapsedTime.Seconds(), metric.WithAttributes(attrs...))
+}
+
+func (m *OtelCustomMetrics) RecordServerStart(
+	ctx context.Context, mcpServerName string, outcome ServerStartOutcome, elapsedTime time.Duration,
 ) {
 	attrs := []attribute.KeyValue{
 		attribute.String(labelMCPServerName, boundString(mcpServerName)),
-		attribute.String(labelToolName, boundString(promptName)),
 		attribute.String(labelToolCallOutcome, string(outcome)),
 	}
-	m.toolCalls.Add(ctx, 1, metric.WithAttributes(attrs...))
-	m.toolCallLatency.Record(ctx, elapsedTime.Seconds(), metric.WithAttributes(attrs...))
+	m.serverStartLatency.Record(ctx, elapsedTime.Seconds(), metric.WithAttributes(attrs...))
+}
+
+func (m *OtelCustomMetrics) RecordToolsListCacheLookup(ctx context.Context, group string, result ToolsListCacheResult) {
+	attrs := []attribute.KeyValue{attribute.String(labelCacheResult, string(result))}
+	if group != "" {
+		// the main proxy server is not labeled with a group
+		attrs = append(attrs, attribute.String(labelToolGroupName, boundString(group)))
+	}
+	m.toolsListCacheLookups.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+func (m *OtelCustomMetrics) RecordUpstreamConnection(ctx context.Context, mcpServerName string, conn UpstreamConnection) {
+	attrs := []attribute.KeyValue{
+		attribute.String(labelMCPServerName, boundString(mcpServerName)),
+		attribute.String(labelConnection, string(conn)),
+	}
+	m.upstreamConnections.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+func (m *OtelCustomMetrics) RecordToolResultSize(ctx context.Context, mcpServerName, toolName string, bytes int64) {
+	attrs := []attribute.KeyValue{
+		attribute.String(labelMCPServerName, boundString(mcpServerName)),
+		attribute.String(labelToolName, boundString(toolName)),
+	}
+	m.toolResultSize.Record(ctx, bytes, metric.WithAttributes(attrs...))
+}
+
+func (m *OtelCustomMetrics) RecordUpstreamQueueWait(ctx context.Context, mcpServerName string, elapsedTime time.Duration) {
+	attrs := []attribute.KeyValue{attribute.String(labelMCPServerName, boundString(mcpServerName))}
+	m.upstreamQueueWait.Record(ctx, elapsedTime.Seconds(), metric.WithAttributes(attrs...))
+}
+
+func (m *OtelCustomMetrics) RecordToolGroupResolution(ctx context.Context, group string, elapsedTime time.Duration) {
+	attrs := []attribute.KeyValue{attribute.String(labelToolGroupName, boundString(group))}
+	m.groupResolutionLatency.Record(ctx, elapsedTime.Seconds(), metric.WithAttributes(attrs...))
+}
+
+func (m *OtelCustomMetrics) RecordKeepAlivePing(
+	ctx context.Context, mcpServerName string, outcome KeepAlivePingOutcome,
+) {
+	attrs := []attribute.KeyValue{
+		attribute.String(labelMCPServerName, boundString(mcpServerName)),
+		attribute.String(labelToolCallOutcome, string(outcome)),
+	}
+	m.keepAlivePings.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+func (m *OtelCustomMetrics) RecordKeepAliveEviction(
+	ctx context.Context, mcpServerName string, reason KeepAliveEvictionReason,
+) {
+	attrs := []attribute.KeyValue{
+		attribute.String(labelMCPServerName, boundString(mcpServerName)),
+		attribute.String(labelEvictionReason, string(reason)),
+	}
+	m.keepAliveEvictions.Add(ctx, 1, metric.WithAttributes(attrs...))
 }
 
 // boundString ensures strings are capped at maxLen and not empty.
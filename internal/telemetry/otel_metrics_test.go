@@ -0,0 +1,153 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// newTestMetrics returns the metrics of mcpjungle and the reader of what they recorded.
+func newTestMetrics(t *testing.T, legacyMetrics bool) (CustomMetrics, *sdkmetric.ManualReader) {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	m, err := NewOtelCustomMetrics(provider.Meter("mcpjungle"), legacyMetrics)
+	testhelpers.AssertNoError(t, err)
+	return m, reader
+}
+
+// collectMetrics returns the metrics recorded so far, by name.
+func collectMetrics(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	testhelpers.AssertNoError(t, reader.Collect(context.Background(), &rm))
+	metrics := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+	return metrics
+}
+
+// histogramAttributes returns the attributes of the data points of a histogram.
+func histogramAttributes(t *testing.T, m metricdata.Metrics) []attribute.Set {
+	t.Helper()
+	h, ok := m.Data.(metricdata.Histogram[float64])
+	testhelpers.AssertTrue(t, ok, "expected a float64 histogram for "+m.Name)
+	var sets []attribute.Set
+	for _, dp := range h.DataPoints {
+		sets = append(sets, dp.Attributes)
+	}
+	return sets
+}
+
+// attributeKeys returns the sorted keys of a set of attributes.
+func attributeKeys(set attribute.Set) []attribute.Key {
+	var keys []attribute.Key
+	for _, kv := range set.ToSlice() {
+		keys = append(keys, kv.Key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func sortedKeys(keys ...attribute.Key) []attribute.Key {
+	slices.Sort(keys)
+	return keys
+}
+
+func TestToolCallFollowsSemanticConventions(t *testing.T) {
+	m, reader := newTestMetrics(t, false)
+	m.RecordToolCall(context.Background(), "github", "create_issue", ToolCallOutcomeError, 150*time.Millisecond)
+
+	metrics := collectMetrics(t, reader)
+	sets := histogramAttributes(t, metrics[MetricMCPServerOperationDuration])
+	testhelpers.AssertEqual(t, 1, len(sets))
+	testhelpers.AssertEqual(t, "s", metrics[MetricMCPServerOperationDuration].Unit)
+	want := sortedKeys(AttrMCPMethodName, AttrMCPServerName, AttrGenAIToolName, AttrGenAIOperationName, AttrErrorType)
+	testhelpers.AssertTrue(t, slices.Equal(want, attributeKeys(sets[0])), "unexpected attribute keys")
+
+	attr := func(k attribute.Key) string {
+		v, _ := sets[0].Value(k)
+		return v.AsString()
+	}
+	testhelpers.AssertEqual(t, "tools/call", attr(AttrMCPMethodName))
+	testhelpers.AssertEqual(t, "github", attr(AttrMCPServerName))
+	testhelpers.AssertEqual(t, "create_issue", attr(AttrGenAIToolName))
+	testhelpers.AssertEqual(t, semconv.GenAIOperationNameExecuteTool.Value.AsString(), attr(AttrGenAIOperationName))
+	testhelpers.AssertEqual(t, semconv.ErrorTypeOther.Value.AsString(), attr(AttrErrorType))
+
+	// the tool calls are no longer recorded in the metrics used before the conventions
+	_, ok := metrics["mcpjungle_tool_calls_total"]
+	testhelpers.AssertFalse(t, ok, "expected no legacy tool calls counter")
+}
+
+func TestPromptCallFollowsSemanticConventions(t *testing.T) {
+	m, reader := newTestMetrics(t, false)
+	m.RecordPromptCall(context.Background(), "github", "review_pr", PromptCallOutcomeSuccess, time.Second)
+
+	sets := histogramAttributes(t, collectMetrics(t, reader)[MetricMCPServerOperationDuration])
+	testhelpers.AssertEqual(t, 1, len(sets))
+	// error.type is only set when the operation failed
+	want := sortedKeys(AttrMCPMethodName, AttrMCPServerName, AttrGenAIPromptName)
+	testhelpers.AssertTrue(t, slices.Equal(want, attributeKeys(sets[0])), "unexpected attribute keys")
+	v, _ := sets[0].Value(AttrMCPMethodName)
+	testhelpers.AssertEqual(t, "prompts/get", v.AsString())
+}
+
+func TestRecordUpstreamOperation(t *testing.T) {
+	m, reader := newTestMetrics(t, false)
+	m.RecordUpstreamOperation(context.Background(), "github", MCPMethodToolsCall, "create_issue", nil, time.Second)
+	m.RecordUpstreamOperation(
+		context.Background(), "github", MCPMethodToolsCall, "create_issue", errors.New("boom"), time.Second,
+	)
+
+	metrics := collectMetrics(t, reader)
+	sets := histogramAttributes(t, metrics[MetricMCPClientOperationDuration])
+	testhelpers.AssertEqual(t, 2, len(sets))
+	failed := 0
+	for _, set := range sets {
+		if set.HasValue(AttrErrorType) {
+			failed++
+		}
+	}
+	testhelpers.AssertEqual(t, 1, failed)
+	// the calls mcpjungle sends to upstream servers are not operations it serves
+	_, ok := metrics[MetricMCPServerOperationDuration]
+	testhelpers.AssertFalse(t, ok, "expected no server operation duration")
+}
+
+func TestLegacyMetrics(t *testing.T) {
+	m, reader := newTestMetrics(t, true)
+	m.RecordToolCall(context.Background(), "github", "create_issue", ToolCallOutcomeSuccess, time.Second)
+
+	metrics := collectMetrics(t, reader)
+	testhelpers.AssertEqual(t, 1, len(histogramAttributes(t, metrics[MetricMCPServerOperationDuration])))
+
+	sets := histogramAttributes(t, metrics["mcpjungle_tool_call_latency_seconds"])
+	testhelpers.AssertEqual(t, 1, len(sets))
+	want := sortedKeys(labelMCPServerName, labelToolName, labelToolCallOutcome)
+	testhelpers.AssertTrue(t, slices.Equal(want, attributeKeys(sets[0])), "unexpected legacy attribute keys")
+	_, ok := metrics["mcpjungle_tool_calls_total"]
+	testhelpers.AssertTrue(t, ok, "expected the legacy tool calls counter")
+}
+
+func TestNewResource(t *testing.T) {
+	res, err := newResource(context.Background(), &Config{ServiceName: "mcpjungle", ServiceVersion: "1.2.3"})
+	testhelpers.AssertNoError(t, err)
+	set := res.Set()
+	name, _ := set.Value(semconv.ServiceNameKey)
+	version, _ := set.Value(semconv.ServiceVersionKey)
+	testhelpers.AssertEqual(t, "mcpjungle", name.AsString())
+	testhelpers.AssertEqual(t, "1.2.3", version.AsString())
+}
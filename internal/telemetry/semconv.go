@@ -0,0 +1,65 @@
+package telemetry
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// MCPMethod is the JSON-RPC method of an MCP request, eg- tools/call.
+type MCPMethod string
+
+const (
+	MCPMethodToolsCall  MCPMethod = "tools/call"
+	MCPMethodPromptsGet MCPMethod = "prompts/get"
+)
+
+// The attributes and metrics of the OpenTelemetry semantic conventions for MCP,
+// see https://opentelemetry.io/docs/specs/semconv/gen-ai/mcp/.
+// The semconv package doesn't define the ones specific to MCP yet.
+const (
+	// AttrMCPMethodName is the method of the MCP request, eg- tools/call
+	AttrMCPMethodName = attribute.Key("mcp.method.name")
+	// AttrGenAIToolName is the name of the tool called, without the name of its server
+	AttrGenAIToolName = semconv.GenAIToolNameKey
+	// AttrGenAIPromptName is the name of the prompt got, without the name of its server
+	AttrGenAIPromptName = attribute.Key("gen_ai.prompt.name")
+	// AttrGenAIOperationName is execute_tool for tool calls
+	AttrGenAIOperationName = semconv.GenAIOperationNameKey
+	// AttrErrorType is only set when the operation failed
+	AttrErrorType = semconv.ErrorTypeKey
+	// AttrMCPServerName is the name of the upstream MCP server in mcpjungle.
+	// The conventions have no attribute for it, so it is namespaced.
+	AttrMCPServerName = attribute.Key("mcpjungle.mcp_server.name")
+
+	// MetricMCPServerOperationDuration is the duration of the MCP requests mcpjungle serves, eg- the tool calls of
+	// its clients, from the point of view of the server
+	MetricMCPServerOperationDuration = "mcp.server.operation.duration"
+	// MetricMCPClientOperationDuration is the duration of the MCP requests mcpjungle sends to the upstream servers,
+	// from the point of view of their client
+	MetricMCPClientOperationDuration = "mcp.client.operation.duration"
+)
+
+// mcpOperationDurationBuckets are the bucket boundaries advised by the conventions for the operation durations.
+var mcpOperationDurationBuckets = []float64{0.01, 0.02, 0.05, 0.1, 0.2, 0.5, 1, 2, 5, 10, 30, 60, 120, 300}
+
+// errorTypeOther is the error.type of the operations that failed, mcpjungle doesn't classify their errors.
+var errorTypeOther = semconv.ErrorTypeOther
+
+// mcpOperationAttributes returns the conventional attributes of an MCP operation on the target tool or prompt
+// of a server.
+func mcpOperationAttributes(serverName string, method MCPMethod, target string, failed bool) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		AttrMCPMethodName.String(string(method)),
+		AttrMCPServerName.String(boundString(serverName)),
+	}
+	switch method {
+	case MCPMethodToolsCall:
+		attrs = append(attrs, AttrGenAIToolName.String(boundString(target)), semconv.GenAIOperationNameExecuteTool)
+	case MCPMethodPromptsGet:
+		attrs = append(attrs, AttrGenAIPromptName.String(boundString(target)))
+	}
+	if failed {
+		attrs = append(attrs, errorTypeOther)
+	}
+	return attrs
+}
@@ -5,7 +5,8 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
-	"unicode"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
 // GenerateAccessToken generates a 256-bit secure random access token for user authentication.
@@ -18,25 +19,7 @@ func GenerateAccessToken() (string, error) {
 	return base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString(b), nil
 }
 
-// ValidateAccessToken checks if a user-provided access token is valid.
-// It doesn't impose many conditions to allow flexibility.
-// It is up to the user to follow best security practices when assigning access tokens.
+// ValidateAccessToken checks if a user-provided access token is valid, see types.ValidateAccessToken.
 func ValidateAccessToken(token string) error {
-	if len(token) < 8 {
-		return fmt.Errorf("access token should be at least 8 characters in length")
-	}
-	if hasWhitespace(token) {
-		return fmt.Errorf("access token should not contain whitespace characters")
-	}
-	return nil
-}
-
-// hasWhitespace checks if the access token contains any whitespace characters.
-func hasWhitespace(token string) bool {
-	for _, r := range token {
-		if unicode.IsSpace(r) {
-			return true
-		}
-	}
-	return false
+	return types.ValidateAccessToken(token)
 }
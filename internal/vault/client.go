@@ -0,0 +1,265 @@
+// Package vault resolves the references to secrets stored in HashiCorp Vault held by the credential fields of
+// MCP servers, see types.VaultReference. It is a small client of the Vault HTTP API, limited to reading secrets,
+// renewing their leases and logging in with a token or a Kubernetes service account.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthMethod is how mcpjungle authenticates to Vault.
+type AuthMethod string
+
+const (
+	// AuthToken uses a Vault token, eg- a periodic token of a policy allowing to read the secrets.
+	AuthToken AuthMethod = "token"
+	// AuthKubernetes logs in with the token of the Kubernetes service account mcpjungle runs as.
+	AuthKubernetes AuthMethod = "kubernetes"
+)
+
+const (
+	// DefaultKubernetesMount is the path the Kubernetes auth method is usually mounted at.
+	DefaultKubernetesMount = "kubernetes"
+	// DefaultKubernetesTokenFile is where Kubernetes mounts the token of the service account of a pod.
+	DefaultKubernetesTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	// DefaultCacheTTL is how long the secrets without a lease, eg- those of KV secrets engines, are cached.
+	DefaultCacheTTL = 5 * time.Minute
+
+	// maxResponseBytes bounds how much of a response of Vault is read.
+	maxResponseBytes = 1 << 20
+)
+
+// Config configures the connection to Vault.
+type Config struct {
+	// Address is the URL of the Vault server, eg- https://vault.example.com:8200
+	Address string
+	// Namespace is the Vault Enterprise namespace of the secrets, empty for the root namespace.
+	Namespace string
+	// CACert is the path of the PEM encoded CA certificate the certificate of Vault is verified with,
+	// the system roots are used if empty.
+	CACert string
+
+	AuthMethod AuthMethod
+	// Token is the Vault token of the token auth method.
+	Token string
+	// KubernetesRole is the Vault role the Kubernetes auth method logs in with.
+	KubernetesRole string
+	// KubernetesMount is the path of the Kubernetes auth method, DefaultKubernetesMount if empty.
+	KubernetesMount string
+	// KubernetesTokenFile is the token of the service account, DefaultKubernetesTokenFile if empty.
+	KubernetesTokenFile string
+
+	// CacheTTL is how long the secrets without a lease are cached, DefaultCacheTTL if 0.
+	CacheTTL time.Duration
+	// HTTPClient sends the requests, a client trusting CACert is created if nil.
+	HTTPClient *http.Client
+}
+
+// Validate checks that the configuration is complete for its auth method.
+func (c *Config) Validate() error {
+	u, err := url.Parse(c.Address)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("the address of Vault must be an http or https URL, eg- https://vault.example.com:8200")
+	}
+	switch c.AuthMethod {
+	case AuthToken:
+		if c.Token == "" {
+			return errors.New("a Vault token is required for the token auth method")
+		}
+	case AuthKubernetes:
+		if c.KubernetesRole == "" {
+			return errors.New("a Vault role is required for the kubernetes auth method")
+		}
+	default:
+		return fmt.Errorf(
+			"unsupported Vault auth method '%s', must be one of: %s, %s", c.AuthMethod, AuthToken, AuthKubernetes,
+		)
+	}
+	if c.CacheTTL < 0 {
+		return errors.New("the cache TTL of the secrets must not be negative")
+	}
+	return nil
+}
+
+// Error is an error response of Vault.
+type Error struct {
+	StatusCode int
+	Errors     []string `json:"errors"`
+}
+
+func (e *Error) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("vault returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("vault returned status %d: %s", e.StatusCode, strings.Join(e.Errors, ", "))
+}
+
+// response is the body of the responses of Vault that mcpjungle reads.
+type response struct {
+	LeaseID       string          `json:"lease_id"`
+	LeaseDuration int             `json:"lease_duration"`
+	Renewable     bool            `json:"renewable"`
+	Data          json.RawMessage `json:"data"`
+	Auth          *struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+	} `json:"auth"`
+}
+
+// client sends the requests of a Store to Vault, logging in again when its token expires.
+type client struct {
+	cfg  Config
+	http *http.Client
+	now  func() time.Time
+
+	mu    sync.Mutex
+	token string
+	// tokenRefreshAt is when a token obtained by logging in should be replaced, zero for static tokens
+	tokenRefreshAt time.Time
+}
+
+func newClient(cfg Config) (*client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.KubernetesMount == "" {
+		cfg.KubernetesMount = DefaultKubernetesMount
+	}
+	if cfg.KubernetesTokenFile == "" {
+		cfg.KubernetesTokenFile = DefaultKubernetesTokenFile
+	}
+	c := &client{cfg: cfg, http: cfg.HTTPClient, now: time.Now}
+	if c.http == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if cfg.CACert != "" {
+			pem, err := os.ReadFile(cfg.CACert)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the CA certificate of Vault: %w", err)
+			}
+			roots := x509.NewCertPool()
+			if !roots.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("the CA certificate of Vault %s contains no PEM certificate", cfg.CACert)
+			}
+			transport.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+		}
+		c.http = &http.Client{Transport: transport, Timeout: 30 * time.Second}
+	}
+	if cfg.AuthMethod == AuthToken {
+		c.token = cfg.Token
+	}
+	return c, nil
+}
+
+// request sends an authenticated request to the path of the Vault API, eg- secret/data/mcp/github.
+// A token obtained by logging in is replaced once if Vault rejects it.
+func (c *client) request(ctx context.Context, method, path string, body any) (*response, error) {
+	token, err := c.currentToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.send(ctx, method, path, token, body)
+	var vErr *Error
+	if errors.As(err, &vErr) && vErr.StatusCode == http.StatusForbidden && c.cfg.AuthMethod == AuthKubernetes {
+		c.mu.Lock()
+		if c.token == token {
+			c.token = ""
+		}
+		c.mu.Unlock()
+		if token, err = c.currentToken(ctx); err != nil {
+			return nil, err
+		}
+		return c.send(ctx, method, path, token, body)
+	}
+	return resp, err
+}
+
+// currentToken returns the token of the requests, logging in if it has none or if it is about to expire.
+func (c *client) currentToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && (c.tokenRefreshAt.IsZero() || c.now().Before(c.tokenRefreshAt)) {
+		return c.token, nil
+	}
+
+	jwt, err := os.ReadFile(c.cfg.KubernetesTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the token of the Kubernetes service account: %w", err)
+	}
+	login := map[string]string{"role": c.cfg.KubernetesRole, "jwt": strings.TrimSpace(string(jwt))}
+	resp, err := c.send(ctx, http.MethodPost, "auth/"+c.cfg.KubernetesMount+"/login", "", login)
+	if err != nil {
+		return "", fmt.Errorf("failed to log in to Vault with the kubernetes auth method: %w", err)
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return "", errors.New("failed to log in to Vault with the kubernetes auth method: the response has no token")
+	}
+	c.token = resp.Auth.ClientToken
+	// the token is replaced before it expires, a token without a TTL is kept
+	c.tokenRefreshAt = time.Time{}
+	if ttl := time.Duration(resp.Auth.LeaseDuration) * time.Second; ttl > 0 {
+		c.tokenRefreshAt = c.now().Add(ttl * 2 / 3)
+	}
+	return c.token, nil
+}
+
+// send sends a request to Vault, returning its body if its status is 2xx and an *Error otherwise.
+func (c *client) send(ctx context.Context, method, path, token string, body any) (*response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	u := strings.TrimSuffix(c.cfg.Address, "/") + "/v1/" + path
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.cfg.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// the network error is not wrapped, it is about Vault rather than the MCP server
+		return nil, fmt.Errorf("failed to reach Vault at %s: %v", c.cfg.Address, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response of Vault: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		vErr := &Error{StatusCode: resp.StatusCode}
+		_ = json.Unmarshal(data, vErr)
+		return nil, vErr
+	}
+	r := &response{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, r); err != nil {
+			return nil, fmt.Errorf("invalid response of Vault: %w", err)
+		}
+	}
+	return r, nil
+}
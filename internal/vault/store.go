@@ -0,0 +1,189 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// Store resolves references to secrets stored in Vault.
+// The secrets are read when they are first referenced and cached in memory for the duration of their lease,
+// or for the cache TTL if they have none. Past two thirds of it, the lease is renewed, or the secret read again
+// if it can't be, so that a secret in use never expires.
+type Store struct {
+	client   *client
+	cacheTTL time.Duration
+
+	mu sync.Mutex
+	// kvVersions holds the version of the KV secrets engine of every mount, 1 for the other engines
+	kvVersions map[string]int
+	secrets    map[string]*cachedSecret // key: mount/path
+}
+
+// cachedSecret is a secret read from Vault, refreshed by the first reference past its refresh time.
+type cachedSecret struct {
+	mu sync.Mutex
+	// data is nil until the secret is read
+	data      map[string]any
+	leaseID   string
+	renewable bool
+	refreshAt time.Time
+	expiresAt time.Time
+}
+
+// NewStore creates a Store of the secrets of the Vault server configured by cfg.
+func NewStore(cfg Config) (*Store, error) {
+	c, err := newClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{client: c, cacheTTL: cfg.CacheTTL, kvVersions: map[string]int{}, secrets: map[string]*cachedSecret{}}
+	if s.cacheTTL == 0 {
+		s.cacheTTL = DefaultCacheTTL
+	}
+	return s, nil
+}
+
+// Resolve returns the value of the key of the secret referenced by ref.
+// A cached secret that can't be refreshed is still used until it expires.
+func (s *Store) Resolve(ctx context.Context, ref types.VaultReference) (string, error) {
+	s.mu.Lock()
+	secret, ok := s.secrets[ref.Mount+"/"+ref.Path]
+	if !ok {
+		secret = &cachedSecret{}
+		s.secrets[ref.Mount+"/"+ref.Path] = secret
+	}
+	s.mu.Unlock()
+
+	secret.mu.Lock()
+	defer secret.mu.Unlock()
+	now := s.client.now()
+	switch {
+	case secret.data == nil || !now.Before(secret.expiresAt):
+		if err := s.refresh(ctx, ref, secret); err != nil {
+			return "", err
+		}
+	case !now.Before(secret.refreshAt):
+		if err := s.refresh(ctx, ref, secret); err != nil {
+			log.Printf(
+				"[WARN] failed to refresh the secret %s/%s from Vault, using the cached one until %s: %v",
+				ref.Mount, ref.Path, secret.expiresAt.Format(time.RFC3339), err,
+			)
+		}
+	}
+
+	v, ok := secret.data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("the secret %s/%s in Vault has no key %s", ref.Mount, ref.Path, ref.Key)
+	}
+	if str, ok := v.(string); ok {
+		return str, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// refresh renews the lease of the secret if it has a renewable one, and reads the secret again otherwise.
+func (s *Store) refresh(ctx context.Context, ref types.VaultReference, secret *cachedSecret) error {
+	if secret.data != nil && secret.renewable && secret.leaseID != "" && s.client.now().Before(secret.expiresAt) {
+		resp, err := s.client.request(ctx, http.MethodPut, "sys/leases/renew", map[string]string{"lease_id": secret.leaseID})
+		// a lease that reached its max TTL is renewed for less than its duration, it is read again once it expires
+		if err == nil && resp.LeaseDuration > 0 {
+			s.setLease(secret, resp.LeaseDuration)
+			return nil
+		}
+	}
+
+	data, resp, err := s.read(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to read the secret %s/%s from Vault: %w", ref.Mount, ref.Path, err)
+	}
+	secret.data, secret.leaseID, secret.renewable = data, resp.LeaseID, resp.Renewable
+	s.setLease(secret, resp.LeaseDuration)
+	return nil
+}
+
+// setLease sets when the secret is refreshed and when it expires, given the duration of its lease in seconds.
+func (s *Store) setLease(secret *cachedSecret, leaseSeconds int) {
+	ttl := time.Duration(leaseSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = s.cacheTTL
+	}
+	now := s.client.now()
+	secret.refreshAt = now.Add(ttl * 2 / 3)
+	secret.expiresAt = now.Add(ttl)
+}
+
+// read reads the secret, at the path of its data for version 2 of the KV secrets engine.
+func (s *Store) read(ctx context.Context, ref types.VaultReference) (map[string]any, *response, error) {
+	path := ref.Mount + "/" + ref.Path
+	version := s.kvVersion(ctx, ref.Mount)
+	if version == 2 {
+		path = ref.Mount + "/data/" + ref.Path
+	}
+	resp, err := s.client.request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var data map[string]any
+	if version == 2 {
+		var kv struct {
+			Data map[string]any `json:"data"`
+		}
+		if err := json.Unmarshal(resp.Data, &kv); err != nil {
+			return nil, nil, fmt.Errorf("invalid secret: %w", err)
+		}
+		data = kv.Data
+	} else if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, nil, fmt.Errorf("invalid secret: %w", err)
+	}
+	if data == nil {
+		// a deleted version of a KV secret has no data
+		return nil, nil, errors.New("the secret has no data")
+	}
+	return data, resp, nil
+}
+
+// kvVersion returns the version of the KV secrets engine mounted at mount, like the Vault CLI detects it.
+// The other engines, and the mounts whose type can't be read, are read like version 1.
+func (s *Store) kvVersion(ctx context.Context, mount string) int {
+	s.mu.Lock()
+	version, ok := s.kvVersions[mount]
+	s.mu.Unlock()
+	if ok {
+		return version
+	}
+
+	version = 1
+	resp, err := s.client.request(ctx, http.MethodGet, "sys/internal/ui/mounts/"+mount, nil)
+	if err != nil {
+		var vErr *Error
+		if !errors.As(err, &vErr) {
+			// Vault is unreachable, the version is detected again by the next read
+			return version
+		}
+	} else {
+		var m struct {
+			Type    string            `json:"type"`
+			Options map[string]string `json:"options"`
+		}
+		if json.Unmarshal(resp.Data, &m) == nil && m.Type == "kv" && m.Options["version"] == "2" {
+			version = 2
+		}
+	}
+	s.mu.Lock()
+	s.kvVersions[mount] = version
+	s.mu.Unlock()
+	return version
+}
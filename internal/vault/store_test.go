@@ -0,0 +1,195 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/testhelpers"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// fakeVault serves a KV version 2 engine mounted at secret and a database engine issuing leased credentials.
+type fakeVault struct {
+	mu       sync.Mutex
+	token    string
+	requests []string
+	// renewable tells whether the leases can be renewed
+	renewable bool
+	// unavailable makes every request fail
+	unavailable bool
+	issued      int
+}
+
+func newTestStore(t *testing.T, f *fakeVault, cfg Config) (*Store, *time.Time) {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	cfg.Address = srv.URL
+	if cfg.AuthMethod == "" {
+		cfg.AuthMethod, cfg.Token = AuthToken, "root"
+	}
+	s, err := NewStore(cfg)
+	testhelpers.AssertNoError(t, err)
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	s.client.now = func() time.Time { return now }
+	return s, &now
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	reply := func(status int, v any) {
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	if f.unavailable {
+		reply(http.StatusServiceUnavailable, map[string]any{"errors": []string{"Vault is sealed"}})
+		return
+	}
+	if r.URL.Path == "/v1/auth/kubernetes/login" {
+		var login map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&login)
+		if login["role"] != "mcpjungle" || login["jwt"] != "service-account-jwt" {
+			reply(http.StatusBadRequest, map[string]any{"errors": []string{"invalid role or JWT"}})
+			return
+		}
+		f.issued++
+		f.token = "k8s-token"
+		reply(http.StatusOK, map[string]any{"auth": map[string]any{"client_token": "k8s-token", "lease_duration": 600}})
+		return
+	}
+	if r.Header.Get("X-Vault-Token") != f.token {
+		reply(http.StatusForbidden, map[string]any{"errors": []string{"permission denied"}})
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v1/sys/internal/ui/mounts/secret":
+		reply(http.StatusOK, map[string]any{"data": map[string]any{"type": "kv", "options": map[string]string{"version": "2"}}})
+	case "/v1/sys/internal/ui/mounts/database":
+		reply(http.StatusOK, map[string]any{"data": map[string]any{"type": "database"}})
+	case "/v1/secret/data/mcp/github":
+		reply(http.StatusOK, map[string]any{"data": map[string]any{"data": map[string]any{"token": "ghp_secret", "port": 8080}}})
+	case "/v1/database/creds/readonly":
+		reply(http.StatusOK, map[string]any{
+			"lease_id": "database/creds/readonly/1", "lease_duration": 300, "renewable": f.renewable,
+			"data": map[string]any{"password": "db-password"},
+		})
+	case "/v1/sys/leases/renew":
+		reply(http.StatusOK, map[string]any{"lease_id": "database/creds/readonly/1", "lease_duration": 300})
+	default:
+		reply(http.StatusNotFound, map[string]any{"errors": []string{}})
+	}
+}
+
+func TestResolveKVSecret(t *testing.T) {
+	f := &fakeVault{token: "root"}
+	s, now := newTestStore(t, f, Config{})
+	ctx := context.Background()
+	ref := types.VaultReference{Mount: "secret", Path: "mcp/github", Key: "token"}
+
+	v, err := s.Resolve(ctx, ref)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "ghp_secret", v)
+	v, err = s.Resolve(ctx, types.VaultReference{Mount: "secret", Path: "mcp/github", Key: "port"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "8080", v)
+	// the secret is read once, along with the version of its engine
+	testhelpers.AssertEqual(t, 2, len(f.requests))
+
+	_, err = s.Resolve(ctx, types.VaultReference{Mount: "secret", Path: "mcp/github", Key: "missing"})
+	testhelpers.AssertStringContains(t, err.Error(), "has no key missing")
+
+	// past two thirds of the cache TTL, the secret is read again, failures keep the cached one until it expires
+	f.unavailable = true
+	*now = now.Add(4 * time.Minute)
+	v, err = s.Resolve(ctx, ref)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "ghp_secret", v)
+
+	*now = now.Add(2 * time.Minute)
+	_, err = s.Resolve(ctx, ref)
+	var vErr *Error
+	testhelpers.AssertTrue(t, errors.As(err, &vErr), "expected an error response of Vault")
+	testhelpers.AssertStringContains(t, err.Error(), "Vault is sealed")
+
+	_, err = s.Resolve(ctx, types.VaultReference{Mount: "secret", Path: "mcp/unknown", Key: "token"})
+	testhelpers.AssertStringContains(t, err.Error(), "failed to read the secret secret/mcp/unknown")
+}
+
+func TestResolveLeasedSecret(t *testing.T) {
+	f := &fakeVault{token: "root", renewable: true}
+	s, now := newTestStore(t, f, Config{})
+	ctx := context.Background()
+	ref := types.VaultReference{Mount: "database", Path: "creds/readonly", Key: "password"}
+
+	v, err := s.Resolve(ctx, ref)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "db-password", v)
+	testhelpers.AssertEqual(t, "GET /v1/database/creds/readonly", f.requests[len(f.requests)-1])
+
+	// the lease is renewed before it expires, instead of issuing new credentials
+	*now = now.Add(250 * time.Second)
+	_, err = s.Resolve(ctx, ref)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "PUT /v1/sys/leases/renew", f.requests[len(f.requests)-1])
+
+	// an expired lease can't be renewed anymore
+	*now = now.Add(time.Hour)
+	_, err = s.Resolve(ctx, ref)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "GET /v1/database/creds/readonly", f.requests[len(f.requests)-1])
+}
+
+func TestKubernetesAuth(t *testing.T) {
+	jwt := filepath.Join(t.TempDir(), "token")
+	testhelpers.AssertNoError(t, os.WriteFile(jwt, []byte("service-account-jwt\n"), 0o600))
+	f := &fakeVault{}
+	s, now := newTestStore(t, f, Config{AuthMethod: AuthKubernetes, KubernetesRole: "mcpjungle", KubernetesTokenFile: jwt})
+	ctx := context.Background()
+	ref := types.VaultReference{Mount: "secret", Path: "mcp/github", Key: "token"}
+
+	v, err := s.Resolve(ctx, ref)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "ghp_secret", v)
+	testhelpers.AssertEqual(t, 1, f.issued)
+
+	// the token is replaced before it expires
+	*now = now.Add(9 * time.Minute)
+	_, err = s.Resolve(ctx, ref)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 2, f.issued)
+
+	// and when Vault rejects it, eg- because it was revoked
+	f.token = "revoked"
+	*now = now.Add(5 * time.Minute)
+	_, err = s.Resolve(ctx, ref)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 3, f.issued)
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		cfg  Config
+		want string
+	}{
+		{Config{Address: "vault:8200", AuthMethod: AuthToken, Token: "root"}, "must be an http or https URL"},
+		{Config{Address: "https://vault:8200", AuthMethod: AuthToken}, "a Vault token is required"},
+		{Config{Address: "https://vault:8200", AuthMethod: AuthKubernetes}, "a Vault role is required"},
+		{Config{Address: "https://vault:8200", AuthMethod: "approle"}, "unsupported Vault auth method 'approle'"},
+	}
+	for _, tt := range tests {
+		err := tt.cfg.Validate()
+		testhelpers.AssertStringContains(t, err.Error(), tt.want)
+	}
+	testhelpers.AssertNoError(t, (&Config{Address: "https://vault:8200", AuthMethod: AuthToken, Token: "root"}).Validate())
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"errors"
-	"fmt"
 	"os"
 
 	"github.com/mcpjungle/mcpjungle/cmd"
@@ -11,8 +10,8 @@ import (
 func main() {
 	if err := cmd.Execute(); err != nil {
 		if !errors.Is(err, cmd.ErrSilent) {
-			_, _ = fmt.Fprintln(os.Stderr, err)
+			cmd.PrintError(os.Stderr, err)
 		}
-		os.Exit(1)
+		os.Exit(cmd.ExitCodeForError(err))
 	}
 }
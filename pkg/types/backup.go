@@ -0,0 +1,98 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// A backup is a gzip compressed stream of JSON lines: a BackupHeader, followed by a BackupRecord per row of the
+// backed up tables, table after table, and a last BackupRecord with End set. A backup without it is incomplete.
+const (
+	// BackupFormat is the Format of the header of every backup.
+	BackupFormat = "mcpjungle-backup"
+	// BackupFormatVersion is the version of the layout of the backups, it changes when the layout changes.
+	// The rows themselves follow the database schema, whose version is BackupHeader.SchemaVersion.
+	BackupFormatVersion = 1
+	// BackupContentType is the content type of the backups, streamed by POST /api/v1/backup.
+	BackupContentType = "application/gzip"
+	// BackupPassphraseHeader holds the passphrase the credentials of a backup are encrypted with,
+	// in the requests restoring it.
+	BackupPassphraseHeader = "X-Backup-Passphrase"
+	// MinBackupPassphraseLength is the length of the shortest passphrase backups are encrypted with.
+	MinBackupPassphraseLength = 8
+)
+
+// BackupRequest is the body of the request creating a backup.
+type BackupRequest struct {
+	// Passphrase encrypts the credentials of the backup, eg- the access tokens of the users and the bearer tokens
+	// of the MCP servers. It is needed to restore the backup.
+	Passphrase string `json:"passphrase"`
+	// IncludeAudit includes the audit data, ie- the delivery log of the webhooks, which can be large.
+	IncludeAudit bool `json:"include_audit,omitempty"`
+}
+
+// BackupHeader is the first line of a backup.
+type BackupHeader struct {
+	// Format is always BackupFormat.
+	Format        string `json:"format"`
+	FormatVersion int    `json:"format_version"`
+	// SchemaVersion is the version of the database schema the rows follow.
+	// A backup can only be restored by the servers with the same schema version.
+	SchemaVersion int `json:"schema_version"`
+	// Version is the version of mcpjungle that made the backup.
+	Version string `json:"version"`
+	// ServerMode is the mode of the server that made the backup, it is restored in a server in the same mode.
+	ServerMode   string    `json:"server_mode"`
+	CreatedAt    time.Time `json:"created_at"`
+	IncludeAudit bool      `json:"include_audit"`
+	// Tables are the tables of the backup, in the order of their rows.
+	Tables     []BackupTable    `json:"tables"`
+	Encryption BackupEncryption `json:"encryption"`
+}
+
+// Rows returns the total number of rows of the backup.
+func (h *BackupHeader) Rows() int64 {
+	var n int64
+	for _, t := range h.Tables {
+		n += t.Rows
+	}
+	return n
+}
+
+// BackupTable is a table of a backup along with its number of rows.
+type BackupTable struct {
+	Name string `json:"name"`
+	Rows int64  `json:"rows"`
+}
+
+// BackupEncryption describes how the credentials of a backup are encrypted.
+// The key is derived from the passphrase with the KDF, the credentials are encrypted with AES-256-GCM.
+type BackupEncryption struct {
+	Algorithm  string `json:"algorithm"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	// Check is a known value encrypted with the key, to tell a wrong passphrase before anything is restored.
+	Check string `json:"check"`
+}
+
+// BackupRecord is every line of a backup after its header.
+type BackupRecord struct {
+	// Table is the table of the row.
+	Table string `json:"table,omitempty"`
+	// Row holds the values of the row by column, the credentials are encrypted.
+	Row json.RawMessage `json:"row,omitempty"`
+	// End is set on the last record of a complete backup.
+	End bool `json:"end,omitempty"`
+	// Error is set on the last record of a backup that failed after it started, it is incomplete.
+	Error *APIError `json:"error,omitempty"`
+}
+
+// RestoreResult is the outcome of restoring a backup.
+type RestoreResult struct {
+	// Version is the version of mcpjungle that made the backup.
+	Version   string    `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	// Tables are the restored tables along with the number of rows restored in each one.
+	Tables []BackupTable `json:"tables"`
+}
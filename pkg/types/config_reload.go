@@ -0,0 +1,24 @@
+package types
+
+// ConfigReloadResult is the outcome of reloading the config file of a running server.
+// If Errors is not empty, the config file was rejected and the running configuration is unchanged.
+type ConfigReloadResult struct {
+	// ConfigFile is the path of the config file that was read, empty if the server has none.
+	ConfigFile string `json:"config_file,omitempty"`
+	// Applied are the changed settings that took effect right away.
+	Applied []ConfigSettingChange `json:"applied"`
+	// Skipped are the changed settings that only take effect once the server is restarted.
+	Skipped []ConfigSettingChange `json:"skipped"`
+	Errors  []string              `json:"errors"`
+}
+
+// ConfigSettingChange is a setting of the server whose value changed in its config file.
+// The secret values are masked.
+type ConfigSettingChange struct {
+	// Key is the path of the setting in the config file, eg- api.rate_limit_per_min
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+	// Reason is why the change was not applied, empty for the applied ones.
+	Reason string `json:"reason,omitempty"`
+}
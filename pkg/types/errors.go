@@ -0,0 +1,85 @@
+package types
+
+// ErrorCode identifies the kind of failure of an API request, so that clients can handle errors without parsing messages.
+// Codes are part of the API's contract: existing codes never change meaning, new ones may be added.
+type ErrorCode string
+
+// The registry of error codes returned by the API, along with the HTTP status they are returned with.
+const (
+	// ErrorCodeInvalidRequest (400) means the request could not be parsed, eg- its body is not valid JSON.
+	ErrorCodeInvalidRequest ErrorCode = "invalid_request"
+	// ErrorCodeValidationFailed (400) means a value of the request is invalid.
+	// The details name it in "field" for a field of the body or "parameter" for a query parameter.
+	// When several fields of the body are invalid, "errors" lists all of them as FieldErrors.
+	ErrorCodeValidationFailed ErrorCode = "validation_failed"
+	// ErrorCodeUnauthorized (401) means the request has no valid access token.
+	ErrorCodeUnauthorized ErrorCode = "unauthorized"
+	// ErrorCodeForbidden (403) means the user is not allowed to make the request.
+	// The details hold the "required_role" if the user lacks a role.
+	ErrorCodeForbidden ErrorCode = "forbidden"
+	// ErrorCodeNotInitialized (403) means the server must be initialized before it serves the request.
+	ErrorCodeNotInitialized ErrorCode = "not_initialized"
+	// ErrorCodeWrongMode (403) means the request is not available in the mode the server runs in.
+	// The details hold the "required_mode".
+	ErrorCodeWrongMode ErrorCode = "wrong_mode"
+	// ErrorCodeNotFound (404) means the entity the request is about does not exist.
+	ErrorCodeNotFound ErrorCode = "not_found"
+	// ErrorCodeAlreadyExists (409) means the entity the request creates already exists.
+	ErrorCodeAlreadyExists ErrorCode = "already_exists"
+	// ErrorCodeRequestInProgress (409) means a request with the same idempotency key is still being processed.
+	ErrorCodeRequestInProgress ErrorCode = "request_in_progress"
+	// ErrorCodeRegistryNotEmpty (409) means a backup can't be restored because the registry already has entities,
+	// the details hold the number of "entities" by kind.
+	ErrorCodeRegistryNotEmpty ErrorCode = "registry_not_empty"
+	// ErrorCodeVersionConflict (412) means the entity was changed since the version the request was computed against.
+	ErrorCodeVersionConflict ErrorCode = "version_conflict"
+	// ErrorCodeIdempotencyKeyReused (422) means the idempotency key of the request was used for a different request.
+	ErrorCodeIdempotencyKeyReused ErrorCode = "idempotency_key_reused"
+	// ErrorCodeIncompatibleBackup (422) means a backup can't be restored by this server, eg- because it was made by
+	// a version of mcpjungle with a different database schema. The message tells how to restore it.
+	ErrorCodeIncompatibleBackup ErrorCode = "incompatible_backup"
+	// ErrorCodeBatchFailed (422) means an atomic batch was rejected because some of its items failed.
+	ErrorCodeBatchFailed ErrorCode = "batch_failed"
+	// ErrorCodeRateLimited (429) means the caller made too many requests, see the Retry-After header.
+	ErrorCodeRateLimited ErrorCode = "rate_limited"
+	// ErrorCodeInternal (500) means the server failed to process the request.
+	ErrorCodeInternal ErrorCode = "internal_error"
+	// ErrorCodeUpstreamUnreachable (502) means the server could not connect to the MCP server the request is about.
+	ErrorCodeUpstreamUnreachable ErrorCode = "upstream_unreachable"
+	// ErrorCodeCredentialUnavailable (502) means a credential of the MCP server the request is about references
+	// a secret that could not be resolved, eg- because Vault is unreachable.
+	ErrorCodeCredentialUnavailable ErrorCode = "credential_unavailable"
+	// ErrorCodeUnavailable (503) means the feature the request uses is not available on this server,
+	// or that the server can't serve the request for now, eg- because its database is busy.
+	ErrorCodeUnavailable ErrorCode = "unavailable"
+	// ErrorCodeDockerUnavailable (503) means the MCP server the request is about is launched in a container,
+	// but the Docker daemon of the registry is not reachable.
+	ErrorCodeDockerUnavailable ErrorCode = "docker_unavailable"
+	// ErrorCodeWarmingUp (503) means the MCP server the request is about wasn't synchronized yet since the registry
+	// started, the request can be retried shortly.
+	ErrorCodeWarmingUp ErrorCode = "warming_up"
+)
+
+// ErrorCodes lists every error code of the registry.
+var ErrorCodes = []ErrorCode{
+	ErrorCodeInvalidRequest, ErrorCodeValidationFailed, ErrorCodeUnauthorized, ErrorCodeForbidden,
+	ErrorCodeNotInitialized, ErrorCodeWrongMode, ErrorCodeNotFound, ErrorCodeAlreadyExists,
+	ErrorCodeRequestInProgress, ErrorCodeRegistryNotEmpty, ErrorCodeVersionConflict, ErrorCodeIdempotencyKeyReused,
+	ErrorCodeIncompatibleBackup, ErrorCodeBatchFailed,
+	ErrorCodeRateLimited, ErrorCodeInternal, ErrorCodeUpstreamUnreachable, ErrorCodeCredentialUnavailable,
+	ErrorCodeUnavailable, ErrorCodeDockerUnavailable, ErrorCodeWarmingUp,
+}
+
+// APIError describes why an API request failed.
+type APIError struct {
+	Code ErrorCode `json:"code"`
+	// Message is a human-readable description of the failure, it may change between versions.
+	Message string `json:"message"`
+	// Details holds information specific to the code, see the documentation of every code.
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// ErrorResponse is the body of every error response of the API.
+type ErrorResponse struct {
+	Error APIError `json:"error"`
+}
@@ -0,0 +1,53 @@
+package types
+
+import "time"
+
+// HealthStatus is the aggregate health of the upstream MCP servers of a registry.
+type HealthStatus string
+
+const (
+	// HealthHealthy means that no MCP server failed its last health check.
+	HealthHealthy HealthStatus = "healthy"
+	// HealthDegraded means that some MCP servers failed their last health check, but not all of them.
+	HealthDegraded HealthStatus = "degraded"
+	// HealthCritical means that every MCP server that was checked failed its last health check.
+	HealthCritical HealthStatus = "critical"
+)
+
+// ServerHealthStatus is the outcome of the last health check of an MCP server.
+type ServerHealthStatus string
+
+const (
+	ServerHealthy       ServerHealthStatus = "healthy"
+	ServerUnhealthy     ServerHealthStatus = "unhealthy"
+	ServerHealthUnknown ServerHealthStatus = "unknown"
+)
+
+// ServerHealth is the result of the last health check of a registered MCP server.
+// A health check connects to the server and pings it.
+type ServerHealth struct {
+	Name   string             `json:"name"`
+	Status ServerHealthStatus `json:"status"`
+	// CheckedAt is when the server was last checked, nil if it wasn't checked since the registry started.
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	// LatencyMs is how long the last check took, in milliseconds.
+	LatencyMs int64 `json:"latency_ms"`
+	// ConsecutiveFailures is the number of checks that failed in a row, 0 if the last one succeeded.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+	// Error is why the last check failed.
+	Error string `json:"error,omitempty"`
+	// WarmingUp is true until the first synchronization of the server since the registry started is done,
+	// its tools can't be called until then.
+	WarmingUp bool `json:"warming_up,omitempty"`
+	// SyncedAt is when the tools of the server were last synchronized, nil if they weren't since the registry started.
+	SyncedAt *time.Time `json:"synced_at,omitempty"`
+	// SyncError is why the last synchronization failed.
+	SyncError string `json:"sync_error,omitempty"`
+}
+
+// ServersHealth is the health of all the upstream MCP servers of a registry.
+// The API responds with status 503 Service Unavailable when it is HealthCritical.
+type ServersHealth struct {
+	Status  HealthStatus   `json:"status"`
+	Servers []ServerHealth `json:"servers"`
+}
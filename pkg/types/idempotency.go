@@ -0,0 +1,10 @@
+package types
+
+// HTTP headers of idempotent API requests.
+const (
+	// IdempotencyKeyHeader holds a unique key chosen by the client for a POST request.
+	// The server stores the response for the key, and replays it if the request is sent again with the same key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set to "true" on the responses that were replayed for a key sent again.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
@@ -1,7 +1,12 @@
 package types
 
+import "fmt"
+
 // McpClient represents an MCP client that is authorized to access the MCPJungle MCP Proxy server.
 type McpClient struct {
+	// UUID is the immutable identifier of the client, it is accepted in place of its name to address it.
+	// It is assigned by the server and ignored when the client is created or updated.
+	UUID string `json:"uuid,omitempty"`
 	// Name is the name of the client that uniquely identifies it within mcpungle.
 	Name        string `json:"name"`
 	Description string `json:"description"`
@@ -14,6 +19,29 @@ type McpClient struct {
 	AllowList []string `json:"allow_list"`
 }
 
+// Validate checks the configuration of an MCP client and returns ValidationErrors listing every invalid field.
+// The access token is optional, one is generated for clients that don't have any.
+func (c *McpClient) Validate() error {
+	var errs ValidationErrors
+	if c.Name == "" {
+		errs.Add("name", "is required")
+	}
+	if c.AccessToken != "" {
+		if problem := accessTokenProblem(c.AccessToken); problem != "" {
+			errs.Add("access_token", "%s", problem)
+		}
+	}
+	for i, s := range c.AllowList {
+		if s == AllowAllMcpServers {
+			continue
+		}
+		if problem := serverNameProblem(s); problem != "" {
+			errs.Add(fmt.Sprintf("allow_list[%d]", i), "is not a valid server name: %q %s", s, problem)
+		}
+	}
+	return errs.Err()
+}
+
 // AllowAllMcpServers is a wildcard operator used to indicate that a mcp client has access to all mcp servers
 // in mcpjungle.
 const AllowAllMcpServers = "*"
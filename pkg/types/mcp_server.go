@@ -1,6 +1,15 @@
 package types
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
 
 // McpServerTransport represents the transport protocol used by an MCP server.
 // All transport types supported by mcpjungle are defined in this file with this type.
@@ -10,6 +19,14 @@ const (
 	TransportStdio          McpServerTransport = "stdio"
 	TransportStreamableHTTP McpServerTransport = "streamable_http"
 	TransportSSE            McpServerTransport = "sse"
+
+	// TransportOpenAPI is a REST service described by an OpenAPI spec, whose operations are served as tools by
+	// an adapter built into mcpjungle.
+	TransportOpenAPI McpServerTransport = "openapi"
+
+	// TransportWebhookTool is a plain HTTP endpoint, eg- the webhook of an automation, served as a single tool by
+	// mcpjungle itself.
+	TransportWebhookTool McpServerTransport = "webhook_tool"
 )
 
 // SessionMode represents the session management mode for an MCP server.
@@ -28,8 +45,247 @@ const (
 	SessionModeStateful SessionMode = "stateful"
 )
 
+// ServerState is the lifecycle state of the persistent connection to an MCP server, eg- the process of a stdio server.
+// Only servers with a persistent connection have one, ie, those in stateful mode or started lazily.
+type ServerState string
+
+const (
+	// ServerStateStopped means that the server is not running, it is started by the next tool call targeting it.
+	ServerStateStopped ServerState = "stopped"
+	// ServerStateStarting means that the server is being started.
+	ServerStateStarting ServerState = "starting"
+	// ServerStateRunning means that the server is running and serves tool calls.
+	ServerStateRunning ServerState = "running"
+	// ServerStateIdleStopping means that the server is being stopped because it was not used for the idle timeout.
+	ServerStateIdleStopping ServerState = "idle-stopping"
+)
+
+// HTTPPoolConfig tunes the pool of connections mcpjungle keeps open to a streamable HTTP server.
+// The pool is shared by all the calls to the server, so that they reuse its connections instead of opening new ones.
+// Fields left to zero use the defaults.
+type HTTPPoolConfig struct {
+	// MaxIdleConnsPerHost is the maximum number of idle connections kept open to the server (default 32).
+	MaxIdleConnsPerHost int `json:"max_idle_conns_per_host,omitempty"`
+
+	// IdleConnTimeoutSec is the number of seconds an idle connection is kept open before being closed (default 90).
+	IdleConnTimeoutSec int `json:"idle_conn_timeout_sec,omitempty"`
+
+	// DisableHTTP2 keeps the connections on HTTP/1.1.
+	// Otherwise, HTTP/2 is used with https servers that support it.
+	DisableHTTP2 bool `json:"disable_http2,omitempty"`
+}
+
+// KeepAliveConfig keeps the session of a stateful streamable HTTP server open while it is idle, for servers that
+// drop idle sessions, so that the next call doesn't have to start a new one.
+type KeepAliveConfig struct {
+	// IntervalSec is the number of seconds between the pings sent to the server while its session is idle.
+	IntervalSec int `json:"interval_sec"`
+
+	// MaxIdleSec is the number of seconds an idle session is kept alive, after which it is closed.
+	// It replaces the session idle timeout for the server. If 0, the session idle timeout closes it.
+	MaxIdleSec int `json:"max_idle_sec,omitempty"`
+}
+
+// ContainerConfig describes the container an MCP server runs in.
+// mcpjungle doesn't run the container of an HTTP or SSE server itself,
+// `mcpjungle generate compose` uses it to run the server with docker compose.
+// A stdio server with a container is launched by mcpjungle in a container of the local Docker daemon instead of
+// a process, its command and args overriding the default command of the image and its env being that of the container.
+type ContainerConfig struct {
+	// Image is the image of the container, eg- ghcr.io/github/github-mcp-server:latest
+	Image string `json:"image"`
+
+	// Env is the set of environment variables of the container of an HTTP or SSE server.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Ports are the ports of the container published to the host, in the docker compose syntax, eg- "8000:8000".
+	Ports []string `json:"ports,omitempty"`
+
+	// Port is the port the server listens on in the container. It defaults to the port of the server's URL.
+	Port int `json:"port,omitempty"`
+
+	// Volumes are mounted in the container of a stdio server, in the docker syntax, eg- "/srv/data:/data:ro".
+	Volumes []string `json:"volumes,omitempty"`
+
+	// Network is the network mode of the container of a stdio server, eg- "none" or "host", "bridge" if empty.
+	Network string `json:"network,omitempty"`
+
+	// MemoryMB limits the memory of the container of a stdio server, in megabytes. 0 means no limit.
+	MemoryMB int `json:"memory_mb,omitempty"`
+
+	// CPUs limits the number of CPUs the container of a stdio server uses, eg- 0.5. 0 means no limit.
+	CPUs float64 `json:"cpus,omitempty"`
+}
+
+// ContainerState is the state of the container a stdio server is launched in by mcpjungle.
+type ContainerState string
+
+const (
+	// ContainerStateStopped means that no container of the server is running.
+	// A container is started by the next call to the server.
+	ContainerStateStopped ContainerState = "stopped"
+	// ContainerStatePulling means that the image of the server is being pulled.
+	ContainerStatePulling ContainerState = "pulling"
+	// ContainerStateStarting means that a container of the server is being created and started.
+	ContainerStateStarting ContainerState = "starting"
+	// ContainerStateRunning means that at least one container of the server is running.
+	ContainerStateRunning ContainerState = "running"
+	// ContainerStateRestarting means that the container of a persistent session exited and is being restarted.
+	ContainerStateRestarting ContainerState = "restarting"
+	// ContainerStateExited means that the container of a persistent session exited and could not be restarted.
+	// A new one is started by the next call to the server.
+	ContainerStateExited ContainerState = "exited"
+)
+
+// PackageRuntime is the package runner a stdio server is launched with from its package.
+type PackageRuntime string
+
+const (
+	// PackageRuntimeNpx runs a package of the npm registry with npx.
+	PackageRuntimeNpx PackageRuntime = "npx"
+	// PackageRuntimeUvx runs a package of PyPI with uvx.
+	PackageRuntimeUvx PackageRuntime = "uvx"
+)
+
+// PackageVersionLatest is the version of a package that runs its latest release.
+const PackageVersionLatest = "latest"
+
+// PackageConfig is the shorthand for a stdio server launched from a package of npm or PyPI,
+// eg- {"runtime": "npx", "name": "@modelcontextprotocol/server-filesystem"}.
+// mcpjungle expands it into the command line of its runtime, followed by the args of the server,
+// eg- npx -y @modelcontextprotocol/server-filesystem@latest /tmp
+type PackageConfig struct {
+	Runtime PackageRuntime `json:"runtime"`
+
+	// Name is the name of the package, without its version.
+	Name string `json:"name"`
+
+	// Version is the version of the package to run, latest by default.
+	Version string `json:"version,omitempty"`
+
+	// Pin replaces the latest version with the version it is when the server is registered,
+	// so that the server keeps running the same version.
+	Pin bool `json:"pin,omitempty"`
+}
+
+// RunsLatest reports whether the latest release of the package is run, which may change from one start to the next.
+func (p *PackageConfig) RunsLatest() bool {
+	return p.Version == "" || p.Version == PackageVersionLatest
+}
+
+// CommandLine returns the command running the package with its runtime, and its arguments, followed by args.
+func (p *PackageConfig) CommandLine(args []string) (string, []string) {
+	version := p.Version
+	if version == "" {
+		version = PackageVersionLatest
+	}
+	var spec []string
+	switch {
+	case p.Runtime == PackageRuntimeNpx:
+		// -y doesn't ask for the confirmation of the install, there is no one to answer it
+		spec = []string{"-y", p.Name + "@" + version}
+	case version == PackageVersionLatest:
+		spec = []string{p.Name + "@" + PackageVersionLatest}
+	default:
+		spec = []string{p.Name + "==" + version}
+	}
+	return string(p.Runtime), append(spec, args...)
+}
+
+// OpenAPIAuthType is the way the adapter of an OpenAPI server authenticates with the REST service.
+type OpenAPIAuthType string
+
+const (
+	// OpenAPIAuthBearer sends the credential as a bearer token in the Authorization header.
+	OpenAPIAuthBearer OpenAPIAuthType = "bearer"
+	// OpenAPIAuthAPIKey sends the credential as is in a custom header, eg- X-API-Key.
+	OpenAPIAuthAPIKey OpenAPIAuthType = "api_key"
+)
+
+// OpenAPIAuth is the credential the adapter of an OpenAPI server sends with every request to the REST service.
+type OpenAPIAuth struct {
+	Type OpenAPIAuthType `json:"type"`
+
+	// Header is the name of the header carrying the API key, only used by the api_key type.
+	Header string `json:"header,omitempty"`
+
+	Value string `json:"value,omitempty"`
+}
+
+// OpenAPIParameter is a parameter of an operation of a REST service.
+// It is passed as the tool argument of the same name.
+type OpenAPIParameter struct {
+	Name string `json:"name"`
+
+	// In is where the parameter is sent: "path", "query" or "header".
+	In string `json:"in"`
+
+	Required bool `json:"required,omitempty"`
+}
+
+// OpenAPIOperation is an operation of a REST service served as a tool.
+type OpenAPIOperation struct {
+	// Tool is the name of the tool, derived from the operationId.
+	Tool string `json:"tool"`
+
+	Description string `json:"description,omitempty"`
+
+	// Method is the HTTP method of the operation, eg- GET.
+	Method string `json:"method"`
+
+	// Path is the path of the operation relative to the base URL, with its path parameters in braces, eg- /pets/{id}.
+	Path string `json:"path"`
+
+	Parameters []OpenAPIParameter `json:"parameters,omitempty"`
+
+	// BodyContentType is the content type of the request body, empty if the operation takes none.
+	// The body is passed as the "body" argument of the tool.
+	BodyContentType string `json:"body_content_type,omitempty"`
+
+	// InputSchema is the JSON schema of the arguments of the tool.
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// OpenAPIConfig describes a REST service served as an MCP server by mcpjungle.
+// It is generated from the service's OpenAPI spec by `mcpjungle register openapi`.
+type OpenAPIConfig struct {
+	// BaseURL is the URL the paths of the operations are relative to, eg- https://api.example.com/v1
+	BaseURL string `json:"base_url"`
+
+	// Auth is the credential sent with every request, nil if the service requires none.
+	Auth *OpenAPIAuth `json:"auth,omitempty"`
+
+	Operations []OpenAPIOperation `json:"operations"`
+}
+
+// WebhookToolConfig describes an HTTP endpoint served as the single tool of a webhook_tool server.
+// The tool is named after the server, its arguments are sent as the JSON body of the request.
+// It is created by `mcpjungle create webhook-tool`.
+type WebhookToolConfig struct {
+	// URL is the endpoint the arguments are sent to, eg- https://automation.example.com/hooks/deploy
+	URL string `json:"url"`
+
+	// Method is the HTTP method of the request, eg- POST.
+	Method string `json:"method"`
+
+	// Headers are sent with every request, eg- X-Source. The credential belongs in Auth.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Auth is the credential sent with every request, nil if the endpoint requires none.
+	Auth *OpenAPIAuth `json:"auth,omitempty"`
+
+	// Description is the description of the tool shown to MCP clients.
+	Description string `json:"description,omitempty"`
+
+	// InputSchema is the JSON schema the arguments of the tool are validated against before they are sent,
+	// empty to accept any object.
+	InputSchema json.RawMessage `json:"input_schema,omitempty"`
+}
+
 // McpServer represents an MCP server registered in the MCPJungle registry.
 type McpServer struct {
+	// UUID is the immutable identifier of the server, it is accepted in place of its name to address it.
+	UUID        string `json:"uuid,omitempty"`
 	Name        string `json:"name"`
 	Transport   string `json:"transport"`
 	Description string `json:"description"`
@@ -41,6 +297,40 @@ type McpServer struct {
 	Env     map[string]string `json:"env"`
 
 	SessionMode string `json:"session_mode"`
+
+	LazyStart bool `json:"lazy_start,omitempty"`
+
+	// HTTPPool is the connection pool configuration of a streamable HTTP server, nil if it uses the defaults.
+	HTTPPool *HTTPPoolConfig `json:"http_pool,omitempty"`
+
+	// KeepAlive is the keep-alive configuration of the session of a stateful streamable HTTP server, nil if disabled.
+	KeepAlive *KeepAliveConfig `json:"keep_alive,omitempty"`
+
+	// State is the lifecycle state of the server's persistent connection, empty if it has none.
+	State ServerState `json:"state,omitempty"`
+
+	// OpenAPI is the REST service of an openapi server, without its credential.
+	OpenAPI *OpenAPIConfig `json:"openapi,omitempty"`
+
+	// WebhookTool is the endpoint of a webhook_tool server, without its credential.
+	WebhookTool *WebhookToolConfig `json:"webhook_tool,omitempty"`
+
+	// Container is the container a stdio server is launched in, nil if it runs as a process.
+	Container *ContainerConfig `json:"container,omitempty"`
+
+	// Package is the package a stdio server is launched from, nil if it was registered with its command.
+	// Command and Args are then the command line it expands into.
+	Package *PackageConfig `json:"package,omitempty"`
+
+	// ContainerState is the state of the container of a stdio server launched in a container, empty otherwise.
+	ContainerState ContainerState `json:"container_state,omitempty"`
+
+	// CredentialSource tells whether the credentials of the server are stored by mcpjungle or resolved from Vault.
+	// It is empty for servers older than credential sources.
+	CredentialSource CredentialSource `json:"credential_source,omitempty"`
+
+	// Version is incremented every time the server's configuration changes, it is also returned as the ETag.
+	Version uint `json:"version,omitempty"`
 }
 
 // RegisterServerInput is the input structure for registering a new MCP server with mcpjungle.
@@ -50,7 +340,7 @@ type RegisterServerInput struct {
 	Name string `json:"name"`
 
 	// Transport (mandatory) is the transport protocol used by the MCP server.
-	// valid values are "stdio", "streamable_http", and "sse".
+	// valid values are "stdio", "streamable_http", "sse", "openapi" and "webhook_tool".
 	Transport string `json:"transport"`
 
 	Description string `json:"description"`
@@ -63,21 +353,95 @@ type RegisterServerInput struct {
 	// BearerToken is an optional token used for authenticating requests to the remote MCP server.
 	// It is useful when the upstream MCP server requires static tokens (e.g., API tokens) for authentication.
 	// If the transport is "stdio", this field is ignored.
+	// Like the values of Env and the credential of OpenAPI, it may reference a secret stored in Vault instead,
+	// see VaultReference.
 	BearerToken string `json:"bearer_token,omitempty"`
 
 	// Command is the command to run the mcp server.
-	// It is mandatory when the transport is "stdio".
+	// It is mandatory when the transport is "stdio", unless the server runs in a container,
+	// in which case the default command of the image is run if it is empty, or is launched from a Package.
 	Command string `json:"command,omitempty"`
 
 	// Args is the list of arguments to pass to the command when the transport is "stdio".
+	// For a server launched from a Package, they follow the package on the command line.
 	Args []string `json:"args,omitempty"`
 
+	// Package is the shorthand for the command of a stdio server launched by npx or uvx, see PackageConfig.
+	// Command must be empty then.
+	Package *PackageConfig `json:"package,omitempty"`
+
 	// Env is the set of environment variables to pass to the mcp server when the transport is "stdio".
 	// Both the key and value must be of type string.
 	Env map[string]string `json:"env,omitempty"`
 
 	// SessionMode controls how mcpjungle manages connections to this MCP server.
 	SessionMode string `json:"session_mode,omitempty"`
+
+	// LazyStart defers starting a stdio server until the first tool call or health check targeting it.
+	// Its process is then kept running between calls, like in stateful mode, and stopped once it has been idle
+	// for the session idle timeout. Only the stdio transport supports it.
+	LazyStart bool `json:"lazy_start,omitempty"`
+
+	// HTTPPool tunes the pool of connections to the server. Only the streamable_http transport supports it.
+	HTTPPool *HTTPPoolConfig `json:"http_pool,omitempty"`
+
+	// KeepAlive pings the session of the server while it is idle, to keep it open. It is disabled by default.
+	// Only the streamable_http transport in stateful session mode supports it.
+	KeepAlive *KeepAliveConfig `json:"keep_alive,omitempty"`
+
+	// Container describes the container the server runs in, if any.
+	// mcpjungle launches the stdio servers that have one in a container, see ContainerConfig.
+	Container *ContainerConfig `json:"container,omitempty"`
+
+	// OpenAPI is the REST service served by the server. It is mandatory when the transport is "openapi",
+	// and is usually generated from the service's OpenAPI spec by `mcpjungle register openapi`.
+	OpenAPI *OpenAPIConfig `json:"openapi,omitempty"`
+
+	// WebhookTool is the HTTP endpoint served as the tool of the server. It is mandatory when the transport is
+	// "webhook_tool".
+	WebhookTool *WebhookToolConfig `json:"webhook_tool,omitempty"`
+}
+
+// BulkRegistrationMode selects how a batch of MCP servers is registered.
+type BulkRegistrationMode string
+
+const (
+	// BulkRegistrationAtomic registers either all the servers of the batch or none of them.
+	BulkRegistrationAtomic BulkRegistrationMode = "atomic"
+	// BulkRegistrationBestEffort registers every server it can, independently of the others.
+	BulkRegistrationBestEffort BulkRegistrationMode = "best_effort"
+)
+
+// BulkItemStatus is the outcome of the registration of one server of a batch.
+type BulkItemStatus string
+
+const (
+	BulkItemRegistered BulkItemStatus = "registered"
+	BulkItemFailed     BulkItemStatus = "failed"
+	// BulkItemSkipped is the status of the valid servers of an atomic batch that failed as a whole
+	BulkItemSkipped BulkItemStatus = "skipped"
+)
+
+// BulkItemResult reports the outcome of the registration of one server of a batch.
+type BulkItemResult struct {
+	// Index is the position of the server in the batch
+	Index  int            `json:"index"`
+	Name   string         `json:"name"`
+	Status BulkItemStatus `json:"status"`
+	Error  string         `json:"error,omitempty"`
+	// Errors lists the invalid fields of the server's configuration, if it failed validation
+	Errors ValidationErrors `json:"errors,omitempty"`
+	// Server is the registered server, if its status is BulkItemRegistered
+	Server *McpServer `json:"server,omitempty"`
+}
+
+// BulkRegistrationResult is the response of the bulk registration API.
+type BulkRegistrationResult struct {
+	Mode       BulkRegistrationMode `json:"mode"`
+	Registered int                  `json:"registered"`
+	Failed     int                  `json:"failed"`
+	// Results holds the outcome of every server of the batch, in the order they were submitted
+	Results []BulkItemResult `json:"results"`
 }
 
 // ServerMetadata represents the server metadata response
@@ -85,6 +449,55 @@ type ServerMetadata struct {
 	Version string `json:"version"`
 }
 
+// ServerVersion represents the detailed version information reported by the server
+type ServerVersion struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+	// MinClientVersion is the oldest CLI version supported by the server
+	MinClientVersion string `json:"min_client_version,omitempty"`
+	// Features lists the optional API features the server supports, eg- FeatureBulkRegistration
+	Features []string `json:"features,omitempty"`
+}
+
+// FeatureBulkRegistration is advertised by servers that can register a batch of MCP servers in a single request.
+const FeatureBulkRegistration = "bulk_registration"
+
+// Supports reports whether the server advertises the given feature.
+// Servers older than the features list don't advertise any.
+func (v *ServerVersion) Supports(feature string) bool {
+	for _, f := range v.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// DatabaseUnavailable is the Database of a ServerReadiness whose database can't be queried.
+// The endpoint is public, so the error itself is only logged by the server.
+const DatabaseUnavailable = "database unavailable"
+
+// ServerReadiness represents the response of the server's readiness endpoint
+type ServerReadiness struct {
+	// Ready is false if the server cannot serve requests because one of its dependencies is unavailable,
+	// or because it is shutting down
+	Ready bool `json:"ready"`
+	// ShuttingDown is true once the server started to shut down, it then only completes the requests in flight
+	ShuttingDown bool `json:"shutting_down,omitempty"`
+	// Database is "ok" if the database is reachable, DatabaseUnavailable otherwise
+	Database string `json:"database"`
+	// Initialized is false until the server has been initialized with `mcpjungle init-server`
+	Initialized bool `json:"initialized"`
+	// Mode is the mode the server runs in, it is empty if the server is not initialized
+	Mode string `json:"mode,omitempty"`
+	// WarmingUp lists the MCP servers that weren't synchronized yet since the server started.
+	// The server is ready meanwhile, only the calls to their tools fail.
+	WarmingUp []string `json:"warming_up,omitempty"`
+	// Time is the current time on the server, clients use it to detect clock skew
+	Time time.Time `json:"time"`
+}
+
 // EnableDisableServerResult represents the result of enabling or disabling an MCP server
 type EnableDisableServerResult struct {
 	// Name is the name of the server that was enabled/disabled
@@ -95,11 +508,47 @@ type EnableDisableServerResult struct {
 	PromptsAffected []string `json:"prompts_affected"`
 }
 
+// Only allow letters, numbers, hyphens, and underscores
+var validServerName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ValidateServerName checks if the server name is valid.
+// Server name must not contain double underscores `__`.
+// Tools in mcpjungle are identified by `<server_name>__<tool_name>` (eg- `github__git_commit`)
+// When a tool is invoked, the text before the first __ is treated as the server name.
+// eg- In `aws__ec2__create_sg`, `aws` is the MCP server's name and `ec2__create_sg` is the tool.
+func ValidateServerName(name string) error {
+	if problem := serverNameProblem(name); problem != "" {
+		return fmt.Errorf("invalid server name: '%s' %s", name, problem)
+	}
+	return nil
+}
+
+// serverNameProblem returns what is wrong with a server name, or "" if it is valid.
+func serverNameProblem(name string) string {
+	if name == "" {
+		return "must not be empty"
+	}
+	if !validServerName.MatchString(name) {
+		return fmt.Sprintf("must follow the regular expression %s", validServerName)
+	}
+	if strings.Contains(name, "__") {
+		return "must not contain multiple consecutive underscores"
+	}
+	if strings.HasSuffix(name, "_") {
+		// Don't allow a trailing underscore in server name.
+		// This avoids situations like this: `aws_` + `ec2_create_sg` -> `aws___ec2_create_sg`
+		//  splitting this would result in: `aws` + `_ec2_create_sg` because we always split on
+		//  the first occurrence of `__`
+		return "must not end with an underscore"
+	}
+	return ""
+}
+
 // ValidateTransport validates the input string and returns the corresponding model.McpServerTransport.
 // It returns an error if the input is invalid or empty.
 func ValidateTransport(input string) (McpServerTransport, error) {
-	errMsgExt := fmt.Sprintf(
-		"(acceptable values: '%s', '%s', '%s')", TransportStreamableHTTP, TransportStdio, TransportSSE,
+	errMsgExt := acceptableValues(
+		TransportStreamableHTTP, TransportStdio, TransportSSE, TransportOpenAPI, TransportWebhookTool,
 	)
 
 	switch input {
@@ -109,6 +558,10 @@ func ValidateTransport(input string) (McpServerTransport, error) {
 		return TransportStdio, nil
 	case string(TransportSSE):
 		return TransportSSE, nil
+	case string(TransportOpenAPI):
+		return TransportOpenAPI, nil
+	case string(TransportWebhookTool):
+		return TransportWebhookTool, nil
 	case "":
 		return "", fmt.Errorf("transport is required %s", errMsgExt)
 	default:
@@ -131,3 +584,355 @@ func ValidateSessionMode(input string) (SessionMode, error) {
 		)
 	}
 }
+
+// Validate checks the registration of an MCP server and returns ValidationErrors listing every invalid field.
+// The server applies the same checks to every registration it receives, so they can be run locally beforehand.
+func (i *RegisterServerInput) Validate() error {
+	var errs ValidationErrors
+	if problem := serverNameProblem(i.Name); problem != "" {
+		errs.Add("name", "%s", problem)
+	}
+	if _, err := ValidateSessionMode(i.SessionMode); err != nil {
+		errs.Add("session_mode", "has an unsupported value %q %s", i.SessionMode, acceptableValues(SessionModeStateless, SessionModeStateful))
+	}
+
+	transport, err := ValidateTransport(i.Transport)
+	acceptable := acceptableValues(
+		TransportStreamableHTTP, TransportStdio, TransportSSE, TransportOpenAPI, TransportWebhookTool,
+	)
+	switch {
+	case i.Transport == "":
+		errs.Add("transport", "is required %s", acceptable)
+	case err != nil:
+		errs.Add("transport", "has an unsupported transport type %q %s", i.Transport, acceptable)
+	case transport == TransportStdio:
+		if i.HTTPPool != nil {
+			errs.Add("http_pool", "is only supported for streamable HTTP transport")
+		}
+		if i.KeepAlive != nil {
+			errs.Add("keep_alive", "is only supported for streamable HTTP transport")
+		}
+		if c := i.Container; c != nil {
+			if len(c.Env) > 0 {
+				errs.Add("container.env", "is not supported for stdio transport, use env instead")
+			}
+			if c.Port != 0 || len(c.Ports) > 0 {
+				errs.Add("container.ports", "is not supported for stdio transport, the server is reached on its stdio")
+			}
+			if i.Package != nil {
+				errs.Add("package", "is not supported for a server launched in a container, set command instead")
+			}
+		} else if i.Command == "" && i.Package == nil {
+			errs.Add("command", "is required for stdio transport, unless the server is launched from a package")
+		}
+		if i.Package != nil {
+			if i.Command != "" {
+				errs.Add("command", "must be empty for a server launched from a package, it is derived from the package")
+			}
+			i.validatePackage(&errs)
+		}
+		if i.OpenAPI != nil {
+			errs.Add("openapi", "is only supported for openapi transport")
+		}
+		if i.WebhookTool != nil {
+			errs.Add("webhook_tool", "is only supported for webhook_tool transport")
+		}
+		for _, k := range slices.Sorted(maps.Keys(i.Env)) {
+			if k == "" {
+				errs.Add("env", "must not contain a variable without a name")
+			} else if strings.ContainsAny(k, "= ") {
+				errs.Add("env."+k, "is not a valid environment variable name")
+			}
+		}
+	case transport == TransportOpenAPI:
+		i.validateOpenAPI(&errs)
+	case transport == TransportWebhookTool:
+		i.validateWebhookTool(&errs)
+	default:
+		kind := "streamable HTTP"
+		if transport == TransportSSE {
+			kind = "SSE"
+		}
+		if i.LazyStart {
+			errs.Add("lazy_start", "is only supported for stdio transport")
+		}
+		if i.HTTPPool != nil && transport == TransportSSE {
+			errs.Add("http_pool", "is only supported for streamable HTTP transport")
+		}
+		if i.KeepAlive != nil && transport == TransportSSE {
+			errs.Add("keep_alive", "is only supported for streamable HTTP transport")
+		} else if i.KeepAlive != nil && i.SessionMode != string(SessionModeStateful) {
+			errs.Add("keep_alive", "is only supported in stateful session mode")
+		}
+		if i.URL == "" {
+			errs.Add("url", "is required for %s transport", kind)
+		} else if !isHTTPURL(i.URL) {
+			errs.Add("url", "must be an http or https URL, eg- https://example.com/mcp")
+		}
+		if i.OpenAPI != nil {
+			errs.Add("openapi", "is only supported for openapi transport")
+		}
+		if i.WebhookTool != nil {
+			errs.Add("webhook_tool", "is only supported for webhook_tool transport")
+		}
+		if i.Package != nil {
+			errs.Add("package", "is only supported for stdio transport")
+		}
+		if c := i.Container; c != nil && (len(c.Volumes) > 0 || c.Network != "" || c.MemoryMB != 0 || c.CPUs != 0) {
+			errs.Add("container", "volumes, network, memory_mb and cpus are only supported for stdio transport")
+		}
+	}
+	if p := i.HTTPPool; p != nil {
+		if p.MaxIdleConnsPerHost < 0 {
+			errs.Add("http_pool.max_idle_conns_per_host", "must not be negative")
+		}
+		if p.IdleConnTimeoutSec < 0 {
+			errs.Add("http_pool.idle_conn_timeout_sec", "must not be negative")
+		}
+	}
+	if k := i.KeepAlive; k != nil {
+		if k.IntervalSec <= 0 {
+			errs.Add("keep_alive.interval_sec", "must be a positive number of seconds")
+		}
+		if k.MaxIdleSec < 0 {
+			errs.Add("keep_alive.max_idle_sec", "must not be negative")
+		}
+	}
+	fields := i.credentialFields()
+	for _, field := range slices.Sorted(maps.Keys(fields)) {
+		if !IsVaultReference(fields[field]) {
+			continue
+		}
+		if _, err := ParseVaultReference(fields[field]); err != nil {
+			errs.Add(field, "is not a valid Vault reference, expected vault:<mount>/<path>#<key>")
+		}
+	}
+	if c := i.Container; c != nil {
+		if strings.TrimSpace(c.Image) == "" {
+			errs.Add("container.image", "is required")
+		}
+		if c.Port < 0 || c.Port > 65535 {
+			errs.Add("container.port", "must be a port number between 1 and 65535")
+		}
+		for _, port := range c.Ports {
+			if strings.TrimSpace(port) == "" {
+				errs.Add("container.ports", "must not contain an empty port")
+				break
+			}
+		}
+		for _, v := range c.Volumes {
+			if src, dst, _ := strings.Cut(v, ":"); src == "" || !strings.HasPrefix(dst, "/") {
+				errs.Add("container.volumes", "must be written <source>:<path in the container>[:ro], got %q", v)
+				break
+			}
+		}
+		if c.MemoryMB < 0 {
+			errs.Add("container.memory_mb", "must not be negative")
+		}
+		if c.CPUs < 0 {
+			errs.Add("container.cpus", "must not be negative")
+		}
+	}
+	return errs.Err()
+}
+
+// validatePackage checks the package a stdio server is launched from.
+func (i *RegisterServerInput) validatePackage(errs *ValidationErrors) {
+	p := i.Package
+	if p.Runtime != PackageRuntimeNpx && p.Runtime != PackageRuntimeUvx {
+		errs.Add(
+			"package.runtime", "has an unsupported value %q %s",
+			p.Runtime, acceptableValues(PackageRuntimeNpx, PackageRuntimeUvx),
+		)
+	}
+	if problem := packageNameProblem(p.Name); problem != "" {
+		errs.Add("package.name", "%s", problem)
+	}
+	if strings.HasPrefix(p.Version, "-") || strings.ContainsAny(p.Version, " \t") {
+		errs.Add("package.version", "is not a valid version")
+	}
+}
+
+// ValidatePackageName checks the name of the npm or PyPI package a stdio server is launched from.
+func ValidatePackageName(name string) error {
+	if problem := packageNameProblem(name); problem != "" {
+		return fmt.Errorf("invalid package name: '%s' %s", name, problem)
+	}
+	return nil
+}
+
+// packageNameProblem returns what is wrong with a package name, or "" if it is valid.
+func packageNameProblem(name string) string {
+	switch {
+	case name == "":
+		return "is required"
+	case strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t"):
+		return "is not a valid package name"
+	case strings.LastIndex(name, "@") > 0 || strings.ContainsAny(name, "=<>!~"):
+		// the names of scoped npm packages start with @, eg- @scope/name
+		return "must not contain a version, set package.version instead"
+	}
+	return ""
+}
+
+// validOpenAPIToolName restricts the tools of an openapi server to the names accepted by MCP clients.
+var validOpenAPIToolName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+var openAPIMethods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+// validateOpenAPI checks the REST service of an openapi server.
+func (i *RegisterServerInput) validateOpenAPI(errs *ValidationErrors) {
+	if i.Command != "" || len(i.Args) > 0 || len(i.Env) > 0 || i.Package != nil {
+		errs.Add("command", "is not supported for openapi transport, the REST service is described by openapi")
+	}
+	if i.URL != "" || i.BearerToken != "" {
+		errs.Add("url", "is not supported for openapi transport, use openapi.base_url and openapi.auth instead")
+	}
+	if i.LazyStart {
+		errs.Add("lazy_start", "is only supported for stdio transport")
+	}
+	if i.HTTPPool != nil || i.KeepAlive != nil {
+		errs.Add("http_pool", "is only supported for streamable HTTP transport")
+	}
+	if i.Container != nil {
+		errs.Add("container", "is not supported for openapi transport")
+	}
+	if i.WebhookTool != nil {
+		errs.Add("webhook_tool", "is only supported for webhook_tool transport")
+	}
+	c := i.OpenAPI
+	if c == nil {
+		errs.Add("openapi", "is required for openapi transport")
+		return
+	}
+	if c.BaseURL == "" {
+		errs.Add("openapi.base_url", "is required")
+	} else if !isHTTPURL(c.BaseURL) {
+		errs.Add("openapi.base_url", "must be an http or https URL, eg- https://api.example.com/v1")
+	}
+	validateOpenAPIAuth(c.Auth, "openapi.auth", errs)
+	if len(c.Operations) == 0 {
+		errs.Add("openapi.operations", "must contain at least one operation")
+	}
+	tools := make(map[string]bool, len(c.Operations))
+	for n, op := range c.Operations {
+		field := fmt.Sprintf("openapi.operations[%d]", n)
+		switch {
+		case !validOpenAPIToolName.MatchString(op.Tool):
+			errs.Add(field+".tool", "must follow the regular expression %s", validOpenAPIToolName)
+		case tools[op.Tool]:
+			errs.Add(field+".tool", "must be unique, %q is already used by another operation", op.Tool)
+		}
+		tools[op.Tool] = true
+		if !slices.Contains(openAPIMethods, op.Method) {
+			errs.Add(field+".method", "must be an upper case HTTP method, eg- GET")
+		}
+		if !strings.HasPrefix(op.Path, "/") {
+			errs.Add(field+".path", "must start with '/'")
+		}
+		for _, p := range op.Parameters {
+			if p.Name == "" {
+				errs.Add(field+".parameters", "must not contain a parameter without a name")
+			} else if p.In != "path" && p.In != "query" && p.In != "header" {
+				errs.Add(
+					field+".parameters."+p.Name+".in",
+					"has an unsupported value %q (acceptable values: 'path', 'query', 'header')", p.In,
+				)
+			}
+		}
+		if len(op.InputSchema) > 0 && !json.Valid(op.InputSchema) {
+			errs.Add(field+".input_schema", "must be a JSON schema")
+		}
+	}
+}
+
+// validateOpenAPIAuth checks the credential of a REST service or of the endpoint of a webhook tool,
+// field is the name of the field holding it.
+func validateOpenAPIAuth(a *OpenAPIAuth, field string, errs *ValidationErrors) {
+	if a == nil {
+		return
+	}
+	switch a.Type {
+	case OpenAPIAuthBearer:
+	case OpenAPIAuthAPIKey:
+		if strings.TrimSpace(a.Header) == "" {
+			errs.Add(field+".header", "is required for api_key authentication")
+		}
+	default:
+		errs.Add(
+			field+".type", "has an unsupported value %q %s",
+			a.Type, acceptableValues(OpenAPIAuthBearer, OpenAPIAuthAPIKey),
+		)
+	}
+	if a.Value == "" {
+		errs.Add(field+".value", "is required")
+	}
+}
+
+// validateWebhookTool checks the endpoint of a webhook_tool server.
+func (i *RegisterServerInput) validateWebhookTool(errs *ValidationErrors) {
+	if i.Command != "" || len(i.Args) > 0 || len(i.Env) > 0 || i.Package != nil {
+		errs.Add("command", "is not supported for webhook_tool transport, the endpoint is described by webhook_tool")
+	}
+	if i.URL != "" || i.BearerToken != "" {
+		errs.Add("url", "is not supported for webhook_tool transport, use webhook_tool.url and webhook_tool.auth")
+	}
+	if i.LazyStart {
+		errs.Add("lazy_start", "is only supported for stdio transport")
+	}
+	if i.HTTPPool != nil || i.KeepAlive != nil {
+		errs.Add("http_pool", "is only supported for streamable HTTP transport")
+	}
+	if i.Container != nil {
+		errs.Add("container", "is not supported for webhook_tool transport")
+	}
+	if i.OpenAPI != nil {
+		errs.Add("openapi", "is only supported for openapi transport")
+	}
+	// the tool is named after the server, MCP clients reject longer tool names
+	if len(i.Name) > 64 {
+		errs.Add("name", "must not be longer than 64 characters, the tool of the webhook is named after it")
+	}
+	c := i.WebhookTool
+	if c == nil {
+		errs.Add("webhook_tool", "is required for webhook_tool transport")
+		return
+	}
+	if c.URL == "" {
+		errs.Add("webhook_tool.url", "is required")
+	} else if !isHTTPURL(c.URL) {
+		errs.Add("webhook_tool.url", "must be an http or https URL, eg- https://automation.example.com/hooks/deploy")
+	}
+	if !slices.Contains(openAPIMethods, c.Method) {
+		errs.Add("webhook_tool.method", "must be an upper case HTTP method, eg- POST")
+	}
+	for _, k := range slices.Sorted(maps.Keys(c.Headers)) {
+		if strings.TrimSpace(k) == "" || strings.ContainsAny(k, ": \t") {
+			errs.Add("webhook_tool.headers", "must not contain the invalid header name %q", k)
+		}
+	}
+	validateOpenAPIAuth(c.Auth, "webhook_tool.auth", errs)
+	if len(c.InputSchema) > 0 {
+		var schema map[string]any
+		if err := json.Unmarshal(c.InputSchema, &schema); err != nil {
+			errs.Add("webhook_tool.input_schema", "must be a JSON schema object")
+		} else if t, ok := schema["type"]; ok && t != "object" {
+			errs.Add("webhook_tool.input_schema", "must describe an object, the arguments of the tool")
+		}
+	}
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL.
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// acceptableValues describes the values a field accepts, for use in validation messages.
+func acceptableValues[T ~string](values ...T) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("'%s'", v)
+	}
+	return "(acceptable values: " + strings.Join(quoted, ", ") + ")"
+}
@@ -80,6 +80,22 @@ func TestValidateTransport(t *testing.T) {
 		t.Errorf("Expected transport to be TransportSSE, got %s", transport)
 	}
 
+	transport, err = ValidateTransport("openapi")
+	if err != nil {
+		t.Errorf("Expected no error for 'openapi', got %v", err)
+	}
+	if transport != TransportOpenAPI {
+		t.Errorf("Expected transport to be TransportOpenAPI, got %s", transport)
+	}
+
+	transport, err = ValidateTransport("webhook_tool")
+	if err != nil {
+		t.Errorf("Expected no error for 'webhook_tool', got %v", err)
+	}
+	if transport != TransportWebhookTool {
+		t.Errorf("Expected transport to be TransportWebhookTool, got %s", transport)
+	}
+
 	// Test empty string
 	transport, err = ValidateTransport("")
 	if err == nil {
@@ -197,3 +213,23 @@ func TestValidateSessionMode(t *testing.T) {
 		})
 	}
 }
+
+func TestPackageCommandLine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pkg  PackageConfig
+		want string
+	}{
+		{PackageConfig{Runtime: PackageRuntimeNpx, Name: "@modelcontextprotocol/server-filesystem"}, "npx -y @modelcontextprotocol/server-filesystem@latest /tmp"},
+		{PackageConfig{Runtime: PackageRuntimeNpx, Name: "@playwright/mcp", Version: "0.0.41"}, "npx -y @playwright/mcp@0.0.41 /tmp"},
+		{PackageConfig{Runtime: PackageRuntimeUvx, Name: "mcp-server-time", Version: "latest"}, "uvx mcp-server-time@latest /tmp"},
+		{PackageConfig{Runtime: PackageRuntimeUvx, Name: "mcp-server-time", Version: "2025.9.25"}, "uvx mcp-server-time==2025.9.25 /tmp"},
+	}
+	for _, tt := range tests {
+		command, args := tt.pkg.CommandLine([]string{"/tmp"})
+		if got := strings.Join(append([]string{command}, args...), " "); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
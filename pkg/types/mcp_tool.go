@@ -9,6 +9,8 @@ type ToolInputSchema struct {
 
 // Tool represents a tool provided by an MCP Server registered in the registry.
 type Tool struct {
+	// UUID is the immutable identifier of the tool, it is accepted in place of its name to address it.
+	UUID        string          `json:"uuid,omitempty"`
 	Name        string          `json:"name"`
 	Enabled     bool            `json:"enabled"`
 	Description string          `json:"description"`
@@ -0,0 +1,24 @@
+package types
+
+// Query parameters of the paginated list endpoints.
+const (
+	// PageLimitParam is the maximum number of items to return.
+	// An admin can pass 0 to list all items in a single response.
+	PageLimitParam = "limit"
+	// PageAfterParam is the cursor returned as NextCursor by the previous page.
+	PageAfterParam = "after"
+)
+
+// Page is the response of the paginated list endpoints.
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// NextCursor is passed as the after parameter to get the next page.
+	// It is empty when this is the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Total is the number of items across all pages, for endpoints that count them.
+	Total *int64 `json:"total,omitempty"`
+}
+
+// NDJSONContentType is the content type of the streamed list endpoints, eg- GET /tools/stream, which write
+// one JSON item per line. If the stream fails after it started, its last line is an ErrorResponse instead of an item.
+const NDJSONContentType = "application/x-ndjson"
@@ -0,0 +1,12 @@
+package types
+
+// HTTP headers that report the rate limit budget of the caller on API responses,
+// when the server is configured with a rate limit.
+const (
+	// RateLimitLimitHeader holds the number of requests the caller can make per window.
+	RateLimitLimitHeader = "X-RateLimit-Limit"
+	// RateLimitRemainingHeader holds the number of requests the caller can still make in the current window.
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	// RateLimitResetHeader holds the time the current window ends at, in seconds since the Unix epoch.
+	RateLimitResetHeader = "X-RateLimit-Reset"
+)
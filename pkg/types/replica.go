@@ -0,0 +1,35 @@
+package types
+
+import "time"
+
+// ReplicaRole is the role of a replica of the registry among the replicas sharing its database.
+type ReplicaRole string
+
+const (
+	// ReplicaLeader is the role of the replica that runs the background jobs, eg- the health checks.
+	ReplicaLeader ReplicaRole = "leader"
+	// ReplicaFollower is the role of the other replicas, they only serve requests.
+	ReplicaFollower ReplicaRole = "follower"
+)
+
+// ReplicaInfo describes a replica of the registry.
+// A registry running as a single instance is always the leader.
+type ReplicaInfo struct {
+	ID   string      `json:"id"`
+	Role ReplicaRole `json:"role"`
+	// Leader is the ID of the leader as of the last election, empty if no replica holds the lease.
+	Leader string `json:"leader,omitempty"`
+	// LeaderSince is when the leader took the lease.
+	LeaderSince *time.Time `json:"leader_since,omitempty"`
+	// LeaseExpiresAt is when the lease of the leader expires unless it is renewed.
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
+}
+
+// ServerInfo describes the running server that answered the request.
+type ServerInfo struct {
+	Version string `json:"version"`
+	Mode    string `json:"mode"`
+	// StartedAt is when the server process started.
+	StartedAt time.Time   `json:"started_at"`
+	Replica   ReplicaInfo `json:"replica"`
+}
@@ -0,0 +1,121 @@
+package types
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// VaultReferencePrefix starts the value of a credential field that references a secret stored in HashiCorp Vault
+// instead of holding the secret itself.
+const VaultReferencePrefix = "vault:"
+
+// VaultReference references a key of a secret stored in HashiCorp Vault. It is written vault:<mount>/<path>#<key>,
+// eg- vault:secret/mcp/github#token for the key token of the secret mcp/github of the secrets engine mounted at secret.
+// mcpjungle resolves it when it connects to the MCP server, so that the secret is never stored in its database.
+type VaultReference struct {
+	Mount string
+	Path  string
+	Key   string
+}
+
+// String returns the reference as it is written in the credential fields.
+func (r VaultReference) String() string {
+	return VaultReferencePrefix + r.Mount + "/" + r.Path + "#" + r.Key
+}
+
+// IsVaultReference reports whether the value of a credential field references a secret stored in Vault.
+func IsVaultReference(v string) bool {
+	return strings.HasPrefix(v, VaultReferencePrefix)
+}
+
+// ParseVaultReference parses a reference written vault:<mount>/<path>#<key>.
+func ParseVaultReference(v string) (VaultReference, error) {
+	rest, ok := strings.CutPrefix(v, VaultReferencePrefix)
+	if !ok {
+		return VaultReference{}, fmt.Errorf("'%s' is not a Vault reference, it must start with %s", v, VaultReferencePrefix)
+	}
+	secretPath, key, _ := strings.Cut(rest, "#")
+	mount, path, _ := strings.Cut(secretPath, "/")
+	path = strings.Trim(path, "/")
+	if mount == "" || path == "" || key == "" {
+		return VaultReference{}, fmt.Errorf("invalid Vault reference '%s', expected vault:<mount>/<path>#<key>", v)
+	}
+	return VaultReference{Mount: mount, Path: path, Key: key}, nil
+}
+
+// CredentialSource tells where the credentials of an MCP server come from.
+type CredentialSource string
+
+const (
+	// CredentialSourceNone is the source of the servers without credentials.
+	CredentialSourceNone CredentialSource = "none"
+	// CredentialSourceStatic means the credentials are stored in the mcpjungle database.
+	CredentialSourceStatic CredentialSource = "static"
+	// CredentialSourceVault means at least one of the credentials references a secret stored in Vault.
+	CredentialSourceVault CredentialSource = "vault"
+)
+
+// CredentialSourceOf returns where the given values of credential fields come from, empty values are ignored.
+func CredentialSourceOf(values ...string) CredentialSource {
+	source := CredentialSourceNone
+	for _, v := range values {
+		switch {
+		case IsVaultReference(v):
+			return CredentialSourceVault
+		case v != "":
+			source = CredentialSourceStatic
+		}
+	}
+	return source
+}
+
+// credentialFields returns the values of the credential fields of the server, by the name of the field:
+// its bearer token, the values of its environment variables and the credential of its REST service or webhook.
+func (i *RegisterServerInput) credentialFields() map[string]string {
+	fields := make(map[string]string)
+	if i.BearerToken != "" {
+		fields["bearer_token"] = i.BearerToken
+	}
+	for k, v := range i.Env {
+		fields["env."+k] = v
+	}
+	if i.OpenAPI != nil && i.OpenAPI.Auth != nil {
+		fields["openapi.auth.value"] = i.OpenAPI.Auth.Value
+	}
+	if i.WebhookTool != nil && i.WebhookTool.Auth != nil {
+		fields["webhook_tool.auth.value"] = i.WebhookTool.Auth.Value
+	}
+	return fields
+}
+
+// MapSecrets replaces the value of every secret field of the server that is set with the result of f, which is called
+// with the name of the field, eg- env.GITHUB_TOKEN, and its value. The secrets are the credential fields, plus the
+// values of the headers of a webhook tool and of the env vars of a container, which often carry credentials too.
+// This is the list of the fields the CLI redacts in exports and restores on import, so that they stay in sync.
+func (i *RegisterServerInput) MapSecrets(f func(field, value string) string) {
+	mapValues := func(prefix string, m map[string]string) {
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			if m[k] != "" {
+				m[k] = f(prefix+k, m[k])
+			}
+		}
+	}
+	if i.BearerToken != "" {
+		i.BearerToken = f("bearer_token", i.BearerToken)
+	}
+	mapValues("env.", i.Env)
+	if i.OpenAPI != nil && i.OpenAPI.Auth != nil && i.OpenAPI.Auth.Value != "" {
+		i.OpenAPI.Auth.Value = f("openapi.auth.value", i.OpenAPI.Auth.Value)
+	}
+	if w := i.WebhookTool; w != nil {
+		if w.Auth != nil && w.Auth.Value != "" {
+			w.Auth.Value = f("webhook_tool.auth.value", w.Auth.Value)
+		}
+		mapValues("webhook_tool.headers.", w.Headers)
+	}
+	if i.Container != nil {
+		mapValues("container.env.", i.Container.Env)
+	}
+}
@@ -0,0 +1,111 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVaultReference(t *testing.T) {
+	t.Parallel()
+
+	ref, err := ParseVaultReference("vault:secret/mcp/github#token")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := VaultReference{Mount: "secret", Path: "mcp/github", Key: "token"}
+	if ref != want {
+		t.Errorf("Expected %+v, got %+v", want, ref)
+	}
+	if ref.String() != "vault:secret/mcp/github#token" {
+		t.Errorf("Expected the reference to be written back as it was parsed, got %s", ref.String())
+	}
+
+	for _, v := range []string{"secret/mcp/github#token", "vault:secret#token", "vault:secret/mcp/github", "vault:/mcp#token"} {
+		if _, err := ParseVaultReference(v); err == nil {
+			t.Errorf("Expected an error for %q", v)
+		}
+	}
+}
+
+func TestCredentialSourceOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		values []string
+		want   CredentialSource
+	}{
+		{nil, CredentialSourceNone},
+		{[]string{""}, CredentialSourceNone},
+		{[]string{"ghp_token"}, CredentialSourceStatic},
+		{[]string{"debug", "vault:secret/mcp/github#token"}, CredentialSourceVault},
+	}
+	for _, tt := range tests {
+		if got := CredentialSourceOf(tt.values...); got != tt.want {
+			t.Errorf("CredentialSourceOf(%v) = %s, want %s", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestValidateVaultReferences(t *testing.T) {
+	t.Parallel()
+
+	input := &RegisterServerInput{
+		Name:      "github",
+		Transport: string(TransportStdio),
+		Command:   "github-mcp-server",
+		Env:       map[string]string{"GITHUB_TOKEN": "vault:secret/mcp/github", "LOG_LEVEL": "debug"},
+	}
+	if fields := fieldsOf(t, input.Validate()); !reflect.DeepEqual(fields, []string{"env.GITHUB_TOKEN"}) {
+		t.Errorf("Expected the invalid reference to be reported, got %v", fields)
+	}
+
+	input.Env["GITHUB_TOKEN"] = "vault:secret/mcp/github#token"
+	if err := input.Validate(); err != nil {
+		t.Errorf("Expected a valid reference to be accepted, got %v", err)
+	}
+
+	input = &RegisterServerInput{
+		Name: "linear", Transport: string(TransportStreamableHTTP), URL: "https://mcp.linear.app/mcp", BearerToken: "vault:",
+	}
+	if fields := fieldsOf(t, input.Validate()); !reflect.DeepEqual(fields, []string{"bearer_token"}) {
+		t.Errorf("Expected the invalid reference to be reported, got %v", fields)
+	}
+}
+
+func TestMapSecrets(t *testing.T) {
+	t.Parallel()
+
+	input := &RegisterServerInput{
+		Name:        "crm",
+		Transport:   string(TransportWebhookTool),
+		BearerToken: "bearer",
+		Env:         map[string]string{"API_KEY": "key", "EMPTY": ""},
+		WebhookTool: &WebhookToolConfig{
+			URL:     "https://crm.example.com/hook",
+			Headers: map[string]string{"X-Api-Key": "header"},
+			Auth:    &OpenAPIAuth{Type: OpenAPIAuthBearer, Value: "webhook"},
+		},
+		Container: &ContainerConfig{Image: "crm", Env: map[string]string{"TOKEN": "container"}},
+	}
+	var fields []string
+	input.MapSecrets(func(field, value string) string {
+		fields = append(fields, field+"="+value)
+		return "redacted"
+	})
+	want := []string{
+		"bearer_token=bearer",
+		"env.API_KEY=key",
+		"webhook_tool.auth.value=webhook",
+		"webhook_tool.headers.X-Api-Key=header",
+		"container.env.TOKEN=container",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("Expected the secret fields %v, got %v", want, fields)
+	}
+	if input.BearerToken != "redacted" || input.WebhookTool.Headers["X-Api-Key"] != "redacted" {
+		t.Errorf("Expected the secrets to be replaced, got %+v", input)
+	}
+	if input.Env["EMPTY"] != "" {
+		t.Errorf("Expected the empty values to be left as they are, got %q", input.Env["EMPTY"])
+	}
+}
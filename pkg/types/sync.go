@@ -0,0 +1,46 @@
+package types
+
+// ServerSyncStatus is the outcome of the synchronization of the tools and prompts of an MCP server.
+type ServerSyncStatus string
+
+const (
+	ServerSynced     ServerSyncStatus = "synced"
+	ServerSyncFailed ServerSyncStatus = "failed"
+	// ServerSyncSkipped is the status of the lazily started servers that were not running during the startup sync.
+	ServerSyncSkipped ServerSyncStatus = "skipped"
+)
+
+// SyncServersInput is the body of a request to synchronize MCP servers.
+type SyncServersInput struct {
+	// Servers are the names of the servers to synchronize, all registered servers if empty.
+	Servers []string `json:"servers,omitempty"`
+}
+
+// ServerSyncResult is the outcome of the synchronization of an MCP server.
+// Synchronizing a server fetches its tools and prompts again and updates the registry to match them.
+type ServerSyncResult struct {
+	Name   string           `json:"name"`
+	Status ServerSyncStatus `json:"status"`
+	// ToolsAdded, ToolsUpdated and ToolsRemoved are the canonical names of the tools the server gained,
+	// whose definition changed and that it lost.
+	ToolsAdded   []string `json:"tools_added,omitempty"`
+	ToolsUpdated []string `json:"tools_updated,omitempty"`
+	ToolsRemoved []string `json:"tools_removed,omitempty"`
+	// PromptsAdded, PromptsUpdated and PromptsRemoved are the same for the prompts of the server.
+	PromptsAdded   []string `json:"prompts_added,omitempty"`
+	PromptsUpdated []string `json:"prompts_updated,omitempty"`
+	PromptsRemoved []string `json:"prompts_removed,omitempty"`
+	// DurationMs is how long the synchronization of the server took, in milliseconds.
+	DurationMs int64 `json:"duration_ms"`
+	// Error is why the synchronization failed. The tools and prompts of the server are left unchanged then.
+	Error string `json:"error,omitempty"`
+}
+
+// SyncServersResult is the outcome of the synchronization of a set of MCP servers, sorted by name.
+type SyncServersResult struct {
+	Synced int `json:"synced"`
+	Failed int `json:"failed"`
+	// DurationMs is how long the synchronization of all the servers took, in milliseconds.
+	DurationMs int64              `json:"duration_ms"`
+	Servers    []ServerSyncResult `json:"servers"`
+}
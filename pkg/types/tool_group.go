@@ -1,10 +1,25 @@
 package types
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ValidGroupName is a regex that matches valid tool group names.
+// A valid tool group name must start with an alphanumeric character and can contain
+// alphanumeric characters, underscores, and hyphens.
+// This ensures that the group name can be safely used in URLs.
+var ValidGroupName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
 // ToolGroup represents a group (collection) of MCP Tools.
 // A group can contain a subset of all available tools in the MCPJungle system.
 // This allows you to expose a limited set of tools to certain mcp clients.
 // This struct is also the basis for the JSON configuration file used to register a new tool group.
 type ToolGroup struct {
+	// UUID is the immutable identifier of the tool group, it is accepted in place of its name to address it.
+	// It is assigned by the server and ignored when the group is created or updated.
+	UUID string `json:"uuid,omitempty"`
 	// Name is the unique name of the tool group (mandatory).
 	Name string `json:"name"`
 	// IncludedTools is a list of tools included in this group.
@@ -15,6 +30,9 @@ type ToolGroup struct {
 	ExcludedTools []string `json:"excluded_tools,omitempty"`
 
 	Description string `json:"description"`
+
+	// Version is incremented every time the group changes, it is also returned as the ETag.
+	Version uint `json:"version,omitempty"`
 }
 
 // ToolGroupEndpoints contains the endpoints a MCP client can use to access a tool group.
@@ -25,6 +43,8 @@ type ToolGroupEndpoints struct {
 }
 
 type CreateToolGroupResponse struct {
+	// UUID is the immutable identifier assigned to the new tool group.
+	UUID string `json:"uuid,omitempty"`
 	*ToolGroupEndpoints
 }
 
@@ -42,3 +62,50 @@ type UpdateToolGroupResponse struct {
 	// New contains the now-live configuration of the tool group.
 	New *ToolGroup `json:"new"`
 }
+
+// ToolReferences lists the tool groups that reference a set of tools.
+// It answers questions like "which groups break if this server is deregistered?".
+type ToolReferences struct {
+	// Tools are the tools that were looked up, eg- all the tools provided by a server
+	Tools []string `json:"tools"`
+	// Groups are the tool groups whose effective tools include any of the tools, sorted by name
+	Groups []ToolGroupReference `json:"groups"`
+}
+
+// ToolGroupReference describes the tools a single tool group references.
+type ToolGroupReference struct {
+	Name  string   `json:"name"`
+	Tools []string `json:"tools"`
+}
+
+// Validate checks the configuration of a tool group and returns ValidationErrors listing every invalid field.
+// It doesn't check that the tools and servers exist, only the server can tell.
+func (g *ToolGroup) Validate() error {
+	var errs ValidationErrors
+	switch {
+	case g.Name == "":
+		errs.Add("name", "is required")
+	case !ValidGroupName.MatchString(g.Name):
+		errs.Add("name", "must start with an alphanumeric character and only contain alphanumeric characters, underscores and hyphens")
+	}
+	for _, list := range []struct {
+		field string
+		tools []string
+	}{
+		{"included_tools", g.IncludedTools},
+		{"excluded_tools", g.ExcludedTools},
+	} {
+		for i, t := range list.tools {
+			// tools are always named after the server that provides them
+			if server, tool, ok := strings.Cut(t, "__"); !ok || server == "" || tool == "" {
+				errs.Add(fmt.Sprintf("%s[%d]", list.field, i), "must be the name of a tool, like <server>__<tool>, got %q", t)
+			}
+		}
+	}
+	for i, s := range g.IncludedServers {
+		if problem := serverNameProblem(s); problem != "" {
+			errs.Add(fmt.Sprintf("included_servers[%d]", i), "is not a valid server name: %q %s", s, problem)
+		}
+	}
+	return errs.Err()
+}
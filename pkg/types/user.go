@@ -12,6 +12,8 @@ const (
 // A user has lesser privileges than an Admin.
 // They can consume mcpjungle but not necessarily manage it.
 type User struct {
+	// UUID is the immutable identifier of the user, it is accepted in place of their username to address them.
+	UUID     string `json:"uuid,omitempty"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
 }
@@ -21,7 +23,23 @@ type CreateOrUpdateUserRequest struct {
 	AccessToken string `json:"access_token,omitempty"`
 }
 
+// Validate checks the request and returns ValidationErrors listing every invalid field.
+// The access token is optional, one is generated for new users that don't have any.
+func (r *CreateOrUpdateUserRequest) Validate() error {
+	var errs ValidationErrors
+	if r.Username == "" {
+		errs.Add("username", "is required")
+	}
+	if r.AccessToken != "" {
+		if problem := accessTokenProblem(r.AccessToken); problem != "" {
+			errs.Add("access_token", "%s", problem)
+		}
+	}
+	return errs.Err()
+}
+
 type CreateOrUpdateUserResponse struct {
+	UUID        string `json:"uuid,omitempty"`
 	Username    string `json:"username"`
 	Role        string `json:"role"`
 	AccessToken string `json:"access_token"`
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// FieldError describes why the value of one field of a request is invalid.
+type FieldError struct {
+	// Field is the path of the field in the request body, eg- "url", "env.API_KEY" or "included_tools[2]"
+	Field string `json:"field"`
+	// Message tells what is wrong with the value, it reads as a sentence after the field, eg- "is required"
+	Message string `json:"message"`
+}
+
+// ValidationErrors lists every invalid field of a request, so that all of them can be fixed at once.
+// It is returned by the Validate methods of the request types, which the server applies to the requests it receives.
+// The API reports it as a validation_failed error whose details hold the list in "errors".
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	problems := make([]string, len(e))
+	for i, fe := range e {
+		problems[i] = fe.Field + " " + fe.Message
+	}
+	return strings.Join(problems, "; ")
+}
+
+// Add records that the value of field is invalid.
+func (e *ValidationErrors) Add(field, format string, args ...any) {
+	*e = append(*e, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns e as an error, or nil if no field is invalid.
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ValidateAccessToken checks if a user-provided access token is valid.
+// It doesn't impose many conditions to allow flexibility.
+// It is up to the user to follow best security practices when assigning access tokens.
+func ValidateAccessToken(token string) error {
+	if problem := accessTokenProblem(token); problem != "" {
+		return fmt.Errorf("access token %s", problem)
+	}
+	return nil
+}
+
+// accessTokenProblem returns what is wrong with a user-provided access token, or "" if it is valid.
+func accessTokenProblem(token string) string {
+	if len(token) < 8 {
+		return "should be at least 8 characters in length"
+	}
+	if strings.IndexFunc(token, unicode.IsSpace) >= 0 {
+		return "should not contain whitespace characters"
+	}
+	return ""
+}
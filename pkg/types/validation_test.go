@@ -0,0 +1,344 @@
+package types
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fieldsOf returns the invalid fields reported by err, which must be nil or ValidationErrors.
+func fieldsOf(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+	}
+	fields := make([]string, len(errs))
+	for i, fe := range errs {
+		fields[i] = fe.Field
+	}
+	return fields
+}
+
+func TestValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	var errs ValidationErrors
+	if errs.Err() != nil {
+		t.Fatal("Expected no error without invalid fields")
+	}
+	errs.Add("name", "is required")
+	errs.Add("included_tools[1]", "must be the name of a tool, got %q", "lint")
+	if got, want := errs.Err().Error(), `name is required; included_tools[1] must be the name of a tool, got "lint"`; got != want {
+		t.Errorf("Expected error %q, got %q", want, got)
+	}
+}
+
+func TestRegisterServerInputValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  RegisterServerInput
+		fields []string
+	}{
+		{
+			name:  "valid streamable http server",
+			input: RegisterServerInput{Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/"},
+		},
+		{
+			name:  "valid stdio server",
+			input: RegisterServerInput{Name: "filesystem", Transport: "stdio", Command: "npx", Env: map[string]string{"ROOT": "/tmp"}},
+		},
+		{
+			name:   "every invalid field is reported",
+			input:  RegisterServerInput{Name: "git__hub", Transport: "sse", URL: "ftp://example.com", SessionMode: "sticky"},
+			fields: []string{"name", "session_mode", "url"},
+		},
+		{
+			name:   "missing transport",
+			input:  RegisterServerInput{Name: "github", URL: "https://api.githubcopilot.com/mcp/"},
+			fields: []string{"transport"},
+		},
+		{
+			name:   "stdio server without command and invalid env",
+			input:  RegisterServerInput{Name: "filesystem", Transport: "stdio", Env: map[string]string{"A=B": "c", "": "d"}},
+			fields: []string{"command", "env", "env.A=B"},
+		},
+		{
+			name:   "url without host",
+			input:  RegisterServerInput{Name: "github", Transport: "streamable_http", URL: "http:///mcp"},
+			fields: []string{"url"},
+		},
+		{
+			name:  "lazily started stdio server",
+			input: RegisterServerInput{Name: "filesystem", Transport: "stdio", Command: "npx", LazyStart: true},
+		},
+		{
+			name:   "lazy start of http server",
+			input:  RegisterServerInput{Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/", LazyStart: true},
+			fields: []string{"lazy_start"},
+		},
+		{
+			name: "tuned connection pool",
+			input: RegisterServerInput{
+				Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/",
+				HTTPPool: &HTTPPoolConfig{MaxIdleConnsPerHost: 100, IdleConnTimeoutSec: 30, DisableHTTP2: true},
+			},
+		},
+		{
+			name: "invalid connection pool",
+			input: RegisterServerInput{
+				Name: "slack", Transport: "sse", URL: "https://example.com/sse",
+				HTTPPool: &HTTPPoolConfig{MaxIdleConnsPerHost: -1, IdleConnTimeoutSec: -1},
+			},
+			fields: []string{"http_pool", "http_pool.max_idle_conns_per_host", "http_pool.idle_conn_timeout_sec"},
+		},
+		{
+			name: "session kept alive",
+			input: RegisterServerInput{
+				Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/", SessionMode: "stateful",
+				KeepAlive: &KeepAliveConfig{IntervalSec: 30, MaxIdleSec: 600},
+			},
+		},
+		{
+			name: "keep-alive of a stateless server",
+			input: RegisterServerInput{
+				Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/",
+				KeepAlive: &KeepAliveConfig{IntervalSec: 0, MaxIdleSec: -1},
+			},
+			fields: []string{"keep_alive", "keep_alive.interval_sec", "keep_alive.max_idle_sec"},
+		},
+		{
+			name: "server in a container",
+			input: RegisterServerInput{
+				Name: "slack", Transport: "sse", URL: "http://localhost:9000/sse",
+				Container: &ContainerConfig{Image: "slack-mcp:latest", Ports: []string{"9000:9000"}, Port: 9000},
+			},
+		},
+		{
+			name: "invalid container",
+			input: RegisterServerInput{
+				Name: "github", Transport: "streamable_http", URL: "http://localhost:3001/mcp",
+				Container: &ContainerConfig{Image: " ", Ports: []string{""}, Port: 70000},
+			},
+			fields: []string{"container.image", "container.port", "container.ports"},
+		},
+		{
+			name: "stdio server launched in a container",
+			input: RegisterServerInput{
+				Name: "filesystem", Transport: "stdio", Env: map[string]string{"ROOT": "/data"},
+				Container: &ContainerConfig{
+					Image: "mcp/filesystem", Volumes: []string{"/srv/data:/data:ro"}, Network: "none", MemoryMB: 256, CPUs: 0.5,
+				},
+			},
+		},
+		{
+			name: "invalid container of a stdio server",
+			input: RegisterServerInput{
+				Name: "filesystem", Transport: "stdio",
+				Container: &ContainerConfig{
+					Image: "mcp/filesystem", Env: map[string]string{"ROOT": "/data"}, Ports: []string{"8000:8000"},
+					Volumes: []string{"data"}, MemoryMB: -1, CPUs: -1,
+				},
+			},
+			fields: []string{"container.env", "container.ports", "container.volumes", "container.memory_mb", "container.cpus"},
+		},
+		{
+			name: "stdio settings of the container of an HTTP server",
+			input: RegisterServerInput{
+				Name: "github", Transport: "streamable_http", URL: "http://localhost:3001/mcp",
+				Container: &ContainerConfig{Image: "github-mcp", Network: "host"},
+			},
+			fields: []string{"container"},
+		},
+		{
+			name: "stdio server launched from a package",
+			input: RegisterServerInput{
+				Name: "filesystem", Transport: "stdio", Args: []string{"/tmp"},
+				Package: &PackageConfig{Runtime: PackageRuntimeNpx, Name: "@modelcontextprotocol/server-filesystem"},
+			},
+		},
+		{
+			name: "invalid package",
+			input: RegisterServerInput{
+				Name: "time", Transport: "stdio", Command: "uvx",
+				Package: &PackageConfig{Runtime: "pipx", Name: "mcp-server-time==1.0", Version: "1.0 --force"},
+			},
+			fields: []string{"command", "package.runtime", "package.name", "package.version"},
+		},
+		{
+			name: "package of a server launched in a container",
+			input: RegisterServerInput{
+				Name: "time", Transport: "stdio", Container: &ContainerConfig{Image: "mcp/time"},
+				Package: &PackageConfig{Runtime: PackageRuntimeUvx, Name: "mcp-server-time"},
+			},
+			fields: []string{"package"},
+		},
+		{
+			name: "package of an HTTP server",
+			input: RegisterServerInput{
+				Name: "github", Transport: "streamable_http", URL: "http://localhost:3001/mcp",
+				Package: &PackageConfig{Runtime: PackageRuntimeNpx, Name: "github-mcp"},
+			},
+			fields: []string{"package"},
+		},
+		{
+			name: "keep-alive of a stdio server",
+			input: RegisterServerInput{
+				Name: "filesystem", Transport: "stdio", Command: "npx", SessionMode: "stateful",
+				KeepAlive: &KeepAliveConfig{IntervalSec: 30},
+			},
+			fields: []string{"keep_alive"},
+		},
+		{
+			name: "openapi server",
+			input: RegisterServerInput{
+				Name: "petstore", Transport: "openapi",
+				OpenAPI: &OpenAPIConfig{
+					BaseURL: "https://petstore.example.com/v1",
+					Auth:    &OpenAPIAuth{Type: OpenAPIAuthAPIKey, Header: "X-API-Key", Value: "secret"},
+					Operations: []OpenAPIOperation{{
+						Tool: "getPet", Method: "GET", Path: "/pets/{id}",
+						Parameters: []OpenAPIParameter{{Name: "id", In: "path", Required: true}},
+					}},
+				},
+			},
+		},
+		{
+			name:   "openapi server without its service",
+			input:  RegisterServerInput{Name: "petstore", Transport: "openapi", URL: "https://petstore.example.com/v1"},
+			fields: []string{"url", "openapi"},
+		},
+		{
+			name: "invalid openapi service",
+			input: RegisterServerInput{
+				Name: "petstore", Transport: "openapi",
+				OpenAPI: &OpenAPIConfig{
+					BaseURL: "petstore.example.com",
+					Auth:    &OpenAPIAuth{Type: OpenAPIAuthAPIKey},
+					Operations: []OpenAPIOperation{
+						{Tool: "get pet", Method: "get", Path: "pets", Parameters: []OpenAPIParameter{{Name: "session", In: "cookie"}}},
+						{Tool: "listPets", Method: "GET", Path: "/pets"},
+						{Tool: "listPets", Method: "GET", Path: "/pets", InputSchema: []byte("{")},
+					},
+				},
+			},
+			fields: []string{
+				"openapi.base_url",
+				"openapi.auth.header",
+				"openapi.auth.value",
+				"openapi.operations[0].tool",
+				"openapi.operations[0].method",
+				"openapi.operations[0].path",
+				"openapi.operations[0].parameters.session.in",
+				"openapi.operations[2].tool",
+				"openapi.operations[2].input_schema",
+			},
+		},
+		{
+			name: "openapi service of an http server",
+			input: RegisterServerInput{
+				Name: "github", Transport: "streamable_http", URL: "https://api.githubcopilot.com/mcp/",
+				OpenAPI: &OpenAPIConfig{BaseURL: "https://api.github.com"},
+			},
+			fields: []string{"openapi"},
+		},
+		{
+			name: "webhook tool",
+			input: RegisterServerInput{
+				Name: "deploy_notify", Transport: "webhook_tool",
+				WebhookTool: &WebhookToolConfig{
+					URL: "https://automation.example.com/hooks/deploy", Method: "POST",
+					Headers:     map[string]string{"X-Source": "mcpjungle"},
+					Auth:        &OpenAPIAuth{Type: OpenAPIAuthBearer, Value: "secret"},
+					InputSchema: []byte(`{"type":"object","required":["service"]}`),
+				},
+			},
+		},
+		{
+			name: "invalid webhook tool",
+			input: RegisterServerInput{
+				Name: strings.Repeat("deploy_notify", 5), Transport: "webhook_tool", Command: "curl",
+				WebhookTool: &WebhookToolConfig{
+					URL: "automation.example.com", Method: "post",
+					Headers:     map[string]string{"X Source": "mcpjungle"},
+					Auth:        &OpenAPIAuth{Type: "basic", Value: "secret"},
+					InputSchema: []byte(`{"type":"string"}`),
+				},
+			},
+			fields: []string{
+				"command",
+				"name",
+				"webhook_tool.url",
+				"webhook_tool.method",
+				"webhook_tool.headers",
+				"webhook_tool.auth.type",
+				"webhook_tool.input_schema",
+			},
+		},
+		{
+			name:   "webhook tool without its endpoint",
+			input:  RegisterServerInput{Name: "deploy_notify", Transport: "webhook_tool"},
+			fields: []string{"webhook_tool"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fieldsOf(t, tt.input.Validate()); !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("Expected invalid fields %v, got %v", tt.fields, got)
+			}
+		})
+	}
+}
+
+func TestToolGroupValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := ToolGroup{Name: "ci-tools", IncludedTools: []string{"github__create_pr"}, IncludedServers: []string{"slack"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected a valid group, got %v", err)
+	}
+
+	invalid := ToolGroup{
+		Name:            "-ci",
+		IncludedTools:   []string{"github__create_pr", "lint"},
+		IncludedServers: []string{"slack_"},
+		ExcludedTools:   []string{"__create_issue"},
+	}
+	want := []string{"name", "included_tools[1]", "excluded_tools[0]", "included_servers[0]"}
+	if got := fieldsOf(t, invalid.Validate()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected invalid fields %v, got %v", want, got)
+	}
+}
+
+func TestMcpClientValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := McpClient{Name: "cursor", AccessToken: "a-custom-token", AllowList: []string{"github", AllowAllMcpServers}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected a valid client, got %v", err)
+	}
+
+	invalid := McpClient{AccessToken: "short", AllowList: []string{"github", "not a server"}}
+	want := []string{"name", "access_token", "allow_list[1]"}
+	if got := fieldsOf(t, invalid.Validate()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected invalid fields %v, got %v", want, got)
+	}
+}
+
+func TestCreateOrUpdateUserRequestValidate(t *testing.T) {
+	t.Parallel()
+
+	if err := (&CreateOrUpdateUserRequest{Username: "alice"}).Validate(); err != nil {
+		t.Errorf("Expected a valid request without access token, got %v", err)
+	}
+	want := []string{"username", "access_token"}
+	if got := fieldsOf(t, (&CreateOrUpdateUserRequest{AccessToken: "has white space"}).Validate()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected invalid fields %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,124 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Types of the registry lifecycle events delivered to webhooks.
+const (
+	EventServerRegistered   = "server.registered"
+	EventServerUpdated      = "server.updated"
+	EventServerDeregistered = "server.deregistered"
+	EventGroupCreated       = "group.created"
+	EventGroupUpdated       = "group.updated"
+	EventGroupDeleted       = "group.deleted"
+	// EventToolSyncChanged is sent when the tools of an MCP server changed after it was reconnected to or synchronized.
+	EventToolSyncChanged = "tool.sync_changed"
+	// EventServerUnhealthy is sent when an MCP server fails a health check, unless it failed the previous one too.
+	EventServerUnhealthy = "server.unhealthy"
+	// EventServerRecovered is sent when an MCP server passes a health check after failing the previous ones.
+	EventServerRecovered = "server.recovered"
+	// EventWebhookTest is only sent to a webhook on demand, to check that it is reachable.
+	EventWebhookTest = "webhook.test"
+)
+
+// AllWebhookEvents is the wildcard event filter that subscribes a webhook to every event.
+const AllWebhookEvents = "*"
+
+// WebhookEventTypes lists the types of events a webhook can subscribe to.
+var WebhookEventTypes = []string{
+	EventServerRegistered,
+	EventServerUpdated,
+	EventServerDeregistered,
+	EventGroupCreated,
+	EventGroupUpdated,
+	EventGroupDeleted,
+	EventToolSyncChanged,
+	EventServerUnhealthy,
+	EventServerRecovered,
+}
+
+// Formats of the body of webhook deliveries.
+const (
+	// WebhookFormatJSON sends the event as is, see WebhookEvent. It is the default.
+	WebhookFormatJSON = "json"
+	// WebhookFormatSlack sends the event as a readable message to a Slack-compatible incoming webhook,
+	// as {"text": "..."} in Slack's mrkdwn syntax. Mattermost and Rocket.Chat accept it too.
+	WebhookFormatSlack = "slack"
+	// WebhookFormatMarkdown sends the event as a readable message in Markdown, for other chat webhooks:
+	// {"text": "...", "event": {...}}, where event is the event as sent in the json format.
+	WebhookFormatMarkdown = "markdown"
+)
+
+// WebhookFormats lists the formats of the body of webhook deliveries.
+var WebhookFormats = []string{WebhookFormatJSON, WebhookFormatSlack, WebhookFormatMarkdown}
+
+// HTTP headers of webhook deliveries.
+const (
+	// WebhookSignatureHeader holds "sha256=" followed by the hex-encoded HMAC-SHA256 of the body,
+	// keyed with the webhook's secret.
+	WebhookSignatureHeader = "X-MCPJungle-Signature"
+	WebhookEventHeader     = "X-MCPJungle-Event"
+	// WebhookDeliveryHeader holds the ID of the event, which is the same across retries of a delivery.
+	WebhookDeliveryHeader = "X-MCPJungle-Delivery"
+)
+
+// Webhook is a subscription to the registry's lifecycle events.
+type Webhook struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Events are the types of events delivered to the webhook, all of them if empty or "*".
+	Events []string `json:"events,omitempty"`
+	// Secret signs the deliveries. It is only returned when the webhook is created.
+	Secret string `json:"secret,omitempty"`
+	// Format is the format of the body of the deliveries, one of WebhookFormats, WebhookFormatJSON if empty.
+	Format string `json:"format,omitempty"`
+
+	Enabled bool `json:"enabled"`
+	// ConsecutiveFailures is the number of deliveries in a row that failed after all their retries.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+	// DisabledReason tells why the webhook was disabled automatically, if it was.
+	DisabledReason string `json:"disabled_reason,omitempty"`
+}
+
+// WebhookEvent is the JSON body of a webhook delivery.
+type WebhookEvent struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookDelivery is the outcome of delivering an event to a webhook, possibly after several attempts.
+type WebhookDelivery struct {
+	EventID    string    `json:"event_id"`
+	Event      string    `json:"event"`
+	Attempts   int       `json:"attempts"`
+	Succeeded  bool      `json:"succeeded"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ToolSyncChange is the data of EventToolSyncChanged events.
+type ToolSyncChange struct {
+	// Server is the name of the MCP server whose tools changed.
+	Server string `json:"server"`
+	// Added and Removed are the canonical names of the tools the server gained and lost.
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// ServerHealthChange is the data of EventServerUnhealthy and EventServerRecovered events.
+type ServerHealthChange struct {
+	// Server is the name of the MCP server whose health changed.
+	Server string             `json:"server"`
+	Status ServerHealthStatus `json:"status"`
+	// Error is why the server failed its health check, empty once it recovered.
+	Error string `json:"error,omitempty"`
+	// CheckedAt is when the health check that changed the health of the server started.
+	CheckedAt time.Time `json:"checked_at"`
+	// UnhealthySince is when the server failed the first of the health checks it failed in a row.
+	UnhealthySince time.Time `json:"unhealthy_since"`
+}
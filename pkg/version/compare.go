@@ -0,0 +1,61 @@
+package version
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Semver is a parsed semantic version (major.minor.patch).
+// Pre-release and build metadata suffixes are ignored.
+type Semver struct {
+	Major, Minor, Patch int
+}
+
+// Parse parses a version string like "v1.2.3", "1.2" or "v1.2.3-rc.1".
+// It returns false if the string is not a semantic version (eg- "dev").
+func Parse(v string) (Semver, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return Semver{}, false
+	}
+
+	parts := strings.Split(v, ".")
+	if len(parts) > 3 {
+		return Semver{}, false
+	}
+	var nums [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return Semver{}, false
+		}
+		nums[i] = n
+	}
+	return Semver{Major: nums[0], Minor: nums[1], Patch: nums[2]}, true
+}
+
+// Compare returns -1, 0 or +1 depending on whether a is older than, equal to or newer than b.
+func (a Semver) Compare(b Semver) int {
+	switch {
+	case a.Major != b.Major:
+		return sign(a.Major - b.Major)
+	case a.Minor != b.Minor:
+		return sign(a.Minor - b.Minor)
+	default:
+		return sign(a.Patch - b.Patch)
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
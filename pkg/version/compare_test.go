@@ -0,0 +1,49 @@
+package version
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		in     string
+		want   Semver
+		wantOk bool
+	}{
+		{"v1.2.3", Semver{1, 2, 3}, true},
+		{"1.2", Semver{1, 2, 0}, true},
+		{"v0.3.0-rc.1", Semver{0, 3, 0}, true},
+		{"v2.0.0+build.5", Semver{2, 0, 0}, true},
+		{"dev", Semver{}, false},
+		{"", Semver{}, false},
+		{"v1.2.3.4", Semver{}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, ok := Parse(tc.in)
+			if ok != tc.wantOk || got != tc.want {
+				t.Errorf("Parse(%q) = %v, %v; want %v, %v", tc.in, got, ok, tc.want, tc.wantOk)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"v1.2.3", "v1.2.4", -1},
+		{"v1.10.0", "v1.9.9", 1},
+		{"v2.0.0", "v1.99.99", 1},
+		{"v0.1.0", "v0.2.0", -1},
+	}
+
+	for _, tc := range testCases {
+		a, _ := Parse(tc.a)
+		b, _ := Parse(tc.b)
+		if got := a.Compare(b); got != tc.want {
+			t.Errorf("Compare(%s, %s) = %d; want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -10,6 +10,18 @@ const defaultVersion = "dev"
 // go build -ldflags="-X 'github.com/mcpjungle/mcpjungle/pkg/version.Version=v1.2.3'"
 var Version = defaultVersion
 
+// Commit is the git commit the binary was built from, it can be overridden at build time just like Version.
+// If not set, it is read from the VCS information embedded by the go toolchain (if available).
+var Commit = ""
+
+// BuildDate is the time the binary was built at (RFC3339), it can be overridden at build time just like Version.
+// If not set, the commit time from the embedded VCS information is used (if available).
+var BuildDate = ""
+
+// MinClientVersion is the oldest CLI version that the server in this binary supports.
+// Clients older than this may not work correctly against the server.
+var MinClientVersion = "v0.1.0"
+
 // GetVersion returns the version string using build info or fallback to default.
 func GetVersion() string {
 	if Version != "" && Version != defaultVersion {
@@ -35,3 +47,32 @@ func NormalizeVersion(v string) string {
 	}
 	return v
 }
+
+// GetCommit returns the git commit the binary was built from, or an empty string if unknown.
+func GetCommit() string {
+	if Commit != "" {
+		return Commit
+	}
+	return readBuildSetting("vcs.revision")
+}
+
+// GetBuildDate returns the time the binary was built at, or an empty string if unknown.
+func GetBuildDate() string {
+	if BuildDate != "" {
+		return BuildDate
+	}
+	return readBuildSetting("vcs.time")
+}
+
+func readBuildSetting(key string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, s := range info.Settings {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,1390 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: admin/v1/admin.proto
+
+// Package mcpjungle.admin.v1 is the gRPC admin API of the MCPJungle registry.
+// It exposes the core operations of the HTTP API, with the same behaviour and authorization:
+// in enterprise mode, the access token of a user is passed in the "authorization" metadata as "Bearer <token>".
+
+package adminv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// McpServer is an MCP server registered in the registry.
+type McpServer struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Transport   string                 `protobuf:"bytes,2,opt,name=transport,proto3" json:"transport,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Url         string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	Command     string                 `protobuf:"bytes,5,opt,name=command,proto3" json:"command,omitempty"`
+	Args        []string               `protobuf:"bytes,6,rep,name=args,proto3" json:"args,omitempty"`
+	Env         map[string]string      `protobuf:"bytes,7,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SessionMode string                 `protobuf:"bytes,8,opt,name=session_mode,json=sessionMode,proto3" json:"session_mode,omitempty"`
+	// version is incremented every time the configuration of the server changes.
+	Version       uint64 `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *McpServer) Reset() {
+	*x = McpServer{}
+	mi := &file_admin_v1_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *McpServer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*McpServer) ProtoMessage() {}
+
+func (x *McpServer) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use McpServer.ProtoReflect.Descriptor instead.
+func (*McpServer) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *McpServer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *McpServer) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *McpServer) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *McpServer) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *McpServer) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *McpServer) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *McpServer) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *McpServer) GetSessionMode() string {
+	if x != nil {
+		return x.SessionMode
+	}
+	return ""
+}
+
+func (x *McpServer) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type ListServersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// page_size is the maximum number of servers to return, 100 if unset and at most 1000.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token returned by the previous page.
+	PageToken     string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServersRequest) Reset() {
+	*x = ListServersRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServersRequest) ProtoMessage() {}
+
+func (x *ListServersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServersRequest.ProtoReflect.Descriptor instead.
+func (*ListServersRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListServersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListServersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListServersResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Servers []*McpServer           `protobuf:"bytes,1,rep,name=servers,proto3" json:"servers,omitempty"`
+	// next_page_token is empty when this is the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServersResponse) Reset() {
+	*x = ListServersResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServersResponse) ProtoMessage() {}
+
+func (x *ListServersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServersResponse.ProtoReflect.Descriptor instead.
+func (*ListServersResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListServersResponse) GetServers() []*McpServer {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+func (x *ListServersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// RegisterServerRequest holds the configuration of the server to register,
+// with the same fields and validation as the HTTP API.
+type RegisterServerRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name and transport ("stdio", "streamable_http" or "sse") are mandatory.
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Transport   string `protobuf:"bytes,2,opt,name=transport,proto3" json:"transport,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// url is mandatory for the streamable_http and sse transports.
+	Url         string `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	BearerToken string `protobuf:"bytes,5,opt,name=bearer_token,json=bearerToken,proto3" json:"bearer_token,omitempty"`
+	// command, args and env are used for the stdio transport.
+	Command       string            `protobuf:"bytes,6,opt,name=command,proto3" json:"command,omitempty"`
+	Args          []string          `protobuf:"bytes,7,rep,name=args,proto3" json:"args,omitempty"`
+	Env           map[string]string `protobuf:"bytes,8,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SessionMode   string            `protobuf:"bytes,9,opt,name=session_mode,json=sessionMode,proto3" json:"session_mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterServerRequest) Reset() {
+	*x = RegisterServerRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterServerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterServerRequest) ProtoMessage() {}
+
+func (x *RegisterServerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterServerRequest.ProtoReflect.Descriptor instead.
+func (*RegisterServerRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RegisterServerRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisterServerRequest) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *RegisterServerRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *RegisterServerRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *RegisterServerRequest) GetBearerToken() string {
+	if x != nil {
+		return x.BearerToken
+	}
+	return ""
+}
+
+func (x *RegisterServerRequest) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *RegisterServerRequest) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *RegisterServerRequest) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *RegisterServerRequest) GetSessionMode() string {
+	if x != nil {
+		return x.SessionMode
+	}
+	return ""
+}
+
+type DeregisterServerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeregisterServerRequest) Reset() {
+	*x = DeregisterServerRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeregisterServerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeregisterServerRequest) ProtoMessage() {}
+
+func (x *DeregisterServerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeregisterServerRequest.ProtoReflect.Descriptor instead.
+func (*DeregisterServerRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeregisterServerRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeregisterServerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeregisterServerResponse) Reset() {
+	*x = DeregisterServerResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeregisterServerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeregisterServerResponse) ProtoMessage() {}
+
+func (x *DeregisterServerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeregisterServerResponse.ProtoReflect.Descriptor instead.
+func (*DeregisterServerResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{5}
+}
+
+// Tool is a tool provided by a registered MCP server.
+type Tool struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name is the canonical name of the tool, prefixed with the name of its server.
+	Name          string           `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Enabled       bool             `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Description   string           `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	InputSchema   *structpb.Struct `protobuf:"bytes,4,opt,name=input_schema,json=inputSchema,proto3" json:"input_schema,omitempty"`
+	Annotations   *structpb.Struct `protobuf:"bytes,5,opt,name=annotations,proto3" json:"annotations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tool) Reset() {
+	*x = Tool{}
+	mi := &file_admin_v1_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tool) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tool) ProtoMessage() {}
+
+func (x *Tool) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tool.ProtoReflect.Descriptor instead.
+func (*Tool) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Tool) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Tool) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Tool) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Tool) GetInputSchema() *structpb.Struct {
+	if x != nil {
+		return x.InputSchema
+	}
+	return nil
+}
+
+func (x *Tool) GetAnnotations() *structpb.Struct {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+type ListToolsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// page_size is the maximum number of tools to return, 100 if unset and at most 1000.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token returned by the previous page.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// server only lists the tools of this MCP server.
+	Server string `protobuf:"bytes,3,opt,name=server,proto3" json:"server,omitempty"`
+	// group only lists the tools of this tool group.
+	Group string `protobuf:"bytes,4,opt,name=group,proto3" json:"group,omitempty"`
+	// enabled only lists the enabled or disabled tools.
+	Enabled *bool `protobuf:"varint,5,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	// query only lists the tools whose name or description contain it.
+	Query         string `protobuf:"bytes,6,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListToolsRequest) Reset() {
+	*x = ListToolsRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListToolsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListToolsRequest) ProtoMessage() {}
+
+func (x *ListToolsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListToolsRequest.ProtoReflect.Descriptor instead.
+func (*ListToolsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListToolsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListToolsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListToolsRequest) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *ListToolsRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ListToolsRequest) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *ListToolsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+type ListToolsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Tools []*Tool                `protobuf:"bytes,1,rep,name=tools,proto3" json:"tools,omitempty"`
+	// next_page_token is empty when this is the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// total is the number of tools matching the filters across all pages.
+	Total         int64 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListToolsResponse) Reset() {
+	*x = ListToolsResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListToolsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListToolsResponse) ProtoMessage() {}
+
+func (x *ListToolsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListToolsResponse.ProtoReflect.Descriptor instead.
+func (*ListToolsResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListToolsResponse) GetTools() []*Tool {
+	if x != nil {
+		return x.Tools
+	}
+	return nil
+}
+
+func (x *ListToolsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListToolsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type InvokeToolRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name is the canonical name of the tool.
+	Name          string           `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Arguments     *structpb.Struct `protobuf:"bytes,2,opt,name=arguments,proto3" json:"arguments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvokeToolRequest) Reset() {
+	*x = InvokeToolRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvokeToolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvokeToolRequest) ProtoMessage() {}
+
+func (x *InvokeToolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvokeToolRequest.ProtoReflect.Descriptor instead.
+func (*InvokeToolRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *InvokeToolRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *InvokeToolRequest) GetArguments() *structpb.Struct {
+	if x != nil {
+		return x.Arguments
+	}
+	return nil
+}
+
+type InvokeToolResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*InvokeToolResponse_Content
+	//	*InvokeToolResponse_Result
+	Event         isInvokeToolResponse_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvokeToolResponse) Reset() {
+	*x = InvokeToolResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvokeToolResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvokeToolResponse) ProtoMessage() {}
+
+func (x *InvokeToolResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvokeToolResponse.ProtoReflect.Descriptor instead.
+func (*InvokeToolResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *InvokeToolResponse) GetEvent() isInvokeToolResponse_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *InvokeToolResponse) GetContent() *structpb.Struct {
+	if x != nil {
+		if x, ok := x.Event.(*InvokeToolResponse_Content); ok {
+			return x.Content
+		}
+	}
+	return nil
+}
+
+func (x *InvokeToolResponse) GetResult() *ToolInvokeResult {
+	if x != nil {
+		if x, ok := x.Event.(*InvokeToolResponse_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+type isInvokeToolResponse_Event interface {
+	isInvokeToolResponse_Event()
+}
+
+type InvokeToolResponse_Content struct {
+	// content is an item of the content of the tool's result, eg- {"type": "text", "text": "..."}.
+	Content *structpb.Struct `protobuf:"bytes,1,opt,name=content,proto3,oneof"`
+}
+
+type InvokeToolResponse_Result struct {
+	// result ends the stream.
+	Result *ToolInvokeResult `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+func (*InvokeToolResponse_Content) isInvokeToolResponse_Event() {}
+
+func (*InvokeToolResponse_Result) isInvokeToolResponse_Event() {}
+
+// ToolInvokeResult is the outcome of a tool call.
+type ToolInvokeResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// is_error is set when the tool reported that the call failed.
+	IsError           bool             `protobuf:"varint,1,opt,name=is_error,json=isError,proto3" json:"is_error,omitempty"`
+	Meta              *structpb.Struct `protobuf:"bytes,2,opt,name=meta,proto3" json:"meta,omitempty"`
+	StructuredContent *structpb.Value  `protobuf:"bytes,3,opt,name=structured_content,json=structuredContent,proto3" json:"structured_content,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ToolInvokeResult) Reset() {
+	*x = ToolInvokeResult{}
+	mi := &file_admin_v1_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolInvokeResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolInvokeResult) ProtoMessage() {}
+
+func (x *ToolInvokeResult) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolInvokeResult.ProtoReflect.Descriptor instead.
+func (*ToolInvokeResult) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ToolInvokeResult) GetIsError() bool {
+	if x != nil {
+		return x.IsError
+	}
+	return false
+}
+
+func (x *ToolInvokeResult) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *ToolInvokeResult) GetStructuredContent() *structpb.Value {
+	if x != nil {
+		return x.StructuredContent
+	}
+	return nil
+}
+
+// ToolGroup is a named set of tools exposed by its own MCP endpoint.
+type ToolGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	IncludedTools []string               `protobuf:"bytes,3,rep,name=included_tools,json=includedTools,proto3" json:"included_tools,omitempty"`
+	// included_servers includes all the tools of these MCP servers.
+	IncludedServers []string `protobuf:"bytes,4,rep,name=included_servers,json=includedServers,proto3" json:"included_servers,omitempty"`
+	// excluded_tools are removed from the tools included by included_servers.
+	ExcludedTools []string `protobuf:"bytes,5,rep,name=excluded_tools,json=excludedTools,proto3" json:"excluded_tools,omitempty"`
+	// version is incremented every time the group changes.
+	Version       uint64 `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToolGroup) Reset() {
+	*x = ToolGroup{}
+	mi := &file_admin_v1_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolGroup) ProtoMessage() {}
+
+func (x *ToolGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolGroup.ProtoReflect.Descriptor instead.
+func (*ToolGroup) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ToolGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ToolGroup) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ToolGroup) GetIncludedTools() []string {
+	if x != nil {
+		return x.IncludedTools
+	}
+	return nil
+}
+
+func (x *ToolGroup) GetIncludedServers() []string {
+	if x != nil {
+		return x.IncludedServers
+	}
+	return nil
+}
+
+func (x *ToolGroup) GetExcludedTools() []string {
+	if x != nil {
+		return x.ExcludedTools
+	}
+	return nil
+}
+
+func (x *ToolGroup) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type CreateToolGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *ToolGroup             `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateToolGroupRequest) Reset() {
+	*x = CreateToolGroupRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateToolGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateToolGroupRequest) ProtoMessage() {}
+
+func (x *CreateToolGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateToolGroupRequest.ProtoReflect.Descriptor instead.
+func (*CreateToolGroupRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreateToolGroupRequest) GetGroup() *ToolGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type UpdateToolGroupRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// group is the new configuration of the group, its version is ignored.
+	Group *ToolGroup `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	// expected_version, if set, makes the update fail with ABORTED unless it is the current version of the group.
+	ExpectedVersion uint64 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateToolGroupRequest) Reset() {
+	*x = UpdateToolGroupRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateToolGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateToolGroupRequest) ProtoMessage() {}
+
+func (x *UpdateToolGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateToolGroupRequest.ProtoReflect.Descriptor instead.
+func (*UpdateToolGroupRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UpdateToolGroupRequest) GetGroup() *ToolGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+func (x *UpdateToolGroupRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type UpdateToolGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Previous      *ToolGroup             `protobuf:"bytes,1,opt,name=previous,proto3" json:"previous,omitempty"`
+	Current       *ToolGroup             `protobuf:"bytes,2,opt,name=current,proto3" json:"current,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateToolGroupResponse) Reset() {
+	*x = UpdateToolGroupResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateToolGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateToolGroupResponse) ProtoMessage() {}
+
+func (x *UpdateToolGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateToolGroupResponse.ProtoReflect.Descriptor instead.
+func (*UpdateToolGroupResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateToolGroupResponse) GetPrevious() *ToolGroup {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+func (x *UpdateToolGroupResponse) GetCurrent() *ToolGroup {
+	if x != nil {
+		return x.Current
+	}
+	return nil
+}
+
+type DeleteToolGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteToolGroupRequest) Reset() {
+	*x = DeleteToolGroupRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteToolGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteToolGroupRequest) ProtoMessage() {}
+
+func (x *DeleteToolGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteToolGroupRequest.ProtoReflect.Descriptor instead.
+func (*DeleteToolGroupRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteToolGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteToolGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteToolGroupResponse) Reset() {
+	*x = DeleteToolGroupResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteToolGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteToolGroupResponse) ProtoMessage() {}
+
+func (x *DeleteToolGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteToolGroupResponse.ProtoReflect.Descriptor instead.
+func (*DeleteToolGroupResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{17}
+}
+
+var File_admin_v1_admin_proto protoreflect.FileDescriptor
+
+const file_admin_v1_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x14admin/v1/admin.proto\x12\x12mcpjungle.admin.v1\x1a\x1cgoogle/protobuf/struct.proto\"\xce\x02\n" +
+	"\tMcpServer\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\ttransport\x18\x02 \x01(\tR\ttransport\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12\x18\n" +
+	"\acommand\x18\x05 \x01(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\x06 \x03(\tR\x04args\x128\n" +
+	"\x03env\x18\a \x03(\v2&.mcpjungle.admin.v1.McpServer.EnvEntryR\x03env\x12!\n" +
+	"\fsession_mode\x18\b \x01(\tR\vsessionMode\x12\x18\n" +
+	"\aversion\x18\t \x01(\x04R\aversion\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"P\n" +
+	"\x12ListServersRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"v\n" +
+	"\x13ListServersResponse\x127\n" +
+	"\aservers\x18\x01 \x03(\v2\x1d.mcpjungle.admin.v1.McpServerR\aservers\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xef\x02\n" +
+	"\x15RegisterServerRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\ttransport\x18\x02 \x01(\tR\ttransport\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12!\n" +
+	"\fbearer_token\x18\x05 \x01(\tR\vbearerToken\x12\x18\n" +
+	"\acommand\x18\x06 \x01(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\a \x03(\tR\x04args\x12D\n" +
+	"\x03env\x18\b \x03(\v22.mcpjungle.admin.v1.RegisterServerRequest.EnvEntryR\x03env\x12!\n" +
+	"\fsession_mode\x18\t \x01(\tR\vsessionMode\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"-\n" +
+	"\x17DeregisterServerRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x1a\n" +
+	"\x18DeregisterServerResponse\"\xcd\x01\n" +
+	"\x04Tool\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aenabled\x18\x02 \x01(\bR\aenabled\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12:\n" +
+	"\finput_schema\x18\x04 \x01(\v2\x17.google.protobuf.StructR\vinputSchema\x129\n" +
+	"\vannotations\x18\x05 \x01(\v2\x17.google.protobuf.StructR\vannotations\"\xbd\x01\n" +
+	"\x10ListToolsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x16\n" +
+	"\x06server\x18\x03 \x01(\tR\x06server\x12\x14\n" +
+	"\x05group\x18\x04 \x01(\tR\x05group\x12\x1d\n" +
+	"\aenabled\x18\x05 \x01(\bH\x00R\aenabled\x88\x01\x01\x12\x14\n" +
+	"\x05query\x18\x06 \x01(\tR\x05queryB\n" +
+	"\n" +
+	"\b_enabled\"\x81\x01\n" +
+	"\x11ListToolsResponse\x12.\n" +
+	"\x05tools\x18\x01 \x03(\v2\x18.mcpjungle.admin.v1.ToolR\x05tools\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\"^\n" +
+	"\x11InvokeToolRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x125\n" +
+	"\targuments\x18\x02 \x01(\v2\x17.google.protobuf.StructR\targuments\"\x92\x01\n" +
+	"\x12InvokeToolResponse\x123\n" +
+	"\acontent\x18\x01 \x01(\v2\x17.google.protobuf.StructH\x00R\acontent\x12>\n" +
+	"\x06result\x18\x02 \x01(\v2$.mcpjungle.admin.v1.ToolInvokeResultH\x00R\x06resultB\a\n" +
+	"\x05event\"\xa1\x01\n" +
+	"\x10ToolInvokeResult\x12\x19\n" +
+	"\bis_error\x18\x01 \x01(\bR\aisError\x12+\n" +
+	"\x04meta\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x04meta\x12E\n" +
+	"\x12structured_content\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\x11structuredContent\"\xd4\x01\n" +
+	"\tToolGroup\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12%\n" +
+	"\x0eincluded_tools\x18\x03 \x03(\tR\rincludedTools\x12)\n" +
+	"\x10included_servers\x18\x04 \x03(\tR\x0fincludedServers\x12%\n" +
+	"\x0eexcluded_tools\x18\x05 \x03(\tR\rexcludedTools\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x04R\aversion\"M\n" +
+	"\x16CreateToolGroupRequest\x123\n" +
+	"\x05group\x18\x01 \x01(\v2\x1d.mcpjungle.admin.v1.ToolGroupR\x05group\"x\n" +
+	"\x16UpdateToolGroupRequest\x123\n" +
+	"\x05group\x18\x01 \x01(\v2\x1d.mcpjungle.admin.v1.ToolGroupR\x05group\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x04R\x0fexpectedVersion\"\x8d\x01\n" +
+	"\x17UpdateToolGroupResponse\x129\n" +
+	"\bprevious\x18\x01 \x01(\v2\x1d.mcpjungle.admin.v1.ToolGroupR\bprevious\x127\n" +
+	"\acurrent\x18\x02 \x01(\v2\x1d.mcpjungle.admin.v1.ToolGroupR\acurrent\",\n" +
+	"\x16DeleteToolGroupRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x19\n" +
+	"\x17DeleteToolGroupResponse2\xa8\x06\n" +
+	"\fAdminService\x12^\n" +
+	"\vListServers\x12&.mcpjungle.admin.v1.ListServersRequest\x1a'.mcpjungle.admin.v1.ListServersResponse\x12Z\n" +
+	"\x0eRegisterServer\x12).mcpjungle.admin.v1.RegisterServerRequest\x1a\x1d.mcpjungle.admin.v1.McpServer\x12m\n" +
+	"\x10DeregisterServer\x12+.mcpjungle.admin.v1.DeregisterServerRequest\x1a,.mcpjungle.admin.v1.DeregisterServerResponse\x12X\n" +
+	"\tListTools\x12$.mcpjungle.admin.v1.ListToolsRequest\x1a%.mcpjungle.admin.v1.ListToolsResponse\x12]\n" +
+	"\n" +
+	"InvokeTool\x12%.mcpjungle.admin.v1.InvokeToolRequest\x1a&.mcpjungle.admin.v1.InvokeToolResponse0\x01\x12\\\n" +
+	"\x0fCreateToolGroup\x12*.mcpjungle.admin.v1.CreateToolGroupRequest\x1a\x1d.mcpjungle.admin.v1.ToolGroup\x12j\n" +
+	"\x0fUpdateToolGroup\x12*.mcpjungle.admin.v1.UpdateToolGroupRequest\x1a+.mcpjungle.admin.v1.UpdateToolGroupResponse\x12j\n" +
+	"\x0fDeleteToolGroup\x12*.mcpjungle.admin.v1.DeleteToolGroupRequest\x1a+.mcpjungle.admin.v1.DeleteToolGroupResponseB7Z5github.com/mcpjungle/mcpjungle/proto/admin/v1;adminv1b\x06proto3"
+
+var (
+	file_admin_v1_admin_proto_rawDescOnce sync.Once
+	file_admin_v1_admin_proto_rawDescData []byte
+)
+
+func file_admin_v1_admin_proto_rawDescGZIP() []byte {
+	file_admin_v1_admin_proto_rawDescOnce.Do(func() {
+		file_admin_v1_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_admin_v1_admin_proto_rawDesc), len(file_admin_v1_admin_proto_rawDesc)))
+	})
+	return file_admin_v1_admin_proto_rawDescData
+}
+
+var file_admin_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_admin_v1_admin_proto_goTypes = []any{
+	(*McpServer)(nil),                // 0: mcpjungle.admin.v1.McpServer
+	(*ListServersRequest)(nil),       // 1: mcpjungle.admin.v1.ListServersRequest
+	(*ListServersResponse)(nil),      // 2: mcpjungle.admin.v1.ListServersResponse
+	(*RegisterServerRequest)(nil),    // 3: mcpjungle.admin.v1.RegisterServerRequest
+	(*DeregisterServerRequest)(nil),  // 4: mcpjungle.admin.v1.DeregisterServerRequest
+	(*DeregisterServerResponse)(nil), // 5: mcpjungle.admin.v1.DeregisterServerResponse
+	(*Tool)(nil),                     // 6: mcpjungle.admin.v1.Tool
+	(*ListToolsRequest)(nil),         // 7: mcpjungle.admin.v1.ListToolsRequest
+	(*ListToolsResponse)(nil),        // 8: mcpjungle.admin.v1.ListToolsResponse
+	(*InvokeToolRequest)(nil),        // 9: mcpjungle.admin.v1.InvokeToolRequest
+	(*InvokeToolResponse)(nil),       // 10: mcpjungle.admin.v1.InvokeToolResponse
+	(*ToolInvokeResult)(nil),         // 11: mcpjungle.admin.v1.ToolInvokeResult
+	(*ToolGroup)(nil),                // 12: mcpjungle.admin.v1.ToolGroup
+	(*CreateToolGroupRequest)(nil),   // 13: mcpjungle.admin.v1.CreateToolGroupRequest
+	(*UpdateToolGroupRequest)(nil),   // 14: mcpjungle.admin.v1.UpdateToolGroupRequest
+	(*UpdateToolGroupResponse)(nil),  // 15: mcpjungle.admin.v1.UpdateToolGroupResponse
+	(*DeleteToolGroupRequest)(nil),   // 16: mcpjungle.admin.v1.DeleteToolGroupRequest
+	(*DeleteToolGroupResponse)(nil),  // 17: mcpjungle.admin.v1.DeleteToolGroupResponse
+	nil,                              // 18: mcpjungle.admin.v1.McpServer.EnvEntry
+	nil,                              // 19: mcpjungle.admin.v1.RegisterServerRequest.EnvEntry
+	(*structpb.Struct)(nil),          // 20: google.protobuf.Struct
+	(*structpb.Value)(nil),           // 21: google.protobuf.Value
+}
+var file_admin_v1_admin_proto_depIdxs = []int32{
+	18, // 0: mcpjungle.admin.v1.McpServer.env:type_name -> mcpjungle.admin.v1.McpServer.EnvEntry
+	0,  // 1: mcpjungle.admin.v1.ListServersResponse.servers:type_name -> mcpjungle.admin.v1.McpServer
+	19, // 2: mcpjungle.admin.v1.RegisterServerRequest.env:type_name -> mcpjungle.admin.v1.RegisterServerRequest.EnvEntry
+	20, // 3: mcpjungle.admin.v1.Tool.input_schema:type_name -> google.protobuf.Struct
+	20, // 4: mcpjungle.admin.v1.Tool.annotations:type_name -> google.protobuf.Struct
+	6,  // 5: mcpjungle.admin.v1.ListToolsResponse.tools:type_name -> mcpjungle.admin.v1.Tool
+	20, // 6: mcpjungle.admin.v1.InvokeToolRequest.arguments:type_name -> google.protobuf.Struct
+	20, // 7: mcpjungle.admin.v1.InvokeToolResponse.content:type_name -> google.protobuf.Struct
+	11, // 8: mcpjungle.admin.v1.InvokeToolResponse.result:type_name -> mcpjungle.admin.v1.ToolInvokeResult
+	20, // 9: mcpjungle.admin.v1.ToolInvokeResult.meta:type_name -> google.protobuf.Struct
+	21, // 10: mcpjungle.admin.v1.ToolInvokeResult.structured_content:type_name -> google.protobuf.Value
+	12, // 11: mcpjungle.admin.v1.CreateToolGroupRequest.group:type_name -> mcpjungle.admin.v1.ToolGroup
+	12, // 12: mcpjungle.admin.v1.UpdateToolGroupRequest.group:type_name -> mcpjungle.admin.v1.ToolGroup
+	12, // 13: mcpjungle.admin.v1.UpdateToolGroupResponse.previous:type_name -> mcpjungle.admin.v1.ToolGroup
+	12, // 14: mcpjungle.admin.v1.UpdateToolGroupResponse.current:type_name -> mcpjungle.admin.v1.ToolGroup
+	1,  // 15: mcpjungle.admin.v1.AdminService.ListServers:input_type -> mcpjungle.admin.v1.ListServersRequest
+	3,  // 16: mcpjungle.admin.v1.AdminService.RegisterServer:input_type -> mcpjungle.admin.v1.RegisterServerRequest
+	4,  // 17: mcpjungle.admin.v1.AdminService.DeregisterServer:input_type -> mcpjungle.admin.v1.DeregisterServerRequest
+	7,  // 18: mcpjungle.admin.v1.AdminService.ListTools:input_type -> mcpjungle.admin.v1.ListToolsRequest
+	9,  // 19: mcpjungle.admin.v1.AdminService.InvokeTool:input_type -> mcpjungle.admin.v1.InvokeToolRequest
+	13, // 20: mcpjungle.admin.v1.AdminService.CreateToolGroup:input_type -> mcpjungle.admin.v1.CreateToolGroupRequest
+	14, // 21: mcpjungle.admin.v1.AdminService.UpdateToolGroup:input_type -> mcpjungle.admin.v1.UpdateToolGroupRequest
+	16, // 22: mcpjungle.admin.v1.AdminService.DeleteToolGroup:input_type -> mcpjungle.admin.v1.DeleteToolGroupRequest
+	2,  // 23: mcpjungle.admin.v1.AdminService.ListServers:output_type -> mcpjungle.admin.v1.ListServersResponse
+	0,  // 24: mcpjungle.admin.v1.AdminService.RegisterServer:output_type -> mcpjungle.admin.v1.McpServer
+	5,  // 25: mcpjungle.admin.v1.AdminService.DeregisterServer:output_type -> mcpjungle.admin.v1.DeregisterServerResponse
+	8,  // 26: mcpjungle.admin.v1.AdminService.ListTools:output_type -> mcpjungle.admin.v1.ListToolsResponse
+	10, // 27: mcpjungle.admin.v1.AdminService.InvokeTool:output_type -> mcpjungle.admin.v1.InvokeToolResponse
+	12, // 28: mcpjungle.admin.v1.AdminService.CreateToolGroup:output_type -> mcpjungle.admin.v1.ToolGroup
+	15, // 29: mcpjungle.admin.v1.AdminService.UpdateToolGroup:output_type -> mcpjungle.admin.v1.UpdateToolGroupResponse
+	17, // 30: mcpjungle.admin.v1.AdminService.DeleteToolGroup:output_type -> mcpjungle.admin.v1.DeleteToolGroupResponse
+	23, // [23:31] is the sub-list for method output_type
+	15, // [15:23] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_admin_v1_admin_proto_init() }
+func file_admin_v1_admin_proto_init() {
+	if File_admin_v1_admin_proto != nil {
+		return
+	}
+	file_admin_v1_admin_proto_msgTypes[7].OneofWrappers = []any{}
+	file_admin_v1_admin_proto_msgTypes[10].OneofWrappers = []any{
+		(*InvokeToolResponse_Content)(nil),
+		(*InvokeToolResponse_Result)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_admin_v1_admin_proto_rawDesc), len(file_admin_v1_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_admin_v1_admin_proto_goTypes,
+		DependencyIndexes: file_admin_v1_admin_proto_depIdxs,
+		MessageInfos:      file_admin_v1_admin_proto_msgTypes,
+	}.Build()
+	File_admin_v1_admin_proto = out.File
+	file_admin_v1_admin_proto_goTypes = nil
+	file_admin_v1_admin_proto_depIdxs = nil
+}
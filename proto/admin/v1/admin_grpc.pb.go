@@ -0,0 +1,417 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: admin/v1/admin.proto
+
+// Package mcpjungle.admin.v1 is the gRPC admin API of the MCPJungle registry.
+// It exposes the core operations of the HTTP API, with the same behaviour and authorization:
+// in enterprise mode, the access token of a user is passed in the "authorization" metadata as "Bearer <token>".
+
+package adminv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdminService_ListServers_FullMethodName      = "/mcpjungle.admin.v1.AdminService/ListServers"
+	AdminService_RegisterServer_FullMethodName   = "/mcpjungle.admin.v1.AdminService/RegisterServer"
+	AdminService_DeregisterServer_FullMethodName = "/mcpjungle.admin.v1.AdminService/DeregisterServer"
+	AdminService_ListTools_FullMethodName        = "/mcpjungle.admin.v1.AdminService/ListTools"
+	AdminService_InvokeTool_FullMethodName       = "/mcpjungle.admin.v1.AdminService/InvokeTool"
+	AdminService_CreateToolGroup_FullMethodName  = "/mcpjungle.admin.v1.AdminService/CreateToolGroup"
+	AdminService_UpdateToolGroup_FullMethodName  = "/mcpjungle.admin.v1.AdminService/UpdateToolGroup"
+	AdminService_DeleteToolGroup_FullMethodName  = "/mcpjungle.admin.v1.AdminService/DeleteToolGroup"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdminService manages the MCP servers, tools and tool groups of the registry.
+type AdminServiceClient interface {
+	// ListServers returns a page of the registered MCP servers.
+	ListServers(ctx context.Context, in *ListServersRequest, opts ...grpc.CallOption) (*ListServersResponse, error)
+	// RegisterServer registers an MCP server and its tools. Requires the admin role.
+	RegisterServer(ctx context.Context, in *RegisterServerRequest, opts ...grpc.CallOption) (*McpServer, error)
+	// DeregisterServer removes an MCP server and its tools. Requires the admin role.
+	DeregisterServer(ctx context.Context, in *DeregisterServerRequest, opts ...grpc.CallOption) (*DeregisterServerResponse, error)
+	// ListTools returns a page of the tools matching the filters of the request.
+	ListTools(ctx context.Context, in *ListToolsRequest, opts ...grpc.CallOption) (*ListToolsResponse, error)
+	// InvokeTool calls a tool and streams its result: one message per content item of the result,
+	// followed by a final message with the outcome of the call.
+	InvokeTool(ctx context.Context, in *InvokeToolRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InvokeToolResponse], error)
+	// CreateToolGroup creates a tool group. Requires the admin role.
+	CreateToolGroup(ctx context.Context, in *CreateToolGroupRequest, opts ...grpc.CallOption) (*ToolGroup, error)
+	// UpdateToolGroup replaces the configuration of a tool group. Requires the admin role.
+	UpdateToolGroup(ctx context.Context, in *UpdateToolGroupRequest, opts ...grpc.CallOption) (*UpdateToolGroupResponse, error)
+	// DeleteToolGroup deletes a tool group. Requires the admin role.
+	DeleteToolGroup(ctx context.Context, in *DeleteToolGroupRequest, opts ...grpc.CallOption) (*DeleteToolGroupResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) ListServers(ctx context.Context, in *ListServersRequest, opts ...grpc.CallOption) (*ListServersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListServersResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListServers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RegisterServer(ctx context.Context, in *RegisterServerRequest, opts ...grpc.CallOption) (*McpServer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(McpServer)
+	err := c.cc.Invoke(ctx, AdminService_RegisterServer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeregisterServer(ctx context.Context, in *DeregisterServerRequest, opts ...grpc.CallOption) (*DeregisterServerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeregisterServerResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeregisterServer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListTools(ctx context.Context, in *ListToolsRequest, opts ...grpc.CallOption) (*ListToolsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListToolsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListTools_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) InvokeTool(ctx context.Context, in *InvokeToolRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InvokeToolResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[0], AdminService_InvokeTool_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[InvokeToolRequest, InvokeToolResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdminService_InvokeToolClient = grpc.ServerStreamingClient[InvokeToolResponse]
+
+func (c *adminServiceClient) CreateToolGroup(ctx context.Context, in *CreateToolGroupRequest, opts ...grpc.CallOption) (*ToolGroup, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ToolGroup)
+	err := c.cc.Invoke(ctx, AdminService_CreateToolGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) UpdateToolGroup(ctx context.Context, in *UpdateToolGroupRequest, opts ...grpc.CallOption) (*UpdateToolGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateToolGroupResponse)
+	err := c.cc.Invoke(ctx, AdminService_UpdateToolGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteToolGroup(ctx context.Context, in *DeleteToolGroupRequest, opts ...grpc.CallOption) (*DeleteToolGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteToolGroupResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteToolGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// AdminService manages the MCP servers, tools and tool groups of the registry.
+type AdminServiceServer interface {
+	// ListServers returns a page of the registered MCP servers.
+	ListServers(context.Context, *ListServersRequest) (*ListServersResponse, error)
+	// RegisterServer registers an MCP server and its tools. Requires the admin role.
+	RegisterServer(context.Context, *RegisterServerRequest) (*McpServer, error)
+	// DeregisterServer removes an MCP server and its tools. Requires the admin role.
+	DeregisterServer(context.Context, *DeregisterServerRequest) (*DeregisterServerResponse, error)
+	// ListTools returns a page of the tools matching the filters of the request.
+	ListTools(context.Context, *ListToolsRequest) (*ListToolsResponse, error)
+	// InvokeTool calls a tool and streams its result: one message per content item of the result,
+	// followed by a final message with the outcome of the call.
+	InvokeTool(*InvokeToolRequest, grpc.ServerStreamingServer[InvokeToolResponse]) error
+	// CreateToolGroup creates a tool group. Requires the admin role.
+	CreateToolGroup(context.Context, *CreateToolGroupRequest) (*ToolGroup, error)
+	// UpdateToolGroup replaces the configuration of a tool group. Requires the admin role.
+	UpdateToolGroup(context.Context, *UpdateToolGroupRequest) (*UpdateToolGroupResponse, error)
+	// DeleteToolGroup deletes a tool group. Requires the admin role.
+	DeleteToolGroup(context.Context, *DeleteToolGroupRequest) (*DeleteToolGroupResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) ListServers(context.Context, *ListServersRequest) (*ListServersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServers not implemented")
+}
+func (UnimplementedAdminServiceServer) RegisterServer(context.Context, *RegisterServerRequest) (*McpServer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterServer not implemented")
+}
+func (UnimplementedAdminServiceServer) DeregisterServer(context.Context, *DeregisterServerRequest) (*DeregisterServerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeregisterServer not implemented")
+}
+func (UnimplementedAdminServiceServer) ListTools(context.Context, *ListToolsRequest) (*ListToolsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTools not implemented")
+}
+func (UnimplementedAdminServiceServer) InvokeTool(*InvokeToolRequest, grpc.ServerStreamingServer[InvokeToolResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method InvokeTool not implemented")
+}
+func (UnimplementedAdminServiceServer) CreateToolGroup(context.Context, *CreateToolGroupRequest) (*ToolGroup, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateToolGroup not implemented")
+}
+func (UnimplementedAdminServiceServer) UpdateToolGroup(context.Context, *UpdateToolGroupRequest) (*UpdateToolGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateToolGroup not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteToolGroup(context.Context, *DeleteToolGroupRequest) (*DeleteToolGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteToolGroup not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_ListServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListServers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListServers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListServers(ctx, req.(*ListServersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RegisterServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RegisterServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RegisterServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RegisterServer(ctx, req.(*RegisterServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeregisterServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeregisterServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeregisterServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeregisterServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeregisterServer(ctx, req.(*DeregisterServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListTools_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListToolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListTools(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListTools_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListTools(ctx, req.(*ListToolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_InvokeTool_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InvokeToolRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).InvokeTool(m, &grpc.GenericServerStream[InvokeToolRequest, InvokeToolResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdminService_InvokeToolServer = grpc.ServerStreamingServer[InvokeToolResponse]
+
+func _AdminService_CreateToolGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateToolGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CreateToolGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CreateToolGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CreateToolGroup(ctx, req.(*CreateToolGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UpdateToolGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateToolGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UpdateToolGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_UpdateToolGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UpdateToolGroup(ctx, req.(*UpdateToolGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteToolGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteToolGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteToolGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteToolGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteToolGroup(ctx, req.(*DeleteToolGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mcpjungle.admin.v1.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListServers",
+			Handler:    _AdminService_ListServers_Handler,
+		},
+		{
+			MethodName: "RegisterServer",
+			Handler:    _AdminService_RegisterServer_Handler,
+		},
+		{
+			MethodName: "DeregisterServer",
+			Handler:    _AdminService_DeregisterServer_Handler,
+		},
+		{
+			MethodName: "ListTools",
+			Handler:    _AdminService_ListTools_Handler,
+		},
+		{
+			MethodName: "CreateToolGroup",
+			Handler:    _AdminService_CreateToolGroup_Handler,
+		},
+		{
+			MethodName: "UpdateToolGroup",
+			Handler:    _AdminService_UpdateToolGroup_Handler,
+		},
+		{
+			MethodName: "DeleteToolGroup",
+			Handler:    _AdminService_DeleteToolGroup_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "InvokeTool",
+			Handler:       _AdminService_InvokeTool_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "admin/v1/admin.proto",
+}
@@ -0,0 +1,4 @@
+package adminv1
+
+// The Go code of the API is generated from admin.proto, run `go generate ./proto/...` after changing it.
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative admin/v1/admin.proto